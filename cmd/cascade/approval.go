@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/goliatone/cascade/internal/broker"
+	execpkg "github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/internal/planner"
+	"github.com/goliatone/cascade/internal/state"
+	"github.com/goliatone/cascade/pkg/config"
+	"github.com/goliatone/cascade/pkg/di"
+)
+
+// approvalPath is where the callback listener serves Slack interactivity requests.
+const approvalPath = "/slack/interactions"
+
+// approvalGate asks for approval in Slack before a release executes its plan or
+// each of its work items, as the approval mode selects.
+type approvalGate struct {
+	mode     string
+	approver broker.Approver
+}
+
+var (
+	approvalListenerOnce sync.Once
+	approvalListener     *broker.SlackApprover
+	approvalListenerErr  error
+)
+
+// newApprovalGate returns the configured approval gate, or nil when approval is
+// off. The callback listener starts with the first gate and then serves every
+// release the process runs, such as the ones cascade serve starts.
+func newApprovalGate(cfg *config.Config, client *http.Client, logger di.Logger) (*approvalGate, error) {
+	if cfg == nil || cfg.Approval.Mode == "" {
+		return nil, nil
+	}
+
+	approvalListenerOnce.Do(func() {
+		approver := broker.NewSlackApprover(cfg.Integration.Slack.Token, cfg.Approval.ApprovalChannel(cfg.Integration.Slack), cfg.Integration.Slack.SigningSecret, cfg.Approval.Timeout, client, broker.WithApprovers(cfg.Approval.Approvers...))
+		listener, err := net.Listen("tcp", cfg.Approval.Listen)
+		if err != nil {
+			approvalListenerErr = err
+			return
+		}
+		mux := http.NewServeMux()
+		mux.Handle(approvalPath, approver)
+		server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+		go func() {
+			if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logger.Error("Approval listener stopped", "error", err)
+			}
+		}()
+		logger.Info("Listening for Slack approvals", "addr", listener.Addr().String(), "path", approvalPath)
+		approvalListener = approver
+	})
+	if approvalListenerErr != nil {
+		return nil, newExecutionError("failed to start the approval listener", approvalListenerErr).
+			WithHint("set approval.listen to a free address")
+	}
+	return &approvalGate{mode: cfg.Approval.Mode, approver: approvalListener}, nil
+}
+
+// approvePlan waits for the plan to be approved when approval is per plan, and
+// fails unless it is.
+func (g *approvalGate) approvePlan(ctx context.Context, w io.Writer, target planner.Target, plan *planner.Plan) error {
+	if g == nil || g.mode != config.ApprovalModePlan {
+		return nil
+	}
+
+	summary, err := broker.RenderPlanSummary(newPlanSummary(target, plan))
+	if err != nil {
+		return newExecutionError("failed to render the plan for approval", err)
+	}
+	fmt.Fprintf(w, "Waiting for approval in Slack to release %s@%s\n", target.Module, target.Version)
+	decision, err := g.approver.RequestApproval(ctx, broker.ApprovalRequest{
+		Text: "*Approval needed*\n" + summary,
+	})
+	if err != nil {
+		return newExecutionError("failed to request approval in Slack", err)
+	}
+	if !decision.Approved {
+		return newExecutionError(fmt.Sprintf("release of %s@%s was not approved: %s", target.Module, target.Version, decisionReason(decision)), nil)
+	}
+	fmt.Fprintf(w, "%s Approved by %s\n", style.mark(markOK), decision.User)
+	return nil
+}
+
+// approveItem waits for item to be approved when approval is per work item. It
+// returns the reason when the item must be skipped instead.
+func (g *approvalGate) approveItem(ctx context.Context, item planner.WorkItem) (bool, string) {
+	if g == nil || g.mode != config.ApprovalModeItem {
+		return true, ""
+	}
+
+	decision, err := g.approver.RequestApproval(ctx, broker.ApprovalRequest{
+		Text: fmt.Sprintf("*Approval needed*\nUpdate *%s* (%s) to %s on branch `%s`?", item.Repo, item.Module, item.UpdatedModules(), item.BranchName),
+	})
+	if err != nil {
+		return false, fmt.Sprintf("approval request failed: %v", err)
+	}
+	if !decision.Approved {
+		return false, "not approved: " + decisionReason(decision)
+	}
+	return true, ""
+}
+
+// approveItems requests approval for every item at once and sends the index of
+// each approved item on the returned channel, which is closed once every item is
+// answered, so items wait for approval outside the scheduler's slots. Items that
+// must be skipped are passed to rejected instead, from the goroutine that waited
+// on them. When approval is not per work item, every index is sent right away.
+func (g *approvalGate) approveItems(ctx context.Context, items []planner.WorkItem, rejected func(index int, item planner.WorkItem, reason string)) <-chan int {
+	ready := make(chan int, len(items))
+	if g == nil || g.mode != config.ApprovalModeItem {
+		for i := range items {
+			ready <- i
+		}
+		close(ready)
+		return ready
+	}
+
+	var wg sync.WaitGroup
+	for i, item := range items {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if approved, reason := g.approveItem(ctx, item); !approved {
+				rejected(i, item, reason)
+				return
+			}
+			ready <- i
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(ready)
+	}()
+	return ready
+}
+
+// decisionReason describes why a decision is a rejection.
+func decisionReason(decision *broker.ApprovalDecision) string {
+	if decision.User != "" {
+		return "rejected by " + decision.User
+	}
+	return decision.Reason
+}
+
+// rejectedItemState records a work item that was skipped for lack of approval.
+func rejectedItemState(item planner.WorkItem, reason string) state.ItemState {
+	return state.ItemState{
+		Repo:        item.Repo,
+		Branch:      item.BranchName,
+		Status:      execpkg.StatusSkipped,
+		Reason:      reason,
+		LastUpdated: time.Now(),
+		Attempts:    1,
+		Provider:    item.Provider,
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/goliatone/cascade/internal/broker"
+	"github.com/goliatone/cascade/internal/planner"
+	"github.com/goliatone/cascade/pkg/config"
+)
+
+// fakeApprover answers every approval request with decision and records the
+// requests it was asked.
+type fakeApprover struct {
+	mu       sync.Mutex
+	decision *broker.ApprovalDecision
+	err      error
+	requests []broker.ApprovalRequest
+}
+
+func (f *fakeApprover) RequestApproval(ctx context.Context, req broker.ApprovalRequest) (*broker.ApprovalDecision, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, req)
+	return f.decision, f.err
+}
+
+func TestApprovalGatePlan(t *testing.T) {
+	target := planner.Target{Module: "example.com/lib", Version: "v1.2.0"}
+	plan := &planner.Plan{Target: target, Items: []planner.WorkItem{{Repo: "example/app"}}}
+
+	approver := &fakeApprover{decision: &broker.ApprovalDecision{Approved: true, User: "alice"}}
+	gate := &approvalGate{mode: config.ApprovalModePlan, approver: approver}
+	var out bytes.Buffer
+	if err := gate.approvePlan(context.Background(), &out, target, plan); err != nil {
+		t.Fatalf("approvePlan() error = %v", err)
+	}
+	if len(approver.requests) != 1 || !strings.Contains(approver.requests[0].Text, "example/app") || !strings.Contains(out.String(), "Approved by alice") {
+		t.Errorf("unexpected approval request %+v, output %q", approver.requests, out.String())
+	}
+	if ok, _ := gate.approveItem(context.Background(), plan.Items[0]); !ok || len(approver.requests) != 1 {
+		t.Error("items should not need approval when the plan does")
+	}
+
+	gate.approver = &fakeApprover{decision: &broker.ApprovalDecision{Reason: "no response within 1h0m0s"}}
+	err := gate.approvePlan(context.Background(), &out, target, plan)
+	var cliErr *CLIError
+	if !errors.As(err, &cliErr) || cliErr.Code != ExitExecutionError || !strings.Contains(err.Error(), "no response") {
+		t.Errorf("expected an execution error naming the timeout, got %v", err)
+	}
+}
+
+func TestApprovalGateItem(t *testing.T) {
+	item := planner.WorkItem{Repo: "example/app", Module: "example.com/app", SourceModule: "example.com/lib", SourceVersion: "v1.2.0"}
+
+	gate := &approvalGate{mode: config.ApprovalModeItem, approver: &fakeApprover{decision: &broker.ApprovalDecision{User: "bob"}}}
+	ok, reason := gate.approveItem(context.Background(), item)
+	if ok || reason != "not approved: rejected by bob" {
+		t.Errorf("approveItem() = %v, %q", ok, reason)
+	}
+	if state := rejectedItemState(item, reason); state.Status != "skipped" || state.Reason != reason {
+		t.Errorf("unexpected rejected item state: %+v", state)
+	}
+
+	gate.approver = &fakeApprover{err: errors.New("channel_not_found")}
+	if ok, reason := gate.approveItem(context.Background(), item); ok || !strings.Contains(reason, "channel_not_found") {
+		t.Errorf("approveItem() = %v, %q; want a skip naming the Slack error", ok, reason)
+	}
+
+	var off *approvalGate
+	if ok, _ := off.approveItem(context.Background(), item); !ok {
+		t.Error("a nil gate should approve everything")
+	}
+}
+
+// repoApprover approves the items of the repositories in approved and rejects
+// the rest.
+type repoApprover struct {
+	approved map[string]bool
+}
+
+func (r repoApprover) RequestApproval(ctx context.Context, req broker.ApprovalRequest) (*broker.ApprovalDecision, error) {
+	for repo, ok := range r.approved {
+		if strings.Contains(req.Text, "*"+repo+"*") {
+			return &broker.ApprovalDecision{Approved: ok, User: "carol"}, nil
+		}
+	}
+	return &broker.ApprovalDecision{User: "carol"}, nil
+}
+
+func TestApprovalGateItems(t *testing.T) {
+	items := []planner.WorkItem{{Repo: "example/a"}, {Repo: "example/b"}, {Repo: "example/c"}}
+
+	gate := &approvalGate{mode: config.ApprovalModeItem, approver: repoApprover{approved: map[string]bool{"example/a": true, "example/c": true}}}
+	var mu sync.Mutex
+	var rejected []string
+	var ready []int
+	for index := range gate.approveItems(context.Background(), items, func(index int, item planner.WorkItem, reason string) {
+		mu.Lock()
+		defer mu.Unlock()
+		rejected = append(rejected, item.Repo+": "+reason)
+	}) {
+		ready = append(ready, index)
+	}
+	sort.Ints(ready)
+	if len(ready) != 2 || ready[0] != 0 || ready[1] != 2 {
+		t.Errorf("approved indexes = %v, want [0 2]", ready)
+	}
+	if len(rejected) != 1 || rejected[0] != "example/b: not approved: rejected by carol" {
+		t.Errorf("rejected = %v", rejected)
+	}
+
+	var off *approvalGate
+	ready = nil
+	for index := range off.approveItems(context.Background(), items, func(int, planner.WorkItem, string) {
+		t.Error("a nil gate should reject nothing")
+	}) {
+		ready = append(ready, index)
+	}
+	if len(ready) != len(items) {
+		t.Errorf("a nil gate should make every item ready, got %v", ready)
+	}
+}
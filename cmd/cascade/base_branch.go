@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/goliatone/cascade/internal/planner"
+)
+
+// overrideBaseBranch points every item at branch, as release --base-branch
+// does: each update starts from it and its pull request targets it. Items are
+// returned unchanged when branch is empty.
+func overrideBaseBranch(items []planner.WorkItem, branch string) []planner.WorkItem {
+	if branch == "" {
+		return items
+	}
+	overridden := make([]planner.WorkItem, len(items))
+	for i, item := range items {
+		item.Branch = branch
+		// pr.base also keeps the branch when the dependent's own .cascade.yaml
+		// is applied after cloning
+		item.PR.Base = branch
+		overridden[i] = item
+	}
+	return overridden
+}
+
+// preflightBaseBranches checks that the base of every item that overrides it,
+// with pr.base or --base-branch, is a branch of the item's remote, so a typo
+// fails the release before any dependent is cloned. Listing failures are
+// reported as warnings and left to the clone to surface.
+func preflightBaseBranches(ctx context.Context, w io.Writer, branches planner.BranchChecker, items []planner.WorkItem) error {
+	var missing []string
+	checked := make(map[string]bool)
+	for _, item := range items {
+		if item.PR.Base == "" {
+			continue
+		}
+		key := item.Repo + "@" + item.Branch
+		if checked[key] {
+			continue
+		}
+		checked[key] = true
+
+		exists, err := branches.BranchExists(ctx, item, item.Branch)
+		switch {
+		case err != nil:
+			fmt.Fprintf(w, "%s could not check base branch %s of %s: %v\n", style.mark(markWarning), item.Branch, item.Repo, err)
+		case !exists:
+			missing = append(missing, fmt.Sprintf("%s has no branch %s", item.Repo, item.Branch))
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return newValidationError(fmt.Sprintf("base branch not found in %d dependents", len(missing)), errors.New(strings.Join(missing, "; "))).
+		WithHint("create the branch in those repositories, or fix --base-branch or pr.base")
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/goliatone/cascade/internal/manifest"
+	"github.com/goliatone/cascade/internal/planner"
+)
+
+// fakeBranches knows the branches of each repository and counts lookups.
+type fakeBranches struct {
+	branches map[string][]string
+	lookups  int
+}
+
+func (f *fakeBranches) BranchExists(ctx context.Context, item planner.WorkItem, branch string) (bool, error) {
+	f.lookups++
+	names, ok := f.branches[item.Repo]
+	if !ok {
+		return false, errors.New("repository not found")
+	}
+	for _, name := range names {
+		if name == branch {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func TestOverrideBaseBranch(t *testing.T) {
+	items := []planner.WorkItem{{Repo: "org/a", Branch: "main"}, {Repo: "org/b", Branch: "develop", PR: manifest.PRConfig{Base: "develop"}}}
+
+	if got := overrideBaseBranch(items, ""); got[0].Branch != "main" || got[1].Branch != "develop" {
+		t.Fatalf("overrideBaseBranch() without a branch = %+v", got)
+	}
+	got := overrideBaseBranch(items, "release/1.x")
+	for _, item := range got {
+		if item.Branch != "release/1.x" || item.PR.Base != "release/1.x" {
+			t.Errorf("item %s = branch %q, pr.base %q, want release/1.x", item.Repo, item.Branch, item.PR.Base)
+		}
+	}
+	if items[0].Branch != "main" {
+		t.Error("overrideBaseBranch() modified its input")
+	}
+}
+
+func TestPreflightBaseBranches(t *testing.T) {
+	branches := &fakeBranches{branches: map[string][]string{
+		"org/a": {"main", "release/1.x"},
+		"org/b": {"main"},
+	}}
+
+	t.Run("existing branches", func(t *testing.T) {
+		items := []planner.WorkItem{
+			{Repo: "org/a", Branch: "release/1.x", PR: manifest.PRConfig{Base: "release/1.x"}},
+			{Repo: "org/a", Branch: "release/1.x", PR: manifest.PRConfig{Base: "release/1.x"}},
+			// Items without an overridden base are not checked
+			{Repo: "org/b", Branch: "develop"},
+		}
+		branches.lookups = 0
+		if err := preflightBaseBranches(context.Background(), &bytes.Buffer{}, branches, items); err != nil {
+			t.Fatalf("preflightBaseBranches() error = %v", err)
+		}
+		if branches.lookups != 1 {
+			t.Errorf("lookups = %d, want 1", branches.lookups)
+		}
+	})
+
+	t.Run("missing branch", func(t *testing.T) {
+		items := []planner.WorkItem{
+			{Repo: "org/a", Branch: "release/1.x", PR: manifest.PRConfig{Base: "release/1.x"}},
+			{Repo: "org/b", Branch: "release/1.x", PR: manifest.PRConfig{Base: "release/1.x"}},
+		}
+		err := preflightBaseBranches(context.Background(), &bytes.Buffer{}, branches, items)
+		var cliErr *CLIError
+		if !errors.As(err, &cliErr) || cliErr.Code != ExitValidationError {
+			t.Fatalf("preflightBaseBranches() error = %v, want a validation error", err)
+		}
+		if !strings.Contains(cliErr.Cause.Error(), "org/b has no branch release/1.x") {
+			t.Errorf("cause = %v", cliErr.Cause)
+		}
+	})
+
+	t.Run("listing failure warns", func(t *testing.T) {
+		items := []planner.WorkItem{{Repo: "org/unknown", Branch: "release/1.x", PR: manifest.PRConfig{Base: "release/1.x"}}}
+		var out bytes.Buffer
+		if err := preflightBaseBranches(context.Background(), &out, branches, items); err != nil {
+			t.Fatalf("preflightBaseBranches() error = %v", err)
+		}
+		if !strings.Contains(out.String(), "could not check base branch release/1.x of org/unknown") {
+			t.Errorf("output = %q", out.String())
+		}
+	})
+}
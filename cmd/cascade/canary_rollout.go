@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/goliatone/cascade/internal/broker"
+	execpkg "github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/internal/planner"
+	"github.com/goliatone/cascade/internal/state"
+	"github.com/goliatone/cascade/pkg/config"
+	"github.com/goliatone/cascade/pkg/di"
+)
+
+// canaryRollout updates the canary dependents of a release first and holds
+// back the rest until the checks on the canaries' pull requests pass.
+type canaryRollout struct {
+	broker   broker.Broker
+	timeout  time.Duration
+	interval time.Duration
+	out      io.Writer
+	logger   di.Logger
+	sleep    func(ctx context.Context, d time.Duration) error
+}
+
+func newCanaryRollout(cfg *config.Config, b broker.Broker, out io.Writer, logger di.Logger) *canaryRollout {
+	return &canaryRollout{
+		broker:   b,
+		timeout:  cfg.Executor.CanaryTimeout,
+		interval: cfg.Executor.CanaryPollInterval,
+		out:      out,
+		logger:   logger,
+		sleep:    sleepContext,
+	}
+}
+
+// canaryPR is a pull request opened by a canary.
+type canaryPR struct {
+	pr       *broker.PullRequest
+	provider string
+}
+
+// run executes items with execute, which returns the states it recorded. When
+// items mix canaries and other dependents, the canaries run first and the rest
+// only once their checks pass; otherwise the rest are not executed and are
+// returned with the reason they were held back.
+func (r *canaryRollout) run(ctx context.Context, items []planner.WorkItem, execute func(items []planner.WorkItem, offset int) []state.ItemState) ([]planner.WorkItem, error) {
+	canaries, rest := planner.SplitCanaries(items)
+	if len(canaries) == 0 || len(rest) == 0 {
+		execute(items, 0)
+		return nil, nil
+	}
+
+	fmt.Fprintf(r.out, "Updating %d canaries first; %d dependents wait for their checks to pass\n", len(canaries), len(rest))
+	states := execute(canaries, 0)
+	if err := r.wait(ctx, states); err != nil {
+		return rest, err
+	}
+
+	fmt.Fprintf(r.out, "Canaries passed; updating the remaining %d dependents\n", len(rest))
+	execute(rest, len(canaries))
+	return nil, nil
+}
+
+// wait returns nil once the checks on every canary pull request passed or
+// report no checks at all. It returns why the rest of the release is held back
+// when a canary failed, its checks failed, or they did not finish in time.
+// Canaries that opened no pull request, such as those already up to date, are
+// not waited for.
+func (r *canaryRollout) wait(ctx context.Context, canaries []state.ItemState) error {
+	var pending []canaryPR
+	for _, canary := range canaries {
+		if canary.Status == execpkg.StatusFailed {
+			return fmt.Errorf("canary %s failed: %s", canary.Repo, canary.Reason)
+		}
+		if canary.PRURL == "" {
+			continue
+		}
+		number, err := extractPRNumber(canary.PRURL)
+		if err != nil {
+			return err
+		}
+		pending = append(pending, canaryPR{
+			pr:       &broker.PullRequest{Repo: canary.Repo, Number: number, URL: canary.PRURL},
+			provider: canary.Provider,
+		})
+	}
+	if len(pending) == 0 {
+		fmt.Fprintf(r.out, "  No canary opened a pull request; nothing to wait for\n")
+		return nil
+	}
+
+	reporter, ok := r.broker.(broker.ChecksReporter)
+	if !ok {
+		return fmt.Errorf("the configured provider cannot report pull request checks")
+	}
+	if r.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+	}
+
+	for {
+		// Checks are rarely reported the moment a pull request opens, so the
+		// first look comes after one interval
+		fmt.Fprintf(r.out, "  Waiting for checks on %s\n", canaryURLs(pending))
+		if err := r.sleep(ctx, r.interval); err != nil {
+			return fmt.Errorf("timed out waiting for checks on %s", canaryURLs(pending))
+		}
+
+		var still []canaryPR
+		for _, canary := range pending {
+			checks, err := reporter.PullRequestChecks(withItemProvider(ctx, canary.provider), canary.pr)
+			switch {
+			case err != nil:
+				if ctx.Err() != nil {
+					return fmt.Errorf("timed out waiting for checks on %s", canaryURLs(pending))
+				}
+				r.logger.Warn("Failed to check pull request checks", "pr", canary.pr.URL, "error", err)
+				still = append(still, canary)
+			case checks.State == broker.ChecksFailed:
+				return fmt.Errorf("checks failed on %s: %s", canary.pr.URL, strings.Join(checks.Failed, ", "))
+			case checks.State == broker.ChecksPending:
+				still = append(still, canary)
+			case checks.State == broker.ChecksNone:
+				fmt.Fprintf(r.out, "  %s %s reports no checks\n", style.mark(markOK), canary.pr.URL)
+			default:
+				fmt.Fprintf(r.out, "  %s %s passed %d checks\n", style.mark(markOK), canary.pr.URL, checks.Total)
+			}
+		}
+		if len(still) == 0 {
+			return nil
+		}
+		pending = still
+	}
+}
+
+func canaryURLs(prs []canaryPR) string {
+	urls := make([]string, len(prs))
+	for i, canary := range prs {
+		urls[i] = canary.pr.URL
+	}
+	return strings.Join(urls, ", ")
+}
+
+// printHeldBack lists the dependents a failed canary rollout did not update.
+func printHeldBack(w io.Writer, held []planner.WorkItem, reason error) {
+	fmt.Fprintf(w, "\n%s Holding back %d dependents: %v\n", style.mark(markSkipped), len(held), reason)
+	for _, item := range held {
+		fmt.Fprintf(w, "  - %s (%s)\n", item.Repo, item.Module)
+	}
+}
+
+// printPlanCanaries notes which items of a release are canaries. Nothing is
+// printed when there are none, or only canaries.
+func printPlanCanaries(w io.Writer, items []planner.WorkItem) {
+	canaries, rest := planner.SplitCanaries(items)
+	if len(canaries) == 0 || len(rest) == 0 {
+		return
+	}
+	repos := make([]string, len(canaries))
+	for i, item := range canaries {
+		repos[i] = item.Repo
+	}
+	fmt.Fprintf(w, "\nCanaries (%d), updated first: %s\n", len(canaries), strings.Join(repos, ", "))
+	fmt.Fprintf(w, "The other %d dependents wait for the checks on their pull requests to pass\n", len(rest))
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goliatone/cascade/internal/broker"
+	execpkg "github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/internal/planner"
+	"github.com/goliatone/cascade/internal/state"
+)
+
+// checksBroker reports the given check states in turn, repeating the last one.
+type checksBroker struct {
+	*mockBroker
+	states []string
+	polls  int
+}
+
+func (b *checksBroker) PullRequestChecks(ctx context.Context, pr *broker.PullRequest) (*broker.ChecksStatus, error) {
+	checks := &broker.ChecksStatus{State: b.states[min(b.polls, len(b.states)-1)], Total: 1}
+	if checks.State == broker.ChecksFailed {
+		checks.Failed = []string{"test"}
+	}
+	b.polls++
+	return checks, nil
+}
+
+// newTestCanaryRollout returns a canaryRollout whose sleeps end the wait after
+// polls of them.
+func newTestCanaryRollout(brokerSvc broker.Broker, polls int) *canaryRollout {
+	slept := 0
+	return &canaryRollout{
+		broker:   brokerSvc,
+		timeout:  time.Hour,
+		interval: time.Minute,
+		out:      &bytes.Buffer{},
+		logger:   &mockLogger{},
+		sleep: func(ctx context.Context, d time.Duration) error {
+			if slept++; slept > polls {
+				return context.DeadlineExceeded
+			}
+			return nil
+		},
+	}
+}
+
+func TestCanaryRolloutRun(t *testing.T) {
+	items := []planner.WorkItem{
+		{Repo: "example/api"},
+		{Repo: "example/core", Canary: true},
+		{Repo: "example/web"},
+	}
+	tests := []struct {
+		name      string
+		status    execpkg.Status
+		prURL     string
+		checks    []string
+		wantBatch []string
+		wantHeld  int
+		wantErr   string
+	}{
+		{
+			name:      "checks pass after polling",
+			status:    execpkg.StatusCompleted,
+			prURL:     "https://github.com/example/core/pull/7",
+			checks:    []string{broker.ChecksPending, broker.ChecksPassed},
+			wantBatch: []string{"example/api", "example/web"},
+		},
+		{
+			name:      "no checks configured",
+			status:    execpkg.StatusCompleted,
+			prURL:     "https://github.com/example/core/pull/7",
+			checks:    []string{broker.ChecksNone},
+			wantBatch: []string{"example/api", "example/web"},
+		},
+		{
+			name:      "canary already up to date",
+			status:    execpkg.StatusNoChange,
+			checks:    []string{broker.ChecksFailed},
+			wantBatch: []string{"example/api", "example/web"},
+		},
+		{
+			name:     "checks fail",
+			status:   execpkg.StatusCompleted,
+			prURL:    "https://github.com/example/core/pull/7",
+			checks:   []string{broker.ChecksPending, broker.ChecksFailed},
+			wantHeld: 2,
+			wantErr:  "checks failed on https://github.com/example/core/pull/7: test",
+		},
+		{
+			name:     "checks time out",
+			status:   execpkg.StatusCompleted,
+			prURL:    "https://github.com/example/core/pull/7",
+			checks:   []string{broker.ChecksPending},
+			wantHeld: 2,
+			wantErr:  "timed out waiting for checks on https://github.com/example/core/pull/7",
+		},
+		{
+			name:     "canary fails",
+			status:   execpkg.StatusFailed,
+			checks:   []string{broker.ChecksPassed},
+			wantHeld: 2,
+			wantErr:  "canary example/core failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			brokerSvc := &checksBroker{mockBroker: &mockBroker{}, states: tt.checks}
+			r := newTestCanaryRollout(brokerSvc, 3)
+
+			var batches [][]string
+			var offsets []int
+			held, err := r.run(context.Background(), items, func(items []planner.WorkItem, offset int) []state.ItemState {
+				var repos []string
+				var states []state.ItemState
+				for _, item := range items {
+					repos = append(repos, item.Repo)
+					states = append(states, state.ItemState{Repo: item.Repo, Status: tt.status, PRURL: tt.prURL, Reason: "go mod tidy failed"})
+				}
+				batches = append(batches, repos)
+				offsets = append(offsets, offset)
+				return states
+			})
+
+			if len(batches) == 0 || strings.Join(batches[0], ",") != "example/core" {
+				t.Fatalf("batches = %v, want the canary first", batches)
+			}
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("run() error = %v, want %q", err, tt.wantErr)
+				}
+				if len(batches) != 1 || len(held) != tt.wantHeld {
+					t.Errorf("ran %v and held back %d items, want only the canary run and %d held", batches, len(held), tt.wantHeld)
+				}
+				return
+			}
+			if err != nil || held != nil {
+				t.Fatalf("run() = %v, %v, want the release to continue", held, err)
+			}
+			if len(batches) != 2 || strings.Join(batches[1], ",") != strings.Join(tt.wantBatch, ",") || offsets[1] != 1 {
+				t.Errorf("batches = %v at offsets %v, want %v after the canary", batches, offsets, tt.wantBatch)
+			}
+		})
+	}
+}
+
+func TestCanaryRolloutRun_NoCanaries(t *testing.T) {
+	r := newTestCanaryRollout(&mockBroker{}, 0)
+	items := []planner.WorkItem{{Repo: "example/api"}, {Repo: "example/web"}}
+
+	calls := 0
+	held, err := r.run(context.Background(), items, func(batch []planner.WorkItem, offset int) []state.ItemState {
+		calls++
+		if len(batch) != len(items) || offset != 0 {
+			t.Errorf("execute(%v, %d), want every item at once", batch, offset)
+		}
+		return nil
+	})
+	if err != nil || held != nil || calls != 1 {
+		t.Errorf("run() = %v, %v after %d batches, want one batch", held, err, calls)
+	}
+}
+
+func TestCanaryRolloutWait_ProviderWithoutChecks(t *testing.T) {
+	r := newTestCanaryRollout(&mockBroker{}, 1)
+	canaries := []state.ItemState{{Repo: "example/core", Status: execpkg.StatusCompleted, PRURL: "https://github.com/example/core/pull/7"}}
+	if err := r.wait(context.Background(), canaries); err == nil || !strings.Contains(err.Error(), "cannot report pull request checks") {
+		t.Errorf("wait() error = %v, want the provider to be reported", err)
+	}
+}
+
+func TestPrintPlanCanaries(t *testing.T) {
+	var out bytes.Buffer
+	printPlanCanaries(&out, []planner.WorkItem{{Repo: "example/api"}, {Repo: "example/core", Canary: true}})
+	if got := out.String(); !strings.Contains(got, "Canaries (1), updated first: example/core") || !strings.Contains(got, "other 1 dependents") {
+		t.Errorf("printPlanCanaries() = %q", got)
+	}
+
+	out.Reset()
+	printPlanCanaries(&out, []planner.WorkItem{{Repo: "example/api"}})
+	if out.Len() != 0 {
+		t.Errorf("printPlanCanaries() without canaries = %q, want nothing", out.String())
+	}
+}
@@ -1,12 +1,19 @@
 package main
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
 
 // Error types for structured error handling
 type CLIError struct {
 	Code    int
 	Message string
 	Cause   error
+	// Hint is an optional, actionable suggestion printed after the error
+	Hint string
 }
 
 func (e *CLIError) Error() string {
@@ -16,12 +23,30 @@ func (e *CLIError) Error() string {
 	return e.Message
 }
 
+func (e *CLIError) Unwrap() error {
+	return e.Cause
+}
+
 func (e *CLIError) ExitCode() int {
 	return e.Code
 }
 
+// WithHint attaches an actionable suggestion to the error and returns it for chaining.
+func (e *CLIError) WithHint(format string, args ...any) *CLIError {
+	if len(args) > 0 {
+		e.Hint = fmt.Sprintf(format, args...)
+	} else {
+		e.Hint = format
+	}
+	return e
+}
+
 // Error creation helpers for structured error handling
 
+func newGenericError(message string, cause error) *CLIError {
+	return &CLIError{Code: ExitGenericError, Message: message, Cause: cause}
+}
+
 func newConfigError(message string, cause error) *CLIError {
 	return &CLIError{Code: ExitConfigError, Message: message, Cause: cause}
 }
@@ -30,6 +55,10 @@ func newValidationError(message string, cause error) *CLIError {
 	return &CLIError{Code: ExitValidationError, Message: message, Cause: cause}
 }
 
+func newNetworkError(message string, cause error) *CLIError {
+	return &CLIError{Code: ExitNetworkError, Message: message, Cause: cause}
+}
+
 func newFileError(message string, cause error) *CLIError {
 	return &CLIError{Code: ExitFileError, Message: message, Cause: cause}
 }
@@ -45,3 +74,65 @@ func newPlanningError(message string, cause error) *CLIError {
 func newExecutionError(message string, cause error) *CLIError {
 	return &CLIError{Code: ExitExecutionError, Message: message, Cause: cause}
 }
+
+func newInterruptError(message string, cause error) *CLIError {
+	return &CLIError{Code: ExitInterruptError, Message: message, Cause: cause}
+}
+
+func newResourceError(message string, cause error) *CLIError {
+	return &CLIError{Code: ExitResourceError, Message: message, Cause: cause}
+}
+
+// asCLIError returns err as a *CLIError. Structured errors anywhere in the chain are
+// used as-is; bare errors (typically from cobra or third-party code) are classified
+// from their message so exit codes stay meaningful.
+func asCLIError(err error) *CLIError {
+	if err == nil {
+		return nil
+	}
+
+	var cliErr *CLIError
+	if errors.As(err, &cliErr) {
+		return cliErr
+	}
+
+	msg := err.Error()
+	switch {
+	case containsAny(msg, "configuration", "config"):
+		return newConfigError("configuration error", err)
+	case containsAny(msg, "no such file", "permission denied", "file not found", "manifest"):
+		return newFileError("file error", err)
+	case containsAny(msg, "must be specified", "invalid", "validation", "required", "unknown command", "unknown flag"):
+		return newValidationError("validation error", err)
+	case containsAny(msg, "network", "connection", "timeout", "unreachable"):
+		return newNetworkError("network error", err)
+	}
+	return &CLIError{Code: ExitGenericError, Message: msg}
+}
+
+// writeCLIError renders err to w in the standard stderr format and returns the exit
+// code the process should terminate with.
+func writeCLIError(w io.Writer, err error) int {
+	cliErr := asCLIError(err)
+	if cliErr == nil {
+		return ExitSuccess
+	}
+
+	fmt.Fprintf(w, "cascade: %s\n", cliErr.Message)
+	if cliErr.Cause != nil {
+		fmt.Fprintf(w, "  Cause: %v\n", cliErr.Cause)
+	}
+	if cliErr.Hint != "" {
+		fmt.Fprintf(w, "  Hint: %s\n", cliErr.Hint)
+	}
+	return cliErr.ExitCode()
+}
+
+func containsAny(s string, needles ...string) bool {
+	for _, needle := range needles {
+		if strings.Contains(s, needle) {
+			return true
+		}
+	}
+	return false
+}
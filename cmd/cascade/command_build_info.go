@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/goliatone/cascade/pkg/version"
+	"github.com/spf13/cobra"
+)
+
+// newBuildInfoCommand creates the build-info subcommand
+func newBuildInfoCommand() *cobra.Command {
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "build-info",
+		Short: "Print machine-readable build metadata",
+		Long: `Build-info prints the metadata embedded in this binary at build time. The JSON
+fields mirror goreleaser's metadata.json so release automation and downstream
+packagers (Homebrew, Scoop) can consume it directly.
+
+Examples:
+  cascade build-info
+  cascade build-info --format=env >> "$GITHUB_ENV"`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return printBuildInfo(cmd.OutOrStdout(), version.GetInfo(), format)
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "json", "Output format: json or env")
+
+	return cmd
+}
+
+func printBuildInfo(w io.Writer, info version.Info, format string) error {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(info); err != nil {
+			return newGenericError("failed to encode build info", err)
+		}
+	case "env":
+		fmt.Fprintf(w, "CASCADE_PROJECT_NAME=%s\n", info.ProjectName)
+		fmt.Fprintf(w, "CASCADE_TAG=%s\n", info.Tag)
+		fmt.Fprintf(w, "CASCADE_VERSION=%s\n", info.Version)
+		fmt.Fprintf(w, "CASCADE_COMMIT=%s\n", info.Commit)
+		fmt.Fprintf(w, "CASCADE_DATE=%s\n", info.Date)
+		fmt.Fprintf(w, "CASCADE_GO_VERSION=%s\n", info.GoVersion)
+		fmt.Fprintf(w, "CASCADE_OS=%s\n", info.OS)
+		fmt.Fprintf(w, "CASCADE_ARCH=%s\n", info.Arch)
+	default:
+		return newValidationError(fmt.Sprintf("unsupported build info format %q", format), nil).
+			WithHint("use --format=json or --format=env")
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	execpkg "github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/internal/planner"
+	"github.com/spf13/cobra"
+)
+
+// newCheckCommand creates the check command
+func newCheckCommand() *cobra.Command {
+	var opts checkOptions
+
+	cmd := &cobra.Command{
+		Use:   "check",
+		Short: "Gate a module release on critical dependents passing against it",
+		Long: `Check runs the update and tests of each required dependent against a release
+candidate of the module, and exits with an execution error unless every one of
+them is green. Call it from the module's release workflow before the tag is
+pushed, so a release that breaks a critical dependent is never tagged.
+
+Each dependent runs as in cascade try --no-pr: its settings come from the
+manifest, the update is committed in the workspace worktree only, and nothing
+is pushed, opened, recorded, or notified. A dependent is green when the update
+completes with its tests passing, or when it already requires the version.
+Failed, skipped, and manual-review dependents block the release. Every required
+dependent is checked, so one run reports all of the blocking ones.
+
+Test an untagged release candidate with --local-target pointing at the module
+checkout; the dependents then build against the checkout. The update still
+requires --version, so pass the latest published version with it.
+
+Examples:
+  cascade check --required-green org/app-a,org/app-b --version=v1.2.0-rc.1
+  cascade check --required-green org/app-a --required-green org/app-b --local-target=. --version=v1.1.4
+  cascade check --required-green org/app-a --dry-run`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCheck(cmd.Context(), cmd.OutOrStdout(), opts)
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&opts.RequiredGreen, "required-green", nil, "Dependents that must pass against the release candidate (owner/repo, comma-separated or repeated)")
+	cmd.Flags().StringVar(&opts.ManifestPath, "manifest", "", "Path to dependency manifest file (default: .cascade.yaml)")
+	cmd.Flags().StringVar(&opts.ModulePath, "module", "", "Go module path (e.g., github.com/example/lib). Auto-detected from go.mod if not provided")
+	cmd.Flags().StringVar(&opts.Version, "version", "", "Release candidate version (e.g., v1.2.3-rc.1). Auto-detected from .version file or git tags if not provided")
+	cmd.Flags().StringVar(&opts.LocalTarget, "local-target", "", "Run the tests against this checkout of the module through a go.work instead of the version")
+
+	return cmd
+}
+
+// checkOptions holds the check command settings.
+type checkOptions struct {
+	RequiredGreen []string
+	ManifestPath  string
+	ModulePath    string
+	Version       string
+	LocalTarget   string
+}
+
+// checkOutcome is the result of checking one required dependent.
+type checkOutcome struct {
+	Repo   string
+	Green  bool
+	Status execpkg.Status
+	Reason string
+}
+
+func runCheck(ctx context.Context, w io.Writer, opts checkOptions) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	cfg := container.Config()
+
+	var repos []string
+	for _, repo := range opts.RequiredGreen {
+		if repo = strings.TrimSpace(repo); repo != "" {
+			repos = append(repos, repo)
+		}
+	}
+	if len(repos) == 0 {
+		return newValidationError("no required dependents given", nil).
+			WithHint("pass --required-green owner/repo[,owner/repo...]")
+	}
+
+	manifestData, target, err := resolveTryTarget(ctx, opts.ManifestPath, opts.ModulePath, opts.Version)
+	if err != nil {
+		return err
+	}
+	local, err := resolveLocalTarget(opts.LocalTarget, []planner.Target{target})
+	if err != nil {
+		return err
+	}
+	localDir := ""
+	if local != nil {
+		localDir = local.dir
+	}
+
+	items := make([]planner.WorkItem, 0, len(repos))
+	for _, repo := range repos {
+		item, listed, err := planTryItem(ctx, manifestData, target, repo, cfg)
+		if err != nil {
+			return err
+		}
+		if !listed {
+			fmt.Fprintf(w, "%s %s is not a dependent of %s in the manifest; checking it with the manifest defaults\n", style.mark(markReview), repo, target.Module)
+		}
+		items = append(items, item)
+	}
+
+	if cfg.Executor.DryRun {
+		fmt.Fprintf(w, "DRY RUN: Would check %s@%s against %d required dependent(s)\n", target.Module, target.Version, len(items))
+		for _, item := range items {
+			fmt.Fprintf(w, "  %s (%s)\n", item.Repo, item.Module)
+		}
+		if localDir != "" {
+			fmt.Fprintf(w, "Would test against the checkout in %s\n", localDir)
+		}
+		return nil
+	}
+
+	if err := ensureWorkspace(cfg.Workspace.Path); err != nil {
+		return newExecutionError("failed to prepare workspace", err)
+	}
+
+	fmt.Fprintf(w, "Checking %s@%s against %d required dependent(s)\n", target.Module, target.Version, len(items))
+	if localDir != "" {
+		fmt.Fprintf(w, "Testing against the checkout in %s\n", localDir)
+	}
+	deps := newExecutionDeps(cfg)
+	git := unpushedGit{GitOperations: deps.git}
+	outcomes := make([]checkOutcome, 0, len(items))
+	for _, item := range items {
+		if err := ctx.Err(); err != nil {
+			return newInterruptError("check interrupted", err)
+		}
+		fmt.Fprintf(w, "%s (%s)\n", item.Repo, item.Module)
+		result, execErr := applyTryItem(ctx, w, deps, git, item, manifestData, localDir)
+		outcome := checkItemOutcome(item.Repo, result, execErr)
+		if result != nil {
+			printTryCommands(w, "test", result.TestResults)
+			printTryCommands(w, "extra", result.ExtraResults)
+		}
+		outcomes = append(outcomes, outcome)
+	}
+
+	var blocking []string
+	fmt.Fprintln(w)
+	for _, outcome := range outcomes {
+		if outcome.Green {
+			fmt.Fprintf(w, "%s %s: green\n", style.mark(markOK), outcome.Repo)
+			continue
+		}
+		blocking = append(blocking, outcome.Repo)
+		fmt.Fprintf(w, "%s %s: %s: %s\n", style.mark(markFailed), outcome.Repo, outcome.Status, outcome.Reason)
+	}
+	if len(blocking) > 0 {
+		return newExecutionError(fmt.Sprintf("%d of %d required dependents are not green against %s@%s: %s",
+			len(blocking), len(outcomes), target.Module, target.Version, strings.Join(blocking, ", ")), nil).
+			WithHint("fix the release candidate or the dependents before tagging; `cascade try <owner/repo> --no-pr` reruns one")
+	}
+	fmt.Fprintf(w, "%s All %d required dependents are green against %s@%s\n", style.mark(markOK), len(outcomes), target.Module, target.Version)
+	return nil
+}
+
+// checkItemOutcome classifies the result of one required dependent. Only a
+// completed update or a dependent already on the version is green.
+func checkItemOutcome(repo string, result *execpkg.Result, execErr error) checkOutcome {
+	outcome := checkOutcome{Repo: repo, Status: execpkg.StatusFailed}
+	if result != nil {
+		outcome.Status, outcome.Reason = result.Status, result.Reason
+	}
+	if execErr != nil {
+		outcome.Status = execpkg.StatusFailed
+		if outcome.Reason == "" {
+			outcome.Reason = execErr.Error()
+		}
+		return outcome
+	}
+	switch {
+	case result == nil:
+		outcome.Reason = "the executor returned no result"
+	case outcome.Status == execpkg.StatusCompleted || outcome.Status == execpkg.StatusNoChange:
+		outcome.Green = true
+	case outcome.Reason == "":
+		outcome.Reason = "no reason given"
+	}
+	return outcome
+}
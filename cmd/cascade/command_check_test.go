@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	execpkg "github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/pkg/config"
+	"github.com/goliatone/cascade/pkg/di"
+)
+
+func TestRunCheck(t *testing.T) {
+	dir := t.TempDir()
+	manifestPath := filepath.Join(dir, ".cascade.yaml")
+	manifestYAML := `manifest_version: 1
+defaults:
+  branch: main
+modules:
+  - module: github.com/example/lib
+    dependents:
+      - repo: example/api
+        module: github.com/example/api
+        module_path: .
+      - repo: example/web
+        module: github.com/example/web
+        module_path: .
+`
+	if err := os.WriteFile(manifestPath, []byte(manifestYAML), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var applied []string
+	exec := &mockExecutor{applyFunc: func(ctx context.Context, input execpkg.WorkItemContext) (*execpkg.Result, error) {
+		applied = append(applied, input.Item.Repo)
+		if _, ok := input.Git.(unpushedGit); !ok {
+			t.Errorf("%s checked with git that pushes", input.Item.Repo)
+		}
+		if input.Item.Repo == "example/web" {
+			return &execpkg.Result{Status: execpkg.StatusFailed, Reason: "tests failed"}, errors.New("go test failed")
+		}
+		return &execpkg.Result{Status: execpkg.StatusCompleted}, nil
+	}}
+
+	cfg := config.New()
+	cfg.Workspace.Path = filepath.Join(dir, "workspace")
+	testContainer, err := di.New(di.WithConfig(cfg), di.WithLogger(&mockLogger{}), di.WithExecutor(exec))
+	if err != nil {
+		t.Fatalf("di.New() error = %v", err)
+	}
+	originalContainer := container
+	container = testContainer
+	defer func() { container = originalContainer }()
+
+	opts := checkOptions{ManifestPath: manifestPath, ModulePath: "github.com/example/lib", Version: "v1.2.0-rc.1"}
+	var out bytes.Buffer
+	err = runCheck(context.Background(), &out, opts)
+	var cliErr *CLIError
+	if !errors.As(err, &cliErr) || cliErr.Code != ExitValidationError {
+		t.Fatalf("expected a validation error without required dependents, got %v", err)
+	}
+
+	opts.RequiredGreen = []string{"example/api", " example/web "}
+	err = runCheck(context.Background(), &out, opts)
+	if !errors.As(err, &cliErr) || cliErr.Code != ExitExecutionError || !strings.Contains(cliErr.Message, "1 of 2 required dependents are not green") {
+		t.Fatalf("expected the failing dependent to block the release, got %v", err)
+	}
+	if len(applied) != 2 {
+		t.Fatalf("applied = %v, want both dependents checked", applied)
+	}
+	for _, want := range []string{"example/api: green", "example/web: failed: tests failed"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output = %q, want %q", out.String(), want)
+		}
+	}
+
+	out.Reset()
+	opts.RequiredGreen = []string{"example/api"}
+	if err := runCheck(context.Background(), &out, opts); err != nil {
+		t.Fatalf("runCheck() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "All 1 required dependents are green against github.com/example/lib@v1.2.0-rc.1") {
+		t.Errorf("output = %q, want the release unblocked", out.String())
+	}
+}
+
+func TestCheckItemOutcome(t *testing.T) {
+	tests := []struct {
+		name   string
+		result *execpkg.Result
+		err    error
+		green  bool
+		status execpkg.Status
+	}{
+		{"completed", &execpkg.Result{Status: execpkg.StatusCompleted}, nil, true, execpkg.StatusCompleted},
+		{"already on version", &execpkg.Result{Status: execpkg.StatusNoChange}, nil, true, execpkg.StatusNoChange},
+		{"manual review", &execpkg.Result{Status: execpkg.StatusManualReview, Reason: "major bump"}, nil, false, execpkg.StatusManualReview},
+		{"skipped", &execpkg.Result{Status: execpkg.StatusSkipped}, nil, false, execpkg.StatusSkipped},
+		{"error", &execpkg.Result{Status: execpkg.StatusCompleted}, errors.New("boom"), false, execpkg.StatusFailed},
+		{"no result", nil, nil, false, execpkg.StatusFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outcome := checkItemOutcome("example/api", tt.result, tt.err)
+			if outcome.Green != tt.green || outcome.Status != tt.status {
+				t.Errorf("checkItemOutcome() = %+v, want green %v status %s", outcome, tt.green, tt.status)
+			}
+			if !outcome.Green && outcome.Reason == "" {
+				t.Errorf("checkItemOutcome() = %+v, want a reason", outcome)
+			}
+		})
+	}
+}
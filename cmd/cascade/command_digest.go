@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/goliatone/cascade/internal/broker"
+	"github.com/spf13/cobra"
+)
+
+// newDigestCommand creates the digest command
+func newDigestCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "digest",
+		Short: "Manage batched notification digests",
+		Long: `Digest manages the notifications held back for digests when
+integration.notifications.digest.window is set.`,
+	}
+
+	cmd.AddCommand(newDigestFlushCommand())
+	return cmd
+}
+
+// newDigestFlushCommand creates the digest flush subcommand
+func newDigestFlushCommand() *cobra.Command {
+	var all bool
+
+	cmd := &cobra.Command{
+		Use:   "flush",
+		Short: "Send notification digests whose window elapsed",
+		Long: `Flush sends the notifications held back for a digest once the digest window
+elapsed since the first of them, as one message per channel. A notification
+sent after the window elapsed does this too; run flush on a schedule so the
+last notifications of a quiet period are not held back until the next cascade.
+
+With --all, every pending digest is sent whether its window elapsed or not.
+
+Examples:
+  cascade digest flush
+  cascade digest flush --all`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDigestFlush(cmd.Context(), cmd.OutOrStdout(), all)
+		},
+	}
+
+	cmd.Flags().BoolVar(&all, "all", false, "Send every pending digest, even if its window has not elapsed")
+
+	return cmd
+}
+
+func runDigestFlush(ctx context.Context, w io.Writer, all bool) error {
+	cfg := container.Config()
+	if cfg.Integration.Notifications.Digest.Window <= 0 {
+		return newValidationError("notification digests are not enabled", nil).
+			WithHint("set integration.notifications.digest.window, e.g. to 1h")
+	}
+
+	flusher, ok := container.Broker().(broker.DigestFlusher)
+	if !ok {
+		return newExecutionError("the configured broker cannot send digests", nil)
+	}
+	sent, err := flusher.FlushDigests(ctx, all)
+	if err != nil {
+		return newExecutionError("failed to send notification digests", err)
+	}
+
+	switch {
+	case cfg.Executor.DryRun:
+		fmt.Fprintln(w, "Dry run: no digests sent")
+	case sent == 0:
+		fmt.Fprintln(w, "No digests due")
+	case sent == 1:
+		fmt.Fprintf(w, "%s Sent 1 notification in a digest\n", style.mark(markOK))
+	default:
+		fmt.Fprintf(w, "%s Sent %d notifications in digests\n", style.mark(markOK), sent)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/internal/planner"
+	"github.com/goliatone/cascade/pkg/config"
+	"github.com/goliatone/cascade/pkg/di"
+)
+
+func TestRunDigestFlush(t *testing.T) {
+	cfg := config.New()
+	cfg.State.Dir = t.TempDir()
+	cfg.Integration.Provider = config.ProviderSandbox
+	testContainer, err := di.New(di.WithConfig(cfg), di.WithLogger(&mockLogger{}))
+	if err != nil {
+		t.Fatalf("di.New() error = %v", err)
+	}
+	originalContainer := container
+	container = testContainer
+	defer func() { container = originalContainer }()
+
+	var out bytes.Buffer
+	err = runDigestFlush(context.Background(), &out, false)
+	var cliErr *CLIError
+	if !errors.As(err, &cliErr) || !strings.Contains(cliErr.Hint, "digest.window") {
+		t.Fatalf("expected a CLI error with a digest window hint, got %v", err)
+	}
+
+	cfg.Integration.Notifications.Digest.Window = time.Hour
+	testContainer, err = di.New(di.WithConfig(cfg), di.WithLogger(&mockLogger{}))
+	if err != nil {
+		t.Fatalf("di.New() error = %v", err)
+	}
+	container = testContainer
+
+	item := planner.WorkItem{Module: "github.com/example/lib", Repo: "example/api"}
+	if _, err := container.Broker().Notify(context.Background(), item, &executor.Result{Status: executor.StatusCompleted}); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	if err := runDigestFlush(context.Background(), &out, false); err != nil {
+		t.Fatalf("runDigestFlush() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "No digests due") {
+		t.Errorf("output = %q, want nothing due", out.String())
+	}
+
+	out.Reset()
+	if err := runDigestFlush(context.Background(), &out, true); err != nil {
+		t.Fatalf("runDigestFlush(all) error = %v", err)
+	}
+	if !strings.Contains(out.String(), "Sent 1 notification in a digest") {
+		t.Errorf("output = %q, want one notification sent", out.String())
+	}
+	log, err := os.ReadFile(filepath.Join(cfg.State.Dir, "sandbox", "notifications.jsonl"))
+	if err != nil || !strings.Contains(string(log), `"event":"digest"`) {
+		t.Errorf("sandbox notifications = %s, %v, want a digest", log, err)
+	}
+}
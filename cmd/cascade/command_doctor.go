@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/goliatone/cascade/pkg/config"
+	"github.com/goliatone/cascade/pkg/di"
+	"github.com/goliatone/cascade/pkg/doctor"
+	"github.com/goliatone/cascade/pkg/gitutil"
+	"github.com/spf13/cobra"
+)
+
+// newDoctorCommand creates the doctor command
+func newDoctorCommand() *cobra.Command {
+	var (
+		manifestPath string
+		jsonOutput   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check that the environment is ready to run cascades",
+		Long: `Doctor checks the tools and credentials cascade depends on and prints a fix
+for each problem: git and the Go toolchain, the GitHub token and its scopes (with
+a test API call), Slack credentials, the state directory, free space in the
+workspace, and the manifest.
+
+Warnings point at things that may fail or degrade later; the command fails only
+when a check fails, so it can gate CI jobs.
+
+Examples:
+  cascade doctor
+  cascade doctor --manifest deps.yaml --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDoctor(cmd.Context(), cmd.OutOrStdout(), manifestPath, jsonOutput)
+		},
+	}
+
+	cmd.Flags().StringVar(&manifestPath, "manifest", "", "Manifest file path (default: .cascade.yaml)")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Print the results as JSON")
+
+	return cmd
+}
+
+func runDoctor(ctx context.Context, w io.Writer, manifestPath string, jsonOutput bool) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	cfg := container.Config()
+	results := doctor.Run(ctx, doctorEnv(cfg, manifestPath), doctor.Checks())
+
+	if jsonOutput {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(results); err != nil {
+			return newGenericError("failed to encode doctor results", err)
+		}
+	} else {
+		printDoctorResults(w, results)
+	}
+
+	if doctor.Failed(results) {
+		return newConfigError("the environment is not ready to run cascades", nil).
+			WithHint("apply the fixes above and run `cascade doctor` again")
+	}
+	return nil
+}
+
+// doctorEnv gathers what the checks inspect, resolved the way release and
+// plan resolve them.
+func doctorEnv(cfg *config.Config, manifestPath string) doctor.Env {
+	env := doctor.Env{
+		Config:       cfg,
+		HTTPClient:   container.HTTPClient(),
+		ManifestPath: resolvePlanManifestPath(manifestPath, "", cfg),
+		StateDir:     di.StateDir(cfg),
+		GitHubToken:  gitutil.GetGitHubToken(),
+	}
+	if cfg != nil {
+		if token := strings.TrimSpace(cfg.Integration.GitHub.Token); token != "" {
+			env.GitHubToken = token
+		}
+	}
+	return env
+}
+
+func printDoctorResults(w io.Writer, results []doctor.Result) {
+	counts := make(map[doctor.Status]int)
+	for _, result := range results {
+		counts[result.Status]++
+		fmt.Fprintf(w, "%s %s: %s\n", style.mark(doctorMarker(result.Status)), result.Check, result.Message)
+		if result.Fix != "" && result.Status != doctor.StatusOK {
+			fmt.Fprintf(w, "    fix: %s\n", result.Fix)
+		}
+	}
+	fmt.Fprintf(w, "\n%d check(s): %d ok, %d warning(s), %d failed, %d skipped\n",
+		len(results), counts[doctor.StatusOK], counts[doctor.StatusWarning], counts[doctor.StatusFailed], counts[doctor.StatusSkipped])
+}
+
+func doctorMarker(status doctor.Status) marker {
+	switch status {
+	case doctor.StatusOK:
+		return markOK
+	case doctor.StatusWarning:
+		return markWarning
+	case doctor.StatusSkipped:
+		return markSkipped
+	default:
+		return markFailed
+	}
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/goliatone/cascade/internal/state"
+	"github.com/spf13/cobra"
+)
+
+// newExplainErrorCommand creates the explain-error command
+func newExplainErrorCommand() *cobra.Command {
+	var (
+		stateID    string
+		repo       string
+		jsonOutput bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "explain-error [exit-code | status | reason]",
+		Short: "Explain an exit code, item status, or failure reason",
+		Long: `Explain-error prints what an exit code, an item status, or an item's failure
+reason means, its common causes, and the next steps to take.
+
+Pass an exit code (0-10), a status recorded in state (completed, failed,
+manual-review, skipped, no-change), or the text of a reason as shown by
+cascade status or cascade state show. With --state and --repo, the status and
+reason recorded for that dependent are explained.
+
+Examples:
+  cascade explain-error 8
+  cascade explain-error manual-review
+  cascade explain-error "git push failed: permission denied"
+  cascade explain-error --state go-errors@v1.4.0 --repo acme/billing-api`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runExplainError(cmd.OutOrStdout(), args, stateID, repo, jsonOutput)
+		},
+	}
+
+	cmd.Flags().StringVar(&stateID, "state", "", "Recorded cascade to read the item from, as module@version (default: --module and --version)")
+	cmd.Flags().StringVar(&repo, "repo", "", "Explain the status and reason recorded for this dependent")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Print the explanations as JSON")
+
+	return cmd
+}
+
+func runExplainError(w io.Writer, args []string, stateID, repo string, jsonOutput bool) error {
+	var (
+		guides []errorGuide
+		err    error
+	)
+	switch {
+	case strings.TrimSpace(repo) != "":
+		if len(args) > 0 {
+			return newValidationError("pass either an error to explain or --repo, not both", nil)
+		}
+		guides, err = explainRecordedItem(w, stateID, strings.TrimSpace(repo), jsonOutput)
+	case len(args) == 0:
+		return newValidationError("nothing to explain", nil).
+			WithHint("pass an exit code, a status, or a reason, or use --state and --repo")
+	default:
+		guides, err = explainInput(strings.TrimSpace(strings.Join(args, " ")))
+	}
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(guides); err != nil {
+			return newGenericError("failed to encode explanations", err)
+		}
+		return nil
+	}
+	renderErrorGuides(w, guides)
+	return nil
+}
+
+// explainInput explains an exit code, a status, or a reason.
+func explainInput(input string) ([]errorGuide, error) {
+	if code, err := strconv.Atoi(input); err == nil {
+		guide, ok := explainExitCode(code)
+		if !ok {
+			return nil, newValidationError(fmt.Sprintf("cascade does not exit with code %d", code), nil).
+				WithHint("exit codes range from %d to %d", ExitSuccess, ExitResourceError)
+		}
+		return []errorGuide{guide}, nil
+	}
+	if guide, ok := explainStatus(input); ok {
+		return []errorGuide{guide}, nil
+	}
+	if guides := explainReason(input); len(guides) > 0 {
+		return guides, nil
+	}
+	return nil, newValidationError(fmt.Sprintf("no explanation found for %q", input), nil).
+		WithHint("pass an exit code, a status such as failed, or a reason recorded by cascade state show")
+}
+
+// explainRecordedItem explains the status and reason recorded for repo. The
+// item's outcome is printed first unless the output is JSON.
+func explainRecordedItem(w io.Writer, stateID, repo string, jsonOutput bool) ([]errorGuide, error) {
+	module, version, err := resolveModuleVersion(stateID, container.Config())
+	if err != nil {
+		return nil, newValidationError(err.Error(), nil)
+	}
+
+	items, err := container.State().LoadItemStates(module, version)
+	if err != nil {
+		if errors.Is(err, state.ErrNotFound) {
+			return nil, newStateError(fmt.Sprintf("no saved state found for %s@%s", module, version), nil).
+				WithHint("list recorded cascades with `cascade status`")
+		}
+		return nil, newStateError("failed to load item states", err)
+	}
+
+	for _, item := range items {
+		if !strings.EqualFold(item.Repo, repo) {
+			continue
+		}
+		var guides []errorGuide
+		if guide, ok := explainStatus(string(item.Status)); ok {
+			guides = append(guides, guide)
+		}
+		guides = append(guides, explainReason(item.Reason)...)
+		if !jsonOutput {
+			fmt.Fprintf(w, "%s in %s@%s: %s\n", item.Repo, module, version, item.Status)
+			if reason := strings.TrimSpace(item.Reason); reason != "" {
+				fmt.Fprintf(w, "  reason: %s\n", reason)
+			}
+			fmt.Fprintln(w)
+		}
+		return guides, nil
+	}
+	return nil, newStateError(fmt.Sprintf("%s has no recorded item for %s@%s", repo, module, version), nil).
+		WithHint("list the recorded items with `cascade state show %s@%s`", module, version)
+}
+
+func renderErrorGuides(w io.Writer, guides []errorGuide) {
+	for i, guide := range guides {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "%s\n  %s\n", guide.Topic, guide.Summary)
+		if len(guide.Causes) > 0 {
+			fmt.Fprintln(w, "\n  Common causes:")
+			for _, cause := range guide.Causes {
+				fmt.Fprintf(w, "    - %s\n", cause)
+			}
+		}
+		if len(guide.NextSteps) > 0 {
+			fmt.Fprintln(w, "\n  Next steps:")
+			for _, step := range guide.NextSteps {
+				fmt.Fprintf(w, "    - %s\n", step)
+			}
+		}
+	}
+}
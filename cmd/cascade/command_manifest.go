@@ -2,8 +2,10 @@ package main
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -12,6 +14,7 @@ import (
 	"github.com/goliatone/cascade/internal/planner"
 	"github.com/goliatone/cascade/pkg/config"
 	"github.com/goliatone/cascade/pkg/di"
+	"github.com/goliatone/cascade/pkg/workspace/layout"
 	"github.com/spf13/cobra"
 )
 
@@ -24,10 +27,106 @@ func newManifestCommand() *cobra.Command {
 Use subcommands to perform specific manifest operations.`,
 	}
 
-	cmd.AddCommand(newManifestGenerateCommand())
+	cmd.AddCommand(newManifestGenerateCommand(), newManifestGraphCommand(), newManifestValidateCommand())
 	return cmd
 }
 
+// newManifestGraphCommand creates the manifest graph subcommand
+func newManifestGraphCommand() *cobra.Command {
+	var (
+		manifestPath string
+		format       string
+		outputPath   string
+		strict       bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "graph [manifest]",
+		Short: "Render the manifest's module and dependent relationships",
+		Long: `Graph renders the modules and dependents declared in a manifest as a Graphviz
+DOT or Mermaid diagram. Skipped dependents are drawn dashed and canaries bold. A
+dependent that is itself a module in the manifest shares that module's node, so
+release chains read left to right.
+
+Entries that are likely mistakes are highlighted and listed on stderr: modules
+without dependents, duplicate module names or paths, dependents listed twice under
+the same module, and dependents overrides keyed by an undeclared module. Use
+--strict to fail when any are found.
+
+Examples:
+  cascade manifest graph                                # DOT for .cascade.yaml
+  cascade manifest graph --format=mermaid               # Mermaid flowchart
+  cascade manifest graph deps.yaml --output=deps.dot    # Write to a file
+  cascade manifest graph | dot -Tsvg > manifest.svg     # Render with Graphviz`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			manifestArg := ""
+			if len(args) > 0 {
+				manifestArg = args[0]
+			}
+			return runManifestGraph(cmd.OutOrStdout(), cmd.ErrOrStderr(), manifestPath, manifestArg, format, outputPath, strict)
+		},
+	}
+
+	cmd.Flags().StringVar(&manifestPath, "manifest", "", "Manifest file path (default: .cascade.yaml)")
+	cmd.Flags().StringVar(&format, "format", "dot", "Output format: dot or mermaid")
+	cmd.Flags().StringVar(&outputPath, "output", "", "Write the graph to a file instead of stdout")
+	cmd.Flags().BoolVar(&strict, "strict", false, "Fail when orphaned or duplicate entries are found")
+
+	return cmd
+}
+
+func runManifestGraph(stdout, stderr io.Writer, manifestFlag, manifestArg, format, outputPath string, strict bool) error {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format != "dot" && format != "mermaid" {
+		return newValidationError(fmt.Sprintf("unsupported graph format %q", format), nil).
+			WithHint("use --format=dot or --format=mermaid")
+	}
+
+	manifestPath := resolvePlanManifestPath(manifestFlag, manifestArg, container.Config())
+	m, err := container.Manifest().Load(manifestPath)
+	if err != nil {
+		return newFileError("failed to load manifest", err).
+			WithHint("create one with `cascade manifest generate` or pass --manifest")
+	}
+
+	graph := manifest.BuildGraph(m)
+
+	var buf bytes.Buffer
+	if format == "mermaid" {
+		err = graph.WriteMermaid(&buf)
+	} else {
+		err = graph.WriteDOT(&buf)
+	}
+	if err != nil {
+		return newGenericError("failed to render manifest graph", err)
+	}
+
+	if outputPath == "" {
+		if _, err := stdout.Write(buf.Bytes()); err != nil {
+			return newGenericError("failed to write manifest graph", err)
+		}
+	} else {
+		if err := os.WriteFile(outputPath, buf.Bytes(), 0o644); err != nil {
+			return newFileError("failed to write manifest graph", err)
+		}
+		fmt.Fprintf(stderr, "Wrote %s graph to %s\n", format, outputPath)
+	}
+
+	if len(graph.Issues) == 0 {
+		return nil
+	}
+
+	fmt.Fprintf(stderr, "\n%s %d manifest issue(s):\n", style.mark(markWarning), len(graph.Issues))
+	for _, issue := range graph.Issues {
+		fmt.Fprintf(stderr, "  - %s\n", issue)
+	}
+	if strict {
+		return newValidationError(fmt.Sprintf("manifest graph has %d issue(s)", len(graph.Issues)), nil)
+	}
+	return nil
+}
+
 // newManifestGenerateCommand creates the manifest generate subcommand
 func newManifestGenerateCommand() *cobra.Command {
 	req := manifestGenerateRequest{}
@@ -74,6 +173,7 @@ Examples:
 	// Output and dependent configuration
 	cmd.Flags().StringVar(&req.OutputPath, "output", "", "Output file path (default: .cascade.yaml)")
 	cmd.Flags().StringSliceVar(&req.Dependents, "dependents", []string{}, "Dependent repositories (format: owner/repo). If omitted, discovers dependents in workspace")
+	cmd.Flags().StringSliceVar(&req.Aliases, "aliases", []string{}, "Former module paths that dependents may still require (e.g., after a rename)")
 	cmd.Flags().StringVar(&req.SlackChannel, "slack-channel", "", "Default Slack notification channel")
 	cmd.Flags().StringVar(&req.Webhook, "webhook", "", "Default webhook URL for notifications")
 
@@ -89,7 +189,7 @@ func runManifestGenerate(options manifestGenerateRequest) error {
 	return manifestGenerate(context.Background(), options, container.Config())
 }
 
-func filterDiscoveredDependents(discovered []manifest.DependentOptions, targetModule, targetVersion, workspaceDir string, logger di.Logger) ([]manifest.DependentOptions, []manifest.DependentOptions) {
+func filterDiscoveredDependents(discovered []manifest.DependentOptions, targetModule, targetVersion, workspaceDir string, workspaceLayout layout.Layout, logger di.Logger) ([]manifest.DependentOptions, []manifest.DependentOptions) {
 	if len(discovered) == 0 {
 		return discovered, nil
 	}
@@ -104,7 +204,7 @@ func filterDiscoveredDependents(discovered []manifest.DependentOptions, targetMo
 		}
 
 		if dep.DiscoverySource == "workspace" && targetVersion != "" && workspaceDir != "" {
-			upToDate, err := workspaceDependentIsUpToDate(dep, targetModule, targetVersion, workspaceDir)
+			upToDate, err := workspaceDependentIsUpToDate(dep, targetModule, targetVersion, workspaceDir, workspaceLayout)
 			if err != nil && logger != nil {
 				logger.Debug("workspace version check failed",
 					"repo", dep.Repository,
@@ -122,13 +222,54 @@ func filterDiscoveredDependents(discovered []manifest.DependentOptions, targetMo
 	return filtered, skipped
 }
 
-func workspaceDependentIsUpToDate(dep manifest.DependentOptions, targetModule, targetVersion, workspaceDir string) (bool, error) {
-	repoParts := strings.Split(dep.Repository, "/")
-	if len(repoParts) == 0 {
+// dropNeverTouched removes the discovered dependents listed in neverTouch,
+// warning about each so the gap in the generated manifest is explained.
+func dropNeverTouched(discovered []manifest.DependentOptions, neverTouch []string, logger di.Logger) []manifest.DependentOptions {
+	if len(neverTouch) == 0 {
+		return discovered
+	}
+	kept := make([]manifest.DependentOptions, 0, len(discovered))
+	for _, dep := range discovered {
+		if err := manifest.NeverTouch(neverTouch, dep.Repository, dep.CloneURL); err != nil {
+			if logger != nil {
+				logger.Warn("Leaving out dependent listed in defaults.never_touch", "repo", dep.Repository, "error", err)
+			}
+			continue
+		}
+		kept = append(kept, dep)
+	}
+	return kept
+}
+
+// existingNeverTouch returns defaults.never_touch of the manifest at path, or
+// nil when there is no manifest there yet.
+func existingNeverTouch(path string, logger di.Logger) []string {
+	if _, err := os.Stat(path); err != nil {
+		return nil
+	}
+	existing, err := container.Manifest().Load(path)
+	if err != nil {
+		if logger != nil {
+			logger.Debug("existing manifest could not be loaded for never_touch", "path", path, "error", err)
+		}
+		return nil
+	}
+	return existing.Defaults.NeverTouch
+}
+
+func workspaceDependentIsUpToDate(dep manifest.DependentOptions, targetModule, targetVersion, workspaceDir string, workspaceLayout layout.Layout) (bool, error) {
+	locations := workspaceLayout.Locations(workspaceDir, dep.Repository)
+	if len(locations) == 0 {
 		return false, fmt.Errorf("invalid repository: %s", dep.Repository)
 	}
 
-	repoDir := filepath.Join(workspaceDir, repoParts[len(repoParts)-1])
+	repoDir := locations[0]
+	for _, dir := range locations {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			repoDir = dir
+			break
+		}
+	}
 	moduleDir := repoDir
 	if dep.LocalModulePath != "" && dep.LocalModulePath != "." {
 		moduleDir = filepath.Join(repoDir, dep.LocalModulePath)
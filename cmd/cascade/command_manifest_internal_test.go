@@ -1,11 +1,18 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/goliatone/cascade/internal/manifest"
+	"github.com/goliatone/cascade/pkg/config"
+	"github.com/goliatone/cascade/pkg/di"
+	"github.com/goliatone/cascade/pkg/workspace/layout"
 )
 
 func TestFilterDiscoveredDependents_DropsSelfModule(t *testing.T) {
@@ -24,7 +31,7 @@ func TestFilterDiscoveredDependents_DropsSelfModule(t *testing.T) {
 		},
 	}
 
-	filtered, skipped := filterDiscoveredDependents(deps, "github.com/goliatone/go-errors", "v0.9.0", "/workspace", nil)
+	filtered, skipped := filterDiscoveredDependents(deps, "github.com/goliatone/go-errors", "v0.9.0", "/workspace", layout.Layout{}, nil)
 
 	if len(filtered) != 1 {
 		t.Fatalf("expected 1 filtered dependent, got %d", len(filtered))
@@ -64,7 +71,7 @@ func TestFilterDiscoveredDependents_DropsUpToDateWorkspace(t *testing.T) {
 		},
 	}
 
-	filtered, skipped := filterDiscoveredDependents(deps, "github.com/goliatone/go-errors", "v0.9.0", tempDir, nil)
+	filtered, skipped := filterDiscoveredDependents(deps, "github.com/goliatone/go-errors", "v0.9.0", tempDir, layout.Layout{}, nil)
 
 	if len(filtered) != 1 {
 		t.Fatalf("expected 1 filtered dependent, got %d", len(filtered))
@@ -79,3 +86,123 @@ func TestFilterDiscoveredDependents_DropsUpToDateWorkspace(t *testing.T) {
 		t.Fatalf("expected skipped repo to be go/up-to-date, got %s", skipped[0].Repository)
 	}
 }
+
+func TestRunManifestGraph(t *testing.T) {
+	dir := t.TempDir()
+	manifestPath := filepath.Join(dir, ".cascade.yaml")
+	content := `manifest_version: 1
+modules:
+  - name: lib
+    module: github.com/example/lib
+    repo: example/lib
+    dependents:
+      - repo: example/app
+        module: github.com/example/app
+        module_path: .
+        skip: true
+  - name: orphan
+    module: github.com/example/orphan
+    repo: example/orphan
+`
+	if err := os.WriteFile(manifestPath, []byte(content), 0o644); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+
+	testContainer, err := di.New(di.WithConfig(config.New()), di.WithLogger(&mockLogger{}))
+	if err != nil {
+		t.Fatalf("failed to create container: %v", err)
+	}
+	originalContainer := container
+	container = testContainer
+	defer func() { container = originalContainer }()
+
+	var stdout, stderr bytes.Buffer
+	if err := runManifestGraph(&stdout, &stderr, manifestPath, "", "mermaid", "", false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "n0 -. skip .-> n2") {
+		t.Errorf("expected skipped dependent edge, got:\n%s", stdout.String())
+	}
+	if !strings.Contains(stderr.String(), "module orphan has no dependents") {
+		t.Errorf("expected orphan warning on stderr, got:\n%s", stderr.String())
+	}
+
+	outputPath := filepath.Join(dir, "graph.dot")
+	stdout.Reset()
+	err = runManifestGraph(&stdout, &stderr, manifestPath, "", "dot", outputPath, true)
+	var cliErr *CLIError
+	if !errors.As(err, &cliErr) {
+		t.Fatalf("expected CLIError in strict mode, got %v", err)
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("expected no stdout when writing to a file, got %q", stdout.String())
+	}
+	data, readErr := os.ReadFile(outputPath)
+	if readErr != nil || !strings.HasPrefix(string(data), "digraph cascade {") {
+		t.Errorf("expected DOT file to be written, got %q (%v)", data, readErr)
+	}
+
+	if err := runManifestGraph(&stdout, &stderr, manifestPath, "", "svg", "", false); err == nil {
+		t.Error("expected unsupported format error")
+	}
+}
+
+func TestRunManifestValidate(t *testing.T) {
+	dir := t.TempDir()
+	manifestPath := filepath.Join(dir, ".cascade.yaml")
+	content := `manifest_version: 1
+modules:
+  - name: lib
+    module: github.com/example/lib
+    repo: example/lib
+    dependents:
+      - repo: example/app
+        module: github.com/example/app
+        module_path: .
+`
+	if err := os.WriteFile(manifestPath, []byte(content), 0o644); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+
+	testContainer, err := di.New(di.WithConfig(config.New()), di.WithLogger(&mockLogger{}))
+	if err != nil {
+		t.Fatalf("failed to create container: %v", err)
+	}
+	originalContainer := container
+	container = testContainer
+	defer func() { container = originalContainer }()
+
+	var stdout bytes.Buffer
+	if err := runManifestValidate(&stdout, manifestPath, "", false); err != nil {
+		t.Fatalf("runManifestValidate() error = %v", err)
+	}
+	if !strings.Contains(stdout.String(), "is valid") {
+		t.Errorf("unexpected output: %q", stdout.String())
+	}
+
+	invalid := strings.Replace(content, "module_path: .", "module_path: .\n        timout: 5m", 1)
+	if err := os.WriteFile(manifestPath, []byte(invalid), 0o644); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+	stdout.Reset()
+	err = runManifestValidate(&stdout, manifestPath, "", false)
+	var cliErr *CLIError
+	if !errors.As(err, &cliErr) || cliErr.Code != ExitValidationError {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+	if want := manifestPath + `:10:9: modules[0].dependents[0]: unknown field "timout"`; strings.TrimSpace(stdout.String()) != want {
+		t.Errorf("output = %q, want %q", stdout.String(), want)
+	}
+
+	stdout.Reset()
+	_ = runManifestValidate(&stdout, "", manifestPath, true)
+	var diagnostics []manifest.Diagnostic
+	if err := json.Unmarshal(stdout.Bytes(), &diagnostics); err != nil || len(diagnostics) != 1 || diagnostics[0].Line != 10 {
+		t.Errorf("JSON output = %s (%v)", stdout.String(), err)
+	}
+
+	err = runManifestValidate(&stdout, filepath.Join(dir, "missing.yaml"), "", false)
+	if !errors.As(err, &cliErr) || cliErr.Code != ExitFileError {
+		t.Errorf("expected a file error, got %v", err)
+	}
+}
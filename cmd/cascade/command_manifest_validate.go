@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/goliatone/cascade/internal/manifest"
+	"github.com/spf13/cobra"
+)
+
+// newManifestValidateCommand creates the manifest validate subcommand
+func newManifestValidateCommand() *cobra.Command {
+	var (
+		manifestPath string
+		jsonOutput   bool
+		printSchema  bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "validate [manifest]",
+		Short: "Check a manifest against the manifest schema",
+		Long: `Validate checks a manifest against the manifest schema and reports each
+problem with its line and column: YAML syntax errors, unknown keys, values of the
+wrong type, invalid durations or enumerated values, and missing required fields.
+A manifest that matches the schema is then checked for the problems release
+would reject, such as duplicate modules, duplicate dependents, or cycles.
+
+The command fails when any problem is found, so it can gate CI. --schema prints
+the JSON Schema instead, for editors such as the YAML language server.
+
+Examples:
+  cascade manifest validate
+  cascade manifest validate deps.yaml --json
+  cascade manifest validate --schema > cascade.schema.json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if printSchema {
+				return writeManifestSchema(cmd.OutOrStdout())
+			}
+			manifestArg := ""
+			if len(args) > 0 {
+				manifestArg = args[0]
+			}
+			return runManifestValidate(cmd.OutOrStdout(), manifestPath, manifestArg, jsonOutput)
+		},
+	}
+
+	cmd.Flags().StringVar(&manifestPath, "manifest", "", "Manifest file path (default: .cascade.yaml)")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Print the problems as JSON")
+	cmd.Flags().BoolVar(&printSchema, "schema", false, "Print the manifest JSON Schema instead of validating")
+
+	return cmd
+}
+
+func runManifestValidate(w io.Writer, manifestFlag, manifestArg string, jsonOutput bool) error {
+	manifestPath := resolvePlanManifestPath(manifestFlag, manifestArg, container.Config())
+	if manifestPath == "" {
+		return newValidationError("manifest path not provided and no default configured", nil)
+	}
+
+	validator, ok := container.Manifest().(manifest.Validator)
+	if !ok {
+		return newValidationError("the configured manifest loader cannot validate manifests", nil)
+	}
+	diagnostics, err := validator.ValidateFile(manifestPath)
+	if err != nil {
+		return newFileError("failed to read manifest", err).
+			WithHint("create one with `cascade manifest generate` or pass --manifest")
+	}
+
+	if jsonOutput {
+		if diagnostics == nil {
+			diagnostics = []manifest.Diagnostic{}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(diagnostics); err != nil {
+			return newGenericError("failed to encode diagnostics", err)
+		}
+	} else if len(diagnostics) == 0 {
+		fmt.Fprintf(w, "%s %s is valid\n", style.mark(markOK), manifestPath)
+	} else {
+		for _, d := range diagnostics {
+			fmt.Fprintln(w, d.String())
+		}
+	}
+
+	if len(diagnostics) > 0 {
+		return newValidationError(fmt.Sprintf("%s has %d problem(s)", manifestPath, len(diagnostics)), nil)
+	}
+	return nil
+}
+
+func writeManifestSchema(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(manifest.ManifestSchema()); err != nil {
+		return newGenericError("failed to encode manifest schema", err)
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/goliatone/cascade/internal/manifest"
+	"github.com/spf13/cobra"
+)
+
+// newOverridesCommand creates the overrides subcommand for dependent-local manifests
+func newOverridesCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "overrides",
+		Short: "Inspect dependent-local .cascade.yaml overrides",
+		Long: `Dependent repositories can ship their own .cascade.yaml to override the tests,
+commands, labels, and notifications cascade uses when updating them. The overrides
+are read from the dependent's default branch at execution time.`,
+	}
+
+	cmd.AddCommand(newOverridesLintCommand())
+	return cmd
+}
+
+// newOverridesLintCommand creates the overrides lint subcommand
+func newOverridesLintCommand() *cobra.Command {
+	var repos []string
+
+	cmd := &cobra.Command{
+		Use:   "lint [file...]",
+		Short: "Validate dependent-local .cascade.yaml override files",
+		Long: `Lint checks dependent-local override files for unknown keys, empty commands,
+malformed module keys, and negative timeouts. Local files are checked by default;
+use --repo to lint the file on a GitHub repository's default branch.
+
+Examples:
+  cascade overrides lint                          # Lint ./.cascade.yaml
+  cascade overrides lint services/api/.cascade.yaml
+  cascade overrides lint --repo=goliatone/go-logger`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sources := make([]overrideSource, 0, len(args)+len(repos))
+			for _, path := range args {
+				sources = append(sources, localOverrideSource(path))
+			}
+			if len(repos) > 0 {
+				remote, err := remoteOverrideSources(cmd.Context(), repos)
+				if err != nil {
+					return err
+				}
+				sources = append(sources, remote...)
+			}
+			if len(sources) == 0 {
+				sources = append(sources, localOverrideSource(manifest.DependentManifestFileName))
+			}
+			return runOverridesLint(cmd.OutOrStdout(), sources)
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&repos, "repo", nil, "Lint the .cascade.yaml on a GitHub repository's default branch (owner/name)")
+
+	return cmd
+}
+
+// overrideSource names an override file and how to read it.
+type overrideSource struct {
+	Name string
+	Read func() ([]byte, error)
+}
+
+func localOverrideSource(path string) overrideSource {
+	return overrideSource{
+		Name: path,
+		Read: func() ([]byte, error) { return os.ReadFile(path) },
+	}
+}
+
+func remoteOverrideSources(ctx context.Context, repos []string) ([]overrideSource, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	client, err := newGitHubClient(ctx, container.Config())
+	if err != nil {
+		return nil, newConfigError("failed to create GitHub client", err).
+			WithHint("set CASCADE_GITHUB_TOKEN to lint remote repositories")
+	}
+
+	sources := make([]overrideSource, 0, len(repos))
+	for _, repo := range repos {
+		parts := strings.SplitN(strings.TrimSpace(repo), "/", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, newValidationError(fmt.Sprintf("invalid repository %q", repo), nil).
+				WithHint("use the owner/name format")
+		}
+		reader := gitHubFileReader(ctx, client, parts[0], parts[1], "")
+		sources = append(sources, overrideSource{
+			Name: repo + ":" + manifest.DependentManifestFileName,
+			Read: func() ([]byte, error) { return reader(manifest.DependentManifestFileName) },
+		})
+	}
+	return sources, nil
+}
+
+func runOverridesLint(w io.Writer, sources []overrideSource) error {
+	failed := 0
+	for _, source := range sources {
+		data, err := source.Read()
+		if err != nil {
+			failed++
+			if errors.Is(err, os.ErrNotExist) {
+				fmt.Fprintf(w, "%s %s: file not found\n", style.mark(markFailed), source.Name)
+			} else {
+				fmt.Fprintf(w, "%s %s: %v\n", style.mark(markFailed), source.Name, err)
+			}
+			continue
+		}
+
+		err = manifest.LintDependentManifest(source.Name, data)
+		if err == nil {
+			fmt.Fprintf(w, "%s %s\n", style.mark(markOK), source.Name)
+			continue
+		}
+
+		failed++
+		var validationErr *manifest.ValidationError
+		if errors.As(err, &validationErr) {
+			fmt.Fprintf(w, "%s %s:\n", style.mark(markFailed), source.Name)
+			for _, issue := range validationErr.Issues {
+				fmt.Fprintf(w, "    - %s\n", issue)
+			}
+			continue
+		}
+		fmt.Fprintf(w, "%s %s: %v\n", style.mark(markFailed), source.Name, err)
+	}
+
+	if failed > 0 {
+		return newValidationError(fmt.Sprintf("%d of %d override files failed lint", failed, len(sources)), nil)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunOverridesLint(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "valid.yaml")
+	invalid := filepath.Join(dir, "invalid.yaml")
+	if err := os.WriteFile(valid, []byte("dependents:\n  github.com/example/lib:\n    tests:\n      - cmd: [\"make\", \"test\"]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(invalid, []byte("dependents:\n  github.com/example/lib:\n    tests:\n      - cmd: []\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := runOverridesLint(&out, []overrideSource{localOverrideSource(valid)}); err != nil {
+		t.Fatalf("expected valid file to pass, got %v\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "✓ "+valid) {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+
+	out.Reset()
+	err := runOverridesLint(&out, []overrideSource{
+		localOverrideSource(valid),
+		localOverrideSource(invalid),
+		localOverrideSource(filepath.Join(dir, "missing.yaml")),
+	})
+	cliErr := asCLIError(err)
+	if cliErr == nil || cliErr.ExitCode() != ExitValidationError {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	if !strings.Contains(cliErr.Message, "2 of 3") {
+		t.Fatalf("unexpected error message: %q", cliErr.Message)
+	}
+	for _, want := range []string{"dependents[github.com/example/lib].tests[0] has an empty command", "missing.yaml: file not found"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out.String())
+		}
+	}
+}
@@ -2,10 +2,15 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"os"
 	"strings"
 	"time"
 
+	"github.com/goliatone/cascade/internal/broker"
+	"github.com/goliatone/cascade/internal/manifest"
 	"github.com/goliatone/cascade/internal/planner"
 	"github.com/spf13/cobra"
 )
@@ -14,12 +19,17 @@ import (
 func newPlanCommand() *cobra.Command {
 	var (
 		manifestPath  string
+		manifestRef   string
 		modulePath    string
 		version       string
 		checkStrategy string
 		checkCacheTTL time.Duration
 		checkParallel int
 		checkTimeout  time.Duration
+		explain       bool
+		notify        bool
+		multiLevel    bool
+		output        string
 	)
 
 	cmd := &cobra.Command{
@@ -38,7 +48,12 @@ Examples:
   cascade plan --module=github.com/example/lib   # Override just the module
   cascade plan --version=v1.2.3                  # Override just the version
   cascade plan custom-manifest.yaml              # Use custom manifest file
-  cascade plan --check-strategy=remote           # Force remote checking for CI/CD`,
+  cascade plan --manifest-ref v2024.10           # Plan against the manifest as it was at a git ref
+  cascade plan --check-strategy=remote           # Force remote checking for CI/CD
+  cascade plan --explain                         # Show why each dependent was included, skipped, or deferred
+  cascade plan --notify                          # Send a summary of pending updates to the configured notifiers
+  cascade plan --multi-level                     # Show the waves of a multi-level release
+  cascade plan --output plan.json                # Save the plan for review and cascade release --from-plan`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			manifestArg := ""
@@ -60,13 +75,17 @@ Examples:
 			if cmd.Flags().Changed("check-timeout") {
 				config.Executor.CheckTimeout = checkTimeout
 			}
+			if cmd.Flags().Changed("multi-level") {
+				config.Executor.MultiLevel = multiLevel
+			}
 
-			return runPlan(manifestPath, manifestArg, modulePath, version)
+			return runPlan(manifestPath, manifestArg, manifestRef, modulePath, version, explain, notify, output)
 		},
 	}
 
 	// Module and version flags (auto-detected if not provided)
 	cmd.Flags().StringVar(&manifestPath, "manifest", "", "Manifest file path (default: .cascade.yaml)")
+	cmd.Flags().StringVar(&manifestRef, "manifest-ref", "", "Read the manifest as it was at this git ref (tag, branch, or commit) of the repository holding it")
 	cmd.Flags().StringVar(&modulePath, "module", "", "Target module path (e.g., github.com/example/lib). Auto-detected from go.mod if not provided")
 	cmd.Flags().StringVar(&version, "version", "", "Target version (e.g., v1.2.3). Auto-detected from .version file or git tags if not provided")
 
@@ -76,10 +95,16 @@ Examples:
 	cmd.Flags().IntVar(&checkParallel, "check-parallel", 0, "Number of parallel checks (0 = auto-detect)")
 	cmd.Flags().DurationVar(&checkTimeout, "check-timeout", 30*time.Second, "Timeout for individual repository checks")
 
+	cmd.Flags().BoolVar(&explain, "explain", false, "Explain why each dependent was included, skipped, or deferred, including which checker answered")
+	cmd.Flags().BoolVar(&notify, "notify", false, "Send a plan summary through the configured Slack and webhook notifiers")
+	cmd.Flags().BoolVar(&multiLevel, "multi-level", false, "Show the waves in which dependents that are manifest modules release to their own dependents")
+	cmd.Flags().StringVar(&output, "output", "", "Also write the full plan as JSON to this file, for cascade release --from-plan")
+
+	cmd.AddCommand(newPlanVerifyCommand())
 	return cmd
 }
 
-func runPlan(manifestFlag, manifestArg, moduleFlag, versionFlag string) error {
+func runPlan(manifestFlag, manifestArg, manifestRef, moduleFlag, versionFlag string, explain, notify bool, output string) error {
 	start := time.Now()
 	ctx := context.Background()
 	logger := container.Logger()
@@ -152,10 +177,10 @@ func runPlan(manifestFlag, manifestArg, moduleFlag, versionFlag string) error {
 		"module", finalModulePath,
 		"version", finalVersion)
 
-	// Load the manifest
-	manifest, err := container.Manifest().Load(manifestPath)
+	// Load the manifest, from history when a ref is given
+	manifest, revision, err := loadPlanManifest(ctx, manifestPath, manifestRef)
 	if err != nil {
-		return newFileError("failed to load manifest", err)
+		return err
 	}
 
 	// Create target with resolved values
@@ -165,7 +190,14 @@ func runPlan(manifestFlag, manifestArg, moduleFlag, versionFlag string) error {
 	}
 
 	// Generate the plan
-	plan, err := container.Planner().Plan(ctx, manifest, target)
+	planCtx := ctx
+	if explain {
+		planCtx = planner.WithExplain(ctx)
+	}
+	if config.Executor.MultiLevel {
+		planCtx = planner.WithWaves(planCtx)
+	}
+	plan, err := container.Planner().Plan(planCtx, manifest, target)
 	if err != nil {
 		return newPlanningError("failed to generate plan", err)
 	}
@@ -176,6 +208,9 @@ func runPlan(manifestFlag, manifestArg, moduleFlag, versionFlag string) error {
 	} else {
 		fmt.Printf("Planning updates for %s@%s\n", target.Module, target.Version)
 	}
+	if revision != nil {
+		fmt.Printf("Manifest: %s at %s (%s)\n", manifestPath, revision.Ref, shortCommit(revision.Commit))
+	}
 
 	// Show planning statistics if dependency checking was enabled
 	if config.Executor.SkipUpToDate && plan.Stats.TotalDependents > 0 {
@@ -228,10 +263,34 @@ func runPlan(manifestFlag, manifestArg, moduleFlag, versionFlag string) error {
 		fmt.Println()
 	}
 
+	printSkippedArchived(&plan.Stats)
+	printSkippedLocal(&plan.Stats)
+	printDuplicateDependents(os.Stdout, &plan.Stats)
+	printPlanEstimate(os.Stdout, &plan.Stats, config.Executor.ConcurrentLimit)
+
 	fmt.Printf("Found %d work items:\n", len(plan.Items))
 	for i, item := range plan.Items {
 		fmt.Printf("  %d. %s (%s) -> %s\n", i+1, item.Repo, item.Module, item.BranchName)
 
+		if len(item.Services) > 0 {
+			fmt.Println("     Services:")
+			for _, svc := range item.Services {
+				fmt.Printf("       - %s (%s)\n", svc.Name, svc.Image)
+			}
+		}
+
+		if item.GoFlags != "" {
+			fmt.Printf("     GOFLAGS: %s\n", item.GoFlags)
+		}
+
+		if len(item.Credentials) > 0 {
+			machines := make([]string, len(item.Credentials))
+			for i, cred := range item.Credentials {
+				machines[i] = cred.Machine
+			}
+			fmt.Printf("     Credentials: %s\n", strings.Join(machines, ", "))
+		}
+
 		if len(item.Tests) > 0 {
 			fmt.Println("     Tests:")
 			for _, cmd := range item.Tests {
@@ -247,18 +306,133 @@ func runPlan(manifestFlag, manifestArg, moduleFlag, versionFlag string) error {
 		}
 	}
 
+	printPlanWaves(os.Stdout, plan.Waves)
+
+	if explain {
+		printPlanExplanations(plan.Explanations)
+	}
+
+	if output != "" {
+		if err := writeRevisionPlanArtifact(output, manifestPath, revision, plan); err != nil {
+			return err
+		}
+		fmt.Printf("\nPlan written to %s; run it with `cascade release --from-plan %s`\n", output, output)
+	}
+
+	if notify {
+		if config.Executor.DryRun {
+			fmt.Println("\nDRY RUN: Plan summary not sent")
+			return nil
+		}
+
+		brokerSvc := container.Broker()
+		if manifestNotifications := manifestNotificationSettings(manifest, logger); manifestNotifications != nil {
+			brokerSvc, err = container.BrokerWithManifestNotifications(manifestNotifications)
+			if err != nil {
+				return newExecutionError("failed to initialize broker with manifest notifications", err)
+			}
+		}
+
+		if err := sendPlanSummary(ctx, os.Stdout, brokerSvc, newPlanSummary(target, plan)); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// loadPlanManifest loads the manifest at manifestPath, or as it was at ref when
+// ref is set, in which case the revision it came from is returned as well.
+func loadPlanManifest(ctx context.Context, manifestPath, ref string) (*manifest.Manifest, *manifestRevision, error) {
+	if ref = strings.TrimSpace(ref); ref != "" {
+		revision, err := loadManifestAtRef(ctx, manifestPath, ref)
+		if err != nil {
+			return nil, nil, err
+		}
+		return revision.Manifest, revision, nil
+	}
+	m, err := container.Manifest().Load(manifestPath)
+	if err != nil {
+		return nil, nil, newFileError("failed to load manifest", err).
+			WithHint("create one with `cascade manifest generate` or pass --manifest")
+	}
+	return m, nil, nil
+}
+
+// newPlanSummary condenses a plan into the payload sent by plan notifications.
+func newPlanSummary(target planner.Target, plan *planner.Plan) broker.PlanSummary {
+	summary := broker.PlanSummary{
+		Module:            target.Module,
+		Version:           target.Version,
+		CheckErrors:       plan.Stats.CheckErrors,
+		EstimatedDuration: plan.Stats.EstimatedDuration,
+		Timestamp:         time.Now(),
+	}
+	for _, item := range plan.Items {
+		summary.Updates = append(summary.Updates, item.Repo)
+	}
+	summary.SkippedUpToDate = append(summary.SkippedUpToDate, plan.Stats.SkippedUpToDateRepos...)
+	summary.SkippedArchived = append(summary.SkippedArchived, plan.Stats.SkippedArchivedRepos...)
+	summary.SkippedLocal = append(summary.SkippedLocal, plan.Stats.SkippedLocalRepos...)
+	return summary
+}
+
+// sendPlanSummary delivers summary through the broker and reports where it went.
+func sendPlanSummary(ctx context.Context, w io.Writer, brokerSvc broker.Broker, summary broker.PlanSummary) error {
+	result, err := brokerSvc.NotifyPlan(ctx, summary)
+	if err != nil {
+		var notImpl *broker.NotImplementedError
+		if errors.As(err, &notImpl) {
+			return newExecutionError("plan notifications are not available", err).
+				WithHint("configure integration.slack or a webhook, and provide GitHub credentials")
+		}
+		return newExecutionError("failed to send plan summary", err)
+	}
+
+	if result == nil || result.Channel == "noop" {
+		fmt.Fprintln(w, "\nPlan summary not sent: no notification integrations configured")
+		return nil
+	}
+	fmt.Fprintf(w, "\nPlan summary sent to %s\n", result.Channel)
+	return nil
+}
+
+// printPlanExplanations renders the per-dependent reasoning recorded by the planner.
+func printPlanExplanations(explanations []planner.Explanation) {
+	fmt.Printf("\nExplanation (%d dependents):\n", len(explanations))
+	for _, exp := range explanations {
+		fmt.Printf("  - %s: %s (%s)\n", exp.Repo, exp.Decision, exp.Reason)
+
+		var details []string
+		if exp.Checker != "" {
+			details = append(details, "checker="+exp.Checker)
+		}
+		if exp.Source != "" {
+			details = append(details, "source="+exp.Source)
+		}
+		if exp.CurrentVersion != "" {
+			details = append(details, "current="+exp.CurrentVersion)
+		}
+		if len(details) > 0 {
+			fmt.Printf("      %s\n", strings.Join(details, ", "))
+		}
+
+		for _, field := range exp.Provenance.Fields() {
+			fmt.Printf("      %s <- %s\n", field, exp.Provenance[field])
+		}
+	}
+	fmt.Println("  Dependents are only deferred behind canaries: cascade has no version constraints or cooldowns that hold an update back.")
+}
+
 // showPerformanceWarnings displays performance-related warnings based on check statistics.
 func showPerformanceWarnings(stats *planner.PlanStats, configuredParallel int) {
 	// Warn if remote checking takes >30s total
 	if stats.CheckDuration > 30*time.Second {
-		fmt.Printf("  ⚠ Warning: Dependency checks took %.1fs (>30s)\n", stats.CheckDuration.Seconds())
+		fmt.Printf("  %s Warning: Dependency checks took %.1fs (>30s)\n", style.mark(markWarning), stats.CheckDuration.Seconds())
 
 		// Suggest increasing parallelism if checks are slow and not already parallelized
 		if !stats.ParallelChecks || configuredParallel < 4 {
-			fmt.Printf("  ⚠ Consider increasing parallelism with --check-parallel=8\n")
+			fmt.Printf("  %s Consider increasing parallelism with --check-parallel=8\n", style.mark(markWarning))
 		}
 	}
 
@@ -269,7 +443,7 @@ func showPerformanceWarnings(stats *planner.PlanStats, configuredParallel int) {
 			hitRate := float64(stats.CacheHits) / float64(total)
 			// If cache hit rate is below 50% and we have a significant number of checks
 			if hitRate < 0.5 && total > 5 {
-				fmt.Printf("  ⚠ Low cache hit rate (%.0f%%). Repeated runs may be slower than expected.\n", hitRate*100)
+				fmt.Printf("  %s Low cache hit rate (%.0f%%). Repeated runs may be slower than expected.\n", style.mark(markWarning), hitRate*100)
 			}
 		}
 	}
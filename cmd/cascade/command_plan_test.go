@@ -2,11 +2,15 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"io"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/goliatone/cascade/internal/broker"
 	"github.com/goliatone/cascade/internal/planner"
 )
 
@@ -181,3 +185,164 @@ func TestShowPerformanceWarnings_CombinedWarnings(t *testing.T) {
 		}
 	}
 }
+
+func TestPrintPlanEstimate(t *testing.T) {
+	tests := []struct {
+		name        string
+		stats       planner.PlanStats
+		concurrency int
+		want        []string
+		wantAbsent  []string
+	}{
+		{
+			name:        "no work items prints nothing",
+			stats:       planner.PlanStats{},
+			concurrency: 4,
+		},
+		{
+			name:        "estimate from history",
+			stats:       planner.PlanStats{WorkItemsCreated: 3, TimedItems: 2, EstimatedDuration: 90 * time.Second, EstimatedAPICalls: 10},
+			concurrency: 2,
+			want: []string{
+				"Estimate (concurrency 2):",
+				"Runtime: ~1m30s (2 of 3 work items timed in earlier releases)",
+				"GitHub API calls: at least 10 for pull requests",
+			},
+			wantAbsent: []string{"Consider raising"},
+		},
+		{
+			name:        "without history",
+			stats:       planner.PlanStats{WorkItemsCreated: 1, EstimatedAPICalls: 3},
+			concurrency: 0,
+			want:        []string{"Estimate (concurrency 1):", "Runtime: unknown"},
+		},
+		{
+			name:        "long run suggests more concurrency",
+			stats:       planner.PlanStats{WorkItemsCreated: 20, TimedItems: 20, EstimatedDuration: 2 * time.Hour, EstimatedAPICalls: 60},
+			concurrency: 4,
+			want:        []string{"Consider raising executor.concurrent_limit"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			printPlanEstimate(&buf, &tt.stats, tt.concurrency)
+			out := buf.String()
+			if len(tt.want) == 0 && out != "" {
+				t.Fatalf("expected no output, got %q", out)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("output missing %q:\n%s", want, out)
+				}
+			}
+			for _, absent := range tt.wantAbsent {
+				if strings.Contains(out, absent) {
+					t.Errorf("output unexpectedly contains %q:\n%s", absent, out)
+				}
+			}
+		})
+	}
+}
+
+func TestNewPlanSummary(t *testing.T) {
+	plan := &planner.Plan{
+		Items: []planner.WorkItem{{Repo: "example/app"}, {Repo: "example/api"}},
+		Stats: planner.PlanStats{
+			SkippedUpToDateRepos: []string{"example/cli"},
+			SkippedArchivedRepos: []string{"example/old"},
+			CheckErrors:          1,
+			EstimatedDuration:    time.Minute,
+		},
+	}
+
+	summary := newPlanSummary(planner.Target{Module: "github.com/example/lib", Version: "v1.2.3"}, plan)
+
+	if summary.Module != "github.com/example/lib" || summary.Version != "v1.2.3" {
+		t.Errorf("unexpected target %s@%s", summary.Module, summary.Version)
+	}
+	if strings.Join(summary.Updates, ",") != "example/app,example/api" {
+		t.Errorf("unexpected updates %v", summary.Updates)
+	}
+	if summary.Skipped() != 2 || summary.CheckErrors != 1 || summary.EstimatedDuration != time.Minute {
+		t.Errorf("unexpected summary %+v", summary)
+	}
+	if summary.Timestamp.IsZero() {
+		t.Error("expected timestamp to be set")
+	}
+}
+
+func TestSendPlanSummary(t *testing.T) {
+	summary := broker.PlanSummary{Module: "github.com/example/lib", Version: "v1.2.3"}
+
+	t.Run("reports the channel", func(t *testing.T) {
+		var buf bytes.Buffer
+		b := &mockBroker{planFunc: func(ctx context.Context, got broker.PlanSummary) (*broker.NotificationResult, error) {
+			if got.Module != summary.Module {
+				t.Errorf("unexpected summary %+v", got)
+			}
+			return &broker.NotificationResult{Channel: "#releases"}, nil
+		}}
+		if err := sendPlanSummary(context.Background(), &buf, b, summary); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.Contains(buf.String(), "Plan summary sent to #releases") {
+			t.Errorf("unexpected output %q", buf.String())
+		}
+	})
+
+	t.Run("no integrations", func(t *testing.T) {
+		var buf bytes.Buffer
+		b := &mockBroker{planFunc: func(ctx context.Context, got broker.PlanSummary) (*broker.NotificationResult, error) {
+			return &broker.NotificationResult{Channel: "noop"}, nil
+		}}
+		if err := sendPlanSummary(context.Background(), &buf, b, summary); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.Contains(buf.String(), "no notification integrations configured") {
+			t.Errorf("unexpected output %q", buf.String())
+		}
+	})
+
+	t.Run("stub broker", func(t *testing.T) {
+		err := sendPlanSummary(context.Background(), io.Discard, broker.NewStub(), summary)
+		var cliErr *CLIError
+		if !errors.As(err, &cliErr) || cliErr.Hint == "" {
+			t.Fatalf("expected CLI error with hint, got %v", err)
+		}
+	})
+
+	t.Run("delivery failure", func(t *testing.T) {
+		b := &mockBroker{planFunc: func(ctx context.Context, got broker.PlanSummary) (*broker.NotificationResult, error) {
+			return nil, errors.New("slack unavailable")
+		}}
+		if err := sendPlanSummary(context.Background(), io.Discard, b, summary); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
+
+func TestPrintDuplicateDependents(t *testing.T) {
+	var buf bytes.Buffer
+	printDuplicateDependents(&buf, &planner.PlanStats{})
+	if buf.Len() != 0 {
+		t.Fatalf("output without duplicates = %q, want none", buf.String())
+	}
+
+	printDuplicateDependents(&buf, &planner.PlanStats{DuplicateDependents: []planner.DuplicateDependent{
+		{Repo: "goliatone/go-logger", Entries: []string{"go-errors", "go-errors-repo"}, Filled: []string{"labels"}, Conflicts: []string{"branch", "pr"}},
+		{Repo: "goliatone/go-router", Entries: []string{"go-errors", "go-errors-repo"}},
+	}})
+	for _, want := range []string{
+		"2 dependents listed by several manifest entries were merged",
+		"goliatone/go-logger (go-errors, go-errors-repo)",
+		"filled from later entries: labels",
+		"conflicting, kept from go-errors: branch, pr",
+		"goliatone/go-router (go-errors, go-errors-repo)",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, buf.String())
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/goliatone/cascade/internal/planner"
+	"github.com/spf13/cobra"
+)
+
+// newPlanVerifyCommand creates the plan verify subcommand
+func newPlanVerifyCommand() *cobra.Command {
+	var (
+		manifestPath string
+		golden       string
+		update       bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "verify [manifest]",
+		Short: "Fail when the plan deviates from a committed golden plan",
+		Long: `Verify plans the release recorded in a golden plan file, written by
+cascade plan --output, and fails when the work items, their settings, or the
+waves differ from it. Run it in CI to catch manifest changes that update more,
+fewer, or different dependents than intended.
+
+The release is planned for the golden plan's module and version, against the
+manifest the golden plan was made from unless one is given.
+
+Examples:
+  cascade plan --output plan.golden.json         # Record the golden plan
+  cascade plan verify --golden plan.golden.json  # Compare the current plan with it
+  cascade plan verify --golden plan.golden.json --update  # Accept the current plan`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			manifestArg := ""
+			if len(args) > 0 {
+				manifestArg = args[0]
+			}
+			return runPlanVerify(cmd.OutOrStdout(), golden, manifestPath, manifestArg, update)
+		},
+	}
+
+	cmd.Flags().StringVar(&golden, "golden", "", "Golden plan file written by cascade plan --output")
+	cmd.Flags().StringVar(&manifestPath, "manifest", "", "Manifest file path (default: the manifest the golden plan was made from)")
+	cmd.Flags().BoolVar(&update, "update", false, "Overwrite the golden plan with the current plan instead of comparing")
+	_ = cmd.MarkFlagRequired("golden")
+
+	return cmd
+}
+
+func runPlanVerify(w io.Writer, golden, manifestFlag, manifestArg string, update bool) error {
+	ctx := context.Background()
+
+	artifact, manifestPath, err := loadPlanArtifact(golden, manifestFlag, manifestArg)
+	if err != nil {
+		return err
+	}
+
+	manifest, err := container.Manifest().Load(manifestPath)
+	if err != nil {
+		return newFileError("failed to load manifest", err).
+			WithHint("create one with `cascade manifest generate` or pass --manifest")
+	}
+
+	target := artifact.Plan.Target
+	planCtx := ctx
+	if len(artifact.Plan.Waves) > 0 {
+		planCtx = planner.WithWaves(ctx)
+	}
+	plan, err := container.Planner().Plan(planCtx, manifest, target)
+	if err != nil {
+		return newPlanningError("failed to generate plan", err)
+	}
+
+	if update {
+		if err := writePlanArtifact(golden, manifestPath, plan); err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%s Updated %s with %d work items for %s@%s\n", style.mark(markOK), golden, len(plan.Items), target.Module, target.Version)
+		return nil
+	}
+
+	changes := planner.ComparePlans(&artifact.Plan, plan)
+	if len(changes) == 0 {
+		fmt.Fprintf(w, "%s Plan for %s@%s matches %s (%d work items)\n", style.mark(markOK), target.Module, target.Version, golden, len(plan.Items))
+		return nil
+	}
+
+	fmt.Fprintf(w, "%s Plan for %s@%s differs from %s:\n", style.mark(markFailed), target.Module, target.Version, golden)
+	for _, change := range changes {
+		fmt.Fprintf(w, "  %s\n", change)
+	}
+	return newValidationError(fmt.Sprintf("plan differs from %s in %d places", golden, len(changes)), nil).
+		WithHint("if the changes are intended, accept them with `cascade plan verify --golden %s --update`", golden)
+}
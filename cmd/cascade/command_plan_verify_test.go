@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/goliatone/cascade/internal/planner"
+	"github.com/goliatone/cascade/pkg/config"
+	"github.com/goliatone/cascade/pkg/di"
+)
+
+const verifyTestManifest = `modules:
+  - module: example.com/lib
+    dependents:
+      - repo: example/app
+        module: example.com/app
+        module_path: .
+        branch: main
+`
+
+func TestRunPlanVerify(t *testing.T) {
+	testContainer, err := di.New(
+		di.WithConfig(config.New()),
+		di.WithLogger(&mockLogger{}),
+		di.WithPlanner(planner.New()),
+	)
+	if err != nil {
+		t.Fatalf("failed to create container: %v", err)
+	}
+	originalContainer := container
+	container = testContainer
+	defer func() { container = originalContainer }()
+
+	dir := t.TempDir()
+	manifestPath := filepath.Join(dir, ".cascade.yaml")
+	if err := os.WriteFile(manifestPath, []byte(verifyTestManifest), 0600); err != nil {
+		t.Fatal(err)
+	}
+	goldenPath := filepath.Join(dir, "plan.golden.json")
+	golden := &planner.Plan{Target: planner.Target{Module: "example.com/lib", Version: "v1.0.0"}}
+	if err := writePlanArtifact(goldenPath, manifestPath, golden); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	err = runPlanVerify(&out, goldenPath, "", "", false)
+	var cliErr *CLIError
+	if !errors.As(err, &cliErr) || cliErr.Code != ExitValidationError {
+		t.Fatalf("expected a validation error for the new dependent, got %v", err)
+	}
+	if !strings.Contains(out.String(), "+ example/app (example.com/app)") {
+		t.Errorf("expected the added work item in the output, got:\n%s", out.String())
+	}
+
+	out.Reset()
+	if err := runPlanVerify(&out, goldenPath, "", "", true); err != nil {
+		t.Fatalf("runPlanVerify(update) error = %v", err)
+	}
+	out.Reset()
+	if err := runPlanVerify(&out, goldenPath, "", "", false); err != nil {
+		t.Fatalf("expected the updated golden plan to match, got %v\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "matches") {
+		t.Errorf("expected a match, got:\n%s", out.String())
+	}
+
+	edited := strings.Replace(verifyTestManifest, "branch: main", "branch: develop", 1)
+	if err := os.WriteFile(manifestPath, []byte(edited), 0600); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	if err := runPlanVerify(&out, goldenPath, "", "", false); err == nil {
+		t.Fatal("expected the changed branch to fail verification")
+	}
+	if !strings.Contains(out.String(), `~ example/app (example.com/app): Branch "main" -> "develop"`) {
+		t.Errorf("expected the changed branch in the output, got:\n%s", out.String())
+	}
+}
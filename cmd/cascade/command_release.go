@@ -3,13 +3,17 @@ package main
 import (
 	"context"
 	"fmt"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/goliatone/cascade/internal/broker"
-	execpkg "github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/internal/manifest"
 	"github.com/goliatone/cascade/internal/planner"
 	"github.com/goliatone/cascade/internal/state"
+	"github.com/goliatone/cascade/pkg/config"
 	"github.com/goliatone/cascade/pkg/di"
+	"github.com/goliatone/cascade/pkg/gitutil"
 	"github.com/spf13/cobra"
 )
 
@@ -17,12 +21,21 @@ import (
 func newReleaseCommand() *cobra.Command {
 	var (
 		manifestPath  string
-		modulePath    string
+		modules       []string
+		releaseSet    string
 		version       string
 		checkStrategy string
 		checkCacheTTL time.Duration
 		checkParallel int
 		checkTimeout  time.Duration
+		savePreviews  bool
+		waitForLock   time.Duration
+		multiLevel    bool
+		fromPlan      string
+		baseBranch    string
+		localTarget   string
+		reports       []string
+		reportDir     string
 	)
 
 	cmd := &cobra.Command{
@@ -41,7 +54,17 @@ Examples:
   cascade release --module=github.com/example/lib   # Override just the module
   cascade release --version=v1.2.3                  # Override just the version
   cascade release .cascade.yaml                     # Explicit manifest file
-  cascade release --check-strategy=remote           # Force remote checking for CI/CD`,
+  cascade release --check-strategy=remote           # Force remote checking for CI/CD
+  cascade release --dry-run --save-previews         # Write PR previews under the state dir
+  cascade release --wait-for-lock=10m               # Queue behind a run already releasing this version
+  cascade release --multi-level                     # Continue into dependents of dependents once updates merge and tag
+  cascade release --from-plan plan.json             # Execute a plan saved with cascade plan --output
+  cascade release --base-branch release/1.x         # Update and open PRs against a release branch
+  cascade release --local-target ../lib             # Test canaries against a local checkout of the module
+  cascade release --report=json,junit               # Write JSON and JUnit run reports for CI artifacts
+  cascade release --module github.com/example/a@v1.2.0 --module github.com/example/b@v2.0.0
+                                                    # Release several modules in one PR per dependent
+  cascade release --release-set release-set.yaml    # Same, with the modules listed in a file`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			manifestArg := ""
@@ -63,14 +86,46 @@ Examples:
 			if cmd.Flags().Changed("check-timeout") {
 				config.Executor.CheckTimeout = checkTimeout
 			}
+			if cmd.Flags().Changed("multi-level") {
+				config.Executor.MultiLevel = multiLevel
+			}
+			if cmd.Flags().Changed("base-branch") {
+				if err := gitutil.ValidateBranchName(baseBranch); err != nil {
+					return newValidationError(fmt.Sprintf("invalid --base-branch %q", baseBranch), err)
+				}
+				config.Executor.BaseBranch = baseBranch
+			}
+			if cmd.Flags().Changed("local-target") {
+				config.Executor.LocalTarget = localTarget
+			}
+			if err := applyReportFlags(config, reports, reportDir); err != nil {
+				return err
+			}
 
-			return runRelease(manifestPath, manifestArg, modulePath, version)
+			targets, err := releaseSetTargets(modules, releaseSet)
+			if err != nil {
+				return err
+			}
+			if targets != nil {
+				if version != "" || fromPlan != "" {
+					return newValidationError("--version and --from-plan cannot be combined with a release set", nil).
+						WithHint("give each module its version as module@version")
+				}
+				return runReleaseSet(context.Background(), manifestPath, manifestArg, targets, savePreviews, waitForLock)
+			}
+
+			modulePath := ""
+			if len(modules) == 1 {
+				modulePath = modules[0]
+			}
+			return runRelease(manifestPath, manifestArg, modulePath, version, savePreviews, waitForLock, fromPlan)
 		},
 	}
 
 	// Flags for overriding auto-detected defaults
 	cmd.Flags().StringVar(&manifestPath, "manifest", "", "Path to dependency manifest file (default: .cascade.yaml)")
-	cmd.Flags().StringVar(&modulePath, "module", "", "Go module path (e.g., github.com/example/lib). Auto-detected from go.mod if not provided")
+	cmd.Flags().StringArrayVar(&modules, "module", nil, "Go module path (e.g., github.com/example/lib). Auto-detected from go.mod if not provided. Repeat as module@version to release several modules together")
+	cmd.Flags().StringVar(&releaseSet, "release-set", "", "Release-set file listing modules and versions to release together")
 	cmd.Flags().StringVar(&version, "version", "", "Target version (e.g., v1.2.3). Auto-detected from .version file or git tags if not provided")
 
 	// Dependency checking flags
@@ -79,12 +134,39 @@ Examples:
 	cmd.Flags().IntVar(&checkParallel, "check-parallel", 0, "Number of parallel checks (0 = auto-detect)")
 	cmd.Flags().DurationVar(&checkTimeout, "check-timeout", 30*time.Second, "Timeout for individual repository checks")
 
+	// Dry-run preview flags
+	cmd.Flags().BoolVar(&savePreviews, "save-previews", false, "With --dry-run, write PR previews to files under the state directory instead of stdout")
+
+	// Multi-level release flags
+	cmd.Flags().BoolVar(&multiLevel, "multi-level", false, "Release dependents that are manifest modules to their own dependents once their updates merge and a new version is tagged")
+
+	// Plan file flags
+	cmd.Flags().StringVar(&fromPlan, "from-plan", "", "Execute the plan saved in this file by cascade plan --output instead of planning again; fails if the manifest changed since")
+
+	// Base branch flags
+	cmd.Flags().StringVar(&baseBranch, "base-branch", "", "Start every update from this branch and open pull requests against it (e.g., release/1.x), overriding the manifest's branch and pr.base")
+
+	// Local target flags
+	cmd.Flags().StringVar(&localTarget, "local-target", "", "Run the tests of canary dependents against this checkout of the module through a go.work; their commits and pull requests still require the released version")
+
+	// Run lock flags
+	cmd.Flags().DurationVar(&waitForLock, "wait-for-lock", 0, "When another run holds the lock for this module@version, wait up to this long instead of failing")
+
+	// Run report flags
+	addReportFlags(cmd, &reports, &reportDir)
+
 	return cmd
 }
 
-func runRelease(manifestFlag, manifestArg, modulePath, version string) error {
+func runRelease(manifestFlag, manifestArg, modulePath, version string, savePreviews bool, waitForLock time.Duration, fromPlan string) error {
+	return runReleaseContext(context.Background(), manifestFlag, manifestArg, modulePath, version, savePreviews, waitForLock, fromPlan)
+}
+
+// runReleaseContext runs a release that stops when ctx is cancelled, such as
+// one started by cascade serve. When fromPlan is set, the plan saved in that
+// file is executed instead of planning again.
+func runReleaseContext(ctx context.Context, manifestFlag, manifestArg, modulePath, version string, savePreviews bool, waitForLock time.Duration, fromPlan string) error {
 	start := time.Now()
-	ctx := context.Background()
 	logger := container.Logger()
 	cfg := container.Config()
 
@@ -103,114 +185,136 @@ func runRelease(manifestFlag, manifestArg, modulePath, version string) error {
 		}
 	}()
 
-	// Apply default discovery logic for manifest path
-	finalManifestPath := resolvePlanManifestPath(manifestFlag, manifestArg, cfg)
-	if finalManifestPath == "" {
-		return newValidationError("manifest path not provided and no default configured", nil)
+	var (
+		artifact          *planner.PlanArtifact
+		finalManifestPath string
+		finalModulePath   string
+		finalVersion      string
+		err               error
+	)
+	if fromPlan != "" {
+		// A saved plan fixes the manifest and target it was made for
+		artifact, finalManifestPath, err = loadPlanArtifact(fromPlan, manifestFlag, manifestArg)
+		if err != nil {
+			return err
+		}
+		if err := checkPlanArtifact(artifact, finalManifestPath, modulePath, version); err != nil {
+			return err
+		}
+		finalModulePath, finalVersion = artifact.Plan.Target.Module, artifact.Plan.Target.Version
+	} else {
+		finalManifestPath, finalModulePath, finalVersion, err = resolveReleaseTarget(ctx, manifestFlag, manifestArg, modulePath, version)
+		if err != nil {
+			return err
+		}
 	}
 
-	// Apply default discovery logic for module path
-	finalModulePath := modulePath
-	if finalModulePath == "" && cfg != nil {
-		finalModulePath = cfg.Module // Use config as fallback
-	}
+	return executeRelease(ctx, finalManifestPath, []planner.Target{{Module: finalModulePath, Version: finalVersion}}, artifact, fromPlan, savePreviews, waitForLock)
+}
 
-	var moduleDir string
-	var err error
-	finalModulePath, moduleDir, err = applyModuleDefaults(finalModulePath)
-	if err != nil {
-		return err
+// runReleaseSet releases several modules together: dependents requiring more
+// than one of them get a single branch and pull request bumping all of them. The
+// run is recorded in state under the first module.
+func runReleaseSet(ctx context.Context, manifestFlag, manifestArg string, targets []planner.Target, savePreviews bool, waitForLock time.Duration) error {
+	cfg := container.Config()
+	if cfg.Executor.ForceAll {
+		cfg.Executor.SkipUpToDate = false
 	}
-
-	// Apply default discovery logic for version
-	finalVersion := version
-	if finalVersion == "" && cfg != nil {
-		finalVersion = cfg.Version // Use config as fallback
+	if cfg.Executor.MultiLevel {
+		return newValidationError("--multi-level cannot be combined with a release set", nil).
+			WithHint("release the modules of the set first, then run multi-level releases for each")
 	}
-
-	var versionWarnings []string
-	finalVersion, versionWarnings, err = applyVersionDefaults(ctx, finalVersion, moduleDir, cfg)
-	if err != nil {
-		return err
+	manifestPath := resolvePlanManifestPath(manifestFlag, manifestArg, cfg)
+	if manifestPath == "" {
+		return newValidationError("manifest path not provided and no default configured", nil)
 	}
+	return executeRelease(ctx, manifestPath, targets, nil, "", savePreviews, waitForLock)
+}
 
-	// Log version warnings if any
-	if len(versionWarnings) > 0 && logger != nil {
-		for _, warning := range versionWarnings {
-			logger.Warn("Version resolution warning", "warning", warning)
-		}
+// executeRelease plans and executes the release of targets, or of the plan in
+// artifact when one was loaded from fromPlan.
+func executeRelease(ctx context.Context, finalManifestPath string, targets []planner.Target, artifact *planner.PlanArtifact, fromPlan string, savePreviews bool, waitForLock time.Duration) error {
+	logger := container.Logger()
+	cfg := container.Config()
+	if artifact != nil && len(artifact.Plan.Targets) > 1 {
+		targets = artifact.Plan.Targets
 	}
+	target := targets[0]
+	label := releaseLabel(targets)
 
 	if err := ensureWorkspace(cfg.Workspace.Path); err != nil {
 		return newExecutionError("failed to prepare workspace", err)
 	}
 
+	local, err := resolveLocalTarget(cfg.Executor.LocalTarget, targets)
+	if err != nil {
+		return err
+	}
+
 	logger.Info("Executing dependency updates",
 		"manifest", finalManifestPath,
-		"module", finalModulePath,
-		"version", finalVersion)
+		"modules", label)
 
-	target := planner.Target{Module: finalModulePath, Version: finalVersion}
-
-	manifestData, err := container.Manifest().Load(finalManifestPath)
-	if err != nil {
-		return newFileError("failed to load manifest", err)
+	// Hold the run lock from planning onward, so a queued run plans against the
+	// outcome of the run it waited for
+	if !cfg.Executor.DryRun {
+		guard, err := acquireRunLock(ctx, os.Stdout, container.State(), target.Module, target.Version, waitForLock)
+		if err != nil {
+			return err
+		}
+		if guard != nil {
+			defer guard.Release()
+		}
 	}
 
-	plan, err := container.Planner().Plan(ctx, manifestData, target)
+	manifestData, err := container.Manifest().Load(finalManifestPath)
 	if err != nil {
-		return newPlanningError("failed to generate plan", err)
+		return newFileError("failed to load manifest", err).
+			WithHint("create one with `cascade manifest generate` or pass --manifest")
 	}
 
-	// Extract notification settings from manifest defaults
-	var manifestNotifications *di.ManifestNotifications
-	defaults := manifestData.Defaults.Notifications
-	hasNotificationDefaults := defaults.SlackChannel != "" || defaults.Webhook != "" || defaults.GitHubIssues != nil
-
-	var githubIssueLabels []string
-	githubIssueEnabled := false
-
-	if defaults.GitHubIssues != nil {
-		githubIssueEnabled = defaults.GitHubIssues.Enabled
-		if len(defaults.GitHubIssues.Labels) > 0 {
-			githubIssueLabels = append([]string(nil), defaults.GitHubIssues.Labels...)
+	var (
+		plan        *planner.Plan
+		invalidated []planner.WorkItem
+		reasons     map[string]string
+	)
+	if artifact != nil {
+		// Check again now that the run lock is held
+		if err := checkPlanArtifact(artifact, finalManifestPath, "", ""); err != nil {
+			return err
 		}
-	}
-
-	if hasNotificationDefaults {
-		// Default on_failure to true if not explicitly set
-		// This ensures failures are always notified unless explicitly disabled
-		onFailure := defaults.OnFailure
-		onSuccess := defaults.OnSuccess
-
-		// If neither flag is set, default on_failure to true
-		if !onFailure && !onSuccess {
-			onFailure = true
+		plan = &artifact.Plan
+		fmt.Printf("Using plan %s made %s\n", fromPlan, artifact.CreatedAt.Local().Format(time.RFC1123))
+
+		// Drop the items of an expired plan that no longer match the remote state
+		reasons = revalidateStalePlan(ctx, os.Stdout, cfg, manifestData, artifact.CreatedAt, plan.Items, logger)
+		if len(reasons) > 0 {
+			valid := make([]planner.WorkItem, 0, len(plan.Items))
+			for _, item := range plan.Items {
+				if _, ok := reasons[item.Repo]; ok {
+					invalidated = append(invalidated, item)
+					continue
+				}
+				valid = append(valid, item)
+			}
+			plan.Items = valid
 		}
-
-		manifestNotifications = &di.ManifestNotifications{
-			SlackChannel: defaults.SlackChannel,
-			OnFailure:    onFailure,
-			OnSuccess:    onSuccess,
-			Webhook:      defaults.Webhook,
+	} else {
+		planCtx := ctx
+		if cfg.Executor.MultiLevel {
+			planCtx = planner.WithWaves(ctx)
 		}
-
-		if defaults.GitHubIssues != nil {
-			manifestNotifications.GitHubIssues = &di.ManifestGitHubIssues{
-				Enabled: githubIssueEnabled,
-			}
-			manifestNotifications.GitHubIssues.Labels = githubIssueLabels
+		plan, err = planner.PlanReleaseSet(planCtx, container.Planner(), manifestData, targets)
+		if err != nil {
+			return newPlanningError("failed to generate plan", err)
 		}
-
-		logger.Debug("Found notification settings in manifest",
-			"slack_channel", manifestNotifications.SlackChannel,
-			"on_failure", manifestNotifications.OnFailure,
-			"on_success", manifestNotifications.OnSuccess,
-			"webhook", manifestNotifications.Webhook,
-			"github_issues_enabled", githubIssueEnabled,
-			"github_issue_labels", githubIssueLabels)
 	}
 
+	plan.Items = overrideBaseBranch(plan.Items, cfg.Executor.BaseBranch)
+
+	// Extract notification settings from manifest defaults
+	manifestNotifications := manifestNotificationSettings(manifestData, logger)
+
 	// Show planning statistics if dependency checking was enabled
 	if cfg.Executor.SkipUpToDate && plan.Stats.TotalDependents > 0 {
 		// Display strategy-specific header
@@ -262,28 +366,80 @@ func runRelease(manifestFlag, manifestArg, modulePath, version string) error {
 		fmt.Println()
 	}
 
+	printSkippedArchived(&plan.Stats)
+	printSkippedLocal(&plan.Stats)
+	printDuplicateDependents(os.Stdout, &plan.Stats)
+
 	if len(plan.Items) == 0 {
-		fmt.Printf("No work items produced for %s@%s\n", target.Module, target.Version)
+		if len(invalidated) > 0 && !cfg.Executor.DryRun {
+			newReleaseTracker(targets, plan, finalManifestPath, invalidated, reasons).finalize()
+		}
+		fmt.Printf("No work items produced for %s\n", label)
 		return nil
 	}
+	if canaries, _ := planner.SplitCanaries(plan.Items); local != nil && len(canaries) == 0 {
+		return newValidationError("--local-target only applies to canary dependents, and the plan has none", nil).
+			WithHint("mark dependents with canary: true in the manifest")
+	}
 
 	if cfg.Executor.DryRun {
-		fmt.Printf("DRY RUN: Would execute updates for %s@%s\n", target.Module, target.Version)
+		fmt.Printf("DRY RUN: Would execute updates for %s\n", label)
+		if cfg.Integration.GoProxy.URL != "" {
+			fmt.Printf("Would wait for %s to serve %s\n", cfg.Integration.GoProxy.URL, label)
+		}
+		printPlanEstimate(os.Stdout, &plan.Stats, cfg.Executor.ConcurrentLimit)
 		fmt.Printf("Would process %d work items:\n", len(plan.Items))
 		for i, item := range plan.Items {
 			fmt.Printf("  %d. %s (%s) -> %s\n", i+1, item.Repo, item.Module, item.BranchName)
 		}
-		return nil
+		printPlanWaves(os.Stdout, plan.Waves)
+		printPlanCanaries(os.Stdout, plan.Items)
+		if local != nil {
+			fmt.Printf("Canaries would test against the checkout of %s in %s\n", local.module, local.dir)
+		}
+
+		if cfg.Executor.DiffPreview() {
+			return previewReleaseDiffs(ctx, cfg, targets, plan.Items, manifestData.Defaults.NeverTouch, logger)
+		}
+
+		previewDir := ""
+		if savePreviews {
+			previewDir = prPreviewDir(cfg.State.Dir, target.Module, target.Version)
+			fmt.Printf("\nPR previews:\n")
+		}
+		return renderPRPreviews(os.Stdout, plan.Items, di.BrokerConfig(cfg), previewDir)
 	}
 
-	deps := newExecutionDeps()
-	stateManager := container.State()
-	summary := &state.Summary{Module: target.Module, Version: target.Version, StartTime: time.Now()}
-	if len(plan.Stats.SkippedUpToDateRepos) > 0 {
-		summary.SkippedUpToDate = append([]string(nil), plan.Stats.SkippedUpToDateRepos...)
+	approval, err := newApprovalGate(cfg, container.HTTPClient(), logger)
+	if err != nil {
+		return err
+	}
+	if err := approval.approvePlan(ctx, os.Stdout, target, plan); err != nil {
+		return err
 	}
-	tracker := newStateTracker(target.Module, target.Version, summary, stateManager, logger, nil)
 
+	for _, t := range targets {
+		if err := waitForProxy(ctx, os.Stdout, cfg.Integration.GoProxy, container.HTTPClient(), t.Module, t.Version); err != nil {
+			return err
+		}
+	}
+
+	deps := newExecutionDeps(cfg)
+	deps.approval = approval
+	deps.neverTouch = manifestData.Defaults.NeverTouch
+	deps.localTarget = local
+	for _, t := range targets {
+		items, err := preflightGoProxy(ctx, os.Stdout, deps.goTool, t.Module, t.Version, plan.Items)
+		if err != nil {
+			return err
+		}
+		plan.Items = items
+	}
+	if err := preflightBaseBranches(ctx, os.Stdout, planner.NewRemoteBranchChecker(checkTimeout(cfg), config.RepoURLs(cfg)), plan.Items); err != nil {
+		return err
+	}
+
+	stateManager := container.State()
 	executor := container.Executor()
 
 	// Get broker with manifest notification settings if available
@@ -297,32 +453,238 @@ func runRelease(manifestFlag, manifestArg, modulePath, version string) error {
 		brokerSvc = container.Broker()
 	}
 
-	fmt.Printf("Executing updates for %s@%s\n", target.Module, target.Version)
-	for i, item := range plan.Items {
-		fmt.Printf("  %d. %s (%s) -> %s\n", i+1, item.Repo, item.Module, item.BranchName)
-		itemState, err := processWorkItem(ctx, deps, cfg.Workspace.Path, item, executor, brokerSvc, logger, cfg.Executor.Timeout)
+	var waves *waveRelease
+	if len(plan.Waves) > 1 {
+		graph, err := planner.BuildGraph(manifestData, target.Module)
 		if err != nil {
-			logger.Warn("Work item completed with errors", "repo", item.Repo, "error", err)
+			return newPlanningError("failed to build the module graph", err)
 		}
-		tracker.record(itemState)
-
-		switch itemState.Status {
-		case execpkg.StatusCompleted:
-			if itemState.PRURL != "" {
-				fmt.Printf("    ✓ PR: %s\n", itemState.PRURL)
-			} else {
-				fmt.Printf("    ✓ Completed with commit %s\n", itemState.CommitHash)
-			}
-		case execpkg.StatusManualReview:
-			fmt.Printf("    ! Manual review required: %s\n", itemState.Reason)
-		case execpkg.StatusSkipped:
-			fmt.Printf("    ⏭ Skipped: %s\n", itemState.Reason)
-		default:
-			fmt.Printf("    ✗ Failed: %s\n", itemState.Reason)
+		waves = &waveRelease{
+			graph:        graph,
+			manifest:     manifestData,
+			manifestPath: finalManifestPath,
+			root:         target,
+			cfg:          cfg,
+			deps:         deps,
+			executor:     executor,
+			broker:       brokerSvc,
+			planner:      container.Planner(),
+			states:       stateManager,
+			httpClient:   container.HTTPClient(),
+			logger:       logger,
+			out:          os.Stdout,
+			versions:     latestModuleVersion(cfg, logger),
+			sleep:        sleepContext,
 		}
+		waves.prepare(ctx)
 	}
 
+	tracker := newReleaseTracker(targets, plan, finalManifestPath, invalidated, reasons)
+
+	fmt.Printf("Executing updates for %s\n", label)
+	held, holdErr := newCanaryRollout(cfg, brokerSvc, os.Stdout, logger).run(ctx, plan.Items, func(items []planner.WorkItem, offset int) []state.ItemState {
+		var states []state.ItemState
+		runWorkItems(ctx, cfg, deps, items, executor, brokerSvc, logger, tracker, func(i int, item planner.WorkItem, itemState state.ItemState, err error) {
+			fmt.Printf("  %d. %s (%s) -> %s\n", offset+i+1, item.Repo, item.Module, item.BranchName)
+			if err != nil {
+				logger.Warn("Work item completed with errors", "repo", item.Repo, "error", err)
+			}
+			printItemOutcome(os.Stdout, itemState)
+			waves.record(item, itemState)
+			states = append(states, itemState)
+		})
+		return states
+	})
+
 	tracker.finalize()
-	fmt.Printf("Release execution completed for %s@%s\n", target.Module, target.Version)
+	printRunTimings(os.Stdout, tracker.summary.Timings)
+	if holdErr != nil {
+		// Held-back items are not recorded, so resume picks them up
+		printHeldBack(os.Stdout, held, holdErr)
+		if err := writeRunReports(os.Stdout, cfg, tracker.summary); err != nil {
+			logger.Warn("Failed to write run report", "error", err)
+		}
+		return newExecutionError(fmt.Sprintf("canary rollout stopped; %d dependents were not updated", len(held)), holdErr).
+			WithHint("fix the canaries, then run `cascade resume` to update the rest")
+	}
+	if waves != nil {
+		tracker.summary.Downstream = waves.run(ctx)
+		tracker.saveSummary()
+	}
+	if err := writeRunReports(os.Stdout, cfg, tracker.summary); err != nil {
+		return err
+	}
+	fmt.Printf("Release execution completed for %s\n", label)
 	return nil
 }
+
+// newReleaseTracker starts recording the state of a release of targets,
+// recording the items re-validation dropped from a stale plan as skipped.
+func newReleaseTracker(targets []planner.Target, plan *planner.Plan, manifestPath string, invalidated []planner.WorkItem, reasons map[string]string) *stateTracker {
+	cfg := container.Config()
+	logger := container.Logger()
+	stateManager := container.State()
+	target := targets[0]
+
+	summary := &state.Summary{Module: target.Module, Version: target.Version, StartTime: time.Now()}
+	if len(targets) > 1 {
+		summary.ReleaseSet = releaseSetSpecs(targets)
+	}
+	if len(plan.Stats.SkippedUpToDateRepos) > 0 {
+		summary.SkippedUpToDate = append([]string(nil), plan.Stats.SkippedUpToDateRepos...)
+	}
+	summary.Inputs = captureRunInputs(stateManager, target.Module, target.Version, manifestPath, cfg, logger)
+	tracker := newStateTracker(target.Module, target.Version, summary, stateManager, logger, nil)
+	for _, item := range invalidated {
+		tracker.record(invalidatedItemState(item, reasons[item.Repo]))
+	}
+	return tracker
+}
+
+// releaseLabel names the modules of a release as "module@version, ...".
+func releaseLabel(targets []planner.Target) string {
+	return strings.Join(releaseSetSpecs(targets), ", ")
+}
+
+// releaseSetSpecs returns targets as module@version specs.
+func releaseSetSpecs(targets []planner.Target) []string {
+	specs := make([]string, 0, len(targets))
+	for _, t := range targets {
+		specs = append(specs, t.Module+"@"+t.Version)
+	}
+	return specs
+}
+
+// releaseSetTargets returns the targets of a release set given as repeated
+// --module module@version flags or a --release-set file, or nil when the flags
+// name a single module.
+func releaseSetTargets(modules []string, releaseSetPath string) ([]planner.Target, error) {
+	if releaseSetPath != "" {
+		if len(modules) > 0 {
+			return nil, newValidationError("--module cannot be combined with --release-set", nil)
+		}
+		targets, err := planner.LoadReleaseSet(releaseSetPath)
+		if err != nil {
+			return nil, newFileError("failed to load release set", err)
+		}
+		return targets, nil
+	}
+
+	if len(modules) == 1 && !strings.Contains(modules[0], "@") {
+		return nil, nil
+	}
+	if len(modules) == 0 {
+		return nil, nil
+	}
+	targets, err := planner.ParseTargets(modules)
+	if err != nil {
+		return nil, newValidationError("invalid release set", err).
+			WithHint("pass each module as --module module@version")
+	}
+	return targets, nil
+}
+
+// resolveReleaseTarget resolves the manifest path, module, and version a release
+// plans with from the flags, the configuration, and auto-detection.
+func resolveReleaseTarget(ctx context.Context, manifestFlag, manifestArg, modulePath, version string) (string, string, string, error) {
+	cfg := container.Config()
+	logger := container.Logger()
+
+	// Apply default discovery logic for manifest path
+	finalManifestPath := resolvePlanManifestPath(manifestFlag, manifestArg, cfg)
+	if finalManifestPath == "" {
+		return "", "", "", newValidationError("manifest path not provided and no default configured", nil)
+	}
+
+	// Apply default discovery logic for module path
+	finalModulePath := modulePath
+	if finalModulePath == "" && cfg != nil {
+		finalModulePath = cfg.Module // Use config as fallback
+	}
+
+	finalModulePath, moduleDir, err := applyModuleDefaults(finalModulePath)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	// Apply default discovery logic for version
+	finalVersion := version
+	if finalVersion == "" && cfg != nil {
+		finalVersion = cfg.Version // Use config as fallback
+	}
+
+	var versionWarnings []string
+	resolvers := moduleVersionResolution(container.Manifest(), finalManifestPath, finalModulePath)
+	finalVersion, versionWarnings, err = applyVersionDefaults(ctx, finalModulePath, finalVersion, moduleDir, resolvers, cfg)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	// Log version warnings if any
+	if len(versionWarnings) > 0 && logger != nil {
+		for _, warning := range versionWarnings {
+			logger.Warn("Version resolution warning", "warning", warning)
+		}
+	}
+	return finalManifestPath, finalModulePath, finalVersion, nil
+}
+
+// manifestNotificationSettings extracts the notification settings declared in the
+// manifest defaults, or nil when the manifest declares none.
+func manifestNotificationSettings(m *manifest.Manifest, logger di.Logger) *di.ManifestNotifications {
+	var manifestNotifications *di.ManifestNotifications
+	defaults := m.Defaults.Notifications
+	hasNotificationDefaults := defaults.SlackChannel != "" || defaults.Webhook != "" || defaults.GitHubIssues != nil
+
+	var githubIssueLabels []string
+	githubIssueEnabled := false
+
+	if defaults.GitHubIssues != nil {
+		githubIssueEnabled = defaults.GitHubIssues.Enabled
+		if len(defaults.GitHubIssues.Labels) > 0 {
+			githubIssueLabels = append([]string(nil), defaults.GitHubIssues.Labels...)
+		}
+	}
+
+	if hasNotificationDefaults {
+		// Default on_failure to true if not explicitly set
+		// This ensures failures are always notified unless explicitly disabled
+		onFailure := defaults.OnFailure
+		onSuccess := defaults.OnSuccess
+
+		// If neither flag is set, default on_failure to true
+		if !onFailure && !onSuccess {
+			onFailure = true
+		}
+
+		manifestNotifications = &di.ManifestNotifications{
+			SlackChannel: defaults.SlackChannel,
+			OnFailure:    onFailure,
+			OnSuccess:    onSuccess,
+			Webhook:      defaults.Webhook,
+		}
+
+		if defaults.GitHubIssues != nil {
+			manifestNotifications.GitHubIssues = &di.ManifestGitHubIssues{
+				Enabled:       githubIssueEnabled,
+				TitleTemplate: defaults.GitHubIssues.TitleTemplate,
+				BodyTemplate:  defaults.GitHubIssues.BodyTemplate,
+				OnRecurrence:  defaults.GitHubIssues.OnRecurrence,
+			}
+			manifestNotifications.GitHubIssues.Labels = githubIssueLabels
+			if len(defaults.GitHubIssues.Assignees) > 0 {
+				manifestNotifications.GitHubIssues.Assignees = append([]string(nil), defaults.GitHubIssues.Assignees...)
+			}
+		}
+
+		logger.Debug("Found notification settings in manifest",
+			"slack_channel", manifestNotifications.SlackChannel,
+			"on_failure", manifestNotifications.OnFailure,
+			"on_success", manifestNotifications.OnSuccess,
+			"webhook", manifestNotifications.Webhook,
+			"github_issues_enabled", githubIssueEnabled,
+			"github_issue_labels", githubIssueLabels)
+	}
+
+	return manifestNotifications
+}
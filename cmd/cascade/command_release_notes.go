@@ -0,0 +1,260 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/goliatone/cascade/internal/broker"
+	execpkg "github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/internal/state"
+	"github.com/spf13/cobra"
+)
+
+// newReleaseNotesCommand creates the release-notes subcommand
+func newReleaseNotesCommand() *cobra.Command {
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "release-notes [state-id]",
+		Short: "Generate a changelog from a recorded cascade run",
+		Long: `Release-notes renders a changelog for a module@version cascade run from the
+pull requests and outcomes recorded in state. Like wait, it asks the provider
+whether each pull request merged: only merged pull requests are listed as
+updated, and those still open or closed without merging are listed apart. The
+output is suitable for release descriptions, Homebrew/Scoop packaging notes, or
+further processing.
+
+Examples:
+  cascade release-notes go-errors@v1.4.0
+  cascade release-notes --module=github.com/example/lib --version=v1.2.3
+  cascade release-notes go-errors@v1.4.0 --format=json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			stateID := ""
+			if len(args) > 0 {
+				stateID = args[0]
+			}
+			return runReleaseNotes(cmd.OutOrStdout(), stateID, format)
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "markdown", "Output format: markdown or json")
+
+	return cmd
+}
+
+// releaseNotes is the structured changelog for a single cascade run. Updated
+// only lists items whose pull request merged; Open lists pull requests that
+// have not merged yet, or whose state could not be checked.
+type releaseNotes struct {
+	Module       string             `json:"module"`
+	Version      string             `json:"version"`
+	Date         time.Time          `json:"date"`
+	Updated      []releaseNoteEntry `json:"updated"`
+	Open         []releaseNoteEntry `json:"open"`
+	Closed       []releaseNoteEntry `json:"closed"`
+	RolledBack   []releaseNoteEntry `json:"rolled_back"`
+	ManualReview []releaseNoteEntry `json:"manual_review"`
+	Failed       []releaseNoteEntry `json:"failed"`
+	UpToDate     []string           `json:"up_to_date"`
+}
+
+// releaseNoteEntry describes the outcome for one dependent repository.
+type releaseNoteEntry struct {
+	Repo   string `json:"repo"`
+	PRURL  string `json:"pr_url,omitempty"`
+	Commit string `json:"commit,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+func runReleaseNotes(w io.Writer, stateID, format string) error {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format != "markdown" && format != "json" {
+		return newValidationError(fmt.Sprintf("unsupported release notes format %q", format), nil).
+			WithHint("use --format=markdown or --format=json")
+	}
+
+	module, version, err := resolveModuleVersion(stateID, container.Config())
+	if err != nil {
+		return newValidationError(err.Error(), nil)
+	}
+
+	summary, err := container.State().LoadSummary(module, version)
+	if err != nil {
+		if err == state.ErrNotFound {
+			return newStateError(fmt.Sprintf("no saved state found for %s@%s", module, version), nil).
+				WithHint("release notes are generated from state recorded by `cascade release`")
+		}
+		return newStateError("failed to load summary", err)
+	}
+
+	items, err := container.State().LoadItemStates(module, version)
+	if err != nil {
+		return newStateError("failed to load item states", err)
+	}
+
+	notes := buildReleaseNotes(context.Background(), summary, items, providerPRStatus(container.Broker()))
+
+	if format == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(notes); err != nil {
+			return newGenericError("failed to encode release notes", err)
+		}
+		return nil
+	}
+
+	renderReleaseNotesMarkdown(w, notes)
+	return nil
+}
+
+// prStatusFunc reports the state of the pull request recorded for an item.
+type prStatusFunc func(ctx context.Context, item state.ItemState) (*broker.PullRequestStatus, error)
+
+// providerPRStatus looks pull requests up with the provider that opened them,
+// the way wait does.
+func providerPRStatus(b broker.Broker) prStatusFunc {
+	return func(ctx context.Context, item state.ItemState) (*broker.PullRequestStatus, error) {
+		reverter, ok := b.(broker.Reverter)
+		if !ok {
+			return nil, fmt.Errorf("the configured provider cannot report whether pull requests merged")
+		}
+		pr, err := itemPullRequest(item)
+		if err != nil {
+			return nil, err
+		}
+		return reverter.PullRequestStatus(withItemProvider(ctx, item.Provider), pr)
+	}
+}
+
+// buildReleaseNotes groups recorded item states by outcome. Item states saved
+// individually take precedence over the copies embedded in the summary.
+// Completed items count as updated only once prStatus reports their pull
+// request merged.
+func buildReleaseNotes(ctx context.Context, summary *state.Summary, items []state.ItemState, prStatus prStatusFunc) releaseNotes {
+	date := summary.EndTime
+	if date.IsZero() {
+		date = summary.StartTime
+	}
+
+	notes := releaseNotes{
+		Module:       summary.Module,
+		Version:      summary.Version,
+		Date:         date,
+		Updated:      []releaseNoteEntry{},
+		Open:         []releaseNoteEntry{},
+		Closed:       []releaseNoteEntry{},
+		RolledBack:   []releaseNoteEntry{},
+		ManualReview: []releaseNoteEntry{},
+		Failed:       []releaseNoteEntry{},
+		UpToDate:     append([]string{}, summary.SkippedUpToDate...),
+	}
+
+	for _, item := range mergeItemStates(summary, items) {
+		entry := releaseNoteEntry{
+			Repo:   item.Repo,
+			PRURL:  item.PRURL,
+			Commit: item.CommitHash,
+			Reason: strings.TrimSpace(item.Reason),
+		}
+
+		switch item.Status {
+		case execpkg.StatusCompleted:
+			addCompletedReleaseNote(ctx, &notes, item, entry, prStatus)
+		case execpkg.StatusManualReview:
+			notes.ManualReview = append(notes.ManualReview, entry)
+		case execpkg.StatusFailed:
+			notes.Failed = append(notes.Failed, entry)
+		case execpkg.StatusSkipped, execpkg.StatusNoChange:
+			notes.UpToDate = append(notes.UpToDate, item.Repo)
+		}
+	}
+	sort.Strings(notes.UpToDate)
+
+	return notes
+}
+
+// addCompletedReleaseNote files a completed item under the state of its pull
+// request: updated once merged, and open or closed otherwise.
+func addCompletedReleaseNote(ctx context.Context, notes *releaseNotes, item state.ItemState, entry releaseNoteEntry, prStatus prStatusFunc) {
+	switch {
+	case item.Revert.Done():
+		entry.Reason = "rolled back: " + string(item.Revert.Action)
+		notes.RolledBack = append(notes.RolledBack, entry)
+		return
+	case item.PRURL == "":
+		entry.Reason = "no pull request recorded"
+		notes.Open = append(notes.Open, entry)
+		return
+	}
+
+	status, err := prStatus(ctx, item)
+	switch {
+	case err != nil:
+		entry.Reason = fmt.Sprintf("merge state unknown: %v", err)
+		notes.Open = append(notes.Open, entry)
+	case status.Merged:
+		if status.MergeCommitSHA != "" {
+			entry.Commit = status.MergeCommitSHA
+		}
+		notes.Updated = append(notes.Updated, entry)
+	case status.State == broker.PullRequestClosed:
+		notes.Closed = append(notes.Closed, entry)
+	default:
+		notes.Open = append(notes.Open, entry)
+	}
+}
+
+func renderReleaseNotesMarkdown(w io.Writer, notes releaseNotes) {
+	fmt.Fprintf(w, "## %s %s\n", notes.Module, notes.Version)
+	if !notes.Date.IsZero() {
+		fmt.Fprintf(w, "\nDependency update rolled out on %s.\n", notes.Date.UTC().Format("2006-01-02"))
+	}
+
+	renderReleaseNoteSection(w, "Updated", notes.Updated)
+	renderReleaseNoteSection(w, "Opened, not merged yet", notes.Open)
+	renderReleaseNoteSection(w, "Closed without merging", notes.Closed)
+	renderReleaseNoteSection(w, "Rolled back", notes.RolledBack)
+	renderReleaseNoteSection(w, "Needs manual review", notes.ManualReview)
+	renderReleaseNoteSection(w, "Failed", notes.Failed)
+
+	if len(notes.UpToDate) > 0 {
+		fmt.Fprintf(w, "\n### Already up to date\n\n")
+		for _, repo := range notes.UpToDate {
+			fmt.Fprintf(w, "- %s\n", repo)
+		}
+	}
+}
+
+func renderReleaseNoteSection(w io.Writer, title string, entries []releaseNoteEntry) {
+	if len(entries) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "\n### %s\n\n", title)
+	for _, entry := range entries {
+		line := "- " + entry.Repo
+		if entry.PRURL != "" {
+			line += ": " + entry.PRURL
+		}
+		if entry.Commit != "" {
+			line += fmt.Sprintf(" (`%s`)", shortCommit(entry.Commit))
+		}
+		if entry.Reason != "" {
+			line += " - " + entry.Reason
+		}
+		fmt.Fprintln(w, line)
+	}
+}
+
+func shortCommit(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goliatone/cascade/internal/broker"
+	execpkg "github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/internal/state"
+	"github.com/goliatone/cascade/pkg/version"
+)
+
+func TestBuildReleaseNotes(t *testing.T) {
+	summary := &state.Summary{
+		Module:          "github.com/example/lib",
+		Version:         "v1.2.3",
+		EndTime:         time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC),
+		SkippedUpToDate: []string{"example/zeta"},
+		Items: []state.ItemState{
+			{Repo: "example/beta", Status: execpkg.StatusFailed, Reason: "stale summary entry"},
+		},
+	}
+	items := []state.ItemState{
+		{Repo: "example/beta", Status: execpkg.StatusCompleted, PRURL: "https://github.com/example/beta/pull/7", CommitHash: "0123456789abcdef"},
+		{Repo: "example/alpha", Status: execpkg.StatusManualReview, PRURL: "https://github.com/example/alpha/pull/3", Reason: "tests failed"},
+		{Repo: "example/gamma", Status: execpkg.StatusSkipped},
+		{Repo: "example/delta", Status: execpkg.StatusCompleted, PRURL: "https://github.com/example/delta/pull/2"},
+		{Repo: "example/epsilon", Status: execpkg.StatusCompleted, PRURL: "https://github.com/example/epsilon/pull/5"},
+		{Repo: "example/eta", Status: execpkg.StatusCompleted, PRURL: "https://github.com/example/eta/pull/9"},
+		{Repo: "example/theta", Status: execpkg.StatusCompleted, PRURL: "https://github.com/example/theta/pull/4",
+			Revert: &state.RevertOutcome{Action: state.RevertClosed}},
+	}
+	statuses := map[string]*broker.PullRequestStatus{
+		"example/beta":    prMerged,
+		"example/delta":   prOpen,
+		"example/epsilon": prClosed,
+	}
+	prStatus := func(ctx context.Context, item state.ItemState) (*broker.PullRequestStatus, error) {
+		if item.Revert.Done() {
+			t.Errorf("looked up the pull request of rolled back item %s", item.Repo)
+		}
+		if status, ok := statuses[item.Repo]; ok {
+			return status, nil
+		}
+		return nil, errors.New("rate limited")
+	}
+
+	notes := buildReleaseNotes(context.Background(), summary, items, prStatus)
+
+	if len(notes.Updated) != 1 || notes.Updated[0].Repo != "example/beta" {
+		t.Fatalf("expected item state to override summary entry, got %+v", notes.Updated)
+	}
+	if len(notes.Open) != 2 || notes.Open[0].Repo != "example/delta" || notes.Open[1].Reason != "merge state unknown: rate limited" {
+		t.Fatalf("unexpected open entries: %+v", notes.Open)
+	}
+	if len(notes.Closed) != 1 || notes.Closed[0].Repo != "example/epsilon" {
+		t.Fatalf("unexpected closed entries: %+v", notes.Closed)
+	}
+	if len(notes.RolledBack) != 1 || notes.RolledBack[0].Repo != "example/theta" {
+		t.Fatalf("unexpected rolled back entries: %+v", notes.RolledBack)
+	}
+	if len(notes.Failed) != 0 {
+		t.Fatalf("expected no failures, got %+v", notes.Failed)
+	}
+	if len(notes.ManualReview) != 1 || notes.ManualReview[0].Reason != "tests failed" {
+		t.Fatalf("unexpected manual review entries: %+v", notes.ManualReview)
+	}
+	if strings.Join(notes.UpToDate, ",") != "example/gamma,example/zeta" {
+		t.Fatalf("unexpected up-to-date repos: %v", notes.UpToDate)
+	}
+
+	var buf bytes.Buffer
+	renderReleaseNotesMarkdown(&buf, notes)
+	expected := "## github.com/example/lib v1.2.3\n\n" +
+		"Dependency update rolled out on 2025-03-04.\n\n" +
+		"### Updated\n\n" +
+		"- example/beta: https://github.com/example/beta/pull/7 (`0123456`)\n\n" +
+		"### Opened, not merged yet\n\n" +
+		"- example/delta: https://github.com/example/delta/pull/2\n" +
+		"- example/eta: https://github.com/example/eta/pull/9 - merge state unknown: rate limited\n\n" +
+		"### Closed without merging\n\n" +
+		"- example/epsilon: https://github.com/example/epsilon/pull/5\n\n" +
+		"### Rolled back\n\n" +
+		"- example/theta: https://github.com/example/theta/pull/4 - rolled back: closed\n\n" +
+		"### Needs manual review\n\n" +
+		"- example/alpha: https://github.com/example/alpha/pull/3 - tests failed\n\n" +
+		"### Already up to date\n\n" +
+		"- example/gamma\n" +
+		"- example/zeta\n"
+	if buf.String() != expected {
+		t.Fatalf("unexpected markdown:\n%s\nwant:\n%s", buf.String(), expected)
+	}
+}
+
+func TestPrintBuildInfo(t *testing.T) {
+	info := version.Info{ProjectName: "cascade", Tag: "v1.2.3", Version: "1.2.3", Commit: "abc1234"}
+
+	var jsonOut bytes.Buffer
+	if err := printBuildInfo(&jsonOut, info, "json"); err != nil {
+		t.Fatalf("printBuildInfo(json) error = %v", err)
+	}
+	var decoded map[string]string
+	if err := json.Unmarshal(jsonOut.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid JSON output: %v", err)
+	}
+	if decoded["tag"] != "v1.2.3" || decoded["project_name"] != "cascade" {
+		t.Fatalf("unexpected JSON output: %v", decoded)
+	}
+
+	var envOut bytes.Buffer
+	if err := printBuildInfo(&envOut, info, "env"); err != nil {
+		t.Fatalf("printBuildInfo(env) error = %v", err)
+	}
+	if !strings.Contains(envOut.String(), "CASCADE_VERSION=1.2.3\n") {
+		t.Fatalf("unexpected env output: %q", envOut.String())
+	}
+
+	err := printBuildInfo(&bytes.Buffer{}, info, "yaml")
+	if cliErr := asCLIError(err); cliErr == nil || cliErr.ExitCode() != ExitValidationError {
+		t.Fatalf("expected validation error for unsupported format, got %v", err)
+	}
+}
@@ -399,7 +399,7 @@ modules:
 			defer func() { container = originalContainer }()
 
 			// Call the function under test
-			err = runRelease("", manifestPath, "", "")
+			err = runRelease("", manifestPath, "", "", false, 0, "")
 
 			// Check results
 			if tt.expectError && err == nil {
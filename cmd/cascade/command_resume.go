@@ -3,6 +3,8 @@ package main
 import (
 	"context"
 	"fmt"
+	"os"
+	"strings"
 	"time"
 
 	execpkg "github.com/goliatone/cascade/internal/executor"
@@ -13,23 +15,202 @@ import (
 
 // newResumeCommand creates the resume subcommand
 func newResumeCommand() *cobra.Command {
-	return &cobra.Command{
+	var (
+		selection       resumeSelection
+		waitForLock     time.Duration
+		currentManifest bool
+		reports         []string
+		reportDir       string
+	)
+
+	cmd := &cobra.Command{
 		Use:   "resume [state-id]",
 		Short: "Resume a previously interrupted operation",
 		Long: `Resume continues a previously interrupted cascade operation
-from its last known state using the state management system.`,
+from its last known state using the state management system.
+
+By default every work item that has not completed or failed is reprocessed.
+Items whose branch was already pushed continue from the phase that failed: the
+pull request is created and notifications are sent without updating and testing
+again. Use the selectors to rebuild a partial plan from the stored item states
+instead.
+
+Failed items are only retried with --retry-failed or --failed-only, within the
+retry policy of their dependent: an item is held back once it used its
+retry.max_attempts, and until its retry.backoff has passed since it last failed.
+
+The plan is rebuilt from the copy of the manifest stored with the release, so
+edits made to the manifest since do not change what is resumed. Pass
+--current-manifest to plan with the manifest on disk instead.
+
+Examples:
+  cascade resume go-errors@v1.4.0                             # Reprocess all unfinished items
+  cascade resume go-errors@v1.4.0 --retry-failed              # Also retry failed items
+  cascade resume go-errors@v1.4.0 --failed-only               # Only items recorded as failed
+  cascade resume go-errors@v1.4.0 --from=goliatone/go-router  # Continue from a repository in plan order
+  cascade resume go-errors@v1.4.0 --retry-item=goliatone/go-auth
+  cascade resume go-errors@v1.4.0 --wait-for-lock=10m         # Queue behind a run already processing this version
+  cascade resume go-errors@v1.4.0 --current-manifest          # Plan with the edited manifest on disk
+  cascade resume go-errors@v1.4.0 --report=junit              # Write a JUnit run report for CI`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			stateID := ""
 			if len(args) > 0 {
 				stateID = args[0]
 			}
-			return runResume(stateID)
+			if err := applyReportFlags(container.Config(), reports, reportDir); err != nil {
+				return err
+			}
+			return runResumeWithSelection(stateID, selection, waitForLock, currentManifest)
 		},
 	}
+
+	cmd.Flags().BoolVar(&selection.RetryFailed, "retry-failed", false, "Also retry failed items whose retry policy allows another attempt")
+	cmd.Flags().BoolVar(&selection.FailedOnly, "failed-only", false, "Only reprocess items whose stored state is failed, within their retry policy")
+	cmd.Flags().StringVar(&selection.From, "from", "", "Skip items before this repository in plan order")
+	cmd.Flags().StringSliceVar(&selection.RetryItems, "retry-item", nil, "Reprocess only these repositories, regardless of stored status (repeatable)")
+	cmd.Flags().DurationVar(&waitForLock, "wait-for-lock", 0, "When another run holds the lock for this module@version, wait up to this long instead of failing")
+	cmd.Flags().BoolVar(&currentManifest, "current-manifest", false, "Plan with the manifest on disk instead of the copy stored with the release")
+	addReportFlags(cmd, &reports, &reportDir)
+
+	return cmd
+}
+
+// resumeSelection narrows which work items a resume reprocesses.
+type resumeSelection struct {
+	// RetryFailed retries failed items too; FailedOnly implies it
+	RetryFailed bool
+	FailedOnly  bool
+	From        string
+	RetryItems  []string
+}
+
+// resumeCandidate is a work item selected for resume along with its stored state.
+type resumeCandidate struct {
+	Item     planner.WorkItem
+	State    state.ItemState
+	HasState bool
+	// Force reprocesses the item even when its stored state is terminal
+	Force bool
+	// RetryFailed retries the item when it failed, within its retry policy
+	RetryFailed bool
+}
+
+// selectResumeItems rebuilds the partial plan a resume should process from the
+// regenerated plan and the stored item states.
+func selectResumeItems(items []planner.WorkItem, itemStates []state.ItemState, sel resumeSelection) ([]resumeCandidate, error) {
+	if len(sel.RetryItems) > 0 && (sel.FailedOnly || sel.From != "") {
+		return nil, newValidationError("--retry-item cannot be combined with --failed-only or --from", nil).
+			WithHint("--retry-item already names the exact items to reprocess")
+	}
+
+	statesByRepo := make(map[string]state.ItemState, len(itemStates))
+	for _, st := range itemStates {
+		statesByRepo[st.Repo] = st
+	}
+	indexByRepo := make(map[string]int, len(items))
+	for i, item := range items {
+		indexByRepo[item.Repo] = i
+	}
+
+	if len(sel.RetryItems) > 0 {
+		candidates := make([]resumeCandidate, 0, len(sel.RetryItems))
+		seen := make(map[string]bool, len(sel.RetryItems))
+		for _, repo := range sel.RetryItems {
+			repo = strings.TrimSpace(repo)
+			if repo == "" || seen[repo] {
+				continue
+			}
+			seen[repo] = true
+
+			idx, ok := indexByRepo[repo]
+			if !ok {
+				return nil, newValidationError(fmt.Sprintf("repository %s is not part of the regenerated plan", repo), nil).
+					WithHint("run `cascade resume --dry-run` to list the items in the plan")
+			}
+			st, hasState := statesByRepo[repo]
+			candidates = append(candidates, resumeCandidate{Item: items[idx], State: st, HasState: hasState, Force: true})
+		}
+		return candidates, nil
+	}
+
+	start := 0
+	if sel.From != "" {
+		idx, ok := indexByRepo[strings.TrimSpace(sel.From)]
+		if !ok {
+			return nil, newValidationError(fmt.Sprintf("repository %s is not part of the regenerated plan", sel.From), nil).
+				WithHint("run `cascade resume --dry-run` to list the items in the plan")
+		}
+		start = idx
+	}
+
+	candidates := make([]resumeCandidate, 0, len(items)-start)
+	for _, item := range items[start:] {
+		st, hasState := statesByRepo[item.Repo]
+		if sel.FailedOnly && (!hasState || st.Status != execpkg.StatusFailed) {
+			continue
+		}
+		candidates = append(candidates, resumeCandidate{Item: item, State: st, HasState: hasState, RetryFailed: sel.RetryFailed || sel.FailedOnly})
+	}
+	return candidates, nil
+}
+
+// done reports whether the candidate already reached a terminal state and should
+// not be reprocessed. Completed items recorded with phases are only done once
+// their pull request and notification phases have completed too. Items rolled
+// back by `cascade revert` are done.
+func (c resumeCandidate) done() bool {
+	if c.Force || !c.HasState {
+		return false
+	}
+	if c.State.Revert.Done() {
+		return true
+	}
+	switch c.State.Status {
+	case execpkg.StatusSkipped, execpkg.StatusNoChange:
+		return true
+	case execpkg.StatusCompleted:
+		// States saved before phases were tracked only know the overall status
+		return len(c.State.Phases) == 0 || c.State.NextPhase() == ""
+	}
+	return false
+}
+
+// held returns why a failed candidate is not retried at now, or "" when it may
+// run: failed items need --retry-failed and must be within their retry policy.
+func (c resumeCandidate) held(now time.Time) string {
+	if c.Force || !c.HasState || c.State.Status != execpkg.StatusFailed {
+		return ""
+	}
+	switch {
+	case !c.RetryFailed:
+		return "failed (pass --retry-failed to retry it)"
+	case !c.State.AttemptsLeft():
+		return fmt.Sprintf("failed after %d of %d attempts", c.State.Attempts, c.State.MaxAttempts)
+	case now.Before(c.State.RetryAt()):
+		return fmt.Sprintf("failed, backing off until %s", c.State.RetryAt().Format(time.RFC3339))
+	}
+	return ""
+}
+
+// resumePhase returns the phase the candidate continues from, or "" when it is
+// processed from the start. Only items whose branch was pushed skip ahead.
+func (c resumeCandidate) resumePhase() state.Phase {
+	if c.Force || !c.HasState || !c.State.Completed(state.PhasePushed) {
+		return ""
+	}
+	switch c.State.Status {
+	case execpkg.StatusCompleted, execpkg.StatusManualReview:
+		return c.State.NextPhase()
+	}
+	return ""
 }
 
 func runResume(stateID string) error {
+	return runResumeWithSelection(stateID, resumeSelection{}, 0, false)
+}
+
+func runResumeWithSelection(stateID string, selection resumeSelection, waitForLock time.Duration, currentManifest bool) error {
 	start := time.Now()
 	logger := container.Logger()
 	cfg := container.Config()
@@ -50,10 +231,23 @@ func runResume(stateID string) error {
 		return newValidationError(err.Error(), nil)
 	}
 
+	// Take the run lock before reading state, so a queued resume sees what the
+	// run it waited for recorded
+	if !cfg.Executor.DryRun {
+		guard, err := acquireRunLock(ctx, os.Stdout, container.State(), module, version, waitForLock)
+		if err != nil {
+			return err
+		}
+		if guard != nil {
+			defer guard.Release()
+		}
+	}
+
 	summary, err := container.State().LoadSummary(module, version)
 	if err != nil {
 		if err == state.ErrNotFound {
-			return fmt.Errorf("no saved state found for %s@%s", module, version)
+			return newStateError(fmt.Sprintf("no saved state found for %s@%s", module, version), nil).
+				WithHint("check the module and version, or run `cascade release` to create state")
 		}
 		return newStateError("failed to load summary", err)
 	}
@@ -64,18 +258,35 @@ func runResume(stateID string) error {
 	}
 
 	manifestPath := resolveManifestPath("", cfg)
-	manifestData, err := container.Manifest().Load(manifestPath)
+	manifestData, inputs, err := loadResumeManifest(os.Stdout, container.State(), container.Manifest(), summary, manifestPath, currentManifest, cfg, logger)
 	if err != nil {
-		return newFileError("failed to load manifest", err)
+		return err
 	}
 
-	plan, err := container.Planner().Plan(ctx, manifestData, planner.Target{Module: module, Version: version})
+	targets := []planner.Target{{Module: module, Version: version}}
+	if len(summary.ReleaseSet) > 0 {
+		// A release set is resumed as the set it was released with
+		if targets, err = planner.ParseTargets(summary.ReleaseSet); err != nil {
+			return newStateError("failed to read the release set of the saved state", err)
+		}
+	}
+	plan, err := planner.PlanReleaseSet(ctx, container.Planner(), manifestData, targets)
 	if err != nil {
 		return newPlanningError("failed to regenerate plan", err)
 	}
 
+	candidates, err := selectResumeItems(plan.Items, itemStates, selection)
+	if err != nil {
+		return err
+	}
+
 	if cfg.Executor.DryRun {
-		printResumeSummary(module, version, itemStates, plan)
+		partial := *plan
+		partial.Items = make([]planner.WorkItem, 0, len(candidates))
+		for _, candidate := range candidates {
+			partial.Items = append(partial.Items, candidate.Item)
+		}
+		printResumeSummary(module, version, itemStates, &partial)
 		return nil
 	}
 
@@ -83,40 +294,86 @@ func runResume(stateID string) error {
 		return newExecutionError("failed to prepare workspace", err)
 	}
 
-	deps := newExecutionDeps()
+	deps := newExecutionDeps(cfg)
+	deps.neverTouch = manifestData.Defaults.NeverTouch
 	stateManager := container.State()
+	if inputs != "" {
+		summary.Inputs = inputs
+	}
 	tracker := newStateTracker(module, version, summary, stateManager, logger, itemStates)
 	tracker.summary.RetryCount++
 	tracker.saveSummary()
 
-	statesByRepo := make(map[string]state.ItemState, len(itemStates))
-	for _, st := range itemStates {
-		statesByRepo[st.Repo] = st
-	}
-
 	executor := container.Executor()
 	brokerSvc := container.Broker()
 
-	retryCount := 0
-	for i, item := range plan.Items {
-		currentState, hasState := statesByRepo[item.Repo]
-		if hasState && (currentState.Status == execpkg.StatusCompleted || currentState.Status == execpkg.StatusSkipped) {
-			fmt.Printf("  %d. %s already %s\n", i+1, item.Repo, currentState.Status)
+	retry := make([]planner.WorkItem, 0, len(candidates))
+	positions := make([]int, 0, len(candidates))
+	phases := make([]state.Phase, 0, len(candidates))
+	held := 0
+	now := time.Now()
+
+	// A run older than plan_max_age is re-validated before its items run again
+	pending := make([]planner.WorkItem, 0, len(candidates))
+	for _, candidate := range candidates {
+		if !candidate.done() && candidate.held(now) == "" {
+			pending = append(pending, candidate.Item)
+		}
+	}
+	invalidated := revalidateStalePlan(ctx, os.Stdout, cfg, manifestData, summary.StartTime, pending, logger)
+
+	for i, candidate := range candidates {
+		if candidate.done() {
+			fmt.Printf("  %d. %s already %s\n", i+1, candidate.Item.Repo, candidate.State.Status)
+			continue
+		}
+		if reason := candidate.held(now); reason != "" {
+			fmt.Printf("  %d. %s %s\n", i+1, candidate.Item.Repo, reason)
+			held++
+			continue
+		}
+		if reason, ok := invalidated[candidate.Item.Repo]; ok {
+			fmt.Printf("  %d. %s invalidated: %s\n", i+1, candidate.Item.Repo, reason)
+			tracker.record(invalidatedItemState(candidate.Item, reason))
 			continue
 		}
+		phase := candidate.resumePhase()
+		if phase != "" {
+			tracker.resumeFrom(candidate.State)
+		}
+		retry = append(retry, candidate.Item)
+		positions = append(positions, i)
+		phases = append(phases, phase)
+	}
+	retryCount := len(retry)
 
-		retryCount++
-		fmt.Printf("  %d. Resuming %s (%s) -> %s\n", i+1, item.Repo, item.Module, item.BranchName)
+	retry, err = preflightGoProxy(ctx, os.Stdout, deps.goTool, module, version, retry)
+	if err != nil {
+		return err
+	}
 
-		stateItem, err := processWorkItem(ctx, deps, cfg.Workspace.Path, item, executor, brokerSvc, logger, cfg.Executor.Timeout)
+	runWorkItems(ctx, cfg, deps, retry, executor, brokerSvc, logger, tracker, func(i int, item planner.WorkItem, itemState state.ItemState, err error) {
+		if phases[i] != "" {
+			fmt.Printf("  %d. Resumed %s (%s) -> %s from %s\n", positions[i]+1, item.Repo, item.Module, item.BranchName, phases[i])
+		} else {
+			fmt.Printf("  %d. Resumed %s (%s) -> %s\n", positions[i]+1, item.Repo, item.Module, item.BranchName)
+		}
 		if err != nil {
 			logger.Warn("Resume attempt finished with errors", "repo", item.Repo, "error", err)
 		}
-		tracker.record(stateItem)
-	}
+		printItemOutcome(os.Stdout, itemState)
+	})
 
 	tracker.finalize()
-	if retryCount == 0 {
+	printRunTimings(os.Stdout, tracker.summary.Timings)
+	if err := writeRunReports(os.Stdout, cfg, tracker.summary); err != nil {
+		return err
+	}
+	if len(candidates) == 0 && len(plan.Items) > 0 {
+		fmt.Printf("No work items for %s@%s matched the resume selection\n", module, version)
+	} else if retryCount == 0 && held > 0 {
+		fmt.Printf("No work items for %s@%s were reprocessed (%d failed items held back)\n", module, version, held)
+	} else if retryCount == 0 {
 		fmt.Printf("All work items for %s@%s are already complete\n", module, version)
 	} else {
 		fmt.Printf("Resume completed for %s@%s (reprocessed %d items)\n", module, version, retryCount)
@@ -0,0 +1,350 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goliatone/cascade/internal/broker"
+	execpkg "github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/internal/manifest"
+	"github.com/goliatone/cascade/internal/planner"
+	"github.com/goliatone/cascade/internal/state"
+)
+
+func TestSelectResumeItems(t *testing.T) {
+	items := []planner.WorkItem{
+		{Repo: "example/a"},
+		{Repo: "example/b"},
+		{Repo: "example/c"},
+		{Repo: "example/d"},
+		{Repo: "example/e"},
+		{Repo: "example/f"},
+	}
+	states := []state.ItemState{
+		{Repo: "example/a", Status: execpkg.StatusCompleted},
+		{Repo: "example/b", Status: execpkg.StatusFailed},
+		{Repo: "example/c", Status: execpkg.StatusManualReview},
+		{Repo: "example/e", Status: execpkg.StatusCompleted, Phases: state.PhaseOrder},
+		{Repo: "example/f", Status: execpkg.StatusCompleted, Phases: []state.Phase{state.PhaseUpdated, state.PhaseTested, state.PhasePushed, state.PhasePRCreated}},
+	}
+
+	tests := []struct {
+		name      string
+		selection resumeSelection
+		wantRepos []string
+		wantDone  []string
+		wantErr   bool
+	}{
+		{
+			name:      "default keeps all items and marks completed ones done",
+			wantRepos: []string{"example/a", "example/b", "example/c", "example/d", "example/e", "example/f"},
+			wantDone:  []string{"example/a", "example/e"},
+		},
+		{
+			name:      "failed only",
+			selection: resumeSelection{FailedOnly: true},
+			wantRepos: []string{"example/b"},
+		},
+		{
+			name:      "from repository",
+			selection: resumeSelection{From: "example/c"},
+			wantRepos: []string{"example/c", "example/d", "example/e", "example/f"},
+			wantDone:  []string{"example/e"},
+		},
+		{
+			name:      "from combined with failed only",
+			selection: resumeSelection{From: "example/c", FailedOnly: true},
+			wantRepos: []string{},
+		},
+		{
+			name:      "retry item forces completed items",
+			selection: resumeSelection{RetryItems: []string{"example/a", "example/d", "example/a"}},
+			wantRepos: []string{"example/a", "example/d"},
+		},
+		{
+			name:      "unknown from repository",
+			selection: resumeSelection{From: "example/missing"},
+			wantErr:   true,
+		},
+		{
+			name:      "unknown retry item",
+			selection: resumeSelection{RetryItems: []string{"example/missing"}},
+			wantErr:   true,
+		},
+		{
+			name:      "retry item with other selectors",
+			selection: resumeSelection{RetryItems: []string{"example/a"}, FailedOnly: true},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			candidates, err := selectResumeItems(items, states, tt.selection)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				if cliErr := asCLIError(err); cliErr.Code != ExitValidationError {
+					t.Fatalf("expected validation error, got code %d", cliErr.Code)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("selectResumeItems() error = %v", err)
+			}
+
+			repos := []string{}
+			done := []string(nil)
+			for _, candidate := range candidates {
+				repos = append(repos, candidate.Item.Repo)
+				if candidate.done() {
+					done = append(done, candidate.Item.Repo)
+				}
+			}
+			if !reflect.DeepEqual(repos, tt.wantRepos) {
+				t.Errorf("repos = %v, want %v", repos, tt.wantRepos)
+			}
+			if !reflect.DeepEqual(done, tt.wantDone) {
+				t.Errorf("done = %v, want %v", done, tt.wantDone)
+			}
+		})
+	}
+}
+
+func TestResumeCandidateDoneAfterRevert(t *testing.T) {
+	reverted := state.ItemState{Status: execpkg.StatusFailed, Revert: &state.RevertOutcome{Action: state.RevertClosed}}
+	if !(resumeCandidate{HasState: true, State: reverted}).done() {
+		t.Error("expected a reverted item to be done")
+	}
+	if (resumeCandidate{HasState: true, Force: true, State: reverted}).done() {
+		t.Error("expected --retry-item to redo a reverted item")
+	}
+
+	failedRevert := state.ItemState{Status: execpkg.StatusFailed, Revert: &state.RevertOutcome{Action: state.RevertFailed}}
+	if (resumeCandidate{HasState: true, State: failedRevert}).done() {
+		t.Error("expected an item whose revert failed to stay resumable")
+	}
+}
+
+func TestResumeCandidateHeld(t *testing.T) {
+	now := time.Now()
+	failed := state.ItemState{Status: execpkg.StatusFailed, Attempts: 2, LastUpdated: now.Add(-time.Minute), MaxAttempts: 3, RetryBackoff: time.Minute}
+
+	tests := []struct {
+		name      string
+		candidate resumeCandidate
+		want      string
+	}{
+		{
+			name:      "pending item",
+			candidate: resumeCandidate{},
+		},
+		{
+			name:      "failed without retry failed",
+			candidate: resumeCandidate{HasState: true, State: failed},
+			want:      "--retry-failed",
+		},
+		{
+			name:      "failed while backing off",
+			candidate: resumeCandidate{HasState: true, State: failed, RetryFailed: true},
+			want:      "backing off until",
+		},
+		{
+			name:      "failed after backoff",
+			candidate: resumeCandidate{HasState: true, State: state.ItemState{Status: execpkg.StatusFailed, Attempts: 2, LastUpdated: now.Add(-time.Hour), MaxAttempts: 3, RetryBackoff: time.Minute}, RetryFailed: true},
+		},
+		{
+			name:      "failed without attempts left",
+			candidate: resumeCandidate{HasState: true, State: state.ItemState{Status: execpkg.StatusFailed, Attempts: 3, MaxAttempts: 3}, RetryFailed: true},
+			want:      "failed after 3 of 3 attempts",
+		},
+		{
+			name:      "retry item ignores the policy",
+			candidate: resumeCandidate{HasState: true, State: state.ItemState{Status: execpkg.StatusFailed, Attempts: 3, MaxAttempts: 3}, Force: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.candidate.held(now)
+			if tt.want == "" && got != "" {
+				t.Fatalf("held() = %q, want the item to run", got)
+			}
+			if !strings.Contains(got, tt.want) {
+				t.Fatalf("held() = %q, want it to contain %q", got, tt.want)
+			}
+		})
+	}
+
+	candidates, err := selectResumeItems([]planner.WorkItem{{Repo: "example/a"}}, []state.ItemState{{Repo: "example/a", Status: execpkg.StatusFailed}}, resumeSelection{FailedOnly: true})
+	if err != nil || len(candidates) != 1 || !candidates[0].RetryFailed {
+		t.Fatalf("expected --failed-only to retry failed items, got %+v, %v", candidates, err)
+	}
+}
+
+func TestResumeCandidatePhase(t *testing.T) {
+	pushed := []state.Phase{state.PhaseUpdated, state.PhaseTested, state.PhasePushed}
+
+	tests := []struct {
+		name      string
+		candidate resumeCandidate
+		want      state.Phase
+	}{
+		{
+			name:      "pull request failed",
+			candidate: resumeCandidate{HasState: true, State: state.ItemState{Status: execpkg.StatusCompleted, Phases: pushed}},
+			want:      state.PhasePRCreated,
+		},
+		{
+			name: "notification failed",
+			candidate: resumeCandidate{HasState: true, State: state.ItemState{
+				Status: execpkg.StatusManualReview,
+				Phases: append(append([]state.Phase{}, pushed...), state.PhasePRCreated),
+			}},
+			want: state.PhaseNotified,
+		},
+		{
+			name:      "tests failed",
+			candidate: resumeCandidate{HasState: true, State: state.ItemState{Status: execpkg.StatusFailed, Phases: []state.Phase{state.PhaseUpdated}}},
+		},
+		{
+			name:      "forced",
+			candidate: resumeCandidate{HasState: true, Force: true, State: state.ItemState{Status: execpkg.StatusCompleted, Phases: pushed}},
+		},
+		{
+			name:      "no state",
+			candidate: resumeCandidate{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.candidate.resumePhase(); got != tt.want {
+				t.Errorf("resumePhase() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcessWorkItem_RecordsPhases(t *testing.T) {
+	item := planner.WorkItem{Repo: "example/a", BranchName: "update"}
+	bk := &mockBroker{
+		notifyFunc: func(ctx context.Context, item planner.WorkItem, result *execpkg.Result) (*broker.NotificationResult, error) {
+			return nil, errors.New("slack unavailable")
+		},
+	}
+
+	itemState, err := processWorkItem(context.Background(), executionDeps{}, t.TempDir(), item, &mockExecutor{}, bk, &mockLogger{}, 0, nil, itemHistory{})
+	if err == nil {
+		t.Fatal("expected notification error")
+	}
+
+	want := []state.Phase{state.PhaseUpdated, state.PhaseTested, state.PhasePushed, state.PhasePRCreated}
+	if !reflect.DeepEqual(itemState.Phases, want) {
+		t.Fatalf("Phases = %v, want %v", itemState.Phases, want)
+	}
+	if itemState.PRURL == "" {
+		t.Fatal("expected PR URL to be recorded")
+	}
+}
+
+func TestProcessWorkItem_ExecutorFailurePhases(t *testing.T) {
+	item := planner.WorkItem{Repo: "example/a", BranchName: "update"}
+	exec := &mockExecutor{
+		applyFunc: func(ctx context.Context, input execpkg.WorkItemContext) (*execpkg.Result, error) {
+			input.Progress(execpkg.PhaseTidy)
+			input.Progress(execpkg.PhaseTests)
+			input.Progress(execpkg.PhaseExtraCommands)
+			return &execpkg.Result{Status: execpkg.StatusFailed, Reason: "tests failed"}, errors.New("tests failed")
+		},
+	}
+	bk := &mockBroker{
+		ensurePRFunc: func(ctx context.Context, item planner.WorkItem, result *execpkg.Result) (*broker.PullRequest, error) {
+			t.Fatal("EnsurePR should not run for a failed item")
+			return nil, nil
+		},
+	}
+
+	itemState, _ := processWorkItem(context.Background(), executionDeps{}, t.TempDir(), item, exec, bk, &mockLogger{}, 0, nil, itemHistory{})
+	want := []state.Phase{state.PhaseUpdated, state.PhaseNotified}
+	if !reflect.DeepEqual(itemState.Phases, want) {
+		t.Fatalf("Phases = %v, want %v", itemState.Phases, want)
+	}
+}
+
+func TestProcessWorkItem_ResumesFromFailedPhase(t *testing.T) {
+	item := planner.WorkItem{
+		Repo:       "example/a",
+		BranchName: "update",
+		Tests:      []manifest.Command{{Cmd: []string{"go", "test", "./..."}}},
+	}
+	prev := state.ItemState{
+		Repo:       "example/a",
+		Status:     execpkg.StatusCompleted,
+		Reason:     "work item executed successfully; PR creation failed: rate limited",
+		CommitHash: "abc123",
+		CommandLogs: []execpkg.CommandResult{
+			{Command: manifest.Command{Cmd: []string{"go", "test", "./..."}}, Output: "ok"},
+			{Command: manifest.Command{Cmd: []string{"make", "docs"}}, Output: "done"},
+		},
+		Phases: []state.Phase{state.PhaseUpdated, state.PhaseTested, state.PhasePushed},
+	}
+
+	exec := &mockExecutor{
+		applyFunc: func(ctx context.Context, input execpkg.WorkItemContext) (*execpkg.Result, error) {
+			t.Fatal("executor should not run for a pushed item")
+			return nil, nil
+		},
+	}
+	var prResult *execpkg.Result
+	notified := 0
+	bk := &mockBroker{
+		ensurePRFunc: func(ctx context.Context, item planner.WorkItem, result *execpkg.Result) (*broker.PullRequest, error) {
+			prResult = result
+			return &broker.PullRequest{URL: "https://example.com/pr/7"}, nil
+		},
+		notifyFunc: func(ctx context.Context, item planner.WorkItem, result *execpkg.Result) (*broker.NotificationResult, error) {
+			notified++
+			return nil, nil
+		},
+	}
+
+	itemState, err := processWorkItem(context.Background(), executionDeps{}, t.TempDir(), item, exec, bk, &mockLogger{}, 0, nil, itemHistory{resume: &prev})
+	if err != nil {
+		t.Fatalf("processWorkItem() error = %v", err)
+	}
+	if prResult == nil || prResult.CommitHash != "abc123" || prResult.Reason != "work item executed successfully" {
+		t.Fatalf("unexpected rebuilt result: %+v", prResult)
+	}
+	if len(prResult.TestResults) != 1 || len(prResult.ExtraResults) != 1 {
+		t.Fatalf("expected logs split into 1 test and 1 extra command, got %d and %d", len(prResult.TestResults), len(prResult.ExtraResults))
+	}
+	if notified != 1 {
+		t.Fatalf("expected one notification, got %d", notified)
+	}
+	if itemState.PRURL != "https://example.com/pr/7" || itemState.NextPhase() != "" {
+		t.Fatalf("unexpected state: PR %q phases %v", itemState.PRURL, itemState.Phases)
+	}
+	if itemState.Reason != "work item executed successfully" || itemState.CommitHash != "abc123" {
+		t.Fatalf("unexpected state: reason %q commit %q", itemState.Reason, itemState.CommitHash)
+	}
+
+	// Only the notification is retried once the pull request exists
+	prev = itemState
+	prev.Phases = prev.Phases[:len(prev.Phases)-1]
+	bk.ensurePRFunc = func(ctx context.Context, item planner.WorkItem, result *execpkg.Result) (*broker.PullRequest, error) {
+		t.Fatal("EnsurePR should not run again")
+		return nil, nil
+	}
+	if _, err := processWorkItem(context.Background(), executionDeps{}, t.TempDir(), item, exec, bk, &mockLogger{}, 0, nil, itemHistory{resume: &prev}); err != nil {
+		t.Fatalf("processWorkItem() error = %v", err)
+	}
+	if notified != 2 {
+		t.Fatalf("expected notification retry, got %d", notified)
+	}
+}
@@ -2,12 +2,17 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/goliatone/cascade/internal/broker"
 	execpkg "github.com/goliatone/cascade/internal/executor"
 	"github.com/goliatone/cascade/internal/state"
+	"github.com/goliatone/cascade/pkg/di"
 	"github.com/spf13/cobra"
 )
 
@@ -16,8 +21,13 @@ func newRevertCommand() *cobra.Command {
 	return &cobra.Command{
 		Use:   "revert [state-id]",
 		Short: "Revert changes from a cascade operation",
-		Long: `Revert undoes changes made by a cascade operation,
-closing pull requests and cleaning up branches as needed.`,
+		Long: `Revert undoes changes made by a cascade operation.
+
+Open pull requests are closed with a comment and their branches deleted.
+Merged pull requests are undone with a revert commit on a new branch,
+which restores each dependent's go.mod, and a pull request for it is opened.
+The outcome for each repository is recorded in state, so running revert
+again only retries the repositories that failed.`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			stateID := ""
@@ -53,7 +63,8 @@ func runRevert(stateID string) error {
 	summary, err := container.State().LoadSummary(module, version)
 	if err != nil {
 		if err == state.ErrNotFound {
-			return fmt.Errorf("no saved state found for %s@%s", module, version)
+			return newStateError(fmt.Sprintf("no saved state found for %s@%s", module, version), nil).
+				WithHint("check the module and version, or run `cascade release` to create state")
 		}
 		return newStateError("failed to load summary", err)
 	}
@@ -71,6 +82,10 @@ func runRevert(stateID string) error {
 	if cfg.Executor.DryRun {
 		fmt.Printf("DRY RUN: Would revert cascade for %s@%s\n", module, version)
 		for _, item := range itemStates {
+			if item.Revert.Done() {
+				fmt.Printf("  - %s (already %s)\n", item.Repo, item.Revert.Action)
+				continue
+			}
 			fmt.Printf("  - %s (branch: %s", item.Repo, item.Branch)
 			if item.PRURL != "" {
 				fmt.Printf(", PR: %s", item.PRURL)
@@ -84,50 +99,244 @@ func runRevert(stateID string) error {
 		return newExecutionError("failed to prepare workspace", err)
 	}
 
-	deps := newExecutionDeps()
-	stateManager := container.State()
-	tracker := newStateTracker(module, version, summary, stateManager, logger, itemStates)
-	brokerSvc := container.Broker()
+	deps := newExecutionDeps(cfg)
+	tracker := newStateTracker(module, version, summary, container.State(), logger, itemStates)
+	reverter := itemReverter{
+		git:       deps.git,
+		gitRunner: deps.gitRunner,
+		broker:    container.Broker(),
+		workspace: cfg.Workspace.Path,
+		module:    module,
+		version:   version,
+		logger:    logger,
+		out:       os.Stdout,
+	}
 
 	fmt.Printf("Reverting cascade for %s@%s\n", module, version)
+	failed := 0
 	for _, item := range itemStates {
-		fmt.Printf("  - Reverting %s\n", item.Repo)
-		repoPath, err := deps.git.EnsureClone(ctx, item.Repo, cfg.Workspace.Path)
-		if err != nil {
-			logger.Warn("Failed to clone repository for revert", "repo", item.Repo, "error", err)
+		if item.Revert.Done() {
+			fmt.Printf("  - %s already %s\n", item.Repo, item.Revert.Action)
 			continue
 		}
 
-		if item.Branch != "" {
-			if err := runGitCommand(ctx, deps.gitRunner, repoPath, "push", "origin", "--delete", item.Branch); err != nil {
-				logger.Warn("Failed to delete remote branch", "repo", item.Repo, "branch", item.Branch, "error", err)
-			} else {
-				fmt.Printf("    ✓ Deleted remote branch %s\n", item.Branch)
-			}
-			if err := runGitCommand(ctx, deps.gitRunner, repoPath, "branch", "-D", item.Branch); err != nil {
-				logger.Warn("Failed to delete local branch", "repo", item.Repo, "branch", item.Branch, "error", err)
-			}
-		}
-
-		if item.PRURL != "" {
-			if number, err := extractPRNumber(item.PRURL); err == nil {
-				pr := &broker.PullRequest{Repo: item.Repo, Number: number, URL: item.PRURL}
-				message := "Cascade has reverted this update. Please close this pull request if appropriate."
-				if commentErr := brokerSvc.Comment(ctx, pr, message); commentErr != nil {
-					logger.Warn("Failed to leave revert comment", "repo", item.Repo, "pr", item.PRURL, "error", commentErr)
-				}
-			} else {
-				logger.Warn("Unable to parse PR number from URL", "repo", item.Repo, "pr", item.PRURL, "error", err)
-			}
+		fmt.Printf("  - Reverting %s\n", item.Repo)
+		item.Revert = reverter.revert(ctx, item)
+		if item.Revert.Action == state.RevertFailed {
+			failed++
+			fmt.Printf("    %s %s\n", style.mark(markFailed), item.Revert.Error)
+		} else {
+			item.Status = execpkg.StatusFailed
+			item.Reason = appendReason(item.Reason, "reverted via cascade CLI")
 		}
-
-		item.Status = execpkg.StatusFailed
-		item.Reason = appendReason(item.Reason, "reverted via cascade CLI")
 		item.LastUpdated = time.Now()
 		tracker.record(item)
 	}
 
 	tracker.finalize()
+	if failed > 0 {
+		return newExecutionError(fmt.Sprintf("failed to revert %d of %d repositories for %s@%s", failed, len(itemStates), module, version), nil).
+			WithHint("fix the reported problems and run `cascade revert` again; completed repositories are skipped")
+	}
 	fmt.Printf("Revert completed for %s@%s\n", module, version)
 	return nil
 }
+
+// itemReverter rolls back the work a release recorded for each item.
+type itemReverter struct {
+	git       execpkg.GitOperations
+	gitRunner execpkg.GitCommandRunner
+	broker    broker.Broker
+	workspace string
+	module    string
+	version   string
+	logger    di.Logger
+	out       io.Writer
+}
+
+// revert closes the item's pull request, or reverts it when it was already
+// merged, and reports what it did.
+func (r itemReverter) revert(ctx context.Context, item state.ItemState) *state.RevertOutcome {
+	ctx = withItemProvider(ctx, item.Provider)
+	outcome := &state.RevertOutcome{RevertedAt: time.Now()}
+	fail := func(err error) *state.RevertOutcome { return revertFailed(outcome, err) }
+
+	if item.PRURL == "" {
+		if item.Branch == "" || (len(item.Phases) > 0 && !item.Completed(state.PhasePushed)) {
+			outcome.Action = state.RevertSkipped
+			return outcome
+		}
+		deleted, err := r.deleteBranch(ctx, item.Repo, item.Branch)
+		if err != nil {
+			return fail(err)
+		}
+		outcome.Action = state.RevertSkipped
+		if deleted {
+			outcome.Action = state.RevertBranchDeleted
+		}
+		return outcome
+	}
+
+	number, err := extractPRNumber(item.PRURL)
+	if err != nil {
+		return fail(err)
+	}
+	pr := &broker.PullRequest{Repo: item.Repo, Number: number, URL: item.PRURL}
+
+	status, err := r.pullRequestStatus(ctx, pr)
+	if err != nil {
+		return fail(err)
+	}
+
+	if status.Merged {
+		return r.revertMerged(ctx, item, pr, status, outcome)
+	}
+
+	if status.State != broker.PullRequestClosed {
+		message := fmt.Sprintf("Cascade is rolling back the %s %s release, so this update is no longer needed.", r.module, r.version)
+		if err := r.closePR(ctx, pr, message); err != nil {
+			return fail(err)
+		}
+		fmt.Fprintf(r.out, "    %s Closed PR %s\n", style.mark(markOK), pr.URL)
+	}
+
+	if branch := headBranch(item, status); branch != "" {
+		// The pull request is closed either way; a leftover branch is only noise
+		if _, err := r.deleteBranch(ctx, item.Repo, branch); err != nil {
+			r.logger.Warn("Failed to delete branch", "repo", item.Repo, "branch", branch, "error", err)
+		}
+	}
+	outcome.Action = state.RevertClosed
+	return outcome
+}
+
+// revertMerged reverts the commit that landed a merged pull request on a new
+// branch and opens a pull request for it.
+func (r itemReverter) revertMerged(ctx context.Context, item state.ItemState, pr *broker.PullRequest, status *broker.PullRequestStatus, outcome *state.RevertOutcome) *state.RevertOutcome {
+	fail := func(err error) *state.RevertOutcome { return revertFailed(outcome, err) }
+
+	reverter, ok := r.broker.(broker.Reverter)
+	if !ok {
+		return fail(fmt.Errorf("PR %s was merged and the configured provider cannot open revert pull requests", pr.URL))
+	}
+	gitReverter, ok := r.git.(execpkg.RevertOperations)
+	if !ok {
+		return fail(fmt.Errorf("PR %s was merged and the configured git operations cannot revert commits", pr.URL))
+	}
+	if status.MergeCommitSHA == "" {
+		return fail(fmt.Errorf("PR %s was merged but its merge commit is unknown", pr.URL))
+	}
+
+	repoPath, err := r.git.EnsureClone(ctx, item.Repo, r.workspace)
+	if err != nil {
+		return fail(err)
+	}
+
+	branch := revertBranchName(headBranch(item, status), pr.Number)
+	worktree, err := r.git.EnsureWorktree(ctx, repoPath, branch, status.BaseBranch)
+	if err != nil {
+		return fail(err)
+	}
+	commit, err := gitReverter.RevertCommit(ctx, worktree, status.MergeCommitSHA)
+	if err != nil {
+		return fail(err)
+	}
+	if err := r.git.Push(ctx, worktree, branch); err != nil {
+		return fail(err)
+	}
+	outcome.CommitHash = commit
+
+	revertPR, err := reverter.OpenPR(ctx, broker.PRInput{
+		Repo:       item.Repo,
+		BaseBranch: status.BaseBranch,
+		HeadBranch: branch,
+		Title:      fmt.Sprintf("Revert update of %s to %s", r.module, r.version),
+		Body:       fmt.Sprintf("Reverts #%d, which updated %s to %s.\n\nOpened by `cascade revert` to roll back the release.", pr.Number, r.module, r.version),
+	})
+	if err != nil {
+		return fail(err)
+	}
+	if revertPR != nil {
+		outcome.PRURL = revertPR.URL
+		fmt.Fprintf(r.out, "    %s Opened revert PR %s\n", style.mark(markOK), revertPR.URL)
+		if err := r.broker.Comment(ctx, pr, fmt.Sprintf("Cascade rolled back the %s %s release; this update is reverted by %s.", r.module, r.version, revertPR.URL)); err != nil {
+			r.logger.Warn("Failed to leave revert comment", "repo", item.Repo, "pr", pr.URL, "error", err)
+		}
+	}
+
+	outcome.Action = state.RevertReverted
+	return outcome
+}
+
+// revertFailed marks outcome as failed with err.
+func revertFailed(outcome *state.RevertOutcome, err error) *state.RevertOutcome {
+	outcome.Action = state.RevertFailed
+	outcome.Error = err.Error()
+	return outcome
+}
+
+// pullRequestStatus looks up pr. Brokers that cannot look pull requests up
+// are assumed to have left it open, which closing will confirm.
+func (r itemReverter) pullRequestStatus(ctx context.Context, pr *broker.PullRequest) (*broker.PullRequestStatus, error) {
+	open := &broker.PullRequestStatus{State: broker.PullRequestOpen}
+	reverter, ok := r.broker.(broker.Reverter)
+	if !ok {
+		return open, nil
+	}
+	status, err := reverter.PullRequestStatus(ctx, pr)
+	var notImplemented *broker.NotImplementedError
+	if errors.As(err, &notImplemented) {
+		return open, nil
+	}
+	return status, err
+}
+
+// closePR comments on pr and closes it.
+func (r itemReverter) closePR(ctx context.Context, pr *broker.PullRequest, message string) error {
+	if reverter, ok := r.broker.(broker.Reverter); ok {
+		return reverter.ClosePRWithComment(ctx, pr, message)
+	}
+	if err := r.broker.Comment(ctx, pr, message); err != nil {
+		r.logger.Warn("Failed to leave revert comment", "repo", pr.Repo, "pr", pr.URL, "error", err)
+	}
+	return r.broker.ClosePR(ctx, pr)
+}
+
+// deleteBranch removes branch from origin and the local clone. It reports
+// false when the remote branch was already gone.
+func (r itemReverter) deleteBranch(ctx context.Context, repo, branch string) (bool, error) {
+	repoPath, err := r.git.EnsureClone(ctx, repo, r.workspace)
+	if err != nil {
+		return false, err
+	}
+
+	deleted := true
+	if err := runGitCommand(ctx, r.gitRunner, repoPath, "push", "origin", "--delete", branch); err != nil {
+		if !strings.Contains(err.Error(), "remote ref does not exist") {
+			return false, fmt.Errorf("failed to delete remote branch %s: %w", branch, err)
+		}
+		deleted = false
+	} else {
+		fmt.Fprintf(r.out, "    %s Deleted remote branch %s\n", style.mark(markOK), branch)
+	}
+	if err := runGitCommand(ctx, r.gitRunner, repoPath, "branch", "-D", branch); err != nil {
+		r.logger.Debug("Local branch not deleted", "repo", repo, "branch", branch, "error", err)
+	}
+	return deleted, nil
+}
+
+// headBranch returns the branch the item's pull request was opened from.
+func headBranch(item state.ItemState, status *broker.PullRequestStatus) string {
+	if item.Branch != "" {
+		return item.Branch
+	}
+	return status.HeadBranch
+}
+
+// revertBranchName names the branch carrying the revert of a merged pull request.
+func revertBranchName(branch string, number int) string {
+	if branch == "" {
+		return fmt.Sprintf("cascade/revert-%d", number)
+	}
+	return branch + "-revert"
+}
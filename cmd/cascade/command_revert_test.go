@@ -0,0 +1,226 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/goliatone/cascade/internal/broker"
+	"github.com/goliatone/cascade/internal/state"
+)
+
+// revertGit records the git operations of a revert.
+type revertGit struct {
+	worktrees []string
+	reverted  []string
+	pushed    []string
+	revertErr error
+}
+
+func (g *revertGit) EnsureClone(ctx context.Context, repo, workspace string) (string, error) {
+	return filepath.Join(workspace, repo), nil
+}
+
+func (g *revertGit) EnsureWorktree(ctx context.Context, repoPath, branch string, base string) (string, error) {
+	g.worktrees = append(g.worktrees, branch+" from "+base)
+	return filepath.Join(repoPath, ".worktrees", branch), nil
+}
+
+func (g *revertGit) Commit(ctx context.Context, repoPath, message string) (string, error) {
+	return "", errors.New("unexpected commit")
+}
+
+func (g *revertGit) Push(ctx context.Context, repoPath, branch string) error {
+	g.pushed = append(g.pushed, branch)
+	return nil
+}
+
+func (g *revertGit) RevertCommit(ctx context.Context, repoPath, commit string) (string, error) {
+	if g.revertErr != nil {
+		return "", g.revertErr
+	}
+	g.reverted = append(g.reverted, commit)
+	return "rev456", nil
+}
+
+// revertRunner records raw git commands and fails those listed in errs.
+type revertRunner struct {
+	calls []string
+	errs  map[string]error
+}
+
+func (r *revertRunner) Run(ctx context.Context, dir string, args ...string) (string, error) {
+	call := strings.Join(args, " ")
+	r.calls = append(r.calls, call)
+	return "", r.errs[call]
+}
+
+// revertBroker adds the Reverter capability to mockBroker.
+type revertBroker struct {
+	*mockBroker
+	status   *broker.PullRequestStatus
+	closed   []string
+	opened   []broker.PRInput
+	comments []string
+}
+
+func (b *revertBroker) PullRequestStatus(ctx context.Context, pr *broker.PullRequest) (*broker.PullRequestStatus, error) {
+	return b.status, nil
+}
+
+func (b *revertBroker) ClosePRWithComment(ctx context.Context, pr *broker.PullRequest, body string) error {
+	b.closed = append(b.closed, pr.URL)
+	return nil
+}
+
+func (b *revertBroker) OpenPR(ctx context.Context, input broker.PRInput) (*broker.PullRequest, error) {
+	b.opened = append(b.opened, input)
+	return &broker.PullRequest{Repo: input.Repo, Number: 8, URL: "https://github.com/example/a/pull/8"}, nil
+}
+
+func (b *revertBroker) Comment(ctx context.Context, pr *broker.PullRequest, body string) error {
+	b.comments = append(b.comments, body)
+	return nil
+}
+
+func newTestReverter(git *revertGit, runner *revertRunner, brokerSvc broker.Broker) itemReverter {
+	return itemReverter{
+		git:       git,
+		gitRunner: runner,
+		broker:    brokerSvc,
+		workspace: "/workspace",
+		module:    "example.com/lib",
+		version:   "v1.2.0",
+		logger:    &mockLogger{},
+		out:       io.Discard,
+	}
+}
+
+func TestItemReverterClosesOpenPR(t *testing.T) {
+	git := &revertGit{}
+	runner := &revertRunner{}
+	brokerSvc := &revertBroker{mockBroker: &mockBroker{}, status: &broker.PullRequestStatus{State: broker.PullRequestOpen}}
+
+	item := state.ItemState{Repo: "example/a", Branch: "cascade/update", PRURL: "https://github.com/example/a/pull/7"}
+	outcome := newTestReverter(git, runner, brokerSvc).revert(context.Background(), item)
+
+	if outcome.Action != state.RevertClosed {
+		t.Fatalf("outcome = %+v, want closed", outcome)
+	}
+	if len(brokerSvc.closed) != 1 || brokerSvc.closed[0] != item.PRURL {
+		t.Errorf("closed PRs = %v, want %s", brokerSvc.closed, item.PRURL)
+	}
+	if strings.Join(runner.calls, ",") != "push origin --delete cascade/update,branch -D cascade/update" {
+		t.Errorf("git calls = %v, want remote and local branch deletion", runner.calls)
+	}
+	if len(git.reverted) != 0 {
+		t.Errorf("open PR should not be reverted, got %v", git.reverted)
+	}
+}
+
+func TestItemReverterRevertsMergedPR(t *testing.T) {
+	git := &revertGit{}
+	runner := &revertRunner{}
+	brokerSvc := &revertBroker{mockBroker: &mockBroker{}, status: &broker.PullRequestStatus{
+		State:          broker.PullRequestClosed,
+		Merged:         true,
+		MergeCommitSHA: "abc123",
+		BaseBranch:     "main",
+	}}
+
+	item := state.ItemState{Repo: "example/a", Branch: "cascade/update", PRURL: "https://github.com/example/a/pull/7"}
+	outcome := newTestReverter(git, runner, brokerSvc).revert(context.Background(), item)
+
+	if outcome.Action != state.RevertReverted || outcome.PRURL != "https://github.com/example/a/pull/8" || outcome.CommitHash != "rev456" {
+		t.Fatalf("outcome = %+v, want reverted through PR 8", outcome)
+	}
+	if strings.Join(git.worktrees, ",") != "cascade/update-revert from main" {
+		t.Errorf("worktrees = %v", git.worktrees)
+	}
+	if strings.Join(git.reverted, ",") != "abc123" || strings.Join(git.pushed, ",") != "cascade/update-revert" {
+		t.Errorf("reverted %v, pushed %v", git.reverted, git.pushed)
+	}
+	if len(brokerSvc.opened) != 1 {
+		t.Fatalf("opened PRs = %v, want one", brokerSvc.opened)
+	}
+	opened := brokerSvc.opened[0]
+	if opened.BaseBranch != "main" || opened.HeadBranch != "cascade/update-revert" || !strings.Contains(opened.Body, "Reverts #7") {
+		t.Errorf("revert PR input = %+v", opened)
+	}
+	if len(brokerSvc.comments) != 1 || !strings.Contains(brokerSvc.comments[0], outcome.PRURL) {
+		t.Errorf("comments = %v, want a link to the revert PR", brokerSvc.comments)
+	}
+	if len(brokerSvc.closed) != 0 || len(runner.calls) != 0 {
+		t.Errorf("merged PR should not be closed or have branches deleted: closed %v, git %v", brokerSvc.closed, runner.calls)
+	}
+}
+
+func TestItemReverterReportsFailures(t *testing.T) {
+	merged := &broker.PullRequestStatus{State: broker.PullRequestClosed, Merged: true, MergeCommitSHA: "abc123", BaseBranch: "main"}
+	item := state.ItemState{Repo: "example/a", Branch: "cascade/update", PRURL: "https://github.com/example/a/pull/7"}
+
+	// A conflicting revert opens no pull request
+	git := &revertGit{revertErr: errors.New("conflict in go.mod")}
+	brokerSvc := &revertBroker{mockBroker: &mockBroker{}, status: merged}
+	outcome := newTestReverter(git, &revertRunner{}, brokerSvc).revert(context.Background(), item)
+	if outcome.Action != state.RevertFailed || !strings.Contains(outcome.Error, "conflict in go.mod") {
+		t.Errorf("outcome = %+v, want failure", outcome)
+	}
+	if len(brokerSvc.opened) != 0 || len(git.pushed) != 0 {
+		t.Errorf("failed revert pushed %v and opened %v", git.pushed, brokerSvc.opened)
+	}
+
+	// Brokers without the Reverter capability close the PR, assuming it is still open
+	outcome = newTestReverter(&revertGit{}, &revertRunner{}, &mockBroker{}).revert(context.Background(), item)
+	if outcome.Action != state.RevertClosed {
+		t.Errorf("outcome = %+v, want the PR closed assuming it is open", outcome)
+	}
+	if !outcome.Done() {
+		t.Error("expected closed outcome to be done")
+	}
+
+	brokerSvc = &revertBroker{mockBroker: &mockBroker{}, status: &broker.PullRequestStatus{State: broker.PullRequestOpen}}
+	runner := &revertRunner{errs: map[string]error{"push origin --delete cascade/update": errors.New("permission denied")}}
+	outcome = newTestReverter(&revertGit{}, runner, brokerSvc).revert(context.Background(), state.ItemState{Repo: "example/a", Branch: "cascade/update"})
+	if outcome.Action != state.RevertFailed || !strings.Contains(outcome.Error, "permission denied") {
+		t.Errorf("outcome = %+v, want branch deletion failure", outcome)
+	}
+}
+
+func TestItemReverterWithoutPR(t *testing.T) {
+	tests := []struct {
+		name string
+		item state.ItemState
+		errs map[string]error
+		want state.RevertAction
+	}{
+		{
+			name: "pushed branch",
+			item: state.ItemState{Repo: "example/a", Branch: "cascade/update", Phases: []state.Phase{state.PhaseUpdated, state.PhaseTested, state.PhasePushed}},
+			want: state.RevertBranchDeleted,
+		},
+		{
+			name: "never pushed",
+			item: state.ItemState{Repo: "example/a", Branch: "cascade/update", Phases: []state.Phase{state.PhaseUpdated}},
+			want: state.RevertSkipped,
+		},
+		{
+			name: "branch already gone",
+			item: state.ItemState{Repo: "example/a", Branch: "cascade/update"},
+			errs: map[string]error{"push origin --delete cascade/update": errors.New("error: unable to delete 'cascade/update': remote ref does not exist")},
+			want: state.RevertSkipped,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reverter := newTestReverter(&revertGit{}, &revertRunner{errs: tt.errs}, &mockBroker{})
+			if outcome := reverter.revert(context.Background(), tt.item); outcome.Action != tt.want {
+				t.Errorf("outcome = %+v, want %s", outcome, tt.want)
+			}
+		})
+	}
+}
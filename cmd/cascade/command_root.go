@@ -45,6 +45,7 @@ Examples:
 		SilenceUsage:  true,
 		SilenceErrors: true,
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			resolveOutputStyle(cmd)
 			return initializeContainer(cmd)
 		},
 		PersistentPostRun: func(cmd *cobra.Command, args []string) {
@@ -68,16 +69,32 @@ Examples:
 
 	// Add configuration flags
 	config.AddFlags(cmd)
+	addOutputFlags(cmd)
 
 	// Add subcommands
 	cmd.AddCommand(
 		newManifestCommand(),
+		newOverridesCommand(),
 		newPlanCommand(),
 		newReleaseCommand(),
 		newResumeCommand(),
 		newRevertCommand(),
+		newWaitCommand(),
+		newDigestCommand(),
+		newStateCommand(),
+		newStatusCommand(),
+		newDoctorCommand(),
+		newExplainErrorCommand(),
+		newServeCommand(),
+		newTryCommand(),
+		newCheckCommand(),
+		newSmokeCommand(),
 		newWorkflowCommand(),
+		newTemplatesCommand(),
 		newVersionCommand(),
+		newSelfUpdateCommand(),
+		newReleaseNotesCommand(),
+		newBuildInfoCommand(),
 	)
 
 	return cmd
@@ -94,9 +111,10 @@ func initializeContainer(cmd *cobra.Command) error {
 	}
 
 	builder := config.NewBuilder().
-		FromFile(configFile). // Use explicit config file or auto-discover
-		FromEnv().            // Load from environment
-		FromFlags(cmd)        // Load from command flags (highest precedence)
+		Profile(config.SelectedProfile(cmd)). // Profile from --profile or CASCADE_PROFILE
+		FromFile(configFile).                 // Use explicit config file or auto-discover
+		FromEnv().                            // Load from environment
+		FromFlags(cmd)                        // Load from command flags (highest precedence)
 
 	var err error
 	cfg, err = builder.Build()
@@ -104,6 +122,14 @@ func initializeContainer(cmd *cobra.Command) error {
 		return newConfigError("failed to build configuration", err)
 	}
 
+	if err := applyGoPrivate(cfg.Integration.GoProxy.Private); err != nil {
+		return newConfigError("failed to apply integration.goproxy.private", err)
+	}
+
+	if err := applyGitSSH(cfg.Integration.Git.SSH); err != nil {
+		return newConfigError("failed to apply integration.git.ssh", err)
+	}
+
 	// Determine if this is a production command that requires credentials
 	containerOptions := []di.Option{di.WithConfig(cfg)}
 	if isProductionCommand(cmd) {
@@ -121,6 +147,10 @@ func initializeContainer(cmd *cobra.Command) error {
 		return newConfigError("failed to initialize dependencies", err)
 	}
 
+	if err := startTelemetry(cmd, cfg, container.Logger()); err != nil {
+		return err
+	}
+
 	// Log container initialization metrics if logger is available
 	if logger := container.Logger(); logger != nil {
 		duration := time.Since(start)
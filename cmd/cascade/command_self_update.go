@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/goliatone/cascade/pkg/version"
+	"github.com/spf13/cobra"
+)
+
+// newSelfUpdateCommand creates the self-update subcommand
+func newSelfUpdateCommand() *cobra.Command {
+	var (
+		targetTag string
+		force     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "self-update",
+		Short: "Replace the cascade binary with the latest release",
+		Long: `Self-update downloads a cascade release for the current platform from
+GitHub Releases, verifies the published checksums.txt against its signature
+with the release key built into cascade, verifies the archive against
+checksums.txt, and atomically replaces the running binary.
+
+Examples:
+  cascade self-update                 # Install the latest release
+  cascade self-update --to=v0.4.0     # Install a specific release
+  cascade self-update --dry-run       # Show what would be installed`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			executable, err := os.Executable()
+			if err != nil {
+				return newFileError("failed to locate the cascade executable", err)
+			}
+
+			dryRun := cfg != nil && cfg.Executor.DryRun
+			return runSelfUpdate(cmd.Context(), cmd.OutOrStdout(), version.NewUpdateChecker(), selfUpdateRequest{
+				Executable: executable,
+				Tag:        targetTag,
+				Force:      force,
+				DryRun:     dryRun,
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&targetTag, "to", "", "Release tag to install (default: latest)")
+	cmd.Flags().BoolVar(&force, "force", false, "Install even when the release is not newer than the running version")
+
+	return cmd
+}
+
+type selfUpdateRequest struct {
+	Executable string
+	Tag        string
+	Force      bool
+	DryRun     bool
+}
+
+func runSelfUpdate(ctx context.Context, w io.Writer, checker *version.UpdateChecker, req selfUpdateRequest) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var (
+		release *version.Release
+		err     error
+	)
+	if tag := strings.TrimSpace(req.Tag); tag != "" {
+		release, err = checker.ReleaseByTag(ctx, tag)
+	} else {
+		release, err = checker.Latest(ctx)
+	}
+	if err != nil {
+		return newNetworkError("failed to fetch release information", err).
+			WithHint("set GITHUB_TOKEN if you are being rate limited")
+	}
+
+	if !req.Force && req.Tag == "" && !version.IsNewer(release.Tag, version.Tag) {
+		fmt.Fprintf(w, "cascade %s is up to date (latest release %s)\n", version.Tag, release.Tag)
+		return nil
+	}
+
+	if req.DryRun {
+		fmt.Fprintf(w, "DRY RUN: would install cascade %s to %s\n", release.Tag, req.Executable)
+		return nil
+	}
+
+	binary, err := checker.DownloadBinary(ctx, release)
+	if err != nil {
+		if errors.Is(err, version.ErrNoSigningKey) {
+			return newConfigError("this build cannot verify releases", err).
+				WithHint("install official release builds, which carry the release signing key, or update manually")
+		}
+		if errors.Is(err, version.ErrChecksumMismatch) || errors.Is(err, version.ErrSignatureInvalid) {
+			return newValidationError("downloaded release failed verification", err)
+		}
+		return newNetworkError("failed to download release", err)
+	}
+
+	if err := version.InstallBinary(req.Executable, binary); err != nil {
+		return newFileError("failed to install release", err).
+			WithHint("re-run with permissions to write %s", req.Executable)
+	}
+
+	fmt.Fprintf(w, "Installed cascade %s to %s\n", release.Tag, req.Executable)
+	return nil
+}
@@ -0,0 +1,358 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/goliatone/cascade/internal/broker"
+	"github.com/goliatone/cascade/internal/manifest"
+	"github.com/goliatone/cascade/internal/server"
+	"github.com/goliatone/cascade/internal/state"
+	"github.com/goliatone/cascade/pkg/di"
+	"github.com/spf13/cobra"
+)
+
+// newServeCommand creates the serve command
+func newServeCommand() *cobra.Command {
+	var (
+		addr               string
+		path               string
+		manifestPath       string
+		queueSize          int
+		includePrereleases bool
+		waitForLock        time.Duration
+		feed               bool
+		feedSize           int
+		staleAfter         time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Run a webhook server that releases cascades when tags are pushed",
+		Long: `Serve runs an HTTP server that accepts GitHub release, push, and create
+webhooks. Deliveries are verified with the webhook secret, the pushed tag is
+mapped to the manifest modules it releases ("api/v1.2.0" releases the module in
+the repository's api/ directory), and a cascade is queued for each of them.
+Queued cascades run one at a time, exactly like cascade release.
+
+The secret is read from integration.github.webhook_secret or
+CASCADE_GITHUB_WEBHOOK_SECRET and must match the secret of the GitHub webhook.
+The manifest is reloaded for every delivery.
+
+With --feed, completed cascades are also published as an Atom feed at
+/feed.atom and a JSON Feed at /feed.json: the module, version, the outcome of
+each dependent, and the pull request links, read from the state directory.
+The feeds are unauthenticated, so only enable them where the server is not
+reachable by untrusted clients.
+
+While it runs, serve also checks the heartbeats of in-flight work items every
+minute and sends an alert through the Slack or webhook notifier for each item
+whose heartbeat is older than --stale-after, which usually means the process
+running it hung or was killed.
+
+Examples:
+  cascade serve
+  cascade serve --addr=:9000 --path=/hooks/github
+  cascade serve --manifest=deps.yaml --include-prereleases
+  cascade serve --feed --feed-size=100`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			if !cmd.Flags().Changed("stale-after") {
+				staleAfter = defaultStaleAfter(container.Config())
+			}
+			return runServe(ctx, serveOptions{
+				Addr:               addr,
+				Path:               path,
+				ManifestPath:       manifestPath,
+				QueueSize:          queueSize,
+				IncludePrereleases: includePrereleases,
+				WaitForLock:        waitForLock,
+				Feed:               feed,
+				FeedSize:           feedSize,
+				StaleAfter:         staleAfter,
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&addr, "addr", ":8080", "Address the webhook server listens on")
+	cmd.Flags().StringVar(&path, "path", server.DefaultPath, "Path GitHub delivers webhooks to")
+	cmd.Flags().StringVar(&manifestPath, "manifest", "", "Path to dependency manifest file (default: .cascade.yaml)")
+	cmd.Flags().IntVar(&queueSize, "queue-size", server.DefaultQueueSize, "Number of cascades that may wait to run; deliveries beyond it are rejected with 503")
+	cmd.Flags().BoolVar(&includePrereleases, "include-prereleases", false, "Also release pre-release tags (e.g. v1.2.0-rc.1) and GitHub pre-releases")
+	cmd.Flags().DurationVar(&waitForLock, "wait-for-lock", 0, "When another run holds the lock for a module@version, wait up to this long instead of failing")
+	cmd.Flags().BoolVar(&feed, "feed", false, "Publish completed cascades as an Atom feed at /feed.atom and a JSON Feed at /feed.json")
+	cmd.Flags().IntVar(&feedSize, "feed-size", server.DefaultFeedSize, "Number of most recently completed cascades the feeds list")
+	cmd.Flags().DurationVar(&staleAfter, "stale-after", 0, "Alert about in-flight items whose heartbeat is older than this, 0 to disable (default: 3x executor.heartbeat_interval)")
+
+	return cmd
+}
+
+// serveOptions holds the serve command settings.
+type serveOptions struct {
+	Addr               string
+	Path               string
+	ManifestPath       string
+	QueueSize          int
+	IncludePrereleases bool
+	WaitForLock        time.Duration
+
+	// Feed publishes the completed cascades recorded in state, listing the
+	// FeedSize most recent ones.
+	Feed     bool
+	FeedSize int
+
+	// StaleAfter is how old the heartbeat of an in-flight item may get before
+	// serve alerts about it. Zero disables the check.
+	StaleAfter time.Duration
+}
+
+func runServe(ctx context.Context, opts serveOptions) error {
+	cfg := container.Config()
+	logger := container.Logger()
+
+	secret := strings.TrimSpace(cfg.Integration.GitHub.WebhookSecret)
+	if secret == "" {
+		return newValidationError("webhook secret is not configured", nil).
+			WithHint("set CASCADE_GITHUB_WEBHOOK_SECRET or integration.github.webhook_secret to the secret of the GitHub webhook")
+	}
+	if resolvePlanManifestPath(opts.ManifestPath, "", cfg) == "" {
+		return newValidationError("manifest path not provided and no default configured", nil)
+	}
+
+	loadManifest := func() (*manifest.Manifest, error) {
+		return container.Manifest().Load(resolvePlanManifestPath(opts.ManifestPath, "", cfg))
+	}
+	release := func(ctx context.Context, job server.Job) error {
+		return runReleaseContext(ctx, opts.ManifestPath, "", job.Module, job.Version, false, opts.WaitForLock, "")
+	}
+
+	serverOpts := []server.Option{
+		server.WithLogger(logger),
+		server.WithPath(opts.Path),
+		server.WithQueueSize(opts.QueueSize),
+		server.WithPrereleases(opts.IncludePrereleases),
+	}
+	if opts.Feed {
+		if _, ok := container.State().(state.SummaryLister); !ok {
+			return newValidationError("the configured state backend cannot list cascades for the feed", nil).
+				WithHint("enable state persistence with state.enabled, or run without --feed")
+		}
+		serverOpts = append(serverOpts, server.WithFeed(func(ctx context.Context, limit int) ([]server.FeedEntry, error) {
+			return feedEntries(container.State(), cfg.UI.BaseURL, limit)
+		}, opts.FeedSize))
+	}
+
+	srv, err := server.New(secret, loadManifest, release, serverOpts...)
+	if err != nil {
+		return newValidationError("invalid webhook server settings", err)
+	}
+
+	// Cascades can be hours apart, so digests are sent when due rather than
+	// with the next notification
+	if cfg.Integration.Notifications.Digest.Window > 0 {
+		if flusher, ok := container.Broker().(broker.DigestFlusher); ok {
+			go flushDigestsEvery(ctx, flusher, digestFlushInterval, logger)
+		}
+	}
+
+	// Releases run in this process, but also from CI and operators' machines
+	// sharing the state directory, so every cascade in state is checked
+	if opts.StaleAfter > 0 {
+		alerter, canAlert := container.Broker().(broker.Alerter)
+		if _, canList := container.State().(state.SummaryLister); canAlert && canList {
+			go watchHeartbeatsEvery(ctx, container.State(), alerter, opts.StaleAfter, heartbeatCheckInterval, logger)
+		}
+	}
+
+	fmt.Printf("Listening for GitHub webhooks on %s%s\n", opts.Addr, opts.Path)
+	if opts.Feed {
+		fmt.Printf("Publishing completed cascades at %s%s and %s\n", opts.Addr, server.FeedAtomPath, server.FeedJSONPath)
+	}
+	if err := srv.Run(ctx, opts.Addr); err != nil {
+		return newExecutionError("webhook server failed", err)
+	}
+	fmt.Println("Webhook server stopped")
+	return nil
+}
+
+// digestFlushInterval is how often serve sends the digests that are due.
+const digestFlushInterval = time.Minute
+
+// flushDigestsEvery sends the notification digests that are due every
+// interval until ctx is cancelled.
+func flushDigestsEvery(ctx context.Context, flusher broker.DigestFlusher, interval time.Duration, logger di.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if sent, err := flusher.FlushDigests(ctx, false); err != nil {
+				logger.Warn("Failed to send notification digests", "error", err)
+			} else if sent > 0 {
+				logger.Info("Sent notification digests", "notifications", sent)
+			}
+		}
+	}
+}
+
+// heartbeatCheckInterval is how often serve looks for stale heartbeats.
+const heartbeatCheckInterval = time.Minute
+
+// watchHeartbeatsEvery checks the heartbeats recorded in manager every interval
+// until ctx is cancelled, alerting about the items whose heartbeat is older than
+// staleAfter.
+func watchHeartbeatsEvery(ctx context.Context, manager state.Manager, alerter broker.Alerter, staleAfter, interval time.Duration, logger di.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	alerted := map[string]bool{}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			alerted = alertStaleHeartbeats(ctx, manager, alerter, staleAfter, now, alerted, logger)
+		}
+	}
+}
+
+// alertStaleHeartbeats sends an alert for every in-flight item of the cascades in
+// manager whose heartbeat is older than staleAfter at now. An item is alerted
+// about once per stall: alerted holds the stalls already reported, and the
+// stalls still current are returned for the next check, so an item is alerted
+// about again only after its heartbeat advanced and then stopped once more.
+func alertStaleHeartbeats(ctx context.Context, manager state.Manager, alerter broker.Alerter, staleAfter time.Duration, now time.Time, alerted map[string]bool, logger di.Logger) map[string]bool {
+	lister, ok := manager.(state.SummaryLister)
+	if !ok {
+		return alerted
+	}
+	heartbeats, ok := manager.(state.HeartbeatStore)
+	if !ok {
+		return alerted
+	}
+	summaries, err := lister.ListSummaries()
+	if err != nil {
+		logger.Warn("Failed to list cascades for heartbeat checks", "error", err)
+		return alerted
+	}
+
+	current := make(map[string]bool)
+	for _, summary := range summaries {
+		inFlight, err := heartbeats.LoadHeartbeats(summary.Module, summary.Version)
+		if err != nil {
+			if !errors.Is(err, state.ErrNotImplemented) {
+				logger.Warn("Failed to load heartbeats", "module", summary.Module, "version", summary.Version, "error", err)
+			}
+			continue
+		}
+		for _, hb := range state.StaleHeartbeats(inFlight, now, staleAfter) {
+			stall := fmt.Sprintf("%s@%s/%s@%d", summary.Module, summary.Version, hb.Repo, hb.LastBeat.UnixNano())
+			current[stall] = true
+			if alerted[stall] {
+				continue
+			}
+
+			age := now.Sub(hb.LastBeat).Round(time.Second)
+			logger.Warn("In-flight work item stopped sending heartbeats",
+				"module", summary.Module, "version", summary.Version, "repo", hb.Repo, "phase", hb.Phase, "pid", hb.PID, "age", age)
+			message := fmt.Sprintf("Cascade %s@%s: %s has sent no heartbeat for %s in phase %s (pid %d may have hung or exited)",
+				summary.Module, summary.Version, hb.Repo, age, hb.Phase, hb.PID)
+			var notImplemented *broker.NotImplementedError
+			if _, err := alerter.Alert(ctx, message); err != nil && !errors.As(err, &notImplemented) {
+				logger.Warn("Failed to send stale heartbeat alert", "repo", hb.Repo, "error", err)
+			}
+		}
+	}
+	return current
+}
+
+// feedEntries returns the limit most recently completed cascades recorded in
+// manager. A cascade is completed once it recorded an item and no item is still
+// in flight. Links point at the summaries under baseURL, when set.
+func feedEntries(manager state.Manager, baseURL string, limit int) ([]server.FeedEntry, error) {
+	lister, ok := manager.(state.SummaryLister)
+	if !ok {
+		return nil, errors.New("the configured state backend cannot list cascades")
+	}
+	summaries, err := lister.ListSummaries()
+	if err != nil {
+		return nil, fmt.Errorf("list cascades: %w", err)
+	}
+
+	completed := make([]state.Summary, 0, len(summaries))
+	for _, summary := range summaries {
+		if !summary.EndTime.IsZero() {
+			completed = append(completed, summary)
+		}
+	}
+	sort.SliceStable(completed, func(i, j int) bool { return completed[i].EndTime.After(completed[j].EndTime) })
+
+	heartbeats, _ := manager.(state.HeartbeatStore)
+	entries := []server.FeedEntry{}
+	for i := range completed {
+		if len(entries) >= limit {
+			break
+		}
+		summary := &completed[i]
+		if heartbeats != nil {
+			inFlight, err := heartbeats.LoadHeartbeats(summary.Module, summary.Version)
+			if err != nil && !errors.Is(err, state.ErrNotImplemented) {
+				return nil, fmt.Errorf("load heartbeats for %s@%s: %w", summary.Module, summary.Version, err)
+			}
+			if len(inFlight) > 0 {
+				continue
+			}
+		}
+
+		items, err := manager.LoadItemStates(summary.Module, summary.Version)
+		if err != nil {
+			return nil, fmt.Errorf("load item states for %s@%s: %w", summary.Module, summary.Version, err)
+		}
+		annotations, err := loadAnnotations(manager, summary.Module, summary.Version)
+		if err != nil {
+			return nil, err
+		}
+		entry := server.FeedEntry{
+			Module:     summary.Module,
+			Version:    summary.Version,
+			Started:    summary.StartTime,
+			Completed:  summary.EndTime,
+			Link:       broker.BuildStateLinks(baseURL, summary.Module, summary.Version, "").Summary,
+			Notes:      feedNotes(state.AnnotationsFor(annotations, "")),
+			Dependents: []server.FeedDependent{},
+		}
+		for _, item := range mergeItemStates(summary, items) {
+			entry.Dependents = append(entry.Dependents, server.FeedDependent{
+				Repo:   item.Repo,
+				Status: string(item.Status),
+				PRURL:  item.PRURL,
+				Notes:  feedNotes(state.AnnotationsFor(annotations, item.Repo)),
+			})
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// feedNotes renders annotations as "note (author)" lines for the feed.
+func feedNotes(annotations []state.Annotation) []string {
+	var notes []string
+	for _, annotation := range annotations {
+		note := annotation.Note
+		if annotation.Author != "" {
+			note += " (" + annotation.Author + ")"
+		}
+		notes = append(notes, note)
+	}
+	return notes
+}
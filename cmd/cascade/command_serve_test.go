@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goliatone/cascade/internal/broker"
+	execpkg "github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/internal/state"
+	"github.com/goliatone/cascade/pkg/config"
+	"github.com/goliatone/cascade/pkg/di"
+)
+
+func TestRunServe(t *testing.T) {
+	cfg := config.New()
+	testContainer, err := di.New(di.WithConfig(cfg), di.WithLogger(&mockLogger{}))
+	if err != nil {
+		t.Fatalf("di.New() error = %v", err)
+	}
+	originalContainer := container
+	container = testContainer
+	defer func() { container = originalContainer }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	opts := serveOptions{Addr: "127.0.0.1:0", Path: "/webhook", ManifestPath: ".cascade.yaml"}
+
+	err = runServe(ctx, opts)
+	var cliErr *CLIError
+	if !errors.As(err, &cliErr) || !strings.Contains(cliErr.Hint, "CASCADE_GITHUB_WEBHOOK_SECRET") {
+		t.Fatalf("expected a CLI error with a webhook secret hint, got %v", err)
+	}
+
+	cfg.Integration.GitHub.WebhookSecret = "s3cret"
+	if err := runServe(ctx, opts); err != nil {
+		t.Fatalf("runServe() after cancellation error = %v", err)
+	}
+}
+
+func TestFeedEntries(t *testing.T) {
+	manager := newIssueTrackingManager(t)
+	started := time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC)
+	cascades := []struct {
+		summary  state.Summary
+		items    []state.ItemState
+		inFlight bool
+	}{
+		{
+			summary: state.Summary{Module: "example.com/lib", Version: "v1.2.0", StartTime: started, EndTime: started.Add(10 * time.Minute)},
+			items: []state.ItemState{
+				{Repo: "example/b", Branch: "update", Status: execpkg.StatusFailed, Reason: "tests failed"},
+				{Repo: "example/a", Branch: "update", Status: execpkg.StatusCompleted, PRURL: "https://github.com/example/a/pull/1"},
+			},
+		},
+		{
+			// Started later but finished first
+			summary: state.Summary{Module: "example.com/other", Version: "v0.4.0", StartTime: started.Add(time.Minute), EndTime: started.Add(5 * time.Minute)},
+			items: []state.ItemState{
+				{Repo: "example/c", Branch: "update", Status: execpkg.StatusCompleted, PRURL: "https://github.com/example/c/pull/5"},
+			},
+		},
+		{
+			summary: state.Summary{Module: "example.com/lib", Version: "v1.3.0", StartTime: started.Add(time.Hour), EndTime: started.Add(61 * time.Minute)},
+			items: []state.ItemState{
+				{Repo: "example/a", Branch: "update", Status: execpkg.StatusCompleted},
+			},
+			inFlight: true,
+		},
+		{
+			summary: state.Summary{Module: "example.com/lib", Version: "v1.4.0", StartTime: started.Add(2 * time.Hour)},
+		},
+	}
+	for _, c := range cascades {
+		summary := c.summary
+		if err := manager.SaveSummary(&summary); err != nil {
+			t.Fatal(err)
+		}
+		for _, item := range c.items {
+			if err := manager.SaveItemState(summary.Module, summary.Version, item); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if c.inFlight {
+			hb := state.Heartbeat{Repo: "example/b", Phase: "test", StartedAt: summary.EndTime}
+			if err := manager.(state.HeartbeatStore).SaveHeartbeat(summary.Module, summary.Version, hb); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := manager.(state.AnnotationStore).AddAnnotation("example.com/lib", "v1.2.0", state.Annotation{Repo: "example/b", Note: "waiting on upstream fix", Author: "sam"}); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := feedEntries(manager, "https://cascade.example.com/state/", 10)
+	if err != nil {
+		t.Fatalf("feedEntries() error = %v", err)
+	}
+	if len(entries) != 2 || entries[0].Version != "v1.2.0" || entries[1].Module != "example.com/other" {
+		t.Fatalf("feedEntries() = %+v, want v1.2.0 then example.com/other", entries)
+	}
+	entry := entries[0]
+	if !entry.Completed.Equal(started.Add(10*time.Minute)) || !entry.Started.Equal(started) {
+		t.Errorf("entry times = %v, %v", entry.Started, entry.Completed)
+	}
+	if entry.Link != "https://cascade.example.com/state/example.com/lib/v1.2.0/summary.json" {
+		t.Errorf("entry link = %q", entry.Link)
+	}
+	if len(entry.Dependents) != 2 || entry.Dependents[0].Repo != "example/a" ||
+		entry.Dependents[0].PRURL != "https://github.com/example/a/pull/1" || entry.Dependents[1].Status != "failed" {
+		t.Errorf("entry dependents = %+v", entry.Dependents)
+	}
+	if notes := entry.Dependents[1].Notes; len(notes) != 1 || notes[0] != "waiting on upstream fix (sam)" || len(entry.Notes) != 0 {
+		t.Errorf("entry notes = %v, dependent notes = %v", entry.Notes, notes)
+	}
+
+	entries, err = feedEntries(manager, "", 1)
+	if err != nil {
+		t.Fatalf("feedEntries() error = %v", err)
+	}
+	if len(entries) != 1 || entries[0].Version != "v1.2.0" || entries[0].Link != "" {
+		t.Errorf("feedEntries() with limit 1 = %+v", entries)
+	}
+}
+
+type recordingAlerter struct {
+	messages []string
+}
+
+func (r *recordingAlerter) Alert(_ context.Context, message string) (*broker.NotificationResult, error) {
+	r.messages = append(r.messages, message)
+	return &broker.NotificationResult{Channel: "test"}, nil
+}
+
+func TestAlertStaleHeartbeats(t *testing.T) {
+	manager := newIssueTrackingManager(t)
+	now := time.Date(2025, 3, 4, 12, 0, 0, 0, time.UTC)
+	summary := state.Summary{Module: "example.com/lib", Version: "v1.3.0", StartTime: now.Add(-time.Hour)}
+	if err := manager.SaveSummary(&summary); err != nil {
+		t.Fatal(err)
+	}
+	store := manager.(state.HeartbeatStore)
+	beat := func(repo string, last time.Time) {
+		t.Helper()
+		hb := state.Heartbeat{Repo: repo, Phase: "test", PID: 4242, StartedAt: now.Add(-time.Hour), LastBeat: last}
+		if err := store.SaveHeartbeat(summary.Module, summary.Version, hb); err != nil {
+			t.Fatal(err)
+		}
+	}
+	beat("example/hung", now.Add(-10*time.Minute))
+	beat("example/live", now.Add(-10*time.Second))
+
+	alerter := &recordingAlerter{}
+	alerted := alertStaleHeartbeats(context.Background(), manager, alerter, 90*time.Second, now, map[string]bool{}, &mockLogger{})
+	if len(alerter.messages) != 1 || !strings.Contains(alerter.messages[0], "example.com/lib@v1.3.0: example/hung has sent no heartbeat for 10m0s in phase test") {
+		t.Fatalf("alerts = %q, want one for the hung item", alerter.messages)
+	}
+
+	// The same stall is reported once
+	alerted = alertStaleHeartbeats(context.Background(), manager, alerter, 90*time.Second, now.Add(time.Minute), alerted, &mockLogger{})
+	if len(alerter.messages) != 1 {
+		t.Fatalf("alerts = %q, want the stall reported once", alerter.messages)
+	}
+
+	// A heartbeat that advanced and stopped again is a new stall
+	beat("example/hung", now.Add(-5*time.Minute))
+	alertStaleHeartbeats(context.Background(), manager, alerter, 90*time.Second, now, alerted, &mockLogger{})
+	if len(alerter.messages) != 2 {
+		t.Fatalf("alerts = %q, want the new stall reported", alerter.messages)
+	}
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/goliatone/cascade/internal/planner"
+	"github.com/goliatone/cascade/pkg/config"
+	"github.com/goliatone/cascade/pkg/workspace"
+	"github.com/spf13/cobra"
+)
+
+// smokeVersion stands in for the target version, which is not released yet.
+const smokeVersion = "local"
+
+// newSmokeCommand creates the smoke command
+func newSmokeCommand() *cobra.Command {
+	var opts smokeOptions
+
+	cmd := &cobra.Command{
+		Use:   "smoke",
+		Short: "Test dependents in the workspace against the unreleased local module",
+		Long: `Smoke checks whether the dependents would still work with the module as it is
+on disk, before it is released. Instead of cloning each dependent, it assembles
+a temporary go.work that uses the local module and the checkouts of the
+dependents found in the workspace, then runs each dependent's tests against it.
+Dependents without tests are built instead.
+
+Nothing is committed, pushed, or recorded. Dependents that are not checked out
+in the workspace, or that need services, are skipped. Smoke exits with an
+execution error when a dependent is incompatible.
+
+Examples:
+  cascade smoke
+  cascade smoke --workspace ~/code
+  cascade smoke --module-dir ../go-errors --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSmoke(cmd.Context(), cmd.OutOrStdout(), opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.ManifestPath, "manifest", "", "Path to dependency manifest file (default: .cascade.yaml)")
+	cmd.Flags().StringVar(&opts.ModuleDir, "module-dir", "", "Directory of the module to test (default: the module containing the current directory)")
+	cmd.Flags().StringVar(&opts.Workspace, "workspace", "", "Directory holding the dependents' checkouts (default: auto-detected from module location)")
+	cmd.Flags().BoolVar(&opts.KeepGoWork, "keep-go-work", false, "Keep the generated go.work and print its path")
+	cmd.Flags().BoolVar(&opts.JSON, "json", false, "Print the report as JSON")
+
+	return cmd
+}
+
+// smokeOptions holds the smoke command settings.
+type smokeOptions struct {
+	ManifestPath string
+	ModuleDir    string
+	Workspace    string
+	KeepGoWork   bool
+	JSON         bool
+}
+
+func runSmoke(ctx context.Context, w io.Writer, opts smokeOptions) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	cfg := container.Config()
+
+	moduleDir, err := smokeModuleDir(opts.ModuleDir)
+	if err != nil {
+		return err
+	}
+	targetMod, err := readSmokeGoMod(moduleDir)
+	if err != nil {
+		return newFileError(fmt.Sprintf("failed to read the module in %s", moduleDir), err)
+	}
+	modulePath := modulePathOf(targetMod)
+	if modulePath == "" {
+		return newValidationError(fmt.Sprintf("%s/go.mod declares no module path", moduleDir), nil)
+	}
+
+	manifestPath := resolvePlanManifestPath(opts.ManifestPath, "", cfg)
+	m, err := container.Manifest().Load(manifestPath)
+	if err != nil {
+		return newFileError("failed to load manifest", err).
+			WithHint("create one with `cascade manifest generate` or pass --manifest")
+	}
+
+	// Checkouts are tested as they are, so the branch only has to satisfy the
+	// planner
+	if strings.TrimSpace(m.Defaults.Branch) == "" {
+		m.Defaults.Branch = "HEAD"
+	}
+
+	workspaceDir := workspace.Resolve(opts.Workspace, cfg, modulePath, moduleDir)
+	l := config.WorkspaceLayout(cfg)
+	plan, err := planner.New(
+		planner.WithWorkspace(workspaceDir),
+		planner.WithWorkspaceLayout(l),
+		planner.WithLogger(container.Logger()),
+	).Plan(ctx, m, planner.Target{Module: modulePath, Version: smokeVersion})
+	if err != nil {
+		return newPlanningError("failed to plan the dependents", err)
+	}
+
+	report := &smokeReport{Module: modulePath, ModuleDir: moduleDir}
+	for _, repo := range plan.Stats.SkippedLocalRepos {
+		report.Results = append(report.Results, smokeResult{Repo: repo, Status: smokeSkipped, Reason: "builds against a local replace of the module"})
+	}
+	deps, skipped := locateSmokeDependents(plan.Items, workspaceDir, l)
+	report.Results = append(report.Results, skipped...)
+	if len(deps) == 0 {
+		return newValidationError(fmt.Sprintf("no dependents of %s are checked out in %s", modulePath, workspaceDir), nil).
+			WithHint("clone the dependents there or pass --workspace")
+	}
+
+	workDir, err := os.MkdirTemp("", "cascade-smoke-")
+	if err != nil {
+		return newExecutionError("failed to create the workspace directory", err)
+	}
+	if !opts.KeepGoWork {
+		defer os.RemoveAll(workDir)
+	}
+	goWork, used, skipped, err := writeSmokeGoWork(workDir, moduleDir, deps)
+	if err != nil {
+		return newExecutionError("failed to write go.work", err)
+	}
+	report.Results = append(report.Results, skipped...)
+	if opts.KeepGoWork {
+		report.GoWork = goWork
+	}
+
+	if !opts.JSON {
+		fmt.Fprintf(w, "Smoke testing %d dependents against %s in %s\n", len(used), modulePath, moduleDir)
+	}
+	runner := newExecutionDeps(cfg).command
+	for _, dep := range used {
+		result := runSmokeDependent(ctx, runner, dep, goWork, cfg.Executor.Timeout)
+		if !opts.JSON {
+			printSmokeResult(w, result)
+		}
+		report.Results = append(report.Results, result)
+	}
+
+	if opts.JSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return newGenericError("failed to encode smoke report", err)
+		}
+	} else {
+		for _, result := range report.Results {
+			if result.Status == smokeSkipped {
+				printSmokeResult(w, result)
+			}
+		}
+		fmt.Fprintf(w, "\n%d compatible, %d incompatible, %d skipped\n", report.count(smokeCompatible), report.count(smokeIncompatible), report.count(smokeSkipped))
+		if report.GoWork != "" {
+			fmt.Fprintf(w, "go.work kept at %s\n", report.GoWork)
+		}
+	}
+
+	if n := report.count(smokeIncompatible); n > 0 {
+		return newExecutionError(fmt.Sprintf("%d dependents are incompatible with the local %s", n, modulePath), nil).
+			WithHint("reproduce a failure with GOWORK=<go.work> from --keep-go-work, or run cascade try after releasing")
+	}
+	return nil
+}
+
+// smokeModuleDir returns the absolute directory of the module to test: dir, or
+// the module containing the current directory.
+func smokeModuleDir(dir string) (string, error) {
+	if dir = strings.TrimSpace(dir); dir == "" {
+		_, detected, err := detectModuleInfo()
+		if err != nil {
+			return "", newValidationError("no go.mod found in the current directory tree", err).
+				WithHint("run smoke inside the module or pass --module-dir")
+		}
+		dir = detected
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", newFileError("failed to resolve module directory", err)
+	}
+	return abs, nil
+}
+
+func printSmokeResult(w io.Writer, result smokeResult) {
+	switch result.Status {
+	case smokeCompatible:
+		fmt.Fprintf(w, "  %s %s (%s)\n", style.mark(markOK), result.Repo, result.Duration.Round(10*time.Millisecond))
+	case smokeIncompatible:
+		fmt.Fprintf(w, "  %s %s: %s\n", style.mark(markFailed), result.Repo, result.FailedCommand)
+		for _, line := range strings.Split(result.Output, "\n") {
+			if line = strings.TrimRight(line, " \t"); line != "" {
+				fmt.Fprintf(w, "      %s\n", line)
+			}
+		}
+	default:
+		fmt.Fprintf(w, "  %s %s: %s\n", style.mark(markSkipped), result.Repo, result.Reason)
+	}
+}
@@ -0,0 +1,652 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	execpkg "github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/internal/state"
+	"github.com/goliatone/cascade/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+// newStateCommand creates the state subcommand for inspecting recorded runs
+func newStateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "state",
+		Short: "Inspect recorded cascade state",
+		Long: `State inspects the summaries, item states, per-attempt run snapshots, and
+run inputs cascade records for each module@version release, and keeps the
+notes operators attach to them.`,
+	}
+
+	cmd.AddCommand(newStateShowCommand(), newStateDiffCommand(), newStateInputsCommand(), newStateAnnotateCommand())
+	return cmd
+}
+
+// newStateShowCommand creates the state show subcommand
+func newStateShowCommand() *cobra.Command {
+	var (
+		staleAfter time.Duration
+		format     string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "show [state-id]",
+		Short: "Show recorded item states and in-flight work",
+		Long: `Show prints the recorded outcome of every work item for a module@version along
+with items that are still executing. In-flight items refresh a heartbeat while
+they run; an item whose heartbeat stops advancing for longer than --stale-after
+is flagged as stale, which usually means the process hung or was killed.
+
+With --format=json, the summary, item states, and heartbeats are printed as
+stored, including the go.mod versions each update moved.
+
+Examples:
+  cascade state show go-errors@v1.4.0
+  cascade state show go-errors@v1.4.0 --format=json
+  cascade state show --module=github.com/example/lib --version=v1.2.3 --stale-after=10m`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			stateID := ""
+			if len(args) > 0 {
+				stateID = args[0]
+			}
+			if !cmd.Flags().Changed("stale-after") {
+				staleAfter = defaultStaleAfter(container.Config())
+			}
+			return runStateShow(cmd.OutOrStdout(), stateID, format, staleAfter, time.Now())
+		},
+	}
+
+	cmd.Flags().DurationVar(&staleAfter, "stale-after", 0, "Flag in-flight items whose heartbeat is older than this (default: 3x executor.heartbeat_interval)")
+	cmd.Flags().StringVar(&format, "format", "text", "Output format: text or json")
+
+	return cmd
+}
+
+// defaultStaleAfter allows a few missed heartbeats before an item is flagged.
+func defaultStaleAfter(cfg *config.Config) time.Duration {
+	interval := 30 * time.Second
+	if cfg != nil && cfg.Executor.HeartbeatInterval > 0 {
+		interval = cfg.Executor.HeartbeatInterval
+	}
+	return 3 * interval
+}
+
+// stateShowJSON is the --format=json output of state show.
+type stateShowJSON struct {
+	Summary    *state.Summary    `json:"summary"`
+	Items      []state.ItemState `json:"items"`
+	Heartbeats []state.Heartbeat `json:"heartbeats,omitempty"`
+
+	Annotations []state.Annotation `json:"annotations,omitempty"`
+}
+
+func runStateShow(w io.Writer, stateID, format string, staleAfter time.Duration, now time.Time) error {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format != "text" && format != "json" {
+		return newValidationError(fmt.Sprintf("unsupported show format %q", format), nil).
+			WithHint("use --format=text or --format=json")
+	}
+
+	module, version, err := resolveModuleVersion(stateID, container.Config())
+	if err != nil {
+		return newValidationError(err.Error(), nil)
+	}
+
+	summary, err := container.State().LoadSummary(module, version)
+	if err != nil {
+		if err == state.ErrNotFound {
+			return newStateError(fmt.Sprintf("no saved state found for %s@%s", module, version), nil).
+				WithHint("check the module and version, or run `cascade release` to create state")
+		}
+		return newStateError("failed to load summary", err)
+	}
+
+	items, err := container.State().LoadItemStates(module, version)
+	if err != nil {
+		return newStateError("failed to load item states", err)
+	}
+
+	var heartbeats []state.Heartbeat
+	if store, ok := container.State().(state.HeartbeatStore); ok {
+		heartbeats, err = store.LoadHeartbeats(module, version)
+		if err != nil && !errors.Is(err, state.ErrNotImplemented) {
+			return newStateError("failed to load heartbeats", err)
+		}
+	}
+
+	annotations, err := loadAnnotations(container.State(), module, version)
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(stateShowJSON{Summary: summary, Items: items, Heartbeats: heartbeats, Annotations: annotations}); err != nil {
+			return newGenericError("failed to encode state", err)
+		}
+		return nil
+	}
+
+	renderStateShow(w, summary, items, heartbeats, staleAfter, now)
+	if len(annotations) > 0 {
+		fmt.Fprintf(w, "\nNotes (%d):\n", len(annotations))
+		for _, annotation := range annotations {
+			subject := annotation.Repo
+			if subject == "" {
+				subject = "cascade"
+			}
+			printAnnotations(w, "  "+subject+" ", []state.Annotation{annotation})
+		}
+	}
+	return nil
+}
+
+func renderStateShow(w io.Writer, summary *state.Summary, items []state.ItemState, heartbeats []state.Heartbeat, staleAfter time.Duration, now time.Time) {
+	fmt.Fprintf(w, "State for %s@%s\n", summary.Module, summary.Version)
+	if !summary.StartTime.IsZero() {
+		fmt.Fprintf(w, "  Started:  %s\n", summary.StartTime.Local().Format(time.RFC3339))
+	}
+	if len(heartbeats) > 0 {
+		fmt.Fprintf(w, "  Finished: in progress\n")
+	} else if !summary.EndTime.IsZero() {
+		fmt.Fprintf(w, "  Finished: %s\n", summary.EndTime.Local().Format(time.RFC3339))
+	}
+	fmt.Fprintf(w, "  Attempts: %d\n", summary.RetryCount+1)
+	if summary.Inputs != "" {
+		fmt.Fprintf(w, "  Inputs:   %s\n", shortDigest(summary.Inputs))
+	}
+	if summary.Upstream != "" {
+		fmt.Fprintf(w, "  Upstream: %s (wave %d)\n", summary.Upstream, summary.Wave)
+	}
+
+	sorted := append([]state.ItemState(nil), items...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Repo < sorted[j].Repo })
+
+	fmt.Fprintf(w, "\nItems (%d):\n", len(sorted))
+	for _, item := range sorted {
+		line := fmt.Sprintf("  %s %s [%s] attempts=%d", itemStatusMarker(item.Status), item.Repo, item.Status, item.Attempts)
+		if item.MaxAttempts > 0 {
+			line += fmt.Sprintf("/%d", item.MaxAttempts)
+		}
+		if item.PRURL != "" {
+			line += " PR: " + item.PRURL
+		}
+		if reason := strings.TrimSpace(item.Reason); reason != "" && item.Status != execpkg.StatusCompleted {
+			line += " - " + reason
+		}
+		if impact := item.DependencyImpact; impact != nil && impact.Applied && impact.OldVersionDetected {
+			line += fmt.Sprintf(" (%s -> %s)", impact.OldVersion, impact.NewVersion)
+		}
+		if item.ProxyFallback {
+			line += " (proxy fallback)"
+		}
+		if item.Revert != nil {
+			line += fmt.Sprintf(" (revert: %s", item.Revert.Action)
+			if item.Revert.PRURL != "" {
+				line += " " + item.Revert.PRURL
+			}
+			line += ")"
+		}
+		fmt.Fprintln(w, style.fit(line))
+	}
+
+	if len(summary.Downstream) > 0 {
+		fmt.Fprintf(w, "\nDownstream (%d):\n", len(summary.Downstream))
+		for _, release := range summary.Downstream {
+			if release.Reason != "" {
+				fmt.Fprintln(w, style.fit(fmt.Sprintf("  %s %s [wave %d] - %s", style.mark(markSkipped), release.Module, release.Wave, release.Reason)))
+				continue
+			}
+			fmt.Fprintln(w, style.fit(fmt.Sprintf("  %s %s@%s [wave %d]", style.mark(markOK), release.Module, release.Version, release.Wave)))
+		}
+	}
+
+	if summary.Timings != nil && len(summary.Timings.Stages) > 0 {
+		fmt.Fprintln(w)
+		printRunTimings(w, summary.Timings)
+	}
+
+	if len(heartbeats) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "\nIn flight (%d):\n", len(heartbeats))
+	for _, hb := range heartbeats {
+		age := now.Sub(hb.LastBeat).Round(time.Second)
+		running := now.Sub(hb.StartedAt).Round(time.Second)
+		if hb.Stale(now, staleAfter) {
+			fmt.Fprintln(w, style.fit(fmt.Sprintf("  %s %s: STALE in phase %s, no heartbeat for %s (pid %d may have hung or exited)", style.mark(markWarning), hb.Repo, hb.Phase, age, hb.PID)))
+			continue
+		}
+		fmt.Fprintln(w, style.fit(fmt.Sprintf("  %s %s: phase %s, running %s, last heartbeat %s ago", style.mark(markRunning), hb.Repo, hb.Phase, running, age)))
+	}
+}
+
+func itemStatusMarker(status execpkg.Status) string {
+	switch status {
+	case execpkg.StatusCompleted:
+		return style.mark(markOK)
+	case execpkg.StatusSkipped, execpkg.StatusNoChange:
+		return style.mark(markSkipped)
+	case execpkg.StatusManualReview:
+		return style.mark(markReview)
+	default:
+		return style.mark(markFailed)
+	}
+}
+
+// newStateDiffCommand creates the state diff subcommand
+func newStateDiffCommand() *cobra.Command {
+	var (
+		runs   []string
+		format string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "diff [state-id]",
+		Short: "Compare item outcomes between two attempts",
+		Long: `Diff compares the item outcomes recorded by two attempts (a release and any
+later resumes) of the same module@version, listing items that newly pass, newly
+fail, or are still stuck. Runs are selected by ID, by 1-based attempt number, or
+with "latest" and "previous". The two most recent runs are compared by default.
+
+Examples:
+  cascade state diff go-errors@v1.4.0
+  cascade state diff go-errors@v1.4.0 --runs=1,3
+  cascade state diff go-errors@v1.4.0 --runs=20250101T120000Z,latest --format=json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			stateID := ""
+			if len(args) > 0 {
+				stateID = args[0]
+			}
+			return runStateDiff(cmd.OutOrStdout(), stateID, runs, format)
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&runs, "runs", []string{"previous", "latest"}, "Two runs to compare: IDs, attempt numbers, latest, or previous")
+	cmd.Flags().StringVar(&format, "format", "text", "Output format: text or json")
+
+	return cmd
+}
+
+func runStateDiff(w io.Writer, stateID string, selectors []string, format string) error {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format != "text" && format != "json" {
+		return newValidationError(fmt.Sprintf("unsupported diff format %q", format), nil).
+			WithHint("use --format=text or --format=json")
+	}
+	if len(selectors) != 2 {
+		return newValidationError("--runs requires exactly two runs", nil).
+			WithHint("for example --runs=previous,latest")
+	}
+
+	module, version, err := resolveModuleVersion(stateID, container.Config())
+	if err != nil {
+		return newValidationError(err.Error(), nil)
+	}
+
+	store, ok := container.State().(state.RunStore)
+	if !ok {
+		return newStateError("the configured state backend does not record run history", nil)
+	}
+
+	runs, err := store.LoadRuns(module, version)
+	if err != nil {
+		if errors.Is(err, state.ErrNotImplemented) {
+			return newStateError("the configured state backend does not record run history", err).
+				WithHint("state persistence may be disabled; check state.enabled in your configuration")
+		}
+		return newStateError("failed to load runs", err)
+	}
+	if len(runs) < 2 {
+		return newStateError(fmt.Sprintf("%s@%s has %d recorded run(s); at least two are needed to compare", module, version, len(runs)), nil).
+			WithHint("runs are recorded by `cascade release` and `cascade resume`")
+	}
+
+	before, err := selectRun(runs, selectors[0])
+	if err != nil {
+		return err
+	}
+	after, err := selectRun(runs, selectors[1])
+	if err != nil {
+		return err
+	}
+
+	diff := state.DiffRuns(before, after)
+
+	if format == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(diff); err != nil {
+			return newGenericError("failed to encode run diff", err)
+		}
+		return nil
+	}
+
+	renderRunDiff(w, module, version, before, after, diff)
+	return nil
+}
+
+// selectRun resolves a run selector against runs ordered oldest first.
+func selectRun(runs []state.Run, selector string) (*state.Run, error) {
+	selector = strings.TrimSpace(selector)
+	switch strings.ToLower(selector) {
+	case "latest":
+		return &runs[len(runs)-1], nil
+	case "previous":
+		return &runs[len(runs)-2], nil
+	}
+
+	if n, err := strconv.Atoi(selector); err == nil {
+		if n < 1 || n > len(runs) {
+			return nil, newValidationError(fmt.Sprintf("run %d is out of range (1-%d)", n, len(runs)), nil)
+		}
+		return &runs[n-1], nil
+	}
+
+	for i := range runs {
+		if runs[i].ID == selector {
+			return &runs[i], nil
+		}
+	}
+
+	ids := make([]string, len(runs))
+	for i, run := range runs {
+		ids[i] = run.ID
+	}
+	return nil, newValidationError(fmt.Sprintf("run %q not found", selector), nil).
+		WithHint("recorded runs: %s", strings.Join(ids, ", "))
+}
+
+func renderRunDiff(w io.Writer, module, version string, before, after *state.Run, diff state.RunDiff) {
+	fmt.Fprintf(w, "Comparing %s@%s runs %s (attempt %d) -> %s (attempt %d)\n",
+		module, version, before.ID, before.Attempt, after.ID, after.Attempt)
+
+	renderRunDiffSection(w, "Newly passing", style.mark(markOK), diff.NewlyPassing)
+	renderRunDiffSection(w, "Newly failing", style.mark(markFailed), diff.NewlyFailing)
+	renderRunDiffSection(w, "Still stuck", style.mark(markReview), diff.StillStuck)
+
+	fmt.Fprintf(w, "\nStill passing: %d\n", len(diff.StillPassing))
+}
+
+func renderRunDiffSection(w io.Writer, title, marker string, entries []state.RunDiffEntry) {
+	fmt.Fprintf(w, "\n%s (%d):\n", title, len(entries))
+	for _, entry := range entries {
+		beforeStatus := string(entry.BeforeStatus)
+		if beforeStatus == "" {
+			beforeStatus = "not run"
+		}
+		line := fmt.Sprintf("  %s %s: %s -> %s", marker, entry.Repo, beforeStatus, entry.AfterStatus)
+		if reason := strings.TrimSpace(entry.Reason); reason != "" && entry.AfterStatus != execpkg.StatusCompleted {
+			line += " - " + reason
+		}
+		fmt.Fprintln(w, style.fit(line))
+	}
+}
+
+// newStateInputsCommand creates the state inputs subcommand
+func newStateInputsCommand() *cobra.Command {
+	var (
+		run        string
+		showConfig bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "inputs [state-id]",
+		Short: "Print the manifest and config a run started with",
+		Long: `Inputs prints the copy of the manifest that a release or resume ran with, as
+stored in state. It also shows the digest and the cascade version. With
+--config it prints the resolved configuration instead, with secrets redacted.
+The latest inputs are shown by default. Use --run to pick the attempt, by ID,
+by 1-based attempt number, or with "latest" and "previous".
+
+Examples:
+  cascade state inputs go-errors@v1.4.0
+  cascade state inputs go-errors@v1.4.0 --run=1 --config`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			stateID := ""
+			if len(args) > 0 {
+				stateID = args[0]
+			}
+			return runStateInputs(cmd.OutOrStdout(), stateID, run, showConfig)
+		},
+	}
+
+	cmd.Flags().StringVar(&run, "run", "", "Attempt whose inputs to print: an ID, attempt number, latest, or previous")
+	cmd.Flags().BoolVar(&showConfig, "config", false, "Print the redacted configuration instead of the manifest")
+
+	return cmd
+}
+
+func runStateInputs(w io.Writer, stateID, runSelector string, showConfig bool) error {
+	module, version, err := resolveModuleVersion(stateID, container.Config())
+	if err != nil {
+		return newValidationError(err.Error(), nil)
+	}
+
+	store, ok := container.State().(state.InputStore)
+	if !ok {
+		return newStateError("the configured state backend does not record run inputs", nil)
+	}
+
+	digest := ""
+	if runSelector == "" {
+		summary, err := container.State().LoadSummary(module, version)
+		if err != nil {
+			if err == state.ErrNotFound {
+				return newStateError(fmt.Sprintf("no saved state found for %s@%s", module, version), nil).
+					WithHint("check the module and version, or run `cascade release` to create state")
+			}
+			return newStateError("failed to load summary", err)
+		}
+		digest = summary.Inputs
+	} else {
+		runStore, ok := container.State().(state.RunStore)
+		if !ok {
+			return newStateError("the configured state backend does not record run history", nil)
+		}
+		runs, err := runStore.LoadRuns(module, version)
+		if err != nil {
+			return newStateError("failed to load runs", err)
+		}
+		run, err := selectRun(runs, runSelector)
+		if err != nil {
+			return err
+		}
+		digest = run.Inputs
+	}
+	if digest == "" {
+		return newStateError(fmt.Sprintf("no run inputs were recorded for %s@%s", module, version), nil).
+			WithHint("inputs are recorded by `cascade release` from this version on")
+	}
+
+	inputs, err := store.LoadInputs(module, version, digest)
+	if err != nil {
+		if errors.Is(err, state.ErrNotFound) || errors.Is(err, state.ErrNotImplemented) {
+			return newStateError(fmt.Sprintf("run inputs %s are missing from state", shortDigest(digest)), err)
+		}
+		return newStateError("failed to load run inputs", err)
+	}
+
+	return renderRunInputs(w, inputs, showConfig)
+}
+
+// renderRunInputs prints the inputs header followed by the stored manifest, or
+// the redacted config when showConfig is set.
+func renderRunInputs(w io.Writer, inputs *state.Inputs, showConfig bool) error {
+	fmt.Fprintf(w, "# Inputs:   %s\n", inputs.Digest)
+	if inputs.CascadeVersion != "" {
+		fmt.Fprintf(w, "# Cascade:  %s\n", inputs.CascadeVersion)
+	}
+	if inputs.ManifestPath != "" {
+		fmt.Fprintf(w, "# Manifest: %s\n", inputs.ManifestPath)
+	}
+	if !inputs.CreatedAt.IsZero() {
+		fmt.Fprintf(w, "# Captured: %s\n", inputs.CreatedAt.Local().Format(time.RFC3339))
+	}
+
+	if !showConfig {
+		fmt.Fprint(w, inputs.Manifest)
+		if inputs.Manifest != "" && !strings.HasSuffix(inputs.Manifest, "\n") {
+			fmt.Fprintln(w)
+		}
+		return nil
+	}
+
+	if len(inputs.Config) == 0 {
+		fmt.Fprintln(w, "# No configuration was recorded")
+		return nil
+	}
+	var decoded any
+	if err := json.Unmarshal(inputs.Config, &decoded); err != nil {
+		return newStateError("stored configuration is not valid JSON", err)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(decoded); err != nil {
+		return newGenericError("failed to encode configuration", err)
+	}
+	return nil
+}
+
+// newStateAnnotateCommand creates the state annotate subcommand
+func newStateAnnotateCommand() *cobra.Command {
+	var opts stateAnnotateOptions
+
+	cmd := &cobra.Command{
+		Use:   "annotate [state-id]",
+		Short: "Attach an operator note to a cascade or one of its dependents",
+		Long: `Annotate attaches a free-form note to a recorded module@version, or with --repo
+to one of its dependents, so the context behind a stuck or paused update is not
+lost between shifts. Notes are kept in state next to the run summary and
+survive resumes and reruns. They are shown by cascade status and cascade state
+show, and carried by the notifications and failure issues about the item.
+
+The author defaults to $USER. --clear removes the notes on the dependent, or on
+the cascade itself without --repo.
+
+Examples:
+  cascade state annotate go-errors@v1.4.0 --repo org/api --note "waiting on upstream fix"
+  cascade state annotate go-errors@v1.4.0 --note "paused for the release freeze" --author sam
+  cascade state annotate go-errors@v1.4.0 --repo org/api --clear`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			stateID := ""
+			if len(args) > 0 {
+				stateID = args[0]
+			}
+			if !cmd.Flags().Changed("author") {
+				opts.Author = os.Getenv("USER")
+			}
+			return runStateAnnotate(cmd.OutOrStdout(), stateID, opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Repo, "repo", "", "Dependent repository to annotate (owner/repo); the cascade itself when empty")
+	cmd.Flags().StringVar(&opts.Note, "note", "", "Note to attach")
+	cmd.Flags().StringVar(&opts.Author, "author", "", "Who wrote the note (default: $USER)")
+	cmd.Flags().BoolVar(&opts.Clear, "clear", false, "Remove the notes instead of adding one")
+
+	return cmd
+}
+
+// stateAnnotateOptions holds the state annotate settings.
+type stateAnnotateOptions struct {
+	Repo   string
+	Note   string
+	Author string
+	Clear  bool
+}
+
+func runStateAnnotate(w io.Writer, stateID string, opts stateAnnotateOptions) error {
+	repo, note := strings.TrimSpace(opts.Repo), strings.TrimSpace(opts.Note)
+	switch {
+	case opts.Clear && note != "":
+		return newValidationError("--note and --clear cannot be combined", nil)
+	case !opts.Clear && note == "":
+		return newValidationError("no note given", nil).
+			WithHint(`pass --note "...", or --clear to remove notes`)
+	}
+
+	module, version, err := resolveModuleVersion(stateID, container.Config())
+	if err != nil {
+		return newValidationError(err.Error(), nil)
+	}
+
+	store, ok := container.State().(state.AnnotationStore)
+	if !ok {
+		return newStateError("the configured state backend does not keep annotations", nil)
+	}
+
+	summary, err := container.State().LoadSummary(module, version)
+	if err != nil {
+		if errors.Is(err, state.ErrNotFound) {
+			return newStateError(fmt.Sprintf("no saved state found for %s@%s", module, version), nil).
+				WithHint("check the module and version, or run `cascade release` to create state")
+		}
+		return newStateError("failed to load summary", err)
+	}
+	if repo != "" {
+		items, err := container.State().LoadItemStates(module, version)
+		if err != nil {
+			return newStateError("failed to load item states", err)
+		}
+		if !hasItemState(mergeItemStates(summary, items), repo) {
+			return newValidationError(fmt.Sprintf("%s is not a dependent recorded for %s@%s", repo, module, version), nil).
+				WithHint("run `cascade state show %s@%s` to list its dependents", module, version)
+		}
+	}
+
+	subject := repo
+	if subject == "" {
+		subject = module + "@" + version
+	}
+
+	if opts.Clear {
+		if err := store.ClearAnnotations(module, version, repo); err != nil {
+			return annotationError("failed to clear annotations", err)
+		}
+		fmt.Fprintf(w, "%s Cleared the notes on %s\n", style.mark(markOK), subject)
+		return nil
+	}
+
+	annotation := state.Annotation{Repo: repo, Note: note, Author: strings.TrimSpace(opts.Author)}
+	if err := store.AddAnnotation(module, version, annotation); err != nil {
+		return annotationError("failed to save annotation", err)
+	}
+	fmt.Fprintf(w, "%s Noted on %s: %s\n", style.mark(markOK), subject, note)
+	return nil
+}
+
+func annotationError(message string, err error) error {
+	switch {
+	case errors.Is(err, state.ErrNotImplemented):
+		return newStateError("the configured state backend does not keep annotations", err).
+			WithHint("state persistence may be disabled; check state.enabled in your configuration")
+	case errors.Is(err, state.ErrReadOnly):
+		return newStateError("the configured state backend is read-only", err)
+	}
+	return newStateError(message, err)
+}
+
+func hasItemState(items []state.ItemState, repo string) bool {
+	for _, item := range items {
+		if item.Repo == repo {
+			return true
+		}
+	}
+	return false
+}
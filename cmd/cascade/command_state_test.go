@@ -0,0 +1,306 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	execpkg "github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/internal/planner"
+	"github.com/goliatone/cascade/internal/state"
+	"github.com/goliatone/cascade/pkg/config"
+	"github.com/goliatone/cascade/pkg/di"
+)
+
+func TestSelectRun(t *testing.T) {
+	runs := []state.Run{{ID: "r1"}, {ID: "r2"}, {ID: "r3"}}
+
+	tests := []struct {
+		selector string
+		want     string
+		wantErr  bool
+	}{
+		{selector: "latest", want: "r3"},
+		{selector: "previous", want: "r2"},
+		{selector: "1", want: "r1"},
+		{selector: "r2", want: "r2"},
+		{selector: "4", wantErr: true},
+		{selector: "missing", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		run, err := selectRun(runs, tt.selector)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("selectRun(%q) expected error", tt.selector)
+			}
+			continue
+		}
+		if err != nil || run.ID != tt.want {
+			t.Errorf("selectRun(%q) = %v, %v; want %s", tt.selector, run, err, tt.want)
+		}
+	}
+}
+
+func TestRenderRunDiff(t *testing.T) {
+	before := &state.Run{ID: "r1", Attempt: 1}
+	after := &state.Run{ID: "r2", Attempt: 2}
+	diff := state.RunDiff{
+		NewlyPassing: []state.RunDiffEntry{{Repo: "example/a", BeforeStatus: execpkg.StatusFailed, AfterStatus: execpkg.StatusCompleted, Reason: "ok"}},
+		StillStuck:   []state.RunDiffEntry{{Repo: "example/b", BeforeStatus: execpkg.StatusFailed, AfterStatus: execpkg.StatusFailed, Reason: "tests failed"}},
+		NewlyFailing: []state.RunDiffEntry{{Repo: "example/c", AfterStatus: execpkg.StatusFailed}},
+	}
+
+	var out bytes.Buffer
+	renderRunDiff(&out, "example.com/lib", "v1.0.0", before, after, diff)
+
+	for _, want := range []string{
+		"Comparing example.com/lib@v1.0.0 runs r1 (attempt 1) -> r2 (attempt 2)",
+		"✓ example/a: failed -> completed\n",
+		"! example/b: failed -> failed - tests failed",
+		"✗ example/c: not run -> failed",
+		"Still passing: 0",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestRenderStateShowFlagsStaleHeartbeats(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	summary := &state.Summary{Module: "example.com/lib", Version: "v1.0.0", StartTime: now.Add(-time.Hour)}
+	items := []state.ItemState{
+		{Repo: "example/b", Status: execpkg.StatusFailed, Attempts: 2, Reason: "tests failed"},
+		{Repo: "example/a", Status: execpkg.StatusCompleted, Attempts: 1, PRURL: "https://github.com/example/a/pull/1"},
+		{Repo: "example/e", Status: execpkg.StatusFailed, Attempts: 1, Reason: "reverted via cascade CLI", Revert: &state.RevertOutcome{Action: state.RevertReverted, PRURL: "https://github.com/example/e/pull/4"}},
+	}
+	heartbeats := []state.Heartbeat{
+		{Repo: "example/c", Phase: "tests", PID: 42, StartedAt: now.Add(-10 * time.Minute), LastBeat: now.Add(-10 * time.Second)},
+		{Repo: "example/d", Phase: "push", PID: 42, StartedAt: now.Add(-20 * time.Minute), LastBeat: now.Add(-5 * time.Minute)},
+	}
+
+	var out bytes.Buffer
+	renderStateShow(&out, summary, items, heartbeats, 90*time.Second, now)
+
+	for _, want := range []string{
+		"State for example.com/lib@v1.0.0",
+		"Finished: in progress",
+		"✓ example/a [completed] attempts=1 PR: https://github.com/example/a/pull/1",
+		"✗ example/b [failed] attempts=2 - tests failed",
+		"✗ example/e [failed] attempts=1 - reverted via cascade CLI (revert: reverted https://github.com/example/e/pull/4)",
+		"⟳ example/c: phase tests, running 10m0s, last heartbeat 10s ago",
+		"⚠ example/d: STALE in phase push, no heartbeat for 5m0s",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+	if strings.Index(out.String(), "example/a") > strings.Index(out.String(), "example/b") {
+		t.Errorf("expected items sorted by repo:\n%s", out.String())
+	}
+}
+
+func TestRenderStateShowMultiLevel(t *testing.T) {
+	previous := style
+	style = outputStyle{width: 120}
+	defer func() { style = previous }()
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	summary := &state.Summary{
+		Module:    "example.com/core",
+		Version:   "v1.1.0",
+		StartTime: now,
+		Upstream:  "example.com/lib@v1.2.0",
+		Wave:      1,
+		Downstream: []state.DownstreamRelease{
+			{Module: "example.com/api", Wave: 2, Version: "v2.0.1"},
+			{Module: "example.com/web", Wave: 2, Reason: "its repository was not updated by this release"},
+		},
+	}
+
+	var out bytes.Buffer
+	renderStateShow(&out, summary, nil, nil, time.Minute, now)
+
+	for _, want := range []string{
+		"Upstream: example.com/lib@v1.2.0 (wave 1)",
+		"Downstream (2):",
+		"example.com/api@v2.0.1 [wave 2]",
+		"example.com/web [wave 2] - its repository was not updated by this release",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+type heartbeatRecorder struct {
+	mockStateManager
+	mu      sync.Mutex
+	phases  []string
+	cleared []string
+}
+
+func (h *heartbeatRecorder) SaveHeartbeat(module, version string, hb state.Heartbeat) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.phases = append(h.phases, hb.Phase)
+	return nil
+}
+
+func (h *heartbeatRecorder) ClearHeartbeat(module, version, repo string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.cleared = append(h.cleared, repo)
+	return nil
+}
+
+func (h *heartbeatRecorder) LoadHeartbeats(module, version string) ([]state.Heartbeat, error) {
+	return nil, nil
+}
+
+func TestStateTrackerHeartbeat(t *testing.T) {
+	recorder := &heartbeatRecorder{}
+	tracker := &stateTracker{module: "example.com/lib", version: "v1.0.0", manager: recorder}
+
+	hb := tracker.startHeartbeat(planner.WorkItem{Repo: "example/a", BranchName: "cascade/update"}, time.Hour)
+	hb.progress(execpkg.PhaseTests)
+	hb.stop()
+
+	if !reflect.DeepEqual(recorder.phases, []string{"starting", "tests"}) {
+		t.Errorf("phases = %v", recorder.phases)
+	}
+	if !reflect.DeepEqual(recorder.cleared, []string{"example/a"}) {
+		t.Errorf("cleared = %v", recorder.cleared)
+	}
+
+	// A nil heartbeat is inert, as returned when interval is disabled
+	var disabled *itemHeartbeat = tracker.startHeartbeat(planner.WorkItem{Repo: "example/b"}, 0)
+	disabled.progress(execpkg.PhaseClone)
+	disabled.stop()
+}
+
+func TestRunStateShowJSON(t *testing.T) {
+	manager := newIssueTrackingManager(t)
+	impact := &execpkg.DependencyImpact{
+		Module:             "example.com/lib",
+		TargetVersion:      "v1.3.0",
+		OldVersion:         "v1.2.3",
+		NewVersion:         "v1.3.0",
+		OldVersionDetected: true,
+		NewVersionDetected: true,
+		Applied:            true,
+	}
+	item := state.ItemState{Repo: "example/a", Branch: "cascade/update", Status: execpkg.StatusCompleted, Attempts: 1, DependencyImpact: impact}
+	if err := manager.SaveSummary(&state.Summary{Module: "example.com/lib", Version: "v1.3.0", Items: []state.ItemState{item}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.SaveItemState("example.com/lib", "v1.3.0", item); err != nil {
+		t.Fatal(err)
+	}
+
+	testContainer, err := di.New(di.WithConfig(config.New()), di.WithLogger(&mockLogger{}), di.WithStateManager(manager))
+	if err != nil {
+		t.Fatalf("di.New() error = %v", err)
+	}
+	originalContainer := container
+	container = testContainer
+	defer func() { container = originalContainer }()
+
+	var out bytes.Buffer
+	if err := runStateShow(&out, "example.com/lib@v1.3.0", "json", time.Minute, time.Now()); err != nil {
+		t.Fatalf("runStateShow() error = %v", err)
+	}
+	var got stateShowJSON
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+	}
+	if len(got.Items) != 1 || !reflect.DeepEqual(got.Items[0].DependencyImpact, impact) {
+		t.Errorf("items = %+v, want the stored dependency impact", got.Items)
+	}
+	if got.Summary == nil || len(got.Summary.Items) != 1 || got.Summary.Items[0].DependencyImpact == nil {
+		t.Errorf("summary = %+v, want the impact in its items", got.Summary)
+	}
+	if !strings.Contains(out.String(), `"old_version": "v1.2.3"`) || !strings.Contains(out.String(), `"new_version": "v1.3.0"`) {
+		t.Errorf("unexpected JSON:\n%s", out.String())
+	}
+
+	out.Reset()
+	if err := runStateShow(&out, "example.com/lib@v1.3.0", "text", time.Minute, time.Now()); err != nil {
+		t.Fatalf("runStateShow() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "example/a [completed] attempts=1 (v1.2.3 -> v1.3.0)") {
+		t.Errorf("unexpected text:\n%s", out.String())
+	}
+
+	if err := runStateShow(&out, "example.com/lib@v1.3.0", "yaml", time.Minute, time.Now()); err == nil {
+		t.Error("expected an unsupported format error")
+	}
+}
+
+func TestRunStateAnnotate(t *testing.T) {
+	manager := newIssueTrackingManager(t)
+	item := state.ItemState{Repo: "example/a", Status: execpkg.StatusFailed, Reason: "tests failed"}
+	if err := manager.SaveSummary(&state.Summary{Module: "example.com/lib", Version: "v1.3.0", Items: []state.ItemState{item}}); err != nil {
+		t.Fatal(err)
+	}
+
+	testContainer, err := di.New(di.WithConfig(config.New()), di.WithLogger(&mockLogger{}), di.WithStateManager(manager))
+	if err != nil {
+		t.Fatalf("di.New() error = %v", err)
+	}
+	originalContainer := container
+	container = testContainer
+	defer func() { container = originalContainer }()
+
+	var out bytes.Buffer
+	for _, opts := range []stateAnnotateOptions{
+		{Repo: "example/a"},
+		{Repo: "example/a", Note: "x", Clear: true},
+		{Repo: "example/missing", Note: "x"},
+	} {
+		if err := runStateAnnotate(&out, "example.com/lib@v1.3.0", opts); err == nil {
+			t.Errorf("runStateAnnotate(%+v) expected error", opts)
+		}
+	}
+	if err := runStateAnnotate(&out, "example.com/lib@v9.9.9", stateAnnotateOptions{Note: "x"}); err == nil {
+		t.Error("expected an error for a cascade without state")
+	}
+
+	if err := runStateAnnotate(&out, "example.com/lib@v1.3.0", stateAnnotateOptions{Repo: "example/a", Note: "waiting on upstream fix", Author: "sam"}); err != nil {
+		t.Fatalf("runStateAnnotate() error = %v", err)
+	}
+	if err := runStateAnnotate(&out, "example.com/lib@v1.3.0", stateAnnotateOptions{Note: "paused for the freeze"}); err != nil {
+		t.Fatalf("runStateAnnotate() error = %v", err)
+	}
+
+	out.Reset()
+	if err := runStatus(&out, statusFilter{Module: "example.com/lib", Version: "v1.3.0"}, false); err != nil {
+		t.Fatalf("runStatus() error = %v", err)
+	}
+	lines := strings.Split(out.String(), "\n")
+	if len(lines) < 4 || !strings.HasPrefix(lines[1], "  note: paused for the freeze (") ||
+		!strings.Contains(lines[2], "example/a [failed]") || !strings.HasPrefix(lines[3], "      note: waiting on upstream fix (sam, ") {
+		t.Errorf("unexpected status:\n%s", out.String())
+	}
+
+	out.Reset()
+	if err := runStateShow(&out, "example.com/lib@v1.3.0", "text", time.Minute, time.Now()); err != nil {
+		t.Fatalf("runStateShow() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "Notes (2):") || !strings.Contains(out.String(), "  example/a note: waiting on upstream fix") {
+		t.Errorf("unexpected state show:\n%s", out.String())
+	}
+
+	if err := runStateAnnotate(&out, "example.com/lib@v1.3.0", stateAnnotateOptions{Repo: "example/a", Clear: true}); err != nil {
+		t.Fatalf("runStateAnnotate(clear) error = %v", err)
+	}
+	annotations, err := manager.(state.AnnotationStore).LoadAnnotations("example.com/lib", "v1.3.0")
+	if err != nil || len(annotations) != 1 || annotations[0].Repo != "" {
+		t.Errorf("annotations after clearing = %+v, %v", annotations, err)
+	}
+}
@@ -0,0 +1,307 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	execpkg "github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/internal/state"
+	"github.com/goliatone/cascade/pkg/di"
+	"github.com/spf13/cobra"
+)
+
+// newStatusCommand creates the status command
+func newStatusCommand() *cobra.Command {
+	var (
+		failedOnly bool
+		jsonOutput bool
+		remote     string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "status [module@version]",
+		Short: "List recorded cascades and the status of each dependent",
+		Long: `Status lists every cascade recorded in the state directory, most recent first,
+with the status and pull request of each dependent repository. Pass module@version,
+or --module and --version, to narrow the list, and --failed-only to show only
+failed dependents.
+
+--remote reads the state another team or CI system keeps in object storage,
+s3://bucket/prefix or gs://bucket/prefix, instead of the configured state. It
+only reads: nothing is locked or written, so read access to the bucket is enough.
+
+Examples:
+  cascade status
+  cascade status --module=github.com/example/lib --failed-only
+  cascade status --json
+  cascade status --remote s3://platform-cascade-state/cascade github.com/example/lib@v1.2.3`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			filter := statusFilter{FailedOnly: failedOnly, Remote: strings.TrimSpace(remote)}
+			if cfg := container.Config(); cfg != nil {
+				filter.Module = strings.TrimSpace(cfg.Module)
+				filter.Version = strings.TrimSpace(cfg.Version)
+			}
+			if len(args) > 0 {
+				parts := splitModuleVersion(strings.TrimSpace(args[0]))
+				if parts == nil {
+					return newValidationError(fmt.Sprintf("state identifier must be in module@version format: %s", args[0]), nil)
+				}
+				filter.Module, filter.Version = parts[0], parts[1]
+			}
+			return runStatus(cmd.OutOrStdout(), filter, jsonOutput)
+		},
+	}
+
+	cmd.Flags().BoolVar(&failedOnly, "failed-only", false, "Only list cascades with failed dependents, and only those dependents")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Print the cascades as JSON")
+	cmd.Flags().StringVar(&remote, "remote", "", "Read-only: report from the state backend at s3://bucket/prefix or gs://bucket/prefix instead of the configured state")
+
+	return cmd
+}
+
+// statusFilter selects the cascades and items status lists.
+type statusFilter struct {
+	Module     string
+	Version    string
+	FailedOnly bool
+
+	// Remote reads the state backend at this URL, read-only, instead of the
+	// configured one.
+	Remote string
+}
+
+// cascadeStatus is one recorded cascade in the status output.
+type cascadeStatus struct {
+	Module    string              `json:"module"`
+	Version   string              `json:"version"`
+	StartTime time.Time           `json:"start_time"`
+	EndTime   time.Time           `json:"end_time"`
+	Items     []cascadeStatusItem `json:"items"`
+
+	// Annotations are the operator notes on the cascade itself
+	Annotations []state.Annotation `json:"annotations,omitempty"`
+}
+
+// cascadeStatusItem is the recorded outcome of one dependent.
+type cascadeStatusItem struct {
+	Repo   string         `json:"repo"`
+	Status execpkg.Status `json:"status"`
+	PRURL  string         `json:"pr_url,omitempty"`
+	Reason string         `json:"reason,omitempty"`
+
+	Annotations []state.Annotation `json:"annotations,omitempty"`
+}
+
+func runStatus(w io.Writer, filter statusFilter, jsonOutput bool) error {
+	manager := container.State()
+	if filter.Remote != "" {
+		var err error
+		manager, err = di.RemoteState(filter.Remote, container.Config().State, container.Logger())
+		if err != nil {
+			return newStateError("failed to open remote state", err).
+				WithHint("pass --remote as s3://bucket/prefix or gs://bucket/prefix; credentials come from the default AWS and Google Cloud credential chains")
+		}
+	}
+
+	summaries, err := statusSummaries(manager, filter)
+	if err != nil {
+		return err
+	}
+
+	statuses := []cascadeStatus{}
+	for i := range summaries {
+		summary := &summaries[i]
+		if filter.Module != "" && summary.Module != filter.Module {
+			continue
+		}
+		if filter.Version != "" && summary.Version != filter.Version {
+			continue
+		}
+
+		items, err := manager.LoadItemStates(summary.Module, summary.Version)
+		if err != nil {
+			return newStateError(fmt.Sprintf("failed to load item states for %s@%s", summary.Module, summary.Version), err)
+		}
+
+		annotations, err := loadAnnotations(manager, summary.Module, summary.Version)
+		if err != nil {
+			return err
+		}
+
+		status := cascadeStatus{
+			Module:      summary.Module,
+			Version:     summary.Version,
+			StartTime:   summary.StartTime,
+			EndTime:     summary.EndTime,
+			Items:       []cascadeStatusItem{},
+			Annotations: state.AnnotationsFor(annotations, ""),
+		}
+		for _, item := range mergeItemStates(summary, items) {
+			if filter.FailedOnly && item.Status != execpkg.StatusFailed {
+				continue
+			}
+			status.Items = append(status.Items, cascadeStatusItem{
+				Repo:   item.Repo,
+				Status: item.Status,
+				PRURL:  item.PRURL,
+				Reason: strings.TrimSpace(item.Reason),
+
+				Annotations: state.AnnotationsFor(annotations, item.Repo),
+			})
+		}
+		if filter.FailedOnly && len(status.Items) == 0 {
+			continue
+		}
+		statuses = append(statuses, status)
+	}
+
+	if jsonOutput {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(statuses); err != nil {
+			return newGenericError("failed to encode status", err)
+		}
+		return nil
+	}
+
+	renderStatus(w, statuses, filter)
+	return nil
+}
+
+// statusSummaries returns the summaries status lists. A single module@version
+// is loaded directly, which needs no permission to list the state.
+func statusSummaries(manager state.Manager, filter statusFilter) ([]state.Summary, error) {
+	if filter.Module != "" && filter.Version != "" {
+		summary, err := manager.LoadSummary(filter.Module, filter.Version)
+		switch {
+		case errors.Is(err, state.ErrNotFound):
+			return nil, nil
+		case err != nil:
+			return nil, newStateError(fmt.Sprintf("failed to load summary for %s@%s", filter.Module, filter.Version), err)
+		}
+		return []state.Summary{*summary}, nil
+	}
+
+	lister, ok := manager.(state.SummaryLister)
+	if !ok {
+		return nil, newStateError("the configured state backend cannot list cascades", nil)
+	}
+	summaries, err := lister.ListSummaries()
+	if err != nil {
+		if errors.Is(err, state.ErrNotImplemented) {
+			return nil, newStateError("the configured state backend cannot list cascades", err).
+				WithHint("state persistence may be disabled; check state.enabled in your configuration")
+		}
+		return nil, newStateError("failed to list cascades", err)
+	}
+	return summaries, nil
+}
+
+// loadAnnotations returns the operator notes on module@version, or none when
+// the state backend does not keep them.
+func loadAnnotations(manager state.Manager, module, version string) ([]state.Annotation, error) {
+	store, ok := manager.(state.AnnotationStore)
+	if !ok {
+		return nil, nil
+	}
+	annotations, err := store.LoadAnnotations(module, version)
+	if err != nil {
+		if errors.Is(err, state.ErrNotImplemented) {
+			return nil, nil
+		}
+		return nil, newStateError(fmt.Sprintf("failed to load annotations for %s@%s", module, version), err)
+	}
+	return annotations, nil
+}
+
+// mergeItemStates combines the items embedded in summary with the item states
+// saved individually, which take precedence, sorted by repository.
+func mergeItemStates(summary *state.Summary, items []state.ItemState) []state.ItemState {
+	byRepo := make(map[string]state.ItemState, len(summary.Items)+len(items))
+	for _, item := range summary.Items {
+		byRepo[item.Repo] = item
+	}
+	for _, item := range items {
+		byRepo[item.Repo] = item
+	}
+
+	merged := make([]state.ItemState, 0, len(byRepo))
+	for _, item := range byRepo {
+		merged = append(merged, item)
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Repo < merged[j].Repo })
+	return merged
+}
+
+func renderStatus(w io.Writer, statuses []cascadeStatus, filter statusFilter) {
+	if len(statuses) == 0 {
+		if filter.Module != "" || filter.Version != "" || filter.FailedOnly {
+			fmt.Fprintln(w, "No recorded cascades match the filters.")
+			return
+		}
+		fmt.Fprintln(w, "No cascades recorded in state.")
+		return
+	}
+
+	for i, status := range statuses {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		header := fmt.Sprintf("%s@%s", status.Module, status.Version)
+		if !status.StartTime.IsZero() {
+			header += "  started " + status.StartTime.Local().Format(time.RFC3339)
+		}
+		if counts := statusCounts(status.Items); counts != "" {
+			header += "  " + counts
+		}
+		fmt.Fprintln(w, header)
+		printAnnotations(w, "  ", status.Annotations)
+
+		for _, item := range status.Items {
+			line := fmt.Sprintf("  %s %s [%s]", itemStatusMarker(item.Status), item.Repo, item.Status)
+			if item.PRURL != "" {
+				line += " PR: " + item.PRURL
+			}
+			if item.Reason != "" && item.Status != execpkg.StatusCompleted {
+				line += " - " + item.Reason
+			}
+			fmt.Fprintln(w, style.fit(line))
+			printAnnotations(w, "      ", item.Annotations)
+		}
+	}
+}
+
+// printAnnotations prints one line per operator note, under indent.
+func printAnnotations(w io.Writer, indent string, annotations []state.Annotation) {
+	for _, annotation := range annotations {
+		line := indent + "note: " + annotation.Note
+		if annotation.Author != "" {
+			line += fmt.Sprintf(" (%s, %s)", annotation.Author, annotation.Created.Local().Format(time.DateOnly))
+		} else {
+			line += fmt.Sprintf(" (%s)", annotation.Created.Local().Format(time.DateOnly))
+		}
+		fmt.Fprintln(w, style.fit(line))
+	}
+}
+
+// statusCounts summarises items by status, e.g. "2 completed, 1 failed".
+func statusCounts(items []cascadeStatusItem) string {
+	counts := make(map[execpkg.Status]int)
+	for _, item := range items {
+		counts[item.Status]++
+	}
+
+	var parts []string
+	for _, status := range []execpkg.Status{execpkg.StatusCompleted, execpkg.StatusNoChange, execpkg.StatusManualReview, execpkg.StatusSkipped, execpkg.StatusFailed} {
+		if counts[status] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[status], status))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	execpkg "github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/internal/state"
+	"github.com/goliatone/cascade/pkg/config"
+	"github.com/goliatone/cascade/pkg/di"
+)
+
+func TestRunStatus(t *testing.T) {
+	previous := style
+	style = outputStyle{width: 120}
+	defer func() { style = previous }()
+
+	manager := newIssueTrackingManager(t)
+	started := time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC)
+	cascades := []struct {
+		summary state.Summary
+		items   []state.ItemState
+	}{
+		{
+			summary: state.Summary{Module: "example.com/lib", Version: "v1.2.0", StartTime: started},
+			items: []state.ItemState{
+				{Repo: "example/a", Branch: "update", Status: execpkg.StatusCompleted, PRURL: "https://github.com/example/a/pull/1"},
+				{Repo: "example/b", Branch: "update", Status: execpkg.StatusFailed, Reason: "tests failed"},
+			},
+		},
+		{
+			summary: state.Summary{Module: "example.com/lib", Version: "v1.3.0", StartTime: started.Add(time.Hour)},
+			items: []state.ItemState{
+				{Repo: "example/a", Branch: "update", Status: execpkg.StatusCompleted, PRURL: "https://github.com/example/a/pull/2"},
+			},
+		},
+		{
+			summary: state.Summary{Module: "example.com/other", Version: "v0.4.0", StartTime: started.Add(-time.Hour)},
+			items: []state.ItemState{
+				{Repo: "example/c", Branch: "update", Status: execpkg.StatusManualReview, PRURL: "https://github.com/example/c/pull/5"},
+			},
+		},
+	}
+	for _, c := range cascades {
+		summary := c.summary
+		if err := manager.SaveSummary(&summary); err != nil {
+			t.Fatal(err)
+		}
+		for _, item := range c.items {
+			if err := manager.SaveItemState(summary.Module, summary.Version, item); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	testContainer, err := di.New(di.WithConfig(config.New()), di.WithLogger(&mockLogger{}), di.WithStateManager(manager))
+	if err != nil {
+		t.Fatalf("di.New() error = %v", err)
+	}
+	originalContainer := container
+	container = testContainer
+	defer func() { container = originalContainer }()
+
+	var out bytes.Buffer
+	if err := runStatus(&out, statusFilter{}, false); err != nil {
+		t.Fatalf("runStatus() error = %v", err)
+	}
+	text := out.String()
+	for _, want := range []string{
+		"example.com/lib@v1.3.0  started ",
+		"example.com/lib@v1.2.0  started ",
+		"1 completed, 1 failed",
+		"example/a [completed] PR: https://github.com/example/a/pull/1",
+		"example/b [failed] - tests failed",
+		"example/c [manual-review] PR: https://github.com/example/c/pull/5",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("output missing %q:\n%s", want, text)
+		}
+	}
+	if !(strings.Index(text, "lib@v1.3.0") < strings.Index(text, "lib@v1.2.0") && strings.Index(text, "lib@v1.2.0") < strings.Index(text, "other@v0.4.0")) {
+		t.Errorf("cascades not listed most recent first:\n%s", text)
+	}
+
+	out.Reset()
+	if err := runStatus(&out, statusFilter{Module: "example.com/lib", FailedOnly: true}, true); err != nil {
+		t.Fatalf("runStatus(json) error = %v", err)
+	}
+	var got []cascadeStatus
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+	}
+	if len(got) != 1 || got[0].Version != "v1.2.0" || len(got[0].Items) != 1 || got[0].Items[0].Repo != "example/b" || got[0].Items[0].Reason != "tests failed" {
+		t.Errorf("failed-only status = %+v, want only example/b in lib@v1.2.0", got)
+	}
+
+	out.Reset()
+	if err := runStatus(&out, statusFilter{Module: "example.com/other", FailedOnly: true}, false); err != nil {
+		t.Fatalf("runStatus() error = %v", err)
+	}
+	if strings.TrimSpace(out.String()) != "No recorded cascades match the filters." {
+		t.Errorf("unexpected output:\n%s", out.String())
+	}
+
+	out.Reset()
+	if err := runStatus(&out, statusFilter{Version: "v9.9.9"}, true); err != nil {
+		t.Fatalf("runStatus(json) error = %v", err)
+	}
+	if strings.TrimSpace(out.String()) != "[]" {
+		t.Errorf("empty JSON status = %q, want []", out.String())
+	}
+
+	// A single module@version is loaded without listing the state
+	out.Reset()
+	if err := runStatus(&out, statusFilter{Module: "example.com/lib", Version: "v1.3.0"}, false); err != nil {
+		t.Fatalf("runStatus(module@version) error = %v", err)
+	}
+	if got := out.String(); !strings.Contains(got, "example.com/lib@v1.3.0") || strings.Contains(got, "v1.2.0") {
+		t.Errorf("module@version output:\n%s", got)
+	}
+
+	out.Reset()
+	if err := runStatus(&out, statusFilter{Module: "example.com/lib", Version: "v9.9.9"}, false); err != nil {
+		t.Fatalf("runStatus(unknown module@version) error = %v", err)
+	}
+	if strings.TrimSpace(out.String()) != "No recorded cascades match the filters." {
+		t.Errorf("unexpected output:\n%s", out.String())
+	}
+}
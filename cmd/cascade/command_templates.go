@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/goliatone/cascade/internal/broker"
+	"github.com/spf13/cobra"
+)
+
+// newTemplatesCommand creates the templates command group.
+func newTemplatesCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "templates",
+		Short: "Inspect the template engine used for PRs and notifications",
+	}
+
+	cmd.AddCommand(newTemplatesFuncsCommand())
+	return cmd
+}
+
+// newTemplatesFuncsCommand lists the helper functions available to templates.
+func newTemplatesFuncsCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "funcs",
+		Short: "List helper functions available to PR, notification, and commit templates",
+		Long: `List the helper functions that can be used in PR title/body templates,
+notification templates, and commit_template, with their signatures and an
+example.
+
+Examples:
+  cascade templates funcs`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return printTemplateFuncs(cmd.OutOrStdout())
+		},
+	}
+}
+
+func printTemplateFuncs(w io.Writer) error {
+	var buf bytes.Buffer
+	tw := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "FUNCTION\tDESCRIPTION\tEXAMPLE")
+	for _, fn := range broker.TemplateFuncs() {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", fn.Signature, fn.Description, fn.Example)
+	}
+	if err := tw.Flush(); err != nil {
+		return newGenericError("failed to format template functions", err)
+	}
+	if err := writeFitted(w, buf.String()); err != nil {
+		return newGenericError("failed to print template functions", err)
+	}
+	return nil
+}
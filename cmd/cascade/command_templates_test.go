@@ -0,0 +1,30 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("broken pipe")
+}
+
+func TestPrintTemplateFuncs(t *testing.T) {
+	var out bytes.Buffer
+	if err := printTemplateFuncs(&out); err != nil {
+		t.Fatalf("printTemplateFuncs() error = %v", err)
+	}
+	if !strings.HasPrefix(out.String(), "FUNCTION") {
+		t.Errorf("output = %q, want the function table", out.String())
+	}
+
+	err := printTemplateFuncs(failingWriter{})
+	var cliErr *CLIError
+	if !errors.As(err, &cliErr) || cliErr.Code != ExitGenericError {
+		t.Errorf("printTemplateFuncs() error = %v, want a generic CLIError", err)
+	}
+}
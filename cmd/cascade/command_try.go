@@ -0,0 +1,368 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	execpkg "github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/internal/manifest"
+	"github.com/goliatone/cascade/internal/planner"
+	"github.com/goliatone/cascade/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+// newTryCommand creates the try command
+func newTryCommand() *cobra.Command {
+	var (
+		manifestPath string
+		modulePath   string
+		version      string
+		noPR         bool
+		localTarget  string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "try <owner/repo>",
+		Short: "Run the update and tests for a single dependent",
+		Long: `Try runs the full update flow for exactly one dependent: clone, update the
+module, tidy, run the dependent's tests and extra commands, commit, push, and
+open the pull request. It is a fast feedback loop when debugging a failing item.
+
+The dependent's settings come from the manifest, merged with manifest defaults
+and the dependent's own .cascade.yaml, exactly as in cascade release. A
+repository the manifest does not list is tried with the manifest defaults.
+Dependency checks are skipped, so up-to-date and skipped dependents run too.
+
+Try does not record state, take the run lock, or send notifications. With
+--no-pr the update is committed in the workspace worktree but not pushed, and
+no pull request is opened.
+
+With --local-target the dependent's tests and extra commands build against a
+local checkout of the module, through a go.work outside the worktree, instead
+of the version the update requires. This checks an unreleased commit before it
+is tagged; the commit still only bumps the version in go.mod and go.sum.
+
+Examples:
+  cascade try example/api --module=github.com/example/lib --version=v1.2.3
+  cascade try example/api --no-pr
+  cascade try example/api --no-pr --local-target=../lib
+  cascade try example/api --dry-run`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTry(cmd.Context(), cmd.OutOrStdout(), args[0], tryOptions{
+				ManifestPath: manifestPath,
+				ModulePath:   modulePath,
+				Version:      version,
+				NoPR:         noPR,
+				LocalTarget:  localTarget,
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&manifestPath, "manifest", "", "Path to dependency manifest file (default: .cascade.yaml)")
+	cmd.Flags().StringVar(&modulePath, "module", "", "Go module path (e.g., github.com/example/lib). Auto-detected from go.mod if not provided")
+	cmd.Flags().StringVar(&version, "version", "", "Target version (e.g., v1.2.3). Auto-detected from .version file or git tags if not provided")
+	cmd.Flags().BoolVar(&noPR, "no-pr", false, "Commit the update locally without pushing it or opening a pull request")
+	cmd.Flags().StringVar(&localTarget, "local-target", "", "Run the tests against this checkout of the module through a go.work instead of the target version")
+
+	return cmd
+}
+
+// tryOptions holds the try command settings.
+type tryOptions struct {
+	ManifestPath string
+	ModulePath   string
+	Version      string
+	NoPR         bool
+	LocalTarget  string
+}
+
+func runTry(ctx context.Context, w io.Writer, repo string, opts tryOptions) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	cfg := container.Config()
+
+	repo = strings.TrimSpace(repo)
+	if repo == "" {
+		return newValidationError("repository must be provided", nil)
+	}
+
+	manifestData, target, err := resolveTryTarget(ctx, opts.ManifestPath, opts.ModulePath, opts.Version)
+	if err != nil {
+		return err
+	}
+	local, err := resolveLocalTarget(opts.LocalTarget, []planner.Target{target})
+	if err != nil {
+		return err
+	}
+	item, listed, err := planTryItem(ctx, manifestData, target, repo, cfg)
+	if err != nil {
+		return err
+	}
+	if !listed {
+		fmt.Fprintf(w, "%s %s is not a dependent of %s in the manifest; trying it with the manifest defaults\n", style.mark(markReview), repo, target.Module)
+	}
+
+	if cfg.Executor.DryRun {
+		fmt.Fprintf(w, "DRY RUN: Would try %s@%s in %s (%s) -> %s\n", target.Module, target.Version, item.Repo, item.Module, item.BranchName)
+		for _, cmd := range item.Tests {
+			fmt.Fprintf(w, "  test: %s\n", strings.Join(cmd.Cmd, " "))
+		}
+		for _, cmd := range item.ExtraCommands {
+			fmt.Fprintf(w, "  extra: %s\n", strings.Join(cmd.Cmd, " "))
+		}
+		if local != nil {
+			fmt.Fprintf(w, "Would test against the checkout in %s\n", local.dir)
+		}
+		if opts.NoPR {
+			fmt.Fprintln(w, "Would commit without pushing or opening a pull request")
+		}
+		return nil
+	}
+
+	if err := ensureWorkspace(cfg.Workspace.Path); err != nil {
+		return newExecutionError("failed to prepare workspace", err)
+	}
+
+	deps := newExecutionDeps(cfg)
+	git := deps.itemGit(item)
+	if opts.NoPR {
+		git = unpushedGit{GitOperations: deps.git}
+	}
+
+	fmt.Fprintf(w, "Trying %s@%s in %s (%s) -> %s\n", target.Module, target.Version, item.Repo, item.Module, item.BranchName)
+	localDir := ""
+	if local != nil {
+		localDir = local.dir
+		fmt.Fprintf(w, "Testing against the checkout in %s\n", localDir)
+	}
+	result, execErr := applyTryItem(ctx, w, deps, git, item, manifestData, localDir)
+	if result == nil {
+		return newExecutionError(fmt.Sprintf("try failed for %s", item.Repo), execErr)
+	}
+	printTryCommands(w, "test", result.TestResults)
+	printTryCommands(w, "extra", result.ExtraResults)
+	if result.EffectiveItem != nil {
+		item = *result.EffectiveItem
+	}
+
+	switch {
+	case execErr != nil || result.Status == execpkg.StatusFailed:
+		fmt.Fprintf(w, "%s Failed: %s\n", style.mark(markFailed), result.Reason)
+		return newExecutionError(fmt.Sprintf("try failed for %s", item.Repo), execErr)
+	case result.Status == execpkg.StatusSkipped:
+		fmt.Fprintf(w, "%s Skipped: %s\n", style.mark(markSkipped), result.Reason)
+		return nil
+	case result.Status == execpkg.StatusNoChange:
+		fmt.Fprintf(w, "%s %s already requires %s@%s: %s\n", style.mark(markOK), item.Repo, target.Module, target.Version, result.Reason)
+		return nil
+	}
+	if result.Status == execpkg.StatusManualReview {
+		fmt.Fprintf(w, "%s Manual review required: %s\n", style.mark(markReview), result.Reason)
+	}
+
+	switch {
+	case result.CommitHash == "":
+		fmt.Fprintf(w, "%s %s already requires %s@%s: %s\n", style.mark(markOK), item.Repo, target.Module, target.Version, result.Reason)
+		return nil
+	case opts.NoPR:
+		fmt.Fprintf(w, "%s Committed %s on %s without pushing\n", style.mark(markOK), result.CommitHash, item.BranchName)
+		return nil
+	}
+
+	pr, err := container.Broker().EnsurePR(ctx, item, result)
+	if err != nil {
+		return newExecutionError("failed to open pull request", err)
+	}
+	if pr != nil && pr.URL != "" {
+		fmt.Fprintf(w, "%s PR: %s\n", style.mark(markOK), pr.URL)
+	} else {
+		fmt.Fprintf(w, "%s Pushed %s to %s\n", style.mark(markOK), result.CommitHash, item.BranchName)
+	}
+	return nil
+}
+
+// resolveTryTarget loads the manifest and resolves the module and version to
+// try, falling back to the configured and detected defaults.
+func resolveTryTarget(ctx context.Context, manifestPath, modulePath, version string) (*manifest.Manifest, planner.Target, error) {
+	cfg := container.Config()
+
+	finalManifestPath := resolvePlanManifestPath(manifestPath, "", cfg)
+	if finalManifestPath == "" {
+		return nil, planner.Target{}, newValidationError("manifest path not provided and no default configured", nil)
+	}
+
+	finalModulePath := modulePath
+	if finalModulePath == "" && cfg != nil {
+		finalModulePath = cfg.Module
+	}
+	finalModulePath, moduleDir, err := applyModuleDefaults(finalModulePath)
+	if err != nil {
+		return nil, planner.Target{}, err
+	}
+
+	finalVersion := version
+	if finalVersion == "" && cfg != nil {
+		finalVersion = cfg.Version
+	}
+	resolvers := moduleVersionResolution(container.Manifest(), finalManifestPath, finalModulePath)
+	finalVersion, versionWarnings, err := applyVersionDefaults(ctx, finalModulePath, finalVersion, moduleDir, resolvers, cfg)
+	if err != nil {
+		return nil, planner.Target{}, err
+	}
+	for _, warning := range versionWarnings {
+		container.Logger().Warn("Version resolution warning", "warning", warning)
+	}
+
+	manifestData, err := container.Manifest().Load(finalManifestPath)
+	if err != nil {
+		return nil, planner.Target{}, newFileError("failed to load manifest", err).
+			WithHint("create one with `cascade manifest generate` or pass --manifest")
+	}
+	return manifestData, planner.Target{Module: finalModulePath, Version: finalVersion}, nil
+}
+
+// applyTryItem runs the update flow of item with git, within the item's
+// timeout, printing each phase as it starts. localDir, when set, is the module
+// checkout the tests build against.
+func applyTryItem(ctx context.Context, w io.Writer, deps executionDeps, git execpkg.GitOperations, item planner.WorkItem, m *manifest.Manifest, localDir string) (*execpkg.Result, error) {
+	cfg := container.Config()
+	timeout := item.Timeout
+	if timeout <= 0 {
+		timeout = cfg.Executor.Timeout
+	}
+	item.Timeout = timeout
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	return container.Executor().Apply(ctx, execpkg.WorkItemContext{
+		Item:        item,
+		Workspace:   cfg.Workspace.Path,
+		Git:         git,
+		Go:          deps.goTool,
+		Runner:      deps.command,
+		Services:    deps.services,
+		Logger:      container.Logger(),
+		NeverTouch:  m.Defaults.NeverTouch,
+		LocalTarget: localDir,
+		Progress: func(phase execpkg.Phase) {
+			fmt.Fprintf(w, "  - %s\n", phase)
+		},
+	})
+}
+
+// planTryItem plans the work item of repo for target. The manifest is narrowed to
+// the repository, so only it is planned, and dependency checks are not run. A
+// repository the target module does not list is planned as an ad-hoc dependent
+// with the manifest defaults; listed reports whether the manifest lists it.
+func planTryItem(ctx context.Context, m *manifest.Manifest, target planner.Target, repo string, cfg *config.Config) (planner.WorkItem, bool, error) {
+	trial := *m
+	trial.Modules = make([]manifest.Module, len(m.Modules))
+	listed := false
+	targetIndex := -1
+	for i, mod := range m.Modules {
+		var dependents []manifest.Dependent
+		for _, dep := range mod.Dependents {
+			if sameRepo(dep.Repo, repo) {
+				dep.Skip = false
+				dependents = append(dependents, dep)
+			}
+		}
+		mod.Dependents = dependents
+		if mod.HasPath(target.Module) {
+			listed = listed || len(dependents) > 0
+			if targetIndex < 0 {
+				targetIndex = i
+			}
+		}
+		trial.Modules[i] = mod
+	}
+	if targetIndex < 0 {
+		trial.Modules = append(trial.Modules, manifest.Module{Module: target.Module})
+		targetIndex = len(trial.Modules) - 1
+	}
+	if !listed {
+		dep := manifest.Dependent{Repo: repo, Module: adHocModulePath(repo, cfg), ModulePath: "."}
+		trial.Modules[targetIndex].Dependents = []manifest.Dependent{dep}
+	}
+
+	opts := []planner.Option{
+		planner.WithWorkspace(cfg.Workspace.Path),
+		planner.WithWorkspaceLayout(config.WorkspaceLayout(cfg)),
+		planner.WithLogger(container.Logger()),
+	}
+	if metadata := container.RepoMetadata(); metadata != nil {
+		opts = append(opts, planner.WithRepoMetadata(metadata))
+	}
+	plan, err := planner.New(opts...).Plan(ctx, &trial, target)
+	if err != nil {
+		return planner.WorkItem{}, listed, newPlanningError("failed to plan the dependent", err)
+	}
+	for _, item := range plan.Items {
+		if sameRepo(item.Repo, repo) {
+			return item, listed, nil
+		}
+	}
+
+	reason := "it produced no work item"
+	switch {
+	case len(plan.Stats.SkippedArchivedRepos) > 0:
+		reason = "the repository is archived"
+	case len(plan.Stats.SkippedLocalRepos) > 0:
+		reason = "it builds against a local replace of the module"
+	}
+	return planner.WorkItem{}, listed, newPlanningError(fmt.Sprintf("cannot try %s: %s", repo, reason), nil)
+}
+
+// adHocModulePath guesses the module path of a repository the manifest does not
+// list: the repository path on the default host for owner/repo shorthands.
+func adHocModulePath(repo string, cfg *config.Config) string {
+	path := strings.TrimSuffix(strings.TrimSpace(repo), ".git")
+	if _, rest, ok := strings.Cut(path, "://"); ok {
+		host, rest, _ := strings.Cut(rest, "/")
+		if at := strings.LastIndex(host, "@"); at >= 0 {
+			host = host[at+1:]
+		}
+		host, _, _ = strings.Cut(host, ":")
+		return host + "/" + rest
+	}
+	if at, colon := strings.Index(path, "@"), strings.Index(path, ":"); at > 0 && colon > at {
+		return path[at+1:colon] + "/" + path[colon+1:]
+	}
+	if strings.Count(path, "/") == 1 {
+		return config.RepoURLs(cfg).DefaultHost() + "/" + path
+	}
+	return path
+}
+
+// sameRepo reports whether two repository identifiers name the same repository.
+func sameRepo(a, b string) bool {
+	normalize := func(repo string) string {
+		return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(repo)), ".git")
+	}
+	return normalize(a) == normalize(b)
+}
+
+// printTryCommands prints the outcome of each command, with the output of
+// failed ones.
+func printTryCommands(w io.Writer, kind string, results []execpkg.CommandResult) {
+	for _, res := range results {
+		command := strings.Join(res.Command.Cmd, " ")
+		if res.Err == nil {
+			fmt.Fprintf(w, "    %s %s: %s\n", style.mark(markOK), kind, command)
+			continue
+		}
+		fmt.Fprintf(w, "    %s %s: %s: %v\n", style.mark(markFailed), kind, command, res.Err)
+		if output := strings.TrimSpace(res.Output); output != "" {
+			for _, line := range strings.Split(output, "\n") {
+				fmt.Fprintf(w, "      %s\n", line)
+			}
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/goliatone/cascade/internal/manifest"
+	"github.com/goliatone/cascade/internal/planner"
+	"github.com/goliatone/cascade/pkg/config"
+	"github.com/goliatone/cascade/pkg/di"
+)
+
+func TestPlanTryItem(t *testing.T) {
+	cfg := config.New()
+	testContainer, err := di.New(di.WithConfig(cfg), di.WithLogger(&mockLogger{}))
+	if err != nil {
+		t.Fatalf("di.New() error = %v", err)
+	}
+	originalContainer := container
+	container = testContainer
+	defer func() { container = originalContainer }()
+
+	m := &manifest.Manifest{
+		Defaults: manifest.Defaults{
+			Branch: "main",
+			Tests:  []manifest.Command{{Cmd: []string{"go", "test", "./..."}}},
+		},
+		Modules: []manifest.Module{{
+			Module: "github.com/example/lib",
+			Dependents: []manifest.Dependent{
+				{Repo: "example/api", Module: "github.com/example/api", ModulePath: "."},
+				{Repo: "example/web", Module: "github.com/example/web", ModulePath: ".", Branch: "develop", Skip: true},
+			},
+		}},
+	}
+	target := planner.Target{Module: "github.com/example/lib", Version: "v1.2.0"}
+
+	item, listed, err := planTryItem(context.Background(), m, target, "Example/Web", cfg)
+	if err != nil {
+		t.Fatalf("planTryItem() error = %v", err)
+	}
+	if !listed || item.Repo != "example/web" || item.Branch != "develop" || item.SourceVersion != "v1.2.0" {
+		t.Errorf("skipped dependent = %+v (listed %v)", item, listed)
+	}
+	if len(m.Modules[0].Dependents) != 2 || !m.Modules[0].Dependents[1].Skip {
+		t.Error("expected the manifest to be left unmodified")
+	}
+
+	item, listed, err = planTryItem(context.Background(), m, target, "example/cli", cfg)
+	if err != nil {
+		t.Fatalf("planTryItem() ad-hoc error = %v", err)
+	}
+	if listed || item.Repo != "example/cli" || item.Module != "github.com/example/cli" || item.Branch != "main" || len(item.Tests) != 1 {
+		t.Errorf("ad-hoc dependent = %+v (listed %v)", item, listed)
+	}
+}
+
+func TestAdHocModulePath(t *testing.T) {
+	cfg := config.New()
+	for repo, want := range map[string]string{
+		"example/api":                        "github.com/example/api",
+		"git.example.com/team/api":           "git.example.com/team/api",
+		"https://gitlab.com/group/api.git":   "gitlab.com/group/api",
+		"git@github.com:Example/API.git":     "github.com/Example/API",
+		"ssh://git@git.example.com:2222/api": "git.example.com/api",
+	} {
+		if got := adHocModulePath(repo, cfg); got != want {
+			t.Errorf("adHocModulePath(%q) = %q, want %q", repo, got, want)
+		}
+	}
+}
@@ -3,11 +3,14 @@ package main
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/url"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"text/tabwriter"
+	"time"
 
 	execpkg "github.com/goliatone/cascade/internal/executor"
 	"github.com/goliatone/cascade/internal/planner"
@@ -57,6 +60,104 @@ func resolveModuleVersion(stateID string, cfg *config.Config) (string, string, e
 	return module, version, nil
 }
 
+// printSkippedArchived lists dependents the planner left out because their
+// repository is archived.
+func printSkippedArchived(stats *planner.PlanStats) {
+	if len(stats.SkippedArchivedRepos) == 0 {
+		return
+	}
+	fmt.Printf("%d archived repositories skipped: %s\n\n",
+		len(stats.SkippedArchivedRepos), strings.Join(stats.SkippedArchivedRepos, ", "))
+}
+
+// printSkippedLocal lists dependents the planner left out because they build
+// against a local copy of the target module.
+func printSkippedLocal(stats *planner.PlanStats) {
+	if len(stats.SkippedLocalRepos) == 0 {
+		return
+	}
+	fmt.Printf("%d locally managed repositories skipped (replace or go.work): %s\n\n",
+		len(stats.SkippedLocalRepos), strings.Join(stats.SkippedLocalRepos, ", "))
+}
+
+// printDuplicateDependents reports dependents listed by several manifest
+// entries, which the planner merged, and the settings it ignored doing so.
+func printDuplicateDependents(w io.Writer, stats *planner.PlanStats) {
+	if len(stats.DuplicateDependents) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "%d dependents listed by several manifest entries were merged:\n", len(stats.DuplicateDependents))
+	for _, duplicate := range stats.DuplicateDependents {
+		mark := markOK
+		if len(duplicate.Conflicts) > 0 {
+			mark = markWarning
+		}
+		fmt.Fprintf(w, "  %s %s (%s)\n", style.mark(mark), duplicate.Repo, strings.Join(duplicate.Entries, ", "))
+		if len(duplicate.Filled) > 0 {
+			fmt.Fprintf(w, "      filled from later entries: %s\n", strings.Join(duplicate.Filled, ", "))
+		}
+		if len(duplicate.Conflicts) > 0 {
+			fmt.Fprintf(w, "      conflicting, kept from %s: %s\n", duplicate.Entries[0], strings.Join(duplicate.Conflicts, ", "))
+		}
+	}
+	fmt.Fprintln(w)
+}
+
+// printRunTimings reports where the items of a run spent their time, so
+// concurrency and caching can be tuned.
+func printRunTimings(w io.Writer, timings *state.RunTimings) {
+	if timings == nil || len(timings.Stages) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "Timing breakdown (%d items, peak concurrency %d):\n", timings.Items, timings.PeakConcurrency)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "  STAGE\tITEMS\tP50\tP90\tMAX\tTOTAL")
+	for _, stage := range timings.Stages {
+		fmt.Fprintf(tw, "  %s\t%d\t%s\t%s\t%s\t%s\n", strings.ReplaceAll(string(stage.Stage), "_", " "), stage.Count,
+			roundStageDuration(stage.P50), roundStageDuration(stage.P90), roundStageDuration(stage.Max), roundStageDuration(stage.Total))
+	}
+	tw.Flush()
+}
+
+// roundStageDuration keeps stage durations readable: milliseconds below a
+// second, tenths of a second above.
+func roundStageDuration(d time.Duration) time.Duration {
+	if d < time.Second {
+		return d.Round(time.Millisecond)
+	}
+	return d.Round(100 * time.Millisecond)
+}
+
+// longRunEstimate is the estimated duration above which plan output suggests
+// raising concurrency or splitting the run.
+const longRunEstimate = 30 * time.Minute
+
+// printPlanEstimate reports the expected runtime and GitHub API usage of
+// executing the plan with the given concurrency.
+func printPlanEstimate(w io.Writer, stats *planner.PlanStats, concurrency int) {
+	if stats.WorkItemsCreated == 0 {
+		return
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	fmt.Fprintf(w, "Estimate (concurrency %d):\n", concurrency)
+	if stats.EstimatedDuration > 0 {
+		fmt.Fprintf(w, "  - Runtime: ~%s (%d of %d work items timed in earlier releases)\n",
+			stats.EstimatedDuration.Round(time.Second), stats.TimedItems, stats.WorkItemsCreated)
+	} else {
+		fmt.Fprintln(w, "  - Runtime: unknown (no timings recorded for earlier releases)")
+	}
+	fmt.Fprintf(w, "  - GitHub API calls: at least %d for pull requests\n", stats.EstimatedAPICalls)
+
+	if stats.EstimatedDuration > longRunEstimate && concurrency < stats.WorkItemsCreated {
+		fmt.Fprintf(w, "  %s Consider raising executor.concurrent_limit (--parallel) or splitting the release\n", style.mark(markWarning))
+	}
+	fmt.Fprintln(w)
+}
+
 func printResumeSummary(module, version string, itemStates []state.ItemState, plan *planner.Plan) {
 	fmt.Printf("DRY RUN: Would resume cascade for %s@%s\n", module, version)
 	if plan == nil || len(plan.Items) == 0 {
@@ -83,6 +184,9 @@ func printResumeSummary(module, version string, itemStates []state.ItemState, pl
 				status = string(st.Status)
 			}
 			reason = st.Reason
+			if st.Completed(state.PhasePushed) && st.NextPhase() != "" {
+				status += ", resumes from " + string(st.NextPhase())
+			}
 		}
 		fmt.Printf("  %d. %s (%s) -> %s [%s]", i+1, item.Repo, item.Module, item.BranchName, status)
 		if strings.TrimSpace(reason) != "" {
@@ -130,3 +234,27 @@ func appendReason(existing, addition string) string {
 	}
 	return existing + "; " + addition
 }
+
+// printItemOutcome reports the result of processing one work item under its
+// listing line in release and resume output.
+func printItemOutcome(w io.Writer, itemState state.ItemState) {
+	switch itemState.Status {
+	case execpkg.StatusCompleted:
+		if itemState.PRURL != "" {
+			fmt.Fprintf(w, "    %s PR: %s\n", style.mark(markOK), itemState.PRURL)
+		} else {
+			fmt.Fprintf(w, "    %s Completed with commit %s\n", style.mark(markOK), itemState.CommitHash)
+		}
+	case execpkg.StatusManualReview:
+		fmt.Fprintf(w, "    %s Manual review required: %s\n", style.mark(markReview), itemState.Reason)
+	case execpkg.StatusSkipped:
+		fmt.Fprintf(w, "    %s Skipped: %s\n", style.mark(markSkipped), itemState.Reason)
+	case execpkg.StatusNoChange:
+		fmt.Fprintf(w, "    %s No change: %s\n", style.mark(markSkipped), itemState.Reason)
+	default:
+		fmt.Fprintf(w, "    %s Failed: %s\n", style.mark(markFailed), itemState.Reason)
+	}
+	if itemState.ProxyFallback {
+		fmt.Fprintf(w, "    %s Fetched the module directly because GOPROXY could not serve it\n", style.mark(markReview))
+	}
+}
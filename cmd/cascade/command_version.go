@@ -1,16 +1,63 @@
 package main
 
 import (
+	"context"
+	"fmt"
+	"io"
+
 	"github.com/goliatone/cascade/pkg/version"
 	"github.com/spf13/cobra"
 )
 
 func newVersionCommand() *cobra.Command {
-	return &cobra.Command{
+	var checkUpdate bool
+
+	cmd := &cobra.Command{
 		Use:   "version",
 		Short: "Print Cascade version information",
+		Long: `Print Cascade version information.
+
+With --check-update, cascade also queries GitHub Releases for a newer version.
+Results are cached for 24 hours so repeated invocations stay offline.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return version.Print(cmd.OutOrStdout())
+			if err := version.Print(cmd.OutOrStdout()); err != nil {
+				return newGenericError("failed to print version information", err)
+			}
+			if !checkUpdate {
+				return nil
+			}
+			return runVersionCheck(cmd.Context(), cmd.OutOrStdout(), version.NewUpdateChecker())
 		},
 	}
+
+	cmd.Flags().BoolVar(&checkUpdate, "check-update", false, "Check GitHub Releases for a newer cascade version")
+
+	return cmd
+}
+
+// runVersionCheck reports whether a newer cascade release is available.
+func runVersionCheck(ctx context.Context, w io.Writer, checker *version.UpdateChecker) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	info, err := checker.Check(ctx)
+	if err != nil {
+		return newNetworkError("failed to check for updates", err).
+			WithHint("set GITHUB_TOKEN if you are being rate limited")
+	}
+
+	switch {
+	case info.Available:
+		fmt.Fprintf(w, "Update available: %s -> %s\n", info.Current, info.Latest)
+		if info.URL != "" {
+			fmt.Fprintf(w, "  Release notes: %s\n", info.URL)
+		}
+		fmt.Fprintln(w, "  Run `cascade self-update` to install it.")
+	case info.Current == info.Latest:
+		fmt.Fprintf(w, "cascade %s is up to date\n", info.Current)
+	default:
+		fmt.Fprintf(w, "Latest release: %s (running %s)\n", info.Latest, info.Current)
+	}
+	return nil
 }
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/goliatone/cascade/pkg/version"
+)
+
+func newReleaseServer(t *testing.T, tag string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(version.Release{Tag: tag, URL: "https://example.com/" + tag})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRunVersionCheck(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  string
+		latest   string
+		expected string
+	}{
+		{name: "update available", current: "v1.0.0", latest: "v1.1.0", expected: "Update available: v1.0.0 -> v1.1.0"},
+		{name: "up to date", current: "v1.1.0", latest: "v1.1.0", expected: "cascade v1.1.0 is up to date"},
+		{name: "development build", current: "dev", latest: "v1.1.0", expected: "Latest release: v1.1.0 (running dev)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newReleaseServer(t, tt.latest)
+			checker := version.NewUpdateChecker(
+				version.WithAPIURL(server.URL),
+				version.WithCacheDir(""),
+				version.WithCurrentVersion(tt.current),
+			)
+
+			var out bytes.Buffer
+			if err := runVersionCheck(context.Background(), &out, checker); err != nil {
+				t.Fatalf("runVersionCheck() error = %v", err)
+			}
+			if !strings.Contains(out.String(), tt.expected) {
+				t.Fatalf("expected output to contain %q, got %q", tt.expected, out.String())
+			}
+		})
+	}
+}
+
+func TestRunVersionCheckNetworkError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	checker := version.NewUpdateChecker(version.WithAPIURL(server.URL), version.WithCacheDir(""))
+	err := runVersionCheck(context.Background(), &bytes.Buffer{}, checker)
+	cliErr := asCLIError(err)
+	if cliErr == nil || cliErr.ExitCode() != ExitNetworkError {
+		t.Fatalf("expected network error, got %v", err)
+	}
+}
+
+func TestRunSelfUpdateDryRun(t *testing.T) {
+	server := newReleaseServer(t, "v99.0.0")
+	checker := version.NewUpdateChecker(version.WithAPIURL(server.URL), version.WithCacheDir(""))
+
+	var out bytes.Buffer
+	err := runSelfUpdate(context.Background(), &out, checker, selfUpdateRequest{
+		Executable: "/usr/local/bin/cascade",
+		Force:      true,
+		DryRun:     true,
+	})
+	if err != nil {
+		t.Fatalf("runSelfUpdate() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "would install cascade v99.0.0") {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/goliatone/cascade/internal/broker"
+	"github.com/goliatone/cascade/internal/state"
+	"github.com/spf13/cobra"
+)
+
+// newWaitCommand creates the wait subcommand
+func newWaitCommand() *cobra.Command {
+	var (
+		waitTimeout  time.Duration
+		pollInterval time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "wait [state-id]",
+		Short: "Wait for the pull requests of a cascade to merge",
+		Long: `Wait blocks until every pull request a cascade opened is merged or has
+failed: it was closed without merging, or its checks failed while pr.auto_merge
+was set for it. Pull requests of dependents with pr.auto_merge are merged once
+their checks pass and the provider accepts the merge, which it refuses until
+required approvals are met.
+
+Wait exits with an error when any pull request failed or did not reach either
+state within --wait-timeout.
+
+Examples:
+  cascade wait github.com/example/lib@v1.2.3
+  cascade wait --module=github.com/example/lib --version=v1.2.3 --wait-timeout=30m`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			stateID := ""
+			if len(args) > 0 {
+				stateID = args[0]
+			}
+			return runWait(cmd.OutOrStdout(), stateID, waitTimeout, pollInterval)
+		},
+	}
+
+	cmd.Flags().DurationVar(&waitTimeout, "wait-timeout", 0, "How long to wait for the pull requests (default: executor wave_timeout, 2h)")
+	cmd.Flags().DurationVar(&pollInterval, "poll-interval", 0, "How often to check the pull requests (default: executor wave_poll_interval, 1m)")
+
+	return cmd
+}
+
+func runWait(out io.Writer, stateID string, waitTimeout, pollInterval time.Duration) error {
+	start := time.Now()
+	logger := container.Logger()
+	cfg := container.Config()
+
+	defer func() {
+		if logger != nil {
+			logger.Debug("Wait command completed",
+				"duration_ms", time.Since(start).Milliseconds(),
+				"state_id", stateID,
+			)
+		}
+	}()
+
+	module, version, err := resolveModuleVersion(stateID, cfg)
+	if err != nil {
+		return newValidationError(err.Error(), nil)
+	}
+	if waitTimeout < 0 || pollInterval < 0 {
+		return newValidationError("--wait-timeout and --poll-interval cannot be negative", nil)
+	}
+	if waitTimeout == 0 {
+		waitTimeout = cfg.Executor.WaveTimeout
+	}
+	if pollInterval == 0 {
+		pollInterval = cfg.Executor.WavePollInterval
+	}
+
+	itemStates, err := container.State().LoadItemStates(module, version)
+	if err != nil {
+		if err == state.ErrNotFound {
+			return newStateError(fmt.Sprintf("no saved state found for %s@%s", module, version), nil).
+				WithHint("check the module and version, or run `cascade release` to create state")
+		}
+		return newStateError("failed to load item states", err)
+	}
+
+	var prs []state.ItemState
+	for _, item := range itemStates {
+		if item.PRURL == "" || item.Revert.Done() {
+			continue
+		}
+		prs = append(prs, item)
+	}
+	if len(prs) == 0 {
+		fmt.Fprintf(out, "No pull requests recorded for %s@%s\n", module, version)
+		return nil
+	}
+
+	if cfg.Executor.DryRun {
+		fmt.Fprintf(out, "DRY RUN: Would wait for %d pull requests of %s@%s\n", len(prs), module, version)
+		for _, item := range prs {
+			fmt.Fprintf(out, "  - %s", item.PRURL)
+			if item.AutoMerge != "" {
+				fmt.Fprintf(out, " (auto-merge: %s)", item.AutoMerge)
+			}
+			fmt.Fprintln(out)
+		}
+		return nil
+	}
+
+	ctx := context.Background()
+	if waitTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, waitTimeout)
+		defer cancel()
+	}
+
+	fmt.Fprintf(out, "Waiting for %d pull requests of %s@%s\n", len(prs), module, version)
+	watch := prWatch{
+		merger:   &prMerger{broker: container.Broker(), logger: logger},
+		interval: pollInterval,
+		out:      out,
+		sleep:    sleepContext,
+	}
+	merged, failed := watch.run(ctx, prs)
+
+	fmt.Fprintf(out, "\n%d merged, %d failed", merged, failed)
+	if pending := len(prs) - merged - failed; pending > 0 {
+		fmt.Fprintf(out, ", %d still open", pending)
+	}
+	fmt.Fprintln(out)
+	if merged < len(prs) {
+		return newExecutionError(fmt.Sprintf("%d of %d pull requests for %s@%s did not merge", len(prs)-merged, len(prs), module, version), nil).
+			WithHint("fix or merge the reported pull requests, then run `cascade wait` again")
+	}
+	return nil
+}
+
+// prWatch polls a set of pull requests until each merged or failed.
+type prWatch struct {
+	merger   *prMerger
+	interval time.Duration
+	out      io.Writer
+	sleep    func(ctx context.Context, d time.Duration) error
+}
+
+// run polls items until each pull request merged or failed, or ctx ends, and
+// returns how many merged and failed. Pull requests still open when ctx ends
+// are listed.
+func (w *prWatch) run(ctx context.Context, items []state.ItemState) (merged, failed int) {
+	type pendingPR struct {
+		item state.ItemState
+		pr   *broker.PullRequest
+	}
+
+	pending := make([]pendingPR, 0, len(items))
+	for _, item := range items {
+		pr, err := itemPullRequest(item)
+		if err != nil {
+			failed++
+			fmt.Fprintf(w.out, "  %s %s: %v\n", style.mark(markFailed), item.Repo, err)
+			continue
+		}
+		pending = append(pending, pendingPR{item: item, pr: pr})
+	}
+
+	for len(pending) > 0 {
+		var still []pendingPR
+		for _, p := range pending {
+			done, err := w.merger.poll(ctx, p.item, p.pr)
+			switch {
+			case err != nil:
+				failed++
+				fmt.Fprintf(w.out, "  %s %v\n", style.mark(markFailed), err)
+			case done:
+				merged++
+				fmt.Fprintf(w.out, "  %s %s is merged\n", style.mark(markOK), p.pr.URL)
+			default:
+				still = append(still, p)
+			}
+		}
+		pending = still
+		if len(pending) == 0 {
+			break
+		}
+		if err := w.sleep(ctx, w.interval); err != nil {
+			fmt.Fprintf(w.out, "  %s Timed out waiting for:\n", style.mark(markWarning))
+			for _, p := range pending {
+				fmt.Fprintf(w.out, "    - %s\n", p.pr.URL)
+			}
+			break
+		}
+	}
+	return merged, failed
+}
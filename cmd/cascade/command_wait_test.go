@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goliatone/cascade/internal/broker"
+	"github.com/goliatone/cascade/internal/state"
+)
+
+// urlStatusBroker reports the status listed for each pull request URL.
+type urlStatusBroker struct {
+	*waveBroker
+	statuses map[string]*broker.PullRequestStatus
+}
+
+func (b *urlStatusBroker) PullRequestStatus(ctx context.Context, pr *broker.PullRequest) (*broker.PullRequestStatus, error) {
+	return b.statuses[pr.URL], nil
+}
+
+func TestPRWatchRun(t *testing.T) {
+	items := []state.ItemState{
+		{Repo: "example/api", PRURL: "https://github.com/example/api/pull/1"},
+		{Repo: "example/core", PRURL: "https://github.com/example/core/pull/2"},
+		{Repo: "example/web", PRURL: "https://github.com/example/web/pull/3"},
+		{Repo: "example/cli", PRURL: "https://github.com/example/cli"},
+	}
+	brokerSvc := &urlStatusBroker{
+		waveBroker: &waveBroker{mockBroker: &mockBroker{}},
+		statuses: map[string]*broker.PullRequestStatus{
+			"https://github.com/example/api/pull/1":  prMerged,
+			"https://github.com/example/core/pull/2": prClosed,
+			"https://github.com/example/web/pull/3":  prOpen,
+		},
+	}
+
+	var out bytes.Buffer
+	slept := 0
+	watch := &prWatch{
+		merger:   &prMerger{broker: brokerSvc, logger: &mockLogger{}},
+		interval: time.Minute,
+		out:      &out,
+		sleep: func(ctx context.Context, d time.Duration) error {
+			if slept++; slept > 2 {
+				return context.DeadlineExceeded
+			}
+			return nil
+		},
+	}
+
+	merged, failed := watch.run(context.Background(), items)
+	if merged != 1 || failed != 2 {
+		t.Fatalf("run() = %d merged, %d failed, want 1 and 2", merged, failed)
+	}
+	for _, want := range []string{
+		"https://github.com/example/api/pull/1 is merged",
+		"https://github.com/example/core/pull/2 was closed without merging",
+		"Timed out waiting for:\n    - https://github.com/example/web/pull/3",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/goliatone/cascade/internal/state"
 	"github.com/goliatone/cascade/pkg/config"
 	"github.com/goliatone/cascade/pkg/di"
+	"github.com/goliatone/cascade/pkg/repometa"
 )
 
 type testLogger struct{}
@@ -38,11 +39,12 @@ func (c *testDIContainer) Broker() broker.Broker                 { return nil }
 func (c *testDIContainer) BrokerWithManifestNotifications(*di.ManifestNotifications) (broker.Broker, error) {
 	return nil, nil
 }
-func (c *testDIContainer) State() state.Manager     { return nil }
-func (c *testDIContainer) Config() *config.Config   { return c.cfg }
-func (c *testDIContainer) Logger() di.Logger        { return c.logger }
-func (c *testDIContainer) HTTPClient() *http.Client { return nil }
-func (c *testDIContainer) Close() error             { return nil }
+func (c *testDIContainer) State() state.Manager            { return nil }
+func (c *testDIContainer) Config() *config.Config          { return c.cfg }
+func (c *testDIContainer) Logger() di.Logger               { return c.logger }
+func (c *testDIContainer) HTTPClient() *http.Client        { return nil }
+func (c *testDIContainer) RepoMetadata() *repometa.Service { return nil }
+func (c *testDIContainer) Close() error                    { return nil }
 
 func prepareWorkflowCommandTest(t *testing.T) (*config.Config, string) {
 	t.Helper()
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"os"
@@ -86,9 +87,13 @@ func (m *mockExecutor) Apply(ctx context.Context, input execpkg.WorkItemContext)
 }
 
 type mockBroker struct {
+	resolveFunc  func(ctx context.Context, item planner.WorkItem, number int, result *execpkg.Result) (*broker.NotificationResult, error)
 	ensurePRFunc func(ctx context.Context, item planner.WorkItem, result *execpkg.Result) (*broker.PullRequest, error)
 	commentFunc  func(ctx context.Context, pr *broker.PullRequest, body string) error
 	notifyFunc   func(ctx context.Context, item planner.WorkItem, result *execpkg.Result) (*broker.NotificationResult, error)
+	mergeFunc    func(ctx context.Context, pr *broker.PullRequest, opts broker.MergeOptions) (*broker.MergeResult, error)
+	closeFunc    func(ctx context.Context, pr *broker.PullRequest) error
+	planFunc     func(ctx context.Context, summary broker.PlanSummary) (*broker.NotificationResult, error)
 }
 
 func (m *mockBroker) EnsurePR(ctx context.Context, item planner.WorkItem, result *execpkg.Result) (*broker.PullRequest, error) {
@@ -112,6 +117,34 @@ func (m *mockBroker) Notify(ctx context.Context, item planner.WorkItem, result *
 	return nil, nil
 }
 
+func (m *mockBroker) MergePR(ctx context.Context, pr *broker.PullRequest, opts broker.MergeOptions) (*broker.MergeResult, error) {
+	if m != nil && m.mergeFunc != nil {
+		return m.mergeFunc(ctx, pr, opts)
+	}
+	return &broker.MergeResult{Merged: true}, nil
+}
+
+func (m *mockBroker) ClosePR(ctx context.Context, pr *broker.PullRequest) error {
+	if m != nil && m.closeFunc != nil {
+		return m.closeFunc(ctx, pr)
+	}
+	return nil
+}
+
+func (m *mockBroker) ResolveIssue(ctx context.Context, item planner.WorkItem, number int, result *execpkg.Result) (*broker.NotificationResult, error) {
+	if m != nil && m.resolveFunc != nil {
+		return m.resolveFunc(ctx, item, number, result)
+	}
+	return nil, nil
+}
+
+func (m *mockBroker) NotifyPlan(ctx context.Context, summary broker.PlanSummary) (*broker.NotificationResult, error) {
+	if m != nil && m.planFunc != nil {
+		return m.planFunc(ctx, summary)
+	}
+	return &broker.NotificationResult{Channel: "mock", Message: "plan summary sent"}, nil
+}
+
 type mockLogger struct {
 	logs []string
 }
@@ -227,7 +260,7 @@ func TestRunPlanWithMockDependencies(t *testing.T) {
 			defer func() { container = originalContainer }()
 
 			// Call the function under test with default flag values
-			err = runPlan("", tt.manifestPath, "", "")
+			err = runPlan("", tt.manifestPath, "", "", "", false, false, "")
 
 			// Check results
 			if tt.expectError && err == nil {
@@ -466,7 +499,7 @@ func TestProductionCommandsFailWithoutCredentials(t *testing.T) {
 				}
 
 				errorMsg := err.Error()
-				if !contains(errorMsg, "production commands require GitHub credentials") {
+				if !contains(errorMsg, "production commands require GitHub, GitLab, or Bitbucket credentials") {
 					t.Errorf("expected error about production credentials requirement, got: %s", errorMsg)
 				}
 
@@ -495,7 +528,7 @@ func TestPlanCommandWorksWithoutCredentials(t *testing.T) {
 		if err != nil {
 			errorMsg := err.Error()
 			// Should not fail due to missing production credentials
-			if contains(errorMsg, "production commands require GitHub credentials") {
+			if contains(errorMsg, "production commands require GitHub, GitLab, or Bitbucket credentials") {
 				t.Errorf("plan command should not require GitHub credentials, got: %s", errorMsg)
 			}
 			// Other errors (like config defaults loading issues) are acceptable for this test
@@ -525,7 +558,7 @@ func TestDryRunModeWorksWithoutCredentials(t *testing.T) {
 		if err != nil {
 			errorMsg := err.Error()
 			// Should not fail due to missing production credentials in dry-run mode
-			if contains(errorMsg, "production commands require GitHub credentials") {
+			if contains(errorMsg, "production commands require GitHub, GitLab, or Bitbucket credentials") {
 				t.Errorf("production command in dry-run mode should not require GitHub credentials, got: %s", errorMsg)
 			}
 			// Other errors (like config defaults loading issues) are acceptable for this test
@@ -788,3 +821,17 @@ func TestManifestGenerate_WorkspaceHandling(t *testing.T) {
 		t.Errorf("expected file to exist at workspace path %s", expectedPath)
 	}
 }
+
+func TestTemplatesFuncsCommandListsHelpers(t *testing.T) {
+	var buf bytes.Buffer
+	if err := printTemplateFuncs(&buf); err != nil {
+		t.Fatalf("printTemplateFuncs returned error: %v", err)
+	}
+
+	output := buf.String()
+	for _, name := range []string{"semverMajor", "shortSHA", "truncate", "toJSON", "default", "formatDate"} {
+		if !strings.Contains(output, name) {
+			t.Errorf("expected templates funcs output to list %q, got:\n%s", name, output)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	execpkg "github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/internal/planner"
+	"github.com/goliatone/cascade/pkg/config"
+	"github.com/goliatone/cascade/pkg/di"
+)
+
+// previewReleaseDiffs applies the items of a release of targets without
+// committing them and prints their diffs. Targets the module proxy cannot serve
+// are fetched directly, as in a real run.
+func previewReleaseDiffs(ctx context.Context, cfg *config.Config, targets []planner.Target, items []planner.WorkItem, neverTouch []string, logger di.Logger) error {
+	deps := newExecutionDeps(cfg)
+	for _, t := range targets {
+		previewed, err := preflightGoProxy(ctx, os.Stdout, deps.goTool, t.Module, t.Version, items)
+		if err != nil {
+			return err
+		}
+		items = previewed
+	}
+
+	fmt.Printf("\nApplying %d updates in a temporary workspace; nothing is committed or pushed\n", len(items))
+	return newDiffPreview(cfg, container.Executor(), deps, neverTouch, logger).run(ctx, os.Stdout, items)
+}
+
+// diffPreview applies work items in a temporary workspace without committing
+// or pushing them, for release --dry-run=diff.
+type diffPreview struct {
+	executor   execpkg.Executor
+	git        execpkg.GitOperations
+	deps       executionDeps
+	neverTouch []string
+	timeout    time.Duration
+	tempDir    string
+	logger     di.Logger
+}
+
+// newDiffPreview previews with the executor and tools of a real run. Clones
+// never reuse the workspace, and the sandbox provider changes nothing since
+// nothing is pushed.
+func newDiffPreview(cfg *config.Config, executor execpkg.Executor, deps executionDeps, neverTouch []string, logger di.Logger) *diffPreview {
+	return &diffPreview{
+		executor:   executor,
+		git:        execpkg.NewGitOperationsWithRunner(deps.gitRunner, execpkg.WithWorkspaceLayout(config.WorkspaceLayout(cfg))),
+		deps:       deps,
+		neverTouch: neverTouch,
+		timeout:    cfg.Executor.Timeout,
+		tempDir:    cfg.Workspace.TempDir,
+		logger:     logger,
+	}
+}
+
+// run previews items one at a time, so their diffs do not interleave, and
+// prints the changes each one makes. It fails when any item fails.
+func (p *diffPreview) run(ctx context.Context, w io.Writer, items []planner.WorkItem) error {
+	workspace, err := os.MkdirTemp(p.tempDir, "cascade-diff-")
+	if err != nil {
+		return newExecutionError("failed to create the preview workspace", err)
+	}
+	defer os.RemoveAll(workspace)
+
+	failed := 0
+	for i, item := range items {
+		fmt.Fprintf(w, "\n%d. %s (%s) -> %s\n", i+1, item.Repo, item.Module, item.BranchName)
+		result, err := p.apply(ctx, item, workspace)
+		if err != nil || result.Status == execpkg.StatusFailed {
+			failed++
+		}
+		printDiffPreview(w, result, err)
+	}
+
+	if failed > 0 {
+		return newExecutionError(fmt.Sprintf("%d of %d updates failed to apply", failed, len(items)), nil).
+			WithHint("debug a single dependent with cascade try")
+	}
+	return nil
+}
+
+func (p *diffPreview) apply(ctx context.Context, item planner.WorkItem, workspace string) (*execpkg.Result, error) {
+	timeout := item.Timeout
+	if timeout <= 0 {
+		timeout = p.timeout
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return p.executor.Apply(ctx, execpkg.WorkItemContext{
+		Item:       item,
+		Workspace:  workspace,
+		Git:        p.git,
+		Go:         p.deps.goTool,
+		Runner:     p.deps.command,
+		Services:   p.deps.services,
+		Logger:     p.logger,
+		NeverTouch: p.neverTouch,
+		Preview:    true,
+	})
+}
+
+func printDiffPreview(w io.Writer, result *execpkg.Result, err error) {
+	switch {
+	case result == nil:
+		fmt.Fprintf(w, "   %s Failed: %v\n", style.mark(markFailed), err)
+		return
+	case err != nil || result.Status == execpkg.StatusFailed:
+		fmt.Fprintf(w, "   %s Failed: %s\n", style.mark(markFailed), result.Reason)
+		return
+	case result.Status == execpkg.StatusSkipped:
+		fmt.Fprintf(w, "   %s Skipped: %s\n", style.mark(markSkipped), result.Reason)
+		return
+	case result.Status == execpkg.StatusNoChange:
+		fmt.Fprintf(w, "   %s No change: %s\n", style.mark(markSkipped), result.Reason)
+		return
+	case result.Status == execpkg.StatusManualReview:
+		fmt.Fprintf(w, "   %s Manual review required: %s\n", style.mark(markReview), result.Reason)
+	}
+
+	if strings.TrimSpace(result.Diff) == "" {
+		fmt.Fprintf(w, "   %s No files changed\n", style.mark(markOK))
+		return
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, strings.TrimRight(result.Diff, "\n"))
+}
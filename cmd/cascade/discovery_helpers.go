@@ -3,20 +3,26 @@ package main
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
 	"net/http"
 	"path"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/goliatone/cascade/internal/broker"
 	"github.com/goliatone/cascade/internal/manifest"
 	"github.com/goliatone/cascade/pkg/config"
 	"github.com/goliatone/cascade/pkg/di"
+	"github.com/goliatone/cascade/pkg/githubx"
+	"github.com/goliatone/cascade/pkg/repometa"
 	gh "github.com/google/go-github/v66/github"
 	oauth2 "golang.org/x/oauth2"
 )
 
-func performMultiSourceDiscovery(ctx context.Context, targetModule, targetVersion, githubOrg, workspace string, maxDepth int,
+func performMultiSourceDiscovery(ctx context.Context, targetModule string, aliases []string, targetVersion, githubOrg, workspace string, maxDepth int,
 	includePatterns, excludePatterns, githubIncludePatterns, githubExcludePatterns []string,
 	cfg *config.Config, logger di.Logger) ([]manifest.DependentOptions, error) {
 
@@ -47,7 +53,7 @@ func performMultiSourceDiscovery(ctx context.Context, targetModule, targetVersio
 			logger.Info("Attempting GitHub discovery", "organization", finalGitHubOrg)
 		}
 
-		ghDeps, err := discoverGitHubDependents(ctx, targetModule, finalGitHubOrg,
+		ghDeps, err := discoverGitHubDependents(ctx, targetModule, aliases, finalGitHubOrg,
 			finalGitHubInclude, finalGitHubExclude, cfg, logger)
 		if err != nil {
 			discoveryErrors = append(discoveryErrors, fmt.Errorf("GitHub discovery failed: %w", err))
@@ -70,7 +76,7 @@ func performMultiSourceDiscovery(ctx context.Context, targetModule, targetVersio
 			logger.Info("Attempting workspace discovery", "workspace", workspaceDir)
 		}
 
-		wsDeps, err := discoverWorkspaceDependents(ctx, targetModule, targetVersion, workspaceDir, maxDepth,
+		wsDeps, err := discoverWorkspaceDependents(ctx, targetModule, aliases, targetVersion, workspaceDir, maxDepth,
 			includePatterns, excludePatterns, cfg, logger)
 		if err != nil {
 			discoveryErrors = append(discoveryErrors, fmt.Errorf("workspace discovery failed: %w", err))
@@ -153,6 +159,10 @@ func mergeConflictingDependents(existing, incoming manifest.DependentOptions, lo
 		merged.LocalModulePath = incoming.LocalModulePath
 	}
 
+	if len(incoming.Tests) > 0 && len(merged.Tests) == 0 {
+		merged.Tests = incoming.Tests
+	}
+
 	if incoming.DiscoverySource != "" {
 		merged.DiscoverySource = incoming.DiscoverySource
 	}
@@ -180,9 +190,9 @@ func resolveGitHubOrg(githubOrg string, cfg *config.Config) string {
 	return ""
 }
 
-func discoverWorkspaceDependents(ctx context.Context, targetModule, targetVersion, workspaceDir string, maxDepth int,
+func discoverWorkspaceDependents(ctx context.Context, targetModule string, aliases []string, targetVersion, workspaceDir string, maxDepth int,
 	includePatterns, excludePatterns []string, cfg *config.Config, logger di.Logger) ([]manifest.DependentOptions, error) {
-	discovery := manifest.NewWorkspaceDiscovery()
+	discovery := manifest.NewWorkspaceDiscovery(manifest.WithWorkspaceRepoURLs(config.RepoURLs(cfg)))
 
 	finalMaxDepth := maxDepth
 	if finalMaxDepth <= 0 {
@@ -202,12 +212,16 @@ func discoverWorkspaceDependents(ctx context.Context, targetModule, targetVersio
 	}
 
 	options := manifest.DiscoveryOptions{
-		WorkspaceDir:    workspaceDir,
-		TargetModule:    targetModule,
-		TargetVersion:   targetVersion,
-		MaxDepth:        finalMaxDepth,
-		IncludePatterns: finalIncludePatterns,
-		ExcludePatterns: finalExcludePatterns,
+		WorkspaceDir:         workspaceDir,
+		TargetModule:         targetModule,
+		TargetAliases:        aliases,
+		TargetVersion:        targetVersion,
+		MaxDepth:             finalMaxDepth,
+		Layout:               config.WorkspaceLayout(cfg),
+		IncludePatterns:      finalIncludePatterns,
+		ExcludePatterns:      finalExcludePatterns,
+		DetectTestCommands:   config.ManifestDetectTestCommands(cfg),
+		ResolveVanityImports: true,
 	}
 
 	dependents, err := discovery.DiscoverDependents(ctx, options)
@@ -226,12 +240,13 @@ func discoverWorkspaceDependents(ctx context.Context, targetModule, targetVersio
 	return dependents, nil
 }
 
-func discoverGitHubDependents(ctx context.Context, targetModule, organization string, includePatterns, excludePatterns []string, cfg *config.Config, logger di.Logger) ([]manifest.DependentOptions, error) {
+func discoverGitHubDependents(ctx context.Context, targetModule string, aliases []string, organization string, includePatterns, excludePatterns []string, cfg *config.Config, logger di.Logger) ([]manifest.DependentOptions, error) {
 	if cfg == nil {
 		return nil, fmt.Errorf("configuration required for GitHub discovery")
 	}
 
-	client, err := newGitHubClient(ctx, cfg)
+	cache := openGitHubDiscoveryCache(cfg, organization, targetModule, logger)
+	client, err := newCachedGitHubClient(ctx, cfg, cache)
 	if err != nil {
 		return nil, err
 	}
@@ -246,69 +261,328 @@ func discoverGitHubDependents(ctx context.Context, targetModule, organization st
 		finalExclude = cfg.ManifestGenerator.Discovery.GitHub.ExcludePatterns
 	}
 
-	return discoverGitHubDependentsWithClient(ctx, client, targetModule, organization, finalInclude, finalExclude, logger)
+	filter, err := manifest.ParseRepoFilter(cfg.ManifestGenerator.Discovery.GitHub.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var dependents []manifest.DependentOptions
+	switch {
+	case cfg.ManifestGenerator.Discovery.GitHub.GraphQL:
+		checkpointPath := githubScanCheckpointPath(cfg, organization)
+		dependents, err = scanGitHubOrgDependents(ctx, manifest.ScanGitHubOrgGraphQL, client, targetModule, aliases, organization, finalInclude, finalExclude, filter, checkpointPath, logger)
+		if errors.Is(err, manifest.ErrGitHubGraphQLUnavailable) {
+			if logger != nil {
+				logger.Warn("GitHub GraphQL API unavailable, scanning through REST", "organization", organization, "error", err)
+			}
+			dependents, err = scanGitHubOrgDependents(ctx, manifest.ScanGitHubOrg, client, targetModule, aliases, organization, finalInclude, finalExclude, filter, checkpointPath, logger)
+		}
+	case cfg.ManifestGenerator.Discovery.GitHub.FullScan:
+		dependents, err = scanGitHubOrgDependents(ctx, manifest.ScanGitHubOrg, client, targetModule, aliases, organization, finalInclude, finalExclude, filter, githubScanCheckpointPath(cfg, organization), logger)
+	default:
+		dependents, err = searchGitHubDependents(ctx, client, containerRepoMetadata(), targetModule, aliases, organization, finalInclude, finalExclude, filter, logger)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if config.ManifestDetectTestCommands(cfg) {
+		detectGitHubTestCommands(ctx, client, dependents, logger)
+	}
+	saveGitHubDiscoveryCache(cache, organization, targetModule, logger)
+
+	return dependents, nil
 }
 
-func discoverGitHubDependentsWithClient(ctx context.Context, client *gh.Client, targetModule, organization string, includePatterns, excludePatterns []string, logger di.Logger) ([]manifest.DependentOptions, error) {
-	if client == nil {
-		return nil, fmt.Errorf("github client is required")
+// githubDiscoveryCachePath returns the file caching the discovery of module's
+// dependents in organization: under the configured cache directory, or under
+// discovery-cache in the state directory. It is empty when neither is set.
+func githubDiscoveryCachePath(cfg *config.Config, organization, module string) string {
+	dir := strings.TrimSpace(cfg.ManifestGenerator.Discovery.GitHub.CacheDir)
+	if dir == "" {
+		if cfg.State.Dir == "" {
+			return ""
+		}
+		dir = filepath.Join(cfg.State.Dir, "discovery-cache")
+	}
+	return manifest.GitHubDiscoveryCachePath(dir, organization, module)
+}
+
+// openGitHubDiscoveryCache opens the discovery cache of module's dependents in
+// organization. It returns nil, and discovery runs uncached, when there is no
+// cache directory or the cache cannot be read.
+func openGitHubDiscoveryCache(cfg *config.Config, organization, module string, logger di.Logger) *manifest.GitHubDiscoveryCache {
+	path := githubDiscoveryCachePath(cfg, organization, module)
+	if path == "" {
+		return nil
+	}
+	cache, err := manifest.OpenGitHubDiscoveryCache(path, organization, module, cfg.ManifestGenerator.Discovery.GitHub.RefreshCache)
+	if err != nil {
+		if logger != nil {
+			logger.Warn("Discovering without the GitHub discovery cache", "path", path, "error", err)
+		}
+		return nil
 	}
+	return cache
+}
 
-	query := fmt.Sprintf("org:%s \"%s\" path:go.mod", organization, targetModule)
-	options := &gh.SearchOptions{ListOptions: gh.ListOptions{PerPage: 100}}
+// saveGitHubDiscoveryCache saves cache and logs how many requests it answered.
+func saveGitHubDiscoveryCache(cache *manifest.GitHubDiscoveryCache, organization, module string, logger di.Logger) {
+	if cache == nil {
+		return
+	}
+	if err := cache.Save(); err != nil {
+		if logger != nil {
+			logger.Warn("Failed to save the GitHub discovery cache", "error", err)
+		}
+		return
+	}
+	if logger != nil {
+		stats := cache.Stats()
+		logger.Info("GitHub discovery cache",
+			"organization", organization,
+			"module", module,
+			"unchanged", stats.Revalidated,
+			"fetched", stats.Fetched)
+	}
+}
 
-	dependents := make([]manifest.DependentOptions, 0)
-	fetchedRepos := make(map[string]struct{})
+// detectGitHubTestCommands proposes test commands for GitHub-discovered dependents by
+// reading their build tooling through the contents API.
+func detectGitHubTestCommands(ctx context.Context, client *gh.Client, dependents []manifest.DependentOptions, logger di.Logger) {
+	for i := range dependents {
+		if len(dependents[i].Tests) > 0 {
+			continue
+		}
 
-	for {
-		results, resp, err := client.Search.Code(ctx, query, options)
-		if err != nil {
-			return nil, fmt.Errorf("github code search failed: %w", err)
+		parts := strings.SplitN(dependents[i].Repository, "/", 2)
+		if len(parts) != 2 {
+			continue
 		}
 
-		for _, item := range results.CodeResults {
-			repo := item.GetRepository()
-			fullName := repo.GetFullName()
+		detected, ok := manifest.DetectTestCommand(gitHubFileReader(ctx, client, parts[0], parts[1], dependents[i].LocalModulePath))
+		if !ok {
+			continue
+		}
+		dependents[i].Tests = []manifest.Command{detected.Command}
+		if logger != nil {
+			logger.Debug("Detected dependent test command",
+				"repository", dependents[i].Repository,
+				"source", detected.Source,
+				"file", detected.File,
+				"command", strings.Join(detected.Command.Cmd, " "))
+		}
+	}
+}
 
-			if !matchesRepoPatterns(fullName, includePatterns, excludePatterns) {
-				continue
-			}
+// gitHubFileReader reads files from a repository's default branch. The module
+// directory is listed once so absent candidates do not cost an API request each.
+func gitHubFileReader(ctx context.Context, client *gh.Client, owner, repo, dir string) manifest.FileReader {
+	dir = strings.Trim(path.Clean("/"+dir), "/")
+	listings := make(map[string]map[string]bool)
 
-			modulePath, localModulePath, err := fetchModuleInfoFromGitHub(ctx, client, repo, item.GetPath())
-			if err != nil {
-				if logger != nil {
-					logger.Warn("Failed to fetch module info from GitHub",
-						"repository", fullName,
-						"path", item.GetPath(),
-						"error", err)
+	exists := func(filePath string) bool {
+		parent := path.Dir(filePath)
+		if parent == "." {
+			parent = ""
+		}
+		entries, ok := listings[parent]
+		if !ok {
+			entries = make(map[string]bool)
+			_, contents, _, err := client.Repositories.GetContents(ctx, owner, repo, parent, nil)
+			if err == nil {
+				for _, entry := range contents {
+					entries[entry.GetName()] = true
 				}
-				continue
 			}
+			listings[parent] = entries
+		}
+		return entries[path.Base(filePath)]
+	}
 
-			key := fmt.Sprintf("%s|%s|%s", fullName, modulePath, localModulePath)
-			if _, exists := fetchedRepos[key]; exists {
-				continue
-			}
-			fetchedRepos[key] = struct{}{}
+	return func(name string) ([]byte, error) {
+		filePath := path.Join(dir, name)
+		if !exists(filePath) {
+			return nil, fs.ErrNotExist
+		}
 
-			dependents = append(dependents, manifest.DependentOptions{
-				Repository:      fullName,
-				ModulePath:      modulePath,
-				LocalModulePath: localModulePath,
-				DiscoverySource: "github",
-			})
+		file, _, _, err := client.Repositories.GetContents(ctx, owner, repo, filePath, nil)
+		if err != nil {
+			return nil, err
+		}
+		content, err := file.GetContent()
+		if err != nil {
+			return nil, err
 		}
+		return []byte(content), nil
+	}
+}
+
+// containerRepoMetadata returns the shared repository metadata cache, if any.
+func containerRepoMetadata() *repometa.Service {
+	if container == nil {
+		return nil
+	}
+	return container.RepoMetadata()
+}
+
+// discoverGitHubDependentsWithClient searches the organization's go.mod files for the
+// target module path and each of its aliases, reporting every repository once.
+// Archived repositories are left out when metadata is available.
+func discoverGitHubDependentsWithClient(ctx context.Context, client *gh.Client, metadata *repometa.Service, targetModule string, aliases []string, organization string, includePatterns, excludePatterns []string, logger di.Logger) ([]manifest.DependentOptions, error) {
+	return searchGitHubDependents(ctx, client, metadata, targetModule, aliases, organization, includePatterns, excludePatterns, manifest.RepoFilter{}, logger)
+}
+
+// searchGitHubDependents is discoverGitHubDependentsWithClient narrowed by a
+// repository filter. Code search cannot filter on repository attributes, so the
+// filter is checked against each result's metadata; with a filter set, results
+// whose metadata cannot be loaded are left out.
+func searchGitHubDependents(ctx context.Context, client *gh.Client, metadata *repometa.Service, targetModule string, aliases []string, organization string, includePatterns, excludePatterns []string, filter manifest.RepoFilter, logger di.Logger) ([]manifest.DependentOptions, error) {
+	if client == nil {
+		return nil, fmt.Errorf("github client is required")
+	}
+
+	dependents := make([]manifest.DependentOptions, 0)
+	fetchedRepos := make(map[string]struct{})
+
+	for _, targetPath := range manifest.ModulePaths(targetModule, aliases) {
+		query := fmt.Sprintf("org:%s \"%s\" path:go.mod", organization, targetPath)
+		options := &gh.SearchOptions{ListOptions: gh.ListOptions{PerPage: 100}}
+
+		for {
+			results, resp, err := client.Search.Code(ctx, query, options)
+			if err != nil {
+				return nil, fmt.Errorf("github code search failed: %w", err)
+			}
 
-		if resp.NextPage == 0 {
-			break
+			for _, item := range results.CodeResults {
+				repo := item.GetRepository()
+				fullName := repo.GetFullName()
+
+				if !matchesRepoPatterns(fullName, includePatterns, excludePatterns) {
+					continue
+				}
+				if metadata != nil {
+					// Without a filter, lookup failures keep the repository; only a
+					// confirmed archive drops it
+					meta, err := metadata.Get(ctx, fullName)
+					if err == nil && !filter.Matches(*meta) {
+						if logger != nil {
+							logger.Debug("Skipping repository excluded by filter", "repository", fullName, "archived", meta.Archived, "filter", filter.String())
+						}
+						continue
+					}
+					if err != nil && !filter.IsZero() {
+						if logger != nil {
+							logger.Warn("Skipping repository whose metadata could not be loaded for the filter", "repository", fullName, "error", err)
+						}
+						continue
+					}
+				} else if !filter.IsZero() && !filter.Matches(repometa.FromGitHubRepository(repo)) {
+					continue
+				}
+
+				modulePath, localModulePath, err := fetchModuleInfoFromGitHub(ctx, client, repo, item.GetPath())
+				if err != nil {
+					if logger != nil {
+						logger.Warn("Failed to fetch module info from GitHub",
+							"repository", fullName,
+							"path", item.GetPath(),
+							"error", err)
+					}
+					continue
+				}
+
+				key := fmt.Sprintf("%s|%s|%s", fullName, modulePath, localModulePath)
+				if _, exists := fetchedRepos[key]; exists {
+					continue
+				}
+				fetchedRepos[key] = struct{}{}
+
+				dependents = append(dependents, manifest.DependentOptions{
+					Repository:      fullName,
+					ModulePath:      modulePath,
+					LocalModulePath: localModulePath,
+					DiscoverySource: "github",
+				})
+			}
+
+			if resp.NextPage == 0 {
+				break
+			}
+			options.Page = resp.NextPage
 		}
-		options.Page = resp.NextPage
 	}
 
 	return dependents, nil
 }
 
+// githubScanCheckpointPath returns the checkpoint file of a full scan of the
+// organization: the configured path, or one in the state directory.
+func githubScanCheckpointPath(cfg *config.Config, organization string) string {
+	if path := strings.TrimSpace(cfg.ManifestGenerator.Discovery.GitHub.ScanCheckpoint); path != "" {
+		return path
+	}
+	if cfg.State.Dir == "" {
+		return ""
+	}
+	return filepath.Join(cfg.State.Dir, "github-scan", organization+".json")
+}
+
+const githubScanLogInterval = 100
+
+// scanGitHubOrgDependents enumerates every repository of the organization rather
+// than searching code, for organizations whose dependents exceed the search
+// result cap. Progress is logged every githubScanLogInterval repositories.
+// The listing is narrowed to the filter's visibility on the server and the rest of
+// the filter is checked against each listed repository. scan is
+// manifest.ScanGitHubOrg or manifest.ScanGitHubOrgGraphQL.
+func scanGitHubOrgDependents(ctx context.Context, scan func(context.Context, *gh.Client, manifest.GitHubOrgScanOptions) ([]manifest.DependentOptions, error), client *gh.Client, targetModule string, aliases []string, organization string, includePatterns, excludePatterns []string, filter manifest.RepoFilter, checkpointPath string, logger di.Logger) ([]manifest.DependentOptions, error) {
+	visibility := ""
+	if filter.Visibility == repometa.VisibilityPublic || filter.Visibility == repometa.VisibilityPrivate {
+		visibility = filter.Visibility
+	}
+	return scan(ctx, client, manifest.GitHubOrgScanOptions{
+		Organization:   organization,
+		TargetModule:   targetModule,
+		TargetAliases:  aliases,
+		Visibility:     visibility,
+		CheckpointPath: checkpointPath,
+		Include: func(repo *gh.Repository) bool {
+			return matchesRepoPatterns(repo.GetFullName(), includePatterns, excludePatterns) &&
+				filter.Matches(repometa.FromGitHubRepository(repo))
+		},
+		Progress: func(p manifest.GitHubOrgScanProgress) {
+			if logger == nil {
+				return
+			}
+			switch {
+			case p.Resumed:
+				logger.Info("Resuming GitHub organization scan from checkpoint",
+					"organization", organization, "scanned", p.Scanned, "dependents", p.Dependents, "checkpoint", checkpointPath)
+			case !p.WaitUntil.IsZero():
+				logger.Warn("GitHub rate limit reached, waiting for reset",
+					"organization", organization, "scanned", p.Scanned, "resume_at", p.WaitUntil.Format(time.RFC3339))
+			case p.Scanned%githubScanLogInterval == 0:
+				logger.Info("Scanning GitHub organization",
+					"organization", organization, "scanned", p.Scanned, "dependents", p.Dependents)
+			}
+		},
+		Found: func(dep manifest.DependentOptions) {
+			if logger != nil {
+				logger.Debug("Found dependent repository", "repository", dep.Repository, "module", dep.ModulePath)
+			}
+		},
+	})
+}
+
 func newGitHubClient(ctx context.Context, cfg *config.Config) (*gh.Client, error) {
+	return newCachedGitHubClient(ctx, cfg, nil)
+}
+
+// newCachedGitHubClient is newGitHubClient sending its requests through cache,
+// when one is given.
+func newCachedGitHubClient(ctx context.Context, cfg *config.Config, cache *manifest.GitHubDiscoveryCache) (*gh.Client, error) {
 	if cfg == nil {
 		return nil, fmt.Errorf("configuration required for GitHub discovery")
 	}
@@ -325,7 +599,15 @@ func newGitHubClient(ctx context.Context, cfg *config.Config) (*gh.Client, error
 		}
 	}
 
+	var logger di.Logger
+	if container != nil {
+		logger = container.Logger()
+	}
 	httpClient := oauth2.NewClient(ctx, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}))
+	httpClient = githubx.WrapClient(httpClient, di.GitHubRateLimitOptions(cfg, logger)...)
+	if cache != nil {
+		httpClient.Transport = cache.Transport(httpClient.Transport)
+	}
 
 	endpoint := strings.TrimSpace(cfg.Integration.GitHub.Endpoint)
 	if endpoint == "" {
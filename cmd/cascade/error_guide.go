@@ -0,0 +1,423 @@
+package main
+
+import (
+	"strings"
+
+	execpkg "github.com/goliatone/cascade/internal/executor"
+)
+
+// errorGuide explains one exit code, item status, or failure reason: what it
+// means, what usually causes it, and what to do next.
+type errorGuide struct {
+	Topic     string   `json:"topic"`
+	Summary   string   `json:"summary"`
+	Causes    []string `json:"causes,omitempty"`
+	NextSteps []string `json:"next_steps,omitempty"`
+}
+
+// exitCodeGuides covers every exit code the CLI errors in cli_err.go map to.
+var exitCodeGuides = map[int]errorGuide{
+	ExitSuccess: {
+		Topic:   "exit code 0 (success)",
+		Summary: "The command finished. Items that failed or need review are reported in the output and in state, not in the exit code, unless the command says otherwise.",
+		NextSteps: []string{
+			"Run `cascade status` or `cascade state show module@version` to review item outcomes.",
+		},
+	},
+	ExitGenericError: {
+		Topic:   "exit code 1 (generic error)",
+		Summary: "An error that cascade could not classify, often from a third-party library or an unexpected condition.",
+		Causes: []string{
+			"An unexpected response from git, the go tool, or a provider API.",
+			"A bug in cascade.",
+		},
+		NextSteps: []string{
+			"Re-run with --log-level=debug to see the failing call.",
+			"Report the error with the debug output if it persists.",
+		},
+	},
+	ExitConfigError: {
+		Topic:   "exit code 2 (configuration error)",
+		Summary: "The configuration from flags, environment variables, or the config file is invalid or incomplete.",
+		Causes: []string{
+			"A required credential, such as the GitHub token, is not set.",
+			"A config file value has the wrong type or an unknown name.",
+			"A checked environment prerequisite failed, as reported by `cascade doctor`.",
+		},
+		NextSteps: []string{
+			"Run `cascade doctor` to check tools, credentials, and directories.",
+			"Check the field named in the error against the README configuration sections.",
+		},
+	},
+	ExitValidationError: {
+		Topic:   "exit code 3 (validation error)",
+		Summary: "An argument, flag, or input file did not pass validation.",
+		Causes: []string{
+			"A missing or malformed --module, --version, or module@version argument.",
+			"A manifest that fails schema validation.",
+			"`cascade plan verify` found a plan that differs from the golden plan.",
+		},
+		NextSteps: []string{
+			"Run the command with --help to check its arguments.",
+			"Run `cascade manifest validate` for line and column diagnostics.",
+		},
+	},
+	ExitNetworkError: {
+		Topic:   "exit code 4 (network error)",
+		Summary: "A remote service could not be reached or answered with an error.",
+		Causes: []string{
+			"No network access, a proxy, or a firewall blocking the host.",
+			"An exhausted GitHub API rate limit.",
+			"A module proxy that has not indexed the version yet.",
+		},
+		NextSteps: []string{
+			"Retry once connectivity is back; transient failures are retried per the retry settings.",
+			"Check the rate limit with `gh api rate_limit`.",
+		},
+	},
+	ExitFileError: {
+		Topic:   "exit code 5 (file error)",
+		Summary: "A file cascade needs could not be read or written.",
+		Causes: []string{
+			"The manifest path does not exist; the default is .cascade.yaml in the current directory.",
+			"Permissions prevent writing to the workspace or an output file.",
+		},
+		NextSteps: []string{
+			"Pass --manifest with the right path, or generate one with `cascade manifest generate`.",
+			"Check the ownership and permissions of the path in the error.",
+		},
+	},
+	ExitStateError: {
+		Topic:   "exit code 6 (state error)",
+		Summary: "Recorded state could not be loaded, saved, or locked.",
+		Causes: []string{
+			"No state was recorded for the module@version given.",
+			"Another release of the same module@version holds the run lock.",
+			"The state directory or bucket is not writable.",
+		},
+		NextSteps: []string{
+			"List recorded cascades with `cascade status`.",
+			"Wait for the other run, or pass --wait-for-lock to queue behind it.",
+			"Run `cascade doctor` to check the state directory.",
+		},
+	},
+	ExitPlanningError: {
+		Topic:   "exit code 7 (planning error)",
+		Summary: "The work items could not be planned from the manifest.",
+		Causes: []string{
+			"The module is missing from the manifest, or has no dependents left after filtering.",
+			"A dependent is listed in defaults.never_touch.",
+			"A saved plan expired and revalidation failed.",
+		},
+		NextSteps: []string{
+			"Run `cascade plan --explain` to see why each dependent was included or skipped.",
+			"Check the manifest entry for the module and its dependents.",
+		},
+	},
+	ExitExecutionError: {
+		Topic:   "exit code 8 (execution error)",
+		Summary: "One or more work items failed while updating dependents, or a step before them, such as the proxy preflight, failed.",
+		Causes: []string{
+			"Tests or extra commands failed in a dependent.",
+			"go get or go mod tidy could not resolve the new version.",
+			"A push or pull request was rejected by the provider.",
+		},
+		NextSteps: []string{
+			"Run `cascade state show module@version` and explain a failed item's reason with `cascade explain-error --state module@version --repo owner/repo`.",
+			"Fix the cause and run `cascade resume module@version --retry-failed`.",
+		},
+	},
+	ExitInterruptError: {
+		Topic:   "exit code 9 (interrupted)",
+		Summary: "The run was stopped by SIGINT or SIGTERM before it finished.",
+		Causes: []string{
+			"Ctrl-C, a CI job timeout, or the runner being shut down.",
+		},
+		NextSteps: []string{
+			"Continue where it stopped with `cascade resume module@version`.",
+		},
+	},
+	ExitResourceError: {
+		Topic:   "exit code 10 (resource exhaustion)",
+		Summary: "The machine ran out of a resource, such as disk space or memory, or a configured limit was reached.",
+		Causes: []string{
+			"The workspace filled the disk with clones and module caches.",
+			"Too many items ran at once for the available memory.",
+		},
+		NextSteps: []string{
+			"Free disk space or move the workspace, then run `cascade doctor`.",
+			"Lower executor.concurrent_limit or the resource limits.",
+		},
+	},
+}
+
+// statusGuides covers every item status recorded in state.
+var statusGuides = map[execpkg.Status]errorGuide{
+	execpkg.StatusCompleted: {
+		Topic:   "status completed",
+		Summary: "The dependent was updated, its tests passed, and a pull request was opened or updated.",
+		NextSteps: []string{
+			"Review and merge the pull request.",
+		},
+	},
+	execpkg.StatusNoChange: {
+		Topic:   "status no-change",
+		Summary: "The update left go.mod and go.sum unchanged, so there was nothing to commit.",
+		Causes: []string{
+			"The dependent already required the target version.",
+			"A submodule already pointed at the tag.",
+		},
+		NextSteps: []string{
+			"Nothing to do. `cascade resume` does not rerun these items.",
+		},
+	},
+	execpkg.StatusManualReview: {
+		Topic:   "status manual-review",
+		Summary: "The update and its tests passed, but something needs a person to look at it before merging.",
+		Causes: []string{
+			"Extra commands failed after the tests passed.",
+		},
+		NextSteps: []string{
+			"Read the command logs with `cascade state show module@version --format=json`.",
+			"Review the pull request before merging it.",
+		},
+	},
+	execpkg.StatusSkipped: {
+		Topic:   "status skipped",
+		Summary: "The dependent was deliberately left alone.",
+		Causes: []string{
+			"The dependent has skip: true in the manifest or in its own .cascade.yaml.",
+		},
+		NextSteps: []string{
+			"Remove the skip setting if the dependent should be updated.",
+		},
+	},
+	execpkg.StatusFailed: {
+		Topic:   "status failed",
+		Summary: "The update of the dependent did not finish; the reason names the step that failed.",
+		NextSteps: []string{
+			"Explain the recorded reason with `cascade explain-error --state module@version --repo owner/repo`.",
+			"Fix the cause and run `cascade resume module@version --retry-failed`.",
+		},
+	},
+}
+
+// reasonGuide explains failure reasons that contain match.
+type reasonGuide struct {
+	match string
+	guide errorGuide
+}
+
+// reasonGuides covers the reasons the executor and the release flow record
+// for items. More specific matches come first.
+var reasonGuides = []reasonGuide{
+	{match: "timed out or was canceled", guide: errorGuide{
+		Topic:   "step timed out or was canceled",
+		Summary: "A step did not finish within the item's timeout, or the run was interrupted.",
+		Causes: []string{
+			"Slow tests, a large clone, or a hanging command.",
+			"The run was stopped with SIGINT or SIGTERM.",
+		},
+		NextSteps: []string{
+			"Raise the dependent's timeout or executor.timeout.",
+			"Run `cascade resume module@version --retry-failed`.",
+		},
+	}},
+	{match: "waiting for an executor slot", guide: errorGuide{
+		Topic:   "waiting for an executor slot",
+		Summary: "The run was canceled while the item waited for a free executor slot.",
+		NextSteps: []string{
+			"Run `cascade resume module@version` to run the items that did not start.",
+		},
+	}},
+	{match: "defaults.never_touch", guide: errorGuide{
+		Topic:   "repository listed in defaults.never_touch",
+		Summary: "Cascade refused to change a repository the manifest forbids it to touch.",
+		NextSteps: []string{
+			"Remove the dependent from the manifest or mark it skip: true.",
+			"Remove the entry from defaults.never_touch if the repository may now be changed.",
+		},
+	}},
+	{match: "validation failed", guide: errorGuide{
+		Topic:   "work item validation failed",
+		Summary: "The work item was incomplete, so nothing was cloned.",
+		Causes: []string{
+			"A saved plan or state recorded before a manifest change lacks a required field.",
+		},
+		NextSteps: []string{
+			"Plan again with `cascade plan` and release from the new plan.",
+		},
+	}},
+	{match: "git clone failed", guide: errorGuide{
+		Topic:   "git clone failed",
+		Summary: "The dependent repository could not be cloned or fetched.",
+		Causes: []string{
+			"The token lacks access to the repository, or the repository was renamed or deleted.",
+			"The clone URL or repo in the manifest is wrong.",
+		},
+		NextSteps: []string{
+			"Run `cascade doctor` to check the GitHub token and its scopes.",
+			"Check repo and clone_url of the dependent in the manifest.",
+		},
+	}},
+	{match: "git worktree failed", guide: errorGuide{
+		Topic:   "git worktree failed",
+		Summary: "A worktree for the update branch could not be created in the workspace.",
+		Causes: []string{
+			"A stale worktree left by a crashed run.",
+			"The base branch does not exist in the dependent.",
+		},
+		NextSteps: []string{
+			"Run `git worktree prune` in the dependent's workspace clone.",
+			"Check the dependent's branch in the manifest.",
+		},
+	}},
+	{match: "go environment failed", guide: errorGuide{
+		Topic:   "go environment failed",
+		Summary: "The environment for the dependent's go commands could not be prepared.",
+		Causes: []string{
+			"A credential's password_env variable is not set.",
+			"An env value template does not render.",
+		},
+		NextSteps: []string{
+			"Export the variables named in credentials, or fix the env templates of the dependent.",
+		},
+	}},
+	{match: "service startup failed", guide: errorGuide{
+		Topic:   "service startup failed",
+		Summary: "A test service declared for the dependent did not start or become ready.",
+		NextSteps: []string{
+			"Check that Docker is available and the service image and readiness check are correct.",
+		},
+	}},
+	{match: "dependency update failed", guide: errorGuide{
+		Topic:   "dependency update failed",
+		Summary: "go get could not move the dependent to the target version.",
+		Causes: []string{
+			"The version is not published yet, or the module proxy has not indexed it.",
+			"The module is private and GOPRIVATE or the dependent's credentials are missing.",
+			"The new version conflicts with other requirements of the dependent.",
+		},
+		NextSteps: []string{
+			"Check the version with `go list -m module@version`.",
+			"Set integration.goproxy.private or the dependent's credentials for private modules.",
+		},
+	}},
+	{match: "go mod tidy failed", guide: errorGuide{
+		Topic:   "go mod tidy failed",
+		Summary: "go mod tidy failed after the update, usually because the dependent no longer builds.",
+		Causes: []string{
+			"The new version removed or renamed packages the dependent imports.",
+			"A private module needed by the dependent could not be fetched.",
+		},
+		NextSteps: []string{
+			"Run go mod tidy in the dependent on the update branch to see the full error.",
+		},
+	}},
+	{match: "tests passed but extra commands failed", guide: errorGuide{
+		Topic:   "extra commands failed",
+		Summary: "The tests passed, but an extra command such as a linter or code generator failed, so the item needs review.",
+		NextSteps: []string{
+			"Read the command logs with `cascade state show module@version --format=json`.",
+		},
+	}},
+	{match: "execution failed", guide: errorGuide{
+		Topic:   "tests failed",
+		Summary: "The dependent's tests or extra commands failed against the new version.",
+		Causes: []string{
+			"A breaking change in the released module.",
+			"Flaky tests, or tests that need services or credentials the runner lacks.",
+		},
+		NextSteps: []string{
+			"Read the command logs with `cascade state show module@version --format=json`.",
+			"Reproduce with `cascade try owner/repo`.",
+		},
+	}},
+	{match: "git commit failed", guide: errorGuide{
+		Topic:   "git commit failed",
+		Summary: "The update could not be committed.",
+		Causes: []string{
+			"Commit signing is required but not configured on the runner.",
+			"A pre-commit hook in the dependent failed.",
+		},
+		NextSteps: []string{
+			"Configure git user and signing on the runner, or adjust the hook.",
+		},
+	}},
+	{match: "git push failed", guide: errorGuide{
+		Topic:   "git push failed",
+		Summary: "The update branch could not be pushed.",
+		Causes: []string{
+			"The token lacks write access to the repository.",
+			"Branch protection rules reject the branch name.",
+		},
+		NextSteps: []string{
+			"Run `cascade doctor` to check the token's scopes.",
+		},
+	}},
+	{match: "pr creation failed", guide: errorGuide{
+		Topic:   "pull request creation failed",
+		Summary: "The branch was pushed, but the pull request could not be opened or updated.",
+		Causes: []string{
+			"The token lacks pull request permissions.",
+			"Labels, reviewers, or team reviewers in the manifest do not exist.",
+		},
+		NextSteps: []string{
+			"Fix the PR settings and run `cascade resume module@version`; the push is not repeated.",
+		},
+	}},
+	{match: "notification failed", guide: errorGuide{
+		Topic:   "notification failed",
+		Summary: "The item finished, but its Slack, webhook, PagerDuty, or issue notification was not sent.",
+		NextSteps: []string{
+			"Run `cascade doctor` to check the Slack credentials, then resume to send it again.",
+		},
+	}},
+	{match: "invalidated:", guide: errorGuide{
+		Topic:   "invalidated by plan revalidation",
+		Summary: "A saved plan expired, and revalidation found the item no longer applies.",
+		NextSteps: []string{
+			"Plan again with `cascade plan --output` and release from the new plan.",
+		},
+	}},
+	{match: "executor returned no result", guide: errorGuide{
+		Topic:   "executor returned no result",
+		Summary: "The executor stopped without reporting an outcome.",
+		NextSteps: []string{
+			"Re-run with --log-level=debug and report the output.",
+		},
+	}},
+}
+
+// explainExitCode returns the guide for an exit code.
+func explainExitCode(code int) (errorGuide, bool) {
+	guide, ok := exitCodeGuides[code]
+	return guide, ok
+}
+
+// explainStatus returns the guide for an item status.
+func explainStatus(status string) (errorGuide, bool) {
+	guide, ok := statusGuides[execpkg.Status(strings.ToLower(strings.TrimSpace(status)))]
+	return guide, ok
+}
+
+// explainReason returns the guides matching each part of a reason. Reasons
+// joined with "; " by the release flow are explained part by part.
+func explainReason(reason string) []errorGuide {
+	var guides []errorGuide
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(reason, "; ") {
+		part = strings.ToLower(part)
+		for _, candidate := range reasonGuides {
+			if !strings.Contains(part, candidate.match) {
+				continue
+			}
+			if !seen[candidate.guide.Topic] {
+				seen[candidate.guide.Topic] = true
+				guides = append(guides, candidate.guide)
+			}
+			break
+		}
+	}
+	return guides
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	execpkg "github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/internal/state"
+	"github.com/goliatone/cascade/pkg/config"
+	"github.com/goliatone/cascade/pkg/di"
+)
+
+func TestErrorGuidesCoverTaxonomy(t *testing.T) {
+	for code := ExitSuccess; code <= ExitResourceError; code++ {
+		if _, ok := explainExitCode(code); !ok {
+			t.Errorf("exit code %d has no explanation", code)
+		}
+	}
+	for _, status := range []execpkg.Status{execpkg.StatusCompleted, execpkg.StatusManualReview, execpkg.StatusFailed, execpkg.StatusSkipped, execpkg.StatusNoChange} {
+		if _, ok := explainStatus(string(status)); !ok {
+			t.Errorf("status %s has no explanation", status)
+		}
+	}
+}
+
+func TestExplainReason(t *testing.T) {
+	tests := []struct {
+		reason string
+		want   []string
+	}{
+		{reason: "git clone failed: executor: git clone failed for acme/app: authentication required", want: []string{"git clone failed"}},
+		{reason: "test execution failed: executor: command [go test ./...] failed in /tmp/w (exit 1): exit status 1", want: []string{"tests failed"}},
+		{reason: "dependency update failed: executor: go operation failed for github.com/acme/testkit@v1.2.0: not found", want: []string{"dependency update failed"}},
+		{reason: "go mod tidy timed out or was canceled: context deadline exceeded", want: []string{"step timed out or was canceled"}},
+		{reason: "tests passed but extra commands failed: lint", want: []string{"extra commands failed"}},
+		{reason: "work item executed successfully; PR creation failed: 403; notification failed: slack", want: []string{"pull request creation failed", "notification failed"}},
+		{reason: "manifest: acme/vault is listed in defaults.never_touch (acme/vault) and must not be changed", want: []string{"repository listed in defaults.never_touch"}},
+		{reason: "something nobody has seen before", want: nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, guide := range explainReason(tt.reason) {
+			got = append(got, guide.Topic)
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("explainReason(%q) = %v, want %v", tt.reason, got, tt.want)
+		}
+	}
+}
+
+func TestRunExplainError(t *testing.T) {
+	var buf bytes.Buffer
+	if err := runExplainError(&buf, []string{"6"}, "", "", false); err != nil {
+		t.Fatalf("runExplainError() error = %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{"exit code 6 (state error)", "Common causes:", "Next steps:", "--wait-for-lock"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	if err := runExplainError(&buf, []string{"git", "push", "failed:", "denied"}, "", "", true); err != nil {
+		t.Fatalf("runExplainError() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), `"topic": "git push failed"`) {
+		t.Errorf("JSON output missing the git push explanation:\n%s", buf.String())
+	}
+
+	for _, args := range [][]string{{"42"}, {"no idea"}, nil} {
+		err := runExplainError(&buf, args, "", "", false)
+		if cliErr := asCLIError(err); cliErr == nil || cliErr.Code != ExitValidationError {
+			t.Errorf("runExplainError(%v) error = %v, want a validation error", args, err)
+		}
+	}
+}
+
+func TestRunExplainErrorRecordedItem(t *testing.T) {
+	manager := newIssueTrackingManager(t)
+	item := state.ItemState{Repo: "example/b", Branch: "update", Status: execpkg.StatusFailed, Reason: "git push failed: permission denied"}
+	if err := manager.SaveItemState("example.com/lib", "v1.2.0", item); err != nil {
+		t.Fatal(err)
+	}
+
+	testContainer, err := di.New(di.WithConfig(config.New()), di.WithLogger(&mockLogger{}), di.WithStateManager(manager))
+	if err != nil {
+		t.Fatalf("di.New() error = %v", err)
+	}
+	originalContainer := container
+	container = testContainer
+	defer func() { container = originalContainer }()
+
+	var out bytes.Buffer
+	if err := runExplainError(&out, nil, "example.com/lib@v1.2.0", "example/b", false); err != nil {
+		t.Fatalf("runExplainError() error = %v", err)
+	}
+	for _, want := range []string{"example/b in example.com/lib@v1.2.0: failed", "reason: git push failed", "status failed", "git push failed\n"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	err = runExplainError(&out, nil, "example.com/lib@v1.2.0", "example/unknown", false)
+	if cliErr := asCLIError(err); cliErr == nil || cliErr.Code != ExitStateError {
+		t.Errorf("unknown repo error = %v, want a state error", err)
+	}
+}
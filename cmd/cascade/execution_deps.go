@@ -4,12 +4,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/goliatone/cascade/internal/broker"
 	execpkg "github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/internal/manifest"
 	"github.com/goliatone/cascade/internal/planner"
 	"github.com/goliatone/cascade/internal/state"
+	"github.com/goliatone/cascade/internal/telemetry"
+	"github.com/goliatone/cascade/pkg/config"
 	"github.com/goliatone/cascade/pkg/di"
 )
 
@@ -19,55 +24,288 @@ type executionDeps struct {
 	gitRunner execpkg.GitCommandRunner
 	goTool    execpkg.GoOperations
 	command   execpkg.CommandRunner
+	services  execpkg.ServiceManager
+
+	// approval, when set, asks for each work item to be approved before it runs
+	approval *approvalGate
+
+	// neverTouch lists the manifest's defaults.never_touch repositories, which
+	// the executor refuses to change
+	neverTouch []string
+
+	// localTarget, when set, is the checkout the tests of canaries build against
+	localTarget *localTarget
 }
 
-func newExecutionDeps() executionDeps {
+func newExecutionDeps(cfg *config.Config) executionDeps {
 	gitRunner := execpkg.NewDefaultGitCommandRunner()
+	var git execpkg.GitOperations = execpkg.NewGitOperationsWithRunner(gitRunner, execpkg.WithWorkspaceLayout(config.WorkspaceLayout(cfg)))
+	if cfg != nil && cfg.Integration.Provider == config.ProviderSandbox {
+		git = unpushedGit{GitOperations: git}
+	}
 	return executionDeps{
-		git:       execpkg.NewGitOperationsWithRunner(gitRunner),
+		git:       git,
 		gitRunner: gitRunner,
 		goTool:    execpkg.NewGoOperations(),
-		command:   execpkg.NewCommandRunner(),
+		command:   execpkg.NewCommandRunner(execpkg.WithResourceLimits(executorLimits(cfg))),
+		services:  execpkg.NewDockerServiceManager(),
 	}
 }
 
-// processWorkItem executes a single work item and coordinates broker/state integration.
-func processWorkItem(ctx context.Context, deps executionDeps, workspace string, item planner.WorkItem, executor execpkg.Executor, broker broker.Broker, logger di.Logger, defaultTimeout time.Duration) (state.ItemState, error) {
-	itemCopy := item
-	if itemCopy.Timeout <= 0 {
-		itemCopy.Timeout = defaultTimeout
-	}
-
-	workCtx := ctx
-	var cancel context.CancelFunc
-	if itemCopy.Timeout > 0 {
-		workCtx, cancel = context.WithTimeout(ctx, itemCopy.Timeout)
-		defer cancel()
-	}
-
-	result, execErr := executor.Apply(workCtx, execpkg.WorkItemContext{
-		Item:      itemCopy,
-		Workspace: workspace,
-		Git:       deps.git,
-		Go:        deps.goTool,
-		Runner:    deps.command,
-		Logger:    logger,
+// unpushedGit runs git operations without pushing. It keeps try --no-pr and
+// sandbox pull requests from needing push access: the update stays committed in
+// the workspace only.
+type unpushedGit struct {
+	execpkg.GitOperations
+}
+
+func (unpushedGit) Push(ctx context.Context, repoPath, branch string) error {
+	return nil
+}
+
+// itemGit returns the git operations for item, which do not push when the
+// item's pull request is recorded in the sandbox.
+func (d executionDeps) itemGit(item planner.WorkItem) execpkg.GitOperations {
+	if strings.EqualFold(item.Provider, broker.ProviderSandbox) {
+		if _, ok := d.git.(unpushedGit); !ok {
+			return unpushedGit{GitOperations: d.git}
+		}
+	}
+	return d.git
+}
+
+// executorLimits converts the configured per work item resource limits for the
+// command runner. The memory quantity has already been validated when the
+// configuration was loaded, so an unparsable value is treated as unset.
+func executorLimits(cfg *config.Config) execpkg.ResourceLimits {
+	if cfg == nil || cfg.Executor.Limits.IsZero() {
+		return execpkg.ResourceLimits{}
+	}
+	memory, _ := cfg.Executor.Limits.MemoryBytes()
+	return execpkg.ResourceLimits{
+		CPU:         cfg.Executor.Limits.CPU,
+		MemoryBytes: memory,
+		Backend:     execpkg.LimitBackend(cfg.Executor.Limits.Backend),
+	}
+}
+
+// runWorkItems processes items through the executor scheduler, running at most the
+// configured concurrent limit at once and items that share a concurrency group or a
+// repository one after another. Each result is recorded with tracker and then passed to report;
+// both calls are serialised, so report may print without interleaving. Items the
+// tracker resumes continue from their first incomplete phase, failure issues the
+// tracker knows about are closed once their item succeeds, and notifications carry
+// the attempt count the tracker records so repeated failures escalate. When
+// approval is per work item, items wait for approval before taking a slot, and
+// items that are not approved are recorded as skipped.
+func runWorkItems(ctx context.Context, cfg *config.Config, deps executionDeps, items []planner.WorkItem, executor execpkg.Executor, broker broker.Broker, logger di.Logger, tracker *stateTracker, report func(index int, item planner.WorkItem, itemState state.ItemState, err error)) {
+	var mu sync.Mutex
+	// Items queue from when they are approved, so the wait for approval does
+	// not count as queue wait.
+	queued := make([]time.Time, len(items))
+	start := time.Now()
+	for i := range queued {
+		queued[i] = start
+	}
+	ready := deps.approval.approveItems(ctx, items, func(index int, item planner.WorkItem, reason string) {
+		itemState := rejectedItemState(item, reason)
+		mu.Lock()
+		defer mu.Unlock()
+		tracker.record(itemState)
+		recordWorkItemMetrics(itemState)
+		report(index, item, itemState, nil)
 	})
+	approved := make(chan int)
+	go func() {
+		defer close(approved)
+		for index := range ready {
+			queued[index] = time.Now()
+			approved <- index
+		}
+	}()
+	execpkg.ScheduleReady(items, cfg.Executor.ConcurrentLimit, approved, func(index int, item planner.WorkItem) {
+		wait := time.Since(queued[index])
+
+		mu.Lock()
+		history := tracker.history(item.Repo)
+		tracker.begin()
+		mu.Unlock()
+
+		heartbeat := tracker.startHeartbeat(item, cfg.Executor.HeartbeatInterval)
+		itemState, err := processWorkItem(ctx, deps, cfg.Workspace.Path, item, executor, broker, logger, cfg.Executor.Timeout, heartbeat, history)
+		heartbeat.stop()
+		if itemState.Timings != nil {
+			itemState.Timings.QueueWait += wait
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		tracker.end()
+		tracker.record(itemState)
+		recordWorkItemMetrics(itemState)
+		report(index, item, itemState, err)
+	})
+}
+
+// recordWorkItemMetrics counts a processed work item by status and records the
+// time it took and spent in each stage.
+func recordWorkItemMetrics(itemState state.ItemState) {
+	telemetry.WorkItems.Inc(string(itemState.Status))
+	if itemState.Duration > 0 {
+		telemetry.WorkItemDuration.Observe(itemState.Duration.Seconds())
+	}
+	if itemState.Timings == nil {
+		return
+	}
+	for _, stage := range state.StageOrder {
+		if spent := itemState.Timings.Get(stage); spent > 0 {
+			telemetry.StageDuration.Observe(spent.Seconds(), string(stage))
+		}
+	}
+}
+
+// itemHistory carries what earlier runs recorded about a work item.
+type itemHistory struct {
+	// resume is the stored state to continue from, nil to start over
+	resume *state.ItemState
+	// issue is the open failure issue to close once the item succeeds
+	issue *state.Issue
+	// attempt is the attempt this run records, counting from 1 across runs
+	attempt int
+	// module and version are what the run's state is stored under
+	module  string
+	version string
+	// annotations are the operator notes on the item and on its cascade
+	annotations []state.Annotation
+}
+
+// notifyContext tells notifiers which attempt of the item they report, so
+// repeated failures can escalate, where its state is stored, so they can link
+// to it, and what operators noted about it.
+func (h itemHistory) notifyContext(ctx context.Context) context.Context {
+	if h.module != "" {
+		ctx = broker.WithStateRun(ctx, h.module, h.version)
+	}
+	if len(h.annotations) > 0 {
+		ctx = broker.WithAnnotations(ctx, h.annotations)
+	}
+	if h.attempt <= 0 {
+		return ctx
+	}
+	return broker.WithAttempt(ctx, h.attempt)
+}
+
+// withItemCorrelation tags ctx with a fresh correlation ID for item so every
+// provider and notifier request it triggers can be traced back to it.
+func withItemCorrelation(ctx context.Context, item planner.WorkItem) (context.Context, string) {
+	id := broker.NewCorrelationID()
+	return broker.WithCorrelation(ctx, broker.Correlation{ID: id, Repo: item.Repo}), id
+}
+
+// withItemProvider sends the provider requests made under ctx to the provider
+// a dependent selected, rather than the one for its repository's host.
+func withItemProvider(ctx context.Context, provider string) context.Context {
+	return broker.WithProviderName(ctx, provider)
+}
+
+// processWorkItem executes a single work item and coordinates broker/state integration.
+// The optional heartbeat is advanced as execution moves through its phases. When
+// the history holds a stored state whose branch was already pushed, the executor
+// is not run again and only the pull request and notification phases it is
+// missing are retried.
+func processWorkItem(ctx context.Context, deps executionDeps, workspace string, item planner.WorkItem, executor execpkg.Executor, broker broker.Broker, logger di.Logger, defaultTimeout time.Duration, heartbeat *itemHeartbeat, history itemHistory) (state.ItemState, error) {
+	started := time.Now()
+	resume := history.resume
+	ctx, correlationID := withItemCorrelation(ctx, item)
+	ctx = withItemProvider(ctx, item.Provider)
+	ctx, span := telemetry.Trace(ctx, "work_item", telemetry.String("repo", item.Repo), telemetry.String("correlation_id", correlationID))
+	timer := &stageTimer{ctx: ctx}
+	logger.Debug("Processing work item", "repo", item.Repo, "correlation_id", correlationID)
+
+	var (
+		result  *execpkg.Result
+		execErr error
+		reached execpkg.Phase
+	)
+	// A repository added to defaults.never_touch since its branch was pushed goes
+	// back through the executor, which refuses it, instead of getting its PR
+	resumed := resume != nil && resume.Completed(state.PhasePushed) &&
+		manifest.NeverTouch(deps.neverTouch, item.Repo, item.CloneURL) == nil
+	if resumed {
+		result = resumedResult(item, *resume)
+	} else {
+		itemCopy := item
+		if itemCopy.Timeout <= 0 {
+			itemCopy.Timeout = defaultTimeout
+		}
+
+		workCtx := ctx
+		var cancel context.CancelFunc
+		if itemCopy.Timeout > 0 {
+			workCtx, cancel = context.WithTimeout(ctx, itemCopy.Timeout)
+			defer cancel()
+		}
+
+		// Time before the executor reports its first phase is spent waiting for a slot
+		timer.enter(state.StageQueueWait)
+		result, execErr = executor.Apply(workCtx, execpkg.WorkItemContext{
+			Item:        itemCopy,
+			Workspace:   workspace,
+			Git:         deps.itemGit(item),
+			Go:          deps.goTool,
+			Runner:      deps.command,
+			Services:    deps.services,
+			Logger:      logger,
+			NeverTouch:  deps.neverTouch,
+			LocalTarget: deps.localTarget.dirFor(item),
+			Progress: func(phase execpkg.Phase) {
+				reached = phase
+				timer.enter(executorStage(phase))
+				heartbeat.progress(phase)
+			},
+		})
+		timer.stop()
+	}
 
 	itemState := state.ItemState{
-		Repo:        item.Repo,
-		Branch:      item.BranchName,
-		LastUpdated: time.Now(),
-		Attempts:    1,
+		Repo:          item.Repo,
+		Branch:        item.BranchName,
+		LastUpdated:   time.Now(),
+		Attempts:      1,
+		CorrelationID: correlationID,
+		Provider:      item.Provider,
+		ProxyFallback: item.ProxyFallback,
+	}
+	if item.Retry != nil {
+		itemState.MaxAttempts = item.Retry.MaxAttempts
+		itemState.RetryBackoff = item.Retry.Backoff
+	}
+
+	// Dependent-local overrides applied by the executor take precedence for PRs and notifications
+	if result != nil && result.EffectiveItem != nil {
+		item = *result.EffectiveItem
 	}
+	itemState.AutoMerge = item.PR.AutoMerge
 
-	if result != nil {
+	if resumed {
+		itemState.Status = resume.Status
+		itemState.Reason = result.Reason
+		itemState.CommitHash = resume.CommitHash
+		itemState.CommandLogs = resume.CommandLogs
+		itemState.PRURL = resume.PRURL
+		itemState.DependencyImpact = resume.DependencyImpact
+		itemState.Phases = append([]state.Phase(nil), resume.Phases...)
+	} else if result != nil {
 		itemState.Status = result.Status
 		itemState.Reason = result.Reason
 		itemState.CommitHash = result.CommitHash
+		itemState.DependencyImpact = result.DependencyImpact
 		logs := append([]execpkg.CommandResult{}, result.TestResults...)
 		logs = append(logs, result.ExtraResults...)
 		itemState.CommandLogs = logs
+		for _, phase := range executedPhases(result, execErr, reached) {
+			itemState.MarkCompleted(phase)
+		}
 	} else {
 		itemState.Status = execpkg.StatusFailed
 		itemState.Reason = appendReason(itemState.Reason, "executor returned no result")
@@ -79,27 +317,152 @@ func processWorkItem(ctx context.Context, deps executionDeps, workspace string,
 	}
 
 	// Handle PR creation for successful or manual review statuses
-	if execErr == nil && result != nil {
+	if execErr == nil && result != nil && !itemState.Completed(state.PhasePRCreated) {
 		switch result.Status {
 		case execpkg.StatusCompleted, execpkg.StatusManualReview:
+			heartbeat.setPhase("pull-request")
+			timer.enter(state.StagePR)
 			pr, prErr := broker.EnsurePR(ctx, item, result)
+			timer.stop()
 			if prErr != nil {
 				errs = append(errs, fmt.Errorf("broker ensure PR: %w", prErr))
 				itemState.Reason = appendReason(itemState.Reason, fmt.Sprintf("PR creation failed: %v", prErr))
-			} else if pr != nil {
-				itemState.PRURL = pr.URL
+			} else {
+				if pr != nil {
+					itemState.PRURL = pr.URL
+				}
+				itemState.MarkCompleted(state.PhasePRCreated)
 			}
 		}
 	}
 
 	// Send notifications for all results (success or failure)
 	// The notifier will handle on_success/on_failure flags from manifest
-	if result != nil {
-		if _, notifyErr := broker.Notify(ctx, item, result); notifyErr != nil {
+	if result != nil && !itemState.Completed(state.PhaseNotified) {
+		heartbeat.setPhase("notify")
+		if notified, notifyErr := broker.Notify(history.notifyContext(ctx), item, result); notifyErr != nil {
 			errs = append(errs, fmt.Errorf("broker notify: %w", notifyErr))
 			itemState.Reason = appendReason(itemState.Reason, fmt.Sprintf("notification failed: %v", notifyErr))
+		} else {
+			itemState.MarkCompleted(state.PhaseNotified)
+			if notified != nil && notified.IssueNumber > 0 && itemState.Status == execpkg.StatusFailed {
+				itemState.IssueNumber = notified.IssueNumber
+			}
 		}
 	}
 
-	return itemState, errors.Join(errs...)
+	// Close the failure issue an earlier run filed now that the item succeeds
+	if history.issue != nil && result != nil && itemState.Status == execpkg.StatusCompleted {
+		resolved, resolveErr := broker.ResolveIssue(ctx, item, history.issue.Number, result)
+		switch {
+		case resolveErr != nil:
+			// The issue stays tracked, so the next successful run tries again
+			if logger != nil {
+				logger.Warn("Failed to close failure issue", "repo", item.Repo, "issue", history.issue.Number, "error", resolveErr)
+			}
+		case resolved != nil:
+			itemState.IssueNumber = history.issue.Number
+			itemState.IssueClosed = true
+		}
+	}
+
+	itemState.Duration = time.Since(started)
+	itemState.Timings = &timer.timings
+	err := errors.Join(errs...)
+	span.SetAttributes(telemetry.String("status", string(itemState.Status)), telemetry.Int("attempt", history.attempt))
+	span.End(err)
+	return itemState, err
+}
+
+// stageTimer attributes elapsed time to the stage a work item is in. It is used
+// from the goroutine processing the item only. When ctx is set, each stage is
+// also traced as a span of the work item.
+type stageTimer struct {
+	timings state.ItemTimings
+	stage   state.Stage
+	since   time.Time
+
+	ctx  context.Context
+	span *telemetry.Span
+}
+
+// enter closes the current stage, if any, and starts timing stage.
+func (s *stageTimer) enter(stage state.Stage) {
+	if stage != "" && stage == s.stage {
+		return
+	}
+	now := time.Now()
+	if s.stage != "" {
+		s.timings.Add(s.stage, now.Sub(s.since))
+		s.span.End(nil)
+		s.span = nil
+	}
+	s.stage, s.since = stage, now
+	if stage != "" && s.ctx != nil {
+		_, s.span = telemetry.Trace(s.ctx, "stage."+string(stage))
+	}
+}
+
+// stop closes the current stage.
+func (s *stageTimer) stop() {
+	s.enter("")
+}
+
+// executorStage maps an executor phase to the stage its time counts toward.
+func executorStage(phase execpkg.Phase) state.Stage {
+	switch phase {
+	case execpkg.PhaseClone, execpkg.PhaseWorktree:
+		return state.StageClone
+	case execpkg.PhaseDependencies, execpkg.PhaseTidy:
+		return state.StageUpdate
+	case execpkg.PhaseServices, execpkg.PhaseTests, execpkg.PhaseExtraCommands:
+		return state.StageTest
+	case execpkg.PhaseCommit, execpkg.PhasePush:
+		return state.StagePush
+	}
+	return ""
+}
+
+// executedPhases returns the item phases an executor run completed, based on its
+// outcome and the last executor phase it reported.
+func executedPhases(result *execpkg.Result, execErr error, reached execpkg.Phase) []state.Phase {
+	if execErr == nil && (result.Status == execpkg.StatusCompleted || result.Status == execpkg.StatusManualReview) {
+		return []state.Phase{state.PhaseUpdated, state.PhaseTested, state.PhasePushed}
+	}
+
+	switch reached {
+	case execpkg.PhaseServices, execpkg.PhaseTests, execpkg.PhaseExtraCommands:
+		return []state.Phase{state.PhaseUpdated}
+	case execpkg.PhaseCommit, execpkg.PhasePush:
+		return []state.Phase{state.PhaseUpdated, state.PhaseTested}
+	}
+	return nil
+}
+
+// resumedResult rebuilds the executor result of a pushed item from its stored
+// state so the pull request and notifications can be retried without running
+// the executor again. Command logs hold the tests followed by the extra commands.
+func resumedResult(item planner.WorkItem, prev state.ItemState) *execpkg.Result {
+	split := min(len(item.Tests), len(prev.CommandLogs))
+	return &execpkg.Result{
+		Status:       prev.Status,
+		Reason:       executorReason(prev.Reason),
+		CommitHash:   prev.CommitHash,
+		TestResults:  append([]execpkg.CommandResult{}, prev.CommandLogs[:split]...),
+		ExtraResults: append([]execpkg.CommandResult{}, prev.CommandLogs[split:]...),
+	}
+}
+
+// executorReason strips the pull request and notification failures that
+// processWorkItem appends to a stored reason, leaving the executor's own.
+func executorReason(reason string) string {
+	parts := strings.Split(reason, "; ")
+	kept := parts[:0]
+	for _, part := range parts {
+		if strings.HasPrefix(part, "PR creation failed: ") || strings.HasPrefix(part, "notification failed: ") {
+			continue
+		}
+		kept = append(kept, part)
+	}
+	return strings.Join(kept, "; ")
 }
@@ -1,6 +1,9 @@
 package main
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
 	"os"
 	"os/exec"
 	"testing"
@@ -176,6 +179,26 @@ func TestErrorTypeMapping(t *testing.T) {
 			err:          newExecutionError("execution failed", nil),
 			expectedCode: ExitExecutionError,
 		},
+		{
+			name:         "network error gets mapped",
+			err:          newNetworkError("network failed", nil),
+			expectedCode: ExitNetworkError,
+		},
+		{
+			name:         "interrupt error gets mapped",
+			err:          newInterruptError("cancelled", nil),
+			expectedCode: ExitInterruptError,
+		},
+		{
+			name:         "resource error gets mapped",
+			err:          newResourceError("disk full", nil),
+			expectedCode: ExitResourceError,
+		},
+		{
+			name:         "generic error gets mapped",
+			err:          newGenericError("failed", nil),
+			expectedCode: ExitGenericError,
+		},
 	}
 
 	for _, tt := range tests {
@@ -190,3 +213,57 @@ func TestErrorTypeMapping(t *testing.T) {
 		})
 	}
 }
+
+// TestWriteCLIError verifies stderr formatting and exit code selection for structured and bare errors
+func TestWriteCLIError(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		expectedCode int
+		expected     string
+	}{
+		{
+			name:         "structured error with cause and hint",
+			err:          newFileError("failed to load manifest", errors.New("open .cascade.yaml: no such file or directory")).WithHint("run %s", "cascade manifest generate"),
+			expectedCode: ExitFileError,
+			expected:     "cascade: failed to load manifest\n  Cause: open .cascade.yaml: no such file or directory\n  Hint: run cascade manifest generate\n",
+		},
+		{
+			name:         "wrapped structured error keeps its code",
+			err:          fmt.Errorf("outer: %w", newStateError("no saved state", nil)),
+			expectedCode: ExitStateError,
+			expected:     "cascade: no saved state\n",
+		},
+		{
+			name:         "bare error is classified",
+			err:          errors.New("dial tcp: connection refused"),
+			expectedCode: ExitNetworkError,
+			expected:     "cascade: network error\n  Cause: dial tcp: connection refused\n",
+		},
+		{
+			name:         "unknown command is a validation error",
+			err:          errors.New(`unknown command "nope" for "cascade"`),
+			expectedCode: ExitValidationError,
+			expected:     "cascade: validation error\n  Cause: unknown command \"nope\" for \"cascade\"\n",
+		},
+		{
+			name:         "unclassified bare error",
+			err:          errors.New("boom"),
+			expectedCode: ExitGenericError,
+			expected:     "cascade: boom\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			code := writeCLIError(&buf, tt.err)
+			if code != tt.expectedCode {
+				t.Errorf("expected exit code %d, got %d", tt.expectedCode, code)
+			}
+			if buf.String() != tt.expected {
+				t.Errorf("unexpected output:\n got: %q\nwant: %q", buf.String(), tt.expected)
+			}
+		})
+	}
+}
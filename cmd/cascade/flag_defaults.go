@@ -30,8 +30,9 @@ func applyModuleDefaults(modulePath string) (string, string, error) {
 	return finalModulePath, moduleDir, nil
 }
 
-// applyVersionDefaults applies default value discovery logic for version
-func applyVersionDefaults(ctx context.Context, version, moduleDir string, cfg *config.Config) (string, []string, error) {
+// applyVersionDefaults applies default value discovery logic for version.
+// resolvers is the module's version_resolution chain, if the manifest sets one.
+func applyVersionDefaults(ctx context.Context, modulePath, version, moduleDir string, resolvers []string, cfg *config.Config) (string, []string, error) {
 	finalVersion := strings.TrimSpace(version)
 	var versionWarnings []string
 
@@ -43,7 +44,7 @@ func applyVersionDefaults(ctx context.Context, version, moduleDir string, cfg *c
 
 	if finalVersion == "" || strings.EqualFold(finalVersion, "latest") {
 		workspaceDir := workspace.Resolve("", cfg, "", "")
-		resolvedVersion, warnings, err := resolveVersionFromWorkspace(ctx, "", finalVersion, workspaceDir, container.Logger())
+		resolvedVersion, warnings, err := resolveVersionFromWorkspace(ctx, modulePath, finalVersion, workspaceDir, resolvers, cfg, container.Logger())
 		if err != nil {
 			if finalVersion == "" {
 				return "", versionWarnings, newValidationError("version resolution failed and no explicit version provided", err)
@@ -22,4 +22,9 @@ func addGitHubDiscoveryFlags(cmd *cobra.Command, req *manifestGenerateRequest) {
 	cmd.Flags().StringVar(&req.GitHubOrg, "github-org", "", "GitHub organization to search for dependent repositories (auto-detected from module path if not provided)")
 	cmd.Flags().StringSliceVar(&req.GitHubInclude, "github-include", []string{}, "Repository name patterns to include during GitHub discovery")
 	cmd.Flags().StringSliceVar(&req.GitHubExclude, "github-exclude", []string{}, "Repository name patterns to exclude during GitHub discovery")
+	cmd.Flags().StringVar(&req.GitHubFilter, "filter", "", "Repository filter for GitHub discovery, e.g. 'topic:go-service pushed:>2024-01-01 archived:false'")
+	cmd.Flags().BoolVar(&req.GitHubFullScan, "github-full-scan", false, "List every repository of the organization instead of using code search (for orgs beyond the 1000 search result cap)")
+	cmd.Flags().BoolVar(&req.GitHubGraphQL, "github-graphql", false, "List every repository of the organization and read its go.mod through the GraphQL API, falling back to REST when unavailable")
+	cmd.Flags().StringVar(&req.GitHubScanCheckpoint, "github-scan-checkpoint", "", "Checkpoint file a full scan resumes from (default: github-scan/<org>.json in the state directory)")
+	cmd.Flags().BoolVar(&req.RefreshDiscovery, "refresh-discovery", false, "Fetch every GitHub discovery response again instead of revalidating the cached ones")
 }
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/goliatone/cascade/pkg/config"
+	"github.com/goliatone/cascade/pkg/gitutil"
+)
+
+// applyGitSSH points GIT_SSH_COMMAND, SSH_KEY_PATH, and SSH_KNOWN_HOSTS at the
+// integration.git.ssh key and known_hosts file in the process environment, so
+// clones, pushes, go commands fetching modules over SSH, and remote dependency
+// checks all authenticate the same way.
+func applyGitSSH(ssh config.GitSSHConfig) error {
+	opts := gitutil.SSHOptions{KeyPath: ssh.KeyPath, Agent: ssh.Agent, KnownHosts: ssh.KnownHosts}
+	for key, value := range gitutil.SSHEnv(opts, os.Getenv) {
+		if err := os.Setenv(key, value); err != nil {
+			return fmt.Errorf("set %s: %w", key, err)
+		}
+	}
+	return nil
+}
@@ -7,10 +7,13 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/goliatone/cascade/internal/manifest"
 	"github.com/goliatone/cascade/pkg/config"
+	"github.com/goliatone/cascade/pkg/repometa"
 	gh "github.com/google/go-github/v66/github"
 )
 
@@ -72,7 +75,7 @@ func TestDiscoverGitHubDependents_ReturnsDependents(t *testing.T) {
 
 	client := newMockGitHubClient(t, handlerMap)
 
-	deps, err := discoverGitHubDependentsWithClient(context.Background(), client, "github.com/target/module", "testorg", nil, nil, nil)
+	deps, err := discoverGitHubDependentsWithClient(context.Background(), client, nil, "github.com/target/module", nil, "testorg", nil, nil, nil)
 	if err != nil {
 		t.Fatalf("discoverGitHubDependentsWithClient returned error: %v", err)
 	}
@@ -91,10 +94,75 @@ func TestDiscoverGitHubDependents_ReturnsDependents(t *testing.T) {
 	}
 }
 
+func TestDiscoverGitHubDependents_SkipsArchivedRepositories(t *testing.T) {
+	handlerMap := map[string]func(*http.Request) *http.Response{
+		"GET /search/code": func(r *http.Request) *http.Response {
+			return jsonResponse(`{"total_count":2,"incomplete_results":false,"items":[` +
+				`{"path":"go.mod","name":"go.mod","repository":{"full_name":"testorg/live","owner":{"login":"testorg"},"name":"live"}},` +
+				`{"path":"go.mod","name":"go.mod","repository":{"full_name":"testorg/old","owner":{"login":"testorg"},"name":"old"}}]}`)
+		},
+		"GET /repos/testorg/live/contents/go.mod": func(r *http.Request) *http.Response {
+			content := base64.StdEncoding.EncodeToString([]byte("module github.com/testorg/live\n"))
+			return jsonResponse(fmt.Sprintf(`{"type":"file","encoding":"base64","content":"%s"}`, content))
+		},
+	}
+	client := newMockGitHubClient(t, handlerMap)
+
+	metadata := repometa.NewService(repometa.FetcherFunc(func(ctx context.Context, repo string) (*repometa.Metadata, error) {
+		return &repometa.Metadata{Repo: repo, Archived: repo == "testorg/old"}, nil
+	}))
+
+	deps, err := discoverGitHubDependentsWithClient(context.Background(), client, metadata, "github.com/target/module", nil, "testorg", nil, nil, nil)
+	if err != nil {
+		t.Fatalf("discoverGitHubDependentsWithClient returned error: %v", err)
+	}
+	if len(deps) != 1 || deps[0].Repository != "testorg/live" {
+		t.Fatalf("expected only testorg/live, got %+v", deps)
+	}
+}
+
+func TestSearchGitHubDependents_AppliesRepoFilter(t *testing.T) {
+	handlerMap := map[string]func(*http.Request) *http.Response{
+		"GET /search/code": func(r *http.Request) *http.Response {
+			return jsonResponse(`{"total_count":3,"incomplete_results":false,"items":[` +
+				`{"path":"go.mod","name":"go.mod","repository":{"full_name":"testorg/svc","owner":{"login":"testorg"},"name":"svc"}},` +
+				`{"path":"go.mod","name":"go.mod","repository":{"full_name":"testorg/tool","owner":{"login":"testorg"},"name":"tool"}},` +
+				`{"path":"go.mod","name":"go.mod","repository":{"full_name":"testorg/gone","owner":{"login":"testorg"},"name":"gone"}}]}`)
+		},
+		"GET /repos/testorg/svc/contents/go.mod": func(r *http.Request) *http.Response {
+			content := base64.StdEncoding.EncodeToString([]byte("module github.com/testorg/svc\n"))
+			return jsonResponse(fmt.Sprintf(`{"type":"file","encoding":"base64","content":"%s"}`, content))
+		},
+	}
+	client := newMockGitHubClient(t, handlerMap)
+
+	metadata := repometa.NewService(repometa.FetcherFunc(func(ctx context.Context, repo string) (*repometa.Metadata, error) {
+		switch repo {
+		case "testorg/svc":
+			return &repometa.Metadata{Repo: repo, Topics: []string{"go-service"}}, nil
+		case "testorg/tool":
+			return &repometa.Metadata{Repo: repo, Topics: []string{"cli"}}, nil
+		}
+		return nil, fmt.Errorf("lookup failed")
+	}))
+
+	filter, err := manifest.ParseRepoFilter("topic:go-service")
+	if err != nil {
+		t.Fatalf("ParseRepoFilter returned error: %v", err)
+	}
+	deps, err := searchGitHubDependents(context.Background(), client, metadata, "github.com/target/module", nil, "testorg", nil, nil, filter, nil)
+	if err != nil {
+		t.Fatalf("searchGitHubDependents returned error: %v", err)
+	}
+	if len(deps) != 1 || deps[0].Repository != "testorg/svc" {
+		t.Fatalf("expected only testorg/svc, got %+v", deps)
+	}
+}
+
 func TestDiscoverGitHubDependents_MissingToken(t *testing.T) {
 	withClearedGitHubEnv(t, func() {
 		cfg := &config.Config{}
-		if _, err := discoverGitHubDependents(context.Background(), "github.com/test/module", "testorg", nil, nil, cfg, nil); err == nil {
+		if _, err := discoverGitHubDependents(context.Background(), "github.com/test/module", nil, "testorg", nil, nil, cfg, nil); err == nil {
 			t.Fatalf("expected error when token missing")
 		}
 	})
@@ -114,3 +182,20 @@ func TestMatchesRepoPatterns(t *testing.T) {
 		t.Fatalf("expected repo to be excluded when include patterns set")
 	}
 }
+
+func TestGitHubDiscoveryCachePath(t *testing.T) {
+	cfg := &config.Config{}
+	if path := githubDiscoveryCachePath(cfg, "acme", "github.com/acme/lib"); path != "" {
+		t.Fatalf("expected no cache without a state directory, got %s", path)
+	}
+
+	cfg.State.Dir = "/state"
+	if path := githubDiscoveryCachePath(cfg, "acme", "github.com/acme/lib"); path != filepath.Join("/state", "discovery-cache", "acme", "github.com", "acme", "lib.json") {
+		t.Fatalf("unexpected default cache path %s", path)
+	}
+
+	cfg.ManifestGenerator.Discovery.GitHub.CacheDir = "/cache"
+	if path := githubDiscoveryCachePath(cfg, "acme", "github.com/acme/lib"); path != filepath.Join("/cache", "acme", "github.com", "acme", "lib.json") {
+		t.Fatalf("unexpected configured cache path %s", path)
+	}
+}
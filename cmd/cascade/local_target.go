@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/goliatone/cascade/internal/planner"
+	"golang.org/x/mod/modfile"
+)
+
+// localTarget is a checkout of a target module that dependents test against
+// instead of the released version.
+type localTarget struct {
+	module string
+	dir    string
+}
+
+// resolveLocalTarget checks that dir holds a checkout of one of the modules in
+// targets. An empty dir returns nil.
+func resolveLocalTarget(dir string, targets []planner.Target) (*localTarget, error) {
+	if dir == "" {
+		return nil, nil
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, newValidationError(fmt.Sprintf("invalid --local-target %q", dir), err)
+	}
+	path := filepath.Join(abs, "go.mod")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, newValidationError(fmt.Sprintf("--local-target %s is not a Go module checkout", dir), err)
+	}
+	module := modfile.ModulePath(data)
+	for _, target := range targets {
+		if target.Module == module {
+			return &localTarget{module: module, dir: abs}, nil
+		}
+	}
+	return nil, newValidationError(fmt.Sprintf("--local-target %s holds %s, not the module being released", dir, module), nil).
+		WithHint("pass the checkout of the target module")
+}
+
+// dirFor returns the checkout item's tests build against: the local target of
+// canary items that update its module, and "" for the rest.
+func (t *localTarget) dirFor(item planner.WorkItem) string {
+	if t == nil || !item.Canary {
+		return ""
+	}
+	for _, update := range item.ModuleUpdates() {
+		if update.Module == t.module {
+			return t.dir
+		}
+	}
+	return ""
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/goliatone/cascade/internal/planner"
+)
+
+func TestResolveLocalTarget(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module github.com/example/lib\n\ngo 1.22\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	targets := []planner.Target{{Module: "github.com/example/other", Version: "v1.0.0"}, {Module: "github.com/example/lib", Version: "v1.2.0"}}
+
+	local, err := resolveLocalTarget(dir, targets)
+	if err != nil {
+		t.Fatalf("resolveLocalTarget: %v", err)
+	}
+	if local.module != "github.com/example/lib" || local.dir != dir {
+		t.Errorf("local = %+v, want the lib checkout in %s", local, dir)
+	}
+
+	if local, err := resolveLocalTarget("", targets); local != nil || err != nil {
+		t.Errorf("resolveLocalTarget(\"\") = %+v, %v, want nil", local, err)
+	}
+	if _, err := resolveLocalTarget(dir, targets[:1]); err == nil || !strings.Contains(err.Error(), "holds github.com/example/lib") {
+		t.Errorf("expected an error for another module, got %v", err)
+	}
+	if _, err := resolveLocalTarget(filepath.Join(dir, "missing"), targets); err == nil || !strings.Contains(err.Error(), "is not a Go module checkout") {
+		t.Errorf("expected an error for a directory without go.mod, got %v", err)
+	}
+}
+
+func TestLocalTargetDirFor(t *testing.T) {
+	local := &localTarget{module: "github.com/example/lib", dir: "/src/lib"}
+	canary := planner.WorkItem{Repo: "example/api", SourceModule: "github.com/example/lib", Canary: true}
+
+	if got := local.dirFor(canary); got != "/src/lib" {
+		t.Errorf("dirFor(canary) = %q, want the checkout", got)
+	}
+	rest := canary
+	rest.Canary = false
+	if got := local.dirFor(rest); got != "" {
+		t.Errorf("dirFor(non-canary) = %q, want none", got)
+	}
+	other := canary
+	other.SourceModule = "github.com/example/other"
+	if got := local.dirFor(other); got != "" {
+		t.Errorf("dirFor(other module) = %q, want none", got)
+	}
+	set := other
+	set.Updates = []planner.ModuleUpdate{{Module: "github.com/example/other"}, {Module: "github.com/example/lib"}}
+	if got := local.dirFor(set); got != "/src/lib" {
+		t.Errorf("dirFor(release set) = %q, want the checkout", got)
+	}
+	if got := (*localTarget)(nil).dirFor(canary); got != "" {
+		t.Errorf("nil dirFor = %q, want none", got)
+	}
+}
@@ -1,7 +1,6 @@
 package main
 
 import (
-	"fmt"
 	"os"
 	"strings"
 
@@ -41,55 +40,16 @@ func handleCLIError(err error) {
 	if err == nil {
 		return
 	}
-
-	// Handle structured errors with appropriate exit codes
-	if cliErr, ok := err.(*CLIError); ok {
-		fmt.Fprintf(os.Stderr, "cascade: %s\n", cliErr.Message)
-		if cliErr.Cause != nil {
-			fmt.Fprintf(os.Stderr, "  Cause: %v\n", cliErr.Cause)
-		}
-		os.Exit(cliErr.ExitCode())
-	}
-
-	// Try to infer error type from error message patterns for better exit codes
-	errorMsg := err.Error()
-
-	// Configuration and validation errors
-	if strings.Contains(errorMsg, "configuration") || strings.Contains(errorMsg, "config") {
-		fmt.Fprintf(os.Stderr, "cascade: configuration error: %v\n", err)
-		os.Exit(ExitConfigError)
-	}
-
-	// File system errors
-	if strings.Contains(errorMsg, "no such file") || strings.Contains(errorMsg, "permission denied") ||
-		strings.Contains(errorMsg, "file not found") || strings.Contains(errorMsg, "manifest") {
-		fmt.Fprintf(os.Stderr, "cascade: file error: %v\n", err)
-		os.Exit(ExitFileError)
-	}
-
-	// Validation errors
-	if strings.Contains(errorMsg, "must be specified") || strings.Contains(errorMsg, "invalid") ||
-		strings.Contains(errorMsg, "validation") || strings.Contains(errorMsg, "required") {
-		fmt.Fprintf(os.Stderr, "cascade: validation error: %v\n", err)
-		os.Exit(ExitValidationError)
-	}
-
-	// Network/connectivity errors
-	if strings.Contains(errorMsg, "network") || strings.Contains(errorMsg, "connection") ||
-		strings.Contains(errorMsg, "timeout") || strings.Contains(errorMsg, "unreachable") {
-		fmt.Fprintf(os.Stderr, "cascade: network error: %v\n", err)
-		os.Exit(ExitNetworkError)
-	}
-
-	// Generic error fallback
-	fmt.Fprintf(os.Stderr, "cascade: %v\n", err)
-	os.Exit(ExitGenericError)
+	os.Exit(writeCLIError(os.Stderr, err))
 }
 
-// execute is the main entry point that sets up and runs the CLI
+// execute is the main entry point that sets up and runs the CLI. Telemetry is
+// flushed here rather than in a post-run hook, which cobra skips on errors.
 func execute() error {
 	rootCmd := newRootCommand()
-	return rootCmd.Execute()
+	err := rootCmd.Execute()
+	stopTelemetry(err)
+	return err
 }
 
 // Helper function to split module@version strings
@@ -7,29 +7,35 @@ import (
 	"strings"
 
 	"github.com/goliatone/cascade/internal/manifest"
+	"github.com/goliatone/cascade/pkg/config"
 	"github.com/goliatone/cascade/pkg/di"
 )
 
-func resolveVersionFromWorkspace(ctx context.Context, modulePath, version, workspaceDir string, logger di.Logger) (string, []string, error) {
+// resolveVersionFromWorkspace resolves the version of modulePath when none was
+// given or "latest" was requested. resolvers is the module's version_resolution
+// chain; without one, "latest" asks the module proxy and a missing version tries
+// the workspace first.
+func resolveVersionFromWorkspace(ctx context.Context, modulePath, version, workspaceDir string, resolvers []string, cfg *config.Config, logger di.Logger) (string, []string, error) {
 	discovery := manifest.NewWorkspaceDiscovery()
 
-	var strategy manifest.VersionResolutionStrategy
-	allowNetwork := true
-
-	if version == "latest" {
-		strategy = manifest.VersionResolutionLatest
+	var (
+		resolution *manifest.VersionResolution
+		err        error
+	)
+	if len(resolvers) > 0 {
+		resolution, err = resolveVersionWithChain(ctx, discovery, modulePath, workspaceDir, resolvers, cfg, logger)
 	} else {
-		strategy = manifest.VersionResolutionAuto
-	}
-
-	options := manifest.VersionResolutionOptions{
-		WorkspaceDir:       workspaceDir,
-		TargetModule:       modulePath,
-		Strategy:           strategy,
-		AllowNetworkAccess: allowNetwork,
+		strategy := manifest.VersionResolutionAuto
+		if version == "latest" {
+			strategy = manifest.VersionResolutionLatest
+		}
+		resolution, err = discovery.ResolveVersion(ctx, manifest.VersionResolutionOptions{
+			WorkspaceDir:       workspaceDir,
+			TargetModule:       modulePath,
+			Strategy:           strategy,
+			AllowNetworkAccess: true,
+		})
 	}
-
-	resolution, err := discovery.ResolveVersion(ctx, options)
 	if err != nil {
 		return "", nil, err
 	}
@@ -39,12 +45,48 @@ func resolveVersionFromWorkspace(ctx context.Context, modulePath, version, works
 			"module", modulePath,
 			"version", resolution.Version,
 			"source", string(resolution.Source),
+			"resolver", resolution.Resolver,
 			"source_path", resolution.SourcePath)
 	}
 
 	return resolution.Version, resolution.Warnings, nil
 }
 
+// resolveVersionWithChain runs a configured resolver chain. A GitHub client is
+// offered to the resolvers when a token is available.
+func resolveVersionWithChain(ctx context.Context, discovery manifest.WorkspaceDiscovery, modulePath, workspaceDir string, resolvers []string, cfg *config.Config, logger di.Logger) (*manifest.VersionResolution, error) {
+	env := manifest.VersionResolverEnv{Workspace: discovery}
+	if client, err := newGitHubClient(ctx, cfg); err == nil {
+		env.GitHub = manifest.NewGitHubDiscovery(client)
+	} else if logger != nil {
+		logger.Debug("Version resolvers run without a GitHub client", "reason", err.Error())
+	}
+
+	chain, err := manifest.NewVersionResolverChain(resolvers, env)
+	if err != nil {
+		return nil, err
+	}
+	return chain.ResolveVersion(ctx, manifest.VersionRequest{Module: modulePath, WorkspaceDir: workspaceDir})
+}
+
+// moduleVersionResolution returns the version_resolution chain the manifest
+// declares for modulePath, or nil when the manifest cannot be read or does not
+// set one.
+func moduleVersionResolution(loader manifest.Loader, manifestPath, modulePath string) []string {
+	if loader == nil || manifestPath == "" || modulePath == "" {
+		return nil
+	}
+	m, err := loader.Load(manifestPath)
+	if err != nil {
+		return nil
+	}
+	module, err := manifest.FindModuleByPath(m, modulePath)
+	if err != nil {
+		return nil
+	}
+	return module.VersionResolution
+}
+
 func displayDiscoverySummary(modulePath, version, workspaceDir string, discoveredDependents []manifest.DependentOptions, finalDependents, versionWarnings []string, yes, nonInteractive, dryRun bool) error {
 	shouldShowSummary := workspaceDir != "" || len(finalDependents) > 0
 	if !shouldShowSummary {
@@ -61,6 +103,9 @@ func displayDiscoverySummary(modulePath, version, workspaceDir string, discovere
 		fmt.Printf("Discovered %d dependent repositories:\n", len(discoveredDependents))
 		for i, dep := range discoveredDependents {
 			fmt.Printf("  %d. %s (module: %s)\n", i+1, dep.Repository, dep.ModulePath)
+			for _, test := range dep.Tests {
+				fmt.Printf("     test: %s\n", strings.Join(test.Cmd, " "))
+			}
 		}
 	} else if len(finalDependents) > 0 {
 		fmt.Printf("Using %d configured dependent repositories:\n", len(finalDependents))
@@ -91,7 +136,7 @@ func displayDiscoverySummary(modulePath, version, workspaceDir string, discovere
 		fmt.Scanln(&response)
 		if response != "" && (response == "n" || response == "N" || response == "no" || response == "NO") {
 			fmt.Println("Manifest generation cancelled.")
-			return fmt.Errorf("manifest generation cancelled by user")
+			return newInterruptError("manifest generation cancelled by user", nil)
 		}
 	}
 
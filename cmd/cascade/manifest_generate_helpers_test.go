@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/goliatone/cascade/internal/manifest"
+	"github.com/goliatone/cascade/pkg/config"
+)
+
+func TestModuleVersionResolution(t *testing.T) {
+	manifestPath := filepath.Join(t.TempDir(), ".cascade.yaml")
+	content := `manifest_version: 1
+modules:
+  - name: lib
+    module: example.com/lib
+    repo: example/lib
+    version_resolution: [tags, proxy]
+    dependents: []
+  - name: other
+    module: example.com/other
+    repo: example/other
+    dependents: []
+`
+	if err := os.WriteFile(manifestPath, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	loader := manifest.NewLoader()
+
+	if got := moduleVersionResolution(loader, manifestPath, "example.com/lib"); !reflect.DeepEqual(got, []string{"tags", "proxy"}) {
+		t.Errorf("lib resolvers = %v, want [tags proxy]", got)
+	}
+	if got := moduleVersionResolution(loader, manifestPath, "example.com/other"); got != nil {
+		t.Errorf("other resolvers = %v, want none", got)
+	}
+	if got := moduleVersionResolution(loader, filepath.Join(t.TempDir(), "missing.yaml"), "example.com/lib"); got != nil {
+		t.Errorf("missing manifest resolvers = %v, want none", got)
+	}
+}
+
+func TestResolveVersionFromWorkspaceUsesConfiguredChain(t *testing.T) {
+	name := "cmd-test-release-api"
+	if err := manifest.RegisterVersionResolver(name, func(manifest.VersionResolverEnv) (manifest.VersionResolver, error) {
+		return manifest.VersionResolverFunc(func(_ context.Context, req manifest.VersionRequest) (*manifest.VersionResolution, error) {
+			return &manifest.VersionResolution{Version: "v3.1.0", Source: manifest.VersionSourceNetwork}, nil
+		}), nil
+	}); err != nil {
+		t.Fatalf("RegisterVersionResolver() error = %v", err)
+	}
+
+	cfg := config.New()
+	cfg.Integration.GitHub.Token = "ghp_" + "0123456789012345678901234567890123"
+	logger := &mockLogger{}
+	got, warnings, err := resolveVersionFromWorkspace(context.Background(), "example.com/lib", "latest", t.TempDir(), []string{"local", name}, cfg, logger)
+	if err != nil {
+		t.Fatalf("resolveVersionFromWorkspace() error = %v", err)
+	}
+	if got != "v3.1.0" {
+		t.Errorf("version = %s, want v3.1.0 from the custom resolver", got)
+	}
+	if len(warnings) != 1 {
+		t.Errorf("warnings = %v, want the local failure", warnings)
+	}
+
+	if _, _, err := resolveVersionFromWorkspace(context.Background(), "example.com/lib", "latest", t.TempDir(), []string{"nightly"}, cfg, logger); err == nil {
+		t.Error("expected an unknown resolver to fail")
+	}
+}
@@ -18,6 +18,7 @@ import (
 type manifestGenerateRequest struct {
 	ModuleName      string
 	ModulePath      string
+	Aliases         []string
 	Repository      string
 	Version         string
 	OutputPath      string
@@ -34,6 +35,12 @@ type manifestGenerateRequest struct {
 	GitHubOrg       string
 	GitHubInclude   []string
 	GitHubExclude   []string
+
+	GitHubFilter         string
+	GitHubFullScan       bool
+	GitHubGraphQL        bool
+	GitHubScanCheckpoint string
+	RefreshDiscovery     bool
 }
 
 func manifestGenerate(ctx context.Context, req manifestGenerateRequest, cfg *config.Config) error {
@@ -92,7 +99,7 @@ func manifestGenerate(ctx context.Context, req manifestGenerateRequest, cfg *con
 	}
 	if finalVersion == "" || strings.EqualFold(finalVersion, "latest") {
 		workspaceDir := workspacepkg.Resolve(req.Workspace, cfg, req.ModulePath, moduleDir)
-		resolvedVersion, warnings, err := resolveVersionFromWorkspace(ctx, req.ModulePath, finalVersion, workspaceDir, logger)
+		resolvedVersion, warnings, err := resolveVersionFromWorkspace(ctx, req.ModulePath, finalVersion, workspaceDir, nil, cfg, logger)
 		if err != nil {
 			if finalVersion == "" {
 				return newValidationError("version resolution failed and no explicit version provided", err)
@@ -111,9 +118,31 @@ func manifestGenerate(ctx context.Context, req manifestGenerateRequest, cfg *con
 	workspaceDir := ""
 	finalDependentOptions := []manifest.DependentOptions{}
 
+	if req.GitHubFilter != "" {
+		cfg.ManifestGenerator.Discovery.GitHub.Filter = req.GitHubFilter
+	}
+	if _, err := manifest.ParseRepoFilter(cfg.ManifestGenerator.Discovery.GitHub.Filter); err != nil {
+		return newValidationError("invalid GitHub discovery filter", err).
+			WithHint("use qualifiers such as 'topic:go-service visibility:private archived:false pushed:>2024-01-01'")
+	}
+	if req.GitHubFullScan {
+		cfg.ManifestGenerator.Discovery.GitHub.FullScan = true
+	}
+	if req.GitHubGraphQL {
+		cfg.ManifestGenerator.Discovery.GitHub.GraphQL = true
+	}
+	if req.GitHubScanCheckpoint != "" {
+		cfg.ManifestGenerator.Discovery.GitHub.ScanCheckpoint = req.GitHubScanCheckpoint
+	}
+	if req.RefreshDiscovery {
+		cfg.ManifestGenerator.Discovery.GitHub.RefreshCache = true
+	}
+
+	neverTouch := existingNeverTouch(finalOutputPath, logger)
+
 	if len(req.Dependents) == 0 {
 		workspaceDir = workspacepkg.Resolve(req.Workspace, cfg, req.ModulePath, moduleDir)
-		mergedDependents, err := performMultiSourceDiscovery(ctx, req.ModulePath, req.Version, req.GitHubOrg, workspaceDir, req.MaxDepth,
+		mergedDependents, err := performMultiSourceDiscovery(ctx, req.ModulePath, req.Aliases, req.Version, req.GitHubOrg, workspaceDir, req.MaxDepth,
 			req.IncludePatterns, req.ExcludePatterns, req.GitHubInclude, req.GitHubExclude, cfg, logger)
 		if err != nil {
 			if logger != nil {
@@ -122,11 +151,11 @@ func manifestGenerate(ctx context.Context, req manifestGenerateRequest, cfg *con
 		} else {
 			discoveredDependents = mergedDependents
 
-			filtered, skipped := filterDiscoveredDependents(discoveredDependents, req.ModulePath, finalVersion, workspaceDir, logger)
+			filtered, skipped := filterDiscoveredDependents(discoveredDependents, req.ModulePath, finalVersion, workspaceDir, config.WorkspaceLayout(cfg), logger)
 			if len(skipped) > 0 && logger != nil {
 				logger.Info("Filtered discovered dependents", "skipped", dependentsOptionsToStrings(skipped))
 			}
-			discoveredDependents = filtered
+			discoveredDependents = dropNeverTouched(filtered, neverTouch, logger)
 			finalDependentOptions = append(finalDependentOptions, discoveredDependents...)
 
 			if len(discoveredDependents) > 0 && logger != nil {
@@ -139,13 +168,20 @@ func manifestGenerate(ctx context.Context, req manifestGenerateRequest, cfg *con
 		if len(discoveredDependents) > 0 && !req.Yes && !req.NonInteractive {
 			filteredDependents, err := promptForDependentSelection(discoveredDependents)
 			if err != nil {
-				return fmt.Errorf("dependent selection failed: %w", err)
+				return newValidationError("dependent selection failed", err).
+					WithHint("use comma separated numbers or ranges, e.g. 1,3-5")
 			}
 			discoveredDependents = filteredDependents
 			finalDependentOptions = append([]manifest.DependentOptions{}, discoveredDependents...)
 		}
 	} else {
-		finalDependentOptions = buildDependentOptions(req.Dependents)
+		finalDependentOptions = buildDependentOptions(req.Dependents, config.RepoURLs(cfg))
+		for _, dep := range finalDependentOptions {
+			if err := manifest.NeverTouch(neverTouch, dep.Repository, dep.CloneURL); err != nil {
+				return newValidationError("dependent is listed in defaults.never_touch", err).
+					WithHint("remove it from --dependents or from never_touch in %s", finalOutputPath)
+			}
+		}
 	}
 
 	finalDependentNames := dependentsOptionsToStrings(finalDependentOptions)
@@ -175,6 +211,7 @@ func manifestGenerate(ctx context.Context, req manifestGenerateRequest, cfg *con
 	options := manifest.GenerateOptions{
 		ModuleName:        req.ModuleName,
 		ModulePath:        req.ModulePath,
+		Aliases:           req.Aliases,
 		Repository:        req.Repository,
 		Version:           finalVersion,
 		Dependents:        finalDependentOptions,
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/goliatone/cascade/internal/manifest"
+)
+
+// manifestRevision is a manifest as it was at a git ref of the repository
+// holding it.
+type manifestRevision struct {
+	Ref      string
+	Commit   string
+	Data     []byte
+	Manifest *manifest.Manifest
+}
+
+// loadManifestAtRef reads the manifest at manifestPath as it was at ref, which
+// may be any revision git accepts, such as a tag, branch, or commit.
+func loadManifestAtRef(ctx context.Context, manifestPath, ref string) (*manifestRevision, error) {
+	absPath, err := filepath.Abs(manifestPath)
+	if err != nil {
+		return nil, newFileError("failed to resolve manifest path", err)
+	}
+	// git reports the top level with symlinks resolved, so resolve the
+	// directory too before relating the two
+	dir := filepath.Dir(absPath)
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = resolved
+	}
+
+	top, err := runManifestGit(ctx, dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, newFileError(fmt.Sprintf("%s is not in a git repository", manifestPath), err).
+			WithHint("--manifest-ref reads the manifest from the history of the repository that holds it")
+	}
+	rel, err := filepath.Rel(top, filepath.Join(dir, filepath.Base(absPath)))
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return nil, newFileError(fmt.Sprintf("%s is outside the repository at %s", manifestPath, top), err)
+	}
+
+	commit, err := runManifestGit(ctx, dir, "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	if err != nil {
+		return nil, newValidationError(fmt.Sprintf("unknown manifest ref %q", ref), err).
+			WithHint("fetch the ref first, for example `git fetch --tags`")
+	}
+
+	data, err := runManifestGit(ctx, dir, "show", commit+":"+filepath.ToSlash(rel))
+	if err != nil {
+		return nil, newFileError(fmt.Sprintf("%s does not exist at %s", rel, ref), err)
+	}
+
+	m, err := manifest.ParseFile(absPath, []byte(data))
+	if err != nil {
+		return nil, newFileError(fmt.Sprintf("failed to load manifest at %s", ref), err)
+	}
+	return &manifestRevision{Ref: ref, Commit: commit, Data: []byte(data), Manifest: m}, nil
+}
+
+// runManifestGit runs git in dir and returns its output. Output is trimmed
+// except for show, whose output is file content.
+func runManifestGit(ctx context.Context, dir string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	if args[0] == "show" {
+		return stdout.String(), nil
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadManifestAtRef(t *testing.T) {
+	dir := t.TempDir()
+	manifestPath := filepath.Join(dir, "config", ".cascade.yaml")
+	if err := os.MkdirAll(filepath.Dir(manifestPath), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeManifest := func(dependent string) {
+		t.Helper()
+		content := `manifest_version: 1
+defaults:
+  pr:
+    body_template_file: pr.md.tmpl
+modules:
+  - name: lib
+    module: example.com/lib
+    repo: example/lib
+    dependents:
+      - repo: ` + dependent + `
+        module: example.com/` + dependent + `
+        module_path: .
+`
+		if err := os.WriteFile(manifestPath, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	commit := func(message string) {
+		t.Helper()
+		execTestGitCommand(t, dir, "add", "-A")
+		execTestGitCommand(t, dir, "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-qm", message)
+	}
+
+	execTestGitCommand(t, dir, "init", "-q")
+	writeManifest("example/old-app")
+	commit("first")
+	execTestGitCommand(t, dir, "tag", "v2024.10")
+	writeManifest("example/new-app")
+	commit("second")
+
+	revision, err := loadManifestAtRef(context.Background(), manifestPath, "v2024.10")
+	if err != nil {
+		t.Fatalf("loadManifestAtRef() error = %v", err)
+	}
+	if len(revision.Commit) != 40 {
+		t.Errorf("Commit = %q, want a full hash", revision.Commit)
+	}
+	deps := revision.Manifest.Modules[0].Dependents
+	if len(deps) != 1 || deps[0].Repo != "example/old-app" {
+		t.Fatalf("dependents at v2024.10 = %+v, want example/old-app", deps)
+	}
+	wantTemplate := filepath.Join(filepath.Dir(manifestPath), "pr.md.tmpl")
+	if got := revision.Manifest.Defaults.PR.BodyTemplateFile; got != wantTemplate {
+		t.Errorf("BodyTemplateFile = %q, want %q", got, wantTemplate)
+	}
+
+	var cliErr *CLIError
+	if _, err := loadManifestAtRef(context.Background(), manifestPath, "v1999.01"); !errors.As(err, &cliErr) || cliErr.Code != ExitValidationError {
+		t.Errorf("unknown ref error = %v, want a validation error", err)
+	}
+	if _, err := loadManifestAtRef(context.Background(), filepath.Join(dir, "missing.yaml"), "HEAD"); !errors.As(err, &cliErr) || cliErr.Code != ExitFileError {
+		t.Errorf("missing file error = %v, want a file error", err)
+	}
+}
@@ -6,10 +6,13 @@ import (
 
 	"github.com/goliatone/cascade/internal/manifest"
 	"github.com/goliatone/cascade/pkg/config"
+	"github.com/goliatone/cascade/pkg/repourl"
 	"github.com/goliatone/cascade/pkg/util/modpath"
 )
 
-func buildDependentOptions(dependents []string) []manifest.DependentOptions {
+// buildDependentOptions converts --dependents entries into dependent options.
+// owner/repo shorthands live on the resolver's default host.
+func buildDependentOptions(dependents []string, urls *repourl.Resolver) []manifest.DependentOptions {
 	if len(dependents) == 0 {
 		return []manifest.DependentOptions{}
 	}
@@ -20,15 +23,20 @@ func buildDependentOptions(dependents []string) []manifest.DependentOptions {
 		modulePath := ""
 
 		if strings.Count(repo, "/") == 1 && !strings.Contains(repo, ".") {
-			modulePath = "github.com/" + repo
+			modulePath = urls.DefaultHost() + "/" + repo
 		} else {
 			modulePath = repo
 			repo = modpath.DeriveRepository(repo)
 		}
 
+		cloneURL, err := urls.CloneURL(modulePath)
+		if err != nil {
+			cloneURL = repo
+		}
+
 		options[i] = manifest.DependentOptions{
 			Repository:      repo,
-			CloneURL:        modpath.BuildCloneURL(repo),
+			CloneURL:        cloneURL,
 			ModulePath:      modulePath,
 			LocalModulePath: modpath.DeriveLocalModulePath(modulePath),
 		}
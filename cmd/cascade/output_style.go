@@ -0,0 +1,183 @@
+package main
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+)
+
+// outputStyle decides how human-readable command output is decorated. Logs and
+// machine-readable formats (JSON, DOT, Mermaid) are never styled.
+type outputStyle struct {
+	// color wraps status markers in ANSI colors
+	color bool
+
+	// emoji uses symbols such as ✓ and ⚠ for status markers; when false, markers
+	// are plain words that read well in screen readers and dumb consoles
+	emoji bool
+
+	// width is the terminal width used to fit tables, or 0 when unknown
+	width int
+}
+
+// style is the output style of the running command. It defaults to symbols
+// without color until resolved from flags and the environment.
+var style = outputStyle{emoji: true}
+
+// marker identifies a status marker in command output.
+type marker int
+
+const (
+	markOK marker = iota
+	markFailed
+	markWarning
+	markRunning
+	markSkipped
+	markReview
+)
+
+var markers = map[marker]struct {
+	symbol string
+	word   string
+	ansi   string
+}{
+	markOK:      {symbol: "✓", word: "OK", ansi: "32"},
+	markFailed:  {symbol: "✗", word: "FAILED", ansi: "31"},
+	markWarning: {symbol: "⚠", word: "WARNING", ansi: "33"},
+	markRunning: {symbol: "⟳", word: "RUNNING", ansi: "36"},
+	markSkipped: {symbol: "⏭", word: "SKIPPED", ansi: "90"},
+	markReview:  {symbol: "!", word: "REVIEW", ansi: "33"},
+}
+
+// mark renders a status marker in the current style.
+func (s outputStyle) mark(m marker) string {
+	def := markers[m]
+	text := def.symbol
+	if !s.emoji {
+		text = def.word
+	}
+	if s.color {
+		return "\x1b[" + def.ansi + "m" + text + "\x1b[0m"
+	}
+	return text
+}
+
+// ellipsis is appended to text cut to fit the terminal width.
+func (s outputStyle) ellipsis() string {
+	if s.emoji {
+		return "…"
+	}
+	return "..."
+}
+
+// fit truncates line to the terminal width, ignoring color sequences when
+// measuring. Lines are left unchanged when the width is unknown.
+func (s outputStyle) fit(line string) string {
+	if s.width <= 0 || visibleLen(line) <= s.width {
+		return line
+	}
+	ellipsis := s.ellipsis()
+	keep := s.width - utf8.RuneCountInString(ellipsis)
+	if keep <= 0 {
+		return ellipsis
+	}
+
+	var b strings.Builder
+	visible := 0
+	for i := 0; i < len(line) && visible < keep; {
+		if end := ansiSequenceEnd(line, i); end > i {
+			b.WriteString(line[i:end])
+			i = end
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(line[i:])
+		b.WriteRune(r)
+		visible++
+		i += size
+	}
+	return b.String() + ellipsis
+}
+
+// visibleLen counts the runes of line that are not part of a color sequence.
+func visibleLen(line string) int {
+	n := 0
+	for i := 0; i < len(line); {
+		if end := ansiSequenceEnd(line, i); end > i {
+			i = end
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(line[i:])
+		n++
+		i += size
+	}
+	return n
+}
+
+// ansiSequenceEnd returns the index just past the SGR sequence starting at i, or
+// i when none starts there.
+func ansiSequenceEnd(line string, i int) int {
+	if !strings.HasPrefix(line[i:], "\x1b[") {
+		return i
+	}
+	if end := strings.IndexByte(line[i:], 'm'); end >= 0 {
+		return i + end + 1
+	}
+	return i
+}
+
+// writeFitted writes text line by line, truncating each line to the terminal
+// width. Used for tables whose rows would otherwise wrap.
+func writeFitted(w io.Writer, text string) error {
+	for _, line := range strings.SplitAfter(text, "\n") {
+		if line == "" {
+			continue
+		}
+		trimmed := strings.TrimSuffix(line, "\n")
+		if _, err := io.WriteString(w, style.fit(trimmed)+line[len(trimmed):]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addOutputFlags registers the output style flags on the root command.
+func addOutputFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().Bool("no-color", false, "Disable colored output (also honors NO_COLOR and TERM=dumb)")
+	cmd.PersistentFlags().Bool("no-emoji", false, "Use plain words instead of symbols for status markers")
+}
+
+// resolveOutputStyle applies the output flags and the environment to style.
+func resolveOutputStyle(cmd *cobra.Command) {
+	noColor, _ := cmd.Flags().GetBool("no-color")
+	noEmoji, _ := cmd.Flags().GetBool("no-emoji")
+	style = newOutputStyle(noColor, noEmoji, os.Getenv, isTerminal(os.Stdout))
+}
+
+// newOutputStyle derives the output style. Color needs a terminal and is turned
+// off by --no-color, a non-empty NO_COLOR (https://no-color.org), or TERM=dumb.
+// Symbols are turned off by --no-emoji or TERM=dumb. The width comes from COLUMNS.
+func newOutputStyle(noColor, noEmoji bool, getenv func(string) string, terminal bool) outputStyle {
+	dumb := strings.TrimSpace(getenv("TERM")) == "dumb"
+
+	s := outputStyle{
+		color: terminal && !noColor && !dumb && getenv("NO_COLOR") == "",
+		emoji: !noEmoji && !dumb,
+	}
+	if columns, err := strconv.Atoi(strings.TrimSpace(getenv("COLUMNS"))); err == nil && columns > 0 {
+		s.width = columns
+	}
+	return s
+}
+
+// isTerminal reports whether f is attached to a character device.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	execpkg "github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/internal/state"
+)
+
+func TestNewOutputStyle(t *testing.T) {
+	tests := []struct {
+		name      string
+		noColor   bool
+		noEmoji   bool
+		env       map[string]string
+		terminal  bool
+		wantColor bool
+		wantEmoji bool
+		wantWidth int
+	}{
+		{name: "terminal defaults", terminal: true, wantColor: true, wantEmoji: true},
+		{name: "piped output has no color", terminal: false, wantEmoji: true},
+		{name: "no-color flag", noColor: true, terminal: true, wantEmoji: true},
+		{name: "NO_COLOR", env: map[string]string{"NO_COLOR": "1"}, terminal: true, wantEmoji: true},
+		{name: "no-emoji flag", noEmoji: true, terminal: true, wantColor: true},
+		{name: "dumb terminal", env: map[string]string{"TERM": "dumb"}, terminal: true},
+		{name: "COLUMNS sets width", env: map[string]string{"COLUMNS": "100"}, wantEmoji: true, wantWidth: 100},
+		{name: "invalid COLUMNS ignored", env: map[string]string{"COLUMNS": "wide"}, wantEmoji: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(key string) string { return tt.env[key] }
+			got := newOutputStyle(tt.noColor, tt.noEmoji, getenv, tt.terminal)
+			if got.color != tt.wantColor || got.emoji != tt.wantEmoji || got.width != tt.wantWidth {
+				t.Errorf("newOutputStyle() = %+v, want color=%v emoji=%v width=%d", got, tt.wantColor, tt.wantEmoji, tt.wantWidth)
+			}
+		})
+	}
+}
+
+func TestOutputStyle_Mark(t *testing.T) {
+	if got := (outputStyle{emoji: true}).mark(markOK); got != "✓" {
+		t.Errorf("emoji mark = %q, want ✓", got)
+	}
+	if got := (outputStyle{}).mark(markFailed); got != "FAILED" {
+		t.Errorf("plain mark = %q, want FAILED", got)
+	}
+	if got := (outputStyle{color: true}).mark(markWarning); got != "\x1b[33mWARNING\x1b[0m" {
+		t.Errorf("colored mark = %q", got)
+	}
+}
+
+func TestOutputStyle_Fit(t *testing.T) {
+	tests := []struct {
+		name  string
+		style outputStyle
+		line  string
+		want  string
+	}{
+		{name: "unknown width", style: outputStyle{}, line: "a long line of output", want: "a long line of output"},
+		{name: "fits", style: outputStyle{width: 30}, line: "short", want: "short"},
+		{name: "truncates with ascii ellipsis", style: outputStyle{width: 10}, line: "a long line of output", want: "a long ..."},
+		{name: "truncates with unicode ellipsis", style: outputStyle{emoji: true, width: 10}, line: "a long line of output", want: "a long li…"},
+		{
+			name:  "ignores color sequences",
+			style: outputStyle{color: true, width: 8},
+			line:  "\x1b[32mOK\x1b[0m example/app",
+			want:  "\x1b[32mOK\x1b[0m ex...",
+		},
+		{
+			name:  "colored line that fits",
+			style: outputStyle{color: true, width: 14},
+			line:  "\x1b[32mOK\x1b[0m example/app",
+			want:  "\x1b[32mOK\x1b[0m example/app",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.style.fit(tt.line); got != tt.want {
+				t.Errorf("fit() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlainOutputStyle(t *testing.T) {
+	previous := style
+	style = outputStyle{width: 40}
+	defer func() { style = previous }()
+
+	var buf bytes.Buffer
+	if err := printTemplateFuncs(&buf); err != nil {
+		t.Fatalf("printTemplateFuncs: %v", err)
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+		if len([]rune(line)) > 40 {
+			t.Errorf("line exceeds width: %q", line)
+		}
+	}
+
+	buf.Reset()
+	summary := &state.Summary{Module: "github.com/example/lib", Version: "v1.2.3"}
+	items := []state.ItemState{
+		{Repo: "example/a", Status: execpkg.StatusCompleted, Attempts: 1},
+		{Repo: "example/b", Status: execpkg.StatusFailed, Attempts: 1},
+	}
+	renderStateShow(&buf, summary, items, nil, time.Minute, time.Now())
+	out := buf.String()
+	for _, want := range []string{"OK example/a", "FAILED example/b"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+	if strings.ContainsAny(out, "✓✗⚠") {
+		t.Errorf("expected no symbols in plain output:\n%s", out)
+	}
+
+	buf.Reset()
+	printItemOutcome(&buf, state.ItemState{Status: execpkg.StatusSkipped, Reason: "up to date"})
+	if buf.String() != "    SKIPPED Skipped: up to date\n" {
+		t.Errorf("unexpected outcome line %q", buf.String())
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/goliatone/cascade/internal/planner"
+	"github.com/goliatone/cascade/internal/state"
+	"github.com/goliatone/cascade/pkg/version"
+)
+
+// writePlanArtifact saves plan to path with the hash of the manifest it was made
+// from, for release --from-plan.
+func writePlanArtifact(path, manifestPath string, plan *planner.Plan) error {
+	return writeRevisionPlanArtifact(path, manifestPath, nil, plan)
+}
+
+// writeRevisionPlanArtifact is writePlanArtifact for a plan made from the
+// manifest as it was at revision, whose content is hashed instead of the
+// working copy's. A nil revision reads the working copy.
+func writeRevisionPlanArtifact(path, manifestPath string, revision *manifestRevision, plan *planner.Plan) error {
+	artifact := planner.PlanArtifact{
+		CascadeVersion: version.Tag,
+		CreatedAt:      time.Now().UTC(),
+		ManifestPath:   manifestPath,
+		Plan:           *plan,
+	}
+	if revision != nil {
+		artifact.ManifestHash = state.ManifestDigest(revision.Data)
+		artifact.ManifestRef = revision.Ref
+		artifact.ManifestCommit = revision.Commit
+	} else {
+		data, err := os.ReadFile(manifestPath)
+		if err != nil {
+			return newFileError("failed to read manifest for the plan file", err)
+		}
+		artifact.ManifestHash = state.ManifestDigest(data)
+	}
+	if err := planner.WritePlanArtifact(path, artifact); err != nil {
+		return newFileError("failed to write plan file", err)
+	}
+	return nil
+}
+
+// loadPlanArtifact reads a plan saved with plan --output. The manifest it was
+// made from is used unless manifestFlag or manifestArg names another.
+func loadPlanArtifact(path, manifestFlag, manifestArg string) (*planner.PlanArtifact, string, error) {
+	artifact, err := planner.ReadPlanArtifact(path)
+	if err != nil {
+		return nil, "", newFileError("failed to load plan file", err).
+			WithHint("create one with `cascade plan --output %s`", path)
+	}
+	manifestPath := artifact.ManifestPath
+	if manifestFlag != "" || manifestArg != "" || manifestPath == "" {
+		manifestPath = resolvePlanManifestPath(manifestFlag, manifestArg, container.Config())
+	}
+	return artifact, manifestPath, nil
+}
+
+// checkPlanArtifact fails unless the manifest at manifestPath is the one the plan
+// was made from and the plan targets module and version, when they are given.
+func checkPlanArtifact(artifact *planner.PlanArtifact, manifestPath, module, moduleVersion string) error {
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return newFileError("failed to read manifest", err)
+	}
+	if digest := state.ManifestDigest(data); digest != artifact.ManifestHash {
+		if artifact.ManifestRef != "" {
+			return newValidationError(fmt.Sprintf("%s differs from the manifest at %s the plan was made from (hash %s, plan has %s)", manifestPath, artifact.ManifestRef, shortDigest(digest), shortDigest(artifact.ManifestHash)), nil).
+				WithHint("release against that revision with `git show %s:<manifest> > old.yaml` and --manifest old.yaml", shortCommit(artifact.ManifestCommit))
+		}
+		return newValidationError(fmt.Sprintf("%s changed since the plan was made (hash %s, plan has %s)", manifestPath, shortDigest(digest), shortDigest(artifact.ManifestHash)), nil).
+			WithHint("run `cascade plan --output` again and review the new plan")
+	}
+
+	target := artifact.Plan.Target
+	if module = strings.TrimSpace(module); module != "" && module != target.Module {
+		return newValidationError(fmt.Sprintf("--module %s does not match the plan, which targets %s", module, target.Module), nil)
+	}
+	if moduleVersion = strings.TrimSpace(moduleVersion); moduleVersion != "" && moduleVersion != target.Version {
+		return newValidationError(fmt.Sprintf("--version %s does not match the plan, which targets %s", moduleVersion, target.Version), nil)
+	}
+	return nil
+}
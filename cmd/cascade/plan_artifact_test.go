@@ -0,0 +1,46 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/goliatone/cascade/internal/planner"
+)
+
+func TestPlanArtifactChecksManifest(t *testing.T) {
+	dir := t.TempDir()
+	manifestPath := filepath.Join(dir, ".cascade.yaml")
+	if err := os.WriteFile(manifestPath, []byte(inputsTestManifest), 0600); err != nil {
+		t.Fatal(err)
+	}
+	planPath := filepath.Join(dir, "plan.json")
+	plan := &planner.Plan{Target: planner.Target{Module: "example.com/lib", Version: "v1.0.0"}}
+	if err := writePlanArtifact(planPath, manifestPath, plan); err != nil {
+		t.Fatalf("writePlanArtifact() error = %v", err)
+	}
+
+	artifact, resolved, err := loadPlanArtifact(planPath, "", "")
+	if err != nil {
+		t.Fatalf("loadPlanArtifact() error = %v", err)
+	}
+	if resolved != manifestPath {
+		t.Errorf("expected the plan's manifest %s, got %s", manifestPath, resolved)
+	}
+	if err := checkPlanArtifact(artifact, resolved, "example.com/lib", "v1.0.0"); err != nil {
+		t.Fatalf("checkPlanArtifact() error = %v", err)
+	}
+
+	var cliErr *CLIError
+	if err := checkPlanArtifact(artifact, resolved, "", "v2.0.0"); !errors.As(err, &cliErr) || cliErr.Code != ExitValidationError {
+		t.Errorf("expected a validation error for another version, got %v", err)
+	}
+
+	if err := os.WriteFile(manifestPath, []byte(inputsTestManifest+"    # edited\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkPlanArtifact(artifact, resolved, "", ""); !errors.As(err, &cliErr) || cliErr.Code != ExitValidationError {
+		t.Errorf("expected a validation error once the manifest changed, got %v", err)
+	}
+}
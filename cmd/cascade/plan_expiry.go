@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	execpkg "github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/internal/manifest"
+	"github.com/goliatone/cascade/internal/planner"
+	"github.com/goliatone/cascade/internal/state"
+	"github.com/goliatone/cascade/pkg/config"
+	"github.com/goliatone/cascade/pkg/di"
+)
+
+// revalidateStalePlan re-checks items against the current remote state when a
+// plan made at created is older than executor.plan_max_age, so stale
+// instructions are not executed. It returns the reason each invalidated item was
+// dropped, keyed by repository, or nil when the plan has not expired.
+func revalidateStalePlan(ctx context.Context, w io.Writer, cfg *config.Config, m *manifest.Manifest, created time.Time, items []planner.WorkItem, logger di.Logger) map[string]string {
+	maxAge := cfg.Executor.PlanMaxAge
+	if !planner.PlanExpired(created, maxAge, time.Now()) || len(items) == 0 {
+		return nil
+	}
+
+	fmt.Fprintf(w, "Plan is %s old, more than plan_max_age %s; re-validating %d work items\n", time.Since(created).Round(time.Minute), maxAge, len(items))
+	_, invalid, warnings := planner.Revalidate(ctx, items, staleCheckOptions(cfg, m, logger)...)
+	for _, warning := range warnings {
+		logger.Warn("Plan re-validation check failed, keeping the item", "detail", warning)
+	}
+
+	reasons := make(map[string]string, len(invalid))
+	for _, inv := range invalid {
+		reasons[inv.Item.Repo] = inv.Reason
+		fmt.Fprintf(w, "  %s %s invalidated: %s\n", style.mark(markSkipped), inv.Item.Repo, inv.Reason)
+	}
+	if len(invalid) == 0 {
+		fmt.Fprintf(w, "  %s All work items are still valid\n", style.mark(markOK))
+	}
+	return reasons
+}
+
+// staleCheckOptions checks base branches and dependents remotely, since the
+// workspace may be as stale as the plan, and looks for newer releases of the
+// targets with their version_resolution chains.
+func staleCheckOptions(cfg *config.Config, m *manifest.Manifest, logger di.Logger) []planner.RevalidateOption {
+	timeout := checkTimeout(cfg)
+	checker := planner.NewRemoteDependencyChecker(planner.CheckOptions{
+		Strategy:   planner.CheckStrategyRemote,
+		Timeout:    timeout,
+		Retries:    cfg.Executor.Retries,
+		RetryDelay: cfg.Executor.RetryDelay,
+		RepoURLs:   config.RepoURLs(cfg),
+	}, logger)
+
+	latest := latestModuleVersion(cfg, logger)
+	return []planner.RevalidateOption{
+		planner.WithBranchCheck(planner.NewRemoteBranchChecker(timeout, config.RepoURLs(cfg))),
+		planner.WithUpToDateCheck(checker, ""),
+		planner.WithNewerVersionCheck(func(ctx context.Context, module string) (string, error) {
+			mod, err := manifest.FindModuleByPath(m, module)
+			if err != nil {
+				return "", err
+			}
+			return latest(ctx, mod)
+		}),
+	}
+}
+
+// checkTimeout bounds each remote check made outside of planning.
+func checkTimeout(cfg *config.Config) time.Duration {
+	if cfg.Executor.CheckTimeout == 0 {
+		return 30 * time.Second
+	}
+	return cfg.Executor.CheckTimeout
+}
+
+// invalidatedItemState records a work item that re-validation dropped from a
+// stale plan.
+func invalidatedItemState(item planner.WorkItem, reason string) state.ItemState {
+	return state.ItemState{
+		Repo:        item.Repo,
+		Branch:      item.BranchName,
+		Status:      execpkg.StatusSkipped,
+		Reason:      "invalidated: " + reason,
+		LastUpdated: time.Now(),
+		Attempts:    1,
+		Provider:    item.Provider,
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/goliatone/cascade/internal/broker"
+	"github.com/goliatone/cascade/internal/planner"
+)
+
+var previewFileSanitizer = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// prPreviewDir returns the directory dry-run PR previews are written to, alongside
+// the state recorded for module@version.
+func prPreviewDir(stateDir, module, version string) string {
+	return filepath.Join(stateDir, module, version, "previews")
+}
+
+// renderPRPreviews renders the pull request each work item would open. Previews are
+// printed to w when dir is empty; otherwise one markdown file per item is written to
+// dir and its path is reported to w.
+func renderPRPreviews(w io.Writer, items []planner.WorkItem, cfg broker.Config, dir string) error {
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return newFileError("failed to create preview directory", err)
+		}
+	}
+
+	for i, item := range items {
+		preview, err := broker.PreviewPR(cfg, item, nil)
+		if err != nil {
+			return newExecutionError(fmt.Sprintf("failed to render PR preview for %s", item.Repo), err)
+		}
+
+		if dir == "" {
+			fmt.Fprintf(w, "\n--- PR %d/%d: %s ---\n", i+1, len(items), item.Repo)
+			writePRPreview(w, preview)
+			continue
+		}
+
+		path := filepath.Join(dir, previewFileName(item.Repo))
+		var buf strings.Builder
+		writePRPreview(&buf, preview)
+		if err := os.WriteFile(path, []byte(buf.String()), 0o644); err != nil {
+			return newFileError(fmt.Sprintf("failed to write PR preview for %s", item.Repo), err)
+		}
+		fmt.Fprintf(w, "  wrote %s\n", path)
+	}
+	return nil
+}
+
+// writePRPreview prints a preview as a markdown document with a metadata header.
+func writePRPreview(w io.Writer, preview *broker.PRPreview) {
+	fmt.Fprintf(w, "Repository: %s\n", preview.Repo)
+	fmt.Fprintf(w, "Branch:     %s -> %s\n", preview.HeadBranch, preview.BaseBranch)
+	fmt.Fprintf(w, "Title:      %s\n", preview.Title)
+	fmt.Fprintf(w, "Labels:     %s\n", joinOrNone(preview.Labels))
+	fmt.Fprintf(w, "Reviewers:  %s\n", joinOrNone(preview.Reviewers))
+	if len(preview.TeamReviewers) > 0 {
+		fmt.Fprintf(w, "Teams:      %s\n", strings.Join(preview.TeamReviewers, ", "))
+	}
+	if preview.Draft {
+		fmt.Fprintf(w, "Draft:      yes\n")
+	}
+	fmt.Fprintf(w, "\n%s\n", strings.TrimSpace(preview.Body))
+}
+
+func previewFileName(repo string) string {
+	name := strings.Trim(previewFileSanitizer.ReplaceAllString(repo, "_"), "_.")
+	if name == "" {
+		name = "repo"
+	}
+	return name + ".md"
+}
+
+func joinOrNone(values []string) string {
+	if len(values) == 0 {
+		return "(none)"
+	}
+	return strings.Join(values, ", ")
+}
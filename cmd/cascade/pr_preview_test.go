@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/goliatone/cascade/internal/broker"
+	"github.com/goliatone/cascade/internal/planner"
+)
+
+func TestRenderPRPreviews(t *testing.T) {
+	items := []planner.WorkItem{{
+		Repo:          "goliatone/go-logger",
+		Module:        "github.com/goliatone/go-logger",
+		SourceVersion: "v1.2.3",
+		Branch:        "main",
+		BranchName:    "cascade/go-errors-v1.2.3",
+	}}
+
+	t.Run("stdout", func(t *testing.T) {
+		var out bytes.Buffer
+		if err := renderPRPreviews(&out, items, broker.DefaultConfig(), ""); err != nil {
+			t.Fatalf("renderPRPreviews() error = %v", err)
+		}
+		for _, want := range []string{
+			"--- PR 1/1: goliatone/go-logger ---",
+			"Branch:     cascade/go-errors-v1.2.3 -> main",
+			"Title:      Update github.com/goliatone/go-logger to v1.2.3",
+			"Labels:     automation:cascade",
+			"Reviewers:  (none)",
+		} {
+			if !strings.Contains(out.String(), want) {
+				t.Errorf("output missing %q:\n%s", want, out.String())
+			}
+		}
+	})
+
+	t.Run("files", func(t *testing.T) {
+		dir := prPreviewDir(t.TempDir(), "github.com/goliatone/go-errors", "v1.2.3")
+		var out bytes.Buffer
+		if err := renderPRPreviews(&out, items, broker.DefaultConfig(), dir); err != nil {
+			t.Fatalf("renderPRPreviews() error = %v", err)
+		}
+
+		path := filepath.Join(dir, "goliatone_go-logger.md")
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("expected preview file: %v", err)
+		}
+		if !strings.Contains(string(data), "Title:      Update github.com/goliatone/go-logger to v1.2.3") {
+			t.Errorf("unexpected preview file:\n%s", data)
+		}
+		if !strings.Contains(out.String(), path) {
+			t.Errorf("expected written path to be reported, got %q", out.String())
+		}
+	})
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/goliatone/cascade/internal/broker"
+	"github.com/goliatone/cascade/internal/state"
+	"github.com/goliatone/cascade/pkg/di"
+)
+
+// prMerger follows the pull request of a release item until it merges. Items
+// with pr.auto_merge are merged once their checks pass and the provider
+// accepts the merge, which it refuses until required approvals are met.
+type prMerger struct {
+	broker broker.Broker
+	logger di.Logger
+}
+
+// itemPullRequest returns the pull request recorded for item.
+func itemPullRequest(item state.ItemState) (*broker.PullRequest, error) {
+	number, err := extractPRNumber(item.PRURL)
+	if err != nil {
+		return nil, err
+	}
+	return &broker.PullRequest{Repo: item.Repo, Number: number, URL: item.PRURL}, nil
+}
+
+// poll looks at pr once. It reports true once pr merged, and an error when pr
+// will not merge: it was closed, or the checks of an auto-merged item failed.
+// Lookups that fail are logged and retried on the next poll.
+func (m *prMerger) poll(ctx context.Context, item state.ItemState, pr *broker.PullRequest) (bool, error) {
+	reverter, ok := m.broker.(broker.Reverter)
+	if !ok {
+		return false, fmt.Errorf("the configured provider cannot report whether %s merged", pr.URL)
+	}
+	ctx = withItemProvider(ctx, item.Provider)
+
+	status, err := reverter.PullRequestStatus(ctx, pr)
+	switch {
+	case err != nil:
+		if ctx.Err() == nil {
+			m.logger.Warn("Failed to check pull request", "pr", pr.URL, "error", err)
+		}
+		return false, nil
+	case status.Merged:
+		return true, nil
+	case status.State == broker.PullRequestClosed:
+		return false, fmt.Errorf("%s was closed without merging", pr.URL)
+	case item.AutoMerge == "":
+		return false, nil
+	}
+
+	reporter, ok := m.broker.(broker.ChecksReporter)
+	if !ok {
+		return false, fmt.Errorf("the configured provider cannot report the checks on %s to merge it", pr.URL)
+	}
+	checks, err := reporter.PullRequestChecks(ctx, pr)
+	switch {
+	case err != nil:
+		if ctx.Err() == nil {
+			m.logger.Warn("Failed to check pull request checks", "pr", pr.URL, "error", err)
+		}
+		return false, nil
+	case checks.State == broker.ChecksFailed:
+		return false, fmt.Errorf("checks failed on %s: %s", pr.URL, strings.Join(checks.Failed, ", "))
+	case checks.State == broker.ChecksPending:
+		return false, nil
+	}
+
+	result, err := m.broker.MergePR(ctx, pr, broker.MergeOptions{Method: broker.MergeMethod(item.AutoMerge)})
+	switch {
+	case errors.Is(err, broker.ErrNotMergeable):
+		// Usually a missing approval; the next poll tries again
+		m.logger.Debug("Pull request is not mergeable yet", "pr", pr.URL, "error", err)
+		return false, nil
+	case err != nil:
+		m.logger.Warn("Failed to merge pull request", "pr", pr.URL, "error", err)
+		return false, nil
+	case !result.Merged:
+		m.logger.Warn("Pull request was not merged", "pr", pr.URL, "reason", result.Message)
+		return false, nil
+	}
+	m.logger.Info("Merged pull request", "pr", pr.URL, "method", item.AutoMerge)
+	return true, nil
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/goliatone/cascade/internal/broker"
+	"github.com/goliatone/cascade/internal/state"
+)
+
+// autoMergeBroker reports pull request statuses like waveBroker and the given
+// check state on every poll.
+type autoMergeBroker struct {
+	*waveBroker
+	checkState string
+}
+
+func (b *autoMergeBroker) PullRequestChecks(ctx context.Context, pr *broker.PullRequest) (*broker.ChecksStatus, error) {
+	checks := &broker.ChecksStatus{State: b.checkState, Total: 1}
+	if checks.State == broker.ChecksFailed {
+		checks.Failed = []string{"test"}
+	}
+	return checks, nil
+}
+
+func TestPRMergerPoll(t *testing.T) {
+	pr := &broker.PullRequest{Repo: "example/core", Number: 7, URL: "https://github.com/example/core/pull/7"}
+	tests := []struct {
+		name       string
+		autoMerge  string
+		status     *broker.PullRequestStatus
+		checks     string
+		mergeErr   error
+		wantMerged bool
+		wantMerge  bool
+		wantErr    string
+	}{
+		{name: "merged", status: prMerged, wantMerged: true},
+		{name: "closed", status: prClosed, wantErr: "was closed without merging"},
+		{name: "open without auto-merge", status: prOpen, checks: broker.ChecksPassed},
+		{name: "checks pending", autoMerge: "squash", status: prOpen, checks: broker.ChecksPending},
+		{name: "checks failed", autoMerge: "squash", status: prOpen, checks: broker.ChecksFailed, wantErr: "checks failed on https://github.com/example/core/pull/7: test"},
+		{name: "checks passed", autoMerge: "squash", status: prOpen, checks: broker.ChecksPassed, wantMerged: true, wantMerge: true},
+		{name: "no checks", autoMerge: "rebase", status: prOpen, checks: broker.ChecksNone, wantMerged: true, wantMerge: true},
+		{name: "awaiting approval", autoMerge: "squash", status: prOpen, checks: broker.ChecksPassed, mergeErr: fmt.Errorf("merge: %w", broker.ErrNotMergeable), wantMerge: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var merges []broker.MergeOptions
+			base := &mockBroker{mergeFunc: func(ctx context.Context, pr *broker.PullRequest, opts broker.MergeOptions) (*broker.MergeResult, error) {
+				merges = append(merges, opts)
+				if tt.mergeErr != nil {
+					return nil, tt.mergeErr
+				}
+				return &broker.MergeResult{Merged: true}, nil
+			}}
+			brokerSvc := &autoMergeBroker{
+				waveBroker: &waveBroker{mockBroker: base, statuses: []*broker.PullRequestStatus{tt.status}},
+				checkState: tt.checks,
+			}
+			merger := &prMerger{broker: brokerSvc, logger: &mockLogger{}}
+
+			merged, err := merger.poll(context.Background(), state.ItemState{Repo: pr.Repo, PRURL: pr.URL, AutoMerge: tt.autoMerge}, pr)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("poll() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || merged != tt.wantMerged {
+				t.Fatalf("poll() = %v, %v, want %v", merged, err, tt.wantMerged)
+			}
+			if (len(merges) > 0) != tt.wantMerge {
+				t.Fatalf("merges = %+v, want merge %v", merges, tt.wantMerge)
+			}
+			if tt.wantMerge && merges[0].Method != broker.MergeMethod(tt.autoMerge) {
+				t.Errorf("merge method = %q, want %q", merges[0].Method, tt.autoMerge)
+			}
+		})
+	}
+
+	// Auto-merge needs the checks, which this broker cannot report
+	merger := &prMerger{broker: &waveBroker{mockBroker: &mockBroker{}, statuses: []*broker.PullRequestStatus{prOpen}}, logger: &mockLogger{}}
+	_, err := merger.poll(context.Background(), state.ItemState{Repo: pr.Repo, PRURL: pr.URL, AutoMerge: "merge"}, pr)
+	if err == nil || !strings.Contains(err.Error(), "cannot report the checks") {
+		t.Errorf("poll() error = %v, want an unsupported provider error", err)
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	execpkg "github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/internal/manifest"
+	"github.com/goliatone/cascade/internal/planner"
+	"github.com/goliatone/cascade/pkg/goproxy"
+)
+
+// applyGoPrivate adds the integration.goproxy.private patterns to GOPRIVATE,
+// and to GONOPROXY and GONOSUMDB when set, in the process environment. Every
+// go command cascade runs inherits it, so version resolution, the proxy
+// preflight, and dependents fetch private modules from their origin and never
+// send their paths to a proxy or the checksum database.
+func applyGoPrivate(patterns []string) error {
+	for key, value := range goproxy.PrivateEnv(patterns, os.Getenv) {
+		if err := os.Setenv(key, value); err != nil {
+			return fmt.Errorf("set %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// preflightGoProxy checks once that module@version resolves through the
+// effective GOPROXY before any dependent runs go get. When only a direct fetch
+// resolves it, the go-modules items are returned switched to fetching it
+// directly and marked with ProxyFallback. Items are returned unchanged when
+// none updates go.mod or the go tool cannot download modules on its own.
+func preflightGoProxy(ctx context.Context, w io.Writer, goTool execpkg.GoOperations, module, version string, items []planner.WorkItem) ([]planner.WorkItem, error) {
+	downloader, ok := goTool.(execpkg.ModuleDownloader)
+	if !ok || !updatesGoModules(items) {
+		return items, nil
+	}
+
+	result, err := execpkg.PreflightProxy(ctx, downloader, module, version, items)
+	if err != nil {
+		return nil, newExecutionError(fmt.Sprintf("%s@%s cannot be downloaded", module, version), err).
+			WithHint("check that the tag is pushed and that GOPROXY, GOPRIVATE, and dependent credentials are correct")
+	}
+	if !result.Fallback {
+		return items, nil
+	}
+
+	fmt.Fprintf(w, "%s GOPROXY cannot serve %s@%s; dependents will fetch it directly (GOPRIVATE, GOFLAGS=-mod=mod)\n", style.mark(markReview), module, version)
+	if reason, _, _ := strings.Cut(strings.TrimSpace(result.ProxyErr.Error()), "\n"); reason != "" {
+		fmt.Fprintf(w, "  proxy error: %s\n", reason)
+	}
+	return execpkg.ApplyProxyFallback(items, module), nil
+}
+
+// updatesGoModules reports whether any item updates a go.mod require.
+func updatesGoModules(items []planner.WorkItem) bool {
+	for _, item := range items {
+		if item.UpdateStrategy != manifest.UpdateStrategyGitSubmodule {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	execpkg "github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/internal/manifest"
+	"github.com/goliatone/cascade/internal/planner"
+	"github.com/goliatone/cascade/internal/state"
+)
+
+// preflightGoTool is a go tool whose downloads only succeed when the module
+// is fetched directly, or never when direct is false.
+type preflightGoTool struct {
+	direct    bool
+	downloads int
+}
+
+func (g *preflightGoTool) Get(ctx context.Context, repoPath, module, version string) error {
+	return nil
+}
+func (g *preflightGoTool) Tidy(ctx context.Context, repoPath string) error { return nil }
+
+func (g *preflightGoTool) Download(ctx context.Context, module, version string, env map[string]string) error {
+	g.downloads++
+	if g.direct && strings.Contains(env["GOPRIVATE"], module) {
+		return nil
+	}
+	return errors.New("reading https://proxy.golang.org/example.com/lib/@v/v1.2.3.info: 404 Not Found\nserver response: not found")
+}
+
+// plainGoTool cannot download modules on its own.
+type plainGoTool struct{}
+
+func (plainGoTool) Get(ctx context.Context, repoPath, module, version string) error { return nil }
+func (plainGoTool) Tidy(ctx context.Context, repoPath string) error                 { return nil }
+
+func TestPreflightGoProxyFallsBackToDirect(t *testing.T) {
+	t.Setenv("GOPRIVATE", "")
+	t.Setenv("GOFLAGS", "")
+	items := []planner.WorkItem{
+		{Repo: "org/a"},
+		{Repo: "org/b", UpdateStrategy: manifest.UpdateStrategyGitSubmodule},
+	}
+	goTool := &preflightGoTool{direct: true}
+
+	var out bytes.Buffer
+	got, err := preflightGoProxy(context.Background(), &out, goTool, "example.com/lib", "v1.2.3", items)
+	if err != nil {
+		t.Fatalf("preflightGoProxy() error = %v", err)
+	}
+	if !got[0].ProxyFallback || got[0].Env["GOPRIVATE"] != "example.com/lib" || got[0].GoFlags != "-mod=mod" {
+		t.Errorf("go-modules item = %+v, want a direct fetch", got[0])
+	}
+	if got[1].ProxyFallback {
+		t.Errorf("submodule item = %+v, want it unchanged", got[1])
+	}
+	if !strings.Contains(out.String(), "GOPROXY cannot serve example.com/lib@v1.2.3") ||
+		!strings.Contains(out.String(), "proxy error: reading https://proxy.golang.org") || strings.Contains(out.String(), "server response") {
+		t.Errorf("unexpected output:\n%s", out.String())
+	}
+}
+
+func TestPreflightGoProxyFailsFast(t *testing.T) {
+	_, err := preflightGoProxy(context.Background(), &bytes.Buffer{}, &preflightGoTool{}, "example.com/lib", "v1.2.3", []planner.WorkItem{{Repo: "org/a"}, {Repo: "org/b"}})
+	var cliErr *CLIError
+	if !errors.As(err, &cliErr) || cliErr.Code != ExitExecutionError || !strings.Contains(err.Error(), "cannot be downloaded") {
+		t.Errorf("preflightGoProxy() error = %v, want an execution error", err)
+	}
+}
+
+func TestPreflightGoProxySkips(t *testing.T) {
+	items := []planner.WorkItem{{Repo: "org/a"}}
+	got, err := preflightGoProxy(context.Background(), &bytes.Buffer{}, plainGoTool{}, "example.com/lib", "v1.2.3", items)
+	if err != nil || len(got) != 1 || got[0].ProxyFallback {
+		t.Errorf("preflightGoProxy() = %+v, %v, want items unchanged", got, err)
+	}
+
+	goTool := &preflightGoTool{}
+	submodules := []planner.WorkItem{{Repo: "org/b", UpdateStrategy: manifest.UpdateStrategyGitSubmodule}}
+	if _, err := preflightGoProxy(context.Background(), &bytes.Buffer{}, goTool, "example.com/lib", "v1.2.3", submodules); err != nil || goTool.downloads != 0 {
+		t.Errorf("preflightGoProxy() error = %v after %d downloads, want no preflight for submodule items", err, goTool.downloads)
+	}
+}
+
+func TestPrintItemOutcomeProxyFallback(t *testing.T) {
+	previous := style
+	style = outputStyle{width: 80}
+	defer func() { style = previous }()
+
+	var buf bytes.Buffer
+	printItemOutcome(&buf, state.ItemState{Status: execpkg.StatusCompleted, PRURL: "https://github.com/org/a/pull/1", ProxyFallback: true})
+	if !strings.Contains(buf.String(), "REVIEW Fetched the module directly because GOPROXY could not serve it") {
+		t.Errorf("unexpected outcome:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/goliatone/cascade/pkg/config"
+	"github.com/goliatone/cascade/pkg/goproxy"
+)
+
+// waitForProxy blocks until the configured module proxy serves module@version,
+// so dependents do not run go get before the proxy has indexed the new tag.
+// Asking for the version also makes mirroring proxies fetch it. It does
+// nothing when no proxy is configured.
+func waitForProxy(ctx context.Context, w io.Writer, cfg config.GoProxyConfig, client *http.Client, module, version string) error {
+	if cfg.URL == "" {
+		return nil
+	}
+
+	proxy := goproxy.New(cfg.URL, goproxy.WithHTTPClient(client), goproxy.WithToken(cfg.Username, cfg.Token))
+	if cfg.WaitTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.WaitTimeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	fmt.Fprintf(w, "Waiting up to %s for %s to serve %s@%s\n", cfg.WaitTimeout, proxy.URL(), module, version)
+	if _, err := proxy.Wait(ctx, module, version, cfg.PollInterval); err != nil {
+		return newExecutionError(fmt.Sprintf("%s@%s is not available on the module proxy", module, version), err).
+			WithHint("check that the tag is pushed and integration.goproxy is correct, or raise integration.goproxy.wait_timeout")
+	}
+	fmt.Fprintf(w, "%s@%s is available on the module proxy after %s\n", module, version, time.Since(start).Round(time.Second))
+	return nil
+}
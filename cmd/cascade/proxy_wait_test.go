@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/goliatone/cascade/pkg/config"
+)
+
+func TestWaitForProxyWithoutURL(t *testing.T) {
+	var out bytes.Buffer
+	if err := waitForProxy(context.Background(), &out, config.GoProxyConfig{}, nil, "example.com/lib", "v1.2.3"); err != nil {
+		t.Fatalf("waitForProxy() error = %v", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("expected no output without a proxy, got %q", out.String())
+	}
+}
+
+func TestWaitForProxyWaitsForVersion(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"Version":"v1.2.3"}`))
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	cfg := config.GoProxyConfig{URL: server.URL, WaitTimeout: time.Minute, PollInterval: time.Millisecond}
+	if err := waitForProxy(context.Background(), &out, cfg, server.Client(), "example.com/lib", "v1.2.3"); err != nil {
+		t.Fatalf("waitForProxy() error = %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("expected 3 proxy requests, got %d", calls.Load())
+	}
+	if !strings.Contains(out.String(), "example.com/lib@v1.2.3 is available on the module proxy") {
+		t.Errorf("expected availability message, got %q", out.String())
+	}
+}
+
+func TestWaitForProxyTimesOut(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	cfg := config.GoProxyConfig{URL: server.URL, WaitTimeout: 20 * time.Millisecond, PollInterval: time.Millisecond}
+	err := waitForProxy(context.Background(), &bytes.Buffer{}, cfg, server.Client(), "example.com/lib", "v1.2.3")
+	var cliErr *CLIError
+	if !errors.As(err, &cliErr) || cliErr.Code != ExitExecutionError {
+		t.Fatalf("expected execution error, got %v", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+}
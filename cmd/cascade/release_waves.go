@@ -0,0 +1,360 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/goliatone/cascade/internal/broker"
+	execpkg "github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/internal/manifest"
+	"github.com/goliatone/cascade/internal/planner"
+	"github.com/goliatone/cascade/internal/state"
+	"github.com/goliatone/cascade/pkg/config"
+	"github.com/goliatone/cascade/pkg/di"
+	"golang.org/x/mod/semver"
+)
+
+// defaultWaveResolution finds the tags of downstream modules when their
+// version_resolution only lists the local resolver, which never sees new tags.
+var defaultWaveResolution = []string{manifest.VersionResolverGitRemote, manifest.VersionResolverProxy}
+
+// moduleVersionFunc returns the newest tagged version of module.
+type moduleVersionFunc func(ctx context.Context, module *manifest.Module) (string, error)
+
+// waveRelease continues a multi-level release past its first wave. For each
+// downstream module, in graph order, it waits for the pull requests that
+// updated the module's repository to merge and for a newer version to be
+// tagged, then releases that version to the module's own dependents like a
+// release started for it, with its own state.
+type waveRelease struct {
+	graph        *planner.Graph
+	manifest     *manifest.Manifest
+	manifestPath string
+	root         planner.Target
+	cfg          *config.Config
+	deps         executionDeps
+	executor     execpkg.Executor
+	broker       broker.Broker
+	planner      planner.Planner
+	states       state.Manager
+	httpClient   *http.Client
+	logger       di.Logger
+	out          io.Writer
+	versions     moduleVersionFunc
+	sleep        func(ctx context.Context, d time.Duration) error
+
+	// baselines holds the version of each downstream module before the
+	// release, or the reason it could not be resolved
+	baselines map[string]string
+	unknown   map[string]string
+
+	// updates holds, per downstream module, the items that updated its repository
+	updates map[string][]state.ItemState
+
+	// cleanup holds the pull request URLs whose branch is deleted once merged
+	cleanup map[string]bool
+}
+
+// prepare records the current version of every downstream module, so a tag
+// pushed after its update merges can be told apart from an earlier one.
+func (w *waveRelease) prepare(ctx context.Context) {
+	w.baselines = make(map[string]string)
+	w.unknown = make(map[string]string)
+	w.updates = make(map[string][]state.ItemState)
+	w.cleanup = make(map[string]bool)
+	for _, wave := range w.waves() {
+		for _, module := range wave.Modules {
+			version, err := w.versions(ctx, w.graph.Module(module))
+			if err != nil {
+				w.unknown[module] = fmt.Sprintf("current version could not be resolved: %v", err)
+				continue
+			}
+			w.baselines[module] = version
+		}
+	}
+}
+
+// waves returns the waves after the first.
+func (w *waveRelease) waves() []planner.Wave {
+	waves := w.graph.Waves()
+	if len(waves) < 2 {
+		return nil
+	}
+	return waves[1:]
+}
+
+// record remembers itemState when item updated a downstream module's repository.
+func (w *waveRelease) record(item planner.WorkItem, itemState state.ItemState) {
+	if w == nil || item.Module == "" {
+		return
+	}
+	if level, ok := w.graph.Level(item.Module); !ok || level == 0 {
+		return
+	}
+	module := w.graph.Module(item.Module).Module
+	w.updates[module] = append(w.updates[module], itemState)
+	if item.PR.DeleteBranchOnMerge && itemState.PRURL != "" {
+		w.cleanup[itemState.PRURL] = true
+	}
+}
+
+// run releases the downstream modules wave by wave and returns their outcomes.
+func (w *waveRelease) run(ctx context.Context) []state.DownstreamRelease {
+	var outcomes []state.DownstreamRelease
+	for _, wave := range w.waves() {
+		fmt.Fprintf(w.out, "\nWave %d: %s\n", wave.Level, strings.Join(wave.Modules, ", "))
+		for _, module := range wave.Modules {
+			outcome := w.release(ctx, wave.Level, module)
+			if outcome.Reason != "" {
+				fmt.Fprintf(w.out, "  %s %s not released: %s\n", style.mark(markSkipped), module, outcome.Reason)
+			}
+			outcomes = append(outcomes, outcome)
+		}
+	}
+	return outcomes
+}
+
+// release waits for module's updates to merge and a new tag, then releases it.
+func (w *waveRelease) release(ctx context.Context, level int, module string) state.DownstreamRelease {
+	outcome := state.DownstreamRelease{Module: module, Wave: level}
+	if reason, ok := w.unknown[module]; ok {
+		outcome.Reason = reason
+		return outcome
+	}
+
+	updates := w.updates[module]
+	if len(updates) == 0 {
+		outcome.Reason = "its repository was not updated by this release"
+		return outcome
+	}
+	for _, update := range updates {
+		if update.PRURL == "" {
+			outcome.Reason = fmt.Sprintf("the update of %s has no pull request (%s)", update.Repo, update.Status)
+			return outcome
+		}
+	}
+
+	waitCtx := ctx
+	if w.cfg.Executor.WaveTimeout > 0 {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(ctx, w.cfg.Executor.WaveTimeout)
+		defer cancel()
+	}
+
+	for _, update := range updates {
+		fmt.Fprintf(w.out, "  Waiting for %s to merge\n", update.PRURL)
+		if err := w.waitMerged(waitCtx, update); err != nil {
+			outcome.Reason = err.Error()
+			return outcome
+		}
+		if w.cleanup[update.PRURL] {
+			w.deleteBranch(ctx, update)
+		}
+	}
+
+	baseline := w.baselines[module]
+	fmt.Fprintf(w.out, "  Waiting for a version of %s newer than %s\n", module, baseline)
+	version, err := w.waitTagged(waitCtx, module, baseline)
+	if err != nil {
+		outcome.Reason = err.Error()
+		return outcome
+	}
+	outcome.Version = version
+
+	if err := w.releaseVersion(ctx, level, planner.Target{Module: module, Version: version}); err != nil {
+		outcome.Reason = err.Error()
+	}
+	return outcome
+}
+
+// waitMerged polls the pull request of update until it is merged, merging it
+// once its checks pass when pr.auto_merge is set. A pull request closed without
+// merging, or whose checks failed, ends the wait.
+func (w *waveRelease) waitMerged(ctx context.Context, update state.ItemState) error {
+	pr, err := itemPullRequest(update)
+	if err != nil {
+		return err
+	}
+	merger := &prMerger{broker: w.broker, logger: w.logger}
+
+	for {
+		merged, err := merger.poll(ctx, update, pr)
+		if err != nil || merged {
+			return err
+		}
+		if err := w.sleep(ctx, w.cfg.Executor.WavePollInterval); err != nil {
+			return fmt.Errorf("timed out waiting for %s to merge", pr.URL)
+		}
+	}
+}
+
+// deleteBranch deletes the branch of a merged update, for dependents with
+// pr.delete_branch_on_merge. Providers that delete it on merge themselves are
+// left to it, and failures only leave the branch behind.
+func (w *waveRelease) deleteBranch(ctx context.Context, update state.ItemState) {
+	cleaner, ok := w.broker.(broker.BranchCleaner)
+	if !ok {
+		return
+	}
+	err := cleaner.DeleteBranch(withItemProvider(ctx, update.Provider), update.Repo, update.Branch)
+	var notImplemented *broker.NotImplementedError
+	switch {
+	case errors.As(err, &notImplemented):
+	case err != nil:
+		w.logger.Warn("Failed to delete merged branch", "repo", update.Repo, "branch", update.Branch, "error", err)
+	default:
+		fmt.Fprintf(w.out, "  Branch %s of %s is deleted\n", update.Branch, update.Repo)
+	}
+}
+
+// waitTagged polls the versions of module until one newer than baseline is
+// tagged, and returns it.
+func (w *waveRelease) waitTagged(ctx context.Context, module, baseline string) (string, error) {
+	for {
+		version, err := w.versions(ctx, w.graph.Module(module))
+		switch {
+		case err != nil:
+			if ctx.Err() != nil {
+				return "", fmt.Errorf("timed out waiting for a version of %s newer than %s", module, baseline)
+			}
+			w.logger.Warn("Failed to resolve module version", "module", module, "error", err)
+		case semver.Compare(version, baseline) > 0:
+			return version, nil
+		}
+		if err := w.sleep(ctx, w.cfg.Executor.WavePollInterval); err != nil {
+			return "", fmt.Errorf("timed out waiting for a version of %s newer than %s", module, baseline)
+		}
+	}
+}
+
+// releaseVersion releases target to its dependents as one wave of the cascade.
+// It plans, executes, and records state like a release started for target.
+func (w *waveRelease) releaseVersion(ctx context.Context, level int, target planner.Target) error {
+	guard, err := acquireRunLock(ctx, w.out, w.states, target.Module, target.Version, 0)
+	if err != nil {
+		return err
+	}
+	if guard != nil {
+		defer guard.Release()
+	}
+
+	plan, err := w.planner.Plan(ctx, w.manifest, target)
+	if err != nil {
+		return fmt.Errorf("failed to plan %s@%s: %w", target.Module, target.Version, err)
+	}
+	if len(plan.Items) == 0 {
+		fmt.Fprintf(w.out, "  No work items produced for %s@%s\n", target.Module, target.Version)
+		return nil
+	}
+	for i := range plan.Items {
+		plan.Items[i].Wave = level
+	}
+
+	if err := waitForProxy(ctx, w.out, w.cfg.Integration.GoProxy, w.httpClient, target.Module, target.Version); err != nil {
+		return err
+	}
+	items, err := preflightGoProxy(ctx, w.out, w.deps.goTool, target.Module, target.Version, plan.Items)
+	if err != nil {
+		return err
+	}
+
+	summary := &state.Summary{
+		Module:    target.Module,
+		Version:   target.Version,
+		StartTime: time.Now(),
+		Upstream:  w.root.Module + "@" + w.root.Version,
+		Wave:      level,
+	}
+	if len(plan.Stats.SkippedUpToDateRepos) > 0 {
+		summary.SkippedUpToDate = append([]string(nil), plan.Stats.SkippedUpToDateRepos...)
+	}
+	summary.Inputs = captureRunInputs(w.states, target.Module, target.Version, w.manifestPath, w.cfg, w.logger)
+	tracker := newStateTracker(target.Module, target.Version, summary, w.states, w.logger, nil)
+
+	fmt.Fprintf(w.out, "  Executing updates for %s@%s\n", target.Module, target.Version)
+	held, holdErr := newCanaryRollout(w.cfg, w.broker, w.out, w.logger).run(ctx, items, func(items []planner.WorkItem, offset int) []state.ItemState {
+		var states []state.ItemState
+		runWorkItems(ctx, w.cfg, w.deps, items, w.executor, w.broker, w.logger, tracker, func(i int, item planner.WorkItem, itemState state.ItemState, err error) {
+			fmt.Fprintf(w.out, "  %d. %s (%s) -> %s\n", offset+i+1, item.Repo, item.Module, item.BranchName)
+			if err != nil {
+				w.logger.Warn("Work item completed with errors", "repo", item.Repo, "error", err)
+			}
+			printItemOutcome(w.out, itemState)
+			w.record(item, itemState)
+			states = append(states, itemState)
+		})
+		return states
+	})
+	tracker.finalize()
+	if holdErr != nil {
+		return fmt.Errorf("%d dependents held back: %w", len(held), holdErr)
+	}
+	return nil
+}
+
+// latestModuleVersion resolves the newest tagged version of a module with its
+// version_resolution chain, leaving out the local resolver.
+func latestModuleVersion(cfg *config.Config, logger di.Logger) moduleVersionFunc {
+	return func(ctx context.Context, module *manifest.Module) (string, error) {
+		names := slices.DeleteFunc(slices.Clone(module.VersionResolution), func(name string) bool {
+			return strings.TrimSpace(name) == manifest.VersionResolverLocal
+		})
+		if len(names) == 0 {
+			names = defaultWaveResolution
+		}
+
+		env := manifest.VersionResolverEnv{}
+		if client, err := newGitHubClient(ctx, cfg); err == nil {
+			env.GitHub = manifest.NewGitHubDiscovery(client)
+		} else if logger != nil {
+			logger.Debug("Version resolvers run without a GitHub client", "reason", err.Error())
+		}
+
+		chain, err := manifest.NewVersionResolverChain(names, env)
+		if err != nil {
+			return "", err
+		}
+		resolution, err := chain.ResolveVersion(ctx, manifest.VersionRequest{Module: module.Module, Repository: module.Repo})
+		if err != nil {
+			return "", err
+		}
+		return resolution.Version, nil
+	}
+}
+
+// sleepContext waits for d or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// printPlanWaves lists the waves of a multi-level release. Nothing is printed
+// when no dependent is a module with dependents of its own.
+func printPlanWaves(w io.Writer, waves []planner.Wave) {
+	if len(waves) < 2 {
+		return
+	}
+	fmt.Fprintf(w, "\nWaves (%d):\n", len(waves))
+	for _, wave := range waves {
+		when := "now"
+		if wave.Level > 0 {
+			when = "after their updates merge and are tagged"
+		}
+		fmt.Fprintf(w, "  %d. %s (%s)\n", wave.Level, strings.Join(wave.Modules, ", "), when)
+		if len(wave.Repos) > 0 {
+			fmt.Fprintf(w, "     -> %s\n", strings.Join(wave.Repos, ", "))
+		}
+	}
+}
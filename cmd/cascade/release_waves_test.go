@@ -0,0 +1,338 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goliatone/cascade/internal/broker"
+	execpkg "github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/internal/manifest"
+	"github.com/goliatone/cascade/internal/planner"
+	"github.com/goliatone/cascade/internal/state"
+	"github.com/goliatone/cascade/pkg/config"
+)
+
+// waveManifest describes lib <- core <- api, each in its own repository.
+func waveManifest() *manifest.Manifest {
+	return &manifest.Manifest{
+		ManifestVersion: 1,
+		Modules: []manifest.Module{
+			{Module: "example.com/lib", Repo: "example/lib", Dependents: []manifest.Dependent{{Repo: "example/core", Module: "example.com/core"}}},
+			{Module: "example.com/core", Repo: "example/core", Dependents: []manifest.Dependent{{Repo: "example/api", Module: "example.com/api"}}},
+			{Module: "example.com/api", Repo: "example/api", Dependents: []manifest.Dependent{{Repo: "example/web", Module: "example.com/web"}}},
+		},
+	}
+}
+
+// waveBroker reports the given statuses in turn, repeating the last one.
+type waveBroker struct {
+	*mockBroker
+	statuses []*broker.PullRequestStatus
+	checks   int
+}
+
+func (b *waveBroker) PullRequestStatus(ctx context.Context, pr *broker.PullRequest) (*broker.PullRequestStatus, error) {
+	status := b.statuses[min(b.checks, len(b.statuses)-1)]
+	b.checks++
+	return status, nil
+}
+
+func (b *waveBroker) ClosePRWithComment(ctx context.Context, pr *broker.PullRequest, body string) error {
+	return nil
+}
+
+func (b *waveBroker) OpenPR(ctx context.Context, input broker.PRInput) (*broker.PullRequest, error) {
+	return nil, nil
+}
+
+var (
+	prOpen   = &broker.PullRequestStatus{State: broker.PullRequestOpen}
+	prMerged = &broker.PullRequestStatus{State: broker.PullRequestClosed, Merged: true}
+	prClosed = &broker.PullRequestStatus{State: broker.PullRequestClosed}
+)
+
+// newTestWaveRelease returns a waveRelease for lib@v1.2.0 whose sleeps end
+// the wait after polls of them.
+func newTestWaveRelease(t *testing.T, brokerSvc broker.Broker, polls int) *waveRelease {
+	t.Helper()
+	m := waveManifest()
+	graph, err := planner.BuildGraph(m, "example.com/lib")
+	if err != nil {
+		t.Fatalf("BuildGraph() error = %v", err)
+	}
+	cfg := config.New()
+	cfg.Executor.WavePollInterval = time.Minute
+
+	slept := 0
+	return &waveRelease{
+		graph:    graph,
+		manifest: m,
+		root:     planner.Target{Module: "example.com/lib", Version: "v1.2.0"},
+		cfg:      cfg,
+		broker:   brokerSvc,
+		planner:  &mockPlanner{},
+		states:   &mockStateManager{},
+		logger:   &mockLogger{},
+		out:      &bytes.Buffer{},
+		versions: func(ctx context.Context, module *manifest.Module) (string, error) {
+			return "v1.0.0", nil
+		},
+		sleep: func(ctx context.Context, d time.Duration) error {
+			if slept++; slept > polls {
+				return context.DeadlineExceeded
+			}
+			return nil
+		},
+	}
+}
+
+func TestWaveReleaseWaitMerged(t *testing.T) {
+	update := state.ItemState{Repo: "example/core", PRURL: "https://github.com/example/core/pull/7"}
+	tests := []struct {
+		name     string
+		statuses []*broker.PullRequestStatus
+		wantErr  string
+	}{
+		{name: "merged after polling", statuses: []*broker.PullRequestStatus{prOpen, prOpen, prMerged}},
+		{name: "closed without merging", statuses: []*broker.PullRequestStatus{prOpen, prClosed}, wantErr: "was closed without merging"},
+		{name: "timed out", statuses: []*broker.PullRequestStatus{prOpen}, wantErr: "timed out waiting for https://github.com/example/core/pull/7 to merge"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			brokerSvc := &waveBroker{mockBroker: &mockBroker{}, statuses: tt.statuses}
+			w := newTestWaveRelease(t, brokerSvc, 3)
+
+			err := w.waitMerged(context.Background(), update)
+			if tt.wantErr == "" {
+				if err != nil || brokerSvc.checks != len(tt.statuses) {
+					t.Errorf("waitMerged() = %v after %d checks", err, brokerSvc.checks)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("waitMerged() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	w := newTestWaveRelease(t, &mockBroker{}, 0)
+	if err := w.waitMerged(context.Background(), update); err == nil || !strings.Contains(err.Error(), "cannot report whether") {
+		t.Errorf("waitMerged() error = %v, want an unsupported provider error", err)
+	}
+}
+
+func TestWaveReleaseWaitTagged(t *testing.T) {
+	w := newTestWaveRelease(t, &mockBroker{}, 5)
+	versions := []string{"v1.0.0", "", "v1.1.0"}
+	calls := 0
+	w.versions = func(ctx context.Context, module *manifest.Module) (string, error) {
+		version := versions[calls]
+		calls++
+		if version == "" {
+			return "", errors.New("proxy unavailable")
+		}
+		return version, nil
+	}
+
+	version, err := w.waitTagged(context.Background(), "example.com/core", "v1.0.0")
+	if err != nil || version != "v1.1.0" || calls != 3 {
+		t.Errorf("waitTagged() = %q, %v after %d calls, want v1.1.0", version, err, calls)
+	}
+
+	w = newTestWaveRelease(t, &mockBroker{}, 2)
+	if _, err := w.waitTagged(context.Background(), "example.com/core", "v1.0.0"); err == nil || !strings.Contains(err.Error(), "timed out waiting for a version of example.com/core newer than v1.0.0") {
+		t.Errorf("waitTagged() error = %v, want a timeout", err)
+	}
+}
+
+func TestWaveReleaseSkipsModules(t *testing.T) {
+	update := state.ItemState{Repo: "example/core", Status: execpkg.StatusCompleted, PRURL: "https://github.com/example/core/pull/7"}
+	tests := []struct {
+		name    string
+		setup   func(w *waveRelease)
+		wantErr string
+	}{
+		{
+			name: "unknown baseline",
+			setup: func(w *waveRelease) {
+				w.unknown["example.com/core"] = "current version could not be resolved: no tags"
+			},
+			wantErr: "current version could not be resolved",
+		},
+		{
+			name:    "not updated",
+			setup:   func(w *waveRelease) {},
+			wantErr: "its repository was not updated by this release",
+		},
+		{
+			name: "update without a pull request",
+			setup: func(w *waveRelease) {
+				w.updates["example.com/core"] = []state.ItemState{{Repo: "example/core", Status: execpkg.StatusFailed}}
+			},
+			wantErr: "the update of example/core has no pull request (failed)",
+		},
+		{
+			name: "pull request closed",
+			setup: func(w *waveRelease) {
+				w.broker = &waveBroker{mockBroker: &mockBroker{}, statuses: []*broker.PullRequestStatus{prClosed}}
+				w.updates["example.com/core"] = []state.ItemState{update}
+			},
+			wantErr: "was closed without merging",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := newTestWaveRelease(t, &mockBroker{}, 0)
+			w.prepare(context.Background())
+			tt.setup(w)
+
+			outcome := w.release(context.Background(), 1, "example.com/core")
+			if outcome.Module != "example.com/core" || outcome.Wave != 1 || !strings.Contains(outcome.Reason, tt.wantErr) {
+				t.Errorf("release() = %+v, want reason %q", outcome, tt.wantErr)
+			}
+		})
+	}
+}
+
+// cleanupWaveBroker records the branches deleted after merges.
+type cleanupWaveBroker struct {
+	*waveBroker
+	deleted []string
+}
+
+func (b *cleanupWaveBroker) DeleteBranch(ctx context.Context, repo, branch string) error {
+	b.deleted = append(b.deleted, repo+"@"+branch)
+	return nil
+}
+
+func TestWaveReleaseDeletesMergedBranches(t *testing.T) {
+	brokerSvc := &cleanupWaveBroker{waveBroker: &waveBroker{mockBroker: &mockBroker{}, statuses: []*broker.PullRequestStatus{prMerged}}}
+	w := newTestWaveRelease(t, brokerSvc, 0)
+	w.prepare(context.Background())
+
+	item := planner.WorkItem{Repo: "example/core", Module: "example.com/core", PR: manifest.PRConfig{DeleteBranchOnMerge: true}}
+	w.record(item, state.ItemState{Repo: "example/core", Branch: "cascade/update", Status: execpkg.StatusCompleted, PRURL: "https://github.com/example/core/pull/7"})
+	item = planner.WorkItem{Repo: "example/api", Module: "example.com/api"}
+	w.record(item, state.ItemState{Repo: "example/api", Branch: "cascade/update", Status: execpkg.StatusCompleted, PRURL: "https://github.com/example/api/pull/8"})
+
+	// No new tag arrives, but the merged branches are cleaned up first
+	w.release(context.Background(), 1, "example.com/core")
+	w.release(context.Background(), 2, "example.com/api")
+	if len(brokerSvc.deleted) != 1 || brokerSvc.deleted[0] != "example/core@cascade/update" {
+		t.Errorf("deleted = %v, want only the branch of the core update", brokerSvc.deleted)
+	}
+	if out := w.out.(*bytes.Buffer).String(); !strings.Contains(out, "Branch cascade/update of example/core is deleted") {
+		t.Errorf("output does not report the deletion:\n%s", out)
+	}
+}
+
+func TestWaveReleaseRunsWaves(t *testing.T) {
+	previous := style
+	style = outputStyle{width: 120}
+	defer func() { style = previous }()
+
+	brokerSvc := &waveBroker{
+		mockBroker: &mockBroker{
+			ensurePRFunc: func(ctx context.Context, item planner.WorkItem, result *execpkg.Result) (*broker.PullRequest, error) {
+				return &broker.PullRequest{Repo: item.Repo, URL: "https://github.com/" + item.Repo + "/pull/3"}, nil
+			},
+		},
+		statuses: []*broker.PullRequestStatus{prMerged},
+	}
+	w := newTestWaveRelease(t, brokerSvc, 0)
+	w.states = newIssueTrackingManager(t)
+	w.executor = &mockExecutor{
+		applyFunc: func(ctx context.Context, input execpkg.WorkItemContext) (*execpkg.Result, error) {
+			return &execpkg.Result{Status: execpkg.StatusCompleted}, nil
+		},
+	}
+	w.planner = &mockPlanner{planFunc: func(ctx context.Context, m *manifest.Manifest, target planner.Target) (*planner.Plan, error) {
+		dep := m.Modules[1].Dependents[0]
+		if target.Module == "example.com/api" {
+			dep = m.Modules[2].Dependents[0]
+		}
+		return &planner.Plan{Target: target, Items: []planner.WorkItem{{Repo: dep.Repo, Module: dep.Module, BranchName: "update"}}}, nil
+	}}
+
+	// Both downstream modules start at v1.0.0 and are tagged v1.1.0 once
+	// their updates merge
+	tagged := false
+	w.versions = func(ctx context.Context, module *manifest.Module) (string, error) {
+		if tagged {
+			return "v1.1.0", nil
+		}
+		return "v1.0.0", nil
+	}
+	w.prepare(context.Background())
+	tagged = true
+
+	// The first wave updated core's repository
+	w.record(planner.WorkItem{Repo: "example/core", Module: "example.com/core"}, state.ItemState{Repo: "example/core", Status: execpkg.StatusCompleted, PRURL: "https://github.com/example/core/pull/7"})
+	w.record(planner.WorkItem{Repo: "example/other", Module: "example.com/other"}, state.ItemState{Repo: "example/other"})
+
+	outcomes := w.run(context.Background())
+	if len(outcomes) != 2 {
+		t.Fatalf("outcomes = %+v, want core and api", outcomes)
+	}
+	for i, want := range []state.DownstreamRelease{
+		{Module: "example.com/core", Wave: 1, Version: "v1.1.0"},
+		{Module: "example.com/api", Wave: 2, Version: "v1.1.0"},
+	} {
+		if outcomes[i] != want {
+			t.Errorf("outcome %d = %+v, want %+v", i, outcomes[i], want)
+		}
+	}
+
+	summary, err := w.states.LoadSummary("example.com/api", "v1.1.0")
+	if err != nil {
+		t.Fatalf("LoadSummary() error = %v", err)
+	}
+	if summary.Upstream != "example.com/lib@v1.2.0" || summary.Wave != 2 {
+		t.Errorf("summary = %+v, want upstream lib@v1.2.0 in wave 2", summary)
+	}
+	items, err := w.states.LoadItemStates("example.com/api", "v1.1.0")
+	if err != nil || len(items) != 1 || items[0].Repo != "example/web" {
+		t.Errorf("item states = %+v, %v, want the web update", items, err)
+	}
+
+	out := w.out.(*bytes.Buffer).String()
+	for _, want := range []string{
+		"Wave 1: example.com/core",
+		"Waiting for https://github.com/example/core/pull/7 to merge",
+		"Waiting for a version of example.com/core newer than v1.0.0",
+		"1. example/api (example.com/api) -> update",
+		"Wave 2: example.com/api",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestPrintPlanWaves(t *testing.T) {
+	var out bytes.Buffer
+	printPlanWaves(&out, []planner.Wave{{Level: 0, Modules: []string{"example.com/lib"}}})
+	if out.Len() != 0 {
+		t.Errorf("single wave printed:\n%s", out.String())
+	}
+
+	printPlanWaves(&out, []planner.Wave{
+		{Level: 0, Modules: []string{"example.com/lib"}, Repos: []string{"example/core"}},
+		{Level: 1, Modules: []string{"example.com/core"}, Repos: []string{"example/api", "example/app"}},
+	})
+	for _, want := range []string{
+		"Waves (2):",
+		"0. example.com/lib (now)\n     -> example/core",
+		"1. example.com/core (after their updates merge and are tagged)\n     -> example/api, example/app",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/goliatone/cascade/internal/manifest"
+	"github.com/goliatone/cascade/internal/state"
+	"github.com/goliatone/cascade/pkg/config"
+	"github.com/goliatone/cascade/pkg/di"
+	"github.com/goliatone/cascade/pkg/version"
+)
+
+// captureRunInputs stores the manifest, the redacted config, and the cascade
+// version a run starts with, and returns their digest. It returns "" when the
+// state backend does not keep inputs or they could not be saved; the run goes
+// ahead either way.
+func captureRunInputs(manager state.Manager, module, moduleVersion, manifestPath string, cfg *config.Config, logger di.Logger) string {
+	store, ok := manager.(state.InputStore)
+	if !ok {
+		return ""
+	}
+
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		logger.Warn("Failed to read manifest for run inputs", "path", manifestPath, "error", err)
+		return ""
+	}
+	inputs, err := state.NewInputs(version.Tag, manifestPath, data, cfg.Redacted())
+	if err != nil {
+		logger.Warn("Failed to capture run inputs", "error", err)
+		return ""
+	}
+	if err := store.SaveInputs(module, moduleVersion, inputs); err != nil {
+		if !errors.Is(err, state.ErrNotImplemented) {
+			logger.Warn("Failed to save run inputs", "module", module, "version", moduleVersion, "error", err)
+		}
+		return ""
+	}
+	return inputs.Digest
+}
+
+// loadResumeManifest returns the manifest a resume plans with and the digest of
+// the inputs it corresponds to. The copy stored with the summary is preferred, so
+// a resume repeats the original run even if the manifest on disk has changed
+// since. The file at manifestPath is used when useCurrent is set or no stored
+// copy is available, and outside dry runs is captured as the inputs of this
+// attempt.
+func loadResumeManifest(w io.Writer, manager state.Manager, loader manifest.Loader, summary *state.Summary, manifestPath string, useCurrent bool, cfg *config.Config, logger di.Logger) (*manifest.Manifest, string, error) {
+	if !useCurrent && summary.Inputs != "" {
+		inputs, err := loadStoredInputs(manager, summary)
+		switch {
+		case err == nil:
+			m, err := manifest.Parse("stored inputs "+shortDigest(inputs.Digest), []byte(inputs.Manifest))
+			if err != nil {
+				return nil, "", newStateError("failed to parse the stored manifest", err).
+					WithHint("pass --current-manifest to plan with the manifest on disk")
+			}
+			reportInputDrift(w, inputs, manifestPath)
+			return m, inputs.Digest, nil
+		case errors.Is(err, state.ErrNotFound), errors.Is(err, state.ErrNotImplemented):
+			logger.Warn("Stored run inputs are unavailable; using the current manifest", "inputs", summary.Inputs)
+		default:
+			return nil, "", newStateError("failed to load stored run inputs", err).
+				WithHint("pass --current-manifest to plan with the manifest on disk")
+		}
+	}
+
+	m, err := loader.Load(manifestPath)
+	if err != nil {
+		return nil, "", newFileError("failed to load manifest", err)
+	}
+	if cfg.Executor.DryRun {
+		return m, "", nil
+	}
+	return m, captureRunInputs(manager, summary.Module, summary.Version, manifestPath, cfg, logger), nil
+}
+
+func loadStoredInputs(manager state.Manager, summary *state.Summary) (*state.Inputs, error) {
+	store, ok := manager.(state.InputStore)
+	if !ok {
+		return nil, state.ErrNotImplemented
+	}
+	return store.LoadInputs(summary.Module, summary.Version, summary.Inputs)
+}
+
+// reportInputDrift tells the user when the manifest on disk or the running
+// cascade differ from the stored inputs a resume is about to reuse.
+func reportInputDrift(w io.Writer, inputs *state.Inputs, manifestPath string) {
+	fmt.Fprintf(w, "Using run inputs %s recorded by the original release\n", shortDigest(inputs.Digest))
+	if data, err := os.ReadFile(manifestPath); err == nil && state.ManifestDigest(data) != inputs.ManifestDigest() {
+		fmt.Fprintf(w, "  Note: %s changed since then; pass --current-manifest to plan with it instead\n", manifestPath)
+	}
+	if inputs.CascadeVersion != "" && inputs.CascadeVersion != version.Tag {
+		fmt.Fprintf(w, "  Note: recorded with cascade %s, running %s\n", inputs.CascadeVersion, version.Tag)
+	}
+}
+
+// shortDigest abbreviates an inputs digest for display.
+func shortDigest(digest string) string {
+	if len(digest) > 12 {
+		return digest[:12]
+	}
+	return digest
+}
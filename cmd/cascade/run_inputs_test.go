@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/goliatone/cascade/internal/manifest"
+	"github.com/goliatone/cascade/internal/state"
+	"github.com/goliatone/cascade/pkg/config"
+)
+
+const inputsTestManifest = `manifest_version: 1
+modules:
+  - name: lib
+    module: example.com/lib
+    repo: example/lib
+    dependents:
+      - repo: example/app
+        module: example.com/app
+`
+
+func TestCaptureRunInputsRedactsConfig(t *testing.T) {
+	manager := newIssueTrackingManager(t)
+	manifestPath := filepath.Join(t.TempDir(), ".cascade.yaml")
+	if err := os.WriteFile(manifestPath, []byte(inputsTestManifest), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.New()
+	cfg.Integration.GitHub.Token = "ghp_secret"
+
+	digest := captureRunInputs(manager, "example.com/lib", "v1.0.0", manifestPath, cfg, &mockLogger{})
+	if digest == "" {
+		t.Fatal("expected inputs to be captured")
+	}
+
+	inputs, err := manager.(state.InputStore).LoadInputs("example.com/lib", "v1.0.0", digest)
+	if err != nil {
+		t.Fatalf("LoadInputs() error = %v", err)
+	}
+	if inputs.Manifest != inputsTestManifest || inputs.ManifestPath != manifestPath {
+		t.Errorf("stored manifest = %q from %q", inputs.Manifest, inputs.ManifestPath)
+	}
+	if strings.Contains(string(inputs.Config), "ghp_secret") || !strings.Contains(string(inputs.Config), config.RedactedValue) {
+		t.Errorf("expected the stored config to be redacted, got %s", inputs.Config)
+	}
+
+	if got := captureRunInputs(manager, "example.com/lib", "v1.0.0", filepath.Join(t.TempDir(), "missing.yaml"), cfg, &mockLogger{}); got != "" {
+		t.Errorf("expected no digest for an unreadable manifest, got %q", got)
+	}
+}
+
+func TestLoadResumeManifestPrefersStoredCopy(t *testing.T) {
+	manager := newIssueTrackingManager(t)
+	manifestPath := filepath.Join(t.TempDir(), ".cascade.yaml")
+	if err := os.WriteFile(manifestPath, []byte(inputsTestManifest), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.New()
+	summary := &state.Summary{Module: "example.com/lib", Version: "v1.0.0"}
+	summary.Inputs = captureRunInputs(manager, summary.Module, summary.Version, manifestPath, cfg, &mockLogger{})
+
+	// The manifest is edited after the release
+	edited := strings.Replace(inputsTestManifest, "example/app", "example/other", 1)
+	if err := os.WriteFile(manifestPath, []byte(edited), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	m, digest, err := loadResumeManifest(&out, manager, manifest.NewLoader(), summary, manifestPath, false, cfg, &mockLogger{})
+	if err != nil {
+		t.Fatalf("loadResumeManifest() error = %v", err)
+	}
+	if digest != summary.Inputs {
+		t.Errorf("digest = %s, want the release inputs %s", digest, summary.Inputs)
+	}
+	if got := m.Modules[0].Dependents[0].Repo; got != "example/app" {
+		t.Errorf("planned with dependent %s, want the stored example/app", got)
+	}
+	if !strings.Contains(out.String(), "changed since then") {
+		t.Errorf("expected a note about the edited manifest, got:\n%s", out.String())
+	}
+
+	out.Reset()
+	m, digest, err = loadResumeManifest(&out, manager, manifest.NewLoader(), summary, manifestPath, true, cfg, &mockLogger{})
+	if err != nil {
+		t.Fatalf("loadResumeManifest(current) error = %v", err)
+	}
+	if got := m.Modules[0].Dependents[0].Repo; got != "example/other" {
+		t.Errorf("planned with dependent %s, want the edited example/other", got)
+	}
+	if digest == "" || digest == summary.Inputs {
+		t.Errorf("expected the current manifest to be captured as new inputs, got %q", digest)
+	}
+}
+
+func TestLoadResumeManifestFallsBackWhenInputsMissing(t *testing.T) {
+	manager := newIssueTrackingManager(t)
+	manifestPath := filepath.Join(t.TempDir(), ".cascade.yaml")
+	if err := os.WriteFile(manifestPath, []byte(inputsTestManifest), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.New()
+	cfg.Executor.DryRun = true
+	summary := &state.Summary{Module: "example.com/lib", Version: "v1.0.0", Inputs: strings.Repeat("0", 64)}
+
+	m, digest, err := loadResumeManifest(&bytes.Buffer{}, manager, manifest.NewLoader(), summary, manifestPath, false, cfg, &mockLogger{})
+	if err != nil {
+		t.Fatalf("loadResumeManifest() error = %v", err)
+	}
+	if len(m.Modules) != 1 {
+		t.Errorf("expected the manifest on disk to be loaded, got %+v", m.Modules)
+	}
+	if digest != "" {
+		t.Errorf("expected dry runs not to capture inputs, got %q", digest)
+	}
+}
+
+func TestRenderRunInputs(t *testing.T) {
+	inputs, err := state.NewInputs("v1.2.0", ".cascade.yaml", []byte(inputsTestManifest), map[string]string{"module": "example.com/lib"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := renderRunInputs(&out, inputs, false); err != nil {
+		t.Fatalf("renderRunInputs() error = %v", err)
+	}
+	for _, want := range []string{"# Inputs:   " + inputs.Digest, "# Cascade:  v1.2.0", "# Manifest: .cascade.yaml", inputsTestManifest} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("manifest output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	out.Reset()
+	if err := renderRunInputs(&out, inputs, true); err != nil {
+		t.Fatalf("renderRunInputs(config) error = %v", err)
+	}
+	if !strings.Contains(out.String(), `"module": "example.com/lib"`) || strings.Contains(out.String(), "dependents:") {
+		t.Errorf("expected only the indented config, got:\n%s", out.String())
+	}
+}
+
+func TestStateTrackerRecordsRunInputs(t *testing.T) {
+	manager := newIssueTrackingManager(t)
+	digest := strings.Repeat("a", 64)
+	summary := &state.Summary{Module: "example.com/lib", Version: "v1.0.0", Inputs: digest}
+
+	tracker := newStateTracker(summary.Module, summary.Version, summary, manager, &mockLogger{}, nil)
+	tracker.finalize()
+
+	runs, err := manager.(state.RunStore).LoadRuns(summary.Module, summary.Version)
+	if err != nil || len(runs) != 1 {
+		t.Fatalf("LoadRuns() = %v, %v, want one run", runs, err)
+	}
+	if runs[0].Inputs != digest {
+		t.Errorf("run inputs = %q, want %q", runs[0].Inputs, digest)
+	}
+}
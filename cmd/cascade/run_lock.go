@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/goliatone/cascade/internal/state"
+)
+
+var (
+	// lockPollInterval is how often a queued run retries the run lock.
+	lockPollInterval = 2 * time.Second
+
+	// lockProgressInterval is how often a queued run reports that it is still waiting.
+	lockProgressInterval = 30 * time.Second
+)
+
+// acquireRunLock takes the run lock for module@version so two releases or resumes
+// never process the same release at once. When another run holds the lock and
+// wait is positive, it queues for up to wait and reports progress to w;
+// otherwise it fails with a state error naming the holder. The guard is nil when
+// the state manager cannot lock runs.
+func acquireRunLock(ctx context.Context, w io.Writer, manager state.Manager, module, version string, wait time.Duration) (state.LockGuard, error) {
+	locker, ok := manager.(state.RunLocker)
+	if !ok {
+		return nil, nil
+	}
+
+	guard, err := locker.LockRun(module, version)
+	if err == nil {
+		return guard, nil
+	}
+	if !errors.Is(err, state.ErrLocked) {
+		return nil, newStateError("failed to acquire run lock", err)
+	}
+	if wait <= 0 {
+		return nil, runLockedError(module, version, err, 0)
+	}
+
+	start := time.Now()
+	deadline := start.Add(wait)
+	lastReport := start
+	fmt.Fprintf(w, "Waiting up to %s for %s@%s, locked by %s\n", wait, module, version, lockHolder(err))
+
+	ticker := time.NewTicker(lockPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, newStateError(fmt.Sprintf("stopped waiting for the lock on %s@%s", module, version), ctx.Err())
+		case <-ticker.C:
+		}
+
+		guard, err = locker.LockRun(module, version)
+		if err == nil {
+			fmt.Fprintf(w, "Acquired lock on %s@%s after %s\n", module, version, time.Since(start).Round(time.Second))
+			return guard, nil
+		}
+		if !errors.Is(err, state.ErrLocked) {
+			return nil, newStateError("failed to acquire run lock", err)
+		}
+
+		now := time.Now()
+		if !now.Before(deadline) {
+			return nil, runLockedError(module, version, err, wait)
+		}
+		if now.Sub(lastReport) >= lockProgressInterval {
+			fmt.Fprintf(w, "  still waiting (%s elapsed), locked by %s\n", now.Sub(start).Round(time.Second), lockHolder(err))
+			lastReport = now
+		}
+	}
+}
+
+// lockHolder describes who holds the lock from a state.ErrLocked error.
+func lockHolder(err error) string {
+	var locked *state.LockedError
+	if errors.As(err, &locked) {
+		return locked.Info.String()
+	}
+	return "another run in this process"
+}
+
+func runLockedError(module, version string, err error, waited time.Duration) *CLIError {
+	message := fmt.Sprintf("another run for %s@%s is in progress", module, version)
+	if waited > 0 {
+		message = fmt.Sprintf("%s@%s is still locked after waiting %s", module, version, waited)
+	}
+	cliErr := newStateError(message, err)
+
+	var locked *state.LockedError
+	if errors.As(err, &locked) {
+		return cliErr.WithHint("it is held by %s; pass --wait-for-lock=10m to queue behind it, or delete %s if that process is gone",
+			locked.Info, locked.Path)
+	}
+	return cliErr.WithHint("pass --wait-for-lock=10m to queue behind it")
+}
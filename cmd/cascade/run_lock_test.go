@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goliatone/cascade/internal/state"
+)
+
+func newLockingManager(dir string) state.Manager {
+	return state.NewManager(state.WithLocker(state.NewFilesystemLocker(dir, &mockLogger{})))
+}
+
+func setLockIntervals(t *testing.T, poll, progress time.Duration) {
+	t.Helper()
+	oldPoll, oldProgress := lockPollInterval, lockProgressInterval
+	lockPollInterval, lockProgressInterval = poll, progress
+	t.Cleanup(func() {
+		lockPollInterval, lockProgressInterval = oldPoll, oldProgress
+	})
+}
+
+func TestAcquireRunLock_FailsFastWhenLocked(t *testing.T) {
+	dir := t.TempDir()
+	holder, err := acquireRunLock(context.Background(), &bytes.Buffer{}, newLockingManager(dir), "example.com/lib", "v1.0.0", 0)
+	if err != nil || holder == nil {
+		t.Fatalf("expected lock guard, got %v, %v", holder, err)
+	}
+	defer holder.Release()
+
+	var out bytes.Buffer
+	_, err = acquireRunLock(context.Background(), &out, newLockingManager(dir), "example.com/lib", "v1.0.0", 0)
+	var cliErr *CLIError
+	if !errors.As(err, &cliErr) {
+		t.Fatalf("expected *CLIError, got %v", err)
+	}
+	if cliErr.Code != ExitStateError {
+		t.Errorf("expected state error code, got %d", cliErr.Code)
+	}
+	if !errors.Is(err, state.ErrLocked) {
+		t.Errorf("expected error to wrap ErrLocked, got %v", err)
+	}
+	if !strings.Contains(cliErr.Hint, "--wait-for-lock") || !strings.Contains(cliErr.Hint, ".cascade.lock") {
+		t.Errorf("hint should mention --wait-for-lock and the lock file, got %q", cliErr.Hint)
+	}
+	if out.Len() != 0 {
+		t.Errorf("expected no progress output, got %q", out.String())
+	}
+}
+
+func TestAcquireRunLock_WaitsForHolder(t *testing.T) {
+	setLockIntervals(t, 5*time.Millisecond, 10*time.Millisecond)
+	dir := t.TempDir()
+	holder, err := acquireRunLock(context.Background(), &bytes.Buffer{}, newLockingManager(dir), "example.com/lib", "v1.0.0", 0)
+	if err != nil {
+		t.Fatalf("acquire holder: %v", err)
+	}
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		holder.Release()
+	}()
+
+	var out bytes.Buffer
+	guard, err := acquireRunLock(context.Background(), &out, newLockingManager(dir), "example.com/lib", "v1.0.0", 5*time.Second)
+	if err != nil {
+		t.Fatalf("expected lock after waiting, got %v", err)
+	}
+	defer guard.Release()
+
+	output := out.String()
+	for _, want := range []string{"Waiting up to 5s for example.com/lib@v1.0.0", "still waiting", "Acquired lock on example.com/lib@v1.0.0"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestAcquireRunLock_TimesOut(t *testing.T) {
+	setLockIntervals(t, 5*time.Millisecond, time.Hour)
+	dir := t.TempDir()
+	holder, err := acquireRunLock(context.Background(), &bytes.Buffer{}, newLockingManager(dir), "example.com/lib", "v1.0.0", 0)
+	if err != nil {
+		t.Fatalf("acquire holder: %v", err)
+	}
+	defer holder.Release()
+
+	_, err = acquireRunLock(context.Background(), &bytes.Buffer{}, newLockingManager(dir), "example.com/lib", "v1.0.0", 30*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "still locked after waiting 30ms") {
+		t.Fatalf("expected timeout error, got %v", err)
+	}
+}
+
+func TestAcquireRunLock_StopsOnCancel(t *testing.T) {
+	setLockIntervals(t, 5*time.Millisecond, time.Hour)
+	dir := t.TempDir()
+	holder, err := acquireRunLock(context.Background(), &bytes.Buffer{}, newLockingManager(dir), "example.com/lib", "v1.0.0", 0)
+	if err != nil {
+		t.Fatalf("acquire holder: %v", err)
+	}
+	defer holder.Release()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = acquireRunLock(ctx, &bytes.Buffer{}, newLockingManager(dir), "example.com/lib", "v1.0.0", time.Minute)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestAcquireRunLock_ManagerWithoutLocking(t *testing.T) {
+	guard, err := acquireRunLock(context.Background(), &bytes.Buffer{}, &mockStateManager{}, "example.com/lib", "v1.0.0", 0)
+	if err != nil || guard != nil {
+		t.Fatalf("expected nil guard and error, got %v, %v", guard, err)
+	}
+}
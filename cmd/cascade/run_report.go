@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/goliatone/cascade/internal/broker"
+	"github.com/goliatone/cascade/internal/report"
+	"github.com/goliatone/cascade/internal/state"
+	"github.com/goliatone/cascade/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+// addReportFlags wires the run report flags shared by release and resume.
+func addReportFlags(cmd *cobra.Command, formats *[]string, dir *string) {
+	cmd.Flags().StringSliceVar(formats, "report", nil, "Write a machine-readable run report when the run ends: json, junit, or both (comma-separated)")
+	cmd.Flags().StringVar(dir, "report-dir", ".", "Directory run reports are written to, as cascade-report.json and cascade-report.xml")
+}
+
+// applyReportFlags validates the report formats and records them in cfg.
+func applyReportFlags(cfg *config.Config, formats []string, dir string) error {
+	var names []string
+	for _, name := range formats {
+		if strings.TrimSpace(name) == "" {
+			continue
+		}
+		format, err := report.ParseFormat(name)
+		if err != nil {
+			return newValidationError(err.Error(), nil).
+				WithHint("use --report=json, --report=junit, or --report=json,junit")
+		}
+		names = append(names, string(format))
+	}
+	cfg.Executor.Reports = strings.Join(names, ",")
+	cfg.Executor.ReportDir = dir
+	return nil
+}
+
+// writeRunReports writes the report of the run recorded in summary in each
+// format cfg.Executor.Reports names.
+func writeRunReports(w io.Writer, cfg *config.Config, summary *state.Summary) error {
+	if cfg.Executor.Reports == "" || summary == nil {
+		return nil
+	}
+
+	dir := cfg.Executor.ReportDir
+	if dir == "" {
+		dir = "."
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return newFileError("failed to create the report directory", err)
+	}
+
+	r := report.New(summary, report.WithOutputLocator(reportOutputLocator(cfg, summary.Module, summary.Version)))
+	for _, name := range strings.Split(cfg.Executor.Reports, ",") {
+		format, err := report.ParseFormat(name)
+		if err != nil {
+			return newValidationError(err.Error(), nil)
+		}
+		var buf bytes.Buffer
+		if err := r.Write(&buf, format); err != nil {
+			return newGenericError(fmt.Sprintf("failed to encode the %s report", format), err)
+		}
+		file := filepath.Join(dir, format.FileName())
+		if err := os.WriteFile(file, buf.Bytes(), 0o644); err != nil {
+			return newFileError(fmt.Sprintf("failed to write the %s report", format), err)
+		}
+		fmt.Fprintf(w, "Wrote %s report to %s\n", format, file)
+	}
+	return nil
+}
+
+// reportOutputLocator returns where the state file holding an item's command
+// output is: under ui.base_url when set, otherwise in the state backend.
+func reportOutputLocator(cfg *config.Config, module, version string) func(repo string) string {
+	return func(repo string) string {
+		if base := strings.TrimSpace(cfg.UI.BaseURL); base != "" {
+			return broker.BuildStateLinks(base, module, version, repo).State
+		}
+		if !cfg.State.Enabled {
+			return ""
+		}
+		rel := state.ItemRelPath(module, version, repo)
+		switch cfg.State.Backend {
+		case "", config.StateBackendFilesystem:
+			return filepath.Join(cfg.State.Dir, filepath.FromSlash(rel))
+		default:
+			return cfg.State.Backend + "://" + path.Join(cfg.State.Bucket, cfg.State.Prefix, rel)
+		}
+	}
+}
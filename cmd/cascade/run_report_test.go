@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	execpkg "github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/internal/state"
+	"github.com/goliatone/cascade/pkg/config"
+)
+
+func TestWriteRunReports(t *testing.T) {
+	cfg := config.New()
+	cfg.State.Enabled = true
+	cfg.State.Dir = "/var/lib/cascade"
+
+	var cliErr *CLIError
+	if err := applyReportFlags(cfg, []string{"json", "xml"}, "."); !errors.As(err, &cliErr) || cliErr.Code != ExitValidationError {
+		t.Fatalf("expected a validation error for an unknown format, got %v", err)
+	}
+
+	dir := filepath.Join(t.TempDir(), "reports")
+	if err := applyReportFlags(cfg, []string{"json", " JUnit "}, dir); err != nil {
+		t.Fatalf("applyReportFlags() error = %v", err)
+	}
+	if cfg.Executor.Reports != "json,junit" {
+		t.Fatalf("Reports = %q", cfg.Executor.Reports)
+	}
+
+	summary := &state.Summary{
+		Module:    "example.com/lib",
+		Version:   "v1.2.0",
+		StartTime: time.Now().Add(-time.Minute),
+		EndTime:   time.Now(),
+		Items: []state.ItemState{
+			{Repo: "example/a", Status: execpkg.StatusCompleted, PRURL: "https://github.com/example/a/pull/1"},
+			{Repo: "example/b", Status: execpkg.StatusFailed, Reason: "tests failed"},
+		},
+	}
+	var out bytes.Buffer
+	if err := writeRunReports(&out, cfg, summary); err != nil {
+		t.Fatalf("writeRunReports() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "Wrote junit report to "+filepath.Join(dir, "cascade-report.xml")) {
+		t.Errorf("output = %q", out.String())
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "cascade-report.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct {
+		Items []struct {
+			Repo   string `json:"repo"`
+			Output string `json:"output"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("invalid JSON report: %v", err)
+	}
+	wantOutput := filepath.Join(cfg.State.Dir, filepath.FromSlash(state.ItemRelPath("example.com/lib", "v1.2.0", "example/b")))
+	if len(decoded.Items) != 2 || decoded.Items[1].Output != wantOutput {
+		t.Errorf("items = %+v, want the output in %s", decoded.Items, wantOutput)
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "cascade-report.xml")); err != nil || !strings.Contains(string(data), `failures="1"`) {
+		t.Errorf("JUnit report = %s, %v", data, err)
+	}
+
+	cfg.UI.BaseURL = "https://cascade.example.com/state"
+	if got := reportOutputLocator(cfg, "example.com/lib", "v1.2.0")("example/b"); !strings.HasPrefix(got, "https://cascade.example.com/state/example.com/lib/v1.2.0/items/") {
+		t.Errorf("output location = %q, want a link under ui.base_url", got)
+	}
+}
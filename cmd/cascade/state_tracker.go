@@ -1,8 +1,14 @@
 package main
 
 import (
+	"errors"
+	"os"
+	"sort"
+	"sync"
 	"time"
 
+	execpkg "github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/internal/planner"
 	"github.com/goliatone/cascade/internal/state"
 	"github.com/goliatone/cascade/pkg/di"
 )
@@ -15,6 +21,30 @@ type stateTracker struct {
 	manager  state.Manager
 	logger   di.Logger
 	existing map[string]state.ItemState
+	runStart time.Time
+
+	// resumed holds the stored states of items that continue from their first
+	// incomplete phase instead of starting over
+	resumed map[string]state.ItemState
+
+	// attempts holds the attempt counts stored for the module version before
+	// this run, keyed by repo, so repeated failures escalate across releases
+	attempts map[string]int
+
+	// issues holds the open failure issues of the module's dependents, keyed by
+	// repo. Items record into it concurrently with lookups, hence the mutex.
+	issuesMu sync.Mutex
+	issues   map[string]state.Issue
+
+	// annotations holds the operator notes on the module version, which
+	// notifications about its items carry
+	annotations []state.Annotation
+
+	// timed holds the states recorded by this run that carry stage timings, and
+	// active and peak count the items being processed, for the run summary.
+	timed  map[string]state.ItemState
+	active int
+	peak   int
 }
 
 func newStateTracker(module, version string, summary *state.Summary, manager state.Manager, logger di.Logger, existing []state.ItemState) *stateTracker {
@@ -43,16 +73,142 @@ func newStateTracker(module, version string, summary *state.Summary, manager sta
 		manager:  manager,
 		logger:   logger,
 		existing: make(map[string]state.ItemState, len(existing)),
+		runStart: time.Now(),
 	}
 
 	for _, st := range existing {
 		tracker.existing[st.Repo] = st
 	}
+	tracker.loadIssues()
+	tracker.loadAttempts()
+	tracker.loadAnnotations()
 
 	tracker.saveSummary()
 	return tracker
 }
 
+// loadAttempts reads the attempt count of every item stored for the module
+// version. A missing state leaves the map empty, so counting starts at 1.
+func (t *stateTracker) loadAttempts() {
+	t.attempts = make(map[string]int)
+	if t.manager == nil {
+		return
+	}
+	items, err := t.manager.LoadItemStates(t.module, t.version)
+	if err != nil {
+		if !errors.Is(err, state.ErrNotFound) && t.logger != nil {
+			t.logger.Warn("failed to load item attempts", "module", t.module, "version", t.version, "error", err)
+		}
+		return
+	}
+	for _, item := range items {
+		t.attempts[item.Repo] = item.Attempts
+	}
+}
+
+// loadAnnotations reads the operator notes on the module version. Managers
+// without annotation support leave them empty.
+func (t *stateTracker) loadAnnotations() {
+	store, ok := t.manager.(state.AnnotationStore)
+	if !ok {
+		return
+	}
+	annotations, err := store.LoadAnnotations(t.module, t.version)
+	if err != nil {
+		if t.logger != nil {
+			t.logger.Warn("failed to load annotations", "module", t.module, "version", t.version, "error", err)
+		}
+		return
+	}
+	t.annotations = annotations
+}
+
+// resumeFrom makes the next run of the item continue from the phases recorded in st.
+func (t *stateTracker) resumeFrom(st state.ItemState) {
+	if t == nil || st.Repo == "" {
+		return
+	}
+	if t.resumed == nil {
+		t.resumed = make(map[string]state.ItemState)
+	}
+	t.resumed[st.Repo] = st
+}
+
+// history returns what earlier runs recorded about repo.
+func (t *stateTracker) history(repo string) itemHistory {
+	var h itemHistory
+	if t == nil {
+		return h
+	}
+	if st, ok := t.resumed[repo]; ok {
+		h.resume = &st
+	}
+	h.attempt = max(t.existing[repo].Attempts, t.attempts[repo]) + 1
+	h.module, h.version = t.module, t.version
+	for _, annotation := range t.annotations {
+		if annotation.Repo == "" || annotation.Repo == repo {
+			h.annotations = append(h.annotations, annotation)
+		}
+	}
+
+	t.issuesMu.Lock()
+	defer t.issuesMu.Unlock()
+	if issue, ok := t.issues[repo]; ok {
+		h.issue = &issue
+	}
+	return h
+}
+
+// loadIssues reads the module's open failure issues. Managers without issue
+// tracking leave the map empty, which disables closing issues.
+func (t *stateTracker) loadIssues() {
+	t.issues = make(map[string]state.Issue)
+	store, ok := t.manager.(state.IssueStore)
+	if !ok {
+		return
+	}
+	issues, err := store.LoadIssues(t.module)
+	if err != nil {
+		if t.logger != nil {
+			t.logger.Warn("failed to load tracked issues", "module", t.module, "error", err)
+		}
+		return
+	}
+	for _, issue := range issues {
+		t.issues[issue.Repo] = issue
+	}
+}
+
+// trackIssue starts or stops tracking the failure issue recorded in item.
+func (t *stateTracker) trackIssue(item state.ItemState) {
+	if item.IssueNumber <= 0 {
+		return
+	}
+	store, ok := t.manager.(state.IssueStore)
+	if !ok {
+		return
+	}
+
+	t.issuesMu.Lock()
+	defer t.issuesMu.Unlock()
+
+	var err error
+	if item.IssueClosed {
+		delete(t.issues, item.Repo)
+		err = store.DeleteIssue(t.module, item.Repo)
+	} else {
+		issue := state.Issue{Repo: item.Repo, Number: item.IssueNumber, Version: t.version, Opened: item.LastUpdated}
+		if prev, ok := t.issues[item.Repo]; ok && prev.Number == issue.Number {
+			issue.Version, issue.Opened = prev.Version, prev.Opened
+		}
+		t.issues[item.Repo] = issue
+		err = store.SaveIssue(t.module, issue)
+	}
+	if err != nil && t.logger != nil {
+		t.logger.Warn("failed to persist tracked issue", "repo", item.Repo, "issue", item.IssueNumber, "error", err)
+	}
+}
+
 func (t *stateTracker) record(item state.ItemState) {
 	if t == nil || item.Repo == "" {
 		return
@@ -76,6 +232,12 @@ func (t *stateTracker) record(item state.ItemState) {
 	}
 
 	t.existing[item.Repo] = item
+	if item.Timings != nil {
+		if t.timed == nil {
+			t.timed = make(map[string]state.ItemState)
+		}
+		t.timed[item.Repo] = item
+	}
 	replaced := false
 	for i := range t.summary.Items {
 		if t.summary.Items[i].Repo == item.Repo {
@@ -89,6 +251,7 @@ func (t *stateTracker) record(item state.ItemState) {
 	}
 
 	t.summary.EndTime = item.LastUpdated
+	t.trackIssue(item)
 	if t.manager != nil {
 		if err := t.manager.SaveItemState(t.module, t.version, item); err != nil && t.logger != nil {
 			t.logger.Warn("failed to persist item state", "repo", item.Repo, "error", err)
@@ -114,5 +277,169 @@ func (t *stateTracker) finalize() {
 	}
 
 	t.summary.EndTime = time.Now()
+	if len(t.timed) > 0 {
+		items := make([]state.ItemState, 0, len(t.timed))
+		for _, item := range t.timed {
+			items = append(items, item)
+		}
+		timings := state.AggregateTimings(items)
+		timings.PeakConcurrency = t.peak
+		t.summary.Timings = timings
+	}
 	t.saveSummary()
+	t.saveRun()
+}
+
+// begin and end bracket the processing of one item so the run summary can
+// report peak concurrency. Callers serialise them with record.
+func (t *stateTracker) begin() {
+	if t == nil {
+		return
+	}
+	t.active++
+	t.peak = max(t.peak, t.active)
+}
+
+func (t *stateTracker) end() {
+	if t == nil {
+		return
+	}
+	t.active--
+}
+
+// saveRun snapshots every known item state so later attempts can be compared
+// against this one. Managers without run history support are skipped.
+func (t *stateTracker) saveRun() {
+	store, ok := t.manager.(state.RunStore)
+	if !ok {
+		return
+	}
+
+	items := make([]state.ItemState, 0, len(t.existing))
+	for _, item := range t.existing {
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Repo < items[j].Repo })
+
+	run := &state.Run{
+		ID:        state.RunID(t.runStart),
+		Module:    t.module,
+		Version:   t.version,
+		Attempt:   t.summary.RetryCount + 1,
+		StartTime: t.runStart,
+		EndTime:   t.summary.EndTime,
+		Items:     items,
+		Inputs:    t.summary.Inputs,
+	}
+	if err := store.SaveRun(run); err != nil && t.logger != nil {
+		t.logger.Warn("failed to persist run snapshot", "module", t.module, "version", t.version, "error", err)
+	}
+}
+
+// itemHeartbeat refreshes the liveness record of one in-flight work item. All
+// methods are safe to call on a nil receiver.
+type itemHeartbeat struct {
+	store   state.HeartbeatStore
+	module  string
+	version string
+	logger  di.Logger
+
+	mu   sync.Mutex
+	beat state.Heartbeat
+
+	stopCh chan struct{}
+	done   chan struct{}
+}
+
+// startHeartbeat records that item has started and refreshes its heartbeat every
+// interval until stop is called. It returns nil when the state manager does not
+// track heartbeats or interval is not positive.
+func (t *stateTracker) startHeartbeat(item planner.WorkItem, interval time.Duration) *itemHeartbeat {
+	if t == nil || interval <= 0 {
+		return nil
+	}
+	store, ok := t.manager.(state.HeartbeatStore)
+	if !ok {
+		return nil
+	}
+
+	now := time.Now()
+	hb := &itemHeartbeat{
+		store:   store,
+		module:  t.module,
+		version: t.version,
+		logger:  t.logger,
+		beat: state.Heartbeat{
+			Repo:      item.Repo,
+			Branch:    item.BranchName,
+			Phase:     "starting",
+			PID:       os.Getpid(),
+			StartedAt: now,
+			LastBeat:  now,
+		},
+		stopCh: make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	hb.save()
+
+	go func() {
+		defer close(hb.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-hb.stopCh:
+				return
+			case <-ticker.C:
+				hb.touch("")
+			}
+		}
+	}()
+
+	return hb
+}
+
+// setPhase records the phase the work item has entered.
+func (h *itemHeartbeat) setPhase(phase string) {
+	if h == nil {
+		return
+	}
+	h.touch(phase)
+}
+
+// progress adapts the heartbeat to the executor's progress callback.
+func (h *itemHeartbeat) progress(phase execpkg.Phase) {
+	h.setPhase(string(phase))
+}
+
+// stop halts the refresh loop and clears the heartbeat.
+func (h *itemHeartbeat) stop() {
+	if h == nil {
+		return
+	}
+	close(h.stopCh)
+	<-h.done
+	if err := h.store.ClearHeartbeat(h.module, h.version, h.beat.Repo); err != nil && h.logger != nil {
+		h.logger.Warn("failed to clear heartbeat", "repo", h.beat.Repo, "error", err)
+	}
+}
+
+func (h *itemHeartbeat) touch(phase string) {
+	h.mu.Lock()
+	if phase != "" {
+		h.beat.Phase = phase
+	}
+	h.beat.LastBeat = time.Now()
+	h.mu.Unlock()
+	h.save()
+}
+
+func (h *itemHeartbeat) save() {
+	h.mu.Lock()
+	beat := h.beat
+	h.mu.Unlock()
+
+	if err := h.store.SaveHeartbeat(h.module, h.version, beat); err != nil && h.logger != nil {
+		h.logger.Warn("failed to persist heartbeat", "repo", beat.Repo, "error", err)
+	}
 }
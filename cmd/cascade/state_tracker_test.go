@@ -0,0 +1,305 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goliatone/cascade/internal/broker"
+	execpkg "github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/internal/planner"
+	"github.com/goliatone/cascade/internal/state"
+)
+
+func newIssueTrackingManager(t *testing.T) state.Manager {
+	t.Helper()
+	storage, err := state.NewFilesystemStorage(t.TempDir(), &mockLogger{})
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+	return state.NewManager(state.WithStorage(storage))
+}
+
+func TestFailureIssueLifecycle(t *testing.T) {
+	manager := newIssueTrackingManager(t)
+	module := "example.com/lib"
+	item := planner.WorkItem{Repo: "example/a", BranchName: "update"}
+
+	// A failing release files an issue and tracks it for the module
+	failing := &mockExecutor{
+		applyFunc: func(ctx context.Context, input execpkg.WorkItemContext) (*execpkg.Result, error) {
+			return &execpkg.Result{Status: execpkg.StatusFailed, Reason: "tests failed"}, errors.New("tests failed")
+		},
+	}
+	filing := &mockBroker{
+		notifyFunc: func(ctx context.Context, item planner.WorkItem, result *execpkg.Result) (*broker.NotificationResult, error) {
+			return &broker.NotificationResult{Channel: "github:" + item.Repo, IssueNumber: 21}, nil
+		},
+	}
+
+	tracker := newStateTracker(module, "v1.0.0", nil, manager, &mockLogger{}, nil)
+	itemState, _ := processWorkItem(context.Background(), executionDeps{}, t.TempDir(), item, failing, filing, &mockLogger{}, 0, nil, tracker.history(item.Repo))
+	if itemState.IssueNumber != 21 || itemState.IssueClosed {
+		t.Fatalf("expected open issue 21 in state, got %d (closed %v)", itemState.IssueNumber, itemState.IssueClosed)
+	}
+	tracker.record(itemState)
+
+	issues, err := manager.(state.IssueStore).LoadIssues(module)
+	if err != nil || len(issues) != 1 || issues[0].Number != 21 || issues[0].Version != "v1.0.0" {
+		t.Fatalf("LoadIssues() = %+v, %v", issues, err)
+	}
+
+	// A later release of another version closes it once the item succeeds
+	var closed []int
+	resolving := &mockBroker{
+		resolveFunc: func(ctx context.Context, item planner.WorkItem, number int, result *execpkg.Result) (*broker.NotificationResult, error) {
+			closed = append(closed, number)
+			return &broker.NotificationResult{IssueNumber: number}, nil
+		},
+	}
+
+	tracker = newStateTracker(module, "v1.0.1", nil, manager, &mockLogger{}, nil)
+	history := tracker.history(item.Repo)
+	if history.issue == nil || history.issue.Number != 21 {
+		t.Fatalf("expected tracked issue 21, got %+v", history.issue)
+	}
+	itemState, err = processWorkItem(context.Background(), executionDeps{}, t.TempDir(), item, &mockExecutor{}, resolving, &mockLogger{}, 0, nil, history)
+	if err != nil {
+		t.Fatalf("processWorkItem() error = %v", err)
+	}
+	if len(closed) != 1 || closed[0] != 21 || !itemState.IssueClosed {
+		t.Fatalf("expected issue 21 to be closed, closed %v state %+v", closed, itemState)
+	}
+	tracker.record(itemState)
+
+	issues, err = manager.(state.IssueStore).LoadIssues(module)
+	if err != nil || len(issues) != 0 {
+		t.Fatalf("LoadIssues() after success = %+v, %v", issues, err)
+	}
+}
+
+func TestFailureIssueKeptWhenCloseFails(t *testing.T) {
+	manager := newIssueTrackingManager(t)
+	module := "example.com/lib"
+	if err := manager.(state.IssueStore).SaveIssue(module, state.Issue{Repo: "example/a", Number: 5, Version: "v1.0.0"}); err != nil {
+		t.Fatalf("SaveIssue() error = %v", err)
+	}
+
+	bk := &mockBroker{
+		resolveFunc: func(ctx context.Context, item planner.WorkItem, number int, result *execpkg.Result) (*broker.NotificationResult, error) {
+			return nil, errors.New("forbidden")
+		},
+	}
+	tracker := newStateTracker(module, "v1.1.0", nil, manager, &mockLogger{}, nil)
+	item := planner.WorkItem{Repo: "example/a", BranchName: "update"}
+	itemState, err := processWorkItem(context.Background(), executionDeps{}, t.TempDir(), item, &mockExecutor{}, bk, &mockLogger{}, 0, nil, tracker.history(item.Repo))
+	if err != nil {
+		t.Fatalf("a failed close should not fail the item: %v", err)
+	}
+	tracker.record(itemState)
+
+	issues, _ := manager.(state.IssueStore).LoadIssues(module)
+	if len(issues) != 1 || issues[0].Number != 5 {
+		t.Fatalf("expected issue 5 to stay tracked, got %+v", issues)
+	}
+}
+
+func TestNotificationsCarryAttemptAcrossRuns(t *testing.T) {
+	manager := newIssueTrackingManager(t)
+	module := "example.com/lib"
+	item := planner.WorkItem{Repo: "example/a", BranchName: "update"}
+
+	failing := &mockExecutor{
+		applyFunc: func(ctx context.Context, input execpkg.WorkItemContext) (*execpkg.Result, error) {
+			return &execpkg.Result{Status: execpkg.StatusFailed, Reason: "tests failed"}, errors.New("tests failed")
+		},
+	}
+	var attempts []int
+	recording := &mockBroker{
+		notifyFunc: func(ctx context.Context, item planner.WorkItem, result *execpkg.Result) (*broker.NotificationResult, error) {
+			attempts = append(attempts, broker.AttemptFromContext(ctx))
+			return nil, nil
+		},
+	}
+
+	// Each release starts a fresh tracker, as re-running the release command does
+	for run := 0; run < 3; run++ {
+		tracker := newStateTracker(module, "v1.0.0", nil, manager, &mockLogger{}, nil)
+		itemState, _ := processWorkItem(context.Background(), executionDeps{}, t.TempDir(), item, failing, recording, &mockLogger{}, 0, nil, tracker.history(item.Repo))
+		tracker.record(itemState)
+	}
+
+	if len(attempts) != 3 || attempts[0] != 1 || attempts[1] != 2 || attempts[2] != 3 {
+		t.Fatalf("notification attempts = %v, want [1 2 3]", attempts)
+	}
+}
+
+func TestProcessWorkItemRecordsStageTimings(t *testing.T) {
+	pause := func() { time.Sleep(2 * time.Millisecond) }
+	executor := &mockExecutor{
+		applyFunc: func(ctx context.Context, input execpkg.WorkItemContext) (*execpkg.Result, error) {
+			pause() // waiting for an executor slot
+			for _, phase := range []execpkg.Phase{execpkg.PhaseClone, execpkg.PhaseDependencies, execpkg.PhaseTests, execpkg.PhasePush} {
+				input.Progress(phase)
+				pause()
+			}
+			return &execpkg.Result{Status: execpkg.StatusCompleted}, nil
+		},
+	}
+	brokerSvc := &mockBroker{
+		ensurePRFunc: func(ctx context.Context, item planner.WorkItem, result *execpkg.Result) (*broker.PullRequest, error) {
+			pause()
+			return &broker.PullRequest{Repo: item.Repo, URL: "https://example.com/pr/1"}, nil
+		},
+	}
+
+	item := planner.WorkItem{Repo: "example/a", BranchName: "update"}
+	itemState, err := processWorkItem(context.Background(), executionDeps{}, t.TempDir(), item, executor, brokerSvc, &mockLogger{}, 0, nil, itemHistory{})
+	if err != nil {
+		t.Fatalf("processWorkItem() error = %v", err)
+	}
+	if itemState.Timings == nil {
+		t.Fatal("expected stage timings")
+	}
+	for _, stage := range state.StageOrder {
+		if itemState.Timings.Get(stage) <= 0 {
+			t.Errorf("stage %s was not timed: %+v", stage, itemState.Timings)
+		}
+	}
+}
+
+func TestProcessWorkItemCorrelatesProviderCalls(t *testing.T) {
+	var executorID, brokerID string
+	executor := &mockExecutor{
+		applyFunc: func(ctx context.Context, input execpkg.WorkItemContext) (*execpkg.Result, error) {
+			correlation, _ := broker.CorrelationFromContext(ctx)
+			executorID = correlation.ID
+			return &execpkg.Result{Status: execpkg.StatusCompleted}, nil
+		},
+	}
+	brokerSvc := &mockBroker{
+		ensurePRFunc: func(ctx context.Context, item planner.WorkItem, result *execpkg.Result) (*broker.PullRequest, error) {
+			correlation, ok := broker.CorrelationFromContext(ctx)
+			if !ok || correlation.Repo != item.Repo {
+				t.Errorf("expected correlation for %s, got %+v", item.Repo, correlation)
+			}
+			brokerID = correlation.ID
+			return &broker.PullRequest{Repo: item.Repo, URL: "https://example.com/pr/1"}, nil
+		},
+	}
+
+	item := planner.WorkItem{Repo: "example/a", BranchName: "update"}
+	itemState, err := processWorkItem(context.Background(), executionDeps{}, t.TempDir(), item, executor, brokerSvc, &mockLogger{}, 0, nil, itemHistory{})
+	if err != nil {
+		t.Fatalf("processWorkItem() error = %v", err)
+	}
+	if itemState.CorrelationID == "" {
+		t.Fatal("expected item state to record its correlation ID")
+	}
+	if executorID != itemState.CorrelationID || brokerID != itemState.CorrelationID {
+		t.Errorf("correlation IDs differ: state %q, executor %q, broker %q", itemState.CorrelationID, executorID, brokerID)
+	}
+}
+
+func TestProcessWorkItemSelectsDependentProvider(t *testing.T) {
+	executor := &mockExecutor{
+		applyFunc: func(ctx context.Context, input execpkg.WorkItemContext) (*execpkg.Result, error) {
+			return &execpkg.Result{Status: execpkg.StatusCompleted}, nil
+		},
+	}
+	var provider string
+	brokerSvc := &mockBroker{
+		ensurePRFunc: func(ctx context.Context, item planner.WorkItem, result *execpkg.Result) (*broker.PullRequest, error) {
+			provider, _ = broker.ProviderNameFromContext(ctx)
+			return &broker.PullRequest{Repo: item.Repo, URL: "https://example.com/pr/1"}, nil
+		},
+	}
+
+	item := planner.WorkItem{Repo: "team/app", BranchName: "update", Provider: broker.ProviderBitbucket}
+	itemState, err := processWorkItem(context.Background(), executionDeps{}, t.TempDir(), item, executor, brokerSvc, &mockLogger{}, 0, nil, itemHistory{})
+	if err != nil {
+		t.Fatalf("processWorkItem() error = %v", err)
+	}
+	if provider != broker.ProviderBitbucket || itemState.Provider != broker.ProviderBitbucket {
+		t.Errorf("provider = %q, state provider = %q, want bitbucket", provider, itemState.Provider)
+	}
+}
+
+func TestProcessWorkItemRecordsDependencyImpact(t *testing.T) {
+	impact := &execpkg.DependencyImpact{Module: "example.com/lib", OldVersion: "v1.0.0", NewVersion: "v1.1.0", OldVersionDetected: true, NewVersionDetected: true, Applied: true}
+	executor := &mockExecutor{
+		applyFunc: func(ctx context.Context, input execpkg.WorkItemContext) (*execpkg.Result, error) {
+			return &execpkg.Result{Status: execpkg.StatusCompleted, DependencyImpact: impact}, nil
+		},
+	}
+
+	item := planner.WorkItem{Repo: "team/app", BranchName: "update"}
+	itemState, err := processWorkItem(context.Background(), executionDeps{}, t.TempDir(), item, executor, &mockBroker{}, &mockLogger{}, 0, nil, itemHistory{})
+	if err != nil {
+		t.Fatalf("processWorkItem() error = %v", err)
+	}
+	if itemState.DependencyImpact != impact {
+		t.Errorf("DependencyImpact = %+v, want the executor's impact", itemState.DependencyImpact)
+	}
+}
+
+func TestStateTrackerSummarisesRunTimings(t *testing.T) {
+	tracker := newStateTracker("example.com/lib", "v1.0.0", nil, nil, &mockLogger{}, nil)
+	tracker.begin()
+	tracker.begin()
+	tracker.end()
+	tracker.begin()
+	tracker.end()
+	tracker.end()
+
+	tracker.record(state.ItemState{Repo: "example/a", Timings: &state.ItemTimings{Clone: time.Second, Test: 3 * time.Second}})
+	tracker.record(state.ItemState{Repo: "example/b", Timings: &state.ItemTimings{Clone: 2 * time.Second}})
+	tracker.finalize()
+
+	timings := tracker.summary.Timings
+	if timings == nil || timings.Items != 2 || timings.PeakConcurrency != 2 {
+		t.Fatalf("summary timings = %+v, want 2 items at peak concurrency 2", timings)
+	}
+
+	var out strings.Builder
+	printRunTimings(&out, timings)
+	for _, want := range []string{
+		"Timing breakdown (2 items, peak concurrency 2):",
+		"STAGE",
+		"clone",
+		"test",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "queue wait") {
+		t.Errorf("expected stages without samples to be omitted:\n%s", out.String())
+	}
+}
+
+func TestStateTrackerHistoryCarriesAnnotations(t *testing.T) {
+	manager := newIssueTrackingManager(t)
+	store := manager.(state.AnnotationStore)
+	for _, annotation := range []state.Annotation{
+		{Note: "paused for the freeze"},
+		{Repo: "example/a", Note: "waiting on upstream fix"},
+		{Repo: "example/b", Note: "flaky tests"},
+	} {
+		if err := store.AddAnnotation("example.com/lib", "v1.0.0", annotation); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tracker := newStateTracker("example.com/lib", "v1.0.0", nil, manager, &mockLogger{}, nil)
+	history := tracker.history("example/a")
+	var notes []string
+	for _, annotation := range history.annotations {
+		notes = append(notes, annotation.Note)
+	}
+	if strings.Join(notes, "; ") != "paused for the freeze; waiting on upstream fix" {
+		t.Errorf("history annotations = %v, want the cascade and item notes", notes)
+	}
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/goliatone/cascade/internal/telemetry"
+	"github.com/goliatone/cascade/pkg/config"
+	"github.com/goliatone/cascade/pkg/di"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// metricsPath is where the metrics listener serves Prometheus metrics
+	metricsPath = "/metrics"
+
+	// telemetryTimeout bounds each push and span export, and the flush when
+	// the command exits
+	telemetryTimeout = 10 * time.Second
+)
+
+// runTelemetry is the metrics listener, Pushgateway pusher, and tracer of the
+// running command.
+type runTelemetry struct {
+	cfg    config.TelemetryConfig
+	logger di.Logger
+	client *http.Client
+
+	server   *http.Server
+	addr     string
+	stopPush chan struct{}
+	pushDone chan struct{}
+
+	tracer *telemetry.Tracer
+	root   *telemetry.Span
+}
+
+// activeTelemetry is stopped by execute once the command returns, since the
+// post-run hooks are skipped when a command fails.
+var activeTelemetry *runTelemetry
+
+// startTelemetry serves metrics, starts pushing them, and installs a tracer
+// whose root span is the command, as cfg.Telemetry configures. It does nothing
+// when telemetry is not configured.
+func startTelemetry(cmd *cobra.Command, cfg *config.Config, logger di.Logger) error {
+	if cfg == nil {
+		return nil
+	}
+	tc := cfg.Telemetry
+	if tc.MetricsListen == "" && tc.Pushgateway.URL == "" && tc.Tracing.Endpoint == "" {
+		return nil
+	}
+
+	// Telemetry requests are not recorded themselves, so they use their own client
+	t := &runTelemetry{cfg: tc, logger: logger, client: &http.Client{Timeout: telemetryTimeout}}
+
+	if tc.MetricsListen != "" {
+		listener, err := net.Listen("tcp", tc.MetricsListen)
+		if err != nil {
+			return newConfigError("failed to start the metrics listener", err).
+				WithHint("set --metrics-listen or telemetry.metrics_listen to a free address")
+		}
+		mux := http.NewServeMux()
+		mux.Handle(metricsPath, telemetry.Default.Handler())
+		t.server = &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+		t.addr = listener.Addr().String()
+		go func() {
+			if err := t.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logger.Error("Metrics listener stopped", "error", err)
+			}
+		}()
+		logger.Info("Serving Prometheus metrics", "addr", t.addr, "path", metricsPath)
+	}
+
+	if tc.Pushgateway.URL != "" {
+		t.stopPush = make(chan struct{})
+		t.pushDone = make(chan struct{})
+		go t.pushLoop()
+	}
+
+	if tc.Tracing.Endpoint != "" {
+		exporter, err := telemetry.NewOTLPExporter(context.Background(), tc.Tracing.Endpoint, tc.Tracing.Headers, t.client)
+		if err != nil {
+			t.stop(nil)
+			return newConfigError("failed to set up trace export", err).
+				WithHint("set telemetry.tracing.endpoint to the OTLP/HTTP endpoint of a collector, such as http://collector:4318")
+		}
+		t.tracer = telemetry.NewTracer(exporter, tc.Tracing.ServiceName, telemetry.WithErrorHandler(func(err error) {
+			logger.Warn("Failed to export spans", "error", err)
+		}))
+		var attrs []telemetry.Attr
+		if cfg.Executor.DryRun {
+			attrs = append(attrs, telemetry.Bool("dry_run", true))
+		}
+		t.root = t.tracer.StartRoot(cmd.CommandPath(), os.Getenv("TRACEPARENT"), attrs...)
+		telemetry.SetTracer(t.tracer)
+		logger.Debug("Exporting traces", "endpoint", telemetry.TracesURL(tc.Tracing.Endpoint))
+	}
+
+	activeTelemetry = t
+	return nil
+}
+
+// stopTelemetry ends the command span with err, exports the remaining spans,
+// pushes metrics a last time, and stops the metrics listener.
+func stopTelemetry(err error) {
+	t := activeTelemetry
+	if t == nil {
+		return
+	}
+	activeTelemetry = nil
+	t.stop(err)
+}
+
+func (t *runTelemetry) stop(err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), telemetryTimeout)
+	defer cancel()
+
+	if t.tracer != nil {
+		t.root.End(err)
+		telemetry.SetTracer(nil)
+		if shutdownErr := t.tracer.Shutdown(ctx); shutdownErr != nil {
+			t.logger.Warn("Failed to export spans", "error", shutdownErr)
+		}
+	}
+	if t.stopPush != nil {
+		close(t.stopPush)
+		<-t.pushDone
+		t.push(ctx)
+	}
+	if t.server != nil {
+		_ = t.server.Shutdown(ctx)
+	}
+}
+
+// pushLoop pushes metrics to the Pushgateway every interval until stopPush
+// is closed.
+func (t *runTelemetry) pushLoop() {
+	defer close(t.pushDone)
+	interval := t.cfg.Pushgateway.Interval
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), telemetryTimeout)
+			t.push(ctx)
+			cancel()
+		case <-t.stopPush:
+			return
+		}
+	}
+}
+
+func (t *runTelemetry) push(ctx context.Context) {
+	if err := telemetry.Default.Push(ctx, t.client, t.cfg.Pushgateway.URL, t.cfg.Pushgateway.Job); err != nil {
+		t.logger.Warn("Failed to push metrics", "url", t.cfg.Pushgateway.URL, "error", err)
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	execpkg "github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/internal/state"
+	"github.com/goliatone/cascade/internal/telemetry"
+	"github.com/goliatone/cascade/pkg/config"
+	"github.com/spf13/cobra"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestTelemetryLifecycle(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		spans  []string
+		pushed string
+	)
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.URL.Path == "/v1/traces" {
+			data, _ := io.ReadAll(r.Body)
+			var payload collectortrace.ExportTraceServiceRequest
+			if err := proto.Unmarshal(data, &payload); err != nil {
+				t.Errorf("invalid OTLP payload: %v", err)
+			}
+			for _, rs := range payload.GetResourceSpans() {
+				for _, ss := range rs.GetScopeSpans() {
+					for _, span := range ss.GetSpans() {
+						spans = append(spans, span.GetName())
+						if span.GetStatus().GetCode() == tracepb.Status_STATUS_CODE_ERROR {
+							spans = append(spans, span.GetName()+" failed")
+						}
+					}
+				}
+			}
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		pushed = r.Method + " " + r.URL.Path + "\n" + string(data)
+	}))
+	defer collector.Close()
+
+	cfg := config.New()
+	cfg.Telemetry = config.TelemetryConfig{
+		MetricsListen: "127.0.0.1:0",
+		Pushgateway:   config.PushgatewayConfig{URL: collector.URL, Job: "cascade", Interval: time.Hour},
+		Tracing:       config.TracingConfig{Endpoint: collector.URL},
+	}
+	cmd := &cobra.Command{Use: "release"}
+	if err := startTelemetry(cmd, cfg, &mockLogger{}); err != nil {
+		t.Fatalf("startTelemetry() error = %v", err)
+	}
+	addr := activeTelemetry.addr
+
+	_, span := telemetry.Start(context.Background(), "planner.plan")
+	span.End(nil)
+	recordWorkItemMetrics(state.ItemState{
+		Repo:     "example/a",
+		Status:   execpkg.StatusFailed,
+		Duration: 90 * time.Second,
+		Timings:  &state.ItemTimings{Clone: 10 * time.Second, Test: time.Minute},
+	})
+
+	resp, err := http.Get("http://" + addr + metricsPath)
+	if err != nil {
+		t.Fatalf("GET /metrics: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	for _, want := range []string{
+		`cascade_work_items_total{status="failed"}`,
+		`cascade_stage_duration_seconds_count{stage="test"}`,
+		`cascade_operation_duration_seconds_count{operation="planner.plan"}`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("metrics missing %s:\n%s", want, body)
+		}
+	}
+
+	stopTelemetry(errors.New("release failed"))
+	if activeTelemetry != nil {
+		t.Error("expected telemetry to be stopped")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if got := strings.Join(spans, ","); got != "planner.plan,release,release failed" {
+		t.Errorf("exported spans = %s", got)
+	}
+	if !strings.HasPrefix(pushed, "PUT /metrics/job/cascade\n") || !strings.Contains(pushed, "cascade_work_items_total") {
+		t.Errorf("pushed = %q", pushed)
+	}
+	if _, err := http.Get("http://" + addr + metricsPath); err == nil {
+		t.Error("expected the metrics listener to be closed")
+	}
+}
+
+func TestStageTimerMergesRepeatedStages(t *testing.T) {
+	timer := &stageTimer{ctx: context.Background()}
+	timer.enter(state.StageClone)
+	since := timer.since
+	timer.enter(state.StageClone)
+	if timer.since != since {
+		t.Error("expected re-entering the current stage to keep timing it")
+	}
+	timer.stop()
+	if timer.span != nil || timer.stage != "" {
+		t.Errorf("timer = %+v, want the stage closed", timer)
+	}
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	execpkg "github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/internal/manifest"
+	"github.com/goliatone/cascade/internal/planner"
+	"github.com/goliatone/cascade/pkg/workspace/layout"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/semver"
+)
+
+// Smoke outcomes of a dependent.
+const (
+	smokeCompatible   = "compatible"
+	smokeIncompatible = "incompatible"
+	smokeSkipped      = "skipped"
+)
+
+// smokeFallbackCommand checks dependents that declare no tests.
+var smokeFallbackCommand = manifest.Command{Cmd: []string{"go", "build", "./..."}}
+
+// smokeReport is the outcome of a workspace smoke run.
+type smokeReport struct {
+	Module    string        `json:"module"`
+	ModuleDir string        `json:"module_dir"`
+	GoWork    string        `json:"go_work,omitempty"`
+	Results   []smokeResult `json:"results"`
+}
+
+// smokeResult is the outcome of one dependent.
+type smokeResult struct {
+	Repo          string        `json:"repo"`
+	Module        string        `json:"module"`
+	Dir           string        `json:"dir,omitempty"`
+	Status        string        `json:"status"`
+	Reason        string        `json:"reason,omitempty"`
+	FailedCommand string        `json:"failed_command,omitempty"`
+	Output        string        `json:"output,omitempty"`
+	Duration      time.Duration `json:"duration,omitempty"`
+}
+
+// count returns how many results have status.
+func (r *smokeReport) count(status string) int {
+	n := 0
+	for _, res := range r.Results {
+		if res.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// smokeDependent is a work item whose repository is checked out in the
+// workspace.
+type smokeDependent struct {
+	Item      planner.WorkItem
+	RepoDir   string
+	ModuleDir string
+}
+
+// locateSmokeDependents finds the checkout of each item in workspace. Items
+// without one are returned as skipped results.
+func locateSmokeDependents(items []planner.WorkItem, workspace string, l layout.Layout) ([]smokeDependent, []smokeResult) {
+	var (
+		found   []smokeDependent
+		skipped []smokeResult
+	)
+	for _, item := range items {
+		dep, ok := locateSmokeDependent(item, workspace, l)
+		if !ok {
+			skipped = append(skipped, smokeResult{
+				Repo:   item.Repo,
+				Module: item.Module,
+				Status: smokeSkipped,
+				Reason: "not checked out in the workspace",
+			})
+			continue
+		}
+		found = append(found, dep)
+	}
+	return found, skipped
+}
+
+func locateSmokeDependent(item planner.WorkItem, workspace string, l layout.Layout) (smokeDependent, bool) {
+	for _, dir := range l.Locations(workspace, item.Repo) {
+		moduleDir := filepath.Join(dir, item.ModulePath)
+		if info, err := os.Stat(filepath.Join(moduleDir, "go.mod")); err == nil && !info.IsDir() {
+			return smokeDependent{Item: item, RepoDir: dir, ModuleDir: moduleDir}, true
+		}
+	}
+	return smokeDependent{}, false
+}
+
+// writeSmokeGoWork writes a go.work into dir that uses targetDir and the
+// module of every dependent. Its go version is the highest any module
+// declares, which the go command requires. Dependents whose module is already
+// used, such as a second checkout of the same module, are left out and
+// returned as skipped results.
+func writeSmokeGoWork(dir, targetDir string, deps []smokeDependent) (string, []smokeDependent, []smokeResult, error) {
+	work, err := modfile.ParseWork("go.work", nil, nil)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	targetMod, err := readSmokeGoMod(targetDir)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	goVersion := goDirective(targetMod)
+	seen := map[string]bool{modulePathOf(targetMod): true}
+	if err := work.AddUse(targetDir, ""); err != nil {
+		return "", nil, nil, err
+	}
+
+	var (
+		used    []smokeDependent
+		skipped []smokeResult
+	)
+	for _, dep := range deps {
+		mod, err := readSmokeGoMod(dep.ModuleDir)
+		if err != nil {
+			skipped = append(skipped, smokeResult{Repo: dep.Item.Repo, Module: dep.Item.Module, Dir: dep.ModuleDir, Status: smokeSkipped, Reason: err.Error()})
+			continue
+		}
+		path := modulePathOf(mod)
+		if seen[path] {
+			skipped = append(skipped, smokeResult{Repo: dep.Item.Repo, Module: dep.Item.Module, Dir: dep.ModuleDir, Status: smokeSkipped, Reason: fmt.Sprintf("module %s is already in the workspace", path)})
+			continue
+		}
+		seen[path] = true
+		if err := work.AddUse(dep.ModuleDir, ""); err != nil {
+			return "", nil, nil, err
+		}
+		if v := goDirective(mod); v != "" && (goVersion == "" || semver.Compare("v"+v, "v"+goVersion) > 0) {
+			goVersion = v
+		}
+		used = append(used, dep)
+	}
+
+	if goVersion != "" {
+		if err := work.AddGoStmt(goVersion); err != nil {
+			return "", nil, nil, err
+		}
+	}
+	work.Cleanup()
+
+	path := filepath.Join(dir, "go.work")
+	if err := os.WriteFile(path, modfile.Format(work.Syntax), 0o644); err != nil {
+		return "", nil, nil, err
+	}
+	return path, used, skipped, nil
+}
+
+func readSmokeGoMod(dir string) (*modfile.File, error) {
+	path := filepath.Join(dir, "go.mod")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read go.mod: %w", err)
+	}
+	mod, err := modfile.ParseLax(path, data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("parse go.mod: %w", err)
+	}
+	return mod, nil
+}
+
+func modulePathOf(mod *modfile.File) string {
+	if mod.Module == nil {
+		return ""
+	}
+	return mod.Module.Mod.Path
+}
+
+func goDirective(mod *modfile.File) string {
+	if mod.Go == nil {
+		return ""
+	}
+	return mod.Go.Version
+}
+
+// runSmokeDependent runs the tests of dep, or go build when it has none,
+// against the modules of goWork. It stops at the first failing command.
+// Dependents that need services are skipped, since none are started.
+func runSmokeDependent(ctx context.Context, runner execpkg.CommandRunner, dep smokeDependent, goWork string, defaultTimeout time.Duration) smokeResult {
+	item := dep.Item
+	result := smokeResult{Repo: item.Repo, Module: item.Module, Dir: dep.ModuleDir}
+	if len(item.Services) > 0 {
+		result.Status = smokeSkipped
+		result.Reason = "its tests need services; check it with cascade try"
+		return result
+	}
+	start := time.Now()
+
+	env := make(map[string]string, len(item.Env)+2)
+	for key, value := range item.Env {
+		env[key] = value
+	}
+	env["GOWORK"] = goWork
+	goflags := item.GoFlags
+	if goflags == "" {
+		goflags = os.Getenv("GOFLAGS")
+	}
+	env["GOFLAGS"] = execpkg.WorkspaceGoFlags(goflags)
+
+	timeout := item.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	commands := item.Tests
+	dir := dep.RepoDir
+	if len(commands) == 0 {
+		commands = []manifest.Command{smokeFallbackCommand}
+		dir = dep.ModuleDir
+	}
+	for _, cmd := range commands {
+		res, err := runner.Run(ctx, dir, cmd, env, timeout)
+		if err == nil {
+			err = res.Err
+		}
+		if err != nil {
+			result.Status = smokeIncompatible
+			result.Reason = err.Error()
+			result.FailedCommand = strings.Join(cmd.Cmd, " ")
+			result.Output = strings.TrimSpace(res.Output)
+			result.Duration = time.Since(start)
+			return result
+		}
+	}
+	result.Status = smokeCompatible
+	result.Duration = time.Since(start)
+	return result
+}
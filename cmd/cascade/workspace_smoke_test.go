@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	execpkg "github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/internal/planner"
+	"github.com/goliatone/cascade/pkg/workspace/layout"
+)
+
+func writeSmokeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWorkspaceSmoke(t *testing.T) {
+	root := t.TempDir()
+	libDir := filepath.Join(root, "lib")
+	writeSmokeFile(t, filepath.Join(libDir, "go.mod"), "module example.com/lib\n\ngo 1.21\n")
+	writeSmokeFile(t, filepath.Join(libDir, "lib.go"), "package lib\n\nfunc Hello() string { return \"hello\" }\n")
+
+	workspaceDir := filepath.Join(root, "workspace")
+	appDir := filepath.Join(workspaceDir, "example", "app")
+	writeSmokeFile(t, filepath.Join(appDir, "go.mod"), "module example.com/app\n\ngo 1.22\n\nrequire example.com/lib v1.0.0\n")
+	writeSmokeFile(t, filepath.Join(appDir, "main.go"), "package main\n\nimport \"example.com/lib\"\n\nfunc main() { println(lib.Hello()) }\n")
+
+	items := []planner.WorkItem{
+		{Repo: "example/app", Module: "example.com/app", ModulePath: "."},
+		{Repo: "example/missing", Module: "example.com/missing", ModulePath: "."},
+		{Repo: "example/app-copy", Module: "example.com/app", ModulePath: "."},
+	}
+	writeSmokeFile(t, filepath.Join(workspaceDir, "example", "app-copy", "go.mod"), "module example.com/app\n\ngo 1.22\n")
+
+	deps, skipped := locateSmokeDependents(items, workspaceDir, layout.Layout{})
+	if len(deps) != 2 || len(skipped) != 1 || skipped[0].Repo != "example/missing" {
+		t.Fatalf("located %d dependents and skipped %+v, want 2 located and example/missing skipped", len(deps), skipped)
+	}
+
+	goWork, used, skipped, err := writeSmokeGoWork(t.TempDir(), libDir, deps)
+	if err != nil {
+		t.Fatalf("writeSmokeGoWork() error = %v", err)
+	}
+	if len(used) != 1 || len(skipped) != 1 || !strings.Contains(skipped[0].Reason, "already in the workspace") {
+		t.Fatalf("used %d dependents and skipped %+v, want the second example.com/app skipped", len(used), skipped)
+	}
+	data, err := os.ReadFile(goWork)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"go 1.22", libDir, appDir} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("go.work does not contain %q:\n%s", want, data)
+		}
+	}
+
+	runner := execpkg.NewCommandRunner()
+	if result := runSmokeDependent(context.Background(), runner, used[0], goWork, time.Minute); result.Status != smokeCompatible {
+		t.Fatalf("status = %s (%s: %s), want compatible", result.Status, result.Reason, result.Output)
+	}
+
+	// Removing the function the dependent calls breaks it
+	writeSmokeFile(t, filepath.Join(libDir, "lib.go"), "package lib\n\nfunc Greeting() string { return \"hello\" }\n")
+	result := runSmokeDependent(context.Background(), runner, used[0], goWork, time.Minute)
+	if result.Status != smokeIncompatible || result.FailedCommand != "go build ./..." || !strings.Contains(result.Output, "Hello") {
+		t.Fatalf("result = %+v, want go build to fail on lib.Hello", result)
+	}
+}
@@ -0,0 +1,364 @@
+package broker
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Slack interactivity request headers and the action IDs of approval buttons.
+const (
+	HeaderSlackSignature = "X-Slack-Signature"
+	HeaderSlackTimestamp = "X-Slack-Request-Timestamp"
+
+	ApprovalActionApprove = "cascade_approve"
+	ApprovalActionReject  = "cascade_reject"
+)
+
+// slackRequestMaxAge bounds the age of interactivity requests, so captured
+// requests cannot be replayed later.
+const slackRequestMaxAge = 5 * time.Minute
+
+// ErrInvalidSlackSignature is returned for interactivity requests that were not
+// signed with the Slack app's signing secret.
+var ErrInvalidSlackSignature = errors.New("invalid Slack request signature")
+
+// ApprovalRequest asks a person to approve work before it runs.
+type ApprovalRequest struct {
+	// ID tells responses to concurrent requests apart. Empty IDs are generated.
+	ID string
+
+	// Text describes the work, in Slack mrkdwn.
+	Text string
+}
+
+// ApprovalDecision is the answer to an ApprovalRequest.
+type ApprovalDecision struct {
+	Approved bool
+
+	// User is who answered; empty when the request timed out.
+	User string
+
+	// Reason explains a rejection that was not a person's answer, such as a timeout.
+	Reason string
+}
+
+// Approver blocks until work is approved or rejected.
+type Approver interface {
+	RequestApproval(ctx context.Context, req ApprovalRequest) (*ApprovalDecision, error)
+}
+
+// SlackApprover posts approval requests with approve and reject buttons to a
+// Slack channel. Slack delivers button clicks to the app's interactivity
+// Request URL, which must be served by ServeHTTP.
+type SlackApprover struct {
+	botToken      string
+	channel       string
+	signingSecret string
+	timeout       time.Duration
+	client        HTTPClient
+	now           func() time.Time
+
+	// approvers holds the Slack user IDs and usernames allowed to answer;
+	// empty allows anyone who can click the buttons.
+	approvers map[string]bool
+
+	mu      sync.Mutex
+	pending map[string]chan ApprovalDecision
+}
+
+var (
+	_ Approver     = (*SlackApprover)(nil)
+	_ http.Handler = (*SlackApprover)(nil)
+)
+
+// SlackApproverOption configures a SlackApprover.
+type SlackApproverOption func(*SlackApprover)
+
+// WithApprovers limits who may answer approval requests to the given Slack
+// user IDs or usernames. Clicks from anyone else leave the request pending.
+func WithApprovers(users ...string) SlackApproverOption {
+	return func(a *SlackApprover) {
+		for _, user := range users {
+			if user != "" {
+				a.approvers[user] = true
+			}
+		}
+	}
+}
+
+// NewSlackApprover creates a Slack approver. Requests that get no answer within
+// timeout are rejected; a zero timeout waits until the context ends.
+func NewSlackApprover(botToken, channel, signingSecret string, timeout time.Duration, client HTTPClient, opts ...SlackApproverOption) *SlackApprover {
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	a := &SlackApprover{
+		botToken:      botToken,
+		channel:       channel,
+		signingSecret: signingSecret,
+		timeout:       timeout,
+		client:        client,
+		now:           time.Now,
+		approvers:     map[string]bool{},
+		pending:       map[string]chan ApprovalDecision{},
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// RequestApproval posts req to the channel and waits for a button click.
+func (a *SlackApprover) RequestApproval(ctx context.Context, req ApprovalRequest) (*ApprovalDecision, error) {
+	if req.ID == "" {
+		req.ID = NewCorrelationID()
+	}
+	decisions := make(chan ApprovalDecision, 1)
+	a.mu.Lock()
+	a.pending[req.ID] = decisions
+	a.mu.Unlock()
+	defer func() {
+		a.mu.Lock()
+		delete(a.pending, req.ID)
+		a.mu.Unlock()
+	}()
+
+	if err := a.post(ctx, req); err != nil {
+		return nil, &NotificationError{Channel: a.channel, Err: fmt.Errorf("post approval request: %w", err)}
+	}
+
+	var expired <-chan time.Time
+	if a.timeout > 0 {
+		timer := time.NewTimer(a.timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+	select {
+	case decision := <-decisions:
+		return &decision, nil
+	case <-expired:
+		return &ApprovalDecision{Reason: fmt.Sprintf("no response within %s", a.timeout)}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// post sends the approval message with its buttons through chat.postMessage.
+func (a *SlackApprover) post(ctx context.Context, req ApprovalRequest) error {
+	button := func(text, style, action string) map[string]any {
+		return map[string]any{
+			"type":      "button",
+			"text":      map[string]any{"type": "plain_text", "text": text},
+			"style":     style,
+			"action_id": action,
+			"value":     req.ID,
+		}
+	}
+	payload := map[string]any{
+		"channel": a.channel,
+		"text":    req.Text,
+		"blocks": []map[string]any{
+			{"type": "section", "text": map[string]any{"type": "mrkdwn", "text": req.Text}},
+			{"type": "actions", "block_id": "cascade_approval", "elements": []map[string]any{
+				button("Approve", "primary", ApprovalActionApprove),
+				button("Reject", "danger", ApprovalActionReject),
+			}},
+		},
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://slack.com/api/chat.postMessage", bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+a.botToken)
+
+	resp, err := a.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("slack API error: status %d", resp.StatusCode)
+	}
+
+	// Slack reports API errors such as an unknown channel with status 200
+	var result struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	if !result.OK {
+		return fmt.Errorf("slack API error: %s", result.Error)
+	}
+	return nil
+}
+
+// slackInteraction is the subset of a block_actions interactivity payload the
+// approver reads.
+type slackInteraction struct {
+	Type string `json:"type"`
+	User struct {
+		ID       string `json:"id"`
+		Username string `json:"username"`
+	} `json:"user"`
+	Actions []struct {
+		ActionID string `json:"action_id"`
+		Value    string `json:"value"`
+	} `json:"actions"`
+	ResponseURL string `json:"response_url"`
+}
+
+// ServeHTTP handles Slack interactivity requests, answering the pending
+// approval request whose button was clicked. Clicks from users who are not
+// approvers only get an ephemeral reply saying so.
+func (a *SlackApprover) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		http.Error(w, "failed to read request", http.StatusBadRequest)
+		return
+	}
+	if err := VerifySlackSignature(a.signingSecret, r.Header.Get(HeaderSlackTimestamp), r.Header.Get(HeaderSlackSignature), body, a.now()); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, "invalid form body", http.StatusBadRequest)
+		return
+	}
+	var interaction slackInteraction
+	if err := json.Unmarshal([]byte(form.Get("payload")), &interaction); err != nil {
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+
+	for _, action := range interaction.Actions {
+		if action.ActionID != ApprovalActionApprove && action.ActionID != ApprovalActionReject {
+			continue
+		}
+		user := interaction.User.Username
+		if user == "" {
+			user = interaction.User.ID
+		}
+		if !a.allowed(interaction.User.ID, interaction.User.Username) {
+			if interaction.ResponseURL != "" {
+				go a.respond(interaction.ResponseURL, map[string]any{
+					"response_type":    "ephemeral",
+					"replace_original": false,
+					"text":             fmt.Sprintf("%s is not allowed to answer this approval request.", user),
+				})
+			}
+			continue
+		}
+		decision := ApprovalDecision{Approved: action.ActionID == ApprovalActionApprove, User: user}
+		answered := a.answer(action.Value, decision)
+		if interaction.ResponseURL != "" {
+			go a.replaceMessage(interaction.ResponseURL, decision, answered)
+		}
+	}
+}
+
+// allowed reports whether the user with id and username may answer requests.
+func (a *SlackApprover) allowed(id, username string) bool {
+	if len(a.approvers) == 0 {
+		return true
+	}
+	return (id != "" && a.approvers[id]) || (username != "" && a.approvers[username])
+}
+
+// answer delivers decision to the pending request id, reporting whether one
+// was still waiting.
+func (a *SlackApprover) answer(id string, decision ApprovalDecision) bool {
+	a.mu.Lock()
+	decisions, ok := a.pending[id]
+	delete(a.pending, id)
+	a.mu.Unlock()
+	if ok {
+		decisions <- decision
+	}
+	return ok
+}
+
+// replaceMessage swaps the buttons of an answered request for its outcome, so
+// nobody clicks them again.
+func (a *SlackApprover) replaceMessage(responseURL string, decision ApprovalDecision, answered bool) {
+	text := fmt.Sprintf(":white_check_mark: Approved by %s", decision.User)
+	if !decision.Approved {
+		text = fmt.Sprintf(":no_entry: Rejected by %s", decision.User)
+	}
+	if !answered {
+		text = "This approval request is no longer pending."
+	}
+	a.respond(responseURL, map[string]any{"replace_original": true, "text": text})
+}
+
+// respond posts message to the response_url of an interaction.
+func (a *SlackApprover) respond(responseURL string, message map[string]any) {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, responseURL, bytes.NewReader(data))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if resp, err := a.client.Do(req); err == nil {
+		resp.Body.Close()
+	}
+}
+
+// VerifySlackSignature checks that body was signed with secret at timestamp,
+// as Slack signs interactivity requests, and that the request is recent.
+func VerifySlackSignature(secret, timestamp, signature string, body []byte, now time.Time) error {
+	if secret == "" || timestamp == "" || signature == "" {
+		return ErrInvalidSlackSignature
+	}
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSlackSignature
+	}
+	if age := now.Sub(time.Unix(seconds, 0)); age > slackRequestMaxAge || age < -slackRequestMaxAge {
+		return fmt.Errorf("%w: request is too old", ErrInvalidSlackSignature)
+	}
+	if !hmac.Equal([]byte(signature), []byte(SignSlackRequest(secret, timestamp, body))) {
+		return ErrInvalidSlackSignature
+	}
+	return nil
+}
+
+// SignSlackRequest returns the X-Slack-Signature value of body sent at timestamp.
+func SignSlackRequest(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + timestamp + ":"))
+	mac.Write(body)
+	return "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
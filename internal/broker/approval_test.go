@@ -0,0 +1,160 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// slackInteractionRequest builds a signed interactivity request clicking action
+// on the approval request id.
+func slackInteractionRequest(t *testing.T, secret, action, id string, at time.Time) *http.Request {
+	t.Helper()
+	payload, err := json.Marshal(map[string]any{
+		"type":    "block_actions",
+		"user":    map[string]any{"id": "U123", "username": "alice"},
+		"actions": []map[string]any{{"action_id": action, "value": id}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := url.Values{"payload": {string(payload)}}.Encode()
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	req := httptest.NewRequest(http.MethodPost, "/slack/interactions", strings.NewReader(body))
+	req.Header.Set(HeaderSlackTimestamp, timestamp)
+	req.Header.Set(HeaderSlackSignature, SignSlackRequest(secret, timestamp, []byte(body)))
+	return req
+}
+
+// waitForPending waits until the approver has registered request id.
+func waitForPending(t *testing.T, approver *SlackApprover, id string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		approver.mu.Lock()
+		_, ok := approver.pending[id]
+		approver.mu.Unlock()
+		if ok {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("approval request %s was never registered", id)
+}
+
+func TestSlackApprover_Decisions(t *testing.T) {
+	for action, approved := range map[string]bool{ApprovalActionApprove: true, ApprovalActionReject: false} {
+		t.Run(action, func(t *testing.T) {
+			client := &mockHTTPClient{responses: []mockResponse{{statusCode: 200, body: `{"ok": true}`}}}
+			approver := NewSlackApprover("xoxb-test", "#releases", "secret", time.Minute, client)
+
+			done := make(chan *ApprovalDecision, 1)
+			go func() {
+				decision, err := approver.RequestApproval(context.Background(), ApprovalRequest{ID: "req-1", Text: "Release lib?"})
+				if err != nil {
+					t.Errorf("RequestApproval() error = %v", err)
+				}
+				done <- decision
+			}()
+			waitForPending(t, approver, "req-1")
+
+			rec := httptest.NewRecorder()
+			approver.ServeHTTP(rec, slackInteractionRequest(t, "secret", action, "req-1", time.Now()))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("ServeHTTP() status = %d", rec.Code)
+			}
+
+			decision := <-done
+			if decision == nil || decision.Approved != approved || decision.User != "alice" {
+				t.Fatalf("decision = %+v, want approved=%v by alice", decision, approved)
+			}
+			body := client.requests[0].Body
+			var sent map[string]any
+			if err := json.NewDecoder(body).Decode(&sent); err != nil || sent["channel"] != "#releases" || sent["blocks"] == nil {
+				t.Errorf("unexpected approval message: %v, %v", sent, err)
+			}
+		})
+	}
+}
+
+func TestSlackApprover_Timeout(t *testing.T) {
+	client := &mockHTTPClient{responses: []mockResponse{{statusCode: 200, body: `{"ok": true}`}}}
+	approver := NewSlackApprover("xoxb-test", "#releases", "secret", 10*time.Millisecond, client)
+
+	decision, err := approver.RequestApproval(context.Background(), ApprovalRequest{Text: "Release lib?"})
+	if err != nil {
+		t.Fatalf("RequestApproval() error = %v", err)
+	}
+	if decision.Approved || !strings.Contains(decision.Reason, "no response") {
+		t.Errorf("expected a timed out rejection, got %+v", decision)
+	}
+}
+
+func TestSlackApprover_PostFailure(t *testing.T) {
+	client := &mockHTTPClient{responses: []mockResponse{{statusCode: 200, body: `{"ok": false, "error": "channel_not_found"}`}}}
+	approver := NewSlackApprover("xoxb-test", "#missing", "secret", time.Minute, client)
+
+	_, err := approver.RequestApproval(context.Background(), ApprovalRequest{Text: "Release lib?"})
+	var notifyErr *NotificationError
+	if !errors.As(err, &notifyErr) || !strings.Contains(err.Error(), "channel_not_found") {
+		t.Fatalf("expected the Slack error, got %v", err)
+	}
+}
+
+func TestSlackApprover_RejectsUnsignedRequests(t *testing.T) {
+	approver := NewSlackApprover("xoxb-test", "#releases", "secret", time.Minute, &mockHTTPClient{})
+
+	for name, req := range map[string]*http.Request{
+		"wrong secret": slackInteractionRequest(t, "other", ApprovalActionApprove, "req-1", time.Now()),
+		"stale":        slackInteractionRequest(t, "secret", ApprovalActionApprove, "req-1", time.Now().Add(-time.Hour)),
+	} {
+		rec := httptest.NewRecorder()
+		approver.ServeHTTP(rec, req)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("%s: status = %d, want 401", name, rec.Code)
+		}
+	}
+}
+
+func TestSlackApprover_Approvers(t *testing.T) {
+	client := &mockHTTPClient{responses: []mockResponse{{statusCode: 200, body: `{"ok": true}`}}}
+	approver := NewSlackApprover("xoxb-test", "#releases", "secret", time.Minute, client, WithApprovers("bob", "U123"))
+	if !approver.allowed("U123", "alice") || !approver.allowed("U456", "bob") || approver.allowed("U456", "mallory") {
+		t.Fatal("approvers should match Slack user IDs and usernames")
+	}
+
+	approver = NewSlackApprover("xoxb-test", "#releases", "secret", time.Minute, client, WithApprovers("bob"))
+	done := make(chan *ApprovalDecision, 1)
+	go func() {
+		decision, err := approver.RequestApproval(context.Background(), ApprovalRequest{ID: "req-1", Text: "Release lib?"})
+		if err != nil {
+			t.Errorf("RequestApproval() error = %v", err)
+		}
+		done <- decision
+	}()
+	waitForPending(t, approver, "req-1")
+
+	rec := httptest.NewRecorder()
+	approver.ServeHTTP(rec, slackInteractionRequest(t, "secret", ApprovalActionApprove, "req-1", time.Now()))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("ServeHTTP() status = %d", rec.Code)
+	}
+	approver.mu.Lock()
+	_, pending := approver.pending["req-1"]
+	approver.mu.Unlock()
+	if !pending {
+		t.Fatal("a click from a user who is not an approver should leave the request pending")
+	}
+
+	approver.answer("req-1", ApprovalDecision{Approved: true, User: "bob"})
+	if decision := <-done; decision == nil || decision.User != "bob" {
+		t.Errorf("decision = %+v, want bob's", decision)
+	}
+}
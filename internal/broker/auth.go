@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/goliatone/cascade/pkg/githubx"
 	"github.com/goliatone/cascade/pkg/gitutil"
 	"github.com/google/go-github/v66/github"
 	"golang.org/x/oauth2"
@@ -22,6 +23,9 @@ type AuthConfig struct {
 	UploadURL string
 	// InsecureSkipVerify skips TLS verification (for self-signed certificates)
 	InsecureSkipVerify bool
+	// RateLimit configures how rate limited requests are retried, such as the
+	// options di.GitHubRateLimitOptions builds from the configuration
+	RateLimit []githubx.Option
 }
 
 // LoadGitHubToken loads a GitHub token from environment variables or configuration.
@@ -59,6 +63,9 @@ func CreateAuthenticatedClient(config AuthConfig) (*github.Client, error) {
 		}
 	}
 
+	// Wait out rate limits instead of failing the request
+	httpClient = githubx.WrapClient(httpClient, config.RateLimit...)
+
 	var client *github.Client
 
 	// Create GitHub client with custom base URL if specified (GitHub Enterprise)
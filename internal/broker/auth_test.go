@@ -5,12 +5,14 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"os"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/goliatone/cascade/pkg/githubx"
 	"github.com/google/go-github/v66/github"
 )
 
@@ -475,3 +477,33 @@ func jsonResponse(req *http.Request, status int, body string) *http.Response {
 	resp.Header.Set("Content-Type", "application/json")
 	return resp
 }
+
+func TestCreateAuthenticatedClient_RateLimitOptions(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	var waits []githubx.Wait
+	client, err := CreateAuthenticatedClient(AuthConfig{
+		Token:   "valid_token",
+		BaseURL: server.URL + "/api/v3/",
+		RateLimit: []githubx.Option{
+			githubx.WithMaxRetries(1),
+			githubx.WithWaitHandler(func(w githubx.Wait) { waits = append(waits, w) }),
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateAuthenticatedClient() error = %v", err)
+	}
+
+	if _, _, err := client.Users.Get(context.Background(), ""); err == nil {
+		t.Fatal("expected the rate limited request to fail once retries are exhausted")
+	}
+	if calls != 2 || len(waits) != 1 {
+		t.Errorf("calls = %d, waits = %d, want the configured single retry", calls, len(waits))
+	}
+}
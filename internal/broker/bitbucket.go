@@ -0,0 +1,445 @@
+package broker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// DefaultBitbucketEndpoint is the Bitbucket Cloud REST API.
+const DefaultBitbucketEndpoint = "https://api.bitbucket.org/2.0"
+
+// BitbucketHost is the git host of Bitbucket Cloud repositories.
+const BitbucketHost = "bitbucket.org"
+
+// Bitbucket pull request states.
+const (
+	bitbucketStateOpen   = "OPEN"
+	bitbucketStateMerged = "MERGED"
+)
+
+// BitbucketProvider implements the Provider interface using the Bitbucket Cloud
+// REST API. Repositories are workspace/repo_slug.
+type BitbucketProvider struct {
+	client   *http.Client
+	endpoint string
+	username string
+	token    string
+}
+
+// NewBitbucketProvider creates a provider for the Bitbucket Cloud API. With a
+// username, token is an app password sent as basic auth; otherwise it is sent
+// as a bearer access token. An empty endpoint selects api.bitbucket.org.
+func NewBitbucketProvider(username, token, endpoint string, client *http.Client) Provider {
+	endpoint = strings.TrimSuffix(strings.TrimSpace(endpoint), "/")
+	if endpoint == "" {
+		endpoint = DefaultBitbucketEndpoint
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &BitbucketProvider{
+		client:   client,
+		endpoint: endpoint,
+		username: strings.TrimSpace(username),
+		token:    token,
+	}
+}
+
+var (
+	_ Provider          = (*BitbucketProvider)(nil)
+	_ PullRequestGetter = (*BitbucketProvider)(nil)
+)
+
+// bitbucketPullRequest is the subset of the Bitbucket pull request resource the
+// provider reads.
+type bitbucketPullRequest struct {
+	ID          int               `json:"id"`
+	Title       string            `json:"title"`
+	State       string            `json:"state"`
+	Links       bitbucketLinks    `json:"links"`
+	Source      bitbucketEndpoint `json:"source"`
+	Destination bitbucketEndpoint `json:"destination"`
+	MergeCommit *struct {
+		Hash string `json:"hash"`
+	} `json:"merge_commit"`
+	Reviewers []bitbucketUser `json:"reviewers"`
+}
+
+type bitbucketLinks struct {
+	HTML struct {
+		Href string `json:"href"`
+	} `json:"html"`
+}
+
+type bitbucketEndpoint struct {
+	Branch struct {
+		Name string `json:"name"`
+	} `json:"branch"`
+}
+
+// bitbucketUser identifies a reviewer by account ID or UUID.
+type bitbucketUser struct {
+	UUID      string `json:"uuid,omitempty"`
+	AccountID string `json:"account_id,omitempty"`
+}
+
+func (pr *bitbucketPullRequest) pullRequest(repo string) *PullRequest {
+	return &PullRequest{
+		URL:    pr.Links.HTML.Href,
+		Number: pr.ID,
+		Repo:   repo,
+		Labels: []string{},
+	}
+}
+
+// CreateOrUpdatePullRequest creates a pull request, or updates the open one
+// from the same source branch. Bitbucket pull requests have no labels, so
+// input.Labels are ignored.
+func (p *BitbucketProvider) CreateOrUpdatePullRequest(ctx context.Context, input PRInput) (*PullRequest, error) {
+	repoPath, err := bitbucketRepoPath(input.Repo)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := p.listPullRequests(ctx, input.Repo, repoPath, input.HeadBranch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find existing pull request: %w", err)
+	}
+
+	if len(existing) > 0 {
+		payload := map[string]any{
+			"title":       input.Title,
+			"description": input.Body,
+		}
+		var updated bitbucketPullRequest
+		if err := p.do(ctx, "update pull request", input.Repo, http.MethodPut, bitbucketPullRequestPath(repoPath, existing[0].ID), payload, &updated); err != nil {
+			return nil, err
+		}
+		return updated.pullRequest(input.Repo), nil
+	}
+
+	payload := map[string]any{
+		"title":               input.Title,
+		"description":         input.Body,
+		"source":              map[string]any{"branch": map[string]string{"name": input.HeadBranch}},
+		"destination":         map[string]any{"branch": map[string]string{"name": input.BaseBranch}},
+		"close_source_branch": true,
+	}
+	var created bitbucketPullRequest
+	if err := p.do(ctx, "create pull request", input.Repo, http.MethodPost, repoPath+"/pullrequests", payload, &created); err != nil {
+		return nil, err
+	}
+	return created.pullRequest(input.Repo), nil
+}
+
+// AddLabels does nothing: Bitbucket pull requests have no labels.
+func (p *BitbucketProvider) AddLabels(ctx context.Context, repo string, number int, labels []string) error {
+	return nil
+}
+
+// RequestReviewers adds reviewers to a pull request, keeping those already on
+// it. Bitbucket identifies users by account ID or by {UUID}, not by name.
+// Bitbucket has no team reviewers, so teamReviewers are ignored.
+func (p *BitbucketProvider) RequestReviewers(ctx context.Context, repo string, number int, reviewers []string, teamReviewers []string) error {
+	if len(reviewers) == 0 {
+		return nil
+	}
+	repoPath, err := bitbucketRepoPath(repo)
+	if err != nil {
+		return err
+	}
+
+	var pr bitbucketPullRequest
+	if err := p.do(ctx, "get pull request", repo, http.MethodGet, bitbucketPullRequestPath(repoPath, number), nil, &pr); err != nil {
+		return err
+	}
+
+	users := append([]bitbucketUser(nil), pr.Reviewers...)
+	for _, reviewer := range reviewers {
+		user := bitbucketReviewer(reviewer)
+		if !containsBitbucketUser(users, user) {
+			users = append(users, user)
+		}
+	}
+	// The update replaces the title, so the current one is sent back
+	payload := map[string]any{
+		"title":     pr.Title,
+		"reviewers": users,
+	}
+	return p.do(ctx, "request reviewers", repo, http.MethodPut, bitbucketPullRequestPath(repoPath, number), payload, nil)
+}
+
+// ListPullRequests lists open pull requests from the given source branch.
+func (p *BitbucketProvider) ListPullRequests(ctx context.Context, repo string, headBranch string) ([]*PullRequest, error) {
+	repoPath, err := bitbucketRepoPath(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	prs, err := p.listPullRequests(ctx, repo, repoPath, headBranch)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*PullRequest
+	for i := range prs {
+		result = append(result, prs[i].pullRequest(repo))
+	}
+	return result, nil
+}
+
+// AddComment adds a comment to a pull request.
+func (p *BitbucketProvider) AddComment(ctx context.Context, repo string, number int, body string) error {
+	repoPath, err := bitbucketRepoPath(repo)
+	if err != nil {
+		return err
+	}
+	payload := map[string]any{"content": map[string]string{"raw": body}}
+	return p.do(ctx, "add comment", repo, http.MethodPost, bitbucketPullRequestPath(repoPath, number)+"/comments", payload, nil)
+}
+
+// MergePullRequest merges a pull request. Refusals because the pull request
+// cannot be merged, such as conflicts or unmet merge checks, are reported as
+// ErrNotMergeable. Bitbucket cannot check the head commit, so opts.SHA is
+// ignored.
+func (p *BitbucketProvider) MergePullRequest(ctx context.Context, repo string, number int, opts MergeOptions) (*MergeResult, error) {
+	repoPath, err := bitbucketRepoPath(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := map[string]any{"close_source_branch": true}
+	if strategy := bitbucketMergeStrategy(opts.Method); strategy != "" {
+		payload["merge_strategy"] = strategy
+	}
+	if message := mergeCommitMessage(opts); message != "" {
+		payload["message"] = message
+	}
+
+	var merged bitbucketPullRequest
+	err = p.do(ctx, "merge pull request", repo, http.MethodPost, bitbucketPullRequestPath(repoPath, number)+"/merge", payload, &merged)
+	if err != nil {
+		var apiErr *BitbucketAPIError
+		if errors.As(err, &apiErr) {
+			switch apiErr.StatusCode {
+			case http.StatusBadRequest, http.StatusConflict:
+				apiErr.Err = fmt.Errorf("%w: %v", ErrNotMergeable, apiErr.Err)
+			}
+		}
+		return nil, err
+	}
+
+	result := &MergeResult{Merged: merged.State == bitbucketStateMerged}
+	if merged.MergeCommit != nil {
+		result.SHA = merged.MergeCommit.Hash
+	}
+	if !result.Merged {
+		// Long merges are accepted and finished in the background
+		result.Message = "merge accepted and still in progress"
+	}
+	return result, nil
+}
+
+// ClosePullRequest declines a pull request.
+func (p *BitbucketProvider) ClosePullRequest(ctx context.Context, repo string, number int) error {
+	repoPath, err := bitbucketRepoPath(repo)
+	if err != nil {
+		return err
+	}
+	return p.do(ctx, "decline pull request", repo, http.MethodPost, bitbucketPullRequestPath(repoPath, number)+"/decline", nil, nil)
+}
+
+// GetPullRequest looks up whether a pull request is open, declined, or merged.
+func (p *BitbucketProvider) GetPullRequest(ctx context.Context, repo string, number int) (*PullRequestStatus, error) {
+	repoPath, err := bitbucketRepoPath(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	var pr bitbucketPullRequest
+	if err := p.do(ctx, "get pull request", repo, http.MethodGet, bitbucketPullRequestPath(repoPath, number), nil, &pr); err != nil {
+		return nil, err
+	}
+
+	status := &PullRequestStatus{
+		State:      PullRequestClosed,
+		Merged:     pr.State == bitbucketStateMerged,
+		BaseBranch: pr.Destination.Branch.Name,
+		HeadBranch: pr.Source.Branch.Name,
+	}
+	if pr.State == bitbucketStateOpen {
+		status.State = PullRequestOpen
+	}
+	if status.Merged && pr.MergeCommit != nil {
+		status.MergeCommitSHA = pr.MergeCommit.Hash
+	}
+	return status, nil
+}
+
+// GetPullRequestChecks reports the build statuses of a pull request's source
+// commit. Stopped builds count as failed.
+func (p *BitbucketProvider) GetPullRequestChecks(ctx context.Context, repo string, number int) (*ChecksStatus, error) {
+	repoPath, err := bitbucketRepoPath(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	var page struct {
+		Values []struct {
+			Key   string `json:"key"`
+			Name  string `json:"name"`
+			State string `json:"state"`
+		} `json:"values"`
+	}
+	if err := p.do(ctx, "list pull request statuses", repo, http.MethodGet, bitbucketPullRequestPath(repoPath, number)+"/statuses?pagelen=100", nil, &page); err != nil {
+		return nil, err
+	}
+
+	checks := newChecksStatus()
+	for _, status := range page.Values {
+		name := firstNonEmptyString(status.Name, status.Key)
+		switch status.State {
+		case "SUCCESSFUL":
+			checks.add(name, ChecksPassed)
+		case "INPROGRESS":
+			checks.add(name, ChecksPending)
+		default:
+			checks.add(name, ChecksFailed)
+		}
+	}
+	return checks, nil
+}
+
+func (p *BitbucketProvider) listPullRequests(ctx context.Context, repo, repoPath, sourceBranch string) ([]bitbucketPullRequest, error) {
+	query := url.Values{}
+	query.Set("q", fmt.Sprintf(`source.branch.name = %s AND state = "%s"`, strconv.Quote(sourceBranch), bitbucketStateOpen))
+	query.Set("sort", "-created_on")
+	query.Set("pagelen", "10")
+
+	var page struct {
+		Values []bitbucketPullRequest `json:"values"`
+	}
+	if err := p.do(ctx, "list pull requests", repo, http.MethodGet, repoPath+"/pullrequests?"+query.Encode(), nil, &page); err != nil {
+		return nil, err
+	}
+	return page.Values, nil
+}
+
+// do sends a request to the API and decodes the JSON response into out, which
+// may be nil.
+func (p *BitbucketProvider) do(ctx context.Context, operation, repo, method, path string, payload, out any) error {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s request: %w", operation, err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, p.endpoint+path, body)
+	if err != nil {
+		return &BitbucketAPIError{Operation: operation, Repo: repo, Err: err}
+	}
+	if p.username != "" {
+		req.SetBasicAuth(p.username, p.token)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+p.token)
+	}
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return &BitbucketAPIError{Operation: operation, Repo: repo, Err: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return &BitbucketAPIError{
+			Operation:    operation,
+			Repo:         repo,
+			StatusCode:   resp.StatusCode,
+			ResponseBody: string(data),
+			Err:          fmt.Errorf("%s", bitbucketErrorMessage(resp.Status, data)),
+		}
+	}
+
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil && !errors.Is(err, io.EOF) {
+		return &BitbucketAPIError{Operation: operation, Repo: repo, StatusCode: resp.StatusCode, Err: fmt.Errorf("decode response: %w", err)}
+	}
+	return nil
+}
+
+// bitbucketRepoPath returns the API path of a repository.
+func bitbucketRepoPath(repo string) (string, error) {
+	workspace, slug, err := ParseRepoString(repo)
+	if err != nil {
+		return "", fmt.Errorf("invalid repository format %q: %w", repo, err)
+	}
+	return "/repositories/" + url.PathEscape(workspace) + "/" + url.PathEscape(slug), nil
+}
+
+func bitbucketPullRequestPath(repoPath string, number int) string {
+	return repoPath + "/pullrequests/" + strconv.Itoa(number)
+}
+
+// bitbucketReviewer reads a reviewer given as {UUID} or as an account ID.
+func bitbucketReviewer(reviewer string) bitbucketUser {
+	if strings.HasPrefix(reviewer, "{") && strings.HasSuffix(reviewer, "}") {
+		return bitbucketUser{UUID: reviewer}
+	}
+	return bitbucketUser{AccountID: reviewer}
+}
+
+func containsBitbucketUser(users []bitbucketUser, user bitbucketUser) bool {
+	for _, existing := range users {
+		if (user.UUID != "" && existing.UUID == user.UUID) || (user.AccountID != "" && existing.AccountID == user.AccountID) {
+			return true
+		}
+	}
+	return false
+}
+
+// bitbucketMergeStrategy maps a merge method to a Bitbucket merge strategy.
+// Empty selects the repository's default.
+func bitbucketMergeStrategy(method MergeMethod) string {
+	switch method {
+	case MergeMethodMerge:
+		return "merge_commit"
+	case MergeMethodSquash:
+		return "squash"
+	case MergeMethodRebase:
+		return "rebase_merge"
+	default:
+		return ""
+	}
+}
+
+// bitbucketErrorMessage extracts the error message Bitbucket returns with
+// failed requests.
+func bitbucketErrorMessage(status string, body []byte) string {
+	var payload struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &payload); err == nil && payload.Error.Message != "" {
+		return fmt.Sprintf("%s: %s", status, payload.Error.Message)
+	}
+	return status
+}
@@ -0,0 +1,263 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// bitbucketServer serves canned responses keyed by method and path, and records
+// request bodies and queries.
+type bitbucketServer struct {
+	t         *testing.T
+	responses map[string]bitbucketResponse
+	requests  map[string]map[string]any
+	queries   map[string]string
+}
+
+type bitbucketResponse struct {
+	status int
+	body   any
+}
+
+func newBitbucketServer(t *testing.T, responses map[string]bitbucketResponse) (*bitbucketServer, Provider) {
+	t.Helper()
+	s := &bitbucketServer{t: t, responses: responses, requests: map[string]map[string]any{}, queries: map[string]string{}}
+	server := httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(server.Close)
+	return s, NewBitbucketProvider("ci-bot", "app-password", server.URL+"/2.0/", server.Client())
+}
+
+func (s *bitbucketServer) serve(w http.ResponseWriter, r *http.Request) {
+	if user, pass, ok := r.BasicAuth(); !ok || user != "ci-bot" || pass != "app-password" {
+		s.t.Errorf("basic auth = %q/%q", user, pass)
+	}
+	key := r.Method + " " + strings.TrimPrefix(r.URL.EscapedPath(), "/2.0")
+	s.queries[key] = r.URL.Query().Get("q")
+	if r.Body != nil {
+		var payload map[string]any
+		if json.NewDecoder(r.Body).Decode(&payload) == nil {
+			s.requests[key] = payload
+		}
+	}
+	resp, ok := s.responses[key]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"type":"error","error":{"message":"Repository not found"}}`))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(resp.status)
+	if resp.body != nil {
+		_ = json.NewEncoder(w).Encode(resp.body)
+	}
+}
+
+func bitbucketPR(id int, state string) map[string]any {
+	return map[string]any{
+		"id":    id,
+		"title": "Update lib",
+		"state": state,
+		"links": map[string]any{"html": map[string]any{"href": "https://bitbucket.org/team/app/pull-requests/" + strconv.Itoa(id)}},
+	}
+}
+
+func TestBitbucketProvider_CreateOrUpdatePullRequest_CreateNew(t *testing.T) {
+	server, provider := newBitbucketServer(t, map[string]bitbucketResponse{
+		"GET /repositories/team/app/pullrequests":  {status: 200, body: map[string]any{"values": []any{}}},
+		"POST /repositories/team/app/pullrequests": {status: 201, body: bitbucketPR(1, "OPEN")},
+	})
+
+	pr, err := provider.CreateOrUpdatePullRequest(context.Background(), PRInput{
+		Repo:       "bitbucket.org/team/app",
+		BaseBranch: "main",
+		HeadBranch: "cascade/update",
+		Title:      "Update lib",
+		Body:       "Bumps lib.",
+		Labels:     []string{"deps"},
+	})
+	if err != nil {
+		t.Fatalf("CreateOrUpdatePullRequest() error = %v", err)
+	}
+	if pr.Number != 1 || pr.URL != "https://bitbucket.org/team/app/pull-requests/1" || pr.Repo != "bitbucket.org/team/app" || len(pr.Labels) != 0 {
+		t.Errorf("pr = %+v", pr)
+	}
+
+	if got := server.queries["GET /repositories/team/app/pullrequests"]; got != `source.branch.name = "cascade/update" AND state = "OPEN"` {
+		t.Errorf("list query = %q", got)
+	}
+	sent := server.requests["POST /repositories/team/app/pullrequests"]
+	source, _ := sent["source"].(map[string]any)
+	destination, _ := sent["destination"].(map[string]any)
+	if sent["description"] != "Bumps lib." || sent["close_source_branch"] != true ||
+		source["branch"].(map[string]any)["name"] != "cascade/update" || destination["branch"].(map[string]any)["name"] != "main" {
+		t.Errorf("create payload = %v", sent)
+	}
+}
+
+func TestBitbucketProvider_CreateOrUpdatePullRequest_UpdateExisting(t *testing.T) {
+	server, provider := newBitbucketServer(t, map[string]bitbucketResponse{
+		"GET /repositories/team/app/pullrequests":   {status: 200, body: map[string]any{"values": []any{bitbucketPR(2, "OPEN")}}},
+		"PUT /repositories/team/app/pullrequests/2": {status: 200, body: bitbucketPR(2, "OPEN")},
+	})
+
+	pr, err := provider.CreateOrUpdatePullRequest(context.Background(), PRInput{
+		Repo:       "team/app",
+		BaseBranch: "main",
+		HeadBranch: "cascade/update",
+		Title:      "Update lib again",
+		Body:       "Bumps lib.",
+	})
+	if err != nil {
+		t.Fatalf("CreateOrUpdatePullRequest() error = %v", err)
+	}
+	if pr.Number != 2 {
+		t.Errorf("pr = %+v, want the existing pull request", pr)
+	}
+	if sent := server.requests["PUT /repositories/team/app/pullrequests/2"]; sent["title"] != "Update lib again" || sent["description"] != "Bumps lib." {
+		t.Errorf("update payload = %v", sent)
+	}
+}
+
+func TestBitbucketProvider_ReviewersLabelsAndComments(t *testing.T) {
+	existing := bitbucketPR(3, "OPEN")
+	existing["reviewers"] = []any{map[string]any{"account_id": "557058:alice"}}
+	server, provider := newBitbucketServer(t, map[string]bitbucketResponse{
+		"GET /repositories/team/app/pullrequests/3":           {status: 200, body: existing},
+		"PUT /repositories/team/app/pullrequests/3":           {status: 200, body: existing},
+		"POST /repositories/team/app/pullrequests/3/comments": {status: 201, body: map[string]any{"id": 1}},
+	})
+	ctx := context.Background()
+
+	if err := provider.RequestReviewers(ctx, "team/app", 3, []string{"557058:alice", "{b0b-uuid}"}, []string{"platform"}); err != nil {
+		t.Fatalf("RequestReviewers() error = %v", err)
+	}
+	sent := server.requests["PUT /repositories/team/app/pullrequests/3"]
+	reviewers, _ := sent["reviewers"].([]any)
+	if sent["title"] != "Update lib" || len(reviewers) != 2 ||
+		reviewers[0].(map[string]any)["account_id"] != "557058:alice" || reviewers[1].(map[string]any)["uuid"] != "{b0b-uuid}" {
+		t.Errorf("reviewers payload = %v", sent)
+	}
+
+	if err := provider.AddLabels(ctx, "team/app", 3, []string{"deps"}); err != nil {
+		t.Fatalf("AddLabels() error = %v", err)
+	}
+
+	if err := provider.AddComment(ctx, "team/app", 3, "Tests passed"); err != nil {
+		t.Fatalf("AddComment() error = %v", err)
+	}
+	content, _ := server.requests["POST /repositories/team/app/pullrequests/3/comments"]["content"].(map[string]any)
+	if content["raw"] != "Tests passed" {
+		t.Errorf("comment payload = %v", content)
+	}
+}
+
+func TestBitbucketProvider_MergeCloseAndGet(t *testing.T) {
+	merged := bitbucketPR(3, "MERGED")
+	merged["merge_commit"] = map[string]any{"hash": "abc123"}
+	merged["source"] = map[string]any{"branch": map[string]any{"name": "cascade/update"}}
+	merged["destination"] = map[string]any{"branch": map[string]any{"name": "main"}}
+	server, provider := newBitbucketServer(t, map[string]bitbucketResponse{
+		"POST /repositories/team/app/pullrequests/3/merge":   {status: 200, body: merged},
+		"POST /repositories/team/app/pullrequests/4/merge":   {status: 400, body: map[string]any{"error": map[string]any{"message": "You can't merge until you resolve all merge conflicts."}}},
+		"POST /repositories/team/app/pullrequests/5/decline": {status: 200, body: bitbucketPR(5, "DECLINED")},
+		"GET /repositories/team/app/pullrequests/3":          {status: 200, body: merged},
+	})
+	ctx := context.Background()
+
+	result, err := provider.MergePullRequest(ctx, "team/app", 3, MergeOptions{Method: MergeMethodSquash, CommitTitle: "Update lib"})
+	if err != nil {
+		t.Fatalf("MergePullRequest() error = %v", err)
+	}
+	if !result.Merged || result.SHA != "abc123" {
+		t.Errorf("merge result = %+v", result)
+	}
+	if sent := server.requests["POST /repositories/team/app/pullrequests/3/merge"]; sent["merge_strategy"] != "squash" || sent["message"] != "Update lib" {
+		t.Errorf("merge payload = %v", sent)
+	}
+
+	_, err = provider.MergePullRequest(ctx, "team/app", 4, MergeOptions{})
+	var apiErr *BitbucketAPIError
+	if !errors.Is(err, ErrNotMergeable) || !errors.As(err, &apiErr) || !strings.Contains(err.Error(), "resolve all merge conflicts") {
+		t.Errorf("MergePullRequest() error = %v, want ErrNotMergeable with Bitbucket's message", err)
+	}
+
+	if err := provider.ClosePullRequest(ctx, "team/app", 5); err != nil {
+		t.Fatalf("ClosePullRequest() error = %v", err)
+	}
+
+	status, err := provider.(PullRequestGetter).GetPullRequest(ctx, "team/app", 3)
+	if err != nil {
+		t.Fatalf("GetPullRequest() error = %v", err)
+	}
+	want := PullRequestStatus{State: PullRequestClosed, Merged: true, MergeCommitSHA: "abc123", BaseBranch: "main", HeadBranch: "cascade/update"}
+	if *status != want {
+		t.Errorf("GetPullRequest() = %+v, want %+v", *status, want)
+	}
+}
+
+func TestBitbucketProvider_GetPullRequestChecks(t *testing.T) {
+	_, provider := newBitbucketServer(t, map[string]bitbucketResponse{
+		"GET /repositories/team/app/pullrequests/3/statuses": {status: 200, body: map[string]any{"values": []map[string]any{
+			{"key": "build", "name": "Build", "state": "SUCCESSFUL"},
+			{"key": "deploy", "state": "INPROGRESS"},
+		}}},
+		"GET /repositories/team/app/pullrequests/4/statuses": {status: 200, body: map[string]any{"values": []map[string]any{
+			{"key": "build", "name": "Build", "state": "STOPPED"},
+		}}},
+	})
+	getter := provider.(ChecksGetter)
+	ctx := context.Background()
+
+	checks, err := getter.GetPullRequestChecks(ctx, "team/app", 3)
+	if err != nil {
+		t.Fatalf("GetPullRequestChecks() error = %v", err)
+	}
+	if checks.State != ChecksPending || checks.Total != 2 || len(checks.Pending) != 1 || checks.Pending[0] != "deploy" {
+		t.Errorf("GetPullRequestChecks() = %+v, want deploy pending", checks)
+	}
+
+	checks, err = getter.GetPullRequestChecks(ctx, "team/app", 4)
+	if err != nil {
+		t.Fatalf("GetPullRequestChecks() error = %v", err)
+	}
+	if checks.State != ChecksFailed || len(checks.Failed) != 1 || checks.Failed[0] != "Build" {
+		t.Errorf("GetPullRequestChecks() = %+v, want Build failed", checks)
+	}
+}
+
+func TestBitbucketProvider_BearerToken(t *testing.T) {
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	provider := NewBitbucketProvider("", "access-token", server.URL, server.Client())
+	if err := provider.AddComment(context.Background(), "team/app", 1, "hi"); err != nil {
+		t.Fatalf("AddComment() error = %v", err)
+	}
+	if auth != "Bearer access-token" {
+		t.Errorf("Authorization = %q, want bearer token", auth)
+	}
+}
+
+func TestBitbucketProvider_Errors(t *testing.T) {
+	_, provider := newBitbucketServer(t, map[string]bitbucketResponse{})
+
+	err := provider.AddComment(context.Background(), "team/app", 9, "hello")
+	var apiErr *BitbucketAPIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 404 || !strings.Contains(err.Error(), "Repository not found") {
+		t.Errorf("AddComment() error = %v, want API 404 with Bitbucket's message", err)
+	}
+
+	if err := provider.ClosePullRequest(context.Background(), "invalid-repo", 1); err == nil || !strings.Contains(err.Error(), "invalid repository format") {
+		t.Errorf("ClosePullRequest() error = %v, want invalid repository format", err)
+	}
+}
@@ -0,0 +1,150 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/goliatone/cascade/internal/planner"
+	"github.com/google/go-github/v66/github"
+)
+
+// BranchCleanupEnabler is implemented by providers whose pull requests cannot
+// delete their head branch on merge themselves, but whose repositories can.
+// It is optional; the broker type-asserts. GitLab and Bitbucket pull requests
+// already delete their source branch on merge.
+type BranchCleanupEnabler interface {
+	// EnableBranchCleanup makes repo delete head branches once their pull
+	// requests merge.
+	EnableBranchCleanup(ctx context.Context, repo string) error
+}
+
+// BranchDeleter is implemented by providers that can delete a branch. It is
+// optional; callers type-assert. A branch that is already gone is not an error.
+type BranchDeleter interface {
+	DeleteBranch(ctx context.Context, repo, branch string) error
+}
+
+// BranchCleaner is implemented by brokers that can delete the branch of a
+// merged pull request. It is optional; callers type-assert.
+type BranchCleaner interface {
+	// DeleteBranch deletes branch from repo.
+	DeleteBranch(ctx context.Context, repo, branch string) error
+}
+
+var (
+	_ BranchCleaner        = (*broker)(nil)
+	_ BranchCleanupEnabler = (*GitHubProvider)(nil)
+	_ BranchCleanupEnabler = (*RoutingProvider)(nil)
+	_ BranchDeleter        = (*GitHubProvider)(nil)
+	_ BranchDeleter        = (*RoutingProvider)(nil)
+)
+
+// enableBranchCleanup turns on branch deletion for the repository of item
+// when its provider needs it. Failures are logged; the branch is then left for
+// DeleteBranch once the merge is seen.
+func (b *broker) enableBranchCleanup(ctx context.Context, item planner.WorkItem) {
+	enabler, ok := b.provider.(BranchCleanupEnabler)
+	if !ok {
+		return
+	}
+	err := b.callProvider(ctx, "enable branch cleanup", item.Repo, func(ctx context.Context) error {
+		return enabler.EnableBranchCleanup(ctx, item.Repo)
+	})
+	if err != nil {
+		b.logger.Warn("Failed to enable branch deletion on merge; the branch stays until cascade sees the merge", "module", item.Module, "repo", item.Repo, "error", err)
+	}
+}
+
+func (b *broker) DeleteBranch(ctx context.Context, repo, branch string) error {
+	if branch == "" {
+		return fmt.Errorf("branch cannot be empty")
+	}
+
+	if b.config.DryRun {
+		b.logger.Info("Dry run: would delete branch", "repo", repo, "branch", branch)
+		return nil
+	}
+
+	deleter, ok := b.provider.(BranchDeleter)
+	if !ok {
+		return &NotImplementedError{Operation: "broker.DeleteBranch"}
+	}
+
+	err := b.callProvider(ctx, "delete branch", repo, func(ctx context.Context) error {
+		return deleter.DeleteBranch(ctx, repo, branch)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete branch %s in %s: %w", branch, repo, err)
+	}
+	return nil
+}
+
+// EnableBranchCleanup turns on the repository's "Automatically delete head
+// branches" setting when it is off. Changing it needs admin access.
+func (p *GitHubProvider) EnableBranchCleanup(ctx context.Context, repo string) error {
+	owner, repoName, err := ParseRepoString(repo)
+	if err != nil {
+		return fmt.Errorf("invalid repository format %q: %w", repo, err)
+	}
+
+	current, _, err := p.client.Repositories.Get(ctx, owner, repoName)
+	if err != nil {
+		return &GitHubAPIError{Operation: "get repository", Repo: repo, Err: err}
+	}
+	if current.GetDeleteBranchOnMerge() {
+		return nil
+	}
+
+	_, _, err = p.client.Repositories.Edit(ctx, owner, repoName, &github.Repository{DeleteBranchOnMerge: github.Bool(true)})
+	if err != nil {
+		return &GitHubAPIError{Operation: "enable delete branch on merge", Repo: repo, Err: err}
+	}
+	return nil
+}
+
+// DeleteBranch deletes a branch. Branches that no longer exist, such as those
+// the repository deleted on merge, are ignored.
+func (p *GitHubProvider) DeleteBranch(ctx context.Context, repo, branch string) error {
+	owner, repoName, err := ParseRepoString(repo)
+	if err != nil {
+		return fmt.Errorf("invalid repository format %q: %w", repo, err)
+	}
+
+	resp, err := p.client.Git.DeleteRef(ctx, owner, repoName, "heads/"+branch)
+	if err != nil {
+		// GitHub answers 422 "Reference does not exist" for missing branches
+		if resp != nil && (resp.StatusCode == http.StatusUnprocessableEntity || resp.StatusCode == http.StatusNotFound) {
+			return nil
+		}
+		return &GitHubAPIError{Operation: "delete branch", Repo: repo, Err: err}
+	}
+	return nil
+}
+
+// EnableBranchCleanup enables branch cleanup when the repository's provider
+// needs it.
+func (r *RoutingProvider) EnableBranchCleanup(ctx context.Context, repo string) error {
+	provider, err := r.providerFor(ctx, repo)
+	if err != nil {
+		return err
+	}
+	if enabler, ok := provider.(BranchCleanupEnabler); ok {
+		return enabler.EnableBranchCleanup(ctx, repo)
+	}
+	return nil
+}
+
+// DeleteBranch deletes the branch when the repository's provider implements
+// BranchDeleter.
+func (r *RoutingProvider) DeleteBranch(ctx context.Context, repo, branch string) error {
+	provider, err := r.providerFor(ctx, repo)
+	if err != nil {
+		return err
+	}
+	deleter, ok := provider.(BranchDeleter)
+	if !ok {
+		return &NotImplementedError{Operation: "provider.DeleteBranch"}
+	}
+	return deleter.DeleteBranch(ctx, repo, branch)
+}
@@ -0,0 +1,102 @@
+package broker_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/goliatone/cascade/internal/broker"
+	"github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/internal/manifest"
+	"github.com/goliatone/cascade/internal/planner"
+)
+
+// cleanupProvider adds branch cleanup to mockProvider.
+type cleanupProvider struct {
+	*mockProvider
+	enableErr error
+	enabled   []string
+	deleted   []string
+}
+
+func (p *cleanupProvider) EnableBranchCleanup(ctx context.Context, repo string) error {
+	p.enabled = append(p.enabled, repo)
+	return p.enableErr
+}
+
+func (p *cleanupProvider) DeleteBranch(ctx context.Context, repo, branch string) error {
+	p.deleted = append(p.deleted, repo+"@"+branch)
+	return nil
+}
+
+func TestBroker_EnsurePR_EnablesBranchCleanup(t *testing.T) {
+	item := planner.WorkItem{
+		Repo:       "owner/repo",
+		Module:     "github.com/test/module",
+		Branch:     "main",
+		BranchName: "cascade/update",
+		PR:         manifest.PRConfig{DeleteBranchOnMerge: true},
+	}
+	result := &executor.Result{Status: executor.StatusCompleted}
+
+	var input broker.PRInput
+	provider := &cleanupProvider{mockProvider: &mockProvider{
+		createOrUpdatePR: func(ctx context.Context, in broker.PRInput) (*broker.PullRequest, error) {
+			input = in
+			return &broker.PullRequest{Repo: in.Repo, Number: 1}, nil
+		},
+	}}
+	logger := &mockLogger{}
+	b := broker.New(provider, &mockNotifier{}, broker.DefaultConfig(), logger)
+
+	if _, err := b.EnsurePR(context.Background(), item, result); err != nil {
+		t.Fatalf("EnsurePR() error = %v", err)
+	}
+	if !input.DeleteBranchOnMerge || len(provider.enabled) != 1 {
+		t.Errorf("DeleteBranchOnMerge = %v and cleanup enabled for %v, want both set", input.DeleteBranchOnMerge, provider.enabled)
+	}
+
+	// Failing to change the repository setting does not fail the pull request
+	provider.enableErr = errors.New("403 Must have admin rights to Repository")
+	if _, err := b.EnsurePR(context.Background(), item, result); err != nil {
+		t.Fatalf("EnsurePR() error = %v", err)
+	}
+	if len(logger.warnCalls) != 1 {
+		t.Errorf("warnings = %v, want the failed setting reported", logger.warnCalls)
+	}
+
+	// Items without the policy leave the repository alone
+	item.PR.DeleteBranchOnMerge = false
+	provider.enabled = nil
+	if _, err := b.EnsurePR(context.Background(), item, result); err != nil {
+		t.Fatalf("EnsurePR() error = %v", err)
+	}
+	if len(provider.enabled) != 0 {
+		t.Errorf("cleanup enabled for %v, want none", provider.enabled)
+	}
+}
+
+func TestBroker_DeleteBranch(t *testing.T) {
+	provider := &cleanupProvider{mockProvider: &mockProvider{}}
+	b := broker.New(provider, &mockNotifier{}, broker.DefaultConfig(), &mockLogger{}).(broker.BranchCleaner)
+
+	if err := b.DeleteBranch(context.Background(), "owner/repo", "cascade/update"); err != nil {
+		t.Fatalf("DeleteBranch() error = %v", err)
+	}
+	if len(provider.deleted) != 1 || provider.deleted[0] != "owner/repo@cascade/update" {
+		t.Errorf("deleted = %v, want owner/repo@cascade/update", provider.deleted)
+	}
+
+	config := broker.DefaultConfig()
+	config.DryRun = true
+	dryRun := broker.New(provider, &mockNotifier{}, config, &mockLogger{}).(broker.BranchCleaner)
+	if err := dryRun.DeleteBranch(context.Background(), "owner/repo", "cascade/other"); err != nil || len(provider.deleted) != 1 {
+		t.Errorf("dry run DeleteBranch() = %v with deletions %v, want nothing deleted", err, provider.deleted)
+	}
+
+	plain := broker.New(&mockProvider{}, &mockNotifier{}, broker.DefaultConfig(), &mockLogger{}).(broker.BranchCleaner)
+	var notImplemented *broker.NotImplementedError
+	if err := plain.DeleteBranch(context.Background(), "owner/repo", "cascade/update"); !errors.As(err, &notImplemented) {
+		t.Errorf("DeleteBranch() error = %v, want NotImplementedError", err)
+	}
+}
@@ -6,6 +6,8 @@ import (
 
 	"github.com/goliatone/cascade/internal/executor"
 	"github.com/goliatone/cascade/internal/planner"
+	"github.com/goliatone/cascade/internal/telemetry"
+	"github.com/goliatone/cascade/pkg/retry"
 )
 
 // Config holds broker configuration.
@@ -20,8 +22,26 @@ type Config struct {
 	// Default labels for all PRs
 	DefaultLabels []string
 
+	// SemverLabels adds a semver:major, semver:minor, or semver:patch label
+	// computed from the dependent's old and new required version
+	SemverLabels bool
+
+	// PRFormat selects the default PR title and body: PRFormatCascade, or
+	// PRFormatDependabot for a semantic title and a Dependabot metadata block
+	// that tooling parsing Dependabot pull requests understands. Templates set
+	// above take precedence.
+	PRFormat string
+
+	// CommitPrefix is the semantic prefix of Dependabot-style titles
+	CommitPrefix string
+
 	// Notification configuration
 	NotificationConfig NotificationConfig
+
+	// ProviderRetry retries pull request calls that fail transiently, such as
+	// on server errors or dropped connections. The zero value makes one
+	// attempt; Retryable defaults to transient provider errors.
+	ProviderRetry retry.Policy
 }
 
 // DefaultConfig returns sensible broker defaults.
@@ -31,6 +51,9 @@ func DefaultConfig() Config {
 		TitleTemplate:      "", // Will use default from templates.go
 		BodyTemplate:       "", // Will use default from templates.go
 		DefaultLabels:      []string{"automation:cascade"},
+		SemverLabels:       true,
+		PRFormat:           PRFormatCascade,
+		CommitPrefix:       executor.DefaultCommitPrefix,
 		NotificationConfig: DefaultNotificationConfig(),
 	}
 }
@@ -86,13 +109,20 @@ type broker struct {
 }
 
 func (b *broker) EnsurePR(ctx context.Context, item planner.WorkItem, result *executor.Result) (*PullRequest, error) {
+	ctx, span := telemetry.Start(ctx, "broker.ensure_pr", telemetry.String("repo", item.Repo))
+	pr, err := b.ensurePR(ctx, item, result)
+	span.End(err)
+	return pr, err
+}
+
+func (b *broker) ensurePR(ctx context.Context, item planner.WorkItem, result *executor.Result) (*PullRequest, error) {
 	// In dry-run mode, return deterministic metadata without making API calls
 	if b.config.DryRun {
 		return &PullRequest{
 			URL:    fmt.Sprintf("https://github.com/%s/pull/0", item.Repo),
 			Number: 0,
 			Repo:   item.Repo,
-			Labels: b.mergeLabels(item.Labels),
+			Labels: prLabels(b.config, item, result),
 		}, nil
 	}
 
@@ -114,47 +144,48 @@ func (b *broker) EnsurePR(ctx context.Context, item planner.WorkItem, result *ex
 		return nil, nil
 	}
 
-	// Render PR title and body using templates
-	title, err := RenderTitle(b.config.TitleTemplate, item, result)
+	// Render, sanitise, and validate the PR payload
+	prInput, err := buildPRInput(b.config, item, result)
 	if err != nil {
-		return nil, fmt.Errorf("render PR title: %w", err)
+		return nil, err
 	}
 
-	body, err := RenderBody(b.config.BodyTemplate, item, result)
-	if err != nil {
-		return nil, fmt.Errorf("render PR body: %w", err)
-	}
-
-	// Prepare PR input
-	prInput := PRInput{
-		Repo:       item.Repo,
-		BaseBranch: item.Branch,
-		HeadBranch: item.BranchName,
-		Title:      title,
-		Body:       body,
-		Labels:     SanitizeLabels(b.mergeLabels(item.Labels)),
-	}
-
-	// Validate PR input before sending to provider
-	if err := ValidatePRInput(&prInput); err != nil {
-		return nil, fmt.Errorf("PR input validation failed: %w", err)
+	// Skip features the provider lacks instead of failing
+	caps := ProviderCapabilities(ctx, b.provider, item.Repo)
+	if prInput.Draft && !caps.Draft {
+		b.logger.Warn("Provider does not support draft pull requests; opening it ready for review", "module", item.Module, "repo", item.Repo)
+		prInput.Draft = false
 	}
 
 	// Create or update the pull request
-	pr, err := b.provider.CreateOrUpdatePullRequest(ctx, prInput)
+	var pr *PullRequest
+	err = b.callProvider(ctx, "create or update PR", item.Repo, func(ctx context.Context) error {
+		var err error
+		pr, err = b.provider.CreateOrUpdatePullRequest(ctx, prInput)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("create or update PR: %w", err)
 	}
 
+	if prInput.DeleteBranchOnMerge {
+		b.enableBranchCleanup(ctx, item)
+	}
+
 	// Note: Labels are applied during PR creation, no need for separate AddLabels call
 
 	// Request reviewers if configured
-	if len(item.PR.Reviewers) > 0 || len(item.PR.TeamReviewers) > 0 {
-		// Sanitize reviewer lists
-		sanitizedReviewers := SanitizeLabels(item.PR.Reviewers)
-		sanitizedTeamReviewers := SanitizeLabels(item.PR.TeamReviewers)
-
-		if err := b.provider.RequestReviewers(ctx, item.Repo, pr.Number, sanitizedReviewers, sanitizedTeamReviewers); err != nil {
+	sanitizedReviewers := SanitizeLabels(item.PR.Reviewers)
+	sanitizedTeamReviewers := SanitizeLabels(item.PR.TeamReviewers)
+	if len(sanitizedTeamReviewers) > 0 && !caps.TeamReviewers {
+		b.logger.Warn("Provider does not support team reviewers; skipping them", "module", item.Module, "repo", item.Repo, "team_reviewers", sanitizedTeamReviewers)
+		sanitizedTeamReviewers = nil
+	}
+	if len(sanitizedReviewers) > 0 || len(sanitizedTeamReviewers) > 0 {
+		err := b.callProvider(ctx, "request reviewers", item.Repo, func(ctx context.Context) error {
+			return b.provider.RequestReviewers(ctx, item.Repo, pr.Number, sanitizedReviewers, sanitizedTeamReviewers)
+		})
+		if err != nil {
 			// Don't fail the whole operation for reviewer errors
 			b.logger.Warn("Failed to request reviewers", "module", item.Module, "repo", item.Repo, "reviewers", sanitizedReviewers, "team_reviewers", sanitizedTeamReviewers, "error", err)
 		}
@@ -189,7 +220,65 @@ func (b *broker) Comment(ctx context.Context, pr *PullRequest, body string) erro
 	return nil
 }
 
+func (b *broker) MergePR(ctx context.Context, pr *PullRequest, opts MergeOptions) (*MergeResult, error) {
+	if pr == nil {
+		return nil, fmt.Errorf("pull request cannot be nil")
+	}
+
+	// In dry-run mode, report the merge without performing it
+	if b.config.DryRun {
+		b.logger.Info("Dry run: would merge pull request", "repo", pr.Repo, "number", pr.Number, "method", opts.Method)
+		return &MergeResult{Message: "dry run: pull request not merged"}, nil
+	}
+
+	if b.provider == nil {
+		return nil, &NotImplementedError{Operation: "broker.MergePR"}
+	}
+
+	if !ProviderCapabilities(ctx, b.provider, pr.Repo).AutoMerge {
+		b.logger.Warn("Provider does not support merging pull requests; leaving it open", "repo", pr.Repo, "number", pr.Number)
+		return &MergeResult{Message: "provider does not support merging pull requests"}, nil
+	}
+
+	result, err := b.provider.MergePullRequest(ctx, pr.Repo, pr.Number, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge PR #%d in %s: %w", pr.Number, pr.Repo, err)
+	}
+	return result, nil
+}
+
+func (b *broker) ClosePR(ctx context.Context, pr *PullRequest) error {
+	if pr == nil {
+		return fmt.Errorf("pull request cannot be nil")
+	}
+
+	// In dry-run mode, skip closing
+	if b.config.DryRun {
+		b.logger.Info("Dry run: would close pull request", "repo", pr.Repo, "number", pr.Number)
+		return nil
+	}
+
+	if b.provider == nil {
+		return &NotImplementedError{Operation: "broker.ClosePR"}
+	}
+
+	err := b.callProvider(ctx, "close PR", pr.Repo, func(ctx context.Context) error {
+		return b.provider.ClosePullRequest(ctx, pr.Repo, pr.Number)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to close PR #%d in %s: %w", pr.Number, pr.Repo, err)
+	}
+	return nil
+}
+
 func (b *broker) Notify(ctx context.Context, item planner.WorkItem, result *executor.Result) (*NotificationResult, error) {
+	ctx, span := telemetry.Start(ctx, "broker.notify", telemetry.String("repo", item.Repo))
+	notified, err := b.notify(ctx, item, result)
+	span.End(err)
+	return notified, err
+}
+
+func (b *broker) notify(ctx context.Context, item planner.WorkItem, result *executor.Result) (*NotificationResult, error) {
 	// In dry-run mode, skip actual notifications
 	if b.config.DryRun {
 		return nil, nil
@@ -215,13 +304,85 @@ func (b *broker) Notify(ctx context.Context, item planner.WorkItem, result *exec
 	return notificationResult, nil
 }
 
+// NotifyPlan sends a plan summary through the notifier. Unlike Notify, delivery
+// failures are returned, since the summary is the whole point of the call.
+func (b *broker) NotifyPlan(ctx context.Context, summary PlanSummary) (*NotificationResult, error) {
+	// In dry-run mode, skip actual notifications
+	if b.config.DryRun {
+		b.logger.Info("Dry run: would send plan summary", "module", summary.Module, "version", summary.Version)
+		return nil, nil
+	}
+
+	if b.notifier == nil {
+		return nil, &NotImplementedError{Operation: "broker.NotifyPlan"}
+	}
+
+	planNotifier, ok := b.notifier.(PlanNotifier)
+	if !ok {
+		return nil, &NotImplementedError{Operation: "broker.NotifyPlan"}
+	}
+
+	notificationResult, err := planNotifier.SendPlan(ctx, summary)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send plan summary for %s@%s: %w", summary.Module, summary.Version, err)
+	}
+
+	if notificationResult != nil && notificationResult.Channel == "noop" {
+		b.logger.Info("Notifications disabled", "module", summary.Module, "message", notificationResult.Message)
+	}
+
+	return notificationResult, nil
+}
+
+// Alert sends an operational message through the notifier, when it can deliver
+// alerts. Like NotifyPlan, delivery failures are returned.
+func (b *broker) Alert(ctx context.Context, message string) (*NotificationResult, error) {
+	// In dry-run mode, skip actual notifications
+	if b.config.DryRun {
+		b.logger.Info("Dry run: would send alert", "message", message)
+		return nil, nil
+	}
+
+	alerter, ok := b.notifier.(Alerter)
+	if !ok {
+		return nil, &NotImplementedError{Operation: "broker.Alert"}
+	}
+
+	notificationResult, err := alerter.Alert(ctx, message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send alert: %w", err)
+	}
+	return notificationResult, nil
+}
+
+// ResolveIssue closes failure issue number for item after it succeeded. It returns
+// a nil result when no notifier tracks issues or in dry-run mode, so callers can
+// tell whether the issue was actually closed.
+func (b *broker) ResolveIssue(ctx context.Context, item planner.WorkItem, number int, result *executor.Result) (*NotificationResult, error) {
+	if b.config.DryRun {
+		b.logger.Info("Dry run: would close failure issue", "repo", item.Repo, "issue", number)
+		return nil, nil
+	}
+
+	resolver, ok := b.notifier.(IssueResolver)
+	if !ok {
+		return nil, nil
+	}
+
+	resolved, err := resolver.ResolveIssue(ctx, item, number, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to close issue #%d in %s: %w", number, item.Repo, err)
+	}
+	return resolved, nil
+}
+
 // mergeLabels combines item labels with default labels, removing duplicates.
-func (b *broker) mergeLabels(itemLabels []string) []string {
+func mergeLabels(defaultLabels, itemLabels []string) []string {
 	labelSet := make(map[string]struct{})
 	var result []string
 
 	// Add default labels first
-	for _, label := range b.config.DefaultLabels {
+	for _, label := range defaultLabels {
 		if _, exists := labelSet[label]; !exists {
 			labelSet[label] = struct{}{}
 			result = append(result, label)
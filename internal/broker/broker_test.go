@@ -11,6 +11,7 @@ import (
 	"github.com/goliatone/cascade/internal/executor"
 	"github.com/goliatone/cascade/internal/manifest"
 	"github.com/goliatone/cascade/internal/planner"
+	"github.com/goliatone/cascade/pkg/retry"
 	"github.com/goliatone/cascade/pkg/testsupport"
 )
 
@@ -86,6 +87,8 @@ type mockProvider struct {
 	requestReviewers func(ctx context.Context, repo string, number int, reviewers []string, teamReviewers []string) error
 	listPullRequests func(ctx context.Context, repo string, headBranch string) ([]*broker.PullRequest, error)
 	addComment       func(ctx context.Context, repo string, number int, body string) error
+	mergePR          func(ctx context.Context, repo string, number int, opts broker.MergeOptions) (*broker.MergeResult, error)
+	closePR          func(ctx context.Context, repo string, number int) error
 }
 
 func (m *mockProvider) CreateOrUpdatePullRequest(ctx context.Context, input broker.PRInput) (*broker.PullRequest, error) {
@@ -128,6 +131,20 @@ func (m *mockProvider) AddComment(ctx context.Context, repo string, number int,
 	return nil
 }
 
+func (m *mockProvider) MergePullRequest(ctx context.Context, repo string, number int, opts broker.MergeOptions) (*broker.MergeResult, error) {
+	if m.mergePR != nil {
+		return m.mergePR(ctx, repo, number, opts)
+	}
+	return &broker.MergeResult{SHA: "merged", Merged: true}, nil
+}
+
+func (m *mockProvider) ClosePullRequest(ctx context.Context, repo string, number int) error {
+	if m.closePR != nil {
+		return m.closePR(ctx, repo, number)
+	}
+	return nil
+}
+
 // mockNotifier implements the Notifier interface for testing
 type mockNotifier struct {
 	send func(ctx context.Context, item planner.WorkItem, result *executor.Result) (*broker.NotificationResult, error)
@@ -401,6 +418,181 @@ func TestBroker_Comment(t *testing.T) {
 	}
 }
 
+func TestBroker_MergePR(t *testing.T) {
+	pr := &broker.PullRequest{
+		URL:    "https://github.com/owner/repo/pull/123",
+		Number: 123,
+		Repo:   "owner/repo",
+	}
+
+	tests := []struct {
+		name         string
+		pr           *broker.PullRequest
+		config       broker.Config
+		mockProvider *mockProvider
+		wantMerged   bool
+		wantErr      bool
+	}{
+		{
+			name:   "successful merge",
+			pr:     pr,
+			config: broker.DefaultConfig(),
+			mockProvider: &mockProvider{
+				mergePR: func(ctx context.Context, repo string, number int, opts broker.MergeOptions) (*broker.MergeResult, error) {
+					if repo != "owner/repo" || number != 123 {
+						t.Errorf("unexpected merge target %s#%d", repo, number)
+					}
+					if opts.Method != broker.MergeMethodSquash {
+						t.Errorf("expected squash method, got %q", opts.Method)
+					}
+					return &broker.MergeResult{SHA: "abc123", Merged: true}, nil
+				},
+			},
+			wantMerged: true,
+		},
+		{
+			name:         "nil PR",
+			pr:           nil,
+			config:       broker.DefaultConfig(),
+			mockProvider: &mockProvider{},
+			wantErr:      true,
+		},
+		{
+			name:   "dry run mode",
+			pr:     pr,
+			config: func() broker.Config { c := broker.DefaultConfig(); c.DryRun = true; return c }(),
+			mockProvider: &mockProvider{
+				mergePR: func(ctx context.Context, repo string, number int, opts broker.MergeOptions) (*broker.MergeResult, error) {
+					t.Error("MergePullRequest should not be called in dry-run mode")
+					return nil, errors.New("should not be called")
+				},
+			},
+		},
+		{
+			name:   "provider error",
+			pr:     pr,
+			config: broker.DefaultConfig(),
+			mockProvider: &mockProvider{
+				mergePR: func(ctx context.Context, repo string, number int, opts broker.MergeOptions) (*broker.MergeResult, error) {
+					return nil, errors.New("GitHub API error")
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := broker.New(tt.mockProvider, &mockNotifier{}, tt.config, &mockLogger{})
+
+			result, err := b.MergePR(context.Background(), tt.pr, broker.MergeOptions{Method: broker.MergeMethodSquash})
+
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("MergePR() error = nil, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("MergePR() error = %v", err)
+			}
+			if result.Merged != tt.wantMerged {
+				t.Errorf("MergePR() merged = %v, want %v", result.Merged, tt.wantMerged)
+			}
+		})
+	}
+
+	t.Run("wraps not mergeable", func(t *testing.T) {
+		provider := &mockProvider{
+			mergePR: func(ctx context.Context, repo string, number int, opts broker.MergeOptions) (*broker.MergeResult, error) {
+				return nil, broker.ErrNotMergeable
+			},
+		}
+		b := broker.New(provider, &mockNotifier{}, broker.DefaultConfig(), &mockLogger{})
+
+		_, err := b.MergePR(context.Background(), pr, broker.MergeOptions{})
+		if !errors.Is(err, broker.ErrNotMergeable) {
+			t.Errorf("expected ErrNotMergeable, got %v", err)
+		}
+	})
+
+	t.Run("stub broker", func(t *testing.T) {
+		_, err := broker.NewStub().MergePR(context.Background(), pr, broker.MergeOptions{})
+		var notImpl *broker.NotImplementedError
+		if !errors.As(err, &notImpl) {
+			t.Errorf("expected NotImplementedError, got %v", err)
+		}
+	})
+}
+
+func TestBroker_ClosePR(t *testing.T) {
+	pr := &broker.PullRequest{
+		URL:    "https://github.com/owner/repo/pull/123",
+		Number: 123,
+		Repo:   "owner/repo",
+	}
+
+	tests := []struct {
+		name         string
+		pr           *broker.PullRequest
+		config       broker.Config
+		mockProvider *mockProvider
+		wantErr      bool
+	}{
+		{
+			name:         "successful close",
+			pr:           pr,
+			config:       broker.DefaultConfig(),
+			mockProvider: &mockProvider{},
+		},
+		{
+			name:         "nil PR",
+			pr:           nil,
+			config:       broker.DefaultConfig(),
+			mockProvider: &mockProvider{},
+			wantErr:      true,
+		},
+		{
+			name:   "dry run mode",
+			pr:     pr,
+			config: func() broker.Config { c := broker.DefaultConfig(); c.DryRun = true; return c }(),
+			mockProvider: &mockProvider{
+				closePR: func(ctx context.Context, repo string, number int) error {
+					t.Error("ClosePullRequest should not be called in dry-run mode")
+					return errors.New("should not be called")
+				},
+			},
+		},
+		{
+			name:   "provider error",
+			pr:     pr,
+			config: broker.DefaultConfig(),
+			mockProvider: &mockProvider{
+				closePR: func(ctx context.Context, repo string, number int) error {
+					return errors.New("GitHub API error")
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := broker.New(tt.mockProvider, &mockNotifier{}, tt.config, &mockLogger{})
+
+			err := b.ClosePR(context.Background(), tt.pr)
+
+			if tt.wantErr && err == nil {
+				t.Errorf("ClosePR() error = nil, wantErr %v", tt.wantErr)
+				return
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("ClosePR() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestBroker_Notify(t *testing.T) {
 	testWorkItem := planner.WorkItem{
 		Repo:   "owner/repo",
@@ -501,6 +693,203 @@ func TestBroker_Notify(t *testing.T) {
 	}
 }
 
+// mockPlanNotifier adds plan summary support to mockNotifier.
+type mockPlanNotifier struct {
+	mockNotifier
+	sendPlan func(ctx context.Context, summary broker.PlanSummary) (*broker.NotificationResult, error)
+}
+
+func (m *mockPlanNotifier) SendPlan(ctx context.Context, summary broker.PlanSummary) (*broker.NotificationResult, error) {
+	if m.sendPlan != nil {
+		return m.sendPlan(ctx, summary)
+	}
+	return &broker.NotificationResult{Channel: "test-channel", Message: "plan summary"}, nil
+}
+
+func TestBroker_NotifyPlan(t *testing.T) {
+	summary := broker.PlanSummary{
+		Module:  "github.com/example/lib",
+		Version: "v1.2.3",
+		Updates: []string{"owner/repo"},
+	}
+
+	tests := []struct {
+		name        string
+		notifier    broker.Notifier
+		config      broker.Config
+		wantChannel string
+		wantErr     bool
+		wantNotImpl bool
+	}{
+		{
+			name:        "sends through plan notifier",
+			notifier:    &mockPlanNotifier{},
+			config:      broker.DefaultConfig(),
+			wantChannel: "test-channel",
+		},
+		{
+			name: "dry run mode",
+			notifier: &mockPlanNotifier{
+				sendPlan: func(ctx context.Context, summary broker.PlanSummary) (*broker.NotificationResult, error) {
+					t.Error("SendPlan should not be called in dry-run mode")
+					return nil, errors.New("should not be called")
+				},
+			},
+			config: func() broker.Config { c := broker.DefaultConfig(); c.DryRun = true; return c }(),
+		},
+		{
+			name: "delivery failure is returned",
+			notifier: &mockPlanNotifier{
+				sendPlan: func(ctx context.Context, summary broker.PlanSummary) (*broker.NotificationResult, error) {
+					return nil, errors.New("slack unavailable")
+				},
+			},
+			config:  broker.DefaultConfig(),
+			wantErr: true,
+		},
+		{
+			name:        "notifier without plan support",
+			notifier:    &mockNotifier{},
+			config:      broker.DefaultConfig(),
+			wantErr:     true,
+			wantNotImpl: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := broker.New(&mockProvider{}, tt.notifier, tt.config, &mockLogger{})
+
+			result, err := b.NotifyPlan(context.Background(), summary)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("NotifyPlan() error = nil, want error")
+				}
+				var notImpl *broker.NotImplementedError
+				if errors.As(err, &notImpl) != tt.wantNotImpl {
+					t.Errorf("NotifyPlan() error = %v, want NotImplementedError %v", err, tt.wantNotImpl)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NotifyPlan() error = %v", err)
+			}
+			if tt.wantChannel == "" {
+				if result != nil {
+					t.Errorf("NotifyPlan() result = %+v, want nil", result)
+				}
+				return
+			}
+			if result == nil || result.Channel != tt.wantChannel {
+				t.Errorf("NotifyPlan() result = %+v, want channel %q", result, tt.wantChannel)
+			}
+		})
+	}
+
+	t.Run("stub broker", func(t *testing.T) {
+		_, err := broker.NewStub().NotifyPlan(context.Background(), summary)
+		var notImpl *broker.NotImplementedError
+		if !errors.As(err, &notImpl) {
+			t.Errorf("expected NotImplementedError, got %v", err)
+		}
+	})
+}
+
+type mockAlertNotifier struct {
+	mockNotifier
+	alerts []string
+}
+
+func (m *mockAlertNotifier) Alert(ctx context.Context, message string) (*broker.NotificationResult, error) {
+	m.alerts = append(m.alerts, message)
+	return &broker.NotificationResult{Channel: "alerts", Message: message}, nil
+}
+
+func TestBroker_Alert(t *testing.T) {
+	notifier := &mockAlertNotifier{}
+	b := broker.New(&mockProvider{}, notifier, broker.DefaultConfig(), &mockLogger{})
+	alerter, ok := b.(broker.Alerter)
+	if !ok {
+		t.Fatal("broker does not implement Alerter")
+	}
+	result, err := alerter.Alert(context.Background(), "item stalled")
+	if err != nil || result == nil || result.Channel != "alerts" || len(notifier.alerts) != 1 {
+		t.Fatalf("Alert() = %+v, %v with alerts %q", result, err, notifier.alerts)
+	}
+
+	dryRun := broker.DefaultConfig()
+	dryRun.DryRun = true
+	b = broker.New(&mockProvider{}, notifier, dryRun, &mockLogger{})
+	if result, err := b.(broker.Alerter).Alert(context.Background(), "item stalled"); err != nil || result != nil || len(notifier.alerts) != 1 {
+		t.Errorf("dry-run Alert() = %+v, %v, want nothing sent", result, err)
+	}
+
+	b = broker.New(&mockProvider{}, &mockNotifier{}, broker.DefaultConfig(), &mockLogger{})
+	_, err = b.(broker.Alerter).Alert(context.Background(), "item stalled")
+	var notImpl *broker.NotImplementedError
+	if !errors.As(err, &notImpl) {
+		t.Errorf("Alert() without an alerting notifier error = %v, want NotImplementedError", err)
+	}
+}
+
+type mockIssueNotifier struct {
+	mockNotifier
+	resolved []int
+	err      error
+}
+
+func (m *mockIssueNotifier) ResolveIssue(ctx context.Context, item planner.WorkItem, number int, result *executor.Result) (*broker.NotificationResult, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.resolved = append(m.resolved, number)
+	return &broker.NotificationResult{Channel: "github:" + item.Repo, IssueNumber: number}, nil
+}
+
+func TestBroker_ResolveIssue(t *testing.T) {
+	item := planner.WorkItem{Repo: "owner/repo"}
+	result := &executor.Result{Status: executor.StatusCompleted}
+
+	t.Run("closes through issue notifier", func(t *testing.T) {
+		notifier := &mockIssueNotifier{}
+		b := broker.New(&mockProvider{}, notifier, broker.DefaultConfig(), &mockLogger{})
+		resolved, err := b.ResolveIssue(context.Background(), item, 12, result)
+		if err != nil {
+			t.Fatalf("ResolveIssue() error = %v", err)
+		}
+		if resolved == nil || resolved.IssueNumber != 12 || len(notifier.resolved) != 1 || notifier.resolved[0] != 12 {
+			t.Fatalf("ResolveIssue() = %+v, resolved %v", resolved, notifier.resolved)
+		}
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		notifier := &mockIssueNotifier{}
+		config := broker.DefaultConfig()
+		config.DryRun = true
+		b := broker.New(&mockProvider{}, notifier, config, &mockLogger{})
+		resolved, err := b.ResolveIssue(context.Background(), item, 12, result)
+		if err != nil || resolved != nil || len(notifier.resolved) != 0 {
+			t.Fatalf("ResolveIssue() = %+v, %v; resolved %v", resolved, err, notifier.resolved)
+		}
+	})
+
+	t.Run("notifier without issue support", func(t *testing.T) {
+		b := broker.New(&mockProvider{}, &mockNotifier{}, broker.DefaultConfig(), &mockLogger{})
+		resolved, err := b.ResolveIssue(context.Background(), item, 12, result)
+		if err != nil || resolved != nil {
+			t.Fatalf("ResolveIssue() = %+v, %v, want nil", resolved, err)
+		}
+	})
+
+	t.Run("failure is returned", func(t *testing.T) {
+		notifier := &mockIssueNotifier{err: errors.New("forbidden")}
+		b := broker.New(&mockProvider{}, notifier, broker.DefaultConfig(), &mockLogger{})
+		if _, err := b.ResolveIssue(context.Background(), item, 12, result); err == nil {
+			t.Fatal("ResolveIssue() error = nil, want error")
+		}
+	})
+}
+
 func TestBroker_Notify_WithNoOpNotifier(t *testing.T) {
 	// Create a broker with NoOpNotifier
 	b := broker.New(&mockProvider{}, broker.NewNoOpNotifier(), broker.DefaultConfig(), &mockLogger{})
@@ -665,3 +1054,48 @@ func TestBroker_StructuredLogging(t *testing.T) {
 		})
 	}
 }
+
+func TestBroker_EnsurePR_RetriesTransientProviderErrors(t *testing.T) {
+	calls := 0
+	provider := &mockProvider{
+		createOrUpdatePR: func(ctx context.Context, input broker.PRInput) (*broker.PullRequest, error) {
+			calls++
+			if calls == 1 {
+				return nil, &broker.GitLabAPIError{Operation: "create merge request", Repo: input.Repo, StatusCode: 502, Err: errors.New("bad gateway")}
+			}
+			return &broker.PullRequest{Number: 7, Repo: input.Repo}, nil
+		},
+	}
+	cfg := broker.DefaultConfig()
+	cfg.ProviderRetry = retry.Policy{MaxAttempts: 3}
+	b := broker.New(provider, &mockNotifier{}, cfg, &mockLogger{})
+
+	item := planner.WorkItem{Repo: "owner/repo", Module: "github.com/owner/repo", Branch: "main", BranchName: "cascade/update", SourceVersion: "v1.0.0"}
+	pr, err := b.EnsurePR(context.Background(), item, &executor.Result{Status: executor.StatusCompleted, CommitHash: "abc123"})
+	if err != nil {
+		t.Fatalf("EnsurePR: %v", err)
+	}
+	if pr.Number != 7 || calls != 2 {
+		t.Errorf("got PR #%d after %d calls, want #7 after 2", pr.Number, calls)
+	}
+}
+
+func TestBroker_ClosePR_DoesNotRetryClientErrors(t *testing.T) {
+	calls := 0
+	provider := &mockProvider{
+		closePR: func(ctx context.Context, repo string, number int) error {
+			calls++
+			return &broker.BitbucketAPIError{Operation: "decline pull request", Repo: repo, StatusCode: 404, Err: errors.New("not found")}
+		},
+	}
+	cfg := broker.DefaultConfig()
+	cfg.ProviderRetry = retry.Policy{MaxAttempts: 3}
+	b := broker.New(provider, &mockNotifier{}, cfg, &mockLogger{})
+
+	if err := b.ClosePR(context.Background(), &broker.PullRequest{Number: 1, Repo: "owner/repo"}); err == nil {
+		t.Fatal("expected an error")
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
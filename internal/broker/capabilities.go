@@ -0,0 +1,78 @@
+package broker
+
+import "context"
+
+// Capabilities lists the optional pull request features a provider supports.
+// The broker skips requested features a provider lacks with a warning instead
+// of failing.
+type Capabilities struct {
+	// AutoMerge is support for merging pull requests through the API
+	AutoMerge bool
+
+	// TeamReviewers is support for requesting reviews from teams
+	TeamReviewers bool
+
+	// Draft is support for opening pull requests as drafts
+	Draft bool
+}
+
+// AllCapabilities reports support for every optional feature.
+func AllCapabilities() Capabilities {
+	return Capabilities{AutoMerge: true, TeamReviewers: true, Draft: true}
+}
+
+// CapabilityReporter is implemented by providers that report which optional
+// features they support. It is optional; callers use ProviderCapabilities.
+type CapabilityReporter interface {
+	Capabilities(ctx context.Context, repo string) Capabilities
+}
+
+// ProviderCapabilities returns the features provider supports for repo.
+// Providers that do not implement CapabilityReporter are assumed to support
+// everything, so requests reach them unchanged.
+func ProviderCapabilities(ctx context.Context, provider Provider, repo string) Capabilities {
+	if reporter, ok := provider.(CapabilityReporter); ok {
+		return reporter.Capabilities(ctx, repo)
+	}
+	return AllCapabilities()
+}
+
+var (
+	_ CapabilityReporter = (*GitHubProvider)(nil)
+	_ CapabilityReporter = (*GitLabProvider)(nil)
+	_ CapabilityReporter = (*BitbucketProvider)(nil)
+	_ CapabilityReporter = (*SandboxProvider)(nil)
+	_ CapabilityReporter = (*RoutingProvider)(nil)
+)
+
+// Capabilities reports that GitHub supports every optional feature.
+func (p *GitHubProvider) Capabilities(ctx context.Context, repo string) Capabilities {
+	return AllCapabilities()
+}
+
+// Capabilities reports that GitLab has no team reviewers.
+func (p *GitLabProvider) Capabilities(ctx context.Context, repo string) Capabilities {
+	return Capabilities{AutoMerge: true, Draft: true}
+}
+
+// Capabilities reports that the Bitbucket provider opens neither drafts nor
+// team review requests.
+func (p *BitbucketProvider) Capabilities(ctx context.Context, repo string) Capabilities {
+	return Capabilities{AutoMerge: true}
+}
+
+// Capabilities reports that the sandbox records every optional feature.
+func (p *SandboxProvider) Capabilities(ctx context.Context, repo string) Capabilities {
+	return AllCapabilities()
+}
+
+// Capabilities reports the capabilities of the provider repo is routed to.
+// Repositories that cannot be routed report every feature, leaving the error to
+// the request itself.
+func (r *RoutingProvider) Capabilities(ctx context.Context, repo string) Capabilities {
+	provider, err := r.providerFor(ctx, repo)
+	if err != nil {
+		return AllCapabilities()
+	}
+	return ProviderCapabilities(ctx, provider, repo)
+}
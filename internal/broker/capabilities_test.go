@@ -0,0 +1,90 @@
+package broker_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/goliatone/cascade/internal/broker"
+	"github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/internal/manifest"
+	"github.com/goliatone/cascade/internal/planner"
+)
+
+// limitedProvider is a mockProvider that reports a fixed set of capabilities.
+type limitedProvider struct {
+	mockProvider
+	caps broker.Capabilities
+}
+
+func (p *limitedProvider) Capabilities(ctx context.Context, repo string) broker.Capabilities {
+	return p.caps
+}
+
+func TestBroker_EnsurePRSkipsUnsupportedFeatures(t *testing.T) {
+	var gotDraft bool
+	var gotTeams []string
+	provider := &limitedProvider{caps: broker.Capabilities{AutoMerge: true}}
+	provider.createOrUpdatePR = func(ctx context.Context, input broker.PRInput) (*broker.PullRequest, error) {
+		gotDraft = input.Draft
+		return &broker.PullRequest{Number: 7, Repo: input.Repo}, nil
+	}
+	provider.requestReviewers = func(ctx context.Context, repo string, number int, reviewers, teamReviewers []string) error {
+		gotTeams = teamReviewers
+		if len(reviewers) != 1 {
+			t.Errorf("expected the user reviewer to be requested, got %v", reviewers)
+		}
+		return nil
+	}
+	logger := &mockLogger{}
+	b := broker.New(provider, &mockNotifier{}, broker.DefaultConfig(), logger)
+
+	item := planner.WorkItem{
+		Repo:          "example/api",
+		Module:        "github.com/example/api",
+		Branch:        "main",
+		BranchName:    "cascade/lib-v1.2.0",
+		SourceModule:  "github.com/example/lib",
+		SourceVersion: "v1.2.0",
+		PR: manifest.PRConfig{
+			Reviewers:     []string{"alice"},
+			TeamReviewers: []string{"platform"},
+			Draft:         true,
+		},
+	}
+	if _, err := b.EnsurePR(context.Background(), item, &executor.Result{Status: executor.StatusCompleted}); err != nil {
+		t.Fatalf("EnsurePR() error = %v", err)
+	}
+	if gotDraft || gotTeams != nil {
+		t.Errorf("expected draft and team reviewers to be skipped, got draft=%v teams=%v", gotDraft, gotTeams)
+	}
+	if len(logger.warnCalls) != 2 {
+		t.Errorf("expected a warning per skipped feature, got %+v", logger.warnCalls)
+	}
+}
+
+func TestBroker_MergePRWithoutAutoMerge(t *testing.T) {
+	provider := &limitedProvider{}
+	provider.mergePR = func(ctx context.Context, repo string, number int, opts broker.MergeOptions) (*broker.MergeResult, error) {
+		t.Error("MergePullRequest should not be called when the provider cannot merge")
+		return nil, nil
+	}
+	b := broker.New(provider, &mockNotifier{}, broker.DefaultConfig(), &mockLogger{})
+
+	result, err := b.MergePR(context.Background(), &broker.PullRequest{Repo: "example/api", Number: 7}, broker.MergeOptions{})
+	if err != nil || result == nil || result.Merged {
+		t.Fatalf("MergePR() = %+v, %v; want an unmerged result", result, err)
+	}
+}
+
+func TestProviderCapabilities(t *testing.T) {
+	gitlab := broker.NewGitLabProvider("token", "", nil)
+	routing := broker.NewRoutingProvider(&mockProvider{}, "github.com", map[string]broker.Provider{"gitlab.com": gitlab})
+	ctx := context.Background()
+
+	if caps := broker.ProviderCapabilities(ctx, routing, "example/api"); caps != broker.AllCapabilities() {
+		t.Errorf("providers without a report should support everything, got %+v", caps)
+	}
+	if caps := broker.ProviderCapabilities(ctx, routing, "https://gitlab.com/group/api"); caps.TeamReviewers || !caps.Draft || !caps.AutoMerge {
+		t.Errorf("unexpected GitLab capabilities: %+v", caps)
+	}
+}
@@ -0,0 +1,93 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+)
+
+// Check outcomes reported by ChecksStatus.
+const (
+	ChecksPending = "pending"
+	ChecksPassed  = "passed"
+	ChecksFailed  = "failed"
+
+	// ChecksNone means the head commit has no checks, or none reported yet.
+	ChecksNone = "none"
+)
+
+// ChecksStatus summarizes the CI checks on the head commit of a pull request.
+type ChecksStatus struct {
+	// State is ChecksFailed when any check failed, ChecksPending when any is
+	// still running, ChecksPassed when all passed, and ChecksNone without checks.
+	State string
+	Total int
+
+	// Failed and Pending name the checks in those states.
+	Failed  []string
+	Pending []string
+}
+
+// ChecksGetter is implemented by providers that can report the checks of a
+// pull request. It is optional; callers type-assert.
+type ChecksGetter interface {
+	GetPullRequestChecks(ctx context.Context, repo string, number int) (*ChecksStatus, error)
+}
+
+// ChecksReporter is implemented by brokers that can report the checks of the
+// pull requests they opened. It is optional; callers type-assert.
+type ChecksReporter interface {
+	// PullRequestChecks reports whether the checks of pr passed.
+	PullRequestChecks(ctx context.Context, pr *PullRequest) (*ChecksStatus, error)
+}
+
+var (
+	_ ChecksReporter = (*broker)(nil)
+	_ ChecksGetter   = (*GitHubProvider)(nil)
+	_ ChecksGetter   = (*GitLabProvider)(nil)
+	_ ChecksGetter   = (*BitbucketProvider)(nil)
+	_ ChecksGetter   = (*SandboxProvider)(nil)
+	_ ChecksGetter   = (*RoutingProvider)(nil)
+)
+
+func (b *broker) PullRequestChecks(ctx context.Context, pr *PullRequest) (*ChecksStatus, error) {
+	if pr == nil {
+		return nil, fmt.Errorf("pull request cannot be nil")
+	}
+
+	getter, ok := b.provider.(ChecksGetter)
+	if !ok {
+		return nil, &NotImplementedError{Operation: "broker.PullRequestChecks"}
+	}
+
+	checks, err := getter.GetPullRequestChecks(ctx, pr.Repo, pr.Number)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up the checks of PR #%d in %s: %w", pr.Number, pr.Repo, err)
+	}
+	return checks, nil
+}
+
+// add records one check in state ChecksPassed, ChecksPending, or ChecksFailed
+// and updates the overall state.
+func (s *ChecksStatus) add(name, state string) {
+	s.Total++
+	switch state {
+	case ChecksFailed:
+		s.Failed = append(s.Failed, name)
+	case ChecksPending:
+		s.Pending = append(s.Pending, name)
+	}
+
+	switch {
+	case len(s.Failed) > 0:
+		s.State = ChecksFailed
+	case len(s.Pending) > 0:
+		s.State = ChecksPending
+	default:
+		s.State = ChecksPassed
+	}
+}
+
+// newChecksStatus returns a status without checks.
+func newChecksStatus() *ChecksStatus {
+	return &ChecksStatus{State: ChecksNone}
+}
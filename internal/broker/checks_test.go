@@ -0,0 +1,53 @@
+package broker_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/goliatone/cascade/internal/broker"
+)
+
+// checksProvider adds check lookups to mockProvider.
+type checksProvider struct {
+	*mockProvider
+	checks *broker.ChecksStatus
+}
+
+func (p *checksProvider) GetPullRequestChecks(ctx context.Context, repo string, number int) (*broker.ChecksStatus, error) {
+	if p.checks == nil {
+		return nil, errors.New("not found")
+	}
+	return p.checks, nil
+}
+
+func TestBroker_PullRequestChecks(t *testing.T) {
+	pr := &broker.PullRequest{Repo: "owner/repo", Number: 3}
+
+	provider := &checksProvider{
+		mockProvider: &mockProvider{},
+		checks:       &broker.ChecksStatus{State: broker.ChecksFailed, Total: 2, Failed: []string{"test"}},
+	}
+	b := broker.New(provider, &mockNotifier{}, broker.DefaultConfig(), &mockLogger{}).(broker.ChecksReporter)
+
+	checks, err := b.PullRequestChecks(context.Background(), pr)
+	if err != nil {
+		t.Fatalf("PullRequestChecks() error = %v", err)
+	}
+	if checks.State != broker.ChecksFailed || len(checks.Failed) != 1 {
+		t.Errorf("PullRequestChecks() = %+v, want the failed test check", checks)
+	}
+
+	provider.checks = nil
+	if _, err := b.PullRequestChecks(context.Background(), pr); err == nil || !strings.Contains(err.Error(), "PR #3") {
+		t.Errorf("PullRequestChecks() error = %v, want lookup failure", err)
+	}
+
+	// Providers without check lookups report the operation as not implemented
+	plain := broker.New(&mockProvider{}, &mockNotifier{}, broker.DefaultConfig(), &mockLogger{}).(broker.ChecksReporter)
+	var notImplemented *broker.NotImplementedError
+	if _, err := plain.PullRequestChecks(context.Background(), pr); !errors.As(err, &notImplemented) {
+		t.Errorf("PullRequestChecks() error = %v, want NotImplementedError", err)
+	}
+}
@@ -0,0 +1,119 @@
+package broker
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// CorrelationHeader carries the correlation ID on every outbound provider and
+// notifier request.
+const CorrelationHeader = "X-Cascade-Correlation-ID"
+
+type correlationKey struct{}
+
+// Correlation links outbound requests to the work item that caused them.
+type Correlation struct {
+	ID   string
+	Repo string
+}
+
+// WithCorrelation returns a context whose outbound requests carry correlation.
+func WithCorrelation(ctx context.Context, correlation Correlation) context.Context {
+	return context.WithValue(ctx, correlationKey{}, correlation)
+}
+
+// CorrelationFromContext returns the correlation recorded with WithCorrelation, if any.
+func CorrelationFromContext(ctx context.Context) (Correlation, bool) {
+	if ctx == nil {
+		return Correlation{}, false
+	}
+	correlation, ok := ctx.Value(correlationKey{}).(Correlation)
+	return correlation, ok
+}
+
+// NewCorrelationID returns a random 16 character hex ID.
+func NewCorrelationID() string {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return time.Now().UTC().Format("20060102150405.000000")
+	}
+	return hex.EncodeToString(b[:])
+}
+
+var (
+	runCorrelationOnce sync.Once
+	runCorrelationID   string
+)
+
+// RunCorrelationID returns the ID requests made outside any work item carry, such
+// as plan summaries and metadata lookups. It is fixed for the process.
+func RunCorrelationID() string {
+	runCorrelationOnce.Do(func() { runCorrelationID = NewCorrelationID() })
+	return runCorrelationID
+}
+
+// CorrelationTransport is an http.RoundTripper that stamps requests with the
+// correlation ID of their context, or RunCorrelationID, and logs request and
+// response metadata at debug level. Bodies and headers other than the GitHub
+// request ID are never logged.
+type CorrelationTransport struct {
+	Base   http.RoundTripper
+	Logger Logger
+
+	// HostOnly logs the host instead of the full path, for endpoints whose
+	// path holds secrets such as webhook URLs.
+	HostOnly bool
+}
+
+// NewCorrelationTransport wraps base so every request carries a correlation ID.
+func NewCorrelationTransport(base http.RoundTripper, logger Logger, hostOnly bool) *CorrelationTransport {
+	return &CorrelationTransport{Base: base, Logger: logger, HostOnly: hostOnly}
+}
+
+// RoundTrip stamps the correlation header, executes the request, and logs it.
+func (t *CorrelationTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	correlation, ok := CorrelationFromContext(req.Context())
+	if !ok || correlation.ID == "" {
+		correlation.ID = RunCorrelationID()
+	}
+	clone := req.Clone(req.Context())
+	clone.Header.Set(CorrelationHeader, correlation.ID)
+
+	started := time.Now()
+	resp, err := base.RoundTrip(clone)
+	if t.Logger == nil {
+		return resp, err
+	}
+
+	args := []any{
+		"correlation_id", correlation.ID,
+		"method", req.Method,
+		"host", req.URL.Host,
+	}
+	if !t.HostOnly {
+		args = append(args, "path", req.URL.Path)
+	}
+	if correlation.Repo != "" {
+		args = append(args, "repo", correlation.Repo)
+	}
+	args = append(args, "duration_ms", time.Since(started).Milliseconds())
+	if err != nil {
+		t.Logger.Debug("provider request failed", append(args, "error", err)...)
+		return resp, err
+	}
+	args = append(args, "status", resp.StatusCode)
+	if requestID := resp.Header.Get("X-GitHub-Request-Id"); requestID != "" {
+		args = append(args, "github_request_id", requestID)
+	}
+	t.Logger.Debug("provider request", args...)
+	return resp, err
+}
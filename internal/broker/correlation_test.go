@@ -0,0 +1,129 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type debugRecordingLogger struct {
+	msgs []string
+	args [][]any
+}
+
+func (l *debugRecordingLogger) Debug(msg string, args ...any) {
+	l.msgs = append(l.msgs, msg)
+	l.args = append(l.args, args)
+}
+func (l *debugRecordingLogger) Info(string, ...any)  {}
+func (l *debugRecordingLogger) Warn(string, ...any)  {}
+func (l *debugRecordingLogger) Error(string, ...any) {}
+
+func (l *debugRecordingLogger) field(i int, key string) (any, bool) {
+	args := l.args[i]
+	for j := 0; j+1 < len(args); j += 2 {
+		if args[j] == key {
+			return args[j+1], true
+		}
+	}
+	return nil, false
+}
+
+func TestCorrelationTransportStampsContextID(t *testing.T) {
+	var gotHeader string
+	logger := &debugRecordingLogger{}
+	transport := NewCorrelationTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		gotHeader = req.Header.Get(CorrelationHeader)
+		header := http.Header{}
+		header.Set("X-GitHub-Request-Id", "ABCD:1234")
+		return &http.Response{StatusCode: http.StatusCreated, Header: header, Body: io.NopCloser(strings.NewReader(""))}, nil
+	}), logger, false)
+
+	ctx := WithCorrelation(context.Background(), Correlation{ID: "item-1", Repo: "example/repo"})
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, "https://api.github.com/repos/example/repo/pulls?token=secret", nil)
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("round trip: %v", err)
+	}
+	resp.Body.Close()
+
+	if gotHeader != "item-1" {
+		t.Errorf("expected correlation header item-1, got %q", gotHeader)
+	}
+	if req.Header.Get(CorrelationHeader) != "" {
+		t.Error("expected the caller's request to be left unmodified")
+	}
+	if len(logger.msgs) != 1 || logger.msgs[0] != "provider request" {
+		t.Fatalf("expected one debug line, got %v", logger.msgs)
+	}
+	want := map[string]any{
+		"correlation_id":    "item-1",
+		"repo":              "example/repo",
+		"method":            http.MethodPost,
+		"host":              "api.github.com",
+		"path":              "/repos/example/repo/pulls",
+		"status":            http.StatusCreated,
+		"github_request_id": "ABCD:1234",
+	}
+	for key, value := range want {
+		if got, ok := logger.field(0, key); !ok || got != value {
+			t.Errorf("expected %s=%v, got %v", key, value, got)
+		}
+	}
+	for _, arg := range logger.args[0] {
+		if s, ok := arg.(string); ok && strings.Contains(s, "secret") {
+			t.Errorf("query string leaked into log: %q", s)
+		}
+	}
+}
+
+func TestCorrelationTransportFallsBackToRunID(t *testing.T) {
+	var gotHeader string
+	transport := NewCorrelationTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		gotHeader = req.Header.Get(CorrelationHeader)
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(""))}, nil
+	}), nil, false)
+
+	req, _ := http.NewRequest(http.MethodGet, "https://api.github.com/rate_limit", nil)
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("round trip: %v", err)
+	}
+	resp.Body.Close()
+
+	if gotHeader == "" || gotHeader != RunCorrelationID() {
+		t.Errorf("expected run correlation ID %q, got %q", RunCorrelationID(), gotHeader)
+	}
+}
+
+func TestCorrelationTransportHostOnlyOmitsPath(t *testing.T) {
+	logger := &debugRecordingLogger{}
+	transport := NewCorrelationTransport(roundTripFunc(func(*http.Request) (*http.Response, error) {
+		return nil, errors.New("connection refused")
+	}), logger, true)
+
+	req, _ := http.NewRequest(http.MethodPost, "https://hooks.slack.com/services/T000/B000/secret", nil)
+	if _, err := transport.RoundTrip(req); err == nil {
+		t.Fatal("expected transport error to be returned")
+	}
+
+	if len(logger.msgs) != 1 || logger.msgs[0] != "provider request failed" {
+		t.Fatalf("expected one failure debug line, got %v", logger.msgs)
+	}
+	if _, ok := logger.field(0, "path"); ok {
+		t.Error("expected path to be omitted in host-only mode")
+	}
+	if host, _ := logger.field(0, "host"); host != "hooks.slack.com" {
+		t.Errorf("expected host to be logged, got %v", host)
+	}
+}
+
+func TestNewCorrelationIDIsUnique(t *testing.T) {
+	a, b := NewCorrelationID(), NewCorrelationID()
+	if len(a) != 16 || a == b {
+		t.Errorf("expected distinct 16 character IDs, got %q and %q", a, b)
+	}
+}
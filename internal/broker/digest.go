@@ -0,0 +1,357 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/internal/planner"
+)
+
+// DigestEntry is a notification held back for a digest.
+type DigestEntry struct {
+	Time    time.Time `json:"time"`
+	Module  string    `json:"module"`
+	Version string    `json:"version,omitempty"`
+	Repo    string    `json:"repo"`
+	Status  string    `json:"status"`
+
+	// Text is the notification as the channel would have sent it.
+	Text string `json:"text"`
+}
+
+// Digest batches the notifications of one channel over a window.
+type Digest struct {
+	// Start is when the first entry was held back, End when the digest is sent.
+	Start   time.Time
+	End     time.Time
+	Entries []DigestEntry
+}
+
+// DigestSender is implemented by notifiers whose notifications can be batched
+// into digests.
+type DigestSender interface {
+	Notifier
+
+	// DigestEntry renders the notification for item without sending it.
+	DigestEntry(ctx context.Context, item planner.WorkItem, result *executor.Result) (DigestEntry, error)
+
+	// SendDigest delivers the entries of digest as one message.
+	SendDigest(ctx context.Context, digest Digest) (*NotificationResult, error)
+}
+
+// DigestFlusher is implemented by notifiers and brokers holding notifications
+// back for digests. It is optional; callers type-assert.
+type DigestFlusher interface {
+	// FlushDigests sends the digests whose window elapsed, or every pending
+	// digest with force, and returns the number of notifications sent.
+	FlushDigests(ctx context.Context, force bool) (int, error)
+}
+
+var (
+	_ DigestSender  = (*SlackNotifier)(nil)
+	_ DigestSender  = (*WebhookNotifier)(nil)
+	_ DigestSender  = (*SandboxNotifier)(nil)
+	_ DigestFlusher = (*DigestNotifier)(nil)
+	_ DigestFlusher = (*MultiNotifier)(nil)
+	_ DigestFlusher = (*EscalatingNotifier)(nil)
+	_ DigestFlusher = (*broker)(nil)
+)
+
+// DigestNotifier holds the notifications of a channel back and sends them as
+// one digest once window elapsed since the first of them. Failures are sent
+// right away. Pending notifications are kept in a file, so the window spans
+// runs; a run that notifies after the window elapsed sends the digest, as does
+// FlushDigests.
+type DigestNotifier struct {
+	sender  DigestSender
+	channel string
+	path    string
+	window  time.Duration
+	now     func() time.Time
+
+	mu sync.Mutex
+}
+
+// NewDigestNotifier batches the notifications of sender, the notifier of
+// channel, over window, keeping pending ones in the file at path.
+func NewDigestNotifier(sender DigestSender, channel, path string, window time.Duration) *DigestNotifier {
+	return &DigestNotifier{
+		sender:  sender,
+		channel: channel,
+		path:    path,
+		window:  window,
+		now:     time.Now,
+	}
+}
+
+// digestFile is the file pending digest entries are kept in.
+type digestFile struct {
+	Channel string        `json:"channel"`
+	Entries []DigestEntry `json:"entries"`
+}
+
+// Send sends failures right away and holds other notifications back for the
+// digest, sending it when its window elapsed.
+func (d *DigestNotifier) Send(ctx context.Context, item planner.WorkItem, result *executor.Result) (*NotificationResult, error) {
+	if resultStatus(result) == executor.StatusFailed {
+		return d.sender.Send(ctx, item, result)
+	}
+
+	entry, err := d.sender.DigestEntry(ctx, item, result)
+	if err != nil {
+		return nil, err
+	}
+	entry.Time = d.now().UTC()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	entries, err := d.load()
+	if err != nil {
+		return nil, &NotificationError{Channel: d.channel, Err: err}
+	}
+	entries = append(entries, entry)
+	if err := d.save(entries); err != nil {
+		return nil, &NotificationError{Channel: d.channel, Err: err}
+	}
+
+	if !d.due(entries) {
+		return &NotificationResult{
+			Channel: d.channel,
+			Message: fmt.Sprintf("held for the digest (%d pending)", len(entries)),
+		}, nil
+	}
+	return d.flush(ctx, entries)
+}
+
+// FlushDigests sends the pending notifications as a digest when its window
+// elapsed, or with force.
+func (d *DigestNotifier) FlushDigests(ctx context.Context, force bool) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	entries, err := d.load()
+	if err != nil {
+		return 0, &NotificationError{Channel: d.channel, Err: err}
+	}
+	if len(entries) == 0 || (!force && !d.due(entries)) {
+		return 0, nil
+	}
+	if _, err := d.flush(ctx, entries); err != nil {
+		return 0, err
+	}
+	return len(entries), nil
+}
+
+// SendPlan forwards plan summaries, which are not batched.
+func (d *DigestNotifier) SendPlan(ctx context.Context, summary PlanSummary) (*NotificationResult, error) {
+	planNotifier, ok := d.sender.(PlanNotifier)
+	if !ok {
+		return nil, nil
+	}
+	return planNotifier.SendPlan(ctx, summary)
+}
+
+// Alert forwards operational alerts, which are not batched.
+func (d *DigestNotifier) Alert(ctx context.Context, message string) (*NotificationResult, error) {
+	alerter, ok := d.sender.(Alerter)
+	if !ok {
+		return nil, nil
+	}
+	return alerter.Alert(ctx, message)
+}
+
+func (d *DigestNotifier) due(entries []DigestEntry) bool {
+	return len(entries) > 0 && d.now().Sub(entries[0].Time) >= d.window
+}
+
+// flush sends entries and clears them. Entries stay pending when sending
+// fails, so a later notification or flush retries.
+func (d *DigestNotifier) flush(ctx context.Context, entries []DigestEntry) (*NotificationResult, error) {
+	result, err := d.sender.SendDigest(ctx, Digest{
+		Start:   entries[0].Time,
+		End:     d.now().UTC(),
+		Entries: entries,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := d.save(nil); err != nil {
+		return nil, &NotificationError{Channel: d.channel, Err: fmt.Errorf("digest sent but not cleared: %w", err)}
+	}
+	return result, nil
+}
+
+func (d *DigestNotifier) load() ([]DigestEntry, error) {
+	data, err := os.ReadFile(d.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read digest: %w", err)
+	}
+	var file digestFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("decode digest %s: %w", d.path, err)
+	}
+	return file.Entries, nil
+}
+
+// save replaces the pending entries through a temporary file.
+func (d *DigestNotifier) save(entries []DigestEntry) error {
+	if len(entries) == 0 {
+		if err := os.Remove(d.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("clear digest: %w", err)
+		}
+		return nil
+	}
+	data, err := json.MarshalIndent(digestFile{Channel: d.channel, Entries: entries}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode digest: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(d.path), 0o755); err != nil {
+		return fmt.Errorf("create digest directory: %w", err)
+	}
+	tmp := d.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("write digest: %w", err)
+	}
+	if err := os.Rename(tmp, d.path); err != nil {
+		return fmt.Errorf("write digest: %w", err)
+	}
+	return nil
+}
+
+// RenderDigest renders the message sent for digest: a count of the
+// notifications followed by each of them.
+func RenderDigest(digest Digest) string {
+	var b strings.Builder
+	noun := "notifications"
+	if len(digest.Entries) == 1 {
+		noun = "notification"
+	}
+	fmt.Fprintf(&b, "📬 *Cascade digest:* %d %s from %s to %s",
+		len(digest.Entries), noun,
+		digest.Start.Format("Jan 2 15:04"), digest.End.Format("Jan 2 15:04 MST"))
+	for _, entry := range digest.Entries {
+		b.WriteString("\n\n")
+		b.WriteString(strings.TrimSpace(entry.Text))
+	}
+	return b.String()
+}
+
+// newDigestEntry builds the digest entry of a notification about item.
+func newDigestEntry(item planner.WorkItem, result *executor.Result, text string) DigestEntry {
+	return DigestEntry{
+		Module:  item.Module,
+		Version: item.SourceVersion,
+		Repo:    item.Repo,
+		Status:  string(resultStatus(result)),
+		Text:    text,
+	}
+}
+
+// DigestEntry renders the Slack notification for item.
+func (s *SlackNotifier) DigestEntry(ctx context.Context, item planner.WorkItem, result *executor.Result) (DigestEntry, error) {
+	message, err := renderNotification(s.config.TemplateFor(NotificationChannelSlack, resultStatus(result)), s.config.templateData(ctx, item, result))
+	if err != nil {
+		return DigestEntry{}, &NotificationError{Channel: s.channel, Err: fmt.Errorf("render notification template: %w", err)}
+	}
+	return newDigestEntry(item, result, message), nil
+}
+
+// SendDigest posts a digest to the Slack channel.
+func (s *SlackNotifier) SendDigest(ctx context.Context, digest Digest) (*NotificationResult, error) {
+	payload := map[string]any{
+		"channel": s.channel,
+		"text":    RenderDigest(digest),
+		"as_user": true,
+		"mrkdwn":  true,
+	}
+
+	return s.sendWithRetry(ctx, payload)
+}
+
+// DigestEntry renders the webhook notification for item.
+func (w *WebhookNotifier) DigestEntry(ctx context.Context, item planner.WorkItem, result *executor.Result) (DigestEntry, error) {
+	message, err := renderNotification(w.config.TemplateFor(NotificationChannelWebhook, resultStatus(result)), w.config.templateData(ctx, item, result))
+	if err != nil {
+		return DigestEntry{}, &NotificationError{Channel: w.url, Err: fmt.Errorf("render notification template: %w", err)}
+	}
+	return newDigestEntry(item, result, message), nil
+}
+
+// SendDigest posts a digest to the webhook endpoint. The payload carries an
+// event field and the entries instead of the work item fields sent by Send.
+func (w *WebhookNotifier) SendDigest(ctx context.Context, digest Digest) (*NotificationResult, error) {
+	payload := map[string]any{
+		"text":    RenderDigest(digest),
+		"event":   "digest",
+		"start":   digest.Start,
+		"end":     digest.End,
+		"count":   len(digest.Entries),
+		"entries": digest.Entries,
+	}
+
+	return w.sendWithRetry(ctx, payload)
+}
+
+// DigestEntry renders the sandbox notification for item.
+func (n *SandboxNotifier) DigestEntry(ctx context.Context, item planner.WorkItem, result *executor.Result) (DigestEntry, error) {
+	message, err := renderNotification(n.config.TemplateFor(NotificationChannelSandbox, resultStatus(result)), n.config.templateData(ctx, item, result))
+	if err != nil {
+		return DigestEntry{}, &NotificationError{Channel: NotificationChannelSandbox, Err: fmt.Errorf("render notification template: %w", err)}
+	}
+	return newDigestEntry(item, result, message), nil
+}
+
+// SendDigest records a digest.
+func (n *SandboxNotifier) SendDigest(ctx context.Context, digest Digest) (*NotificationResult, error) {
+	return n.record(sandboxNotification{Event: "digest", Text: RenderDigest(digest)})
+}
+
+// FlushDigests flushes the digests of every notifier that holds notifications
+// back. It returns the notifications sent and the errors of the notifiers that
+// failed.
+func (m *MultiNotifier) FlushDigests(ctx context.Context, force bool) (int, error) {
+	sent := 0
+	var errs []error
+	for _, notifier := range m.notifiers {
+		flusher, ok := notifier.(DigestFlusher)
+		if !ok {
+			continue
+		}
+		n, err := flusher.FlushDigests(ctx, force)
+		sent += n
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return sent, errors.Join(errs...)
+}
+
+// FlushDigests flushes the digests of every named notifier.
+func (e *EscalatingNotifier) FlushDigests(ctx context.Context, force bool) (int, error) {
+	return e.all.FlushDigests(ctx, force)
+}
+
+// FlushDigests sends the digests the notifier holds back whose window elapsed,
+// or every pending digest with force. It does nothing in dry-run mode or when no
+// notifier batches notifications.
+func (b *broker) FlushDigests(ctx context.Context, force bool) (int, error) {
+	if b.config.DryRun {
+		b.logger.Info("Dry run: would flush notification digests", "force", force)
+		return 0, nil
+	}
+	flusher, ok := b.notifier.(DigestFlusher)
+	if !ok {
+		return 0, nil
+	}
+	return flusher.FlushDigests(ctx, force)
+}
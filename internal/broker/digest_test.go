@@ -0,0 +1,138 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/internal/planner"
+)
+
+func TestDigestNotifier(t *testing.T) {
+	client := &mockHTTPClient{responses: []mockResponse{
+		{statusCode: 200}, // failure, sent right away
+		{statusCode: 500}, // digest, first attempt
+		{statusCode: 200}, // digest, retried
+	}}
+	cfg := DefaultNotificationConfig()
+	cfg.RetryDelay = time.Millisecond
+	cfg.Templates.Channels = map[string]ChannelTemplates{
+		NotificationChannelWebhook: {Default: "{{.Repo}} {{.Status}}"},
+	}
+	path := filepath.Join(t.TempDir(), "notifications", "digest-webhook.json")
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	digest := NewDigestNotifier(NewWebhookNotifier("https://hooks.example.com/cascade", client, cfg), NotificationChannelWebhook, path, time.Hour)
+	digest.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	item := func(repo string) planner.WorkItem {
+		return planner.WorkItem{Module: "github.com/example/lib", SourceVersion: "v1.2.0", Repo: repo}
+	}
+
+	for _, repo := range []string{"example/api", "example/web"} {
+		res, err := digest.Send(ctx, item(repo), &executor.Result{Status: executor.StatusCompleted})
+		if err != nil || !strings.Contains(res.Message, "held for the digest") {
+			t.Fatalf("Send(%s) = %+v, %v, want it held back", repo, res, err)
+		}
+		now = now.Add(10 * time.Minute)
+	}
+	if len(client.requests) != 0 {
+		t.Fatalf("held notifications sent %d requests", len(client.requests))
+	}
+
+	if _, err := digest.Send(ctx, item("example/cli"), &executor.Result{Status: executor.StatusFailed}); err != nil {
+		t.Fatalf("Send(failed) error = %v", err)
+	}
+	if len(client.requests) != 1 {
+		t.Fatalf("failure sent %d requests, want 1", len(client.requests))
+	}
+
+	// Pending notifications survive a new notifier, as in a later run
+	if sent, err := digest.FlushDigests(ctx, false); err != nil || sent != 0 {
+		t.Fatalf("FlushDigests() before the window = %d, %v", sent, err)
+	}
+	next := NewDigestNotifier(NewWebhookNotifier("https://hooks.example.com/cascade", client, cfg), NotificationChannelWebhook, path, time.Hour)
+	now = now.Add(45 * time.Minute)
+	next.now = func() time.Time { return now }
+	res, err := next.Send(ctx, item("example/worker"), &executor.Result{Status: executor.StatusManualReview})
+	if err != nil || res.Channel != "https://hooks.example.com/cascade" {
+		t.Fatalf("Send() after the window = %+v, %v, want the digest sent", res, err)
+	}
+
+	if len(client.requests) != 3 {
+		t.Fatalf("requests = %d, want 3", len(client.requests))
+	}
+	body, _ := io.ReadAll(client.requests[2].Body)
+	var payload struct {
+		Text    string        `json:"text"`
+		Event   string        `json:"event"`
+		Count   int           `json:"count"`
+		Entries []DigestEntry `json:"entries"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload.Event != "digest" || payload.Count != 3 || len(payload.Entries) != 3 {
+		t.Fatalf("digest payload = %s", body)
+	}
+	if entry := payload.Entries[0]; entry.Repo != "example/api" || entry.Module != "github.com/example/lib" || entry.Version != "v1.2.0" || entry.Status != "completed" {
+		t.Errorf("digest entry = %+v", entry)
+	}
+	for _, want := range []string{"3 notifications from Mar 2 10:00 to Mar 2 11:05 UTC", "example/api completed", "example/worker manual-review"} {
+		if !strings.Contains(payload.Text, want) {
+			t.Errorf("digest text = %q, want %q", payload.Text, want)
+		}
+	}
+	if strings.Contains(payload.Text, "example/cli") {
+		t.Errorf("digest text = %q, includes the failure sent right away", payload.Text)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("digest file not cleared after sending: %v", err)
+	}
+}
+
+func TestDigestNotifierFlush(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "digest-sandbox.json")
+	log := filepath.Join(t.TempDir(), "notifications.jsonl")
+	cfg := DefaultNotificationConfig()
+	cfg.Templates.Channels = map[string]ChannelTemplates{
+		NotificationChannelSandbox: {Default: "{{.Repo}} {{.Status}}"},
+	}
+	digest := NewDigestNotifier(NewSandboxNotifier(log, cfg), NotificationChannelSandbox, path, time.Hour)
+	multi := NewMultiNotifier(digest, NewNoOpNotifier())
+
+	ctx := context.Background()
+	item := planner.WorkItem{Module: "github.com/example/lib", Repo: "example/api"}
+	if _, err := multi.Send(ctx, item, &executor.Result{Status: executor.StatusSkipped}); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	if sent, err := multi.FlushDigests(ctx, false); err != nil || sent != 0 {
+		t.Fatalf("FlushDigests() = %d, %v, want nothing due", sent, err)
+	}
+	if sent, err := multi.FlushDigests(ctx, true); err != nil || sent != 1 {
+		t.Fatalf("FlushDigests(force) = %d, %v, want 1", sent, err)
+	}
+	if sent, err := multi.FlushDigests(ctx, true); err != nil || sent != 0 {
+		t.Fatalf("FlushDigests(force) after sending = %d, %v, want 0", sent, err)
+	}
+
+	data, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entry sandboxNotification
+	if err := json.Unmarshal(data, &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry.Event != "digest" || !strings.Contains(entry.Text, "1 notification from") || !strings.HasSuffix(entry.Text, "example/api skipped") {
+		t.Errorf("sandbox digest = %+v", entry)
+	}
+}
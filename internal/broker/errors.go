@@ -6,6 +6,14 @@ import (
 	"net/http"
 )
 
+// ErrRepositoryArchived is returned when a pull request targets an archived repository.
+var ErrRepositoryArchived = errors.New("repository is archived")
+
+// ErrNotMergeable is returned when the provider refuses to merge a pull request,
+// for example because checks are pending, it conflicts with the base branch, or
+// its head moved away from the expected SHA.
+var ErrNotMergeable = errors.New("pull request is not mergeable")
+
 // NotImplementedError signals stubbed behaviour.
 type NotImplementedError struct {
 	Operation string
@@ -68,6 +76,46 @@ func (e *GitHubAPIError) IsRateLimited() bool {
 	return e.StatusCode == http.StatusForbidden && e.ResponseBody != ""
 }
 
+// GitLabAPIError wraps GitLab API operation failures.
+type GitLabAPIError struct {
+	Operation    string
+	Repo         string
+	StatusCode   int
+	ResponseBody string
+	Err          error
+}
+
+func (e *GitLabAPIError) Error() string {
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("broker: GitLab API operation %s failed for repo %s (status %d): %v", e.Operation, e.Repo, e.StatusCode, e.Err)
+	}
+	return fmt.Sprintf("broker: GitLab API operation %s failed for repo %s: %v", e.Operation, e.Repo, e.Err)
+}
+
+func (e *GitLabAPIError) Unwrap() error {
+	return e.Err
+}
+
+// BitbucketAPIError wraps Bitbucket Cloud API operation failures.
+type BitbucketAPIError struct {
+	Operation    string
+	Repo         string
+	StatusCode   int
+	ResponseBody string
+	Err          error
+}
+
+func (e *BitbucketAPIError) Error() string {
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("broker: Bitbucket API operation %s failed for repo %s (status %d): %v", e.Operation, e.Repo, e.StatusCode, e.Err)
+	}
+	return fmt.Sprintf("broker: Bitbucket API operation %s failed for repo %s: %v", e.Operation, e.Repo, e.Err)
+}
+
+func (e *BitbucketAPIError) Unwrap() error {
+	return e.Err
+}
+
 // TemplateRenderError wraps template rendering failures.
 type TemplateRenderError struct {
 	TemplateName string
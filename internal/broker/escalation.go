@@ -0,0 +1,163 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/internal/planner"
+)
+
+// Channel names the escalation ladder selects notifiers by, in addition to
+// NotificationChannelSlack and NotificationChannelWebhook.
+const (
+	NotificationChannelGitHubIssues = "github_issues"
+	NotificationChannelPagerDuty    = "pagerduty"
+)
+
+type attemptKey struct{}
+
+type escalationKey struct{}
+
+// WithAttempt returns a context that tells notifiers which attempt of a work item
+// the notification reports, counting from 1 across runs.
+func WithAttempt(ctx context.Context, attempt int) context.Context {
+	return context.WithValue(ctx, attemptKey{}, attempt)
+}
+
+// AttemptFromContext returns the attempt recorded with WithAttempt, or 0 when the
+// context carries none.
+func AttemptFromContext(ctx context.Context) int {
+	if ctx == nil {
+		return 0
+	}
+	attempt, _ := ctx.Value(attemptKey{}).(int)
+	return attempt
+}
+
+// Escalation describes the escalation step a notification is sent on. Notifiers
+// selected by an EscalatingNotifier find it on their context.
+type Escalation struct {
+	// Attempt is the attempt the step was selected for.
+	Attempt int
+
+	// Assignees are added to the failure issue, such as a team lead on the last step.
+	Assignees []string
+}
+
+func withEscalation(ctx context.Context, escalation Escalation) context.Context {
+	return context.WithValue(ctx, escalationKey{}, escalation)
+}
+
+// EscalationFromContext returns the escalation step the notification was selected
+// for, if any.
+func EscalationFromContext(ctx context.Context) (Escalation, bool) {
+	if ctx == nil {
+		return Escalation{}, false
+	}
+	escalation, ok := ctx.Value(escalationKey{}).(Escalation)
+	return escalation, ok
+}
+
+// EscalationStep selects the channels that report failures from the Attempt-th
+// attempt of a work item on, until a later step takes over.
+type EscalationStep struct {
+	Attempt   int
+	Channels  []string
+	Assignees []string
+}
+
+// EscalatingNotifier sends each notification to the channels of one escalation
+// step. Failures use the last step whose Attempt does not exceed the attempt on
+// the context, so repeated failures of a dependent across runs reach more
+// channels; other results always use the first step. Issue resolution, alerts,
+// and plan summaries go to every named notifier.
+type EscalatingNotifier struct {
+	notifiers map[string]Notifier
+	steps     []EscalationStep
+	all       *MultiNotifier
+}
+
+// NewEscalatingNotifier creates a notifier that selects among notifiers, keyed by
+// channel name, according to steps. Channels without a notifier are skipped.
+func NewEscalatingNotifier(notifiers map[string]Notifier, steps []EscalationStep) *EscalatingNotifier {
+	sorted := append([]EscalationStep(nil), steps...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Attempt < sorted[j].Attempt })
+
+	names := make([]string, 0, len(notifiers))
+	for name := range notifiers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	all := make([]Notifier, 0, len(names))
+	for _, name := range names {
+		all = append(all, notifiers[name])
+	}
+
+	return &EscalatingNotifier{
+		notifiers: notifiers,
+		steps:     sorted,
+		all:       NewMultiNotifier(all...),
+	}
+}
+
+// Step returns the escalation step for a failure on attempt. Attempts before the
+// first step use the first step.
+func (e *EscalatingNotifier) Step(attempt int) EscalationStep {
+	if len(e.steps) == 0 {
+		return EscalationStep{Attempt: attempt}
+	}
+	step := e.steps[0]
+	for _, candidate := range e.steps[1:] {
+		if candidate.Attempt > attempt {
+			break
+		}
+		step = candidate
+	}
+	return step
+}
+
+// Send notifies the channels of the escalation step for the item's attempt.
+func (e *EscalatingNotifier) Send(ctx context.Context, item planner.WorkItem, result *executor.Result) (*NotificationResult, error) {
+	attempt := max(AttemptFromContext(ctx), 1)
+
+	step := e.Step(1)
+	if resultStatus(result) == executor.StatusFailed {
+		step = e.Step(attempt)
+	}
+
+	var selected []Notifier
+	for _, channel := range step.Channels {
+		if notifier, ok := e.notifiers[channel]; ok {
+			selected = append(selected, notifier)
+		}
+	}
+	if len(selected) == 0 {
+		return &NotificationResult{
+			Channel: "escalation",
+			Message: fmt.Sprintf("no configured channels for attempt %d", attempt),
+		}, nil
+	}
+
+	ctx = withEscalation(ctx, Escalation{Attempt: attempt, Assignees: step.Assignees})
+	if len(selected) == 1 {
+		return selected[0].Send(ctx, item, result)
+	}
+	return NewMultiNotifier(selected...).Send(ctx, item, result)
+}
+
+// ResolveIssue forwards issue resolution to every notifier that tracks failure issues.
+func (e *EscalatingNotifier) ResolveIssue(ctx context.Context, item planner.WorkItem, number int, result *executor.Result) (*NotificationResult, error) {
+	return e.all.ResolveIssue(ctx, item, number, result)
+}
+
+// Alert forwards an operational message to every notifier that supports alerts.
+func (e *EscalatingNotifier) Alert(ctx context.Context, message string) (*NotificationResult, error) {
+	return e.all.Alert(ctx, message)
+}
+
+// SendPlan forwards a plan summary to every notifier that supports plan summaries.
+func (e *EscalatingNotifier) SendPlan(ctx context.Context, summary PlanSummary) (*NotificationResult, error) {
+	return e.all.SendPlan(ctx, summary)
+}
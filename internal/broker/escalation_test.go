@@ -0,0 +1,146 @@
+package broker
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/internal/planner"
+)
+
+// recordingNotifier records the notifications and escalations it receives.
+type recordingNotifier struct {
+	name        string
+	sent        int
+	escalations []Escalation
+	resolved    []int
+}
+
+func (r *recordingNotifier) Send(ctx context.Context, item planner.WorkItem, result *executor.Result) (*NotificationResult, error) {
+	r.sent++
+	if escalation, ok := EscalationFromContext(ctx); ok {
+		r.escalations = append(r.escalations, escalation)
+	}
+	return &NotificationResult{Channel: r.name}, nil
+}
+
+func (r *recordingNotifier) ResolveIssue(ctx context.Context, item planner.WorkItem, number int, result *executor.Result) (*NotificationResult, error) {
+	r.resolved = append(r.resolved, number)
+	return &NotificationResult{Channel: r.name}, nil
+}
+
+func escalationLadder() (map[string]*recordingNotifier, *EscalatingNotifier) {
+	recorders := map[string]*recordingNotifier{
+		NotificationChannelSlack:        {name: NotificationChannelSlack},
+		NotificationChannelGitHubIssues: {name: NotificationChannelGitHubIssues},
+		NotificationChannelPagerDuty:    {name: NotificationChannelPagerDuty},
+	}
+	notifiers := make(map[string]Notifier, len(recorders))
+	for name, recorder := range recorders {
+		notifiers[name] = recorder
+	}
+	// Steps are deliberately out of order; the notifier sorts them
+	return recorders, NewEscalatingNotifier(notifiers, []EscalationStep{
+		{Attempt: 3, Channels: []string{"slack", "github_issues", "pagerduty"}, Assignees: []string{"team-lead"}},
+		{Attempt: 1, Channels: []string{"slack"}},
+		{Attempt: 2, Channels: []string{"slack", "github_issues"}},
+	})
+}
+
+func TestEscalatingNotifier_Step(t *testing.T) {
+	_, notifier := escalationLadder()
+
+	tests := []struct {
+		attempt int
+		want    int
+	}{
+		{attempt: 0, want: 1},
+		{attempt: 1, want: 1},
+		{attempt: 2, want: 2},
+		{attempt: 3, want: 3},
+		{attempt: 7, want: 3},
+	}
+	for _, tt := range tests {
+		if got := notifier.Step(tt.attempt).Attempt; got != tt.want {
+			t.Errorf("Step(%d).Attempt = %d, want %d", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestEscalatingNotifier_SendEscalatesFailures(t *testing.T) {
+	item := planner.WorkItem{Repo: "owner/repo"}
+	failed := &executor.Result{Status: executor.StatusFailed}
+
+	tests := []struct {
+		name    string
+		attempt int
+		result  *executor.Result
+		want    map[string]int
+	}{
+		{name: "first failure", attempt: 1, result: failed, want: map[string]int{"slack": 1}},
+		{name: "second failure", attempt: 2, result: failed, want: map[string]int{"slack": 1, "github_issues": 1}},
+		{name: "third failure", attempt: 3, result: failed, want: map[string]int{"slack": 1, "github_issues": 1, "pagerduty": 1}},
+		{name: "no attempt on context", result: failed, want: map[string]int{"slack": 1}},
+		{name: "success after failures", attempt: 3, result: &executor.Result{Status: executor.StatusCompleted}, want: map[string]int{"slack": 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorders, notifier := escalationLadder()
+			ctx := context.Background()
+			if tt.attempt > 0 {
+				ctx = WithAttempt(ctx, tt.attempt)
+			}
+
+			if _, err := notifier.Send(ctx, item, tt.result); err != nil {
+				t.Fatalf("Send() error = %v", err)
+			}
+			for name, recorder := range recorders {
+				if recorder.sent != tt.want[name] {
+					t.Errorf("%s received %d notifications, want %d", name, recorder.sent, tt.want[name])
+				}
+			}
+		})
+	}
+}
+
+func TestEscalatingNotifier_PassesAssignees(t *testing.T) {
+	recorders, notifier := escalationLadder()
+	ctx := WithAttempt(context.Background(), 4)
+
+	if _, err := notifier.Send(ctx, planner.WorkItem{Repo: "owner/repo"}, &executor.Result{Status: executor.StatusFailed}); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	got := recorders[NotificationChannelGitHubIssues].escalations
+	want := []Escalation{{Attempt: 4, Assignees: []string{"team-lead"}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("escalations = %+v, want %+v", got, want)
+	}
+}
+
+func TestEscalatingNotifier_SkipsUnconfiguredChannels(t *testing.T) {
+	notifier := NewEscalatingNotifier(map[string]Notifier{}, []EscalationStep{{Attempt: 1, Channels: []string{"pagerduty"}}})
+
+	result, err := notifier.Send(context.Background(), planner.WorkItem{Repo: "owner/repo"}, &executor.Result{Status: executor.StatusFailed})
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if result == nil || result.Channel != "escalation" {
+		t.Fatalf("result = %+v, want escalation placeholder", result)
+	}
+}
+
+func TestEscalatingNotifier_ResolveIssueReachesEveryNotifier(t *testing.T) {
+	recorders, notifier := escalationLadder()
+
+	if _, err := notifier.ResolveIssue(context.Background(), planner.WorkItem{Repo: "owner/repo"}, 12, &executor.Result{Status: executor.StatusCompleted}); err != nil {
+		t.Fatalf("ResolveIssue() error = %v", err)
+	}
+	for name, recorder := range recorders {
+		if len(recorder.resolved) != 1 || recorder.resolved[0] != 12 {
+			t.Errorf("%s resolved %v, want [12]", name, recorder.resolved)
+		}
+	}
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"strings"
 
+	"github.com/goliatone/cascade/pkg/repometa"
 	"github.com/google/go-github/v66/github"
 )
 
@@ -16,18 +18,77 @@ type Provider interface {
 	RequestReviewers(ctx context.Context, repo string, number int, reviewers []string, teamReviewers []string) error
 	ListPullRequests(ctx context.Context, repo string, headBranch string) ([]*PullRequest, error)
 	AddComment(ctx context.Context, repo string, number int, body string) error
+	MergePullRequest(ctx context.Context, repo string, number int, opts MergeOptions) (*MergeResult, error)
+	ClosePullRequest(ctx context.Context, repo string, number int) error
 }
 
 // GitHubProvider implements the Provider interface using the GitHub API.
 type GitHubProvider struct {
-	client *github.Client
+	client      *github.Client
+	labelPolicy LabelPolicy
+	metadata    *repometa.Service
+}
+
+// LabelPolicy controls how the provider handles PR labels that do not yet exist
+// in the target repository.
+type LabelPolicy struct {
+	// AutoCreate creates missing labels before applying them to a PR
+	AutoCreate bool
+
+	// Color is the default hex color (without '#') for created labels
+	Color string
+
+	// Description is the default description for created labels
+	Description string
+
+	// Colors maps label names to hex colors, overriding Color
+	Colors map[string]string
+}
+
+// DefaultLabelColor is used for auto-created labels when no color is configured.
+const DefaultLabelColor = "ededed"
+
+// colorFor returns the configured color for a label, falling back to defaults.
+func (p LabelPolicy) colorFor(label string) string {
+	for name, color := range p.Colors {
+		if strings.EqualFold(name, label) && strings.TrimSpace(color) != "" {
+			return strings.TrimPrefix(strings.TrimSpace(color), "#")
+		}
+	}
+	if color := strings.TrimSpace(p.Color); color != "" {
+		return strings.TrimPrefix(color, "#")
+	}
+	return DefaultLabelColor
+}
+
+// GitHubProviderOption customises a GitHubProvider.
+type GitHubProviderOption func(*GitHubProvider)
+
+// WithLabelPolicy configures label auto-creation for the provider.
+func WithLabelPolicy(policy LabelPolicy) GitHubProviderOption {
+	return func(p *GitHubProvider) {
+		p.labelPolicy = policy
+	}
+}
+
+// WithRepoMetadata makes the provider refuse pull requests on archived
+// repositories, using the shared metadata cache so repositories already looked up
+// during planning cost no extra API calls.
+func WithRepoMetadata(svc *repometa.Service) GitHubProviderOption {
+	return func(p *GitHubProvider) {
+		p.metadata = svc
+	}
 }
 
 // NewGitHubProvider creates a new GitHub provider with the given client.
-func NewGitHubProvider(client *github.Client) Provider {
-	return &GitHubProvider{
+func NewGitHubProvider(client *github.Client, opts ...GitHubProviderOption) Provider {
+	provider := &GitHubProvider{
 		client: client,
 	}
+	for _, opt := range opts {
+		opt(provider)
+	}
+	return provider
 }
 
 // CreateOrUpdatePullRequest creates a new pull request or updates an existing one.
@@ -37,6 +98,18 @@ func (p *GitHubProvider) CreateOrUpdatePullRequest(ctx context.Context, input PR
 		return nil, fmt.Errorf("invalid repository format %q: %w", input.Repo, err)
 	}
 
+	// Archived repositories reject pushes and pull requests; fail before any write.
+	// Metadata lookup failures are ignored and the API reports the problem instead.
+	if p.metadata != nil {
+		if meta, err := p.metadata.Get(ctx, owner+"/"+repo); err == nil && meta.Archived {
+			return nil, &GitHubAPIError{
+				Operation: "create pull request",
+				Repo:      input.Repo,
+				Err:       ErrRepositoryArchived,
+			}
+		}
+	}
+
 	// Check for existing PR with the same head branch
 	existingPR, err := p.findExistingPR(ctx, owner, repo, input.HeadBranch)
 	if err != nil {
@@ -77,6 +150,7 @@ func (p *GitHubProvider) CreateOrUpdatePullRequest(ctx context.Context, input PR
 		Head:  &input.HeadBranch,
 		Base:  &input.BaseBranch,
 		Body:  &input.Body,
+		Draft: &input.Draft,
 	}
 
 	createdPR, _, err := p.client.PullRequests.Create(ctx, owner, repo, newPR)
@@ -214,17 +288,215 @@ func (p *GitHubProvider) AddComment(ctx context.Context, repo string, number int
 	return nil
 }
 
+// MergePullRequest merges a pull request. Refusals because the pull request is not
+// in a mergeable state are reported as ErrNotMergeable.
+func (p *GitHubProvider) MergePullRequest(ctx context.Context, repo string, number int, opts MergeOptions) (*MergeResult, error) {
+	owner, repoName, err := ParseRepoString(repo)
+	if err != nil {
+		return nil, fmt.Errorf("invalid repository format %q: %w", repo, err)
+	}
+
+	mergeOpts := &github.PullRequestOptions{
+		CommitTitle: opts.CommitTitle,
+		SHA:         opts.SHA,
+		MergeMethod: string(opts.Method),
+	}
+
+	merged, resp, err := p.client.PullRequests.Merge(ctx, owner, repoName, number, opts.CommitMessage, mergeOpts)
+	if err != nil {
+		apiErr := &GitHubAPIError{
+			Operation: "merge pull request",
+			Repo:      repo,
+			Err:       err,
+		}
+		// 405: not mergeable (checks, conflicts, protection); 409: head SHA changed
+		if resp != nil {
+			apiErr.StatusCode = resp.StatusCode
+			if resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusConflict {
+				apiErr.Err = fmt.Errorf("%w: %v", ErrNotMergeable, err)
+			}
+		}
+		return nil, apiErr
+	}
+
+	return &MergeResult{
+		SHA:     merged.GetSHA(),
+		Merged:  merged.GetMerged(),
+		Message: merged.GetMessage(),
+	}, nil
+}
+
+// ClosePullRequest closes a pull request without merging it.
+func (p *GitHubProvider) ClosePullRequest(ctx context.Context, repo string, number int) error {
+	owner, repoName, err := ParseRepoString(repo)
+	if err != nil {
+		return fmt.Errorf("invalid repository format %q: %w", repo, err)
+	}
+
+	_, _, err = p.client.PullRequests.Edit(ctx, owner, repoName, number, &github.PullRequest{
+		State: github.String("closed"),
+	})
+	if err != nil {
+		return &GitHubAPIError{
+			Operation: "close pull request",
+			Repo:      repo,
+			Err:       err,
+		}
+	}
+
+	return nil
+}
+
+// GetPullRequest looks up whether a pull request is open, closed, or merged.
+func (p *GitHubProvider) GetPullRequest(ctx context.Context, repo string, number int) (*PullRequestStatus, error) {
+	owner, repoName, err := ParseRepoString(repo)
+	if err != nil {
+		return nil, fmt.Errorf("invalid repository format %q: %w", repo, err)
+	}
+
+	pr, _, err := p.client.PullRequests.Get(ctx, owner, repoName, number)
+	if err != nil {
+		return nil, &GitHubAPIError{
+			Operation: "get pull request",
+			Repo:      repo,
+			Err:       err,
+		}
+	}
+
+	return &PullRequestStatus{
+		State:          pr.GetState(),
+		Merged:         pr.GetMerged(),
+		MergeCommitSHA: pr.GetMergeCommitSHA(),
+		BaseBranch:     pr.GetBase().GetRef(),
+		HeadBranch:     pr.GetHead().GetRef(),
+	}, nil
+}
+
+// GetPullRequestChecks reports the check runs and commit statuses on the head
+// commit of a pull request. Neutral and skipped check runs count as passed.
+func (p *GitHubProvider) GetPullRequestChecks(ctx context.Context, repo string, number int) (*ChecksStatus, error) {
+	owner, repoName, err := ParseRepoString(repo)
+	if err != nil {
+		return nil, fmt.Errorf("invalid repository format %q: %w", repo, err)
+	}
+
+	pr, _, err := p.client.PullRequests.Get(ctx, owner, repoName, number)
+	if err != nil {
+		return nil, &GitHubAPIError{Operation: "get pull request", Repo: repo, Err: err}
+	}
+	sha := pr.GetHead().GetSHA()
+
+	checks := newChecksStatus()
+	opts := &github.ListCheckRunsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		runs, resp, err := p.client.Checks.ListCheckRunsForRef(ctx, owner, repoName, sha, opts)
+		if err != nil {
+			return nil, &GitHubAPIError{Operation: "list check runs", Repo: repo, Err: err}
+		}
+		for _, run := range runs.CheckRuns {
+			checks.add(run.GetName(), gitHubCheckRunState(run))
+		}
+		if resp == nil || resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	combined, _, err := p.client.Repositories.GetCombinedStatus(ctx, owner, repoName, sha, &github.ListOptions{PerPage: 100})
+	if err != nil {
+		return nil, &GitHubAPIError{Operation: "get combined status", Repo: repo, Err: err}
+	}
+	for _, status := range combined.Statuses {
+		checks.add(status.GetContext(), gitHubCommitStatusState(status.GetState()))
+	}
+	return checks, nil
+}
+
+func gitHubCheckRunState(run *github.CheckRun) string {
+	if run.GetStatus() != "completed" {
+		return ChecksPending
+	}
+	switch run.GetConclusion() {
+	case "success", "neutral", "skipped":
+		return ChecksPassed
+	default:
+		return ChecksFailed
+	}
+}
+
+func gitHubCommitStatusState(state string) string {
+	switch state {
+	case "success":
+		return ChecksPassed
+	case "pending":
+		return ChecksPending
+	default:
+		return ChecksFailed
+	}
+}
+
 func (p *GitHubProvider) ensureLabels(ctx context.Context, repo string, number int, pr *github.PullRequest, desired []string) error {
 	labelsToApply := diffLabels(pr, desired)
 	if len(labelsToApply) == 0 {
 		return nil
 	}
+	if p.labelPolicy.AutoCreate {
+		if err := p.createMissingLabels(ctx, repo, labelsToApply); err != nil {
+			return fmt.Errorf("create labels: %w", err)
+		}
+	}
 	if err := p.AddLabels(ctx, repo, number, labelsToApply); err != nil {
 		return fmt.Errorf("apply labels: %w", err)
 	}
 	return nil
 }
 
+// createMissingLabels creates any label that does not exist in the repository yet,
+// using the colors and description from the provider's label policy.
+func (p *GitHubProvider) createMissingLabels(ctx context.Context, repo string, labels []string) error {
+	owner, repoName, err := ParseRepoString(repo)
+	if err != nil {
+		return fmt.Errorf("invalid repository format %q: %w", repo, err)
+	}
+
+	for _, name := range labels {
+		_, resp, err := p.client.Issues.GetLabel(ctx, owner, repoName, name)
+		if err == nil {
+			continue
+		}
+		if resp == nil || resp.StatusCode != http.StatusNotFound {
+			return &GitHubAPIError{
+				Operation: "get label",
+				Repo:      repo,
+				Err:       err,
+			}
+		}
+
+		label := &github.Label{
+			Name:  github.String(name),
+			Color: github.String(p.labelPolicy.colorFor(name)),
+		}
+		if desc := strings.TrimSpace(p.labelPolicy.Description); desc != "" {
+			label.Description = github.String(desc)
+		}
+
+		_, resp, err = p.client.Issues.CreateLabel(ctx, owner, repoName, label)
+		if err != nil {
+			// Another run may have created the label concurrently
+			if resp != nil && resp.StatusCode == http.StatusUnprocessableEntity {
+				continue
+			}
+			return &GitHubAPIError{
+				Operation: "create label",
+				Repo:      repo,
+				Err:       err,
+			}
+		}
+	}
+
+	return nil
+}
+
 func diffLabels(pr *github.PullRequest, desired []string) []string {
 	if len(desired) == 0 {
 		return nil
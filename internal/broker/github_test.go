@@ -3,12 +3,14 @@ package broker
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
 	"testing"
 
+	"github.com/goliatone/cascade/pkg/repometa"
 	"github.com/google/go-github/v66/github"
 )
 
@@ -229,6 +231,232 @@ func TestGitHubProvider_RequestReviewers_InvalidRepo(t *testing.T) {
 	}
 }
 
+func TestGitHubProvider_MergePullRequest(t *testing.T) {
+	responses := map[string]*http.Response{
+		"PUT /repos/owner/repo/pulls/1/merge": createJSONResponse(200, &github.PullRequestMergeResult{
+			SHA:     github.String("abc123"),
+			Merged:  github.Bool(true),
+			Message: github.String("Pull Request successfully merged"),
+		}),
+	}
+
+	provider := newTestGitHubProvider(responses)
+	ctx := context.Background()
+
+	result, err := provider.MergePullRequest(ctx, "owner/repo", 1, MergeOptions{Method: MergeMethodSquash})
+	if err != nil {
+		t.Fatalf("MergePullRequest failed: %v", err)
+	}
+
+	if !result.Merged {
+		t.Error("expected merged result")
+	}
+
+	if result.SHA != "abc123" {
+		t.Errorf("expected SHA %q, got %q", "abc123", result.SHA)
+	}
+}
+
+func TestGitHubProvider_MergePullRequest_NotMergeable(t *testing.T) {
+	responses := map[string]*http.Response{
+		"PUT /repos/owner/repo/pulls/1/merge": createJSONResponse(405, map[string]string{
+			"message": "Pull Request is not mergeable",
+		}),
+	}
+
+	provider := newTestGitHubProvider(responses)
+	ctx := context.Background()
+
+	_, err := provider.MergePullRequest(ctx, "owner/repo", 1, MergeOptions{})
+	if err == nil {
+		t.Fatal("expected error for unmergeable pull request, got nil")
+	}
+
+	if !errors.Is(err, ErrNotMergeable) {
+		t.Errorf("expected ErrNotMergeable, got: %v", err)
+	}
+
+	var apiErr *GitHubAPIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("expected GitHubAPIError with status 405, got: %v", err)
+	}
+}
+
+func TestGitHubProvider_MergePullRequest_InvalidRepo(t *testing.T) {
+	provider := newTestGitHubProvider(map[string]*http.Response{})
+	ctx := context.Background()
+
+	_, err := provider.MergePullRequest(ctx, "invalid-repo", 1, MergeOptions{})
+	if err == nil {
+		t.Fatal("expected error for invalid repo format, got nil")
+	}
+
+	if !strings.Contains(err.Error(), "invalid repository format") {
+		t.Errorf("expected error about invalid repository format, got: %v", err)
+	}
+}
+
+func TestGitHubProvider_ClosePullRequest(t *testing.T) {
+	responses := map[string]*http.Response{
+		"PATCH /repos/owner/repo/pulls/1": createJSONResponse(200, &github.PullRequest{
+			Number: github.Int(1),
+			State:  github.String("closed"),
+		}),
+	}
+
+	provider := newTestGitHubProvider(responses)
+	ctx := context.Background()
+
+	if err := provider.ClosePullRequest(ctx, "owner/repo", 1); err != nil {
+		t.Fatalf("ClosePullRequest failed: %v", err)
+	}
+}
+
+func TestGitHubProvider_ClosePullRequest_InvalidRepo(t *testing.T) {
+	provider := newTestGitHubProvider(map[string]*http.Response{})
+	ctx := context.Background()
+
+	err := provider.ClosePullRequest(ctx, "invalid-repo", 1)
+	if err == nil {
+		t.Fatal("expected error for invalid repo format, got nil")
+	}
+
+	if !strings.Contains(err.Error(), "invalid repository format") {
+		t.Errorf("expected error about invalid repository format, got: %v", err)
+	}
+}
+
+func TestGitHubProvider_GetPullRequest(t *testing.T) {
+	responses := map[string]*http.Response{
+		"GET /repos/owner/repo/pulls/7": createJSONResponse(200, &github.PullRequest{
+			Number:         github.Int(7),
+			State:          github.String("closed"),
+			Merged:         github.Bool(true),
+			MergeCommitSHA: github.String("abc123"),
+			Base:           &github.PullRequestBranch{Ref: github.String("main")},
+			Head:           &github.PullRequestBranch{Ref: github.String("cascade/update")},
+		}),
+	}
+
+	provider := newTestGitHubProvider(responses).(*GitHubProvider)
+	status, err := provider.GetPullRequest(context.Background(), "owner/repo", 7)
+	if err != nil {
+		t.Fatalf("GetPullRequest failed: %v", err)
+	}
+
+	want := PullRequestStatus{State: PullRequestClosed, Merged: true, MergeCommitSHA: "abc123", BaseBranch: "main", HeadBranch: "cascade/update"}
+	if *status != want {
+		t.Errorf("GetPullRequest() = %+v, want %+v", *status, want)
+	}
+
+	if _, err := provider.GetPullRequest(context.Background(), "owner/repo", 8); err == nil {
+		t.Error("expected error for a missing pull request")
+	}
+}
+
+func TestGitHubProvider_GetPullRequestChecks(t *testing.T) {
+	checkRuns := func(runs ...*github.CheckRun) *http.Response {
+		return createJSONResponse(200, &github.ListCheckRunsResults{Total: github.Int(len(runs)), CheckRuns: runs})
+	}
+	run := func(name, status, conclusion string) *github.CheckRun {
+		return &github.CheckRun{Name: github.String(name), Status: github.String(status), Conclusion: github.String(conclusion)}
+	}
+	tests := []struct {
+		name      string
+		runs      *http.Response
+		statuses  []*github.RepoStatus
+		wantState string
+		wantTotal int
+	}{
+		{
+			name:      "all passed",
+			runs:      checkRuns(run("test", "completed", "success"), run("lint", "completed", "skipped")),
+			statuses:  []*github.RepoStatus{{Context: github.String("ci/legacy"), State: github.String("success")}},
+			wantState: ChecksPassed,
+			wantTotal: 3,
+		},
+		{
+			name:      "still running",
+			runs:      checkRuns(run("test", "in_progress", ""), run("lint", "completed", "success")),
+			wantState: ChecksPending,
+			wantTotal: 2,
+		},
+		{
+			name:      "a failure wins over pending checks",
+			runs:      checkRuns(run("test", "in_progress", "")),
+			statuses:  []*github.RepoStatus{{Context: github.String("ci/legacy"), State: github.String("error")}},
+			wantState: ChecksFailed,
+			wantTotal: 2,
+		},
+		{
+			name:      "no checks",
+			runs:      checkRuns(),
+			wantState: ChecksNone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := newTestGitHubProvider(map[string]*http.Response{
+				"GET /repos/owner/repo/pulls/7": createJSONResponse(200, &github.PullRequest{
+					Number: github.Int(7),
+					Head:   &github.PullRequestBranch{SHA: github.String("abc123")},
+				}),
+				"GET /repos/owner/repo/commits/abc123/check-runs": tt.runs,
+				"GET /repos/owner/repo/commits/abc123/status":     createJSONResponse(200, &github.CombinedStatus{Statuses: tt.statuses}),
+			}).(ChecksGetter)
+
+			checks, err := provider.GetPullRequestChecks(context.Background(), "owner/repo", 7)
+			if err != nil {
+				t.Fatalf("GetPullRequestChecks() error = %v", err)
+			}
+			if checks.State != tt.wantState || checks.Total != tt.wantTotal {
+				t.Errorf("GetPullRequestChecks() = %+v, want %s with %d checks", checks, tt.wantState, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestGitHubProvider_EnableBranchCleanup(t *testing.T) {
+	provider := newTestGitHubProvider(map[string]*http.Response{
+		"GET /repos/owner/repo":         createJSONResponse(200, &github.Repository{DeleteBranchOnMerge: github.Bool(false)}),
+		"PATCH /repos/owner/repo":       createJSONResponse(200, &github.Repository{DeleteBranchOnMerge: github.Bool(true)}),
+		"GET /repos/owner/already-on":   createJSONResponse(200, &github.Repository{DeleteBranchOnMerge: github.Bool(true)}),
+		"PATCH /repos/owner/already-on": createJSONResponse(500, nil),
+		"GET /repos/owner/locked":       createJSONResponse(200, &github.Repository{DeleteBranchOnMerge: github.Bool(false)}),
+		"PATCH /repos/owner/locked":     createJSONResponse(403, map[string]string{"message": "Must have admin rights to Repository."}),
+	}).(BranchCleanupEnabler)
+
+	if err := provider.EnableBranchCleanup(context.Background(), "owner/repo"); err != nil {
+		t.Fatalf("EnableBranchCleanup() error = %v", err)
+	}
+	if err := provider.EnableBranchCleanup(context.Background(), "owner/already-on"); err != nil {
+		t.Errorf("EnableBranchCleanup() error = %v, want the enabled setting left alone", err)
+	}
+	if err := provider.EnableBranchCleanup(context.Background(), "owner/locked"); err == nil {
+		t.Error("EnableBranchCleanup() error = nil, want the 403 reported")
+	}
+}
+
+func TestGitHubProvider_DeleteBranch(t *testing.T) {
+	provider := newTestGitHubProvider(map[string]*http.Response{
+		"DELETE /repos/owner/repo/git/refs/heads/cascade/update": {StatusCode: 204, Body: http.NoBody, Header: make(http.Header)},
+		"DELETE /repos/owner/repo/git/refs/heads/cascade/gone":   createJSONResponse(422, map[string]string{"message": "Reference does not exist"}),
+		"DELETE /repos/owner/repo/git/refs/heads/cascade/locked": createJSONResponse(403, map[string]string{"message": "Resource not accessible"}),
+	}).(BranchDeleter)
+	ctx := context.Background()
+
+	if err := provider.DeleteBranch(ctx, "owner/repo", "cascade/update"); err != nil {
+		t.Errorf("DeleteBranch() error = %v", err)
+	}
+	if err := provider.DeleteBranch(ctx, "owner/repo", "cascade/gone"); err != nil {
+		t.Errorf("DeleteBranch() of a missing branch error = %v, want nil", err)
+	}
+	if err := provider.DeleteBranch(ctx, "owner/repo", "cascade/locked"); err == nil {
+		t.Error("DeleteBranch() error = nil, want the 403 reported")
+	}
+}
+
 func TestParseRepoString(t *testing.T) {
 	tests := []struct {
 		input       string
@@ -340,3 +568,133 @@ func TestExtractRateLimitInfo(t *testing.T) {
 		t.Error("expected nil for nil response, got non-nil")
 	}
 }
+
+func TestGitHubProvider_CreateOrUpdatePullRequest_AutoCreatesMissingLabels(t *testing.T) {
+	var created []github.Label
+	transport := &recordingLabelTransport{
+		existing: map[string]bool{"enhancement": true},
+		created:  &created,
+	}
+	client := github.NewClient(&http.Client{Transport: transport})
+	provider := NewGitHubProvider(client, WithLabelPolicy(LabelPolicy{
+		AutoCreate:  true,
+		Color:       "#0e8a16",
+		Description: "Managed by cascade",
+		Colors:      map[string]string{"automation:cascade": "5319e7"},
+	}))
+
+	_, err := provider.CreateOrUpdatePullRequest(context.Background(), PRInput{
+		Repo:       "owner/repo",
+		BaseBranch: "main",
+		HeadBranch: "feature-branch",
+		Title:      "Test PR",
+		Labels:     []string{"enhancement", "automation:cascade", "deps"},
+	})
+	if err != nil {
+		t.Fatalf("CreateOrUpdatePullRequest failed: %v", err)
+	}
+
+	if len(created) != 2 {
+		t.Fatalf("expected 2 labels created, got %d", len(created))
+	}
+	if created[0].GetName() != "automation:cascade" || created[0].GetColor() != "5319e7" {
+		t.Errorf("unexpected first label: %s/%s", created[0].GetName(), created[0].GetColor())
+	}
+	if created[1].GetName() != "deps" || created[1].GetColor() != "0e8a16" {
+		t.Errorf("unexpected second label: %s/%s", created[1].GetName(), created[1].GetColor())
+	}
+	if created[1].GetDescription() != "Managed by cascade" {
+		t.Errorf("expected description to be applied, got %q", created[1].GetDescription())
+	}
+	if !transport.labelsApplied {
+		t.Error("expected labels to be applied to the PR after creation")
+	}
+}
+
+func TestGitHubProvider_CreateOrUpdatePullRequest_NoAutoCreateByDefault(t *testing.T) {
+	var created []github.Label
+	transport := &recordingLabelTransport{created: &created}
+	provider := NewGitHubProvider(github.NewClient(&http.Client{Transport: transport}))
+
+	_, err := provider.CreateOrUpdatePullRequest(context.Background(), PRInput{
+		Repo:       "owner/repo",
+		BaseBranch: "main",
+		HeadBranch: "feature-branch",
+		Title:      "Test PR",
+		Labels:     []string{"deps"},
+	})
+	if err != nil {
+		t.Fatalf("CreateOrUpdatePullRequest failed: %v", err)
+	}
+	if len(created) != 0 {
+		t.Fatalf("expected no labels created without auto_create, got %d", len(created))
+	}
+}
+
+// recordingLabelTransport serves the PR and label endpoints used by label auto-creation.
+type recordingLabelTransport struct {
+	existing      map[string]bool
+	created       *[]github.Label
+	labelsApplied bool
+}
+
+func (r *recordingLabelTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	path := req.URL.Path
+	switch {
+	case req.Method == http.MethodGet && path == "/repos/owner/repo/pulls":
+		return createJSONResponse(200, []*github.PullRequest{}), nil
+	case req.Method == http.MethodPost && path == "/repos/owner/repo/pulls":
+		return createJSONResponse(201, &github.PullRequest{Number: github.Int(1)}), nil
+	case req.Method == http.MethodPost && path == "/repos/owner/repo/issues/1/labels":
+		r.labelsApplied = true
+		return createJSONResponse(200, []*github.Label{}), nil
+	case req.Method == http.MethodGet && strings.HasPrefix(path, "/repos/owner/repo/labels/"):
+		name := strings.TrimPrefix(path, "/repos/owner/repo/labels/")
+		if r.existing[name] {
+			return createJSONResponse(200, &github.Label{Name: github.String(name)}), nil
+		}
+		return createJSONResponse(404, map[string]string{"message": "Not Found"}), nil
+	case req.Method == http.MethodPost && path == "/repos/owner/repo/labels":
+		var label github.Label
+		if err := json.NewDecoder(req.Body).Decode(&label); err != nil {
+			return nil, err
+		}
+		*r.created = append(*r.created, label)
+		return createJSONResponse(201, &label), nil
+	}
+	return createJSONResponse(404, map[string]string{"message": "Not Found"}), nil
+}
+
+func TestGitHubProvider_CreateOrUpdatePullRequest_ArchivedRepository(t *testing.T) {
+	// No PR endpoints are registered: an archived repository must fail before any call
+	client := github.NewClient(&http.Client{Transport: &fakeRoundTripper{responses: map[string]*http.Response{}}})
+	metadata := repometa.NewService(repometa.FetcherFunc(func(ctx context.Context, repo string) (*repometa.Metadata, error) {
+		return &repometa.Metadata{Repo: repo, Archived: repo == "owner/archived"}, nil
+	}))
+	provider := NewGitHubProvider(client, WithRepoMetadata(metadata))
+
+	_, err := provider.CreateOrUpdatePullRequest(context.Background(), PRInput{
+		Repo:       "owner/archived",
+		BaseBranch: "main",
+		HeadBranch: "feature-branch",
+		Title:      "Test PR",
+	})
+	if !errors.Is(err, ErrRepositoryArchived) {
+		t.Fatalf("expected ErrRepositoryArchived, got %v", err)
+	}
+	var apiErr *GitHubAPIError
+	if !errors.As(err, &apiErr) || apiErr.Repo != "owner/archived" {
+		t.Fatalf("expected GitHubAPIError for owner/archived, got %v", err)
+	}
+
+	// Active repositories proceed to the API as usual
+	_, err = provider.CreateOrUpdatePullRequest(context.Background(), PRInput{
+		Repo:       "owner/active",
+		BaseBranch: "main",
+		HeadBranch: "feature-branch",
+		Title:      "Test PR",
+	})
+	if err == nil || errors.Is(err, ErrRepositoryArchived) {
+		t.Fatalf("expected an API error for the unmocked active repository, got %v", err)
+	}
+}
@@ -0,0 +1,445 @@
+package broker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// DefaultGitLabEndpoint is the GitLab.com REST API.
+const DefaultGitLabEndpoint = "https://gitlab.com/api/v4"
+
+// GitLab merge request states.
+const (
+	gitLabStateOpened = "opened"
+	gitLabStateMerged = "merged"
+)
+
+// GitLabProvider implements the Provider interface using the GitLab REST API.
+// Pull requests are GitLab merge requests, numbered by their project IID.
+type GitLabProvider struct {
+	client   *http.Client
+	endpoint string
+	token    string
+}
+
+// NewGitLabProvider creates a provider for the GitLab API at endpoint, such as
+// https://gitlab.example.com/api/v4. An empty endpoint selects GitLab.com.
+func NewGitLabProvider(token, endpoint string, client *http.Client) Provider {
+	endpoint = strings.TrimSuffix(strings.TrimSpace(endpoint), "/")
+	if endpoint == "" {
+		endpoint = DefaultGitLabEndpoint
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &GitLabProvider{
+		client:   client,
+		endpoint: endpoint,
+		token:    token,
+	}
+}
+
+var (
+	_ Provider          = (*GitLabProvider)(nil)
+	_ PullRequestGetter = (*GitLabProvider)(nil)
+)
+
+// gitLabMergeRequest is the subset of the GitLab merge request resource the
+// provider reads.
+type gitLabMergeRequest struct {
+	IID             int      `json:"iid"`
+	WebURL          string   `json:"web_url"`
+	State           string   `json:"state"`
+	Labels          []string `json:"labels"`
+	SourceBranch    string   `json:"source_branch"`
+	TargetBranch    string   `json:"target_branch"`
+	MergeCommitSHA  string   `json:"merge_commit_sha"`
+	SquashCommitSHA string   `json:"squash_commit_sha"`
+	SHA             string   `json:"sha"`
+
+	HeadPipeline *gitLabPipeline `json:"head_pipeline"`
+}
+
+type gitLabPipeline struct {
+	ID     int    `json:"id"`
+	Status string `json:"status"`
+}
+
+func (mr *gitLabMergeRequest) pullRequest(repo string) *PullRequest {
+	labels := mr.Labels
+	if labels == nil {
+		labels = []string{}
+	}
+	return &PullRequest{
+		URL:    mr.WebURL,
+		Number: mr.IID,
+		Repo:   repo,
+		Labels: labels,
+	}
+}
+
+// CreateOrUpdatePullRequest creates a merge request, or updates the open one
+// from the same source branch.
+func (p *GitLabProvider) CreateOrUpdatePullRequest(ctx context.Context, input PRInput) (*PullRequest, error) {
+	project, err := gitLabProject(input.Repo)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := p.listMergeRequests(ctx, input.Repo, project, input.HeadBranch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find existing merge request: %w", err)
+	}
+
+	// GitLab creates labels that do not exist yet when they are applied
+	if len(existing) > 0 {
+		payload := map[string]any{
+			"title":       input.Title,
+			"description": input.Body,
+		}
+		if len(input.Labels) > 0 {
+			payload["add_labels"] = strings.Join(input.Labels, ",")
+		}
+		var updated gitLabMergeRequest
+		if err := p.do(ctx, "update merge request", input.Repo, http.MethodPut, mergeRequestPath(project, existing[0].IID), payload, &updated); err != nil {
+			return nil, err
+		}
+		pr := updated.pullRequest(input.Repo)
+		pr.Labels = input.Labels
+		return pr, nil
+	}
+
+	// GitLab marks merge requests whose title starts with Draft: as drafts
+	title := input.Title
+	if input.Draft && !strings.HasPrefix(strings.ToLower(title), "draft:") {
+		title = "Draft: " + title
+	}
+	payload := map[string]any{
+		"source_branch":        input.HeadBranch,
+		"target_branch":        input.BaseBranch,
+		"title":                title,
+		"description":          input.Body,
+		"remove_source_branch": true,
+	}
+	if len(input.Labels) > 0 {
+		payload["labels"] = strings.Join(input.Labels, ",")
+	}
+	var created gitLabMergeRequest
+	if err := p.do(ctx, "create merge request", input.Repo, http.MethodPost, "/projects/"+project+"/merge_requests", payload, &created); err != nil {
+		return nil, err
+	}
+	pr := created.pullRequest(input.Repo)
+	pr.Labels = input.Labels
+	return pr, nil
+}
+
+// AddLabels adds labels to a merge request.
+func (p *GitLabProvider) AddLabels(ctx context.Context, repo string, number int, labels []string) error {
+	if len(labels) == 0 {
+		return nil
+	}
+	project, err := gitLabProject(repo)
+	if err != nil {
+		return err
+	}
+	payload := map[string]any{"add_labels": strings.Join(labels, ",")}
+	return p.do(ctx, "add labels", repo, http.MethodPut, mergeRequestPath(project, number), payload, nil)
+}
+
+// RequestReviewers sets the reviewers of a merge request by username. GitLab has
+// no team reviewers, so teamReviewers are ignored.
+func (p *GitLabProvider) RequestReviewers(ctx context.Context, repo string, number int, reviewers []string, teamReviewers []string) error {
+	if len(reviewers) == 0 {
+		return nil
+	}
+	project, err := gitLabProject(repo)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]int, 0, len(reviewers))
+	for _, username := range reviewers {
+		id, err := p.userID(ctx, repo, username)
+		if err != nil {
+			return err
+		}
+		ids = append(ids, id)
+	}
+	payload := map[string]any{"reviewer_ids": ids}
+	return p.do(ctx, "request reviewers", repo, http.MethodPut, mergeRequestPath(project, number), payload, nil)
+}
+
+// ListPullRequests lists open merge requests from the given source branch.
+func (p *GitLabProvider) ListPullRequests(ctx context.Context, repo string, headBranch string) ([]*PullRequest, error) {
+	project, err := gitLabProject(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	mrs, err := p.listMergeRequests(ctx, repo, project, headBranch)
+	if err != nil {
+		return nil, err
+	}
+
+	var prs []*PullRequest
+	for i := range mrs {
+		prs = append(prs, mrs[i].pullRequest(repo))
+	}
+	return prs, nil
+}
+
+// AddComment adds a note to a merge request.
+func (p *GitLabProvider) AddComment(ctx context.Context, repo string, number int, body string) error {
+	project, err := gitLabProject(repo)
+	if err != nil {
+		return err
+	}
+	payload := map[string]any{"body": body}
+	return p.do(ctx, "add comment", repo, http.MethodPost, mergeRequestPath(project, number)+"/notes", payload, nil)
+}
+
+// MergePullRequest merges a merge request. Squash merges squash the source
+// branch; GitLab applies the project's merge method otherwise. Refusals because
+// the merge request cannot be merged are reported as ErrNotMergeable.
+func (p *GitLabProvider) MergePullRequest(ctx context.Context, repo string, number int, opts MergeOptions) (*MergeResult, error) {
+	project, err := gitLabProject(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := map[string]any{"squash": opts.Method == MergeMethodSquash}
+	if opts.SHA != "" {
+		payload["sha"] = opts.SHA
+	}
+	if message := mergeCommitMessage(opts); message != "" {
+		if opts.Method == MergeMethodSquash {
+			payload["squash_commit_message"] = message
+		} else {
+			payload["merge_commit_message"] = message
+		}
+	}
+
+	var merged gitLabMergeRequest
+	err = p.do(ctx, "merge merge request", repo, http.MethodPut, mergeRequestPath(project, number)+"/merge", payload, &merged)
+	if err != nil {
+		var apiErr *GitLabAPIError
+		// 405: not mergeable (pipeline, conflicts, draft); 406: merge failed; 409: SHA changed
+		if errors.As(err, &apiErr) {
+			switch apiErr.StatusCode {
+			case http.StatusMethodNotAllowed, http.StatusNotAcceptable, http.StatusConflict, http.StatusUnprocessableEntity:
+				apiErr.Err = fmt.Errorf("%w: %v", ErrNotMergeable, apiErr.Err)
+			}
+		}
+		return nil, err
+	}
+
+	return &MergeResult{
+		SHA:    firstNonEmptyString(merged.MergeCommitSHA, merged.SquashCommitSHA, merged.SHA),
+		Merged: merged.State == gitLabStateMerged,
+	}, nil
+}
+
+// ClosePullRequest closes a merge request without merging it.
+func (p *GitLabProvider) ClosePullRequest(ctx context.Context, repo string, number int) error {
+	project, err := gitLabProject(repo)
+	if err != nil {
+		return err
+	}
+	payload := map[string]any{"state_event": "close"}
+	return p.do(ctx, "close merge request", repo, http.MethodPut, mergeRequestPath(project, number), payload, nil)
+}
+
+// GetPullRequest looks up whether a merge request is open, closed, or merged.
+func (p *GitLabProvider) GetPullRequest(ctx context.Context, repo string, number int) (*PullRequestStatus, error) {
+	project, err := gitLabProject(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	var mr gitLabMergeRequest
+	if err := p.do(ctx, "get merge request", repo, http.MethodGet, mergeRequestPath(project, number), nil, &mr); err != nil {
+		return nil, err
+	}
+
+	status := &PullRequestStatus{
+		State:      PullRequestClosed,
+		Merged:     mr.State == gitLabStateMerged,
+		BaseBranch: mr.TargetBranch,
+		HeadBranch: mr.SourceBranch,
+	}
+	if mr.State == gitLabStateOpened {
+		status.State = PullRequestOpen
+	}
+	if status.Merged {
+		status.MergeCommitSHA = firstNonEmptyString(mr.MergeCommitSHA, mr.SquashCommitSHA, mr.SHA)
+	}
+	return status, nil
+}
+
+// GetPullRequestChecks reports the head pipeline of a merge request, which
+// GitLab counts as its single check. Skipped pipelines count as passed.
+func (p *GitLabProvider) GetPullRequestChecks(ctx context.Context, repo string, number int) (*ChecksStatus, error) {
+	project, err := gitLabProject(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	var mr gitLabMergeRequest
+	if err := p.do(ctx, "get merge request", repo, http.MethodGet, mergeRequestPath(project, number), nil, &mr); err != nil {
+		return nil, err
+	}
+
+	checks := newChecksStatus()
+	if mr.HeadPipeline == nil {
+		return checks, nil
+	}
+	name := fmt.Sprintf("pipeline #%d", mr.HeadPipeline.ID)
+	switch mr.HeadPipeline.Status {
+	case "success", "skipped":
+		checks.add(name, ChecksPassed)
+	case "failed", "canceled":
+		checks.add(name, ChecksFailed)
+	default:
+		checks.add(name, ChecksPending)
+	}
+	return checks, nil
+}
+
+func (p *GitLabProvider) listMergeRequests(ctx context.Context, repo, project, sourceBranch string) ([]gitLabMergeRequest, error) {
+	query := url.Values{}
+	query.Set("state", gitLabStateOpened)
+	query.Set("source_branch", sourceBranch)
+	query.Set("order_by", "created_at")
+	query.Set("sort", "desc")
+	query.Set("per_page", "10")
+
+	var mrs []gitLabMergeRequest
+	if err := p.do(ctx, "list merge requests", repo, http.MethodGet, "/projects/"+project+"/merge_requests?"+query.Encode(), nil, &mrs); err != nil {
+		return nil, err
+	}
+	return mrs, nil
+}
+
+func (p *GitLabProvider) userID(ctx context.Context, repo, username string) (int, error) {
+	var users []struct {
+		ID int `json:"id"`
+	}
+	path := "/users?" + url.Values{"username": {username}}.Encode()
+	if err := p.do(ctx, "look up reviewer", repo, http.MethodGet, path, nil, &users); err != nil {
+		return 0, err
+	}
+	if len(users) == 0 {
+		return 0, &GitLabAPIError{Operation: "look up reviewer", Repo: repo, Err: fmt.Errorf("user %s not found", username)}
+	}
+	return users[0].ID, nil
+}
+
+// do sends a request to the API and decodes the JSON response into out, which
+// may be nil.
+func (p *GitLabProvider) do(ctx context.Context, operation, repo, method, path string, payload, out any) error {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s request: %w", operation, err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, p.endpoint+path, body)
+	if err != nil {
+		return &GitLabAPIError{Operation: operation, Repo: repo, Err: err}
+	}
+	req.Header.Set("PRIVATE-TOKEN", p.token)
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return &GitLabAPIError{Operation: operation, Repo: repo, Err: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return &GitLabAPIError{
+			Operation:    operation,
+			Repo:         repo,
+			StatusCode:   resp.StatusCode,
+			ResponseBody: string(data),
+			Err:          fmt.Errorf("%s", gitLabErrorMessage(resp.Status, data)),
+		}
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return &GitLabAPIError{Operation: operation, Repo: repo, StatusCode: resp.StatusCode, Err: fmt.Errorf("decode response: %w", err)}
+	}
+	return nil
+}
+
+// gitLabProject returns the URL-encoded project path of a repository, as the
+// API expects in place of a numeric project ID.
+func gitLabProject(repo string) (string, error) {
+	owner, name, err := ParseRepoString(repo)
+	if err != nil {
+		return "", fmt.Errorf("invalid repository format %q: %w", repo, err)
+	}
+	return url.PathEscape(owner + "/" + name), nil
+}
+
+func mergeRequestPath(project string, number int) string {
+	return "/projects/" + project + "/merge_requests/" + strconv.Itoa(number)
+}
+
+// mergeCommitMessage joins the title and message of a merge into one commit
+// message, as GitLab takes a single field.
+func mergeCommitMessage(opts MergeOptions) string {
+	switch {
+	case opts.CommitTitle != "" && opts.CommitMessage != "":
+		return opts.CommitTitle + "\n\n" + opts.CommitMessage
+	case opts.CommitTitle != "":
+		return opts.CommitTitle
+	default:
+		return opts.CommitMessage
+	}
+}
+
+// gitLabErrorMessage extracts the "message" or "error" field GitLab returns
+// with failed requests.
+func gitLabErrorMessage(status string, body []byte) string {
+	var payload struct {
+		Message any    `json:"message"`
+		Error   string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &payload); err == nil {
+		switch {
+		case payload.Message != nil:
+			return fmt.Sprintf("%s: %v", status, payload.Message)
+		case payload.Error != "":
+			return fmt.Sprintf("%s: %s", status, payload.Error)
+		}
+	}
+	return status
+}
+
+func firstNonEmptyString(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,247 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// gitLabServer serves canned responses keyed by method and escaped path, and
+// records request bodies.
+type gitLabServer struct {
+	t         *testing.T
+	responses map[string]gitLabResponse
+	requests  map[string]map[string]any
+}
+
+type gitLabResponse struct {
+	status int
+	body   any
+}
+
+func newGitLabServer(t *testing.T, responses map[string]gitLabResponse) (*gitLabServer, Provider) {
+	t.Helper()
+	s := &gitLabServer{t: t, responses: responses, requests: map[string]map[string]any{}}
+	server := httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(server.Close)
+	return s, NewGitLabProvider("glpat-test", server.URL+"/api/v4/", server.Client())
+}
+
+func (s *gitLabServer) serve(w http.ResponseWriter, r *http.Request) {
+	if got := r.Header.Get("PRIVATE-TOKEN"); got != "glpat-test" {
+		s.t.Errorf("PRIVATE-TOKEN = %q", got)
+	}
+	key := r.Method + " " + strings.TrimPrefix(r.URL.EscapedPath(), "/api/v4")
+	if r.Body != nil {
+		var payload map[string]any
+		if json.NewDecoder(r.Body).Decode(&payload) == nil {
+			s.requests[key] = payload
+		}
+	}
+	resp, ok := s.responses[key]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message":"404 Not found"}`))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(resp.status)
+	_ = json.NewEncoder(w).Encode(resp.body)
+}
+
+func TestGitLabProvider_CreateOrUpdatePullRequest_CreateNew(t *testing.T) {
+	server, provider := newGitLabServer(t, map[string]gitLabResponse{
+		"GET /projects/group%2Fproject/merge_requests": {status: 200, body: []any{}},
+		"POST /projects/group%2Fproject/merge_requests": {status: 201, body: map[string]any{
+			"iid":     4,
+			"web_url": "https://gitlab.com/group/project/-/merge_requests/4",
+		}},
+	})
+
+	pr, err := provider.CreateOrUpdatePullRequest(context.Background(), PRInput{
+		Repo:       "gitlab.com/group/project",
+		BaseBranch: "main",
+		HeadBranch: "cascade/update",
+		Title:      "Update lib",
+		Body:       "Bumps lib.",
+		Labels:     []string{"deps", "automation"},
+	})
+	if err != nil {
+		t.Fatalf("CreateOrUpdatePullRequest() error = %v", err)
+	}
+	if pr.Number != 4 || pr.URL != "https://gitlab.com/group/project/-/merge_requests/4" || pr.Repo != "gitlab.com/group/project" {
+		t.Errorf("pr = %+v", pr)
+	}
+
+	sent := server.requests["POST /projects/group%2Fproject/merge_requests"]
+	if sent["source_branch"] != "cascade/update" || sent["target_branch"] != "main" || sent["description"] != "Bumps lib." || sent["labels"] != "deps,automation" {
+		t.Errorf("create payload = %v", sent)
+	}
+
+	if _, err := provider.CreateOrUpdatePullRequest(context.Background(), PRInput{
+		Repo:       "gitlab.com/group/project",
+		BaseBranch: "main",
+		HeadBranch: "cascade/update",
+		Title:      "Update lib",
+		Draft:      true,
+	}); err != nil {
+		t.Fatalf("CreateOrUpdatePullRequest() draft error = %v", err)
+	}
+	if sent := server.requests["POST /projects/group%2Fproject/merge_requests"]; sent["title"] != "Draft: Update lib" {
+		t.Errorf("expected a draft title, got %v", sent["title"])
+	}
+}
+
+func TestGitLabProvider_CreateOrUpdatePullRequest_UpdateExisting(t *testing.T) {
+	server, provider := newGitLabServer(t, map[string]gitLabResponse{
+		"GET /projects/group%2Fproject/merge_requests": {status: 200, body: []any{
+			map[string]any{"iid": 2, "web_url": "https://gitlab.com/group/project/-/merge_requests/2"},
+		}},
+		"PUT /projects/group%2Fproject/merge_requests/2": {status: 200, body: map[string]any{
+			"iid":     2,
+			"web_url": "https://gitlab.com/group/project/-/merge_requests/2",
+		}},
+	})
+
+	pr, err := provider.CreateOrUpdatePullRequest(context.Background(), PRInput{
+		Repo:       "group/project",
+		BaseBranch: "main",
+		HeadBranch: "cascade/update",
+		Title:      "Update lib",
+		Labels:     []string{"deps"},
+	})
+	if err != nil {
+		t.Fatalf("CreateOrUpdatePullRequest() error = %v", err)
+	}
+	if pr.Number != 2 {
+		t.Errorf("pr = %+v, want the existing merge request", pr)
+	}
+	if sent := server.requests["PUT /projects/group%2Fproject/merge_requests/2"]; sent["title"] != "Update lib" || sent["add_labels"] != "deps" {
+		t.Errorf("update payload = %v", sent)
+	}
+}
+
+func TestGitLabProvider_ReviewersLabelsAndComments(t *testing.T) {
+	server, provider := newGitLabServer(t, map[string]gitLabResponse{
+		"GET /users": {status: 200, body: []any{map[string]any{"id": 17}}},
+		"PUT /projects/group%2Fproject/merge_requests/3":        {status: 200, body: map[string]any{"iid": 3}},
+		"POST /projects/group%2Fproject/merge_requests/3/notes": {status: 201, body: map[string]any{"id": 1}},
+	})
+	ctx := context.Background()
+
+	if err := provider.RequestReviewers(ctx, "group/project", 3, []string{"alice"}, []string{"platform"}); err != nil {
+		t.Fatalf("RequestReviewers() error = %v", err)
+	}
+	ids, _ := server.requests["PUT /projects/group%2Fproject/merge_requests/3"]["reviewer_ids"].([]any)
+	if len(ids) != 1 || ids[0] != float64(17) {
+		t.Errorf("reviewer_ids = %v, want [17]", ids)
+	}
+
+	if err := provider.AddLabels(ctx, "group/project", 3, []string{"deps"}); err != nil {
+		t.Fatalf("AddLabels() error = %v", err)
+	}
+	if got := server.requests["PUT /projects/group%2Fproject/merge_requests/3"]["add_labels"]; got != "deps" {
+		t.Errorf("add_labels = %v", got)
+	}
+
+	if err := provider.AddComment(ctx, "group/project", 3, "Tests passed"); err != nil {
+		t.Fatalf("AddComment() error = %v", err)
+	}
+	if got := server.requests["POST /projects/group%2Fproject/merge_requests/3/notes"]["body"]; got != "Tests passed" {
+		t.Errorf("note body = %v", got)
+	}
+}
+
+func TestGitLabProvider_MergeCloseAndGet(t *testing.T) {
+	server, provider := newGitLabServer(t, map[string]gitLabResponse{
+		"PUT /projects/group%2Fproject/merge_requests/3/merge": {status: 200, body: map[string]any{
+			"iid": 3, "state": "merged", "squash_commit_sha": "abc123",
+		}},
+		"PUT /projects/group%2Fproject/merge_requests/4/merge": {status: 405, body: map[string]any{"message": "405 Method Not Allowed"}},
+		"PUT /projects/group%2Fproject/merge_requests/5":       {status: 200, body: map[string]any{"iid": 5, "state": "closed"}},
+		"GET /projects/group%2Fproject/merge_requests/3": {status: 200, body: map[string]any{
+			"iid": 3, "state": "merged", "merge_commit_sha": "def456", "target_branch": "main", "source_branch": "cascade/update",
+		}},
+	})
+	ctx := context.Background()
+
+	result, err := provider.MergePullRequest(ctx, "group/project", 3, MergeOptions{Method: MergeMethodSquash, CommitTitle: "Update lib"})
+	if err != nil {
+		t.Fatalf("MergePullRequest() error = %v", err)
+	}
+	if !result.Merged || result.SHA != "abc123" {
+		t.Errorf("merge result = %+v", result)
+	}
+	if sent := server.requests["PUT /projects/group%2Fproject/merge_requests/3/merge"]; sent["squash"] != true || sent["squash_commit_message"] != "Update lib" {
+		t.Errorf("merge payload = %v", sent)
+	}
+
+	_, err = provider.MergePullRequest(ctx, "group/project", 4, MergeOptions{})
+	var apiErr *GitLabAPIError
+	if !errors.Is(err, ErrNotMergeable) || !errors.As(err, &apiErr) || apiErr.StatusCode != 405 {
+		t.Errorf("MergePullRequest() error = %v, want ErrNotMergeable with status 405", err)
+	}
+
+	if err := provider.ClosePullRequest(ctx, "group/project", 5); err != nil {
+		t.Fatalf("ClosePullRequest() error = %v", err)
+	}
+	if got := server.requests["PUT /projects/group%2Fproject/merge_requests/5"]["state_event"]; got != "close" {
+		t.Errorf("state_event = %v", got)
+	}
+
+	status, err := provider.(PullRequestGetter).GetPullRequest(ctx, "group/project", 3)
+	if err != nil {
+		t.Fatalf("GetPullRequest() error = %v", err)
+	}
+	want := PullRequestStatus{State: PullRequestClosed, Merged: true, MergeCommitSHA: "def456", BaseBranch: "main", HeadBranch: "cascade/update"}
+	if *status != want {
+		t.Errorf("GetPullRequest() = %+v, want %+v", *status, want)
+	}
+}
+
+func TestGitLabProvider_GetPullRequestChecks(t *testing.T) {
+	_, provider := newGitLabServer(t, map[string]gitLabResponse{
+		"GET /projects/group%2Fproject/merge_requests/3": {status: 200, body: map[string]any{
+			"iid": 3, "state": "opened", "head_pipeline": map[string]any{"id": 41, "status": "running"},
+		}},
+		"GET /projects/group%2Fproject/merge_requests/4": {status: 200, body: map[string]any{
+			"iid": 4, "state": "opened", "head_pipeline": map[string]any{"id": 42, "status": "failed"},
+		}},
+		"GET /projects/group%2Fproject/merge_requests/5": {status: 200, body: map[string]any{"iid": 5, "state": "opened"}},
+	})
+	getter := provider.(ChecksGetter)
+	ctx := context.Background()
+
+	for number, want := range map[int]string{3: ChecksPending, 4: ChecksFailed, 5: ChecksNone} {
+		checks, err := getter.GetPullRequestChecks(ctx, "group/project", number)
+		if err != nil {
+			t.Fatalf("GetPullRequestChecks(%d) error = %v", number, err)
+		}
+		if checks.State != want {
+			t.Errorf("GetPullRequestChecks(%d) = %+v, want %s", number, checks, want)
+		}
+	}
+
+	checks, _ := getter.GetPullRequestChecks(ctx, "group/project", 4)
+	if len(checks.Failed) != 1 || checks.Failed[0] != "pipeline #42" {
+		t.Errorf("failed checks = %v, want pipeline #42", checks.Failed)
+	}
+}
+
+func TestGitLabProvider_Errors(t *testing.T) {
+	_, provider := newGitLabServer(t, map[string]gitLabResponse{})
+
+	err := provider.AddComment(context.Background(), "group/project", 9, "hello")
+	var apiErr *GitLabAPIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 404 || !strings.Contains(err.Error(), "404 Not found") {
+		t.Errorf("AddComment() error = %v, want API 404 with GitLab's message", err)
+	}
+
+	if err := provider.ClosePullRequest(context.Background(), "invalid-repo", 1); err == nil || !strings.Contains(err.Error(), "invalid repository format") {
+		t.Errorf("ClosePullRequest() error = %v, want invalid repository format", err)
+	}
+}
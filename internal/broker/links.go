@@ -0,0 +1,85 @@
+package broker
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/goliatone/cascade/internal/planner"
+	"github.com/goliatone/cascade/internal/state"
+)
+
+type stateRunKey struct{}
+
+type stateRun struct {
+	module  string
+	version string
+}
+
+// WithStateRun returns a context that tells notifiers the module and version the
+// run's state is stored under. A release set stores every item under its first
+// module, which need not be the item's source module.
+func WithStateRun(ctx context.Context, module, version string) context.Context {
+	return context.WithValue(ctx, stateRunKey{}, stateRun{module: module, version: version})
+}
+
+type annotationsKey struct{}
+
+// WithAnnotations returns a context that carries the operator annotations of the
+// work item a notification reports, so repeated failures and escalations keep
+// the team's context.
+func WithAnnotations(ctx context.Context, annotations []state.Annotation) context.Context {
+	return context.WithValue(ctx, annotationsKey{}, annotations)
+}
+
+// annotationsFromContext returns the annotations recorded with WithAnnotations.
+func annotationsFromContext(ctx context.Context) []state.Annotation {
+	if ctx == nil {
+		return nil
+	}
+	annotations, _ := ctx.Value(annotationsKey{}).([]state.Annotation)
+	return annotations
+}
+
+// StateLinks are deep links to the state Cascade records for a work item.
+type StateLinks struct {
+	// State is the item's state file, which holds its command logs.
+	State string
+
+	// Summary is the summary of the run the item belongs to.
+	Summary string
+}
+
+// BuildStateLinks returns the links to the state of repo recorded for
+// module@version, for a state directory served at baseURL. An empty baseURL
+// returns no links.
+func BuildStateLinks(baseURL, module, version, repo string) StateLinks {
+	baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	if baseURL == "" || module == "" || version == "" {
+		return StateLinks{}
+	}
+	return StateLinks{
+		State:   baseURL + "/" + escapeStatePath(state.ItemRelPath(module, version, repo)),
+		Summary: baseURL + "/" + escapeStatePath(state.SummaryRelPath(module, version)),
+	}
+}
+
+// itemStateLinks returns the links to the state of item, stored under the run
+// recorded on ctx or, without one, under the item's source module and version.
+func itemStateLinks(ctx context.Context, baseURL string, item planner.WorkItem) StateLinks {
+	module, version := item.SourceModule, item.SourceVersion
+	if ctx != nil {
+		if run, ok := ctx.Value(stateRunKey{}).(stateRun); ok && run.module != "" {
+			module, version = run.module, run.version
+		}
+	}
+	return BuildStateLinks(baseURL, module, version, item.Repo)
+}
+
+func escapeStatePath(p string) string {
+	segments := strings.Split(p, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
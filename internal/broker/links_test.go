@@ -0,0 +1,121 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/internal/planner"
+	"github.com/goliatone/cascade/internal/state"
+	"github.com/google/go-github/v66/github"
+)
+
+func TestBuildStateLinks(t *testing.T) {
+	links := BuildStateLinks("https://cascade.example.com/state/", "github.com/acme/lib", "v1.2.0", "acme/api")
+
+	wantState := "https://cascade.example.com/state/" + state.ItemRelPath("github.com/acme/lib", "v1.2.0", "acme/api")
+	if links.State != wantState {
+		t.Errorf("State = %q, want %q", links.State, wantState)
+	}
+	if links.Summary != "https://cascade.example.com/state/github.com/acme/lib/v1.2.0/summary.json" {
+		t.Errorf("Summary = %q", links.Summary)
+	}
+
+	if links := BuildStateLinks("", "github.com/acme/lib", "v1.2.0", "acme/api"); links != (StateLinks{}) {
+		t.Errorf("expected no links without a base URL, got %+v", links)
+	}
+}
+
+func TestWebhookNotifier_SendLinksToState(t *testing.T) {
+	client := &mockHTTPClient{responses: []mockResponse{{statusCode: 200}}}
+	config := DefaultNotificationConfig()
+	config.LinkBaseURL = "https://cascade.example.com/state"
+	notifier := NewWebhookNotifier("https://example.com/webhook", client, config)
+
+	item := planner.WorkItem{
+		Module:        "github.com/acme/api",
+		Repo:          "acme/api",
+		SourceModule:  "github.com/acme/lib-b",
+		SourceVersion: "v2.0.0",
+	}
+	result := &executor.Result{Status: executor.StatusFailed, Reason: "tests failed"}
+
+	// A release set stores the item under its first module
+	ctx := WithStateRun(context.Background(), "github.com/acme/lib-a", "v1.2.0")
+	if _, err := notifier.Send(ctx, item, result); err != nil {
+		t.Fatalf("Send returned error: %v", err)
+	}
+
+	body, _ := io.ReadAll(client.requests[0].Body)
+	var payload map[string]any
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+
+	want := BuildStateLinks(config.LinkBaseURL, "github.com/acme/lib-a", "v1.2.0", "acme/api")
+	if payload["state_url"] != want.State || payload["summary_url"] != want.Summary {
+		t.Fatalf("unexpected links: state_url=%v summary_url=%v, want %+v", payload["state_url"], payload["summary_url"], want)
+	}
+	if text, _ := payload["text"].(string); !strings.Contains(text, "*State and logs:* "+want.State) {
+		t.Fatalf("expected the message to link to the item state, got:\n%s", text)
+	}
+}
+
+func TestGitHubIssueNotifier_BodyLinksToState(t *testing.T) {
+	issues := &stubGitHubIssuesService{createIssue: &github.Issue{Number: github.Int(7)}}
+	notifier := NewGitHubIssueNotifier(issues, &GitHubIssueConfig{Enabled: true}, WithIssueLinkBaseURL("https://cascade.example.com/state"))
+
+	item := planner.WorkItem{Repo: "acme/api", SourceModule: "github.com/acme/lib", SourceVersion: "v1.2.0"}
+	if _, err := notifier.Send(context.Background(), item, &executor.Result{Status: executor.StatusFailed}); err != nil {
+		t.Fatalf("Send returned error: %v", err)
+	}
+
+	want := BuildStateLinks("https://cascade.example.com/state", "github.com/acme/lib", "v1.2.0", "acme/api")
+	if len(issues.createRequests) != 1 {
+		t.Fatalf("expected one issue, got %d", len(issues.createRequests))
+	}
+	request := issues.createRequests[0]
+	body := request.GetBody()
+	if !strings.Contains(body, "**State and logs:** "+want.State) || !strings.Contains(body, "**Run summary:** "+want.Summary) {
+		t.Fatalf("expected the issue body to link to the state, got:\n%s", body)
+	}
+	if strings.Contains(request.GetTitle(), "https://") {
+		t.Fatalf("expected the issue title to stay free of links, got %q", request.GetTitle())
+	}
+}
+
+func TestNotificationsCarryAnnotations(t *testing.T) {
+	annotations := []state.Annotation{
+		{Repo: "acme/api", Note: "waiting on upstream fix", Author: "sam", Created: time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC)},
+	}
+	ctx := WithAnnotations(context.Background(), annotations)
+	item := planner.WorkItem{Module: "github.com/acme/api", Repo: "acme/api", SourceModule: "github.com/acme/lib", SourceVersion: "v1.2.0"}
+	result := &executor.Result{Status: executor.StatusFailed, Reason: "tests failed"}
+
+	client := &mockHTTPClient{responses: []mockResponse{{statusCode: 200}}}
+	webhook := NewWebhookNotifier("https://example.com/webhook", client, DefaultNotificationConfig())
+	if _, err := webhook.Send(ctx, item, result); err != nil {
+		t.Fatalf("Send returned error: %v", err)
+	}
+	body, _ := io.ReadAll(client.requests[0].Body)
+	var payload map[string]any
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if text, _ := payload["text"].(string); !strings.Contains(text, "*Note:* waiting on upstream fix — sam") {
+		t.Fatalf("expected the message to carry the note, got:\n%s", text)
+	}
+
+	issues := &stubGitHubIssuesService{createIssue: &github.Issue{Number: github.Int(7)}}
+	notifier := NewGitHubIssueNotifier(issues, &GitHubIssueConfig{Enabled: true})
+	if _, err := notifier.Send(ctx, item, result); err != nil {
+		t.Fatalf("Send returned error: %v", err)
+	}
+	if body := issues.createRequests[0].GetBody(); !strings.Contains(body, "## Operator Notes\n- waiting on upstream fix (sam, 2025-03-04)") {
+		t.Fatalf("expected the issue body to carry the note, got:\n%s", body)
+	}
+}
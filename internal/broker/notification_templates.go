@@ -0,0 +1,118 @@
+package broker
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/goliatone/cascade/internal/executor"
+)
+
+// Notification channel names used to select channel-specific templates.
+const (
+	NotificationChannelSlack   = "slack"
+	NotificationChannelWebhook = "webhook"
+)
+
+// NotificationTemplates selects notification message templates by result status and
+// by channel. Lookups fall back from the most to the least specific entry.
+type NotificationTemplates struct {
+	// Status maps a result status (completed, failed, manual-review, skipped) to a template.
+	Status map[string]string
+
+	// Channels maps a channel (slack, webhook) to templates that take precedence
+	// over Status for that channel.
+	Channels map[string]ChannelTemplates
+}
+
+// ChannelTemplates holds the templates for a single notification channel.
+type ChannelTemplates struct {
+	// Default applies to every status on the channel.
+	Default string
+
+	// Status maps a result status to a template for the channel.
+	Status map[string]string
+}
+
+// TemplateFor returns the template to render for channel and status. The first
+// non-empty value wins, in this order:
+//
+//  1. Templates.Channels[channel].Status[status]
+//  2. Templates.Channels[channel].Default
+//  3. Templates.Status[status]
+//  4. Template
+//  5. the built-in default for status
+func (c NotificationConfig) TemplateFor(channel string, status executor.Status) string {
+	if ch, ok := c.Templates.Channels[channel]; ok {
+		if tmpl := ch.Status[string(status)]; tmpl != "" {
+			return tmpl
+		}
+		if ch.Default != "" {
+			return ch.Default
+		}
+	}
+	if tmpl := c.Templates.Status[string(status)]; tmpl != "" {
+		return tmpl
+	}
+	if c.Template != "" {
+		return c.Template
+	}
+	return defaultNotificationTemplateFor(status)
+}
+
+// Validate parses every configured template so mistakes surface at startup rather
+// than when the first notification is sent.
+func (c NotificationConfig) Validate() error {
+	templates := map[string]string{"template": c.Template}
+	for status, tmpl := range c.Templates.Status {
+		templates["templates."+status] = tmpl
+	}
+	for channel, ch := range c.Templates.Channels {
+		templates["channels."+channel+".template"] = ch.Default
+		for status, tmpl := range ch.Status {
+			templates["channels."+channel+".templates."+status] = tmpl
+		}
+	}
+
+	names := make([]string, 0, len(templates))
+	for name := range templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var issues []string
+	for _, name := range names {
+		if templates[name] == "" {
+			continue
+		}
+		if _, err := template.New(name).Funcs(templateFuncMap).Parse(templates[name]); err != nil {
+			issues = append(issues, fmt.Sprintf("%s: %v", name, err))
+		}
+	}
+	if len(issues) > 0 {
+		return fmt.Errorf("invalid notification templates: %s", strings.Join(issues, "; "))
+	}
+	return nil
+}
+
+// defaultNotificationTemplateFor returns the built-in template for status: terse for
+// successful and skipped items, detailed for everything that needs attention.
+func defaultNotificationTemplateFor(status executor.Status) string {
+	switch status {
+	case executor.StatusCompleted:
+		return defaultSuccessNotificationTemplate
+	case executor.StatusSkipped:
+		return defaultSkippedNotificationTemplate
+	case executor.StatusNoChange:
+		return defaultNoChangeNotificationTemplate
+	default:
+		return defaultNotificationTemplate
+	}
+}
+
+const defaultSuccessNotificationTemplate = `✅ *{{.Module}}* updated in {{.Repo}}{{if .BranchName}} on {{.BranchName}}{{end}}{{if .CommitHash}} ({{.CommitHash | truncate8}}){{end}}`
+
+const defaultNoChangeNotificationTemplate = `➖ *{{.Module}}* already up to date in {{.Repo}}{{if .Reason}}: {{.Reason | truncate200 | escape}}{{end}}`
+
+const defaultSkippedNotificationTemplate = `⏭️ *{{.Module}}* update skipped in {{.Repo}}{{if .Reason}}: {{.Reason | truncate200 | escape}}{{end}}`
@@ -0,0 +1,118 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/internal/planner"
+)
+
+func TestNotificationConfig_TemplateFor(t *testing.T) {
+	config := NotificationConfig{
+		Template: "catch-all",
+		Templates: NotificationTemplates{
+			Status: map[string]string{
+				"failed": "status-failed",
+			},
+			Channels: map[string]ChannelTemplates{
+				NotificationChannelSlack: {
+					Status: map[string]string{"failed": "slack-failed"},
+				},
+				NotificationChannelWebhook: {
+					Default: "webhook-default",
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		channel string
+		status  executor.Status
+		want    string
+	}{
+		{NotificationChannelSlack, executor.StatusFailed, "slack-failed"},
+		{NotificationChannelSlack, executor.StatusCompleted, "catch-all"},
+		{NotificationChannelWebhook, executor.StatusFailed, "webhook-default"},
+		{"email", executor.StatusFailed, "status-failed"},
+		{"email", executor.StatusSkipped, "catch-all"},
+	}
+	for _, tt := range tests {
+		if got := config.TemplateFor(tt.channel, tt.status); got != tt.want {
+			t.Errorf("TemplateFor(%s, %s) = %q, want %q", tt.channel, tt.status, got, tt.want)
+		}
+	}
+
+	defaults := NotificationConfig{}
+	if got := defaults.TemplateFor(NotificationChannelSlack, executor.StatusSkipped); got != defaultSkippedNotificationTemplate {
+		t.Errorf("expected built-in skipped template, got %q", got)
+	}
+	if got := defaults.TemplateFor(NotificationChannelSlack, executor.StatusNoChange); got != defaultNoChangeNotificationTemplate {
+		t.Errorf("expected built-in no-change template, got %q", got)
+	}
+	if got := defaults.TemplateFor(NotificationChannelSlack, executor.StatusManualReview); got != defaultNotificationTemplate {
+		t.Errorf("expected detailed template for manual review, got %q", got)
+	}
+}
+
+func TestNotificationConfig_Validate(t *testing.T) {
+	valid := NotificationConfig{
+		Templates: NotificationTemplates{
+			Status: map[string]string{"completed": "{{.Repo | truncate8}}"},
+		},
+	}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	invalid := NotificationConfig{
+		Templates: NotificationTemplates{
+			Channels: map[string]ChannelTemplates{
+				NotificationChannelSlack: {Status: map[string]string{"failed": "{{.Repo | nope}}"}},
+			},
+		},
+	}
+	err := invalid.Validate()
+	if err == nil || !strings.Contains(err.Error(), "channels.slack.templates.failed") {
+		t.Fatalf("expected error naming the broken template, got %v", err)
+	}
+}
+
+func TestRenderNotification_DefaultSuccessIsTerse(t *testing.T) {
+	item := planner.WorkItem{Module: "example.com/module", Repo: "owner/repo", BranchName: "update-branch"}
+	message, err := RenderNotification("", item, &executor.Result{Status: executor.StatusCompleted, Reason: "All tests passed"})
+	if err != nil {
+		t.Fatalf("RenderNotification error: %v", err)
+	}
+	if strings.Contains(message, "\n") || strings.Contains(message, "All tests passed") {
+		t.Fatalf("expected a single-line success message without details, got:\n%s", message)
+	}
+}
+
+func TestWebhookNotifier_UsesChannelTemplate(t *testing.T) {
+	client := &mockHTTPClient{responses: []mockResponse{{statusCode: http.StatusOK, body: "ok"}}}
+
+	config := DefaultNotificationConfig()
+	config.Templates.Channels = map[string]ChannelTemplates{
+		NotificationChannelWebhook: {Status: map[string]string{"failed": "{{.Repo}} is {{.Status}}"}},
+		NotificationChannelSlack:   {Default: "slack only"},
+	}
+	notifier := NewWebhookNotifier("https://example.com/hook", client, config)
+
+	item := planner.WorkItem{Module: "example.com/module", Repo: "owner/repo"}
+	if _, err := notifier.Send(context.Background(), item, &executor.Result{Status: executor.StatusFailed}); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	var payload map[string]any
+	body, _ := io.ReadAll(client.requests[0].Body)
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if payload["text"] != "owner/repo is failed" {
+		t.Fatalf("text = %v, want webhook failure template", payload["text"])
+	}
+}
@@ -4,14 +4,18 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
+	"text/template"
 	"time"
 
 	"github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/internal/manifest"
 	"github.com/goliatone/cascade/internal/planner"
+	"github.com/goliatone/cascade/pkg/retry"
 	"github.com/google/go-github/v66/github"
 )
 
@@ -22,21 +26,83 @@ type Notifier interface {
 
 // NotificationConfig holds configuration for notifications.
 type NotificationConfig struct {
-	// Template for notification messages
+	// Template for notification messages on every channel and status. When empty,
+	// a built-in template is chosen per status.
 	Template string
 
+	// Templates overrides Template per status and per channel
+	Templates NotificationTemplates
+
 	// Retry configuration
 	MaxRetries int
 	RetryDelay time.Duration
 
 	// HTTP client timeout
 	Timeout time.Duration
+
+	// LinkBaseURL is the address the state directory is served from. When set,
+	// notifications link to the item's state and logs.
+	LinkBaseURL string
+}
+
+// templateData builds the template data of a notification about item, with
+// links to its state when LinkBaseURL is set and the annotations on ctx.
+func (c NotificationConfig) templateData(ctx context.Context, item planner.WorkItem, result *executor.Result) TemplateData {
+	data := buildTemplateData(item, result)
+	links := itemStateLinks(ctx, c.LinkBaseURL, item)
+	data.StateURL, data.SummaryURL = links.State, links.Summary
+	data.Annotations = annotationsFromContext(ctx)
+	return data
+}
+
+// notificationRetryJitter spreads the retries of notifications that failed
+// together, such as when a chat service had an outage.
+const notificationRetryJitter = 0.2
+
+// retryPolicy retries transient delivery failures MaxRetries times, doubling
+// RetryDelay after each retry, and bounds each attempt by Timeout.
+func (c NotificationConfig) retryPolicy() retry.Policy {
+	return retry.Policy{
+		MaxAttempts:    c.MaxRetries + 1,
+		Delay:          c.RetryDelay,
+		Jitter:         notificationRetryJitter,
+		AttemptTimeout: c.Timeout,
+		Retryable:      isTransientError,
+	}
+}
+
+// sendWithRetry delivers a notification with send under the retry policy of
+// config, reporting failures against channel.
+func sendWithRetry(ctx context.Context, config NotificationConfig, channel string, send func(ctx context.Context) (*NotificationResult, error)) (*NotificationResult, error) {
+	var (
+		result   *NotificationResult
+		attempts int
+	)
+	err := retry.Do(ctx, config.retryPolicy(), func(ctx context.Context) error {
+		attempts++
+		var err error
+		result, err = send(ctx)
+		return err
+	})
+	switch {
+	case err == nil:
+		return result, nil
+	case ctx.Err() != nil:
+		return nil, &NotificationError{
+			Channel: channel,
+			Err:     fmt.Errorf("context cancelled after %d attempts: %w (last error: %v)", attempts, ctx.Err(), err),
+		}
+	default:
+		return nil, &NotificationError{
+			Channel: channel,
+			Err:     fmt.Errorf("failed after %d attempts: %w", attempts, err),
+		}
+	}
 }
 
 // DefaultNotificationConfig returns sensible defaults.
 func DefaultNotificationConfig() NotificationConfig {
 	return NotificationConfig{
-		Template:   defaultNotificationTemplate,
 		MaxRetries: 3,
 		RetryDelay: time.Second * 2,
 		Timeout:    time.Second * 30,
@@ -71,7 +137,7 @@ func NewSlackNotifier(botToken, channel string, client HTTPClient, config Notifi
 
 // Send sends a notification to Slack.
 func (s *SlackNotifier) Send(ctx context.Context, item planner.WorkItem, result *executor.Result) (*NotificationResult, error) {
-	message, err := RenderNotification(s.config.Template, item, result)
+	message, err := renderNotification(s.config.TemplateFor(NotificationChannelSlack, resultStatus(result)), s.config.templateData(ctx, item, result))
 	if err != nil {
 		return nil, &NotificationError{
 			Channel: s.channel,
@@ -89,40 +155,23 @@ func (s *SlackNotifier) Send(ctx context.Context, item planner.WorkItem, result
 	return s.sendWithRetry(ctx, payload)
 }
 
-// sendWithRetry sends the message with retry logic.
-func (s *SlackNotifier) sendWithRetry(ctx context.Context, payload map[string]any) (*NotificationResult, error) {
-	var lastErr error
-
-	for attempt := 0; attempt <= s.config.MaxRetries; attempt++ {
-		if attempt > 0 {
-			select {
-			case <-ctx.Done():
-				return nil, &NotificationError{
-					Channel: s.channel,
-					Err:     fmt.Errorf("context cancelled after %d attempts: %w", attempt, ctx.Err()),
-				}
-			case <-time.After(s.config.RetryDelay * time.Duration(attempt)):
-				// Exponential backoff
-			}
-		}
-
-		result, err := s.sendSlackMessage(ctx, payload)
-		if err == nil {
-			return result, nil
-		}
-
-		lastErr = err
-
-		// Don't retry on non-transient errors
-		if !isTransientError(err) {
-			break
-		}
+// Alert posts an operational message to the Slack channel.
+func (s *SlackNotifier) Alert(ctx context.Context, message string) (*NotificationResult, error) {
+	payload := map[string]any{
+		"channel": s.channel,
+		"text":    message,
+		"as_user": true,
+		"mrkdwn":  true,
 	}
 
-	return nil, &NotificationError{
-		Channel: s.channel,
-		Err:     fmt.Errorf("failed after %d attempts: %w", s.config.MaxRetries+1, lastErr),
-	}
+	return s.sendWithRetry(ctx, payload)
+}
+
+// sendWithRetry sends the message under the notification retry policy.
+func (s *SlackNotifier) sendWithRetry(ctx context.Context, payload map[string]any) (*NotificationResult, error) {
+	return sendWithRetry(ctx, s.config, s.channel, func(ctx context.Context) (*NotificationResult, error) {
+		return s.sendSlackMessage(ctx, payload)
+	})
 }
 
 // sendSlackMessage sends a single message to Slack API.
@@ -177,7 +226,8 @@ func NewWebhookNotifier(url string, client HTTPClient, config NotificationConfig
 
 // Send sends a notification to the webhook endpoint.
 func (w *WebhookNotifier) Send(ctx context.Context, item planner.WorkItem, result *executor.Result) (*NotificationResult, error) {
-	message, err := RenderNotification(w.config.Template, item, result)
+	data := w.config.templateData(ctx, item, result)
+	message, err := renderNotification(w.config.TemplateFor(NotificationChannelWebhook, resultStatus(result)), data)
 	if err != nil {
 		return nil, &NotificationError{
 			Channel: w.url,
@@ -196,44 +246,30 @@ func (w *WebhookNotifier) Send(ctx context.Context, item planner.WorkItem, resul
 		"repo":   item.Repo,
 		"status": status,
 	}
+	if data.StateURL != "" {
+		payload["state_url"] = data.StateURL
+		payload["summary_url"] = data.SummaryURL
+	}
 
 	return w.sendWithRetry(ctx, payload)
 }
 
-// sendWithRetry sends the webhook with retry logic.
-func (w *WebhookNotifier) sendWithRetry(ctx context.Context, payload map[string]any) (*NotificationResult, error) {
-	var lastErr error
-
-	for attempt := 0; attempt <= w.config.MaxRetries; attempt++ {
-		if attempt > 0 {
-			select {
-			case <-ctx.Done():
-				return nil, &NotificationError{
-					Channel: w.url,
-					Err:     fmt.Errorf("context cancelled after %d attempts: %w", attempt, ctx.Err()),
-				}
-			case <-time.After(w.config.RetryDelay * time.Duration(attempt)):
-				// Exponential backoff
-			}
-		}
-
-		result, err := w.sendWebhook(ctx, payload)
-		if err == nil {
-			return result, nil
-		}
-
-		lastErr = err
-
-		// Don't retry on non-transient errors
-		if !isTransientError(err) {
-			break
-		}
+// Alert posts an operational message to the webhook endpoint. The payload carries
+// an event field instead of the work item fields sent by Send.
+func (w *WebhookNotifier) Alert(ctx context.Context, message string) (*NotificationResult, error) {
+	payload := map[string]any{
+		"text":  message,
+		"event": "alert",
 	}
 
-	return nil, &NotificationError{
-		Channel: w.url,
-		Err:     fmt.Errorf("failed after %d attempts: %w", w.config.MaxRetries+1, lastErr),
-	}
+	return w.sendWithRetry(ctx, payload)
+}
+
+// sendWithRetry sends the webhook under the notification retry policy.
+func (w *WebhookNotifier) sendWithRetry(ctx context.Context, payload map[string]any) (*NotificationResult, error) {
+	return sendWithRetry(ctx, w.config, w.url, func(ctx context.Context) (*NotificationResult, error) {
+		return w.sendWebhook(ctx, payload)
+	})
 }
 
 // sendWebhook sends a single webhook request.
@@ -272,10 +308,55 @@ type GitHubIssuesService interface {
 	ListByRepo(ctx context.Context, owner, repo string, opts *github.IssueListByRepoOptions) ([]*github.Issue, *github.Response, error)
 }
 
+// GitHubIssueUpdater is implemented by GitHubIssuesService values that can comment
+// on and reopen issues, as go-github's IssuesService does. Recurring failures are
+// only commented on or reopened through services that support it.
+type GitHubIssueUpdater interface {
+	CreateComment(ctx context.Context, owner, repo string, number int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error)
+	Edit(ctx context.Context, owner, repo string, number int, issue *github.IssueRequest) (*github.Issue, *github.Response, error)
+}
+
+// IssueResolver is implemented by notifiers that track failures in issues and can
+// close them once a later run succeeds. It is optional; callers type-assert.
+type IssueResolver interface {
+	ResolveIssue(ctx context.Context, item planner.WorkItem, number int, result *executor.Result) (*NotificationResult, error)
+}
+
 // GitHubIssueConfig captures default configuration for GitHub issue notifications.
 type GitHubIssueConfig struct {
 	Enabled bool
 	Labels  []string
+
+	// TitleTemplate and BodyTemplate replace the built-in issue templates when set.
+	TitleTemplate string
+	BodyTemplate  string
+
+	// Assignees are assigned to newly created issues.
+	Assignees []string
+
+	// OnRecurrence is one of the manifest.IssueRecurrence values and selects how
+	// a failure that already has an issue is reported.
+	OnRecurrence string
+}
+
+// Validate reports templates that fail to parse.
+func (c GitHubIssueConfig) Validate() error {
+	var issues []string
+	for _, tmpl := range []struct{ name, text string }{
+		{"title_template", c.TitleTemplate},
+		{"body_template", c.BodyTemplate},
+	} {
+		if tmpl.text == "" {
+			continue
+		}
+		if _, err := template.New(tmpl.name).Funcs(templateFuncMap).Parse(tmpl.text); err != nil {
+			issues = append(issues, fmt.Sprintf("%s: %v", tmpl.name, err))
+		}
+	}
+	if len(issues) > 0 {
+		return fmt.Errorf("invalid github issue templates: %s", strings.Join(issues, "; "))
+	}
+	return nil
 }
 
 // GitHubIssueNotifier creates or reuses GitHub issues for failure notifications.
@@ -284,30 +365,52 @@ type GitHubIssueNotifier struct {
 	defaults      *GitHubIssueConfig
 	titleTemplate string
 	bodyTemplate  string
+	linkBaseURL   string
+}
+
+// GitHubIssueNotifierOption configures a GitHubIssueNotifier.
+type GitHubIssueNotifierOption func(*GitHubIssueNotifier)
+
+// WithIssueLinkBaseURL links issue bodies to the item's state and logs in the
+// state directory served at baseURL.
+func WithIssueLinkBaseURL(baseURL string) GitHubIssueNotifierOption {
+	return func(g *GitHubIssueNotifier) {
+		g.linkBaseURL = baseURL
+	}
 }
 
 // NewGitHubIssueNotifier constructs a notifier backed by the GitHub Issues API.
-func NewGitHubIssueNotifier(issues GitHubIssuesService, defaults *GitHubIssueConfig) *GitHubIssueNotifier {
-	return &GitHubIssueNotifier{
+func NewGitHubIssueNotifier(issues GitHubIssuesService, defaults *GitHubIssueConfig, opts ...GitHubIssueNotifierOption) *GitHubIssueNotifier {
+	g := &GitHubIssueNotifier{
 		issues:        issues,
 		defaults:      cloneGitHubIssueConfig(defaults),
 		titleTemplate: defaultGitHubIssueTitleTemplate,
 		bodyTemplate:  defaultGitHubIssueBodyTemplate,
 	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(g)
+		}
+	}
+	return g
 }
 
 func cloneGitHubIssueConfig(cfg *GitHubIssueConfig) *GitHubIssueConfig {
 	if cfg == nil {
 		return nil
 	}
-	clone := &GitHubIssueConfig{Enabled: cfg.Enabled}
+	clone := *cfg
 	if len(cfg.Labels) > 0 {
 		clone.Labels = append([]string(nil), cfg.Labels...)
 	}
-	return clone
+	if len(cfg.Assignees) > 0 {
+		clone.Assignees = append([]string(nil), cfg.Assignees...)
+	}
+	return &clone
 }
 
-// Send creates a GitHub issue for failed work items when enabled by configuration.
+// Send creates a GitHub issue for failed work items when enabled by configuration
+// or selected by an escalation step, whose assignees are added to the issue.
 func (g *GitHubIssueNotifier) Send(ctx context.Context, item planner.WorkItem, result *executor.Result) (*NotificationResult, error) {
 	if g.issues == nil {
 		return nil, &NotificationError{
@@ -326,7 +429,8 @@ func (g *GitHubIssueNotifier) Send(ctx context.Context, item planner.WorkItem, r
 	}
 
 	config := g.effectiveConfig(item)
-	if !config.Enabled {
+	escalation, escalated := EscalationFromContext(ctx)
+	if !config.Enabled && !escalated {
 		return &NotificationResult{
 			Channel: channel,
 			Message: "github issue notifications disabled",
@@ -348,7 +452,16 @@ func (g *GitHubIssueNotifier) Send(ctx context.Context, item planner.WorkItem, r
 		}
 	}
 
-	title, err := RenderGitHubIssueTitle(g.titleTemplate, item, result)
+	titleTemplate := g.titleTemplate
+	if config.TitleTemplate != "" {
+		titleTemplate = config.TitleTemplate
+	}
+	bodyTemplate := g.bodyTemplate
+	if config.BodyTemplate != "" {
+		bodyTemplate = config.BodyTemplate
+	}
+
+	title, err := RenderGitHubIssueTitle(titleTemplate, item, result)
 	if err != nil {
 		return nil, &NotificationError{
 			Channel: channel,
@@ -356,7 +469,11 @@ func (g *GitHubIssueNotifier) Send(ctx context.Context, item planner.WorkItem, r
 		}
 	}
 
-	body, err := RenderGitHubIssueBody(g.bodyTemplate, item, result)
+	data := buildTemplateData(item, result)
+	links := itemStateLinks(ctx, g.linkBaseURL, item)
+	data.StateURL, data.SummaryURL = links.State, links.Summary
+	data.Annotations = annotationsFromContext(ctx)
+	body, err := renderGitHubIssueBody(bodyTemplate, data)
 	if err != nil {
 		return nil, &NotificationError{
 			Channel: channel,
@@ -369,7 +486,7 @@ func (g *GitHubIssueNotifier) Send(ctx context.Context, item planner.WorkItem, r
 		labels = []string{"cascade-failure"}
 	}
 
-	existing, err := g.findExistingIssue(ctx, owner, repo, title, labels)
+	existing, err := g.findExistingIssue(ctx, owner, repo, title, labels, config.OnRecurrence == manifest.IssueRecurrenceReopen)
 	if err != nil {
 		return nil, &NotificationError{
 			Channel: channel,
@@ -378,14 +495,7 @@ func (g *GitHubIssueNotifier) Send(ctx context.Context, item planner.WorkItem, r
 	}
 
 	if existing != nil {
-		url := existing.GetHTMLURL()
-		if url == "" {
-			url = fmt.Sprintf("https://github.com/%s/issues/%d", item.Repo, existing.GetNumber())
-		}
-		return &NotificationResult{
-			Channel: channel,
-			Message: url,
-		}, nil
+		return g.recur(ctx, channel, item.Repo, owner, repo, existing, body, config.OnRecurrence, escalation.Assignees)
 	}
 
 	labelsCopy := append([]string(nil), labels...)
@@ -396,6 +506,9 @@ func (g *GitHubIssueNotifier) Send(ctx context.Context, item planner.WorkItem, r
 	if len(labelsCopy) > 0 {
 		request.Labels = &labelsCopy
 	}
+	if assignees := mergeAssignees(config.Assignees, escalation.Assignees); len(assignees) > 0 {
+		request.Assignees = &assignees
+	}
 
 	issue, resp, err := g.issues.Create(ctx, owner, repo, request)
 	if err != nil {
@@ -416,43 +529,193 @@ func (g *GitHubIssueNotifier) Send(ctx context.Context, item planner.WorkItem, r
 	}
 
 	issueURL := ""
+	issueNumber := 0
 	if issue != nil {
-		issueURL = issue.GetHTMLURL()
-		if issueURL == "" {
-			issueURL = fmt.Sprintf("https://github.com/%s/issues/%d", item.Repo, issue.GetNumber())
+		issueURL = githubIssueURL(item.Repo, issue)
+		issueNumber = issue.GetNumber()
+	}
+
+	return &NotificationResult{
+		Channel:     channel,
+		Message:     issueURL,
+		IssueNumber: issueNumber,
+	}, nil
+}
+
+// recur reports a failure that already has an issue according to policy: the
+// issue is left alone, commented on, or reopened and commented on when closed.
+// Escalation assignees missing from the issue are added whatever the policy.
+func (g *GitHubIssueNotifier) recur(ctx context.Context, channel, fullRepo, owner, repo string, issue *github.Issue, body, policy string, escalated []string) (*NotificationResult, error) {
+	result := &NotificationResult{
+		Channel:     channel,
+		Message:     githubIssueURL(fullRepo, issue),
+		IssueNumber: issue.GetNumber(),
+	}
+
+	updater, ok := g.issues.(GitHubIssueUpdater)
+	if !ok {
+		return result, nil
+	}
+
+	current := make([]string, 0, len(issue.Assignees))
+	for _, user := range issue.Assignees {
+		current = append(current, user.GetLogin())
+	}
+	if assignees := mergeAssignees(current, escalated); len(assignees) > len(current) {
+		if _, resp, err := updater.Edit(ctx, owner, repo, issue.GetNumber(), &github.IssueRequest{Assignees: &assignees}); err != nil {
+			return nil, githubIssueError(channel, "assign issue", fullRepo, resp, err)
+		}
+	}
+
+	if policy == "" || policy == manifest.IssueRecurrenceIgnore {
+		return result, nil
+	}
+
+	if issue.GetState() == "closed" {
+		state := "open"
+		if _, resp, err := updater.Edit(ctx, owner, repo, issue.GetNumber(), &github.IssueRequest{State: &state}); err != nil {
+			return nil, githubIssueError(channel, "reopen issue", fullRepo, resp, err)
+		}
+	}
+
+	comment := &github.IssueComment{Body: &body}
+	if _, resp, err := updater.CreateComment(ctx, owner, repo, issue.GetNumber(), comment); err != nil {
+		return nil, githubIssueError(channel, "comment on issue", fullRepo, resp, err)
+	}
+	return result, nil
+}
+
+// ResolveIssue comments on and closes issue number in item's repository once the
+// failure it tracks no longer occurs. It does nothing when the issues service
+// cannot update issues.
+func (g *GitHubIssueNotifier) ResolveIssue(ctx context.Context, item planner.WorkItem, number int, result *executor.Result) (*NotificationResult, error) {
+	channel := fmt.Sprintf("github:%s", item.Repo)
+	updater, ok := g.issues.(GitHubIssueUpdater)
+	if !ok || number <= 0 {
+		return nil, nil
+	}
+
+	owner, repo, err := ParseRepoString(item.Repo)
+	if err != nil {
+		return nil, &NotificationError{
+			Channel: channel,
+			Err:     fmt.Errorf("invalid repository: %w", err),
+		}
+	}
+
+	body, err := renderTemplate("github_issue_resolved", defaultGitHubIssueResolvedTemplate, buildTemplateData(item, result))
+	if err != nil {
+		return nil, &NotificationError{
+			Channel: channel,
+			Err:     fmt.Errorf("render resolution comment: %w", err),
 		}
 	}
 
+	comment := &github.IssueComment{Body: &body}
+	if _, resp, err := updater.CreateComment(ctx, owner, repo, number, comment); err != nil {
+		return nil, githubIssueError(channel, "comment on issue", item.Repo, resp, err)
+	}
+
+	state, reason := "closed", "completed"
+	issue, resp, err := updater.Edit(ctx, owner, repo, number, &github.IssueRequest{State: &state, StateReason: &reason})
+	if err != nil {
+		return nil, githubIssueError(channel, "close issue", item.Repo, resp, err)
+	}
+	if issue == nil {
+		issue = &github.Issue{Number: &number}
+	}
+
 	return &NotificationResult{
-		Channel: channel,
-		Message: issueURL,
+		Channel:     channel,
+		Message:     githubIssueURL(item.Repo, issue),
+		IssueNumber: number,
 	}, nil
 }
 
+// githubIssueError wraps a failed GitHub Issues API call.
+func githubIssueError(channel, operation, repo string, resp *github.Response, err error) error {
+	status := 0
+	if resp != nil && resp.Response != nil {
+		status = resp.Response.StatusCode
+	}
+	return &NotificationError{
+		Channel: channel,
+		Err: &GitHubAPIError{
+			Operation:    operation,
+			Repo:         repo,
+			StatusCode:   status,
+			ResponseBody: extractGitHubResponseBody(resp),
+			Err:          err,
+		},
+	}
+}
+
+// mergeAssignees returns base followed by the extra logins it does not already
+// contain, compared case-insensitively as GitHub logins are.
+func mergeAssignees(base, extra []string) []string {
+	merged := append([]string(nil), base...)
+	for _, login := range extra {
+		found := false
+		for _, existing := range merged {
+			if strings.EqualFold(existing, login) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, login)
+		}
+	}
+	return merged
+}
+
+// githubIssueURL returns the web URL of issue in repo.
+func githubIssueURL(repo string, issue *github.Issue) string {
+	if url := issue.GetHTMLURL(); url != "" {
+		return url
+	}
+	return fmt.Sprintf("https://github.com/%s/issues/%d", repo, issue.GetNumber())
+}
+
 func (g *GitHubIssueNotifier) effectiveConfig(item planner.WorkItem) GitHubIssueConfig {
 	config := GitHubIssueConfig{}
 	if g.defaults != nil {
-		config.Enabled = g.defaults.Enabled
-		if len(g.defaults.Labels) > 0 {
-			config.Labels = append([]string(nil), g.defaults.Labels...)
-		}
+		config = *cloneGitHubIssueConfig(g.defaults)
 	}
 
-	if item.Notifications.GitHubIssues != nil {
-		config.Enabled = item.Notifications.GitHubIssues.Enabled
-		if len(item.Notifications.GitHubIssues.Labels) > 0 {
-			config.Labels = append([]string(nil), item.Notifications.GitHubIssues.Labels...)
+	if issues := item.Notifications.GitHubIssues; issues != nil {
+		config.Enabled = issues.Enabled
+		if len(issues.Labels) > 0 {
+			config.Labels = append([]string(nil), issues.Labels...)
+		}
+		if len(issues.Assignees) > 0 {
+			config.Assignees = append([]string(nil), issues.Assignees...)
+		}
+		if issues.TitleTemplate != "" {
+			config.TitleTemplate = issues.TitleTemplate
+		}
+		if issues.BodyTemplate != "" {
+			config.BodyTemplate = issues.BodyTemplate
+		}
+		if issues.OnRecurrence != "" {
+			config.OnRecurrence = issues.OnRecurrence
 		}
 	}
 
 	return config
 }
 
-func (g *GitHubIssueNotifier) findExistingIssue(ctx context.Context, owner, repo, title string, labels []string) (*github.Issue, error) {
+// findExistingIssue returns the open issue titled title. With includeClosed, the
+// most recently created closed issue is returned when none is open.
+func (g *GitHubIssueNotifier) findExistingIssue(ctx context.Context, owner, repo, title string, labels []string, includeClosed bool) (*github.Issue, error) {
 	opts := &github.IssueListByRepoOptions{
 		State:       "open",
 		ListOptions: github.ListOptions{PerPage: 50},
 	}
+	if includeClosed {
+		opts.State = "all"
+	}
+	var closed *github.Issue
 	if len(labels) > 0 {
 		opts.Labels = labels
 	}
@@ -464,9 +727,15 @@ func (g *GitHubIssueNotifier) findExistingIssue(ctx context.Context, owner, repo
 			return nil, err
 		}
 		for _, issue := range issues {
-			if strings.EqualFold(issue.GetTitle(), title) {
+			if !strings.EqualFold(issue.GetTitle(), title) {
+				continue
+			}
+			if issue.GetState() != "closed" {
 				return issue, nil
 			}
+			if closed == nil {
+				closed = issue
+			}
 		}
 
 		checkedPages++
@@ -476,7 +745,7 @@ func (g *GitHubIssueNotifier) findExistingIssue(ctx context.Context, owner, repo
 		opts.Page = resp.NextPage
 	}
 
-	return nil, nil
+	return closed, nil
 }
 
 func extractGitHubResponseBody(resp *github.Response) string {
@@ -507,6 +776,8 @@ func (m *MultiNotifier) Send(ctx context.Context, item planner.WorkItem, result
 	var errors []string
 	var firstResult *NotificationResult
 
+	issueNumber := 0
+
 	for _, notifier := range m.notifiers {
 		notifyResult, err := notifier.Send(ctx, item, result)
 		if err != nil {
@@ -517,6 +788,9 @@ func (m *MultiNotifier) Send(ctx context.Context, item planner.WorkItem, result
 		if firstResult == nil {
 			firstResult = notifyResult
 		}
+		if notifyResult != nil && issueNumber == 0 {
+			issueNumber = notifyResult.IssueNumber
+		}
 	}
 
 	if len(errors) == len(m.notifiers) {
@@ -527,10 +801,85 @@ func (m *MultiNotifier) Send(ctx context.Context, item planner.WorkItem, result
 		}
 	}
 
+	// Report the tracking issue even when another notifier answered first
+	if firstResult != nil && issueNumber != 0 && firstResult.IssueNumber == 0 {
+		merged := *firstResult
+		merged.IssueNumber = issueNumber
+		firstResult = &merged
+	}
+
 	// Return partial success (some notifiers succeeded)
 	return firstResult, nil
 }
 
+// ResolveIssue forwards issue resolution to every notifier that tracks failure issues.
+func (m *MultiNotifier) ResolveIssue(ctx context.Context, item planner.WorkItem, number int, result *executor.Result) (*NotificationResult, error) {
+	var errors []string
+	var firstResult *NotificationResult
+	attempted := 0
+
+	for _, notifier := range m.notifiers {
+		resolver, ok := notifier.(IssueResolver)
+		if !ok {
+			continue
+		}
+		attempted++
+
+		resolveResult, err := resolver.ResolveIssue(ctx, item, number, result)
+		if err != nil {
+			errors = append(errors, err.Error())
+			continue
+		}
+
+		if firstResult == nil {
+			firstResult = resolveResult
+		}
+	}
+
+	if attempted > 0 && len(errors) == attempted {
+		return nil, &NotificationError{
+			Channel: "multi",
+			Err:     fmt.Errorf("all issue resolvers failed: %s", strings.Join(errors, "; ")),
+		}
+	}
+
+	return firstResult, nil
+}
+
+// Alert forwards an operational message to every notifier that supports alerts.
+func (m *MultiNotifier) Alert(ctx context.Context, message string) (*NotificationResult, error) {
+	var errors []string
+	var firstResult *NotificationResult
+	attempted := 0
+
+	for _, notifier := range m.notifiers {
+		alerter, ok := notifier.(Alerter)
+		if !ok {
+			continue
+		}
+		attempted++
+
+		alertResult, err := alerter.Alert(ctx, message)
+		if err != nil {
+			errors = append(errors, err.Error())
+			continue
+		}
+
+		if firstResult == nil {
+			firstResult = alertResult
+		}
+	}
+
+	if attempted > 0 && len(errors) == attempted {
+		return nil, &NotificationError{
+			Channel: "multi",
+			Err:     fmt.Errorf("all alerters failed: %s", strings.Join(errors, "; ")),
+		}
+	}
+
+	return firstResult, nil
+}
+
 // NoOpNotifier is a notifier that records notification intent but doesn't
 // send actual notifications. Used when notification integrations are not configured.
 type NoOpNotifier struct{}
@@ -556,6 +905,11 @@ func isTransientError(err error) bool {
 		return false
 	}
 
+	// an attempt that ran out of its own time may succeed on the next one
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
 	errStr := err.Error()
 	return strings.Contains(errStr, "timeout") ||
 		strings.Contains(errStr, "connection") ||
@@ -574,10 +928,17 @@ const defaultNotificationTemplate = `{{if eq .Status "completed"}}✅{{else if e
 {{if .FailureSummary}}*Failing Test:* {{.FailureSummary | escape}}{{end}}
 {{if .FailureMessage}}*Failure:* {{.FailureMessage | truncate200 | escape}}{{end}}
 {{if .FailureCommand}}*Command:* {{.FailureCommand | escape}}{{end}}
-{{if .DependencySummary}}*Dependency:* {{.DependencySummary | escape}}{{if .DependencyNote}} — {{.DependencyNote | truncate200 | escape}}{{end}}{{end}}
+{{if .DependencySummary}}*Dependency:* {{.DependencySummary | escape}}{{if .DependencyNote}} — {{.DependencyNote | truncate200 | escape}}{{end}}{{end}}{{if .StateURL}}
+*State and logs:* {{.StateURL}}
+*Run summary:* {{.SummaryURL}}{{end}}{{range .Annotations}}
+*Note:* {{.Note | truncate200 | escape}}{{if .Author}} — {{.Author | escape}}{{end}}{{end}}
 
 Generated at {{.Timestamp.Format "15:04:05 MST"}}`
 
+const defaultGitHubIssueResolvedTemplate = `Cascade updated *{{.SourceModule}}*{{if .SourceVersion}} to *{{.SourceVersion}}*{{end}} in *{{.Repo}}* successfully{{if .CommitHash}} (commit {{.CommitHash | truncate8}}){{end}}, so this failure is resolved. Closing.
+
+_Reported by Cascade at {{.Timestamp.Format "2006-01-02 15:04:05 MST"}}._`
+
 const defaultGitHubIssueTitleTemplate = `Cascade failure: update {{.SourceModule}}{{if .SourceVersion}} to {{.SourceVersion}}{{end}} in {{.Repo}}`
 
 const defaultGitHubIssueBodyTemplate = `Cascade failed to update *{{.SourceModule}}*{{if .SourceVersion}} to *{{.SourceVersion}}*{{end}} for repository *{{.Repo}}*.
@@ -585,7 +946,9 @@ const defaultGitHubIssueBodyTemplate = `Cascade failed to update *{{.SourceModul
 - **Status:** {{.Status}}
 {{if .BranchName}}- **Branch:** {{.BranchName}}{{end}}
 {{if .CommitHash}}- **Commit:** {{.CommitHash | truncate8}}{{end}}
-{{if .ModulePath}}- **Module Path:** {{.ModulePath}}{{end}}
+{{if .ModulePath}}- **Module Path:** {{.ModulePath}}{{end}}{{if .StateURL}}
+- **State and logs:** {{.StateURL}}
+- **Run summary:** {{.SummaryURL}}{{end}}
 
 {{if .Reason}}## Failure Reason
 {{.Reason | escape}}
@@ -604,35 +967,49 @@ const defaultGitHubIssueBodyTemplate = `Cascade failed to update *{{.SourceModul
 {{if .DependencySummary}}## Dependency Impact
 {{.DependencySummary}}
 {{end}}
+{{if .Annotations}}## Operator Notes
+{{range .Annotations}}- {{.Note | escape}}{{if .Author}} ({{.Author | escape}}, {{.Created.Format "2006-01-02"}}){{else}} ({{.Created.Format "2006-01-02"}}){{end}}
+{{end}}{{end}}
 
 _Reported by Cascade at {{.Timestamp.Format "2006-01-02 15:04:05 MST"}}._`
 
-// RenderNotification renders a notification message from a template.
+// RenderNotification renders a notification message from a template. An empty
+// template selects the built-in default for the result status.
 func RenderNotification(tmpl string, item planner.WorkItem, result *executor.Result) (string, error) {
+	return renderNotification(tmpl, buildTemplateData(item, result))
+}
+
+func renderNotification(tmpl string, data TemplateData) (string, error) {
 	if tmpl == "" {
-		tmpl = defaultNotificationTemplate
+		tmpl = defaultNotificationTemplateFor(executor.Status(data.Status))
 	}
-
-	data := buildTemplateData(item, result)
 	return renderTemplate("notification", tmpl, data)
 }
 
+func resultStatus(result *executor.Result) executor.Status {
+	if result == nil {
+		return ""
+	}
+	return result.Status
+}
+
 // RenderGitHubIssueTitle renders a GitHub issue title using the provided or default template.
 func RenderGitHubIssueTitle(tmpl string, item planner.WorkItem, result *executor.Result) (string, error) {
 	if tmpl == "" {
 		tmpl = defaultGitHubIssueTitleTemplate
 	}
 
-	data := buildTemplateData(item, result)
-	return renderTemplate("github_issue_title", tmpl, data)
+	return renderTemplate("github_issue_title", tmpl, buildTemplateData(item, result))
 }
 
 // RenderGitHubIssueBody renders a GitHub issue body using the provided or default template.
 func RenderGitHubIssueBody(tmpl string, item planner.WorkItem, result *executor.Result) (string, error) {
+	return renderGitHubIssueBody(tmpl, buildTemplateData(item, result))
+}
+
+func renderGitHubIssueBody(tmpl string, data TemplateData) (string, error) {
 	if tmpl == "" {
 		tmpl = defaultGitHubIssueBodyTemplate
 	}
-
-	data := buildTemplateData(item, result)
 	return renderTemplate("github_issue_body", tmpl, data)
 }
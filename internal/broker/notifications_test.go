@@ -14,6 +14,7 @@ import (
 	"github.com/goliatone/cascade/internal/executor"
 	"github.com/goliatone/cascade/internal/manifest"
 	"github.com/goliatone/cascade/internal/planner"
+	"github.com/goliatone/cascade/pkg/templatefuncs"
 	"github.com/google/go-github/v66/github"
 )
 
@@ -63,6 +64,9 @@ type stubGitHubIssuesService struct {
 	listIssues     [][]*github.Issue
 	listErr        error
 	listCalls      int
+	listOptions    []github.IssueListByRepoOptions
+	comments       map[int][]string
+	edits          map[int]*github.IssueRequest
 }
 
 func (s *stubGitHubIssuesService) Create(_ context.Context, _, _ string, issue *github.IssueRequest) (*github.Issue, *github.Response, error) {
@@ -78,8 +82,11 @@ func (s *stubGitHubIssuesService) Create(_ context.Context, _, _ string, issue *
 	return s.createIssue, &github.Response{}, nil
 }
 
-func (s *stubGitHubIssuesService) ListByRepo(_ context.Context, _, _ string, _ *github.IssueListByRepoOptions) ([]*github.Issue, *github.Response, error) {
+func (s *stubGitHubIssuesService) ListByRepo(_ context.Context, _, _ string, opts *github.IssueListByRepoOptions) ([]*github.Issue, *github.Response, error) {
 	s.listCalls++
+	if opts != nil {
+		s.listOptions = append(s.listOptions, *opts)
+	}
 	if s.listErr != nil {
 		return nil, &github.Response{}, s.listErr
 	}
@@ -93,6 +100,22 @@ func (s *stubGitHubIssuesService) ListByRepo(_ context.Context, _, _ string, _ *
 	return s.listIssues[index], &github.Response{}, nil
 }
 
+func (s *stubGitHubIssuesService) CreateComment(_ context.Context, _, _ string, number int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
+	if s.comments == nil {
+		s.comments = make(map[int][]string)
+	}
+	s.comments[number] = append(s.comments[number], comment.GetBody())
+	return comment, &github.Response{}, nil
+}
+
+func (s *stubGitHubIssuesService) Edit(_ context.Context, _, _ string, number int, issue *github.IssueRequest) (*github.Issue, *github.Response, error) {
+	if s.edits == nil {
+		s.edits = make(map[int]*github.IssueRequest)
+	}
+	s.edits[number] = issue
+	return &github.Issue{Number: github.Int(number)}, &github.Response{}, nil
+}
+
 func TestSlackNotifier_Send_Success(t *testing.T) {
 	client := &mockHTTPClient{
 		responses: []mockResponse{
@@ -481,6 +504,50 @@ func TestMultiNotifier_Send_PartialSuccess(t *testing.T) {
 	}
 }
 
+func TestNotifiers_Alert(t *testing.T) {
+	slackClient := &mockHTTPClient{responses: []mockResponse{{statusCode: 200, body: `{"ok": true}`}}}
+	webhookClient := &mockHTTPClient{responses: []mockResponse{{statusCode: 200, body: `{"status": "ok"}`}}}
+
+	config := DefaultNotificationConfig()
+	config.MaxRetries = 0
+
+	multi := NewMultiNotifier(
+		NewSlackNotifier("bot-token", "#ops", slackClient, config),
+		NewGitHubIssueNotifier(&stubGitHubIssuesService{}, nil),
+		NewWebhookNotifier("https://example.com/webhook", webhookClient, config),
+	)
+
+	result, err := multi.Alert(context.Background(), "quota warning")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if result == nil || result.Channel != "#ops" {
+		t.Fatalf("Expected Slack result first, got %+v", result)
+	}
+
+	if len(slackClient.requests) != 1 || len(webhookClient.requests) != 1 {
+		t.Fatalf("Expected one request per alerter, got slack=%d webhook=%d", len(slackClient.requests), len(webhookClient.requests))
+	}
+
+	body, _ := io.ReadAll(webhookClient.requests[0].Body)
+	if !strings.Contains(string(body), `"event":"alert"`) || !strings.Contains(string(body), "quota warning") {
+		t.Errorf("Expected alert payload, got %s", body)
+	}
+}
+
+func TestMultiNotifier_Alert_AllFail(t *testing.T) {
+	config := DefaultNotificationConfig()
+	config.MaxRetries = 0
+
+	multi := NewMultiNotifier(
+		NewWebhookNotifier("https://example.com/webhook", &mockHTTPClient{responses: []mockResponse{{statusCode: 400}}}, config),
+	)
+
+	if _, err := multi.Alert(context.Background(), "quota warning"); err == nil {
+		t.Fatal("Expected error when every alerter fails")
+	}
+}
+
 func TestMultiNotifier_Send_AllFail(t *testing.T) {
 	failClient1 := &mockHTTPClient{
 		responses: []mockResponse{
@@ -644,11 +711,11 @@ FAIL    example.com/app    0.123s
 		t.Fatalf("RenderNotification error: %v", err)
 	}
 
-	if !strings.Contains(message, "*Failing Test:* "+escapeMarkdown("TestExample (example.com/app)")) {
+	if !strings.Contains(message, "*Failing Test:* "+templatefuncs.EscapeMarkdown("TestExample (example.com/app)")) {
 		t.Fatalf("expected failing test summary in message, got:\n%s", message)
 	}
 
-	if !strings.Contains(message, "*Failure:* "+escapeMarkdown("example_test.go:12: unexpected response code")) {
+	if !strings.Contains(message, "*Failure:* "+templatefuncs.EscapeMarkdown("example_test.go:12: unexpected response code")) {
 		t.Fatalf("expected failure details in message, got:\n%s", message)
 	}
 
@@ -656,7 +723,7 @@ FAIL    example.com/app    0.123s
 		t.Fatalf("expected failing command in message, got:\n%s", message)
 	}
 
-	if !strings.Contains(message, "*Dependency:* "+escapeMarkdown("example.com/pkg -> v1.2.0 (was v1.1.0)")) {
+	if !strings.Contains(message, "*Dependency:* "+templatefuncs.EscapeMarkdown("example.com/pkg -> v1.2.0 (was v1.1.0)")) {
 		t.Fatalf("expected dependency summary in message, got:\n%s", message)
 	}
 }
@@ -710,8 +777,16 @@ func TestIsTransientError(t *testing.T) {
 func TestNotificationConfig_Defaults(t *testing.T) {
 	config := DefaultNotificationConfig()
 
-	if config.Template != defaultNotificationTemplate {
-		t.Error("Expected default notification template")
+	if config.Template != "" {
+		t.Error("Expected no catch-all template so per-status defaults apply")
+	}
+
+	if got := config.TemplateFor(NotificationChannelSlack, executor.StatusFailed); got != defaultNotificationTemplate {
+		t.Error("Expected detailed default template for failures")
+	}
+
+	if got := config.TemplateFor(NotificationChannelSlack, executor.StatusCompleted); got != defaultSuccessNotificationTemplate {
+		t.Error("Expected terse default template for successes")
 	}
 
 	if config.MaxRetries != 3 {
@@ -799,3 +874,230 @@ func TestNoOpNotifier_Send_WithNilResult(t *testing.T) {
 		t.Errorf("Expected channel 'noop', got '%s'", notificationResult.Channel)
 	}
 }
+
+func TestGitHubIssueNotifier_TemplatesAndAssignees(t *testing.T) {
+	service := &stubGitHubIssuesService{createIssue: &github.Issue{Number: github.Int(9)}}
+	notifier := NewGitHubIssueNotifier(service, &GitHubIssueConfig{
+		Enabled:       true,
+		TitleTemplate: "Cascade: {{.Repo}} failed on {{.SourceVersion}}",
+		BodyTemplate:  "default body",
+		Assignees:     []string{"platform-oncall"},
+	})
+
+	item := planner.WorkItem{
+		Repo:          "owner/repo",
+		SourceModule:  "example.com/module",
+		SourceVersion: "v1.2.3",
+		Notifications: manifest.Notifications{
+			GitHubIssues: &manifest.GitHubIssueNotification{Enabled: true, BodyTemplate: "{{.Reason}}"},
+		},
+	}
+	result := &executor.Result{Status: executor.StatusFailed, Reason: "tests failed"}
+
+	if _, err := notifier.Send(context.Background(), item, result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(service.createRequests) != 1 {
+		t.Fatalf("expected 1 create request, got %d", len(service.createRequests))
+	}
+	request := service.createRequests[0]
+	if got := stringPtrValue(request.Title); got != "Cascade: owner/repo failed on v1.2.3" {
+		t.Errorf("title = %q", got)
+	}
+	if got := stringPtrValue(request.Body); got != "tests failed" {
+		t.Errorf("body = %q, want dependent template output", got)
+	}
+	if request.Assignees == nil || len(*request.Assignees) != 1 || (*request.Assignees)[0] != "platform-oncall" {
+		t.Errorf("assignees = %v", request.Assignees)
+	}
+}
+
+func TestGitHubIssueNotifier_Recurrence(t *testing.T) {
+	item := planner.WorkItem{Repo: "owner/repo", SourceModule: "example.com/module", SourceVersion: "v1.2.3"}
+	result := &executor.Result{Status: executor.StatusFailed, Reason: "build failed"}
+	title, err := RenderGitHubIssueTitle("", item, result)
+	if err != nil {
+		t.Fatalf("render title failed: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		policy      string
+		issue       *github.Issue
+		wantState   string
+		wantComment bool
+		wantReopen  bool
+		wantCreate  bool
+	}{
+		{
+			name:      "ignore leaves open issue alone",
+			policy:    manifest.IssueRecurrenceIgnore,
+			issue:     &github.Issue{Title: github.String(title), Number: github.Int(4), State: github.String("open")},
+			wantState: "open",
+		},
+		{
+			name:        "comment on open issue",
+			policy:      manifest.IssueRecurrenceComment,
+			issue:       &github.Issue{Title: github.String(title), Number: github.Int(4), State: github.String("open")},
+			wantState:   "open",
+			wantComment: true,
+		},
+		{
+			name:        "reopen closed issue",
+			policy:      manifest.IssueRecurrenceReopen,
+			issue:       &github.Issue{Title: github.String(title), Number: github.Int(4), State: github.String("closed")},
+			wantState:   "all",
+			wantComment: true,
+			wantReopen:  true,
+		},
+		{
+			name:       "comment ignores closed issues",
+			policy:     manifest.IssueRecurrenceComment,
+			wantState:  "open",
+			wantCreate: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &stubGitHubIssuesService{createIssue: &github.Issue{Number: github.Int(5)}}
+			if tt.issue != nil {
+				service.listIssues = [][]*github.Issue{{tt.issue}}
+			}
+			notifier := NewGitHubIssueNotifier(service, &GitHubIssueConfig{Enabled: true, OnRecurrence: tt.policy})
+
+			notification, err := notifier.Send(context.Background(), item, result)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if notification.Message == "" {
+				t.Fatal("expected issue URL")
+			}
+			if got := service.listOptions[0].State; got != tt.wantState {
+				t.Errorf("list state = %q, want %q", got, tt.wantState)
+			}
+			if got := len(service.comments[4]) == 1; got != tt.wantComment {
+				t.Errorf("commented = %v, want %v", got, tt.wantComment)
+			}
+			if tt.wantComment && !strings.Contains(service.comments[4][0], "build failed") {
+				t.Errorf("comment %q does not contain the rendered body", service.comments[4][0])
+			}
+			reopened := service.edits[4] != nil && service.edits[4].GetState() == "open"
+			if reopened != tt.wantReopen {
+				t.Errorf("reopened = %v, want %v", reopened, tt.wantReopen)
+			}
+			if got := len(service.createRequests) == 1; got != tt.wantCreate {
+				t.Errorf("created = %v, want %v", got, tt.wantCreate)
+			}
+		})
+	}
+}
+
+func TestGitHubIssueNotifier_Escalation(t *testing.T) {
+	item := planner.WorkItem{Repo: "owner/repo", SourceModule: "example.com/module", SourceVersion: "v1.2.3"}
+	result := &executor.Result{Status: executor.StatusFailed, Reason: "build failed"}
+	ctx := withEscalation(context.Background(), Escalation{Attempt: 3, Assignees: []string{"team-lead"}})
+
+	t.Run("creates issue when selected although disabled", func(t *testing.T) {
+		service := &stubGitHubIssuesService{createIssue: &github.Issue{Number: github.Int(5)}}
+		notifier := NewGitHubIssueNotifier(service, &GitHubIssueConfig{Assignees: []string{"platform-oncall"}})
+
+		if _, err := notifier.Send(ctx, item, result); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(service.createRequests) != 1 {
+			t.Fatalf("expected 1 create request, got %d", len(service.createRequests))
+		}
+		assignees := service.createRequests[0].Assignees
+		if assignees == nil || strings.Join(*assignees, ",") != "platform-oncall,team-lead" {
+			t.Errorf("assignees = %v, want platform-oncall,team-lead", assignees)
+		}
+	})
+
+	t.Run("assigns existing issue", func(t *testing.T) {
+		title, err := RenderGitHubIssueTitle("", item, result)
+		if err != nil {
+			t.Fatalf("render title failed: %v", err)
+		}
+		existing := &github.Issue{
+			Title:     github.String(title),
+			Number:    github.Int(4),
+			State:     github.String("open"),
+			Assignees: []*github.User{{Login: github.String("platform-oncall")}},
+		}
+		service := &stubGitHubIssuesService{listIssues: [][]*github.Issue{{existing}}}
+		notifier := NewGitHubIssueNotifier(service, &GitHubIssueConfig{Enabled: true})
+
+		if _, err := notifier.Send(ctx, item, result); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		edit := service.edits[4]
+		if edit == nil || edit.Assignees == nil || strings.Join(*edit.Assignees, ",") != "platform-oncall,team-lead" {
+			t.Fatalf("edit = %+v, want team-lead added", edit)
+		}
+
+		// A lead who is already assigned is left alone
+		existing.Assignees = append(existing.Assignees, &github.User{Login: github.String("Team-Lead")})
+		service.edits = nil
+		if _, err := notifier.Send(ctx, item, result); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(service.edits) != 0 {
+			t.Errorf("edits = %+v, want none", service.edits)
+		}
+	})
+}
+
+func TestGitHubIssueConfig_Validate(t *testing.T) {
+	if err := (GitHubIssueConfig{TitleTemplate: "{{.Repo}}", BodyTemplate: "{{.Reason | truncate200}}"}).Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err := GitHubIssueConfig{BodyTemplate: "{{.Repo"}.Validate()
+	if err == nil || !strings.Contains(err.Error(), "body_template") {
+		t.Fatalf("expected body_template error, got %v", err)
+	}
+}
+
+func TestGitHubIssueNotifier_ResolveIssue(t *testing.T) {
+	service := &stubGitHubIssuesService{}
+	notifier := NewGitHubIssueNotifier(service, &GitHubIssueConfig{Enabled: true})
+	item := planner.WorkItem{Repo: "owner/repo", SourceModule: "example.com/module", SourceVersion: "v1.2.4"}
+	result := &executor.Result{Status: executor.StatusCompleted, CommitHash: "abcdef1234567"}
+
+	resolved, err := notifier.ResolveIssue(context.Background(), item, 42, result)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resolved == nil || resolved.IssueNumber != 42 {
+		t.Fatalf("unexpected result: %+v", resolved)
+	}
+	if len(service.comments[42]) != 1 || !strings.Contains(service.comments[42][0], "v1.2.4") {
+		t.Fatalf("expected resolution comment, got %v", service.comments[42])
+	}
+	edit := service.edits[42]
+	if edit == nil || edit.GetState() != "closed" || edit.GetStateReason() != "completed" {
+		t.Fatalf("expected issue to be closed as completed, got %+v", edit)
+	}
+}
+
+func TestGitHubIssueNotifier_ReportsIssueNumber(t *testing.T) {
+	service := &stubGitHubIssuesService{createIssue: &github.Issue{Number: github.Int(17)}}
+	issues := NewGitHubIssueNotifier(service, &GitHubIssueConfig{Enabled: true})
+	multi := NewMultiNotifier(NewNoOpNotifier(), issues)
+
+	item := planner.WorkItem{Repo: "owner/repo", SourceModule: "example.com/module"}
+	result := &executor.Result{Status: executor.StatusFailed, Reason: "tests failed"}
+
+	notification, err := multi.Send(context.Background(), item, result)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if notification == nil || notification.Channel != "noop" || notification.IssueNumber != 17 {
+		t.Fatalf("expected first result with the issue number, got %+v", notification)
+	}
+
+	resolved, err := multi.ResolveIssue(context.Background(), item, 17, &executor.Result{Status: executor.StatusCompleted})
+	if err != nil || resolved == nil || resolved.IssueNumber != 17 {
+		t.Fatalf("ResolveIssue() = %+v, %v", resolved, err)
+	}
+}
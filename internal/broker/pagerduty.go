@@ -0,0 +1,171 @@
+package broker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/internal/planner"
+	"github.com/goliatone/cascade/pkg/templatefuncs"
+)
+
+// DefaultPagerDutyEndpoint is the PagerDuty Events API v2 enqueue endpoint.
+const DefaultPagerDutyEndpoint = "https://events.pagerduty.com/v2/enqueue"
+
+// pagerDutySummaryLimit is the longest summary the Events API accepts.
+const pagerDutySummaryLimit = 1024
+
+// PagerDutyConfig configures PagerDuty incident notifications.
+type PagerDutyConfig struct {
+	// RoutingKey is the integration key of the PagerDuty service.
+	RoutingKey string
+
+	// Endpoint overrides DefaultPagerDutyEndpoint.
+	Endpoint string
+
+	// Severity is one of critical, error, warning, or info. Default: error.
+	Severity string
+}
+
+// PagerDutyNotifier triggers PagerDuty incidents for failed work items through the
+// Events API v2. Repeated failures of a dependent share a dedup key, so they are
+// grouped into one incident.
+type PagerDutyNotifier struct {
+	routingKey string
+	endpoint   string
+	severity   string
+	client     HTTPClient
+	config     NotificationConfig
+}
+
+// NewPagerDutyNotifier creates a new PagerDuty notifier.
+func NewPagerDutyNotifier(pd PagerDutyConfig, client HTTPClient, config NotificationConfig) *PagerDutyNotifier {
+	if client == nil {
+		client = &http.Client{Timeout: config.Timeout}
+	}
+	endpoint := strings.TrimSpace(pd.Endpoint)
+	if endpoint == "" {
+		endpoint = DefaultPagerDutyEndpoint
+	}
+	severity := strings.TrimSpace(pd.Severity)
+	if severity == "" {
+		severity = "error"
+	}
+	return &PagerDutyNotifier{
+		routingKey: pd.RoutingKey,
+		endpoint:   endpoint,
+		severity:   severity,
+		client:     client,
+		config:     config,
+	}
+}
+
+// Send triggers an incident for failed work items. Other results are not paged.
+func (p *PagerDutyNotifier) Send(ctx context.Context, item planner.WorkItem, result *executor.Result) (*NotificationResult, error) {
+	if result == nil || result.Status != executor.StatusFailed {
+		return &NotificationResult{
+			Channel: NotificationChannelPagerDuty,
+			Message: "no failure detected",
+		}, nil
+	}
+
+	summary := fmt.Sprintf("Cascade failed to update %s", item.SourceModule)
+	if item.SourceVersion != "" {
+		summary += " to " + item.SourceVersion
+	}
+	summary += " in " + item.Repo
+	if result.Reason != "" {
+		summary += ": " + result.Reason
+	}
+	summary = templatefuncs.Truncate(summary, pagerDutySummaryLimit)
+
+	details := map[string]any{
+		"module":         item.Module,
+		"source_module":  item.SourceModule,
+		"source_version": item.SourceVersion,
+		"branch":         item.BranchName,
+		"reason":         result.Reason,
+	}
+	if escalation, ok := EscalationFromContext(ctx); ok {
+		details["attempt"] = escalation.Attempt
+		if len(escalation.Assignees) > 0 {
+			details["assignees"] = escalation.Assignees
+		}
+	} else if attempt := AttemptFromContext(ctx); attempt > 0 {
+		details["attempt"] = attempt
+	}
+
+	payload := map[string]any{
+		"routing_key":  p.routingKey,
+		"event_action": "trigger",
+		"dedup_key":    p.dedupKey(item),
+		"payload": map[string]any{
+			"summary":        summary,
+			"source":         item.Repo,
+			"severity":       p.severity,
+			"component":      item.Module,
+			"group":          item.SourceModule,
+			"custom_details": details,
+		},
+	}
+	if links := itemStateLinks(ctx, p.config.LinkBaseURL, item); links.State != "" {
+		payload["links"] = []map[string]string{
+			{"href": links.State, "text": "Cascade state and logs"},
+			{"href": links.Summary, "text": "Cascade run summary"},
+		}
+	}
+
+	notificationResult, err := p.sendWithRetry(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+	notificationResult.Message = summary
+	return notificationResult, nil
+}
+
+// dedupKey groups every failure of item's dependent for the same source version.
+func (p *PagerDutyNotifier) dedupKey(item planner.WorkItem) string {
+	return fmt.Sprintf("cascade/%s@%s/%s", item.SourceModule, item.SourceVersion, item.Repo)
+}
+
+// sendWithRetry sends the event under the notification retry policy.
+func (p *PagerDutyNotifier) sendWithRetry(ctx context.Context, payload map[string]any) (*NotificationResult, error) {
+	return sendWithRetry(ctx, p.config, NotificationChannelPagerDuty, func(ctx context.Context) (*NotificationResult, error) {
+		return p.sendEvent(ctx, payload)
+	})
+}
+
+// sendEvent sends a single event to the Events API.
+func (p *PagerDutyNotifier) sendEvent(ctx context.Context, payload map[string]any) (*NotificationResult, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", p.endpoint, bytes.NewBuffer(data))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// The Events API answers 202 Accepted; 429 means the event was rate limited
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, fmt.Errorf("pagerduty events API temporary error: status %d", resp.StatusCode)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("pagerduty events API error: status %d", resp.StatusCode)
+	}
+
+	return &NotificationResult{Channel: NotificationChannelPagerDuty}, nil
+}
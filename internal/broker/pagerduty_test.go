@@ -0,0 +1,110 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/internal/planner"
+)
+
+func TestPagerDutyNotifier_TriggersFailures(t *testing.T) {
+	client := &mockHTTPClient{responses: []mockResponse{{statusCode: 202, body: `{"status":"success"}`}}}
+	notifier := NewPagerDutyNotifier(PagerDutyConfig{RoutingKey: "routing-key"}, client, DefaultNotificationConfig())
+
+	item := planner.WorkItem{
+		Repo:          "owner/repo",
+		Module:        "github.com/owner/repo",
+		SourceModule:  "github.com/example/lib",
+		SourceVersion: "v1.2.3",
+	}
+	ctx := withEscalation(context.Background(), Escalation{Attempt: 3, Assignees: []string{"team-lead"}})
+
+	result, err := notifier.Send(ctx, item, &executor.Result{Status: executor.StatusFailed, Reason: "tests failed"})
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if result.Channel != NotificationChannelPagerDuty {
+		t.Errorf("channel = %q, want %q", result.Channel, NotificationChannelPagerDuty)
+	}
+	if !strings.Contains(result.Message, "tests failed") {
+		t.Errorf("message %q does not contain the reason", result.Message)
+	}
+
+	if len(client.requests) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(client.requests))
+	}
+	req := client.requests[0]
+	if req.URL.String() != DefaultPagerDutyEndpoint {
+		t.Errorf("endpoint = %q, want %q", req.URL.String(), DefaultPagerDutyEndpoint)
+	}
+
+	body, _ := io.ReadAll(req.Body)
+	var event struct {
+		RoutingKey  string `json:"routing_key"`
+		EventAction string `json:"event_action"`
+		DedupKey    string `json:"dedup_key"`
+		Payload     struct {
+			Summary       string         `json:"summary"`
+			Source        string         `json:"source"`
+			Severity      string         `json:"severity"`
+			CustomDetails map[string]any `json:"custom_details"`
+		} `json:"payload"`
+	}
+	if err := json.Unmarshal(body, &event); err != nil {
+		t.Fatalf("decode event: %v", err)
+	}
+	if event.RoutingKey != "routing-key" || event.EventAction != "trigger" {
+		t.Errorf("event = %+v, want trigger with routing key", event)
+	}
+	if event.DedupKey != "cascade/github.com/example/lib@v1.2.3/owner/repo" {
+		t.Errorf("dedup key = %q", event.DedupKey)
+	}
+	if event.Payload.Source != "owner/repo" || event.Payload.Severity != "error" {
+		t.Errorf("payload = %+v, want source owner/repo and default severity", event.Payload)
+	}
+	if got := event.Payload.CustomDetails["attempt"]; got != float64(3) {
+		t.Errorf("attempt detail = %v, want 3", got)
+	}
+	if got, ok := event.Payload.CustomDetails["assignees"].([]any); !ok || len(got) != 1 || got[0] != "team-lead" {
+		t.Errorf("assignees detail = %v, want [team-lead]", event.Payload.CustomDetails["assignees"])
+	}
+}
+
+func TestPagerDutyNotifier_SkipsNonFailures(t *testing.T) {
+	client := &mockHTTPClient{}
+	notifier := NewPagerDutyNotifier(PagerDutyConfig{RoutingKey: "routing-key"}, client, DefaultNotificationConfig())
+
+	result, err := notifier.Send(context.Background(), planner.WorkItem{Repo: "owner/repo"}, &executor.Result{Status: executor.StatusCompleted})
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if result.Message != "no failure detected" {
+		t.Errorf("message = %q, want no failure detected", result.Message)
+	}
+	if len(client.requests) != 0 {
+		t.Errorf("expected no requests, got %d", len(client.requests))
+	}
+}
+
+func TestPagerDutyNotifier_RejectedEvent(t *testing.T) {
+	client := &mockHTTPClient{responses: []mockResponse{{statusCode: 400, body: `{"status":"invalid event"}`}}}
+	cfg := DefaultNotificationConfig()
+	cfg.RetryDelay = 0
+	notifier := NewPagerDutyNotifier(PagerDutyConfig{RoutingKey: "bad", Severity: "critical"}, client, cfg)
+
+	_, err := notifier.Send(context.Background(), planner.WorkItem{Repo: "owner/repo"}, &executor.Result{Status: executor.StatusFailed})
+	if err == nil {
+		t.Fatal("expected error for rejected event")
+	}
+	var notificationErr *NotificationError
+	if !ErrorAs(err, &notificationErr) || notificationErr.Channel != NotificationChannelPagerDuty {
+		t.Errorf("error = %v, want NotificationError for pagerduty", err)
+	}
+	if len(client.requests) != 1 {
+		t.Errorf("expected no retries for a rejected event, got %d requests", len(client.requests))
+	}
+}
@@ -0,0 +1,171 @@
+package broker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// PlanSummary describes the outcome of planning a release without executing it.
+type PlanSummary struct {
+	// Module and Version identify the release that was planned
+	Module  string
+	Version string
+
+	// Updates lists the repositories that need to be updated
+	Updates []string
+
+	// SkippedUpToDate lists repositories already on the target version
+	SkippedUpToDate []string
+
+	// SkippedArchived lists repositories skipped because they are archived
+	SkippedArchived []string
+
+	// SkippedLocal lists repositories skipped because they build against a local
+	// copy of the module through a replace directive or go.work file
+	SkippedLocal []string
+
+	// CheckErrors is the number of dependents whose version check failed; they
+	// are included in Updates for safety
+	CheckErrors int
+
+	// EstimatedDuration is the expected runtime of executing the plan. Zero when
+	// no timing history is available.
+	EstimatedDuration time.Duration
+
+	// Timestamp records when the plan was generated
+	Timestamp time.Time
+}
+
+// Skipped returns the number of dependents that need no update.
+func (s PlanSummary) Skipped() int {
+	return len(s.SkippedUpToDate) + len(s.SkippedArchived) + len(s.SkippedLocal)
+}
+
+// PlanNotifier is implemented by notifiers that can deliver a plan summary. It is
+// optional; callers should type-assert before use.
+type PlanNotifier interface {
+	SendPlan(ctx context.Context, summary PlanSummary) (*NotificationResult, error)
+}
+
+const defaultPlanSummaryTemplate = `📋 *{{.Module}}@{{.Version}}* plan: {{len .Updates}} {{if eq (len .Updates) 1}}repository needs{{else}}repositories need{{end}} updates, {{.Skipped}} skipped
+{{- if .Updates}}
+*Pending:* {{join .Updates ", "}}{{end}}
+{{- if .SkippedUpToDate}}
+*Up to date:* {{join .SkippedUpToDate ", "}}{{end}}
+{{- if .SkippedArchived}}
+*Archived:* {{join .SkippedArchived ", "}}{{end}}
+{{- if .SkippedLocal}}
+*Managed locally:* {{join .SkippedLocal ", "}}{{end}}
+{{- if .CheckErrors}}
+*Check errors:* {{.CheckErrors}} (included for safety){{end}}
+{{- if .EstimatedDuration}}
+*Estimated runtime:* {{.EstimatedDuration}}{{end}}
+
+Planned at {{.Timestamp.Format "15:04:05 MST"}}`
+
+// RenderPlanSummary renders the message sent for a plan summary.
+func RenderPlanSummary(summary PlanSummary) (string, error) {
+	if summary.Timestamp.IsZero() {
+		summary.Timestamp = time.Now()
+	}
+
+	t, err := template.New("plan_summary").Funcs(templateFuncMap).Parse(defaultPlanSummaryTemplate)
+	if err != nil {
+		return "", &TemplateRenderError{TemplateName: "plan_summary", Operation: "parse", Err: err}
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, summary); err != nil {
+		return "", &TemplateRenderError{TemplateName: "plan_summary", Operation: "execute", Err: err}
+	}
+	return buf.String(), nil
+}
+
+// SendPlan posts a plan summary to the Slack channel.
+func (s *SlackNotifier) SendPlan(ctx context.Context, summary PlanSummary) (*NotificationResult, error) {
+	message, err := RenderPlanSummary(summary)
+	if err != nil {
+		return nil, &NotificationError{
+			Channel: s.channel,
+			Err:     fmt.Errorf("render plan summary: %w", err),
+		}
+	}
+
+	payload := map[string]any{
+		"channel": s.channel,
+		"text":    message,
+		"as_user": true,
+		"mrkdwn":  true,
+	}
+
+	return s.sendWithRetry(ctx, payload)
+}
+
+// SendPlan posts a plan summary to the webhook endpoint. The payload carries an
+// event field and the planned counts instead of the work item fields sent by Send.
+func (w *WebhookNotifier) SendPlan(ctx context.Context, summary PlanSummary) (*NotificationResult, error) {
+	message, err := RenderPlanSummary(summary)
+	if err != nil {
+		return nil, &NotificationError{
+			Channel: w.url,
+			Err:     fmt.Errorf("render plan summary: %w", err),
+		}
+	}
+
+	payload := map[string]any{
+		"text":    message,
+		"event":   "plan",
+		"module":  summary.Module,
+		"version": summary.Version,
+		"updates": len(summary.Updates),
+		"skipped": summary.Skipped(),
+	}
+
+	return w.sendWithRetry(ctx, payload)
+}
+
+// SendPlan forwards a plan summary to every notifier that supports plan summaries.
+func (m *MultiNotifier) SendPlan(ctx context.Context, summary PlanSummary) (*NotificationResult, error) {
+	var errors []string
+	var firstResult *NotificationResult
+	attempted := 0
+
+	for _, notifier := range m.notifiers {
+		planNotifier, ok := notifier.(PlanNotifier)
+		if !ok {
+			continue
+		}
+		attempted++
+
+		planResult, err := planNotifier.SendPlan(ctx, summary)
+		if err != nil {
+			errors = append(errors, err.Error())
+			continue
+		}
+
+		if firstResult == nil {
+			firstResult = planResult
+		}
+	}
+
+	if attempted > 0 && len(errors) == attempted {
+		return nil, &NotificationError{
+			Channel: "multi",
+			Err:     fmt.Errorf("all plan notifiers failed: %s", strings.Join(errors, "; ")),
+		}
+	}
+
+	return firstResult, nil
+}
+
+// SendPlan records the plan summary intent but doesn't send it.
+func (n *NoOpNotifier) SendPlan(ctx context.Context, summary PlanSummary) (*NotificationResult, error) {
+	return &NotificationResult{
+		Channel: "noop",
+		Message: "Plan summary skipped (no integrations configured)",
+	}, nil
+}
@@ -0,0 +1,112 @@
+package broker
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testPlanSummary() PlanSummary {
+	return PlanSummary{
+		Module:            "github.com/example/lib",
+		Version:           "v1.2.3",
+		Updates:           []string{"example/app", "example/api"},
+		SkippedUpToDate:   []string{"example/cli"},
+		SkippedArchived:   []string{"example/old"},
+		SkippedLocal:      []string{"example/dev"},
+		CheckErrors:       1,
+		EstimatedDuration: 4 * time.Minute,
+		Timestamp:         time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestRenderPlanSummary(t *testing.T) {
+	message, err := RenderPlanSummary(testPlanSummary())
+	if err != nil {
+		t.Fatalf("RenderPlanSummary failed: %v", err)
+	}
+
+	for _, want := range []string{
+		"*github.com/example/lib@v1.2.3* plan: 2 repositories need updates, 3 skipped",
+		"*Pending:* example/app, example/api",
+		"*Up to date:* example/cli",
+		"*Archived:* example/old",
+		"*Managed locally:* example/dev",
+		"*Check errors:* 1",
+		"*Estimated runtime:* 4m0s",
+		"Planned at 12:00:00 UTC",
+	} {
+		if !strings.Contains(message, want) {
+			t.Errorf("expected message to contain %q, got:\n%s", want, message)
+		}
+	}
+
+	empty, err := RenderPlanSummary(PlanSummary{Module: "github.com/example/lib", Version: "v1.2.3", Updates: []string{"example/app"}})
+	if err != nil {
+		t.Fatalf("RenderPlanSummary failed: %v", err)
+	}
+	if !strings.Contains(empty, "1 repository needs updates, 0 skipped") {
+		t.Errorf("expected singular wording, got:\n%s", empty)
+	}
+	if strings.Contains(empty, "Up to date") || strings.Contains(empty, "\n\n\n") {
+		t.Errorf("expected empty sections to be omitted, got:\n%s", empty)
+	}
+}
+
+func TestNotifiers_SendPlan(t *testing.T) {
+	slackClient := &mockHTTPClient{responses: []mockResponse{{statusCode: 200, body: `{"ok": true}`}}}
+	webhookClient := &mockHTTPClient{responses: []mockResponse{{statusCode: 200, body: `{"status": "ok"}`}}}
+
+	config := DefaultNotificationConfig()
+	config.MaxRetries = 0
+
+	multi := NewMultiNotifier(
+		NewSlackNotifier("bot-token", "#releases", slackClient, config),
+		NewGitHubIssueNotifier(&stubGitHubIssuesService{}, nil),
+		NewWebhookNotifier("https://example.com/webhook", webhookClient, config),
+	)
+
+	result, err := multi.SendPlan(context.Background(), testPlanSummary())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if result == nil || result.Channel != "#releases" {
+		t.Fatalf("Expected Slack result first, got %+v", result)
+	}
+
+	if len(slackClient.requests) != 1 || len(webhookClient.requests) != 1 {
+		t.Fatalf("Expected one request per plan notifier, got slack=%d webhook=%d", len(slackClient.requests), len(webhookClient.requests))
+	}
+
+	body, _ := io.ReadAll(webhookClient.requests[0].Body)
+	for _, want := range []string{`"event":"plan"`, `"updates":2`, `"skipped":3`, `"version":"v1.2.3"`} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("Expected webhook payload to contain %s, got %s", want, body)
+		}
+	}
+}
+
+func TestMultiNotifier_SendPlan_AllFail(t *testing.T) {
+	config := DefaultNotificationConfig()
+	config.MaxRetries = 0
+
+	multi := NewMultiNotifier(
+		NewWebhookNotifier("https://example.com/webhook", &mockHTTPClient{responses: []mockResponse{{statusCode: 400}}}, config),
+	)
+
+	if _, err := multi.SendPlan(context.Background(), testPlanSummary()); err == nil {
+		t.Fatal("Expected error when every plan notifier fails")
+	}
+}
+
+func TestNoOpNotifier_SendPlan(t *testing.T) {
+	result, err := NewNoOpNotifier().SendPlan(context.Background(), testPlanSummary())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if result.Channel != "noop" {
+		t.Errorf("Expected noop channel, got %q", result.Channel)
+	}
+}
@@ -0,0 +1,63 @@
+package broker
+
+import (
+	"github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/internal/planner"
+)
+
+// PR formats.
+const (
+	// PRFormatCascade renders Cascade's own PR title and body.
+	PRFormatCascade = "cascade"
+
+	// PRFormatDependabot renders PRs the way Dependabot does, so tooling that
+	// already parses Dependabot and Renovate pull requests handles them too.
+	PRFormatDependabot = "dependabot"
+)
+
+// dependabotBodyTemplate is the default body of PRFormatDependabot: the
+// "Bumps ..." summary, Cascade's status, and Dependabot's metadata block.
+const dependabotBodyTemplate = `{{.UpdateSummary}}
+**Repository**: {{.Repo}}
+{{if .Status}}**Status**: {{.Status}}
+{{end}}{{if .CommitHash}}**Commit**: {{.CommitHash}}
+{{end}}
+{{.UpdateMetadata}}`
+
+// renderPRTitle renders the PR title of item in the configured format. A title
+// template from the manifest takes precedence over the configured format and
+// template.
+func renderPRTitle(config Config, item planner.WorkItem, result *executor.Result) (string, error) {
+	tmpl := config.TitleTemplate
+	if item.PR.TitleTemplate != "" {
+		tmpl = item.PR.TitleTemplate
+	}
+	if config.PRFormat == PRFormatDependabot && tmpl == "" {
+		return executor.DependabotSubject(config.CommitPrefix, executor.DependencyUpdates(item, resultImpact(result))), nil
+	}
+	return RenderTitle(tmpl, item, result)
+}
+
+// renderPRBody renders the PR body of item in the configured format. A body
+// template file or body template from the manifest takes precedence over the
+// configured format and template, in that order.
+func renderPRBody(config Config, item planner.WorkItem, result *executor.Result) (string, error) {
+	if item.PR.BodyTemplateFile != "" {
+		return RenderBodyFile(item.PR.BodyTemplateFile, item, result)
+	}
+	tmpl := config.BodyTemplate
+	if item.PR.BodyTemplate != "" {
+		tmpl = item.PR.BodyTemplate
+	}
+	if config.PRFormat == PRFormatDependabot && tmpl == "" {
+		return RenderBody(dependabotBodyTemplate, item, result)
+	}
+	return RenderBody(tmpl, item, result)
+}
+
+func resultImpact(result *executor.Result) *executor.DependencyImpact {
+	if result == nil {
+		return nil
+	}
+	return result.DependencyImpact
+}
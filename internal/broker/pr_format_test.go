@@ -0,0 +1,103 @@
+package broker_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/goliatone/cascade/internal/broker"
+	"github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/internal/manifest"
+	"github.com/goliatone/cascade/internal/planner"
+)
+
+func TestPreviewPR_DependabotFormat(t *testing.T) {
+	item := planner.WorkItem{
+		Repo:          "acme/api",
+		Module:        "github.com/acme/api",
+		SourceModule:  "github.com/acme/lib",
+		SourceVersion: "v1.3.0",
+		Branch:        "main",
+		BranchName:    "auto/lib-v1.3.0",
+	}
+	result := &executor.Result{
+		Status: executor.StatusCompleted,
+		DependencyImpact: &executor.DependencyImpact{
+			Module:             "github.com/acme/lib",
+			OldVersion:         "v1.2.0",
+			OldVersionDetected: true,
+			NewVersion:         "v1.3.0",
+			NewVersionDetected: true,
+		},
+	}
+
+	config := broker.DefaultConfig()
+	config.PRFormat = broker.PRFormatDependabot
+	preview, err := broker.PreviewPR(config, item, result)
+	if err != nil {
+		t.Fatalf("PreviewPR() error = %v", err)
+	}
+
+	if preview.Title != "chore(deps): bump github.com/acme/lib from v1.2.0 to v1.3.0" {
+		t.Errorf("Title = %q", preview.Title)
+	}
+	for _, want := range []string{
+		"Bumps github.com/acme/lib from v1.2.0 to v1.3.0.",
+		"---\nupdated-dependencies:\n- dependency-name: github.com/acme/lib\n",
+		"  update-type: version-update:semver-minor\n...\n",
+	} {
+		if !strings.Contains(preview.Body, want) {
+			t.Errorf("Body missing %q:\n%s", want, preview.Body)
+		}
+	}
+
+	// Configured templates take precedence over the format's defaults
+	config.TitleTemplate = "deps: {{.SourceModule}}"
+	config.BodyTemplate = "custom\n\n{{.UpdateMetadata}}"
+	preview, err = broker.PreviewPR(config, item, result)
+	if err != nil {
+		t.Fatalf("PreviewPR() error = %v", err)
+	}
+	if preview.Title != "deps: github.com/acme/lib" || !strings.HasPrefix(preview.Body, "custom\n\n---\nupdated-dependencies:") {
+		t.Errorf("expected the configured templates, got title %q and body:\n%s", preview.Title, preview.Body)
+	}
+}
+
+func TestPreviewPR_ManifestTemplates(t *testing.T) {
+	item := planner.WorkItem{
+		Repo:          "acme/api",
+		Module:        "github.com/acme/api",
+		SourceModule:  "github.com/acme/lib",
+		SourceVersion: "v2.1.0",
+		Branch:        "main",
+		BranchName:    "auto/lib-v2.1.0",
+		PR: manifest.PRConfig{
+			TitleTemplate: "chore(deps): bump {{ .SourceModule }} to v{{ semverMajor .SourceVersion }}.x",
+			BodyTemplate:  "Automated dependency update for {{ module }} to {{ version }} in {{ .Module }}",
+		},
+	}
+
+	config := broker.DefaultConfig()
+	config.PRFormat = broker.PRFormatDependabot
+	config.TitleTemplate = "configured title"
+	config.BodyTemplate = "configured body"
+	preview, err := broker.PreviewPR(config, item, &executor.Result{Status: executor.StatusCompleted})
+	if err != nil {
+		t.Fatalf("PreviewPR() error = %v", err)
+	}
+	if preview.Title != "chore(deps): bump github.com/acme/lib to v2.x" {
+		t.Errorf("Title = %q, want the manifest title template", preview.Title)
+	}
+	if preview.Body != "Automated dependency update for github.com/acme/lib to v2.1.0 in github.com/acme/api" {
+		t.Errorf("Body = %q, want the manifest body template with commit placeholders", preview.Body)
+	}
+
+	// The configured templates apply when the manifest sets none
+	item.PR = manifest.PRConfig{}
+	preview, err = broker.PreviewPR(config, item, &executor.Result{Status: executor.StatusCompleted})
+	if err != nil {
+		t.Fatalf("PreviewPR() error = %v", err)
+	}
+	if preview.Title != "configured title" || preview.Body != "configured body" {
+		t.Errorf("expected the configured templates, got title %q and body %q", preview.Title, preview.Body)
+	}
+}
@@ -0,0 +1,74 @@
+package broker
+
+import (
+	"fmt"
+
+	"github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/internal/planner"
+)
+
+// PRPreview describes the pull request the broker would open for a work item.
+type PRPreview struct {
+	Repo          string   `json:"repo"`
+	BaseBranch    string   `json:"base_branch"`
+	HeadBranch    string   `json:"head_branch"`
+	Title         string   `json:"title"`
+	Body          string   `json:"body"`
+	Labels        []string `json:"labels"`
+	Reviewers     []string `json:"reviewers,omitempty"`
+	TeamReviewers []string `json:"team_reviewers,omitempty"`
+	Draft         bool     `json:"draft,omitempty"`
+}
+
+// PreviewPR renders the pull request EnsurePR would submit for item using the same
+// templates, label merging, sanitisation, and validation, without calling a provider.
+// A nil result renders the templates as they would look before execution.
+func PreviewPR(config Config, item planner.WorkItem, result *executor.Result) (*PRPreview, error) {
+	input, err := buildPRInput(config, item, result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PRPreview{
+		Repo:          input.Repo,
+		BaseBranch:    input.BaseBranch,
+		HeadBranch:    input.HeadBranch,
+		Title:         input.Title,
+		Body:          input.Body,
+		Labels:        input.Labels,
+		Reviewers:     SanitizeLabels(item.PR.Reviewers),
+		TeamReviewers: SanitizeLabels(item.PR.TeamReviewers),
+		Draft:         input.Draft,
+	}, nil
+}
+
+// buildPRInput renders and validates the provider payload for a work item.
+func buildPRInput(config Config, item planner.WorkItem, result *executor.Result) (PRInput, error) {
+	title, err := renderPRTitle(config, item, result)
+	if err != nil {
+		return PRInput{}, fmt.Errorf("render PR title: %w", err)
+	}
+
+	body, err := renderPRBody(config, item, result)
+	if err != nil {
+		return PRInput{}, fmt.Errorf("render PR body: %w", err)
+	}
+
+	input := PRInput{
+		Repo:       item.Repo,
+		BaseBranch: item.Branch,
+		HeadBranch: item.BranchName,
+		Title:      title,
+		Body:       body,
+		Labels:     SanitizeLabels(prLabels(config, item, result)),
+		Draft:      item.PR.Draft,
+
+		DeleteBranchOnMerge: item.PR.DeleteBranchOnMerge,
+	}
+
+	if err := ValidatePRInput(&input); err != nil {
+		return PRInput{}, fmt.Errorf("PR input validation failed: %w", err)
+	}
+
+	return input, nil
+}
@@ -0,0 +1,56 @@
+package broker_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/goliatone/cascade/internal/broker"
+	"github.com/goliatone/cascade/internal/manifest"
+	"github.com/goliatone/cascade/internal/planner"
+)
+
+func TestPreviewPR(t *testing.T) {
+	item := planner.WorkItem{
+		Repo:          "goliatone/go-logger",
+		Module:        "github.com/goliatone/go-logger",
+		SourceModule:  "github.com/goliatone/go-errors",
+		SourceVersion: "v1.2.3",
+		Branch:        "main",
+		BranchName:    "cascade/go-errors-v1.2.3",
+		Labels:        []string{"automation:cascade", "deps"},
+		PR: manifest.PRConfig{
+			Reviewers:     []string{"octocat"},
+			TeamReviewers: []string{"platform"},
+		},
+	}
+
+	preview, err := broker.PreviewPR(broker.DefaultConfig(), item, nil)
+	if err != nil {
+		t.Fatalf("PreviewPR() error = %v", err)
+	}
+
+	if preview.Title != "Update github.com/goliatone/go-logger to v1.2.3" {
+		t.Errorf("Title = %q", preview.Title)
+	}
+	if preview.BaseBranch != "main" || preview.HeadBranch != "cascade/go-errors-v1.2.3" {
+		t.Errorf("branches = %s <- %s", preview.BaseBranch, preview.HeadBranch)
+	}
+	if !strings.Contains(preview.Body, "**Repository**: goliatone/go-logger") {
+		t.Errorf("Body missing repository line:\n%s", preview.Body)
+	}
+	if want := []string{"automation:cascade", "deps"}; !reflect.DeepEqual(preview.Labels, want) {
+		t.Errorf("Labels = %v, want %v", preview.Labels, want)
+	}
+	if !reflect.DeepEqual(preview.Reviewers, []string{"octocat"}) || !reflect.DeepEqual(preview.TeamReviewers, []string{"platform"}) {
+		t.Errorf("reviewers = %v / %v", preview.Reviewers, preview.TeamReviewers)
+	}
+}
+
+func TestPreviewPR_InvalidInput(t *testing.T) {
+	item := planner.WorkItem{Repo: "goliatone/go-logger", Module: "github.com/goliatone/go-logger", SourceVersion: "v1.2.3"}
+
+	if _, err := broker.PreviewPR(broker.DefaultConfig(), item, nil); err == nil {
+		t.Fatal("expected validation error for missing branches")
+	}
+}
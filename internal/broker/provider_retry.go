@@ -0,0 +1,70 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/goliatone/cascade/internal/telemetry"
+	"github.com/goliatone/cascade/pkg/retry"
+	"github.com/google/go-github/v66/github"
+)
+
+// callProvider runs a pull request provider call under the ProviderRetry
+// policy. Only calls that are safe to repeat go through it: creating or
+// updating a PR finds the PR an earlier attempt opened, while comments and
+// merges are sent once.
+func (b *broker) callProvider(ctx context.Context, operation, repo string, call func(ctx context.Context) error) error {
+	policy := b.config.ProviderRetry
+	if policy.Retryable == nil {
+		policy.Retryable = isTransientProviderError
+	}
+	ctx, span := telemetry.Start(ctx, "broker.provider."+operation, telemetry.String("repo", repo))
+	attempts := 1
+	policy.OnRetry = func(attempt int, err error, delay time.Duration) {
+		attempts = attempt + 1
+		b.logger.Warn("Provider call failed, retrying", "operation", operation, "repo", repo, "attempt", attempt, "delay", delay, "error", err)
+	}
+	err := retry.Do(ctx, policy, call)
+	span.SetAttributes(telemetry.Int("attempts", attempts))
+	span.End(err)
+	return err
+}
+
+// isTransientProviderError reports whether a provider call failed in a way a
+// later attempt may not: server errors, secondary rate limits answered with
+// 429, timeouts, and dropped connections.
+func isTransientProviderError(err error) bool {
+	if status := providerStatusCode(err); status != 0 {
+		return status >= http.StatusInternalServerError || status == http.StatusTooManyRequests
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// providerStatusCode returns the HTTP status of a failed provider call, or 0
+// when the call got no response.
+func providerStatusCode(err error) int {
+	var (
+		githubResp *github.ErrorResponse
+		githubErr  *GitHubAPIError
+		gitlabErr  *GitLabAPIError
+		bitbucket  *BitbucketAPIError
+	)
+	switch {
+	case errors.As(err, &githubResp) && githubResp.Response != nil:
+		return githubResp.Response.StatusCode
+	case errors.As(err, &githubErr) && githubErr.StatusCode != 0:
+		return githubErr.StatusCode
+	case errors.As(err, &gitlabErr) && gitlabErr.StatusCode != 0:
+		return gitlabErr.StatusCode
+	case errors.As(err, &bitbucket) && bitbucket.StatusCode != 0:
+		return bitbucket.StatusCode
+	}
+	return 0
+}
@@ -0,0 +1,237 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// rateLimitResourceCore is the REST API quota shared by pull request, label, and
+// issue calls. Other quotas (search, graphql) are not tracked.
+const rateLimitResourceCore = "core"
+
+// Alerter is implemented by notifiers that can deliver a free-form operational
+// alert that is not tied to a work item. It is optional; callers should
+// type-assert before use.
+type Alerter interface {
+	Alert(ctx context.Context, message string) (*NotificationResult, error)
+}
+
+var _ Alerter = (*broker)(nil)
+
+// RateLimitUsage summarises the GitHub API quota consumed during a run.
+type RateLimitUsage struct {
+	// Used is the number of requests the run consumed across every quota window
+	Used int
+
+	// Limit is the quota size reported by the most recent response
+	Limit int
+
+	// Remaining is the quota left in the current window
+	Remaining int
+
+	// Reset is when the current window resets
+	Reset time.Time
+}
+
+// Percent returns the cumulative usage as a percentage of the quota.
+func (u RateLimitUsage) Percent() float64 {
+	if u.Limit <= 0 {
+		return 0
+	}
+	return float64(u.Used) * 100 / float64(u.Limit)
+}
+
+// RateLimitMonitor tracks cumulative GitHub API usage from response headers and
+// raises a warning the first time usage crosses each configured threshold. A nil
+// monitor is valid and ignores every observation.
+type RateLimitMonitor struct {
+	thresholds []int
+	logger     Logger
+
+	mu      sync.Mutex
+	alerter Alerter
+	fired   map[int]bool
+
+	// window tracks the quota window the latest response belongs to
+	windowReset time.Time
+	windowStart int
+	windowUsed  int
+
+	// closedUsed is the usage accumulated by earlier windows in this run
+	closedUsed int
+	limit      int
+	remaining  int
+}
+
+// NewRateLimitMonitor returns a monitor that alerts at the given percentages of the
+// quota. Thresholds outside 1-100 are ignored; nil is returned when none remain.
+func NewRateLimitMonitor(thresholds []int, logger Logger) *RateLimitMonitor {
+	var valid []int
+	seen := make(map[int]bool, len(thresholds))
+	for _, threshold := range thresholds {
+		if threshold < 1 || threshold > 100 || seen[threshold] {
+			continue
+		}
+		seen[threshold] = true
+		valid = append(valid, threshold)
+	}
+	if len(valid) == 0 {
+		return nil
+	}
+	sort.Ints(valid)
+
+	return &RateLimitMonitor{
+		thresholds: valid,
+		logger:     logger,
+		fired:      make(map[int]bool, len(valid)),
+	}
+}
+
+// SetAlerter sets where threshold alerts are delivered. Without an alerter,
+// crossings are only logged.
+func (m *RateLimitMonitor) SetAlerter(alerter Alerter) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.alerter = alerter
+}
+
+// Usage returns the usage observed so far.
+func (m *RateLimitMonitor) Usage() RateLimitUsage {
+	if m == nil {
+		return RateLimitUsage{}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.usageLocked()
+}
+
+func (m *RateLimitMonitor) usageLocked() RateLimitUsage {
+	return RateLimitUsage{
+		Used:      m.closedUsed + m.windowUsed,
+		Limit:     m.limit,
+		Remaining: m.remaining,
+		Reset:     m.windowReset,
+	}
+}
+
+// Observe records the rate limit headers of a GitHub API response and sends an
+// alert when the cumulative usage newly crosses one or more thresholds.
+func (m *RateLimitMonitor) Observe(ctx context.Context, header http.Header) {
+	if m == nil || header == nil {
+		return
+	}
+	if resource := header.Get("X-RateLimit-Resource"); resource != "" && resource != rateLimitResourceCore {
+		return
+	}
+
+	limit, errLimit := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	remaining, errRemaining := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if errLimit != nil || errRemaining != nil || limit <= 0 {
+		return
+	}
+	var reset time.Time
+	if epoch, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		reset = time.Unix(epoch, 0).UTC()
+	}
+
+	m.mu.Lock()
+	switch {
+	case m.limit == 0 || reset.After(m.windowReset):
+		// A new window started; bank the usage of the previous one. The request
+		// that produced this response already counts against the new window.
+		m.closedUsed += m.windowUsed
+		m.windowReset = reset
+		m.windowStart = remaining + 1
+		m.windowUsed = 0
+	case reset.Before(m.windowReset):
+		// Late response from an earlier window; its usage is already banked
+		m.mu.Unlock()
+		return
+	}
+
+	// Responses can arrive out of order, so usage only ever grows
+	if used := m.windowStart - remaining; used > m.windowUsed {
+		m.windowUsed = used
+	}
+	m.limit = limit
+	m.remaining = remaining
+
+	usage := m.usageLocked()
+	var crossed []int
+	for _, threshold := range m.thresholds {
+		if !m.fired[threshold] && usage.Percent() >= float64(threshold) {
+			m.fired[threshold] = true
+			crossed = append(crossed, threshold)
+		}
+	}
+	alerter := m.alerter
+	m.mu.Unlock()
+
+	if len(crossed) == 0 {
+		return
+	}
+
+	// Several thresholds crossed at once collapse into one alert for the highest
+	threshold := crossed[len(crossed)-1]
+	message := RateLimitAlertMessage(usage, threshold)
+	if m.logger != nil {
+		m.logger.Warn("GitHub API rate limit threshold crossed",
+			"threshold_percent", threshold,
+			"used", usage.Used,
+			"limit", usage.Limit,
+			"remaining", usage.Remaining)
+	}
+	if alerter == nil {
+		return
+	}
+	if _, err := alerter.Alert(context.WithoutCancel(ctx), message); err != nil && m.logger != nil {
+		m.logger.Warn("Failed to send rate limit alert", "threshold_percent", threshold, "error", err)
+	}
+}
+
+// RateLimitAlertMessage renders the warning sent when usage crosses threshold.
+func RateLimitAlertMessage(usage RateLimitUsage, threshold int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, ":warning: Cascade has used %d of %d GitHub API requests (%.0f%%) this run, crossing the %d%% alert threshold.",
+		usage.Used, usage.Limit, usage.Percent(), threshold)
+	fmt.Fprintf(&b, " %d requests remain", usage.Remaining)
+	if !usage.Reset.IsZero() {
+		fmt.Fprintf(&b, " until the quota resets at %s", usage.Reset.UTC().Format("15:04 MST"))
+	}
+	b.WriteString(".")
+	return b.String()
+}
+
+// RateLimitTransport is an http.RoundTripper that reports GitHub API responses to
+// a RateLimitMonitor.
+type RateLimitTransport struct {
+	Base    http.RoundTripper
+	Monitor *RateLimitMonitor
+}
+
+// NewRateLimitTransport wraps base so every response is observed by monitor.
+func NewRateLimitTransport(base http.RoundTripper, monitor *RateLimitMonitor) *RateLimitTransport {
+	return &RateLimitTransport{Base: base, Monitor: monitor}
+}
+
+// RoundTrip executes the request and records its rate limit headers.
+func (t *RateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(req)
+	if resp != nil {
+		t.Monitor.Observe(req.Context(), resp.Header)
+	}
+	return resp, err
+}
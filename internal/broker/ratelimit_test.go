@@ -0,0 +1,181 @@
+package broker
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type recordingAlerter struct {
+	messages []string
+}
+
+func (r *recordingAlerter) Alert(_ context.Context, message string) (*NotificationResult, error) {
+	r.messages = append(r.messages, message)
+	return &NotificationResult{Channel: "test", Message: message}, nil
+}
+
+type warnCountingLogger struct {
+	warnings int
+}
+
+func (l *warnCountingLogger) Debug(string, ...any) {}
+func (l *warnCountingLogger) Info(string, ...any)  {}
+func (l *warnCountingLogger) Warn(string, ...any)  { l.warnings++ }
+func (l *warnCountingLogger) Error(string, ...any) {}
+
+func rateLimitHeader(limit, remaining int, reset time.Time) http.Header {
+	header := make(http.Header)
+	header.Set("X-RateLimit-Limit", strconv.Itoa(limit))
+	header.Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+	header.Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+	header.Set("X-RateLimit-Resource", "core")
+	return header
+}
+
+func TestNewRateLimitMonitor_NoThresholds(t *testing.T) {
+	if m := NewRateLimitMonitor(nil, nil); m != nil {
+		t.Fatal("expected nil monitor without thresholds")
+	}
+	if m := NewRateLimitMonitor([]int{0, 101}, nil); m != nil {
+		t.Fatal("expected nil monitor when every threshold is out of range")
+	}
+
+	// A nil monitor ignores observations
+	var m *RateLimitMonitor
+	m.Observe(context.Background(), rateLimitHeader(100, 10, time.Now()))
+	if usage := m.Usage(); usage.Used != 0 {
+		t.Fatalf("expected zero usage from nil monitor, got %+v", usage)
+	}
+}
+
+func TestRateLimitMonitor_AlertsOncePerThreshold(t *testing.T) {
+	alerter := &recordingAlerter{}
+	logger := &warnCountingLogger{}
+	monitor := NewRateLimitMonitor([]int{80, 50}, logger)
+	monitor.SetAlerter(alerter)
+
+	reset := time.Date(2025, 1, 1, 13, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	// The run starts with 900 of 1000 requests left: the first request used one
+	monitor.Observe(ctx, rateLimitHeader(1000, 900, reset))
+	monitor.Observe(ctx, rateLimitHeader(1000, 500, reset))
+	if len(alerter.messages) != 0 {
+		t.Fatalf("expected no alert below 50%%, got %v", alerter.messages)
+	}
+
+	monitor.Observe(ctx, rateLimitHeader(1000, 400, reset))
+	if len(alerter.messages) != 1 {
+		t.Fatalf("expected one alert after crossing 50%%, got %d", len(alerter.messages))
+	}
+	if !strings.Contains(alerter.messages[0], "used 501 of 1000") || !strings.Contains(alerter.messages[0], "50% alert threshold") {
+		t.Errorf("unexpected alert message: %s", alerter.messages[0])
+	}
+
+	// Staying above the threshold does not alert again
+	monitor.Observe(ctx, rateLimitHeader(1000, 390, reset))
+	if len(alerter.messages) != 1 {
+		t.Fatalf("expected threshold to alert once, got %d alerts", len(alerter.messages))
+	}
+
+	monitor.Observe(ctx, rateLimitHeader(1000, 100, reset))
+	if len(alerter.messages) != 2 || !strings.Contains(alerter.messages[1], "80% alert threshold") {
+		t.Fatalf("expected second alert for 80%%, got %v", alerter.messages)
+	}
+	if logger.warnings != 2 {
+		t.Errorf("expected a warning log per alert, got %d", logger.warnings)
+	}
+}
+
+func TestRateLimitMonitor_CollapsesSimultaneousThresholds(t *testing.T) {
+	alerter := &recordingAlerter{}
+	monitor := NewRateLimitMonitor([]int{25, 50, 75}, nil)
+	monitor.SetAlerter(alerter)
+
+	reset := time.Now().Add(time.Hour)
+	monitor.Observe(context.Background(), rateLimitHeader(100, 99, reset))
+	monitor.Observe(context.Background(), rateLimitHeader(100, 40, reset))
+
+	if len(alerter.messages) != 1 {
+		t.Fatalf("expected a single alert, got %v", alerter.messages)
+	}
+	if !strings.Contains(alerter.messages[0], "50% alert threshold") {
+		t.Errorf("expected the highest crossed threshold, got %s", alerter.messages[0])
+	}
+}
+
+func TestRateLimitMonitor_AccumulatesAcrossWindows(t *testing.T) {
+	monitor := NewRateLimitMonitor([]int{90}, nil)
+	first := time.Now().Add(time.Minute)
+	second := first.Add(time.Hour)
+	ctx := context.Background()
+
+	monitor.Observe(ctx, rateLimitHeader(100, 69, first))
+	monitor.Observe(ctx, rateLimitHeader(100, 40, first))
+	// Out of order response from the same window
+	monitor.Observe(ctx, rateLimitHeader(100, 45, first))
+
+	monitor.Observe(ctx, rateLimitHeader(100, 99, second))
+	monitor.Observe(ctx, rateLimitHeader(100, 90, second))
+	// Late response from the previous window is ignored
+	monitor.Observe(ctx, rateLimitHeader(100, 30, first))
+
+	usage := monitor.Usage()
+	if usage.Used != 40 {
+		t.Errorf("expected 30 + 10 requests used, got %d", usage.Used)
+	}
+	if usage.Remaining != 90 || !usage.Reset.Equal(second.Truncate(time.Second)) {
+		t.Errorf("expected current window to be reported, got %+v", usage)
+	}
+}
+
+func TestRateLimitMonitor_IgnoresOtherResources(t *testing.T) {
+	monitor := NewRateLimitMonitor([]int{10}, nil)
+
+	header := rateLimitHeader(30, 1, time.Now())
+	header.Set("X-RateLimit-Resource", "search")
+	monitor.Observe(context.Background(), header)
+	monitor.Observe(context.Background(), http.Header{})
+
+	if usage := monitor.Usage(); usage.Used != 0 || usage.Limit != 0 {
+		t.Errorf("expected search quota and headerless responses to be ignored, got %+v", usage)
+	}
+}
+
+type headerRoundTripper struct {
+	header http.Header
+}
+
+func (h headerRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     h.header,
+		Body:       io.NopCloser(strings.NewReader("{}")),
+		Request:    req,
+	}, nil
+}
+
+func TestRateLimitTransport_ObservesResponses(t *testing.T) {
+	alerter := &recordingAlerter{}
+	monitor := NewRateLimitMonitor([]int{1}, nil)
+	monitor.SetAlerter(alerter)
+
+	client := &http.Client{Transport: NewRateLimitTransport(headerRoundTripper{header: rateLimitHeader(100, 98, time.Now())}, monitor)}
+	resp, err := client.Get("https://api.github.com/repos/owner/repo")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if usage := monitor.Usage(); usage.Used != 1 || usage.Limit != 100 {
+		t.Errorf("expected one observed request, got %+v", usage)
+	}
+	if len(alerter.messages) != 1 {
+		t.Errorf("expected alert at 1%%, got %v", alerter.messages)
+	}
+}
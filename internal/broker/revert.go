@@ -0,0 +1,110 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Pull request states reported by PullRequestStatus.
+const (
+	PullRequestOpen   = "open"
+	PullRequestClosed = "closed"
+)
+
+// PullRequestStatus describes where a pull request stands.
+type PullRequestStatus struct {
+	// State is PullRequestOpen or PullRequestClosed. Merged pull requests are closed.
+	State  string
+	Merged bool
+
+	// MergeCommitSHA is the commit that landed the pull request on BaseBranch:
+	// the merge commit, or the squashed or last rebased commit.
+	MergeCommitSHA string
+	BaseBranch     string
+	HeadBranch     string
+}
+
+// PullRequestGetter is implemented by providers that can look up a single pull
+// request. It is optional; callers type-assert.
+type PullRequestGetter interface {
+	GetPullRequest(ctx context.Context, repo string, number int) (*PullRequestStatus, error)
+}
+
+// Reverter is implemented by brokers that can roll back the pull requests of a
+// release. It is optional; callers type-assert.
+type Reverter interface {
+	// PullRequestStatus reports whether pr is still open or was merged.
+	PullRequestStatus(ctx context.Context, pr *PullRequest) (*PullRequestStatus, error)
+
+	// ClosePRWithComment explains why pr is being closed, then closes it.
+	ClosePRWithComment(ctx context.Context, pr *PullRequest, body string) error
+
+	// OpenPR opens a pull request that was not produced by a work item, such as
+	// one reverting a merged update.
+	OpenPR(ctx context.Context, input PRInput) (*PullRequest, error)
+}
+
+var (
+	_ Reverter          = (*broker)(nil)
+	_ PullRequestGetter = (*GitHubProvider)(nil)
+)
+
+func (b *broker) PullRequestStatus(ctx context.Context, pr *PullRequest) (*PullRequestStatus, error) {
+	if pr == nil {
+		return nil, fmt.Errorf("pull request cannot be nil")
+	}
+
+	getter, ok := b.provider.(PullRequestGetter)
+	if !ok {
+		return nil, &NotImplementedError{Operation: "broker.PullRequestStatus"}
+	}
+
+	status, err := getter.GetPullRequest(ctx, pr.Repo, pr.Number)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up PR #%d in %s: %w", pr.Number, pr.Repo, err)
+	}
+	return status, nil
+}
+
+func (b *broker) ClosePRWithComment(ctx context.Context, pr *PullRequest, body string) error {
+	if pr == nil {
+		return fmt.Errorf("pull request cannot be nil")
+	}
+
+	if b.config.DryRun {
+		b.logger.Info("Dry run: would comment on and close pull request", "repo", pr.Repo, "number", pr.Number)
+		return nil
+	}
+
+	// A missing comment should not keep the pull request open
+	commentErr := b.Comment(ctx, pr, body)
+	if commentErr != nil {
+		b.logger.Warn("Failed to comment before closing pull request", "repo", pr.Repo, "number", pr.Number, "error", commentErr)
+	}
+	if err := b.ClosePR(ctx, pr); err != nil {
+		return errors.Join(err, commentErr)
+	}
+	return nil
+}
+
+func (b *broker) OpenPR(ctx context.Context, input PRInput) (*PullRequest, error) {
+	if err := ValidatePRInput(&input); err != nil {
+		return nil, err
+	}
+
+	if b.config.DryRun {
+		b.logger.Info("Dry run: would open pull request", "repo", input.Repo, "head", input.HeadBranch, "title", input.Title)
+		return nil, nil
+	}
+
+	if b.provider == nil {
+		return nil, &NotImplementedError{Operation: "broker.OpenPR"}
+	}
+
+	pr, err := b.provider.CreateOrUpdatePullRequest(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open pull request in %s: %w", input.Repo, err)
+	}
+	return pr, nil
+}
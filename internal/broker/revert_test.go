@@ -0,0 +1,119 @@
+package broker_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/goliatone/cascade/internal/broker"
+)
+
+// statusProvider adds pull request lookups to mockProvider.
+type statusProvider struct {
+	*mockProvider
+	status *broker.PullRequestStatus
+}
+
+func (p *statusProvider) GetPullRequest(ctx context.Context, repo string, number int) (*broker.PullRequestStatus, error) {
+	if p.status == nil {
+		return nil, errors.New("not found")
+	}
+	return p.status, nil
+}
+
+func TestBroker_PullRequestStatus(t *testing.T) {
+	pr := &broker.PullRequest{Repo: "owner/repo", Number: 3}
+
+	provider := &statusProvider{
+		mockProvider: &mockProvider{},
+		status:       &broker.PullRequestStatus{State: broker.PullRequestClosed, Merged: true, MergeCommitSHA: "abc123"},
+	}
+	b := broker.New(provider, &mockNotifier{}, broker.DefaultConfig(), &mockLogger{}).(broker.Reverter)
+
+	status, err := b.PullRequestStatus(context.Background(), pr)
+	if err != nil {
+		t.Fatalf("PullRequestStatus() error = %v", err)
+	}
+	if !status.Merged || status.MergeCommitSHA != "abc123" {
+		t.Errorf("PullRequestStatus() = %+v, want merged at abc123", status)
+	}
+
+	provider.status = nil
+	if _, err := b.PullRequestStatus(context.Background(), pr); err == nil || !strings.Contains(err.Error(), "PR #3") {
+		t.Errorf("PullRequestStatus() error = %v, want lookup failure", err)
+	}
+
+	// Providers without lookups report the operation as not implemented
+	plain := broker.New(&mockProvider{}, &mockNotifier{}, broker.DefaultConfig(), &mockLogger{}).(broker.Reverter)
+	var notImplemented *broker.NotImplementedError
+	if _, err := plain.PullRequestStatus(context.Background(), pr); !errors.As(err, &notImplemented) {
+		t.Errorf("PullRequestStatus() error = %v, want NotImplementedError", err)
+	}
+}
+
+func TestBroker_ClosePRWithComment(t *testing.T) {
+	pr := &broker.PullRequest{Repo: "owner/repo", Number: 3}
+
+	var calls []string
+	provider := &mockProvider{
+		addComment: func(ctx context.Context, repo string, number int, body string) error {
+			calls = append(calls, "comment:"+body)
+			return errors.New("comments disabled")
+		},
+		closePR: func(ctx context.Context, repo string, number int) error {
+			calls = append(calls, "close")
+			return nil
+		},
+	}
+	b := broker.New(provider, &mockNotifier{}, broker.DefaultConfig(), &mockLogger{}).(broker.Reverter)
+
+	// A failed comment is logged, and the pull request is still closed
+	if err := b.ClosePRWithComment(context.Background(), pr, "rolled back"); err != nil {
+		t.Fatalf("ClosePRWithComment() error = %v", err)
+	}
+	if strings.Join(calls, ",") != "comment:rolled back,close" {
+		t.Errorf("provider calls = %v, want comment then close", calls)
+	}
+
+	provider.closePR = func(ctx context.Context, repo string, number int) error {
+		return errors.New("forbidden")
+	}
+	err := b.ClosePRWithComment(context.Background(), pr, "rolled back")
+	if err == nil || !strings.Contains(err.Error(), "forbidden") || !strings.Contains(err.Error(), "comments disabled") {
+		t.Errorf("ClosePRWithComment() error = %v, want close and comment failures", err)
+	}
+
+	calls = nil
+	cfg := broker.DefaultConfig()
+	cfg.DryRun = true
+	dryRun := broker.New(provider, &mockNotifier{}, cfg, &mockLogger{}).(broker.Reverter)
+	if err := dryRun.ClosePRWithComment(context.Background(), pr, "rolled back"); err != nil || len(calls) != 0 {
+		t.Errorf("dry run ClosePRWithComment() error = %v, calls = %v", err, calls)
+	}
+}
+
+func TestBroker_OpenPR(t *testing.T) {
+	var got broker.PRInput
+	provider := &mockProvider{
+		createOrUpdatePR: func(ctx context.Context, input broker.PRInput) (*broker.PullRequest, error) {
+			got = input
+			return &broker.PullRequest{Repo: input.Repo, Number: 9, URL: "https://github.com/owner/repo/pull/9"}, nil
+		},
+	}
+	b := broker.New(provider, &mockNotifier{}, broker.DefaultConfig(), &mockLogger{}).(broker.Reverter)
+
+	input := broker.PRInput{Repo: "owner/repo", BaseBranch: "main", HeadBranch: "cascade/revert", Title: "Revert update", Body: "Rolls back the update."}
+	pr, err := b.OpenPR(context.Background(), input)
+	if err != nil {
+		t.Fatalf("OpenPR() error = %v", err)
+	}
+	if pr.Number != 9 || got.HeadBranch != "cascade/revert" || got.BaseBranch != "main" {
+		t.Errorf("OpenPR() = %+v with input %+v", pr, got)
+	}
+
+	input.BaseBranch = ""
+	if _, err := b.OpenPR(context.Background(), input); err == nil {
+		t.Error("expected error without a base branch")
+	}
+}
@@ -0,0 +1,198 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/goliatone/cascade/internal/manifest"
+	"github.com/goliatone/cascade/pkg/gitutil"
+)
+
+// Provider names accepted by a dependent's provider setting.
+const (
+	ProviderGitHub    = manifest.ProviderGitHub
+	ProviderGitLab    = manifest.ProviderGitLab
+	ProviderBitbucket = manifest.ProviderBitbucket
+)
+
+type providerNameKey struct{}
+
+// WithProviderName returns a context whose provider requests go to the named
+// provider instead of the one for the repository's host. Empty names leave ctx
+// unchanged.
+func WithProviderName(ctx context.Context, name string) context.Context {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, providerNameKey{}, name)
+}
+
+// ProviderNameFromContext returns the name recorded with WithProviderName, if any.
+func ProviderNameFromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	name, ok := ctx.Value(providerNameKey{}).(string)
+	return name, ok
+}
+
+// RoutingProvider sends each repository to the provider for its git host, so a
+// cascade can update dependents on GitHub, GitLab, and Bitbucket in one run.
+// Repositories written as owner/name belong to the default host. A provider
+// named with WithProviderName takes precedence over the host.
+type RoutingProvider struct {
+	fallback    Provider
+	defaultHost string
+	routes      map[string]Provider
+	named       map[string]Provider
+}
+
+// RoutingOption configures a RoutingProvider.
+type RoutingOption func(*RoutingProvider)
+
+// WithNamedProvider makes provider selectable by name through WithProviderName.
+func WithNamedProvider(name string, provider Provider) RoutingOption {
+	return func(r *RoutingProvider) {
+		if provider != nil {
+			r.named[strings.ToLower(name)] = provider
+		}
+	}
+}
+
+// NewRoutingProvider routes repositories on the hosts in routes to their
+// providers and all others to fallback.
+func NewRoutingProvider(fallback Provider, defaultHost string, routes map[string]Provider, opts ...RoutingOption) *RoutingProvider {
+	normalized := make(map[string]Provider, len(routes))
+	for host, provider := range routes {
+		normalized[strings.ToLower(host)] = provider
+	}
+	r := &RoutingProvider{
+		fallback:    fallback,
+		defaultHost: strings.ToLower(defaultHost),
+		routes:      normalized,
+		named:       map[string]Provider{},
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+var (
+	_ Provider          = (*RoutingProvider)(nil)
+	_ PullRequestGetter = (*RoutingProvider)(nil)
+)
+
+// providerFor returns the provider named in ctx, or else the one for the host
+// of repo.
+func (r *RoutingProvider) providerFor(ctx context.Context, repo string) (Provider, error) {
+	if name, ok := ProviderNameFromContext(ctx); ok {
+		provider, ok := r.named[name]
+		if !ok {
+			return nil, fmt.Errorf("provider %s requested for %s is not configured", name, repo)
+		}
+		return provider, nil
+	}
+	if provider, ok := r.routes[repoHost(repo, r.defaultHost)]; ok {
+		return provider, nil
+	}
+	return r.fallback, nil
+}
+
+// repoHost returns the lowercased host of a repository identifier.
+func repoHost(repo, defaultHost string) string {
+	if !strings.Contains(repo, "://") && !strings.HasPrefix(repo, "git@") && strings.Count(repo, "/") == 1 {
+		return defaultHost
+	}
+	parsed, err := gitutil.ParseRepoURL(repo)
+	if err != nil {
+		return defaultHost
+	}
+	return strings.ToLower(parsed.Host)
+}
+
+func (r *RoutingProvider) CreateOrUpdatePullRequest(ctx context.Context, input PRInput) (*PullRequest, error) {
+	provider, err := r.providerFor(ctx, input.Repo)
+	if err != nil {
+		return nil, err
+	}
+	return provider.CreateOrUpdatePullRequest(ctx, input)
+}
+
+func (r *RoutingProvider) AddLabels(ctx context.Context, repo string, number int, labels []string) error {
+	provider, err := r.providerFor(ctx, repo)
+	if err != nil {
+		return err
+	}
+	return provider.AddLabels(ctx, repo, number, labels)
+}
+
+func (r *RoutingProvider) RequestReviewers(ctx context.Context, repo string, number int, reviewers []string, teamReviewers []string) error {
+	provider, err := r.providerFor(ctx, repo)
+	if err != nil {
+		return err
+	}
+	return provider.RequestReviewers(ctx, repo, number, reviewers, teamReviewers)
+}
+
+func (r *RoutingProvider) ListPullRequests(ctx context.Context, repo string, headBranch string) ([]*PullRequest, error) {
+	provider, err := r.providerFor(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	return provider.ListPullRequests(ctx, repo, headBranch)
+}
+
+func (r *RoutingProvider) AddComment(ctx context.Context, repo string, number int, body string) error {
+	provider, err := r.providerFor(ctx, repo)
+	if err != nil {
+		return err
+	}
+	return provider.AddComment(ctx, repo, number, body)
+}
+
+func (r *RoutingProvider) MergePullRequest(ctx context.Context, repo string, number int, opts MergeOptions) (*MergeResult, error) {
+	provider, err := r.providerFor(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	return provider.MergePullRequest(ctx, repo, number, opts)
+}
+
+func (r *RoutingProvider) ClosePullRequest(ctx context.Context, repo string, number int) error {
+	provider, err := r.providerFor(ctx, repo)
+	if err != nil {
+		return err
+	}
+	return provider.ClosePullRequest(ctx, repo, number)
+}
+
+// GetPullRequest looks the pull request up when the repository's provider
+// implements PullRequestGetter.
+func (r *RoutingProvider) GetPullRequest(ctx context.Context, repo string, number int) (*PullRequestStatus, error) {
+	provider, err := r.providerFor(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	getter, ok := provider.(PullRequestGetter)
+	if !ok {
+		return nil, &NotImplementedError{Operation: "provider.GetPullRequest"}
+	}
+	return getter.GetPullRequest(ctx, repo, number)
+}
+
+// GetPullRequestChecks reports the checks when the repository's provider
+// implements ChecksGetter.
+func (r *RoutingProvider) GetPullRequestChecks(ctx context.Context, repo string, number int) (*ChecksStatus, error) {
+	provider, err := r.providerFor(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	getter, ok := provider.(ChecksGetter)
+	if !ok {
+		return nil, &NotImplementedError{Operation: "provider.GetPullRequestChecks"}
+	}
+	return getter.GetPullRequestChecks(ctx, repo, number)
+}
@@ -0,0 +1,93 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// recordingProvider reports which provider handled a call.
+type recordingProvider struct {
+	Provider
+	name  string
+	calls *[]string
+}
+
+func (p recordingProvider) AddComment(ctx context.Context, repo string, number int, body string) error {
+	*p.calls = append(*p.calls, p.name+":"+repo)
+	return nil
+}
+
+func TestRoutingProvider(t *testing.T) {
+	var calls []string
+	github := recordingProvider{name: "github", calls: &calls}
+	gitlab := recordingProvider{name: "gitlab", calls: &calls}
+
+	router := NewRoutingProvider(github, "github.com", map[string]Provider{"GitLab.example.com": gitlab})
+	for _, repo := range []string{
+		"owner/repo",
+		"gitlab.example.com/group/project",
+		"https://gitlab.example.com/group/project.git",
+		"github.com/owner/repo",
+	} {
+		if err := router.AddComment(context.Background(), repo, 1, "hi"); err != nil {
+			t.Fatalf("AddComment(%s) error = %v", repo, err)
+		}
+	}
+
+	want := []string{
+		"github:owner/repo",
+		"gitlab:gitlab.example.com/group/project",
+		"gitlab:https://gitlab.example.com/group/project.git",
+		"github:github.com/owner/repo",
+	}
+	for i := range want {
+		if i >= len(calls) || calls[i] != want[i] {
+			t.Fatalf("calls = %v, want %v", calls, want)
+		}
+	}
+
+	// Shorthands follow the default host
+	calls = nil
+	router = NewRoutingProvider(github, "gitlab.example.com", map[string]Provider{"gitlab.example.com": gitlab})
+	_ = router.AddComment(context.Background(), "group/project", 1, "hi")
+	if len(calls) != 1 || calls[0] != "gitlab:group/project" {
+		t.Errorf("calls = %v, want the shorthand routed to GitLab", calls)
+	}
+
+	var notImplemented *NotImplementedError
+	if _, err := router.GetPullRequest(context.Background(), "group/project", 1); !errors.As(err, &notImplemented) {
+		t.Errorf("GetPullRequest() error = %v, want NotImplementedError", err)
+	}
+}
+
+func TestRoutingProvider_NamedProvider(t *testing.T) {
+	var calls []string
+	github := recordingProvider{name: "github", calls: &calls}
+	bitbucket := recordingProvider{name: "bitbucket", calls: &calls}
+
+	router := NewRoutingProvider(github, "github.com", map[string]Provider{BitbucketHost: bitbucket},
+		WithNamedProvider(ProviderGitHub, github),
+		WithNamedProvider(ProviderBitbucket, bitbucket),
+	)
+
+	_ = router.AddComment(context.Background(), "bitbucket.org/team/app", 1, "hi")
+	_ = router.AddComment(WithProviderName(context.Background(), "Bitbucket"), "code.example.com/team/app", 1, "hi")
+	_ = router.AddComment(WithProviderName(context.Background(), ""), "team/app", 1, "hi")
+
+	want := []string{"bitbucket:bitbucket.org/team/app", "bitbucket:code.example.com/team/app", "github:team/app"}
+	if len(calls) != len(want) {
+		t.Fatalf("calls = %v, want %v", calls, want)
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Fatalf("calls = %v, want %v", calls, want)
+		}
+	}
+
+	err := router.AddComment(WithProviderName(context.Background(), ProviderGitLab), "team/app", 1, "hi")
+	if err == nil || !strings.Contains(err.Error(), "provider gitlab requested for team/app is not configured") {
+		t.Errorf("AddComment() error = %v, want unconfigured provider error", err)
+	}
+}
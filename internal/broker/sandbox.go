@@ -0,0 +1,430 @@
+package broker
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/internal/manifest"
+	"github.com/goliatone/cascade/internal/planner"
+)
+
+// ProviderSandbox names the provider that records pull requests locally.
+const ProviderSandbox = manifest.ProviderSandbox
+
+// NotificationChannelSandbox is the channel of the notifier that records
+// notifications locally.
+const NotificationChannelSandbox = "sandbox"
+
+// SandboxProvider implements the Provider interface without a git host: each
+// pull request is a JSON file under dir, at <repo>/pulls/<number>.json, and its
+// URL is the file's file:// URL. It lets a release run end to end without
+// credentials.
+type SandboxProvider struct {
+	dir string
+	now func() time.Time
+
+	mu sync.Mutex
+}
+
+// NewSandboxProvider creates a provider that records pull requests under dir.
+func NewSandboxProvider(dir string) *SandboxProvider {
+	return &SandboxProvider{dir: dir, now: time.Now}
+}
+
+var (
+	_ Provider          = (*SandboxProvider)(nil)
+	_ PullRequestGetter = (*SandboxProvider)(nil)
+)
+
+// SandboxPullRequest is the record SandboxProvider keeps for a pull request.
+type SandboxPullRequest struct {
+	Number         int              `json:"number"`
+	URL            string           `json:"url"`
+	Repo           string           `json:"repo"`
+	State          string           `json:"state"`
+	Merged         bool             `json:"merged"`
+	MergeCommitSHA string           `json:"merge_commit_sha,omitempty"`
+	BaseBranch     string           `json:"base_branch"`
+	HeadBranch     string           `json:"head_branch"`
+	Title          string           `json:"title"`
+	Body           string           `json:"body"`
+	Labels         []string         `json:"labels"`
+	Reviewers      []string         `json:"reviewers,omitempty"`
+	TeamReviewers  []string         `json:"team_reviewers,omitempty"`
+	Draft          bool             `json:"draft,omitempty"`
+	Comments       []SandboxComment `json:"comments,omitempty"`
+	CreatedAt      time.Time        `json:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at"`
+
+	// DeleteBranchOnMerge records that the head branch is to be deleted once
+	// the pull request merges.
+	DeleteBranchOnMerge bool `json:"delete_branch_on_merge,omitempty"`
+}
+
+// SandboxComment is a comment on a sandbox pull request.
+type SandboxComment struct {
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (r *SandboxPullRequest) pullRequest() *PullRequest {
+	return &PullRequest{
+		URL:    r.URL,
+		Number: r.Number,
+		Repo:   r.Repo,
+		Labels: append([]string{}, r.Labels...),
+	}
+}
+
+// CreateOrUpdatePullRequest records a pull request, or updates the open one from
+// the same head branch.
+func (p *SandboxProvider) CreateOrUpdatePullRequest(ctx context.Context, input PRInput) (*PullRequest, error) {
+	if input.Repo == "" {
+		return nil, &ProviderError{Operation: "sandbox.CreateOrUpdatePullRequest", Err: errors.New("repository is required")}
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	records, err := p.load(input.Repo)
+	if err != nil {
+		return nil, err
+	}
+	now := p.now().UTC()
+	for _, record := range records {
+		if record.State == PullRequestOpen && record.HeadBranch == input.HeadBranch {
+			record.BaseBranch = input.BaseBranch
+			record.Title = input.Title
+			record.Body = input.Body
+			record.Labels = mergeLabels(record.Labels, input.Labels)
+			record.DeleteBranchOnMerge = record.DeleteBranchOnMerge || input.DeleteBranchOnMerge
+			record.UpdatedAt = now
+			if err := p.save(record); err != nil {
+				return nil, err
+			}
+			return record.pullRequest(), nil
+		}
+	}
+
+	record := &SandboxPullRequest{
+		Number:     len(records) + 1,
+		Repo:       input.Repo,
+		State:      PullRequestOpen,
+		BaseBranch: input.BaseBranch,
+		HeadBranch: input.HeadBranch,
+		Title:      input.Title,
+		Body:       input.Body,
+		Labels:     mergeLabels(input.Labels, nil),
+		Draft:      input.Draft,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+
+		DeleteBranchOnMerge: input.DeleteBranchOnMerge,
+	}
+	record.URL = (&url.URL{Scheme: "file", Path: filepath.ToSlash(p.path(input.Repo, record.Number))}).String()
+	if err := p.save(record); err != nil {
+		return nil, err
+	}
+	return record.pullRequest(), nil
+}
+
+func (p *SandboxProvider) AddLabels(ctx context.Context, repo string, number int, labels []string) error {
+	return p.update(repo, number, func(record *SandboxPullRequest) error {
+		record.Labels = mergeLabels(record.Labels, labels)
+		return nil
+	})
+}
+
+func (p *SandboxProvider) RequestReviewers(ctx context.Context, repo string, number int, reviewers []string, teamReviewers []string) error {
+	return p.update(repo, number, func(record *SandboxPullRequest) error {
+		record.Reviewers = mergeLabels(record.Reviewers, reviewers)
+		record.TeamReviewers = mergeLabels(record.TeamReviewers, teamReviewers)
+		return nil
+	})
+}
+
+// ListPullRequests returns the open pull requests from headBranch.
+func (p *SandboxProvider) ListPullRequests(ctx context.Context, repo string, headBranch string) ([]*PullRequest, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	records, err := p.load(repo)
+	if err != nil {
+		return nil, err
+	}
+	var prs []*PullRequest
+	for _, record := range records {
+		if record.State == PullRequestOpen && (headBranch == "" || record.HeadBranch == headBranch) {
+			prs = append(prs, record.pullRequest())
+		}
+	}
+	return prs, nil
+}
+
+func (p *SandboxProvider) AddComment(ctx context.Context, repo string, number int, body string) error {
+	return p.update(repo, number, func(record *SandboxPullRequest) error {
+		record.Comments = append(record.Comments, SandboxComment{Body: body, CreatedAt: p.now().UTC()})
+		return nil
+	})
+}
+
+// MergePullRequest marks the pull request merged with a made-up merge commit.
+func (p *SandboxProvider) MergePullRequest(ctx context.Context, repo string, number int, opts MergeOptions) (*MergeResult, error) {
+	var result *MergeResult
+	err := p.update(repo, number, func(record *SandboxPullRequest) error {
+		if record.State != PullRequestOpen {
+			return fmt.Errorf("pull request #%d is %s", number, record.State)
+		}
+		sum := sha1.Sum([]byte(record.Repo + "#" + strconv.Itoa(record.Number) + "@" + record.HeadBranch))
+		record.State = PullRequestClosed
+		record.Merged = true
+		record.MergeCommitSHA = hex.EncodeToString(sum[:])
+		result = &MergeResult{SHA: record.MergeCommitSHA, Merged: true, Message: "merged in sandbox"}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (p *SandboxProvider) ClosePullRequest(ctx context.Context, repo string, number int) error {
+	return p.update(repo, number, func(record *SandboxPullRequest) error {
+		record.State = PullRequestClosed
+		return nil
+	})
+}
+
+func (p *SandboxProvider) GetPullRequest(ctx context.Context, repo string, number int) (*PullRequestStatus, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	record, err := p.read(repo, number)
+	if err != nil {
+		return nil, err
+	}
+	return &PullRequestStatus{
+		State:          record.State,
+		Merged:         record.Merged,
+		MergeCommitSHA: record.MergeCommitSHA,
+		BaseBranch:     record.BaseBranch,
+		HeadBranch:     record.HeadBranch,
+	}, nil
+}
+
+// GetPullRequestChecks reports no checks, since nothing runs CI in the
+// sandbox. The pull request must exist.
+func (p *SandboxProvider) GetPullRequestChecks(ctx context.Context, repo string, number int) (*ChecksStatus, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, err := p.read(repo, number); err != nil {
+		return nil, err
+	}
+	return newChecksStatus(), nil
+}
+
+// update applies change to the recorded pull request and saves it.
+func (p *SandboxProvider) update(repo string, number int, change func(*SandboxPullRequest) error) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	record, err := p.read(repo, number)
+	if err != nil {
+		return err
+	}
+	if err := change(record); err != nil {
+		return &ProviderError{Operation: "sandbox.update", Err: err}
+	}
+	record.UpdatedAt = p.now().UTC()
+	return p.save(record)
+}
+
+// load returns the pull requests recorded for repo, in number order.
+func (p *SandboxProvider) load(repo string) ([]*SandboxPullRequest, error) {
+	entries, err := os.ReadDir(filepath.Dir(p.path(repo, 0)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, &ProviderError{Operation: "sandbox.load", Err: err}
+	}
+	var records []*SandboxPullRequest
+	for _, entry := range entries {
+		number, err := strconv.Atoi(strings.TrimSuffix(entry.Name(), ".json"))
+		if err != nil || entry.IsDir() {
+			continue
+		}
+		record, err := p.read(repo, number)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	slices.SortFunc(records, func(a, b *SandboxPullRequest) int { return a.Number - b.Number })
+	return records, nil
+}
+
+func (p *SandboxProvider) read(repo string, number int) (*SandboxPullRequest, error) {
+	data, err := os.ReadFile(p.path(repo, number))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, &ProviderError{Operation: "sandbox.read", Err: fmt.Errorf("pull request #%d not found in %s", number, repo)}
+	}
+	if err != nil {
+		return nil, &ProviderError{Operation: "sandbox.read", Err: err}
+	}
+	var record SandboxPullRequest
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, &ProviderError{Operation: "sandbox.read", Err: fmt.Errorf("decode %s: %w", p.path(repo, number), err)}
+	}
+	return &record, nil
+}
+
+func (p *SandboxProvider) save(record *SandboxPullRequest) error {
+	if record.Labels == nil {
+		record.Labels = []string{}
+	}
+	path := p.path(record.Repo, record.Number)
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return &ProviderError{Operation: "sandbox.save", Err: err}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return &ProviderError{Operation: "sandbox.save", Err: err}
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return &ProviderError{Operation: "sandbox.save", Err: err}
+	}
+	return nil
+}
+
+// path returns the file of a pull request. Its directory lists all the pull
+// requests of repo.
+func (p *SandboxProvider) path(repo string, number int) string {
+	dir, _ := filepath.Abs(p.dir)
+	return filepath.Join(dir, sandboxRepoDir(repo), "pulls", strconv.Itoa(number)+".json")
+}
+
+var sandboxUnsafeChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// sandboxRepoDir turns a repository identifier such as owner/name or a clone
+// URL into a relative directory path that stays under the sandbox directory.
+func sandboxRepoDir(repo string) string {
+	repo = strings.TrimSuffix(strings.TrimSpace(repo), ".git")
+	if _, rest, ok := strings.Cut(repo, "://"); ok {
+		repo = rest
+	}
+	var parts []string
+	for _, part := range strings.FieldsFunc(repo, func(r rune) bool { return r == '/' || r == ':' }) {
+		part = strings.Trim(sandboxUnsafeChars.ReplaceAllString(part, "-"), ".-")
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) == 0 {
+		return "_"
+	}
+	return filepath.Join(parts...)
+}
+
+// SandboxNotifier records notifications as JSON lines in a file instead of
+// delivering them, so notification templates can be tried without Slack or
+// webhook credentials.
+type SandboxNotifier struct {
+	path   string
+	config NotificationConfig
+	now    func() time.Time
+
+	mu sync.Mutex
+}
+
+// NewSandboxNotifier creates a notifier that appends notifications to path.
+func NewSandboxNotifier(path string, config NotificationConfig) *SandboxNotifier {
+	return &SandboxNotifier{path: path, config: config, now: time.Now}
+}
+
+var (
+	_ Notifier     = (*SandboxNotifier)(nil)
+	_ PlanNotifier = (*SandboxNotifier)(nil)
+	_ Alerter      = (*SandboxNotifier)(nil)
+)
+
+// sandboxNotification is a line of the sandbox notification log.
+type sandboxNotification struct {
+	Time    time.Time `json:"time"`
+	Event   string    `json:"event"`
+	Module  string    `json:"module,omitempty"`
+	Repo    string    `json:"repo,omitempty"`
+	Status  string    `json:"status,omitempty"`
+	Attempt int       `json:"attempt,omitempty"`
+	Text    string    `json:"text"`
+}
+
+// Send records the notification for a work item, rendered with the sandbox
+// channel templates.
+func (n *SandboxNotifier) Send(ctx context.Context, item planner.WorkItem, result *executor.Result) (*NotificationResult, error) {
+	status := resultStatus(result)
+	message, err := renderNotification(n.config.TemplateFor(NotificationChannelSandbox, status), n.config.templateData(ctx, item, result))
+	if err != nil {
+		return nil, &NotificationError{Channel: NotificationChannelSandbox, Err: fmt.Errorf("render notification template: %w", err)}
+	}
+	return n.record(sandboxNotification{
+		Event:   "item",
+		Module:  item.Module,
+		Repo:    item.Repo,
+		Status:  string(status),
+		Attempt: AttemptFromContext(ctx),
+		Text:    message,
+	})
+}
+
+// SendPlan records a plan summary.
+func (n *SandboxNotifier) SendPlan(ctx context.Context, summary PlanSummary) (*NotificationResult, error) {
+	message, err := RenderPlanSummary(summary)
+	if err != nil {
+		return nil, &NotificationError{Channel: NotificationChannelSandbox, Err: fmt.Errorf("render plan summary: %w", err)}
+	}
+	return n.record(sandboxNotification{Event: "plan", Module: summary.Module, Text: message})
+}
+
+// Alert records an operational alert.
+func (n *SandboxNotifier) Alert(ctx context.Context, message string) (*NotificationResult, error) {
+	return n.record(sandboxNotification{Event: "alert", Text: message})
+}
+
+func (n *SandboxNotifier) record(entry sandboxNotification) (*NotificationResult, error) {
+	entry.Time = n.now().UTC()
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return nil, &NotificationError{Channel: NotificationChannelSandbox, Err: err}
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(n.path), 0o755); err != nil {
+		return nil, &NotificationError{Channel: NotificationChannelSandbox, Err: err}
+	}
+	f, err := os.OpenFile(n.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, &NotificationError{Channel: NotificationChannelSandbox, Err: err}
+	}
+	defer f.Close()
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return nil, &NotificationError{Channel: NotificationChannelSandbox, Err: err}
+	}
+	return &NotificationResult{Channel: NotificationChannelSandbox, Message: entry.Text}, nil
+}
@@ -0,0 +1,148 @@
+package broker
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/internal/planner"
+)
+
+func TestSandboxProvider_PullRequestLifecycle(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	provider := NewSandboxProvider(dir)
+
+	pr, err := provider.CreateOrUpdatePullRequest(ctx, PRInput{
+		Repo:       "example/api",
+		BaseBranch: "main",
+		HeadBranch: "cascade/lib-v1.2.0",
+		Title:      "Bump lib",
+		Labels:     []string{"automation:cascade"},
+	})
+	if err != nil {
+		t.Fatalf("CreateOrUpdatePullRequest() error = %v", err)
+	}
+	file := filepath.Join(dir, "example", "api", "pulls", "1.json")
+	if pr.Number != 1 || pr.URL != "file://"+filepath.ToSlash(file) {
+		t.Fatalf("unexpected pull request: %+v", pr)
+	}
+
+	updated, err := provider.CreateOrUpdatePullRequest(ctx, PRInput{
+		Repo:       "example/api",
+		BaseBranch: "main",
+		HeadBranch: "cascade/lib-v1.2.0",
+		Title:      "Bump lib to v1.2.0",
+		Labels:     []string{"semver:minor"},
+
+		DeleteBranchOnMerge: true,
+	})
+	if err != nil || updated.Number != 1 {
+		t.Fatalf("expected the open pull request to be updated, got %+v, %v", updated, err)
+	}
+	if err := provider.RequestReviewers(ctx, "example/api", 1, []string{"alice"}, nil); err != nil {
+		t.Fatalf("RequestReviewers() error = %v", err)
+	}
+	if err := provider.AddComment(ctx, "example/api", 1, "tests passed"); err != nil {
+		t.Fatalf("AddComment() error = %v", err)
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var record SandboxPullRequest
+	if err := json.Unmarshal(data, &record); err != nil {
+		t.Fatal(err)
+	}
+	if record.Title != "Bump lib to v1.2.0" || strings.Join(record.Labels, ",") != "automation:cascade,semver:minor" ||
+		len(record.Reviewers) != 1 || len(record.Comments) != 1 || !record.DeleteBranchOnMerge {
+		t.Errorf("unexpected record: %s", data)
+	}
+
+	prs, err := provider.ListPullRequests(ctx, "example/api", "cascade/lib-v1.2.0")
+	if err != nil || len(prs) != 1 {
+		t.Fatalf("ListPullRequests() = %v, %v", prs, err)
+	}
+
+	merged, err := provider.MergePullRequest(ctx, "example/api", 1, MergeOptions{})
+	if err != nil || !merged.Merged || len(merged.SHA) != 40 {
+		t.Fatalf("MergePullRequest() = %+v, %v", merged, err)
+	}
+	status, err := provider.GetPullRequest(ctx, "example/api", 1)
+	if err != nil || status.State != PullRequestClosed || !status.Merged || status.MergeCommitSHA != merged.SHA {
+		t.Fatalf("GetPullRequest() = %+v, %v", status, err)
+	}
+	if _, err := provider.MergePullRequest(ctx, "example/api", 1, MergeOptions{}); err == nil {
+		t.Error("expected merging a closed pull request to fail")
+	}
+
+	next, err := provider.CreateOrUpdatePullRequest(ctx, PRInput{Repo: "example/api", BaseBranch: "main", HeadBranch: "cascade/lib-v1.2.0"})
+	if err != nil || next.Number != 2 {
+		t.Fatalf("expected a new pull request once the first closed, got %+v, %v", next, err)
+	}
+	if err := provider.ClosePullRequest(ctx, "example/api", 2); err != nil {
+		t.Fatalf("ClosePullRequest() error = %v", err)
+	}
+	if prs, _ := provider.ListPullRequests(ctx, "example/api", ""); len(prs) != 0 {
+		t.Errorf("expected no open pull requests, got %v", prs)
+	}
+	if err := provider.AddLabels(ctx, "example/api", 9, []string{"x"}); err == nil {
+		t.Error("expected an error for an unknown pull request")
+	}
+}
+
+func TestSandboxRepoDir(t *testing.T) {
+	for repo, want := range map[string]string{
+		"example/api":                          "example/api",
+		"https://gitlab.com/group/sub/api.git": "gitlab.com/group/sub/api",
+		"git@github.com:Example/API.git":       "git-github.com/Example/API",
+		"../../etc/passwd":                     "etc/passwd",
+		"":                                     "_",
+	} {
+		if got := sandboxRepoDir(repo); got != filepath.FromSlash(want) {
+			t.Errorf("sandboxRepoDir(%q) = %q, want %q", repo, got, want)
+		}
+	}
+}
+
+func TestSandboxNotifier(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notifications.jsonl")
+	cfg := DefaultNotificationConfig()
+	cfg.Templates.Channels = map[string]ChannelTemplates{
+		NotificationChannelSandbox: {Default: "{{.Repo}} {{.Status}}"},
+	}
+	notifier := NewSandboxNotifier(path, cfg)
+
+	item := planner.WorkItem{Module: "github.com/example/lib", Repo: "example/api"}
+	res, err := notifier.Send(WithAttempt(context.Background(), 2), item, &executor.Result{Status: executor.StatusFailed})
+	if err != nil || res.Channel != NotificationChannelSandbox || res.Message != "example/api failed" {
+		t.Fatalf("Send() = %+v, %v", res, err)
+	}
+	if _, err := notifier.Alert(context.Background(), "rate limit at 90%"); err != nil {
+		t.Fatalf("Alert() error = %v", err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var entries []sandboxNotification
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry sandboxNotification
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) != 2 || entries[0].Event != "item" || entries[0].Attempt != 2 || entries[0].Status != "failed" || entries[1].Event != "alert" {
+		t.Errorf("unexpected notification log: %+v", entries)
+	}
+}
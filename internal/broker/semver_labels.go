@@ -0,0 +1,48 @@
+package broker
+
+import (
+	"github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/internal/planner"
+	"github.com/goliatone/cascade/pkg/templatefuncs"
+)
+
+// Semver delta labels added to pull requests when Config.SemverLabels is set.
+const (
+	LabelSemverMajor = "semver:major"
+	LabelSemverMinor = "semver:minor"
+	LabelSemverPatch = "semver:patch"
+)
+
+// SemverLabel returns the label describing how far the update moved the
+// dependent's required version, comparing the version go.mod required before
+// the update with the one it requires after. Changes within a patch release,
+// such as a newer pre-release, count as patch. It returns "" when either
+// version is missing or not semver, or when the version did not change.
+func SemverLabel(impact *executor.DependencyImpact) string {
+	if impact == nil || !impact.OldVersionDetected || !impact.NewVersionDetected {
+		return ""
+	}
+
+	switch templatefuncs.SemverDelta(impact.OldVersion, impact.NewVersion) {
+	case "major":
+		return LabelSemverMajor
+	case "minor":
+		return LabelSemverMinor
+	case "patch", "prerelease":
+		return LabelSemverPatch
+	default:
+		return ""
+	}
+}
+
+// prLabels returns the labels of the pull request for item: the default labels,
+// the item's labels, and, when enabled, the semver delta label of result.
+func prLabels(config Config, item planner.WorkItem, result *executor.Result) []string {
+	labels := mergeLabels(config.DefaultLabels, item.Labels)
+	if config.SemverLabels && result != nil {
+		if label := SemverLabel(result.DependencyImpact); label != "" {
+			labels = mergeLabels(labels, []string{label})
+		}
+	}
+	return labels
+}
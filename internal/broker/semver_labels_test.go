@@ -0,0 +1,94 @@
+package broker_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/goliatone/cascade/internal/broker"
+	"github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/internal/planner"
+)
+
+func TestSemverLabel(t *testing.T) {
+	tests := []struct {
+		name   string
+		impact *executor.DependencyImpact
+		want   string
+	}{
+		{name: "patch", impact: detectedImpact("v1.2.3", "v1.2.4"), want: broker.LabelSemverPatch},
+		{name: "minor", impact: detectedImpact("v1.2.3", "v1.3.0"), want: broker.LabelSemverMinor},
+		{name: "major", impact: detectedImpact("v1.9.0", "v2.0.0"), want: broker.LabelSemverMajor},
+		{name: "pre-release", impact: detectedImpact("v1.3.0-rc.1", "v1.3.0"), want: broker.LabelSemverPatch},
+		{name: "pseudo-version to release", impact: detectedImpact("v0.0.0-20240101000000-abcdef123456", "v0.1.0"), want: broker.LabelSemverMinor},
+		{name: "incompatible", impact: detectedImpact("v2.0.0+incompatible", "v3.1.0+incompatible"), want: broker.LabelSemverMajor},
+		{name: "unchanged", impact: detectedImpact("v1.2.3", "v1.2.3")},
+		{name: "old version not detected", impact: &executor.DependencyImpact{NewVersion: "v1.2.4", NewVersionDetected: true}},
+		{name: "not semver", impact: detectedImpact("master", "v1.2.4")},
+		{name: "no impact"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := broker.SemverLabel(tt.impact); got != tt.want {
+				t.Errorf("SemverLabel() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBroker_EnsurePR_SemverLabels(t *testing.T) {
+	item := planner.WorkItem{
+		Repo:          "goliatone/app",
+		Module:        "github.com/goliatone/go-errors",
+		SourceVersion: "v1.3.0",
+		Branch:        "main",
+		BranchName:    "cascade/go-errors-v1.3.0",
+		Labels:        []string{"dependencies"},
+	}
+	result := &executor.Result{Status: executor.StatusCompleted, DependencyImpact: detectedImpact("v1.2.3", "v1.3.0")}
+
+	tests := []struct {
+		name   string
+		config func(cfg *broker.Config)
+		result *executor.Result
+		want   []string
+	}{
+		{name: "adds the delta label", result: result, want: []string{"automation:cascade", "dependencies", broker.LabelSemverMinor}},
+		{name: "disabled", config: func(cfg *broker.Config) { cfg.SemverLabels = false }, result: result, want: []string{"automation:cascade", "dependencies"}},
+		{name: "no dependency impact", result: &executor.Result{Status: executor.StatusCompleted}, want: []string{"automation:cascade", "dependencies"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var labels []string
+			provider := &mockProvider{createOrUpdatePR: func(ctx context.Context, input broker.PRInput) (*broker.PullRequest, error) {
+				labels = input.Labels
+				return &broker.PullRequest{Repo: input.Repo, Number: 1, Labels: input.Labels}, nil
+			}}
+			cfg := broker.DefaultConfig()
+			if tt.config != nil {
+				tt.config(&cfg)
+			}
+
+			b := broker.New(provider, broker.NewNoOpNotifier(), cfg, &mockLogger{})
+			if _, err := b.EnsurePR(context.Background(), item, tt.result); err != nil {
+				t.Fatalf("EnsurePR() error = %v", err)
+			}
+			if !reflect.DeepEqual(labels, tt.want) {
+				t.Errorf("labels = %v, want %v", labels, tt.want)
+			}
+		})
+	}
+}
+
+func detectedImpact(old, updated string) *executor.DependencyImpact {
+	return &executor.DependencyImpact{
+		Module:             "github.com/goliatone/go-errors",
+		OldVersion:         old,
+		NewVersion:         updated,
+		OldVersionDetected: true,
+		NewVersionDetected: true,
+		Applied:            old != updated,
+	}
+}
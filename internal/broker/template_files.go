@@ -0,0 +1,87 @@
+package broker
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/internal/planner"
+)
+
+// prTemplateFiles caches PR body template files shared by every broker in the
+// process, so a release parses each file once.
+var prTemplateFiles = newTemplateFileCache()
+
+// templateFileCache holds parsed template files keyed by path. An entry is
+// reparsed when the file's size or modification time changes.
+type templateFileCache struct {
+	mu      sync.Mutex
+	entries map[string]templateFileEntry
+}
+
+type templateFileEntry struct {
+	size    int64
+	modTime time.Time
+	tmpl    *template.Template
+	err     error
+}
+
+func newTemplateFileCache() *templateFileCache {
+	return &templateFileCache{entries: make(map[string]templateFileEntry)}
+}
+
+// load returns the parsed template at path. Parse errors are cached with the
+// file's version, so a bad template is reported once per change rather than
+// reparsed for every work item.
+func (c *templateFileCache) load(path string) (*template.Template, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, &TemplateRenderError{TemplateName: path, Operation: "read", Err: err}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if entry, ok := c.entries[path]; ok && entry.size == info.Size() && entry.modTime.Equal(info.ModTime()) {
+		return entry.tmpl, entry.err
+	}
+
+	entry := templateFileEntry{size: info.Size(), modTime: info.ModTime()}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, &TemplateRenderError{TemplateName: path, Operation: "read", Err: err}
+	}
+	// Naming the template after the file makes parse and execution errors
+	// point at file:line
+	entry.tmpl, err = template.New(filepath.Base(path)).Funcs(templateFuncMap).Parse(string(data))
+	if err != nil {
+		entry.tmpl = nil
+		entry.err = &TemplateRenderError{TemplateName: path, Operation: "parse", Err: err}
+	}
+	c.entries[path] = entry
+	return entry.tmpl, entry.err
+}
+
+// RenderBodyFile renders a PR body from the Go template file at path with work
+// item and result data. Unlike RenderBody, a template that cannot be read,
+// parsed, or executed is an error rather than a fallback to the default body,
+// since the file was chosen explicitly.
+func RenderBodyFile(path string, item planner.WorkItem, result *executor.Result) (string, error) {
+	tmpl, err := prTemplateFiles.load(path)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, buildTemplateData(item, result)); err != nil {
+		return "", &TemplateRenderError{TemplateName: path, Operation: "execute", Err: err}
+	}
+	if len(bytes.TrimSpace(buf.Bytes())) == 0 {
+		return "", &TemplateRenderError{TemplateName: path, Operation: "execute", Err: fmt.Errorf("template rendered an empty body")}
+	}
+	return buf.String(), nil
+}
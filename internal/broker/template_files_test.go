@@ -0,0 +1,101 @@
+package broker_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goliatone/cascade/internal/broker"
+	"github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/internal/manifest"
+	"github.com/goliatone/cascade/internal/planner"
+)
+
+func TestPreviewPR_BodyTemplateFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "body.md.tmpl")
+	writeTemplateFile(t, path, "Bump {{.SourceModule}} in {{.Item.Repo}}\n{{.TestSummary}}\n{{.DependencySummary}}\n{{.Result.Status}}")
+
+	item := planner.WorkItem{
+		Repo:          "acme/api",
+		Module:        "github.com/acme/api",
+		SourceModule:  "github.com/acme/lib",
+		SourceVersion: "v1.3.0",
+		Branch:        "main",
+		BranchName:    "auto/lib-v1.3.0",
+		PR:            manifest.PRConfig{BodyTemplateFile: path},
+	}
+	result := &executor.Result{
+		Status:      executor.StatusCompleted,
+		TestResults: []executor.CommandResult{{}, {Err: errors.New("exit 1")}},
+		DependencyImpact: &executor.DependencyImpact{
+			Module:             "github.com/acme/lib",
+			OldVersion:         "v1.2.0",
+			OldVersionDetected: true,
+			NewVersion:         "v1.3.0",
+			NewVersionDetected: true,
+		},
+	}
+
+	config := broker.DefaultConfig()
+	config.BodyTemplate = "configured body"
+	preview, err := broker.PreviewPR(config, item, result)
+	if err != nil {
+		t.Fatalf("PreviewPR() error = %v", err)
+	}
+	want := "Bump github.com/acme/lib in acme/api\n1 of 2 test commands passed\ngithub.com/acme/lib -> v1.3.0 (was v1.2.0)\ncompleted"
+	if preview.Body != want {
+		t.Errorf("Body = %q, want %q", preview.Body, want)
+	}
+
+	// Edits are picked up without restarting
+	writeTemplateFile(t, path, "edited {{.Repo}}")
+	preview, err = broker.PreviewPR(config, item, result)
+	if err != nil || preview.Body != "edited acme/api" {
+		t.Errorf("expected the edited template, got %q, %v", preview.Body, err)
+	}
+}
+
+func TestRenderBodyFile_Errors(t *testing.T) {
+	dir := t.TempDir()
+	item := planner.WorkItem{Repo: "acme/api", Module: "github.com/acme/api", SourceVersion: "v1.3.0"}
+
+	tests := []struct {
+		name     string
+		content  string
+		wantOp   string
+		wantText string
+	}{
+		{name: "missing", wantOp: "read", wantText: "no such file"},
+		{name: "unclosed action", content: "line one\n{{.Repo", wantOp: "parse", wantText: "unclosed.md.tmpl:2"},
+		{name: "unknown field", content: "{{.Nope}}", wantOp: "execute", wantText: "can't evaluate field Nope"},
+		{name: "empty body", content: "{{if false}}x{{end}}", wantOp: "execute", wantText: "empty body"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, strings.Fields(tt.name)[0]+".md.tmpl")
+			if tt.content != "" {
+				writeTemplateFile(t, path, tt.content)
+			}
+			_, err := broker.RenderBodyFile(path, item, nil)
+			var renderErr *broker.TemplateRenderError
+			if !errors.As(err, &renderErr) || renderErr.Operation != tt.wantOp || !strings.Contains(err.Error(), tt.wantText) {
+				t.Fatalf("RenderBodyFile() error = %v, want a %s error mentioning %q", err, tt.wantOp, tt.wantText)
+			}
+		})
+	}
+}
+
+func writeTemplateFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// Make each write visible to the cache even within one clock tick
+	modTime := time.Now().Add(time.Duration(len(content)) * time.Second)
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
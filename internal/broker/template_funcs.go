@@ -0,0 +1,15 @@
+package broker
+
+import "github.com/goliatone/cascade/pkg/templatefuncs"
+
+// TemplateFunc documents a helper available to PR and notification templates.
+type TemplateFunc = templatefuncs.Func
+
+// TemplateFuncs returns documentation for the helpers available to PR and
+// notification templates, sorted by name.
+func TemplateFuncs() []TemplateFunc {
+	return templatefuncs.Docs()
+}
+
+// templateFuncMap provides safe template functions
+var templateFuncMap = templatefuncs.FuncMap()
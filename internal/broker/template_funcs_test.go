@@ -0,0 +1,55 @@
+package broker
+
+import (
+	"testing"
+
+	"github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/internal/planner"
+)
+
+func TestRenderBodyWithExtendedFuncs(t *testing.T) {
+	item := planner.WorkItem{
+		Module:        "github.com/example/dependency",
+		SourceVersion: "v2.3.4",
+		Repo:          "example/myapp",
+		Labels:        []string{"deps", "automation"},
+	}
+	result := &executor.Result{
+		Status:     executor.StatusCompleted,
+		CommitHash: "0123456789abcdef",
+		DependencyImpact: &executor.DependencyImpact{
+			Module:     "github.com/example/dependency",
+			OldVersion: "v1.9.0",
+			NewVersion: "v2.3.4",
+		},
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{name: "semver parts", template: "{{semverMajor .SourceVersion}}.{{semverMinor .SourceVersion}}.{{semverPatch .SourceVersion}}", expected: "2.3.4"},
+		{name: "semver invalid", template: "{{semverMajor .Reason}}", expected: "0"},
+		{name: "semver delta", template: "{{semverDelta .DependencyOld .DependencyNew}}", expected: "major"},
+		{name: "short sha", template: "{{shortSHA .CommitHash}}", expected: "0123456"},
+		{name: "truncate", template: `{{truncate .Module 12}}`, expected: "github.co..."},
+		{name: "to json", template: "{{toJSON .Labels}}", expected: `["deps","automation"]`},
+		{name: "default empty", template: `{{.Reason | default "n/a"}}`, expected: "n/a"},
+		{name: "default set", template: `{{.Repo | default "n/a"}}`, expected: "example/myapp"},
+		{name: "replace", template: `{{.Repo | replace "/" "-"}}`, expected: "example-myapp"},
+		{name: "format date", template: `{{formatDate "2006" .Timestamp | len}}`, expected: "4"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderBody(tt.template, item, result)
+			if err != nil {
+				t.Fatalf("RenderBody() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Fatalf("RenderBody() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
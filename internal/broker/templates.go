@@ -10,6 +10,8 @@ import (
 
 	"github.com/goliatone/cascade/internal/executor"
 	"github.com/goliatone/cascade/internal/planner"
+	"github.com/goliatone/cascade/internal/state"
+	"github.com/goliatone/cascade/pkg/templatefuncs"
 )
 
 // TemplateData contains all available data for template rendering.
@@ -25,6 +27,11 @@ type TemplateData struct {
 	CommitMessage string
 	Labels        []string
 
+	// Updates lists every module the item bumps; a release set bumps several.
+	// UpdatedModules renders them as "module@version, ..."
+	Updates        []planner.ModuleUpdate
+	UpdatedModules string
+
 	// Execution result data
 	Status            string
 	Reason            string
@@ -42,16 +49,43 @@ type TemplateData struct {
 	DependencySummary string
 	DependencyNote    string
 
+	// UpdateSummary lists the bumps as Dependabot's "Bumps ... from ... to ..."
+	// lines, and UpdateMetadata is Dependabot's updated-dependencies block.
+	UpdateSummary  string
+	UpdateMetadata string
+
+	// Links to the state Cascade recorded for the item, set when notifications
+	// are configured with a base URL. StateURL is the item's state file, which
+	// holds its command logs, and SummaryURL the summary of the run.
+	StateURL   string
+	SummaryURL string
+
+	// Annotations are the notes operators attached to the item with
+	// cascade state annotate, oldest first.
+	Annotations []state.Annotation
+
+	// TestSummary counts the passed test commands, e.g. "2 of 3 test commands
+	// passed"; empty before tests run
+	TestSummary string
+
+	// Item and Result are the work item and execution result the data was
+	// built from, for templates needing fields not copied above. Result is
+	// nil before execution.
+	Item   planner.WorkItem
+	Result *executor.Result
+
 	// Metadata
 	Timestamp time.Time
 }
 
 // Default templates
 const (
-	defaultTitleTemplate = "Update {{.Module}} to {{.SourceVersion}}"
+	defaultTitleTemplate = "{{if gt (len .Updates) 1}}Update {{.UpdatedModules}}{{else}}Update {{.Module}} to {{.SourceVersion}}{{end}}"
 	defaultBodyTemplate  = `## Summary
-Updates {{.Module}} from current version to {{.SourceVersion}}.
-
+{{if gt (len .Updates) 1}}Updates {{len .Updates}} modules released together:
+{{range .Updates}}- {{.Module}} to {{.Version}}
+{{end}}{{else}}Updates {{.Module}} from current version to {{.SourceVersion}}.
+{{end}}
 **Repository**: {{.Repo}}
 {{if .BranchName}}**Branch**: {{.BranchName}}{{end}}
 {{if .Status}}**Status**: {{.Status}}{{end}}
@@ -90,18 +124,6 @@ Updates {{.Module}} from current version to {{.SourceVersion}}.
 Generated at {{.Timestamp.Format "2006-01-02 15:04:05 MST"}}`
 )
 
-// templateFuncMap provides safe template functions
-var templateFuncMap = template.FuncMap{
-	"upper":       strings.ToUpper,
-	"lower":       strings.ToLower,
-	"title":       strings.Title,
-	"truncate":    truncateString,
-	"truncate8":   func(s string) string { return truncateString(s, 8) },
-	"truncate200": func(s string) string { return truncateString(s, 200) },
-	"escape":      escapeMarkdown,
-	"join":        joinStrings,
-}
-
 // RenderTitle renders a PR title from a template with work item and result data.
 func RenderTitle(tmpl string, item planner.WorkItem, result *executor.Result) (string, error) {
 	if tmpl == "" {
@@ -134,37 +156,45 @@ func buildTemplateData(item planner.WorkItem, result *executor.Result) TemplateD
 		BranchName:    item.BranchName,
 		CommitMessage: item.CommitMessage,
 		Labels:        item.Labels,
+		Item:          item,
+		Result:        result,
 		Timestamp:     time.Now(),
 	}
+	data.Updates = item.ModuleUpdates()
+	data.UpdatedModules = item.UpdatedModules()
+	updates := executor.DependencyUpdates(item, nil)
 
 	if result != nil {
 		data.Status = string(result.Status)
 		data.Reason = result.Reason
 		data.CommitHash = result.CommitHash
 
+		data.TestSummary = summarizeTests(result.TestResults)
+
 		// Collect test outputs (truncated for safety)
 		for _, testResult := range result.TestResults {
 			if testResult.Output != "" {
-				data.TestOutputs = append(data.TestOutputs, truncateString(testResult.Output, 1000))
+				data.TestOutputs = append(data.TestOutputs, templatefuncs.Truncate(testResult.Output, 1000))
 			}
 		}
 
 		// Collect extra command outputs (truncated for safety)
 		for _, extraResult := range result.ExtraResults {
 			if extraResult.Output != "" {
-				data.ExtraOutputs = append(data.ExtraOutputs, truncateString(extraResult.Output, 1000))
+				data.ExtraOutputs = append(data.ExtraOutputs, templatefuncs.Truncate(extraResult.Output, 1000))
 			}
 		}
 
 		if failure := extractFirstTestFailure(result.TestResults); failure != nil {
 			data.FailureSummary = buildFailureSummary(failure)
 			if failure.Message != "" {
-				data.FailureMessage = truncateString(failure.Message, 280)
+				data.FailureMessage = templatefuncs.Truncate(failure.Message, 280)
 			}
 			data.FailureCommand = failure.Command
 		}
 
 		if impact := result.DependencyImpact; impact != nil {
+			updates = executor.DependencyUpdates(item, impact)
 			data.DependencyModule = impact.Module
 			data.DependencyTarget = impact.TargetVersion
 			data.DependencyOld = impact.OldVersion
@@ -174,13 +204,15 @@ func buildTemplateData(item planner.WorkItem, result *executor.Result) TemplateD
 			data.DependencyNote = formatDependencyNote(impact)
 		}
 	}
+	data.UpdateSummary = executor.DependabotSummary(updates)
+	data.UpdateMetadata = executor.UpdateMetadata(updates)
 
 	return data
 }
 
 // renderTemplate executes a template with the given data.
 func renderTemplate(name, tmpl string, data TemplateData) (string, error) {
-	t, err := template.New(name).Funcs(templateFuncMap).Parse(tmpl)
+	t, err := template.New(name).Funcs(templateFuncMap).Funcs(placeholderFuncs(data)).Parse(tmpl)
 	if err != nil {
 		// Return default on parse error
 		if name == "title" {
@@ -225,6 +257,17 @@ func renderTemplate(name, tmpl string, data TemplateData) (string, error) {
 	return buf.String(), nil
 }
 
+// placeholderFuncs renders the {{ module }}, {{ version }}, and {{ modules }}
+// placeholders of commit templates, which manifest generate also writes into PR
+// templates, as the first updated module, its version, and every update.
+func placeholderFuncs(data TemplateData) template.FuncMap {
+	return template.FuncMap{
+		"module":  func() string { return data.SourceModule },
+		"version": func() string { return data.SourceVersion },
+		"modules": func() string { return data.UpdatedModules },
+	}
+}
+
 type testFailureInsight struct {
 	Package string
 	Test    string
@@ -253,7 +296,7 @@ func extractFirstTestFailure(results []executor.CommandResult) *testFailureInsig
 			if insight.Message == "" {
 				trimmed := strings.TrimSpace(res.Output)
 				if trimmed != "" {
-					insight.Message = truncateString(trimmed, 280)
+					insight.Message = templatefuncs.Truncate(trimmed, 280)
 				} else if res.Err != nil {
 					insight.Message = res.Err.Error()
 				}
@@ -269,12 +312,12 @@ func extractFirstTestFailure(results []executor.CommandResult) *testFailureInsig
 			if execErr, ok := res.Err.(*executor.CommandExecutionError); ok {
 				output := strings.TrimSpace(execErr.Output)
 				if output != "" {
-					fallback.Message = truncateString(output, 280)
+					fallback.Message = templatefuncs.Truncate(output, 280)
 				} else {
 					fallback.Message = execErr.Error()
 				}
 			} else if res.Output != "" {
-				fallback.Message = truncateString(strings.TrimSpace(res.Output), 280)
+				fallback.Message = templatefuncs.Truncate(strings.TrimSpace(res.Output), 280)
 			} else {
 				fallback.Message = res.Err.Error()
 			}
@@ -350,6 +393,24 @@ func parseGoTestFailure(output string) *testFailureInsight {
 	return insight
 }
 
+// summarizeTests reports how many test commands passed.
+func summarizeTests(results []executor.CommandResult) string {
+	if len(results) == 0 {
+		return ""
+	}
+	passed := 0
+	for _, res := range results {
+		if res.Err == nil {
+			passed++
+		}
+	}
+	noun := "test commands"
+	if len(results) == 1 {
+		noun = "test command"
+	}
+	return fmt.Sprintf("%d of %d %s passed", passed, len(results), noun)
+}
+
 func buildFailureSummary(insight *testFailureInsight) string {
 	if insight == nil {
 		return ""
@@ -419,33 +480,3 @@ func formatDependencyNote(impact *executor.DependencyImpact) string {
 
 	return strings.Join(notes, " | ")
 }
-
-// truncateString truncates a string to maxLen characters, adding ellipsis if needed.
-func truncateString(s string, maxLen int) string {
-	if len(s) <= maxLen {
-		return s
-	}
-	return s[:maxLen-3] + "..."
-}
-
-var slackEscapeReplacer = strings.NewReplacer(
-	"&", "&amp;",
-	"<", "&lt;",
-	">", "&gt;",
-	"`", "\\`",
-	"*", "\\*",
-	"[", "\\[",
-	"]", "\\]",
-	"|", "\\|",
-)
-
-// escapeMarkdown escapes characters that would break Slack mrkdwn formatting while
-// keeping common symbols (like parentheses and underscores) readable.
-func escapeMarkdown(s string) string {
-	return slackEscapeReplacer.Replace(s)
-}
-
-// joinStrings is a template function that joins string slices.
-func joinStrings(elems []string, sep string) string {
-	return strings.Join(elems, sep)
-}
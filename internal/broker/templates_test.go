@@ -64,6 +64,43 @@ func TestRenderTitle(t *testing.T) {
 	}
 }
 
+func TestRenderReleaseSetTemplates(t *testing.T) {
+	item := planner.WorkItem{
+		Module:        "github.com/example/myapp",
+		SourceModule:  "github.com/example/a",
+		SourceVersion: "v1.2.0",
+		Updates: []planner.ModuleUpdate{
+			{Module: "github.com/example/a", Version: "v1.2.0"},
+			{Module: "github.com/example/b", Version: "v2.0.0"},
+		},
+		Repo: "github.com/example/myapp",
+	}
+
+	title, err := RenderTitle("", item, nil)
+	if err != nil {
+		t.Fatalf("RenderTitle() error = %v", err)
+	}
+	if title != "Update github.com/example/a@v1.2.0, github.com/example/b@v2.0.0" {
+		t.Fatalf("unexpected default title %q", title)
+	}
+
+	body, err := RenderBody("", item, nil)
+	if err != nil {
+		t.Fatalf("RenderBody() error = %v", err)
+	}
+	if !strings.Contains(body, "Updates 2 modules released together:\n- github.com/example/a to v1.2.0\n- github.com/example/b to v2.0.0\n") {
+		t.Fatalf("expected the default body to list both modules, got:\n%s", body)
+	}
+
+	custom, err := RenderTitle("deps: {{range $i, $u := .Updates}}{{if $i}} + {{end}}{{$u.Module}}{{end}}", item, nil)
+	if err != nil {
+		t.Fatalf("RenderTitle() error = %v", err)
+	}
+	if custom != "deps: github.com/example/a + github.com/example/b" {
+		t.Fatalf("unexpected custom title %q", custom)
+	}
+}
+
 func TestRenderTitleWithInvalidTemplate(t *testing.T) {
 	item := planner.WorkItem{
 		Module:        "github.com/example/dependency",
@@ -250,79 +287,6 @@ func TestRenderBodyWithNilResult(t *testing.T) {
 	}
 }
 
-func TestTruncateString(t *testing.T) {
-	tests := []struct {
-		name   string
-		input  string
-		maxLen int
-		want   string
-	}{
-		{
-			name:   "short string",
-			input:  "hello",
-			maxLen: 10,
-			want:   "hello",
-		},
-		{
-			name:   "exact length",
-			input:  "hello",
-			maxLen: 5,
-			want:   "hello",
-		},
-		{
-			name:   "needs truncation",
-			input:  "this is a very long string that needs truncation",
-			maxLen: 20,
-			want:   "this is a very lo...",
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			if got := truncateString(tt.input, tt.maxLen); got != tt.want {
-				t.Fatalf("truncateString() = %q, want %q", got, tt.want)
-			}
-		})
-	}
-}
-
-func TestEscapeMarkdown(t *testing.T) {
-	tests := []struct {
-		name  string
-		input string
-		want  string
-	}{
-		{
-			name:  "backticks",
-			input: "code with `backticks`",
-			want:  "code with \\`backticks\\`",
-		},
-		{
-			name:  "asterisks",
-			input: "text with *emphasis*",
-			want:  "text with \\*emphasis\\*",
-		},
-		{
-			name:  "html characters",
-			input: "<script>alert('xss')</script>",
-			want:  "&lt;script&gt;alert('xss')&lt;/script&gt;",
-		},
-		{
-			name:  "mixed characters",
-			input: "# Header with `code` and <tags>",
-			want:  "# Header with \\`code\\` and &lt;tags&gt;",
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			if got := escapeMarkdown(tt.input); got != tt.want {
-				t.Fatalf("escapeMarkdown() = %q, want %q", got, tt.want)
-			}
-		})
-	}
-}
-
 func TestCommandOutputTruncation(t *testing.T) {
 	item := planner.WorkItem{
 		Module:        "github.com/example/dependency",
@@ -12,6 +12,10 @@ type Broker interface {
 	EnsurePR(ctx context.Context, item planner.WorkItem, result *executor.Result) (*PullRequest, error)
 	Comment(ctx context.Context, pr *PullRequest, body string) error
 	Notify(ctx context.Context, item planner.WorkItem, result *executor.Result) (*NotificationResult, error)
+	NotifyPlan(ctx context.Context, summary PlanSummary) (*NotificationResult, error)
+	ResolveIssue(ctx context.Context, item planner.WorkItem, number int, result *executor.Result) (*NotificationResult, error)
+	MergePR(ctx context.Context, pr *PullRequest, opts MergeOptions) (*MergeResult, error)
+	ClosePR(ctx context.Context, pr *PullRequest) error
 }
 
 // PullRequest represents metadata returned from the provider.
@@ -22,6 +26,36 @@ type PullRequest struct {
 	Labels []string
 }
 
+// MergeMethod selects how a pull request is merged.
+type MergeMethod string
+
+// Merge methods accepted by GitHub.
+const (
+	MergeMethodMerge  MergeMethod = "merge"
+	MergeMethodSquash MergeMethod = "squash"
+	MergeMethodRebase MergeMethod = "rebase"
+)
+
+// MergeOptions controls how a pull request is merged. Zero values use the
+// provider's defaults.
+type MergeOptions struct {
+	Method        MergeMethod
+	CommitTitle   string
+	CommitMessage string
+
+	// SHA, when set, makes the merge fail unless the pull request head still
+	// points at this commit
+	SHA string
+}
+
+// MergeResult describes a completed merge.
+type MergeResult struct {
+	// SHA is the merge commit on the base branch
+	SHA     string
+	Merged  bool
+	Message string
+}
+
 // PRInput stores payload data sent to the provider when creating/updating a PR.
 type PRInput struct {
 	Repo       string
@@ -30,10 +64,22 @@ type PRInput struct {
 	Title      string
 	Body       string
 	Labels     []string
+
+	// Draft opens a new pull request as a draft. Existing pull requests keep
+	// their state.
+	Draft bool
+
+	// DeleteBranchOnMerge asks the provider to delete HeadBranch once the pull
+	// request merges.
+	DeleteBranchOnMerge bool
 }
 
 // NotificationResult holds notification metadata (e.g. Slack message IDs).
 type NotificationResult struct {
 	Channel string
 	Message string
+
+	// IssueNumber is the GitHub issue that tracks a failure, when a notifier
+	// opened or reused one.
+	IssueNumber int
 }
@@ -0,0 +1,282 @@
+package executor
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/goliatone/cascade/internal/manifest"
+	"github.com/goliatone/cascade/internal/planner"
+)
+
+// Changelog defaults applied when the work item leaves a field empty.
+const (
+	DefaultChangelogFile     = "CHANGELOG.md"
+	DefaultChangelogSection  = "Changed"
+	DefaultChangelogDir      = ".changes"
+	DefaultChangelogTemplate = "Bump {{.Name}}{{if .From}} from {{.From}}{{end}} to {{.To}}"
+)
+
+// changelogFooter is the Conventional Commits footer token entries are added
+// to commit messages with.
+const changelogFooter = "Changelog"
+
+// ChangelogEntries renders one changelog entry per module item bumps, using
+// the item's changelog template.
+func ChangelogEntries(item planner.WorkItem, impact *DependencyImpact) ([]string, error) {
+	text := DefaultChangelogTemplate
+	if item.Changelog != nil && strings.TrimSpace(item.Changelog.Template) != "" {
+		text = item.Changelog.Template
+	}
+	tmpl, err := template.New("changelog").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parse changelog template: %w", err)
+	}
+
+	var entries []string
+	for _, update := range DependencyUpdates(item, impact) {
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, update); err != nil {
+			return nil, fmt.Errorf("render changelog entry for %s: %w", update.Name, err)
+		}
+		// Each entry is a single line in every format
+		entry := strings.Join(strings.Fields(buf.String()), " ")
+		if entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// writeChangelog writes the changelog entries of item into the worktree at
+// workPath, for the keep-a-changelog and fragment formats. Other formats write
+// nothing.
+func writeChangelog(workPath string, item planner.WorkItem, impact *DependencyImpact) error {
+	if item.Changelog == nil {
+		return nil
+	}
+	cfg := *item.Changelog
+	if cfg.Format != manifest.ChangelogKeepAChangelog && cfg.Format != manifest.ChangelogFragment {
+		return nil
+	}
+
+	entries, err := ChangelogEntries(item, impact)
+	if err != nil || len(entries) == 0 {
+		return err
+	}
+
+	if cfg.Format == manifest.ChangelogFragment {
+		dir := valueOr(cfg.Dir, DefaultChangelogDir)
+		path, err := worktreePath(workPath, filepath.Join(dir, fragmentName(item.BranchName)))
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return fmt.Errorf("create changelog directory: %w", err)
+		}
+		return os.WriteFile(path, []byte(bulletList(entries)), 0o644)
+	}
+
+	path, err := worktreePath(workPath, valueOr(cfg.File, DefaultChangelogFile))
+	if err != nil {
+		return err
+	}
+	mode := os.FileMode(0o644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	content, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("read changelog: %w", err)
+	}
+	updated := insertUnreleased(string(content), valueOr(cfg.Section, DefaultChangelogSection), entries)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create changelog directory: %w", err)
+	}
+	return os.WriteFile(path, []byte(updated), mode)
+}
+
+// changelogCommitFooter returns commit message footers with item's entries
+// for the conventional format, and an empty string otherwise.
+func changelogCommitFooter(item planner.WorkItem, impact *DependencyImpact) (string, error) {
+	if item.Changelog == nil || item.Changelog.Format != manifest.ChangelogConventional {
+		return "", nil
+	}
+	entries, err := ChangelogEntries(item, impact)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	for _, entry := range entries {
+		b.WriteString(changelogFooter + ": " + entry + "\n")
+	}
+	return b.String(), nil
+}
+
+// appendFooter adds footer as the last paragraph of message.
+func appendFooter(message, footer string) string {
+	if footer == "" {
+		return message
+	}
+	return strings.TrimRight(message, "\n") + "\n\n" + footer
+}
+
+// insertUnreleased adds entries as bullets under the section heading of the
+// Unreleased release in a Keep a Changelog document, creating the release,
+// the section, or the whole document as needed. Entries already listed in the
+// section are not added again, so a retried update leaves one entry.
+func insertUnreleased(content, section string, entries []string) string {
+	if strings.TrimSpace(content) == "" {
+		return "# Changelog\n\n## [Unreleased]\n\n### " + section + "\n\n" + bulletList(entries)
+	}
+
+	lines := strings.Split(content, "\n")
+	unreleased := -1
+	for i, line := range lines {
+		if isUnreleasedHeading(line) {
+			unreleased = i
+			break
+		}
+	}
+
+	if unreleased < 0 {
+		// The release goes above the first release, or at the end of a
+		// document that has none
+		at := -1
+		for i, line := range lines {
+			if strings.HasPrefix(line, "## ") {
+				at = i
+				break
+			}
+		}
+		if at < 0 {
+			for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+				lines = lines[:len(lines)-1]
+			}
+			at = len(lines)
+		}
+		block := []string{"## [Unreleased]", "", "### " + section, ""}
+		block = append(block, bullets(entries)...)
+		block = append(block, "")
+		if at > 0 && strings.TrimSpace(lines[at-1]) != "" {
+			block = append([]string{""}, block...)
+		}
+		return joinLines(lines[:at], block, lines[at:])
+	}
+
+	end := len(lines)
+	sectionLine := -1
+	for i := unreleased + 1; i < len(lines); i++ {
+		if strings.HasPrefix(lines[i], "## ") {
+			end = i
+			break
+		}
+		if sectionLine < 0 && strings.HasPrefix(lines[i], "### ") && strings.EqualFold(strings.TrimSpace(lines[i][4:]), section) {
+			sectionLine = i
+		}
+	}
+
+	if sectionLine < 0 {
+		at := unreleased + 1
+		for at < end && strings.TrimSpace(lines[at]) == "" {
+			at++
+		}
+		block := []string{"", "### " + section, ""}
+		block = append(block, bullets(entries)...)
+		block = append(block, "")
+		return joinLines(lines[:unreleased+1], block, lines[at:])
+	}
+
+	listed := make(map[string]bool)
+	for i := sectionLine + 1; i < end && !strings.HasPrefix(lines[i], "### "); i++ {
+		listed[strings.TrimSpace(lines[i])] = true
+	}
+	var added []string
+	for _, bullet := range bullets(entries) {
+		if !listed[bullet] {
+			added = append(added, bullet)
+		}
+	}
+	if len(added) == 0 {
+		return content
+	}
+	at := sectionLine + 1
+	for at < end && strings.TrimSpace(lines[at]) == "" {
+		at++
+	}
+	block := added
+	if at == end || strings.HasPrefix(lines[at], "#") {
+		// An empty section gets its list followed by a blank line
+		block = append(block, "")
+	}
+	return joinLines(lines[:sectionLine+1], []string{""}, block, lines[at:])
+}
+
+func isUnreleasedHeading(line string) bool {
+	if !strings.HasPrefix(line, "## ") {
+		return false
+	}
+	title := strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "## ")), "[]")
+	return strings.EqualFold(title, "unreleased")
+}
+
+func bullets(entries []string) []string {
+	lines := make([]string, len(entries))
+	for i, entry := range entries {
+		lines[i] = "- " + entry
+	}
+	return lines
+}
+
+func bulletList(entries []string) string {
+	return strings.Join(bullets(entries), "\n") + "\n"
+}
+
+func joinLines(parts ...[]string) string {
+	var lines []string
+	for _, part := range parts {
+		lines = append(lines, part...)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// fragmentName names the fragment of a work item after its branch, so each
+// update adds its own file and a retried update rewrites it.
+func fragmentName(branch string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		default:
+			return '-'
+		}
+	}, branch)
+	name = strings.Trim(name, "-.")
+	if name == "" {
+		name = "cascade-update"
+	}
+	return name + ".md"
+}
+
+// worktreePath joins rel to workPath, failing when it points outside of it.
+func worktreePath(workPath, rel string) (string, error) {
+	if filepath.IsAbs(rel) {
+		return "", fmt.Errorf("changelog path %q must be relative to the repository", rel)
+	}
+	path := filepath.Join(workPath, rel)
+	if inside, err := filepath.Rel(workPath, path); err != nil || inside == ".." || strings.HasPrefix(inside, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("changelog path %q is outside the repository", rel)
+	}
+	return path, nil
+}
+
+func valueOr(value, fallback string) string {
+	if value = strings.TrimSpace(value); value != "" {
+		return value
+	}
+	return fallback
+}
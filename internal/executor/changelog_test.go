@@ -0,0 +1,148 @@
+package executor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/goliatone/cascade/internal/manifest"
+	"github.com/goliatone/cascade/internal/planner"
+)
+
+func changelogItem(changelog manifest.Changelog) planner.WorkItem {
+	return planner.WorkItem{
+		SourceModule:  "github.com/acme/lib",
+		SourceVersion: "v1.3.0",
+		BranchName:    "cascade/update-lib-v1.3.0",
+		CommitMessage: "Update github.com/acme/lib to v1.3.0",
+		Changelog:     &changelog,
+	}
+}
+
+var changelogImpact = &DependencyImpact{
+	Module:             "github.com/acme/lib",
+	OldVersion:         "v1.2.0",
+	OldVersionDetected: true,
+}
+
+func TestInsertUnreleased(t *testing.T) {
+	entries := []string{"Bump github.com/acme/lib from v1.2.0 to v1.3.0"}
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "new document",
+			content: "",
+			want:    "# Changelog\n\n## [Unreleased]\n\n### Changed\n\n- Bump github.com/acme/lib from v1.2.0 to v1.3.0\n",
+		},
+		{
+			name:    "existing section",
+			content: "# Changelog\n\n## [Unreleased]\n\n### Changed\n\n- Rename flags\n\n## [1.0.0] - 2024-10-01\n\n- First release\n",
+			want:    "# Changelog\n\n## [Unreleased]\n\n### Changed\n\n- Bump github.com/acme/lib from v1.2.0 to v1.3.0\n- Rename flags\n\n## [1.0.0] - 2024-10-01\n\n- First release\n",
+		},
+		{
+			name:    "missing section",
+			content: "# Changelog\n\n## [Unreleased]\n\n### Added\n\n- Export metrics\n",
+			want:    "# Changelog\n\n## [Unreleased]\n\n### Changed\n\n- Bump github.com/acme/lib from v1.2.0 to v1.3.0\n\n### Added\n\n- Export metrics\n",
+		},
+		{
+			name:    "empty unreleased release",
+			content: "# Changelog\n\n## Unreleased\n\n## [1.0.0] - 2024-10-01\n",
+			want:    "# Changelog\n\n## Unreleased\n\n### Changed\n\n- Bump github.com/acme/lib from v1.2.0 to v1.3.0\n\n## [1.0.0] - 2024-10-01\n",
+		},
+		{
+			name:    "missing unreleased release",
+			content: "# Changelog\n\nAll notable changes.\n\n## [1.0.0] - 2024-10-01\n",
+			want:    "# Changelog\n\nAll notable changes.\n\n## [Unreleased]\n\n### Changed\n\n- Bump github.com/acme/lib from v1.2.0 to v1.3.0\n\n## [1.0.0] - 2024-10-01\n",
+		},
+		{
+			name:    "no releases",
+			content: "# Changelog\n\nAll notable changes.\n",
+			want:    "# Changelog\n\nAll notable changes.\n\n## [Unreleased]\n\n### Changed\n\n- Bump github.com/acme/lib from v1.2.0 to v1.3.0\n",
+		},
+		{
+			name:    "entry already listed",
+			content: "## [Unreleased]\n\n### Changed\n\n- Bump github.com/acme/lib from v1.2.0 to v1.3.0\n",
+			want:    "## [Unreleased]\n\n### Changed\n\n- Bump github.com/acme/lib from v1.2.0 to v1.3.0\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := insertUnreleased(tt.content, "Changed", entries); got != tt.want {
+				t.Errorf("insertUnreleased() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteChangelog(t *testing.T) {
+	t.Run("keep a changelog", func(t *testing.T) {
+		dir := t.TempDir()
+		item := changelogItem(manifest.Changelog{Format: manifest.ChangelogKeepAChangelog, File: "docs/CHANGES.md", Section: "Dependencies"})
+		if err := writeChangelog(dir, item, changelogImpact); err != nil {
+			t.Fatalf("writeChangelog() error = %v", err)
+		}
+		data, err := os.ReadFile(filepath.Join(dir, "docs", "CHANGES.md"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), "### Dependencies\n\n- Bump github.com/acme/lib from v1.2.0 to v1.3.0\n") {
+			t.Errorf("changelog =\n%s", data)
+		}
+	})
+
+	t.Run("fragment", func(t *testing.T) {
+		dir := t.TempDir()
+		item := changelogItem(manifest.Changelog{Format: manifest.ChangelogFragment, Template: "deps: {{.Name}} {{.To}} ({{.UpdateType}})"})
+		if err := writeChangelog(dir, item, changelogImpact); err != nil {
+			t.Fatalf("writeChangelog() error = %v", err)
+		}
+		data, err := os.ReadFile(filepath.Join(dir, ".changes", "cascade-update-lib-v1.3.0.md"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := "- deps: github.com/acme/lib v1.3.0 (version-update:semver-minor)\n"; string(data) != want {
+			t.Errorf("fragment = %q, want %q", data, want)
+		}
+	})
+
+	t.Run("outside the repository", func(t *testing.T) {
+		item := changelogItem(manifest.Changelog{Format: manifest.ChangelogKeepAChangelog, File: "../CHANGELOG.md"})
+		if err := writeChangelog(t.TempDir(), item, changelogImpact); err == nil {
+			t.Fatal("expected an error for a changelog outside the repository")
+		}
+	})
+
+	t.Run("conventional writes no file", func(t *testing.T) {
+		dir := t.TempDir()
+		item := changelogItem(manifest.Changelog{Format: manifest.ChangelogConventional})
+		if err := writeChangelog(dir, item, changelogImpact); err != nil {
+			t.Fatalf("writeChangelog() error = %v", err)
+		}
+		if files, _ := os.ReadDir(dir); len(files) != 0 {
+			t.Errorf("expected no files, got %d", len(files))
+		}
+	})
+}
+
+func TestCommitMessage_ChangelogFooter(t *testing.T) {
+	e := &executor{}
+	item := changelogItem(manifest.Changelog{Format: manifest.ChangelogConventional})
+	message, err := e.commitMessage(item, changelogImpact)
+	if err != nil {
+		t.Fatalf("commitMessage() error = %v", err)
+	}
+	want := "Update github.com/acme/lib to v1.3.0\n\nChangelog: Bump github.com/acme/lib from v1.2.0 to v1.3.0\n"
+	if message != want {
+		t.Errorf("commitMessage() = %q, want %q", message, want)
+	}
+
+	item.Changelog = nil
+	if message, _ := e.commitMessage(item, changelogImpact); message != item.CommitMessage {
+		t.Errorf("commitMessage() without changelog = %q", message)
+	}
+}
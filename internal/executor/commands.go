@@ -2,6 +2,7 @@ package executor
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -11,11 +12,27 @@ import (
 )
 
 // commandRunner implements CommandRunner using os/exec.
-type commandRunner struct{}
+type commandRunner struct {
+	limits ResourceLimits
+}
+
+// CommandRunnerOption configures a CommandRunner.
+type CommandRunnerOption func(*commandRunner)
+
+// WithResourceLimits constrains the CPU and memory of every command the runner starts.
+func WithResourceLimits(limits ResourceLimits) CommandRunnerOption {
+	return func(c *commandRunner) {
+		c.limits = limits
+	}
+}
 
 // NewCommandRunner creates a CommandRunner implementation.
-func NewCommandRunner() CommandRunner {
-	return &commandRunner{}
+func NewCommandRunner(opts ...CommandRunnerOption) CommandRunner {
+	runner := &commandRunner{}
+	for _, opt := range opts {
+		opt(runner)
+	}
+	return runner
 }
 
 func (c *commandRunner) Run(ctx context.Context, repoPath string, cmd manifest.Command, env map[string]string, timeout time.Duration) (CommandResult, error) {
@@ -45,8 +62,9 @@ func (c *commandRunner) Run(ctx context.Context, repoPath string, cmd manifest.C
 		workDir = filepath.Join(repoPath, cmd.Dir)
 	}
 
-	// Create command
-	execCmd := exec.CommandContext(ctx, cmd.Cmd[0], cmd.Cmd[1:]...)
+	// Create command, wrapped by any resource limits
+	argv, env := c.limits.apply(cmd.Cmd, env)
+	execCmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
 	execCmd.Dir = workDir
 
 	// Set up environment
@@ -57,6 +75,9 @@ func (c *commandRunner) Run(ctx context.Context, repoPath string, cmd manifest.C
 	result.Output = string(output)
 
 	if err != nil {
+		if !c.limits.IsZero() && ctx.Err() == nil && killedBySignal(err) {
+			err = fmt.Errorf("killed while running under resource limits (%s): %w", c.limits, err)
+		}
 		cmdErr := &CommandExecutionError{
 			Command:  cmd.Cmd,
 			Dir:      workDir,
@@ -4,19 +4,122 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"reflect"
+	"strings"
 	"time"
 
 	"github.com/goliatone/cascade/internal/manifest"
+	"github.com/goliatone/cascade/internal/planner"
+	"github.com/goliatone/cascade/internal/telemetry"
+	"github.com/goliatone/cascade/pkg/repourl"
 )
 
-// New returns a stub executor implementation.
-func New() Executor {
-	return &executor{}
+// defaultCommandTimeout bounds test and extra commands when neither the work item
+// nor the executor sets a timeout.
+const defaultCommandTimeout = 5 * time.Minute
+
+// Option configures the executor.
+type Option func(*executor)
+
+// WithDefaultTimeout sets the timeout for test and extra commands of work items
+// that do not set their own. Non-positive values keep the 5 minute default.
+func WithDefaultTimeout(timeout time.Duration) Option {
+	return func(e *executor) {
+		if timeout > 0 {
+			e.defaultTimeout = timeout
+		}
+	}
+}
+
+// WithConcurrentLimit caps how many work items Apply processes at once. Further
+// calls wait for a free slot or for their context to end. Values below one leave
+// Apply unbounded.
+func WithConcurrentLimit(limit int) Option {
+	return func(e *executor) {
+		if limit > 0 {
+			e.slots = make(chan struct{}, limit)
+		} else {
+			e.slots = nil
+		}
+	}
 }
 
-type executor struct{}
+// WithRetry retries the network-bound steps of a work item (clone, dependency
+// download, and push) up to retries more times, waiting delay before the first
+// retry and doubling it after each one.
+func WithRetry(retries int, delay time.Duration) Option {
+	return func(e *executor) {
+		e.retries = max(retries, 0)
+		e.retryDelay = max(delay, 0)
+	}
+}
+
+// WithRepoURLs builds clone URLs with resolver, so work items on configured
+// hosts are cloned from the right server and URL rewrites apply.
+func WithRepoURLs(resolver *repourl.Resolver) Option {
+	return func(e *executor) {
+		e.repoURLs = resolver
+	}
+}
+
+// WithDependabotCommits writes commit messages in Dependabot's format, with a
+// semantic subject using prefix and a metadata block listing the updated
+// modules, instead of the work item's commit message. An empty prefix uses
+// DefaultCommitPrefix.
+func WithDependabotCommits(prefix string) Option {
+	return func(e *executor) {
+		e.dependabotCommits = true
+		e.commitPrefix = strings.TrimSpace(prefix)
+		if e.commitPrefix == "" {
+			e.commitPrefix = DefaultCommitPrefix
+		}
+	}
+}
+
+// New returns an executor configured by opts.
+func New(opts ...Option) Executor {
+	e := &executor{defaultTimeout: defaultCommandTimeout}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+type executor struct {
+	defaultTimeout time.Duration
+	slots          chan struct{}
+	retries        int
+	retryDelay     time.Duration
+	repoURLs       *repourl.Resolver
+
+	dependabotCommits bool
+	commitPrefix      string
+}
 
 func (e *executor) Apply(ctx context.Context, input WorkItemContext) (*Result, error) {
+	ctx, span := telemetry.Start(ctx, "executor.apply", telemetry.String("repo", input.Item.Repo))
+	result, err := e.apply(ctx, input)
+	if result != nil {
+		span.SetAttributes(telemetry.String("status", string(result.Status)))
+	}
+	span.End(err)
+	return result, err
+}
+
+func (e *executor) apply(ctx context.Context, input WorkItemContext) (*Result, error) {
+	if e.slots != nil {
+		select {
+		case e.slots <- struct{}{}:
+			defer func() { <-e.slots }()
+		case <-ctx.Done():
+			return &Result{
+				Status: StatusFailed,
+				Reason: fmt.Sprintf("waiting for an executor slot: %v", ctx.Err()),
+			}, ctx.Err()
+		}
+	}
+
 	// Validate inputs
 	if err := e.validateInput(input); err != nil {
 		return &Result{
@@ -25,6 +128,14 @@ func (e *executor) Apply(ctx context.Context, input WorkItemContext) (*Result, e
 		}, err
 	}
 
+	// Plans can be loaded from files, so check again right before the clone
+	if err := manifest.NeverTouch(input.NeverTouch, input.Item.Repo, input.Item.CloneURL); err != nil {
+		return &Result{
+			Status: StatusFailed,
+			Reason: err.Error(),
+		}, err
+	}
+
 	// Handle skip flag
 	if input.Item.Skip {
 		return &Result{
@@ -52,60 +163,103 @@ func (e *executor) Apply(ctx context.Context, input WorkItemContext) (*Result, e
 	if input.Item.CloneURL != "" {
 		cloneURL = input.Item.CloneURL
 	}
+	if urls := e.repoURLs; urls != nil || input.Item.CloneProtocol != "" {
+		if urls == nil {
+			urls = repourl.New()
+		}
+		if resolved, err := urls.CloneURLWithProtocol(cloneURL, repourl.Protocol(input.Item.CloneProtocol)); err == nil {
+			cloneURL = resolved
+		}
+	}
 
 	if input.Logger != nil {
 		input.Logger.Info("cloning repository", "repo", input.Item.Repo, "clone_url", cloneURL, "workspace", input.Workspace)
 	}
 
-	repoPath, err := input.Git.EnsureClone(ctx, cloneURL, input.Workspace)
+	input.report(PhaseClone)
+	var repoPath string
+	err := e.retry(ctx, input, "git clone", func() error {
+		var cloneErr error
+		repoPath, cloneErr = input.Git.EnsureClone(ctx, cloneURL, input.Workspace)
+		return cloneErr
+	})
 	if err != nil {
 		e.handleExecutionError(result, err, "git clone")
 		return result, err
 	}
 
+	// Re-apply the dependent's own .cascade.yaml from the freshly fetched default branch
+	// so overrides take effect even when planning had no local workspace.
+	input.Item = e.applyDependentOverrides(ctx, input, repoPath, result)
+	if input.Item.Skip {
+		result.Status = StatusSkipped
+		result.Reason = "skipped by dependent .cascade.yaml"
+		return result, nil
+	}
+
 	// Create worktree for branch
 	if input.Logger != nil {
 		input.Logger.Info("creating worktree", "branch", input.Item.BranchName, "base", input.Item.Branch)
 	}
 
+	input.report(PhaseWorktree)
 	workPath, err := input.Git.EnsureWorktree(ctx, repoPath, input.Item.BranchName, input.Item.Branch)
 	if err != nil {
 		e.handleExecutionError(result, err, "git worktree")
 		return result, err
 	}
 
-	if result.DependencyImpact != nil {
-		captureOldDependencyVersion(result.DependencyImpact, workPath)
+	// Scope the dependent's GOFLAGS and private module credentials to its own
+	// go commands
+	itemEnv, err := newGoEnv(input.Item, os.Getenv)
+	if err != nil {
+		e.handleExecutionError(result, err, "go environment")
+		return result, err
 	}
+	defer itemEnv.cleanup()
 
-	// Update module dependencies using GoOperations
-	if input.Logger != nil {
-		input.Logger.Info("updating module", "module", input.Item.SourceModule, "version", input.Item.SourceVersion)
+	if input.Item.UpdateStrategy == manifest.UpdateStrategyGitSubmodule {
+		err = e.updateSubmodule(ctx, input, workPath, result)
+	} else {
+		err = e.updateGoModule(ctx, input, workPath, itemEnv.vars, result)
 	}
-
-	err = input.Go.Get(ctx, workPath, input.Item.SourceModule, input.Item.SourceVersion)
 	if err != nil {
-		e.handleExecutionError(result, err, "dependency update")
 		return result, err
 	}
-
-	if result.DependencyImpact != nil {
-		captureNewDependencyVersion(result.DependencyImpact, workPath, "after go get")
-	}
-
-	// Run go mod tidy
-	if input.Logger != nil {
-		input.Logger.Info("running go mod tidy")
+	if result.Status == StatusNoChange {
+		if input.Logger != nil {
+			input.Logger.Info("update changed nothing", "repo", input.Item.Repo, "reason", result.Reason)
+		}
+		return result, nil
 	}
 
-	err = input.Go.Tidy(ctx, workPath)
-	if err != nil {
-		e.handleExecutionError(result, err, "go mod tidy")
-		return result, err
+	// Build the tests against the local checkout of the target when one is
+	// given. The go.work lives outside the worktree, so the commit is unaffected
+	env := itemEnv.vars
+	if input.LocalTarget != "" && input.Item.UpdateStrategy != manifest.UpdateStrategyGitSubmodule {
+		localEnv, cleanup, err := localTargetEnv(input.Item, workPath, input.LocalTarget, env, os.Getenv)
+		if err != nil {
+			e.handleExecutionError(result, err, "local target")
+			return result, err
+		}
+		defer cleanup()
+		if input.Logger != nil {
+			input.Logger.Info("testing against local target", "repo", input.Item.Repo, "dir", input.LocalTarget)
+		}
+		env = localEnv
 	}
 
-	if result.DependencyImpact != nil {
-		captureNewDependencyVersion(result.DependencyImpact, workPath, "after go mod tidy")
+	// Start backing services and point the commands at them
+	stopServices := func() {}
+	if len(input.Item.Services) > 0 {
+		input.report(PhaseServices)
+		serviceVars, stop, err := e.startServices(ctx, input)
+		if err != nil {
+			e.handleExecutionError(result, err, "service startup")
+			return result, err
+		}
+		env = serviceEnv(serviceVars, env)
+		stopServices = stop
 	}
 
 	// Execute tests using CommandRunner
@@ -113,7 +267,8 @@ func (e *executor) Apply(ctx context.Context, input WorkItemContext) (*Result, e
 		input.Logger.Info("executing tests", "count", len(input.Item.Tests))
 	}
 
-	testResults, testErr := e.executeCommands(ctx, input, workPath, input.Item.Tests)
+	input.report(PhaseTests)
+	testResults, testErr := e.executeCommands(ctx, input, workPath, input.Item.Tests, env)
 	result.TestResults = testResults
 
 	// Execute extra commands using CommandRunner
@@ -121,9 +276,12 @@ func (e *executor) Apply(ctx context.Context, input WorkItemContext) (*Result, e
 		input.Logger.Info("executing extra commands", "count", len(input.Item.ExtraCommands))
 	}
 
-	extraResults, extraErr := e.executeCommands(ctx, input, workPath, input.Item.ExtraCommands)
+	input.report(PhaseExtraCommands)
+	extraResults, extraErr := e.executeCommands(ctx, input, workPath, input.Item.ExtraCommands, env)
 	result.ExtraResults = extraResults
 
+	stopServices()
+
 	// Handle partial success scenarios
 	if testErr != nil && extraErr != nil {
 		// Both tests and extra commands failed
@@ -140,16 +298,32 @@ func (e *executor) Apply(ctx context.Context, input WorkItemContext) (*Result, e
 		// Continue with commit/push since tests passed
 	}
 
+	// Describe the update in the dependent's changelog
+	if err := writeChangelog(workPath, input.Item, result.DependencyImpact); err != nil {
+		e.handleExecutionError(result, err, "changelog")
+		return result, err
+	}
+
+	if input.Preview {
+		return e.preview(ctx, input, workPath, result)
+	}
+
 	// Commit changes
+	commitMessage, err := e.commitMessage(input.Item, result.DependencyImpact)
+	if err != nil {
+		e.handleExecutionError(result, err, "changelog")
+		return result, err
+	}
 	if input.Logger != nil {
-		input.Logger.Info("committing changes", "message", input.Item.CommitMessage)
+		input.Logger.Info("committing changes", "message", commitMessage)
 	}
 
-	commitHash, err := input.Git.Commit(ctx, workPath, input.Item.CommitMessage)
+	input.report(PhaseCommit)
+	commitHash, err := input.Git.Commit(ctx, workPath, commitMessage)
 	if err != nil {
 		// Check if it's a "no changes" error - this might be expected in some cases
 		if errors.Is(err, ErrNoChanges) {
-			result.Status = StatusCompleted
+			result.Status = StatusNoChange
 			result.Reason = "no changes to commit"
 			return result, nil
 		}
@@ -163,7 +337,10 @@ func (e *executor) Apply(ctx context.Context, input WorkItemContext) (*Result, e
 		input.Logger.Info("pushing changes", "branch", input.Item.BranchName)
 	}
 
-	err = input.Git.Push(ctx, workPath, input.Item.BranchName)
+	input.report(PhasePush)
+	err = e.retry(ctx, input, "git push", func() error {
+		return input.Git.Push(ctx, workPath, input.Item.BranchName)
+	})
 	if err != nil {
 		e.handleExecutionError(result, err, "git push")
 		return result, err
@@ -182,6 +359,161 @@ func (e *executor) Apply(ctx context.Context, input WorkItemContext) (*Result, e
 	return result, nil
 }
 
+// preview records the changes in workPath on result instead of committing
+// and pushing them.
+func (e *executor) preview(ctx context.Context, input WorkItemContext, workPath string, result *Result) (*Result, error) {
+	differ, ok := input.Git.(DiffOperations)
+	if !ok {
+		err := errors.New("git operations cannot show diffs")
+		e.handleExecutionError(result, err, "git diff")
+		return result, err
+	}
+	diff, err := differ.Diff(ctx, workPath)
+	if err != nil {
+		e.handleExecutionError(result, err, "git diff")
+		return result, err
+	}
+	result.Diff = diff
+	if result.Status != StatusManualReview {
+		result.Status = StatusCompleted
+		result.Reason = "previewed without committing"
+	}
+	return result, nil
+}
+
+// updateGoModule requires the target version, or every version of a release set,
+// in the dependent's go.mod and tidies the module, recording the change of the
+// first module on result. When go.mod and go.sum hash the same afterwards, the
+// update was a no-op and result is marked StatusNoChange.
+func (e *executor) updateGoModule(ctx context.Context, input WorkItemContext, workPath string, env map[string]string, result *Result) error {
+	if result.DependencyImpact != nil {
+		captureOldDependencyVersion(result.DependencyImpact, workPath)
+	}
+	before, hashErr := hashModuleFiles(workPath)
+
+	strategy, ok := manifest.LookupBumpStrategy(input.Item.BumpStrategy)
+	if !ok {
+		err := fmt.Errorf("unknown bump strategy %q", input.Item.BumpStrategy)
+		e.handleExecutionError(result, err, "dependency update")
+		return err
+	}
+
+	// Update module dependencies using GoOperations. Items of a release set bump
+	// every module of the set the dependent requires before tidying once.
+	env = goWorkEnv(env, input.Item.GoWork)
+	input.report(PhaseDependencies)
+	for _, update := range input.Item.ModuleUpdates() {
+		query := strategy(update.Module, update.Version)
+		if input.Logger != nil {
+			input.Logger.Info("updating module", "module", update.Module, "version", update.Version, "query", query.Version)
+		}
+
+		err := e.retry(ctx, input, "dependency update", func() error {
+			return goGetQuery(ctx, input.Go, workPath, update.Module, query, env)
+		})
+		if err != nil {
+			e.handleExecutionError(result, err, "dependency update")
+			return err
+		}
+	}
+
+	if result.DependencyImpact != nil {
+		captureNewDependencyVersion(result.DependencyImpact, workPath, "after go get")
+	}
+
+	// Run go mod tidy
+	if input.Logger != nil {
+		input.Logger.Info("running go mod tidy")
+	}
+
+	input.report(PhaseTidy)
+	if err := goTidy(ctx, input.Go, workPath, env); err != nil {
+		e.handleExecutionError(result, err, "go mod tidy")
+		return err
+	}
+
+	if result.DependencyImpact != nil {
+		captureNewDependencyVersion(result.DependencyImpact, workPath, "after go mod tidy")
+	}
+
+	// Files that cannot be read leave the no-op check to git at commit time
+	if after, err := hashModuleFiles(workPath); hashErr == nil && err == nil && after == before {
+		result.Status = StatusNoChange
+		result.Reason = "go.mod and go.sum are unchanged after the update"
+	}
+	return nil
+}
+
+// applyDependentOverrides loads the dependent manifest from repoPath and merges it onto
+// the work item. Load failures are logged and the planned item is used unchanged.
+// commitMessage returns the message to commit the item's changes with,
+// including the changelog footer of the conventional changelog format.
+func (e *executor) commitMessage(item planner.WorkItem, impact *DependencyImpact) (string, error) {
+	message := item.CommitMessage
+	if e.dependabotCommits {
+		message = DependabotCommitMessage(e.commitPrefix, item, impact)
+	}
+	footer, err := changelogCommitFooter(item, impact)
+	if err != nil {
+		return "", err
+	}
+	return appendFooter(message, footer), nil
+}
+
+func (e *executor) applyDependentOverrides(ctx context.Context, input WorkItemContext, repoPath string, result *Result) planner.WorkItem {
+	depManifest, err := manifest.LoadDependentManifest(ctx, repoPath)
+	if err != nil {
+		if input.Logger != nil {
+			input.Logger.Error("failed to load dependent overrides", "repo", input.Item.Repo, "error", err)
+		}
+		return input.Item
+	}
+	if depManifest == nil {
+		return input.Item
+	}
+
+	item := planner.ApplyDependentManifest(input.Item, depManifest)
+	if !reflect.DeepEqual(item, input.Item) {
+		if input.Logger != nil {
+			input.Logger.Info("applied dependent overrides", "repo", item.Repo, "module", item.SourceModule)
+		}
+		result.EffectiveItem = &item
+	}
+	return item
+}
+
+// startServices starts the item's services and returns their connection variables
+// along with a function that removes them. Removal uses a fresh context so services
+// are cleaned up even when the work item timed out.
+func (e *executor) startServices(ctx context.Context, input WorkItemContext) (map[string]string, func(), error) {
+	if input.Services == nil {
+		return nil, nil, &ServiceError{
+			Service:   strings.Join(serviceNames(input.Item.Services), ","),
+			Operation: "start",
+			Err:       fmt.Errorf("no service manager configured"),
+		}
+	}
+
+	project := ServiceProject(input.Item.Repo, input.Item.BranchName)
+	if input.Logger != nil {
+		input.Logger.Info("starting services", "repo", input.Item.Repo, "services", serviceNames(input.Item.Services))
+	}
+
+	vars, err := input.Services.Start(ctx, project, input.Item.Services)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stop := func() {
+		stopCtx, cancel := context.WithTimeout(context.Background(), serviceStopTimeout)
+		defer cancel()
+		if err := input.Services.Stop(stopCtx, project, input.Item.Services); err != nil && input.Logger != nil {
+			input.Logger.Error("failed to stop services", "repo", input.Item.Repo, "error", err)
+		}
+	}
+	return vars, stop, nil
+}
+
 func (e *executor) validateInput(input WorkItemContext) error {
 	if input.Item.Repo == "" {
 		return fmt.Errorf("work item repo is required")
@@ -218,16 +550,16 @@ func (e *executor) validateInput(input WorkItemContext) error {
 	return nil
 }
 
-func (e *executor) executeCommands(ctx context.Context, input WorkItemContext, workPath string, commands []manifest.Command) ([]CommandResult, error) {
+func (e *executor) executeCommands(ctx context.Context, input WorkItemContext, workPath string, commands []manifest.Command, env map[string]string) ([]CommandResult, error) {
 	var results []CommandResult
 
 	for _, cmd := range commands {
 		timeout := input.Item.Timeout
 		if timeout <= 0 {
-			timeout = 5 * time.Minute // default timeout
+			timeout = e.defaultTimeout
 		}
 
-		result, err := input.Runner.Run(ctx, workPath, cmd, input.Item.Env, timeout)
+		result, err := input.Runner.Run(ctx, workPath, cmd, env, timeout)
 		results = append(results, result)
 
 		if err != nil {
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -132,7 +134,7 @@ func TestExecutor_Apply_StatusLogic(t *testing.T) {
 				CommitMessage: "Update dependency",
 			},
 			commitError:            executor.ErrNoChanges,
-			expectedStatus:         executor.StatusCompleted,
+			expectedStatus:         executor.StatusNoChange,
 			expectedReasonContains: "no changes to commit",
 		},
 		{
@@ -270,6 +272,58 @@ func (m *advancedMockGoOperations) Get(ctx context.Context, repoPath, module, ve
 	return m.getError
 }
 
+func TestExecutor_Apply_NoChangeWhenModuleFilesUnchanged(t *testing.T) {
+	workPath := t.TempDir()
+	writeFile := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(workPath, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	writeFile("go.mod", "module example.com/app\n\ngo 1.22\n\nrequire github.com/example/lib v1.2.3\n")
+	writeFile("go.sum", "github.com/example/lib v1.2.3 h1:abc=\n")
+
+	apply := func(goOps executor.GoOperations) (*executor.Result, *recordingCommandRunner) {
+		t.Helper()
+		runner := &recordingCommandRunner{}
+		git := &mockGitOperations{clonePath: workPath, workPath: workPath, commitHash: "abc"}
+		result, err := executor.New().Apply(context.Background(), optionsTestInput(git, goOps, runner))
+		if err != nil {
+			t.Fatalf("apply: %v", err)
+		}
+		return result, runner
+	}
+
+	result, runner := apply(&mockGoOperations{})
+	if result.Status != executor.StatusNoChange || result.CommitHash != "" {
+		t.Fatalf("expected a no-op update to be recorded as no-change, got %s (commit %q)", result.Status, result.CommitHash)
+	}
+	if len(runner.calls) != 0 {
+		t.Fatalf("expected no commands to run after a no-op update, got %d", len(runner.calls))
+	}
+
+	result, _ = apply(&goSumWritingGoOperations{path: filepath.Join(workPath, "go.sum")})
+	if result.Status != executor.StatusCompleted {
+		t.Fatalf("expected a go.sum change to be committed, got %s: %s", result.Status, result.Reason)
+	}
+}
+
+// goSumWritingGoOperations appends a line to go.sum when tidying.
+type goSumWritingGoOperations struct {
+	mockGoOperations
+	path string
+}
+
+func (g *goSumWritingGoOperations) Tidy(ctx context.Context, repoPath string) error {
+	f, err := os.OpenFile(g.path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.WriteString("github.com/example/lib v1.2.3/go.mod h1:def=\n")
+	return err
+}
+
 func (m *advancedMockGoOperations) Tidy(ctx context.Context, repoPath string) error {
 	return m.tidyError
 }
@@ -2,7 +2,13 @@ package executor_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
@@ -62,6 +68,73 @@ func TestExecutor_ApplyProducesExpectedResult(t *testing.T) {
 	}
 }
 
+// previewGitOperations shows a fixed diff and fails commits and pushes, which a
+// preview must not reach.
+type previewGitOperations struct {
+	mockGitOperations
+	diff string
+}
+
+func (m *previewGitOperations) Commit(ctx context.Context, repoPath, message string) (string, error) {
+	return "", errors.New("preview committed")
+}
+
+func (m *previewGitOperations) Push(ctx context.Context, repoPath, branch string) error {
+	return errors.New("preview pushed")
+}
+
+func (m *previewGitOperations) Diff(ctx context.Context, repoPath string) (string, error) {
+	return m.diff, nil
+}
+
+func TestExecutor_ApplyPreview(t *testing.T) {
+	git := &previewGitOperations{
+		mockGitOperations: mockGitOperations{clonePath: "/workspace/test-repo", workPath: "/workspace/test-repo/worktree-branch"},
+		diff:              "diff --git a/go.mod b/go.mod",
+	}
+	item := planner.WorkItem{
+		Repo:          "https://github.com/test/repo",
+		SourceModule:  "github.com/goliatone/go-errors",
+		SourceVersion: "v1.2.3",
+		BranchName:    "update-go-errors-v1.2.3",
+		CommitMessage: "Update go-errors to v1.2.3",
+		ExtraCommands: []manifest.Command{{Cmd: []string{"go", "generate", "./..."}}},
+	}
+
+	result, err := executor.New().Apply(context.Background(), executor.WorkItemContext{
+		Item:      item,
+		Workspace: "/workspace",
+		Git:       git,
+		Go:        &mockGoOperations{},
+		Runner:    &mockCommandRunner{},
+		Logger:    &mockLogger{},
+		Preview:   true,
+	})
+	if err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if result.Status != executor.StatusCompleted || result.Diff != git.diff || result.CommitHash != "" {
+		t.Errorf("result = %+v, want a completed preview with the diff and no commit", result)
+	}
+	if len(result.ExtraResults) != 1 {
+		t.Errorf("extra commands run = %d, want 1", len(result.ExtraResults))
+	}
+
+	// Git operations that cannot diff fail the preview
+	result, err = executor.New().Apply(context.Background(), executor.WorkItemContext{
+		Item:      item,
+		Workspace: "/workspace",
+		Git:       &git.mockGitOperations,
+		Go:        &mockGoOperations{},
+		Runner:    &mockCommandRunner{},
+		Logger:    &mockLogger{},
+		Preview:   true,
+	})
+	if err == nil || result.Status != executor.StatusFailed {
+		t.Errorf("result = %+v, error = %v, want a failed preview", result, err)
+	}
+}
+
 func TestExecutor_Apply_TableDriven(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -353,6 +426,500 @@ func TestExecutor_Apply_UsesProvidedCommandsAndEnv(t *testing.T) {
 	}
 }
 
+func TestExecutor_Apply_ReleaseSetBumpsEveryModule(t *testing.T) {
+	workspace := "/workspace"
+	mockGit := &mockGitOperations{
+		clonePath:  workspace + "/test-repo",
+		workPath:   workspace + "/test-repo/worktree",
+		commitHash: "abc123",
+	}
+	mockGo := &mockGoOperations{}
+
+	input := executor.WorkItemContext{
+		Item: planner.WorkItem{
+			Repo:          "https://github.com/test/repo",
+			SourceModule:  "github.com/example/a",
+			SourceVersion: "v1.2.0",
+			Updates: []planner.ModuleUpdate{
+				{Module: "github.com/example/a", Version: "v1.2.0"},
+				{Module: "github.com/example/b", Version: "v2.0.0"},
+			},
+			Branch:        "main",
+			BranchName:    "auto/a-v1.2.0-b-v2.0.0",
+			CommitMessage: "Update github.com/example/a to v1.2.0, github.com/example/b to v2.0.0",
+		},
+		Workspace: workspace,
+		Git:       mockGit,
+		Go:        mockGo,
+		Runner:    &mockCommandRunner{},
+		Logger:    &mockLogger{},
+	}
+
+	result, err := executor.New().Apply(context.Background(), input)
+	if err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if result.Status != executor.StatusCompleted {
+		t.Fatalf("expected completed status, got %s", result.Status)
+	}
+	if got := strings.Join(mockGo.gets, " "); got != "github.com/example/a@v1.2.0 github.com/example/b@v2.0.0" {
+		t.Fatalf("expected both modules to be bumped, got %s", got)
+	}
+}
+
+// requiringGoOperations adds the module go get is asked for to go.mod, so the
+// update is not a no-op.
+type requiringGoOperations struct {
+	mockGoOperations
+}
+
+func (m *requiringGoOperations) Get(ctx context.Context, repoPath, module, version string) error {
+	f, err := os.OpenFile(filepath.Join(repoPath, "go.mod"), os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := fmt.Fprintf(f, "\nrequire %s %s\n", module, version); err != nil {
+		return err
+	}
+	return m.mockGoOperations.Get(ctx, repoPath, module, version)
+}
+
+func TestExecutor_Apply_LocalTarget(t *testing.T) {
+	root := t.TempDir()
+	workPath := filepath.Join(root, "worktree")
+	targetDir := filepath.Join(root, "lib")
+	for dir, mod := range map[string]string{
+		workPath:  "module github.com/test/repo\n\ngo 1.22\n",
+		targetDir: "module github.com/example/lib\n\ngo 1.22\n",
+	} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(mod), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mockGo := &requiringGoOperations{}
+	runner := &recordingCommandRunner{}
+	input := executor.WorkItemContext{
+		Item: planner.WorkItem{
+			Repo:          "https://github.com/test/repo",
+			SourceModule:  "github.com/example/lib",
+			SourceVersion: "v1.2.0",
+			Branch:        "main",
+			BranchName:    "auto/lib-v1.2.0",
+			CommitMessage: "Update github.com/example/lib to v1.2.0",
+			Tests:         []manifest.Command{{Cmd: []string{"go", "test", "./..."}}},
+		},
+		Workspace:   root,
+		Git:         &mockGitOperations{clonePath: root, workPath: workPath, commitHash: "abc123"},
+		Go:          mockGo,
+		Runner:      runner,
+		Logger:      &mockLogger{},
+		LocalTarget: targetDir,
+	}
+
+	result, err := executor.New().Apply(context.Background(), input)
+	if err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if result.Status != executor.StatusCompleted || result.CommitHash != "abc123" {
+		t.Fatalf("expected a completed commit, got %s %q", result.Status, result.CommitHash)
+	}
+	if got := strings.Join(mockGo.gets, " "); got != "github.com/example/lib@v1.2.0" {
+		t.Errorf("expected the target version to be required, got %s", got)
+	}
+	if len(runner.calls) != 1 {
+		t.Fatalf("expected one test command, got %d", len(runner.calls))
+	}
+	goWork := runner.calls[0].env["GOWORK"]
+	if goWork == "" || strings.HasPrefix(goWork, workPath) {
+		t.Fatalf("GOWORK = %q, want a go.work outside the worktree", goWork)
+	}
+	if _, err := os.Stat(goWork); !os.IsNotExist(err) {
+		t.Errorf("expected the go.work to be removed after the item, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(workPath, "go.work")); !os.IsNotExist(err) {
+		t.Errorf("expected no go.work in the worktree")
+	}
+
+	// A checkout of another module fails the item before its tests run
+	runner.calls = nil
+	input.Item.SourceModule = "github.com/example/other"
+	result, err = executor.New().Apply(context.Background(), input)
+	if err == nil || result.Status != executor.StatusFailed || !strings.Contains(result.Reason, "local target") {
+		t.Fatalf("expected a local target failure, got %v, %+v", err, result)
+	}
+	if len(runner.calls) != 0 {
+		t.Errorf("expected no commands to run, got %d", len(runner.calls))
+	}
+}
+
+func TestExecutor_Apply_RendersEnvTemplates(t *testing.T) {
+	workspace := "/workspace"
+	mockGit := &mockGitOperations{clonePath: workspace + "/test-repo", workPath: workspace + "/test-repo/worktree", commitHash: "abc123"}
+	recordingRunner := &recordingCommandRunner{}
+
+	workItem := planner.WorkItem{
+		Repo:          "github.com/test/repo",
+		SourceModule:  "github.com/goliatone/go-errors",
+		SourceVersion: "v1.2.3",
+		Branch:        "main",
+		BranchName:    "update-branch",
+		CommitMessage: "update dependency",
+		Tests:         []manifest.Command{{Cmd: []string{"go", "test", "./..."}}},
+		Env: map[string]string{
+			"MODULE_VERSION": "{{ .SourceVersion }}",
+			"EXPECTED":       "{{ .SourceModule }}@{{ .SourceVersion }}",
+			"PLAIN":          "bar",
+		},
+	}
+
+	result, err := executor.New().Apply(context.Background(), executor.WorkItemContext{
+		Item:      workItem,
+		Workspace: workspace,
+		Git:       mockGit,
+		Go:        &mockGoOperations{},
+		Runner:    recordingRunner,
+		Logger:    &mockLogger{},
+	})
+	if err != nil || result.Status != executor.StatusCompleted {
+		t.Fatalf("apply = %+v, %v", result, err)
+	}
+	env := recordingRunner.calls[0].env
+	if env["MODULE_VERSION"] != "v1.2.3" || env["EXPECTED"] != "github.com/goliatone/go-errors@v1.2.3" || env["PLAIN"] != "bar" {
+		t.Errorf("env = %v, want templates rendered with the work item", env)
+	}
+
+	workItem.Env = map[string]string{"MODULE_VERSION": "{{ .Version }}"}
+	result, err = executor.New().Apply(context.Background(), executor.WorkItemContext{
+		Item:      workItem,
+		Workspace: workspace,
+		Git:       mockGit,
+		Go:        &mockGoOperations{},
+		Runner:    &recordingCommandRunner{},
+		Logger:    &mockLogger{},
+	})
+	if err == nil || result.Status != executor.StatusFailed || !strings.Contains(err.Error(), "env MODULE_VERSION") {
+		t.Errorf("apply = %+v, %v, want a failure naming the variable", result, err)
+	}
+}
+
+func TestExecutor_Apply_AppliesDependentManifestFromClone(t *testing.T) {
+	clonePath := t.TempDir()
+	overrides := `dependents:
+  github.com/goliatone/go-errors:
+    tests:
+      - cmd: ["make", "test"]
+    labels: ["deps"]
+`
+	if err := os.WriteFile(filepath.Join(clonePath, ".cascade.yaml"), []byte(overrides), 0o644); err != nil {
+		t.Fatalf("write overrides: %v", err)
+	}
+
+	recordingRunner := &recordingCommandRunner{}
+	input := executor.WorkItemContext{
+		Item: planner.WorkItem{
+			Repo:          "https://github.com/test/repo",
+			SourceModule:  "github.com/goliatone/go-errors",
+			SourceVersion: "v1.2.3",
+			Branch:        "main",
+			BranchName:    "update-branch",
+			CommitMessage: "update dependency",
+			Tests:         []manifest.Command{{Cmd: []string{"go", "test", "./..."}}},
+		},
+		Workspace: "/workspace",
+		Git:       &mockGitOperations{clonePath: clonePath, workPath: clonePath, commitHash: "abc123"},
+		Go:        &mockGoOperations{},
+		Runner:    recordingRunner,
+		Logger:    &mockLogger{},
+	}
+
+	result, err := executor.New().Apply(context.Background(), input)
+	if err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+
+	if len(recordingRunner.calls) != 1 || strings.Join(recordingRunner.calls[0].cmd.Cmd, " ") != "make test" {
+		t.Fatalf("expected override test command to run, got %#v", recordingRunner.calls)
+	}
+	if result.EffectiveItem == nil {
+		t.Fatal("expected EffectiveItem to be set when overrides change the work item")
+	}
+	if !reflect.DeepEqual(result.EffectiveItem.Labels, []string{"deps"}) {
+		t.Fatalf("expected override labels, got %v", result.EffectiveItem.Labels)
+	}
+}
+
+func TestExecutor_Apply_DependentManifestSkip(t *testing.T) {
+	clonePath := t.TempDir()
+	overrides := "dependents:\n  github.com/goliatone/go-errors:\n    skip: true\n"
+	if err := os.WriteFile(filepath.Join(clonePath, ".cascade.yaml"), []byte(overrides), 0o644); err != nil {
+		t.Fatalf("write overrides: %v", err)
+	}
+
+	input := executor.WorkItemContext{
+		Item: planner.WorkItem{
+			Repo:          "https://github.com/test/repo",
+			SourceModule:  "github.com/goliatone/go-errors",
+			SourceVersion: "v1.2.3",
+			BranchName:    "update-branch",
+			CommitMessage: "update dependency",
+		},
+		Workspace: "/workspace",
+		Git:       &mockGitOperations{clonePath: clonePath, workPath: clonePath},
+		Go:        &mockGoOperations{shouldFail: true},
+		Runner:    &mockCommandRunner{},
+		Logger:    &mockLogger{},
+	}
+
+	result, err := executor.New().Apply(context.Background(), input)
+	if err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if result.Status != executor.StatusSkipped {
+		t.Fatalf("expected skipped status, got %s (%s)", result.Status, result.Reason)
+	}
+}
+
+func TestExecutor_Apply_ReportsProgress(t *testing.T) {
+	var phases []executor.Phase
+	input := executor.WorkItemContext{
+		Item: planner.WorkItem{
+			Repo:          "https://github.com/test/repo",
+			SourceModule:  "github.com/goliatone/go-errors",
+			SourceVersion: "v1.2.3",
+			Branch:        "main",
+			BranchName:    "update-branch",
+			CommitMessage: "update dependency",
+		},
+		Workspace: "/workspace",
+		Git:       &mockGitOperations{clonePath: t.TempDir(), workPath: t.TempDir(), commitHash: "abc123"},
+		Go:        &mockGoOperations{},
+		Runner:    &mockCommandRunner{},
+		Logger:    &mockLogger{},
+		Progress:  func(phase executor.Phase) { phases = append(phases, phase) },
+	}
+
+	if _, err := executor.New().Apply(context.Background(), input); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+
+	want := []executor.Phase{
+		executor.PhaseClone,
+		executor.PhaseWorktree,
+		executor.PhaseDependencies,
+		executor.PhaseTidy,
+		executor.PhaseTests,
+		executor.PhaseExtraCommands,
+		executor.PhaseCommit,
+		executor.PhasePush,
+	}
+	if !reflect.DeepEqual(phases, want) {
+		t.Fatalf("phases = %v, want %v", phases, want)
+	}
+}
+
+// submoduleGitOperations adds submodule support to mockGitOperations.
+type submoduleGitOperations struct {
+	mockGitOperations
+	submodules map[string]string
+	updated    []string
+}
+
+func (m *submoduleGitOperations) FindSubmodule(ctx context.Context, repoPath, repo string) (string, error) {
+	if path, ok := m.submodules[repo]; ok {
+		return path, nil
+	}
+	return "", fmt.Errorf("no submodule of %s found in %s", repo, repoPath)
+}
+
+func (m *submoduleGitOperations) UpdateSubmodule(ctx context.Context, repoPath, path, ref string) (executor.SubmoduleUpdate, error) {
+	m.updated = append(m.updated, path+"@"+ref)
+	return executor.SubmoduleUpdate{Path: path, OldCommit: "1111111", NewCommit: "2222222"}, nil
+}
+
+func TestExecutor_Apply_GitSubmoduleStrategy(t *testing.T) {
+	git := &submoduleGitOperations{
+		mockGitOperations: mockGitOperations{clonePath: t.TempDir(), workPath: t.TempDir(), commitHash: "abc123"},
+		submodules:        map[string]string{"github.com/goliatone/go-errors": "third_party/go-errors"},
+	}
+	var phases []executor.Phase
+	input := executor.WorkItemContext{
+		Item: planner.WorkItem{
+			Repo:           "https://github.com/test/repo",
+			SourceModule:   "github.com/goliatone/go-errors/v2",
+			SourceVersion:  "v2.1.0",
+			Branch:         "main",
+			BranchName:     "update-branch",
+			CommitMessage:  "update dependency",
+			UpdateStrategy: manifest.UpdateStrategyGitSubmodule,
+		},
+		Workspace: "/workspace",
+		Git:       git,
+		Go:        &mockGoOperations{shouldFail: true}, // go commands must not run
+		Runner:    &mockCommandRunner{},
+		Logger:    &mockLogger{},
+		Progress:  func(phase executor.Phase) { phases = append(phases, phase) },
+	}
+
+	result, err := executor.New().Apply(context.Background(), input)
+	if err != nil {
+		t.Fatalf("apply: %v (%s)", err, result.Reason)
+	}
+	if result.Status != executor.StatusCompleted {
+		t.Fatalf("status = %s, want completed", result.Status)
+	}
+	if !reflect.DeepEqual(git.updated, []string{"third_party/go-errors@v2.1.0"}) {
+		t.Fatalf("updated submodules = %v", git.updated)
+	}
+	if slices.Contains(phases, executor.PhaseTidy) {
+		t.Fatalf("expected no tidy phase for submodules, got %v", phases)
+	}
+	impact := result.DependencyImpact
+	if impact == nil || impact.OldVersion != "1111111" || impact.NewVersion != "v2.1.0" || !impact.NewVersionDetected {
+		t.Fatalf("dependency impact = %+v", impact)
+	}
+}
+
+func TestExecutor_Apply_GitSubmoduleStrategy_Errors(t *testing.T) {
+	item := planner.WorkItem{
+		Repo:           "https://github.com/test/repo",
+		SourceModule:   "github.com/goliatone/go-errors",
+		SourceVersion:  "v1.2.3",
+		Branch:         "main",
+		BranchName:     "update-branch",
+		CommitMessage:  "update dependency",
+		UpdateStrategy: manifest.UpdateStrategyGitSubmodule,
+	}
+
+	tests := []struct {
+		name       string
+		git        executor.GitOperations
+		wantReason string
+	}{
+		{
+			name:       "git operations without submodule support",
+			git:        &mockGitOperations{clonePath: t.TempDir(), workPath: t.TempDir()},
+			wantReason: "do not support submodules",
+		},
+		{
+			name: "no matching submodule",
+			git: &submoduleGitOperations{
+				mockGitOperations: mockGitOperations{clonePath: t.TempDir(), workPath: t.TempDir()},
+			},
+			wantReason: "set submodule_path",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := executor.WorkItemContext{
+				Item:      item,
+				Workspace: "/workspace",
+				Git:       tt.git,
+				Go:        &mockGoOperations{},
+				Runner:    &mockCommandRunner{},
+				Logger:    &mockLogger{},
+			}
+
+			result, err := executor.New().Apply(context.Background(), input)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if result.Status != executor.StatusFailed || !strings.Contains(result.Reason, tt.wantReason) {
+				t.Fatalf("result = %s %q, want reason containing %q", result.Status, result.Reason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestExecutor_Apply_StartsServicesAroundCommands(t *testing.T) {
+	runner := &recordingCommandRunner{}
+	services := &recordingServiceManager{env: map[string]string{"POSTGRES_HOST": "127.0.0.1", "DATABASE_URL": "from-service"}}
+	var phases []executor.Phase
+
+	input := executor.WorkItemContext{
+		Item: planner.WorkItem{
+			Repo:          "goliatone/app",
+			SourceModule:  "github.com/goliatone/go-errors",
+			SourceVersion: "v1.2.3",
+			Branch:        "main",
+			BranchName:    "update-branch",
+			CommitMessage: "update dependency",
+			Tests:         []manifest.Command{{Cmd: []string{"go", "test", "./..."}}},
+			Env:           map[string]string{"DATABASE_URL": "from-manifest"},
+			Services:      []manifest.Service{{Name: "postgres", Image: "postgres:16", Port: 5432}},
+		},
+		Workspace: "/workspace",
+		Git:       &mockGitOperations{clonePath: t.TempDir(), workPath: t.TempDir(), commitHash: "abc123"},
+		Go:        &mockGoOperations{},
+		Runner:    runner,
+		Services:  services,
+		Logger:    &mockLogger{},
+		Progress:  func(phase executor.Phase) { phases = append(phases, phase) },
+	}
+
+	result, err := executor.New().Apply(context.Background(), input)
+	if err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if result.Status != executor.StatusCompleted {
+		t.Fatalf("expected completed status, got %s (%s)", result.Status, result.Reason)
+	}
+
+	if services.started != executor.ServiceProject("goliatone/app", "update-branch") || services.stopped != services.started {
+		t.Fatalf("expected services started and stopped for the item, got start=%q stop=%q", services.started, services.stopped)
+	}
+	if len(runner.calls) != 1 {
+		t.Fatalf("expected one command, got %d", len(runner.calls))
+	}
+	env := runner.calls[0].env
+	if env["POSTGRES_HOST"] != "127.0.0.1" {
+		t.Fatalf("expected service env to be injected, got %v", env)
+	}
+	if env["DATABASE_URL"] != "from-manifest" {
+		t.Fatalf("expected manifest env to win over service env, got %q", env["DATABASE_URL"])
+	}
+	if phases[4] != executor.PhaseServices {
+		t.Fatalf("expected services phase before tests, got %v", phases)
+	}
+}
+
+func TestExecutor_Apply_ServiceStartupFailure(t *testing.T) {
+	runner := &recordingCommandRunner{}
+	input := executor.WorkItemContext{
+		Item: planner.WorkItem{
+			Repo:          "goliatone/app",
+			SourceModule:  "github.com/goliatone/go-errors",
+			SourceVersion: "v1.2.3",
+			Branch:        "main",
+			BranchName:    "update-branch",
+			CommitMessage: "update dependency",
+			Tests:         []manifest.Command{{Cmd: []string{"go", "test", "./..."}}},
+			Services:      []manifest.Service{{Name: "postgres", Image: "postgres:16"}},
+		},
+		Workspace: "/workspace",
+		Git:       &mockGitOperations{clonePath: t.TempDir(), workPath: t.TempDir()},
+		Go:        &mockGoOperations{},
+		Runner:    runner,
+		Logger:    &mockLogger{},
+	}
+
+	result, err := executor.New().Apply(context.Background(), input)
+	if err == nil {
+		t.Fatal("expected error without a service manager")
+	}
+	if result.Status != executor.StatusFailed || !strings.Contains(result.Reason, "service startup failed") {
+		t.Fatalf("unexpected result: %s %s", result.Status, result.Reason)
+	}
+	if len(runner.calls) != 0 {
+		t.Fatalf("expected tests not to run, got %d calls", len(runner.calls))
+	}
+}
+
 // Mock implementations for testing
 type mockGitOperations struct {
 	clonePath  string
@@ -391,12 +958,14 @@ func (m *mockGitOperations) Push(ctx context.Context, repoPath, branch string) e
 
 type mockGoOperations struct {
 	shouldFail bool
+	gets       []string
 }
 
 func (m *mockGoOperations) Get(ctx context.Context, repoPath, module, version string) error {
 	if m.shouldFail {
 		return fmt.Errorf("mock go get error")
 	}
+	m.gets = append(m.gets, module+"@"+version)
 	return nil
 }
 
@@ -444,8 +1013,52 @@ func (r *recordingCommandRunner) Run(ctx context.Context, repoPath string, cmd m
 	return executor.CommandResult{Command: cmd}, nil
 }
 
+type recordingServiceManager struct {
+	env     map[string]string
+	started string
+	stopped string
+}
+
+func (m *recordingServiceManager) Start(ctx context.Context, project string, services []manifest.Service) (map[string]string, error) {
+	m.started = project
+	return m.env, nil
+}
+
+func (m *recordingServiceManager) Stop(ctx context.Context, project string, services []manifest.Service) error {
+	m.stopped = project
+	return nil
+}
+
 type mockLogger struct{}
 
 func (m *mockLogger) Info(msg string, args ...any)  {}
 func (m *mockLogger) Error(msg string, args ...any) {}
 func (m *mockLogger) Debug(msg string, args ...any) {}
+
+func TestExecutor_Apply_RefusesNeverTouchRepos(t *testing.T) {
+	// cloning fails, so only the guard can produce a NeverTouchError
+	mockGit := &mockGitOperations{shouldFail: true}
+	result, err := executor.New().Apply(context.Background(), executor.WorkItemContext{
+		Item: planner.WorkItem{
+			Repo:          "acme/frozen",
+			SourceModule:  "github.com/acme/lib",
+			SourceVersion: "v1.0.0",
+			BranchName:    "update-lib",
+			CommitMessage: "Update lib",
+		},
+		Workspace:  "/workspace",
+		Git:        mockGit,
+		Go:         &mockGoOperations{},
+		Runner:     &mockCommandRunner{},
+		Logger:     &mockLogger{},
+		NeverTouch: []string{"github.com/acme/frozen"},
+	})
+
+	var neverTouch *manifest.NeverTouchError
+	if !errors.As(err, &neverTouch) {
+		t.Fatalf("expected a NeverTouchError, got %v", err)
+	}
+	if result == nil || result.Status != executor.StatusFailed {
+		t.Fatalf("result = %+v, want a failed result", result)
+	}
+}
@@ -9,36 +9,58 @@ import (
 
 	"github.com/goliatone/cascade/pkg/gitutil"
 	"github.com/goliatone/cascade/pkg/util/modpath"
+	"github.com/goliatone/cascade/pkg/workspace/layout"
 )
 
 // gitOperations implements GitOperations interface using a command runner.
 type gitOperations struct {
 	runner GitCommandRunner
+	layout layout.Layout
 }
 
-// NewGitOperations creates a new GitOperations implementation with the default command runner.
-func NewGitOperations() GitOperations {
-	return &gitOperations{
-		runner: NewDefaultGitCommandRunner(),
+// GitOperationsOption configures the git operations.
+type GitOperationsOption func(*gitOperations)
+
+// WithWorkspaceLayout places clones in the workspace according to l instead of
+// the default <workspace>/<repo>.
+func WithWorkspaceLayout(l layout.Layout) GitOperationsOption {
+	return func(g *gitOperations) {
+		g.layout = l
 	}
 }
 
+// NewGitOperations creates a new GitOperations implementation with the default command runner.
+func NewGitOperations(opts ...GitOperationsOption) GitOperations {
+	return NewGitOperationsWithRunner(NewDefaultGitCommandRunner(), opts...)
+}
+
 // NewGitOperationsWithRunner creates a new GitOperations implementation with a custom command runner.
-func NewGitOperationsWithRunner(runner GitCommandRunner) GitOperations {
-	return &gitOperations{
+func NewGitOperationsWithRunner(runner GitCommandRunner, opts ...GitOperationsOption) GitOperations {
+	g := &gitOperations{
 		runner: runner,
 	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
 }
 
 // EnsureClone ensures a repository is cloned to the workspace and returns the repo path.
-// If the repository already exists, it verifies it's the correct repository.
+// If the repository already exists, it verifies it's the correct repository. A clone of
+// the same repository left at <workspace>/<repo> by the default layout is moved to the
+// directory of the configured layout instead of being cloned again.
 func (g *gitOperations) EnsureClone(ctx context.Context, repo, workspace string) (string, error) {
-	// Extract repo name from URL for directory name
-	repoName := extractRepoName(repo)
-	repoPath := filepath.Join(workspace, repoName)
+	repoPath, err := g.layout.Dir(workspace, repo)
+	if err != nil {
+		return "", fmt.Errorf("failed to place repository %s in workspace: %w", repo, err)
+	}
 
 	cloneURL := buildCloneURL(repo)
 
+	if err := g.migrateClone(ctx, repo, cloneURL, workspace, repoPath); err != nil {
+		return "", err
+	}
+
 	// Check if repository already exists
 	if _, err := os.Stat(filepath.Join(repoPath, ".git")); err == nil {
 		// Verify it's the correct repository
@@ -66,12 +88,12 @@ func (g *gitOperations) EnsureClone(ctx context.Context, repo, workspace string)
 	}
 
 	// Create workspace directory if it doesn't exist
-	if err := os.MkdirAll(workspace, 0755); err != nil {
-		return "", fmt.Errorf("failed to create workspace directory %s: %w", workspace, err)
+	if err := os.MkdirAll(filepath.Dir(repoPath), 0755); err != nil {
+		return "", fmt.Errorf("failed to create workspace directory %s: %w", filepath.Dir(repoPath), err)
 	}
 
 	// Clone the repository
-	_, err := g.runner.Run(ctx, "", "clone", cloneURL, repoPath)
+	_, err = g.runner.Run(ctx, "", "clone", cloneURL, repoPath)
 	if err != nil {
 		return "", fmt.Errorf("failed to clone repository %s to %s: %w", repo, repoPath, err)
 	}
@@ -79,6 +101,39 @@ func (g *gitOperations) EnsureClone(ctx context.Context, repo, workspace string)
 	return repoPath, nil
 }
 
+// migrateClone moves a clone of repo from its default layout location to
+// repoPath. Clones of other repositories that share the name are left in place,
+// and nothing is moved once repoPath exists.
+func (g *gitOperations) migrateClone(ctx context.Context, repo, cloneURL, workspace, repoPath string) error {
+	legacyPath, err := layout.Layout{}.Dir(workspace, repo)
+	if err != nil || legacyPath == repoPath || strings.HasPrefix(repoPath, legacyPath+string(filepath.Separator)) {
+		return nil
+	}
+	if _, err := os.Stat(repoPath); err == nil {
+		return nil
+	}
+	if _, err := os.Stat(filepath.Join(legacyPath, ".git")); err != nil {
+		return nil
+	}
+
+	origin, err := g.runner.Run(ctx, legacyPath, "config", "--get", "remote.origin.url")
+	if err != nil || normalizeGitURL(cleanGitOutput(origin)) != normalizeGitURL(cloneURL) {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(repoPath), 0755); err != nil {
+		return fmt.Errorf("failed to create workspace directory %s: %w", filepath.Dir(repoPath), err)
+	}
+	if err := os.Rename(legacyPath, repoPath); err != nil {
+		return fmt.Errorf("failed to move clone %s to %s: %w", legacyPath, repoPath, err)
+	}
+	// Worktrees record the absolute path of their repository
+	if _, err := g.runner.Run(ctx, repoPath, "worktree", "repair"); err != nil {
+		return fmt.Errorf("moved clone %s to %s but failed to repair its worktrees: %w", legacyPath, repoPath, err)
+	}
+	return nil
+}
+
 // EnsureWorktree ensures a worktree exists for the given branch and returns the worktree path.
 // If the branch doesn't exist, it creates it from the current default branch.
 func (g *gitOperations) EnsureWorktree(ctx context.Context, repoPath, branch string, base string) (string, error) {
@@ -235,6 +290,18 @@ func (g *gitOperations) Commit(ctx context.Context, repoPath, message string) (s
 	return hash, nil
 }
 
+// Diff stages all changes in repoPath and returns them as a unified diff.
+func (g *gitOperations) Diff(ctx context.Context, repoPath string) (string, error) {
+	if _, err := g.runner.Run(ctx, repoPath, "add", "."); err != nil {
+		return "", fmt.Errorf("failed to stage changes in %s: %w", repoPath, err)
+	}
+	diff, err := g.runner.Run(ctx, repoPath, "diff", "--cached", "--no-color", "--no-ext-diff")
+	if err != nil {
+		return "", fmt.Errorf("failed to diff changes in %s: %w", repoPath, err)
+	}
+	return diff, nil
+}
+
 // Push pushes the specified branch to the origin remote.
 func (g *gitOperations) Push(ctx context.Context, repoPath, branch string) error {
 	_, err := g.runner.Run(ctx, repoPath, "push", "origin", branch)
@@ -245,6 +312,108 @@ func (g *gitOperations) Push(ctx context.Context, repoPath, branch string) error
 	return nil
 }
 
+// FindSubmodule returns the path of the submodule declared in .gitmodules whose
+// URL points at repo, given as host/owner/name.
+func (g *gitOperations) FindSubmodule(ctx context.Context, repoPath, repo string) (string, error) {
+	output, err := g.runner.Run(ctx, repoPath, "config", "--file", ".gitmodules", "--get-regexp", `^submodule\..*\.url$`)
+	if err != nil {
+		return "", fmt.Errorf("failed to read .gitmodules in %s: %w", repoPath, err)
+	}
+
+	want := strings.ToLower(strings.TrimSuffix(repo, ".git"))
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 || submoduleRepository(fields[1]) != want {
+			continue
+		}
+		key := strings.TrimSuffix(fields[0], ".url")
+		path, err := g.runner.Run(ctx, repoPath, "config", "--file", ".gitmodules", "--get", key+".path")
+		if err != nil {
+			return "", fmt.Errorf("failed to read path of %s in %s: %w", key, repoPath, err)
+		}
+		return cleanGitOutput(path), nil
+	}
+
+	return "", fmt.Errorf("no submodule of %s found in %s", repo, repoPath)
+}
+
+// UpdateSubmodule checks out ref in the submodule at path and stages the new
+// submodule pointer in the superproject.
+func (g *gitOperations) UpdateSubmodule(ctx context.Context, repoPath, path, ref string) (SubmoduleUpdate, error) {
+	update := SubmoduleUpdate{Path: path}
+
+	oldCommit, err := g.runner.Run(ctx, repoPath, "rev-parse", "HEAD:"+path)
+	if err != nil {
+		return update, fmt.Errorf("failed to resolve submodule %s in %s: %w", path, repoPath, err)
+	}
+	update.OldCommit = cleanGitOutput(oldCommit)
+
+	if _, err := g.runner.Run(ctx, repoPath, "submodule", "update", "--init", "--", path); err != nil {
+		return update, fmt.Errorf("failed to initialise submodule %s in %s: %w", path, repoPath, err)
+	}
+
+	submodulePath := filepath.Join(repoPath, path)
+	if _, err := g.runner.Run(ctx, submodulePath, "fetch", "--tags", "origin"); err != nil {
+		return update, fmt.Errorf("failed to fetch tags in submodule %s: %w", path, err)
+	}
+	if _, err := g.runner.Run(ctx, submodulePath, "checkout", "--detach", "refs/tags/"+ref+"^{commit}"); err != nil {
+		return update, fmt.Errorf("failed to check out %s in submodule %s: %w", ref, path, err)
+	}
+
+	newCommit, err := g.runner.Run(ctx, submodulePath, "rev-parse", "HEAD")
+	if err != nil {
+		return update, fmt.Errorf("failed to resolve %s in submodule %s: %w", ref, path, err)
+	}
+	update.NewCommit = cleanGitOutput(newCommit)
+
+	if _, err := g.runner.Run(ctx, repoPath, "add", "--", path); err != nil {
+		return update, fmt.Errorf("failed to stage submodule %s in %s: %w", path, repoPath, err)
+	}
+
+	return update, nil
+}
+
+// RevertCommit reverts commit on the branch checked out at repoPath.
+func (g *gitOperations) RevertCommit(ctx context.Context, repoPath, commit string) (string, error) {
+	parents, err := g.runner.Run(ctx, repoPath, "rev-list", "--parents", "-n", "1", commit)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve commit %s in %s: %w", commit, repoPath, err)
+	}
+
+	args := []string{"revert", "--no-edit"}
+	// rev-list prints the commit followed by its parents
+	if len(strings.Fields(parents)) > 2 {
+		args = append(args, "-m", "1")
+	}
+	args = append(args, commit)
+
+	if _, err := g.runner.Run(ctx, repoPath, args...); err != nil {
+		if _, abortErr := g.runner.Run(ctx, repoPath, "revert", "--abort"); abortErr != nil {
+			return "", fmt.Errorf("failed to revert %s in %s: %w (abort failed: %v)", commit, repoPath, err, abortErr)
+		}
+		return "", fmt.Errorf("failed to revert %s in %s: %w", commit, repoPath, err)
+	}
+
+	hash, err := g.runner.Run(ctx, repoPath, "rev-parse", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("failed to get commit hash in %s: %w", repoPath, err)
+	}
+	return cleanGitOutput(hash), nil
+}
+
+// submoduleRepository returns the host/owner/name of a submodule URL, lowercased,
+// or "" for relative URLs.
+func submoduleRepository(url string) string {
+	if strings.HasPrefix(url, ".") {
+		return ""
+	}
+	parsed, err := gitutil.ParseRepoURL(url)
+	if err != nil || parsed.Owner == "" {
+		return ""
+	}
+	return strings.ToLower(parsed.Host + "/" + parsed.Owner + "/" + parsed.Name)
+}
+
 // branchExists checks if a given branch reference exists.
 func (g *gitOperations) branchExists(ctx context.Context, repoPath, ref string) bool {
 	_, err := g.runner.Run(ctx, repoPath, "show-ref", "--verify", "--quiet", ref)
@@ -7,6 +7,8 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/goliatone/cascade/pkg/workspace/layout"
 )
 
 // mockGitCommandRunner implements GitCommandRunner for testing.
@@ -311,6 +313,24 @@ func TestGitOperations_Commit(t *testing.T) {
 	}
 }
 
+func TestGitOperations_Diff(t *testing.T) {
+	mockRunner := newMockGitCommandRunner()
+	diff := "diff --git a/go.mod b/go.mod\n--- a/go.mod\n+++ b/go.mod"
+	mockRunner.setResponse("diff --cached --no-color --no-ext-diff", diff, nil)
+
+	git := NewGitOperationsWithRunner(mockRunner)
+	got, err := git.(DiffOperations).Diff(context.Background(), "/tmp/repo")
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	if got != diff {
+		t.Errorf("Diff() = %q, want %q", got, diff)
+	}
+	if len(mockRunner.calls) != 2 || strings.Join(mockRunner.calls[0].args, " ") != "add ." {
+		t.Errorf("calls = %+v, want changes staged before the diff", mockRunner.calls)
+	}
+}
+
 func TestGitOperations_EnsureClone_AllowsTrailingNewline(t *testing.T) {
 	mockRunner := newMockGitCommandRunner()
 	mockRunner.setResponse("config --get remote.origin.url", "https://github.com/test/repo.git\n", nil)
@@ -339,6 +359,65 @@ func TestGitOperations_EnsureClone_AllowsTrailingNewline(t *testing.T) {
 	}
 }
 
+func TestGitOperations_EnsureClone_WorkspaceLayout(t *testing.T) {
+	nested, err := layout.Parse("{{host}}/{{owner}}/{{repo}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		origin     string
+		wantMoved  bool
+		wantCalled string
+	}{
+		{name: "moves a clone of the same repository", origin: "git@github.com:test/repo.git", wantMoved: true, wantCalled: "worktree repair"},
+		{name: "keeps a clone of another repository", origin: "https://github.com/other/repo.git", wantCalled: "clone https://github.com/test/repo.git"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRunner := newMockGitCommandRunner()
+			mockRunner.setResponse("config --get remote.origin.url", tt.origin+"\n", nil)
+			git := NewGitOperationsWithRunner(mockRunner, WithWorkspaceLayout(nested))
+
+			workspace := t.TempDir()
+			legacyPath := filepath.Join(workspace, "repo")
+			if err := os.MkdirAll(filepath.Join(legacyPath, ".git"), 0o755); err != nil {
+				t.Fatal(err)
+			}
+
+			path, err := git.EnsureClone(context.Background(), "https://github.com/test/repo.git", workspace)
+			if err != nil {
+				t.Fatalf("EnsureClone() error = %v", err)
+			}
+			if want := filepath.Join(workspace, "github.com", "test", "repo"); path != want {
+				t.Errorf("EnsureClone() = %s, want %s", path, want)
+			}
+
+			_, legacyErr := os.Stat(legacyPath)
+			if moved := os.IsNotExist(legacyErr); moved != tt.wantMoved {
+				t.Errorf("legacy clone moved = %v, want %v", moved, tt.wantMoved)
+			}
+			if tt.wantMoved {
+				if _, err := os.Stat(filepath.Join(path, ".git")); err != nil {
+					t.Errorf("moved clone missing: %v", err)
+				}
+			}
+
+			called := false
+			for _, call := range mockRunner.calls {
+				if strings.HasPrefix(strings.Join(call.args, " "), tt.wantCalled) {
+					called = true
+				}
+			}
+			if !called {
+				t.Errorf("git %q was not run: %+v", tt.wantCalled, mockRunner.calls)
+			}
+		})
+	}
+}
+
 func TestGitOperations_EnsureWorktree_AllowsTrailingBranchNewline(t *testing.T) {
 	const branch = "feature"
 
@@ -482,6 +561,52 @@ func TestGitOperations_Push(t *testing.T) {
 	}
 }
 
+func TestGitOperations_RevertCommit(t *testing.T) {
+	tests := []struct {
+		name     string
+		parents  string
+		wantCall string
+	}{
+		{name: "squashed commit", parents: "abc123 p1\n", wantCall: "revert --no-edit abc123"},
+		{name: "merge commit", parents: "abc123 p1 p2\n", wantCall: "revert --no-edit -m 1 abc123"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := newMockGitCommandRunner()
+			runner.setResponse("rev-list --parents -n 1 abc123", tt.parents, nil)
+			runner.setResponse("rev-parse HEAD", "def456\n", nil)
+			git := &gitOperations{runner: runner}
+
+			hash, err := git.RevertCommit(context.Background(), "/repo", "abc123")
+			if err != nil {
+				t.Fatalf("RevertCommit() error = %v", err)
+			}
+			if hash != "def456" {
+				t.Errorf("RevertCommit() = %q, want def456", hash)
+			}
+			if !containsGitCall(runner.calls, tt.wantCall) {
+				t.Errorf("expected git call %q, got %v", tt.wantCall, runner.calls)
+			}
+		})
+	}
+}
+
+func TestGitOperations_RevertCommit_AbortsOnConflict(t *testing.T) {
+	runner := newMockGitCommandRunner()
+	runner.setResponse("rev-list --parents -n 1 abc123", "abc123 p1", nil)
+	runner.setResponse("revert --no-edit abc123", "", errors.New("conflict in go.mod"))
+	git := &gitOperations{runner: runner}
+
+	_, err := git.RevertCommit(context.Background(), "/repo", "abc123")
+	if err == nil || !strings.Contains(err.Error(), "conflict in go.mod") {
+		t.Fatalf("RevertCommit() error = %v, want conflict", err)
+	}
+	if !containsGitCall(runner.calls, "revert --abort") {
+		t.Error("expected the conflicting revert to be aborted")
+	}
+}
+
 func TestGitOperations_ExtractRepoName(t *testing.T) {
 	tests := []struct {
 		name     string
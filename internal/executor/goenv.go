@@ -0,0 +1,212 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/goliatone/cascade/internal/manifest"
+	"github.com/goliatone/cascade/internal/planner"
+)
+
+// GoEnvOperations is implemented by GoOperations that can run go commands with
+// extra environment variables. The executor uses it to give go get and go mod
+// tidy a dependent's environment, GOFLAGS, and private module credentials without
+// touching the process environment shared by other work items.
+type GoEnvOperations interface {
+	GetWithEnv(ctx context.Context, repoPath, module, version string, env map[string]string) error
+	TidyWithEnv(ctx context.Context, repoPath string, env map[string]string) error
+}
+
+// goEnv holds the environment of a work item's go commands.
+type goEnv struct {
+	vars  map[string]string
+	netrc string
+}
+
+// newGoEnv builds the go command environment of item: its env rendered with
+// renderEnv, GOFLAGS extended with item.GoFlags, and, when the item has
+// credentials, a private netrc file that both the go command (NETRC) and git (a
+// credential helper) read. Passwords are looked up with getenv. Call cleanup once
+// the item's commands have finished.
+func newGoEnv(item planner.WorkItem, getenv func(string) string) (*goEnv, error) {
+	vars, err := renderEnv(item)
+	if err != nil {
+		return nil, err
+	}
+	env := &goEnv{vars: vars}
+
+	if flags := strings.TrimSpace(item.GoFlags); flags != "" {
+		base, ok := item.Env["GOFLAGS"]
+		if !ok {
+			base = getenv("GOFLAGS")
+		}
+		env.vars["GOFLAGS"] = strings.TrimSpace(base + " " + flags)
+	}
+
+	if len(item.Credentials) == 0 {
+		return env, nil
+	}
+
+	path, err := writeNetrc(item.Credentials, getenv)
+	if err != nil {
+		return nil, err
+	}
+	env.netrc = path
+	env.vars["NETRC"] = path
+
+	// Point git at the same credentials through GIT_CONFIG_COUNT so module
+	// fetches over git (GOPRIVATE hosts) authenticate too. Entries configured by
+	// the caller are kept.
+	offset, _ := strconv.Atoi(getenv("GIT_CONFIG_COUNT"))
+	for i, cred := range item.Credentials {
+		n := strconv.Itoa(offset + i)
+		env.vars["GIT_CONFIG_KEY_"+n] = "credential.https://" + strings.TrimSpace(cred.Machine) + ".helper"
+		env.vars["GIT_CONFIG_VALUE_"+n] = credentialHelper(cred)
+	}
+	env.vars["GIT_CONFIG_COUNT"] = strconv.Itoa(offset + len(item.Credentials))
+
+	return env, nil
+}
+
+// renderEnv returns a copy of item.Env in which values holding a template, such
+// as "{{ .SourceVersion }}", are rendered with the work item.
+func renderEnv(item planner.WorkItem) (map[string]string, error) {
+	vars := make(map[string]string, len(item.Env)+2)
+	for key, value := range item.Env {
+		if !strings.Contains(value, "{{") {
+			vars[key] = value
+			continue
+		}
+		tmpl, err := template.New(key).Option("missingkey=error").Parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("env %s: %w", key, err)
+		}
+		var b strings.Builder
+		if err := tmpl.Execute(&b, item); err != nil {
+			return nil, fmt.Errorf("env %s: %w", key, err)
+		}
+		vars[key] = b.String()
+	}
+	return vars, nil
+}
+
+// cleanup removes the item's netrc file.
+func (e *goEnv) cleanup() {
+	if e != nil && e.netrc != "" {
+		os.Remove(e.netrc)
+	}
+}
+
+// writeNetrc writes a netrc file holding the user's own netrc entries followed by
+// credentials. The file is only readable by the current user.
+func writeNetrc(credentials []manifest.Credential, getenv func(string) string) (string, error) {
+	var b strings.Builder
+	if existing := userNetrc(getenv); existing != "" {
+		b.WriteString(strings.TrimRight(existing, "\n"))
+		b.WriteString("\n\n")
+	}
+	for _, cred := range credentials {
+		password := getenv(cred.PasswordEnv)
+		if password == "" {
+			return "", fmt.Errorf("credential for %s: environment variable %s is not set", cred.Machine, cred.PasswordEnv)
+		}
+		fmt.Fprintf(&b, "machine %s\nlogin %s\npassword %s\n", strings.TrimSpace(cred.Machine), cred.Login, password)
+	}
+
+	f, err := os.CreateTemp("", "cascade-netrc-*")
+	if err != nil {
+		return "", fmt.Errorf("create netrc: %w", err)
+	}
+	if _, err := f.WriteString(b.String()); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return "", fmt.Errorf("write netrc: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("write netrc: %w", err)
+	}
+	return f.Name(), nil
+}
+
+// userNetrc returns the contents of the netrc file the go command would read
+// without an override, or "" when there is none.
+func userNetrc(getenv func(string) string) string {
+	path := getenv("NETRC")
+	if path == "" {
+		home := getenv("HOME")
+		if home == "" {
+			return ""
+		}
+		path = filepath.Join(home, ".netrc")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// credentialHelper returns a git credential helper that answers with the login
+// and reads the password from the credential's environment variable when git
+// runs it, so the secret never appears in git configuration.
+func credentialHelper(cred manifest.Credential) string {
+	login := "'" + strings.ReplaceAll(cred.Login, "'", `'\''`) + "'"
+	return fmt.Sprintf(`!f() { test "$1" = get && printf 'username=%%s\npassword=%%s\n' %s "$%s"; }; f`, login, cred.PasswordEnv)
+}
+
+// goWorkEnv returns the environment of the go get and go mod tidy of an item
+// with go.work mode mode. For manifest.GoWorkOff it is a copy of env with
+// GOWORK=off, so a go.work file in the worktree or one of its parents cannot
+// resolve modules from local directories. Other modes return env unchanged.
+func goWorkEnv(env map[string]string, mode string) map[string]string {
+	if mode != manifest.GoWorkOff {
+		return env
+	}
+	vars := make(map[string]string, len(env)+1)
+	for key, value := range env {
+		vars[key] = value
+	}
+	vars["GOWORK"] = "off"
+	return vars
+}
+
+// GoFlagsOperations is implemented by GoOperations that can pass flags to go
+// get. Bump strategies such as latest-compatible need it.
+type GoFlagsOperations interface {
+	GetWithFlags(ctx context.Context, repoPath, module, version string, flags []string, env map[string]string) error
+}
+
+// goGetQuery runs go get for a bump strategy's query. Queries with flags need
+// ops to implement GoFlagsOperations.
+func goGetQuery(ctx context.Context, ops GoOperations, repoPath, module string, query manifest.GoGetQuery, env map[string]string) error {
+	if len(query.Flags) == 0 {
+		return goGet(ctx, ops, repoPath, module, query.Version, env)
+	}
+	flagOps, ok := ops.(GoFlagsOperations)
+	if !ok {
+		return &GoOperationError{Module: module, Version: query.Version, Err: fmt.Errorf("go get flags %v are not supported by the configured go operations", query.Flags)}
+	}
+	return flagOps.GetWithFlags(ctx, repoPath, module, query.Version, query.Flags, env)
+}
+
+// goGet runs go get with env when ops supports it.
+func goGet(ctx context.Context, ops GoOperations, repoPath, module, version string, env map[string]string) error {
+	if envOps, ok := ops.(GoEnvOperations); ok && len(env) > 0 {
+		return envOps.GetWithEnv(ctx, repoPath, module, version, env)
+	}
+	return ops.Get(ctx, repoPath, module, version)
+}
+
+// goTidy runs go mod tidy with env when ops supports it.
+func goTidy(ctx context.Context, ops GoOperations, repoPath string, env map[string]string) error {
+	if envOps, ok := ops.(GoEnvOperations); ok && len(env) > 0 {
+		return envOps.TidyWithEnv(ctx, repoPath, env)
+	}
+	return ops.Tidy(ctx, repoPath)
+}
@@ -0,0 +1,227 @@
+package executor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/goliatone/cascade/internal/manifest"
+	"github.com/goliatone/cascade/internal/planner"
+)
+
+func envLookup(values map[string]string) func(string) string {
+	return func(key string) string { return values[key] }
+}
+
+func TestNewGoEnv_GoFlags(t *testing.T) {
+	item := planner.WorkItem{GoFlags: "-tags=integration", Env: map[string]string{"GOPRIVATE": "git.example.com"}}
+
+	env, err := newGoEnv(item, envLookup(map[string]string{"GOFLAGS": "-mod=mod"}))
+	if err != nil {
+		t.Fatalf("newGoEnv: %v", err)
+	}
+	defer env.cleanup()
+	if got := env.vars["GOFLAGS"]; got != "-mod=mod -tags=integration" {
+		t.Errorf("GOFLAGS = %q, want the process flags followed by the dependent's", got)
+	}
+	if env.vars["GOPRIVATE"] != "git.example.com" {
+		t.Errorf("expected the item env to be kept, got %v", env.vars)
+	}
+
+	item.Env["GOFLAGS"] = "-mod=vendor"
+	env, _ = newGoEnv(item, envLookup(map[string]string{"GOFLAGS": "-mod=mod"}))
+	if got := env.vars["GOFLAGS"]; got != "-mod=vendor -tags=integration" {
+		t.Errorf("GOFLAGS = %q, want the item's GOFLAGS to replace the process value", got)
+	}
+
+	env, _ = newGoEnv(planner.WorkItem{}, envLookup(nil))
+	if len(env.vars) != 0 || env.netrc != "" {
+		t.Errorf("expected an empty environment, got %+v", env)
+	}
+}
+
+func TestNewGoEnv_RendersTemplates(t *testing.T) {
+	item := planner.WorkItem{
+		Repo:          "org/app",
+		SourceModule:  "example.com/lib",
+		SourceVersion: "v1.2.0",
+		Env: map[string]string{
+			"MODULE_VERSION": "{{ .SourceVersion }}",
+			"UPDATE":         "{{ .Repo }} -> {{ .SourceModule }}@{{ .SourceVersion }}",
+			"LITERAL":        "a}}b",
+		},
+	}
+
+	env, err := newGoEnv(item, envLookup(nil))
+	if err != nil {
+		t.Fatalf("newGoEnv: %v", err)
+	}
+	want := map[string]string{"MODULE_VERSION": "v1.2.0", "UPDATE": "org/app -> example.com/lib@v1.2.0", "LITERAL": "a}}b"}
+	for k, v := range want {
+		if env.vars[k] != v {
+			t.Errorf("%s = %q, want %q", k, env.vars[k], v)
+		}
+	}
+	if item.Env["MODULE_VERSION"] != "{{ .SourceVersion }}" {
+		t.Error("newGoEnv modified the item env")
+	}
+
+	for _, value := range []string{"{{ .SourceVersion", "{{ .Unknown }}"} {
+		item.Env = map[string]string{"BROKEN": value}
+		if _, err := newGoEnv(item, envLookup(nil)); err == nil || !strings.Contains(err.Error(), "env BROKEN") {
+			t.Errorf("newGoEnv(%q) error = %v, want a template error", value, err)
+		}
+	}
+}
+
+func TestNewGoEnv_Credentials(t *testing.T) {
+	home := t.TempDir()
+	if err := os.WriteFile(filepath.Join(home, ".netrc"), []byte("machine github.com\nlogin me\npassword own\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	item := planner.WorkItem{Credentials: []manifest.Credential{
+		{Machine: "git.example.com", Login: "ci", PasswordEnv: "EXAMPLE_TOKEN"},
+		{Machine: "proxy.example.com", Login: "o'brien", PasswordEnv: "PROXY_TOKEN"},
+	}}
+	getenv := envLookup(map[string]string{
+		"HOME":             home,
+		"EXAMPLE_TOKEN":    "s3cret",
+		"PROXY_TOKEN":      "p4ss",
+		"GIT_CONFIG_COUNT": "1",
+	})
+
+	env, err := newGoEnv(item, getenv)
+	if err != nil {
+		t.Fatalf("newGoEnv: %v", err)
+	}
+
+	path := env.vars["NETRC"]
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("netrc not written: %v", err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("netrc mode = %v, want 0600", info.Mode().Perm())
+	}
+	data, _ := os.ReadFile(path)
+	for _, want := range []string{"machine github.com\nlogin me\npassword own", "machine git.example.com\nlogin ci\npassword s3cret", "machine proxy.example.com\nlogin o'brien\npassword p4ss"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("netrc missing %q:\n%s", want, data)
+		}
+	}
+
+	if env.vars["GIT_CONFIG_COUNT"] != "3" {
+		t.Errorf("GIT_CONFIG_COUNT = %q, want existing entries kept", env.vars["GIT_CONFIG_COUNT"])
+	}
+	if env.vars["GIT_CONFIG_KEY_1"] != "credential.https://git.example.com.helper" {
+		t.Errorf("GIT_CONFIG_KEY_1 = %q", env.vars["GIT_CONFIG_KEY_1"])
+	}
+	if helper := env.vars["GIT_CONFIG_VALUE_2"]; !strings.Contains(helper, `'o'\''brien'`) || !strings.Contains(helper, `"$PROXY_TOKEN"`) || strings.Contains(helper, "p4ss") {
+		t.Errorf("unexpected credential helper %q", helper)
+	}
+
+	env.cleanup()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected netrc to be removed, got %v", err)
+	}
+}
+
+func TestNewGoEnv_MissingPassword(t *testing.T) {
+	item := planner.WorkItem{Credentials: []manifest.Credential{{Machine: "git.example.com", Login: "ci", PasswordEnv: "EXAMPLE_TOKEN"}}}
+	_, err := newGoEnv(item, envLookup(nil))
+	if err == nil || !strings.Contains(err.Error(), "EXAMPLE_TOKEN is not set") {
+		t.Fatalf("expected missing password error, got %v", err)
+	}
+}
+
+type envGoOperations struct {
+	getEnv  map[string]string
+	tidyEnv map[string]string
+	plain   int
+}
+
+func (g *envGoOperations) Get(ctx context.Context, repoPath, module, version string) error {
+	g.plain++
+	return nil
+}
+
+func (g *envGoOperations) Tidy(ctx context.Context, repoPath string) error {
+	g.plain++
+	return nil
+}
+
+func (g *envGoOperations) GetWithEnv(ctx context.Context, repoPath, module, version string, env map[string]string) error {
+	g.getEnv = env
+	return nil
+}
+
+func (g *envGoOperations) TidyWithEnv(ctx context.Context, repoPath string, env map[string]string) error {
+	g.tidyEnv = env
+	return nil
+}
+
+func TestGoGetAndTidy_UseEnvWhenSupported(t *testing.T) {
+	ops := &envGoOperations{}
+	env := map[string]string{"GOFLAGS": "-mod=mod"}
+
+	goGet(context.Background(), ops, "/repo", "example.com/lib", "v1.0.0", env)
+	goTidy(context.Background(), ops, "/repo", env)
+	if ops.getEnv["GOFLAGS"] != "-mod=mod" || ops.tidyEnv["GOFLAGS"] != "-mod=mod" || ops.plain != 0 {
+		t.Fatalf("expected env-aware calls, got %+v", ops)
+	}
+
+	goGet(context.Background(), ops, "/repo", "example.com/lib", "v1.0.0", nil)
+	goTidy(context.Background(), ops, "/repo", nil)
+	if ops.plain != 2 {
+		t.Fatalf("expected plain calls without env, got %d", ops.plain)
+	}
+}
+
+// flagGoOperations records the flags passed to go get.
+type flagGoOperations struct {
+	envGoOperations
+	flags []string
+}
+
+func (g *flagGoOperations) GetWithFlags(ctx context.Context, repoPath, module, version string, flags []string, env map[string]string) error {
+	g.flags = flags
+	return nil
+}
+
+func TestGoGetQuery_PassesFlags(t *testing.T) {
+	query := manifest.GoGetQuery{Version: "v1.0.0", Flags: []string{"-u"}}
+
+	ops := &flagGoOperations{}
+	if err := goGetQuery(context.Background(), ops, "/repo", "example.com/lib", query, nil); err != nil {
+		t.Fatalf("goGetQuery() error = %v", err)
+	}
+	if !reflect.DeepEqual(ops.flags, []string{"-u"}) {
+		t.Errorf("flags = %v, want [-u]", ops.flags)
+	}
+
+	if err := goGetQuery(context.Background(), &envGoOperations{}, "/repo", "example.com/lib", query, nil); err == nil {
+		t.Error("expected go operations without flag support to fail")
+	}
+}
+
+func TestGoWorkEnv(t *testing.T) {
+	env := map[string]string{"GOFLAGS": "-mod=mod"}
+
+	off := goWorkEnv(env, manifest.GoWorkOff)
+	if off["GOWORK"] != "off" || off["GOFLAGS"] != "-mod=mod" {
+		t.Fatalf("expected GOWORK=off added to the item env, got %v", off)
+	}
+	if _, ok := env["GOWORK"]; ok {
+		t.Fatal("expected the item env, which tests also use, to stay unchanged")
+	}
+
+	for _, mode := range []string{"", manifest.GoWorkRespect} {
+		if got := goWorkEnv(env, mode); !reflect.DeepEqual(got, env) {
+			t.Errorf("mode %q: expected env unchanged, got %v", mode, got)
+		}
+	}
+}
@@ -18,17 +18,31 @@ func NewGoOperations() GoOperations {
 
 // Get updates a module to the specified version using go get.
 func (g *goOperations) Get(ctx context.Context, repoPath, module, version string) error {
+	return g.GetWithEnv(ctx, repoPath, module, version, nil)
+}
+
+// GetWithEnv runs go get with env added to the process environment.
+func (g *goOperations) GetWithEnv(ctx context.Context, repoPath, module, version string, env map[string]string) error {
+	return g.GetWithFlags(ctx, repoPath, module, version, nil, env)
+}
+
+// GetWithFlags runs go get with flags before the module and env added to the
+// process environment.
+func (g *goOperations) GetWithFlags(ctx context.Context, repoPath, module, version string, flags []string, env map[string]string) error {
 	// Construct go get command with module@version format
-	var args []string
+	args := append([]string{"get"}, flags...)
 	if version == "" || version == "latest" {
-		args = []string{"get", module}
+		args = append(args, module)
 	} else {
-		args = []string{"get", fmt.Sprintf("%s@%s", module, version)}
+		args = append(args, fmt.Sprintf("%s@%s", module, version))
 	}
 
 	// Execute go get command
 	cmd := exec.CommandContext(ctx, "go", args...)
 	cmd.Dir = repoPath
+	if len(env) > 0 {
+		cmd.Env = prepareEnv(env)
+	}
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
@@ -50,9 +64,17 @@ func (g *goOperations) Get(ctx context.Context, repoPath, module, version string
 
 // Tidy runs go mod tidy to clean up the module dependencies.
 func (g *goOperations) Tidy(ctx context.Context, repoPath string) error {
+	return g.TidyWithEnv(ctx, repoPath, nil)
+}
+
+// TidyWithEnv runs go mod tidy with env added to the process environment.
+func (g *goOperations) TidyWithEnv(ctx context.Context, repoPath string, env map[string]string) error {
 	// Execute go mod tidy command
 	cmd := exec.CommandContext(ctx, "go", "mod", "tidy")
 	cmd.Dir = repoPath
+	if len(env) > 0 {
+		cmd.Env = prepareEnv(env)
+	}
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
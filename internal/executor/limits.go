@@ -0,0 +1,99 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os/exec"
+	"strconv"
+	"syscall"
+)
+
+// LimitBackend selects how ResourceLimits are enforced.
+type LimitBackend string
+
+const (
+	// LimitBackendEnv exports GOMAXPROCS and GOMEMLIMIT to each command. These are
+	// soft limits: Go programs (the go tool and test binaries) honour them, other
+	// processes are unaffected.
+	LimitBackendEnv LimitBackend = "env"
+
+	// LimitBackendCgroup runs each command in a transient systemd scope with
+	// MemoryMax and CPUQuota set, so the kernel enforces the limits. It requires
+	// systemd-run and a user service manager.
+	LimitBackendCgroup LimitBackend = "cgroup"
+)
+
+// ResourceLimits constrains the CPU and memory of commands run for a work item.
+type ResourceLimits struct {
+	CPU         float64
+	MemoryBytes int64
+	Backend     LimitBackend
+}
+
+// IsZero reports whether no limit is configured.
+func (l ResourceLimits) IsZero() bool {
+	return l.CPU <= 0 && l.MemoryBytes <= 0
+}
+
+// String describes the limits for logs and error messages.
+func (l ResourceLimits) String() string {
+	switch {
+	case l.CPU > 0 && l.MemoryBytes > 0:
+		return fmt.Sprintf("cpu=%s memory=%d bytes", formatCPU(l.CPU), l.MemoryBytes)
+	case l.CPU > 0:
+		return fmt.Sprintf("cpu=%s", formatCPU(l.CPU))
+	case l.MemoryBytes > 0:
+		return fmt.Sprintf("memory=%d bytes", l.MemoryBytes)
+	default:
+		return "unlimited"
+	}
+}
+
+// apply returns the argv and environment a command should run with under the
+// limits. Environment variables set explicitly for the command take precedence.
+func (l ResourceLimits) apply(argv []string, env map[string]string) ([]string, map[string]string) {
+	if l.IsZero() {
+		return argv, env
+	}
+
+	limited := make(map[string]string, len(env)+2)
+	if l.CPU > 0 {
+		limited["GOMAXPROCS"] = strconv.Itoa(int(math.Max(1, math.Ceil(l.CPU))))
+	}
+	if l.MemoryBytes > 0 {
+		limited["GOMEMLIMIT"] = strconv.FormatInt(l.MemoryBytes, 10)
+	}
+	for k, v := range env {
+		limited[k] = v
+	}
+
+	if l.Backend != LimitBackendCgroup {
+		return argv, limited
+	}
+
+	wrapped := []string{"systemd-run", "--user", "--scope", "--quiet"}
+	if l.MemoryBytes > 0 {
+		wrapped = append(wrapped, "-p", "MemoryMax="+strconv.FormatInt(l.MemoryBytes, 10), "-p", "MemorySwapMax=0")
+	}
+	if l.CPU > 0 {
+		wrapped = append(wrapped, "-p", fmt.Sprintf("CPUQuota=%d%%", int(math.Round(l.CPU*100))))
+	}
+	wrapped = append(wrapped, "--")
+	return append(wrapped, argv...), limited
+}
+
+// killedBySignal reports whether err is an exit caused by SIGKILL, which is how
+// the kernel terminates a process that exceeds its cgroup memory limit.
+func killedBySignal(err error) bool {
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ProcessState == nil {
+		return false
+	}
+	status, ok := exitErr.ProcessState.Sys().(syscall.WaitStatus)
+	return ok && status.Signaled() && status.Signal() == syscall.SIGKILL
+}
+
+func formatCPU(cpu float64) string {
+	return strconv.FormatFloat(cpu, 'f', -1, 64)
+}
@@ -0,0 +1,111 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goliatone/cascade/internal/manifest"
+)
+
+func TestResourceLimits_Apply(t *testing.T) {
+	argv := []string{"go", "test", "./..."}
+
+	t.Run("zero limits leave the command untouched", func(t *testing.T) {
+		env := map[string]string{"FOO": "bar"}
+		gotArgv, gotEnv := ResourceLimits{}.apply(argv, env)
+		if !reflect.DeepEqual(gotArgv, argv) || !reflect.DeepEqual(gotEnv, env) {
+			t.Fatalf("expected unchanged command, got %v %v", gotArgv, gotEnv)
+		}
+	})
+
+	t.Run("env backend exports go runtime limits", func(t *testing.T) {
+		limits := ResourceLimits{CPU: 1.5, MemoryBytes: 4 << 30}
+		gotArgv, gotEnv := limits.apply(argv, map[string]string{"FOO": "bar"})
+		if !reflect.DeepEqual(gotArgv, argv) {
+			t.Fatalf("env backend should not wrap the command, got %v", gotArgv)
+		}
+		want := map[string]string{"FOO": "bar", "GOMAXPROCS": "2", "GOMEMLIMIT": "4294967296"}
+		if !reflect.DeepEqual(gotEnv, want) {
+			t.Fatalf("env = %v, want %v", gotEnv, want)
+		}
+	})
+
+	t.Run("explicit command env takes precedence", func(t *testing.T) {
+		_, gotEnv := ResourceLimits{CPU: 4}.apply(argv, map[string]string{"GOMAXPROCS": "1"})
+		if gotEnv["GOMAXPROCS"] != "1" {
+			t.Fatalf("expected command env to win, got GOMAXPROCS=%s", gotEnv["GOMAXPROCS"])
+		}
+	})
+
+	t.Run("cgroup backend wraps the command in a systemd scope", func(t *testing.T) {
+		limits := ResourceLimits{CPU: 2, MemoryBytes: 512 << 20, Backend: LimitBackendCgroup}
+		gotArgv, gotEnv := limits.apply(argv, nil)
+		want := []string{
+			"systemd-run", "--user", "--scope", "--quiet",
+			"-p", "MemoryMax=536870912", "-p", "MemorySwapMax=0",
+			"-p", "CPUQuota=200%",
+			"--", "go", "test", "./...",
+		}
+		if !reflect.DeepEqual(gotArgv, want) {
+			t.Fatalf("argv = %v, want %v", gotArgv, want)
+		}
+		if gotEnv["GOMAXPROCS"] != "2" || gotEnv["GOMEMLIMIT"] != "536870912" {
+			t.Fatalf("expected go runtime limits alongside the cgroup, got %v", gotEnv)
+		}
+	})
+}
+
+func TestResourceLimits_String(t *testing.T) {
+	tests := map[string]ResourceLimits{
+		"unlimited":                     {},
+		"cpu=0.5":                       {CPU: 0.5},
+		"memory=1024 bytes":             {MemoryBytes: 1024},
+		"cpu=2 memory=1073741824 bytes": {CPU: 2, MemoryBytes: 1 << 30},
+	}
+	for want, limits := range tests {
+		if got := limits.String(); got != want {
+			t.Errorf("String() = %q, want %q", got, want)
+		}
+	}
+}
+
+func TestCommandRunner_WithResourceLimits(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell")
+	}
+
+	runner := NewCommandRunner(WithResourceLimits(ResourceLimits{CPU: 2, MemoryBytes: 1 << 30}))
+	result, err := runner.Run(context.Background(), t.TempDir(), manifest.Command{
+		Cmd: []string{"sh", "-c", "echo $GOMAXPROCS $GOMEMLIMIT"},
+	}, nil, 10*time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := strings.TrimSpace(result.Output); got != "2 1073741824" {
+		t.Fatalf("output = %q, want limits exported to the command", got)
+	}
+}
+
+func TestCommandRunner_ReportsKillUnderLimits(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses POSIX signals")
+	}
+
+	runner := NewCommandRunner(WithResourceLimits(ResourceLimits{MemoryBytes: 1 << 20}))
+	_, err := runner.Run(context.Background(), t.TempDir(), manifest.Command{
+		Cmd: []string{"sh", "-c", "kill -9 $$"},
+	}, nil, 10*time.Second)
+
+	var cmdErr *CommandExecutionError
+	if !errors.As(err, &cmdErr) {
+		t.Fatalf("expected CommandExecutionError, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "killed while running under resource limits (memory=1048576 bytes)") {
+		t.Fatalf("expected limit hint in error, got %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+package executor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/goliatone/cascade/internal/planner"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/semver"
+)
+
+// localTargetEnv returns a copy of env in which go commands build the module
+// in moduleDir against the checkout of the target module in targetDir, rather
+// than the version go.mod requires. GOWORK points at a go.work that uses both
+// directories, written to a temporary directory outside the worktree, so
+// nothing the executor commits mentions the checkout. The checkout must hold
+// one of the modules item updates. Call cleanup once the commands finished.
+func localTargetEnv(item planner.WorkItem, moduleDir, targetDir string, env map[string]string, getenv func(string) string) (map[string]string, func(), error) {
+	target, err := readGoMod(targetDir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("local target %s: %w", targetDir, err)
+	}
+	if !updatesModule(item, target.Module.Mod.Path) {
+		return nil, nil, fmt.Errorf("local target %s holds %s, which %s does not update to", targetDir, target.Module.Mod.Path, item.Repo)
+	}
+	dependent, err := readGoMod(moduleDir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("dependent module: %w", err)
+	}
+
+	work, err := modfile.ParseWork("go.work", nil, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	// The go command needs the go version of go.work to be at least that of
+	// every module it uses
+	goVersion := ""
+	for _, mod := range []*modfile.File{dependent, target} {
+		if mod.Go != nil && (goVersion == "" || semver.Compare("v"+mod.Go.Version, "v"+goVersion) > 0) {
+			goVersion = mod.Go.Version
+		}
+	}
+	if goVersion != "" {
+		if err := work.AddGoStmt(goVersion); err != nil {
+			return nil, nil, err
+		}
+	}
+	for _, dir := range []string{moduleDir, targetDir} {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := work.AddUse(abs, ""); err != nil {
+			return nil, nil, err
+		}
+	}
+	work.Cleanup()
+
+	dir, err := os.MkdirTemp("", "cascade-gowork-*")
+	if err != nil {
+		return nil, nil, fmt.Errorf("create go.work: %w", err)
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+	path := filepath.Join(dir, "go.work")
+	if err := os.WriteFile(path, modfile.Format(work.Syntax), 0o644); err != nil {
+		cleanup()
+		return nil, nil, fmt.Errorf("write go.work: %w", err)
+	}
+
+	vars := make(map[string]string, len(env)+2)
+	for key, value := range env {
+		vars[key] = value
+	}
+	vars["GOWORK"] = path
+	goflags, ok := env["GOFLAGS"]
+	if !ok {
+		goflags = getenv("GOFLAGS")
+	}
+	vars["GOFLAGS"] = WorkspaceGoFlags(goflags)
+	return vars, cleanup, nil
+}
+
+// readGoMod parses the go.mod in dir, which must declare a module path.
+func readGoMod(dir string) (*modfile.File, error) {
+	path := filepath.Join(dir, "go.mod")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read go.mod: %w", err)
+	}
+	mod, err := modfile.ParseLax(path, data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("parse go.mod: %w", err)
+	}
+	if mod.Module == nil {
+		return nil, fmt.Errorf("%s declares no module", path)
+	}
+	return mod, nil
+}
+
+func updatesModule(item planner.WorkItem, module string) bool {
+	for _, update := range item.ModuleUpdates() {
+		if update.Module == module {
+			return true
+		}
+	}
+	return false
+}
+
+// WorkspaceGoFlags drops -mod=mod from goflags, which the go command rejects in
+// workspace mode.
+func WorkspaceGoFlags(goflags string) string {
+	var kept []string
+	for _, flag := range strings.Fields(goflags) {
+		if flag != "-mod=mod" && flag != "--mod=mod" {
+			kept = append(kept, flag)
+		}
+	}
+	return strings.Join(kept, " ")
+}
@@ -0,0 +1,84 @@
+package executor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/goliatone/cascade/internal/planner"
+)
+
+func writeGoMod(t *testing.T, dir, content string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLocalTargetEnv(t *testing.T) {
+	root := t.TempDir()
+	moduleDir := filepath.Join(root, "app")
+	targetDir := filepath.Join(root, "lib")
+	writeGoMod(t, moduleDir, "module example.com/app\n\ngo 1.22\n\nrequire example.com/lib v1.2.0\n")
+	writeGoMod(t, targetDir, "module example.com/lib\n\ngo 1.23.1\n")
+	item := planner.WorkItem{Repo: "example/app", SourceModule: "example.com/lib", SourceVersion: "v1.2.0"}
+
+	env, cleanup, err := localTargetEnv(item, moduleDir, targetDir, map[string]string{"FOO": "bar"}, envLookup(map[string]string{"GOFLAGS": "-mod=mod -tags=integration"}))
+	if err != nil {
+		t.Fatalf("localTargetEnv: %v", err)
+	}
+	if env["FOO"] != "bar" || env["GOFLAGS"] != "-tags=integration" {
+		t.Errorf("env = %v, want FOO kept and -mod=mod dropped from GOFLAGS", env)
+	}
+
+	goWork := env["GOWORK"]
+	if strings.HasPrefix(goWork, moduleDir) {
+		t.Errorf("GOWORK = %s, want it outside the worktree", goWork)
+	}
+	data, err := os.ReadFile(goWork)
+	if err != nil {
+		t.Fatalf("read go.work: %v", err)
+	}
+	for _, want := range []string{"go 1.23.1", moduleDir, targetDir} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("go.work missing %q:\n%s", want, data)
+		}
+	}
+
+	cleanup()
+	if _, err := os.Stat(goWork); !os.IsNotExist(err) {
+		t.Errorf("go.work still exists after cleanup: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(moduleDir, "go.work")); !os.IsNotExist(err) {
+		t.Errorf("go.work written into the worktree")
+	}
+}
+
+func TestLocalTargetEnv_Errors(t *testing.T) {
+	root := t.TempDir()
+	moduleDir := filepath.Join(root, "app")
+	writeGoMod(t, moduleDir, "module example.com/app\n\ngo 1.22\n")
+	writeGoMod(t, filepath.Join(root, "other"), "module example.com/other\n\ngo 1.22\n")
+	item := planner.WorkItem{Repo: "example/app", SourceModule: "example.com/lib", SourceVersion: "v1.2.0"}
+
+	tests := []struct {
+		name      string
+		targetDir string
+		want      string
+	}{
+		{name: "other module", targetDir: filepath.Join(root, "other"), want: "holds example.com/other, which example/app does not update to"},
+		{name: "no go.mod", targetDir: filepath.Join(root, "missing"), want: "read go.mod"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := localTargetEnv(item, moduleDir, tt.targetDir, nil, envLookup(nil))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("localTargetEnv() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package executor
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// hashModuleFiles returns a SHA-256 digest of the go.mod and go.sum files in
+// moduleDir. A missing go.sum hashes differently from an empty one, so creating
+// it counts as a change.
+func hashModuleFiles(moduleDir string) (string, error) {
+	h := sha256.New()
+	for _, name := range []string{"go.mod", "go.sum"} {
+		data, err := os.ReadFile(filepath.Join(moduleDir, name))
+		switch {
+		case errors.Is(err, fs.ErrNotExist) && name == "go.sum":
+			fmt.Fprintf(h, "%s:missing\n", name)
+			continue
+		case err != nil:
+			return "", fmt.Errorf("read %s: %w", name, err)
+		}
+		fmt.Fprintf(h, "%s:%d\n", name, len(data))
+		h.Write(data)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,310 @@
+package executor_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/internal/manifest"
+	"github.com/goliatone/cascade/internal/planner"
+	"github.com/goliatone/cascade/pkg/repourl"
+)
+
+func optionsTestInput(git executor.GitOperations, goOps executor.GoOperations, runner executor.CommandRunner) executor.WorkItemContext {
+	return executor.WorkItemContext{
+		Item: planner.WorkItem{
+			Repo:          "example/app",
+			SourceModule:  "github.com/example/lib",
+			SourceVersion: "v1.2.3",
+			BranchName:    "cascade/lib-v1.2.3",
+			CommitMessage: "bump lib",
+			Tests:         []manifest.Command{{Cmd: []string{"go", "test", "./..."}}},
+		},
+		Workspace: "/workspace",
+		Git:       git,
+		Go:        goOps,
+		Runner:    runner,
+		Logger:    &mockLogger{},
+	}
+}
+
+func TestExecutor_WithDefaultTimeout(t *testing.T) {
+	git := &mockGitOperations{clonePath: "/workspace/app", workPath: "/workspace/app-wt", commitHash: "abc"}
+
+	runner := &recordingCommandRunner{}
+	if _, err := executor.New().Apply(context.Background(), optionsTestInput(git, &mockGoOperations{}, runner)); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if runner.calls[0].timeout != 5*time.Minute {
+		t.Fatalf("default timeout = %s, want 5m0s", runner.calls[0].timeout)
+	}
+
+	runner = &recordingCommandRunner{}
+	exec := executor.New(executor.WithDefaultTimeout(90 * time.Second))
+	if _, err := exec.Apply(context.Background(), optionsTestInput(git, &mockGoOperations{}, runner)); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if runner.calls[0].timeout != 90*time.Second {
+		t.Fatalf("configured timeout = %s, want 1m30s", runner.calls[0].timeout)
+	}
+
+	runner = &recordingCommandRunner{}
+	input := optionsTestInput(git, &mockGoOperations{}, runner)
+	input.Item.Timeout = time.Minute
+	if _, err := exec.Apply(context.Background(), input); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if runner.calls[0].timeout != time.Minute {
+		t.Fatalf("item timeout = %s, want the item's own 1m0s", runner.calls[0].timeout)
+	}
+}
+
+// blockingRunner holds every command until release is closed and tracks how many
+// run at the same time.
+type blockingRunner struct {
+	release chan struct{}
+	started chan struct{}
+
+	mu      sync.Mutex
+	active  int
+	maxSeen int
+}
+
+func (r *blockingRunner) Run(ctx context.Context, repoPath string, cmd manifest.Command, env map[string]string, timeout time.Duration) (executor.CommandResult, error) {
+	r.mu.Lock()
+	r.active++
+	r.maxSeen = max(r.maxSeen, r.active)
+	r.mu.Unlock()
+	r.started <- struct{}{}
+
+	<-r.release
+
+	r.mu.Lock()
+	r.active--
+	r.mu.Unlock()
+	return executor.CommandResult{Command: cmd}, nil
+}
+
+func TestExecutor_WithConcurrentLimit(t *testing.T) {
+	git := &mockGitOperations{clonePath: "/workspace/app", workPath: "/workspace/app-wt", commitHash: "abc"}
+	runner := &blockingRunner{release: make(chan struct{}), started: make(chan struct{}, 4)}
+	exec := executor.New(executor.WithConcurrentLimit(2))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := exec.Apply(context.Background(), optionsTestInput(git, &mockGoOperations{}, runner)); err != nil {
+				t.Errorf("apply: %v", err)
+			}
+		}()
+	}
+
+	<-runner.started
+	<-runner.started
+	select {
+	case <-runner.started:
+		t.Fatal("a third work item started while the limit was 2")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(runner.release)
+	wg.Wait()
+
+	if runner.maxSeen != 2 {
+		t.Fatalf("max concurrent work items = %d, want 2", runner.maxSeen)
+	}
+
+	t.Run("waiting respects context", func(t *testing.T) {
+		held := &blockingRunner{release: make(chan struct{}), started: make(chan struct{}, 1)}
+		exec := executor.New(executor.WithConcurrentLimit(1))
+		go exec.Apply(context.Background(), optionsTestInput(git, &mockGoOperations{}, held))
+		<-held.started
+		defer close(held.release)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		result, err := exec.Apply(ctx, optionsTestInput(git, &mockGoOperations{}, &mockCommandRunner{}))
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected deadline exceeded while waiting for a slot, got %v", err)
+		}
+		if result == nil || result.Status != executor.StatusFailed {
+			t.Fatalf("expected failed result, got %#v", result)
+		}
+	})
+}
+
+// flakyGoOperations fails Get the given number of times before succeeding.
+type flakyGoOperations struct {
+	failures int32
+	calls    atomic.Int32
+}
+
+func (f *flakyGoOperations) Get(ctx context.Context, repoPath, module, version string) error {
+	if f.calls.Add(1) <= f.failures {
+		return fmt.Errorf("proxy unavailable")
+	}
+	return nil
+}
+
+func (f *flakyGoOperations) Tidy(ctx context.Context, repoPath string) error {
+	return nil
+}
+
+func TestExecutor_WithRetry(t *testing.T) {
+	git := &mockGitOperations{clonePath: "/workspace/app", workPath: "/workspace/app-wt", commitHash: "abc"}
+
+	t.Run("no retries by default", func(t *testing.T) {
+		goOps := &flakyGoOperations{failures: 1}
+		result, err := executor.New().Apply(context.Background(), optionsTestInput(git, goOps, &mockCommandRunner{}))
+		if err == nil || result.Status != executor.StatusFailed {
+			t.Fatalf("expected failure without retries, got %v (%s)", err, result.Status)
+		}
+		if goOps.calls.Load() != 1 {
+			t.Fatalf("go get called %d times, want 1", goOps.calls.Load())
+		}
+	})
+
+	t.Run("recovers from transient failures", func(t *testing.T) {
+		goOps := &flakyGoOperations{failures: 2}
+		exec := executor.New(executor.WithRetry(2, time.Millisecond))
+		result, err := exec.Apply(context.Background(), optionsTestInput(git, goOps, &mockCommandRunner{}))
+		if err != nil {
+			t.Fatalf("apply: %v", err)
+		}
+		if result.Status != executor.StatusCompleted {
+			t.Fatalf("status = %s, want completed", result.Status)
+		}
+		if goOps.calls.Load() != 3 {
+			t.Fatalf("go get called %d times, want 3", goOps.calls.Load())
+		}
+	})
+
+	t.Run("gives up after the configured retries", func(t *testing.T) {
+		goOps := &flakyGoOperations{failures: 5}
+		exec := executor.New(executor.WithRetry(2, time.Millisecond))
+		if _, err := exec.Apply(context.Background(), optionsTestInput(git, goOps, &mockCommandRunner{})); err == nil {
+			t.Fatal("expected failure after exhausting retries")
+		}
+		if goOps.calls.Load() != 3 {
+			t.Fatalf("go get called %d times, want 3", goOps.calls.Load())
+		}
+	})
+
+	t.Run("stops retrying when the context ends", func(t *testing.T) {
+		goOps := &flakyGoOperations{failures: 5}
+		exec := executor.New(executor.WithRetry(3, time.Hour))
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		if _, err := exec.Apply(ctx, optionsTestInput(git, goOps, &mockCommandRunner{})); err == nil {
+			t.Fatal("expected failure")
+		}
+		if goOps.calls.Load() != 1 {
+			t.Fatalf("go get called %d times, want 1", goOps.calls.Load())
+		}
+	})
+}
+
+// cloneRecordingGit records the URL passed to EnsureClone.
+type cloneRecordingGit struct {
+	*mockGitOperations
+	cloned string
+}
+
+func (g *cloneRecordingGit) EnsureClone(ctx context.Context, repo, workspace string) (string, error) {
+	g.cloned = repo
+	return g.mockGitOperations.EnsureClone(ctx, repo, workspace)
+}
+
+func TestExecutor_WithRepoURLs(t *testing.T) {
+	urls := repourl.New(
+		repourl.WithDefaultHost("github.example.com"),
+		repourl.WithHosts(repourl.Host{Name: "github.example.com", Protocol: repourl.ProtocolSSH, SSHPort: 2222}),
+	)
+
+	git := &cloneRecordingGit{mockGitOperations: &mockGitOperations{clonePath: "/workspace/app", workPath: "/workspace/app-wt", commitHash: "abc"}}
+	if _, err := executor.New().Apply(context.Background(), optionsTestInput(git, &mockGoOperations{}, &mockCommandRunner{})); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if git.cloned != "example/app" {
+		t.Fatalf("without a resolver the repo is passed through, got %q", git.cloned)
+	}
+
+	exec := executor.New(executor.WithRepoURLs(urls))
+	if _, err := exec.Apply(context.Background(), optionsTestInput(git, &mockGoOperations{}, &mockCommandRunner{})); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if want := "ssh://git@github.example.com:2222/example/app.git"; git.cloned != want {
+		t.Fatalf("cloned %q, want %q", git.cloned, want)
+	}
+}
+
+func TestExecutor_CloneProtocol(t *testing.T) {
+	git := &cloneRecordingGit{mockGitOperations: &mockGitOperations{clonePath: "/workspace/app", workPath: "/workspace/app-wt", commitHash: "abc"}}
+	input := optionsTestInput(git, &mockGoOperations{}, &mockCommandRunner{})
+	input.Item.CloneProtocol = "ssh"
+	if _, err := executor.New().Apply(context.Background(), input); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if want := "git@github.com:example/app.git"; git.cloned != want {
+		t.Fatalf("cloned %q, want %q", git.cloned, want)
+	}
+}
+
+func TestExecutor_ScopesGoFlagsToItem(t *testing.T) {
+	git := &mockGitOperations{clonePath: "/workspace/app", workPath: "/workspace/app-wt", commitHash: "abc"}
+	runner := &recordingCommandRunner{}
+
+	input := optionsTestInput(git, &mockGoOperations{}, runner)
+	input.Item.GoFlags = "-tags=private"
+	input.Item.Env = map[string]string{"GOFLAGS": "-mod=mod"}
+	if _, err := executor.New().Apply(context.Background(), input); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if got := runner.calls[0].env["GOFLAGS"]; got != "-mod=mod -tags=private" {
+		t.Fatalf("GOFLAGS = %q, want the item's flags appended", got)
+	}
+	if input.Item.Env["GOFLAGS"] != "-mod=mod" {
+		t.Fatal("expected the item env to be left unchanged")
+	}
+}
+
+type commitRecordingGit struct {
+	*mockGitOperations
+	message string
+}
+
+func (g *commitRecordingGit) Commit(ctx context.Context, repoPath, message string) (string, error) {
+	g.message = message
+	return g.mockGitOperations.Commit(ctx, repoPath, message)
+}
+
+func TestExecutor_WithDependabotCommits(t *testing.T) {
+	git := &commitRecordingGit{mockGitOperations: &mockGitOperations{clonePath: "/workspace/app", workPath: "/workspace/app-wt", commitHash: "abc"}}
+	if _, err := executor.New().Apply(context.Background(), optionsTestInput(git, &mockGoOperations{}, &mockCommandRunner{})); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if git.message != "bump lib" {
+		t.Fatalf("expected the item's commit message by default, got %q", git.message)
+	}
+
+	exec := executor.New(executor.WithDependabotCommits(""))
+	if _, err := exec.Apply(context.Background(), optionsTestInput(git, &mockGoOperations{}, &mockCommandRunner{})); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	want := "chore(deps): bump github.com/example/lib to v1.2.3\n\n" +
+		"Bumps github.com/example/lib to v1.2.3.\n\n" +
+		"---\nupdated-dependencies:\n" +
+		"- dependency-name: github.com/example/lib\n" +
+		"  dependency-version: v1.2.3\n" +
+		"  dependency-type: direct:production\n" +
+		"...\n"
+	if git.message != want {
+		t.Fatalf("commit message = %q, want %q", git.message, want)
+	}
+}
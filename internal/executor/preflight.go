@@ -0,0 +1,184 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/goliatone/cascade/internal/manifest"
+	"github.com/goliatone/cascade/internal/planner"
+	"github.com/goliatone/cascade/pkg/goproxy"
+)
+
+// ModuleDownloader is implemented by GoOperations that can fetch a module
+// version outside any dependent. The proxy preflight uses it to check the
+// target version once instead of letting every dependent hit the same error.
+type ModuleDownloader interface {
+	Download(ctx context.Context, module, version string, env map[string]string) error
+}
+
+// proxyFallbackGoFlags is added to GOFLAGS of items that fetch the target
+// directly, so go commands may update go.mod and go.sum as they resolve it.
+const proxyFallbackGoFlags = "-mod=mod"
+
+// Download runs go mod download for module@version from an empty scratch
+// module, with env added to the process environment.
+func (g *goOperations) Download(ctx context.Context, module, version string, env map[string]string) error {
+	dir, err := os.MkdirTemp("", "cascade-preflight-*")
+	if err != nil {
+		return &GoOperationError{Module: module, Version: version, Err: fmt.Errorf("create scratch module: %w", err)}
+	}
+	defer os.RemoveAll(dir)
+
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module cascade.preflight\n"), 0o644); err != nil {
+		return &GoOperationError{Module: module, Version: version, Err: fmt.Errorf("create scratch module: %w", err)}
+	}
+
+	cmd := exec.CommandContext(ctx, "go", "mod", "download", "-json", fmt.Sprintf("%s@%s", module, version))
+	cmd.Dir = dir
+	if len(env) > 0 {
+		cmd.Env = prepareEnv(env)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		// go mod download reports the failure in the JSON on stdout
+		output := strings.TrimSpace(stdout.String() + "\n" + stderr.String())
+		return &GoOperationError{
+			Module:  module,
+			Version: version,
+			Err:     fmt.Errorf("go mod download failed: %w\nOutput: %s", err, output),
+		}
+	}
+	return nil
+}
+
+// ProxyPreflight reports how the target version resolved before execution.
+type ProxyPreflight struct {
+	// Fallback is set when the version only resolved by fetching the module
+	// directly, bypassing GOPROXY and the checksum database.
+	Fallback bool
+
+	// ProxyErr is why the effective GOPROXY could not serve the version.
+	ProxyErr error
+}
+
+// PreflightProxy checks that module@version resolves through the effective
+// GOPROXY. When it does not, it retries with the module added to GOPRIVATE and
+// GOFLAGS=-mod=mod and reports a fallback, which ApplyProxyFallback then
+// applies to the items. Credentials of go-modules items are used for both
+// attempts. It fails only when neither attempt resolves the version.
+func PreflightProxy(ctx context.Context, ops ModuleDownloader, module, version string, items []planner.WorkItem) (*ProxyPreflight, error) {
+	return preflightProxy(ctx, ops, module, version, items, os.Getenv)
+}
+
+func preflightProxy(ctx context.Context, ops ModuleDownloader, module, version string, items []planner.WorkItem, getenv func(string) string) (*ProxyPreflight, error) {
+	probe := planner.WorkItem{Credentials: preflightCredentials(items)}
+
+	env, err := newGoEnv(probe, getenv)
+	if err != nil {
+		return nil, err
+	}
+	defer env.cleanup()
+
+	proxyErr := ops.Download(ctx, module, version, env.vars)
+	if proxyErr == nil {
+		return &ProxyPreflight{}, nil
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, ctxErr
+	}
+
+	probe = withProxyFallback(probe, module, getenv)
+	direct, err := newGoEnv(probe, getenv)
+	if err != nil {
+		return nil, err
+	}
+	defer direct.cleanup()
+
+	if err := ops.Download(ctx, module, version, direct.vars); err != nil {
+		return nil, fmt.Errorf("%s@%s cannot be resolved through GOPROXY or directly: %w", module, version, errors.Join(proxyErr, err))
+	}
+	return &ProxyPreflight{Fallback: true, ProxyErr: proxyErr}, nil
+}
+
+// ApplyProxyFallback returns a copy of items in which every go-modules item
+// fetches module directly and is marked with ProxyFallback. Submodule items
+// do not run go commands and are returned unchanged.
+func ApplyProxyFallback(items []planner.WorkItem, module string) []planner.WorkItem {
+	out := make([]planner.WorkItem, len(items))
+	for i, item := range items {
+		if item.UpdateStrategy == manifest.UpdateStrategyGitSubmodule {
+			out[i] = item
+			continue
+		}
+		out[i] = withProxyFallback(item, module, os.Getenv)
+	}
+	return out
+}
+
+// withProxyFallback adds module to the GOPRIVATE patterns of item, and to
+// GONOPROXY and GONOSUMDB when those are set since they override GOPRIVATE,
+// and adds -mod=mod to its GoFlags unless a -mod flag is already given.
+func withProxyFallback(item planner.WorkItem, module string, getenv func(string) string) planner.WorkItem {
+	env := make(map[string]string, len(item.Env)+3)
+	for k, v := range item.Env {
+		env[k] = v
+	}
+	for _, key := range []string{"GOPRIVATE", "GONOPROXY", "GONOSUMDB"} {
+		base, ok := env[key]
+		if !ok {
+			base = getenv(key)
+		}
+		if key != "GOPRIVATE" && base == "" {
+			continue
+		}
+		env[key] = goproxy.AppendPattern(base, module)
+	}
+	item.Env = env
+
+	if !hasModFlag(item.GoFlags) && !hasModFlag(env["GOFLAGS"]) && !hasModFlag(getenv("GOFLAGS")) {
+		item.GoFlags = strings.TrimSpace(item.GoFlags + " " + proxyFallbackGoFlags)
+	}
+	item.ProxyFallback = true
+	return item
+}
+
+// hasModFlag reports whether flags sets -mod.
+func hasModFlag(flags string) bool {
+	for _, flag := range strings.Fields(flags) {
+		if strings.HasPrefix(flag, "-mod=") || strings.HasPrefix(flag, "--mod=") {
+			return true
+		}
+	}
+	return false
+}
+
+// preflightCredentials collects the credentials of go-modules items, once per
+// machine, so the preflight can reach private hosts the items can reach.
+func preflightCredentials(items []planner.WorkItem) []manifest.Credential {
+	seen := make(map[string]bool)
+	var creds []manifest.Credential
+	for _, item := range items {
+		if item.UpdateStrategy == manifest.UpdateStrategyGitSubmodule {
+			continue
+		}
+		for _, cred := range item.Credentials {
+			machine := strings.TrimSpace(cred.Machine)
+			if seen[machine] {
+				continue
+			}
+			seen[machine] = true
+			creds = append(creds, cred)
+		}
+	}
+	return creds
+}
@@ -0,0 +1,201 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/goliatone/cascade/internal/manifest"
+	"github.com/goliatone/cascade/internal/planner"
+)
+
+// fakeDownloader fails downloads whose env matches fail and records each env.
+type fakeDownloader struct {
+	fail  func(env map[string]string) error
+	calls []map[string]string
+}
+
+func (f *fakeDownloader) Download(ctx context.Context, module, version string, env map[string]string) error {
+	f.calls = append(f.calls, env)
+	if f.fail != nil {
+		return f.fail(env)
+	}
+	return nil
+}
+
+func mapEnv(vars map[string]string) func(string) string {
+	return func(key string) string { return vars[key] }
+}
+
+func TestPreflightProxy_ResolvesThroughProxy(t *testing.T) {
+	ops := &fakeDownloader{}
+	result, err := preflightProxy(context.Background(), ops, "example.com/lib", "v1.2.0", nil, mapEnv(nil))
+	if err != nil {
+		t.Fatalf("preflightProxy() error = %v", err)
+	}
+	if result.Fallback || len(ops.calls) != 1 {
+		t.Errorf("result = %+v after %d downloads, want a single proxy download", result, len(ops.calls))
+	}
+}
+
+func TestPreflightProxy_FallsBackToDirect(t *testing.T) {
+	proxyErr := errors.New("example.com/lib@v1.2.0: reading https://proxy.golang.org/...: 404 Not Found")
+	ops := &fakeDownloader{fail: func(env map[string]string) error {
+		if env["GOPRIVATE"] == "" {
+			return proxyErr
+		}
+		return nil
+	}}
+
+	result, err := preflightProxy(context.Background(), ops, "example.com/lib", "v1.2.0", nil, mapEnv(map[string]string{"GOPRIVATE": "corp.example.com"}))
+	if err != nil {
+		t.Fatalf("preflightProxy() error = %v", err)
+	}
+	if !result.Fallback || !errors.Is(result.ProxyErr, proxyErr) {
+		t.Errorf("result = %+v, want a fallback carrying the proxy error", result)
+	}
+	if len(ops.calls) != 2 {
+		t.Fatalf("downloads = %d, want 2", len(ops.calls))
+	}
+	direct := ops.calls[1]
+	if direct["GOPRIVATE"] != "corp.example.com,example.com/lib" || direct["GOFLAGS"] != "-mod=mod" {
+		t.Errorf("direct env = %v", direct)
+	}
+}
+
+func TestPreflightProxy_BothAttemptsFail(t *testing.T) {
+	ops := &fakeDownloader{fail: func(env map[string]string) error {
+		if env["GOPRIVATE"] == "" {
+			return errors.New("proxy: 404 Not Found")
+		}
+		return errors.New("git ls-remote: unknown revision v1.2.0")
+	}}
+
+	_, err := preflightProxy(context.Background(), ops, "example.com/lib", "v1.2.0", nil, mapEnv(nil))
+	if err == nil || !strings.Contains(err.Error(), "proxy: 404") || !strings.Contains(err.Error(), "unknown revision") {
+		t.Errorf("preflightProxy() error = %v, want both attempts reported", err)
+	}
+}
+
+func TestPreflightProxy_UsesItemCredentials(t *testing.T) {
+	var netrc string
+	ops := &fakeDownloader{fail: func(env map[string]string) error {
+		data, err := os.ReadFile(env["NETRC"])
+		if err != nil {
+			return err
+		}
+		netrc = string(data)
+		return nil
+	}}
+	items := []planner.WorkItem{
+		{Repo: "corp/a", Credentials: []manifest.Credential{{Machine: "git.corp.example.com", Login: "bot", PasswordEnv: "CORP_TOKEN"}}},
+		{Repo: "corp/b", Credentials: []manifest.Credential{{Machine: "git.corp.example.com", Login: "bot", PasswordEnv: "CORP_TOKEN"}}},
+		{Repo: "corp/c", UpdateStrategy: manifest.UpdateStrategyGitSubmodule, Credentials: []manifest.Credential{{Machine: "vendor.example.com", Login: "x", PasswordEnv: "UNSET"}}},
+	}
+
+	if _, err := preflightProxy(context.Background(), ops, "git.corp.example.com/lib", "v1.2.0", items, mapEnv(map[string]string{"CORP_TOKEN": "s3cret"})); err != nil {
+		t.Fatalf("preflightProxy() error = %v", err)
+	}
+	if strings.Count(netrc, "machine git.corp.example.com") != 1 || !strings.Contains(netrc, "password s3cret") || strings.Contains(netrc, "vendor.example.com") {
+		t.Errorf("netrc = %q, want the go-modules credentials once", netrc)
+	}
+}
+
+func TestWithProxyFallback(t *testing.T) {
+	tests := []struct {
+		name      string
+		item      planner.WorkItem
+		env       map[string]string
+		wantEnv   map[string]string
+		wantFlags string
+	}{
+		{
+			name:      "adds module to GOPRIVATE and -mod=mod",
+			item:      planner.WorkItem{GoFlags: "-tags=integration"},
+			wantEnv:   map[string]string{"GOPRIVATE": "example.com/lib"},
+			wantFlags: "-tags=integration -mod=mod",
+		},
+		{
+			name:      "extends item GOPRIVATE and set overrides",
+			item:      planner.WorkItem{Env: map[string]string{"GOPRIVATE": "corp.example.com"}},
+			env:       map[string]string{"GONOSUMDB": "other.example.com"},
+			wantEnv:   map[string]string{"GOPRIVATE": "corp.example.com,example.com/lib", "GONOSUMDB": "other.example.com,example.com/lib"},
+			wantFlags: "-mod=mod",
+		},
+		{
+			name:      "keeps an explicit -mod flag",
+			item:      planner.WorkItem{GoFlags: "-mod=vendor", Env: map[string]string{"GOPRIVATE": "example.com/lib"}},
+			wantEnv:   map[string]string{"GOPRIVATE": "example.com/lib"},
+			wantFlags: "-mod=vendor",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := withProxyFallback(tt.item, "example.com/lib", mapEnv(tt.env))
+			if !got.ProxyFallback || got.GoFlags != tt.wantFlags {
+				t.Errorf("item = %+v, want ProxyFallback and GoFlags %q", got, tt.wantFlags)
+			}
+			if len(got.Env) != len(tt.wantEnv) {
+				t.Errorf("Env = %v, want %v", got.Env, tt.wantEnv)
+			}
+			for k, v := range tt.wantEnv {
+				if got.Env[k] != v {
+					t.Errorf("Env[%s] = %q, want %q", k, got.Env[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestApplyProxyFallback(t *testing.T) {
+	shared := map[string]string{"CGO_ENABLED": "0"}
+	items := []planner.WorkItem{
+		{Repo: "org/a", Env: shared},
+		{Repo: "org/b", UpdateStrategy: manifest.UpdateStrategyGitSubmodule},
+	}
+
+	got := ApplyProxyFallback(items, "example.com/lib")
+	if !got[0].ProxyFallback || !strings.Contains(got[0].Env["GOPRIVATE"], "example.com/lib") {
+		t.Errorf("go-modules item = %+v, want a direct fetch", got[0])
+	}
+	if got[1].ProxyFallback || got[1].Env != nil {
+		t.Errorf("submodule item = %+v, want it unchanged", got[1])
+	}
+	if _, ok := shared["GOPRIVATE"]; ok || items[0].ProxyFallback {
+		t.Error("ApplyProxyFallback modified the input items")
+	}
+}
+
+func TestGoOperations_Download(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "log")
+	script := `#!/bin/sh
+echo "$* GOPRIVATE=$GOPRIVATE" > "` + logPath + `"
+test -f go.mod || { echo "go.mod missing" >&2; exit 1; }
+case "$4" in
+    *@v9.9.9) echo '{"Error": "unknown revision v9.9.9"}'; exit 1 ;;
+esac
+`
+	if err := os.WriteFile(filepath.Join(dir, "go"), []byte(script), 0o755); err != nil {
+		t.Fatalf("failed to write fake go binary: %v", err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	ops := NewGoOperations().(ModuleDownloader)
+	if err := ops.Download(context.Background(), "example.com/lib", "v1.2.0", map[string]string{"GOPRIVATE": "example.com/lib"}); err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+	logged, _ := os.ReadFile(logPath)
+	if got := strings.TrimSpace(string(logged)); got != "mod download -json example.com/lib@v1.2.0 GOPRIVATE=example.com/lib" {
+		t.Errorf("go invocation = %q", got)
+	}
+
+	err := ops.Download(context.Background(), "example.com/lib", "v9.9.9", nil)
+	if !IsGoError(err) || !strings.Contains(err.Error(), "unknown revision v9.9.9") {
+		t.Errorf("Download() error = %v, want a GoOperationError with go's output", err)
+	}
+}
@@ -0,0 +1,26 @@
+package executor
+
+import (
+	"context"
+	"time"
+
+	"github.com/goliatone/cascade/pkg/retry"
+)
+
+// retry runs op, retrying failures with exponential backoff as configured by
+// WithRetry. Cancellation of ctx stops retrying and returns the last error.
+func (e *executor) retry(ctx context.Context, input WorkItemContext, operation string, op func() error) error {
+	policy := retry.Policy{
+		MaxAttempts: e.retries + 1,
+		Delay:       e.retryDelay,
+		OnRetry: func(attempt int, err error, delay time.Duration) {
+			if input.Logger != nil {
+				input.Logger.Info("retrying after failure", "repo", input.Item.Repo, "operation", operation,
+					"attempt", attempt, "of", e.retries, "delay", delay, "error", err)
+			}
+		},
+	}
+	return retry.Do(ctx, policy, func(context.Context) error {
+		return op()
+	})
+}
@@ -0,0 +1,120 @@
+package executor
+
+import (
+	"sort"
+
+	"github.com/goliatone/cascade/internal/planner"
+)
+
+// Schedule calls run for every item with at most limit items in flight. Items
+// sharing a ConcurrencyGroup are never run at the same time, and neither are
+// items of the same repository, which share its clone in the workspace: while
+// one is in flight, the rest wait and later items that conflict with nothing in
+// flight are started instead. Items otherwise start in plan order. Schedule
+// returns once every call has finished; run must be safe for concurrent use
+// when limit > 1.
+func Schedule(items []planner.WorkItem, limit int, run func(index int, item planner.WorkItem)) {
+	ready := make(chan int, len(items))
+	for i := range items {
+		ready <- i
+	}
+	close(ready)
+	ScheduleReady(items, limit, ready, run)
+}
+
+// ScheduleReady is Schedule for items that become runnable over time: an item
+// is only considered once its index is received from ready, so items waiting
+// on something outside the scheduler, such as an approval, hold no slot.
+// Indexes never sent are not run. ScheduleReady returns once ready is closed
+// and every call has finished.
+func ScheduleReady(items []planner.WorkItem, limit int, ready <-chan int, run func(index int, item planner.WorkItem)) {
+	if limit < 1 {
+		limit = 1
+	}
+
+	var pending []int
+	busy := make(map[string]bool)
+	done := make(chan int)
+	running := 0
+
+	// enqueue keeps pending in plan order whatever order items became ready in.
+	enqueue := func(index int) {
+		pos := sort.SearchInts(pending, index)
+		pending = append(pending, 0)
+		copy(pending[pos+1:], pending[pos:])
+		pending[pos] = index
+	}
+
+	for ready != nil || len(pending) > 0 || running > 0 {
+		// Take every item that is already ready before picking the next one,
+		// so a burst of approvals still starts in plan order.
+	drain:
+		for ready != nil {
+			select {
+			case index, ok := <-ready:
+				if !ok {
+					ready = nil
+					break drain
+				}
+				enqueue(index)
+			default:
+				break drain
+			}
+		}
+
+		for running < limit {
+			next := nextRunnable(items, pending, busy)
+			if next < 0 {
+				break
+			}
+			index := pending[next]
+			pending = append(pending[:next], pending[next+1:]...)
+			for _, key := range items[index].ExclusiveKeys() {
+				busy[key] = true
+			}
+			running++
+			go func(index int) {
+				defer func() { done <- index }()
+				run(index, items[index])
+			}(index)
+		}
+
+		if ready == nil && running == 0 {
+			// Nothing is in flight, so nothing pending can be blocked.
+			continue
+		}
+
+		select {
+		case index, ok := <-ready:
+			if !ok {
+				ready = nil
+				continue
+			}
+			enqueue(index)
+		case index := <-done:
+			running--
+			for _, key := range items[index].ExclusiveKeys() {
+				delete(busy, key)
+			}
+		}
+	}
+}
+
+// nextRunnable returns the position in pending of the first item that shares
+// neither its group nor its repository with an item in flight, or -1 when every
+// pending item is waiting.
+func nextRunnable(items []planner.WorkItem, pending []int, busy map[string]bool) int {
+	for pos, index := range pending {
+		runnable := true
+		for _, key := range items[index].ExclusiveKeys() {
+			if busy[key] {
+				runnable = false
+				break
+			}
+		}
+		if runnable {
+			return pos
+		}
+	}
+	return -1
+}
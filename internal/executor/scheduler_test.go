@@ -0,0 +1,189 @@
+package executor
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/goliatone/cascade/internal/planner"
+)
+
+func TestSchedule(t *testing.T) {
+	t.Run("runs every item once", func(t *testing.T) {
+		items := []planner.WorkItem{{Repo: "org/a"}, {Repo: "org/b"}, {Repo: "org/c"}}
+		var mu sync.Mutex
+		seen := make(map[string]int)
+		Schedule(items, 2, func(_ int, item planner.WorkItem) {
+			mu.Lock()
+			seen[item.Repo]++
+			mu.Unlock()
+		})
+		for _, item := range items {
+			if seen[item.Repo] != 1 {
+				t.Fatalf("expected %s to run once, ran %d times", item.Repo, seen[item.Repo])
+			}
+		}
+	})
+
+	t.Run("limit of one runs items in plan order", func(t *testing.T) {
+		items := []planner.WorkItem{
+			{Repo: "org/a", ConcurrencyGroup: "prod"},
+			{Repo: "org/b"},
+			{Repo: "org/c", ConcurrencyGroup: "prod"},
+		}
+		var order []string
+		Schedule(items, 0, func(_ int, item planner.WorkItem) {
+			order = append(order, item.Repo)
+		})
+		if len(order) != 3 || order[0] != "org/a" || order[1] != "org/b" || order[2] != "org/c" {
+			t.Fatalf("unexpected order %v", order)
+		}
+	})
+
+	t.Run("items sharing a group never overlap", func(t *testing.T) {
+		items := []planner.WorkItem{
+			{Repo: "org/a", ConcurrencyGroup: "prod"},
+			{Repo: "org/b", ConcurrencyGroup: "prod"},
+			{Repo: "org/c", ConcurrencyGroup: "prod"},
+			{Repo: "org/d"},
+			{Repo: "org/e", ConcurrencyGroup: "staging"},
+		}
+
+		var mu sync.Mutex
+		active := make(map[string]int)
+		inFlight, maxInFlight := 0, 0
+		overlap := false
+		Schedule(items, len(items), func(_ int, item planner.WorkItem) {
+			mu.Lock()
+			inFlight++
+			if inFlight > maxInFlight {
+				maxInFlight = inFlight
+			}
+			if group := item.ConcurrencyGroup; group != "" {
+				active[group]++
+				if active[group] > 1 {
+					overlap = true
+				}
+			}
+			mu.Unlock()
+
+			time.Sleep(20 * time.Millisecond)
+
+			mu.Lock()
+			inFlight--
+			if group := item.ConcurrencyGroup; group != "" {
+				active[group]--
+			}
+			mu.Unlock()
+		})
+
+		if overlap {
+			t.Fatal("items in the same concurrency group ran in parallel")
+		}
+		if maxInFlight < 3 {
+			t.Fatalf("expected ungrouped and other-group items to run alongside the group, max in flight %d", maxInFlight)
+		}
+	})
+
+	t.Run("items of the same repository never overlap", func(t *testing.T) {
+		items := []planner.WorkItem{
+			{Repo: "org/mono", Module: "github.com/org/mono/api"},
+			{Repo: "org/mono", Module: "github.com/org/mono/worker"},
+			{Repo: "org/b"},
+		}
+
+		var mu sync.Mutex
+		active := make(map[string]int)
+		overlap := false
+		Schedule(items, len(items), func(_ int, item planner.WorkItem) {
+			mu.Lock()
+			active[item.Repo]++
+			if active[item.Repo] > 1 {
+				overlap = true
+			}
+			mu.Unlock()
+
+			time.Sleep(20 * time.Millisecond)
+
+			mu.Lock()
+			active[item.Repo]--
+			mu.Unlock()
+		})
+
+		if overlap {
+			t.Fatal("items of the same repository ran in parallel")
+		}
+	})
+
+	t.Run("waiting group does not block later items", func(t *testing.T) {
+		items := []planner.WorkItem{
+			{Repo: "org/a", ConcurrencyGroup: "prod"},
+			{Repo: "org/b", ConcurrencyGroup: "prod"},
+			{Repo: "org/c"},
+		}
+		release := make(chan struct{})
+		started := make(chan string, len(items))
+		finished := make(chan struct{})
+		go func() {
+			Schedule(items, 2, func(_ int, item planner.WorkItem) {
+				started <- item.Repo
+				if item.Repo == "org/a" {
+					<-release
+				}
+			})
+			close(finished)
+		}()
+
+		first, second := <-started, <-started
+		if first == "org/b" || second == "org/b" {
+			t.Fatalf("org/b started while org/a held the group (started %s, %s)", first, second)
+		}
+		close(release)
+		<-finished
+		if last := <-started; last != "org/b" {
+			t.Fatalf("expected org/b to run last, got %s", last)
+		}
+	})
+}
+
+func TestScheduleReady(t *testing.T) {
+	t.Run("items that are not ready hold no slot", func(t *testing.T) {
+		items := []planner.WorkItem{{Repo: "org/a"}, {Repo: "org/b"}, {Repo: "org/c"}}
+		ready := make(chan int)
+		ranB := make(chan struct{})
+
+		var mu sync.Mutex
+		var order []string
+		finished := make(chan struct{})
+		go func() {
+			defer close(finished)
+			ScheduleReady(items, 1, ready, func(_ int, item planner.WorkItem) {
+				mu.Lock()
+				order = append(order, item.Repo)
+				mu.Unlock()
+				if item.Repo == "org/b" {
+					close(ranB)
+				}
+			})
+		}()
+
+		// org/a is still waiting while org/b runs in the only slot.
+		ready <- 1
+		select {
+		case <-ranB:
+		case <-time.After(time.Second):
+			t.Fatal("ready item waited for an item that was not ready")
+		}
+		ready <- 0
+		close(ready)
+
+		select {
+		case <-finished:
+		case <-time.After(time.Second):
+			t.Fatal("ScheduleReady did not return after ready was closed")
+		}
+		if len(order) != 2 || order[0] != "org/b" || order[1] != "org/a" {
+			t.Fatalf("unexpected order %v", order)
+		}
+	})
+}
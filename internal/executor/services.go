@@ -0,0 +1,317 @@
+package executor
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/goliatone/cascade/internal/manifest"
+)
+
+const (
+	defaultServiceProbeInterval = time.Second
+	defaultServiceReadyTimeout  = 60 * time.Second
+	serviceStopTimeout          = 30 * time.Second
+)
+
+var serviceNameSanitizer = regexp.MustCompile(`[^a-z0-9_.-]+`)
+
+// ServiceManager starts and removes the backing services a work item declares.
+type ServiceManager interface {
+	// Start launches every service, waits until each is healthy, and returns the
+	// environment variables that point test commands at them. Services that were
+	// started are removed again when Start fails.
+	Start(ctx context.Context, project string, services []manifest.Service) (map[string]string, error)
+	// Stop removes the services started for project.
+	Stop(ctx context.Context, project string, services []manifest.Service) error
+}
+
+// DockerCommandRunner defines the interface for executing docker commands.
+type DockerCommandRunner interface {
+	Run(ctx context.Context, args ...string) (string, error)
+}
+
+// ServiceError wraps service lifecycle failures.
+type ServiceError struct {
+	Service   string
+	Operation string
+	Err       error
+}
+
+func (e *ServiceError) Error() string {
+	return fmt.Sprintf("executor: service %s %s failed: %v", e.Service, e.Operation, e.Err)
+}
+
+func (e *ServiceError) Unwrap() error {
+	return e.Err
+}
+
+// ServiceProject returns the container name prefix for a work item's services. It is
+// stable per repository and branch so leftovers from an interrupted run are replaced.
+func ServiceProject(repo, branch string) string {
+	sum := sha1.Sum([]byte(repo + "@" + branch))
+	base := repo
+	if idx := strings.LastIndex(repo, "/"); idx >= 0 {
+		base = repo[idx+1:]
+	}
+	base = strings.Trim(serviceNameSanitizer.ReplaceAllString(strings.ToLower(base), "-"), "-.")
+	if base == "" {
+		base = "repo"
+	}
+	return fmt.Sprintf("cascade-%s-%s", base, hex.EncodeToString(sum[:4]))
+}
+
+// serviceEnv merges service connection variables under the item's environment so
+// values set explicitly in the manifest win.
+func serviceEnv(services, item map[string]string) map[string]string {
+	if len(services) == 0 {
+		return item
+	}
+	merged := make(map[string]string, len(services)+len(item))
+	for k, v := range services {
+		merged[k] = v
+	}
+	for k, v := range item {
+		merged[k] = v
+	}
+	return merged
+}
+
+// dockerServiceManager implements ServiceManager with the docker CLI.
+type dockerServiceManager struct {
+	runner DockerCommandRunner
+	dial   func(ctx context.Context, address string) error
+}
+
+// NewDockerServiceManager creates a ServiceManager that runs services as docker containers.
+func NewDockerServiceManager() ServiceManager {
+	return NewDockerServiceManagerWithRunner(&defaultDockerCommandRunner{})
+}
+
+// NewDockerServiceManagerWithRunner creates a docker ServiceManager using runner.
+func NewDockerServiceManagerWithRunner(runner DockerCommandRunner) ServiceManager {
+	return &dockerServiceManager{runner: runner, dial: dialTCP}
+}
+
+func (m *dockerServiceManager) Start(ctx context.Context, project string, services []manifest.Service) (map[string]string, error) {
+	env := make(map[string]string)
+	for i, svc := range services {
+		if err := m.start(ctx, project, svc, env); err != nil {
+			stopCtx, cancel := context.WithTimeout(context.Background(), serviceStopTimeout)
+			_ = m.Stop(stopCtx, project, services[:i+1])
+			cancel()
+			return nil, err
+		}
+	}
+	return env, nil
+}
+
+func (m *dockerServiceManager) start(ctx context.Context, project string, svc manifest.Service, env map[string]string) error {
+	name := containerName(project, svc.Name)
+
+	// Replace a container left behind by an interrupted run
+	_, _ = m.runner.Run(ctx, "rm", "-f", "-v", name)
+
+	args := []string{"run", "-d", "--name", name, "--label", "cascade.project=" + project}
+	for _, key := range sortedKeys(svc.Env) {
+		args = append(args, "-e", key+"="+svc.Env[key])
+	}
+	if svc.Port > 0 {
+		args = append(args, "-p", fmt.Sprintf("127.0.0.1::%d", svc.Port))
+	}
+	args = append(args, svc.Image)
+
+	if _, err := m.runner.Run(ctx, args...); err != nil {
+		return &ServiceError{Service: svc.Name, Operation: "start", Err: err}
+	}
+
+	endpoint := serviceEndpoint{Name: svc.Name, Host: "127.0.0.1"}
+	if svc.Port > 0 {
+		out, err := m.runner.Run(ctx, "port", name, fmt.Sprintf("%d/tcp", svc.Port))
+		if err != nil {
+			return &ServiceError{Service: svc.Name, Operation: "port lookup", Err: err}
+		}
+		host, port, err := parseDockerPort(out)
+		if err != nil {
+			return &ServiceError{Service: svc.Name, Operation: "port lookup", Err: err}
+		}
+		endpoint.Host, endpoint.Port = host, port
+	}
+
+	if err := m.waitReady(ctx, name, svc, endpoint); err != nil {
+		return &ServiceError{Service: svc.Name, Operation: "health check", Err: err}
+	}
+
+	prefix := envPrefix(svc.Name)
+	env[prefix+"_HOST"] = endpoint.Host
+	if endpoint.Port > 0 {
+		env[prefix+"_PORT"] = strconv.Itoa(endpoint.Port)
+	}
+	for _, key := range sortedKeys(svc.Export) {
+		value, err := renderServiceExport(key, svc.Export[key], endpoint)
+		if err != nil {
+			return &ServiceError{Service: svc.Name, Operation: "export " + key, Err: err}
+		}
+		env[key] = value
+	}
+	return nil
+}
+
+// waitReady probes the service until its health check passes or the timeout expires.
+func (m *dockerServiceManager) waitReady(ctx context.Context, name string, svc manifest.Service, endpoint serviceEndpoint) error {
+	interval, timeout := defaultServiceProbeInterval, defaultServiceReadyTimeout
+	var probeCmd []string
+	if hc := svc.HealthCheck; hc != nil {
+		if hc.Interval > 0 {
+			interval = hc.Interval
+		}
+		if hc.Timeout > 0 {
+			timeout = hc.Timeout
+		}
+		probeCmd = hc.Cmd
+	}
+	if len(probeCmd) == 0 && endpoint.Port == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var lastErr error
+	for {
+		if len(probeCmd) > 0 {
+			_, lastErr = m.runner.Run(ctx, append([]string{"exec", name}, probeCmd...)...)
+		} else {
+			lastErr = m.dial(ctx, net.JoinHostPort(endpoint.Host, strconv.Itoa(endpoint.Port)))
+		}
+		if lastErr == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("not ready after %s: %w", timeout, lastErr)
+		case <-time.After(interval):
+		}
+	}
+}
+
+func (m *dockerServiceManager) Stop(ctx context.Context, project string, services []manifest.Service) error {
+	var errs []string
+	for _, svc := range services {
+		if _, err := m.runner.Run(ctx, "rm", "-f", "-v", containerName(project, svc.Name)); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", svc.Name, err))
+		}
+	}
+	if len(errs) > 0 {
+		return &ServiceError{Service: strings.Join(serviceNames(services), ","), Operation: "stop", Err: fmt.Errorf("%s", strings.Join(errs, "; "))}
+	}
+	return nil
+}
+
+// serviceEndpoint is the data available to export templates.
+type serviceEndpoint struct {
+	Name string
+	Host string
+	Port int
+}
+
+func renderServiceExport(key, text string, endpoint serviceEndpoint) (string, error) {
+	tmpl, err := template.New(key).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, endpoint); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// parseDockerPort parses `docker port` output such as "127.0.0.1:49153", using the
+// first IPv4 binding when several are listed.
+func parseDockerPort(output string) (string, int, error) {
+	lines := strings.Fields(strings.TrimSpace(output))
+	if len(lines) == 0 {
+		return "", 0, fmt.Errorf("no published port")
+	}
+	binding := lines[0]
+	for _, line := range lines {
+		if !strings.HasPrefix(line, "[") {
+			binding = line
+			break
+		}
+	}
+	host, portStr, err := net.SplitHostPort(binding)
+	if err != nil {
+		return "", 0, fmt.Errorf("unexpected port binding %q: %w", binding, err)
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return "", 0, fmt.Errorf("unexpected port binding %q: %w", binding, err)
+	}
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "127.0.0.1"
+	}
+	return host, port, nil
+}
+
+func containerName(project, service string) string {
+	return project + "-" + strings.Trim(serviceNameSanitizer.ReplaceAllString(strings.ToLower(service), "-"), "-.")
+}
+
+func envPrefix(name string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(name))
+}
+
+func serviceNames(services []manifest.Service) []string {
+	names := make([]string, len(services))
+	for i, svc := range services {
+		names[i] = svc.Name
+	}
+	return names
+}
+
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func dialTCP(ctx context.Context, address string) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// defaultDockerCommandRunner implements DockerCommandRunner using os/exec.
+type defaultDockerCommandRunner struct{}
+
+// Run executes a docker command and returns its trimmed combined output.
+func (r *defaultDockerCommandRunner) Run(ctx context.Context, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "docker", args...)
+	output, err := cmd.CombinedOutput()
+	result := strings.TrimSpace(string(output))
+	if err != nil {
+		if result != "" {
+			return result, fmt.Errorf("docker %s: %w: %s", args[0], err, result)
+		}
+		return result, fmt.Errorf("docker %s: %w", args[0], err)
+	}
+	return result, nil
+}
@@ -0,0 +1,86 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"golang.org/x/mod/module"
+)
+
+// updateSubmodule moves the dependent's submodule of the source module to the
+// release tag and records the pointer change on result.
+func (e *executor) updateSubmodule(ctx context.Context, input WorkItemContext, workPath string, result *Result) error {
+	submodules, ok := input.Git.(SubmoduleOperations)
+	if !ok {
+		err := fmt.Errorf("git operations do not support submodules")
+		e.handleExecutionError(result, err, "submodule update")
+		return err
+	}
+
+	input.report(PhaseDependencies)
+	path := input.Item.SubmodulePath
+	if path == "" {
+		repo := submoduleRepo(input.Item.SourceModule)
+		found, err := submodules.FindSubmodule(ctx, workPath, repo)
+		if err != nil {
+			err = fmt.Errorf("%w; set submodule_path for this dependent", err)
+			e.handleExecutionError(result, err, "submodule lookup")
+			return err
+		}
+		path = found
+	}
+
+	tag := submoduleTag(input.Item.SourceModule, input.Item.SourceVersion)
+	if input.Logger != nil {
+		input.Logger.Info("updating submodule", "path", path, "module", input.Item.SourceModule, "tag", tag)
+	}
+
+	var update SubmoduleUpdate
+	err := e.retry(ctx, input, "submodule update", func() error {
+		var updateErr error
+		update, updateErr = submodules.UpdateSubmodule(ctx, workPath, path, tag)
+		return updateErr
+	})
+	if err != nil {
+		e.handleExecutionError(result, err, "submodule update")
+		return err
+	}
+
+	if impact := result.DependencyImpact; impact != nil {
+		impact.OldVersion = update.OldCommit
+		impact.OldVersionDetected = update.OldCommit != ""
+		impact.NewVersion = input.Item.SourceVersion
+		impact.NewVersionDetected = true
+		impact.Notes = append(impact.Notes, fmt.Sprintf("submodule %s moved to %s (%s)", path, tag, update.NewCommit))
+	}
+	return nil
+}
+
+// submoduleRepo returns the host/owner/name repository of a module path, without
+// the major version suffix or the subdirectory of nested modules.
+func submoduleRepo(modulePath string) string {
+	prefix, _, ok := module.SplitPathVersion(modulePath)
+	if !ok {
+		prefix = modulePath
+	}
+	parts := strings.Split(prefix, "/")
+	if len(parts) > 3 {
+		parts = parts[:3]
+	}
+	return strings.Join(parts, "/")
+}
+
+// submoduleTag returns the git tag of version for modulePath. Modules nested in a
+// subdirectory of their repository are tagged with the subdirectory as prefix.
+func submoduleTag(modulePath, version string) string {
+	prefix, _, ok := module.SplitPathVersion(modulePath)
+	if !ok {
+		prefix = modulePath
+	}
+	parts := strings.Split(prefix, "/")
+	if len(parts) > 3 {
+		return strings.Join(parts[3:], "/") + "/" + version
+	}
+	return version
+}
@@ -0,0 +1,94 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSubmoduleRepoAndTag(t *testing.T) {
+	tests := []struct {
+		module   string
+		version  string
+		wantRepo string
+		wantTag  string
+	}{
+		{module: "github.com/goliatone/go-errors", version: "v1.2.3", wantRepo: "github.com/goliatone/go-errors", wantTag: "v1.2.3"},
+		{module: "github.com/goliatone/go-errors/v2", version: "v2.0.1", wantRepo: "github.com/goliatone/go-errors", wantTag: "v2.0.1"},
+		{module: "github.com/goliatone/tools/lint", version: "v0.4.0", wantRepo: "github.com/goliatone/tools", wantTag: "lint/v0.4.0"},
+		{module: "github.com/goliatone/tools/lint/v3", version: "v3.0.0", wantRepo: "github.com/goliatone/tools", wantTag: "lint/v3.0.0"},
+	}
+
+	for _, tt := range tests {
+		if got := submoduleRepo(tt.module); got != tt.wantRepo {
+			t.Errorf("submoduleRepo(%q) = %q, want %q", tt.module, got, tt.wantRepo)
+		}
+		if got := submoduleTag(tt.module, tt.version); got != tt.wantTag {
+			t.Errorf("submoduleTag(%q, %q) = %q, want %q", tt.module, tt.version, got, tt.wantTag)
+		}
+	}
+}
+
+func TestGitOperations_FindSubmodule(t *testing.T) {
+	runner := newMockGitCommandRunner()
+	runner.setResponse(`config --file .gitmodules --get-regexp ^submodule\..*\.url$`,
+		"submodule.docs.url ../docs.git\nsubmodule.vendor/errors.url git@github.com:Goliatone/go-errors.git\n", nil)
+	runner.setResponse("config --file .gitmodules --get submodule.vendor/errors.path", "third_party/go-errors\n", nil)
+	git := &gitOperations{runner: runner}
+
+	path, err := git.FindSubmodule(context.Background(), "/repo", "github.com/goliatone/go-errors")
+	if err != nil {
+		t.Fatalf("FindSubmodule() error = %v", err)
+	}
+	if path != "third_party/go-errors" {
+		t.Errorf("FindSubmodule() = %q, want third_party/go-errors", path)
+	}
+
+	if _, err := git.FindSubmodule(context.Background(), "/repo", "github.com/goliatone/other"); err == nil {
+		t.Error("expected error for a repository without submodule")
+	}
+}
+
+func TestGitOperations_UpdateSubmodule(t *testing.T) {
+	runner := newMockGitCommandRunner()
+	runner.setResponse("rev-parse HEAD:third_party/go-errors", "1111111\n", nil)
+	runner.setResponse("rev-parse HEAD", "2222222\n", nil)
+	git := &gitOperations{runner: runner}
+
+	update, err := git.UpdateSubmodule(context.Background(), "/repo", "third_party/go-errors", "v1.2.3")
+	if err != nil {
+		t.Fatalf("UpdateSubmodule() error = %v", err)
+	}
+	want := SubmoduleUpdate{Path: "third_party/go-errors", OldCommit: "1111111", NewCommit: "2222222"}
+	if update != want {
+		t.Errorf("UpdateSubmodule() = %+v, want %+v", update, want)
+	}
+
+	for _, signature := range []string{
+		"submodule update --init -- third_party/go-errors",
+		"fetch --tags origin",
+		"checkout --detach refs/tags/v1.2.3^{commit}",
+		"add -- third_party/go-errors",
+	} {
+		if !containsGitCall(runner.calls, signature) {
+			t.Errorf("expected git call %q", signature)
+		}
+	}
+	for _, call := range runner.calls {
+		if call.args[0] == "checkout" && call.dir != "/repo/third_party/go-errors" {
+			t.Errorf("checkout ran in %s, want the submodule directory", call.dir)
+		}
+	}
+}
+
+func TestGitOperations_UpdateSubmodule_MissingTag(t *testing.T) {
+	runner := newMockGitCommandRunner()
+	runner.setResponse("checkout --detach refs/tags/v9.9.9^{commit}", "", errors.New("invalid reference"))
+	git := &gitOperations{runner: runner}
+
+	_, err := git.UpdateSubmodule(context.Background(), "/repo", "lib", "v9.9.9")
+	if err == nil || !strings.Contains(err.Error(), "failed to check out v9.9.9") {
+		t.Fatalf("UpdateSubmodule() error = %v, want checkout failure", err)
+	}
+}
@@ -24,6 +24,49 @@ type WorkItemContext struct {
 	Go        GoOperations
 	Runner    CommandRunner
 	Logger    Logger
+	// Services starts the backing services the item declares (required only when it declares any)
+	Services ServiceManager
+	// Progress is called as the executor enters each phase (optional)
+	Progress ProgressFunc
+	// NeverTouch lists repositories the item must not change, from the
+	// manifest's defaults.never_touch (optional). An item for one fails before
+	// it is cloned.
+	NeverTouch []string
+	// Preview stops the item before it is committed and records the changes
+	// of its worktree on Result.Diff (optional). Git must implement
+	// DiffOperations.
+	Preview bool
+	// LocalTarget is a checkout of the target module the item's tests and
+	// extra commands build against, through a go.work outside the worktree,
+	// instead of the version go.mod requires (optional). The update itself and
+	// what is committed still name the target version.
+	LocalTarget string
+}
+
+// Phase names a stage of work item execution reported through ProgressFunc.
+type Phase string
+
+// Execution phases in the order the executor runs them.
+const (
+	PhaseClone         Phase = "clone"
+	PhaseWorktree      Phase = "worktree"
+	PhaseDependencies  Phase = "update-dependencies"
+	PhaseTidy          Phase = "tidy"
+	PhaseServices      Phase = "services"
+	PhaseTests         Phase = "tests"
+	PhaseExtraCommands Phase = "extra-commands"
+	PhaseCommit        Phase = "commit"
+	PhasePush          Phase = "push"
+)
+
+// ProgressFunc receives the phase a work item has entered.
+type ProgressFunc func(phase Phase)
+
+// report forwards phase to the progress callback, if one is set.
+func (w WorkItemContext) report(phase Phase) {
+	if w.Progress != nil {
+		w.Progress(phase)
+	}
 }
 
 // GitOperations defines the interface for git repository operations.
@@ -34,6 +77,43 @@ type GitOperations interface {
 	Push(ctx context.Context, repoPath, branch string) error
 }
 
+// SubmoduleOperations is implemented by GitOperations that can bump git submodules.
+// It is optional; the executor type-asserts it for items using the git-submodule
+// update strategy.
+type SubmoduleOperations interface {
+	// FindSubmodule returns the path of the submodule whose URL points at repo
+	// (host/owner/name).
+	FindSubmodule(ctx context.Context, repoPath, repo string) (string, error)
+
+	// UpdateSubmodule checks out ref in the submodule at path and stages the new
+	// pointer, returning the commits the submodule moved between.
+	UpdateSubmodule(ctx context.Context, repoPath, path, ref string) (SubmoduleUpdate, error)
+}
+
+// DiffOperations is implemented by GitOperations that can show uncommitted
+// changes. It is optional; the executor type-asserts it to preview items.
+type DiffOperations interface {
+	// Diff stages every change in the worktree at repoPath, including new
+	// files, and returns them as a unified diff against HEAD.
+	Diff(ctx context.Context, repoPath string) (string, error)
+}
+
+// RevertOperations is implemented by GitOperations that can undo commits. It is
+// optional; `cascade revert` type-asserts it to roll back merged updates.
+type RevertOperations interface {
+	// RevertCommit reverts commit on the branch checked out at repoPath and
+	// returns the new commit hash. Merge commits are reverted against their
+	// first parent. A conflicting revert is aborted and reported as an error.
+	RevertCommit(ctx context.Context, repoPath, commit string) (string, error)
+}
+
+// SubmoduleUpdate describes a bumped submodule pointer.
+type SubmoduleUpdate struct {
+	Path      string
+	OldCommit string
+	NewCommit string
+}
+
 // GoOperations defines the interface for Go module operations.
 type GoOperations interface {
 	Get(ctx context.Context, repoPath, module, version string) error
@@ -60,18 +140,24 @@ type Result struct {
 	TestResults      []CommandResult
 	ExtraResults     []CommandResult
 	DependencyImpact *DependencyImpact
+	// EffectiveItem is set when the dependent's own .cascade.yaml changed the work item
+	// at execution time; downstream consumers should prefer it over the planned item.
+	EffectiveItem *planner.WorkItem
+	// Diff holds the uncommitted changes of a previewed item.
+	Diff string
 }
 
-// DependencyImpact captures how a dependency update affected go.mod.
+// DependencyImpact captures how a dependency update affected go.mod. It is
+// stored with the item state, so its JSON form is read by external tooling.
 type DependencyImpact struct {
-	Module             string
-	TargetVersion      string
-	OldVersion         string
-	NewVersion         string
-	OldVersionDetected bool
-	NewVersionDetected bool
-	Applied            bool
-	Notes              []string
+	Module             string   `json:"module"`
+	TargetVersion      string   `json:"target_version"`
+	OldVersion         string   `json:"old_version"`
+	NewVersion         string   `json:"new_version"`
+	OldVersionDetected bool     `json:"old_version_detected"`
+	NewVersionDetected bool     `json:"new_version_detected"`
+	Applied            bool     `json:"applied"`
+	Notes              []string `json:"notes,omitempty"`
 }
 
 // CommandResult represents the outcome of executing a single command.
@@ -124,6 +210,10 @@ const (
 	StatusManualReview Status = "manual-review"
 	StatusFailed       Status = "failed"
 	StatusSkipped      Status = "skipped"
+
+	// StatusNoChange marks an update that left the dependent's go.mod and
+	// go.sum byte for byte unchanged, so there was nothing to commit.
+	StatusNoChange Status = "no-change"
 )
 
 // NotImplementedError is returned by stub implementations.
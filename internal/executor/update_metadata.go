@@ -0,0 +1,148 @@
+package executor
+
+import (
+	"strings"
+
+	"github.com/goliatone/cascade/internal/planner"
+	"golang.org/x/mod/semver"
+)
+
+// DefaultCommitPrefix is the semantic commit prefix of Dependabot-style commit
+// messages and PR titles.
+const DefaultCommitPrefix = "chore(deps)"
+
+// Update types, as Dependabot reports them in its metadata block.
+const (
+	UpdateTypeMajor = "version-update:semver-major"
+	UpdateTypeMinor = "version-update:semver-minor"
+	UpdateTypePatch = "version-update:semver-patch"
+)
+
+// DependencyUpdate is one module bump described the way Dependabot describes the
+// updates in its commit messages and pull requests.
+type DependencyUpdate struct {
+	// Name is the module path.
+	Name string
+
+	// From is the version the dependent required before the update, empty when
+	// it is unknown.
+	From string
+
+	// To is the version the dependent requires after the update.
+	To string
+
+	// UpdateType is UpdateTypeMajor, UpdateTypeMinor, or UpdateTypePatch, and
+	// empty when From is unknown or either version is not semantic.
+	UpdateType string
+}
+
+// DependencyUpdates lists the modules item bumps. The previous version is only
+// known for the module impact describes, which is the item's source module.
+func DependencyUpdates(item planner.WorkItem, impact *DependencyImpact) []DependencyUpdate {
+	modules := item.ModuleUpdates()
+	updates := make([]DependencyUpdate, 0, len(modules))
+	for _, module := range modules {
+		update := DependencyUpdate{Name: module.Module, To: module.Version}
+		if impact != nil && impact.Module == module.Module {
+			if impact.OldVersionDetected {
+				update.From = impact.OldVersion
+			}
+			if impact.NewVersionDetected && impact.NewVersion != "" {
+				update.To = impact.NewVersion
+			}
+		}
+		update.UpdateType = updateType(update.From, update.To)
+		updates = append(updates, update)
+	}
+	return updates
+}
+
+// updateType classifies the bump from one version to another.
+func updateType(from, to string) string {
+	if !semver.IsValid(from) || !semver.IsValid(to) {
+		return ""
+	}
+	switch {
+	case semver.Major(from) != semver.Major(to):
+		return UpdateTypeMajor
+	case semver.MajorMinor(from) != semver.MajorMinor(to):
+		return UpdateTypeMinor
+	default:
+		return UpdateTypePatch
+	}
+}
+
+// DependabotSubject returns the subject line Dependabot would write for updates,
+// such as "chore(deps): bump github.com/acme/lib from v1.2.0 to v1.3.0". An empty
+// prefix yields "Bump ...".
+func DependabotSubject(prefix string, updates []DependencyUpdate) string {
+	verb := "Bump"
+	if prefix = strings.TrimSpace(prefix); prefix != "" {
+		verb = prefix + ": bump"
+	}
+	switch len(updates) {
+	case 0:
+		return verb + " dependencies"
+	case 1:
+		return verb + " " + describeUpdate(updates[0])
+	}
+
+	names := make([]string, 0, len(updates))
+	for _, update := range updates {
+		names = append(names, update.Name)
+	}
+	return verb + " " + strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1]
+}
+
+// describeUpdate renders an update as "module from old to new", leaving out the
+// old version when it is unknown.
+func describeUpdate(update DependencyUpdate) string {
+	if update.From == "" {
+		return update.Name + " to " + update.To
+	}
+	return update.Name + " from " + update.From + " to " + update.To
+}
+
+// DependabotSummary lists updates as the "Bumps ..." lines Dependabot opens its
+// commit message bodies and pull requests with.
+func DependabotSummary(updates []DependencyUpdate) string {
+	var b strings.Builder
+	for _, update := range updates {
+		b.WriteString("Bumps " + describeUpdate(update) + ".\n")
+	}
+	return b.String()
+}
+
+// UpdateMetadata renders the YAML metadata block Dependabot appends to its
+// commit messages, which tools such as dependabot/fetch-metadata parse:
+//
+//	---
+//	updated-dependencies:
+//	- dependency-name: github.com/acme/lib
+//	  dependency-version: v1.3.0
+//	  dependency-type: direct:production
+//	  update-type: version-update:semver-minor
+//	...
+//
+// The update-type line is left out when it is unknown.
+func UpdateMetadata(updates []DependencyUpdate) string {
+	var b strings.Builder
+	b.WriteString("---\nupdated-dependencies:\n")
+	for _, update := range updates {
+		b.WriteString("- dependency-name: " + update.Name + "\n")
+		b.WriteString("  dependency-version: " + update.To + "\n")
+		b.WriteString("  dependency-type: direct:production\n")
+		if update.UpdateType != "" {
+			b.WriteString("  update-type: " + update.UpdateType + "\n")
+		}
+	}
+	b.WriteString("...\n")
+	return b.String()
+}
+
+// DependabotCommitMessage renders a commit message for item in Dependabot's
+// format: a semantic subject, the "Bumps ..." summary, and the metadata block.
+func DependabotCommitMessage(prefix string, item planner.WorkItem, impact *DependencyImpact) string {
+	updates := DependencyUpdates(item, impact)
+	return DependabotSubject(prefix, updates) + "\n\n" + DependabotSummary(updates) + "\n" + UpdateMetadata(updates)
+}
@@ -0,0 +1,66 @@
+package executor
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/goliatone/cascade/internal/planner"
+)
+
+func TestDependencyUpdates(t *testing.T) {
+	item := planner.WorkItem{
+		SourceModule:  "github.com/acme/lib-a",
+		SourceVersion: "v1.3.0",
+		Updates: []planner.ModuleUpdate{
+			{Module: "github.com/acme/lib-a", Version: "v1.3.0"},
+			{Module: "github.com/acme/lib-b", Version: "v2.0.0"},
+		},
+	}
+	impact := &DependencyImpact{
+		Module:             "github.com/acme/lib-a",
+		OldVersion:         "v1.2.4",
+		OldVersionDetected: true,
+		NewVersion:         "v1.3.0",
+		NewVersionDetected: true,
+	}
+
+	want := []DependencyUpdate{
+		{Name: "github.com/acme/lib-a", From: "v1.2.4", To: "v1.3.0", UpdateType: UpdateTypeMinor},
+		{Name: "github.com/acme/lib-b", To: "v2.0.0"},
+	}
+	updates := DependencyUpdates(item, impact)
+	if !reflect.DeepEqual(updates, want) {
+		t.Fatalf("DependencyUpdates = %+v, want %+v", updates, want)
+	}
+
+	if got := DependabotSubject("chore(deps)", updates); got != "chore(deps): bump github.com/acme/lib-a and github.com/acme/lib-b" {
+		t.Errorf("subject = %q", got)
+	}
+	if got := DependabotSubject("", updates[:1]); got != "Bump github.com/acme/lib-a from v1.2.4 to v1.3.0" {
+		t.Errorf("subject without prefix = %q", got)
+	}
+
+	metadata := UpdateMetadata(updates)
+	if !strings.HasPrefix(metadata, "---\nupdated-dependencies:\n- dependency-name: github.com/acme/lib-a\n") || !strings.HasSuffix(metadata, "...\n") {
+		t.Fatalf("unexpected metadata block:\n%s", metadata)
+	}
+	if strings.Count(metadata, "update-type:") != 1 {
+		t.Fatalf("expected an update type only for the update with a known old version:\n%s", metadata)
+	}
+}
+
+func TestUpdateType(t *testing.T) {
+	for _, tt := range []struct{ from, to, want string }{
+		{"v1.2.3", "v2.0.0", UpdateTypeMajor},
+		{"v1.2.3", "v1.3.0", UpdateTypeMinor},
+		{"v1.2.3", "v1.2.4", UpdateTypePatch},
+		{"v1.2.3", "v1.2.4-rc.1", UpdateTypePatch},
+		{"", "v1.2.4", ""},
+		{"../lib", "v1.2.4", ""},
+	} {
+		if got := updateType(tt.from, tt.to); got != tt.want {
+			t.Errorf("updateType(%q, %q) = %q, want %q", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package manifest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/mod/semver"
+)
+
+// Built-in version bump strategies, usable as a dependent's bump_strategy.
+const (
+	// BumpStrategyPin requires exactly the released version. It is the default.
+	BumpStrategyPin = "pin"
+
+	// BumpStrategyTilde requires the latest patch release of the released
+	// version's minor version, like a ~v1.2.3 range.
+	BumpStrategyTilde = "tilde"
+
+	// BumpStrategyMinorRange requires the latest minor or patch release of the
+	// released version's major version, like a ^v1.2.3 range.
+	BumpStrategyMinorRange = "minor-range"
+
+	// BumpStrategyLatestCompatible requires the released version and upgrades
+	// the modules it depends on to their latest minor or patch releases.
+	BumpStrategyLatestCompatible = "latest-compatible"
+)
+
+// GoGetQuery is the go get invocation that bumps a dependency.
+type GoGetQuery struct {
+	// Version is the version query after the module path, such as v1.2.3 or v1.2.
+	Version string
+
+	// Flags are passed to go get before the module, such as -u.
+	Flags []string
+}
+
+// BumpStrategy turns the released version of module into the go get query that
+// requires it in a dependent's go.mod.
+type BumpStrategy func(module, version string) GoGetQuery
+
+var bumpStrategies = struct {
+	sync.RWMutex
+	strategies map[string]BumpStrategy
+}{
+	strategies: map[string]BumpStrategy{
+		BumpStrategyPin:              pinBump,
+		BumpStrategyTilde:            tildeBump,
+		BumpStrategyMinorRange:       minorRangeBump,
+		BumpStrategyLatestCompatible: latestCompatibleBump,
+	},
+}
+
+// RegisterBumpStrategy makes a custom strategy available to dependents as
+// bump_strategy name. Register strategies from an init function, before
+// manifests are validated. Names cannot be registered twice, so built-in
+// strategies cannot be replaced.
+func RegisterBumpStrategy(name string, strategy BumpStrategy) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("bump strategy name cannot be empty")
+	}
+	if strategy == nil {
+		return fmt.Errorf("bump strategy %s needs a function", name)
+	}
+
+	bumpStrategies.Lock()
+	defer bumpStrategies.Unlock()
+	if _, exists := bumpStrategies.strategies[name]; exists {
+		return fmt.Errorf("bump strategy %s is already registered", name)
+	}
+	bumpStrategies.strategies[name] = strategy
+	return nil
+}
+
+// BumpStrategyNames returns the registered strategy names in sorted order.
+func BumpStrategyNames() []string {
+	bumpStrategies.RLock()
+	defer bumpStrategies.RUnlock()
+	names := make([]string, 0, len(bumpStrategies.strategies))
+	for name := range bumpStrategies.strategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LookupBumpStrategy returns the strategy registered as name. An empty name
+// selects BumpStrategyPin.
+func LookupBumpStrategy(name string) (BumpStrategy, bool) {
+	if name = strings.TrimSpace(name); name == "" {
+		name = BumpStrategyPin
+	}
+	bumpStrategies.RLock()
+	defer bumpStrategies.RUnlock()
+	strategy, ok := bumpStrategies.strategies[name]
+	return strategy, ok
+}
+
+func pinBump(_, version string) GoGetQuery {
+	return GoGetQuery{Version: version}
+}
+
+// tildeBump queries the version's major.minor prefix, which go get resolves to
+// the latest patch release. Pre-releases and non-semver versions stay pinned,
+// since a prefix query would skip them.
+func tildeBump(_, version string) GoGetQuery {
+	if !isRelease(version) {
+		return GoGetQuery{Version: version}
+	}
+	return GoGetQuery{Version: semver.MajorMinor(version)}
+}
+
+// minorRangeBump queries the version's major prefix, which go get resolves to
+// the latest minor or patch release.
+func minorRangeBump(_, version string) GoGetQuery {
+	if !isRelease(version) {
+		return GoGetQuery{Version: version}
+	}
+	return GoGetQuery{Version: semver.Major(version)}
+}
+
+func latestCompatibleBump(_, version string) GoGetQuery {
+	return GoGetQuery{Version: version, Flags: []string{"-u"}}
+}
+
+// isRelease reports whether version is a semantic version without a
+// pre-release or build suffix.
+func isRelease(version string) bool {
+	return semver.IsValid(version) && semver.Prerelease(version) == "" && semver.Build(version) == ""
+}
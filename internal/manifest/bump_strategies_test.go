@@ -0,0 +1,74 @@
+package manifest
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBuiltInBumpStrategies(t *testing.T) {
+	tests := []struct {
+		strategy string
+		version  string
+		want     GoGetQuery
+	}{
+		{strategy: "", version: "v1.4.2", want: GoGetQuery{Version: "v1.4.2"}},
+		{strategy: BumpStrategyPin, version: "v1.4.2", want: GoGetQuery{Version: "v1.4.2"}},
+		{strategy: BumpStrategyTilde, version: "v1.4.2", want: GoGetQuery{Version: "v1.4"}},
+		{strategy: BumpStrategyTilde, version: "v1.5.0-rc.1", want: GoGetQuery{Version: "v1.5.0-rc.1"}},
+		{strategy: BumpStrategyMinorRange, version: "v2.3.0", want: GoGetQuery{Version: "v2"}},
+		{strategy: BumpStrategyMinorRange, version: "master", want: GoGetQuery{Version: "master"}},
+		{strategy: BumpStrategyLatestCompatible, version: "v1.4.2", want: GoGetQuery{Version: "v1.4.2", Flags: []string{"-u"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.strategy+"@"+tt.version, func(t *testing.T) {
+			strategy, ok := LookupBumpStrategy(tt.strategy)
+			if !ok {
+				t.Fatalf("strategy %q is not registered", tt.strategy)
+			}
+			if got := strategy("example.com/lib", tt.version); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("query = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegisterBumpStrategy(t *testing.T) {
+	name := "test-next-minor"
+	err := RegisterBumpStrategy(name, func(_, version string) GoGetQuery {
+		return GoGetQuery{Version: version + "-next"}
+	})
+	if err != nil {
+		t.Fatalf("RegisterBumpStrategy() error = %v", err)
+	}
+	if err := RegisterBumpStrategy(name, pinBump); err == nil {
+		t.Error("expected registering a name twice to fail")
+	}
+	if err := RegisterBumpStrategy(BumpStrategyPin, pinBump); err == nil {
+		t.Error("expected built-in strategies not to be replaceable")
+	}
+	if err := RegisterBumpStrategy("no-func", nil); err == nil {
+		t.Error("expected a function to be required")
+	}
+
+	m := &Manifest{
+		ManifestVersion: 1,
+		Modules: []Module{{
+			Name:   "lib",
+			Module: "example.com/lib",
+			Repo:   "example/lib",
+			Dependents: []Dependent{
+				{Repo: "example/app", Module: "example.com/app", ModulePath: ".", BumpStrategy: name},
+			},
+		}},
+	}
+	if err := Validate(m); err != nil {
+		t.Fatalf("expected a registered strategy to validate, got %v", err)
+	}
+
+	m.Modules[0].Dependents[0].BumpStrategy = "caret"
+	if err := Validate(m); err == nil || !strings.Contains(err.Error(), "bump_strategy must be one of") {
+		t.Errorf("expected an unknown strategy to fail validation, got %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package manifest
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// Changelog formats.
+const (
+	// ChangelogKeepAChangelog adds an entry under the Unreleased section of a
+	// changelog file that follows keepachangelog.com.
+	ChangelogKeepAChangelog = "keep-a-changelog"
+
+	// ChangelogFragment writes a new file with the entry into a fragments
+	// directory, for tools that assemble changelogs from fragments.
+	ChangelogFragment = "fragment"
+
+	// ChangelogConventional adds the entry as a Changelog footer of the commit
+	// message, for tools that build changelogs from Conventional Commits.
+	ChangelogConventional = "conventional"
+)
+
+// Changelog describes the changelog entry written into a dependent with each
+// update. Every bumped module gets one entry.
+type Changelog struct {
+	// Format is keep-a-changelog, fragment, or conventional. Empty writes no
+	// entry.
+	Format string `yaml:"format,omitempty"`
+
+	// File is the changelog keep-a-changelog edits, relative to the repository
+	// root. It is created when missing. Default: CHANGELOG.md
+	File string `yaml:"file,omitempty"`
+
+	// Section is the heading under Unreleased that keep-a-changelog adds entries
+	// to. Default: Changed
+	Section string `yaml:"section,omitempty"`
+
+	// Dir is the directory fragment writes files into, relative to the
+	// repository root. Default: .changes
+	Dir string `yaml:"dir,omitempty"`
+
+	// Template is the Go template of one entry, executed with the bumped
+	// module's Name, From, To, and UpdateType. From is empty when the previous
+	// version is unknown.
+	// Default: Bump {{.Name}}{{if .From}} from {{.From}}{{end}} to {{.To}}
+	Template string `yaml:"template,omitempty"`
+}
+
+// IsZero reports whether the changelog sets nothing.
+func (c Changelog) IsZero() bool {
+	return c == Changelog{}
+}
+
+// mergeChangelog fills the fields dependent leaves unset from defaults.
+func mergeChangelog(defaults, dependent Changelog) Changelog {
+	result := dependent
+	if result.Format == "" {
+		result.Format = defaults.Format
+	}
+	if result.File == "" {
+		result.File = defaults.File
+	}
+	if result.Section == "" {
+		result.Section = defaults.Section
+	}
+	if result.Dir == "" {
+		result.Dir = defaults.Dir
+	}
+	if result.Template == "" {
+		result.Template = defaults.Template
+	}
+	return result
+}
+
+// lintChangelog reports an unknown format, paths that leave the repository,
+// and templates that do not parse, under field.
+func lintChangelog(field string, c Changelog) []string {
+	var issues []string
+	switch c.Format {
+	case "", ChangelogKeepAChangelog, ChangelogFragment, ChangelogConventional:
+	default:
+		issues = append(issues, fmt.Sprintf("%s.format must be one of %s, %s, %s (got %q)", field, ChangelogKeepAChangelog, ChangelogFragment, ChangelogConventional, c.Format))
+	}
+	for _, p := range []struct{ key, value string }{{"file", c.File}, {"dir", c.Dir}} {
+		if p.value == "" {
+			continue
+		}
+		clean := path.Clean(filepath.ToSlash(p.value))
+		if filepath.IsAbs(p.value) || path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") {
+			issues = append(issues, fmt.Sprintf("%s.%s must be a path inside the repository (got %q)", field, p.key, p.value))
+		}
+	}
+	if strings.ContainsAny(c.Section, "\r\n") {
+		issues = append(issues, fmt.Sprintf("%s.section cannot contain line breaks", field))
+	}
+	if c.Template != "" {
+		if _, err := template.New("changelog").Parse(c.Template); err != nil {
+			issues = append(issues, fmt.Sprintf("%s.template is invalid: %v", field, err))
+		}
+	}
+	return issues
+}
@@ -0,0 +1,56 @@
+package manifest
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Credential is a netrc entry for a private module host. The executor writes it to
+// a per work item netrc file and points NETRC and git at it, so only that
+// dependent's go commands can authenticate with it.
+type Credential struct {
+	// Machine is the host name the credential applies to, e.g. git.example.com.
+	Machine string `yaml:"machine"`
+	// Login is the user name sent with the password.
+	Login string `yaml:"login"`
+	// PasswordEnv names the environment variable holding the password or token,
+	// so secrets never live in the manifest.
+	PasswordEnv string `yaml:"password_env"`
+}
+
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// lintCredentials reports configuration problems in a credentials list under field.
+func lintCredentials(field string, credentials []Credential) []string {
+	var issues []string
+	seen := make(map[string]bool, len(credentials))
+	for i, cred := range credentials {
+		label := fmt.Sprintf("%s[%d]", field, i)
+		machine := strings.TrimSpace(cred.Machine)
+		switch {
+		case machine == "":
+			issues = append(issues, fmt.Sprintf("%s machine cannot be empty", label))
+		case strings.ContainsAny(machine, "/: \t"):
+			issues = append(issues, fmt.Sprintf("%s machine %q must be a bare host name", label, cred.Machine))
+		case seen[machine]:
+			issues = append(issues, fmt.Sprintf("%s duplicate machine %q", label, machine))
+		default:
+			seen[machine] = true
+		}
+
+		switch {
+		case strings.TrimSpace(cred.Login) == "":
+			issues = append(issues, fmt.Sprintf("%s (%s) login cannot be empty", label, machine))
+		case strings.ContainsAny(cred.Login, " \t\n"):
+			issues = append(issues, fmt.Sprintf("%s (%s) login cannot contain whitespace", label, machine))
+		}
+		switch {
+		case strings.TrimSpace(cred.PasswordEnv) == "":
+			issues = append(issues, fmt.Sprintf("%s (%s) password_env cannot be empty", label, machine))
+		case !envNamePattern.MatchString(cred.PasswordEnv):
+			issues = append(issues, fmt.Sprintf("%s (%s) password_env %q is not a valid environment variable name", label, machine, cred.PasswordEnv))
+		}
+	}
+	return issues
+}
@@ -0,0 +1,360 @@
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Diagnostic is a problem found in a manifest. Line and Column are 1-based
+// and zero when the problem has no position, such as a dependency cycle.
+type Diagnostic struct {
+	Source  string `json:"source,omitempty"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+	Path    string `json:"path,omitempty"`
+	Message string `json:"message"`
+}
+
+// String formats the diagnostic as "source:line:column: path: message".
+func (d Diagnostic) String() string {
+	var b strings.Builder
+	if d.Source != "" {
+		b.WriteString(d.Source)
+		if d.Line > 0 {
+			fmt.Fprintf(&b, ":%d:%d", d.Line, d.Column)
+		}
+		b.WriteString(": ")
+	}
+	if d.Path != "" {
+		b.WriteString(d.Path)
+		b.WriteString(": ")
+	}
+	b.WriteString(d.Message)
+	return b.String()
+}
+
+// Validator is implemented by loaders that report manifest problems as
+// diagnostics instead of a single error.
+type Validator interface {
+	// Validate checks a decoded manifest against the manifest rules.
+	Validate(m *Manifest) ([]Diagnostic, error)
+	// ValidateFile checks the manifest at path against the schema, then the
+	// manifest rules, with the position of each problem in the file.
+	ValidateFile(path string) ([]Diagnostic, error)
+}
+
+// Validate returns one diagnostic per problem Validate finds in m. The error
+// is reserved for manifests that cannot be checked.
+func (l *loader) Validate(m *Manifest) ([]Diagnostic, error) {
+	if m == nil {
+		return nil, errors.New("manifest: cannot validate a nil manifest")
+	}
+	return issueDiagnostics("", nil, Validate(m)), nil
+}
+
+// ValidateFile reads path and returns the diagnostics of ValidateSource.
+func (l *loader) ValidateFile(path string) ([]Diagnostic, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, &LoadError{Path: path, Err: err}
+	}
+	return ValidateSource(path, data), nil
+}
+
+// ValidateSource checks manifest content against ManifestSchema: YAML
+// syntax, unknown keys, type mismatches, invalid durations and enumerated
+// values, and missing required fields. Content that matches the schema is then
+// checked with Validate, and its issues are positioned at the module or
+// dependent they concern. source names the content in diagnostics.
+func ValidateSource(source string, data []byte) []Diagnostic {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return []Diagnostic{syntaxDiagnostic(source, err)}
+	}
+	if root.Kind == 0 || len(root.Content) == 0 {
+		return []Diagnostic{{Source: source, Message: "manifest is empty"}}
+	}
+	doc := root.Content[0]
+
+	w := &schemaWalker{source: source}
+	w.check(doc, ManifestSchema(), "")
+	if len(w.diagnostics) > 0 {
+		return w.diagnostics
+	}
+
+	m, err := parse(source, data)
+	if err != nil {
+		return []Diagnostic{{Source: source, Message: err.Error()}}
+	}
+	return issueDiagnostics(source, doc, Validate(m))
+}
+
+var syntaxLinePattern = regexp.MustCompile(`line (\d+): `)
+
+// syntaxDiagnostic converts a YAML syntax error into a diagnostic on its line.
+func syntaxDiagnostic(source string, err error) Diagnostic {
+	d := Diagnostic{Source: source, Message: strings.TrimPrefix(err.Error(), "yaml: ")}
+	if match := syntaxLinePattern.FindStringSubmatchIndex(d.Message); match != nil {
+		d.Line, _ = strconv.Atoi(d.Message[match[2]:match[3]])
+		d.Column = 1
+		d.Message = d.Message[:match[0]] + d.Message[match[1]:]
+	}
+	return d
+}
+
+// schemaWalker checks a YAML document against a schema, collecting diagnostics.
+type schemaWalker struct {
+	source      string
+	diagnostics []Diagnostic
+}
+
+func (w *schemaWalker) report(node *yaml.Node, path, format string, args ...any) {
+	w.diagnostics = append(w.diagnostics, Diagnostic{
+		Source:  w.source,
+		Line:    node.Line,
+		Column:  node.Column,
+		Path:    path,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (w *schemaWalker) check(node *yaml.Node, s *Schema, path string) {
+	if node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	// An empty value decodes to the zero value of any type
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+		return
+	}
+
+	if s.duration {
+		w.checkDuration(node, path)
+		return
+	}
+
+	switch s.Type {
+	case "object":
+		if node.Kind != yaml.MappingNode {
+			w.report(node, path, "expected a mapping, got %s", describeNode(node))
+			return
+		}
+		seen := make(map[string]bool)
+		w.checkMapping(node, s, path, seen)
+		for _, key := range s.Required {
+			if !seen[key] {
+				w.report(node, path, "missing required field %q", key)
+			}
+		}
+	case "array":
+		if node.Kind != yaml.SequenceNode {
+			w.report(node, path, "expected a list, got %s", describeNode(node))
+			return
+		}
+		for i, item := range node.Content {
+			w.check(item, s.Items, fmt.Sprintf("%s[%d]", path, i))
+		}
+	case "string":
+		if node.Kind != yaml.ScalarNode {
+			w.report(node, path, "expected a string, got %s", describeNode(node))
+			return
+		}
+		if len(s.Enum) > 0 && node.Value != "" && !slices.Contains(s.Enum, node.Value) {
+			w.report(node, path, "must be one of %s (got %q)", strings.Join(s.Enum, ", "), node.Value)
+		}
+	case "integer":
+		if node.Kind != yaml.ScalarNode || node.Tag != "!!int" {
+			w.report(node, path, "expected an integer, got %s", describeNode(node))
+		}
+	case "number":
+		if node.Kind != yaml.ScalarNode || (node.Tag != "!!int" && node.Tag != "!!float") {
+			w.report(node, path, "expected a number, got %s", describeNode(node))
+		}
+	case "boolean":
+		if node.Kind != yaml.ScalarNode || node.Tag != "!!bool" {
+			w.report(node, path, "expected true or false, got %s", describeNode(node))
+		}
+	}
+}
+
+// checkMapping checks the keys of node, including keys merged in with <<,
+// recording each key in seen.
+func (w *schemaWalker) checkMapping(node *yaml.Node, s *Schema, path string, seen map[string]bool) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if key.Value == "<<" && key.Tag == "!!merge" {
+			w.checkMerge(value, s, path, seen)
+			continue
+		}
+
+		fieldPath := key.Value
+		if path != "" {
+			fieldPath = path + "." + key.Value
+		}
+		prop := s.Properties[key.Value]
+		if prop == nil {
+			values, ok := s.AdditionalProperties.(*Schema)
+			if !ok {
+				w.report(key, path, "unknown field %q", key.Value)
+				continue
+			}
+			prop = values
+		}
+		seen[key.Value] = true
+		w.check(value, prop, fieldPath)
+	}
+}
+
+// checkMerge checks the mappings a << key merges into an object.
+func (w *schemaWalker) checkMerge(node *yaml.Node, s *Schema, path string, seen map[string]bool) {
+	if node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	switch node.Kind {
+	case yaml.MappingNode:
+		w.checkMapping(node, s, path, seen)
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			w.checkMerge(item, s, path, seen)
+		}
+	default:
+		w.report(node, path, "<< must merge a mapping, got %s", describeNode(node))
+	}
+}
+
+func (w *schemaWalker) checkDuration(node *yaml.Node, path string) {
+	if node.Kind != yaml.ScalarNode {
+		w.report(node, path, "expected a duration, got %s", describeNode(node))
+		return
+	}
+	if node.Tag == "!!int" {
+		return
+	}
+	if _, err := time.ParseDuration(node.Value); err != nil {
+		w.report(node, path, "invalid duration %q: use a value such as 30s, 5m, or 1h30m", node.Value)
+	}
+}
+
+// describeNode names the kind of value node holds, for type mismatch messages.
+func describeNode(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "a mapping"
+	case yaml.SequenceNode:
+		return "a list"
+	}
+	switch node.Tag {
+	case "!!int":
+		return fmt.Sprintf("integer %s", node.Value)
+	case "!!float":
+		return fmt.Sprintf("number %s", node.Value)
+	case "!!bool":
+		return fmt.Sprintf("boolean %s", node.Value)
+	}
+	return fmt.Sprintf("string %q", node.Value)
+}
+
+// issuePattern matches the module and dependent an issue from Validate names,
+// e.g. "module[0] (lib) dependent[2] (example/api) ...".
+var issuePattern = regexp.MustCompile(`^module\[(\d+)\](?: \([^)]*\))?(?: dependent\[(\d+)\])?`)
+
+// issueDiagnostics converts the issues of a Validate error into diagnostics.
+// When doc is set, issues about a module or dependent are positioned at its
+// entry, and issues about defaults at the field they name.
+func issueDiagnostics(source string, doc *yaml.Node, err error) []Diagnostic {
+	if err == nil {
+		return nil
+	}
+	issues, ok := GetValidationIssues(err)
+	if !ok {
+		return []Diagnostic{{Source: source, Message: err.Error()}}
+	}
+
+	diagnostics := make([]Diagnostic, 0, len(issues))
+	for _, issue := range issues {
+		d := Diagnostic{Source: source, Message: issue}
+		if doc != nil {
+			if node, path := locateIssue(doc, issue); node != nil {
+				d.Line, d.Column, d.Path = node.Line, node.Column, path
+			}
+		}
+		diagnostics = append(diagnostics, d)
+	}
+	return diagnostics
+}
+
+// locateIssue returns the node an issue from Validate concerns and its path.
+func locateIssue(doc *yaml.Node, issue string) (*yaml.Node, string) {
+	if match := issuePattern.FindStringSubmatch(issue); match != nil {
+		path := "modules[" + match[1] + "]"
+		if match[2] != "" {
+			path += ".dependents[" + match[2] + "]"
+		}
+		if node := lookupPath(doc, path); node != nil {
+			return node, path
+		}
+		return nil, ""
+	}
+
+	field, _, _ := strings.Cut(issue, " ")
+	if !strings.HasPrefix(field, "defaults.") && !strings.HasPrefix(field, "module.") {
+		return nil, ""
+	}
+	// Position at the deepest field of the path the document sets
+	elements := strings.Split(field, ".")
+	for n := len(elements); n > 0; n-- {
+		path := strings.Join(elements[:n], ".")
+		if node := lookupPath(doc, path); node != nil {
+			return node, path
+		}
+	}
+	return nil, ""
+}
+
+var pathElementPattern = regexp.MustCompile(`^([^\[]+)((?:\[\d+\])*)$`)
+
+// lookupPath returns the value node at a path such as "modules[0].dependents[1]".
+func lookupPath(node *yaml.Node, path string) *yaml.Node {
+	for _, element := range strings.Split(path, ".") {
+		match := pathElementPattern.FindStringSubmatch(element)
+		if match == nil {
+			return nil
+		}
+		if node = mappingValue(node, match[1]); node == nil {
+			return nil
+		}
+		for _, index := range strings.FieldsFunc(match[2], func(r rune) bool { return r == '[' || r == ']' }) {
+			i, _ := strconv.Atoi(index)
+			if node.Kind == yaml.AliasNode && node.Alias != nil {
+				node = node.Alias
+			}
+			if node.Kind != yaml.SequenceNode || i >= len(node.Content) {
+				return nil
+			}
+			node = node.Content[i]
+		}
+	}
+	return node
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package manifest_test
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/goliatone/cascade/internal/manifest"
+)
+
+const validSource = `manifest_version: 1
+defaults:
+  branch: main
+modules:
+  - name: lib
+    module: github.com/example/lib
+    repo: example/lib
+    dependents:
+      - repo: example/api
+        module: github.com/example/api
+        module_path: .
+        timeout: 10m
+`
+
+func TestValidateSource(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   []string
+	}{
+		{name: "valid", source: validSource},
+		{
+			name:   "unknown key",
+			source: strings.Replace(validSource, "        timeout: 10m", "        timout: 10m", 1),
+			want:   []string{`m.yaml:12:9: modules[0].dependents[0]: unknown field "timout"`},
+		},
+		{
+			name:   "invalid duration",
+			source: strings.Replace(validSource, "timeout: 10m", "timeout: 10 minutes", 1),
+			want:   []string{`m.yaml:12:18: modules[0].dependents[0].timeout: invalid duration "10 minutes": use a value such as 30s, 5m, or 1h30m`},
+		},
+		{
+			name:   "type mismatch",
+			source: validSource + "        tests: go test ./...\n        canary: maybe\n",
+			want: []string{
+				`m.yaml:13:16: modules[0].dependents[0].tests: expected a list, got string "go test ./..."`,
+				`m.yaml:14:17: modules[0].dependents[0].canary: expected true or false, got string "maybe"`,
+			},
+		},
+		{
+			name:   "missing required field",
+			source: strings.Replace(validSource, "        module_path: .\n", "", 1),
+			want:   []string{`m.yaml:9:9: modules[0].dependents[0]: missing required field "module_path"`},
+		},
+		{
+			name:   "enumerated value",
+			source: validSource + "        provider: gitea\n",
+			want:   []string{`m.yaml:13:19: modules[0].dependents[0].provider: must be one of github, gitlab, bitbucket, sandbox (got "gitea")`},
+		},
+		{
+			name:   "merge key",
+			source: "x-dependent: &dep\n  module_path: .\n" + strings.Replace(validSource, "        module_path: .\n", "        <<: *dep\n", 1),
+			want:   []string{`m.yaml:1:1: unknown field "x-dependent"`},
+		},
+		{
+			name:   "syntax error",
+			source: "manifest_version: 1\nmodules: name: lib\n",
+			want:   []string{"m.yaml:2:1: mapping values are not allowed in this context"},
+		},
+		{
+			name:   "rule violation",
+			source: strings.Replace(validSource, "repo: example/api", `repo: ""`, 1),
+			want:   []string{`m.yaml:9:9: modules[0].dependents[0]: module[0] (lib) dependent[0] repo cannot be empty`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, d := range manifest.ValidateSource("m.yaml", []byte(tt.source)) {
+				got = append(got, d.String())
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("ValidateSource() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestLoaderValidator(t *testing.T) {
+	validator, ok := manifest.NewLoader().(manifest.Validator)
+	if !ok {
+		t.Fatal("expected the loader to implement Validator")
+	}
+
+	diagnostics, err := validator.ValidateFile(filepath.Join("testdata", "basic.yaml"))
+	if err != nil || len(diagnostics) != 0 {
+		t.Fatalf("ValidateFile(basic.yaml) = %v, %v", diagnostics, err)
+	}
+
+	diagnostics, err = validator.ValidateFile(filepath.Join("testdata", "invalid_schema.yaml"))
+	if err != nil {
+		t.Fatalf("ValidateFile(invalid_schema.yaml) error = %v", err)
+	}
+	if len(diagnostics) == 0 || diagnostics[0].Line == 0 || diagnostics[0].Path == "" {
+		t.Errorf("expected positioned diagnostics, got %v", diagnostics)
+	}
+
+	m, err := manifest.NewLoader().Load(filepath.Join("testdata", "invalid_schema.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromManifest, err := validator.Validate(m)
+	if err != nil || len(fromManifest) != len(diagnostics) || fromManifest[0].Message != diagnostics[0].Message {
+		t.Errorf("Validate() = %v, %v, want the messages of ValidateFile", fromManifest, err)
+	}
+	if _, err := validator.Validate(nil); err == nil {
+		t.Error("expected an error validating a nil manifest")
+	}
+	if _, err := validator.ValidateFile(filepath.Join("testdata", "missing.yaml")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+func TestManifestSchema(t *testing.T) {
+	data, err := json.Marshal(manifest.ManifestSchema())
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	var schema struct {
+		Schema     string   `json:"$schema"`
+		Required   []string `json:"required"`
+		Properties map[string]struct {
+			Items struct {
+				Properties map[string]struct {
+					Items struct {
+						Required   []string `json:"required"`
+						Properties map[string]struct {
+							Enum  []string          `json:"enum"`
+							OneOf []json.RawMessage `json:"oneOf"`
+						} `json:"properties"`
+						AdditionalProperties bool `json:"additionalProperties"`
+					} `json:"items"`
+				} `json:"properties"`
+			} `json:"items"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	dependent := schema.Properties["modules"].Items.Properties["dependents"].Items
+	if schema.Schema != manifest.SchemaURI || strings.Join(schema.Required, ",") != "manifest_version,modules" {
+		t.Errorf("unexpected root schema: %s", data)
+	}
+	if strings.Join(dependent.Required, ",") != "repo,module,module_path" || dependent.AdditionalProperties {
+		t.Errorf("unexpected dependent schema: %+v", dependent)
+	}
+	if len(dependent.Properties["provider"].Enum) != 4 || len(dependent.Properties["timeout"].OneOf) != 2 {
+		t.Errorf("unexpected dependent properties: %+v", dependent.Properties)
+	}
+}
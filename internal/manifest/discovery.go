@@ -7,12 +7,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
-	"sort"
 	"strings"
 
+	"github.com/goliatone/cascade/internal/telemetry"
+	"github.com/goliatone/cascade/pkg/repourl"
 	"github.com/goliatone/cascade/pkg/util/modpath"
+	"github.com/goliatone/cascade/pkg/workspace/layout"
 	"golang.org/x/mod/semver"
 )
 
@@ -35,17 +36,34 @@ type DiscoveryOptions struct {
 	// TargetModule is the module path we're looking for dependents of
 	TargetModule string
 
+	// TargetAliases are historical import paths of TargetModule; modules requiring
+	// any of them are reported as dependents too
+	TargetAliases []string
+
 	// TargetVersion is the version we're updating to (optional - if set, filters out modules already at this version)
 	TargetVersion string
 
-	// MaxDepth limits how deep to scan in the directory tree (0 = no limit)
+	// MaxDepth limits how deep to scan in the directory tree (0 = no limit).
+	// Depth is counted from the repository directories of Layout
 	MaxDepth int
 
+	// Layout is how repositories are arranged in the workspace (zero value =
+	// directly in WorkspaceDir)
+	Layout layout.Layout
+
 	// IncludePatterns specifies directory patterns to include (empty = include all)
 	IncludePatterns []string
 
 	// ExcludePatterns specifies directory patterns to exclude
 	ExcludePatterns []string
+
+	// DetectTestCommands proposes each dependent's test command from its Taskfile,
+	// Makefile, or magefile instead of leaving it to the manifest defaults
+	DetectTestCommands bool
+
+	// ResolveVanityImports looks up the go-import meta tag of dependents with custom
+	// import paths (e.g., go.uber.org/zap) to find their real repository and clone URL
+	ResolveVanityImports bool
 }
 
 // DiscoveredModule represents a Go module found during workspace scanning.
@@ -53,6 +71,7 @@ type DiscoveredModule struct {
 	Path       string // File system path to the module
 	ModulePath string // Go module path from go.mod
 	Repository string // Inferred repository path
+	Workspace  string // go.work file whose use directives list the module, if any
 }
 
 // VersionResolutionOptions configures version resolution behavior.
@@ -95,6 +114,9 @@ type VersionResolution struct {
 	// SourcePath is the path where the version was found (for local resolutions)
 	SourcePath string
 
+	// Resolver names the resolver in the chain that produced the version
+	Resolver string
+
 	// Warnings contains any warnings generated during resolution
 	Warnings []string
 }
@@ -122,15 +144,41 @@ type moduleInfo struct {
 	Indirect bool        `json:"Indirect"`
 }
 
+// WorkspaceDiscoveryOption customises a workspace discovery instance.
+type WorkspaceDiscoveryOption func(*workspaceDiscovery)
+
+// WithWorkspaceRepoURLs builds the clone URLs of discovered dependents with
+// resolver instead of the built-in host defaults.
+func WithWorkspaceRepoURLs(resolver *repourl.Resolver) WorkspaceDiscoveryOption {
+	return func(w *workspaceDiscovery) {
+		w.urls = resolver
+	}
+}
+
 // NewWorkspaceDiscovery creates a new workspace discovery instance.
-func NewWorkspaceDiscovery() WorkspaceDiscovery {
-	return &workspaceDiscovery{}
+func NewWorkspaceDiscovery(opts ...WorkspaceDiscoveryOption) WorkspaceDiscovery {
+	w := &workspaceDiscovery{}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
 }
 
-type workspaceDiscovery struct{}
+type workspaceDiscovery struct {
+	vanity *modpath.VanityResolver
+	urls   *repourl.Resolver
+}
 
 // DiscoverDependents scans the workspace for Go modules that depend on the target module.
 func (w *workspaceDiscovery) DiscoverDependents(ctx context.Context, options DiscoveryOptions) ([]DependentOptions, error) {
+	ctx, span := telemetry.Start(ctx, "discovery.workspace", telemetry.String("module", options.TargetModule))
+	dependents, err := w.discoverDependents(ctx, options)
+	span.SetAttributes(telemetry.Int("dependents", len(dependents)))
+	span.End(err)
+	return dependents, err
+}
+
+func (w *workspaceDiscovery) discoverDependents(ctx context.Context, options DiscoveryOptions) ([]DependentOptions, error) {
 	if options.WorkspaceDir == "" {
 		return nil, fmt.Errorf("workspace directory is required")
 	}
@@ -146,23 +194,32 @@ func (w *workspaceDiscovery) DiscoverDependents(ctx context.Context, options Dis
 
 	var dependents []DependentOptions
 
+	targetPaths := ModulePaths(options.TargetModule, options.TargetAliases)
+
 	// Check each module for dependencies on the target
 	for _, module := range modules {
 		// Skip if this module IS the target module (prevent self-inclusion)
-		if module.ModulePath == options.TargetModule {
+		if containsString(targetPaths, module.ModulePath) {
 			continue
 		}
 
-		depends, err := w.moduleHasDependency(ctx, module.Path, options.TargetModule)
-		if err != nil {
-			// Log warning but continue with other modules
-			continue
+		depends, matchedPath := false, ""
+		for _, path := range targetPaths {
+			found, err := w.moduleHasDependency(ctx, module.Path, path)
+			if err != nil {
+				// Log warning but continue with other modules
+				break
+			}
+			if found {
+				depends, matchedPath = true, path
+				break
+			}
 		}
 
 		if depends {
 			// If target version is specified, check if module needs update
 			if options.TargetVersion != "" {
-				currentVersion := w.getDependencyVersion(ctx, module.Path, options.TargetModule)
+				currentVersion := w.getDependencyVersion(ctx, module.Path, matchedPath)
 				if currentVersion != "" {
 					// Normalize versions for comparison
 					normalizedCurrent := currentVersion
@@ -186,10 +243,23 @@ func (w *workspaceDiscovery) DiscoverDependents(ctx context.Context, options Dis
 			repository := w.inferRepository(module.ModulePath)
 			dependent := DependentOptions{
 				Repository:      repository,
-				CloneURL:        modpath.BuildCloneURL(repository),
+				CloneURL:        w.cloneURL(module.ModulePath, repository),
 				ModulePath:      module.ModulePath,
 				LocalModulePath: w.inferLocalModulePath(module.ModulePath),
 			}
+			if options.ResolveVanityImports && modpath.IsVanityPath(module.ModulePath) {
+				// Unresolvable paths keep the inferred values
+				if root, err := w.vanityResolver().Resolve(ctx, module.ModulePath); err == nil {
+					dependent.Repository = root.Repository()
+					dependent.CloneURL = root.RepoURL
+					dependent.LocalModulePath = root.LocalModulePath(module.ModulePath)
+				}
+			}
+			if options.DetectTestCommands {
+				if detected, ok := DetectTestCommand(DirFileReader(module.Path)); ok {
+					dependent.Tests = []Command{detected.Command}
+				}
+			}
 			dependents = append(dependents, dependent)
 		}
 	}
@@ -206,45 +276,30 @@ func (w *workspaceDiscovery) ResolveVersion(ctx context.Context, options Version
 		return nil, fmt.Errorf("workspace directory is required")
 	}
 
-	resolution := &VersionResolution{
-		Warnings: []string{},
-	}
-
+	var names []string
 	switch options.Strategy {
 	case VersionResolutionLocal:
-		return w.resolveLocalVersion(ctx, options.WorkspaceDir, options.TargetModule, resolution)
+		names = []string{VersionResolverLocal}
 	case VersionResolutionLatest:
 		if !options.AllowNetworkAccess {
 			return nil, fmt.Errorf("latest version resolution requires network access")
 		}
-		return w.resolveLatestVersion(ctx, options.TargetModule, resolution)
+		names = []string{VersionResolverProxy}
 	case VersionResolutionAuto:
-		// Try local first
-		localRes, localErr := w.resolveLocalVersion(ctx, options.WorkspaceDir, options.TargetModule, resolution)
-		if localErr == nil && localRes.Version != "" {
-			return localRes, nil
-		}
-
-		// Add warning about local resolution failure
-		if localErr != nil {
-			resolution.Warnings = append(resolution.Warnings, fmt.Sprintf("Local resolution failed: %v", localErr))
-		}
-
-		// Try network if allowed
+		// Local first, then the module proxy when network access is allowed
+		names = []string{VersionResolverLocal}
 		if options.AllowNetworkAccess {
-			netRes, netErr := w.resolveLatestVersion(ctx, options.TargetModule, resolution)
-			if netErr == nil {
-				return netRes, nil
-			}
-			resolution.Warnings = append(resolution.Warnings, fmt.Sprintf("Network resolution failed: %v", netErr))
-		} else {
-			resolution.Warnings = append(resolution.Warnings, "Network access not allowed, cannot resolve latest version")
+			names = append(names, VersionResolverProxy)
 		}
-
-		return nil, fmt.Errorf("failed to resolve version using auto strategy")
 	default:
 		return nil, fmt.Errorf("unsupported version resolution strategy: %s", options.Strategy)
 	}
+
+	chain, err := NewVersionResolverChain(names, VersionResolverEnv{Workspace: w})
+	if err != nil {
+		return nil, err
+	}
+	return chain.ResolveVersion(ctx, VersionRequest{Module: options.TargetModule, WorkspaceDir: options.WorkspaceDir})
 }
 
 // resolveLocalVersion attempts to find the module version from local workspace modules.
@@ -275,48 +330,10 @@ func (w *workspaceDiscovery) resolveLocalVersion(ctx context.Context, workspaceD
 	return nil, fmt.Errorf("module %s not found in any workspace dependencies", targetModule)
 }
 
-// resolveLatestVersion attempts to get the latest version from the Go module proxy.
-func (w *workspaceDiscovery) resolveLatestVersion(ctx context.Context, targetModule string, resolution *VersionResolution) (*VersionResolution, error) {
-	// Use go list -m -versions to get available versions
-	cmd := exec.CommandContext(ctx, "go", "list", "-m", "-versions", targetModule)
-	output, err := cmd.Output()
-	if err != nil {
-		return nil, fmt.Errorf("failed to list module versions: %w", err)
-	}
-
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	if len(lines) == 0 {
-		return nil, fmt.Errorf("no version information available for module %s", targetModule)
-	}
-
-	// The first line should contain: module_path version1 version2 ...
-	parts := strings.Fields(lines[0])
-	if len(parts) < 2 {
-		return nil, fmt.Errorf("unexpected go list output format")
-	}
-
-	// Skip the module path and collect versions
-	versions := parts[1:]
-	if len(versions) == 0 {
-		return nil, fmt.Errorf("no versions available for module %s", targetModule)
-	}
-
-	// Sort versions using semver and get the latest
-	sort.Slice(versions, func(i, j int) bool {
-		return semver.Compare(versions[i], versions[j]) < 0
-	})
-
-	latestVersion := versions[len(versions)-1]
-	resolution.Version = latestVersion
-	resolution.Source = VersionSourceNetwork
-	return resolution, nil
-}
-
 // getModuleVersionFromPath extracts the version of a specific module from a Go module path.
 func (w *workspaceDiscovery) getModuleVersionFromPath(ctx context.Context, modulePath, targetModule string) (string, error) {
 	// Use go list -m -json to get module information
-	cmd := exec.CommandContext(ctx, "go", "list", "-m", "-json", "all")
-	cmd.Dir = modulePath
+	cmd := discoveryGoCommand(ctx, modulePath, "list", "-m", "-json", "all")
 
 	output, err := cmd.Output()
 	if err != nil {
@@ -341,9 +358,12 @@ func (w *workspaceDiscovery) getModuleVersionFromPath(ctx context.Context, modul
 	return "", nil // Module not found locally
 }
 
-// findGoModules discovers all Go modules within the workspace directory.
+// findGoModules discovers all Go modules within the workspace directory. A module
+// nested in another module's directory is left out, since it is usually part of
+// the outer repository, unless a go.work file lists it as a workspace module.
 func (w *workspaceDiscovery) findGoModules(ctx context.Context, options DiscoveryOptions) ([]DiscoveredModule, error) {
 	var allModules []DiscoveredModule
+	workspaceUses := make(map[string]string) // module directory -> go.work file
 
 	// First pass: find all go.mod files
 	err := filepath.Walk(options.WorkspaceDir, func(path string, info os.FileInfo, err error) error {
@@ -351,14 +371,14 @@ func (w *workspaceDiscovery) findGoModules(ctx context.Context, options Discover
 			return err
 		}
 
-		// Check depth limit
+		// Check depth limit, below the directories the layout adds
 		if options.MaxDepth > 0 {
 			rel, err := filepath.Rel(options.WorkspaceDir, path)
 			if err != nil {
 				return err
 			}
 			depth := strings.Count(rel, string(filepath.Separator))
-			if depth > options.MaxDepth {
+			if depth > options.MaxDepth+options.Layout.Depth()-1 {
 				if info.IsDir() {
 					return filepath.SkipDir
 				}
@@ -366,6 +386,16 @@ func (w *workspaceDiscovery) findGoModules(ctx context.Context, options Discover
 			}
 		}
 
+		// Record the modules go.work files use; unreadable ones are ignored
+		if !info.IsDir() && info.Name() == "go.work" && w.shouldIncludeDirectory(filepath.Dir(path), options) {
+			if uses, err := readGoWorkUses(path); err == nil {
+				for _, use := range uses {
+					workspaceUses[use] = path
+				}
+			}
+			return nil
+		}
+
 		// Skip if not a go.mod file
 		if !info.IsDir() && info.Name() == "go.mod" {
 			moduleDir := filepath.Dir(path)
@@ -392,9 +422,20 @@ func (w *workspaceDiscovery) findGoModules(ctx context.Context, options Discover
 		return nil, err
 	}
 
-	// Second pass: filter out modules that are subdirectories of other modules
+	for i := range allModules {
+		if dir, err := filepath.Abs(allModules[i].Path); err == nil {
+			allModules[i].Workspace = workspaceUses[dir]
+		}
+	}
+
+	// Second pass: filter out modules that are subdirectories of other modules,
+	// except workspace modules
 	var filteredModules []DiscoveredModule
 	for _, module := range allModules {
+		if module.Workspace != "" {
+			filteredModules = append(filteredModules, module)
+			continue
+		}
 		isSubmodule := false
 		for _, other := range allModules {
 			if module.Path != other.Path {
@@ -417,8 +458,7 @@ func (w *workspaceDiscovery) findGoModules(ctx context.Context, options Discover
 // moduleHasDependency checks if a Go module depends on the target module.
 func (w *workspaceDiscovery) moduleHasDependency(ctx context.Context, modulePath, targetModule string) (bool, error) {
 	// First try using go list to get module dependencies
-	cmd := exec.CommandContext(ctx, "go", "list", "-m", "all")
-	cmd.Dir = modulePath
+	cmd := discoveryGoCommand(ctx, modulePath, "list", "-m", "all")
 
 	output, err := cmd.Output()
 	if err != nil {
@@ -484,8 +524,7 @@ func (w *workspaceDiscovery) parseGoModForDependency(modulePath, targetModule st
 // Returns empty string if the dependency is not found or on error.
 func (w *workspaceDiscovery) getDependencyVersion(ctx context.Context, modulePath, targetModule string) string {
 	// Try using go list first for accurate version info (handles replace directives)
-	cmd := exec.CommandContext(ctx, "go", "list", "-m", "-f", "{{.Version}}", targetModule)
-	cmd.Dir = modulePath
+	cmd := discoveryGoCommand(ctx, modulePath, "list", "-m", "-f", "{{.Version}}", targetModule)
 
 	output, err := cmd.Output()
 	if err == nil {
@@ -646,6 +685,33 @@ func (w *workspaceDiscovery) inferRepository(modulePath string) string {
 	return modpath.DeriveRepository(modulePath)
 }
 
+// cloneURL builds the clone URL of a dependent. Module paths that name their
+// host are preferred over the inferred repository, so the configured default
+// host does not capture github.com modules.
+func (w *workspaceDiscovery) cloneURL(modulePath, repository string) string {
+	urls := w.urls
+	if urls == nil {
+		urls = repourl.New()
+	}
+	source := repository
+	if strings.Count(modulePath, "/") >= 2 {
+		source = modulePath
+	}
+	cloneURL, err := urls.CloneURL(source)
+	if err != nil {
+		return repository
+	}
+	return cloneURL
+}
+
+// vanityResolver returns the resolver used for custom import paths, creating it on first use.
+func (w *workspaceDiscovery) vanityResolver() *modpath.VanityResolver {
+	if w.vanity == nil {
+		w.vanity = modpath.NewVanityResolver(nil)
+	}
+	return w.vanity
+}
+
 // inferLocalModulePath calculates the relative path from repository root to module
 func (w *workspaceDiscovery) inferLocalModulePath(modulePath string) string {
 	return modpath.DeriveLocalModulePath(modulePath)
@@ -3,16 +3,28 @@ package manifest
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+
+	"github.com/goliatone/cascade/pkg/repourl"
+	"github.com/goliatone/cascade/pkg/util/modpath"
+	"github.com/goliatone/cascade/pkg/workspace/layout"
 )
 
 func TestWorkspaceDiscovery_DiscoverDependents(t *testing.T) {
 	discovery := NewWorkspaceDiscovery()
 	testdataDir := filepath.Join("testdata", "workspace-discovery")
+	ownerLayout, err := layout.Parse("{{owner}}/{{repo}}")
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	tests := []struct {
 		name          string
@@ -74,6 +86,18 @@ func TestWorkspaceDiscovery_DiscoverDependents(t *testing.T) {
 			expectedRepos: []string{"example/module-a", "example/module-b"},
 			expectError:   false,
 		},
+		{
+			name: "counts max depth from layout repositories",
+			options: DiscoveryOptions{
+				WorkspaceDir: testdataDir,
+				TargetModule: "github.com/target/module",
+				MaxDepth:     1,
+				Layout:       ownerLayout,
+			},
+			expectedCount: 3, // the owner level of the layout adds one level
+			expectedRepos: []string{"example/module-a", "example/module-b", "example/module-d"},
+			expectError:   false,
+		},
 		{
 			name: "returns error for missing workspace",
 			options: DiscoveryOptions{
@@ -293,6 +317,109 @@ require github.com/target/module v1.0.0
 	}
 }
 
+func TestWorkspaceDiscovery_DiscoverDependents_MatchesAliases(t *testing.T) {
+	discovery := NewWorkspaceDiscovery()
+	workspaceDir := t.TempDir()
+
+	goMods := map[string]string{
+		"renamed": "module github.com/example/renamed\n\ngo 1.21\n\nrequire github.com/target/module v1.0.0\n",
+		"legacy":  "module github.com/example/legacy\n\ngo 1.21\n\nrequire github.com/target/old-module v0.9.0\n",
+		"old":     "module github.com/target/old-module\n\ngo 1.21\n",
+	}
+	for dir, content := range goMods {
+		if err := os.MkdirAll(filepath.Join(workspaceDir, dir), 0o755); err != nil {
+			t.Fatalf("failed to create %s dir: %v", dir, err)
+		}
+		if err := os.WriteFile(filepath.Join(workspaceDir, dir, "go.mod"), []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write %s go.mod: %v", dir, err)
+		}
+	}
+
+	opts := DiscoveryOptions{
+		WorkspaceDir:  workspaceDir,
+		TargetModule:  "github.com/target/module",
+		TargetAliases: []string{"github.com/target/old-module"},
+	}
+
+	dependents, err := discovery.DiscoverDependents(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := make(map[string]bool)
+	for _, dep := range dependents {
+		got[dep.Repository] = true
+	}
+	want := map[string]bool{"example/renamed": true, "example/legacy": true}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("dependents = %v, want %v", got, want)
+	}
+}
+
+func TestWorkspaceDiscovery_DiscoverDependents_ResolvesVanityImports(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<html><head><meta name="go-import" content="%s/tools git https://github.com/example/tools"></head></html>`, r.Host)
+	}))
+	defer server.Close()
+	vanityPath := strings.TrimPrefix(server.URL, "https://") + "/tools/cmd/lint"
+
+	workspaceDir := t.TempDir()
+	dependentDir := filepath.Join(workspaceDir, "lint")
+	if err := os.MkdirAll(dependentDir, 0o755); err != nil {
+		t.Fatalf("failed to create dependent dir: %v", err)
+	}
+	goMod := "module " + vanityPath + "\n\ngo 1.21\n\nrequire github.com/target/module v1.0.0\n"
+	if err := os.WriteFile(filepath.Join(dependentDir, "go.mod"), []byte(goMod), 0o644); err != nil {
+		t.Fatalf("failed to write dependent go.mod: %v", err)
+	}
+
+	discovery := &workspaceDiscovery{vanity: modpath.NewVanityResolver(server.Client())}
+	opts := DiscoveryOptions{WorkspaceDir: workspaceDir, TargetModule: "github.com/target/module"}
+
+	dependents, err := discovery.DiscoverDependents(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(dependents) != 1 || dependents[0].Repository != vanityPath {
+		t.Fatalf("expected raw path without resolution, got %+v", dependents)
+	}
+
+	opts.ResolveVanityImports = true
+	dependents, err = discovery.DiscoverDependents(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(dependents) != 1 {
+		t.Fatalf("expected 1 dependent, got %d", len(dependents))
+	}
+	got := dependents[0]
+	if got.Repository != "example/tools" || got.CloneURL != "https://github.com/example/tools" || got.LocalModulePath != "cmd/lint" || got.ModulePath != vanityPath {
+		t.Fatalf("unexpected resolved dependent: %+v", got)
+	}
+}
+
+func TestWorkspaceDiscovery_cloneURL(t *testing.T) {
+	urls := repourl.New(
+		repourl.WithDefaultHost("github.example.com"),
+		repourl.WithHosts(repourl.Host{Name: "github.example.com", Protocol: repourl.ProtocolSSH}),
+	)
+	wd := NewWorkspaceDiscovery(WithWorkspaceRepoURLs(urls)).(*workspaceDiscovery)
+
+	tests := []struct {
+		modulePath string
+		want       string
+	}{
+		{modulePath: "github.example.com/team/app/v2", want: "git@github.example.com:team/app.git"},
+		{modulePath: "github.com/goliatone/go-errors", want: "https://github.com/goliatone/go-errors.git"},
+		{modulePath: "gitlab.com/group/sub/project", want: "https://gitlab.com/group/sub/project.git"},
+	}
+	for _, tt := range tests {
+		if got := wd.cloneURL(tt.modulePath, wd.inferRepository(tt.modulePath)); got != tt.want {
+			t.Errorf("cloneURL(%q) = %q, want %q", tt.modulePath, got, tt.want)
+		}
+	}
+}
+
 func TestWorkspaceDiscovery_extractModulePath(t *testing.T) {
 	wd := &workspaceDiscovery{}
 
@@ -1103,3 +1230,40 @@ require (
 		})
 	}
 }
+
+func TestWorkspaceDiscovery_DiscoverDependents_GoWorkModules(t *testing.T) {
+	discovery := NewWorkspaceDiscovery()
+	workspaceDir := t.TempDir()
+
+	files := map[string]string{
+		"mono/go.work":           "go 1.21\n\nuse (\n\t.\n\t./svc\n\t./tools\n)\n",
+		"mono/go.mod":            "module github.com/acme/mono\n\ngo 1.21\n",
+		"mono/svc/go.mod":        "module github.com/acme/mono/svc\n\ngo 1.21\n\nrequire github.com/target/module v1.0.0\n",
+		"mono/tools/go.mod":      "module github.com/acme/mono/tools\n\ngo 1.21\n",
+		"mono/internal/x/go.mod": "module github.com/acme/mono/internal/x\n\ngo 1.21\n\nrequire github.com/target/module v1.0.0\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(workspaceDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed to create %s: %v", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	dependents, err := discovery.DiscoverDependents(context.Background(), DiscoveryOptions{
+		WorkspaceDir: workspaceDir,
+		TargetModule: "github.com/target/module",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The workspace module requiring the target is found even though it is
+	// nested, the workspace root is not reported through it, and nested modules
+	// outside the workspace stay part of their repository.
+	if len(dependents) != 1 || dependents[0].ModulePath != "github.com/acme/mono/svc" {
+		t.Fatalf("expected only github.com/acme/mono/svc, got %+v", dependents)
+	}
+}
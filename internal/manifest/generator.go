@@ -15,11 +15,12 @@ type Generator interface {
 // GenerateOptions defines the configuration for manifest generation.
 type GenerateOptions struct {
 	// Module metadata
-	ModuleName      string // Human-friendly identifier (e.g., "go-errors")
-	ModulePath      string // Go module path (e.g., "github.com/goliatone/go-errors")
-	Repository      string // GitHub repository (e.g., "goliatone/go-errors")
-	Version         string // Target version (e.g., "v1.2.3")
-	ReleaseArtifact string // Optional release artifact path
+	ModuleName      string   // Human-friendly identifier (e.g., "go-errors")
+	ModulePath      string   // Go module path (e.g., "github.com/goliatone/go-errors")
+	Aliases         []string // Former module paths dependents may still require
+	Repository      string   // GitHub repository (e.g., "goliatone/go-errors")
+	Version         string   // Target version (e.g., "v1.2.3")
+	ReleaseArtifact string   // Optional release artifact path
 
 	// Dependent repositories
 	Dependents []DependentOptions
@@ -171,6 +172,7 @@ func (g *generator) buildModule(options GenerateOptions) Module {
 	module := Module{
 		Name:            options.ModuleName,
 		Module:          options.ModulePath,
+		Aliases:         NormalizeAliases(options.ModulePath, options.Aliases),
 		Repo:            options.Repository,
 		ReleaseArtifact: options.ReleaseArtifact,
 		Dependents:      g.buildDependents(options),
@@ -245,7 +247,10 @@ func isNotificationsEmpty(notifications Notifications) bool {
 		if notifications.GitHubIssues.Enabled {
 			githubIssuesConfigured = true
 		}
-		if len(notifications.GitHubIssues.Labels) > 0 {
+		if len(notifications.GitHubIssues.Labels) > 0 || len(notifications.GitHubIssues.Assignees) > 0 {
+			githubIssuesConfigured = true
+		}
+		if notifications.GitHubIssues.TitleTemplate != "" || notifications.GitHubIssues.BodyTemplate != "" || notifications.GitHubIssues.OnRecurrence != "" {
 			githubIssuesConfigured = true
 		}
 	}
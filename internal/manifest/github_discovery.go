@@ -10,6 +10,12 @@ import (
 	"strings"
 	"time"
 
+	"github.com/goliatone/cascade/internal/telemetry"
+	"github.com/goliatone/cascade/pkg/githubx"
+	"github.com/goliatone/cascade/pkg/gitutil"
+	"github.com/goliatone/cascade/pkg/goproxy"
+	"github.com/goliatone/cascade/pkg/repometa"
+	"github.com/goliatone/cascade/pkg/repourl"
 	"github.com/goliatone/cascade/pkg/util/modpath"
 	"github.com/google/go-github/v66/github"
 	"golang.org/x/mod/semver"
@@ -34,6 +40,9 @@ type GitHubDiscoveryOptions struct {
 	// TargetModule is the module path we're looking for dependents of
 	TargetModule string
 
+	// TargetAliases are historical import paths of TargetModule
+	TargetAliases []string
+
 	// IncludePatterns specifies repository name patterns to include (empty = include all)
 	IncludePatterns []string
 
@@ -45,6 +54,11 @@ type GitHubDiscoveryOptions struct {
 
 	// SearchQuery allows custom GitHub search query modifications
 	SearchQuery string
+
+	// Filter narrows discovery by repository topics, visibility, archived state,
+	// and last push. Its qualifiers are added to the search query and checked
+	// again against each result.
+	Filter RepoFilter
 }
 
 // GitHubVersionResolutionOptions configures GitHub-based version resolution.
@@ -101,11 +115,34 @@ type GitHubDiscoveredRepository struct {
 	Private       bool   // Whether the repository is private
 }
 
+// GitHubDiscoveryOption customises a GitHub discovery instance.
+type GitHubDiscoveryOption func(*gitHubDiscovery)
+
+// WithGitHubRepoMetadata records the repositories returned by searches in svc, so
+// later phases of the run read default branches and archived state from the cache.
+func WithGitHubRepoMetadata(svc *repometa.Service) GitHubDiscoveryOption {
+	return func(g *gitHubDiscovery) {
+		g.metadata = svc
+	}
+}
+
+// WithGitHubRepoURLs builds the clone URLs of discovered repositories with
+// resolver, whose default host should be the GitHub server being searched.
+func WithGitHubRepoURLs(resolver *repourl.Resolver) GitHubDiscoveryOption {
+	return func(g *gitHubDiscovery) {
+		g.urls = resolver
+	}
+}
+
 // NewGitHubDiscovery creates a new GitHub discovery instance.
-func NewGitHubDiscovery(client *github.Client) GitHubDiscovery {
-	return &gitHubDiscovery{
+func NewGitHubDiscovery(client *github.Client, opts ...GitHubDiscoveryOption) GitHubDiscovery {
+	g := &gitHubDiscovery{
 		client: client,
 	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
 }
 
 // GitHubAuthConfig holds authentication configuration options for GitHub discovery.
@@ -118,6 +155,9 @@ type GitHubAuthConfig struct {
 	UploadURL string
 	// InsecureSkipVerify skips TLS verification (for self-signed certificates)
 	InsecureSkipVerify bool
+	// RateLimit configures how rate limited requests are retried, such as the
+	// options di.GitHubRateLimitOptions builds from the configuration
+	RateLimit []githubx.Option
 }
 
 // NewGitHubDiscoveryFromToken creates a new GitHub discovery instance with authentication.
@@ -201,6 +241,9 @@ func createAuthenticatedClient(config GitHubAuthConfig) (*github.Client, error)
 		}
 	}
 
+	// Wait out rate limits instead of failing the discovery
+	httpClient = githubx.WrapClient(httpClient, config.RateLimit...)
+
 	var client *github.Client
 
 	// Create GitHub client with custom base URL if specified (GitHub Enterprise)
@@ -218,7 +261,22 @@ func createAuthenticatedClient(config GitHubAuthConfig) (*github.Client, error)
 }
 
 type gitHubDiscovery struct {
-	client *github.Client
+	client   *github.Client
+	metadata *repometa.Service
+	urls     *repourl.Resolver
+}
+
+// cloneURL builds the clone URL of an owner/repo on the searched server.
+func (g *gitHubDiscovery) cloneURL(fullName string) string {
+	urls := g.urls
+	if urls == nil {
+		urls = repourl.New()
+	}
+	cloneURL, err := urls.CloneURL(fullName)
+	if err != nil {
+		return fullName
+	}
+	return cloneURL
 }
 
 // ValidateAuthentication validates that the GitHub client can authenticate successfully.
@@ -309,30 +367,29 @@ func isRateLimitModerate(rate *github.Rate) bool {
 }
 
 // handleRateLimitError provides helpful error messages when rate limiting occurs.
-func (g *gitHubDiscovery) handleRateLimitError(ctx context.Context, err error) error {
-	// Check if this is a rate limit error
-	if strings.Contains(err.Error(), "rate limit") || strings.Contains(err.Error(), "403") {
-		// Try to get current rate limit info for better error message
-		if rateLimits, _, rateLimitErr := g.client.RateLimits(ctx); rateLimitErr == nil && rateLimits.Core != nil {
-			resetDuration := time.Until(rateLimits.Core.Reset.Time)
-			return fmt.Errorf("GitHub API rate limit exceeded: %d/%d requests used, resets in %v at %v. "+
-				"Consider using a personal access token for higher rate limits, or wait before retrying. Original error: %w",
-				rateLimits.Core.Limit-rateLimits.Core.Remaining,
-				rateLimits.Core.Limit,
-				resetDuration.Round(time.Minute),
-				rateLimits.Core.Reset.Time.Format("15:04:05 MST"),
-				err)
+// Rate limits short enough to wait out are retried by the client transport, so
+// the errors that reach here are limits that last longer than it waits.
+func (g *gitHubDiscovery) handleRateLimitError(err error) error {
+	if limit, ok := githubx.RateLimited(err); ok {
+		resetIn := time.Until(limit.Reset).Round(time.Second)
+		if limit.Kind == githubx.Secondary {
+			return fmt.Errorf("GitHub secondary rate limit exceeded, retry in %v. "+
+				"Too many requests were made in a short time; wait before retrying. Original error: %w",
+				resetIn, err)
 		}
-		return fmt.Errorf("GitHub API rate limit exceeded. Consider using a personal access token for higher rate limits, or wait before retrying. Original error: %w", err)
+		return fmt.Errorf("GitHub API rate limit exceeded: %d/%d requests used, resets in %v at %v. "+
+			"Consider using a personal access token for higher rate limits, or wait before retrying. Original error: %w",
+			limit.Limit-limit.Remaining,
+			limit.Limit,
+			resetIn,
+			limit.Reset.Format("15:04:05 MST"),
+			err)
 	}
 
-	// Check if this is an authentication error
-	if strings.Contains(err.Error(), "401") || strings.Contains(err.Error(), "Bad credentials") {
+	switch githubx.StatusCode(err) {
+	case http.StatusUnauthorized:
 		return fmt.Errorf("GitHub authentication failed: invalid or expired token. Please check your GITHUB_TOKEN environment variable. Original error: %w", err)
-	}
-
-	// Check if this is a permission error
-	if strings.Contains(err.Error(), "403") && !strings.Contains(err.Error(), "rate limit") {
+	case http.StatusForbidden:
 		return fmt.Errorf("GitHub API access denied: insufficient permissions or repository not accessible. Please ensure your token has appropriate permissions. Original error: %w", err)
 	}
 
@@ -342,6 +399,14 @@ func (g *gitHubDiscovery) handleRateLimitError(ctx context.Context, err error) e
 
 // DiscoverDependents searches GitHub for repositories that depend on the target module.
 func (g *gitHubDiscovery) DiscoverDependents(ctx context.Context, options GitHubDiscoveryOptions) ([]DependentOptions, error) {
+	ctx, span := telemetry.Start(ctx, "discovery.github", telemetry.String("org", options.Organization), telemetry.String("module", options.TargetModule))
+	dependents, err := g.discoverDependents(ctx, options)
+	span.SetAttributes(telemetry.Int("dependents", len(dependents)))
+	span.End(err)
+	return dependents, err
+}
+
+func (g *gitHubDiscovery) discoverDependents(ctx context.Context, options GitHubDiscoveryOptions) ([]DependentOptions, error) {
 	if options.Organization == "" {
 		return nil, fmt.Errorf("GitHub organization is required")
 	}
@@ -360,22 +425,23 @@ func (g *gitHubDiscovery) DiscoverDependents(ctx context.Context, options GitHub
 	}
 
 	var dependents []DependentOptions
+	targetPaths := ModulePaths(options.TargetModule, options.TargetAliases)
 
 	for _, repo := range repos {
 		// Check if the repository actually uses Go and has go.mod files
-		hasDependency, err := g.repositoryHasDependency(ctx, repo, options.TargetModule)
+		hasDependency, err := g.repositoryHasDependency(ctx, repo, targetPaths)
 		if err != nil {
 			// Log warning but continue with other repositories
 			continue
 		}
 
 		if hasDependency {
-			if repo.ModulePath == options.TargetModule {
+			if containsString(targetPaths, repo.ModulePath) {
 				continue
 			}
 			dependent := DependentOptions{
 				Repository:      repo.FullName,
-				CloneURL:        modpath.BuildCloneURL(repo.FullName),
+				CloneURL:        g.cloneURL(repo.FullName),
 				ModulePath:      repo.ModulePath,
 				LocalModulePath: modpath.DeriveLocalModulePath(repo.ModulePath),
 			}
@@ -400,30 +466,27 @@ func (g *gitHubDiscovery) ResolveVersion(ctx context.Context, options GitHubVers
 		return nil, fmt.Errorf("rate limit check failed: %w", err)
 	}
 
-	resolution := &VersionResolution{
-		Warnings: []string{},
-	}
-
+	var names []string
 	switch options.Strategy {
 	case GitHubVersionResolutionTags:
-		return g.resolveVersionFromTags(ctx, options.Repository, resolution)
+		names = []string{VersionResolverTags}
 	case GitHubVersionResolutionProxy:
-		// Try proxy first if requested
+		// The proxy is tried first when requested, falling back to tags
 		if options.UseProxy {
-			proxyResolution, err := g.resolveVersionFromProxy(ctx, options.TargetModule, resolution)
-			if err != nil {
-				// Proxy failed, fall back to tags
-				resolution.Warnings = append(resolution.Warnings, fmt.Sprintf("Go proxy resolution failed (%v), falling back to Git tags", err))
-				return g.resolveVersionFromTags(ctx, options.Repository, resolution)
-			}
-			return proxyResolution, nil
+			names = append(names, VersionResolverProxy)
 		}
-		return g.resolveVersionFromTags(ctx, options.Repository, resolution)
+		names = append(names, VersionResolverTags)
 	case GitHubVersionResolutionGitRemote:
-		return g.resolveVersionFromGitRemote(ctx, options.Repository, resolution)
+		names = []string{VersionResolverGitRemote}
 	default:
 		return nil, fmt.Errorf("unsupported GitHub version resolution strategy: %s", options.Strategy)
 	}
+
+	chain, err := NewVersionResolverChain(names, VersionResolverEnv{GitHub: g})
+	if err != nil {
+		return nil, err
+	}
+	return chain.ResolveVersion(ctx, VersionRequest{Module: options.TargetModule, Repository: options.Repository})
 }
 
 // searchRepositories searches for repositories in the GitHub organization.
@@ -445,10 +508,19 @@ func (g *gitHubDiscovery) searchRepositories(ctx context.Context, options GitHub
 	for {
 		result, resp, err := g.client.Search.Repositories(ctx, query, searchOpts)
 		if err != nil {
-			return nil, g.handleRateLimitError(ctx, fmt.Errorf("GitHub repository search failed: %w", err))
+			return nil, g.handleRateLimitError(fmt.Errorf("GitHub repository search failed: %w", err))
 		}
 
 		for _, repo := range result.Repositories {
+			meta := repometa.FromGitHubRepository(repo)
+			if g.metadata != nil {
+				g.metadata.Store(meta)
+			}
+			// Archived repositories cannot receive dependency updates unless the
+			// filter asks for them
+			if !options.Filter.Matches(meta) {
+				continue
+			}
 			if g.shouldIncludeRepository(repo, options) {
 				discoveredRepo := GitHubDiscoveredRepository{
 					Owner:         repo.GetOwner().GetLogin(),
@@ -493,6 +565,7 @@ func (g *gitHubDiscovery) buildSearchQuery(options GitHubDiscoveryOptions) strin
 
 	// Add organization filter
 	query = append(query, "org:"+options.Organization)
+	query = append(query, options.Filter.SearchQualifiers()...)
 
 	return strings.Join(query, " ")
 }
@@ -539,8 +612,8 @@ func (g *gitHubDiscovery) matchPattern(pattern, text string) bool {
 	return pattern == text
 }
 
-// repositoryHasDependency checks if a GitHub repository depends on the target module.
-func (g *gitHubDiscovery) repositoryHasDependency(ctx context.Context, repo GitHubDiscoveredRepository, targetModule string) (bool, error) {
+// repositoryHasDependency checks if a GitHub repository depends on any of the target module paths.
+func (g *gitHubDiscovery) repositoryHasDependency(ctx context.Context, repo GitHubDiscoveredRepository, targetPaths []string) (bool, error) {
 	// Search for go.mod files in the repository
 	query := fmt.Sprintf("filename:go.mod repo:%s", repo.FullName)
 
@@ -552,12 +625,12 @@ func (g *gitHubDiscovery) repositoryHasDependency(ctx context.Context, repo GitH
 
 	result, _, err := g.client.Search.Code(ctx, query, searchOpts)
 	if err != nil {
-		return false, g.handleRateLimitError(ctx, fmt.Errorf("failed to search for go.mod files in %s: %w", repo.FullName, err))
+		return false, g.handleRateLimitError(fmt.Errorf("failed to search for go.mod files in %s: %w", repo.FullName, err))
 	}
 
 	// Check each go.mod file for the target dependency
 	for _, codeResult := range result.CodeResults {
-		hasDep, err := g.checkGoModFileForDependency(ctx, repo, codeResult, targetModule)
+		hasDep, err := g.checkGoModFileForDependency(ctx, repo, codeResult, targetPaths)
 		if err != nil {
 			continue // Skip files we can't read
 		}
@@ -569,8 +642,8 @@ func (g *gitHubDiscovery) repositoryHasDependency(ctx context.Context, repo GitH
 	return false, nil
 }
 
-// checkGoModFileForDependency checks a specific go.mod file for the target dependency.
-func (g *gitHubDiscovery) checkGoModFileForDependency(ctx context.Context, repo GitHubDiscoveredRepository, codeResult *github.CodeResult, targetModule string) (bool, error) {
+// checkGoModFileForDependency checks a specific go.mod file for any of the target module paths.
+func (g *gitHubDiscovery) checkGoModFileForDependency(ctx context.Context, repo GitHubDiscoveredRepository, codeResult *github.CodeResult, targetPaths []string) (bool, error) {
 	// Get the content of the go.mod file
 	content, _, _, err := g.client.Repositories.GetContents(ctx, repo.Owner, repo.Name, codeResult.GetPath(), &github.RepositoryContentGetOptions{
 		Ref: repo.DefaultBranch,
@@ -589,8 +662,13 @@ func (g *gitHubDiscovery) checkGoModFileForDependency(ctx context.Context, repo
 		return false, fmt.Errorf("failed to decode go.mod content: %w", err)
 	}
 
-	// Simple text search for the target module
-	return strings.Contains(fileContent, targetModule), nil
+	// Simple text search for the target module and its aliases
+	for _, path := range targetPaths {
+		if strings.Contains(fileContent, path) {
+			return true, nil
+		}
+	}
+	return false, nil
 }
 
 // inferModulePath attempts to infer the Go module path from a GitHub repository full name.
@@ -606,15 +684,22 @@ func (g *gitHubDiscovery) inferLocalModulePath(modulePath string) string {
 	return "."
 }
 
-// resolveVersionFromProxy attempts to resolve the latest version using Go module proxy.
-func (g *gitHubDiscovery) resolveVersionFromProxy(ctx context.Context, targetModule string, resolution *VersionResolution) (*VersionResolution, error) {
+// resolveProxyVersion resolves the latest version using the Go module proxy.
+// Modules matching GOPRIVATE (or GONOPROXY) are never sent to the proxy: the
+// go command lists their versions from the origin repository, authenticating
+// with the GitHub token when one is set.
+func resolveProxyVersion(ctx context.Context, targetModule string, resolution *VersionResolution) (*VersionResolution, error) {
 	// Use go list -m -versions to query the module proxy
 	cmd := exec.CommandContext(ctx, "go", "list", "-m", "-versions", targetModule)
 
-	// Set environment to ensure we use the proxy
 	env := os.Environ()
-	// Ensure GOPROXY is set for proxy access
-	if goproxy := os.Getenv("GOPROXY"); goproxy == "" {
+	if goproxy.IsPrivate(targetModule, os.Getenv) {
+		for key, value := range gitutil.GitHubCredentialEnv() {
+			env = append(env, key+"="+value)
+		}
+		resolution.Warnings = append(resolution.Warnings, fmt.Sprintf("%s matches GOPRIVATE; versions were listed from its repository instead of the module proxy", targetModule))
+	} else if os.Getenv("GOPROXY") == "" {
+		// Ensure GOPROXY is set for proxy access
 		env = append(env, "GOPROXY=https://proxy.golang.org,direct")
 	}
 	cmd.Env = env
@@ -644,7 +729,7 @@ func (g *gitHubDiscovery) resolveVersionFromProxy(ctx context.Context, targetMod
 		}
 		// Extract versions (skip the first part which is the module name)
 		versions := parts[1:]
-		latestVersion := g.findLatestSemanticVersion(versions)
+		latestVersion := latestSemanticVersion(versions)
 		if latestVersion == "" {
 			return nil, fmt.Errorf("no semantic versions found in Go module proxy for %s", targetModule)
 		}
@@ -667,7 +752,7 @@ func (g *gitHubDiscovery) resolveVersionFromProxy(ctx context.Context, targetMod
 		return nil, fmt.Errorf("no versions found in Go module proxy for %s", targetModule)
 	}
 
-	latestVersion := g.findLatestSemanticVersion(allVersions)
+	latestVersion := latestSemanticVersion(allVersions)
 	if latestVersion == "" {
 		return nil, fmt.Errorf("no semantic versions found in Go module proxy for %s", targetModule)
 	}
@@ -690,7 +775,7 @@ func (g *gitHubDiscovery) resolveVersionFromTags(ctx context.Context, repository
 		PerPage: 100, // Get up to 100 tags
 	})
 	if err != nil {
-		return nil, g.handleRateLimitError(ctx, fmt.Errorf("failed to list tags for %s: %w", repository, err))
+		return nil, g.handleRateLimitError(fmt.Errorf("failed to list tags for %s: %w", repository, err))
 	}
 
 	if len(tags) == 0 {
@@ -704,7 +789,7 @@ func (g *gitHubDiscovery) resolveVersionFromTags(ctx context.Context, repository
 	}
 
 	// Find the latest semantic version tag
-	latestVersion := g.findLatestSemanticVersion(tagNames)
+	latestVersion := latestSemanticVersion(tagNames)
 	if latestVersion == "" {
 		return nil, fmt.Errorf("no semantic version tags found for repository %s", repository)
 	}
@@ -717,7 +802,7 @@ func (g *gitHubDiscovery) resolveVersionFromTags(ctx context.Context, repository
 // resolveVersionFromGitRemote resolves the latest version using git ls-remote --tags.
 func (g *gitHubDiscovery) resolveVersionFromGitRemote(ctx context.Context, repository string, resolution *VersionResolution) (*VersionResolution, error) {
 	// Construct the git repository URL
-	repoURL := fmt.Sprintf("https://github.com/%s.git", repository)
+	repoURL := g.cloneURL(repository)
 
 	// Run git ls-remote --tags to get all tags
 	cmd := exec.CommandContext(ctx, "git", "ls-remote", "--tags", repoURL)
@@ -759,7 +844,7 @@ func (g *gitHubDiscovery) resolveVersionFromGitRemote(ctx context.Context, repos
 	}
 
 	// Find the latest semantic version tag
-	latestVersion := g.findLatestSemanticVersion(tagNames)
+	latestVersion := latestSemanticVersion(tagNames)
 	if latestVersion == "" {
 		return nil, fmt.Errorf("no semantic version tags found for repository %s", repository)
 	}
@@ -772,6 +857,12 @@ func (g *gitHubDiscovery) resolveVersionFromGitRemote(ctx context.Context, repos
 
 // findLatestSemanticVersion finds the latest semantic version from a list of version strings.
 func (g *gitHubDiscovery) findLatestSemanticVersion(versions []string) string {
+	return latestSemanticVersion(versions)
+}
+
+// latestSemanticVersion returns the highest semantic version in versions, adding
+// the v prefix where it is missing, or "" when none is valid.
+func latestSemanticVersion(versions []string) string {
 	var validVersions []string
 
 	for _, version := range versions {
@@ -0,0 +1,234 @@
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// GitHubDiscoveryCache keeps the GitHub API responses of one discovery, the
+// search of an organization for the dependents of a module, between runs.
+// Requests made through its Transport carry the ETag or Last-Modified of the
+// cached response, and an unchanged resource is answered with a 304, which
+// GitHub does not count against the rate limit. The cached body is then
+// returned as if it had been fetched.
+type GitHubDiscoveryCache struct {
+	path         string
+	organization string
+	module       string
+	refresh      bool
+
+	mu      sync.Mutex
+	cached  map[string]gitHubCachedResponse
+	used    map[string]gitHubCachedResponse
+	stats   GitHubDiscoveryCacheStats
+	savedAt time.Time
+}
+
+// GitHubDiscoveryCacheStats counts the requests of a discovery.
+type GitHubDiscoveryCacheStats struct {
+	// Revalidated requests were answered from the cache after a 304.
+	Revalidated int
+	// Fetched requests downloaded the response.
+	Fetched int
+}
+
+// gitHubDiscoveryCacheFile is the file a GitHubDiscoveryCache is saved to.
+type gitHubDiscoveryCacheFile struct {
+	Organization string                          `json:"organization"`
+	Module       string                          `json:"module"`
+	SavedAt      time.Time                       `json:"saved_at"`
+	Responses    map[string]gitHubCachedResponse `json:"responses"`
+}
+
+// gitHubCachedResponse is a response with its validators. Link is kept for
+// pagination.
+type gitHubCachedResponse struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+	ContentType  string `json:"content_type,omitempty"`
+	Link         string `json:"link,omitempty"`
+	Body         []byte `json:"body"`
+}
+
+// GitHubDiscoveryCachePath returns the cache file of the discovery of
+// module's dependents in organization under dir.
+func GitHubDiscoveryCachePath(dir, organization, module string) string {
+	return filepath.Join(dir, organization, filepath.FromSlash(module)+".json")
+}
+
+// OpenGitHubDiscoveryCache loads the cache saved at path. A missing file, or
+// one saved for another organization or module, starts an empty cache. With
+// refresh, requests are sent without validators, so every response is fetched
+// again and replaces the cached one.
+func OpenGitHubDiscoveryCache(path, organization, module string, refresh bool) (*GitHubDiscoveryCache, error) {
+	c := &GitHubDiscoveryCache{
+		path:         path,
+		organization: organization,
+		module:       module,
+		refresh:      refresh,
+		cached:       make(map[string]gitHubCachedResponse),
+		used:         make(map[string]gitHubCachedResponse),
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read discovery cache: %w", err)
+	}
+	var file gitHubDiscoveryCacheFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("decode discovery cache %s: %w; remove it to start over", path, err)
+	}
+	if file.Organization == organization && file.Module == module && file.Responses != nil {
+		c.cached = file.Responses
+		c.savedAt = file.SavedAt
+	}
+	return c, nil
+}
+
+// SavedAt returns when the cache was last saved, zero for a new cache.
+func (c *GitHubDiscoveryCache) SavedAt() time.Time {
+	return c.savedAt
+}
+
+// Stats returns the requests counted so far.
+func (c *GitHubDiscoveryCache) Stats() GitHubDiscoveryCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+// Save writes the responses of this run to the cache file through a temporary
+// file. Responses no request of this run asked for are dropped, so the cache
+// only holds what the discovery still reads. The file is only readable by the
+// current user, since it may hold the contents of private repositories.
+func (c *GitHubDiscoveryCache) Save() error {
+	c.mu.Lock()
+	file := gitHubDiscoveryCacheFile{
+		Organization: c.organization,
+		Module:       c.module,
+		SavedAt:      time.Now().UTC(),
+		Responses:    c.used,
+	}
+	data, err := json.Marshal(file)
+	c.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("encode discovery cache: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return fmt.Errorf("create discovery cache directory: %w", err)
+	}
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("write discovery cache: %w", err)
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		return fmt.Errorf("write discovery cache: %w", err)
+	}
+	return nil
+}
+
+// Transport returns a round tripper that answers GET requests from the cache
+// when the server reports the resource unchanged, and records the responses
+// that carry validators. base sends the requests; nil uses
+// http.DefaultTransport.
+func (c *GitHubDiscoveryCache) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &gitHubCacheTransport{cache: c, base: base}
+}
+
+type gitHubCacheTransport struct {
+	cache *GitHubDiscoveryCache
+	base  http.RoundTripper
+}
+
+func (t *gitHubCacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		return t.base.RoundTrip(req)
+	}
+	c := t.cache
+	key := req.URL.String()
+
+	c.mu.Lock()
+	cached, ok := c.cached[key]
+	c.mu.Unlock()
+	if ok && !c.refresh {
+		req = req.Clone(req.Context())
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && ok && !c.refresh:
+		// The 304 carries the current rate limit headers; the rest comes
+		// from the cached response
+		resp.Body.Close()
+		resp.StatusCode = http.StatusOK
+		resp.Status = "200 OK"
+		if cached.ContentType != "" {
+			resp.Header.Set("Content-Type", cached.ContentType)
+		}
+		if cached.Link != "" {
+			resp.Header.Set("Link", cached.Link)
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(cached.Body))
+		resp.ContentLength = int64(len(cached.Body))
+		c.record(key, cached, true)
+		return resp, nil
+
+	case resp.StatusCode == http.StatusOK && (resp.Header.Get("ETag") != "" || resp.Header.Get("Last-Modified") != ""):
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		c.record(key, gitHubCachedResponse{
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+			ContentType:  resp.Header.Get("Content-Type"),
+			Link:         resp.Header.Get("Link"),
+			Body:         body,
+		}, false)
+		return resp, nil
+	}
+
+	c.mu.Lock()
+	c.stats.Fetched++
+	c.mu.Unlock()
+	return resp, nil
+}
+
+// record keeps response for key in this run's cache.
+func (c *GitHubDiscoveryCache) record(key string, response gitHubCachedResponse, revalidated bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.used[key] = response
+	if revalidated {
+		c.stats.Revalidated++
+	} else {
+		c.stats.Fetched++
+	}
+}
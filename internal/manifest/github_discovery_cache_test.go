@@ -0,0 +1,122 @@
+package manifest
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestGitHubDiscoveryCache(t *testing.T) {
+	var (
+		requests    int
+		conditional int
+	)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			conditional++
+			w.Header().Set("X-RateLimit-Remaining", "4999")
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Link", `<https://api.github.com/search?page=2>; rel="next"`)
+		io.WriteString(w, `{"total_count":1}`)
+	})
+	mux.HandleFunc("/uncached", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		io.WriteString(w, `{}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	path := GitHubDiscoveryCachePath(t.TempDir(), "acme", "github.com/acme/lib")
+	if filepath.Base(path) != "lib.json" {
+		t.Fatalf("cache path = %s, want one file per module", path)
+	}
+
+	run := func(refresh bool, urls ...string) (*GitHubDiscoveryCache, []*http.Response) {
+		t.Helper()
+		cache, err := OpenGitHubDiscoveryCache(path, "acme", "github.com/acme/lib", refresh)
+		if err != nil {
+			t.Fatalf("open cache: %v", err)
+		}
+		client := &http.Client{Transport: cache.Transport(nil)}
+		var responses []*http.Response
+		for _, url := range urls {
+			resp, err := client.Get(server.URL + url)
+			if err != nil {
+				t.Fatalf("GET %s: %v", url, err)
+			}
+			responses = append(responses, resp)
+		}
+		if err := cache.Save(); err != nil {
+			t.Fatalf("save cache: %v", err)
+		}
+		return cache, responses
+	}
+	body := func(resp *http.Response) string {
+		t.Helper()
+		defer resp.Body.Close()
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	cache, responses := run(false, "/search", "/uncached")
+	if got := body(responses[0]); got != `{"total_count":1}` {
+		t.Errorf("first body = %q", got)
+	}
+	if stats := cache.Stats(); stats.Fetched != 2 || stats.Revalidated != 0 {
+		t.Errorf("first stats = %+v, want 2 fetched", stats)
+	}
+
+	// The next run revalidates the cached response and keeps its headers
+	cache, responses = run(false, "/search", "/uncached")
+	resp := responses[0]
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Link") == "" || resp.Header.Get("X-RateLimit-Remaining") != "4999" {
+		t.Errorf("revalidated response = %d %v, want 200 with the cached Link and current rate limit", resp.StatusCode, resp.Header)
+	}
+	if got := body(resp); got != `{"total_count":1}` {
+		t.Errorf("revalidated body = %q, want the cached body", got)
+	}
+	if stats := cache.Stats(); stats.Revalidated != 1 || stats.Fetched != 1 || conditional != 1 {
+		t.Errorf("second stats = %+v (%d conditional), want 1 revalidated", stats, conditional)
+	}
+	if cache.SavedAt().IsZero() {
+		t.Error("expected the time the cache was saved")
+	}
+
+	// Refresh fetches everything again
+	cache, responses = run(true, "/search")
+	body(responses[0])
+	if stats := cache.Stats(); stats.Revalidated != 0 || stats.Fetched != 1 || conditional != 1 {
+		t.Errorf("refresh stats = %+v (%d conditional), want a plain fetch", stats, conditional)
+	}
+
+	// Responses a run did not ask for are dropped on save
+	run(false, "/uncached")
+	cache, responses = run(false, "/search")
+	body(responses[0])
+	if stats := cache.Stats(); stats.Revalidated != 0 || stats.Fetched != 1 {
+		t.Errorf("stats after pruning = %+v, want the response fetched again", stats)
+	}
+	if requests != 7 {
+		t.Errorf("requests = %d, want 7", requests)
+	}
+
+	// A cache saved for another module starts empty
+	other, err := OpenGitHubDiscoveryCache(path, "acme", "github.com/acme/other", false)
+	if err != nil {
+		t.Fatalf("open cache: %v", err)
+	}
+	if len(other.cached) != 0 || !other.SavedAt().IsZero() {
+		t.Errorf("expected an empty cache for another module, got %d responses", len(other.cached))
+	}
+}
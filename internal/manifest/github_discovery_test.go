@@ -226,6 +226,19 @@ func TestBuildSearchQuery(t *testing.T) {
 			},
 			expected: "custom query org:test-org",
 		},
+		{
+			name: "repository filter",
+			options: GitHubDiscoveryOptions{
+				Organization: "test-org",
+				Filter: RepoFilter{
+					Topics:     []string{"go-service"},
+					Visibility: "private",
+					PushedOp:   ">",
+					PushedDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				},
+			},
+			expected: "language:go filename:go.mod org:test-org topic:go-service is:private pushed:>2024-01-01",
+		},
 	}
 
 	for _, tt := range tests {
@@ -942,3 +955,61 @@ func setupErrorScenarios(mux *http.ServeMux, t *testing.T) {
 		json.NewEncoder(w).Encode(response)
 	})
 }
+
+func TestGitHubDiscovery_HandleRateLimitError(t *testing.T) {
+	discovery := &gitHubDiscovery{}
+	response := func(status int) *http.Response {
+		return &http.Response{StatusCode: status, Request: &http.Request{Method: http.MethodGet, URL: &url.URL{}}}
+	}
+	retryAfter := 90 * time.Second
+
+	tests := []struct {
+		name string
+		err  error
+		// want is a substring of the returned error; empty wants err unchanged
+		want string
+	}{
+		{
+			name: "primary rate limit",
+			err: &github.RateLimitError{
+				Rate:     github.Rate{Limit: 5000, Remaining: 0, Reset: github.Timestamp{Time: time.Now().Add(time.Hour)}},
+				Response: response(http.StatusForbidden),
+			},
+			want: "GitHub API rate limit exceeded: 5000/5000 requests used",
+		},
+		{
+			name: "secondary rate limit",
+			err:  &github.AbuseRateLimitError{RetryAfter: &retryAfter, Response: response(http.StatusForbidden)},
+			want: "GitHub secondary rate limit exceeded",
+		},
+		{
+			name: "bad credentials",
+			err:  &github.ErrorResponse{Response: response(http.StatusUnauthorized), Message: "Bad credentials"},
+			want: "GitHub authentication failed",
+		},
+		{
+			name: "permission denied",
+			err:  &github.ErrorResponse{Response: response(http.StatusForbidden), Message: "Resource not accessible by integration"},
+			want: "GitHub API access denied",
+		},
+		{
+			name: "message mentioning a rate limit",
+			err:  &github.ErrorResponse{Response: response(http.StatusNotFound), Message: "rate limit 403 not found"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := discovery.handleRateLimitError(tt.err)
+			if tt.want == "" {
+				if err != tt.err {
+					t.Errorf("handleRateLimitError() = %v, want the error returned unchanged", err)
+				}
+				return
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("handleRateLimitError() = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
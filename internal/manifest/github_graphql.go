@@ -0,0 +1,357 @@
+package manifest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goliatone/cascade/internal/telemetry"
+	"github.com/google/go-github/v66/github"
+)
+
+// ErrGitHubGraphQLUnavailable reports that the GitHub server has no GraphQL API,
+// as on older GitHub Enterprise Server versions. Callers fall back to REST.
+var ErrGitHubGraphQLUnavailable = errors.New("GitHub GraphQL API is unavailable")
+
+// githubGraphQLPageSize is the number of repositories, with their go.mod, read
+// by each query. 100 is the most a GraphQL connection returns.
+const githubGraphQLPageSize = 100
+
+const githubOrgRepositoriesQuery = `query($org: String!, $first: Int!, $cursor: String, $privacy: RepositoryPrivacy) {
+  organization(login: $org) {
+    repositories(first: $first, after: $cursor, privacy: $privacy, orderBy: {field: NAME, direction: ASC}) {
+      pageInfo { hasNextPage endCursor }
+      nodes {
+        name
+        nameWithOwner
+        owner { login }
+        url
+        sshUrl
+        isArchived
+        isPrivate
+        visibility
+        pushedAt
+        defaultBranchRef { name }
+        repositoryTopics(first: 100) { nodes { topic { name } } }
+        goMod: object(expression: "HEAD:go.mod") { ... on Blob { text isTruncated } }
+      }
+    }
+  }
+}`
+
+type githubGraphQLRepository struct {
+	Name          string `json:"name"`
+	NameWithOwner string `json:"nameWithOwner"`
+	Owner         struct {
+		Login string `json:"login"`
+	} `json:"owner"`
+	URL              string     `json:"url"`
+	SSHURL           string     `json:"sshUrl"`
+	IsArchived       bool       `json:"isArchived"`
+	IsPrivate        bool       `json:"isPrivate"`
+	Visibility       string     `json:"visibility"`
+	PushedAt         *time.Time `json:"pushedAt"`
+	DefaultBranchRef *struct {
+		Name string `json:"name"`
+	} `json:"defaultBranchRef"`
+	RepositoryTopics struct {
+		Nodes []struct {
+			Topic struct {
+				Name string `json:"name"`
+			} `json:"topic"`
+		} `json:"nodes"`
+	} `json:"repositoryTopics"`
+	GoMod *struct {
+		Text        *string `json:"text"`
+		IsTruncated bool    `json:"isTruncated"`
+	} `json:"goMod"`
+}
+
+type githubGraphQLRepositoriesPage struct {
+	PageInfo struct {
+		HasNextPage bool   `json:"hasNextPage"`
+		EndCursor   string `json:"endCursor"`
+	} `json:"pageInfo"`
+	Nodes []githubGraphQLRepository `json:"nodes"`
+}
+
+type githubGraphQLResponse struct {
+	Data struct {
+		Organization *struct {
+			Repositories githubGraphQLRepositoriesPage `json:"repositories"`
+		} `json:"organization"`
+	} `json:"data"`
+	Errors []struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// githubGraphQLRateLimitError is a query rejected for exceeding the GraphQL rate
+// limit, which GitHub counts apart from the REST one.
+type githubGraphQLRateLimitError struct {
+	reset time.Time
+}
+
+func (e *githubGraphQLRateLimitError) Error() string {
+	return fmt.Sprintf("GitHub GraphQL rate limit exceeded until %s", e.reset.Format(time.RFC3339))
+}
+
+// ScanGitHubOrgGraphQL is ScanGitHubOrg through the GitHub GraphQL API: each
+// query lists 100 repositories together with their root go.mod, so an
+// organization is scanned in one request per 100 repositories instead of one
+// per repository. The checkpoint is saved after every page. It returns an error
+// wrapping ErrGitHubGraphQLUnavailable when the server has no GraphQL API;
+// ScanGitHubOrg then scans through REST.
+func ScanGitHubOrgGraphQL(ctx context.Context, client *github.Client, options GitHubOrgScanOptions) ([]DependentOptions, error) {
+	ctx, span := telemetry.Start(ctx, "discovery.github_org_graphql", telemetry.String("org", options.Organization), telemetry.String("module", options.TargetModule))
+	dependents, err := scanGitHubOrgGraphQL(ctx, client, options)
+	span.SetAttributes(telemetry.Int("dependents", len(dependents)))
+	span.End(err)
+	return dependents, err
+}
+
+func scanGitHubOrgGraphQL(ctx context.Context, client *github.Client, options GitHubOrgScanOptions) ([]DependentOptions, error) {
+	if client == nil {
+		return nil, fmt.Errorf("GitHub client is required")
+	}
+	if options.Organization == "" {
+		return nil, fmt.Errorf("GitHub organization is required")
+	}
+	if options.TargetModule == "" {
+		return nil, fmt.Errorf("target module is required")
+	}
+
+	checkpoint, resumed, err := loadGitHubOrgScanCheckpoint(options, "graphql")
+	if err != nil {
+		return nil, err
+	}
+	targetPaths := ModulePaths(options.TargetModule, options.TargetAliases)
+	endpoint := githubGraphQLEndpoint(client.BaseURL)
+	report := func(p GitHubOrgScanProgress) {
+		if options.Progress == nil {
+			return
+		}
+		p.Scanned, p.Dependents, p.Resumed = checkpoint.Scanned, len(checkpoint.Dependents), resumed
+		resumed = false
+		options.Progress(p)
+	}
+
+	for {
+		var page *githubGraphQLRepositoriesPage
+		err := withGitHubRateLimit(ctx, checkpoint, options, report, func() error {
+			var err error
+			page, err = queryGitHubOrgRepositories(ctx, client.Client(), endpoint, options, checkpoint.Cursor)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list repositories of %s: %w", options.Organization, err)
+		}
+
+		for i := checkpoint.Offset; i < len(page.Nodes); i++ {
+			node := page.Nodes[i]
+			repo := node.repository()
+			if options.includes(repo) {
+				modulePath, requires, err := node.goModRequires(ctx, client, targetPaths, func(call func() error) error {
+					return withGitHubRateLimit(ctx, checkpoint, options, report, call)
+				})
+				if err != nil {
+					return nil, fmt.Errorf("failed to read go.mod of %s: %w", repo.GetFullName(), err)
+				}
+				if requires && !containsString(targetPaths, modulePath) {
+					found := GitHubOrgScanDependent{Repository: repo.GetFullName(), CloneURL: repo.GetCloneURL(), ModulePath: modulePath}
+					checkpoint.Dependents = append(checkpoint.Dependents, found)
+					if options.Found != nil {
+						options.Found(found.options())
+					}
+				}
+			}
+
+			checkpoint.Offset = i + 1
+			checkpoint.Scanned++
+			report(GitHubOrgScanProgress{Repository: repo.GetFullName()})
+		}
+
+		if !page.PageInfo.HasNextPage {
+			break
+		}
+		checkpoint.Cursor, checkpoint.Offset = page.PageInfo.EndCursor, 0
+		if err := saveGitHubOrgScanCheckpoint(options.CheckpointPath, checkpoint); err != nil {
+			return nil, err
+		}
+	}
+
+	if options.CheckpointPath != "" {
+		if err := os.Remove(options.CheckpointPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("remove scan checkpoint: %w", err)
+		}
+	}
+
+	dependents := make([]DependentOptions, 0, len(checkpoint.Dependents))
+	for _, found := range checkpoint.Dependents {
+		dependents = append(dependents, found.options())
+	}
+	return dependents, nil
+}
+
+// githubGraphQLEndpoint returns the GraphQL endpoint of the server whose REST
+// API is at baseURL: https://api.github.com/graphql for github.com and
+// /api/graphql for GitHub Enterprise Server.
+func githubGraphQLEndpoint(baseURL *url.URL) string {
+	endpoint := *baseURL
+	path := strings.TrimSuffix(endpoint.Path, "/")
+	if strings.HasSuffix(path, "/api/v3") {
+		path = strings.TrimSuffix(path, "/v3")
+	}
+	endpoint.Path = path + "/graphql"
+	return endpoint.String()
+}
+
+// queryGitHubOrgRepositories reads the page of the organization's repositories
+// after cursor.
+func queryGitHubOrgRepositories(ctx context.Context, httpClient *http.Client, endpoint string, options GitHubOrgScanOptions, cursor string) (*githubGraphQLRepositoriesPage, error) {
+	variables := map[string]any{"org": options.Organization, "first": githubGraphQLPageSize}
+	if cursor != "" {
+		variables["cursor"] = cursor
+	}
+	if options.Visibility != "" {
+		variables["privacy"] = strings.ToUpper(options.Visibility)
+	}
+	body, err := json.Marshal(map[string]any{"query": githubOrgRepositoriesQuery, "variables": variables})
+	if err != nil {
+		return nil, fmt.Errorf("encode GraphQL query: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if reset, limited := githubGraphQLRateLimited(resp); limited {
+		return nil, &githubGraphQLRateLimitError{reset: reset}
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusGone, http.StatusNotImplemented:
+		return nil, fmt.Errorf("%w: %s returned %s", ErrGitHubGraphQLUnavailable, endpoint, resp.Status)
+	default:
+		return nil, fmt.Errorf("GraphQL query returned %s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+
+	var result githubGraphQLResponse
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("decode GraphQL response: %w", err)
+	}
+	if len(result.Errors) > 0 {
+		if result.Errors[0].Type == "RATE_LIMITED" {
+			reset := githubRateLimitHeaderReset(resp)
+			if reset.IsZero() {
+				reset = time.Now().Add(time.Minute)
+			}
+			return nil, &githubGraphQLRateLimitError{reset: reset}
+		}
+		messages := make([]string, 0, len(result.Errors))
+		for _, e := range result.Errors {
+			messages = append(messages, e.Message)
+		}
+		return nil, fmt.Errorf("GraphQL query failed: %s", strings.Join(messages, "; "))
+	}
+	if result.Data.Organization == nil {
+		return nil, fmt.Errorf("organization %s not found", options.Organization)
+	}
+	return &result.Data.Organization.Repositories, nil
+}
+
+// githubGraphQLRateLimited reports whether resp rejects a query for exceeding
+// the primary or the secondary rate limit, and when it may be retried.
+func githubGraphQLRateLimited(resp *http.Response) (time.Time, bool) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return time.Time{}, false
+	}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		return time.Now().Add(time.Duration(seconds) * time.Second), true
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		if reset := githubRateLimitHeaderReset(resp); !reset.IsZero() {
+			return reset, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func githubRateLimitHeaderReset(resp *http.Response) time.Time {
+	seconds, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(seconds, 0)
+}
+
+// repository returns the repository as the REST API describes it, for the
+// Include filter and for reading its go.mod through REST.
+func (n githubGraphQLRepository) repository() *github.Repository {
+	repo := &github.Repository{
+		Name:       github.String(n.Name),
+		FullName:   github.String(n.NameWithOwner),
+		Owner:      &github.User{Login: github.String(n.Owner.Login)},
+		Archived:   github.Bool(n.IsArchived),
+		Private:    github.Bool(n.IsPrivate),
+		Visibility: github.String(strings.ToLower(n.Visibility)),
+		CloneURL:   github.String(n.URL + ".git"),
+		SSHURL:     github.String(n.SSHURL),
+	}
+	if n.DefaultBranchRef != nil {
+		repo.DefaultBranch = github.String(n.DefaultBranchRef.Name)
+	}
+	if n.PushedAt != nil {
+		repo.PushedAt = &github.Timestamp{Time: *n.PushedAt}
+	}
+	for _, topic := range n.RepositoryTopics.Nodes {
+		repo.Topics = append(repo.Topics, topic.Topic.Name)
+	}
+	return repo
+}
+
+// goModRequires reports the module path of the repository's root go.mod, read
+// by the query, and whether it requires one of targetPaths. A go.mod the query
+// returned truncated is read again through REST, with rateLimited retrying it.
+func (n githubGraphQLRepository) goModRequires(ctx context.Context, client *github.Client, targetPaths []string, rateLimited func(func() error) error) (string, bool, error) {
+	if n.GoMod == nil || n.GoMod.Text == nil {
+		return "", false, nil
+	}
+	repo := n.repository()
+	if !n.GoMod.IsTruncated {
+		modulePath, requires := parseGitHubGoMod(repo, *n.GoMod.Text, targetPaths)
+		return modulePath, requires, nil
+	}
+	var (
+		modulePath string
+		requires   bool
+	)
+	err := rateLimited(func() error {
+		var err error
+		modulePath, requires, err = readGitHubGoMod(ctx, client, repo, targetPaths)
+		return err
+	})
+	return modulePath, requires, err
+}
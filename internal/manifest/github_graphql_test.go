@@ -0,0 +1,181 @@
+package manifest
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestScanGitHubOrgGraphQL(t *testing.T) {
+	goMods := map[string]string{
+		"a": "module github.com/acme/a\n\nrequire github.com/acme/lib v1.0.0\n",
+		"b": "module github.com/acme/b\n\nrequire github.com/acme/other v1.0.0\n",
+		"d": "module github.com/acme/d\n\nrequire github.com/acme/lib v1.2.0\n",
+		"f": "module github.com/acme/f\n\nrequire github.com/acme/lib-legacy v0.9.0\n",
+	}
+	var queries []map[string]any
+	restReads := 0
+	limited := true
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	mux.HandleFunc("/graphql", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Variables map[string]any `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode query: %v", err)
+		}
+		queries = append(queries, body.Variables)
+
+		names, cursor, next := []string{"a", "b", "c"}, "page-2", true
+		if body.Variables["cursor"] == "page-2" {
+			if limited {
+				limited = false
+				_ = json.NewEncoder(w).Encode(map[string]any{
+					"errors": []map[string]any{{"type": "RATE_LIMITED", "message": "API rate limit exceeded"}},
+				})
+				return
+			}
+			names, cursor, next = []string{"d", "e", "f"}, "", false
+		}
+		nodes := make([]map[string]any, 0, len(names))
+		for _, name := range names {
+			node := map[string]any{
+				"name":             name,
+				"nameWithOwner":    "acme/" + name,
+				"owner":            map[string]any{"login": "acme"},
+				"url":              "https://github.com/acme/" + name,
+				"isArchived":       name == "e",
+				"visibility":       "PRIVATE",
+				"defaultBranchRef": map[string]any{"name": "main"},
+				"repositoryTopics": map[string]any{"nodes": []any{}},
+			}
+			if content, ok := goMods[name]; ok {
+				node["goMod"] = map[string]any{"text": content, "isTruncated": name == "f"}
+			}
+			nodes = append(nodes, node)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"organization": map[string]any{
+			"repositories": map[string]any{
+				"pageInfo": map[string]any{"hasNextPage": next, "endCursor": cursor},
+				"nodes":    nodes,
+			},
+		}}})
+	})
+	mux.HandleFunc("/repos/acme/f/contents/go.mod", func(w http.ResponseWriter, r *http.Request) {
+		restReads++
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"type":     "file",
+			"encoding": "base64",
+			"content":  base64.StdEncoding.EncodeToString([]byte(goMods["f"])),
+		})
+	})
+
+	client, err := createMockGitHubClient(server.URL)
+	if err != nil {
+		t.Fatalf("failed to create mock client: %v", err)
+	}
+
+	defer func(original func(context.Context, time.Time) error) { githubScanWait = original }(githubScanWait)
+	githubScanWait = func(ctx context.Context, until time.Time) error { return context.Canceled }
+
+	checkpointPath := filepath.Join(t.TempDir(), "scan", "acme.json")
+	options := GitHubOrgScanOptions{
+		Organization:   "acme",
+		TargetModule:   "github.com/acme/lib",
+		TargetAliases:  []string{"github.com/acme/lib-legacy"},
+		Visibility:     "private",
+		CheckpointPath: checkpointPath,
+	}
+
+	_, err = ScanGitHubOrgGraphQL(context.Background(), client, options)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the scan to stop at the rate limit, got %v", err)
+	}
+	data, err := os.ReadFile(checkpointPath)
+	if err != nil {
+		t.Fatalf("expected a checkpoint after the rate limit: %v", err)
+	}
+	var checkpoint GitHubOrgScanCheckpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		t.Fatalf("decode checkpoint: %v", err)
+	}
+	if checkpoint.API != "graphql" || checkpoint.Cursor != "page-2" || checkpoint.Scanned != 3 || len(checkpoint.Dependents) != 1 {
+		t.Fatalf("unexpected checkpoint: %+v", checkpoint)
+	}
+
+	if _, err := ScanGitHubOrg(context.Background(), client, options); err == nil {
+		t.Fatal("expected the REST scan to reject the GraphQL checkpoint")
+	}
+
+	githubScanWait = func(ctx context.Context, until time.Time) error { return nil }
+	dependents, err := ScanGitHubOrgGraphQL(context.Background(), client, options)
+	if err != nil {
+		t.Fatalf("resumed scan failed: %v", err)
+	}
+
+	var repos []string
+	for _, dep := range dependents {
+		repos = append(repos, dep.Repository)
+	}
+	if fmt.Sprint(repos) != "[acme/a acme/d acme/f]" {
+		t.Fatalf("unexpected dependents: %v", repos)
+	}
+	if dependents[0].CloneURL != "https://github.com/acme/a.git" || dependents[0].DiscoverySource != "github" {
+		t.Fatalf("unexpected dependent options: %+v", dependents[0])
+	}
+	if restReads != 1 {
+		t.Fatalf("expected the truncated go.mod to be read through REST once, got %d", restReads)
+	}
+	if len(queries) != 3 || queries[0]["privacy"] != "PRIVATE" || queries[0]["first"] != float64(githubGraphQLPageSize) {
+		t.Fatalf("unexpected queries: %v", queries)
+	}
+	if _, err := os.Stat(checkpointPath); !os.IsNotExist(err) {
+		t.Fatalf("expected the checkpoint to be removed after a complete scan, got %v", err)
+	}
+}
+
+func TestScanGitHubOrgGraphQL_Unavailable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	client, err := createMockGitHubClient(server.URL + "/api/v3")
+	if err != nil {
+		t.Fatalf("failed to create mock client: %v", err)
+	}
+	_, err = ScanGitHubOrgGraphQL(context.Background(), client, GitHubOrgScanOptions{
+		Organization: "acme",
+		TargetModule: "github.com/acme/lib",
+	})
+	if !errors.Is(err, ErrGitHubGraphQLUnavailable) {
+		t.Fatalf("expected ErrGitHubGraphQLUnavailable, got %v", err)
+	}
+}
+
+func TestGitHubGraphQLEndpoint(t *testing.T) {
+	for base, want := range map[string]string{
+		"https://api.github.com/":            "https://api.github.com/graphql",
+		"https://github.example.com/api/v3/": "https://github.example.com/api/graphql",
+		"https://github.example.com/github/": "https://github.example.com/github/graphql",
+	} {
+		u, err := url.Parse(base)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := githubGraphQLEndpoint(u); got != want {
+			t.Errorf("githubGraphQLEndpoint(%s) = %s, want %s", base, got, want)
+		}
+	}
+}
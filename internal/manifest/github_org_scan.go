@@ -0,0 +1,369 @@
+package manifest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/goliatone/cascade/internal/telemetry"
+	"github.com/goliatone/cascade/pkg/githubx"
+	"github.com/goliatone/cascade/pkg/util/modpath"
+	"github.com/google/go-github/v66/github"
+	"golang.org/x/mod/modfile"
+)
+
+// GitHubOrgScanOptions configures a scan of every repository of an
+// organization. Unlike code search, which stops at 1000 results, the scan lists
+// the organization's repositories page by page and reads each root go.mod.
+type GitHubOrgScanOptions struct {
+	Organization  string
+	TargetModule  string
+	TargetAliases []string
+
+	// Visibility limits the listing to public or private repositories on the
+	// server; empty lists all of them.
+	Visibility string
+
+	// Include selects the repositories to read; nil reads every unarchived one.
+	Include func(repo *github.Repository) bool
+
+	// CheckpointPath, when set, records the scan position and the dependents
+	// found so far after every repository. A scan started with the same path
+	// continues from there. The file is removed once the scan completes.
+	CheckpointPath string
+
+	// Progress is called after every repository and before waiting for a rate
+	// limit to reset.
+	Progress func(GitHubOrgScanProgress)
+
+	// Found is called with each dependent as soon as it is found.
+	Found func(DependentOptions)
+}
+
+// GitHubOrgScanProgress reports how far a scan has come.
+type GitHubOrgScanProgress struct {
+	Scanned    int
+	Dependents int
+
+	// Repository is the repository just read.
+	Repository string
+
+	// WaitUntil is set while the scan waits for the GitHub rate limit to reset.
+	WaitUntil time.Time
+
+	// Resumed is set on the first report of a scan continued from a checkpoint.
+	Resumed bool
+}
+
+// GitHubOrgScanCheckpoint is the saved position of an interrupted scan.
+type GitHubOrgScanCheckpoint struct {
+	Organization string `json:"organization"`
+	TargetModule string `json:"target_module"`
+	Visibility   string `json:"visibility,omitempty"`
+
+	// Page is the page of the repository listing to read next, and Offset the
+	// number of its repositories already scanned.
+	Page   int `json:"page"`
+	Offset int `json:"offset"`
+
+	// API is "graphql" for a scan through the GraphQL API, which continues
+	// after Cursor instead of from Page.
+	API    string `json:"api,omitempty"`
+	Cursor string `json:"cursor,omitempty"`
+
+	Scanned    int                      `json:"scanned"`
+	Dependents []GitHubOrgScanDependent `json:"dependents"`
+	UpdatedAt  time.Time                `json:"updated_at"`
+}
+
+// GitHubOrgScanDependent is a dependent recorded in a scan checkpoint.
+type GitHubOrgScanDependent struct {
+	Repository string `json:"repository"`
+	CloneURL   string `json:"clone_url,omitempty"`
+	ModulePath string `json:"module_path"`
+}
+
+// githubScanWait blocks until t or until ctx ends. Tests replace it.
+var githubScanWait = func(ctx context.Context, t time.Time) error {
+	timer := time.NewTimer(time.Until(t))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// ScanGitHubOrg lists every repository of the organization and returns the ones
+// whose root go.mod requires the target module or one of its aliases. When the
+// rate limit runs out the scan saves its checkpoint and waits for the reset;
+// cancel ctx to stop it and continue later from the checkpoint.
+func ScanGitHubOrg(ctx context.Context, client *github.Client, options GitHubOrgScanOptions) ([]DependentOptions, error) {
+	ctx, span := telemetry.Start(ctx, "discovery.github_org", telemetry.String("org", options.Organization), telemetry.String("module", options.TargetModule))
+	dependents, err := scanGitHubOrg(ctx, client, options)
+	span.SetAttributes(telemetry.Int("dependents", len(dependents)))
+	span.End(err)
+	return dependents, err
+}
+
+func scanGitHubOrg(ctx context.Context, client *github.Client, options GitHubOrgScanOptions) ([]DependentOptions, error) {
+	if client == nil {
+		return nil, fmt.Errorf("GitHub client is required")
+	}
+	if options.Organization == "" {
+		return nil, fmt.Errorf("GitHub organization is required")
+	}
+	if options.TargetModule == "" {
+		return nil, fmt.Errorf("target module is required")
+	}
+
+	checkpoint, resumed, err := loadGitHubOrgScanCheckpoint(options, "")
+	if err != nil {
+		return nil, err
+	}
+	targetPaths := ModulePaths(options.TargetModule, options.TargetAliases)
+	report := func(p GitHubOrgScanProgress) {
+		if options.Progress == nil {
+			return
+		}
+		p.Scanned, p.Dependents, p.Resumed = checkpoint.Scanned, len(checkpoint.Dependents), resumed
+		resumed = false
+		options.Progress(p)
+	}
+
+	for {
+		listType := "all"
+		if options.Visibility != "" {
+			listType = options.Visibility
+		}
+		listOpts := &github.RepositoryListByOrgOptions{
+			Type:        listType,
+			Sort:        "full_name",
+			Direction:   "asc",
+			ListOptions: github.ListOptions{PerPage: 100, Page: checkpoint.Page},
+		}
+		var (
+			repos []*github.Repository
+			resp  *github.Response
+		)
+		err := withGitHubRateLimit(ctx, checkpoint, options, report, func() error {
+			var err error
+			repos, resp, err = client.Repositories.ListByOrg(ctx, options.Organization, listOpts)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list repositories of %s: %w", options.Organization, err)
+		}
+
+		for i := checkpoint.Offset; i < len(repos); i++ {
+			repo := repos[i]
+			if options.includes(repo) {
+				var modulePath string
+				var requires bool
+				err := withGitHubRateLimit(ctx, checkpoint, options, report, func() error {
+					var err error
+					modulePath, requires, err = readGitHubGoMod(ctx, client, repo, targetPaths)
+					return err
+				})
+				if err != nil {
+					return nil, fmt.Errorf("failed to read go.mod of %s: %w", repo.GetFullName(), err)
+				}
+				if requires && !containsString(targetPaths, modulePath) {
+					found := GitHubOrgScanDependent{Repository: repo.GetFullName(), CloneURL: repo.GetCloneURL(), ModulePath: modulePath}
+					checkpoint.Dependents = append(checkpoint.Dependents, found)
+					if options.Found != nil {
+						options.Found(found.options())
+					}
+				}
+			}
+
+			checkpoint.Offset = i + 1
+			checkpoint.Scanned++
+			if err := saveGitHubOrgScanCheckpoint(options.CheckpointPath, checkpoint); err != nil {
+				return nil, err
+			}
+			report(GitHubOrgScanProgress{Repository: repo.GetFullName()})
+		}
+
+		if resp == nil || resp.NextPage == 0 {
+			break
+		}
+		checkpoint.Page, checkpoint.Offset = resp.NextPage, 0
+		if err := saveGitHubOrgScanCheckpoint(options.CheckpointPath, checkpoint); err != nil {
+			return nil, err
+		}
+	}
+
+	if options.CheckpointPath != "" {
+		if err := os.Remove(options.CheckpointPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("remove scan checkpoint: %w", err)
+		}
+	}
+
+	dependents := make([]DependentOptions, 0, len(checkpoint.Dependents))
+	for _, found := range checkpoint.Dependents {
+		dependents = append(dependents, found.options())
+	}
+	return dependents, nil
+}
+
+func (o GitHubOrgScanOptions) includes(repo *github.Repository) bool {
+	if o.Include == nil {
+		return !repo.GetArchived()
+	}
+	return o.Include(repo)
+}
+
+func (d GitHubOrgScanDependent) options() DependentOptions {
+	return DependentOptions{
+		Repository:      d.Repository,
+		CloneURL:        d.CloneURL,
+		ModulePath:      d.ModulePath,
+		LocalModulePath: modpath.DeriveLocalModulePath(d.ModulePath),
+		DiscoverySource: "github",
+	}
+}
+
+// withGitHubRateLimit runs call, and when GitHub rejects it for exceeding a rate
+// limit, saves the checkpoint, waits for the limit to reset, and runs it again.
+func withGitHubRateLimit(ctx context.Context, checkpoint *GitHubOrgScanCheckpoint, options GitHubOrgScanOptions, report func(GitHubOrgScanProgress), call func() error) error {
+	for {
+		err := call()
+		until, limited := githubRateLimitReset(err)
+		if !limited {
+			return err
+		}
+		if err := saveGitHubOrgScanCheckpoint(options.CheckpointPath, checkpoint); err != nil {
+			return err
+		}
+		report(GitHubOrgScanProgress{WaitUntil: until})
+		if err := githubScanWait(ctx, until); err != nil {
+			return fmt.Errorf("stopped while waiting for the GitHub rate limit to reset: %w", err)
+		}
+	}
+}
+
+// githubRateLimitReset reports whether err is a GitHub rate limit error and when
+// the request may be retried.
+func githubRateLimitReset(err error) (time.Time, bool) {
+	if limit, ok := githubx.RateLimited(err); ok {
+		if limit.Kind == githubx.Primary {
+			return limit.Reset.Add(time.Second), true
+		}
+		return limit.Reset, true
+	}
+	var graphqlErr *githubGraphQLRateLimitError
+	if errors.As(err, &graphqlErr) {
+		return graphqlErr.reset.Add(time.Second), true
+	}
+	return time.Time{}, false
+}
+
+// readGitHubGoMod reads the root go.mod of repo on its default branch and
+// reports its module path and whether it requires one of targetPaths.
+// Repositories without a go.mod require nothing.
+func readGitHubGoMod(ctx context.Context, client *github.Client, repo *github.Repository, targetPaths []string) (string, bool, error) {
+	file, _, resp, err := client.Repositories.GetContents(ctx, repo.GetOwner().GetLogin(), repo.GetName(), "go.mod", &github.RepositoryContentGetOptions{Ref: repo.GetDefaultBranch()})
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+	content, err := file.GetContent()
+	if err != nil {
+		return "", false, err
+	}
+	modulePath, requires := parseGitHubGoMod(repo, content, targetPaths)
+	return modulePath, requires, nil
+}
+
+// parseGitHubGoMod returns the module path declared by repo's go.mod content and
+// whether it requires one of targetPaths. A go.mod that does not parse requires
+// nothing.
+func parseGitHubGoMod(repo *github.Repository, content string, targetPaths []string) (string, bool) {
+	parsed, err := modfile.ParseLax("go.mod", []byte(content), nil)
+	if err != nil {
+		return "", false
+	}
+	modulePath := "github.com/" + repo.GetFullName()
+	if parsed.Module != nil && parsed.Module.Mod.Path != "" {
+		modulePath = parsed.Module.Mod.Path
+	}
+	for _, req := range parsed.Require {
+		if containsString(targetPaths, req.Mod.Path) {
+			return modulePath, true
+		}
+	}
+	return modulePath, false
+}
+
+// loadGitHubOrgScanCheckpoint returns the checkpoint to continue from, or a new
+// one at the first page when there is none. api is the API the scan lists
+// repositories through; a checkpoint of a scan through another API is rejected.
+func loadGitHubOrgScanCheckpoint(options GitHubOrgScanOptions, api string) (*GitHubOrgScanCheckpoint, bool, error) {
+	fresh := &GitHubOrgScanCheckpoint{Organization: options.Organization, TargetModule: options.TargetModule, Visibility: options.Visibility, API: api, Page: 1}
+	if options.CheckpointPath == "" {
+		return fresh, false, nil
+	}
+	data, err := os.ReadFile(options.CheckpointPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return fresh, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("read scan checkpoint: %w", err)
+	}
+
+	var checkpoint GitHubOrgScanCheckpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return nil, false, fmt.Errorf("decode scan checkpoint %s: %w", options.CheckpointPath, err)
+	}
+	if checkpoint.Organization != options.Organization || checkpoint.TargetModule != options.TargetModule || checkpoint.Visibility != options.Visibility {
+		return nil, false, fmt.Errorf("scan checkpoint %s belongs to a scan of %s for %s; remove it to start over", options.CheckpointPath, checkpoint.Organization, checkpoint.TargetModule)
+	}
+	if checkpoint.API != api {
+		return nil, false, fmt.Errorf("scan checkpoint %s belongs to a scan through the %s API; remove it to start over", options.CheckpointPath, githubScanAPIName(checkpoint.API))
+	}
+	if checkpoint.Page < 1 {
+		checkpoint.Page = 1
+	}
+	return &checkpoint, true, nil
+}
+
+func githubScanAPIName(api string) string {
+	if api == "" {
+		return "REST"
+	}
+	return strings.ToUpper(api)
+}
+
+// saveGitHubOrgScanCheckpoint writes checkpoint to path through a temporary
+// file, so an interrupted write never leaves a truncated checkpoint.
+func saveGitHubOrgScanCheckpoint(path string, checkpoint *GitHubOrgScanCheckpoint) error {
+	if path == "" {
+		return nil
+	}
+	checkpoint.UpdatedAt = time.Now().UTC()
+	data, err := json.MarshalIndent(checkpoint, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode scan checkpoint: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create scan checkpoint directory: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("write scan checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("write scan checkpoint: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package manifest
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestScanGitHubOrg_ResumesFromCheckpointAfterRateLimit(t *testing.T) {
+	goMods := map[string]string{
+		"a": "module github.com/acme/a\n\nrequire github.com/acme/lib v1.0.0\n",
+		"b": "module github.com/acme/b\n\nrequire github.com/acme/lib v1.2.0\n",
+		"e": "module github.com/acme/e\n\nrequire github.com/acme/other v1.0.0\n",
+		"f": "module github.com/acme/f\n\nrequire github.com/acme/lib-legacy v0.9.0\n",
+	}
+	reads := map[string]int{}
+	limitB := true
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	mux.HandleFunc("/orgs/acme/repos", func(w http.ResponseWriter, r *http.Request) {
+		names := []string{"a", "b"}
+		if r.URL.Query().Get("page") == "2" {
+			names = []string{"c", "d", "e", "f"}
+		} else {
+			w.Header().Set("Link", fmt.Sprintf(`<%s/orgs/acme/repos?page=2>; rel="next"`, server.URL))
+		}
+		repos := make([]map[string]any, 0, len(names))
+		for _, name := range names {
+			repos = append(repos, map[string]any{
+				"name":           name,
+				"full_name":      "acme/" + name,
+				"owner":          map[string]any{"login": "acme"},
+				"default_branch": "main",
+				"clone_url":      "https://github.com/acme/" + name + ".git",
+				"archived":       name == "d",
+			})
+		}
+		_ = json.NewEncoder(w).Encode(repos)
+	})
+	mux.HandleFunc("/repos/acme/", func(w http.ResponseWriter, r *http.Request) {
+		name := filepath.Base(filepath.Dir(filepath.Dir(r.URL.Path)))
+		reads[name]++
+		if name == "b" && limitB {
+			limitB = false
+			w.Header().Set("X-RateLimit-Limit", "5000")
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(-time.Second).Unix(), 10))
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"message":"API rate limit exceeded"}`))
+			return
+		}
+		content, ok := goMods[name]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"type":     "file",
+			"encoding": "base64",
+			"content":  base64.StdEncoding.EncodeToString([]byte(content)),
+		})
+	})
+
+	client, err := createMockGitHubClient(server.URL)
+	if err != nil {
+		t.Fatalf("failed to create mock client: %v", err)
+	}
+
+	waits := 0
+	defer func(original func(context.Context, time.Time) error) { githubScanWait = original }(githubScanWait)
+	githubScanWait = func(ctx context.Context, until time.Time) error {
+		waits++
+		return context.Canceled
+	}
+
+	checkpointPath := filepath.Join(t.TempDir(), "scan", "acme.json")
+	options := GitHubOrgScanOptions{
+		Organization:   "acme",
+		TargetModule:   "github.com/acme/lib",
+		TargetAliases:  []string{"github.com/acme/lib-legacy"},
+		CheckpointPath: checkpointPath,
+	}
+
+	_, err = ScanGitHubOrg(context.Background(), client, options)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the scan to stop at the rate limit, got %v", err)
+	}
+	if waits != 1 {
+		t.Fatalf("expected one rate limit wait, got %d", waits)
+	}
+
+	data, err := os.ReadFile(checkpointPath)
+	if err != nil {
+		t.Fatalf("expected a checkpoint after the rate limit: %v", err)
+	}
+	var checkpoint GitHubOrgScanCheckpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		t.Fatalf("decode checkpoint: %v", err)
+	}
+	if checkpoint.Page != 1 || checkpoint.Offset != 1 || checkpoint.Scanned != 1 || len(checkpoint.Dependents) != 1 {
+		t.Fatalf("unexpected checkpoint: %+v", checkpoint)
+	}
+
+	githubScanWait = func(ctx context.Context, until time.Time) error { return nil }
+	var progress []GitHubOrgScanProgress
+	var found []string
+	options.Progress = func(p GitHubOrgScanProgress) { progress = append(progress, p) }
+	options.Found = func(dep DependentOptions) { found = append(found, dep.Repository) }
+
+	dependents, err := ScanGitHubOrg(context.Background(), client, options)
+	if err != nil {
+		t.Fatalf("resumed scan failed: %v", err)
+	}
+
+	var repos []string
+	for _, dep := range dependents {
+		repos = append(repos, dep.Repository)
+	}
+	if fmt.Sprint(repos) != "[acme/a acme/b acme/f]" {
+		t.Fatalf("unexpected dependents: %v", repos)
+	}
+	if dependents[2].LocalModulePath != "." || dependents[2].DiscoverySource != "github" {
+		t.Fatalf("unexpected dependent options: %+v", dependents[2])
+	}
+	if fmt.Sprint(found) != "[acme/b acme/f]" {
+		t.Fatalf("expected only new dependents to be streamed, got %v", found)
+	}
+	if reads["a"] != 1 {
+		t.Fatalf("expected acme/a to be read once across both scans, got %d", reads["a"])
+	}
+	if reads["d"] != 0 {
+		t.Fatalf("expected the archived repository to be skipped, got %d reads", reads["d"])
+	}
+	if len(progress) == 0 || !progress[0].Resumed {
+		t.Fatalf("expected the first progress report to mark the resume, got %+v", progress)
+	}
+	if last := progress[len(progress)-1]; last.Scanned != 6 || last.Dependents != 3 {
+		t.Fatalf("unexpected final progress: %+v", last)
+	}
+	if _, err := os.Stat(checkpointPath); !os.IsNotExist(err) {
+		t.Fatalf("expected the checkpoint to be removed after a complete scan, got %v", err)
+	}
+}
+
+func TestScanGitHubOrg_RejectsCheckpointOfAnotherScan(t *testing.T) {
+	checkpointPath := filepath.Join(t.TempDir(), "acme.json")
+	if err := saveGitHubOrgScanCheckpoint(checkpointPath, &GitHubOrgScanCheckpoint{Organization: "acme", TargetModule: "github.com/acme/other", Page: 2}); err != nil {
+		t.Fatalf("save checkpoint: %v", err)
+	}
+
+	client, err := createMockGitHubClient("http://127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	_, err = ScanGitHubOrg(context.Background(), client, GitHubOrgScanOptions{
+		Organization:   "acme",
+		TargetModule:   "github.com/acme/lib",
+		CheckpointPath: checkpointPath,
+	})
+	if err == nil {
+		t.Fatal("expected a checkpoint for another module to be rejected")
+	}
+}
@@ -0,0 +1,49 @@
+package manifest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"golang.org/x/mod/modfile"
+)
+
+// readGoWorkUses returns the module directories the go.work file at path lists
+// in its use directives, as absolute paths.
+func readGoWorkUses(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read go.work: %w", err)
+	}
+	work, err := modfile.ParseWork(path, data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("parse go.work: %w", err)
+	}
+
+	dir, err := filepath.Abs(filepath.Dir(path))
+	if err != nil {
+		return nil, err
+	}
+	uses := make([]string, 0, len(work.Use))
+	for _, use := range work.Use {
+		usePath := filepath.FromSlash(use.Path)
+		if !filepath.IsAbs(usePath) {
+			usePath = filepath.Join(dir, usePath)
+		}
+		uses = append(uses, filepath.Clean(usePath))
+	}
+	return uses, nil
+}
+
+// discoveryGoCommand returns a go command run in dir with GOWORK=off. A module
+// used by a go.work file together with other modules is then inspected through
+// its own go.mod only, rather than through the requirements of the whole
+// workspace.
+func discoveryGoCommand(ctx context.Context, dir string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "go", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOWORK=off")
+	return cmd
+}
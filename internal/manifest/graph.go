@@ -0,0 +1,262 @@
+package manifest
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Graph node kinds.
+const (
+	GraphNodeModule    = "module"
+	GraphNodeDependent = "dependent"
+)
+
+// Graph is the module to dependent structure declared by a manifest, along with
+// entries that look like mistakes.
+type Graph struct {
+	Nodes  []GraphNode
+	Edges  []GraphEdge
+	Issues []string
+}
+
+// GraphNode is a module or a dependent repository. A dependent whose module path is
+// itself a manifest module shares that module's node, so release chains read left
+// to right.
+type GraphNode struct {
+	ID     string
+	Kind   string
+	Label  string
+	Detail string
+
+	// Problem marks nodes named by an issue (orphaned or duplicated)
+	Problem bool
+}
+
+// GraphEdge connects a module to one of its dependents.
+type GraphEdge struct {
+	From   string
+	To     string
+	Skip   bool
+	Canary bool
+
+	// Duplicate marks a repeated dependent entry within the same module
+	Duplicate bool
+}
+
+// BuildGraph derives the dependency graph of m. Modules without dependents,
+// duplicate module names or paths, repeated dependents, and dependents overrides
+// keyed by an unknown module path are reported in Issues.
+func BuildGraph(m *Manifest) *Graph {
+	g := &Graph{}
+	if m == nil {
+		return g
+	}
+
+	nodeIndex := make(map[string]int)
+	addNode := func(key string, node GraphNode) int {
+		if idx, ok := nodeIndex[key]; ok {
+			return idx
+		}
+		node.ID = fmt.Sprintf("n%d", len(g.Nodes))
+		g.Nodes = append(g.Nodes, node)
+		nodeIndex[key] = len(g.Nodes) - 1
+		return len(g.Nodes) - 1
+	}
+
+	// Register modules first so dependents that are also modules resolve to them
+	moduleNodes := make([]int, len(m.Modules))
+	seenNames := make(map[string]bool)
+	for i, module := range m.Modules {
+		label := module.Name
+		if label == "" {
+			label = module.Module
+		}
+
+		key := "module:" + module.Module
+		if module.Module == "" {
+			key = fmt.Sprintf("module:#%d", i)
+		}
+		_, duplicatePath := nodeIndex[key]
+		moduleNodes[i] = addNode(key, GraphNode{Kind: GraphNodeModule, Label: label, Detail: module.Module})
+
+		if duplicatePath {
+			g.Issues = append(g.Issues, fmt.Sprintf("module path %s is declared by more than one module", module.Module))
+			g.Nodes[moduleNodes[i]].Problem = true
+		}
+		if module.Name != "" && seenNames[module.Name] {
+			g.Issues = append(g.Issues, fmt.Sprintf("duplicate module name: %s", module.Name))
+			g.Nodes[moduleNodes[i]].Problem = true
+		}
+		seenNames[module.Name] = true
+	}
+
+	// Aliases resolve to their module unless another module claims the path outright
+	for i, module := range m.Modules {
+		for _, alias := range NormalizeAliases(module.Module, module.Aliases) {
+			if _, ok := nodeIndex["module:"+alias]; !ok {
+				nodeIndex["module:"+alias] = moduleNodes[i]
+			}
+		}
+	}
+
+	for i, module := range m.Modules {
+		from := moduleNodes[i]
+		if len(module.Dependents) == 0 {
+			g.Issues = append(g.Issues, fmt.Sprintf("module %s has no dependents", g.Nodes[from].Label))
+			g.Nodes[from].Problem = true
+			continue
+		}
+
+		seenRepos := make(map[string]bool)
+		for _, dep := range module.Dependents {
+			to, ok := nodeIndex["module:"+dep.Module]
+			if !ok || dep.Module == "" {
+				to = addNode("repo:"+dep.Repo, GraphNode{Kind: GraphNodeDependent, Label: dep.Repo, Detail: dep.Module})
+			}
+
+			edge := GraphEdge{From: g.Nodes[from].ID, To: g.Nodes[to].ID, Skip: dep.Skip, Canary: dep.Canary}
+			if seenRepos[dep.Repo] {
+				g.Issues = append(g.Issues, fmt.Sprintf("module %s lists dependent %s more than once", g.Nodes[from].Label, dep.Repo))
+				g.Nodes[to].Problem = true
+				edge.Duplicate = true
+			}
+			seenRepos[dep.Repo] = true
+			g.Edges = append(g.Edges, edge)
+		}
+	}
+
+	keys := make([]string, 0, len(m.Dependents))
+	for key := range m.Dependents {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if _, ok := nodeIndex["module:"+key]; !ok {
+			g.Issues = append(g.Issues, fmt.Sprintf("dependents[%s] overrides a module that is not declared in modules", key))
+		}
+	}
+
+	return g
+}
+
+// WriteDOT renders the graph in Graphviz DOT format.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("digraph cascade {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [fontname=\"Helvetica\"];\n")
+
+	for _, node := range g.Nodes {
+		attrs := []string{"label=" + dotQuote(graphNodeLabel(node, "\n"))}
+		if node.Kind == GraphNodeModule {
+			attrs = append(attrs, "shape=box", "style=bold")
+		} else {
+			attrs = append(attrs, "shape=ellipse")
+		}
+		if node.Problem {
+			attrs = append(attrs, "color=red")
+		}
+		fmt.Fprintf(&b, "  %s [%s];\n", node.ID, strings.Join(attrs, ", "))
+	}
+
+	for _, edge := range g.Edges {
+		var attrs, labels []string
+		if edge.Skip {
+			attrs = append(attrs, "style=dashed")
+			labels = append(labels, "skip")
+		}
+		if edge.Canary {
+			attrs = append(attrs, "penwidth=2")
+			labels = append(labels, "canary")
+		}
+		if edge.Duplicate {
+			attrs = append(attrs, "color=red")
+			labels = append(labels, "duplicate")
+		}
+		if len(labels) > 0 {
+			attrs = append(attrs, "label="+dotQuote(strings.Join(labels, ", ")))
+		}
+
+		if len(attrs) == 0 {
+			fmt.Fprintf(&b, "  %s -> %s;\n", edge.From, edge.To)
+			continue
+		}
+		fmt.Fprintf(&b, "  %s -> %s [%s];\n", edge.From, edge.To, strings.Join(attrs, ", "))
+	}
+
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteMermaid renders the graph as a Mermaid flowchart.
+func (g *Graph) WriteMermaid(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("graph LR\n")
+
+	var problems []string
+	for _, node := range g.Nodes {
+		label := mermaidQuote(graphNodeLabel(node, "<br/>"))
+		if node.Kind == GraphNodeModule {
+			fmt.Fprintf(&b, "  %s[%s]\n", node.ID, label)
+		} else {
+			fmt.Fprintf(&b, "  %s(%s)\n", node.ID, label)
+		}
+		if node.Problem {
+			problems = append(problems, node.ID)
+		}
+	}
+
+	for _, edge := range g.Edges {
+		var labels []string
+		if edge.Skip {
+			labels = append(labels, "skip")
+		}
+		if edge.Canary {
+			labels = append(labels, "canary")
+		}
+		if edge.Duplicate {
+			labels = append(labels, "duplicate")
+		}
+		label := strings.Join(labels, ", ")
+
+		switch {
+		case edge.Skip:
+			fmt.Fprintf(&b, "  %s -. %s .-> %s\n", edge.From, label, edge.To)
+		case edge.Canary:
+			fmt.Fprintf(&b, "  %s == %s ==> %s\n", edge.From, label, edge.To)
+		case label != "":
+			fmt.Fprintf(&b, "  %s -- %s --> %s\n", edge.From, label, edge.To)
+		default:
+			fmt.Fprintf(&b, "  %s --> %s\n", edge.From, edge.To)
+		}
+	}
+
+	if len(problems) > 0 {
+		b.WriteString("  classDef problem stroke:#d73a49,stroke-width:2px\n")
+		fmt.Fprintf(&b, "  class %s problem\n", strings.Join(problems, ","))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func graphNodeLabel(node GraphNode, sep string) string {
+	if node.Detail == "" || node.Detail == node.Label {
+		return node.Label
+	}
+	return node.Label + sep + node.Detail
+}
+
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
+
+func mermaidQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "#quot;") + `"`
+}
@@ -0,0 +1,152 @@
+package manifest
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func graphTestManifest() *Manifest {
+	return &Manifest{
+		ManifestVersion: 1,
+		Modules: []Module{
+			{
+				Name:   "errors",
+				Module: "github.com/example/errors",
+				Repo:   "example/errors",
+				Dependents: []Dependent{
+					{Repo: "example/router", Module: "github.com/example/router"},
+					{Repo: "example/app", Module: "github.com/example/app", Canary: true},
+					{Repo: "example/legacy", Module: "github.com/example/legacy", Skip: true},
+				},
+			},
+			{
+				Name:   "router",
+				Module: "github.com/example/router",
+				Repo:   "example/router",
+				Dependents: []Dependent{
+					{Repo: "example/app", Module: "github.com/example/app"},
+					{Repo: "example/app", Module: "github.com/example/app"},
+				},
+			},
+			{
+				Name:   "unused",
+				Module: "github.com/example/unused",
+				Repo:   "example/unused",
+			},
+		},
+		Dependents: map[string]DependentConfig{
+			"github.com/example/errors": {},
+			"github.com/example/gone":   {},
+		},
+	}
+}
+
+func TestBuildGraph(t *testing.T) {
+	g := BuildGraph(graphTestManifest())
+
+	// errors, router, unused, app, legacy: router is shared with its dependent entry
+	if len(g.Nodes) != 5 {
+		t.Fatalf("expected 5 nodes, got %d: %+v", len(g.Nodes), g.Nodes)
+	}
+	if len(g.Edges) != 5 {
+		t.Fatalf("expected 5 edges, got %d", len(g.Edges))
+	}
+
+	chain := g.Edges[0]
+	if chain.From != "n0" || chain.To != "n1" {
+		t.Errorf("expected errors -> router chain edge, got %+v", chain)
+	}
+	if !g.Edges[1].Canary || !g.Edges[2].Skip {
+		t.Errorf("expected canary and skip flags on edges, got %+v", g.Edges[1:3])
+	}
+	if g.Edges[3].Duplicate || !g.Edges[4].Duplicate {
+		t.Errorf("expected only the repeated dependent to be marked duplicate, got %+v", g.Edges[3:])
+	}
+
+	wantIssues := []string{
+		"module router lists dependent example/app more than once",
+		"module unused has no dependents",
+		"dependents[github.com/example/gone] overrides a module that is not declared in modules",
+	}
+	if len(g.Issues) != len(wantIssues) {
+		t.Fatalf("expected issues %v, got %v", wantIssues, g.Issues)
+	}
+	for i, want := range wantIssues {
+		if g.Issues[i] != want {
+			t.Errorf("issue %d = %q, want %q", i, g.Issues[i], want)
+		}
+	}
+}
+
+func TestBuildGraph_DuplicateModules(t *testing.T) {
+	m := &Manifest{
+		Modules: []Module{
+			{Name: "lib", Module: "github.com/example/lib", Dependents: []Dependent{{Repo: "example/a"}}},
+			{Name: "lib", Module: "github.com/example/lib", Dependents: []Dependent{{Repo: "example/b"}}},
+		},
+	}
+
+	g := BuildGraph(m)
+	if len(g.Issues) != 2 {
+		t.Fatalf("expected duplicate path and name issues, got %v", g.Issues)
+	}
+	if !g.Nodes[0].Problem {
+		t.Error("expected duplicated module node to be flagged")
+	}
+}
+
+func TestGraph_WriteDOT(t *testing.T) {
+	var out bytes.Buffer
+	if err := BuildGraph(graphTestManifest()).WriteDOT(&out); err != nil {
+		t.Fatalf("WriteDOT: %v", err)
+	}
+
+	got := out.String()
+	for _, want := range []string{
+		"digraph cascade {\n",
+		`n0 [label="errors\ngithub.com/example/errors", shape=box, style=bold];`,
+		`n2 [label="unused\ngithub.com/example/unused", shape=box, style=bold, color=red];`,
+		`n3 [label="example/app\ngithub.com/example/app", shape=ellipse, color=red];`,
+		"n0 -> n1;\n",
+		`n0 -> n3 [penwidth=2, label="canary"];`,
+		`n0 -> n4 [style=dashed, label="skip"];`,
+		`n1 -> n3 [color=red, label="duplicate"];`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("DOT output missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestGraph_WriteMermaid(t *testing.T) {
+	var out bytes.Buffer
+	if err := BuildGraph(graphTestManifest()).WriteMermaid(&out); err != nil {
+		t.Fatalf("WriteMermaid: %v", err)
+	}
+
+	got := out.String()
+	for _, want := range []string{
+		"graph LR\n",
+		`n0["errors<br/>github.com/example/errors"]`,
+		`n4("example/legacy<br/>github.com/example/legacy")`,
+		"n0 --> n1\n",
+		"n0 == canary ==> n3\n",
+		"n0 -. skip .-> n4\n",
+		"n1 -- duplicate --> n3\n",
+		"class n2,n3 problem\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Mermaid output missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestGraphQuoting(t *testing.T) {
+	if got := dotQuote("a \"b\"\nc"); got != `"a \"b\"\nc"` {
+		t.Errorf("dotQuote = %s", got)
+	}
+	if got := mermaidQuote(`say "hi"`); got != `"say #quot;hi#quot;"` {
+		t.Errorf("mermaidQuote = %s", got)
+	}
+}
@@ -2,6 +2,7 @@ package manifest
 
 import (
 	"os"
+	"path/filepath"
 
 	"gopkg.in/yaml.v3"
 )
@@ -25,6 +26,33 @@ func (l *loader) Load(path string) (*Manifest, error) {
 		return nil, &LoadError{Path: path, Err: err}
 	}
 
+	manifest, err := parse(path, data)
+	if err != nil {
+		return nil, err
+	}
+	resolveTemplateFiles(manifest, filepath.Dir(path))
+	return manifest, nil
+}
+
+// Parse decodes manifest content that was not read through a Loader, such as
+// the copy stored with a run's state. source names the content in errors.
+func Parse(source string, data []byte) (*Manifest, error) {
+	return parse(source, data)
+}
+
+// ParseFile decodes content of the manifest file at path that was read some
+// other way, such as from an earlier git revision. Relative template files
+// resolve against the directory of path, as they do for Load.
+func ParseFile(path string, data []byte) (*Manifest, error) {
+	manifest, err := parse(path, data)
+	if err != nil {
+		return nil, err
+	}
+	resolveTemplateFiles(manifest, filepath.Dir(path))
+	return manifest, nil
+}
+
+func parse(path string, data []byte) (*Manifest, error) {
 	var manifest Manifest
 	if err := yaml.Unmarshal(data, &manifest); err != nil {
 		return nil, &ParseError{Path: path, Err: err}
@@ -40,6 +68,33 @@ func (l *loader) Generate(workdir string) (*Manifest, error) {
 	return nil, &GenerateError{WorkDir: workdir, Reason: "not supported"}
 }
 
+// resolveTemplateFiles makes relative PR body template files absolute against
+// dir, the directory of the manifest declaring them, so they still resolve
+// when the plan runs elsewhere.
+func resolveTemplateFiles(m *Manifest, dir string) {
+	resolve := func(pr *PRConfig) {
+		if pr.BodyTemplateFile != "" && !filepath.IsAbs(pr.BodyTemplateFile) {
+			if abs, err := filepath.Abs(filepath.Join(dir, pr.BodyTemplateFile)); err == nil {
+				pr.BodyTemplateFile = abs
+			}
+		}
+	}
+
+	resolve(&m.Defaults.PR)
+	if m.Module != nil {
+		resolve(&m.Module.PR)
+	}
+	for i := range m.Modules {
+		for j := range m.Modules[i].Dependents {
+			resolve(&m.Modules[i].Dependents[j].PR)
+		}
+	}
+	for key, dep := range m.Dependents {
+		resolve(&dep.PR)
+		m.Dependents[key] = dep
+	}
+}
+
 // normalizeManifest ensures all slices are non nil after YAML unmarshaling
 // so that validation doesn't reject manifests that omit optional fields.
 func normalizeManifest(m *Manifest) {
@@ -5,8 +5,10 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/goliatone/cascade/internal/manifest"
 	"github.com/goliatone/cascade/pkg/testsupport"
@@ -112,6 +114,31 @@ func TestLoader_Load_ErrorCases(t *testing.T) {
 	}
 }
 
+func TestParse_MatchesLoad(t *testing.T) {
+	path := filepath.Join("testdata", "basic.yaml")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read manifest: %v", err)
+	}
+	loaded, err := manifest.NewLoader().Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	parsed, err := manifest.Parse("stored inputs", data)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if !reflect.DeepEqual(parsed, loaded) {
+		t.Errorf("Parse() = %+v, want %+v", parsed, loaded)
+	}
+
+	_, err = manifest.Parse("stored inputs", []byte("modules: [unterminated"))
+	var parseErr *manifest.ParseError
+	if !errors.As(err, &parseErr) || parseErr.Path != "stored inputs" {
+		t.Errorf("Parse() error = %v, want ParseError naming the source", err)
+	}
+}
+
 func TestLoader_Generate_NotSupported(t *testing.T) {
 	loader := manifest.NewLoader()
 	_, err := loader.Generate("/tmp")
@@ -517,6 +544,369 @@ func TestValidate_SchemaErrors(t *testing.T) {
 	}
 }
 
+func TestValidate_DependentCheckOverrides(t *testing.T) {
+	m := &manifest.Manifest{
+		ManifestVersion: 1,
+		Modules: []manifest.Module{{
+			Name:   "go-errors",
+			Module: "github.com/goliatone/go-errors",
+			Repo:   "goliatone/go-errors",
+			Dependents: []manifest.Dependent{
+				{Repo: "goliatone/a", Module: "github.com/goliatone/a", ModulePath: ".", CheckStrategy: "remote", CheckCacheTTL: time.Minute},
+				{Repo: "goliatone/b", Module: "github.com/goliatone/b", ModulePath: ".", CheckStrategy: "sometimes"},
+				{Repo: "goliatone/c", Module: "github.com/goliatone/c", ModulePath: ".", CheckCacheTTL: -time.Second},
+			},
+		}},
+	}
+
+	err := manifest.Validate(m)
+	if err == nil {
+		t.Fatalf("Validate expected error but got none")
+	}
+
+	issues, _ := manifest.GetValidationIssues(err)
+	if len(issues) != 2 {
+		t.Fatalf("expected 2 issues, got %d: %v", len(issues), issues)
+	}
+	if !strings.Contains(issues[0], `check_strategy must be one of local, remote, auto (got "sometimes")`) {
+		t.Errorf("unexpected strategy issue: %s", issues[0])
+	}
+	if !strings.Contains(issues[1], "check_cache_ttl cannot be negative") {
+		t.Errorf("unexpected ttl issue: %s", issues[1])
+	}
+}
+
+func TestValidate_DependentUpdateStrategy(t *testing.T) {
+	m := &manifest.Manifest{
+		ManifestVersion: 1,
+		Modules: []manifest.Module{{
+			Name:   "go-errors",
+			Module: "github.com/goliatone/go-errors",
+			Repo:   "goliatone/go-errors",
+			Dependents: []manifest.Dependent{
+				{Repo: "goliatone/a", Module: "github.com/goliatone/a", ModulePath: ".", UpdateStrategy: "git-submodule"},
+				{Repo: "goliatone/b", Module: "github.com/goliatone/b", ModulePath: ".", UpdateStrategy: "git-submodule", SubmodulePath: "third_party/go-errors"},
+				{Repo: "goliatone/c", Module: "github.com/goliatone/c", ModulePath: ".", UpdateStrategy: "vendor"},
+				{Repo: "goliatone/d", Module: "github.com/goliatone/d", ModulePath: ".", SubmodulePath: "lib"},
+				{Repo: "goliatone/e", Module: "github.com/goliatone/e", ModulePath: ".", UpdateStrategy: "git-submodule", SubmodulePath: "../go-errors"},
+			},
+		}},
+	}
+
+	err := manifest.Validate(m)
+	if err == nil {
+		t.Fatalf("Validate expected error but got none")
+	}
+
+	issues, _ := manifest.GetValidationIssues(err)
+	want := []string{
+		`(goliatone/c) update_strategy must be one of go-modules, git-submodule (got "vendor")`,
+		"(goliatone/d) submodule_path requires update_strategy git-submodule",
+		`(goliatone/e) submodule_path must be relative to the repository (got "../go-errors")`,
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("expected %d issues, got %d: %v", len(want), len(issues), issues)
+	}
+	for i, issue := range issues {
+		if !strings.Contains(issue, want[i]) {
+			t.Errorf("issue[%d] = %s, want to contain %s", i, issue, want[i])
+		}
+	}
+}
+
+func TestValidate_DependentProvider(t *testing.T) {
+	m := &manifest.Manifest{
+		ManifestVersion: 1,
+		Modules: []manifest.Module{{
+			Name:   "go-errors",
+			Module: "github.com/goliatone/go-errors",
+			Repo:   "goliatone/go-errors",
+			Dependents: []manifest.Dependent{
+				{Repo: "team/a", Module: "bitbucket.org/team/a", ModulePath: ".", Provider: manifest.ProviderBitbucket},
+				{Repo: "team/b", Module: "git.example.com/team/b", ModulePath: ".", Provider: "gitea"},
+			},
+		}},
+	}
+
+	err := manifest.Validate(m)
+	issues, _ := manifest.GetValidationIssues(err)
+	if len(issues) != 1 || !strings.Contains(issues[0], `(team/b) provider must be one of github, gitlab, bitbucket, sandbox (got "gitea")`) {
+		t.Fatalf("issues = %v, want one provider issue for team/b", issues)
+	}
+}
+
+func TestValidate_DependentCloneProtocol(t *testing.T) {
+	m := &manifest.Manifest{
+		ManifestVersion: 1,
+		Modules: []manifest.Module{{
+			Name:   "go-errors",
+			Module: "github.com/goliatone/go-errors",
+			Repo:   "goliatone/go-errors",
+			Dependents: []manifest.Dependent{
+				{Repo: "team/a", Module: "github.com/team/a", ModulePath: ".", CloneProtocol: manifest.CloneProtocolSSH},
+				{Repo: "team/b", Module: "github.com/team/b", ModulePath: ".", CloneProtocol: "git"},
+			},
+		}},
+	}
+
+	err := manifest.Validate(m)
+	issues, _ := manifest.GetValidationIssues(err)
+	if len(issues) != 1 || !strings.Contains(issues[0], `(team/b) clone_protocol must be one of https, ssh (got "git")`) {
+		t.Fatalf("issues = %v, want one clone_protocol issue for team/b", issues)
+	}
+}
+
+func TestValidate_Changelog(t *testing.T) {
+	m := &manifest.Manifest{
+		ManifestVersion: 1,
+		Defaults: manifest.Defaults{
+			Changelog: manifest.Changelog{Format: manifest.ChangelogKeepAChangelog, File: "/srv/CHANGELOG.md"},
+		},
+		Modules: []manifest.Module{{
+			Name:   "go-errors",
+			Module: "github.com/goliatone/go-errors",
+			Repo:   "goliatone/go-errors",
+			Dependents: []manifest.Dependent{
+				{Repo: "team/a", Module: "github.com/team/a", ModulePath: ".", Changelog: manifest.Changelog{Format: manifest.ChangelogFragment, Dir: "changes/unreleased"}},
+				{Repo: "team/b", Module: "github.com/team/b", ModulePath: ".", Changelog: manifest.Changelog{Format: "towncrier", Dir: "../changes", Template: "{{.Name"}},
+			},
+		}},
+	}
+
+	err := manifest.Validate(m)
+	issues, _ := manifest.GetValidationIssues(err)
+	want := []string{
+		`defaults.changelog.file must be a path inside the repository (got "/srv/CHANGELOG.md")`,
+		`(team/b) changelog.format must be one of keep-a-changelog, fragment, conventional (got "towncrier")`,
+		`(team/b) changelog.dir must be a path inside the repository (got "../changes")`,
+		`(team/b) changelog.template is invalid`,
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("issues = %v, want %d changelog issues", issues, len(want))
+	}
+	for i, fragment := range want {
+		if !strings.Contains(issues[i], fragment) {
+			t.Errorf("issue %d = %q, want it to contain %q", i, issues[i], fragment)
+		}
+	}
+}
+
+func TestValidate_PRBase(t *testing.T) {
+	m := &manifest.Manifest{
+		ManifestVersion: 1,
+		Defaults:        manifest.Defaults{PR: manifest.PRConfig{Base: "release/1.x"}},
+		Modules: []manifest.Module{{
+			Name:   "go-errors",
+			Module: "github.com/goliatone/go-errors",
+			Repo:   "goliatone/go-errors",
+			Dependents: []manifest.Dependent{
+				{Repo: "team/a", Module: "github.com/team/a", ModulePath: ".", PR: manifest.PRConfig{Base: "release/../1.x"}},
+			},
+		}},
+	}
+
+	err := manifest.Validate(m)
+	issues, _ := manifest.GetValidationIssues(err)
+	if len(issues) != 1 || !strings.Contains(issues[0], `(team/a) pr.base "release/../1.x" is not a valid branch name`) {
+		t.Fatalf("issues = %v, want one pr.base issue for team/a", issues)
+	}
+}
+
+func TestValidate_PRAutoMerge(t *testing.T) {
+	m := &manifest.Manifest{
+		ManifestVersion: 1,
+		Defaults:        manifest.Defaults{PR: manifest.PRConfig{AutoMerge: "squash"}},
+		Modules: []manifest.Module{{
+			Name:   "go-errors",
+			Module: "github.com/goliatone/go-errors",
+			Repo:   "goliatone/go-errors",
+			Dependents: []manifest.Dependent{
+				{Repo: "team/a", Module: "github.com/team/a", ModulePath: ".", PR: manifest.PRConfig{AutoMerge: "fast-forward"}},
+				{Repo: "team/b", Module: "github.com/team/b", ModulePath: ".", PR: manifest.PRConfig{AutoMerge: "rebase"}},
+			},
+		}},
+	}
+
+	err := manifest.Validate(m)
+	issues, _ := manifest.GetValidationIssues(err)
+	if len(issues) != 1 || !strings.Contains(issues[0], `(team/a) pr.auto_merge "fast-forward" must be squash, merge, or rebase`) {
+		t.Fatalf("issues = %v, want one pr.auto_merge issue for team/a", issues)
+	}
+}
+
+func TestValidate_DependentGoWork(t *testing.T) {
+	m := &manifest.Manifest{
+		ManifestVersion: 1,
+		Modules: []manifest.Module{{
+			Name:   "go-errors",
+			Module: "github.com/goliatone/go-errors",
+			Repo:   "goliatone/go-errors",
+			Dependents: []manifest.Dependent{
+				{Repo: "team/a", Module: "github.com/team/a", ModulePath: ".", GoWork: manifest.GoWorkOff},
+				{Repo: "team/b", Module: "github.com/team/b", ModulePath: ".", GoWork: "sync"},
+			},
+		}},
+	}
+
+	err := manifest.Validate(m)
+	issues, _ := manifest.GetValidationIssues(err)
+	if len(issues) != 1 || !strings.Contains(issues[0], `(team/b) go_work must be one of respect, off (got "sync")`) {
+		t.Fatalf("issues = %v, want one go_work issue for team/b", issues)
+	}
+}
+
+func TestValidate_VersionResolution(t *testing.T) {
+	m := &manifest.Manifest{
+		ManifestVersion: 1,
+		Modules: []manifest.Module{{
+			Name:              "go-errors",
+			Module:            "github.com/goliatone/go-errors",
+			Repo:              "goliatone/go-errors",
+			Dependents:        []manifest.Dependent{},
+			VersionResolution: []string{"local", "proxy", "local", "release-api", " "},
+		}},
+	}
+
+	err := manifest.Validate(m)
+	if err == nil {
+		t.Fatalf("Validate expected error but got none")
+	}
+
+	issues, _ := manifest.GetValidationIssues(err)
+	want := []string{
+		"version_resolution lists local more than once",
+		`version_resolution has unknown resolver "release-api" (available: git-remote, local, proxy, tags`,
+		"version_resolution cannot contain empty entries",
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("expected %d issues, got %d: %v", len(want), len(issues), issues)
+	}
+	for i, issue := range issues {
+		if !strings.Contains(issue, want[i]) {
+			t.Errorf("issue[%d] = %s, want to contain %s", i, issue, want[i])
+		}
+	}
+
+	m.Modules[0].VersionResolution = []string{"tags", "git-remote"}
+	if err := manifest.Validate(m); err != nil {
+		t.Errorf("Validate() error = %v, want built-in resolvers to be accepted", err)
+	}
+}
+
+func TestValidate_DependentServices(t *testing.T) {
+	m := &manifest.Manifest{
+		ManifestVersion: 1,
+		Modules: []manifest.Module{{
+			Name:   "go-errors",
+			Module: "github.com/goliatone/go-errors",
+			Repo:   "goliatone/go-errors",
+			Dependents: []manifest.Dependent{
+				{Repo: "goliatone/a", Module: "github.com/goliatone/a", ModulePath: ".", Services: []manifest.Service{{Name: "db", Image: "postgres:16", Port: 5432}}},
+				{Repo: "goliatone/b", Module: "github.com/goliatone/b", ModulePath: ".", Services: []manifest.Service{{Name: "db"}}},
+			},
+		}},
+	}
+
+	issues, _ := manifest.GetValidationIssues(manifest.Validate(m))
+	want := []string{"module[0] (go-errors) dependent[1] (goliatone/b) services[0] (db) image cannot be empty"}
+	if !reflect.DeepEqual(issues, want) {
+		t.Fatalf("issues = %#v, want %#v", issues, want)
+	}
+}
+
+func TestValidate_DependentCredentials(t *testing.T) {
+	m := &manifest.Manifest{
+		ManifestVersion: 1,
+		Modules: []manifest.Module{{
+			Name:   "go-errors",
+			Module: "github.com/goliatone/go-errors",
+			Repo:   "goliatone/go-errors",
+			Dependents: []manifest.Dependent{
+				{Repo: "goliatone/a", Module: "github.com/goliatone/a", ModulePath: ".", GoFlags: "-mod=mod",
+					Credentials: []manifest.Credential{{Machine: "git.example.com", Login: "ci", PasswordEnv: "EXAMPLE_TOKEN"}}},
+				{Repo: "goliatone/b", Module: "github.com/goliatone/b", ModulePath: ".",
+					Credentials: []manifest.Credential{
+						{Machine: "https://git.example.com", Login: "ci", PasswordEnv: "EXAMPLE-TOKEN"},
+						{Machine: "proxy.example.com"},
+					}},
+			},
+		}},
+	}
+
+	issues, _ := manifest.GetValidationIssues(manifest.Validate(m))
+	want := []string{
+		`module[0] (go-errors) dependent[1] (goliatone/b) credentials[0] machine "https://git.example.com" must be a bare host name`,
+		`module[0] (go-errors) dependent[1] (goliatone/b) credentials[0] (https://git.example.com) password_env "EXAMPLE-TOKEN" is not a valid environment variable name`,
+		"module[0] (go-errors) dependent[1] (goliatone/b) credentials[1] (proxy.example.com) login cannot be empty",
+		"module[0] (go-errors) dependent[1] (goliatone/b) credentials[1] (proxy.example.com) password_env cannot be empty",
+	}
+	if !reflect.DeepEqual(issues, want) {
+		t.Fatalf("issues = %#v, want %#v", issues, want)
+	}
+}
+
+func TestValidate_GitHubIssueNotifications(t *testing.T) {
+	m := &manifest.Manifest{
+		ManifestVersion: 1,
+		Defaults: manifest.Defaults{Notifications: manifest.Notifications{
+			GitHubIssues: &manifest.GitHubIssueNotification{Enabled: true, OnRecurrence: "reopen-or-comment"},
+		}},
+		Modules: []manifest.Module{{
+			Name:   "go-errors",
+			Module: "github.com/goliatone/go-errors",
+			Repo:   "goliatone/go-errors",
+			Dependents: []manifest.Dependent{
+				{Repo: "goliatone/a", Module: "github.com/goliatone/a", ModulePath: ".",
+					Notifications: manifest.Notifications{GitHubIssues: &manifest.GitHubIssueNotification{
+						Enabled: true, OnRecurrence: manifest.IssueRecurrenceComment, Assignees: []string{"@octocat", ""},
+					}}},
+			},
+		}},
+	}
+
+	issues, _ := manifest.GetValidationIssues(manifest.Validate(m))
+	want := []string{
+		`defaults.notifications.github_issues.on_recurrence must be one of ignore, comment, reopen (got "reopen-or-comment")`,
+		`module[0] (go-errors) dependent[0] (goliatone/a) notifications.github_issues.assignees[0] "@octocat" must be a GitHub login`,
+		`module[0] (go-errors) dependent[0] (goliatone/a) notifications.github_issues.assignees[1] "" must be a GitHub login`,
+	}
+	if !reflect.DeepEqual(issues, want) {
+		t.Fatalf("issues = %#v, want %#v", issues, want)
+	}
+}
+
+func TestExpandDefaults_GitHubIssueNotifications(t *testing.T) {
+	defaults := manifest.Defaults{Notifications: manifest.Notifications{
+		GitHubIssues: &manifest.GitHubIssueNotification{
+			Enabled:       true,
+			Labels:        []string{"cascade-failure"},
+			TitleTemplate: "Cascade: {{.Repo}}",
+			BodyTemplate:  "{{.Reason}}",
+			Assignees:     []string{"platform-oncall"},
+			OnRecurrence:  manifest.IssueRecurrenceReopen,
+		},
+	}}
+	dep := manifest.Dependent{Notifications: manifest.Notifications{
+		GitHubIssues: &manifest.GitHubIssueNotification{Enabled: true, BodyTemplate: "custom", OnRecurrence: manifest.IssueRecurrenceComment},
+	}}
+
+	got := manifest.ExpandDefaults(dep, defaults).Notifications.GitHubIssues
+	want := &manifest.GitHubIssueNotification{
+		Enabled:       true,
+		Labels:        []string{"cascade-failure"},
+		TitleTemplate: "Cascade: {{.Repo}}",
+		BodyTemplate:  "custom",
+		Assignees:     []string{"platform-oncall"},
+		OnRecurrence:  manifest.IssueRecurrenceComment,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("github_issues = %+v, want %+v", got, want)
+	}
+	if dep.Notifications.GitHubIssues.TitleTemplate != "" {
+		t.Fatal("ExpandDefaults modified the dependent's settings")
+	}
+}
+
 func TestValidate_CycleDetection(t *testing.T) {
 	loader := manifest.NewLoader()
 	m, err := loader.Load(filepath.Join("testdata", "invalid_cycle.yaml"))
@@ -714,3 +1104,187 @@ func TestErrorHelpers_NegativeCases(t *testing.T) {
 		t.Fatalf("GetValidationIssues should return false for regular error")
 	}
 }
+
+func TestLintDependentManifest(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       string
+		wantIssues []string
+		wantParse  bool
+	}{
+		{
+			name: "valid overrides",
+			data: `dependents:
+  github.com/goliatone/go-errors:
+    tests:
+      - cmd: ["task", "test"]
+`,
+		},
+		{
+			name:      "unknown key",
+			data:      "dependents:\n  github.com/goliatone/go-errors:\n    testz: []\n",
+			wantParse: true,
+		},
+		{
+			name: "semantic issues",
+			data: `dependents:
+  go-errors:
+    extra_commands:
+      - cmd: []
+    timeout: -1s
+`,
+			wantIssues: []string{
+				"dependents[go-errors] does not look like a Go module path",
+				"dependents[go-errors].extra_commands[0] has an empty command",
+				"dependents[go-errors].timeout cannot be negative",
+			},
+		},
+		{
+			name: "valid services",
+			data: `module:
+  module: github.com/example/app
+  services:
+    - name: postgres
+      image: postgres:16
+      port: 5432
+      healthcheck:
+        cmd: [pg_isready]
+        interval: 2s
+      export:
+        DATABASE_URL: "postgres://{{.Host}}:{{.Port}}/app"
+`,
+		},
+		{
+			name: "invalid services",
+			data: `dependents:
+  github.com/goliatone/go-errors:
+    services:
+      - name: db
+        image: postgres:16
+        export:
+          DATABASE_URL: "{{.Host"
+      - name: db
+        port: 70000
+      - name: 1cache
+        image: redis:7
+        healthcheck:
+          interval: 1s
+`,
+			wantIssues: []string{
+				`dependents[github.com/goliatone/go-errors].services[0] (db) export DATABASE_URL is not a valid template: template: DATABASE_URL:1: unclosed action`,
+				`dependents[github.com/goliatone/go-errors].services[1] duplicate service name "db"`,
+				"dependents[github.com/goliatone/go-errors].services[1] (db) image cannot be empty",
+				"dependents[github.com/goliatone/go-errors].services[1] (db) port must be between 1 and 65535",
+				`dependents[github.com/goliatone/go-errors].services[2] name "1cache" must start with a letter and contain only letters, digits, '-' or '_'`,
+				"dependents[github.com/goliatone/go-errors].services[2] (1cache) healthcheck needs a cmd or a published port",
+			},
+		},
+		{
+			name: "invalid env template",
+			data: `module:
+  module: github.com/example/app
+  env:
+    MODULE_VERSION: "{{ .SourceVersion }}"
+dependents:
+  github.com/goliatone/go-errors:
+    env:
+      EXPECTED: "{{ .SourceVersion"
+`,
+			wantIssues: []string{
+				"dependents[github.com/goliatone/go-errors].env EXPECTED is not a valid template: template: EXPECTED:1: unclosed action",
+			},
+		},
+		{
+			name:       "empty file",
+			data:       "",
+			wantIssues: []string{"no module defaults or dependents overrides declared"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := manifest.LintDependentManifest(".cascade.yaml", []byte(tt.data))
+			if tt.wantParse {
+				if !manifest.IsParseError(err) {
+					t.Fatalf("expected parse error, got %v", err)
+				}
+				return
+			}
+			if len(tt.wantIssues) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var validationErr *manifest.ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("expected validation error, got %v", err)
+			}
+			if !reflect.DeepEqual(validationErr.Issues, tt.wantIssues) {
+				t.Fatalf("issues = %#v, want %#v", validationErr.Issues, tt.wantIssues)
+			}
+		})
+	}
+}
+
+func TestFindModuleByPath_Aliases(t *testing.T) {
+	m := &manifest.Manifest{
+		Modules: []manifest.Module{
+			{Name: "errors", Module: "github.com/goliatone/errors", Aliases: []string{"github.com/goliatone/go-errors"}},
+			{Name: "go-errors", Module: "github.com/goliatone/go-errors"},
+		},
+	}
+
+	// An exact module path wins over an alias
+	module, err := manifest.FindModuleByPath(m, "github.com/goliatone/go-errors")
+	if err != nil {
+		t.Fatalf("FindModuleByPath error: %v", err)
+	}
+	if module.Name != "go-errors" {
+		t.Fatalf("expected exact match go-errors, got %s", module.Name)
+	}
+
+	m.Modules = m.Modules[:1]
+	module, err = manifest.FindModuleByPath(m, "github.com/goliatone/go-errors")
+	if err != nil {
+		t.Fatalf("FindModuleByPath alias error: %v", err)
+	}
+	if module.Name != "errors" {
+		t.Fatalf("expected alias to resolve to errors, got %s", module.Name)
+	}
+}
+
+func TestNormalizeAliases(t *testing.T) {
+	got := manifest.NormalizeAliases("github.com/a/new", []string{" github.com/a/old ", "", "github.com/a/new", "github.com/a/old", "github.com/a/older"})
+	want := []string{"github.com/a/old", "github.com/a/older"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("NormalizeAliases = %#v, want %#v", got, want)
+	}
+	if got := manifest.NormalizeAliases("github.com/a/new", []string{"github.com/a/new"}); got != nil {
+		t.Fatalf("expected nil aliases, got %#v", got)
+	}
+}
+
+func TestValidate_ModuleAliases(t *testing.T) {
+	dependents := []manifest.Dependent{{Repo: "goliatone/a", Module: "github.com/goliatone/a", ModulePath: "."}}
+	m := &manifest.Manifest{
+		ManifestVersion: 1,
+		Modules: []manifest.Module{
+			{Name: "errors", Module: "github.com/goliatone/errors", Repo: "goliatone/errors", Aliases: []string{"github.com/goliatone/go-errors", " ", "github.com/goliatone/errors"}, Dependents: dependents},
+			{Name: "faults", Module: "github.com/goliatone/faults", Repo: "goliatone/faults", Aliases: []string{"github.com/goliatone/go-errors"}, Dependents: dependents},
+			// Listing the old path as its own module is allowed; planning merges both
+			{Name: "go-errors", Module: "github.com/goliatone/go-errors", Repo: "goliatone/go-errors", Dependents: dependents},
+		},
+	}
+
+	issues, _ := manifest.GetValidationIssues(manifest.Validate(m))
+	want := []string{
+		"module[0] (errors) alias[1] cannot be empty",
+		"module[0] (errors) alias github.com/goliatone/errors repeats the module path",
+		"module[1] (faults) alias github.com/goliatone/go-errors is already an alias of errors",
+	}
+	if !reflect.DeepEqual(issues, want) {
+		t.Fatalf("issues = %#v, want %#v", issues, want)
+	}
+}
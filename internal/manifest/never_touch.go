@@ -0,0 +1,101 @@
+package manifest
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NeverTouchError is returned when a dependent or work item names a repository
+// listed in defaults.never_touch.
+type NeverTouchError struct {
+	Repo string
+	// Entry is the never_touch entry that matched Repo.
+	Entry string
+}
+
+func (e *NeverTouchError) Error() string {
+	return fmt.Sprintf("manifest: %s is listed in defaults.never_touch (%s) and must not be changed", e.Repo, e.Entry)
+}
+
+// NeverTouch returns a *NeverTouchError when any of repos (a dependent's repo
+// and clone URL, for example) matches an entry of list, and nil otherwise.
+//
+// Entries and repositories are compared without case, scheme, ".git" suffix,
+// or SSH user, so "org/repo" matches "github.com/org/repo",
+// "https://github.com/org/repo.git", and "git@github.com:org/repo.git". An
+// entry without a host matches the repository on every host: the list is a
+// safety rail, so it errs on the side of leaving a repository alone.
+func NeverTouch(list []string, repos ...string) error {
+	for _, repo := range repos {
+		name := repoName(repo)
+		if name == "" {
+			continue
+		}
+		for _, entry := range list {
+			want := repoName(entry)
+			if want == "" {
+				continue
+			}
+			if name == want || strings.HasSuffix(name, "/"+want) || strings.HasSuffix(want, "/"+name) {
+				return &NeverTouchError{Repo: strings.TrimSpace(repo), Entry: strings.TrimSpace(entry)}
+			}
+		}
+	}
+	return nil
+}
+
+// NeverTouchDependent reports whether dependent is listed in the manifest's
+// defaults.never_touch, as a *NeverTouchError.
+func (m *Manifest) NeverTouchDependent(dependent Dependent) error {
+	if m == nil {
+		return nil
+	}
+	return NeverTouch(m.Defaults.NeverTouch, dependent.Repo, dependent.CloneURL)
+}
+
+// repoName reduces a repository reference or clone URL to a lower-case
+// host/owner/name path, or owner/name when it has no host.
+func repoName(repo string) string {
+	name := strings.ToLower(strings.TrimSpace(repo))
+	if scheme := strings.Index(name, "://"); scheme >= 0 {
+		name = name[scheme+3:]
+	} else if at := strings.Index(name, "@"); at >= 0 && strings.Contains(name[at:], ":") {
+		// scp-like SSH address: git@host:owner/name
+		name = strings.Replace(name[at+1:], ":", "/", 1)
+	}
+	if at := strings.LastIndex(name, "@"); at >= 0 && at < strings.Index(name+"/", "/") {
+		name = name[at+1:]
+	}
+	name = strings.TrimSuffix(strings.Trim(name, "/"), ".git")
+	return strings.Trim(name, "/")
+}
+
+// RepoKey reduces a repository reference to the form used to tell whether two
+// dependents name the same repository: repoName without the github.com host
+// that an owner/name reference implies.
+func RepoKey(repo string) string {
+	return strings.TrimPrefix(repoName(repo), "github.com/")
+}
+
+// lintNeverTouch reports empty defaults.never_touch entries and dependents that
+// the list forbids changing. Skipped dependents are left alone anyway and are
+// not reported.
+func lintNeverTouch(m *Manifest) []string {
+	var issues []string
+	for i, entry := range m.Defaults.NeverTouch {
+		if repoName(entry) == "" {
+			issues = append(issues, fmt.Sprintf("defaults.never_touch[%d] cannot be empty", i))
+		}
+	}
+	for i, module := range m.Modules {
+		for j, dep := range module.Dependents {
+			if dep.Skip {
+				continue
+			}
+			if err := m.NeverTouchDependent(dep); err != nil {
+				issues = append(issues, fmt.Sprintf("module[%d] (%s) dependent[%d] (%s) is listed in defaults.never_touch; remove the entry or mark it skip: true", i, module.Name, j, dep.Repo))
+			}
+		}
+	}
+	return issues
+}
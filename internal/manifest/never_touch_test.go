@@ -0,0 +1,77 @@
+package manifest
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNeverTouch(t *testing.T) {
+	list := []string{"acme/payments", "gitlab.example.com/ops/billing"}
+	tests := []struct {
+		repo string
+		want bool
+	}{
+		{repo: "acme/payments", want: true},
+		{repo: "Acme/Payments", want: true},
+		{repo: "github.com/acme/payments", want: true},
+		{repo: "https://github.com/acme/payments.git", want: true},
+		{repo: "git@github.com:acme/payments.git", want: true},
+		{repo: "ssh://git@gitlab.example.com/ops/billing.git", want: true},
+		{repo: "ops/billing", want: true},
+		{repo: "acme/payments-api", want: false},
+		{repo: "other/payments", want: false},
+		{repo: "github.com/ops/billing", want: false},
+		{repo: "", want: false},
+	}
+	for _, tt := range tests {
+		err := NeverTouch(list, tt.repo)
+		if got := err != nil; got != tt.want {
+			t.Errorf("NeverTouch(%q) = %v, want match %v", tt.repo, err, tt.want)
+		}
+		var nt *NeverTouchError
+		if tt.want && !errors.As(err, &nt) {
+			t.Errorf("NeverTouch(%q) returned %T, want *NeverTouchError", tt.repo, err)
+		}
+	}
+}
+
+func TestValidate_NeverTouch(t *testing.T) {
+	m := &Manifest{
+		ManifestVersion: 1,
+		Defaults:        Defaults{NeverTouch: []string{"acme/frozen", " "}},
+		Modules: []Module{{
+			Name:   "lib",
+			Module: "github.com/acme/lib",
+			Repo:   "acme/lib",
+			Dependents: []Dependent{
+				{Repo: "acme/app", Module: "github.com/acme/app", ModulePath: "."},
+				{Repo: "acme/frozen", Module: "github.com/acme/frozen", ModulePath: "."},
+				{Repo: "acme/other", CloneURL: "git@github.com:acme/frozen.git", Module: "github.com/acme/other", ModulePath: ".", Skip: true},
+			},
+		}},
+	}
+
+	err := Validate(m)
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected a ValidationError, got %v", err)
+	}
+	if len(verr.Issues) != 2 {
+		t.Fatalf("issues = %q, want the empty entry and acme/frozen", verr.Issues)
+	}
+	if !strings.Contains(verr.Issues[0], "never_touch[1] cannot be empty") || !strings.Contains(verr.Issues[1], "(acme/frozen) is listed in defaults.never_touch") {
+		t.Errorf("issues = %q", verr.Issues)
+	}
+}
+
+func TestRepoKey(t *testing.T) {
+	for _, repo := range []string{"acme/payments", "Acme/Payments", "github.com/acme/payments", "https://github.com/acme/payments.git", "git@github.com:acme/payments.git"} {
+		if got := RepoKey(repo); got != "acme/payments" {
+			t.Errorf("RepoKey(%q) = %q, want acme/payments", repo, got)
+		}
+	}
+	if got := RepoKey("gitlab.example.com/acme/payments"); got != "gitlab.example.com/acme/payments" {
+		t.Errorf("RepoKey() = %q, want the host kept for other hosts", got)
+	}
+}
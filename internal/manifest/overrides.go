@@ -1,15 +1,23 @@
 package manifest
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
 )
 
-const manifestFileName = ".cascade.yaml"
+// DependentManifestFileName is the file a dependent repository uses to declare
+// overrides for the modules it consumes.
+const DependentManifestFileName = ".cascade.yaml"
 
 // LoadDependentManifest loads a dependent repository's manifest if present.
 // Returns nil when the manifest file does not exist.
@@ -20,7 +28,7 @@ func LoadDependentManifest(ctx context.Context, repoPath string) (*Manifest, err
 		return nil, nil
 	}
 
-	manifestPath := filepath.Join(repoPath, manifestFileName)
+	manifestPath := filepath.Join(repoPath, DependentManifestFileName)
 	if _, err := os.Stat(manifestPath); err != nil {
 		if errors.Is(err, fs.ErrNotExist) {
 			return nil, nil
@@ -62,3 +70,85 @@ func LoadDependentOverrides(ctx context.Context, repoPath, modulePath string) (*
 	override := config
 	return &override, nil
 }
+
+// LintDependentManifest checks a dependent repository's .cascade.yaml for problems
+// that would cause overrides to be silently ignored: unknown keys, empty module
+// keys, empty commands, invalid services, and negative timeouts. It returns a *ParseError when the
+// YAML cannot be decoded and a *ValidationError listing every issue otherwise.
+func LintDependentManifest(path string, data []byte) error {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	var m Manifest
+	if err := decoder.Decode(&m); err != nil && !errors.Is(err, io.EOF) {
+		return &ParseError{Path: path, Err: err}
+	}
+
+	var issues []string
+
+	if m.Module != nil {
+		if strings.TrimSpace(m.Module.Module) == "" {
+			issues = append(issues, "module.module cannot be empty")
+		}
+		issues = append(issues, lintCommands("module.tests", m.Module.Tests)...)
+		issues = append(issues, lintCommands("module.extra_commands", m.Module.ExtraCommands)...)
+		issues = append(issues, lintServices("module.services", m.Module.Services)...)
+		issues = append(issues, lintEnv("module.env", m.Module.Env)...)
+		issues = append(issues, lintGitHubIssues("module.notifications.github_issues", m.Module.Notifications.GitHubIssues)...)
+		if m.Module.Timeout < 0 {
+			issues = append(issues, "module.timeout cannot be negative")
+		}
+		issues = append(issues, lintChangelog("module.changelog", m.Module.Changelog)...)
+		issues = append(issues, lintPRBase("module.pr.base", m.Module.PR.Base)...)
+		issues = append(issues, lintPRAutoMerge("module.pr.auto_merge", m.Module.PR.AutoMerge)...)
+	}
+
+	keys := make([]string, 0, len(m.Dependents))
+	for key := range m.Dependents {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		dep := m.Dependents[key]
+		if strings.TrimSpace(key) == "" {
+			issues = append(issues, "dependents key cannot be empty")
+			continue
+		}
+		if !strings.Contains(key, "/") && !strings.Contains(key, ".") {
+			issues = append(issues, fmt.Sprintf("dependents[%s] does not look like a Go module path", key))
+		}
+		issues = append(issues, lintCommands(fmt.Sprintf("dependents[%s].tests", key), dep.Tests)...)
+		issues = append(issues, lintCommands(fmt.Sprintf("dependents[%s].extra_commands", key), dep.ExtraCommands)...)
+		issues = append(issues, lintServices(fmt.Sprintf("dependents[%s].services", key), dep.Services)...)
+		issues = append(issues, lintCredentials(fmt.Sprintf("dependents[%s].credentials", key), dep.Credentials)...)
+		issues = append(issues, lintEnv(fmt.Sprintf("dependents[%s].env", key), dep.Env)...)
+		issues = append(issues, lintGitHubIssues(fmt.Sprintf("dependents[%s].notifications.github_issues", key), dep.Notifications.GitHubIssues)...)
+		if dep.Timeout < 0 {
+			issues = append(issues, fmt.Sprintf("dependents[%s].timeout cannot be negative", key))
+		}
+		issues = append(issues, lintRetryPolicy(fmt.Sprintf("dependents[%s].retry", key), dep.Retry)...)
+		issues = append(issues, lintChangelog(fmt.Sprintf("dependents[%s].changelog", key), dep.Changelog)...)
+		issues = append(issues, lintPRBase(fmt.Sprintf("dependents[%s].pr.base", key), dep.PR.Base)...)
+		issues = append(issues, lintPRAutoMerge(fmt.Sprintf("dependents[%s].pr.auto_merge", key), dep.PR.AutoMerge)...)
+	}
+
+	if m.Module == nil && len(m.Dependents) == 0 {
+		issues = append(issues, "no module defaults or dependents overrides declared")
+	}
+
+	if len(issues) > 0 {
+		return &ValidationError{Issues: issues}
+	}
+	return nil
+}
+
+func lintCommands(field string, commands []Command) []string {
+	var issues []string
+	for i, cmd := range commands {
+		if len(cmd.Cmd) == 0 || strings.TrimSpace(cmd.Cmd[0]) == "" {
+			issues = append(issues, fmt.Sprintf("%s[%d] has an empty command", field, i))
+		}
+	}
+	return issues
+}
@@ -174,6 +174,7 @@ func sanitizeManifest(input *manifestpkg.Manifest, opts sanitizeOptions) (*manif
 		module.Module = strings.TrimSpace(module.Module)
 		module.Repo = strings.TrimSpace(module.Repo)
 		module.ReleaseArtifact = strings.TrimSpace(module.ReleaseArtifact)
+		module.Aliases = manifestpkg.NormalizeAliases(module.Module, module.Aliases)
 
 		if module.Dependents == nil {
 			module.Dependents = []manifestpkg.Dependent{}
@@ -258,6 +259,7 @@ func mergeManifest(existing, generated *manifestpkg.Manifest) *manifestpkg.Manif
 			module.Module = newModule.Module
 			module.Repo = newModule.Repo
 			module.ReleaseArtifact = newModule.ReleaseArtifact
+			module.Aliases = manifestpkg.NormalizeAliases(newModule.Module, append(append([]string(nil), module.Aliases...), newModule.Aliases...))
 			module.Dependents = cloneDependents(newModule.Dependents)
 			replaced = true
 			break
@@ -313,6 +315,7 @@ func cloneManifest(m *manifestpkg.Manifest) *manifestpkg.Manifest {
 	clone.Defaults.Tests = cloneCommands(m.Defaults.Tests)
 	clone.Defaults.ExtraCommands = cloneCommands(m.Defaults.ExtraCommands)
 	clone.Defaults.Labels = append([]string(nil), m.Defaults.Labels...)
+	clone.Defaults.NeverTouch = append([]string(nil), m.Defaults.NeverTouch...)
 
 	if m.Modules != nil {
 		clone.Modules = make([]manifestpkg.Module, len(m.Modules))
@@ -328,6 +331,7 @@ func cloneManifest(m *manifestpkg.Manifest) *manifestpkg.Manifest {
 
 func cloneModule(m manifestpkg.Module) manifestpkg.Module {
 	clone := m
+	clone.Aliases = append([]string(nil), m.Aliases...)
 	clone.Dependents = cloneDependents(m.Dependents)
 	return clone
 }
@@ -182,3 +182,54 @@ func TestPersistorSave_WritesFileWhenNotDryRun(t *testing.T) {
 		t.Fatalf("written manifest mismatch\nwant:\n%s\ngot:\n%s", string(result.YAML), string(written))
 	}
 }
+
+func TestPersistorSave_MergesModuleAliases(t *testing.T) {
+	dependents := []manifestpkg.Dependent{{Repo: "example/app", Module: "github.com/example/app", ModulePath: "."}}
+	existing := &manifestpkg.Manifest{
+		ManifestVersion: 1,
+		Modules: []manifestpkg.Module{{
+			Name:       "example-module",
+			Module:     "github.com/example/module",
+			Repo:       "example/module",
+			Aliases:    []string{"github.com/example/old-module"},
+			Dependents: dependents,
+		}},
+	}
+	generated := &manifestpkg.Manifest{
+		ManifestVersion: 1,
+		Modules: []manifestpkg.Module{{
+			Name:       "example-module",
+			Module:     "github.com/example/module",
+			Repo:       "example/module",
+			Aliases:    []string{"github.com/example/module", " github.com/example/older-module "},
+			Dependents: dependents,
+		}},
+	}
+
+	manifestPath := filepath.Join(t.TempDir(), "manifest.yaml")
+	existingBytes, err := yaml.Marshal(existing)
+	if err != nil {
+		t.Fatalf("marshal existing manifest: %v", err)
+	}
+	if err := os.WriteFile(manifestPath, existingBytes, 0o644); err != nil {
+		t.Fatalf("write existing manifest: %v", err)
+	}
+
+	result, err := persist.NewPersistor(manifestpkg.NewLoader()).Save(generated, persist.Options{
+		Path:         manifestPath,
+		TargetModule: "github.com/example/module",
+		DryRun:       true,
+	})
+	if err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+
+	got := result.Manifest.Modules[0].Aliases
+	want := []string{"github.com/example/old-module", "github.com/example/older-module"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("aliases = %v, want %v", got, want)
+	}
+	if len(existing.Modules[0].Aliases) != 1 {
+		t.Fatalf("expected existing manifest aliases to be untouched, got %v", existing.Modules[0].Aliases)
+	}
+}
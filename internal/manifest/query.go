@@ -24,16 +24,38 @@ func FindModule(m *Manifest, name string) (*Module, error) {
 	return nil, &ModuleNotFoundError{ModuleName: name}
 }
 
-// FindModuleByPath returns the module with the provided module path.
+// FindModuleByPath returns the module with the provided module path. Aliases are
+// matched when no module declares the path as its own.
 func FindModuleByPath(m *Manifest, modulePath string) (*Module, error) {
 	for i := range m.Modules {
 		if m.Modules[i].Module == modulePath {
 			return &m.Modules[i], nil
 		}
 	}
+	for i := range m.Modules {
+		if m.Modules[i].HasPath(modulePath) {
+			return &m.Modules[i], nil
+		}
+	}
 	return nil, &ModuleNotFoundError{ModuleName: modulePath}
 }
 
+// FindModulesByPaths returns every module whose path or aliases intersect paths, in
+// manifest order. It is used to merge entries that describe the same module under
+// different import paths.
+func FindModulesByPaths(m *Manifest, paths []string) []*Module {
+	var modules []*Module
+	for i := range m.Modules {
+		for _, p := range paths {
+			if m.Modules[i].HasPath(p) {
+				modules = append(modules, &m.Modules[i])
+				break
+			}
+		}
+	}
+	return modules
+}
+
 // ExpandDefaults applies defaults to a dependent and returns the result.
 func ExpandDefaults(d Dependent, defaults Defaults) Dependent {
 	result := d
@@ -69,6 +91,8 @@ func ExpandDefaults(d Dependent, defaults Defaults) Dependent {
 	// Merge nested structs without overwriting explicit dependent values
 	result.Notifications = mergeNotifications(defaults.Notifications, result.Notifications)
 	result.PR = mergePRConfig(defaults.PR, result.PR)
+	result.Retry = mergeRetryPolicy(defaults.Retry, result.Retry)
+	result.Changelog = mergeChangelog(defaults.Changelog, result.Changelog)
 
 	return result
 }
@@ -121,11 +145,28 @@ func mergeNotifications(defaults, dependent Notifications) Notifications {
 		if len(copy.Labels) > 0 {
 			copy.Labels = append([]string(nil), copy.Labels...)
 		}
+		if len(copy.Assignees) > 0 {
+			copy.Assignees = append([]string(nil), copy.Assignees...)
+		}
 		result.GitHubIssues = &copy
 	} else if result.GitHubIssues != nil && defaults.GitHubIssues != nil {
-		if len(result.GitHubIssues.Labels) == 0 && len(defaults.GitHubIssues.Labels) > 0 {
-			result.GitHubIssues.Labels = append([]string(nil), defaults.GitHubIssues.Labels...)
+		issues := *result.GitHubIssues
+		if len(issues.Labels) == 0 && len(defaults.GitHubIssues.Labels) > 0 {
+			issues.Labels = append([]string(nil), defaults.GitHubIssues.Labels...)
+		}
+		if len(issues.Assignees) == 0 && len(defaults.GitHubIssues.Assignees) > 0 {
+			issues.Assignees = append([]string(nil), defaults.GitHubIssues.Assignees...)
+		}
+		if issues.TitleTemplate == "" {
+			issues.TitleTemplate = defaults.GitHubIssues.TitleTemplate
+		}
+		if issues.BodyTemplate == "" {
+			issues.BodyTemplate = defaults.GitHubIssues.BodyTemplate
+		}
+		if issues.OnRecurrence == "" {
+			issues.OnRecurrence = defaults.GitHubIssues.OnRecurrence
 		}
+		result.GitHubIssues = &issues
 	}
 	return result
 }
@@ -173,6 +214,9 @@ func mergePRConfig(defaults, dependent PRConfig) PRConfig {
 	if result.BodyTemplate == "" {
 		result.BodyTemplate = defaults.BodyTemplate
 	}
+	if result.BodyTemplateFile == "" {
+		result.BodyTemplateFile = defaults.BodyTemplateFile
+	}
 	if result.Reviewers == nil && len(defaults.Reviewers) > 0 {
 		result.Reviewers = make([]string, len(defaults.Reviewers))
 		copy(result.Reviewers, defaults.Reviewers)
@@ -181,6 +225,18 @@ func mergePRConfig(defaults, dependent PRConfig) PRConfig {
 		result.TeamReviewers = make([]string, len(defaults.TeamReviewers))
 		copy(result.TeamReviewers, defaults.TeamReviewers)
 	}
+	if !result.Draft {
+		result.Draft = defaults.Draft
+	}
+	if result.Base == "" {
+		result.Base = defaults.Base
+	}
+	if !result.DeleteBranchOnMerge {
+		result.DeleteBranchOnMerge = defaults.DeleteBranchOnMerge
+	}
+	if result.AutoMerge == "" {
+		result.AutoMerge = defaults.AutoMerge
+	}
 	return result
 }
 
@@ -189,8 +245,10 @@ func mergePRConfig(defaults, dependent PRConfig) PRConfig {
 func HasOriginalPRConfig(d Dependent) bool {
 	return d.PR.TitleTemplate != "" ||
 		d.PR.BodyTemplate != "" ||
+		d.PR.BodyTemplateFile != "" ||
 		len(d.PR.Reviewers) > 0 ||
-		len(d.PR.TeamReviewers) > 0
+		len(d.PR.TeamReviewers) > 0 ||
+		d.PR.Draft
 }
 
 // ExpandDefaultsWithMetadata applies defaults to a dependent and returns the result
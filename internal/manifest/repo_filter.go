@@ -0,0 +1,170 @@
+package manifest
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goliatone/cascade/pkg/repometa"
+)
+
+// RepoFilter selects discovered repositories by their attributes. It is written
+// with GitHub search qualifiers, for example
+// "topic:go-service pushed:>2024-01-01 archived:false", so the same expression can
+// narrow a repository search on the server and be checked against repository
+// metadata where the API cannot filter.
+type RepoFilter struct {
+	// Topics must all be set on the repository.
+	Topics []string
+
+	// Visibility is public, private, or internal; empty matches any.
+	Visibility string
+
+	// Archived selects archived (true) or unarchived (false) repositories. Nil
+	// keeps the discovery default of leaving archived repositories out.
+	Archived *bool
+
+	// PushedOp compares the last push with PushedDate: one of >, >=, <, <=, or
+	// empty for a push on that day. PushedDate is zero when not filtering.
+	PushedOp   string
+	PushedDate time.Time
+}
+
+// repoFilterDateLayout is the date format of GitHub search qualifiers.
+const repoFilterDateLayout = "2006-01-02"
+
+// ParseRepoFilter parses a space separated list of qualifiers: topic:<name>
+// (repeatable), visibility:public|private|internal, archived:true|false, and
+// pushed:<op><date>, where the date is YYYY-MM-DD or a number of days ago such
+// as 90d and the optional op is >, >=, <, or <=.
+func ParseRepoFilter(expr string) (RepoFilter, error) {
+	var filter RepoFilter
+	for _, term := range strings.Fields(expr) {
+		key, value, ok := strings.Cut(term, ":")
+		if !ok || value == "" {
+			return RepoFilter{}, fmt.Errorf("invalid filter %q: expected key:value", term)
+		}
+
+		switch strings.ToLower(key) {
+		case "topic":
+			filter.Topics = append(filter.Topics, value)
+		case "visibility", "is":
+			switch v := strings.ToLower(value); v {
+			case repometa.VisibilityPublic, repometa.VisibilityPrivate, repometa.VisibilityInternal:
+				filter.Visibility = v
+			default:
+				return RepoFilter{}, fmt.Errorf("invalid filter %q: visibility must be public, private, or internal", term)
+			}
+		case "archived":
+			archived, err := strconv.ParseBool(value)
+			if err != nil {
+				return RepoFilter{}, fmt.Errorf("invalid filter %q: archived must be true or false", term)
+			}
+			filter.Archived = &archived
+		case "pushed":
+			op, date, err := parsePushedFilter(value, time.Now())
+			if err != nil {
+				return RepoFilter{}, fmt.Errorf("invalid filter %q: %w", term, err)
+			}
+			filter.PushedOp, filter.PushedDate = op, date
+		default:
+			return RepoFilter{}, fmt.Errorf("unknown filter %q: supported filters are topic, visibility, archived, and pushed", term)
+		}
+	}
+	return filter, nil
+}
+
+func parsePushedFilter(value string, now time.Time) (string, time.Time, error) {
+	op := ""
+	for _, candidate := range []string{">=", "<=", ">", "<"} {
+		if strings.HasPrefix(value, candidate) {
+			op, value = candidate, strings.TrimPrefix(value, candidate)
+			break
+		}
+	}
+
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return "", time.Time{}, fmt.Errorf("pushed age must be a number of days such as 90d")
+		}
+		y, m, d := now.UTC().AddDate(0, 0, -n).Date()
+		return op, time.Date(y, m, d, 0, 0, 0, 0, time.UTC), nil
+	}
+
+	date, err := time.Parse(repoFilterDateLayout, value)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("pushed must be a YYYY-MM-DD date or a number of days such as 90d")
+	}
+	return op, date, nil
+}
+
+// IsZero reports whether the filter has no qualifiers.
+func (f RepoFilter) IsZero() bool {
+	return len(f.Topics) == 0 && f.Visibility == "" && f.Archived == nil && f.PushedDate.IsZero()
+}
+
+// SearchQualifiers returns the filter as GitHub repository search qualifiers.
+func (f RepoFilter) SearchQualifiers() []string {
+	var qualifiers []string
+	for _, topic := range f.Topics {
+		qualifiers = append(qualifiers, "topic:"+topic)
+	}
+	if f.Visibility != "" {
+		qualifiers = append(qualifiers, "is:"+f.Visibility)
+	}
+	if f.Archived != nil {
+		qualifiers = append(qualifiers, "archived:"+strconv.FormatBool(*f.Archived))
+	}
+	if !f.PushedDate.IsZero() {
+		qualifiers = append(qualifiers, "pushed:"+f.PushedOp+f.PushedDate.Format(repoFilterDateLayout))
+	}
+	return qualifiers
+}
+
+// Matches reports whether the repository described by meta passes the filter.
+// A repository whose last push is unknown fails a pushed filter.
+func (f RepoFilter) Matches(meta repometa.Metadata) bool {
+	wantArchived := false
+	if f.Archived != nil {
+		wantArchived = *f.Archived
+	}
+	if meta.Archived != wantArchived {
+		return false
+	}
+	for _, topic := range f.Topics {
+		if !meta.HasTopic(topic) {
+			return false
+		}
+	}
+	if f.Visibility != "" && !strings.EqualFold(meta.Visibility, f.Visibility) {
+		return false
+	}
+	if !f.PushedDate.IsZero() {
+		if meta.PushedAt.IsZero() {
+			return false
+		}
+		dayStart := f.PushedDate
+		dayEnd := dayStart.AddDate(0, 0, 1)
+		pushed := meta.PushedAt.UTC()
+		switch f.PushedOp {
+		case ">":
+			return !pushed.Before(dayEnd)
+		case ">=":
+			return !pushed.Before(dayStart)
+		case "<":
+			return pushed.Before(dayStart)
+		case "<=":
+			return pushed.Before(dayEnd)
+		default:
+			return !pushed.Before(dayStart) && pushed.Before(dayEnd)
+		}
+	}
+	return true
+}
+
+// String returns the filter in the syntax ParseRepoFilter accepts.
+func (f RepoFilter) String() string {
+	return strings.Join(f.SearchQualifiers(), " ")
+}
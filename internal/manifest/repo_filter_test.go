@@ -0,0 +1,78 @@
+package manifest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goliatone/cascade/pkg/repometa"
+)
+
+func TestParseRepoFilter(t *testing.T) {
+	filter, err := ParseRepoFilter("topic:go-service topic:team-a visibility:Private archived:false pushed:>=2024-01-01")
+	if err != nil {
+		t.Fatalf("ParseRepoFilter returned error: %v", err)
+	}
+	if got := filter.String(); got != "topic:go-service topic:team-a is:private archived:false pushed:>=2024-01-01" {
+		t.Fatalf("unexpected filter %q", got)
+	}
+
+	empty, err := ParseRepoFilter("  ")
+	if err != nil || !empty.IsZero() {
+		t.Fatalf("expected an empty filter, got %+v (%v)", empty, err)
+	}
+
+	for _, expr := range []string{"topic", "stars:>10", "visibility:secret", "archived:maybe", "pushed:>yesterday", "pushed:-3d"} {
+		if _, err := ParseRepoFilter(expr); err == nil {
+			t.Errorf("expected %q to be rejected", expr)
+		}
+	}
+}
+
+func TestParsePushedFilter_RelativeDays(t *testing.T) {
+	now := time.Date(2024, 3, 31, 15, 4, 5, 0, time.UTC)
+	op, date, err := parsePushedFilter(">90d", now)
+	if err != nil {
+		t.Fatalf("parsePushedFilter returned error: %v", err)
+	}
+	if op != ">" || !date.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected pushed filter %s%s", op, date)
+	}
+}
+
+func TestRepoFilterMatches(t *testing.T) {
+	pushed := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	meta := repometa.Metadata{Repo: "acme/svc", Visibility: "private", Topics: []string{"Go-Service"}, PushedAt: pushed}
+	archived := meta
+	archived.Archived = true
+
+	tests := []struct {
+		expr string
+		meta repometa.Metadata
+		want bool
+	}{
+		{"", meta, true},
+		{"", archived, false},
+		{"archived:true", archived, true},
+		{"archived:true", meta, false},
+		{"topic:go-service", meta, true},
+		{"topic:go-service topic:team-a", meta, false},
+		{"visibility:public", meta, false},
+		{"visibility:private", meta, true},
+		{"pushed:2024-01-01", meta, true},
+		{"pushed:>2024-01-01", meta, false},
+		{"pushed:>=2024-01-01", meta, true},
+		{"pushed:<2024-01-01", meta, false},
+		{"pushed:<=2024-01-01", meta, true},
+		{"pushed:>2023-12-31", meta, true},
+		{"pushed:>2023-12-31", repometa.Metadata{Repo: "acme/unknown"}, false},
+	}
+	for _, tt := range tests {
+		filter, err := ParseRepoFilter(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseRepoFilter(%q) returned error: %v", tt.expr, err)
+		}
+		if got := filter.Matches(tt.meta); got != tt.want {
+			t.Errorf("%q matching %+v = %v, want %v", tt.expr, tt.meta, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package manifest
+
+import (
+	"fmt"
+	"time"
+)
+
+// RetryPolicy bounds how `cascade resume --retry-failed` retries a dependent
+// whose update failed.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, the first one included.
+	// Zero retries without limit.
+	MaxAttempts int `yaml:"max_attempts,omitempty"`
+
+	// Backoff is how long after a failed attempt the next one may start. It
+	// doubles with every further failure. Zero retries right away.
+	Backoff time.Duration `yaml:"backoff,omitempty"`
+}
+
+// IsZero reports whether the policy sets nothing.
+func (p RetryPolicy) IsZero() bool {
+	return p.MaxAttempts == 0 && p.Backoff == 0
+}
+
+// mergeRetryPolicy fills the fields dependent leaves unset from defaults.
+func mergeRetryPolicy(defaults, dependent RetryPolicy) RetryPolicy {
+	result := dependent
+	if result.MaxAttempts == 0 {
+		result.MaxAttempts = defaults.MaxAttempts
+	}
+	if result.Backoff == 0 {
+		result.Backoff = defaults.Backoff
+	}
+	return result
+}
+
+// lintRetryPolicy reports a retry policy with negative settings under field.
+func lintRetryPolicy(field string, policy RetryPolicy) []string {
+	var issues []string
+	if policy.MaxAttempts < 0 {
+		issues = append(issues, fmt.Sprintf("%s.max_attempts cannot be negative", field))
+	}
+	if policy.Backoff < 0 {
+		issues = append(issues, fmt.Sprintf("%s.backoff cannot be negative", field))
+	}
+	return issues
+}
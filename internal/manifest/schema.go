@@ -0,0 +1,139 @@
+package manifest
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// SchemaURI identifies the JSON Schema dialect Schema describes.
+const SchemaURI = "https://json-schema.org/draft/2020-12/schema"
+
+// durationPattern matches the Go durations manifests accept, such as 90s or 1h30m.
+const durationPattern = `^-?([0-9]+(\.[0-9]*)?(ns|us|µs|ms|s|m|h))+$|^0$`
+
+// Schema is the subset of JSON Schema used to describe manifests. It is
+// derived from the manifest types, so it always matches what the loader decodes.
+type Schema struct {
+	Schema      string `json:"$schema,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Type        string `json:"type,omitempty"`
+
+	Properties map[string]*Schema `json:"properties,omitempty"`
+	Required   []string           `json:"required,omitempty"`
+	// AdditionalProperties is false for fixed objects and the value schema for maps.
+	AdditionalProperties any     `json:"additionalProperties,omitempty"`
+	Items                *Schema `json:"items,omitempty"`
+
+	Enum    []string  `json:"enum,omitempty"`
+	Pattern string    `json:"pattern,omitempty"`
+	OneOf   []*Schema `json:"oneOf,omitempty"`
+
+	// duration marks Go durations: strings such as 5m, or integer nanoseconds.
+	duration bool
+}
+
+// requiredFields lists the keys each manifest type must set.
+var requiredFields = map[reflect.Type][]string{
+	reflect.TypeOf(Manifest{}):     {"manifest_version", "modules"},
+	reflect.TypeOf(ModuleConfig{}): {"module", "module_path"},
+	reflect.TypeOf(Module{}):       {"name", "module", "repo"},
+	reflect.TypeOf(Dependent{}):    {"repo", "module", "module_path"},
+	reflect.TypeOf(Command{}):      {"cmd"},
+	reflect.TypeOf(Service{}):      {"name", "image"},
+	reflect.TypeOf(Credential{}):   {"machine", "login", "password_env"},
+}
+
+// fieldEnums lists the values accepted by enumerated fields, keyed by type and key.
+var fieldEnums = map[reflect.Type]map[string][]string{
+	reflect.TypeOf(Dependent{}): {
+		"check_strategy":  {CheckStrategyLocal, CheckStrategyRemote, CheckStrategyAuto},
+		"update_strategy": {UpdateStrategyGoModules, UpdateStrategyGitSubmodule},
+		"provider":        {ProviderGitHub, ProviderGitLab, ProviderBitbucket, ProviderSandbox},
+		"clone_protocol":  {CloneProtocolHTTPS, CloneProtocolSSH},
+	},
+	reflect.TypeOf(Changelog{}): {
+		"format": {ChangelogKeepAChangelog, ChangelogFragment, ChangelogConventional},
+	},
+	reflect.TypeOf(GitHubIssueNotification{}): {
+		"on_recurrence": {IssueRecurrenceIgnore, IssueRecurrenceComment, IssueRecurrenceReopen},
+	},
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// ManifestSchema returns the JSON Schema of .cascade.yaml manifests, for
+// editors and CI tools that validate manifests themselves.
+func ManifestSchema() *Schema {
+	s := schemaFor(reflect.TypeOf(Manifest{}))
+	s.Schema = SchemaURI
+	s.Title = "Cascade manifest"
+	return s
+}
+
+func schemaFor(t reflect.Type) *Schema {
+	if t == durationType {
+		return &Schema{
+			Description: "Go duration such as 30s or 5m, or integer nanoseconds",
+			OneOf: []*Schema{
+				{Type: "string", Pattern: durationPattern},
+				{Type: "integer"},
+			},
+			duration: true,
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return schemaFor(t.Elem())
+	case reflect.Struct:
+		s := &Schema{
+			Type:                 "object",
+			Properties:           make(map[string]*Schema),
+			Required:             requiredFields[t],
+			AdditionalProperties: false,
+		}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, ok := yamlFieldName(field)
+			if !ok {
+				continue
+			}
+			prop := schemaFor(field.Type)
+			prop.Enum = fieldEnums[t][name]
+			s.Properties[name] = prop
+		}
+		return s
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: schemaFor(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: schemaFor(t.Elem())}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	default:
+		return &Schema{Type: "string"}
+	}
+}
+
+// yamlFieldName returns the key a struct field is decoded from, following the
+// yaml package rules: the tag name, or the lowercased field name.
+func yamlFieldName(field reflect.StructField) (string, bool) {
+	if !field.IsExported() {
+		return "", false
+	}
+	tag := field.Tag.Get("yaml")
+	if tag == "-" {
+		return "", false
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = strings.ToLower(field.Name)
+	}
+	return name, true
+}
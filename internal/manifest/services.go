@@ -0,0 +1,80 @@
+package manifest
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Service is a backing container (database, cache, broker) the executor starts
+// before a dependent's tests and removes once they finish.
+type Service struct {
+	// Name identifies the service; it prefixes the injected <NAME>_HOST and <NAME>_PORT variables.
+	Name string `yaml:"name"`
+	// Image is the container image to run, e.g. postgres:16.
+	Image string `yaml:"image"`
+	// Port is the container port published on a random loopback port of the host.
+	Port int `yaml:"port,omitempty"`
+	// Env is passed to the container.
+	Env map[string]string `yaml:"env,omitempty"`
+	// HealthCheck decides when the service is ready. Without one, the published port
+	// accepting TCP connections marks the service ready.
+	HealthCheck *ServiceHealthCheck `yaml:"healthcheck,omitempty"`
+	// Export maps environment variables injected into test and extra commands to
+	// templates rendered with {{.Host}}, {{.Port}}, and {{.Name}}, e.g. a DATABASE_URL.
+	Export map[string]string `yaml:"export,omitempty"`
+}
+
+// ServiceHealthCheck configures service readiness probing.
+type ServiceHealthCheck struct {
+	// Cmd runs inside the container; a zero exit status means the service is ready.
+	Cmd []string `yaml:"cmd,omitempty"`
+	// Interval between probes. Default: 1s
+	Interval time.Duration `yaml:"interval,omitempty"`
+	// Timeout bounds the total wait for readiness. Default: 60s
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+}
+
+var serviceNamePattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_-]*$`)
+
+// lintServices reports configuration problems in a services list under field.
+func lintServices(field string, services []Service) []string {
+	var issues []string
+	seen := make(map[string]bool, len(services))
+	for i, svc := range services {
+		label := fmt.Sprintf("%s[%d]", field, i)
+		switch {
+		case strings.TrimSpace(svc.Name) == "":
+			issues = append(issues, fmt.Sprintf("%s name cannot be empty", label))
+		case !serviceNamePattern.MatchString(svc.Name):
+			issues = append(issues, fmt.Sprintf("%s name %q must start with a letter and contain only letters, digits, '-' or '_'", label, svc.Name))
+		case seen[svc.Name]:
+			issues = append(issues, fmt.Sprintf("%s duplicate service name %q", label, svc.Name))
+		default:
+			seen[svc.Name] = true
+		}
+
+		if strings.TrimSpace(svc.Image) == "" {
+			issues = append(issues, fmt.Sprintf("%s (%s) image cannot be empty", label, svc.Name))
+		}
+		if svc.Port < 0 || svc.Port > 65535 {
+			issues = append(issues, fmt.Sprintf("%s (%s) port must be between 1 and 65535", label, svc.Name))
+		}
+		if hc := svc.HealthCheck; hc != nil {
+			if hc.Interval < 0 || hc.Timeout < 0 {
+				issues = append(issues, fmt.Sprintf("%s (%s) healthcheck interval and timeout cannot be negative", label, svc.Name))
+			}
+			if len(hc.Cmd) == 0 && svc.Port == 0 {
+				issues = append(issues, fmt.Sprintf("%s (%s) healthcheck needs a cmd or a published port", label, svc.Name))
+			}
+		}
+		for key, value := range svc.Export {
+			if _, err := template.New(key).Option("missingkey=error").Parse(value); err != nil {
+				issues = append(issues, fmt.Sprintf("%s (%s) export %s is not a valid template: %v", label, svc.Name, key, err))
+			}
+		}
+	}
+	return issues
+}
@@ -0,0 +1,139 @@
+package manifest
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Test command detection sources reported by DetectTestCommand.
+const (
+	TestSourceTaskfile = "taskfile"
+	TestSourceMakefile = "makefile"
+	TestSourceMagefile = "magefile"
+)
+
+// FileReader returns the contents of a file relative to a dependent's module root.
+// Implementations return an error wrapping fs.ErrNotExist when the file is absent.
+type FileReader func(name string) ([]byte, error)
+
+// DetectedTestCommand is the verification command proposed for a dependent.
+type DetectedTestCommand struct {
+	Command Command
+	// Source names the build tool the command was derived from
+	Source string
+	// File is the file the test target was found in
+	File string
+}
+
+var (
+	taskfileNames = []string{"Taskfile.yml", "Taskfile.yaml", "Taskfile.dist.yml", "Taskfile.dist.yaml"}
+	makefileNames = []string{"GNUmakefile", "Makefile", "makefile"}
+	magefileNames = []string{"magefile.go", "mage.go", "magefiles/magefile.go", "magefiles/mage.go"}
+
+	makeTestTarget = regexp.MustCompile(`^test\s*:([^=]|$)`)
+	mageTestFunc   = regexp.MustCompile(`(?m)^func\s+Test\s*\(`)
+)
+
+// DetectTestCommand inspects a dependent's build tooling and proposes the command
+// its maintainers use to verify changes. Taskfiles take precedence over Makefiles,
+// which take precedence over magefiles. It returns false when no test target is
+// found, in which case callers should fall back to `go test ./...`.
+func DetectTestCommand(read FileReader) (DetectedTestCommand, bool) {
+	if read == nil {
+		return DetectedTestCommand{}, false
+	}
+
+	for _, name := range taskfileNames {
+		if data, err := read(name); err == nil && hasTaskfileTestTask(data) {
+			return DetectedTestCommand{
+				Command: Command{Cmd: []string{"task", "test"}},
+				Source:  TestSourceTaskfile,
+				File:    name,
+			}, true
+		}
+	}
+
+	for _, name := range makefileNames {
+		if data, err := read(name); err == nil && hasMakeTestTarget(data) {
+			return DetectedTestCommand{
+				Command: Command{Cmd: []string{"make", "test"}},
+				Source:  TestSourceMakefile,
+				File:    name,
+			}, true
+		}
+	}
+
+	for _, name := range magefileNames {
+		if data, err := read(name); err == nil && mageTestFunc.Match(data) {
+			return DetectedTestCommand{
+				Command: Command{Cmd: []string{"mage", "test"}},
+				Source:  TestSourceMagefile,
+				File:    name,
+			}, true
+		}
+	}
+
+	return DetectedTestCommand{}, false
+}
+
+// DirFileReader returns a FileReader rooted at dir on the local filesystem.
+func DirFileReader(dir string) FileReader {
+	return func(name string) ([]byte, error) {
+		return os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+	}
+}
+
+// hasTaskfileTestTask reports whether a Taskfile declares a top-level "test" task.
+// It scans the tasks block by indentation rather than decoding YAML so templated
+// or partially invalid Taskfiles are still recognised.
+func hasTaskfileTestTask(data []byte) bool {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	inTasks := false
+	taskIndent := -1
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		if indent == 0 {
+			inTasks = strings.HasPrefix(trimmed, "tasks:")
+			taskIndent = -1
+			continue
+		}
+		if !inTasks {
+			continue
+		}
+		if taskIndent == -1 {
+			taskIndent = indent
+		}
+		if indent != taskIndent {
+			continue
+		}
+
+		name := strings.TrimSuffix(strings.SplitN(trimmed, ":", 2)[0], " ")
+		name = strings.Trim(name, `"'`)
+		if name == "test" {
+			return true
+		}
+	}
+	return false
+}
+
+// hasMakeTestTarget reports whether a Makefile defines a "test" target.
+func hasMakeTestTarget(data []byte) bool {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		if makeTestTarget.MatchString(scanner.Text()) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,144 @@
+package manifest
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func mapFileReader(files map[string]string) FileReader {
+	return func(name string) ([]byte, error) {
+		content, ok := files[name]
+		if !ok {
+			return nil, fs.ErrNotExist
+		}
+		return []byte(content), nil
+	}
+}
+
+func TestDetectTestCommand(t *testing.T) {
+	tests := []struct {
+		name       string
+		files      map[string]string
+		wantCmd    []string
+		wantSource string
+		wantFound  bool
+	}{
+		{
+			name: "taskfile test task",
+			files: map[string]string{
+				"Taskfile.yml": "version: '3'\n\ntasks:\n  build:\n    cmds:\n      - go build ./...\n  test:\n    cmds:\n      - go test ./...\n",
+				"Makefile":     "test:\n\tgo test ./...\n",
+			},
+			wantCmd:    []string{"task", "test"},
+			wantSource: TestSourceTaskfile,
+			wantFound:  true,
+		},
+		{
+			name: "taskfile without test task falls through to makefile",
+			files: map[string]string{
+				"Taskfile.yml": "version: '3'\ntasks:\n  lint:\n    cmds:\n      - test: nested\n",
+				"Makefile":     ".PHONY: test\ntest: lint\n\tgo test ./...\n",
+			},
+			wantCmd:    []string{"make", "test"},
+			wantSource: TestSourceMakefile,
+			wantFound:  true,
+		},
+		{
+			name: "makefile variable is not a target",
+			files: map[string]string{
+				"Makefile": "test := ./...\nbuild:\n\tgo build $(test)\n",
+			},
+			wantFound: false,
+		},
+		{
+			name: "magefile test target",
+			files: map[string]string{
+				"magefiles/magefile.go": "//go:build mage\npackage main\n\nfunc Test() error { return nil }\n",
+			},
+			wantCmd:    []string{"mage", "test"},
+			wantSource: TestSourceMagefile,
+			wantFound:  true,
+		},
+		{
+			name:      "no build tooling",
+			files:     map[string]string{"go.mod": "module example.com/app\n"},
+			wantFound: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := DetectTestCommand(mapFileReader(tt.files))
+			if found != tt.wantFound {
+				t.Fatalf("DetectTestCommand() found = %v, want %v", found, tt.wantFound)
+			}
+			if !found {
+				return
+			}
+			if !reflect.DeepEqual(got.Command.Cmd, tt.wantCmd) {
+				t.Errorf("DetectTestCommand() cmd = %v, want %v", got.Command.Cmd, tt.wantCmd)
+			}
+			if got.Source != tt.wantSource {
+				t.Errorf("DetectTestCommand() source = %q, want %q", got.Source, tt.wantSource)
+			}
+		})
+	}
+}
+
+func TestWorkspaceDiscovery_DiscoverDependents_DetectsTestCommands(t *testing.T) {
+	workspaceDir := t.TempDir()
+
+	writeModule := func(name, extraFile, extraContent string) {
+		dir := filepath.Join(workspaceDir, name)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("failed to create %s: %v", name, err)
+		}
+		goMod := "module github.com/example/" + name + "\n\ngo 1.21\n\nrequire github.com/target/module v1.0.0\n"
+		if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(goMod), 0o644); err != nil {
+			t.Fatalf("failed to write go.mod: %v", err)
+		}
+		if extraFile != "" {
+			if err := os.WriteFile(filepath.Join(dir, extraFile), []byte(extraContent), 0o644); err != nil {
+				t.Fatalf("failed to write %s: %v", extraFile, err)
+			}
+		}
+	}
+	writeModule("with-make", "Makefile", "test:\n\tgo test ./...\n")
+	writeModule("plain", "", "")
+
+	options := DiscoveryOptions{
+		WorkspaceDir:       workspaceDir,
+		TargetModule:       "github.com/target/module",
+		DetectTestCommands: true,
+	}
+	dependents, err := NewWorkspaceDiscovery().DiscoverDependents(context.Background(), options)
+	if err != nil {
+		t.Fatalf("DiscoverDependents() error = %v", err)
+	}
+
+	tests := make(map[string][]Command)
+	for _, dep := range dependents {
+		tests[dep.ModulePath] = dep.Tests
+	}
+	if want := []Command{{Cmd: []string{"make", "test"}}}; !reflect.DeepEqual(tests["github.com/example/with-make"], want) {
+		t.Errorf("with-make tests = %v, want %v", tests["github.com/example/with-make"], want)
+	}
+	if got := tests["github.com/example/plain"]; got != nil {
+		t.Errorf("plain tests = %v, want defaults", got)
+	}
+
+	options.DetectTestCommands = false
+	dependents, err = NewWorkspaceDiscovery().DiscoverDependents(context.Background(), options)
+	if err != nil {
+		t.Fatalf("DiscoverDependents() error = %v", err)
+	}
+	for _, dep := range dependents {
+		if dep.Tests != nil {
+			t.Errorf("expected no detected tests when detection is disabled, got %v for %s", dep.Tests, dep.ModulePath)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package manifest
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 // Manifest is the root structure parsed from .cascade.yaml.
 type Manifest struct {
@@ -22,7 +25,9 @@ type ModuleConfig struct {
 	Notifications Notifications     `yaml:"notifications,omitempty"`
 	PR            PRConfig          `yaml:"pr,omitempty"`
 	Env           map[string]string `yaml:"env,omitempty"`
+	Services      []Service         `yaml:"services,omitempty"`
 	Timeout       time.Duration     `yaml:"timeout,omitempty"`
+	Changelog     Changelog         `yaml:"changelog,omitempty"`
 }
 
 // Defaults captures project-wide defaults inherited by dependents.
@@ -34,15 +39,76 @@ type Defaults struct {
 	CommitTemplate string        `yaml:"commit_template"`
 	Notifications  Notifications `yaml:"notifications"`
 	PR             PRConfig      `yaml:"pr"`
+	Retry          RetryPolicy   `yaml:"retry,omitempty"`
+	Changelog      Changelog     `yaml:"changelog,omitempty"`
+
+	// NeverTouch lists repositories that cascade must never change, such as
+	// frozen or critical services. Planning fails if one is a dependent, and
+	// discovery and the executor leave them alone.
+	NeverTouch []string `yaml:"never_touch,omitempty"`
 }
 
 // Module describes a releasable module and its dependents.
 type Module struct {
 	Name            string      `yaml:"name"`
 	Module          string      `yaml:"module"`
+	Aliases         []string    `yaml:"aliases,omitempty"`
 	Repo            string      `yaml:"repo"`
 	ReleaseArtifact string      `yaml:"release_artifact"`
 	Dependents      []Dependent `yaml:"dependents"`
+
+	// VersionResolution lists the resolvers tried in order when a release of
+	// the module is requested without a version or as "latest". Built-in
+	// resolvers are local, proxy, tags, and git-remote; others can be added with
+	// RegisterVersionResolver. Default: local, proxy.
+	VersionResolution []string `yaml:"version_resolution,omitempty"`
+}
+
+// Paths returns the module path followed by its aliases, the historical import
+// paths (for example a vanity URL and its GitHub path) that name the same module.
+func (m Module) Paths() []string {
+	return ModulePaths(m.Module, m.Aliases)
+}
+
+// HasPath reports whether path is the module path or one of its aliases.
+func (m Module) HasPath(path string) bool {
+	if path == "" {
+		return false
+	}
+	for _, p := range m.Paths() {
+		if p == path {
+			return true
+		}
+	}
+	return false
+}
+
+// ModulePaths returns modulePath followed by the distinct, non-empty aliases.
+func ModulePaths(modulePath string, aliases []string) []string {
+	paths := make([]string, 0, 1+len(aliases))
+	seen := make(map[string]bool, 1+len(aliases))
+	for _, p := range append([]string{modulePath}, aliases...) {
+		p = strings.TrimSpace(p)
+		if p == "" || seen[p] {
+			continue
+		}
+		seen[p] = true
+		paths = append(paths, p)
+	}
+	return paths
+}
+
+// NormalizeAliases trims and deduplicates aliases, dropping blanks and entries equal
+// to modulePath. It returns nil when no aliases remain.
+func NormalizeAliases(modulePath string, aliases []string) []string {
+	modulePath = strings.TrimSpace(modulePath)
+	var normalized []string
+	for _, p := range ModulePaths("", aliases) {
+		if p != modulePath {
+			normalized = append(normalized, p)
+		}
+	}
+	return normalized
 }
 
 // DependentConfig captures dependent-specific overrides keyed by upstream module path.
@@ -54,9 +120,14 @@ type DependentConfig struct {
 	Notifications Notifications     `yaml:"notifications,omitempty"`
 	PR            PRConfig          `yaml:"pr,omitempty"`
 	Env           map[string]string `yaml:"env,omitempty"`
+	Services      []Service         `yaml:"services,omitempty"`
 	Timeout       time.Duration     `yaml:"timeout,omitempty"`
 	Canary        bool              `yaml:"canary,omitempty"`
 	Skip          bool              `yaml:"skip,omitempty"`
+	GoFlags       string            `yaml:"goflags,omitempty"`
+	Credentials   []Credential      `yaml:"credentials,omitempty"`
+	Retry         RetryPolicy       `yaml:"retry,omitempty"`
+	Changelog     Changelog         `yaml:"changelog,omitempty"`
 }
 
 // Dependent defines a repo that consumes a module.
@@ -74,9 +145,89 @@ type Dependent struct {
 	Canary        bool              `yaml:"canary,omitempty"`
 	Skip          bool              `yaml:"skip,omitempty"`
 	Env           map[string]string `yaml:"env,omitempty"`
+	Services      []Service         `yaml:"services,omitempty"`
 	Timeout       time.Duration     `yaml:"timeout,omitempty"`
+	CheckStrategy string            `yaml:"check_strategy,omitempty"`
+	CheckCacheTTL time.Duration     `yaml:"check_cache_ttl,omitempty"`
+
+	// ConcurrencyGroup serialises work items: dependents sharing a group never
+	// run in parallel, whatever the executor's concurrent limit.
+	ConcurrencyGroup string `yaml:"concurrency_group,omitempty"`
+
+	// GoFlags is appended to GOFLAGS for this dependent's go commands only.
+	GoFlags string `yaml:"goflags,omitempty"`
+
+	// Credentials authenticate this dependent's go commands against private
+	// module hosts.
+	Credentials []Credential `yaml:"credentials,omitempty"`
+
+	// UpdateStrategy selects how the dependent consumes the module: through a
+	// go.mod require (default) or as a git submodule.
+	UpdateStrategy string `yaml:"update_strategy,omitempty"`
+
+	// BumpStrategy selects how go.mod requires the released version: pin
+	// (default), tilde, minor-range, latest-compatible, or a strategy added with
+	// RegisterBumpStrategy. It does not apply to git submodules.
+	BumpStrategy string `yaml:"bump_strategy,omitempty"`
+
+	// GoWork selects how go get and go mod tidy treat go.work files in or above
+	// the dependent's worktree: respect (default) lets the go command use them,
+	// off runs both with GOWORK=off so only the dependent's go.mod counts.
+	GoWork string `yaml:"go_work,omitempty"`
+
+	// SubmodulePath is the path of the module's submodule in the dependent. When
+	// empty, the submodule whose URL matches the module's repository is used.
+	SubmodulePath string `yaml:"submodule_path,omitempty"`
+
+	// Provider names the service pull requests are opened on: github, gitlab,
+	// or bitbucket. sandbox records them in the state directory instead. When
+	// empty it follows the repository's host.
+	Provider string `yaml:"provider,omitempty"`
+
+	// CloneProtocol clones the dependent over https or ssh instead of its
+	// host's configured protocol. It does not apply to an explicit CloneURL.
+	CloneProtocol string `yaml:"clone_protocol,omitempty"`
+
+	// Retry bounds how failed updates of the dependent are retried by resume.
+	Retry RetryPolicy `yaml:"retry,omitempty"`
+
+	// Changelog writes an entry describing each update into the dependent.
+	Changelog Changelog `yaml:"changelog,omitempty"`
 }
 
+// Update strategy values accepted by Dependent.UpdateStrategy.
+const (
+	UpdateStrategyGoModules    = "go-modules"
+	UpdateStrategyGitSubmodule = "git-submodule"
+)
+
+// go.work modes accepted by Dependent.GoWork.
+const (
+	GoWorkRespect = "respect"
+	GoWorkOff     = "off"
+)
+
+// Provider values accepted by Dependent.Provider.
+const (
+	ProviderGitHub    = "github"
+	ProviderGitLab    = "gitlab"
+	ProviderBitbucket = "bitbucket"
+	ProviderSandbox   = "sandbox"
+)
+
+// Clone protocol values accepted by Dependent.CloneProtocol.
+const (
+	CloneProtocolHTTPS = "https"
+	CloneProtocolSSH   = "ssh"
+)
+
+// Check strategy values accepted by Dependent.CheckStrategy.
+const (
+	CheckStrategyLocal  = "local"
+	CheckStrategyRemote = "remote"
+	CheckStrategyAuto   = "auto"
+)
+
 // Command represents an executable command.
 type Command struct {
 	Cmd []string `yaml:"cmd"`
@@ -89,6 +240,26 @@ type PRConfig struct {
 	BodyTemplate  string   `yaml:"body_template,omitempty"`
 	Reviewers     []string `yaml:"reviewers,omitempty"`
 	TeamReviewers []string `yaml:"team_reviewers,omitempty"`
+
+	// BodyTemplateFile is a Go template file rendering the PR body, relative to
+	// the manifest that declares it. It replaces the configured body template.
+	BodyTemplateFile string `yaml:"body_template_file,omitempty" json:"BodyTemplateFile,omitempty"`
+
+	// Draft opens pull requests as drafts on providers that support them
+	Draft bool `yaml:"draft,omitempty" json:"Draft,omitempty"`
+
+	// Base is the branch pull requests target, such as release/1.x. Updates
+	// start from it instead of the dependent's branch.
+	Base string `yaml:"base,omitempty" json:"Base,omitempty"`
+
+	// DeleteBranchOnMerge removes the update branch once its pull request
+	// merges, so dependents do not collect stale cascade branches.
+	DeleteBranchOnMerge bool `yaml:"delete_branch_on_merge,omitempty" json:"DeleteBranchOnMerge,omitempty"`
+
+	// AutoMerge merges pull requests with this method, squash, merge, or
+	// rebase, once their checks pass and approvals are met. Empty leaves
+	// merging to people.
+	AutoMerge string `yaml:"auto_merge,omitempty" json:"AutoMerge,omitempty"`
 }
 
 // Notifications holds optional notification targets.
@@ -104,4 +275,25 @@ type Notifications struct {
 type GitHubIssueNotification struct {
 	Enabled bool     `yaml:"enabled"`
 	Labels  []string `yaml:"labels,omitempty"`
+
+	// TitleTemplate and BodyTemplate are Go templates rendered with the same data
+	// as notification templates. Empty values use the built-in templates. Failures
+	// are matched to existing issues by rendered title.
+	TitleTemplate string `yaml:"title_template,omitempty"`
+	BodyTemplate  string `yaml:"body_template,omitempty"`
+
+	// Assignees are GitHub logins assigned to newly created issues.
+	Assignees []string `yaml:"assignees,omitempty"`
+
+	// OnRecurrence selects what happens when a failure already has an issue:
+	// "ignore" leaves it as is (default), "comment" comments on the open issue,
+	// and "reopen" also reopens a closed one instead of creating a new issue.
+	OnRecurrence string `yaml:"on_recurrence,omitempty"`
 }
+
+// Values accepted by GitHubIssueNotification.OnRecurrence.
+const (
+	IssueRecurrenceIgnore  = "ignore"
+	IssueRecurrenceComment = "comment"
+	IssueRecurrenceReopen  = "reopen"
+)
@@ -2,7 +2,12 @@ package manifest
 
 import (
 	"fmt"
+	"path/filepath"
+	"sort"
 	"strings"
+	"text/template"
+
+	"github.com/goliatone/cascade/pkg/gitutil"
 )
 
 // Validate performs schema and dependency checks on a manifest.
@@ -32,12 +37,20 @@ func Validate(m *Manifest) error {
 		}
 	}
 
+	issues = append(issues, lintGitHubIssues("defaults.notifications.github_issues", m.Defaults.Notifications.GitHubIssues)...)
+	issues = append(issues, lintRetryPolicy("defaults.retry", m.Defaults.Retry)...)
+	issues = append(issues, lintChangelog("defaults.changelog", m.Defaults.Changelog)...)
+	issues = append(issues, lintPRBase("defaults.pr.base", m.Defaults.PR.Base)...)
+	issues = append(issues, lintPRAutoMerge("defaults.pr.auto_merge", m.Defaults.PR.AutoMerge)...)
+	issues = append(issues, lintNeverTouch(m)...)
+
 	if m.Modules == nil {
 		issues = append(issues, "modules cannot be nil")
 	} else {
 		// check for duplicate module names and build moduleByPath map
 		moduleNames := make(map[string]bool)
 		moduleByPath := make(map[string]string) // modulePath -> name
+		aliasOwner := make(map[string]string)   // alias -> name
 		for i, module := range m.Modules {
 			if module.Name == "" {
 				issues = append(issues, fmt.Sprintf("module[%d] name cannot be empty", i))
@@ -57,6 +70,21 @@ func Validate(m *Manifest) error {
 			if module.Repo == "" {
 				issues = append(issues, fmt.Sprintf("module[%d] (%s) repo cannot be empty", i, module.Name))
 			}
+			for j, alias := range module.Aliases {
+				alias = strings.TrimSpace(alias)
+				switch {
+				case alias == "":
+					issues = append(issues, fmt.Sprintf("module[%d] (%s) alias[%d] cannot be empty", i, module.Name, j))
+				case alias == module.Module:
+					issues = append(issues, fmt.Sprintf("module[%d] (%s) alias %s repeats the module path", i, module.Name, alias))
+				case aliasOwner[alias] != "" && aliasOwner[alias] != module.Name:
+					issues = append(issues, fmt.Sprintf("module[%d] (%s) alias %s is already an alias of %s", i, module.Name, alias, aliasOwner[alias]))
+				default:
+					aliasOwner[alias] = module.Name
+				}
+			}
+
+			issues = append(issues, lintVersionResolution(fmt.Sprintf("module[%d] (%s) version_resolution", i, module.Name), module.VersionResolution)...)
 
 			// dependents are not nil
 			if module.Dependents == nil {
@@ -79,6 +107,56 @@ func Validate(m *Manifest) error {
 					if dep.ModulePath == "" {
 						issues = append(issues, fmt.Sprintf("module[%d] (%s) dependent[%d] (%s) module_path cannot be empty", i, module.Name, j, dep.Repo))
 					}
+					switch dep.CheckStrategy {
+					case "", CheckStrategyLocal, CheckStrategyRemote, CheckStrategyAuto:
+					default:
+						issues = append(issues, fmt.Sprintf("module[%d] (%s) dependent[%d] (%s) check_strategy must be one of local, remote, auto (got %q)", i, module.Name, j, dep.Repo, dep.CheckStrategy))
+					}
+					switch dep.UpdateStrategy {
+					case "", UpdateStrategyGoModules:
+						if dep.SubmodulePath != "" {
+							issues = append(issues, fmt.Sprintf("module[%d] (%s) dependent[%d] (%s) submodule_path requires update_strategy %s", i, module.Name, j, dep.Repo, UpdateStrategyGitSubmodule))
+						}
+					case UpdateStrategyGitSubmodule:
+						if path := dep.SubmodulePath; path != "" && (filepath.IsAbs(path) || path == ".." || strings.HasPrefix(filepath.ToSlash(path), "../")) {
+							issues = append(issues, fmt.Sprintf("module[%d] (%s) dependent[%d] (%s) submodule_path must be relative to the repository (got %q)", i, module.Name, j, dep.Repo, path))
+						}
+					default:
+						issues = append(issues, fmt.Sprintf("module[%d] (%s) dependent[%d] (%s) update_strategy must be one of %s, %s (got %q)", i, module.Name, j, dep.Repo, UpdateStrategyGoModules, UpdateStrategyGitSubmodule, dep.UpdateStrategy))
+					}
+					if dep.BumpStrategy != "" {
+						if _, ok := LookupBumpStrategy(dep.BumpStrategy); !ok {
+							issues = append(issues, fmt.Sprintf("module[%d] (%s) dependent[%d] (%s) bump_strategy must be one of %s (got %q)", i, module.Name, j, dep.Repo, strings.Join(BumpStrategyNames(), ", "), dep.BumpStrategy))
+						} else if dep.UpdateStrategy == UpdateStrategyGitSubmodule {
+							issues = append(issues, fmt.Sprintf("module[%d] (%s) dependent[%d] (%s) bump_strategy does not apply to update_strategy %s", i, module.Name, j, dep.Repo, UpdateStrategyGitSubmodule))
+						}
+					}
+					switch dep.GoWork {
+					case "", GoWorkRespect, GoWorkOff:
+					default:
+						issues = append(issues, fmt.Sprintf("module[%d] (%s) dependent[%d] (%s) go_work must be one of %s, %s (got %q)", i, module.Name, j, dep.Repo, GoWorkRespect, GoWorkOff, dep.GoWork))
+					}
+					switch dep.Provider {
+					case "", ProviderGitHub, ProviderGitLab, ProviderBitbucket, ProviderSandbox:
+					default:
+						issues = append(issues, fmt.Sprintf("module[%d] (%s) dependent[%d] (%s) provider must be one of %s, %s, %s, %s (got %q)", i, module.Name, j, dep.Repo, ProviderGitHub, ProviderGitLab, ProviderBitbucket, ProviderSandbox, dep.Provider))
+					}
+					switch dep.CloneProtocol {
+					case "", CloneProtocolHTTPS, CloneProtocolSSH:
+					default:
+						issues = append(issues, fmt.Sprintf("module[%d] (%s) dependent[%d] (%s) clone_protocol must be one of %s, %s (got %q)", i, module.Name, j, dep.Repo, CloneProtocolHTTPS, CloneProtocolSSH, dep.CloneProtocol))
+					}
+					if dep.CheckCacheTTL < 0 {
+						issues = append(issues, fmt.Sprintf("module[%d] (%s) dependent[%d] (%s) check_cache_ttl cannot be negative", i, module.Name, j, dep.Repo))
+					}
+					issues = append(issues, lintServices(fmt.Sprintf("module[%d] (%s) dependent[%d] (%s) services", i, module.Name, j, dep.Repo), dep.Services)...)
+					issues = append(issues, lintCredentials(fmt.Sprintf("module[%d] (%s) dependent[%d] (%s) credentials", i, module.Name, j, dep.Repo), dep.Credentials)...)
+					issues = append(issues, lintEnv(fmt.Sprintf("module[%d] (%s) dependent[%d] (%s) env", i, module.Name, j, dep.Repo), dep.Env)...)
+					issues = append(issues, lintGitHubIssues(fmt.Sprintf("module[%d] (%s) dependent[%d] (%s) notifications.github_issues", i, module.Name, j, dep.Repo), dep.Notifications.GitHubIssues)...)
+					issues = append(issues, lintRetryPolicy(fmt.Sprintf("module[%d] (%s) dependent[%d] (%s) retry", i, module.Name, j, dep.Repo), dep.Retry)...)
+					issues = append(issues, lintChangelog(fmt.Sprintf("module[%d] (%s) dependent[%d] (%s) changelog", i, module.Name, j, dep.Repo), dep.Changelog)...)
+					issues = append(issues, lintPRBase(fmt.Sprintf("module[%d] (%s) dependent[%d] (%s) pr.base", i, module.Name, j, dep.Repo), dep.PR.Base)...)
+					issues = append(issues, lintPRAutoMerge(fmt.Sprintf("module[%d] (%s) dependent[%d] (%s) pr.auto_merge", i, module.Name, j, dep.Repo), dep.PR.AutoMerge)...)
 				}
 			}
 		}
@@ -96,6 +174,45 @@ func Validate(m *Manifest) error {
 	return nil
 }
 
+// lintVersionResolution checks that every resolver is registered and listed once.
+func lintVersionResolution(field string, names []string) []string {
+	var issues []string
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		switch {
+		case name == "":
+			issues = append(issues, fmt.Sprintf("%s cannot contain empty entries", field))
+		case seen[name]:
+			issues = append(issues, fmt.Sprintf("%s lists %s more than once", field, name))
+		default:
+			seen[name] = true
+			if _, ok := versionResolverFactory(name); !ok {
+				issues = append(issues, fmt.Sprintf("%s has unknown resolver %q (available: %s)", field, name, strings.Join(VersionResolverNames(), ", ")))
+			}
+		}
+	}
+	return issues
+}
+
+// lintEnv checks that every env value parses as a template. Values are
+// rendered with the work item when the executor runs the dependent.
+func lintEnv(field string, env map[string]string) []string {
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var issues []string
+	for _, key := range keys {
+		if _, err := template.New(key).Option("missingkey=error").Parse(env[key]); err != nil {
+			issues = append(issues, fmt.Sprintf("%s %s is not a valid template: %v", field, key, err))
+		}
+	}
+	return issues
+}
+
 // detectCycles uses DFS to find dependency cycles in the module graph.
 func detectCycles(modules []Module, moduleByPath map[string]string) []string {
 	var issues []string
@@ -164,3 +281,46 @@ func dfs(moduleName string, moduleMap map[string]*Module, moduleByPath map[strin
 	visited[moduleName] = 2 // Mark as visited
 	return nil
 }
+
+// lintPRBase reports a pull request base under field that is not a valid
+// branch name. An empty base is valid.
+func lintPRBase(field, base string) []string {
+	if base == "" {
+		return nil
+	}
+	if err := gitutil.ValidateBranchName(base); err != nil {
+		return []string{fmt.Sprintf("%s %q is not a valid branch name: %v", field, base, err)}
+	}
+	return nil
+}
+
+// lintPRAutoMerge reports a pull request auto-merge method under field that is
+// not squash, merge, or rebase. An empty method is valid.
+func lintPRAutoMerge(field, method string) []string {
+	switch method {
+	case "", "squash", "merge", "rebase":
+		return nil
+	}
+	return []string{fmt.Sprintf("%s %q must be squash, merge, or rebase", field, method)}
+}
+
+// lintGitHubIssues reports configuration problems in GitHub issue notification
+// settings under field.
+func lintGitHubIssues(field string, cfg *GitHubIssueNotification) []string {
+	if cfg == nil {
+		return nil
+	}
+
+	var issues []string
+	switch cfg.OnRecurrence {
+	case "", IssueRecurrenceIgnore, IssueRecurrenceComment, IssueRecurrenceReopen:
+	default:
+		issues = append(issues, fmt.Sprintf("%s.on_recurrence must be one of ignore, comment, reopen (got %q)", field, cfg.OnRecurrence))
+	}
+	for i, assignee := range cfg.Assignees {
+		if strings.TrimSpace(assignee) == "" || strings.ContainsAny(assignee, " \t@") {
+			issues = append(issues, fmt.Sprintf("%s.assignees[%d] %q must be a GitHub login", field, i, assignee))
+		}
+	}
+	return issues
+}
@@ -0,0 +1,244 @@
+package manifest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/goliatone/cascade/pkg/util/modpath"
+)
+
+// Built-in version resolvers, usable in a module's version_resolution list.
+const (
+	// VersionResolverLocal reads the version required by modules in the workspace.
+	VersionResolverLocal = "local"
+
+	// VersionResolverProxy asks the Go module proxy for the latest version.
+	VersionResolverProxy = "proxy"
+
+	// VersionResolverTags picks the latest semantic version tag via the GitHub API.
+	VersionResolverTags = "tags"
+
+	// VersionResolverGitRemote picks the latest semantic version tag via git ls-remote.
+	VersionResolverGitRemote = "git-remote"
+)
+
+// DefaultVersionResolution is the chain used when a module does not set
+// version_resolution: the workspace first, then the module proxy.
+var DefaultVersionResolution = []string{VersionResolverLocal, VersionResolverProxy}
+
+// VersionRequest describes the module whose version is being resolved.
+type VersionRequest struct {
+	// Module is the Go module path.
+	Module string
+
+	// Repository is the owner/repo hosting the module. When empty it is derived
+	// from the module path.
+	Repository string
+
+	// WorkspaceDir is scanned by the local resolver.
+	WorkspaceDir string
+}
+
+// VersionResolver resolves the version of a module from one source.
+type VersionResolver interface {
+	ResolveVersion(ctx context.Context, req VersionRequest) (*VersionResolution, error)
+}
+
+// VersionResolverFunc adapts a function to VersionResolver.
+type VersionResolverFunc func(ctx context.Context, req VersionRequest) (*VersionResolution, error)
+
+// ResolveVersion calls f.
+func (f VersionResolverFunc) ResolveVersion(ctx context.Context, req VersionRequest) (*VersionResolution, error) {
+	return f(ctx, req)
+}
+
+// VersionResolverEnv holds the clients a resolver factory may use. Either field
+// may be nil.
+type VersionResolverEnv struct {
+	Workspace WorkspaceDiscovery
+	GitHub    GitHubDiscovery
+}
+
+// VersionResolverFactory builds a resolver for a chain.
+type VersionResolverFactory func(env VersionResolverEnv) (VersionResolver, error)
+
+var versionResolvers = struct {
+	sync.RWMutex
+	factories map[string]VersionResolverFactory
+}{
+	factories: map[string]VersionResolverFactory{
+		VersionResolverLocal:     newLocalVersionResolver,
+		VersionResolverProxy:     newProxyVersionResolver,
+		VersionResolverTags:      newTagsVersionResolver,
+		VersionResolverGitRemote: newGitRemoteVersionResolver,
+	},
+}
+
+// RegisterVersionResolver makes a custom resolver, such as one querying an
+// internal release API, available to version_resolution lists under name.
+// Register resolvers from an init function, before manifests are validated.
+// Names cannot be registered twice, so built-in resolvers cannot be replaced.
+func RegisterVersionResolver(name string, factory VersionResolverFactory) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("version resolver name cannot be empty")
+	}
+	if factory == nil {
+		return fmt.Errorf("version resolver %s needs a factory", name)
+	}
+
+	versionResolvers.Lock()
+	defer versionResolvers.Unlock()
+	if _, exists := versionResolvers.factories[name]; exists {
+		return fmt.Errorf("version resolver %s is already registered", name)
+	}
+	versionResolvers.factories[name] = factory
+	return nil
+}
+
+// VersionResolverNames returns the registered resolver names in sorted order.
+func VersionResolverNames() []string {
+	versionResolvers.RLock()
+	defer versionResolvers.RUnlock()
+	names := make([]string, 0, len(versionResolvers.factories))
+	for name := range versionResolvers.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func versionResolverFactory(name string) (VersionResolverFactory, bool) {
+	versionResolvers.RLock()
+	defer versionResolvers.RUnlock()
+	factory, ok := versionResolvers.factories[name]
+	return factory, ok
+}
+
+// VersionResolverChain tries resolvers in order and returns the first version
+// found. Failures of earlier resolvers are kept as warnings.
+type VersionResolverChain struct {
+	names     []string
+	resolvers []VersionResolver
+}
+
+// NewVersionResolverChain builds a chain from registered resolver names. An
+// empty list selects DefaultVersionResolution.
+func NewVersionResolverChain(names []string, env VersionResolverEnv) (*VersionResolverChain, error) {
+	if len(names) == 0 {
+		names = DefaultVersionResolution
+	}
+
+	chain := &VersionResolverChain{}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		factory, ok := versionResolverFactory(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown version resolver %q (available: %s)", name, strings.Join(VersionResolverNames(), ", "))
+		}
+		resolver, err := factory(env)
+		if err != nil {
+			return nil, fmt.Errorf("version resolver %s: %w", name, err)
+		}
+		chain.add(name, resolver)
+	}
+	return chain, nil
+}
+
+func (c *VersionResolverChain) add(name string, resolver VersionResolver) {
+	c.names = append(c.names, name)
+	c.resolvers = append(c.resolvers, resolver)
+}
+
+// Names returns the resolver names in the order they are tried.
+func (c *VersionResolverChain) Names() []string {
+	return append([]string(nil), c.names...)
+}
+
+// ResolveVersion returns the first resolution produced by the chain. The
+// resolution's Resolver names the resolver that produced it.
+func (c *VersionResolverChain) ResolveVersion(ctx context.Context, req VersionRequest) (*VersionResolution, error) {
+	if req.Module == "" {
+		return nil, fmt.Errorf("target module is required")
+	}
+	if len(c.resolvers) == 0 {
+		return nil, fmt.Errorf("no version resolvers configured")
+	}
+
+	var (
+		warnings []string
+		errs     []error
+	)
+	for i, resolver := range c.resolvers {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		resolution, err := resolver.ResolveVersion(ctx, req)
+		if err == nil && (resolution == nil || resolution.Version == "") {
+			err = fmt.Errorf("no version found")
+		}
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s resolution failed: %v", c.names[i], err))
+			errs = append(errs, fmt.Errorf("%s: %w", c.names[i], err))
+			continue
+		}
+		if resolution.Resolver == "" {
+			resolution.Resolver = c.names[i]
+		}
+		resolution.Warnings = append(warnings, resolution.Warnings...)
+		return resolution, nil
+	}
+	return nil, fmt.Errorf("failed to resolve a version for %s (tried %s): %w", req.Module, strings.Join(c.names, ", "), errors.Join(errs...))
+}
+
+// requestRepository returns the owner/repo of the request.
+func requestRepository(req VersionRequest) string {
+	if req.Repository != "" {
+		return req.Repository
+	}
+	return modpath.DeriveRepository(req.Module)
+}
+
+func newLocalVersionResolver(env VersionResolverEnv) (VersionResolver, error) {
+	discovery, ok := env.Workspace.(*workspaceDiscovery)
+	if !ok || discovery == nil {
+		discovery = &workspaceDiscovery{}
+	}
+	return VersionResolverFunc(func(ctx context.Context, req VersionRequest) (*VersionResolution, error) {
+		if req.WorkspaceDir == "" {
+			return nil, fmt.Errorf("workspace directory is required")
+		}
+		return discovery.resolveLocalVersion(ctx, req.WorkspaceDir, req.Module, &VersionResolution{Warnings: []string{}})
+	}), nil
+}
+
+func newProxyVersionResolver(VersionResolverEnv) (VersionResolver, error) {
+	return VersionResolverFunc(func(ctx context.Context, req VersionRequest) (*VersionResolution, error) {
+		return resolveProxyVersion(ctx, req.Module, &VersionResolution{Warnings: []string{}})
+	}), nil
+}
+
+func newTagsVersionResolver(env VersionResolverEnv) (VersionResolver, error) {
+	discovery, _ := env.GitHub.(*gitHubDiscovery)
+	return VersionResolverFunc(func(ctx context.Context, req VersionRequest) (*VersionResolution, error) {
+		if discovery == nil || discovery.client == nil {
+			return nil, fmt.Errorf("tags resolution requires a GitHub token")
+		}
+		return discovery.resolveVersionFromTags(ctx, requestRepository(req), &VersionResolution{Warnings: []string{}})
+	}), nil
+}
+
+func newGitRemoteVersionResolver(env VersionResolverEnv) (VersionResolver, error) {
+	discovery, _ := env.GitHub.(*gitHubDiscovery)
+	if discovery == nil {
+		// git ls-remote only needs clone URLs, not an API client
+		discovery = &gitHubDiscovery{}
+	}
+	return VersionResolverFunc(func(ctx context.Context, req VersionRequest) (*VersionResolution, error) {
+		return discovery.resolveVersionFromGitRemote(ctx, requestRepository(req), &VersionResolution{Warnings: []string{}})
+	}), nil
+}
@@ -0,0 +1,146 @@
+package manifest
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func staticResolver(version string, err error) VersionResolver {
+	return VersionResolverFunc(func(context.Context, VersionRequest) (*VersionResolution, error) {
+		if err != nil {
+			return nil, err
+		}
+		return &VersionResolution{Version: version, Source: VersionSourceNetwork}, nil
+	})
+}
+
+func TestVersionResolverChainFallsThrough(t *testing.T) {
+	chain := &VersionResolverChain{}
+	chain.add("first", staticResolver("", errors.New("unreachable")))
+	chain.add("empty", staticResolver("", nil))
+	chain.add("third", staticResolver("v1.4.0", nil))
+	chain.add("unused", staticResolver("v9.9.9", nil))
+
+	resolution, err := chain.ResolveVersion(context.Background(), VersionRequest{Module: "example.com/lib"})
+	if err != nil {
+		t.Fatalf("ResolveVersion() error = %v", err)
+	}
+	if resolution.Version != "v1.4.0" || resolution.Resolver != "third" {
+		t.Errorf("resolution = %s from %s, want v1.4.0 from third", resolution.Version, resolution.Resolver)
+	}
+	want := []string{"first resolution failed: unreachable", "empty resolution failed: no version found"}
+	if !reflect.DeepEqual(resolution.Warnings, want) {
+		t.Errorf("warnings = %v, want %v", resolution.Warnings, want)
+	}
+}
+
+func TestVersionResolverChainReportsEveryFailure(t *testing.T) {
+	chain := &VersionResolverChain{}
+	chain.add("first", staticResolver("", errors.New("offline")))
+	chain.add("second", staticResolver("", errors.New("no tags")))
+
+	_, err := chain.ResolveVersion(context.Background(), VersionRequest{Module: "example.com/lib"})
+	if err == nil {
+		t.Fatal("expected an error when every resolver fails")
+	}
+	for _, want := range []string{"tried first, second", "first: offline", "second: no tags"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q missing %q", err, want)
+		}
+	}
+
+	if _, err := chain.ResolveVersion(context.Background(), VersionRequest{}); err == nil {
+		t.Error("expected the module to be required")
+	}
+}
+
+func TestRegisterVersionResolver(t *testing.T) {
+	name := "test-release-api"
+	var got VersionRequest
+	err := RegisterVersionResolver(name, func(env VersionResolverEnv) (VersionResolver, error) {
+		return VersionResolverFunc(func(_ context.Context, req VersionRequest) (*VersionResolution, error) {
+			got = req
+			return &VersionResolution{Version: "v2.0.0", Source: VersionSourceNetwork}, nil
+		}), nil
+	})
+	if err != nil {
+		t.Fatalf("RegisterVersionResolver() error = %v", err)
+	}
+
+	if err := RegisterVersionResolver(name, func(VersionResolverEnv) (VersionResolver, error) { return nil, nil }); err == nil {
+		t.Error("expected registering a name twice to fail")
+	}
+	if err := RegisterVersionResolver(VersionResolverProxy, func(VersionResolverEnv) (VersionResolver, error) { return nil, nil }); err == nil {
+		t.Error("expected built-in resolvers not to be replaceable")
+	}
+	if err := RegisterVersionResolver("no-factory", nil); err == nil {
+		t.Error("expected a factory to be required")
+	}
+
+	found := false
+	for _, registered := range VersionResolverNames() {
+		found = found || registered == name
+	}
+	if !found {
+		t.Errorf("VersionResolverNames() = %v, missing %s", VersionResolverNames(), name)
+	}
+
+	chain, err := NewVersionResolverChain([]string{"local", name}, VersionResolverEnv{})
+	if err != nil {
+		t.Fatalf("NewVersionResolverChain() error = %v", err)
+	}
+	if !reflect.DeepEqual(chain.Names(), []string{"local", name}) {
+		t.Errorf("chain names = %v", chain.Names())
+	}
+
+	// The workspace does not require the module, so the custom resolver answers
+	req := VersionRequest{Module: "github.com/target/module", WorkspaceDir: filepath.Join("testdata", "workspace-discovery")}
+	resolution, err := chain.ResolveVersion(context.Background(), req)
+	if err != nil {
+		t.Fatalf("ResolveVersion() error = %v", err)
+	}
+	if resolution.Version != "v2.0.0" || resolution.Resolver != name || got.Module != req.Module {
+		t.Errorf("resolution = %+v, request = %+v", resolution, got)
+	}
+	if len(resolution.Warnings) != 1 || !strings.HasPrefix(resolution.Warnings[0], "local resolution failed") {
+		t.Errorf("warnings = %v, want the local failure", resolution.Warnings)
+	}
+}
+
+func TestNewVersionResolverChain(t *testing.T) {
+	chain, err := NewVersionResolverChain(nil, VersionResolverEnv{})
+	if err != nil {
+		t.Fatalf("NewVersionResolverChain(nil) error = %v", err)
+	}
+	if !reflect.DeepEqual(chain.Names(), DefaultVersionResolution) {
+		t.Errorf("default chain = %v, want %v", chain.Names(), DefaultVersionResolution)
+	}
+
+	if _, err := NewVersionResolverChain([]string{"proxy", "nightly"}, VersionResolverEnv{}); err == nil || !strings.Contains(err.Error(), `unknown version resolver "nightly"`) {
+		t.Errorf("expected unknown resolvers to be rejected, got %v", err)
+	}
+}
+
+func TestTagsResolverRequiresGitHub(t *testing.T) {
+	chain, err := NewVersionResolverChain([]string{VersionResolverTags}, VersionResolverEnv{})
+	if err != nil {
+		t.Fatalf("NewVersionResolverChain() error = %v", err)
+	}
+	_, err = chain.ResolveVersion(context.Background(), VersionRequest{Module: "github.com/example/lib"})
+	if err == nil || !strings.Contains(err.Error(), "requires a GitHub token") {
+		t.Errorf("expected a missing token error, got %v", err)
+	}
+}
+
+func TestRequestRepository(t *testing.T) {
+	if got := requestRepository(VersionRequest{Module: "github.com/example/lib/v2"}); got != "example/lib" {
+		t.Errorf("derived repository = %q, want example/lib", got)
+	}
+	if got := requestRepository(VersionRequest{Module: "go.example.com/lib", Repository: "example/lib"}); got != "example/lib" {
+		t.Errorf("explicit repository = %q, want example/lib", got)
+	}
+}
@@ -0,0 +1,68 @@
+package planner
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// PlanArtifactVersion is the format version of plan artifacts. ReadPlanArtifact
+// rejects artifacts written in another format.
+const PlanArtifactVersion = 1
+
+// PlanArtifact is a plan saved for review and executed later as it is, with
+// what is needed to tell whether it still applies.
+type PlanArtifact struct {
+	Version        int       `json:"version"`
+	CascadeVersion string    `json:"cascade_version,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+
+	// ManifestPath is the manifest the plan was made from, as it was given.
+	ManifestPath string `json:"manifest_path,omitempty"`
+
+	// ManifestHash is the SHA-256 of the manifest content the plan was made from.
+	ManifestHash string `json:"manifest_hash"`
+
+	// ManifestRef is the git ref the manifest was read from, when the plan was
+	// made against an earlier revision rather than the working copy.
+	ManifestRef string `json:"manifest_ref,omitempty"`
+
+	// ManifestCommit is the commit ManifestRef resolved to.
+	ManifestCommit string `json:"manifest_commit,omitempty"`
+
+	Plan Plan `json:"plan"`
+}
+
+// WritePlanArtifact writes artifact to path as indented JSON, setting its
+// format version.
+func WritePlanArtifact(path string, artifact PlanArtifact) error {
+	artifact.Version = PlanArtifactVersion
+	data, err := json.MarshalIndent(artifact, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode plan: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("write plan: %w", err)
+	}
+	return nil
+}
+
+// ReadPlanArtifact reads an artifact written by WritePlanArtifact.
+func ReadPlanArtifact(path string) (*PlanArtifact, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read plan: %w", err)
+	}
+	var artifact PlanArtifact
+	if err := json.Unmarshal(data, &artifact); err != nil {
+		return nil, fmt.Errorf("decode plan %s: %w", path, err)
+	}
+	if artifact.Version != PlanArtifactVersion {
+		return nil, fmt.Errorf("plan %s has format version %d, expected %d", path, artifact.Version, PlanArtifactVersion)
+	}
+	if artifact.ManifestHash == "" || artifact.Plan.Target.Module == "" || artifact.Plan.Target.Version == "" {
+		return nil, fmt.Errorf("plan %s is missing its target or manifest hash", path)
+	}
+	return &artifact, nil
+}
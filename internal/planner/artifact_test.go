@@ -0,0 +1,62 @@
+package planner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPlanArtifactRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plan.json")
+	artifact := PlanArtifact{
+		CascadeVersion: "v0.9.0",
+		CreatedAt:      time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		ManifestPath:   ".cascade.yaml",
+		ManifestHash:   "abc123",
+		Plan: Plan{
+			Target: Target{Module: "example.com/lib", Version: "v1.2.0"},
+			Items: []WorkItem{{
+				Repo:    "example/app",
+				Module:  "example.com/app",
+				Branch:  "main",
+				Labels:  []string{"automation:cascade"},
+				Timeout: 5 * time.Minute,
+			}},
+		},
+	}
+	if err := WritePlanArtifact(path, artifact); err != nil {
+		t.Fatalf("WritePlanArtifact() error = %v", err)
+	}
+
+	got, err := ReadPlanArtifact(path)
+	if err != nil {
+		t.Fatalf("ReadPlanArtifact() error = %v", err)
+	}
+	if got.Version != PlanArtifactVersion || got.ManifestHash != "abc123" || !got.CreatedAt.Equal(artifact.CreatedAt) {
+		t.Errorf("unexpected artifact: %+v", got)
+	}
+	if got.Plan.Target.Module != "example.com/lib" || got.Plan.Target.Version != "v1.2.0" || len(got.Plan.Items) != 1 ||
+		got.Plan.Items[0].Timeout != 5*time.Minute || got.Plan.Items[0].Labels[0] != "automation:cascade" {
+		t.Errorf("plan did not round trip: %+v", got.Plan)
+	}
+}
+
+func TestReadPlanArtifactRejectsInvalid(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"version": `{"version": 2, "manifest_hash": "abc", "plan": {"Target": {"Module": "m", "Version": "v1.0.0"}}}`,
+		"hash":    `{"version": 1, "plan": {"Target": {"Module": "m", "Version": "v1.0.0"}}}`,
+		"target":  `{"version": 1, "manifest_hash": "abc", "plan": {}}`,
+		"json":    `not json`,
+	} {
+		path := filepath.Join(dir, name+".json")
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ReadPlanArtifact(path); err == nil || !strings.Contains(err.Error(), path) {
+			t.Errorf("%s: expected an error naming the file, got %v", name, err)
+		}
+	}
+}
@@ -80,6 +80,16 @@ func (c *dependencyCache) deleteEntryIfUnchanged(key cacheKey, snapshot *cacheEn
 // Set stores all dependencies for a repository at a specific ref.
 // This caches the entire go.mod dependency set for efficient batch lookups.
 func (c *dependencyCache) Set(cloneURL, ref string, deps map[string]string) {
+	c.SetWithTTL(cloneURL, ref, deps, 0)
+}
+
+// SetWithTTL stores dependencies like Set but with an entry-specific TTL.
+// A zero or negative TTL falls back to the cache-wide default.
+func (c *dependencyCache) SetWithTTL(cloneURL, ref string, deps map[string]string, ttl time.Duration) {
+	if ttl <= 0 {
+		ttl = c.ttl
+	}
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -87,7 +97,7 @@ func (c *dependencyCache) Set(cloneURL, ref string, deps map[string]string) {
 	c.entries[key] = &cacheEntry{
 		dependencies: deps,
 		cachedAt:     time.Now(),
-		ttl:          c.ttl,
+		ttl:          ttl,
 	}
 }
 
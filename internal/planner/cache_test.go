@@ -87,6 +87,24 @@ func TestDependencyCache_TTLExpiration(t *testing.T) {
 	}
 }
 
+func TestDependencyCache_SetWithTTL_OverridesDefault(t *testing.T) {
+	shortTTL := 50 * time.Millisecond
+	cache := newDependencyCache(shortTTL)
+
+	deps := map[string]string{"github.com/foo/bar": "v1.2.3"}
+	cache.SetWithTTL("https://github.com/user/long", "main", deps, time.Hour)
+	cache.SetWithTTL("https://github.com/user/default", "main", deps, 0)
+
+	time.Sleep(shortTTL + 10*time.Millisecond)
+
+	if _, found := cache.Get("https://github.com/user/long", "main", "github.com/foo/bar", ""); !found {
+		t.Error("Expected entry with extended TTL to remain cached")
+	}
+	if _, found := cache.Get("https://github.com/user/default", "main", "github.com/foo/bar", ""); found {
+		t.Error("Expected entry with default TTL to expire")
+	}
+}
+
 func TestDependencyCache_Prune(t *testing.T) {
 	shortTTL := 50 * time.Millisecond
 	cache := newDependencyCache(shortTTL)
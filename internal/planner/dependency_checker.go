@@ -4,10 +4,10 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"path/filepath"
 	"strings"
 
 	"github.com/goliatone/cascade/internal/manifest"
+	"github.com/goliatone/cascade/pkg/workspace/layout"
 )
 
 // Logger defines the interface for logging.
@@ -21,13 +21,29 @@ type Logger interface {
 // dependencyChecker implements the DependencyChecker interface.
 type dependencyChecker struct {
 	logger Logger
+	layout layout.Layout
+}
+
+// DependencyCheckerOption configures the workspace dependency checker.
+type DependencyCheckerOption func(*dependencyChecker)
+
+// WithCheckerLayout looks repositories up in the directories of l before the
+// conventional workspace locations.
+func WithCheckerLayout(l layout.Layout) DependencyCheckerOption {
+	return func(c *dependencyChecker) {
+		c.layout = l
+	}
 }
 
 // NewDependencyChecker creates a new DependencyChecker with optional logger.
-func NewDependencyChecker(logger Logger) DependencyChecker {
-	return &dependencyChecker{
+func NewDependencyChecker(logger Logger, opts ...DependencyCheckerOption) DependencyChecker {
+	c := &dependencyChecker{
 		logger: logger,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // NeedsUpdate determines if a dependent repository needs an update to the target version.
@@ -37,7 +53,7 @@ func NewDependencyChecker(logger Logger) DependencyChecker {
 // - Repository not found in workspace → return true (assume needs cloning/updating)
 // - go.mod not found → return error (malformed repository)
 // - Dependency not in go.mod → return false with warning (nothing to update)
-// - Replace directive present → return true with warning (manual review needed)
+// - Local replace or go.work copy of the target → return false with LocallyManagedError
 // - Parse failures → return error with context
 func (c *dependencyChecker) NeedsUpdate(ctx context.Context, dependent manifest.Dependent, target Target, workspace string) (bool, error) {
 	if c.logger != nil {
@@ -57,6 +73,7 @@ func (c *dependencyChecker) NeedsUpdate(ctx context.Context, dependent manifest.
 				"workspace", workspace,
 				"error", err.Error())
 		}
+		recordCheck(ctx, string(CheckStrategyLocal), "", "", "repository not found in workspace; update assumed")
 		return true, nil
 	}
 
@@ -81,8 +98,28 @@ func (c *dependencyChecker) NeedsUpdate(ctx context.Context, dependent manifest.
 		}
 	}
 
-	// 4. Extract current dependency version
-	currentVersion, err := ExtractDependency(modInfo, target.Module)
+	// 4. Skip dependents that build against a local copy of the target
+	local, err := detectLocallyManaged(repoPath, modInfo.File, dependent.Repo, target)
+	if err != nil {
+		if c.logger != nil {
+			c.logger.Warn("failed to inspect go.work, ignoring it",
+				"repo", dependent.Repo,
+				"error", err.Error())
+		}
+	} else if local != nil {
+		if c.logger != nil {
+			c.logger.Info("dependency managed locally, skipping update",
+				"repo", dependent.Repo,
+				"module", local.Module,
+				"source", local.Source,
+				"dir", local.Dir)
+		}
+		recordCheck(ctx, string(CheckStrategyLocal), checkSourceWorkspace, "", local.Reason())
+		return false, local
+	}
+
+	// 5. Extract current dependency version, accepting any alias of the target
+	currentVersion, err := extractTargetDependency(modInfo, target)
 	if err != nil {
 		// Dependency not found in go.mod - nothing to update
 		if strings.Contains(err.Error(), "not found") {
@@ -92,20 +129,10 @@ func (c *dependencyChecker) NeedsUpdate(ctx context.Context, dependent manifest.
 					"module", target.Module,
 					"reason", "manifest may be stale or dependency is indirect")
 			}
+			recordCheck(ctx, string(CheckStrategyLocal), checkSourceWorkspace, "", "dependency not present in go.mod")
 			return false, nil
 		}
 
-		// Replace directive with local path - needs manual review
-		if strings.Contains(err.Error(), "replace directive") {
-			if c.logger != nil {
-				c.logger.Warn("dependency has local replace directive, assuming update needed",
-					"repo", dependent.Repo,
-					"module", target.Module,
-					"reason", "manual review required for replace directives")
-			}
-			return true, nil
-		}
-
 		// Other parse errors
 		return false, &DependencyCheckError{
 			Dependent: dependent.Repo,
@@ -114,7 +141,7 @@ func (c *dependencyChecker) NeedsUpdate(ctx context.Context, dependent manifest.
 		}
 	}
 
-	// 5. Compare versions
+	// 6. Compare versions
 	needsUpdate, err := CompareVersions(currentVersion, target.Version)
 	if err != nil {
 		return false, &DependencyCheckError{
@@ -124,6 +151,8 @@ func (c *dependencyChecker) NeedsUpdate(ctx context.Context, dependent manifest.
 		}
 	}
 
+	recordCheck(ctx, string(CheckStrategyLocal), checkSourceWorkspace, currentVersion, versionReason(needsUpdate, currentVersion, target.Version))
+
 	if c.logger != nil {
 		if needsUpdate {
 			c.logger.Info("dependency needs update",
@@ -142,37 +171,37 @@ func (c *dependencyChecker) NeedsUpdate(ctx context.Context, dependent manifest.
 	return needsUpdate, nil
 }
 
-// locateRepository finds the repository path in the workspace.
+// extractTargetDependency extracts the version required under the target module
+// path or, when that is absent, under the first alias the go.mod requires.
+func extractTargetDependency(modInfo *ModuleInfo, target Target) (string, error) {
+	var firstErr error
+	for _, path := range target.Paths() {
+		version, err := ExtractDependency(modInfo, path)
+		if err == nil {
+			return version, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+		if !strings.Contains(err.Error(), "not found") {
+			return "", err
+		}
+	}
+	return "", firstErr
+}
+
+// locateRepository finds the repository path in the workspace: the directory of
+// the configured layout first, then workspace/repo, workspace/owner/repo and
+// workspace/host/owner/repo.
 func (c *dependencyChecker) locateRepository(dependent manifest.Dependent, workspace string) (string, error) {
 	if workspace == "" {
 		return "", fmt.Errorf("workspace path not configured")
 	}
-
-	// Extract repository name from repo field (e.g., "goliatone/go-crud" -> "go-crud")
-	parts := strings.Split(dependent.Repo, "/")
-	if len(parts) == 0 {
+	if strings.TrimSpace(dependent.Repo) == "" {
 		return "", fmt.Errorf("invalid repo format: %s", dependent.Repo)
 	}
-	repoName := parts[len(parts)-1]
-
-	// Try direct path: workspace/repoName
-	repoPath := filepath.Join(workspace, repoName)
-	if c.directoryExists(repoPath) {
-		return repoPath, nil
-	}
-
-	// Try with org: workspace/org/repoName
-	if len(parts) >= 2 {
-		org := parts[len(parts)-2]
-		repoPath = filepath.Join(workspace, org, repoName)
-		if c.directoryExists(repoPath) {
-			return repoPath, nil
-		}
-	}
 
-	// Try full path: workspace/host/org/repoName
-	if len(parts) >= 3 {
-		repoPath = filepath.Join(workspace, parts[len(parts)-3], parts[len(parts)-2], repoName)
+	for _, repoPath := range c.layout.Locations(workspace, dependent.Repo) {
 		if c.directoryExists(repoPath) {
 			return repoPath, nil
 		}
@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/goliatone/cascade/internal/manifest"
+	"github.com/goliatone/cascade/pkg/workspace/layout"
 )
 
 // TestDependencyCheckerInterface verifies the DependencyChecker interface contract.
@@ -402,6 +403,34 @@ func TestLocateRepository(t *testing.T) {
 	}
 }
 
+// TestLocateRepositoryWithLayout verifies the layout directory wins over the
+// conventional locations of a same-named repository.
+func TestLocateRepositoryWithLayout(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, repo := range []string{"api", "github.com/acme/api"} {
+		if err := createTestRepo(filepath.Join(tmpDir, repo)); err != nil {
+			t.Fatalf("Failed to create test repo %s: %v", repo, err)
+		}
+	}
+
+	nested, err := layout.Parse("{{host}}/{{owner}}/{{repo}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checker := NewDependencyChecker(nil, WithCheckerLayout(nested)).(*dependencyChecker)
+
+	got, err := checker.locateRepository(manifest.Dependent{Repo: "acme/api"}, tmpDir)
+	if err != nil || got != filepath.Join(tmpDir, "github.com", "acme", "api") {
+		t.Errorf("locateRepository() = %q, %v, want the layout directory", got, err)
+	}
+
+	// Clones of other owners fall back to the conventional locations
+	got, err = checker.locateRepository(manifest.Dependent{Repo: "other/api"}, tmpDir)
+	if err != nil || got != filepath.Join(tmpDir, "api") {
+		t.Errorf("locateRepository() = %q, %v, want the workspace/repo fallback", got, err)
+	}
+}
+
 // TestNewDependencyChecker verifies the constructor.
 func TestNewDependencyChecker(t *testing.T) {
 	t.Run("with nil logger", func(t *testing.T) {
@@ -449,6 +478,7 @@ func TestNeedsUpdateIntegration(t *testing.T) {
 		wantErr    bool
 		wantWarn   bool
 		warnMsg    string
+		wantLocal  string
 	}{
 		{
 			name: "repo already up-to-date",
@@ -476,6 +506,20 @@ func TestNeedsUpdateIntegration(t *testing.T) {
 			wantUpdate: true,
 			wantErr:    false,
 		},
+		{
+			name: "repo requires target under an alias",
+			dependent: manifest.Dependent{
+				Repo:   "goliatone/repo-outdated",
+				Module: "github.com/goliatone/repo-outdated",
+			},
+			target: Target{
+				Module:  "github.com/goliatone/errors",
+				Aliases: []string{"github.com/goliatone/go-errors"},
+				Version: "v0.9.0",
+			},
+			wantUpdate: true,
+			wantErr:    false,
+		},
 		{
 			name: "dependency not in go.mod",
 			dependent: manifest.Dependent{
@@ -501,10 +545,23 @@ func TestNeedsUpdateIntegration(t *testing.T) {
 				Module:  "github.com/goliatone/go-errors",
 				Version: "v0.9.0",
 			},
-			wantUpdate: true,
-			wantErr:    false,
-			wantWarn:   true,
-			warnMsg:    "dependency has local replace directive",
+			wantUpdate: false,
+			wantErr:    true,
+			wantLocal:  LocalSourceReplace,
+		},
+		{
+			name: "dependency used from go.work",
+			dependent: manifest.Dependent{
+				Repo:   "goliatone/repo-workspace",
+				Module: "github.com/goliatone/repo-workspace",
+			},
+			target: Target{
+				Module:  "github.com/goliatone/go-errors",
+				Version: "v0.9.0",
+			},
+			wantUpdate: false,
+			wantErr:    true,
+			wantLocal:  LocalSourceWorkUse,
 		},
 		{
 			name: "go.mod not found",
@@ -556,6 +613,16 @@ func TestNeedsUpdateIntegration(t *testing.T) {
 				t.Errorf("NeedsUpdate() = %v, want %v", gotUpdate, tt.wantUpdate)
 			}
 
+			if tt.wantLocal != "" {
+				var local *LocallyManagedError
+				if !errors.As(err, &local) {
+					t.Fatalf("NeedsUpdate() error = %v, want LocallyManagedError", err)
+				}
+				if local.Source != tt.wantLocal || local.Module != tt.target.Module {
+					t.Errorf("LocallyManagedError = %+v, want source %q for %s", local, tt.wantLocal, tt.target.Module)
+				}
+			}
+
 			if tt.wantWarn {
 				if len(logger.warnMsgs) == 0 {
 					t.Errorf("Expected warning message, got none")
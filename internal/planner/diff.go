@@ -0,0 +1,145 @@
+package planner
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Kinds of PlanChange.
+const (
+	PlanChangeAdded   = "added"
+	PlanChangeRemoved = "removed"
+	PlanChangeChanged = "changed"
+)
+
+// PlanChange is one way a plan differs from an earlier one.
+type PlanChange struct {
+	// Kind is PlanChangeAdded, PlanChangeRemoved, or PlanChangeChanged
+	Kind string
+
+	// Item names the work item as "repo (module)". It is empty for changes to
+	// the plan as a whole, such as its target or waves.
+	Item string
+
+	// Field is the changed field, for PlanChangeChanged
+	Field string
+
+	// Before and After are the JSON values of a changed field
+	Before string
+	After  string
+}
+
+func (c PlanChange) String() string {
+	switch c.Kind {
+	case PlanChangeAdded:
+		return "+ " + c.Item
+	case PlanChangeRemoved:
+		return "- " + c.Item
+	}
+	if c.Item == "" {
+		return fmt.Sprintf("~ %s: %s -> %s", c.Field, c.Before, c.After)
+	}
+	return fmt.Sprintf("~ %s: %s %s -> %s", c.Item, c.Field, c.Before, c.After)
+}
+
+// ComparePlans lists how current differs from golden in what it would change:
+// its target, its work items and their settings, and its waves. Planning
+// statistics and explanations vary between runs and are not compared.
+// Changes are sorted by item, with plan-wide changes first.
+func ComparePlans(golden, current *Plan) []PlanChange {
+	var changes []PlanChange
+	changes = append(changes, compareFields("", golden.Target, current.Target)...)
+	changes = append(changes, compareFields("", struct{ Waves []Wave }{golden.Waves}, struct{ Waves []Wave }{current.Waves})...)
+
+	before := itemsByKey(golden.Items)
+	after := itemsByKey(current.Items)
+	keys := make([]string, 0, len(before)+len(after))
+	for key := range before {
+		keys = append(keys, key)
+	}
+	for key := range after {
+		if _, ok := before[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		old, hadOld := before[key]
+		item, hasNew := after[key]
+		switch {
+		case !hasNew:
+			changes = append(changes, PlanChange{Kind: PlanChangeRemoved, Item: key})
+		case !hadOld:
+			changes = append(changes, PlanChange{Kind: PlanChangeAdded, Item: key})
+		default:
+			changes = append(changes, compareFields(key, old, item)...)
+		}
+	}
+	return changes
+}
+
+// itemsByKey indexes items by repository and module, which tell the work items
+// of a plan apart even when one repository holds several dependent modules.
+func itemsByKey(items []WorkItem) map[string]WorkItem {
+	byKey := make(map[string]WorkItem, len(items))
+	for _, item := range items {
+		byKey[fmt.Sprintf("%s (%s)", item.Repo, item.Module)] = item
+	}
+	return byKey
+}
+
+// compareFields reports the top-level JSON fields of a and b whose values differ.
+func compareFields(item string, a, b any) []PlanChange {
+	before, after := jsonFields(a), jsonFields(b)
+	names := make([]string, 0, len(before)+len(after))
+	for name := range before {
+		names = append(names, name)
+	}
+	for name := range after {
+		if _, ok := before[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var changes []PlanChange
+	for _, name := range names {
+		old, value := before[name], after[name]
+		if old == value {
+			continue
+		}
+		if old == "" {
+			old = "null"
+		}
+		if value == "" {
+			value = "null"
+		}
+		changes = append(changes, PlanChange{Kind: PlanChangeChanged, Item: item, Field: name, Before: old, After: value})
+	}
+	return changes
+}
+
+// jsonFields returns the compact JSON of each top-level field of v. Fields
+// omitted from the JSON read as null.
+func jsonFields(v any) map[string]string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil
+	}
+	fields := make(map[string]string, len(raw))
+	for name, value := range raw {
+		text := strings.TrimSpace(string(value))
+		if text == "null" {
+			continue
+		}
+		fields[name] = text
+	}
+	return fields
+}
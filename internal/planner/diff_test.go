@@ -0,0 +1,55 @@
+package planner
+
+import (
+	"testing"
+	"time"
+)
+
+func TestComparePlans(t *testing.T) {
+	golden := &Plan{
+		Target: Target{Module: "example.com/lib", Version: "v1.2.0"},
+		Items: []WorkItem{
+			{Repo: "example/api", Module: "example.com/api", Branch: "main", Labels: []string{"deps"}},
+			{Repo: "example/old", Module: "example.com/old", Branch: "main"},
+		},
+		Stats: PlanStats{TotalDependents: 2, CheckDuration: time.Second},
+	}
+	current := &Plan{
+		Target: Target{Module: "example.com/lib", Version: "v1.2.0"},
+		Items: []WorkItem{
+			{Repo: "example/api", Module: "example.com/api", Branch: "develop", Labels: []string{"deps"}, Timeout: time.Minute},
+			{Repo: "example/new", Module: "example.com/new", Branch: "main"},
+		},
+		Stats: PlanStats{TotalDependents: 3, CheckDuration: 2 * time.Second},
+	}
+
+	if changes := ComparePlans(golden, golden); len(changes) != 0 {
+		t.Fatalf("expected no changes comparing a plan with itself, got %v", changes)
+	}
+
+	var got []string
+	for _, change := range ComparePlans(golden, current) {
+		got = append(got, change.String())
+	}
+	want := []string{
+		`~ example/api (example.com/api): Branch "main" -> "develop"`,
+		`~ example/api (example.com/api): Timeout 0 -> 60000000000`,
+		`+ example/new (example.com/new)`,
+		`- example/old (example.com/old)`,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ComparePlans() = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("change %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+
+	retarget := *golden
+	retarget.Target.Version = "v1.3.0"
+	changes := ComparePlans(golden, &retarget)
+	if len(changes) != 1 || changes[0].Item != "" || changes[0].Field != "Version" {
+		t.Errorf("expected the target version change, got %v", changes)
+	}
+}
@@ -0,0 +1,115 @@
+package planner
+
+import (
+	"path"
+	"reflect"
+	"strings"
+
+	"github.com/goliatone/cascade/internal/manifest"
+)
+
+// DuplicateDependent records a dependent that several manifest entries list for
+// the same target, such as a central manifest entry and an entry for the old
+// path of a renamed module. The planner merges them into one work item.
+type DuplicateDependent struct {
+	Repo string
+
+	// Entries names the manifest modules listing the dependent, in manifest
+	// order. The first entry's settings win.
+	Entries []string
+
+	// Filled lists the fields taken from a later entry because earlier ones
+	// left them empty.
+	Filled []string `json:"Filled,omitempty"`
+
+	// Conflicts lists the fields later entries set to other values. Those
+	// values were ignored.
+	Conflicts []string `json:"Conflicts,omitempty"`
+}
+
+// mergeDuplicateDependents returns the dependents of modules with repeated
+// dependents merged, in manifest order. Dependents are the same when they name
+// the same repository and module path. Each field keeps the value of the first
+// entry that sets it, and the merges are reported.
+func mergeDuplicateDependents(modules []*manifest.Module) ([]manifest.Dependent, []DuplicateDependent) {
+	var (
+		dependents []manifest.Dependent
+		origins    []string
+		duplicates []DuplicateDependent
+	)
+	index := make(map[string]int)
+	reported := make(map[string]int)
+
+	for _, module := range modules {
+		for _, dep := range module.Dependents {
+			key := dependentKey(dep)
+			i, ok := index[key]
+			if !ok {
+				index[key] = len(dependents)
+				dependents = append(dependents, dep)
+				origins = append(origins, moduleName(module))
+				continue
+			}
+
+			r, ok := reported[key]
+			if !ok {
+				r = len(duplicates)
+				reported[key] = r
+				duplicates = append(duplicates, DuplicateDependent{
+					Repo:    dependents[i].Repo,
+					Entries: []string{origins[i]},
+				})
+			}
+			filled, conflicts := mergeDependentFields(&dependents[i], dep)
+			duplicate := &duplicates[r]
+			duplicate.Entries = append(duplicate.Entries, moduleName(module))
+			duplicate.Filled = appendUnique(duplicate.Filled, filled...)
+			duplicate.Conflicts = appendUnique(duplicate.Conflicts, conflicts...)
+		}
+	}
+	return dependents, duplicates
+}
+
+// dependentKey identifies the repository and module a dependent updates.
+func dependentKey(dep manifest.Dependent) string {
+	return manifest.RepoKey(dep.Repo) + "|" + path.Clean(strings.TrimSpace(dep.ModulePath))
+}
+
+func moduleName(module *manifest.Module) string {
+	if module.Name != "" {
+		return module.Name
+	}
+	return module.Module
+}
+
+// mergeDependentFields fills the fields of dst that are empty with those of
+// src. It returns the YAML names of the fields it filled and of those both set
+// to different values, which keep the value of dst.
+func mergeDependentFields(dst *manifest.Dependent, src manifest.Dependent) (filled, conflicts []string) {
+	dv := reflect.ValueOf(dst).Elem()
+	sv := reflect.ValueOf(src)
+	for i := 0; i < dv.NumField(); i++ {
+		name := yamlFieldName(dv.Type().Field(i))
+		if name == "repo" || name == "module_path" {
+			continue
+		}
+		to, from := dv.Field(i), sv.Field(i)
+		switch {
+		case from.IsZero():
+		case to.IsZero():
+			to.Set(from)
+			filled = append(filled, name)
+		case !reflect.DeepEqual(to.Interface(), from.Interface()):
+			conflicts = append(conflicts, name)
+		}
+	}
+	return filled, conflicts
+}
+
+func yamlFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
@@ -3,6 +3,7 @@ package planner
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
 // NotImplementedError signals unimplemented planner behaviour.
@@ -46,6 +47,56 @@ func (e *PlanningError) Unwrap() error {
 	return e.Err
 }
 
+// LocallyManagedError reports a dependent that builds against a local copy of the
+// target module, through a replace directive or its go.work file. Raising the
+// required version would not change what it builds, so the planner skips it.
+type LocallyManagedError struct {
+	// Dependent is the repository that was checked
+	Dependent string
+
+	// Module is the path the dependent uses for the target module
+	Module string
+
+	// Source names the directive that points at the local copy: "go.mod replace",
+	// "go.work replace", or "go.work use"
+	Source string
+
+	// Dir is the local directory the module resolves to
+	Dir string
+}
+
+func (e *LocallyManagedError) Error() string {
+	return fmt.Sprintf("planner: %s manages %s locally: %s", e.Dependent, e.Module, e.Reason())
+}
+
+// Reason describes the directive in the form shown for skipped dependents.
+func (e *LocallyManagedError) Reason() string {
+	return fmt.Sprintf("managed locally (%s %s => %s)", e.Source, e.Module, e.Dir)
+}
+
+// CycleError reports manifest modules that depend on each other in a loop, which
+// a multi-level cascade cannot order.
+type CycleError struct {
+	// Modules lists the loop, starting and ending with the same module
+	Modules []string
+}
+
+func (e *CycleError) Error() string {
+	return fmt.Sprintf("planner: dependency cycle between modules: %s", strings.Join(e.Modules, " -> "))
+}
+
+// newCycleError builds the loop closed by an edge from the end of path back to
+// module.
+func newCycleError(path []string, module string) *CycleError {
+	for i, p := range path {
+		if p == module {
+			loop := append([]string(nil), path[i:]...)
+			return &CycleError{Modules: append(loop, module)}
+		}
+	}
+	return &CycleError{Modules: []string{module, module}}
+}
+
 // Helper predicates for error detection using errors.As
 
 // IsTargetNotFound returns true if err is a TargetNotFoundError.
@@ -65,3 +116,9 @@ func IsPlanningError(err error) bool {
 	var planning *PlanningError
 	return errors.As(err, &planning)
 }
+
+// IsLocallyManaged returns true if err is a LocallyManagedError.
+func IsLocallyManaged(err error) bool {
+	var local *LocallyManagedError
+	return errors.As(err, &local)
+}
@@ -0,0 +1,138 @@
+package planner
+
+import "time"
+
+// TimingHistory reports how long past work items took, keyed by repository. The
+// state manager implements it when its storage records item durations.
+type TimingHistory interface {
+	LoadRepoTimings(module string) (map[string]time.Duration, error)
+}
+
+// WithTimingHistory enables the runtime estimate in plan stats, based on how long
+// each dependent took in earlier releases of the same module.
+func WithTimingHistory(history TimingHistory) Option {
+	return func(p *planner) {
+		p.history = history
+	}
+}
+
+// WithConcurrency sets the number of work items the executor runs at once, used
+// when estimating wall-clock duration. Values below one are treated as one.
+func WithConcurrency(limit int) Option {
+	return func(p *planner) {
+		p.concurrency = limit
+	}
+}
+
+// Pull request calls the broker makes for every work item: listing open pull
+// requests for the branch, creating or updating one, and applying labels.
+const pullRequestAPICalls = 3
+
+// estimateAPICalls returns the number of GitHub API calls the broker is expected
+// to make for items. Label creation and failure notifications are not included,
+// so the estimate is a lower bound.
+func estimateAPICalls(items []WorkItem) int {
+	calls := 0
+	for _, item := range items {
+		calls += pullRequestAPICalls
+		if len(item.PR.Reviewers) > 0 || len(item.PR.TeamReviewers) > 0 {
+			calls++
+		}
+	}
+	return calls
+}
+
+// estimateDuration simulates the executor scheduler over items and returns the
+// expected wall-clock duration along with the number of items that had their own
+// recorded timing. Items without history are assumed to take the mean of the
+// recorded timings. It returns zero when there is no history at all.
+func estimateDuration(items []WorkItem, timings map[string]time.Duration, limit int) (time.Duration, int) {
+	var total time.Duration
+	known := 0
+	for _, d := range timings {
+		if d > 0 {
+			total += d
+			known++
+		}
+	}
+	if known == 0 || len(items) == 0 {
+		return 0, 0
+	}
+	fallback := total / time.Duration(known)
+
+	durations := make([]time.Duration, len(items))
+	timed := 0
+	for i, item := range items {
+		if d := timings[item.Repo]; d > 0 {
+			durations[i] = d
+			timed++
+		} else {
+			durations[i] = fallback
+		}
+	}
+
+	if limit < 1 {
+		limit = 1
+	}
+
+	type slot struct {
+		index int
+		end   time.Duration
+	}
+	pending := make([]int, len(items))
+	for i := range items {
+		pending[i] = i
+	}
+	busy := make(map[string]bool)
+	var running []slot
+	var now time.Duration
+
+	for len(pending) > 0 || len(running) > 0 {
+		for len(running) < limit {
+			next := nextRunnableItem(items, pending, busy)
+			if next < 0 {
+				break
+			}
+			index := pending[next]
+			pending = append(pending[:next], pending[next+1:]...)
+			for _, key := range items[index].ExclusiveKeys() {
+				busy[key] = true
+			}
+			running = append(running, slot{index: index, end: now + durations[index]})
+		}
+
+		first := 0
+		for i := range running {
+			if running[i].end < running[first].end {
+				first = i
+			}
+		}
+		finished := running[first]
+		running = append(running[:first], running[first+1:]...)
+		now = finished.end
+		for _, key := range items[finished.index].ExclusiveKeys() {
+			delete(busy, key)
+		}
+	}
+
+	return now, timed
+}
+
+// nextRunnableItem mirrors the executor scheduler: the first pending item that
+// shares neither its repository nor its concurrency group with a running item,
+// or -1 when every pending item is waiting.
+func nextRunnableItem(items []WorkItem, pending []int, busy map[string]bool) int {
+	for pos, index := range pending {
+		runnable := true
+		for _, key := range items[index].ExclusiveKeys() {
+			if busy[key] {
+				runnable = false
+				break
+			}
+		}
+		if runnable {
+			return pos
+		}
+	}
+	return -1
+}
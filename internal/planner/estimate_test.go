@@ -0,0 +1,65 @@
+package planner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goliatone/cascade/internal/manifest"
+)
+
+func TestEstimateDuration(t *testing.T) {
+	items := []WorkItem{
+		{Repo: "org/a", ConcurrencyGroup: "prod"},
+		{Repo: "org/b", ConcurrencyGroup: "prod"},
+		{Repo: "org/c"},
+		{Repo: "org/new"},
+	}
+	timings := map[string]time.Duration{
+		"org/a": 10 * time.Minute,
+		"org/b": 10 * time.Minute,
+		"org/c": 4 * time.Minute,
+		"org/z": 2 * time.Minute,
+	}
+
+	tests := []struct {
+		name  string
+		limit int
+		want  time.Duration
+	}{
+		// 10 + 10 + 4 + mean(10, 10, 4, 2) = 24 + 6.5
+		{name: "sequential", limit: 1, want: 30*time.Minute + 30*time.Second},
+		// a and b share a group, so c and new run alongside a
+		{name: "grouped items stay serial", limit: 4, want: 20 * time.Minute},
+		// a and c start; new follows c; b waits for a
+		{name: "limited slots", limit: 2, want: 20 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, timed := estimateDuration(items, timings, tt.limit)
+			if got != tt.want {
+				t.Fatalf("estimate = %s, want %s", got, tt.want)
+			}
+			if timed != 3 {
+				t.Fatalf("timed = %d, want 3", timed)
+			}
+		})
+	}
+
+	if got, timed := estimateDuration(items, nil, 4); got != 0 || timed != 0 {
+		t.Fatalf("expected no estimate without history, got %s (%d timed)", got, timed)
+	}
+}
+
+func TestEstimateAPICalls(t *testing.T) {
+	items := []WorkItem{
+		{Repo: "org/a"},
+		{Repo: "org/b", PR: manifest.PRConfig{Reviewers: []string{"alice"}}},
+		{Repo: "org/c", PR: manifest.PRConfig{TeamReviewers: []string{"platform"}}},
+	}
+	if got := estimateAPICalls(items); got != 11 {
+		t.Fatalf("estimateAPICalls = %d, want 11", got)
+	}
+	if got := estimateAPICalls(nil); got != 0 {
+		t.Fatalf("estimateAPICalls(nil) = %d, want 0", got)
+	}
+}
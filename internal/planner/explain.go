@@ -0,0 +1,161 @@
+package planner
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// Decision values recorded in Explanation.Decision.
+const (
+	// DecisionIncluded marks a dependent that produced a work item.
+	DecisionIncluded = "included"
+
+	// DecisionSkipped marks a dependent that was excluded from the plan.
+	DecisionSkipped = "skipped"
+
+	// DecisionDeferred marks a dependent that produced a work item held back
+	// until the canary dependents of the plan pass their checks.
+	DecisionDeferred = "deferred"
+)
+
+// Check sources recorded by dependency checkers when explain mode is enabled.
+const (
+	checkSourceWorkspace   = "workspace go.mod"
+	checkSourceCache       = "remote cache"
+	checkSourceShallowCopy = "shallow clone"
+)
+
+// Explanation records why a dependent was included in or skipped from a plan.
+type Explanation struct {
+	// Repo is the dependent repository the decision applies to
+	Repo string
+
+	// Decision is DecisionIncluded, DecisionSkipped, or DecisionDeferred
+	Decision string
+
+	// Reason is a human readable justification for the decision
+	Reason string
+
+	// Checker names the dependency checker that answered (local or remote)
+	Checker string `json:"Checker,omitempty"`
+
+	// Source describes where the checker read the dependency version from
+	Source string `json:"Source,omitempty"`
+
+	// CurrentVersion is the version the dependent currently requires, when known
+	CurrentVersion string `json:"CurrentVersion,omitempty"`
+
+	// Provenance records which configuration layer supplied each merged field of an
+	// included dependent
+	Provenance Provenance `json:"Provenance,omitempty"`
+}
+
+type explainKey struct{}
+
+type explainEnabled struct{}
+
+type traceKey struct{}
+
+// checkTrace collects the details reported by the checker that answered for a dependent.
+// Wrapping checkers may call several inner checkers; the last record wins so the
+// trace reflects the checker whose answer was returned.
+type checkTrace struct {
+	mu             sync.Mutex
+	checker        string
+	source         string
+	currentVersion string
+	reason         string
+}
+
+// WithExplain returns a context that instructs Plan to record an Explanation for
+// every dependent considered.
+func WithExplain(ctx context.Context) context.Context {
+	return context.WithValue(ctx, explainKey{}, explainEnabled{})
+}
+
+// ExplainEnabled reports whether the context requests plan explanations.
+func ExplainEnabled(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	_, ok := ctx.Value(explainKey{}).(explainEnabled)
+	return ok
+}
+
+func withCheckTrace(ctx context.Context, trace *checkTrace) context.Context {
+	return context.WithValue(ctx, traceKey{}, trace)
+}
+
+// recordCheck stores checker details on the trace attached to ctx, if any.
+func recordCheck(ctx context.Context, checker, source, currentVersion, reason string) {
+	if ctx == nil {
+		return
+	}
+	trace, ok := ctx.Value(traceKey{}).(*checkTrace)
+	if !ok || trace == nil {
+		return
+	}
+
+	trace.mu.Lock()
+	defer trace.mu.Unlock()
+	trace.checker = checker
+	trace.source = source
+	trace.currentVersion = currentVersion
+	trace.reason = reason
+}
+
+// deferBehindCanaries marks the included explanations of non-canary items as
+// deferred when the plan has canaries, which releases update first.
+func deferBehindCanaries(explanations []Explanation, items []WorkItem, included map[int]int) {
+	var canaries []string
+	for _, item := range items {
+		if item.Canary {
+			canaries = append(canaries, item.Repo)
+		}
+	}
+	if len(canaries) == 0 {
+		return
+	}
+	wait := "; waits for canaries " + strings.Join(canaries, ", ") + " to pass their checks"
+	for index, itemIndex := range included {
+		if items[itemIndex].Canary {
+			continue
+		}
+		explanations[index].Decision = DecisionDeferred
+		explanations[index].Reason += wait
+	}
+}
+
+// versionReason describes the outcome of a version comparison.
+func versionReason(needsUpdate bool, current, target string) string {
+	if needsUpdate {
+		return "requires " + current + " -> " + target
+	}
+	return "up-to-date at " + current
+}
+
+func (t *checkTrace) setReason(reason string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.reason = reason
+}
+
+func (t *checkTrace) explanation(repo, decision, fallbackReason string) Explanation {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	reason := t.reason
+	if reason == "" {
+		reason = fallbackReason
+	}
+
+	return Explanation{
+		Repo:           repo,
+		Decision:       decision,
+		Reason:         reason,
+		Checker:        t.checker,
+		Source:         t.source,
+		CurrentVersion: t.currentVersion,
+	}
+}
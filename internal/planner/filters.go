@@ -48,7 +48,8 @@ func SortDependents(dependents []manifest.Dependent) []manifest.Dependent {
 
 // SelectCanaries returns a new slice containing canary dependents.
 // Currently passes through all dependents unchanged, but provides
-// a placeholder for future canary selection logic.
+// a placeholder for future canary selection logic. Canaries are planned
+// like any other dependent; releases order them with SplitCanaries.
 func SelectCanaries(dependents []manifest.Dependent) []manifest.Dependent {
 	if len(dependents) == 0 {
 		return nil
@@ -61,3 +62,16 @@ func SelectCanaries(dependents []manifest.Dependent) []manifest.Dependent {
 	copy(result, dependents)
 	return result
 }
+
+// SplitCanaries separates the work items of canary dependents from the rest,
+// keeping the plan order within each group. The input slice is not modified.
+func SplitCanaries(items []WorkItem) (canaries, rest []WorkItem) {
+	for _, item := range items {
+		if item.Canary {
+			canaries = append(canaries, item)
+		} else {
+			rest = append(rest, item)
+		}
+	}
+	return canaries, rest
+}
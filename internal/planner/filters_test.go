@@ -261,3 +261,24 @@ func TestSelectCanaries(t *testing.T) {
 		})
 	}
 }
+
+func TestSplitCanaries(t *testing.T) {
+	items := []WorkItem{
+		{Repo: "org/repo1"},
+		{Repo: "org/repo2", Canary: true},
+		{Repo: "org/repo3"},
+		{Repo: "org/repo4", Canary: true},
+	}
+
+	canaries, rest := SplitCanaries(items)
+	if len(canaries) != 2 || canaries[0].Repo != "org/repo2" || canaries[1].Repo != "org/repo4" {
+		t.Errorf("canaries = %+v, want org/repo2 and org/repo4 in plan order", canaries)
+	}
+	if len(rest) != 2 || rest[0].Repo != "org/repo1" || rest[1].Repo != "org/repo3" {
+		t.Errorf("rest = %+v, want org/repo1 and org/repo3 in plan order", rest)
+	}
+
+	if canaries, rest := SplitCanaries(nil); canaries != nil || rest != nil {
+		t.Errorf("SplitCanaries(nil) = %v, %v, want nil", canaries, rest)
+	}
+}
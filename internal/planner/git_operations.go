@@ -3,6 +3,7 @@ package planner
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
@@ -15,33 +16,55 @@ import (
 	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
 	"github.com/goliatone/cascade/internal/manifest"
 	"github.com/goliatone/cascade/pkg/gitutil"
+	"github.com/goliatone/cascade/pkg/repourl"
+	"github.com/goliatone/cascade/pkg/util/modpath"
 )
 
 // gitOperations defines interface for git operations (for testability).
 type gitOperations interface {
-	parseCloneURL(dependent manifest.Dependent) (string, error)
+	parseCloneURL(ctx context.Context, dependent manifest.Dependent) (string, error)
 	fetchGoMod(ctx context.Context, cloneURL, ref string) (string, error)
 }
 
+// credentialedGitOperations is implemented by gitOperations that can clone
+// with a dependent's credentials, for private module hosts that the GitHub
+// token does not reach.
+type credentialedGitOperations interface {
+	fetchGoModWithCredentials(ctx context.Context, cloneURL, ref string, credentials []manifest.Credential) (string, error)
+}
+
 // gitOperationsImpl is the real implementation of git operations.
 type gitOperationsImpl struct {
 	timeout time.Duration
+	vanity  *modpath.VanityResolver
+	urls    *repourl.Resolver
 }
 
-// newGitOperations creates a new git operations implementation.
-func newGitOperations(timeout time.Duration) gitOperations {
+// newGitOperations creates a new git operations implementation. Clone URLs are
+// built with urls, or with the built-in host defaults when it is nil.
+func newGitOperations(timeout time.Duration, urls *repourl.Resolver) gitOperations {
 	if timeout == 0 {
 		timeout = 30 * time.Second // Default timeout
 	}
-	return &gitOperationsImpl{timeout: timeout}
+	if urls == nil {
+		urls = repourl.New()
+	}
+	return &gitOperationsImpl{timeout: timeout, vanity: modpath.NewVanityResolver(nil), urls: urls}
 }
 
 // parseCloneURL converts a Dependent to a git clone URL.
-// It handles GitHub/GitLab/Bitbucket formats and supports SSH and HTTPS URLs.
-func (g *gitOperationsImpl) parseCloneURL(dependent manifest.Dependent) (string, error) {
-	// If CloneURL is explicitly set, use it
+// It handles GitHub/GitLab/Bitbucket formats, configured git hosts, and SSH and
+// HTTPS URLs. Custom import paths (e.g., go.uber.org/zap) on hosts that are not
+// configured are resolved through their go-import meta tag.
+func (g *gitOperationsImpl) parseCloneURL(ctx context.Context, dependent manifest.Dependent) (string, error) {
+	urls := g.urls
+	if urls == nil {
+		urls = repourl.New()
+	}
+
+	// If CloneURL is explicitly set, use it (after any configured rewrites)
 	if dependent.CloneURL != "" {
-		return dependent.CloneURL, nil
+		return urls.CloneURL(dependent.CloneURL)
 	}
 
 	// If Repo is empty, we can't construct a URL
@@ -50,23 +73,30 @@ func (g *gitOperationsImpl) parseCloneURL(dependent manifest.Dependent) (string,
 	}
 
 	repo := dependent.Repo
+	host := strings.SplitN(repo, "/", 2)[0]
 
-	// If it already looks like a full URL, use as-is
-	if strings.HasPrefix(repo, "https://") || strings.HasPrefix(repo, "http://") || strings.HasPrefix(repo, "git@") {
-		return repo, nil
-	}
-
-	// Use gitutil to build the clone URL for shorthand formats
-	cloneURL, err := gitutil.BuildCloneURL(repo, gitutil.ProtocolHTTPS)
-	if err != nil {
-		return "", err
+	if modpath.IsVanityPath(repo) && !urls.HasHost(host) && g.vanity != nil {
+		root, err := g.vanity.Resolve(ctx, repo)
+		if err == nil {
+			return urls.CloneURL(root.RepoURL)
+		}
+		// host/owner/repo on a self-hosted server can still be cloned directly
+		if strings.Count(repo, "/") < 2 {
+			return "", fmt.Errorf("resolve vanity import path: %w", err)
+		}
 	}
 
-	return cloneURL, nil
+	return urls.CloneURLWithProtocol(repo, repourl.Protocol(dependent.CloneProtocol))
 }
 
 // fetchGoMod performs a shallow clone and retrieves the go.mod file contents.
 func (g *gitOperationsImpl) fetchGoMod(ctx context.Context, cloneURL, ref string) (string, error) {
+	return g.fetchGoModWithCredentials(ctx, cloneURL, ref, nil)
+}
+
+// fetchGoModWithCredentials is fetchGoMod authenticating with the credential
+// for the clone URL's host, when credentials hold one.
+func (g *gitOperationsImpl) fetchGoModWithCredentials(ctx context.Context, cloneURL, ref string, credentials []manifest.Credential) (string, error) {
 	// Create context with timeout
 	ctx, cancel := context.WithTimeout(ctx, g.timeout)
 	defer cancel()
@@ -78,8 +108,13 @@ func (g *gitOperationsImpl) fetchGoMod(ctx context.Context, cloneURL, ref string
 	}
 	defer os.RemoveAll(tmpDir) // Clean up on all paths
 
+	auth, err := g.credentialAuth(cloneURL, credentials)
+	if err != nil {
+		return "", fmt.Errorf("shallow clone: setup auth: %w", err)
+	}
+
 	// Perform shallow clone
-	if err := g.shallowClone(ctx, cloneURL, ref, tmpDir); err != nil {
+	if err := g.clone(ctx, cloneURL, ref, tmpDir, auth); err != nil {
 		return "", fmt.Errorf("shallow clone: %w", err)
 	}
 
@@ -95,6 +130,16 @@ func (g *gitOperationsImpl) fetchGoMod(ctx context.Context, cloneURL, ref string
 
 // shallowClone performs a shallow git clone (depth=1) of the specified repository.
 func (g *gitOperationsImpl) shallowClone(ctx context.Context, cloneURL, ref, destPath string) error {
+	// Get authentication method
+	auth, err := g.authMethod(cloneURL)
+	if err != nil {
+		return fmt.Errorf("setup auth: %w", err)
+	}
+	return g.clone(ctx, cloneURL, ref, destPath, auth)
+}
+
+// clone performs a shallow clone of ref with auth.
+func (g *gitOperationsImpl) clone(ctx context.Context, cloneURL, ref, destPath string, auth transport.AuthMethod) error {
 	// Default to main branch if no ref specified
 	if ref == "" {
 		ref = "refs/heads/main"
@@ -106,12 +151,6 @@ func (g *gitOperationsImpl) shallowClone(ctx context.Context, cloneURL, ref, des
 		ref = "refs/heads/" + ref
 	}
 
-	// Get authentication method
-	auth, err := g.authMethod(cloneURL)
-	if err != nil {
-		return fmt.Errorf("setup auth: %w", err)
-	}
-
 	// Configure clone options for shallow clone
 	opts := &git.CloneOptions{
 		URL:           cloneURL,
@@ -124,17 +163,42 @@ func (g *gitOperationsImpl) shallowClone(ctx context.Context, cloneURL, ref, des
 	}
 
 	// Perform clone
-	_, err = git.PlainCloneContext(ctx, destPath, false, opts)
-	if err != nil {
+	if _, err := git.PlainCloneContext(ctx, destPath, false, opts); err != nil {
 		return fmt.Errorf("git clone: %w", err)
 	}
 
 	return nil
 }
 
+// credentialAuth returns basic auth with the credential whose machine is the
+// host of an HTTP(S) cloneURL, with the password read from its environment
+// variable. Without a matching credential it returns authMethod's choice.
+func (g *gitOperationsImpl) credentialAuth(cloneURL string, credentials []manifest.Credential) (transport.AuthMethod, error) {
+	parsed, err := url.Parse(cloneURL)
+	if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") {
+		return g.authMethod(cloneURL)
+	}
+	for _, cred := range credentials {
+		if !strings.EqualFold(strings.TrimSpace(cred.Machine), parsed.Hostname()) {
+			continue
+		}
+		password := os.Getenv(cred.PasswordEnv)
+		if password == "" {
+			return nil, fmt.Errorf("credential for %s: environment variable %s is not set", cred.Machine, cred.PasswordEnv)
+		}
+		return &http.BasicAuth{Username: cred.Login, Password: password}, nil
+	}
+	return g.authMethod(cloneURL)
+}
+
 // authMethod returns the appropriate authentication method for the clone URL.
-// It tries GitHub token first, then SSH keys, and falls back to no auth for public repos.
+// SSH URLs authenticate with SSH keys; other URLs use the GitHub token when one
+// is set and fall back to no auth for public repos.
 func (g *gitOperationsImpl) authMethod(cloneURL string) (transport.AuthMethod, error) {
+	if gitutil.IsSSHURL(cloneURL) {
+		return sshAuth(cloneURL)
+	}
+
 	// Try GitHub token first (most common in CI/CD)
 	if token := gitutil.GetGitHubToken(); token != "" {
 		return &http.BasicAuth{
@@ -143,22 +207,34 @@ func (g *gitOperationsImpl) authMethod(cloneURL string) (transport.AuthMethod, e
 		}, nil
 	}
 
-	// Try SSH key for git@ URLs
-	if strings.HasPrefix(cloneURL, "git@") {
-		sshKeyPath, err := gitutil.GetSSHKeyPathOrError()
-		if err != nil {
-			return nil, err
-		}
+	// Public repository - no auth needed
+	return nil, nil
+}
 
-		// Create SSH auth method
-		auth, err := ssh.NewPublicKeysFromFile("git", sshKeyPath, "")
+// sshAuth authenticates as the user of an SSH clone URL, or git, with the key
+// at SSH_KEY_PATH, then the keys in ssh-agent, then ~/.ssh/id_rsa. Host keys
+// are checked against SSH_KNOWN_HOSTS, or ~/.ssh/known_hosts.
+func sshAuth(cloneURL string) (transport.AuthMethod, error) {
+	user := "git"
+	if endpoint, err := transport.NewEndpoint(cloneURL); err == nil && endpoint.User != "" {
+		user = endpoint.User
+	}
+
+	if os.Getenv(gitutil.EnvSSHKeyPath) == "" && os.Getenv(gitutil.EnvSSHAuthSock) != "" {
+		auth, err := ssh.NewSSHAgentAuth(user)
 		if err != nil {
-			return nil, fmt.Errorf("create SSH auth: %w", err)
+			return nil, fmt.Errorf("create SSH agent auth: %w", err)
 		}
-
 		return auth, nil
 	}
 
-	// Public repository - no auth needed
-	return nil, nil
+	sshKeyPath, err := gitutil.GetSSHKeyPathOrError()
+	if err != nil {
+		return nil, err
+	}
+	auth, err := ssh.NewPublicKeysFromFile(user, sshKeyPath, "")
+	if err != nil {
+		return nil, fmt.Errorf("create SSH auth: %w", err)
+	}
+	return auth, nil
 }
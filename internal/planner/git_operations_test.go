@@ -3,16 +3,22 @@ package planner
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
 	"github.com/goliatone/cascade/internal/manifest"
 	"github.com/goliatone/cascade/pkg/gitutil"
+	"github.com/goliatone/cascade/pkg/repourl"
+	"github.com/goliatone/cascade/pkg/util/modpath"
 )
 
 func TestParseCloneURL(t *testing.T) {
-	g := newGitOperations(30 * time.Second)
+	g := newGitOperations(30*time.Second, nil)
 	impl := g.(*gitOperationsImpl)
 
 	tests := []struct {
@@ -82,7 +88,7 @@ func TestParseCloneURL(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := impl.parseCloneURL(tt.dependent)
+			got, err := impl.parseCloneURL(context.Background(), tt.dependent)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("parseCloneURL() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -94,6 +100,70 @@ func TestParseCloneURL(t *testing.T) {
 	}
 }
 
+func TestParseCloneURL_VanityImportPath(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/zap" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `<html><head><meta name="go-import" content="%s/zap git https://github.com/uber-go/zap"></head></html>`, r.Host)
+	}))
+	defer server.Close()
+
+	impl := &gitOperationsImpl{timeout: time.Second, vanity: modpath.NewVanityResolver(server.Client())}
+	host := strings.TrimPrefix(server.URL, "https://")
+
+	got, err := impl.parseCloneURL(context.Background(), manifest.Dependent{Repo: host + "/zap"})
+	if err != nil {
+		t.Fatalf("parseCloneURL() error = %v", err)
+	}
+	if got != "https://github.com/uber-go/zap" {
+		t.Errorf("parseCloneURL() = %s, want https://github.com/uber-go/zap", got)
+	}
+
+	// Unresolvable host/owner/repo paths are cloned from the host directly
+	got, err = impl.parseCloneURL(context.Background(), manifest.Dependent{Repo: host + "/team/app"})
+	if err != nil {
+		t.Fatalf("parseCloneURL() fallback error = %v", err)
+	}
+	if got != "https://"+host+"/team/app.git" {
+		t.Errorf("parseCloneURL() fallback = %s", got)
+	}
+
+	if _, err := impl.parseCloneURL(context.Background(), manifest.Dependent{Repo: host + "/missing"}); err == nil {
+		t.Error("expected error for unresolvable vanity path")
+	}
+}
+
+func TestParseCloneURL_ConfiguredHosts(t *testing.T) {
+	urls := repourl.New(
+		repourl.WithDefaultHost("github.example.com"),
+		repourl.WithHosts(repourl.Host{Name: "gitlab.example.com", Protocol: repourl.ProtocolSSH, SSHPort: 2222, Subgroups: true}),
+		repourl.WithRewrites(repourl.Rewrite{Base: "https://mirror.example.com/", InsteadOf: "https://github.example.com/"}),
+	)
+	impl := newGitOperations(time.Second, urls).(*gitOperationsImpl)
+
+	tests := []struct {
+		dependent manifest.Dependent
+		want      string
+	}{
+		{dependent: manifest.Dependent{Repo: "team/app"}, want: "https://mirror.example.com/team/app.git"},
+		{dependent: manifest.Dependent{Repo: "gitlab.example.com/platform/tools/lint"}, want: "ssh://git@gitlab.example.com:2222/platform/tools/lint.git"},
+		{dependent: manifest.Dependent{Repo: "team/app", CloneURL: "https://github.example.com/team/app.git"}, want: "https://mirror.example.com/team/app.git"},
+		{dependent: manifest.Dependent{Repo: "team/app", CloneProtocol: manifest.CloneProtocolSSH}, want: "git@github.example.com:team/app.git"},
+		{dependent: manifest.Dependent{Repo: "gitlab.example.com/platform/lint", CloneProtocol: manifest.CloneProtocolHTTPS}, want: "https://gitlab.example.com/platform/lint.git"},
+	}
+	for _, tt := range tests {
+		got, err := impl.parseCloneURL(context.Background(), tt.dependent)
+		if err != nil {
+			t.Fatalf("parseCloneURL(%+v) error = %v", tt.dependent, err)
+		}
+		if got != tt.want {
+			t.Errorf("parseCloneURL(%+v) = %s, want %s", tt.dependent, got, tt.want)
+		}
+	}
+}
+
 func TestGetGitHubToken(t *testing.T) {
 	// This functionality is now tested in pkg/gitutil/auth_test.go
 	// Keep this test as a simple integration check
@@ -140,7 +210,7 @@ func TestGetGitHubToken(t *testing.T) {
 }
 
 func TestAuthMethod(t *testing.T) {
-	g := newGitOperations(30 * time.Second)
+	g := newGitOperations(30*time.Second, nil)
 	impl := g.(*gitOperationsImpl)
 
 	tests := []struct {
@@ -200,13 +270,80 @@ func TestAuthMethod(t *testing.T) {
 	}
 }
 
+func TestCredentialAuth(t *testing.T) {
+	impl := newGitOperations(30*time.Second, nil).(*gitOperationsImpl)
+	t.Setenv("GITHUB_TOKEN", "github-token")
+	t.Setenv("CORP_GIT_TOKEN", "corp-token")
+	credentials := []manifest.Credential{{Machine: "git.corp.example.com", Login: "ci-bot", PasswordEnv: "CORP_GIT_TOKEN"}}
+
+	auth, err := impl.credentialAuth("https://git.corp.example.com/team/app.git", credentials)
+	if err != nil {
+		t.Fatalf("credentialAuth() error = %v", err)
+	}
+	if basic, ok := auth.(*githttp.BasicAuth); !ok || basic.Username != "ci-bot" || basic.Password != "corp-token" {
+		t.Errorf("credentialAuth() = %#v, want the credential for the host", auth)
+	}
+
+	auth, err = impl.credentialAuth("https://github.com/acme/app.git", credentials)
+	if err != nil {
+		t.Fatalf("credentialAuth() error = %v", err)
+	}
+	if basic, ok := auth.(*githttp.BasicAuth); !ok || basic.Password != "github-token" {
+		t.Errorf("credentialAuth() = %#v, want the GitHub token for other hosts", auth)
+	}
+
+	t.Setenv("CORP_GIT_TOKEN", "")
+	if _, err := impl.credentialAuth("https://git.corp.example.com/team/app.git", credentials); err == nil || !strings.Contains(err.Error(), "CORP_GIT_TOKEN is not set") {
+		t.Errorf("credentialAuth() error = %v, want missing password error", err)
+	}
+}
+
+func TestRemoteCheckerUsesDependentCredentials(t *testing.T) {
+	ops := &credentialedMockGitOperations{}
+	checker := &remoteDependencyChecker{cache: newDependencyCache(time.Minute), gitOps: ops}
+	dependent := manifest.Dependent{
+		Repo:        "git.corp.example.com/team/app",
+		CloneURL:    "https://git.corp.example.com/team/app.git",
+		Credentials: []manifest.Credential{{Machine: "git.corp.example.com", Login: "ci-bot", PasswordEnv: "CORP_GIT_TOKEN"}},
+	}
+
+	needsUpdate, err := checker.NeedsUpdate(context.Background(), dependent, Target{Module: "git.corp.example.com/team/lib", Version: "v1.2.0"}, "")
+	if err != nil {
+		t.Fatalf("NeedsUpdate() error = %v", err)
+	}
+	if !needsUpdate {
+		t.Error("NeedsUpdate() = false, want true")
+	}
+	if len(ops.credentials) != 1 || ops.credentials[0].Machine != "git.corp.example.com" {
+		t.Errorf("go.mod was fetched with credentials %v, want the dependent's", ops.credentials)
+	}
+}
+
+// credentialedMockGitOperations records the credentials go.mod is fetched with.
+type credentialedMockGitOperations struct {
+	credentials []manifest.Credential
+}
+
+func (m *credentialedMockGitOperations) parseCloneURL(_ context.Context, dependent manifest.Dependent) (string, error) {
+	return dependent.CloneURL, nil
+}
+
+func (m *credentialedMockGitOperations) fetchGoMod(ctx context.Context, cloneURL, ref string) (string, error) {
+	return m.fetchGoModWithCredentials(ctx, cloneURL, ref, nil)
+}
+
+func (m *credentialedMockGitOperations) fetchGoModWithCredentials(_ context.Context, _, _ string, credentials []manifest.Credential) (string, error) {
+	m.credentials = credentials
+	return "module git.corp.example.com/team/app\n\nrequire git.corp.example.com/team/lib v1.1.0\n", nil
+}
+
 // mockGitOperations is a mock implementation for testing.
 type mockGitOperations struct {
 	parseCloneURLFunc func(dependent manifest.Dependent) (string, error)
 	fetchGoModFunc    func(ctx context.Context, cloneURL, ref string) (string, error)
 }
 
-func (m *mockGitOperations) parseCloneURL(dependent manifest.Dependent) (string, error) {
+func (m *mockGitOperations) parseCloneURL(_ context.Context, dependent manifest.Dependent) (string, error) {
 	if m.parseCloneURLFunc != nil {
 		return m.parseCloneURLFunc(dependent)
 	}
@@ -225,7 +362,7 @@ func TestFetchGoModIntegration(t *testing.T) {
 		t.Skip("skipping integration test in short mode")
 	}
 
-	g := newGitOperations(60 * time.Second) // Longer timeout for network operations
+	g := newGitOperations(60*time.Second, nil) // Longer timeout for network operations
 	impl := g.(*gitOperationsImpl)
 
 	tests := []struct {
@@ -295,7 +432,7 @@ func TestShallowCloneTimeout(t *testing.T) {
 	}
 
 	// Create git ops with very short timeout
-	g := newGitOperations(1 * time.Millisecond)
+	g := newGitOperations(1*time.Millisecond, nil)
 	impl := g.(*gitOperationsImpl)
 
 	tmpDir, err := os.MkdirTemp("", "cascade-timeout-test-*")
@@ -317,7 +454,7 @@ func TestFetchGoModCleansUpTempDir(t *testing.T) {
 		t.Skip("skipping integration test in short mode")
 	}
 
-	g := newGitOperations(60 * time.Second)
+	g := newGitOperations(60*time.Second, nil)
 	impl := g.(*gitOperationsImpl)
 
 	// Track temp directories created
@@ -402,7 +539,7 @@ func TestFetchGoModWithAuthentication(t *testing.T) {
 		t.Skip("skipping authentication test: no GitHub token found in environment")
 	}
 
-	g := newGitOperations(60 * time.Second)
+	g := newGitOperations(60*time.Second, nil)
 	impl := g.(*gitOperationsImpl)
 
 	tests := []struct {
@@ -452,7 +589,7 @@ func TestFetchGoModWithAuthentication(t *testing.T) {
 }
 
 func TestAuthMethodSelection(t *testing.T) {
-	g := newGitOperations(30 * time.Second)
+	g := newGitOperations(30*time.Second, nil)
 	impl := g.(*gitOperationsImpl)
 
 	tests := []struct {
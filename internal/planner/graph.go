@@ -0,0 +1,228 @@
+package planner
+
+import (
+	"context"
+	"sort"
+
+	"github.com/goliatone/cascade/internal/manifest"
+)
+
+// Wave is one level of a multi-level cascade. Wave 0 updates the dependents of
+// the released module. A later wave starts once the updates of its modules
+// from earlier waves are merged and tagged, and updates their dependents to
+// those new versions.
+type Wave struct {
+	Level int
+
+	// Modules are the modules whose new versions the wave cascades
+	Modules []string
+
+	// Repos are the dependent repositories the wave may update, sorted
+	Repos []string
+}
+
+// Graph is the dependency DAG of the manifest modules reachable from a released
+// module. A dependent whose own module is a manifest module with dependents of
+// its own is a node: once its update is merged and tagged, the new version
+// cascades further. Each node sits at the length of the longest path to it from
+// the root, so a module is only released after every module it depends on.
+type Graph struct {
+	root       string
+	modules    map[string]*manifest.Module
+	dependents map[string][]manifest.Dependent
+	edges      map[string][]string
+	levels     map[string]int
+}
+
+// BuildGraph builds the graph of modules reachable from root in m. Aliases are
+// resolved, so every node is keyed by its canonical module path. It returns a
+// *CycleError when modules depend on each other in a loop.
+func BuildGraph(m *manifest.Manifest, root string) (*Graph, error) {
+	module, err := manifest.FindModuleByPath(m, root)
+	if err != nil {
+		return nil, &TargetNotFoundError{ModuleName: root}
+	}
+
+	g := &Graph{
+		modules:    make(map[string]*manifest.Module),
+		dependents: make(map[string][]manifest.Dependent),
+		edges:      make(map[string][]string),
+		levels:     make(map[string]int),
+	}
+	g.root = g.add(m, module)
+
+	// Depth-first search from the root, recording a post-order for the
+	// longest-path levels and reporting back edges as cycles
+	const (
+		visiting = 1
+		done     = 2
+	)
+	marks := make(map[string]int)
+	var (
+		order []string
+		path  []string
+		visit func(node string) error
+	)
+	visit = func(node string) error {
+		marks[node] = visiting
+		path = append(path, node)
+		for _, next := range g.downstream(m, node) {
+			switch marks[next] {
+			case visiting:
+				return newCycleError(path, next)
+			case done:
+				continue
+			}
+			if err := visit(next); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		marks[node] = done
+		order = append(order, node)
+		return nil
+	}
+	if err := visit(g.root); err != nil {
+		return nil, err
+	}
+
+	for _, node := range order {
+		g.levels[node] = 0
+	}
+	for i := len(order) - 1; i >= 0; i-- {
+		node := order[i]
+		for _, next := range g.edges[node] {
+			if level := g.levels[node] + 1; level > g.levels[next] {
+				g.levels[next] = level
+			}
+		}
+	}
+	return g, nil
+}
+
+// add records module and its dependents under its canonical path.
+func (g *Graph) add(m *manifest.Manifest, module *manifest.Module) string {
+	target, dependents, _ := resolveTargetAliases(m, module, Target{Module: module.Module})
+	if _, ok := g.modules[target.Module]; !ok {
+		canonical, err := manifest.FindModuleByPath(m, target.Module)
+		if err != nil {
+			canonical = module
+		}
+		g.modules[target.Module] = canonical
+		g.dependents[target.Module] = SortDependents(FilterSkipped(dependents))
+	}
+	return target.Module
+}
+
+// downstream finds the manifest modules among the dependents of node, records
+// the edges to them, and returns them in order.
+func (g *Graph) downstream(m *manifest.Manifest, node string) []string {
+	if next, ok := g.edges[node]; ok {
+		return next
+	}
+	seen := make(map[string]bool)
+	next := []string{}
+	for _, dep := range g.dependents[node] {
+		if dep.Module == "" {
+			continue
+		}
+		module, err := manifest.FindModuleByPath(m, dep.Module)
+		if err != nil {
+			continue
+		}
+		path := g.add(m, module)
+		if path == node || seen[path] || len(g.dependents[path]) == 0 {
+			continue
+		}
+		seen[path] = true
+		next = append(next, path)
+	}
+	sort.Strings(next)
+	g.edges[node] = next
+	return next
+}
+
+// Root returns the canonical path of the released module.
+func (g *Graph) Root() string {
+	return g.root
+}
+
+// Level returns the wave in which the dependents of module are updated.
+func (g *Graph) Level(module string) (int, bool) {
+	level, ok := g.levels[g.canonical(module)]
+	return level, ok
+}
+
+// Module returns the manifest entry of module, or nil when it is not a node.
+func (g *Graph) Module(module string) *manifest.Module {
+	key := g.canonical(module)
+	if _, ok := g.levels[key]; !ok {
+		return nil
+	}
+	return g.modules[key]
+}
+
+// Downstream returns the modules that depend directly on module.
+func (g *Graph) Downstream(module string) []string {
+	return append([]string(nil), g.edges[g.canonical(module)]...)
+}
+
+// Waves returns the levels of the graph in order, starting with the root.
+func (g *Graph) Waves() []Wave {
+	depth := 0
+	for _, level := range g.levels {
+		depth = max(depth, level)
+	}
+
+	waves := make([]Wave, depth+1)
+	for i := range waves {
+		waves[i].Level = i
+	}
+	for module, level := range g.levels {
+		waves[level].Modules = append(waves[level].Modules, module)
+	}
+	for i := range waves {
+		sort.Strings(waves[i].Modules)
+		seen := make(map[string]bool)
+		for _, module := range waves[i].Modules {
+			for _, dep := range g.dependents[module] {
+				if !seen[dep.Repo] {
+					seen[dep.Repo] = true
+					waves[i].Repos = append(waves[i].Repos, dep.Repo)
+				}
+			}
+		}
+		sort.Strings(waves[i].Repos)
+	}
+	return waves
+}
+
+// canonical returns the node key of module, which may be an alias.
+func (g *Graph) canonical(module string) string {
+	if _, ok := g.modules[module]; ok {
+		return module
+	}
+	for path, m := range g.modules {
+		if m.HasPath(module) {
+			return path
+		}
+	}
+	return module
+}
+
+type wavesKey struct{}
+
+// WithWaves returns a context that instructs Plan to build the module graph of
+// the target and describe its waves in Plan.Waves.
+func WithWaves(ctx context.Context) context.Context {
+	return context.WithValue(ctx, wavesKey{}, true)
+}
+
+// WavesEnabled reports whether the context requests plan waves.
+func WavesEnabled(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	enabled, _ := ctx.Value(wavesKey{}).(bool)
+	return enabled
+}
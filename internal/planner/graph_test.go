@@ -0,0 +1,150 @@
+package planner_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/goliatone/cascade/internal/manifest"
+	"github.com/goliatone/cascade/internal/planner"
+)
+
+// layeredManifest describes lib <- core <- api, with app depending on both lib
+// and core, and worker on api.
+func layeredManifest() *manifest.Manifest {
+	return &manifest.Manifest{
+		ManifestVersion: 1,
+		Defaults:        manifest.Defaults{Branch: "main"},
+		Modules: []manifest.Module{
+			{
+				Name:   "lib",
+				Module: "github.com/org/lib",
+				Repo:   "org/lib",
+				Dependents: []manifest.Dependent{
+					{Repo: "org/core", Module: "github.com/org/core", ModulePath: "."},
+					{Repo: "org/app", Module: "github.com/org/app", ModulePath: "."},
+					{Repo: "org/legacy", Module: "github.com/org/legacy", ModulePath: ".", Skip: true},
+				},
+			},
+			{
+				Name:   "core",
+				Module: "github.com/org/core",
+				Repo:   "org/core",
+				Dependents: []manifest.Dependent{
+					{Repo: "org/api", Module: "go.org.dev/api", ModulePath: "."},
+					{Repo: "org/app", Module: "github.com/org/app", ModulePath: "."},
+				},
+			},
+			{
+				Name:    "api",
+				Module:  "github.com/org/api",
+				Aliases: []string{"go.org.dev/api"},
+				Repo:    "org/api",
+				Dependents: []manifest.Dependent{
+					{Repo: "org/worker", Module: "github.com/org/worker", ModulePath: "."},
+				},
+			},
+			{
+				// app is a manifest module without dependents, so it ends the cascade
+				Name:   "app",
+				Module: "github.com/org/app",
+				Repo:   "org/app",
+			},
+		},
+	}
+}
+
+func TestBuildGraph_Waves(t *testing.T) {
+	graph, err := planner.BuildGraph(layeredManifest(), "github.com/org/lib")
+	if err != nil {
+		t.Fatalf("BuildGraph() error = %v", err)
+	}
+
+	want := []planner.Wave{
+		{Level: 0, Modules: []string{"github.com/org/lib"}, Repos: []string{"org/app", "org/core"}},
+		{Level: 1, Modules: []string{"github.com/org/core"}, Repos: []string{"org/api", "org/app"}},
+		{Level: 2, Modules: []string{"github.com/org/api"}, Repos: []string{"org/worker"}},
+	}
+	if got := graph.Waves(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Waves() = %+v, want %+v", got, want)
+	}
+
+	if level, ok := graph.Level("go.org.dev/api"); !ok || level != 2 {
+		t.Errorf("Level(alias) = %d, %v, want 2", level, ok)
+	}
+	if _, ok := graph.Level("github.com/org/app"); ok {
+		t.Error("app has no dependents and should not be a node")
+	}
+	if got := graph.Downstream("github.com/org/lib"); !reflect.DeepEqual(got, []string{"github.com/org/core"}) {
+		t.Errorf("Downstream(lib) = %v", got)
+	}
+	if module := graph.Module("github.com/org/api"); module == nil || module.Repo != "org/api" {
+		t.Errorf("Module(api) = %+v", module)
+	}
+}
+
+func TestBuildGraph_LongestPathLevel(t *testing.T) {
+	m := layeredManifest()
+	// lib now also feeds api directly; api still waits for core
+	m.Modules[0].Dependents = append(m.Modules[0].Dependents, manifest.Dependent{Repo: "org/api", Module: "github.com/org/api", ModulePath: "."})
+
+	graph, err := planner.BuildGraph(m, "github.com/org/lib")
+	if err != nil {
+		t.Fatalf("BuildGraph() error = %v", err)
+	}
+	if level, _ := graph.Level("github.com/org/api"); level != 2 {
+		t.Errorf("Level(api) = %d, want 2", level)
+	}
+}
+
+func TestBuildGraph_Cycle(t *testing.T) {
+	m := layeredManifest()
+	m.Modules[2].Dependents = append(m.Modules[2].Dependents, manifest.Dependent{Repo: "org/core", Module: "github.com/org/core", ModulePath: "."})
+
+	_, err := planner.BuildGraph(m, "github.com/org/lib")
+	var cycle *planner.CycleError
+	if !errors.As(err, &cycle) {
+		t.Fatalf("BuildGraph() error = %v, want a CycleError", err)
+	}
+	want := []string{"github.com/org/core", "github.com/org/api", "github.com/org/core"}
+	if !reflect.DeepEqual(cycle.Modules, want) {
+		t.Errorf("cycle = %v, want %v", cycle.Modules, want)
+	}
+}
+
+func TestBuildGraph_UnknownRoot(t *testing.T) {
+	if _, err := planner.BuildGraph(layeredManifest(), "github.com/org/missing"); !planner.IsTargetNotFound(err) {
+		t.Errorf("BuildGraph() error = %v, want target not found", err)
+	}
+}
+
+func TestPlanner_Waves(t *testing.T) {
+	target := planner.Target{Module: "github.com/org/lib", Version: "v1.2.0"}
+
+	plan, err := planner.New().Plan(context.Background(), layeredManifest(), target)
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	if plan.Waves != nil {
+		t.Errorf("Waves = %+v without WithWaves", plan.Waves)
+	}
+
+	plan, err = planner.New().Plan(planner.WithWaves(context.Background()), layeredManifest(), target)
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	if len(plan.Waves) != 3 || len(plan.Items) != 2 || plan.Items[0].Wave != 0 {
+		t.Errorf("plan = %d items, waves %+v", len(plan.Items), plan.Waves)
+	}
+
+	cyclic := layeredManifest()
+	cyclic.Modules[2].Dependents = append(cyclic.Modules[2].Dependents, manifest.Dependent{Repo: "org/lib", Module: "github.com/org/lib", ModulePath: "."})
+	if _, err := planner.New().Plan(context.Background(), cyclic, target); err != nil {
+		t.Errorf("Plan() error = %v, cycles only matter for waves", err)
+	}
+	var cycle *planner.CycleError
+	if _, err := planner.New().Plan(planner.WithWaves(context.Background()), cyclic, target); !errors.As(err, &cycle) {
+		t.Errorf("Plan() error = %v, want a CycleError", err)
+	}
+}
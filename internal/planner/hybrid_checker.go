@@ -44,13 +44,15 @@ func NewHybridDependencyChecker(
 // - CheckStrategyLocal: Uses workspace-based checking only
 // - CheckStrategyRemote: Uses remote git operations only
 // - CheckStrategyAuto: Tries local first, falls back to remote on error
+//
+// A dependent's own check_strategy takes precedence over the configured strategy.
 func (h *hybridDependencyChecker) NeedsUpdate(
 	ctx context.Context,
 	dependent manifest.Dependent,
 	target Target,
 	workspace string,
 ) (bool, error) {
-	switch h.strategy {
+	switch h.strategyFor(dependent) {
 	case CheckStrategyLocal:
 		if h.logger != nil {
 			h.logger.Debug("using local dependency checker",
@@ -122,6 +124,11 @@ func (h *hybridDependencyChecker) NeedsUpdate(
 			return remoteNeedsUpdate, nil
 		}
 
+		// A local copy of the target is authoritative; the remote go.mod cannot see go.work
+		if IsLocallyManaged(err) {
+			return false, err
+		}
+
 		// Local check failed - fallback to remote
 		if h.logger != nil {
 			h.logger.Debug("local check failed, falling back to remote",
@@ -136,6 +143,27 @@ func (h *hybridDependencyChecker) NeedsUpdate(
 	}
 }
 
+// strategyFor resolves the effective strategy for a dependent, preferring the
+// dependent's manifest override over the checker-wide strategy. Local checks are
+// downgraded to remote when no workspace is configured.
+func (h *hybridDependencyChecker) strategyFor(dependent manifest.Dependent) CheckStrategy {
+	strategy := h.strategy
+	if dependent.CheckStrategy != "" {
+		strategy = CheckStrategy(dependent.CheckStrategy)
+		if h.logger != nil && strategy != h.strategy {
+			h.logger.Debug("using dependent check strategy override",
+				"repo", dependent.Repo,
+				"strategy", string(strategy))
+		}
+	}
+
+	if strategy == CheckStrategyLocal && h.workspace == "" && h.remoteChecker != nil {
+		return CheckStrategyRemote
+	}
+
+	return strategy
+}
+
 // detectCheckStrategy automatically detects the appropriate check strategy based on
 // workspace availability. This is used when CheckStrategyAuto is configured.
 //
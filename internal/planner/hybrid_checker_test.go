@@ -288,6 +288,39 @@ func TestHybridDependencyChecker_AutoStrategy_LocalFallbackToRemote(t *testing.T
 	}
 }
 
+func TestHybridDependencyChecker_AutoStrategy_LocallyManagedSkipsRemote(t *testing.T) {
+	localChecker := &mockDependencyChecker{
+		needsUpdateFunc: func(ctx context.Context, dependent manifest.Dependent, target Target, workspace string) (bool, error) {
+			return false, &LocallyManagedError{Dependent: dependent.Repo, Module: target.Module, Source: LocalSourceWorkUse, Dir: "../go-errors"}
+		},
+	}
+
+	remoteChecker := &mockRemoteDependencyCheckerImpl{
+		mockDependencyChecker: mockDependencyChecker{
+			needsUpdateFunc: func(ctx context.Context, dependent manifest.Dependent, target Target, workspace string) (bool, error) {
+				return true, nil
+			},
+		},
+	}
+
+	checker := NewHybridDependencyChecker(localChecker, remoteChecker, CheckStrategyAuto, "/workspace", nil)
+
+	dependent := manifest.Dependent{Repo: "goliatone/test-repo"}
+	target := Target{Module: "github.com/goliatone/go-errors", Version: "v0.9.0"}
+
+	needsUpdate, err := checker.NeedsUpdate(context.Background(), dependent, target, "/workspace")
+
+	if needsUpdate {
+		t.Error("expected needsUpdate=false for a locally managed dependency")
+	}
+	if !IsLocallyManaged(err) {
+		t.Fatalf("expected LocallyManagedError, got: %v", err)
+	}
+	if remoteChecker.callCount != 0 {
+		t.Errorf("expected remote checker not to be called, got %d calls", remoteChecker.callCount)
+	}
+}
+
 func TestHybridDependencyChecker_AutoStrategy_BothFail(t *testing.T) {
 	localChecker := &mockDependencyChecker{
 		needsUpdateFunc: func(ctx context.Context, dependent manifest.Dependent, target Target, workspace string) (bool, error) {
@@ -395,6 +428,75 @@ func TestHybridDependencyChecker_WithLogger(t *testing.T) {
 	}
 }
 
+func TestHybridDependencyChecker_DependentStrategyOverride(t *testing.T) {
+	localChecker := &mockDependencyChecker{
+		needsUpdateFunc: func(ctx context.Context, dependent manifest.Dependent, target Target, workspace string) (bool, error) {
+			return true, nil
+		},
+	}
+	remoteChecker := &mockRemoteDependencyCheckerImpl{
+		mockDependencyChecker: mockDependencyChecker{
+			needsUpdateFunc: func(ctx context.Context, dependent manifest.Dependent, target Target, workspace string) (bool, error) {
+				return false, nil
+			},
+		},
+	}
+
+	checker := NewHybridDependencyChecker(
+		localChecker,
+		remoteChecker,
+		CheckStrategyLocal,
+		"/workspace",
+		nil,
+	)
+
+	target := Target{Module: "github.com/goliatone/go-errors", Version: "v0.9.0"}
+
+	needsUpdate, err := checker.NeedsUpdate(context.Background(), manifest.Dependent{
+		Repo:          "goliatone/huge-repo",
+		CheckStrategy: "remote",
+	}, target, "/workspace")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if needsUpdate {
+		t.Error("expected remote override result needsUpdate=false")
+	}
+	if localChecker.callCount != 0 {
+		t.Errorf("expected local checker not called for remote override, got %d", localChecker.callCount)
+	}
+	if remoteChecker.callCount != 1 {
+		t.Errorf("expected remote checker called once, got %d", remoteChecker.callCount)
+	}
+
+	if _, err := checker.NeedsUpdate(context.Background(), manifest.Dependent{Repo: "goliatone/small-repo"}, target, "/workspace"); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if localChecker.callCount != 1 {
+		t.Errorf("expected global local strategy for dependent without override, got %d local calls", localChecker.callCount)
+	}
+}
+
+func TestHybridDependencyChecker_LocalOverrideWithoutWorkspaceUsesRemote(t *testing.T) {
+	localChecker := &mockDependencyChecker{}
+	remoteChecker := &mockRemoteDependencyCheckerImpl{}
+
+	checker := NewHybridDependencyChecker(localChecker, remoteChecker, CheckStrategyRemote, "", nil)
+
+	dependent := manifest.Dependent{Repo: "goliatone/test-repo", CheckStrategy: "local"}
+	target := Target{Module: "github.com/goliatone/go-errors", Version: "v0.9.0"}
+
+	if _, err := checker.NeedsUpdate(context.Background(), dependent, target, ""); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if localChecker.callCount != 0 {
+		t.Errorf("expected local checker skipped without workspace, got %d calls", localChecker.callCount)
+	}
+	if remoteChecker.callCount != 1 {
+		t.Errorf("expected remote checker called once, got %d", remoteChecker.callCount)
+	}
+}
+
 func TestDetectCheckStrategy_ExplicitLocal(t *testing.T) {
 	opts := CheckOptions{Strategy: CheckStrategyLocal}
 	strategy := detectCheckStrategy("/some/workspace", opts)
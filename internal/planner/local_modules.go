@@ -0,0 +1,84 @@
+package planner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/mod/modfile"
+)
+
+// Directives through which a dependent can resolve the target module locally.
+const (
+	LocalSourceReplace     = "go.mod replace"
+	LocalSourceWorkReplace = "go.work replace"
+	LocalSourceWorkUse     = "go.work use"
+)
+
+// localReplace returns the path and directory of the first replace directive that
+// points one of paths at a directory instead of a module version.
+func localReplace(replaces []*modfile.Replace, paths []string) (path, dir string, ok bool) {
+	for _, r := range replaces {
+		if r.New.Version != "" {
+			continue
+		}
+		for _, p := range paths {
+			if r.Old.Path == p {
+				return p, r.New.Path, true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// detectLocallyManaged reports whether the dependent checked out at repoPath builds
+// against a local copy of the target: a replace directive in its go.mod, or a
+// go.work file in the repository that replaces the target or uses a directory
+// holding it. A nil error and nil result mean the target resolves normally. An
+// unreadable go.work is returned as an error.
+func detectLocallyManaged(repoPath string, modFile *modfile.File, dependent string, target Target) (*LocallyManagedError, error) {
+	paths := target.Paths()
+
+	if modFile != nil {
+		if path, dir, ok := localReplace(modFile.Replace, paths); ok {
+			return &LocallyManagedError{Dependent: dependent, Module: path, Source: LocalSourceReplace, Dir: dir}, nil
+		}
+	}
+
+	workPath := filepath.Join(repoPath, "go.work")
+	data, err := os.ReadFile(workPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read go.work: %w", err)
+	}
+	work, err := modfile.ParseWork(workPath, data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("parse go.work: %w", err)
+	}
+
+	if path, dir, ok := localReplace(work.Replace, paths); ok {
+		return &LocallyManagedError{Dependent: dependent, Module: path, Source: LocalSourceWorkReplace, Dir: dir}, nil
+	}
+
+	for _, use := range work.Use {
+		dir := use.Path
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(repoPath, dir)
+		}
+		modData, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+		if err != nil {
+			// The go command reports missing use directories; they hold no module here
+			continue
+		}
+		modulePath := modfile.ModulePath(modData)
+		for _, p := range paths {
+			if modulePath == p {
+				return &LocallyManagedError{Dependent: dependent, Module: p, Source: LocalSourceWorkUse, Dir: use.Path}, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,115 @@
+package planner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/mod/modfile"
+)
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func TestDetectLocallyManaged(t *testing.T) {
+	target := Target{Module: "github.com/goliatone/go-errors", Version: "v0.9.0"}
+	goMod := "module github.com/goliatone/app\n\ngo 1.21\n\nrequire github.com/goliatone/go-errors v0.8.0\n"
+
+	tests := []struct {
+		name       string
+		goMod      string
+		goWork     string
+		files      map[string]string
+		wantSource string
+		wantDir    string
+	}{
+		{
+			name:  "no go.work",
+			goMod: goMod,
+		},
+		{
+			name:       "go.mod replace",
+			goMod:      goMod + "\nreplace github.com/goliatone/go-errors => ../go-errors\n",
+			wantSource: LocalSourceReplace,
+			wantDir:    "../go-errors",
+		},
+		{
+			name:  "go.mod version replace",
+			goMod: goMod + "\nreplace github.com/goliatone/go-errors => github.com/fork/go-errors v0.8.1\n",
+		},
+		{
+			name:       "go.work replace",
+			goMod:      goMod,
+			goWork:     "go 1.21\n\nuse .\n\nreplace github.com/goliatone/go-errors => ./vendor/go-errors\n",
+			wantSource: LocalSourceWorkReplace,
+			wantDir:    "./vendor/go-errors",
+		},
+		{
+			name:       "go.work use",
+			goMod:      goMod,
+			goWork:     "go 1.21\n\nuse (\n\t.\n\t./libs/errors\n)\n",
+			files:      map[string]string{"libs/errors/go.mod": "module github.com/goliatone/go-errors\n\ngo 1.21\n"},
+			wantSource: LocalSourceWorkUse,
+			wantDir:    "./libs/errors",
+		},
+		{
+			name:   "go.work use of unrelated module",
+			goMod:  goMod,
+			goWork: "go 1.21\n\nuse (\n\t.\n\t./libs/other\n\t./missing\n)\n",
+			files:  map[string]string{"libs/other/go.mod": "module github.com/goliatone/other\n\ngo 1.21\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := t.TempDir()
+			if tt.goWork != "" {
+				writeTestFile(t, filepath.Join(repo, "go.work"), tt.goWork)
+			}
+			for name, content := range tt.files {
+				writeTestFile(t, filepath.Join(repo, name), content)
+			}
+			modFile, err := modfile.Parse("go.mod", []byte(tt.goMod), nil)
+			if err != nil {
+				t.Fatalf("parse go.mod: %v", err)
+			}
+
+			local, err := detectLocallyManaged(repo, modFile, "goliatone/app", target)
+			if err != nil {
+				t.Fatalf("detectLocallyManaged() error = %v", err)
+			}
+			if tt.wantSource == "" {
+				if local != nil {
+					t.Fatalf("detectLocallyManaged() = %+v, want nil", local)
+				}
+				return
+			}
+			if local == nil {
+				t.Fatalf("detectLocallyManaged() = nil, want %s", tt.wantSource)
+			}
+			if local.Source != tt.wantSource || local.Dir != tt.wantDir || local.Module != target.Module {
+				t.Errorf("detectLocallyManaged() = %+v, want source %q dir %q", local, tt.wantSource, tt.wantDir)
+			}
+			if local.Dependent != "goliatone/app" {
+				t.Errorf("Dependent = %q, want goliatone/app", local.Dependent)
+			}
+		})
+	}
+}
+
+func TestDetectLocallyManaged_InvalidGoWork(t *testing.T) {
+	repo := t.TempDir()
+	writeTestFile(t, filepath.Join(repo, "go.work"), "use (\n")
+
+	_, err := detectLocallyManaged(repo, nil, "goliatone/app", Target{Module: "github.com/goliatone/go-errors"})
+	if err == nil {
+		t.Fatal("expected error for unparsable go.work")
+	}
+}
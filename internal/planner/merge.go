@@ -0,0 +1,220 @@
+package planner
+
+import (
+	"sort"
+
+	"github.com/goliatone/cascade/internal/manifest"
+)
+
+// Provenance sources recorded for merged dependent fields, listed from lowest to
+// highest precedence. Each layer only replaces the fields it sets explicitly.
+const (
+	SourceManifestDefaults    = "manifest defaults"
+	SourceManifestDependent   = "manifest dependent"
+	SourceDependentModule     = "dependent .cascade.yaml module"
+	SourceDependentOverride   = "dependent .cascade.yaml override"
+	sourceDefaultsAndManifest = SourceManifestDefaults + " + " + SourceManifestDependent
+)
+
+// Provenance maps a merged field to the configuration layer that supplied its value.
+// Keys use manifest YAML names; nested fields are dot separated (for example
+// "pr.reviewers" or "env.GOFLAGS").
+type Provenance map[string]string
+
+// Fields returns the recorded field names in sorted order.
+func (p Provenance) Fields() []string {
+	fields := make([]string, 0, len(p))
+	for field := range p {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+func (p Provenance) set(field, source string) {
+	if p != nil {
+		p[field] = source
+	}
+}
+
+// mergeLayer is a configuration layer applied on top of an expanded dependent.
+type mergeLayer struct {
+	source string
+	cfg    *manifest.DependentConfig
+}
+
+// mergeDependent resolves the effective configuration for a dependent. Manifest
+// defaults fill the fields the manifest dependent leaves empty (commands and labels
+// are combined), then each layer is applied in order so later layers win. PR title
+// and body templates from defaults are only inherited by dependents that declare
+// PR configuration of their own; a body template file is always inherited.
+func mergeDependent(dependent manifest.Dependent, defaults manifest.Defaults, layers ...mergeLayer) (manifest.Dependent, Provenance) {
+	provenance := Provenance{}
+	recordExpandedDefaults(provenance, dependent, defaults)
+
+	hadOriginalPR := manifest.HasOriginalPRConfig(dependent)
+	expanded := manifest.ExpandDefaults(dependent, defaults)
+	if !hadOriginalPR {
+		expanded.PR.TitleTemplate = ""
+		expanded.PR.BodyTemplate = ""
+		delete(provenance, "pr.title")
+		delete(provenance, "pr.body_template")
+	}
+
+	return mergeLayers(expanded, provenance, layers...), provenance
+}
+
+// recordExpandedDefaults records which of the manifest defaults or the manifest
+// dependent supplies each field, mirroring manifest.ExpandDefaults.
+func recordExpandedDefaults(p Provenance, dep manifest.Dependent, defaults manifest.Defaults) {
+	recordScalar(p, "branch", dep.Branch != "", defaults.Branch != "")
+	recordCombined(p, "tests", len(dep.Tests) > 0, len(defaults.Tests) > 0)
+	recordCombined(p, "extra_commands", len(dep.ExtraCommands) > 0, len(defaults.ExtraCommands) > 0)
+	recordCombined(p, "labels", len(dep.Labels) > 0, len(defaults.Labels) > 0)
+
+	n, dn := dep.Notifications, defaults.Notifications
+	recordScalar(p, "notifications.slack_channel", n.SlackChannel != "", dn.SlackChannel != "")
+	recordScalar(p, "notifications.webhook", n.Webhook != "", dn.Webhook != "")
+	recordScalar(p, "notifications.github_issues", n.GitHubIssues != nil, dn.GitHubIssues != nil)
+	recordScalar(p, "notifications.on_failure", n.OnFailure, false)
+	recordScalar(p, "notifications.on_success", n.OnSuccess, false)
+
+	pr, dpr := dep.PR, defaults.PR
+	recordScalar(p, "pr.title", pr.TitleTemplate != "", dpr.TitleTemplate != "")
+	recordScalar(p, "pr.body_template", pr.BodyTemplate != "", dpr.BodyTemplate != "")
+	recordScalar(p, "pr.body_template_file", pr.BodyTemplateFile != "", dpr.BodyTemplateFile != "")
+	recordScalar(p, "pr.reviewers", pr.Reviewers != nil, len(dpr.Reviewers) > 0)
+	recordScalar(p, "pr.team_reviewers", pr.TeamReviewers != nil, len(dpr.TeamReviewers) > 0)
+	recordScalar(p, "pr.draft", pr.Draft, dpr.Draft)
+	recordScalar(p, "pr.base", pr.Base != "", dpr.Base != "")
+	recordScalar(p, "pr.delete_branch_on_merge", pr.DeleteBranchOnMerge, dpr.DeleteBranchOnMerge)
+	recordScalar(p, "pr.auto_merge", pr.AutoMerge != "", dpr.AutoMerge != "")
+	recordScalar(p, "retry.max_attempts", dep.Retry.MaxAttempts != 0, defaults.Retry.MaxAttempts != 0)
+	recordScalar(p, "retry.backoff", dep.Retry.Backoff != 0, defaults.Retry.Backoff != 0)
+
+	c, dc := dep.Changelog, defaults.Changelog
+	recordScalar(p, "changelog.format", c.Format != "", dc.Format != "")
+	recordScalar(p, "changelog.file", c.File != "", dc.File != "")
+	recordScalar(p, "changelog.section", c.Section != "", dc.Section != "")
+	recordScalar(p, "changelog.dir", c.Dir != "", dc.Dir != "")
+	recordScalar(p, "changelog.template", c.Template != "", dc.Template != "")
+
+	for key := range dep.Env {
+		p.set("env."+key, SourceManifestDependent)
+	}
+	recordScalar(p, "services", len(dep.Services) > 0, false)
+	recordScalar(p, "timeout", dep.Timeout > 0, false)
+	recordScalar(p, "canary", dep.Canary, false)
+	recordScalar(p, "goflags", dep.GoFlags != "", false)
+	recordScalar(p, "credentials", len(dep.Credentials) > 0, false)
+	recordScalar(p, "concurrency_group", dep.ConcurrencyGroup != "", false)
+}
+
+func recordScalar(p Provenance, field string, fromDependent, fromDefaults bool) {
+	switch {
+	case fromDependent:
+		p.set(field, SourceManifestDependent)
+	case fromDefaults:
+		p.set(field, SourceManifestDefaults)
+	}
+}
+
+func recordCombined(p Provenance, field string, fromDependent, fromDefaults bool) {
+	switch {
+	case fromDependent && fromDefaults:
+		p.set(field, sourceDefaultsAndManifest)
+	default:
+		recordScalar(p, field, fromDependent, fromDefaults)
+	}
+}
+
+// mergeLayers applies each layer to base in order. Provenance is recorded when p is
+// non-nil.
+func mergeLayers(base manifest.Dependent, p Provenance, layers ...mergeLayer) manifest.Dependent {
+	for _, layer := range layers {
+		base = applyLayer(base, p, layer)
+	}
+	return base
+}
+
+// applyLayer merges a layer onto the base dependent, giving precedence to the values
+// the layer sets explicitly.
+func applyLayer(base manifest.Dependent, p Provenance, layer mergeLayer) manifest.Dependent {
+	cfg := layer.cfg
+	if cfg == nil {
+		return base
+	}
+
+	if cfg.Branch != "" {
+		base.Branch = cfg.Branch
+		p.set("branch", layer.source)
+	}
+
+	if len(cfg.Tests) > 0 {
+		base.Tests = cloneCommands(cfg.Tests)
+		p.set("tests", layer.source)
+	}
+
+	if len(cfg.ExtraCommands) > 0 {
+		base.ExtraCommands = cloneCommands(cfg.ExtraCommands)
+		p.set("extra_commands", layer.source)
+	}
+
+	if len(cfg.Labels) > 0 {
+		base.Labels = cloneStrings(cfg.Labels)
+		p.set("labels", layer.source)
+	}
+
+	base.Notifications = applyNotificationOverrides(base.Notifications, cfg.Notifications, p, layer.source)
+	base.PR = applyPROverrides(base.PR, cfg.PR, p, layer.source)
+	base.Changelog = applyChangelogOverrides(base.Changelog, cfg.Changelog, p, layer.source)
+
+	if len(cfg.Env) > 0 {
+		base.Env = mergeEnv(base.Env, cfg.Env)
+		for key := range cfg.Env {
+			p.set("env."+key, layer.source)
+		}
+	}
+
+	if len(cfg.Services) > 0 {
+		base.Services = cloneServices(cfg.Services)
+		p.set("services", layer.source)
+	}
+
+	if cfg.Timeout > 0 {
+		base.Timeout = cfg.Timeout
+		p.set("timeout", layer.source)
+	}
+
+	if cfg.GoFlags != "" {
+		base.GoFlags = cfg.GoFlags
+		p.set("goflags", layer.source)
+	}
+
+	if len(cfg.Credentials) > 0 {
+		base.Credentials = cloneCredentials(cfg.Credentials)
+		p.set("credentials", layer.source)
+	}
+
+	if cfg.Retry.MaxAttempts != 0 {
+		base.Retry.MaxAttempts = cfg.Retry.MaxAttempts
+		p.set("retry.max_attempts", layer.source)
+	}
+
+	if cfg.Retry.Backoff != 0 {
+		base.Retry.Backoff = cfg.Retry.Backoff
+		p.set("retry.backoff", layer.source)
+	}
+
+	if cfg.Canary {
+		base.Canary = true
+		p.set("canary", layer.source)
+	}
+
+	if cfg.Skip {
+		base.Skip = true
+		p.set("skip", layer.source)
+	}
+
+	return base
+}
@@ -1,9 +1,13 @@
 package planner
 
-import "github.com/goliatone/cascade/internal/manifest"
+import (
+	"strings"
+
+	"github.com/goliatone/cascade/internal/manifest"
+)
 
 // convertModuleConfig transforms a ModuleConfig into a DependentConfig so it can be merged
-// with the same precedence logic as dependent overrides.
+// as a layer with the same precedence logic as dependent overrides.
 func convertModuleConfig(module *manifest.ModuleConfig) *manifest.DependentConfig {
 	if module == nil {
 		return nil
@@ -17,58 +21,83 @@ func convertModuleConfig(module *manifest.ModuleConfig) *manifest.DependentConfi
 		Notifications: cloneNotifications(module.Notifications),
 		PR:            clonePRConfig(module.PR),
 		Env:           cloneEnv(module.Env),
+		Services:      cloneServices(module.Services),
 		Timeout:       module.Timeout,
+		Changelog:     module.Changelog,
 	}
 
 	return cfg
 }
 
-// applyDependentConfig merges the provided config onto the base dependent, giving precedence
-// to the override values when present.
-func applyDependentConfig(base manifest.Dependent, cfg *manifest.DependentConfig) manifest.Dependent {
-	if cfg == nil {
-		return base
-	}
-
-	if cfg.Branch != "" {
-		base.Branch = cfg.Branch
-	}
-
-	if len(cfg.Tests) > 0 {
-		base.Tests = cloneCommands(cfg.Tests)
-	}
-
-	if len(cfg.ExtraCommands) > 0 {
-		base.ExtraCommands = cloneCommands(cfg.ExtraCommands)
-	}
-
-	if len(cfg.Labels) > 0 {
-		base.Labels = cloneStrings(cfg.Labels)
-	}
-
-	if !isZeroNotifications(cfg.Notifications) {
-		base.Notifications = applyNotificationOverrides(base.Notifications, cfg.Notifications)
+// ApplyDependentManifest re-applies a dependent repository's own .cascade.yaml to a
+// planned work item. Module defaults are applied first, then the override keyed by the
+// item's source module, mirroring the precedence used at plan time. The executor uses
+// this after cloning so overrides take effect even when planning ran without a workspace.
+func ApplyDependentManifest(item WorkItem, depManifest *manifest.Manifest) WorkItem {
+	if depManifest == nil {
+		return item
 	}
 
-	base.PR = applyPROverrides(base.PR, cfg.PR)
-
-	if len(cfg.Env) > 0 {
-		base.Env = mergeEnv(base.Env, cfg.Env)
+	dependent := manifest.Dependent{
+		Repo:          item.Repo,
+		Branch:        item.Branch,
+		Tests:         item.Tests,
+		ExtraCommands: item.ExtraCommands,
+		Labels:        item.Labels,
+		Notifications: item.Notifications,
+		PR:            item.PR,
+		Env:           item.Env,
+		Services:      item.Services,
+		Timeout:       item.Timeout,
+		Canary:        item.Canary,
+		Skip:          item.Skip,
+		GoFlags:       item.GoFlags,
+		Credentials:   item.Credentials,
+	}
+	if item.Retry != nil {
+		dependent.Retry = *item.Retry
+	}
+	if item.Changelog != nil {
+		dependent.Changelog = *item.Changelog
 	}
 
-	if cfg.Timeout > 0 {
-		base.Timeout = cfg.Timeout
+	layers := []mergeLayer{{source: SourceDependentModule, cfg: convertModuleConfig(depManifest.Module)}}
+	if override, ok := depManifest.Dependents[item.SourceModule]; ok {
+		layers = append(layers, mergeLayer{source: SourceDependentOverride, cfg: &override})
 	}
+	dependent = mergeLayers(dependent, nil, layers...)
 
-	if cfg.Canary {
-		base.Canary = true
-	}
+	item.Branch = baseBranch(dependent)
+	item.Tests = dependent.Tests
+	item.ExtraCommands = dependent.ExtraCommands
+	item.Labels = dependent.Labels
+	item.Notifications = dependent.Notifications
+	item.PR = dependent.PR
+	item.Env = dependent.Env
+	item.Services = dependent.Services
+	item.Timeout = dependent.Timeout
+	item.Canary = dependent.Canary
+	item.Skip = dependent.Skip
+	item.GoFlags = dependent.GoFlags
+	item.Credentials = dependent.Credentials
+	if !dependent.Retry.IsZero() {
+		retry := dependent.Retry
+		item.Retry = &retry
+	}
+	if !dependent.Changelog.IsZero() {
+		changelog := dependent.Changelog
+		item.Changelog = &changelog
+	}
+	return item
+}
 
-	if cfg.Skip {
-		base.Skip = true
+// baseBranch returns the branch the update of dependent starts from and its
+// pull request targets: pr.base when set, otherwise the dependent's branch.
+func baseBranch(dependent manifest.Dependent) string {
+	if base := strings.TrimSpace(dependent.PR.Base); base != "" {
+		return base
 	}
-
-	return base
+	return dependent.Branch
 }
 
 func cloneCommands(cmds []manifest.Command) []manifest.Command {
@@ -121,6 +150,32 @@ func mergeEnv(base map[string]string, override map[string]string) map[string]str
 	return result
 }
 
+func cloneServices(services []manifest.Service) []manifest.Service {
+	if len(services) == 0 {
+		return nil
+	}
+
+	cloned := make([]manifest.Service, len(services))
+	for i, svc := range services {
+		cloned[i] = svc
+		cloned[i].Env = cloneEnv(svc.Env)
+		cloned[i].Export = cloneEnv(svc.Export)
+		if svc.HealthCheck != nil {
+			hc := *svc.HealthCheck
+			hc.Cmd = cloneStrings(hc.Cmd)
+			cloned[i].HealthCheck = &hc
+		}
+	}
+	return cloned
+}
+
+func cloneCredentials(credentials []manifest.Credential) []manifest.Credential {
+	if len(credentials) == 0 {
+		return nil
+	}
+	return append([]manifest.Credential(nil), credentials...)
+}
+
 func cloneNotifications(n manifest.Notifications) manifest.Notifications {
 	copy := manifest.Notifications{
 		SlackChannel: n.SlackChannel,
@@ -133,39 +188,42 @@ func cloneNotifications(n manifest.Notifications) manifest.Notifications {
 		if len(issues.Labels) > 0 {
 			issues.Labels = cloneStrings(issues.Labels)
 		}
+		if len(issues.Assignees) > 0 {
+			issues.Assignees = cloneStrings(issues.Assignees)
+		}
 		copy.GitHubIssues = &issues
 	}
 	return copy
 }
 
-func isZeroNotifications(n manifest.Notifications) bool {
-	return n.SlackChannel == "" &&
-		!n.OnFailure &&
-		!n.OnSuccess &&
-		n.Webhook == "" &&
-		n.GitHubIssues == nil
-}
-
-func applyNotificationOverrides(base, override manifest.Notifications) manifest.Notifications {
+func applyNotificationOverrides(base, override manifest.Notifications, p Provenance, source string) manifest.Notifications {
 	result := base
 	if override.SlackChannel != "" {
 		result.SlackChannel = override.SlackChannel
+		p.set("notifications.slack_channel", source)
 	}
 	if override.Webhook != "" {
 		result.Webhook = override.Webhook
+		p.set("notifications.webhook", source)
 	}
 	if override.OnFailure {
 		result.OnFailure = true
+		p.set("notifications.on_failure", source)
 	}
 	if override.OnSuccess {
 		result.OnSuccess = true
+		p.set("notifications.on_success", source)
 	}
 	if override.GitHubIssues != nil {
 		issues := *override.GitHubIssues
 		if len(issues.Labels) > 0 {
 			issues.Labels = cloneStrings(issues.Labels)
 		}
+		if len(issues.Assignees) > 0 {
+			issues.Assignees = cloneStrings(issues.Assignees)
+		}
 		result.GitHubIssues = &issues
+		p.set("notifications.github_issues", source)
 	}
 	return result
 }
@@ -174,6 +232,12 @@ func clonePRConfig(cfg manifest.PRConfig) manifest.PRConfig {
 	copy := manifest.PRConfig{
 		TitleTemplate: cfg.TitleTemplate,
 		BodyTemplate:  cfg.BodyTemplate,
+		Draft:         cfg.Draft,
+		Base:          cfg.Base,
+		AutoMerge:     cfg.AutoMerge,
+
+		BodyTemplateFile:    cfg.BodyTemplateFile,
+		DeleteBranchOnMerge: cfg.DeleteBranchOnMerge,
 	}
 	if len(cfg.Reviewers) > 0 {
 		copy.Reviewers = cloneStrings(cfg.Reviewers)
@@ -184,19 +248,68 @@ func clonePRConfig(cfg manifest.PRConfig) manifest.PRConfig {
 	return copy
 }
 
-func applyPROverrides(base, override manifest.PRConfig) manifest.PRConfig {
+func applyPROverrides(base, override manifest.PRConfig, p Provenance, source string) manifest.PRConfig {
 	result := base
 	if override.TitleTemplate != "" {
 		result.TitleTemplate = override.TitleTemplate
+		p.set("pr.title", source)
 	}
 	if override.BodyTemplate != "" {
 		result.BodyTemplate = override.BodyTemplate
+		p.set("pr.body_template", source)
+	}
+	if override.BodyTemplateFile != "" {
+		result.BodyTemplateFile = override.BodyTemplateFile
+		p.set("pr.body_template_file", source)
 	}
 	if len(override.Reviewers) > 0 {
 		result.Reviewers = cloneStrings(override.Reviewers)
+		p.set("pr.reviewers", source)
 	}
 	if len(override.TeamReviewers) > 0 {
 		result.TeamReviewers = cloneStrings(override.TeamReviewers)
+		p.set("pr.team_reviewers", source)
+	}
+	if override.Draft {
+		result.Draft = true
+		p.set("pr.draft", source)
+	}
+	if override.Base != "" {
+		result.Base = override.Base
+		p.set("pr.base", source)
+	}
+	if override.DeleteBranchOnMerge {
+		result.DeleteBranchOnMerge = true
+		p.set("pr.delete_branch_on_merge", source)
+	}
+	if override.AutoMerge != "" {
+		result.AutoMerge = override.AutoMerge
+		p.set("pr.auto_merge", source)
+	}
+	return result
+}
+
+func applyChangelogOverrides(base, override manifest.Changelog, p Provenance, source string) manifest.Changelog {
+	result := base
+	if override.Format != "" {
+		result.Format = override.Format
+		p.set("changelog.format", source)
+	}
+	if override.File != "" {
+		result.File = override.File
+		p.set("changelog.file", source)
+	}
+	if override.Section != "" {
+		result.Section = override.Section
+		p.set("changelog.section", source)
+	}
+	if override.Dir != "" {
+		result.Dir = override.Dir
+		p.set("changelog.dir", source)
+	}
+	if override.Template != "" {
+		result.Template = override.Template
+		p.set("changelog.template", source)
 	}
 	return result
 }
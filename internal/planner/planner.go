@@ -2,9 +2,14 @@ package planner
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/goliatone/cascade/internal/manifest"
+	"github.com/goliatone/cascade/internal/telemetry"
+	"github.com/goliatone/cascade/pkg/repometa"
+	"github.com/goliatone/cascade/pkg/workspace/layout"
 )
 
 // Planner computes a cascade plan from a manifest and target release.
@@ -29,6 +34,14 @@ func WithWorkspace(workspace string) Option {
 	}
 }
 
+// WithWorkspaceLayout sets the layout of repository clones in the workspace,
+// used to find dependent overrides.
+func WithWorkspaceLayout(l layout.Layout) Option {
+	return func(p *planner) {
+		p.layout = l
+	}
+}
+
 // WithLogger attaches a logger for debug output.
 func WithLogger(logger Logger) Option {
 	return func(p *planner) {
@@ -36,6 +49,16 @@ func WithLogger(logger Logger) Option {
 	}
 }
 
+// WithRepoMetadata enables a preflight that looks each dependent repository up in
+// svc: archived repositories are skipped, and the repository's default branch is
+// used when neither the manifest nor overrides set a branch. Lookups are cached by
+// svc, so the broker reuses them later in the run.
+func WithRepoMetadata(svc *repometa.Service) Option {
+	return func(p *planner) {
+		p.metadata = svc
+	}
+}
+
 // New returns a planner with optional configuration.
 func New(opts ...Option) Planner {
 	p := &planner{}
@@ -48,10 +71,25 @@ func New(opts ...Option) Planner {
 type planner struct {
 	checker   DependencyChecker
 	workspace string
+	layout    layout.Layout
 	logger    Logger
+	metadata  *repometa.Service
+
+	history     TimingHistory
+	concurrency int
 }
 
 func (p *planner) Plan(ctx context.Context, m *manifest.Manifest, target Target) (*Plan, error) {
+	ctx, span := telemetry.Start(ctx, "planner.plan", telemetry.String("module", target.Module), telemetry.String("version", target.Version))
+	plan, err := p.plan(ctx, m, target)
+	if plan != nil {
+		span.SetAttributes(telemetry.Int("items", len(plan.Items)))
+	}
+	span.End(err)
+	return plan, err
+}
+
+func (p *planner) plan(ctx context.Context, m *manifest.Manifest, target Target) (*Plan, error) {
 	// Validate target fields
 	if target.Module == "" {
 		return nil, &InvalidTargetError{Field: "module"}
@@ -66,34 +104,93 @@ func (p *planner) Plan(ctx context.Context, m *manifest.Manifest, target Target)
 		return nil, &TargetNotFoundError{ModuleName: target.Module}
 	}
 
+	// Resolve aliases so the target is always the canonical path and entries that
+	// describe the same module under another import path contribute dependents
+	target, dependents, duplicates := resolveTargetAliases(m, targetModule, target)
+
 	// Filter and sort dependents for processing
-	filtered := FilterSkipped(targetModule.Dependents)
+	filtered := FilterSkipped(dependents)
+
+	// defaults.never_touch is an absolute guard: a dependent on the list means
+	// the manifest contradicts itself, so fail rather than quietly skipping it
+	for _, dependent := range filtered {
+		if err := m.NeverTouchDependent(dependent); err != nil {
+			return nil, &PlanningError{Target: target, Err: err}
+		}
+	}
 	canaries := SelectCanaries(filtered)
 	sorted := SortDependents(canaries)
 
+	explain := ExplainEnabled(ctx)
+	var explanations []Explanation
+	// included maps the explanation of each included dependent to its item
+	included := map[int]int{}
+	if explain {
+		explanations = explainFiltered(dependents)
+	}
+
 	// Initialize statistics
 	stats := PlanStats{
-		TotalDependents: len(sorted),
+		TotalDependents:     len(sorted),
+		DuplicateDependents: duplicates,
 	}
 
 	// Process each dependent to create work items
 	var items []WorkItem
 	for _, dependent := range sorted {
-		// Check if dependency update is needed (if checker is configured)
-		if p.checker != nil && p.workspace != "" {
-			needsUpdate, err := p.checker.NeedsUpdate(ctx, dependent, target, p.workspace)
+		trace := &checkTrace{}
+		includeReason := "dependency checking disabled"
+
+		meta := p.repoMetadata(ctx, dependent)
+		if meta != nil && meta.Archived {
+			stats.SkippedArchivedRepos = append(stats.SkippedArchivedRepos, dependent.Repo)
+			if explain {
+				explanations = append(explanations, Explanation{
+					Repo:     dependent.Repo,
+					Decision: DecisionSkipped,
+					Reason:   "repository is archived",
+				})
+			}
+			continue
+		}
+
+		// Check if dependency update is needed (if checker is configured). Submodule
+		// pointers are not recorded in go.mod, so those dependents are always included.
+		if dependent.UpdateStrategy == manifest.UpdateStrategyGitSubmodule {
+			includeReason = "git submodule, version not checked"
+		} else if p.checker != nil && p.workspace != "" {
+			checkCtx := ctx
+			if explain {
+				checkCtx = withCheckTrace(ctx, trace)
+			}
+
+			needsUpdate, err := p.checker.NeedsUpdate(checkCtx, dependent, target, p.workspace)
+			includeReason = "update required"
+			var local *LocallyManagedError
+			if errors.As(err, &local) {
+				// The dependent builds against a local copy; a PR would change nothing
+				stats.SkippedLocalRepos = append(stats.SkippedLocalRepos, dependent.Repo)
+				if explain {
+					explanations = append(explanations, trace.explanation(dependent.Repo, DecisionSkipped, local.Reason()))
+				}
+				continue
+			}
 			if err != nil {
 				// Log error but continue (fail-open for robustness)
 				// In production, use proper logger injection
 				// For now, we fail-open by assuming update is needed
 				stats.CheckErrors++
 				needsUpdate = true
+				trace.setReason("check failed, included for safety: " + err.Error())
 			}
 
 			if !needsUpdate {
 				// Skip this dependent - already up-to-date
 				stats.SkippedUpToDate++
 				stats.SkippedUpToDateRepos = append(stats.SkippedUpToDateRepos, dependent.Repo)
+				if explain {
+					explanations = append(explanations, trace.explanation(dependent.Repo, DecisionSkipped, "up-to-date"))
+				}
 				continue
 			}
 		}
@@ -105,7 +202,7 @@ func (p *planner) Plan(ctx context.Context, m *manifest.Manifest, target Target)
 		)
 
 		if p.workspace != "" {
-			dc := &dependencyChecker{logger: p.logger}
+			dc := &dependencyChecker{logger: p.logger, layout: p.layout}
 			path, err := dc.locateRepository(dependent, p.workspace)
 			if err != nil {
 				if p.logger != nil {
@@ -116,7 +213,7 @@ func (p *planner) Plan(ctx context.Context, m *manifest.Manifest, target Target)
 				}
 			} else {
 				repoPath = path
-				cfg, err := manifest.LoadDependentOverrides(ctx, repoPath, target.Module)
+				cfg, err := loadDependentOverrides(ctx, repoPath, target)
 				if err != nil {
 					if p.logger != nil {
 						p.logger.Warn("failed to load dependent overrides",
@@ -148,26 +245,19 @@ func (p *planner) Plan(ctx context.Context, m *manifest.Manifest, target Target)
 			}
 		}
 
-		// Apply defaults to the dependent, with metadata about original PR config
-		expanded, hadOriginalPR := manifest.ExpandDefaultsWithMetadata(dependent, m.Defaults)
-
-		if moduleDefaults != nil {
-			expanded = applyDependentConfig(expanded, convertModuleConfig(moduleDefaults))
-		}
-
-		if dependentOverride != nil {
-			expanded = applyDependentConfig(expanded, dependentOverride)
-		}
-
-		// If the dependent had no original PR config, use empty templates instead of defaults
-		if !hadOriginalPR {
-			expanded.PR.TitleTemplate = ""
-			expanded.PR.BodyTemplate = ""
-		}
+		// Merge manifest defaults, the manifest entry, and the dependent's own
+		// .cascade.yaml in precedence order
+		expanded, provenance := mergeDependent(dependent, m.Defaults,
+			mergeLayer{source: SourceDependentModule, cfg: convertModuleConfig(moduleDefaults)},
+			mergeLayer{source: SourceDependentOverride, cfg: dependentOverride},
+		)
 
 		// Generate branch name and commit message using templates
 		branchName := GenerateBranchName(target.Module, target.Version)
-		commitMessage := RenderCommitMessage(m.Defaults.CommitTemplate, target)
+		commitMessage, err := RenderCommitMessage(m.Defaults.CommitTemplate, target)
+		if err != nil {
+			return nil, &PlanningError{Target: target, Err: err}
+		}
 
 		// Create work item
 		item := WorkItem{
@@ -177,7 +267,7 @@ func (p *planner) Plan(ctx context.Context, m *manifest.Manifest, target Target)
 			ModulePath:    expanded.ModulePath,
 			SourceModule:  target.Module,
 			SourceVersion: target.Version,
-			Branch:        expanded.Branch,
+			Branch:        baseBranch(expanded),
 			BranchName:    branchName,
 			CommitMessage: commitMessage,
 			Tests:         expanded.Tests,
@@ -186,9 +276,36 @@ func (p *planner) Plan(ctx context.Context, m *manifest.Manifest, target Target)
 			PR:            expanded.PR,
 			Notifications: expanded.Notifications,
 			Env:           expanded.Env,
+			Services:      expanded.Services,
 			Timeout:       expanded.Timeout,
 			Canary:        expanded.Canary,
 			Skip:          false, // Already filtered out Skip=true above
+
+			ConcurrencyGroup: strings.TrimSpace(expanded.ConcurrencyGroup),
+			GoFlags:          strings.TrimSpace(expanded.GoFlags),
+			Credentials:      expanded.Credentials,
+			UpdateStrategy:   strings.TrimSpace(expanded.UpdateStrategy),
+			SubmodulePath:    strings.TrimSpace(expanded.SubmodulePath),
+			BumpStrategy:     strings.TrimSpace(expanded.BumpStrategy),
+			GoWork:           strings.TrimSpace(expanded.GoWork),
+			Provider:         strings.TrimSpace(expanded.Provider),
+			CloneProtocol:    strings.TrimSpace(expanded.CloneProtocol),
+		}
+		if !expanded.Retry.IsZero() {
+			retry := expanded.Retry
+			item.Retry = &retry
+		}
+		if !expanded.Changelog.IsZero() {
+			changelog := expanded.Changelog
+			item.Changelog = &changelog
+		}
+		if item.Branch == "" && meta != nil {
+			item.Branch = meta.DefaultBranch
+		}
+
+		// A dependent's own .cascade.yaml may point the item at another clone URL
+		if err := manifest.NeverTouch(m.Defaults.NeverTouch, item.Repo, item.CloneURL); err != nil {
+			return nil, &PlanningError{Target: target, Err: err}
 		}
 
 		// Validate the work item has all required fields
@@ -213,6 +330,15 @@ func (p *planner) Plan(ctx context.Context, m *manifest.Manifest, target Target)
 		}
 
 		items = append(items, item)
+		if explain {
+			exp := trace.explanation(dependent.Repo, DecisionIncluded, includeReason)
+			exp.Provenance = provenance
+			included[len(explanations)] = len(items) - 1
+			explanations = append(explanations, exp)
+		}
+	}
+	if explain {
+		deferBehindCanaries(explanations, items, included)
 	}
 
 	// Ensure items slice is never nil for consistent JSON marshaling
@@ -222,14 +348,112 @@ func (p *planner) Plan(ctx context.Context, m *manifest.Manifest, target Target)
 
 	// Update statistics
 	stats.WorkItemsCreated = len(items)
+	stats.EstimatedAPICalls = estimateAPICalls(items)
+	if p.history != nil && len(items) > 0 {
+		timings, err := p.history.LoadRepoTimings(target.Module)
+		if err != nil {
+			if p.logger != nil {
+				p.logger.Debug("timing history unavailable", "module", target.Module, "error", err)
+			}
+		} else {
+			stats.EstimatedDuration, stats.TimedItems = estimateDuration(items, timings, p.concurrency)
+		}
+	}
+
+	var waves []Wave
+	if WavesEnabled(ctx) {
+		graph, err := BuildGraph(m, target.Module)
+		if err != nil {
+			return nil, &PlanningError{Target: target, Err: err}
+		}
+		waves = graph.Waves()
+	}
 
 	return &Plan{
-		Target: target,
-		Items:  items,
-		Stats:  stats,
+		Target:       target,
+		Items:        items,
+		Stats:        stats,
+		Explanations: explanations,
+		Waves:        waves,
 	}, nil
 }
 
+// loadDependentOverrides loads the dependent's overrides keyed by the target module
+// path, falling back to overrides keyed by one of its aliases.
+func loadDependentOverrides(ctx context.Context, repoPath string, target Target) (*manifest.DependentConfig, error) {
+	for _, path := range target.Paths() {
+		cfg, err := manifest.LoadDependentOverrides(ctx, repoPath, path)
+		if err != nil || cfg != nil {
+			return cfg, err
+		}
+	}
+	return nil, nil
+}
+
+// resolveTargetAliases canonicalizes target to module's path with the aliases of
+// every manifest entry sharing one of its paths, and returns their dependents with
+// repeated dependents merged (see mergeDuplicateDependents) and the merges. When
+// another module lists module's path as an alias, that module is canonical.
+func resolveTargetAliases(m *manifest.Manifest, module *manifest.Module, target Target) (Target, []manifest.Dependent, []DuplicateDependent) {
+	for i := range m.Modules {
+		candidate := &m.Modules[i]
+		if candidate != module && candidate.Module != module.Module && candidate.HasPath(module.Module) {
+			module = candidate
+			break
+		}
+	}
+
+	resolved := Target{Module: module.Module, Version: target.Version}
+	paths := manifest.ModulePaths(module.Module, append(append([]string(nil), module.Aliases...), target.Aliases...))
+
+	modules := manifest.FindModulesByPaths(m, paths)
+	for _, related := range modules {
+		paths = manifest.ModulePaths(module.Module, append(paths, related.Paths()...))
+	}
+	if len(paths) > 1 {
+		resolved.Aliases = paths[1:]
+	}
+
+	if len(modules) == 0 {
+		modules = []*manifest.Module{module}
+	}
+	dependents, duplicates := mergeDuplicateDependents(modules)
+	return resolved, dependents, duplicates
+}
+
+// repoMetadata returns the hosted metadata of dependent, or nil when preflight is
+// disabled or the lookup fails. Failures never block planning.
+func (p *planner) repoMetadata(ctx context.Context, dependent manifest.Dependent) *repometa.Metadata {
+	if p.metadata == nil || strings.Count(dependent.Repo, "/") != 1 {
+		return nil
+	}
+	meta, err := p.metadata.Get(ctx, dependent.Repo)
+	if err != nil {
+		if p.logger != nil {
+			p.logger.Debug("repository metadata lookup failed",
+				"repo", dependent.Repo,
+				"error", err.Error())
+		}
+		return nil
+	}
+	return meta
+}
+
+// explainFiltered returns explanations for dependents removed before dependency checking.
+func explainFiltered(dependents []manifest.Dependent) []Explanation {
+	var explanations []Explanation
+	for _, dep := range SortDependents(dependents) {
+		if dep.Skip {
+			explanations = append(explanations, Explanation{
+				Repo:     dep.Repo,
+				Decision: DecisionSkipped,
+				Reason:   "filtered: skip is set in manifest",
+			})
+		}
+	}
+	return explanations
+}
+
 // validateWorkItem performs sanity checks on a WorkItem to ensure required fields
 // are populated and numeric values are within reasonable bounds.
 func validateWorkItem(item WorkItem, target Target) error {
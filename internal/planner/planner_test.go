@@ -3,6 +3,7 @@ package planner_test
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -13,6 +14,7 @@ import (
 
 	"github.com/goliatone/cascade/internal/manifest"
 	"github.com/goliatone/cascade/internal/planner"
+	"github.com/goliatone/cascade/pkg/repometa"
 	"github.com/goliatone/cascade/pkg/testsupport"
 )
 
@@ -319,7 +321,7 @@ dependents:
 	target := planner.Target{Module: "github.com/goliatone/go-errors", Version: "v1.2.3"}
 
 	p := planner.New(planner.WithWorkspace(workspace))
-	plan, err := p.Plan(context.Background(), m, target)
+	plan, err := p.Plan(planner.WithExplain(context.Background()), m, target)
 	if err != nil {
 		t.Fatalf("Plan returned error: %v", err)
 	}
@@ -367,6 +369,30 @@ dependents:
 	if loggerItem.Timeout != time.Minute {
 		t.Fatalf("expected override timeout, got %s", loggerItem.Timeout)
 	}
+
+	var provenance planner.Provenance
+	for _, exp := range plan.Explanations {
+		if exp.Repo == "goliatone/go-logger" {
+			provenance = exp.Provenance
+		}
+	}
+	wantProvenance := planner.Provenance{
+		"branch":                      planner.SourceDependentOverride,
+		"tests":                       planner.SourceDependentOverride,
+		"extra_commands":              planner.SourceDependentModule,
+		"labels":                      planner.SourceDependentModule,
+		"notifications.slack_channel": planner.SourceDependentModule,
+		"pr.title":                    planner.SourceDependentModule,
+		"pr.body_template":            planner.SourceManifestDependent,
+		"pr.reviewers":                planner.SourceManifestDefaults,
+		"env.MODULE_ENV":              planner.SourceDependentModule,
+		"env.OVERRIDE_ENV":            planner.SourceDependentOverride,
+		"timeout":                     planner.SourceDependentOverride,
+		"canary":                      planner.SourceManifestDependent,
+	}
+	if !reflect.DeepEqual(provenance, wantProvenance) {
+		t.Fatalf("provenance mismatch\n got: %v\nwant: %v", provenance, wantProvenance)
+	}
 }
 
 func TestPlanner_FallbackWhenOverrideLoadFails(t *testing.T) {
@@ -428,6 +454,60 @@ func TestPlanner_FallbackWhenOverrideLoadFails(t *testing.T) {
 	}
 }
 
+func TestPlanner_OverridePRTemplatesWithoutManifestPR(t *testing.T) {
+	workspace := t.TempDir()
+	dependentDir := filepath.Join(workspace, "go-router")
+	if err := os.MkdirAll(dependentDir, 0o755); err != nil {
+		t.Fatalf("mkdir dependent: %v", err)
+	}
+
+	overrides := `dependents:
+  github.com/goliatone/go-errors:
+    pr:
+      title: "router: bump go-errors"
+`
+	if err := os.WriteFile(filepath.Join(dependentDir, ".cascade.yaml"), []byte(overrides), 0o644); err != nil {
+		t.Fatalf("write dependent manifest: %v", err)
+	}
+
+	m, err := manifest.NewLoader().Load(filepath.Join("..", "manifest", "testdata", "basic.yaml"))
+	if err != nil {
+		t.Fatalf("load manifest: %v", err)
+	}
+
+	plan, err := planner.New(planner.WithWorkspace(workspace)).Plan(planner.WithExplain(context.Background()), m, planner.Target{Module: "github.com/goliatone/go-errors", Version: "v1.2.3"})
+	if err != nil {
+		t.Fatalf("Plan returned error: %v", err)
+	}
+
+	for _, item := range plan.Items {
+		if item.Repo != "goliatone/go-router" {
+			continue
+		}
+		// Default templates are not inherited, but the dependent's own override applies
+		if item.PR.TitleTemplate != "router: bump go-errors" || item.PR.BodyTemplate != "" {
+			t.Fatalf("unexpected PR config: %#v", item.PR)
+		}
+	}
+
+	for _, exp := range plan.Explanations {
+		if exp.Repo != "goliatone/go-router" {
+			continue
+		}
+		if got := exp.Provenance["pr.title"]; got != planner.SourceDependentOverride {
+			t.Fatalf("pr.title provenance = %q", got)
+		}
+		if _, ok := exp.Provenance["pr.body_template"]; ok {
+			t.Fatalf("unexpected provenance for unset body template: %v", exp.Provenance)
+		}
+		if got := exp.Provenance["tests"]; got != "manifest defaults + manifest dependent" {
+			t.Fatalf("tests provenance = %q", got)
+		}
+		return
+	}
+	t.Fatal("expected explanation for go-router")
+}
+
 func TestPlanner_EmptyPlan_ZeroDependents(t *testing.T) {
 	loader := manifest.NewLoader()
 	m, err := loader.Load(filepath.Join("testdata", "empty.yaml"))
@@ -618,6 +698,76 @@ func TestPlanner_WithDependencyChecker_IncludesOutdatedDependents(t *testing.T)
 	}
 }
 
+func TestPlanner_GitSubmoduleDependentsBypassChecker(t *testing.T) {
+	m := &manifest.Manifest{
+		ManifestVersion: 1,
+		Defaults:        manifest.Defaults{Branch: "main"},
+		Modules: []manifest.Module{{
+			Name:   "go-errors",
+			Module: "github.com/goliatone/go-errors",
+			Repo:   "goliatone/go-errors",
+			Dependents: []manifest.Dependent{
+				{Repo: "goliatone/app", Module: "github.com/goliatone/app", ModulePath: "."},
+				{Repo: "goliatone/vendored", Module: "github.com/goliatone/vendored", ModulePath: ".",
+					UpdateStrategy: manifest.UpdateStrategyGitSubmodule, SubmodulePath: "third_party/go-errors"},
+			},
+		}},
+	}
+	target := planner.Target{Module: "github.com/goliatone/go-errors", Version: "v1.2.3"}
+
+	var checked []string
+	checker := &mockDependencyChecker{
+		needsUpdateFunc: func(ctx context.Context, dependent manifest.Dependent, target planner.Target, workspace string) (bool, error) {
+			checked = append(checked, dependent.Repo)
+			return false, nil
+		},
+	}
+
+	p := planner.New(
+		planner.WithDependencyChecker(checker),
+		planner.WithWorkspace(t.TempDir()),
+	)
+
+	plan, err := p.Plan(context.Background(), m, target)
+	if err != nil {
+		t.Fatalf("Plan returned error: %v", err)
+	}
+
+	if !reflect.DeepEqual(checked, []string{"goliatone/app"}) {
+		t.Fatalf("checked = %v, want only goliatone/app", checked)
+	}
+	if len(plan.Items) != 1 {
+		t.Fatalf("expected 1 work item, got %d", len(plan.Items))
+	}
+	item := plan.Items[0]
+	if item.Repo != "goliatone/vendored" || item.UpdateStrategy != manifest.UpdateStrategyGitSubmodule || item.SubmodulePath != "third_party/go-errors" {
+		t.Fatalf("unexpected work item: %+v", item)
+	}
+}
+
+func TestPlanner_CopiesDependentProvider(t *testing.T) {
+	m := &manifest.Manifest{
+		ManifestVersion: 1,
+		Defaults:        manifest.Defaults{Branch: "main"},
+		Modules: []manifest.Module{{
+			Name:   "go-errors",
+			Module: "github.com/goliatone/go-errors",
+			Repo:   "goliatone/go-errors",
+			Dependents: []manifest.Dependent{
+				{Repo: "team/app", Module: "code.example.com/team/app", ModulePath: ".", Provider: manifest.ProviderBitbucket},
+			},
+		}},
+	}
+
+	plan, err := planner.New().Plan(context.Background(), m, planner.Target{Module: "github.com/goliatone/go-errors", Version: "v1.2.3"})
+	if err != nil {
+		t.Fatalf("Plan returned error: %v", err)
+	}
+	if len(plan.Items) != 1 || plan.Items[0].Provider != manifest.ProviderBitbucket {
+		t.Fatalf("items = %+v, want one item for bitbucket", plan.Items)
+	}
+}
+
 func TestPlanner_WithDependencyChecker_FailsOpenOnError(t *testing.T) {
 	loader := manifest.NewLoader()
 	m, err := loader.Load(filepath.Join("..", "manifest", "testdata", "basic.yaml"))
@@ -855,3 +1005,783 @@ func TestPlanner_PlanStatistics(t *testing.T) {
 		}
 	})
 }
+
+func TestPlanner_Explain(t *testing.T) {
+	workspace := t.TempDir()
+	writeGoMod := func(repo, version string) {
+		dir := filepath.Join(workspace, repo)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		content := fmt.Sprintf("module github.com/goliatone/%s\n\ngo 1.21\n\nrequire github.com/goliatone/go-errors %s\n", repo, version)
+		if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(content), 0o644); err != nil {
+			t.Fatalf("write go.mod: %v", err)
+		}
+	}
+	writeGoMod("current", "v1.2.3")
+	writeGoMod("outdated", "v1.0.0")
+
+	m := &manifest.Manifest{
+		ManifestVersion: 1,
+		Defaults:        manifest.Defaults{Branch: "main"},
+		Modules: []manifest.Module{{
+			Name:   "go-errors",
+			Module: "github.com/goliatone/go-errors",
+			Repo:   "goliatone/go-errors",
+			Dependents: []manifest.Dependent{
+				{Repo: "goliatone/outdated", Module: "github.com/goliatone/outdated", ModulePath: "."},
+				{Repo: "goliatone/current", Module: "github.com/goliatone/current", ModulePath: "."},
+				{Repo: "goliatone/ignored", Module: "github.com/goliatone/ignored", ModulePath: ".", Skip: true},
+			},
+		}},
+	}
+	target := planner.Target{Module: "github.com/goliatone/go-errors", Version: "v1.2.3"}
+
+	p := planner.New(
+		planner.WithDependencyChecker(planner.NewDependencyChecker(nil)),
+		planner.WithWorkspace(workspace),
+	)
+
+	plain, err := p.Plan(context.Background(), m, target)
+	if err != nil {
+		t.Fatalf("Plan returned error: %v", err)
+	}
+	if plain.Explanations != nil {
+		t.Fatalf("expected no explanations without explain context, got %v", plain.Explanations)
+	}
+
+	plan, err := p.Plan(planner.WithExplain(context.Background()), m, target)
+	if err != nil {
+		t.Fatalf("Plan returned error: %v", err)
+	}
+
+	want := []planner.Explanation{
+		{Repo: "goliatone/ignored", Decision: planner.DecisionSkipped, Reason: "filtered: skip is set in manifest"},
+		{Repo: "goliatone/current", Decision: planner.DecisionSkipped, Reason: "up-to-date at v1.2.3", Checker: "local", Source: "workspace go.mod", CurrentVersion: "v1.2.3"},
+		{Repo: "goliatone/outdated", Decision: planner.DecisionIncluded, Reason: "requires v1.0.0 -> v1.2.3", Checker: "local", Source: "workspace go.mod", CurrentVersion: "v1.0.0",
+			Provenance: planner.Provenance{"branch": planner.SourceManifestDefaults}},
+	}
+	if !reflect.DeepEqual(plan.Explanations, want) {
+		got, _ := json.MarshalIndent(plan.Explanations, "", "  ")
+		t.Fatalf("explanations mismatch\n got: %s", got)
+	}
+}
+
+func TestPlanner_ExplainDefersBehindCanaries(t *testing.T) {
+	m := &manifest.Manifest{
+		ManifestVersion: 1,
+		Defaults:        manifest.Defaults{Branch: "main"},
+		Modules: []manifest.Module{{
+			Name:   "go-errors",
+			Module: "github.com/goliatone/go-errors",
+			Repo:   "goliatone/go-errors",
+			Dependents: []manifest.Dependent{
+				{Repo: "goliatone/early", Module: "github.com/goliatone/early", ModulePath: ".", Canary: true},
+				{Repo: "goliatone/late", Module: "github.com/goliatone/late", ModulePath: "."},
+			},
+		}},
+	}
+
+	plan, err := planner.New().Plan(planner.WithExplain(context.Background()), m, planner.Target{Module: "github.com/goliatone/go-errors", Version: "v1.2.3"})
+	if err != nil {
+		t.Fatalf("Plan returned error: %v", err)
+	}
+
+	decisions := map[string]planner.Explanation{}
+	for _, exp := range plan.Explanations {
+		decisions[exp.Repo] = exp
+	}
+	if got := decisions["goliatone/early"]; got.Decision != planner.DecisionIncluded {
+		t.Errorf("canary explanation = %+v, want included", got)
+	}
+	late := decisions["goliatone/late"]
+	if late.Decision != planner.DecisionDeferred || late.Reason != "dependency checking disabled; waits for canaries goliatone/early to pass their checks" {
+		t.Errorf("non-canary explanation = %+v, want deferred behind the canary", late)
+	}
+}
+
+func TestPlanner_SkipsLocallyManagedDependents(t *testing.T) {
+	workspace := t.TempDir()
+	writeRepo := func(repo, extra string) {
+		dir := filepath.Join(workspace, repo)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		content := fmt.Sprintf("module github.com/goliatone/%s\n\ngo 1.21\n\nrequire github.com/goliatone/go-errors v1.0.0\n%s", repo, extra)
+		if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(content), 0o644); err != nil {
+			t.Fatalf("write go.mod: %v", err)
+		}
+	}
+	writeRepo("outdated", "")
+	writeRepo("replaced", "\nreplace github.com/goliatone/go-errors => ../go-errors\n")
+
+	m := &manifest.Manifest{
+		ManifestVersion: 1,
+		Defaults:        manifest.Defaults{Branch: "main"},
+		Modules: []manifest.Module{{
+			Name:   "go-errors",
+			Module: "github.com/goliatone/go-errors",
+			Repo:   "goliatone/go-errors",
+			Dependents: []manifest.Dependent{
+				{Repo: "goliatone/outdated", Module: "github.com/goliatone/outdated", ModulePath: "."},
+				{Repo: "goliatone/replaced", Module: "github.com/goliatone/replaced", ModulePath: "."},
+			},
+		}},
+	}
+	target := planner.Target{Module: "github.com/goliatone/go-errors", Version: "v1.2.3"}
+
+	p := planner.New(
+		planner.WithDependencyChecker(planner.NewDependencyChecker(nil)),
+		planner.WithWorkspace(workspace),
+	)
+
+	plan, err := p.Plan(planner.WithExplain(context.Background()), m, target)
+	if err != nil {
+		t.Fatalf("Plan returned error: %v", err)
+	}
+
+	if len(plan.Items) != 1 || plan.Items[0].Repo != "goliatone/outdated" {
+		t.Fatalf("expected only goliatone/outdated to be planned, got %+v", plan.Items)
+	}
+	if !reflect.DeepEqual(plan.Stats.SkippedLocalRepos, []string{"goliatone/replaced"}) {
+		t.Fatalf("SkippedLocalRepos = %v", plan.Stats.SkippedLocalRepos)
+	}
+	if plan.Stats.CheckErrors != 0 {
+		t.Fatalf("CheckErrors = %d, want 0", plan.Stats.CheckErrors)
+	}
+
+	var found bool
+	for _, explanation := range plan.Explanations {
+		if explanation.Repo != "goliatone/replaced" {
+			continue
+		}
+		found = true
+		wantReason := "managed locally (go.mod replace github.com/goliatone/go-errors => ../go-errors)"
+		if explanation.Decision != planner.DecisionSkipped || explanation.Reason != wantReason {
+			t.Fatalf("explanation = %+v, want skipped with reason %q", explanation, wantReason)
+		}
+	}
+	if !found {
+		t.Fatal("expected an explanation for goliatone/replaced")
+	}
+}
+
+func TestApplyDependentManifest_Services(t *testing.T) {
+	item := planner.WorkItem{
+		Repo:         "example/app",
+		SourceModule: "github.com/example/lib",
+		Services:     []manifest.Service{{Name: "db", Image: "postgres:15"}},
+	}
+	depManifest := &manifest.Manifest{
+		Module: &manifest.ModuleConfig{
+			Module: "example/app",
+			Services: []manifest.Service{
+				{Name: "db", Image: "postgres:16", Port: 5432, Env: map[string]string{"POSTGRES_PASSWORD": "x"}},
+				{Name: "cache", Image: "redis:7"},
+			},
+		},
+	}
+
+	got := planner.ApplyDependentManifest(item, depManifest)
+	if len(got.Services) != 2 || got.Services[0].Image != "postgres:16" || got.Services[1].Name != "cache" {
+		t.Fatalf("Services = %#v, want the dependent's module services to replace the planned list", got.Services)
+	}
+
+	depManifest.Module.Services[0].Env["POSTGRES_PASSWORD"] = "changed"
+	if got.Services[0].Env["POSTGRES_PASSWORD"] != "x" {
+		t.Fatal("expected services to be cloned from the dependent manifest")
+	}
+}
+
+func TestPlanner_MergesRetryPolicy(t *testing.T) {
+	m := &manifest.Manifest{
+		ManifestVersion: 1,
+		Defaults:        manifest.Defaults{Branch: "main", Retry: manifest.RetryPolicy{MaxAttempts: 3, Backoff: time.Minute}},
+		Modules: []manifest.Module{{
+			Name:   "go-errors",
+			Module: "github.com/goliatone/go-errors",
+			Repo:   "goliatone/go-errors",
+			Dependents: []manifest.Dependent{
+				{Repo: "example/app", Module: "github.com/example/app", ModulePath: ".", Retry: manifest.RetryPolicy{MaxAttempts: 5}},
+			},
+		}},
+	}
+
+	plan, err := planner.New().Plan(planner.WithExplain(context.Background()), m, planner.Target{Module: "github.com/goliatone/go-errors", Version: "v1.2.3"})
+	if err != nil {
+		t.Fatalf("Plan returned error: %v", err)
+	}
+	want := &manifest.RetryPolicy{MaxAttempts: 5, Backoff: time.Minute}
+	if len(plan.Items) != 1 || !reflect.DeepEqual(plan.Items[0].Retry, want) {
+		t.Fatalf("items = %+v, want retry policy %+v", plan.Items, want)
+	}
+	provenance := plan.Explanations[0].Provenance
+	if provenance["retry.max_attempts"] != planner.SourceManifestDependent || provenance["retry.backoff"] != planner.SourceManifestDefaults {
+		t.Errorf("unexpected retry provenance: %v", provenance)
+	}
+}
+
+func TestApplyDependentManifest_GoFlagsAndCredentials(t *testing.T) {
+	item := planner.WorkItem{
+		Repo:         "example/app",
+		SourceModule: "github.com/example/lib",
+		GoFlags:      "-mod=mod",
+	}
+	depManifest := &manifest.Manifest{
+		Dependents: map[string]manifest.DependentConfig{
+			"github.com/example/lib": {
+				GoFlags:     "-tags=private",
+				Credentials: []manifest.Credential{{Machine: "git.example.com", Login: "ci", PasswordEnv: "EXAMPLE_TOKEN"}},
+			},
+		},
+	}
+
+	got := planner.ApplyDependentManifest(item, depManifest)
+	if got.GoFlags != "-tags=private" {
+		t.Errorf("GoFlags = %q, want the dependent override", got.GoFlags)
+	}
+	if len(got.Credentials) != 1 || got.Credentials[0].Machine != "git.example.com" {
+		t.Fatalf("Credentials = %#v", got.Credentials)
+	}
+
+	depManifest.Dependents["github.com/example/lib"].Credentials[0].Login = "changed"
+	if got.Credentials[0].Login != "ci" {
+		t.Error("expected credentials to be cloned from the dependent manifest")
+	}
+}
+
+func TestApplyDependentManifest(t *testing.T) {
+	item := planner.WorkItem{
+		Repo:         "example/app",
+		SourceModule: "github.com/example/lib",
+		Branch:       "main",
+		Tests:        []manifest.Command{{Cmd: []string{"go", "test", "./..."}}},
+		Labels:       []string{"automation:cascade"},
+		Env:          map[string]string{"A": "1"},
+	}
+	depManifest := &manifest.Manifest{
+		Module: &manifest.ModuleConfig{
+			Module: "example/app",
+			Labels: []string{"module-default"},
+			Env:    map[string]string{"B": "2"},
+		},
+		Dependents: map[string]manifest.DependentConfig{
+			"github.com/example/lib": {
+				Tests:  []manifest.Command{{Cmd: []string{"task", "test"}}},
+				Labels: []string{"deps"},
+			},
+			"github.com/example/other": {
+				Skip: true,
+			},
+		},
+	}
+
+	got := planner.ApplyDependentManifest(item, depManifest)
+
+	if !reflect.DeepEqual(got.Tests, []manifest.Command{{Cmd: []string{"task", "test"}}}) {
+		t.Errorf("Tests = %#v", got.Tests)
+	}
+	if !reflect.DeepEqual(got.Labels, []string{"deps"}) {
+		t.Errorf("Labels = %v, want dependent override to win over module defaults", got.Labels)
+	}
+	if !reflect.DeepEqual(got.Env, map[string]string{"A": "1", "B": "2"}) {
+		t.Errorf("Env = %v", got.Env)
+	}
+	if got.Skip {
+		t.Error("override for another module must not apply")
+	}
+	if got.Branch != "main" {
+		t.Errorf("Branch = %q", got.Branch)
+	}
+
+	if got.Services != nil {
+		t.Errorf("Services = %#v, want none", got.Services)
+	}
+
+	if unchanged := planner.ApplyDependentManifest(item, nil); !reflect.DeepEqual(unchanged, item) {
+		t.Errorf("nil manifest changed item: %#v", unchanged)
+	}
+}
+
+func TestPlanner_CarriesConcurrencyGroup(t *testing.T) {
+	loader := manifest.NewLoader()
+	m, err := loader.Load(filepath.Join("..", "manifest", "testdata", "basic.yaml"))
+	if err != nil {
+		t.Fatalf("load manifest: %v", err)
+	}
+	m.Modules[0].Dependents[0].ConcurrencyGroup = " prod-eu "
+
+	p := planner.New()
+	plan, err := p.Plan(context.Background(), m, planner.Target{Module: "github.com/goliatone/go-errors", Version: "v1.2.3"})
+	if err != nil {
+		t.Fatalf("Plan returned error: %v", err)
+	}
+	if len(plan.Items) == 0 {
+		t.Fatal("expected work items")
+	}
+	if got := plan.Items[0].ConcurrencyGroup; got != "prod-eu" {
+		t.Fatalf("ConcurrencyGroup = %q, want prod-eu", got)
+	}
+	for _, item := range plan.Items[1:] {
+		if item.ConcurrencyGroup != "" {
+			t.Fatalf("%s: unexpected concurrency group %q", item.Repo, item.ConcurrencyGroup)
+		}
+	}
+
+	applied := planner.ApplyDependentManifest(plan.Items[0], &manifest.Manifest{Module: &manifest.ModuleConfig{Module: "example/app"}})
+	if applied.ConcurrencyGroup != "prod-eu" {
+		t.Fatalf("dependent manifest dropped concurrency group, got %q", applied.ConcurrencyGroup)
+	}
+}
+
+type stubTimingHistory struct {
+	timings map[string]time.Duration
+	err     error
+	module  string
+}
+
+func (s *stubTimingHistory) LoadRepoTimings(module string) (map[string]time.Duration, error) {
+	s.module = module
+	return s.timings, s.err
+}
+
+func TestPlanner_EstimatesRuntime(t *testing.T) {
+	loader := manifest.NewLoader()
+	m, err := loader.Load(filepath.Join("..", "manifest", "testdata", "basic.yaml"))
+	if err != nil {
+		t.Fatalf("load manifest: %v", err)
+	}
+	target := planner.Target{Module: "github.com/goliatone/go-errors", Version: "v1.2.3"}
+
+	history := &stubTimingHistory{timings: map[string]time.Duration{
+		"goliatone/go-logger": 3 * time.Minute,
+		"goliatone/go-router": 5 * time.Minute,
+	}}
+	plan, err := planner.New(planner.WithTimingHistory(history), planner.WithConcurrency(1)).Plan(context.Background(), m, target)
+	if err != nil {
+		t.Fatalf("Plan returned error: %v", err)
+	}
+	if history.module != target.Module {
+		t.Fatalf("history queried for %q, want %q", history.module, target.Module)
+	}
+	if plan.Stats.EstimatedDuration != 8*time.Minute || plan.Stats.TimedItems != 2 {
+		t.Fatalf("estimate = %s (%d timed), want 8m0s (2 timed)", plan.Stats.EstimatedDuration, plan.Stats.TimedItems)
+	}
+	if plan.Stats.EstimatedAPICalls == 0 {
+		t.Fatal("expected an API call estimate")
+	}
+
+	plan, err = planner.New(planner.WithTimingHistory(history), planner.WithConcurrency(4)).Plan(context.Background(), m, target)
+	if err != nil {
+		t.Fatalf("Plan returned error: %v", err)
+	}
+	if plan.Stats.EstimatedDuration != 5*time.Minute {
+		t.Fatalf("parallel estimate = %s, want 5m0s", plan.Stats.EstimatedDuration)
+	}
+
+	failing := &stubTimingHistory{err: errors.New("no history")}
+	plan, err = planner.New(planner.WithTimingHistory(failing)).Plan(context.Background(), m, target)
+	if err != nil {
+		t.Fatalf("history errors must not fail planning: %v", err)
+	}
+	if plan.Stats.EstimatedDuration != 0 {
+		t.Fatalf("expected no estimate when history fails, got %s", plan.Stats.EstimatedDuration)
+	}
+}
+
+func TestPlanner_ResolvesModuleAliases(t *testing.T) {
+	loader := manifest.NewLoader()
+	m, err := loader.Load(filepath.Join("..", "manifest", "testdata", "basic.yaml"))
+	if err != nil {
+		t.Fatalf("load manifest: %v", err)
+	}
+
+	// go-errors was renamed; the old entry stays around with one repo not yet migrated
+	legacy := m.Modules[0]
+	legacy.Name = "go-errors-legacy"
+	legacy.Dependents = append([]manifest.Dependent{legacy.Dependents[0]}, manifest.Dependent{
+		Repo:       "goliatone/go-legacy",
+		Module:     "github.com/goliatone/go-legacy",
+		ModulePath: ".",
+		Branch:     "main",
+	})
+	m.Modules[0].Module = "github.com/goliatone/errors"
+	m.Modules[0].Aliases = []string{"github.com/goliatone/go-errors"}
+	m.Modules = append(m.Modules, legacy)
+
+	var checked []planner.Target
+	checker := &mockDependencyChecker{
+		needsUpdateFunc: func(ctx context.Context, dependent manifest.Dependent, target planner.Target, workspace string) (bool, error) {
+			checked = append(checked, target)
+			return true, nil
+		},
+	}
+	p := planner.New(planner.WithDependencyChecker(checker), planner.WithWorkspace(t.TempDir()))
+
+	// Planning by the old path resolves to the canonical module
+	plan, err := p.Plan(context.Background(), m, planner.Target{Module: "github.com/goliatone/go-errors", Version: "v1.2.3"})
+	if err != nil {
+		t.Fatalf("Plan returned error: %v", err)
+	}
+
+	wantTarget := planner.Target{
+		Module:  "github.com/goliatone/errors",
+		Aliases: []string{"github.com/goliatone/go-errors"},
+		Version: "v1.2.3",
+	}
+	if !reflect.DeepEqual(plan.Target, wantTarget) {
+		t.Fatalf("plan target = %#v, want %#v", plan.Target, wantTarget)
+	}
+	for _, target := range checked {
+		if !reflect.DeepEqual(target, wantTarget) {
+			t.Fatalf("checker received target %#v, want %#v", target, wantTarget)
+		}
+	}
+
+	repos := make(map[string]int)
+	for _, item := range plan.Items {
+		repos[item.Repo]++
+	}
+	if len(repos) != len(plan.Items) {
+		t.Fatalf("expected dependents to be deduplicated, got %v", repos)
+	}
+	if repos["goliatone/go-legacy"] != 1 || repos["goliatone/go-logger"] != 1 {
+		t.Fatalf("expected dependents of both entries, got %v", repos)
+	}
+}
+
+func TestPlanner_MergesDuplicateDependents(t *testing.T) {
+	// A central entry and one copied from a module's own manifest list the same
+	// dependent, once by its full repository path
+	m := &manifest.Manifest{
+		ManifestVersion: 1,
+		Modules: []manifest.Module{
+			{
+				Name:   "go-errors",
+				Module: "github.com/goliatone/go-errors",
+				Repo:   "goliatone/go-errors",
+				Dependents: []manifest.Dependent{
+					{Repo: "goliatone/go-logger", Module: "github.com/goliatone/go-logger", ModulePath: ".", Branch: "main"},
+					{Repo: "goliatone/go-router", Module: "github.com/goliatone/go-router", ModulePath: ".", Branch: "main"},
+				},
+			},
+			{
+				Name:   "go-errors-repo",
+				Module: "github.com/goliatone/go-errors",
+				Repo:   "goliatone/go-errors",
+				Dependents: []manifest.Dependent{
+					{Repo: "github.com/goliatone/go-logger", Module: "github.com/goliatone/go-logger", ModulePath: "./", Branch: "develop", Labels: []string{"deps"}},
+					{Repo: "goliatone/go-router", Module: "github.com/goliatone/go-router", ModulePath: "./tools", Branch: "main"},
+				},
+			},
+		},
+	}
+
+	for run := 0; run < 2; run++ {
+		plan, err := planner.New().Plan(context.Background(), m, planner.Target{Module: "github.com/goliatone/go-errors", Version: "v1.2.3"})
+		if err != nil {
+			t.Fatalf("Plan returned error: %v", err)
+		}
+
+		var logger *planner.WorkItem
+		for i := range plan.Items {
+			if plan.Items[i].Module == "github.com/goliatone/go-logger" {
+				if logger != nil {
+					t.Fatalf("items = %+v, want go-logger planned once", plan.Items)
+				}
+				logger = &plan.Items[i]
+			}
+		}
+		if len(plan.Items) != 3 || logger == nil {
+			t.Fatalf("items = %+v, want go-logger once and go-router for each module path", plan.Items)
+		}
+		if logger.Repo != "goliatone/go-logger" || logger.Branch != "main" || !reflect.DeepEqual(logger.Labels, []string{"deps"}) {
+			t.Errorf("go-logger item = %+v, want the first entry's repo and branch with the second entry's labels", logger)
+		}
+
+		want := []planner.DuplicateDependent{{
+			Repo:      "goliatone/go-logger",
+			Entries:   []string{"go-errors", "go-errors-repo"},
+			Filled:    []string{"labels"},
+			Conflicts: []string{"branch"},
+		}}
+		if !reflect.DeepEqual(plan.Stats.DuplicateDependents, want) {
+			t.Fatalf("duplicates = %+v, want %+v", plan.Stats.DuplicateDependents, want)
+		}
+	}
+}
+
+func TestPlanner_RepoMetadataPreflight(t *testing.T) {
+	m := &manifest.Manifest{
+		ManifestVersion: 1,
+		Modules: []manifest.Module{{
+			Name:   "go-errors",
+			Module: "github.com/goliatone/go-errors",
+			Repo:   "goliatone/go-errors",
+			Dependents: []manifest.Dependent{
+				{Repo: "goliatone/active", Module: "github.com/goliatone/active", ModulePath: "."},
+				{Repo: "goliatone/pinned", Module: "github.com/goliatone/pinned", ModulePath: ".", Branch: "release"},
+				{Repo: "goliatone/retired", Module: "github.com/goliatone/retired", ModulePath: "."},
+				{Repo: "goliatone/unknown", Module: "github.com/goliatone/unknown", ModulePath: ".", Branch: "main"},
+			},
+		}},
+	}
+	target := planner.Target{Module: "github.com/goliatone/go-errors", Version: "v1.2.3"}
+
+	var fetched []string
+	metadata := repometa.NewService(repometa.FetcherFunc(func(ctx context.Context, repo string) (*repometa.Metadata, error) {
+		fetched = append(fetched, repo)
+		if repo == "goliatone/unknown" {
+			return nil, fmt.Errorf("api unavailable")
+		}
+		return &repometa.Metadata{Repo: repo, DefaultBranch: "trunk", Archived: repo == "goliatone/retired"}, nil
+	}))
+	p := planner.New(planner.WithRepoMetadata(metadata))
+
+	plan, err := p.Plan(planner.WithExplain(context.Background()), m, target)
+	if err != nil {
+		t.Fatalf("Plan returned error: %v", err)
+	}
+
+	branches := make(map[string]string)
+	for _, item := range plan.Items {
+		branches[item.Repo] = item.Branch
+	}
+	// The repository default branch only fills in when nothing else sets one, and
+	// failed lookups never drop a dependent
+	want := map[string]string{"goliatone/active": "trunk", "goliatone/pinned": "release", "goliatone/unknown": "main"}
+	if !reflect.DeepEqual(branches, want) {
+		t.Fatalf("branches = %v, want %v", branches, want)
+	}
+	if !reflect.DeepEqual(plan.Stats.SkippedArchivedRepos, []string{"goliatone/retired"}) {
+		t.Fatalf("SkippedArchivedRepos = %v", plan.Stats.SkippedArchivedRepos)
+	}
+
+	var archivedExplained bool
+	for _, exp := range plan.Explanations {
+		if exp.Repo == "goliatone/retired" && exp.Decision == planner.DecisionSkipped && exp.Reason == "repository is archived" {
+			archivedExplained = true
+		}
+	}
+	if !archivedExplained {
+		t.Fatalf("expected archived explanation, got %+v", plan.Explanations)
+	}
+
+	// A second plan in the same run is served from the cache
+	if _, err := p.Plan(context.Background(), m, target); err != nil {
+		t.Fatalf("second Plan returned error: %v", err)
+	}
+	if len(fetched) != 5 {
+		t.Fatalf("expected 4 lookups plus a retry of the failed one, got %v", fetched)
+	}
+}
+
+func TestPlanner_NeverTouchDependentFailsPlanning(t *testing.T) {
+	m := &manifest.Manifest{
+		ManifestVersion: 1,
+		Defaults:        manifest.Defaults{Branch: "main", NeverTouch: []string{"acme/frozen"}},
+		Modules: []manifest.Module{{
+			Name:   "lib",
+			Module: "github.com/acme/lib",
+			Repo:   "acme/lib",
+			Dependents: []manifest.Dependent{
+				{Repo: "acme/app", Module: "github.com/acme/app", ModulePath: "."},
+				{Repo: "acme/frozen", Module: "github.com/acme/frozen", ModulePath: "."},
+			},
+		}},
+	}
+
+	_, err := planner.New().Plan(context.Background(), m, planner.Target{Module: "github.com/acme/lib", Version: "v1.0.0"})
+	var neverTouch *manifest.NeverTouchError
+	if !errors.As(err, &neverTouch) {
+		t.Fatalf("expected a NeverTouchError, got %v", err)
+	}
+	if neverTouch.Repo != "acme/frozen" {
+		t.Errorf("repo = %q, want acme/frozen", neverTouch.Repo)
+	}
+
+	// a skipped entry leaves the repository alone and is not a conflict
+	m.Modules[0].Dependents[1].Skip = true
+	plan, err := planner.New().Plan(context.Background(), m, planner.Target{Module: "github.com/acme/lib", Version: "v1.0.0"})
+	if err != nil {
+		t.Fatalf("Plan with the entry skipped: %v", err)
+	}
+	if len(plan.Items) != 1 || plan.Items[0].Repo != "acme/app" {
+		t.Errorf("items = %+v, want only acme/app", plan.Items)
+	}
+}
+
+func TestPlanner_BodyTemplateFiles(t *testing.T) {
+	dir := t.TempDir()
+	content := `manifest_version: 1
+defaults:
+  branch: main
+  pr:
+    body_template_file: templates/pr-body.md.tmpl
+modules:
+  - name: go-errors
+    module: github.com/goliatone/go-errors
+    repo: goliatone/go-errors
+    dependents:
+      - repo: goliatone/go-logger
+        module: github.com/goliatone/go-logger
+        module_path: .
+      - repo: goliatone/go-router
+        module: github.com/goliatone/go-router
+        module_path: .
+        pr:
+          body_template_file: /srv/templates/router.md.tmpl
+`
+	path := filepath.Join(dir, ".cascade.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+	m, err := manifest.NewLoader().Load(path)
+	if err != nil {
+		t.Fatalf("load manifest: %v", err)
+	}
+
+	plan, err := planner.New().Plan(context.Background(), m, planner.Target{Module: "github.com/goliatone/go-errors", Version: "v1.2.3"})
+	if err != nil {
+		t.Fatalf("Plan returned error: %v", err)
+	}
+
+	want := map[string]string{
+		// Defaults are inherited without other PR settings, relative to the manifest
+		"goliatone/go-logger": filepath.Join(dir, "templates", "pr-body.md.tmpl"),
+		"goliatone/go-router": "/srv/templates/router.md.tmpl",
+	}
+	for _, item := range plan.Items {
+		if item.PR.BodyTemplateFile != want[item.Repo] {
+			t.Errorf("%s body template file = %q, want %q", item.Repo, item.PR.BodyTemplateFile, want[item.Repo])
+		}
+	}
+}
+
+func TestPlanner_Changelog(t *testing.T) {
+	m := &manifest.Manifest{
+		ManifestVersion: 1,
+		Defaults: manifest.Defaults{
+			Branch:    "main",
+			Changelog: manifest.Changelog{Format: manifest.ChangelogKeepAChangelog, Section: "Dependencies"},
+		},
+		Modules: []manifest.Module{{
+			Name:   "go-errors",
+			Module: "github.com/goliatone/go-errors",
+			Repo:   "goliatone/go-errors",
+			Dependents: []manifest.Dependent{
+				{Repo: "goliatone/go-logger", Module: "github.com/goliatone/go-logger", ModulePath: ".", Changelog: manifest.Changelog{File: "docs/CHANGELOG.md"}},
+			},
+		}},
+	}
+
+	plan, err := planner.New().Plan(context.Background(), m, planner.Target{Module: "github.com/goliatone/go-errors", Version: "v1.2.3"})
+	if err != nil {
+		t.Fatalf("Plan returned error: %v", err)
+	}
+	want := manifest.Changelog{Format: manifest.ChangelogKeepAChangelog, File: "docs/CHANGELOG.md", Section: "Dependencies"}
+	if got := plan.Items[0].Changelog; got == nil || *got != want {
+		t.Fatalf("Changelog = %+v, want %+v", got, want)
+	}
+}
+
+func TestPlanner_PRBase(t *testing.T) {
+	m := &manifest.Manifest{
+		ManifestVersion: 1,
+		Defaults:        manifest.Defaults{Branch: "main"},
+		Modules: []manifest.Module{{
+			Name:   "go-errors",
+			Module: "github.com/goliatone/go-errors",
+			Repo:   "goliatone/go-errors",
+			Dependents: []manifest.Dependent{
+				{Repo: "goliatone/go-logger", Module: "github.com/goliatone/go-logger", ModulePath: ".", PR: manifest.PRConfig{Base: "release/1.x"}},
+				{Repo: "goliatone/go-router", Module: "github.com/goliatone/go-router", ModulePath: "."},
+			},
+		}},
+	}
+
+	plan, err := planner.New().Plan(context.Background(), m, planner.Target{Module: "github.com/goliatone/go-errors", Version: "v1.2.3"})
+	if err != nil {
+		t.Fatalf("Plan returned error: %v", err)
+	}
+	branches := map[string]string{}
+	for _, item := range plan.Items {
+		branches[item.Repo] = item.Branch
+	}
+	if branches["goliatone/go-logger"] != "release/1.x" || branches["goliatone/go-router"] != "main" {
+		t.Fatalf("branches = %v, want go-logger on release/1.x and go-router on main", branches)
+	}
+
+	// A dependent's own .cascade.yaml setting branch does not undo pr.base
+	item := planner.ApplyDependentManifest(plan.Items[0], &manifest.Manifest{Module: &manifest.ModuleConfig{Branch: "develop"}})
+	if item.Branch != plan.Items[0].Branch {
+		t.Errorf("Branch after dependent manifest = %s, want %s", item.Branch, plan.Items[0].Branch)
+	}
+}
+
+func TestPlanner_PRDeleteBranchOnMerge(t *testing.T) {
+	m := &manifest.Manifest{
+		ManifestVersion: 1,
+		Defaults:        manifest.Defaults{Branch: "main", PR: manifest.PRConfig{DeleteBranchOnMerge: true}},
+		Modules: []manifest.Module{{
+			Name:   "go-errors",
+			Module: "github.com/goliatone/go-errors",
+			Repo:   "goliatone/go-errors",
+			Dependents: []manifest.Dependent{
+				{Repo: "goliatone/go-logger", Module: "github.com/goliatone/go-logger", ModulePath: "."},
+			},
+		}},
+	}
+
+	plan, err := planner.New().Plan(context.Background(), m, planner.Target{Module: "github.com/goliatone/go-errors", Version: "v1.2.3"})
+	if err != nil {
+		t.Fatalf("Plan returned error: %v", err)
+	}
+	if len(plan.Items) != 1 || !plan.Items[0].PR.DeleteBranchOnMerge {
+		t.Fatalf("items = %+v, want pr.delete_branch_on_merge from defaults", plan.Items)
+	}
+
+	// A dependent's own .cascade.yaml can opt in when the manifest does not
+	m.Defaults.PR.DeleteBranchOnMerge = false
+	plan, err = planner.New().Plan(context.Background(), m, planner.Target{Module: "github.com/goliatone/go-errors", Version: "v1.2.3"})
+	if err != nil {
+		t.Fatalf("Plan returned error: %v", err)
+	}
+	item := planner.ApplyDependentManifest(plan.Items[0], &manifest.Manifest{Module: &manifest.ModuleConfig{PR: manifest.PRConfig{DeleteBranchOnMerge: true}}})
+	if !item.PR.DeleteBranchOnMerge {
+		t.Error("DeleteBranchOnMerge after dependent manifest = false, want true")
+	}
+}
+
+func TestPlanner_PRAutoMerge(t *testing.T) {
+	m := &manifest.Manifest{
+		ManifestVersion: 1,
+		Defaults:        manifest.Defaults{Branch: "main", PR: manifest.PRConfig{AutoMerge: "squash"}},
+		Modules: []manifest.Module{{
+			Name:   "go-errors",
+			Module: "github.com/goliatone/go-errors",
+			Repo:   "goliatone/go-errors",
+			Dependents: []manifest.Dependent{
+				{Repo: "goliatone/go-logger", Module: "github.com/goliatone/go-logger", ModulePath: "."},
+				{Repo: "goliatone/go-router", Module: "github.com/goliatone/go-router", ModulePath: ".", PR: manifest.PRConfig{AutoMerge: "rebase"}},
+			},
+		}},
+	}
+
+	plan, err := planner.New().Plan(context.Background(), m, planner.Target{Module: "github.com/goliatone/go-errors", Version: "v1.2.3"})
+	if err != nil {
+		t.Fatalf("Plan returned error: %v", err)
+	}
+	got := map[string]string{}
+	for _, item := range plan.Items {
+		got[item.Repo] = item.PR.AutoMerge
+	}
+	if got["goliatone/go-logger"] != "squash" || got["goliatone/go-router"] != "rebase" {
+		t.Fatalf("auto_merge = %v, want squash from defaults and rebase from the dependent", got)
+	}
+
+	item := planner.ApplyDependentManifest(plan.Items[0], &manifest.Manifest{Module: &manifest.ModuleConfig{PR: manifest.PRConfig{AutoMerge: "merge"}}})
+	if item.PR.AutoMerge != "merge" {
+		t.Errorf("AutoMerge after dependent manifest = %q, want merge", item.PR.AutoMerge)
+	}
+}
@@ -0,0 +1,248 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/goliatone/cascade/internal/manifest"
+	"gopkg.in/yaml.v3"
+)
+
+// ModuleUpdate is one module@version bumped by a work item.
+type ModuleUpdate struct {
+	Module  string
+	Version string
+}
+
+// String returns the update as module@version.
+func (u ModuleUpdate) String() string {
+	return u.Module + "@" + u.Version
+}
+
+// ModuleUpdates returns the modules the item bumps: Updates for an item of a
+// release set, otherwise its source module and version.
+func (w WorkItem) ModuleUpdates() []ModuleUpdate {
+	if len(w.Updates) > 0 {
+		return w.Updates
+	}
+	return []ModuleUpdate{{Module: w.SourceModule, Version: w.SourceVersion}}
+}
+
+// UpdatedModules lists the modules the item bumps as "module@version, ...".
+func (w WorkItem) UpdatedModules() string {
+	return formatModuleUpdates(w.ModuleUpdates())
+}
+
+// ReleaseSet is a release-set file listing modules released together.
+type ReleaseSet struct {
+	Modules []ReleaseSetModule `yaml:"modules"`
+}
+
+// ReleaseSetModule is one module of a release set.
+type ReleaseSetModule struct {
+	Module  string `yaml:"module"`
+	Version string `yaml:"version"`
+}
+
+// ParseTargets parses module@version specs into targets.
+func ParseTargets(specs []string) ([]Target, error) {
+	targets := make([]Target, 0, len(specs))
+	for _, spec := range specs {
+		module, version, ok := strings.Cut(strings.TrimSpace(spec), "@")
+		if !ok || module == "" || version == "" {
+			return nil, fmt.Errorf("invalid module %q: expected module@version", spec)
+		}
+		targets = append(targets, Target{Module: module, Version: version})
+	}
+	return targets, checkReleaseSetTargets(targets)
+}
+
+// LoadReleaseSet reads the targets of a release-set file.
+func LoadReleaseSet(path string) ([]Target, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read release set: %w", err)
+	}
+	var set ReleaseSet
+	if err := yaml.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("parse release set %s: %w", path, err)
+	}
+	if len(set.Modules) == 0 {
+		return nil, fmt.Errorf("release set %s lists no modules", path)
+	}
+
+	targets := make([]Target, 0, len(set.Modules))
+	for i, mod := range set.Modules {
+		if mod.Module == "" || mod.Version == "" {
+			return nil, fmt.Errorf("release set %s: modules[%d] needs a module and a version", path, i)
+		}
+		targets = append(targets, Target{Module: mod.Module, Version: mod.Version})
+	}
+	return targets, checkReleaseSetTargets(targets)
+}
+
+func checkReleaseSetTargets(targets []Target) error {
+	seen := make(map[string]bool, len(targets))
+	for _, target := range targets {
+		if seen[target.Module] {
+			return fmt.Errorf("module %s is listed more than once", target.Module)
+		}
+		seen[target.Module] = true
+	}
+	return nil
+}
+
+// PlanReleaseSet plans the targets of a release set together. Each target is
+// planned on its own, and the work items of a dependent needing several of the
+// targets are merged into one item that bumps all of them on a single branch. The
+// merged item keeps the settings of the first target, in set order, that updates
+// the dependent. The plan's Target is the first target.
+func PlanReleaseSet(ctx context.Context, p Planner, m *manifest.Manifest, targets []Target) (*Plan, error) {
+	if len(targets) == 0 {
+		return nil, &InvalidTargetError{Field: "module"}
+	}
+	if len(targets) == 1 {
+		return p.Plan(ctx, m, targets[0])
+	}
+
+	merged := &Plan{Items: []WorkItem{}}
+	branchName := ReleaseSetBranchName(targets)
+	index := make(map[string]int)
+	skipped := make(map[string]bool)
+
+	for _, target := range targets {
+		plan, err := p.Plan(ctx, m, target)
+		if err != nil {
+			return nil, err
+		}
+		merged.Targets = append(merged.Targets, plan.Target)
+		merged.Explanations = append(merged.Explanations, plan.Explanations...)
+		mergeReleaseSetStats(&merged.Stats, plan.Stats)
+		for _, repo := range plan.Stats.SkippedUpToDateRepos {
+			skipped[repo] = true
+		}
+
+		for _, item := range plan.Items {
+			update := ModuleUpdate{Module: plan.Target.Module, Version: plan.Target.Version}
+			key := item.Repo + "|" + item.ModulePath
+			i, ok := index[key]
+			if !ok {
+				item.Updates = []ModuleUpdate{update}
+				item.BranchName = branchName
+				index[key] = len(merged.Items)
+				merged.Items = append(merged.Items, item)
+				continue
+			}
+
+			existing := &merged.Items[i]
+			if existing.UpdateStrategy == manifest.UpdateStrategyGitSubmodule || item.UpdateStrategy == manifest.UpdateStrategyGitSubmodule {
+				return nil, &PlanningError{Target: target, Err: fmt.Errorf("dependent %s is updated as a git submodule and cannot bump several modules of a release set", item.Repo)}
+			}
+			existing.Updates = append(existing.Updates, update)
+		}
+	}
+
+	for i := range merged.Items {
+		item := &merged.Items[i]
+		if len(item.Updates) > 1 {
+			message, err := RenderReleaseSetCommitMessage(m.Defaults.CommitTemplate, item.Updates)
+			if err != nil {
+				return nil, &PlanningError{Target: targets[0], Err: err}
+			}
+			item.CommitMessage = message
+		}
+		delete(skipped, item.Repo)
+	}
+
+	merged.Target = merged.Targets[0]
+	merged.Stats.WorkItemsCreated = len(merged.Items)
+	merged.Stats.EstimatedAPICalls = estimateAPICalls(merged.Items)
+	merged.Stats.SkippedUpToDateRepos = nil
+	for repo := range skipped {
+		merged.Stats.SkippedUpToDateRepos = append(merged.Stats.SkippedUpToDateRepos, repo)
+	}
+	sort.Strings(merged.Stats.SkippedUpToDateRepos)
+	merged.Stats.SkippedUpToDate = len(merged.Stats.SkippedUpToDateRepos)
+	return merged, nil
+}
+
+// mergeReleaseSetStats adds the statistics of one target's plan to total.
+// Dependents shared by several targets are counted once per target.
+func mergeReleaseSetStats(total *PlanStats, stats PlanStats) {
+	total.TotalDependents += stats.TotalDependents
+	total.SkippedArchivedRepos = appendUnique(total.SkippedArchivedRepos, stats.SkippedArchivedRepos...)
+	total.SkippedLocalRepos = appendUnique(total.SkippedLocalRepos, stats.SkippedLocalRepos...)
+	total.DuplicateDependents = append(total.DuplicateDependents, stats.DuplicateDependents...)
+	total.CheckErrors += stats.CheckErrors
+	if total.CheckStrategy == "" {
+		total.CheckStrategy = stats.CheckStrategy
+	}
+	total.CacheHits += stats.CacheHits
+	total.CacheMisses += stats.CacheMisses
+	total.RemoteChecks += stats.RemoteChecks
+	total.LocalChecks += stats.LocalChecks
+	total.ParallelChecks = total.ParallelChecks || stats.ParallelChecks
+	total.CheckDuration += stats.CheckDuration
+}
+
+func appendUnique(values []string, more ...string) []string {
+	for _, v := range more {
+		found := false
+		for _, existing := range values {
+			if existing == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// ReleaseSetBranchName names the branch of a release set after all of its
+// modules, e.g. auto/lib-a-v1.2.0-lib-b-v2.0.0.
+func ReleaseSetBranchName(targets []Target) string {
+	segments := make([]string, 0, len(targets))
+	for _, target := range targets {
+		segments = append(segments, strings.TrimPrefix(GenerateBranchName(target.Module, target.Version), "auto/"))
+	}
+	return "auto/" + strings.Join(segments, "-")
+}
+
+// RenderReleaseSetCommitMessage renders the commit message of an item bumping
+// several modules. {{ module }} and {{ version }} render the first update and
+// {{ modules }} the whole list. Without a template the message lists every
+// update.
+func RenderReleaseSetCommitMessage(tmpl string, updates []ModuleUpdate) (string, error) {
+	if len(updates) == 0 {
+		return RenderCommitMessage(tmpl, Target{})
+	}
+	parts := make([]string, 0, len(updates))
+	for _, u := range updates {
+		parts = append(parts, u.Module+" to "+u.Version)
+	}
+	fallback := "Update " + strings.Join(parts, ", ")
+	if tmpl == "" {
+		return fallback, nil
+	}
+	return renderCommitTemplate(tmpl, CommitMessageData{
+		Module:  updates[0].Module,
+		Version: updates[0].Version,
+		Modules: formatModuleUpdates(updates),
+		Updates: updates,
+	}, fallback)
+}
+
+// formatModuleUpdates lists updates as comma separated module@version pairs.
+func formatModuleUpdates(updates []ModuleUpdate) string {
+	parts := make([]string, 0, len(updates))
+	for _, u := range updates {
+		parts = append(parts, u.String())
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,151 @@
+package planner_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/goliatone/cascade/internal/manifest"
+	"github.com/goliatone/cascade/internal/planner"
+)
+
+func releaseSetManifest() *manifest.Manifest {
+	return &manifest.Manifest{
+		ManifestVersion: 1,
+		Defaults:        manifest.Defaults{Branch: "main", CommitTemplate: "chore: bump {{ modules }}"},
+		Modules: []manifest.Module{
+			{
+				Name:   "lib-a",
+				Module: "github.com/acme/lib-a",
+				Repo:   "acme/lib-a",
+				Dependents: []manifest.Dependent{
+					{Repo: "acme/both", Module: "github.com/acme/both", ModulePath: "."},
+					{Repo: "acme/only-a", Module: "github.com/acme/only-a", ModulePath: "."},
+				},
+			},
+			{
+				Name:   "lib-b",
+				Module: "github.com/acme/lib-b",
+				Repo:   "acme/lib-b",
+				Dependents: []manifest.Dependent{
+					{Repo: "acme/both", Module: "github.com/acme/both", ModulePath: "."},
+					{Repo: "acme/only-b", Module: "github.com/acme/only-b", ModulePath: "."},
+				},
+			},
+		},
+	}
+}
+
+func TestPlanReleaseSet_MergesDependentsAcrossTargets(t *testing.T) {
+	targets := []planner.Target{
+		{Module: "github.com/acme/lib-a", Version: "v1.2.0"},
+		{Module: "github.com/acme/lib-b", Version: "v2.0.0"},
+	}
+
+	plan, err := planner.PlanReleaseSet(context.Background(), planner.New(), releaseSetManifest(), targets)
+	if err != nil {
+		t.Fatalf("PlanReleaseSet returned error: %v", err)
+	}
+
+	if !reflect.DeepEqual(plan.Targets, targets) || !reflect.DeepEqual(plan.Target, targets[0]) {
+		t.Fatalf("unexpected plan targets: %+v (target %+v)", plan.Targets, plan.Target)
+	}
+	if len(plan.Items) != 3 || plan.Stats.WorkItemsCreated != 3 {
+		t.Fatalf("expected 3 work items, got %d", len(plan.Items))
+	}
+
+	byRepo := make(map[string]planner.WorkItem)
+	for _, item := range plan.Items {
+		if item.BranchName != "auto/lib-a-v1.2.0-lib-b-v2.0.0" {
+			t.Errorf("%s: unexpected branch %q", item.Repo, item.BranchName)
+		}
+		byRepo[item.Repo] = item
+	}
+
+	both := byRepo["acme/both"]
+	wantUpdates := []planner.ModuleUpdate{
+		{Module: "github.com/acme/lib-a", Version: "v1.2.0"},
+		{Module: "github.com/acme/lib-b", Version: "v2.0.0"},
+	}
+	if !reflect.DeepEqual(both.Updates, wantUpdates) {
+		t.Fatalf("unexpected updates for acme/both: %+v", both.Updates)
+	}
+	if both.SourceModule != "github.com/acme/lib-a" || both.SourceVersion != "v1.2.0" {
+		t.Fatalf("expected the first target as source, got %s@%s", both.SourceModule, both.SourceVersion)
+	}
+	if both.CommitMessage != "chore: bump github.com/acme/lib-a@v1.2.0, github.com/acme/lib-b@v2.0.0" {
+		t.Fatalf("unexpected commit message %q", both.CommitMessage)
+	}
+
+	onlyB := byRepo["acme/only-b"]
+	if onlyB.UpdatedModules() != "github.com/acme/lib-b@v2.0.0" {
+		t.Fatalf("unexpected updates for acme/only-b: %s", onlyB.UpdatedModules())
+	}
+	if onlyB.CommitMessage != "chore: bump github.com/acme/lib-b@v2.0.0" {
+		t.Fatalf("unexpected commit message %q", onlyB.CommitMessage)
+	}
+}
+
+func TestPlanReleaseSet_RejectsSubmoduleDependentOfSeveralTargets(t *testing.T) {
+	m := releaseSetManifest()
+	for i := range m.Modules {
+		m.Modules[i].Dependents[0].UpdateStrategy = manifest.UpdateStrategyGitSubmodule
+	}
+	targets := []planner.Target{
+		{Module: "github.com/acme/lib-a", Version: "v1.2.0"},
+		{Module: "github.com/acme/lib-b", Version: "v2.0.0"},
+	}
+
+	if _, err := planner.PlanReleaseSet(context.Background(), planner.New(), m, targets); err == nil {
+		t.Fatal("expected a submodule dependent of both targets to be rejected")
+	}
+}
+
+func TestParseTargets(t *testing.T) {
+	targets, err := planner.ParseTargets([]string{"github.com/acme/lib-a@v1.2.0", " github.com/acme/lib-b@v2.0.0 "})
+	if err != nil {
+		t.Fatalf("ParseTargets returned error: %v", err)
+	}
+	if len(targets) != 2 || targets[1].Module != "github.com/acme/lib-b" || targets[1].Version != "v2.0.0" {
+		t.Fatalf("unexpected targets: %+v", targets)
+	}
+
+	for _, specs := range [][]string{
+		{"github.com/acme/lib-a"},
+		{"@v1.0.0"},
+		{"github.com/acme/lib-a@v1.0.0", "github.com/acme/lib-a@v1.1.0"},
+	} {
+		if _, err := planner.ParseTargets(specs); err == nil {
+			t.Errorf("expected %v to be rejected", specs)
+		}
+	}
+}
+
+func TestLoadReleaseSet(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "release-set.yaml")
+	content := "modules:\n  - module: github.com/acme/lib-a\n    version: v1.2.0\n  - module: github.com/acme/lib-b\n    version: v2.0.0\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write release set: %v", err)
+	}
+
+	targets, err := planner.LoadReleaseSet(path)
+	if err != nil {
+		t.Fatalf("LoadReleaseSet returned error: %v", err)
+	}
+	want := []planner.Target{
+		{Module: "github.com/acme/lib-a", Version: "v1.2.0"},
+		{Module: "github.com/acme/lib-b", Version: "v2.0.0"},
+	}
+	if !reflect.DeepEqual(targets, want) {
+		t.Fatalf("targets = %+v, want %+v", targets, want)
+	}
+
+	if err := os.WriteFile(path, []byte("modules:\n  - module: github.com/acme/lib-a\n"), 0o644); err != nil {
+		t.Fatalf("write release set: %v", err)
+	}
+	if _, err := planner.LoadReleaseSet(path); err == nil {
+		t.Fatal("expected a module without a version to be rejected")
+	}
+}
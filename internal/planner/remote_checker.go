@@ -2,11 +2,16 @@ package planner
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
 	"sync"
 	"time"
 
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
 	"github.com/goliatone/cascade/internal/manifest"
+	"github.com/goliatone/cascade/pkg/retry"
 	"golang.org/x/mod/modfile"
 )
 
@@ -34,13 +39,62 @@ func NewRemoteDependencyChecker(opts CheckOptions, logger Logger) RemoteDependen
 
 	checker := &remoteDependencyChecker{
 		cache:   newDependencyCache(opts.CacheTTL),
-		gitOps:  newGitOperations(opts.Timeout),
+		gitOps:  newGitOperations(opts.Timeout, opts.RepoURLs),
 		logger:  logger,
 		options: opts,
 	}
 	return checker
 }
 
+// fetchGoMod fetches go.mod, retrying transient failures such as dropped
+// connections and timed out clones as configured by Retries and RetryDelay.
+func (r *remoteDependencyChecker) fetchGoMod(ctx context.Context, dependent manifest.Dependent, cloneURL, ref string) (string, error) {
+	repo := dependent.Repo
+	var content string
+	err := retry.Do(ctx, retry.Policy{
+		MaxAttempts: r.options.Retries + 1,
+		Delay:       r.options.RetryDelay,
+		Jitter:      0.2,
+		Retryable:   isTransientFetchError,
+		OnRetry: func(attempt int, err error, delay time.Duration) {
+			if r.logger != nil {
+				r.logger.Debug("fetching go.mod failed, retrying",
+					"repo", repo,
+					"attempt", attempt,
+					"delay", delay.String(),
+					"error", err.Error())
+			}
+		},
+	}, func(ctx context.Context) error {
+		var err error
+		content, err = r.fetchGoModOnce(ctx, dependent, cloneURL, ref)
+		return err
+	})
+	return content, err
+}
+
+// fetchGoModOnce fetches go.mod, with the dependent's credentials when it has
+// any, so private hosts reached over git authenticate like the dependent's
+// go commands do.
+func (r *remoteDependencyChecker) fetchGoModOnce(ctx context.Context, dependent manifest.Dependent, cloneURL, ref string) (string, error) {
+	if ops, ok := r.gitOps.(credentialedGitOperations); ok && len(dependent.Credentials) > 0 {
+		return ops.fetchGoModWithCredentials(ctx, cloneURL, ref, dependent.Credentials)
+	}
+	return r.gitOps.fetchGoMod(ctx, cloneURL, ref)
+}
+
+// isTransientFetchError reports whether a failed go.mod fetch may succeed if
+// retried. Repositories, refs or files that do not exist and rejected
+// credentials fail the same way every time.
+func isTransientFetchError(err error) bool {
+	return !errors.Is(err, transport.ErrRepositoryNotFound) &&
+		!errors.Is(err, transport.ErrEmptyRemoteRepository) &&
+		!errors.Is(err, transport.ErrAuthenticationRequired) &&
+		!errors.Is(err, transport.ErrAuthorizationFailed) &&
+		!errors.Is(err, plumbing.ErrReferenceNotFound) &&
+		!errors.Is(err, os.ErrNotExist)
+}
+
 // NeedsUpdate determines if a dependent repository needs an update to the target version.
 // It fetches the go.mod file from the remote repository (with caching) and compares versions.
 //
@@ -58,7 +112,7 @@ func (r *remoteDependencyChecker) NeedsUpdate(
 	workspace string, // Ignored in remote mode
 ) (bool, error) {
 	// 1. Parse clone URL from dependent
-	cloneURL, err := r.gitOps.parseCloneURL(dependent)
+	cloneURL, err := r.gitOps.parseCloneURL(ctx, dependent)
 	if err != nil {
 		if r.logger != nil {
 			r.logger.Debug("failed to parse clone URL, assuming update needed",
@@ -69,7 +123,7 @@ func (r *remoteDependencyChecker) NeedsUpdate(
 	}
 
 	// 2. Determine ref (branch/tag)
-	ref := dependent.Branch
+	ref := baseBranch(dependent)
 	if ref == "" {
 		ref = "main" // Default branch
 	}
@@ -92,6 +146,7 @@ func (r *remoteDependencyChecker) NeedsUpdate(
 						"repo", dependent.Repo,
 						"module", target.Module)
 				}
+				recordCheck(ctx, string(CheckStrategyRemote), checkSourceCache, "", "dependency not present in go.mod")
 				return false, nil
 			}
 
@@ -101,6 +156,8 @@ func (r *remoteDependencyChecker) NeedsUpdate(
 				return true, fmt.Errorf("compare versions: %w", err)
 			}
 
+			recordCheck(ctx, string(CheckStrategyRemote), checkSourceCache, currentVersion, versionReason(needsUpdate, currentVersion, target.Version))
+
 			if r.logger != nil {
 				r.logger.Info("remote dependency check result (cached)",
 					"repo", dependent.Repo,
@@ -124,7 +181,7 @@ func (r *remoteDependencyChecker) NeedsUpdate(
 	}
 
 	startTime := time.Now()
-	goModContent, err := r.gitOps.fetchGoMod(ctx, cloneURL, ref)
+	goModContent, err := r.fetchGoMod(ctx, dependent, cloneURL, ref)
 	duration := time.Since(startTime)
 
 	if err != nil {
@@ -158,13 +215,27 @@ func (r *remoteDependencyChecker) NeedsUpdate(
 		return true, fmt.Errorf("parse go.mod: %w", err)
 	}
 
-	// Cache the dependencies for future lookups
+	// Dependents that replace the target with a local directory build against their
+	// own copy. Only go.mod is fetched, so go.work files are not considered here.
+	if path, dir, ok := localReplace(parseGoModReplaces(goModContent), target.Paths()); ok {
+		local := &LocallyManagedError{Dependent: dependent.Repo, Module: path, Source: LocalSourceReplace, Dir: dir}
+		if r.logger != nil {
+			r.logger.Info("dependency managed locally, skipping update",
+				"repo", dependent.Repo,
+				"module", path,
+				"dir", dir)
+		}
+		recordCheck(ctx, string(CheckStrategyRemote), checkSourceShallowCopy, "", local.Reason())
+		return false, local
+	}
+
+	// Cache the dependencies for future lookups, honoring per-dependent TTLs
 	if r.options.CacheEnabled {
-		r.cache.Set(cloneURL, ref, deps)
+		r.cache.SetWithTTL(cloneURL, ref, deps, dependent.CheckCacheTTL)
 	}
 
-	// 6. Extract current version of target module
-	currentVersion, exists := deps[target.Module]
+	// 6. Extract current version of target module or one of its aliases
+	_, currentVersion, exists := target.lookupDependency(deps)
 	if !exists {
 		// Dependency not present in go.mod - no update needed
 		if r.logger != nil {
@@ -172,6 +243,7 @@ func (r *remoteDependencyChecker) NeedsUpdate(
 				"repo", dependent.Repo,
 				"module", target.Module)
 		}
+		recordCheck(ctx, string(CheckStrategyRemote), checkSourceShallowCopy, "", "dependency not present in go.mod")
 		return false, nil
 	}
 
@@ -181,6 +253,8 @@ func (r *remoteDependencyChecker) NeedsUpdate(
 		return true, fmt.Errorf("compare versions: %w", err)
 	}
 
+	recordCheck(ctx, string(CheckStrategyRemote), checkSourceShallowCopy, currentVersion, versionReason(needsUpdate, currentVersion, target.Version))
+
 	if r.logger != nil {
 		r.logger.Info("remote dependency check result",
 			"repo", dependent.Repo,
@@ -224,7 +298,7 @@ func (r *remoteDependencyChecker) Warm(
 			sem <- struct{}{}        // Acquire semaphore
 			defer func() { <-sem }() // Release semaphore
 
-			cloneURL, err := r.gitOps.parseCloneURL(dependent)
+			cloneURL, err := r.gitOps.parseCloneURL(ctx, dependent)
 			if err != nil {
 				errMu.Lock()
 				errs = append(errs, fmt.Errorf("parse clone URL for %s: %w", dependent.Repo, err))
@@ -232,12 +306,12 @@ func (r *remoteDependencyChecker) Warm(
 				return
 			}
 
-			ref := dependent.Branch
+			ref := baseBranch(dependent)
 			if ref == "" {
 				ref = "main"
 			}
 
-			goModContent, err := r.gitOps.fetchGoMod(ctx, cloneURL, ref)
+			goModContent, err := r.fetchGoModOnce(ctx, dependent, cloneURL, ref)
 			if err != nil {
 				if r.logger != nil {
 					r.logger.Debug("warm cache failed for repository",
@@ -259,7 +333,7 @@ func (r *remoteDependencyChecker) Warm(
 				return
 			}
 
-			r.cache.Set(cloneURL, ref, deps)
+			r.cache.SetWithTTL(cloneURL, ref, deps, dependent.CheckCacheTTL)
 
 			if r.logger != nil {
 				r.logger.Debug("cached dependencies for repository",
@@ -324,6 +398,16 @@ func (r *remoteDependencyChecker) LogCacheStats() {
 		"hit_rate", hitRate)
 }
 
+// parseGoModReplaces returns the replace directives of go.mod content, or nil when
+// it does not parse.
+func parseGoModReplaces(content string) []*modfile.Replace {
+	f, err := modfile.Parse("go.mod", []byte(content), nil)
+	if err != nil {
+		return nil
+	}
+	return f.Replace
+}
+
 // parseGoModContentAndExtractDeps parses go.mod content and extracts all dependencies.
 // Returns a map of module path -> version for all dependencies.
 func parseGoModContentAndExtractDeps(content string) (map[string]string, error) {
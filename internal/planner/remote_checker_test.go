@@ -2,11 +2,13 @@ package planner
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/go-git/go-git/v5/plumbing/transport"
 	"github.com/goliatone/cascade/internal/manifest"
 )
 
@@ -584,6 +586,49 @@ replace github.com/goliatone/go-errors => github.com/goliatone/go-errors v0.7.0
 	}
 }
 
+func TestRemoteDependencyChecker_NeedsUpdate_LocalReplaceDirective(t *testing.T) {
+	mockGit := &mockGitOperations{
+		parseCloneURLFunc: defaultParseCloneURL,
+		fetchGoModFunc: func(ctx context.Context, cloneURL, ref string) (string, error) {
+			return `module github.com/goliatone/go-crud
+
+go 1.21
+
+require github.com/goliatone/go-errors v0.8.0
+
+replace github.com/goliatone/go-errors => ../go-errors
+`, nil
+		},
+	}
+
+	checker := &remoteDependencyChecker{
+		cache:  newDependencyCache(5 * time.Minute),
+		gitOps: mockGit,
+		logger: &mockLogger{},
+		options: CheckOptions{
+			CacheEnabled: true,
+			CacheTTL:     5 * time.Minute,
+			Timeout:      30 * time.Second,
+		},
+	}
+
+	dependent := manifest.Dependent{Repo: "goliatone/go-crud", Branch: "main"}
+	target := Target{Module: "github.com/goliatone/go-errors", Version: "v0.9.0"}
+
+	needsUpdate, err := checker.NeedsUpdate(context.Background(), dependent, target, "")
+
+	if needsUpdate {
+		t.Error("expected needsUpdate=false for a locally replaced dependency")
+	}
+	var local *LocallyManagedError
+	if !errors.As(err, &local) {
+		t.Fatalf("expected LocallyManagedError, got: %v", err)
+	}
+	if local.Source != LocalSourceReplace || local.Dir != "../go-errors" {
+		t.Errorf("LocallyManagedError = %+v, want go.mod replace to ../go-errors", local)
+	}
+}
+
 func TestRemoteDependencyChecker_NeedsUpdate_DefaultBranch(t *testing.T) {
 	var capturedRef string
 	mockGit := &mockGitOperations{
@@ -900,3 +945,50 @@ func TestNewRemoteDependencyChecker_DefaultOptions(t *testing.T) {
 		t.Error("expected default Timeout to be set")
 	}
 }
+
+func TestRemoteDependencyChecker_NeedsUpdate_RetriesTransientFetchErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		fetchErr   error
+		wantFetch  int
+		wantUpdate bool
+		wantErr    bool
+	}{
+		{name: "transient error is retried", fetchErr: errors.New("connection reset by peer"), wantFetch: 2},
+		{name: "missing repository is not retried", fetchErr: fmt.Errorf("shallow clone: %w", transport.ErrRepositoryNotFound), wantFetch: 1, wantUpdate: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetches := 0
+			checker := &remoteDependencyChecker{
+				cache: newDependencyCache(5 * time.Minute),
+				gitOps: &mockGitOperations{
+					parseCloneURLFunc: defaultParseCloneURL,
+					fetchGoModFunc: func(ctx context.Context, cloneURL, ref string) (string, error) {
+						fetches++
+						if fetches == 1 {
+							return "", tt.fetchErr
+						}
+						return "module example.com/app\n\nrequire example.com/lib v1.0.0\n", nil
+					},
+				},
+				logger:  &mockLogger{},
+				options: CheckOptions{Retries: 2, RetryDelay: time.Millisecond},
+			}
+
+			needsUpdate, err := checker.NeedsUpdate(context.Background(),
+				manifest.Dependent{Repo: "example/app"},
+				Target{Module: "example.com/lib", Version: "v1.0.0"}, "")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NeedsUpdate error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if needsUpdate != tt.wantUpdate {
+				t.Errorf("needsUpdate = %v, want %v", needsUpdate, tt.wantUpdate)
+			}
+			if fetches != tt.wantFetch {
+				t.Errorf("fetches = %d, want %d", fetches, tt.wantFetch)
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/goliatone/cascade/internal/manifest"
+	"github.com/goliatone/cascade/pkg/repourl"
+	"golang.org/x/mod/semver"
+)
+
+// PlanExpired reports whether a plan made at created is older than maxAge at
+// now. A non-positive maxAge, or an unknown creation time, never expires.
+func PlanExpired(created time.Time, maxAge time.Duration, now time.Time) bool {
+	if maxAge <= 0 || created.IsZero() {
+		return false
+	}
+	return now.Sub(created) > maxAge
+}
+
+// BranchChecker reports whether a branch exists in the remote of a work item's
+// repository.
+type BranchChecker interface {
+	BranchExists(ctx context.Context, item WorkItem, branch string) (bool, error)
+}
+
+// LatestVersionFunc returns the newest released version of module.
+type LatestVersionFunc func(ctx context.Context, module string) (string, error)
+
+// Invalidation records why a planned work item can no longer be executed.
+type Invalidation struct {
+	Item   WorkItem
+	Reason string
+}
+
+// RevalidateOption configures the checks Revalidate runs.
+type RevalidateOption func(*revalidator)
+
+// WithBranchCheck invalidates items whose base branch no longer exists.
+func WithBranchCheck(branches BranchChecker) RevalidateOption {
+	return func(r *revalidator) {
+		r.branches = branches
+	}
+}
+
+// WithUpToDateCheck invalidates items whose dependent already requires every
+// module the item bumps, as reported by checker.
+func WithUpToDateCheck(checker DependencyChecker, workspace string) RevalidateOption {
+	return func(r *revalidator) {
+		r.checker = checker
+		r.workspace = workspace
+	}
+}
+
+// WithNewerVersionCheck invalidates items bumping a module for which latest
+// reports a newer release than the planned version.
+func WithNewerVersionCheck(latest LatestVersionFunc) RevalidateOption {
+	return func(r *revalidator) {
+		r.latest = latest
+	}
+}
+
+type revalidator struct {
+	branches  BranchChecker
+	checker   DependencyChecker
+	workspace string
+	latest    LatestVersionFunc
+
+	// versions caches the latest version of each module for one run
+	versions map[string]string
+}
+
+// Revalidate re-checks planned work items against the current remote state,
+// for plans that may have gone stale since they were made. It returns the items
+// that are still valid and the invalidated ones, in plan order. A check that
+// fails keeps the item, so an unreachable remote does not drop work; failures
+// are returned as warnings.
+func Revalidate(ctx context.Context, items []WorkItem, opts ...RevalidateOption) ([]WorkItem, []Invalidation, []string) {
+	r := &revalidator{versions: make(map[string]string)}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	valid := make([]WorkItem, 0, len(items))
+	var (
+		invalid  []Invalidation
+		warnings []string
+	)
+	for _, item := range items {
+		reason, itemWarnings := r.check(ctx, item)
+		warnings = append(warnings, itemWarnings...)
+		if reason != "" {
+			invalid = append(invalid, Invalidation{Item: item, Reason: reason})
+			continue
+		}
+		valid = append(valid, item)
+	}
+	return valid, invalid, warnings
+}
+
+// check returns why item is invalid, or "" when it is still valid.
+func (r *revalidator) check(ctx context.Context, item WorkItem) (string, []string) {
+	var warnings []string
+	warn := func(check string, err error) {
+		warnings = append(warnings, fmt.Sprintf("%s: %s check failed: %v", item.Repo, check, err))
+	}
+
+	if r.branches != nil && item.Branch != "" {
+		exists, err := r.branches.BranchExists(ctx, item, item.Branch)
+		switch {
+		case err != nil:
+			warn("branch", err)
+		case !exists:
+			return fmt.Sprintf("base branch %s no longer exists", item.Branch), warnings
+		}
+	}
+
+	if r.latest != nil {
+		for _, update := range item.ModuleUpdates() {
+			latest, err := r.latestVersion(ctx, update.Module)
+			if err != nil {
+				warn("version", err)
+				continue
+			}
+			if semver.IsValid(latest) && semver.IsValid(update.Version) && semver.Compare(latest, update.Version) > 0 {
+				return fmt.Sprintf("%s %s was released after the plan targeted %s", update.Module, latest, update.Version), warnings
+			}
+		}
+	}
+
+	if r.checker != nil {
+		dependent := manifest.Dependent{
+			Repo:       item.Repo,
+			CloneURL:   item.CloneURL,
+			Module:     item.Module,
+			ModulePath: item.ModulePath,
+			Branch:     item.Branch,
+			// Remote checks clone with the credentials the item's go commands use
+			Credentials:   item.Credentials,
+			CloneProtocol: item.CloneProtocol,
+		}
+		current := 0
+		updates := item.ModuleUpdates()
+		for _, update := range updates {
+			needsUpdate, err := r.checker.NeedsUpdate(ctx, dependent, Target{Module: update.Module, Version: update.Version}, r.workspace)
+			if err != nil {
+				warn("dependency", err)
+				break
+			}
+			if !needsUpdate {
+				current++
+			}
+		}
+		if current == len(updates) {
+			return fmt.Sprintf("already requires %s", item.UpdatedModules()), warnings
+		}
+	}
+
+	return "", warnings
+}
+
+func (r *revalidator) latestVersion(ctx context.Context, module string) (string, error) {
+	if version, ok := r.versions[module]; ok {
+		return version, nil
+	}
+	version, err := r.latest(ctx, module)
+	if err != nil {
+		return "", err
+	}
+	r.versions[module] = version
+	return version, nil
+}
+
+// NewRemoteBranchChecker returns a BranchChecker that lists the branches of a
+// work item's remote, building clone URLs with urls like the remote dependency
+// checker does. Each listing is bounded by timeout.
+func NewRemoteBranchChecker(timeout time.Duration, urls *repourl.Resolver) BranchChecker {
+	ops, _ := newGitOperations(timeout, urls).(*gitOperationsImpl)
+	return &remoteBranchChecker{ops: ops}
+}
+
+type remoteBranchChecker struct {
+	ops *gitOperationsImpl
+}
+
+// BranchExists lists the remote's refs and looks for refs/heads/<branch>.
+func (c *remoteBranchChecker) BranchExists(ctx context.Context, item WorkItem, branch string) (bool, error) {
+	cloneURL, err := c.ops.parseCloneURL(ctx, manifest.Dependent{Repo: item.Repo, CloneURL: item.CloneURL, CloneProtocol: item.CloneProtocol})
+	if err != nil {
+		return false, fmt.Errorf("parse clone URL: %w", err)
+	}
+	auth, err := c.ops.authMethod(cloneURL)
+	if err != nil {
+		return false, fmt.Errorf("setup auth: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.ops.timeout)
+	defer cancel()
+	remote := git.NewRemote(memory.NewStorage(), &gitconfig.RemoteConfig{Name: "origin", URLs: []string{cloneURL}})
+	refs, err := remote.ListContext(ctx, &git.ListOptions{Auth: auth})
+	if err != nil {
+		return false, fmt.Errorf("list remote branches: %w", err)
+	}
+
+	want := plumbing.NewBranchReferenceName(strings.TrimPrefix(branch, "refs/heads/"))
+	for _, ref := range refs {
+		if ref.Name() == want {
+			return true, nil
+		}
+	}
+	return false, nil
+}
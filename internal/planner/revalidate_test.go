@@ -0,0 +1,96 @@
+package planner_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/goliatone/cascade/internal/manifest"
+	"github.com/goliatone/cascade/internal/planner"
+)
+
+type stubBranchChecker map[string]bool
+
+func (s stubBranchChecker) BranchExists(_ context.Context, item planner.WorkItem, branch string) (bool, error) {
+	exists, ok := s[item.Repo]
+	if !ok {
+		return false, errors.New("remote unreachable")
+	}
+	return exists, nil
+}
+
+// upToDateChecker reports repos in current as already requiring every target.
+type upToDateChecker map[string]bool
+
+func (c upToDateChecker) NeedsUpdate(_ context.Context, dependent manifest.Dependent, _ planner.Target, _ string) (bool, error) {
+	return !c[dependent.Repo], nil
+}
+
+func TestRevalidate(t *testing.T) {
+	item := func(repo string) planner.WorkItem {
+		return planner.WorkItem{Repo: repo, Branch: "main", SourceModule: "github.com/acme/lib", SourceVersion: "v1.2.0"}
+	}
+	items := []planner.WorkItem{item("acme/valid"), item("acme/no-branch"), item("acme/bumped"), item("acme/unreachable")}
+
+	branches := stubBranchChecker{"acme/valid": true, "acme/no-branch": false, "acme/bumped": true}
+	latest := func(context.Context, string) (string, error) { return "v1.2.0", nil }
+
+	valid, invalid, warnings := planner.Revalidate(context.Background(), items,
+		planner.WithBranchCheck(branches),
+		planner.WithUpToDateCheck(upToDateChecker{"acme/bumped": true}, ""),
+		planner.WithNewerVersionCheck(latest),
+	)
+
+	if len(valid) != 2 || valid[0].Repo != "acme/valid" || valid[1].Repo != "acme/unreachable" {
+		t.Fatalf("expected valid and unreachable items to be kept, got %+v", valid)
+	}
+	if len(invalid) != 2 {
+		t.Fatalf("expected 2 invalidated items, got %+v", invalid)
+	}
+	if invalid[0].Item.Repo != "acme/no-branch" || invalid[0].Reason != "base branch main no longer exists" {
+		t.Errorf("unexpected invalidation %+v", invalid[0])
+	}
+	if invalid[1].Item.Repo != "acme/bumped" || invalid[1].Reason != "already requires github.com/acme/lib@v1.2.0" {
+		t.Errorf("unexpected invalidation %+v", invalid[1])
+	}
+	if len(warnings) != 1 {
+		t.Errorf("expected a warning for the unreachable remote, got %v", warnings)
+	}
+}
+
+func TestRevalidate_NewerVersionReleased(t *testing.T) {
+	calls := 0
+	latest := func(context.Context, string) (string, error) {
+		calls++
+		return "v1.3.0", nil
+	}
+	items := []planner.WorkItem{
+		{Repo: "acme/a", SourceModule: "github.com/acme/lib", SourceVersion: "v1.2.0"},
+		{Repo: "acme/b", SourceModule: "github.com/acme/lib", SourceVersion: "v1.2.0"},
+	}
+
+	valid, invalid, _ := planner.Revalidate(context.Background(), items, planner.WithNewerVersionCheck(latest))
+	if len(valid) != 0 || len(invalid) != 2 {
+		t.Fatalf("expected every item to be invalidated, got valid=%v invalid=%v", valid, invalid)
+	}
+	if invalid[0].Reason != "github.com/acme/lib v1.3.0 was released after the plan targeted v1.2.0" {
+		t.Errorf("unexpected reason %q", invalid[0].Reason)
+	}
+	if calls != 1 {
+		t.Errorf("expected the latest version to be looked up once per module, got %d lookups", calls)
+	}
+}
+
+func TestPlanExpired(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	if !planner.PlanExpired(now.Add(-25*time.Hour), 24*time.Hour, now) {
+		t.Error("expected a 25h old plan to expire after 24h")
+	}
+	if planner.PlanExpired(now.Add(-time.Hour), 24*time.Hour, now) {
+		t.Error("expected a 1h old plan to be fresh")
+	}
+	if planner.PlanExpired(now.Add(-1000*time.Hour), 0, now) || planner.PlanExpired(time.Time{}, time.Hour, now) {
+		t.Error("expected plans to never expire without a max age or creation time")
+	}
+}
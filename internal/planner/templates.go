@@ -1,26 +1,64 @@
 package planner
 
 import (
-	"regexp"
+	"bytes"
+	"fmt"
 	"strings"
-)
+	"text/template"
 
-var (
-	modulePlaceholder  = regexp.MustCompile(`(?i)\{\{\s*\.?module\s*\}\}`)
-	versionPlaceholder = regexp.MustCompile(`(?i)\{\{\s*\.?version\s*\}\}`)
+	"github.com/goliatone/cascade/pkg/templatefuncs"
 )
 
-// RenderCommitMessage renders a commit message template with placeholder substitution.
-// Supports {{ module }} and {{ version }} placeholders via simple string replacement.
-// Returns a sensible default if template is empty.
-func RenderCommitMessage(template string, target Target) string {
-	if template == "" {
-		return "Update " + target.Module + " to " + target.Version
+// CommitMessageData is the data commit message templates render.
+type CommitMessageData struct {
+	// Module and Version are the updated module and its new version; the first
+	// update of a release set.
+	Module  string
+	Version string
+
+	// Modules lists every update as comma separated module@version pairs.
+	Modules string
+
+	// Updates are the module updates of the commit.
+	Updates []ModuleUpdate
+}
+
+// RenderCommitMessage renders a commit message template with text/template and
+// the helpers PR templates use, such as semverMajor and shortSHA. Besides the
+// fields of CommitMessageData, the {{ module }}, {{ version }}, and
+// {{ modules }} placeholders render the module, its version, and the
+// module@version list. Returns a sensible default if template is empty, and
+// that default together with an error if the template does not render.
+func RenderCommitMessage(tmpl string, target Target) (string, error) {
+	fallback := "Update " + target.Module + " to " + target.Version
+	if tmpl == "" {
+		return fallback, nil
 	}
+	return renderCommitTemplate(tmpl, CommitMessageData{
+		Module:  target.Module,
+		Version: target.Version,
+		Modules: target.Module + "@" + target.Version,
+		Updates: []ModuleUpdate{{Module: target.Module, Version: target.Version}},
+	}, fallback)
+}
 
-	result := modulePlaceholder.ReplaceAllString(template, target.Module)
-	result = versionPlaceholder.ReplaceAllString(result, target.Version)
-	return result
+// renderCommitTemplate renders tmpl with data, returning fallback and the error
+// when it fails to parse or execute.
+func renderCommitTemplate(tmpl string, data CommitMessageData, fallback string) (string, error) {
+	funcs := templatefuncs.FuncMap()
+	funcs["module"] = func() string { return data.Module }
+	funcs["version"] = func() string { return data.Version }
+	funcs["modules"] = func() string { return data.Modules }
+
+	t, err := template.New("commit_template").Funcs(funcs).Parse(tmpl)
+	if err != nil {
+		return fallback, fmt.Errorf("parse commit_template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return fallback, fmt.Errorf("render commit_template: %w", err)
+	}
+	return buf.String(), nil
 }
 
 // GenerateBranchName creates a sanitized branch name from module and version.
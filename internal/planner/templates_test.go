@@ -1,6 +1,9 @@
 package planner
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestRenderCommitMessage(t *testing.T) {
 	tests := []struct {
@@ -51,6 +54,18 @@ func TestRenderCommitMessage(t *testing.T) {
 			target:   Target{Module: "go-errors", Version: "v1.2.3"},
 			expected: "go-errors/v1.2.3: Update go-errors to v1.2.3",
 		},
+		{
+			name:     "template with a helper",
+			template: "chore(deps): bump {{ module }} to v{{ semverMajor .Version }} ({{ .Version }})",
+			target:   Target{Module: "go-errors", Version: "v2.1.0"},
+			expected: "chore(deps): bump go-errors to v2 (v2.1.0)",
+		},
+		{
+			name:     "template with a pipeline",
+			template: `Update {{ .Module | replace "github.com/" "" }} to {{ version }}`,
+			target:   Target{Module: "github.com/goliatone/go-errors", Version: "v1.2.3"},
+			expected: "Update goliatone/go-errors to v1.2.3",
+		},
 		{
 			name:     "template with unusual characters in target",
 			template: "Update {{ module }} to {{ version }}",
@@ -61,7 +76,10 @@ func TestRenderCommitMessage(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := RenderCommitMessage(tt.template, tt.target)
+			result, err := RenderCommitMessage(tt.template, tt.target)
+			if err != nil {
+				t.Fatalf("RenderCommitMessage() error = %v", err)
+			}
 			if result != tt.expected {
 				t.Errorf("RenderCommitMessage() = %q, want %q", result, tt.expected)
 			}
@@ -69,6 +87,64 @@ func TestRenderCommitMessage(t *testing.T) {
 	}
 }
 
+func TestRenderCommitMessageInvalidTemplate(t *testing.T) {
+	target := Target{Module: "go-errors", Version: "v1.2.3"}
+
+	for _, template := range []string{"Update {{ module", "Update {{ unknownFunc }}", "Update {{ .Missing }}"} {
+		result, err := RenderCommitMessage(template, target)
+		if err == nil {
+			t.Errorf("RenderCommitMessage(%q) expected an error", template)
+		}
+		if result != "Update go-errors to v1.2.3" {
+			t.Errorf("RenderCommitMessage(%q) = %q, want the default message", template, result)
+		}
+	}
+}
+
+func TestRenderReleaseSetCommitMessage(t *testing.T) {
+	updates := []ModuleUpdate{
+		{Module: "example.com/lib-a", Version: "v1.2.0"},
+		{Module: "example.com/lib-b", Version: "v2.0.0"},
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{
+			name:     "empty template lists every update",
+			expected: "Update example.com/lib-a to v1.2.0, example.com/lib-b to v2.0.0",
+		},
+		{
+			name:     "placeholders",
+			template: "chore(deps): bump {{ modules }} (first {{ module }}@{{ version }})",
+			expected: "chore(deps): bump example.com/lib-a@v1.2.0, example.com/lib-b@v2.0.0 (first example.com/lib-a@v1.2.0)",
+		},
+		{
+			name:     "range over updates",
+			template: "chore(deps): bump{{ range .Updates }} {{ .Module }}@v{{ semverMajor .Version }}{{ end }}",
+			expected: "chore(deps): bump example.com/lib-a@v1 example.com/lib-b@v2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := RenderReleaseSetCommitMessage(tt.template, updates)
+			if err != nil {
+				t.Fatalf("RenderReleaseSetCommitMessage() error = %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("RenderReleaseSetCommitMessage() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+
+	if _, err := RenderReleaseSetCommitMessage("{{ .Nope }}", updates); err == nil || !strings.Contains(err.Error(), "commit_template") {
+		t.Errorf("RenderReleaseSetCommitMessage() error = %v, want a commit_template error", err)
+	}
+}
+
 func TestGenerateBranchName(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -166,8 +242,8 @@ func TestRenderCommitMessageDeterministic(t *testing.T) {
 	template := "Update {{ module }} to {{ version }}"
 	target := Target{Module: "go-errors", Version: "v1.2.3"}
 
-	result1 := RenderCommitMessage(template, target)
-	result2 := RenderCommitMessage(template, target)
+	result1, _ := RenderCommitMessage(template, target)
+	result2, _ := RenderCommitMessage(template, target)
 
 	if result1 != result2 {
 		t.Errorf("RenderCommitMessage is not deterministic: %q != %q", result1, result2)
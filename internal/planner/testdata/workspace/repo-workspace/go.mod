@@ -0,0 +1,5 @@
+module github.com/goliatone/repo-workspace
+
+go 1.21
+
+require github.com/goliatone/go-errors v0.8.0
@@ -0,0 +1,3 @@
+module github.com/goliatone/go-errors
+
+go 1.21
@@ -5,12 +5,33 @@ import (
 	"time"
 
 	"github.com/goliatone/cascade/internal/manifest"
+	"github.com/goliatone/cascade/pkg/repourl"
 )
 
 // Target describes the module and version we are planning updates for.
 type Target struct {
 	Module  string
 	Version string
+
+	// Aliases are historical import paths of Module; dependents requiring any of
+	// them are treated as depending on Module
+	Aliases []string `json:",omitempty"`
+}
+
+// Paths returns the target module path followed by its aliases.
+func (t Target) Paths() []string {
+	return manifest.ModulePaths(t.Module, t.Aliases)
+}
+
+// lookupDependency returns the version required under the first target path found
+// in deps, along with the path that matched.
+func (t Target) lookupDependency(deps map[string]string) (path, version string, ok bool) {
+	for _, p := range t.Paths() {
+		if v, found := deps[p]; found {
+			return p, v, true
+		}
+	}
+	return "", "", false
 }
 
 // Plan is the deterministic set of work items derived from a manifest + target.
@@ -18,6 +39,16 @@ type Plan struct {
 	Target Target
 	Items  []WorkItem
 	Stats  PlanStats
+
+	// Explanations is populated only when planning with a WithExplain context.
+	Explanations []Explanation `json:"Explanations,omitempty"`
+
+	// Waves is populated only when planning with a WithWaves context. Items
+	// belong to wave 0; later waves are planned once their modules are tagged.
+	Waves []Wave `json:"Waves,omitempty"`
+	// Targets lists every module of a release set planned with PlanReleaseSet;
+	// Target is the first of them. It is empty for single-module plans.
+	Targets []Target `json:"Targets,omitempty"`
 }
 
 // PlanStats captures statistics about the planning process.
@@ -34,6 +65,17 @@ type PlanStats struct {
 	// SkippedUpToDateRepos enumerates the repositories skipped for being up-to-date.
 	SkippedUpToDateRepos []string `json:"SkippedUpToDateRepos,omitempty"`
 
+	// SkippedArchivedRepos enumerates the repositories skipped because they are archived.
+	SkippedArchivedRepos []string `json:"SkippedArchivedRepos,omitempty"`
+
+	// SkippedLocalRepos enumerates the repositories skipped because a replace
+	// directive or go.work file points them at a local copy of the target.
+	SkippedLocalRepos []string `json:"SkippedLocalRepos,omitempty"`
+
+	// DuplicateDependents lists the dependents several manifest entries listed
+	// for the target, which were merged into one work item.
+	DuplicateDependents []DuplicateDependent `json:"DuplicateDependents,omitempty"`
+
 	// CheckErrors is the number of errors encountered during dependency checking
 	CheckErrors int
 
@@ -58,6 +100,18 @@ type PlanStats struct {
 
 	// CheckDuration is the total time spent checking dependencies
 	CheckDuration time.Duration
+
+	// EstimatedDuration is the expected wall-clock time of executing the plan,
+	// derived from earlier runs. Zero when no history is available.
+	EstimatedDuration time.Duration `json:"EstimatedDuration,omitempty"`
+
+	// TimedItems is the number of work items with recorded timings; the others
+	// are estimated from the average of the history
+	TimedItems int `json:"TimedItems,omitempty"`
+
+	// EstimatedAPICalls is the minimum number of GitHub API calls the release is
+	// expected to make for pull requests
+	EstimatedAPICalls int `json:"EstimatedAPICalls,omitempty"`
 }
 
 // WorkItem represents the actions required to update a dependent repository.
@@ -77,9 +131,72 @@ type WorkItem struct {
 	PR            manifest.PRConfig
 	Notifications manifest.Notifications
 	Env           map[string]string
+	Services      []manifest.Service
 	Timeout       time.Duration
 	Canary        bool
 	Skip          bool
+
+	// ConcurrencyGroup names the group this item is serialised with during
+	// parallel execution. Empty means the item runs independently.
+	ConcurrencyGroup string
+
+	// GoFlags is appended to GOFLAGS for the item's go commands
+	GoFlags string `json:"GoFlags,omitempty"`
+
+	// Credentials authenticate the item's go commands against private module hosts
+	Credentials []manifest.Credential `json:"Credentials,omitempty"`
+
+	// UpdateStrategy is manifest.UpdateStrategyGitSubmodule when the item bumps a
+	// submodule pointer instead of a go.mod require
+	UpdateStrategy string `json:"UpdateStrategy,omitempty"`
+
+	// SubmodulePath is the submodule to bump; empty selects it by repository URL
+	SubmodulePath string `json:"SubmodulePath,omitempty"`
+
+	// BumpStrategy names the manifest.BumpStrategy that turns the target version
+	// into the go get query; empty pins the target version
+	BumpStrategy string `json:"BumpStrategy,omitempty"`
+
+	// GoWork is manifest.GoWorkOff to run go get and go mod tidy with
+	// GOWORK=off; empty or respect lets them use go.work files
+	GoWork string `json:"GoWork,omitempty"`
+
+	// Provider names the service the pull request is opened on; empty follows
+	// the repository's host
+	Provider string `json:"Provider,omitempty"`
+
+	// CloneProtocol is https or ssh to clone the item over that protocol
+	// instead of its host's; empty follows the host
+	CloneProtocol string `json:"CloneProtocol,omitempty"`
+
+	// ProxyFallback is set when GOPROXY could not serve the target version and
+	// the item fetches it directly, with the module added to GOPRIVATE
+	ProxyFallback bool `json:"ProxyFallback,omitempty"`
+
+	// Wave is the level of a multi-level cascade the item belongs to
+	Wave int `json:"Wave,omitempty"`
+
+	// Retry bounds how resume retries the item after it fails; nil sets no bounds
+	Retry *manifest.RetryPolicy `json:"Retry,omitempty"`
+
+	// Changelog is the changelog entry the executor writes with the update; nil
+	// writes none
+	Changelog *manifest.Changelog `json:"Changelog,omitempty"`
+
+	// Updates lists every module the item bumps when it belongs to a release
+	// set; SourceModule and SourceVersion hold the first of them
+	Updates []ModuleUpdate `json:"Updates,omitempty"`
+}
+
+// ExclusiveKeys returns what the item holds while it runs, so items sharing a
+// key are never run at the same time: its repository, whose clone in the
+// workspace every item of the repository uses, and its concurrency group.
+func (w WorkItem) ExclusiveKeys() []string {
+	keys := []string{"repo:" + w.Repo}
+	if w.ConcurrencyGroup != "" {
+		keys = append(keys, "group:"+w.ConcurrencyGroup)
+	}
+	return keys
 }
 
 // Metadata captures optional context for downstream consumers.
@@ -179,6 +296,19 @@ type CheckOptions struct {
 
 	// Timeout sets the timeout for individual dependency checks
 	Timeout time.Duration
+
+	// Retries is how many times a failed remote fetch is retried before the
+	// check fails open. Missing repositories, refs and credentials are not
+	// retried.
+	Retries int
+
+	// RetryDelay is the wait before the first retry; later waits back off
+	// exponentially.
+	RetryDelay time.Duration
+
+	// RepoURLs builds clone URLs for remote checks. Nil uses the built-in host
+	// defaults.
+	RepoURLs *repourl.Resolver
 }
 
 // cacheKey identifies a unique repository + ref combination in the cache.
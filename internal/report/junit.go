@@ -0,0 +1,173 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/goliatone/cascade/internal/executor"
+)
+
+// junitOutputLimit bounds the command output each failure carries; the full
+// output stays in the item's state file.
+const junitOutputLimit = 8 << 10
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Skipped    int             `xml:"skipped,attr"`
+	Time       string          `xml:"time,attr"`
+	Timestamp  string          `xml:"timestamp,attr,omitempty"`
+	Properties []junitProperty `xml:"properties>property,omitempty"`
+	Cases      []junitTestCase `xml:"testcase"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr,omitempty"`
+	Type    string `xml:"type,attr,omitempty"`
+	Body    string `xml:",chardata"`
+}
+
+// writeJUnit encodes the report as JUnit XML: one test suite for the cascade
+// with a test case per dependent. Failed dependents are failures, skipped and
+// manual-review ones are skipped, and so are those already up to date.
+func (r *Report) writeJUnit(w io.Writer) error {
+	name := r.Module + "@" + r.Version
+	suite := junitTestSuite{
+		Name: name,
+		Time: junitSeconds(r.DurationSeconds),
+	}
+	if !r.StartTime.IsZero() {
+		suite.Timestamp = r.StartTime.UTC().Format(time.RFC3339)
+	}
+	suite.Properties = append(suite.Properties, junitProperty{Name: "attempt", Value: fmt.Sprint(r.Attempt)})
+	for _, member := range r.ReleaseSet {
+		suite.Properties = append(suite.Properties, junitProperty{Name: "release_set", Value: member})
+	}
+
+	for _, item := range r.Items {
+		testCase := junitTestCase{
+			Name:      item.Repo,
+			ClassName: name,
+			Time:      junitSeconds(item.DurationSeconds),
+			SystemOut: item.systemOut(),
+		}
+		switch item.Status {
+		case executor.StatusCompleted, executor.StatusNoChange:
+		case executor.StatusSkipped:
+			testCase.Skipped = &junitMessage{Message: item.Reason}
+		case executor.StatusManualReview:
+			testCase.Skipped = &junitMessage{Message: strings.TrimSpace("manual review: " + item.Reason)}
+		default:
+			testCase.Failure = &junitMessage{Message: item.Reason, Type: string(item.Status), Body: item.failureDetails()}
+		}
+		suite.add(testCase)
+	}
+	for _, repo := range r.UpToDate {
+		suite.add(junitTestCase{
+			Name:      repo,
+			ClassName: name,
+			Time:      junitSeconds(0),
+			Skipped:   &junitMessage{Message: "already up to date"},
+		})
+	}
+
+	suites := junitTestSuites{
+		Name:     "cascade",
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Skipped:  suite.Skipped,
+		Time:     suite.Time,
+		Suites:   []junitTestSuite{suite},
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(suites); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func (s *junitTestSuite) add(testCase junitTestCase) {
+	s.Tests++
+	if testCase.Failure != nil {
+		s.Failures++
+	}
+	if testCase.Skipped != nil {
+		s.Skipped++
+	}
+	s.Cases = append(s.Cases, testCase)
+}
+
+// systemOut lists the pull request, branch, and output location of the item.
+func (i Item) systemOut() string {
+	var lines []string
+	if i.PRURL != "" {
+		lines = append(lines, "Pull request: "+i.PRURL)
+	}
+	if i.Branch != "" {
+		lines = append(lines, "Branch: "+i.Branch)
+	}
+	if i.Output != "" {
+		lines = append(lines, "Command output: "+i.Output)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// failureDetails returns the failed commands of the item with the tail of
+// their output.
+func (i Item) failureDetails() string {
+	var b strings.Builder
+	for _, log := range i.logs {
+		if log.Err == nil {
+			continue
+		}
+		fmt.Fprintf(&b, "$ %s\n%v\n", strings.Join(log.Command.Cmd, " "), log.Err)
+		if output := strings.TrimSpace(log.Output); output != "" {
+			if len(output) > junitOutputLimit {
+				output = "..." + output[len(output)-junitOutputLimit:]
+			}
+			b.WriteString(output)
+			b.WriteString("\n")
+		}
+	}
+	if i.Output != "" {
+		fmt.Fprintf(&b, "Full output: %s\n", i.Output)
+	}
+	return b.String()
+}
+
+func junitSeconds(seconds float64) string {
+	return fmt.Sprintf("%.3f", seconds)
+}
@@ -0,0 +1,208 @@
+// Package report builds machine-readable reports of a release or resume from
+// the state it recorded, for CI artifacts and dashboards.
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/internal/state"
+)
+
+// Format names a report encoding.
+type Format string
+
+// Supported report formats.
+const (
+	FormatJSON  Format = "json"
+	FormatJUnit Format = "junit"
+)
+
+// Formats lists the supported report formats.
+var Formats = []Format{FormatJSON, FormatJUnit}
+
+// ParseFormat returns the format named name, ignoring case.
+func ParseFormat(name string) (Format, error) {
+	format := Format(strings.ToLower(strings.TrimSpace(name)))
+	for _, supported := range Formats {
+		if format == supported {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported report format %q (supported: json, junit)", name)
+}
+
+// FileName returns the name a report in this format is written under.
+func (f Format) FileName() string {
+	if f == FormatJUnit {
+		return "cascade-report.xml"
+	}
+	return "cascade-report." + string(f)
+}
+
+// Report summarises the outcome of a recorded cascade.
+type Report struct {
+	Module  string `json:"module"`
+	Version string `json:"version"`
+
+	// ReleaseSet lists every module@version released together, empty for
+	// single-module releases.
+	ReleaseSet []string `json:"release_set,omitempty"`
+
+	// Attempt counts the release and its resumes, from 1.
+	Attempt int `json:"attempt"`
+
+	StartTime       time.Time `json:"start_time"`
+	EndTime         time.Time `json:"end_time"`
+	DurationSeconds float64   `json:"duration_seconds"`
+
+	Totals Totals `json:"totals"`
+	Items  []Item `json:"items"`
+
+	// UpToDate lists the dependents planning skipped because they already
+	// required the version.
+	UpToDate []string `json:"up_to_date,omitempty"`
+}
+
+// Totals counts the items of a report by status.
+type Totals struct {
+	Items        int `json:"items"`
+	Completed    int `json:"completed"`
+	NoChange     int `json:"no_change"`
+	ManualReview int `json:"manual_review"`
+	Skipped      int `json:"skipped"`
+	Failed       int `json:"failed"`
+}
+
+// Item is the outcome of one dependent.
+type Item struct {
+	Repo            string          `json:"repo"`
+	Status          executor.Status `json:"status"`
+	Reason          string          `json:"reason,omitempty"`
+	Branch          string          `json:"branch,omitempty"`
+	CommitHash      string          `json:"commit_hash,omitempty"`
+	PRURL           string          `json:"pr_url,omitempty"`
+	Attempts        int             `json:"attempts"`
+	DurationSeconds float64         `json:"duration_seconds"`
+
+	// Output is where the output of the item's commands is recorded: its
+	// state file, as a path or URL. Empty when unknown.
+	Output string `json:"output,omitempty"`
+
+	Commands []Command `json:"commands,omitempty"`
+
+	// logs keeps the command output for the JUnit failure details.
+	logs []executor.CommandResult
+}
+
+// Command is a test or extra command an item ran.
+type Command struct {
+	Command string `json:"command"`
+	Dir     string `json:"dir,omitempty"`
+	Passed  bool   `json:"passed"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Option configures a Report.
+type Option func(*options)
+
+type options struct {
+	locate func(repo string) string
+}
+
+// WithOutputLocator sets how the location of an item's command output is
+// found. Default: none.
+func WithOutputLocator(locate func(repo string) string) Option {
+	return func(o *options) {
+		o.locate = locate
+	}
+}
+
+// New builds the report of summary, with its items sorted by repository.
+func New(summary *state.Summary, opts ...Option) *Report {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	r := &Report{
+		Module:     summary.Module,
+		Version:    summary.Version,
+		ReleaseSet: summary.ReleaseSet,
+		Attempt:    summary.RetryCount + 1,
+		StartTime:  summary.StartTime,
+		EndTime:    summary.EndTime,
+		Items:      make([]Item, 0, len(summary.Items)),
+		UpToDate:   summary.SkippedUpToDate,
+	}
+	if !r.StartTime.IsZero() && r.EndTime.After(r.StartTime) {
+		r.DurationSeconds = r.EndTime.Sub(r.StartTime).Seconds()
+	}
+
+	for _, st := range summary.Items {
+		item := Item{
+			Repo:            st.Repo,
+			Status:          st.Status,
+			Reason:          strings.TrimSpace(st.Reason),
+			Branch:          st.Branch,
+			CommitHash:      st.CommitHash,
+			PRURL:           st.PRURL,
+			Attempts:        st.Attempts,
+			DurationSeconds: st.Duration.Seconds(),
+			logs:            st.CommandLogs,
+		}
+		if o.locate != nil {
+			item.Output = o.locate(st.Repo)
+		}
+		for _, log := range st.CommandLogs {
+			command := Command{
+				Command: strings.Join(log.Command.Cmd, " "),
+				Dir:     log.Command.Dir,
+				Passed:  log.Err == nil,
+			}
+			if log.Err != nil {
+				command.Error = log.Err.Error()
+			}
+			item.Commands = append(item.Commands, command)
+		}
+		r.Items = append(r.Items, item)
+		r.Totals.add(st.Status)
+	}
+	sort.Slice(r.Items, func(i, j int) bool { return r.Items[i].Repo < r.Items[j].Repo })
+	return r
+}
+
+func (t *Totals) add(status executor.Status) {
+	t.Items++
+	switch status {
+	case executor.StatusCompleted:
+		t.Completed++
+	case executor.StatusNoChange:
+		t.NoChange++
+	case executor.StatusManualReview:
+		t.ManualReview++
+	case executor.StatusSkipped:
+		t.Skipped++
+	default:
+		t.Failed++
+	}
+}
+
+// Write encodes the report to w in format.
+func (r *Report) Write(w io.Writer, format Format) error {
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	case FormatJUnit:
+		return r.writeJUnit(w)
+	default:
+		return fmt.Errorf("unsupported report format %q", format)
+	}
+}
@@ -0,0 +1,131 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goliatone/cascade/internal/executor"
+	"github.com/goliatone/cascade/internal/manifest"
+	"github.com/goliatone/cascade/internal/state"
+)
+
+func testSummary() *state.Summary {
+	started := time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC)
+	return &state.Summary{
+		Module:          "example.com/lib",
+		Version:         "v1.2.0",
+		StartTime:       started,
+		EndTime:         started.Add(90 * time.Second),
+		RetryCount:      1,
+		SkippedUpToDate: []string{"example/d"},
+		Items: []state.ItemState{
+			{
+				Repo:     "example/c",
+				Status:   executor.StatusFailed,
+				Reason:   "tests failed",
+				Branch:   "cascade/update",
+				Attempts: 2,
+				Duration: 30 * time.Second,
+				CommandLogs: []executor.CommandResult{
+					{Command: manifest.Command{Cmd: []string{"go", "test", "./..."}}, Output: "--- FAIL: TestAPI", Err: errors.New("exit status 1")},
+				},
+			},
+			{
+				Repo:     "example/a",
+				Status:   executor.StatusCompleted,
+				Branch:   "cascade/update",
+				PRURL:    "https://github.com/example/a/pull/1",
+				Attempts: 1,
+				Duration: 45 * time.Second,
+				CommandLogs: []executor.CommandResult{
+					{Command: manifest.Command{Cmd: []string{"go", "test", "./..."}}, Output: "ok"},
+				},
+			},
+			{Repo: "example/b", Status: executor.StatusSkipped, Reason: "archived", Attempts: 1},
+		},
+	}
+}
+
+func TestNew(t *testing.T) {
+	r := New(testSummary(), WithOutputLocator(func(repo string) string { return "state/" + repo + ".json" }))
+
+	if r.Attempt != 2 || r.DurationSeconds != 90 {
+		t.Errorf("attempt = %d, duration = %v", r.Attempt, r.DurationSeconds)
+	}
+	if r.Totals != (Totals{Items: 3, Completed: 1, Skipped: 1, Failed: 1}) {
+		t.Errorf("totals = %+v", r.Totals)
+	}
+	if len(r.Items) != 3 || r.Items[0].Repo != "example/a" || r.Items[2].Repo != "example/c" {
+		t.Fatalf("items = %+v, want them sorted by repository", r.Items)
+	}
+	failed := r.Items[2]
+	if failed.Output != "state/example/c.json" || failed.DurationSeconds != 30 ||
+		len(failed.Commands) != 1 || failed.Commands[0].Passed || failed.Commands[0].Error != "exit status 1" {
+		t.Errorf("failed item = %+v", failed)
+	}
+
+	var out bytes.Buffer
+	if err := r.Write(&out, FormatJSON); err != nil {
+		t.Fatalf("Write(json) error = %v", err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+	}
+	if strings.Contains(out.String(), "--- FAIL") {
+		t.Errorf("expected the JSON report to leave command output in state:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), `"pr_url": "https://github.com/example/a/pull/1"`) || !strings.Contains(out.String(), `"up_to_date": [`) {
+		t.Errorf("unexpected JSON:\n%s", out.String())
+	}
+}
+
+func TestWriteJUnit(t *testing.T) {
+	r := New(testSummary(), WithOutputLocator(func(repo string) string { return "state/" + repo + ".json" }))
+
+	var out bytes.Buffer
+	if err := r.Write(&out, FormatJUnit); err != nil {
+		t.Fatalf("Write(junit) error = %v", err)
+	}
+
+	var suites junitTestSuites
+	if err := xml.Unmarshal(out.Bytes(), &suites); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, out.String())
+	}
+	if suites.Tests != 4 || suites.Failures != 1 || suites.Skipped != 2 || len(suites.Suites) != 1 {
+		t.Fatalf("suites = %+v", suites)
+	}
+	cases := suites.Suites[0].Cases
+	if cases[0].Name != "example/a" || cases[0].Failure != nil || cases[0].Skipped != nil ||
+		!strings.Contains(cases[0].SystemOut, "Pull request: https://github.com/example/a/pull/1") {
+		t.Errorf("passing case = %+v", cases[0])
+	}
+	if cases[1].Skipped == nil || cases[1].Skipped.Message != "archived" {
+		t.Errorf("skipped case = %+v", cases[1])
+	}
+	failure := cases[2].Failure
+	if failure == nil || failure.Message != "tests failed" || !strings.Contains(failure.Body, "$ go test ./...\nexit status 1\n--- FAIL: TestAPI") ||
+		!strings.Contains(failure.Body, "Full output: state/example/c.json") {
+		t.Errorf("failed case = %+v", cases[2])
+	}
+	if cases[3].Name != "example/d" || cases[3].Skipped == nil || cases[3].Skipped.Message != "already up to date" {
+		t.Errorf("up-to-date case = %+v", cases[3])
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	if format, err := ParseFormat(" JUnit "); err != nil || format != FormatJUnit || format.FileName() != "cascade-report.xml" {
+		t.Errorf("ParseFormat(junit) = %q, %v", format, err)
+	}
+	if format, _ := ParseFormat("json"); format.FileName() != "cascade-report.json" {
+		t.Errorf("json file name = %q", format.FileName())
+	}
+	if _, err := ParseFormat("html"); err == nil {
+		t.Error("expected an unsupported format error")
+	}
+}
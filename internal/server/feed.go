@@ -0,0 +1,271 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Feed paths and the number of cascades they list.
+const (
+	FeedAtomPath = "/feed.atom"
+	FeedJSONPath = "/feed.json"
+
+	DefaultFeedSize = 50
+)
+
+// FeedEntry is a completed cascade listed in the feed.
+type FeedEntry struct {
+	Module    string    `json:"module"`
+	Version   string    `json:"version"`
+	Started   time.Time `json:"started"`
+	Completed time.Time `json:"completed"`
+
+	// Link is the cascade's run summary, when the state directory is served.
+	Link string `json:"link,omitempty"`
+
+	// Notes are the operator annotations on the cascade itself.
+	Notes []string `json:"notes,omitempty"`
+
+	Dependents []FeedDependent `json:"dependents"`
+}
+
+// FeedDependent is the outcome of one dependent of a cascade.
+type FeedDependent struct {
+	Repo   string `json:"repo"`
+	Status string `json:"status"`
+	PRURL  string `json:"pr_url,omitempty"`
+
+	Notes []string `json:"notes,omitempty"`
+}
+
+// FeedLoader returns up to limit completed cascades, most recently completed
+// first. It is called for every feed request.
+type FeedLoader func(ctx context.Context, limit int) ([]FeedEntry, error)
+
+// WithFeed serves the cascades load returns as an Atom feed at /feed.atom and
+// a JSON Feed at /feed.json, listing the size most recent ones. A size below
+// one lists DefaultFeedSize. Default: no feed.
+func WithFeed(load FeedLoader, size int) Option {
+	return func(s *Server) {
+		s.feed = load
+		s.feedSize = size
+		if s.feedSize < 1 {
+			s.feedSize = DefaultFeedSize
+		}
+	}
+}
+
+// title summarises the entry, e.g. "github.com/acme/lib v1.2.0: 3 dependents
+// updated".
+func (e FeedEntry) title() string {
+	updated := 0
+	for _, dep := range e.Dependents {
+		if dep.PRURL != "" {
+			updated++
+		}
+	}
+	noun := "dependents"
+	if updated == 1 {
+		noun = "dependent"
+	}
+	return fmt.Sprintf("%s %s: %d %s updated", e.Module, e.Version, updated, noun)
+}
+
+// text lists the dependents of the entry, one per line, each followed by its
+// notes.
+func (e FeedEntry) text() string {
+	var b strings.Builder
+	for _, note := range e.Notes {
+		fmt.Fprintf(&b, "note: %s\n", note)
+	}
+	for _, dep := range e.Dependents {
+		fmt.Fprintf(&b, "%s: %s", dep.Repo, dep.Status)
+		if dep.PRURL != "" {
+			fmt.Fprintf(&b, " %s", dep.PRURL)
+		}
+		b.WriteString("\n")
+		for _, note := range dep.Notes {
+			fmt.Fprintf(&b, "  note: %s\n", note)
+		}
+	}
+	return b.String()
+}
+
+func (s *Server) handleFeed(format string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			writeJSON(w, http.StatusMethodNotAllowed, response{Status: "error", Reason: "feeds must be fetched with GET"})
+			return
+		}
+
+		entries, err := s.feed(r.Context(), s.feedSize)
+		if err != nil {
+			s.logError("Failed to load feed", "error", err)
+			writeJSON(w, http.StatusInternalServerError, response{Status: "error", Reason: "failed to load cascades"})
+			return
+		}
+		if len(entries) > s.feedSize {
+			entries = entries[:s.feedSize]
+		}
+
+		// Subscribers polling an unchanged feed get a 304
+		var updated time.Time
+		for _, entry := range entries {
+			if entry.Completed.After(updated) {
+				updated = entry.Completed
+			}
+		}
+		if !updated.IsZero() {
+			updated = updated.UTC().Truncate(time.Second)
+			if since, err := http.ParseTime(r.Header.Get("If-Modified-Since")); err == nil && !updated.After(since) {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("Last-Modified", updated.Format(http.TimeFormat))
+		}
+
+		self := requestURL(r)
+		switch format {
+		case "atom":
+			w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(xml.Header))
+			enc := xml.NewEncoder(w)
+			enc.Indent("", "  ")
+			_ = enc.Encode(atomFeed(self, updated, entries))
+		default:
+			w.Header().Set("Content-Type", "application/feed+json")
+			w.WriteHeader(http.StatusOK)
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			_ = enc.Encode(jsonFeed(self, entries))
+		}
+	}
+}
+
+// requestURL returns the URL r was sent to, as seen by the client.
+func requestURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	return scheme + "://" + r.Host + r.URL.Path
+}
+
+// entryID identifies entry within the feed at self.
+func entryID(self string, entry FeedEntry) string {
+	return self + "#" + entry.Module + "@" + entry.Version
+}
+
+type atomXML struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomEntry struct {
+	ID        string      `xml:"id"`
+	Title     string      `xml:"title"`
+	Updated   string      `xml:"updated"`
+	Published string      `xml:"published,omitempty"`
+	Links     []atomLink  `xml:"link"`
+	Content   atomContent `xml:"content"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+func atomFeed(self string, updated time.Time, entries []FeedEntry) atomXML {
+	if updated.IsZero() {
+		updated = time.Unix(0, 0).UTC()
+	}
+	feed := atomXML{
+		ID:      self,
+		Title:   "Cascade releases",
+		Updated: updated.Format(time.RFC3339),
+		Links:   []atomLink{{Rel: "self", Href: self}},
+	}
+	for _, entry := range entries {
+		item := atomEntry{
+			ID:      entryID(self, entry),
+			Title:   entry.title(),
+			Updated: entry.Completed.UTC().Format(time.RFC3339),
+			Content: atomContent{Type: "text", Body: entry.text()},
+		}
+		if !entry.Started.IsZero() {
+			item.Published = entry.Started.UTC().Format(time.RFC3339)
+		}
+		if entry.Link != "" {
+			item.Links = append(item.Links, atomLink{Rel: "alternate", Href: entry.Link})
+		}
+		for _, dep := range entry.Dependents {
+			if dep.PRURL != "" {
+				item.Links = append(item.Links, atomLink{Rel: "related", Href: dep.PRURL})
+			}
+		}
+		feed.Entries = append(feed.Entries, item)
+	}
+	return feed
+}
+
+// jsonFeedDoc is a JSON Feed 1.1 document. Items carry the cascade under the
+// _cascade extension.
+type jsonFeedDoc struct {
+	Version string         `json:"version"`
+	Title   string         `json:"title"`
+	FeedURL string         `json:"feed_url"`
+	Items   []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	ID            string    `json:"id"`
+	URL           string    `json:"url,omitempty"`
+	Title         string    `json:"title"`
+	ContentText   string    `json:"content_text"`
+	DatePublished string    `json:"date_published,omitempty"`
+	DateModified  string    `json:"date_modified"`
+	Cascade       FeedEntry `json:"_cascade"`
+}
+
+func jsonFeed(self string, entries []FeedEntry) jsonFeedDoc {
+	feed := jsonFeedDoc{
+		Version: "https://jsonfeed.org/version/1.1",
+		Title:   "Cascade releases",
+		FeedURL: self,
+		Items:   []jsonFeedItem{},
+	}
+	for _, entry := range entries {
+		item := jsonFeedItem{
+			ID:           entryID(self, entry),
+			URL:          entry.Link,
+			Title:        entry.title(),
+			ContentText:  entry.text(),
+			DateModified: entry.Completed.UTC().Format(time.RFC3339),
+			Cascade:      entry,
+		}
+		if !entry.Started.IsZero() {
+			item.DatePublished = entry.Started.UTC().Format(time.RFC3339)
+		}
+		feed.Items = append(feed.Items, item)
+	}
+	return feed
+}
@@ -0,0 +1,152 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goliatone/cascade/internal/manifest"
+)
+
+func TestServerFeed(t *testing.T) {
+	completed := time.Date(2026, 3, 2, 10, 30, 0, 0, time.UTC)
+	entries := []FeedEntry{
+		{
+			Module:    "github.com/acme/lib",
+			Version:   "v1.4.0",
+			Started:   completed.Add(-5 * time.Minute),
+			Completed: completed,
+			Link:      "https://cascade.acme.dev/github.com/acme/lib/v1.4.0/summary.json",
+			Dependents: []FeedDependent{
+				{Repo: "acme/api", Status: "completed", PRURL: "https://github.com/acme/api/pull/12"},
+				{Repo: "acme/web", Status: "skipped"},
+			},
+		},
+		{
+			Module:    "go.acme.dev/kit",
+			Version:   "v0.3.1",
+			Completed: completed.Add(-time.Hour),
+		},
+	}
+	var (
+		limit   int
+		loadErr error
+	)
+	srv, err := New(testSecret,
+		func() (*manifest.Manifest, error) { return testManifest(), nil },
+		func(ctx context.Context, job Job) error { return nil },
+		WithFeed(func(ctx context.Context, n int) ([]FeedEntry, error) {
+			limit = n
+			return entries, loadErr
+		}, 0),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	handler := srv.Handler()
+
+	get := func(path string, header http.Header) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "http://cascade.acme.dev"+path, nil)
+		for key, values := range header {
+			req.Header[key] = values
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := get(FeedJSONPath, nil)
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/feed+json" {
+		t.Fatalf("json feed = %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if limit != DefaultFeedSize {
+		t.Errorf("loader limit = %d, want %d", limit, DefaultFeedSize)
+	}
+	if got := rec.Header().Get("Last-Modified"); got != completed.Format(http.TimeFormat) {
+		t.Errorf("Last-Modified = %q, want %q", got, completed.Format(http.TimeFormat))
+	}
+	var feed jsonFeedDoc
+	if err := json.Unmarshal(rec.Body.Bytes(), &feed); err != nil {
+		t.Fatalf("invalid json feed %q: %v", rec.Body.String(), err)
+	}
+	if feed.FeedURL != "http://cascade.acme.dev/feed.json" || len(feed.Items) != 2 {
+		t.Fatalf("json feed = %+v", feed)
+	}
+	item := feed.Items[0]
+	if item.ID != "http://cascade.acme.dev/feed.json#github.com/acme/lib@v1.4.0" ||
+		item.Title != "github.com/acme/lib v1.4.0: 1 dependent updated" ||
+		item.URL != entries[0].Link ||
+		item.DateModified != "2026-03-02T10:30:00Z" ||
+		item.DatePublished != "2026-03-02T10:25:00Z" {
+		t.Errorf("json feed item = %+v", item)
+	}
+	if len(item.Cascade.Dependents) != 2 || item.Cascade.Dependents[0].PRURL != "https://github.com/acme/api/pull/12" {
+		t.Errorf("json feed dependents = %+v", item.Cascade.Dependents)
+	}
+	if !strings.Contains(item.ContentText, "acme/web: skipped") {
+		t.Errorf("json feed content = %q", item.ContentText)
+	}
+	if feed.Items[1].DatePublished != "" || feed.Items[1].Title != "go.acme.dev/kit v0.3.1: 0 dependents updated" {
+		t.Errorf("json feed item = %+v", feed.Items[1])
+	}
+
+	rec = get(FeedAtomPath, nil)
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), "application/atom+xml") {
+		t.Fatalf("atom feed = %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	var atom atomXML
+	if err := xml.Unmarshal(rec.Body.Bytes(), &atom); err != nil {
+		t.Fatalf("invalid atom feed %q: %v", rec.Body.String(), err)
+	}
+	if atom.ID != "http://cascade.acme.dev/feed.atom" || atom.Updated != "2026-03-02T10:30:00Z" || len(atom.Entries) != 2 {
+		t.Fatalf("atom feed = %+v", atom)
+	}
+	links := atom.Entries[0].Links
+	if len(links) != 2 || links[0].Rel != "alternate" || links[1].Rel != "related" || links[1].Href != "https://github.com/acme/api/pull/12" {
+		t.Errorf("atom entry links = %+v", links)
+	}
+
+	// Unchanged since the last poll
+	rec = get(FeedAtomPath, http.Header{"If-Modified-Since": {completed.Format(http.TimeFormat)}})
+	if rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+		t.Errorf("conditional feed = %d %q, want 304", rec.Code, rec.Body.String())
+	}
+	rec = get(FeedAtomPath, http.Header{"If-Modified-Since": {completed.Add(-time.Minute).Format(http.TimeFormat)}})
+	if rec.Code != http.StatusOK {
+		t.Errorf("conditional feed after a new cascade = %d, want 200", rec.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, FeedJSONPath, nil)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST feed = %d, want 405", rec.Code)
+	}
+
+	loadErr = errors.New("state directory unreadable")
+	if rec := get(FeedJSONPath, nil); rec.Code != http.StatusInternalServerError {
+		t.Errorf("failing feed = %d, want 500", rec.Code)
+	}
+}
+
+func TestServerFeedDisabled(t *testing.T) {
+	srv, err := New(testSecret,
+		func() (*manifest.Manifest, error) { return testManifest(), nil },
+		func(ctx context.Context, job Job) error { return nil },
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, FeedJSONPath, nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("feed without WithFeed = %d, want 404", rec.Code)
+	}
+}
@@ -0,0 +1,149 @@
+package server
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// GitHub webhook headers.
+const (
+	HeaderEvent     = "X-GitHub-Event"
+	HeaderDelivery  = "X-GitHub-Delivery"
+	HeaderSignature = "X-Hub-Signature-256"
+)
+
+var (
+	// ErrMissingSignature is returned when a delivery carries no signature.
+	ErrMissingSignature = errors.New("missing " + HeaderSignature + " header")
+	// ErrInvalidSignature is returned when a signature does not match the payload.
+	ErrInvalidSignature = errors.New("signature does not match the payload")
+)
+
+// VerifySignature checks the X-Hub-Signature-256 header of a delivery: the
+// hex encoded HMAC-SHA256 of body keyed with secret, prefixed with "sha256=".
+func VerifySignature(secret, signature string, body []byte) error {
+	signature = strings.TrimSpace(signature)
+	if signature == "" {
+		return ErrMissingSignature
+	}
+	digest, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return ErrInvalidSignature
+	}
+	got, err := hex.DecodeString(digest)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// Sign returns the X-Hub-Signature-256 value of body for secret.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// TagEvent is a tag published in a repository, as reported by a release,
+// push, or create webhook.
+type TagEvent struct {
+	// Event is the GitHub event name, e.g. "release".
+	Event string
+	// Host is the git host of the repository, e.g. "github.com".
+	Host string
+	// Repo is the owner/repo name of the repository.
+	Repo string
+	// Tag is the tag name, e.g. "v1.2.0" or "api/v1.2.0".
+	Tag string
+	// Prerelease is set for GitHub releases marked as pre-releases.
+	Prerelease bool
+}
+
+type githubRepository struct {
+	FullName string `json:"full_name"`
+	HTMLURL  string `json:"html_url"`
+}
+
+type githubPayload struct {
+	Action     string           `json:"action"`
+	Ref        string           `json:"ref"`
+	RefType    string           `json:"ref_type"`
+	Deleted    bool             `json:"deleted"`
+	Repository githubRepository `json:"repository"`
+	Release    struct {
+		TagName    string `json:"tag_name"`
+		Draft      bool   `json:"draft"`
+		Prerelease bool   `json:"prerelease"`
+	} `json:"release"`
+}
+
+// ParseTagEvent extracts the published tag from a webhook payload. It returns
+// a nil event with a reason when the delivery does not publish a tag, such as
+// a branch push, a deleted tag, or a draft release.
+func ParseTagEvent(event string, body []byte) (*TagEvent, string, error) {
+	switch event {
+	case "release", "push", "create":
+	default:
+		return nil, fmt.Sprintf("%s events are not handled", event), nil
+	}
+
+	var payload githubPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, "", fmt.Errorf("invalid %s payload: %w", event, err)
+	}
+	if payload.Repository.FullName == "" {
+		return nil, "", fmt.Errorf("%s payload has no repository", event)
+	}
+
+	tag := &TagEvent{
+		Event: event,
+		Host:  "github.com",
+		Repo:  strings.ToLower(payload.Repository.FullName),
+	}
+	if u, err := url.Parse(payload.Repository.HTMLURL); err == nil && u.Host != "" {
+		tag.Host = strings.ToLower(u.Host)
+	}
+
+	switch event {
+	case "release":
+		if payload.Action != "published" {
+			return nil, fmt.Sprintf("release %s actions are not handled", payload.Action), nil
+		}
+		if payload.Release.Draft {
+			return nil, "draft releases are not handled", nil
+		}
+		tag.Tag = payload.Release.TagName
+		tag.Prerelease = payload.Release.Prerelease
+	case "push":
+		name, ok := strings.CutPrefix(payload.Ref, "refs/tags/")
+		if !ok {
+			return nil, fmt.Sprintf("push to %s is not a tag", payload.Ref), nil
+		}
+		if payload.Deleted {
+			return nil, fmt.Sprintf("tag %s was deleted", name), nil
+		}
+		tag.Tag = name
+	case "create":
+		if payload.RefType != "tag" {
+			return nil, fmt.Sprintf("created %s %s is not a tag", payload.RefType, payload.Ref), nil
+		}
+		tag.Tag = payload.Ref
+	}
+
+	if tag.Tag == "" {
+		return nil, "", fmt.Errorf("%s payload has no tag", event)
+	}
+	return tag, "", nil
+}
@@ -0,0 +1,361 @@
+// Package server runs cascades from GitHub webhooks. Release, tag push, and tag
+// create deliveries are verified against a shared secret, mapped to the
+// manifest modules the tag releases, and queued; queued cascades run one at a
+// time through a caller supplied release function.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/goliatone/cascade/internal/manifest"
+	"golang.org/x/mod/semver"
+)
+
+// Default settings.
+const (
+	DefaultPath      = "/webhook"
+	DefaultQueueSize = 16
+
+	// maxPayloadBytes bounds webhook bodies; GitHub caps payloads at 25MB but
+	// release, push, and create events are far smaller.
+	maxPayloadBytes = 5 << 20
+)
+
+var (
+	errDuplicate = errors.New("already queued or released")
+	errQueueFull = errors.New("queue is full")
+)
+
+// Logger defines the interface for logging.
+type Logger interface {
+	Info(msg string, args ...any)
+	Error(msg string, args ...any)
+	Debug(msg string, args ...any)
+	Warn(msg string, args ...any)
+}
+
+// ManifestLoader returns the manifest tags are mapped against. It is called
+// for every delivery, so manifest edits apply without a restart.
+type ManifestLoader func() (*manifest.Manifest, error)
+
+// ReleaseFunc runs the cascade of a queued job. Jobs are released one at a
+// time, and ctx is cancelled when the server stops.
+type ReleaseFunc func(ctx context.Context, job Job) error
+
+// Job is a cascade queued from a webhook delivery.
+type Job struct {
+	Target
+	Event    string `json:"event"`
+	Repo     string `json:"repo"`
+	Tag      string `json:"tag"`
+	Delivery string `json:"delivery,omitempty"`
+}
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithLogger attaches a logger.
+func WithLogger(logger Logger) Option {
+	return func(s *Server) {
+		s.logger = logger
+	}
+}
+
+// WithPath sets the path webhooks are delivered to. Default: /webhook.
+func WithPath(path string) Option {
+	return func(s *Server) {
+		if path != "" {
+			s.path = path
+		}
+	}
+}
+
+// WithQueueSize sets how many cascades may wait to run. Deliveries that find
+// the queue full are answered with 503, so GitHub reports them as failed and
+// they can be redelivered. Default: 16.
+func WithQueueSize(size int) Option {
+	return func(s *Server) {
+		if size > 0 {
+			s.queueSize = size
+		}
+	}
+}
+
+// WithPrereleases queues tags with pre-release versions, such as
+// v1.2.0-rc.1, and GitHub releases marked as pre-releases. Default: ignored.
+func WithPrereleases(enabled bool) Option {
+	return func(s *Server) {
+		s.prereleases = enabled
+	}
+}
+
+// Server receives GitHub webhooks and queues the cascades they trigger.
+type Server struct {
+	secret      string
+	manifests   ManifestLoader
+	release     ReleaseFunc
+	logger      Logger
+	path        string
+	queueSize   int
+	prereleases bool
+	feed        FeedLoader
+	feedSize    int
+
+	queue chan Job
+	mu    sync.Mutex
+	seen  map[string]bool
+}
+
+// New returns a server that verifies deliveries with secret, maps tags with
+// the manifest from manifests, and runs cascades with release.
+func New(secret string, manifests ManifestLoader, release ReleaseFunc, opts ...Option) (*Server, error) {
+	if secret == "" {
+		return nil, errors.New("webhook secret is required")
+	}
+	if manifests == nil || release == nil {
+		return nil, errors.New("manifest loader and release function are required")
+	}
+
+	s := &Server{
+		secret:    secret,
+		manifests: manifests,
+		release:   release,
+		path:      DefaultPath,
+		queueSize: DefaultQueueSize,
+		seen:      make(map[string]bool),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.queue = make(chan Job, s.queueSize)
+	return s, nil
+}
+
+// Handler returns the HTTP handler serving the webhook path and /healthz.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(s.path, s.handleWebhook)
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, response{Status: "ok"})
+	})
+	if s.feed != nil {
+		mux.HandleFunc(FeedAtomPath, s.handleFeed("atom"))
+		mux.HandleFunc(FeedJSONPath, s.handleFeed("json"))
+	}
+	return mux
+}
+
+// Run serves webhooks on addr and releases queued cascades until ctx is
+// cancelled. The cascade running at that point is cancelled too; its state is
+// kept, so it can be continued with cascade resume.
+func (s *Server) Run(ctx context.Context, addr string) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	httpServer := &http.Server{Addr: addr, Handler: s.Handler(), ReadHeaderTimeout: 10 * time.Second}
+	served := make(chan error, 1)
+	go func() {
+		served <- httpServer.ListenAndServe()
+	}()
+
+	worked := make(chan struct{})
+	go func() {
+		s.Work(ctx)
+		close(worked)
+	}()
+
+	var err error
+	select {
+	case <-ctx.Done():
+	case err = <-served:
+	}
+
+	shutdownCtx, stop := context.WithTimeout(context.Background(), 10*time.Second)
+	defer stop()
+	_ = httpServer.Shutdown(shutdownCtx)
+	cancel()
+	<-worked
+
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// Work releases queued jobs one at a time until ctx is cancelled. A failed
+// job may be queued again by a later delivery of the same tag.
+func (s *Server) Work(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case job := <-s.queue:
+			s.logInfo("Starting cascade", "module", job.Module, "version", job.Version, "event", job.Event, "delivery", job.Delivery)
+			if err := s.release(ctx, job); err != nil {
+				s.logError("Cascade failed", "module", job.Module, "version", job.Version, "error", err)
+				s.mu.Lock()
+				delete(s.seen, job.Key())
+				s.mu.Unlock()
+				continue
+			}
+			s.logInfo("Cascade completed", "module", job.Module, "version", job.Version)
+		}
+	}
+}
+
+// response is the JSON body of webhook replies.
+type response struct {
+	Status  string   `json:"status"`
+	Reason  string   `json:"reason,omitempty"`
+	Queued  []Target `json:"queued,omitempty"`
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+func (s *Server) handleWebhook(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeJSON(w, http.StatusMethodNotAllowed, response{Status: "error", Reason: "webhooks must be POSTed"})
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxPayloadBytes))
+	if err != nil {
+		status := http.StatusBadRequest
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		writeJSON(w, status, response{Status: "error", Reason: err.Error()})
+		return
+	}
+	if err := VerifySignature(s.secret, r.Header.Get(HeaderSignature), body); err != nil {
+		s.logWarn("Rejected webhook delivery", "delivery", r.Header.Get(HeaderDelivery), "error", err)
+		writeJSON(w, http.StatusUnauthorized, response{Status: "error", Reason: err.Error()})
+		return
+	}
+
+	event := r.Header.Get(HeaderEvent)
+	if event == "ping" {
+		writeJSON(w, http.StatusOK, response{Status: "pong"})
+		return
+	}
+
+	tag, reason, err := ParseTagEvent(event, body)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, response{Status: "error", Reason: err.Error()})
+		return
+	}
+	if tag == nil {
+		s.ignore(w, r, reason)
+		return
+	}
+
+	m, err := s.manifests()
+	if err != nil {
+		s.logError("Failed to load manifest for webhook", "error", err)
+		writeJSON(w, http.StatusInternalServerError, response{Status: "error", Reason: "failed to load manifest"})
+		return
+	}
+	targets, reason := Targets(m, *tag)
+	if len(targets) == 0 {
+		s.ignore(w, r, reason)
+		return
+	}
+
+	reply := response{Status: "queued"}
+	full := false
+	for _, target := range targets {
+		if !s.prereleases && (tag.Prerelease || semver.Prerelease(target.Version) != "") {
+			reply.Skipped = append(reply.Skipped, fmt.Sprintf("%s: pre-releases are not handled", target.Key()))
+			continue
+		}
+		job := Job{Target: target, Event: event, Repo: tag.Repo, Tag: tag.Tag, Delivery: r.Header.Get(HeaderDelivery)}
+		switch err := s.enqueue(job); {
+		case err == nil:
+			s.logInfo("Queued cascade", "module", target.Module, "version", target.Version, "event", event, "delivery", job.Delivery)
+			reply.Queued = append(reply.Queued, target)
+		case errors.Is(err, errQueueFull):
+			full = true
+			reply.Skipped = append(reply.Skipped, fmt.Sprintf("%s: %v", target.Key(), err))
+		default:
+			reply.Skipped = append(reply.Skipped, fmt.Sprintf("%s: %v", target.Key(), err))
+		}
+	}
+
+	// A target that did not fit the queue fails the delivery so GitHub shows it
+	// and it can be redelivered; targets queued now are skipped as duplicates
+	// then.
+	switch {
+	case full:
+		reply.Status = "error"
+		if len(reply.Queued) > 0 {
+			reply.Status = "partial"
+		}
+		writeJSON(w, http.StatusServiceUnavailable, reply)
+	case len(reply.Queued) > 0:
+		writeJSON(w, http.StatusAccepted, reply)
+	default:
+		reply.Status = "ignored"
+		writeJSON(w, http.StatusOK, reply)
+	}
+}
+
+// enqueue queues job unless the same release is already queued, running, or
+// released.
+func (s *Server) enqueue(job Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.seen[job.Key()] {
+		return errDuplicate
+	}
+	select {
+	case s.queue <- job:
+		s.seen[job.Key()] = true
+		return nil
+	default:
+		return errQueueFull
+	}
+}
+
+func (s *Server) ignore(w http.ResponseWriter, r *http.Request, reason string) {
+	s.logDebug("Ignored webhook delivery", "event", r.Header.Get(HeaderEvent), "delivery", r.Header.Get(HeaderDelivery), "reason", reason)
+	writeJSON(w, http.StatusOK, response{Status: "ignored", Reason: reason})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func (s *Server) logInfo(msg string, args ...any) {
+	if s.logger != nil {
+		s.logger.Info(msg, args...)
+	}
+}
+
+func (s *Server) logWarn(msg string, args ...any) {
+	if s.logger != nil {
+		s.logger.Warn(msg, args...)
+	}
+}
+
+func (s *Server) logError(msg string, args ...any) {
+	if s.logger != nil {
+		s.logger.Error(msg, args...)
+	}
+}
+
+func (s *Server) logDebug(msg string, args ...any) {
+	if s.logger != nil {
+		s.logger.Debug(msg, args...)
+	}
+}
@@ -0,0 +1,258 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goliatone/cascade/internal/manifest"
+)
+
+const testSecret = "s3cret"
+
+func testManifest() *manifest.Manifest {
+	return &manifest.Manifest{
+		Modules: []manifest.Module{
+			{Module: "github.com/acme/lib", Repo: "acme/lib"},
+			{Module: "github.com/acme/lib/v2", Repo: "acme/lib"},
+			{Module: "github.com/acme/lib/api", Repo: "https://github.com/acme/lib.git"},
+			{Module: "go.acme.dev/kit", Repo: "git@github.com:acme/kit.git"},
+			{Module: "github.com/acme/tools"},
+		},
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	body := []byte(`{"zen":"hi"}`)
+	if err := VerifySignature(testSecret, Sign(testSecret, body), body); err != nil {
+		t.Errorf("VerifySignature() error = %v", err)
+	}
+	for signature, want := range map[string]error{
+		"":                             ErrMissingSignature,
+		"sha1=abc":                     ErrInvalidSignature,
+		"sha256=zz":                    ErrInvalidSignature,
+		Sign("other", body):            ErrInvalidSignature,
+		Sign(testSecret, []byte("{}")): ErrInvalidSignature,
+	} {
+		if err := VerifySignature(testSecret, signature, body); !errors.Is(err, want) {
+			t.Errorf("VerifySignature(%q) error = %v, want %v", signature, err, want)
+		}
+	}
+}
+
+func TestParseTagEvent(t *testing.T) {
+	repo := `"repository":{"full_name":"Acme/Lib","html_url":"https://github.example.com/Acme/Lib"}`
+	tests := []struct {
+		name       string
+		event      string
+		body       string
+		want       *TagEvent
+		wantReason string
+		wantErr    string
+	}{
+		{
+			name:  "published release",
+			event: "release",
+			body:  `{"action":"published","release":{"tag_name":"v1.2.0","prerelease":true},` + repo + `}`,
+			want:  &TagEvent{Event: "release", Host: "github.example.com", Repo: "acme/lib", Tag: "v1.2.0", Prerelease: true},
+		},
+		{name: "edited release", event: "release", body: `{"action":"edited",` + repo + `}`, wantReason: "release edited actions are not handled"},
+		{name: "draft release", event: "release", body: `{"action":"published","release":{"tag_name":"v1.2.0","draft":true},` + repo + `}`, wantReason: "draft releases"},
+		{
+			name:  "tag push",
+			event: "push",
+			body:  `{"ref":"refs/tags/api/v1.2.0",` + repo + `}`,
+			want:  &TagEvent{Event: "push", Host: "github.example.com", Repo: "acme/lib", Tag: "api/v1.2.0"},
+		},
+		{name: "branch push", event: "push", body: `{"ref":"refs/heads/main",` + repo + `}`, wantReason: "push to refs/heads/main is not a tag"},
+		{name: "deleted tag", event: "push", body: `{"ref":"refs/tags/v1.2.0","deleted":true,` + repo + `}`, wantReason: "tag v1.2.0 was deleted"},
+		{
+			name:  "tag create",
+			event: "create",
+			body:  `{"ref":"v1.2.0","ref_type":"tag",` + repo + `}`,
+			want:  &TagEvent{Event: "create", Host: "github.example.com", Repo: "acme/lib", Tag: "v1.2.0"},
+		},
+		{name: "branch create", event: "create", body: `{"ref":"dev","ref_type":"branch",` + repo + `}`, wantReason: "created branch dev is not a tag"},
+		{name: "other event", event: "issues", body: `{}`, wantReason: "issues events are not handled"},
+		{name: "invalid json", event: "push", body: `{`, wantErr: "invalid push payload"},
+		{name: "no repository", event: "push", body: `{"ref":"refs/tags/v1.0.0"}`, wantErr: "push payload has no repository"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, reason, err := ParseTagEvent(tt.event, []byte(tt.body))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseTagEvent() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseTagEvent() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) || !strings.Contains(reason, tt.wantReason) {
+				t.Errorf("ParseTagEvent() = %+v, %q, want %+v, %q", got, reason, tt.want, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestTargets(t *testing.T) {
+	tests := []struct {
+		name       string
+		tag        TagEvent
+		want       []Target
+		wantReason string
+	}{
+		{name: "root module", tag: TagEvent{Host: "github.com", Repo: "acme/lib", Tag: "v1.4.0"}, want: []Target{{Module: "github.com/acme/lib", Version: "v1.4.0"}}},
+		{name: "major version", tag: TagEvent{Host: "github.com", Repo: "acme/lib", Tag: "v2.0.1"}, want: []Target{{Module: "github.com/acme/lib/v2", Version: "v2.0.1"}}},
+		{name: "submodule", tag: TagEvent{Host: "github.com", Repo: "acme/lib", Tag: "api/v0.3.0"}, want: []Target{{Module: "github.com/acme/lib/api", Version: "v0.3.0"}}},
+		{name: "vanity path", tag: TagEvent{Host: "github.com", Repo: "acme/kit", Tag: "v1.0.0"}, want: []Target{{Module: "go.acme.dev/kit", Version: "v1.0.0"}}},
+		{name: "repository from module path", tag: TagEvent{Host: "github.com", Repo: "acme/tools", Tag: "v0.1.0"}, want: []Target{{Module: "github.com/acme/tools", Version: "v0.1.0"}}},
+		{name: "unknown prefix", tag: TagEvent{Host: "github.com", Repo: "acme/lib", Tag: "web/v1.0.0"}, wantReason: "no module of acme/lib is released by tag web/v1.0.0"},
+		{name: "other host", tag: TagEvent{Host: "github.example.com", Repo: "acme/kit", Tag: "v1.0.0"}, wantReason: "acme/kit is not a module repository"},
+		{name: "not semver", tag: TagEvent{Host: "github.com", Repo: "acme/lib", Tag: "release-7"}, wantReason: "tag release-7 is not a semantic version"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, reason := Targets(testManifest(), tt.tag)
+			if !reflect.DeepEqual(got, tt.want) || !strings.Contains(reason, tt.wantReason) {
+				t.Errorf("Targets() = %+v, %q, want %+v, %q", got, reason, tt.want, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestServerWebhook(t *testing.T) {
+	released := make(chan Job, 4)
+	fail := true
+	srv, err := New(testSecret,
+		func() (*manifest.Manifest, error) { return testManifest(), nil },
+		func(ctx context.Context, job Job) error {
+			released <- job
+			if fail {
+				fail = false
+				return errors.New("proxy not ready")
+			}
+			return nil
+		},
+		WithQueueSize(1),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	handler := srv.Handler()
+
+	deliver := func(event, body string, sign bool) (int, response) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, DefaultPath, strings.NewReader(body))
+		req.Header.Set(HeaderEvent, event)
+		req.Header.Set(HeaderDelivery, "delivery-1")
+		if sign {
+			req.Header.Set(HeaderSignature, Sign(testSecret, []byte(body)))
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		var reply response
+		if err := json.Unmarshal(rec.Body.Bytes(), &reply); err != nil {
+			t.Fatalf("invalid reply %q: %v", rec.Body.String(), err)
+		}
+		return rec.Code, reply
+	}
+	push := func(tag string) string {
+		return `{"ref":"refs/tags/` + tag + `","repository":{"full_name":"acme/lib","html_url":"https://github.com/acme/lib"}}`
+	}
+
+	if code, reply := deliver("push", push("v1.4.0"), false); code != http.StatusUnauthorized || !strings.Contains(reply.Reason, "missing") {
+		t.Errorf("unsigned delivery = %d %+v", code, reply)
+	}
+	if code, reply := deliver("ping", `{}`, true); code != http.StatusOK || reply.Status != "pong" {
+		t.Errorf("ping = %d %+v", code, reply)
+	}
+	if code, reply := deliver("push", push("v1.5.0-rc.1"), true); code != http.StatusOK || reply.Status != "ignored" || len(reply.Skipped) != 1 {
+		t.Errorf("pre-release = %d %+v", code, reply)
+	}
+
+	want := []Target{{Module: "github.com/acme/lib", Version: "v1.4.0"}}
+	if code, reply := deliver("push", push("v1.4.0"), true); code != http.StatusAccepted || !reflect.DeepEqual(reply.Queued, want) {
+		t.Errorf("tag push = %d %+v", code, reply)
+	}
+	if code, reply := deliver("create", `{"ref":"v1.4.0","ref_type":"tag","repository":{"full_name":"acme/lib"}}`, true); code != http.StatusOK || reply.Status != "ignored" {
+		t.Errorf("duplicate tag = %d %+v", code, reply)
+	}
+	if code, reply := deliver("push", push("v2.0.0"), true); code != http.StatusServiceUnavailable || !strings.Contains(strings.Join(reply.Skipped, ""), "queue is full") {
+		t.Errorf("full queue = %d %+v", code, reply)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go srv.Work(ctx)
+
+	job := waitJob(t, released)
+	if job.Target != want[0] || job.Event != "push" || job.Tag != "v1.4.0" || job.Delivery != "delivery-1" {
+		t.Errorf("released job = %+v", job)
+	}
+
+	// The failed release can be triggered again by a redelivery
+	deadline := time.Now().Add(time.Second)
+	for {
+		code, _ := deliver("push", push("v1.4.0"), true)
+		if code == http.StatusAccepted {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("redelivery after a failed release = %d, want 202", code)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	waitJob(t, released)
+}
+
+func TestServerWebhookPartiallyQueued(t *testing.T) {
+	// Both modules live at the root of acme/lib, so one tag releases both
+	m := &manifest.Manifest{Modules: []manifest.Module{
+		{Module: "github.com/acme/lib", Repo: "acme/lib"},
+		{Module: "go.acme.dev/lib", Repo: "acme/lib"},
+	}}
+	srv, err := New(testSecret,
+		func() (*manifest.Manifest, error) { return m, nil },
+		func(ctx context.Context, job Job) error { return nil },
+		WithQueueSize(1),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	body := `{"ref":"refs/tags/v1.4.0","repository":{"full_name":"acme/lib","html_url":"https://github.com/acme/lib"}}`
+	req := httptest.NewRequest(http.MethodPost, DefaultPath, strings.NewReader(body))
+	req.Header.Set(HeaderEvent, "push")
+	req.Header.Set(HeaderSignature, Sign(testSecret, []byte(body)))
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, req)
+
+	var reply response
+	if err := json.Unmarshal(rec.Body.Bytes(), &reply); err != nil {
+		t.Fatalf("invalid reply %q: %v", rec.Body.String(), err)
+	}
+	if rec.Code != http.StatusServiceUnavailable || reply.Status != "partial" || len(reply.Queued) != 1 || len(reply.Skipped) != 1 {
+		t.Errorf("delivery with a full queue = %d %+v, want 503 with one target queued", rec.Code, reply)
+	}
+}
+
+func waitJob(t *testing.T, jobs <-chan Job) Job {
+	t.Helper()
+	select {
+	case job := <-jobs:
+		return job
+	case <-time.After(2 * time.Second):
+		t.Fatal("no job released")
+		return Job{}
+	}
+}
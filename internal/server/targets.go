@@ -0,0 +1,116 @@
+package server
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/goliatone/cascade/internal/manifest"
+	"golang.org/x/mod/semver"
+)
+
+// majorSuffix matches the /vN element of module paths for major versions 2+.
+var majorSuffix = regexp.MustCompile(`/(v[2-9]|v[1-9][0-9]+)$`)
+
+// Target is a module release a tag maps to.
+type Target struct {
+	Module  string `json:"module"`
+	Version string `json:"version"`
+}
+
+// Key identifies the release, e.g. "example.com/lib@v1.2.0".
+func (t Target) Key() string {
+	return t.Module + "@" + t.Version
+}
+
+// Targets returns the manifest modules that tag releases. A module matches
+// when it lives in the tagged repository, its directory in the repository
+// matches the tag prefix ("api/v1.2.0" releases the module in api/), and its
+// major version suffix matches the version. Modules whose paths do not start
+// with their repository, such as vanity paths, are treated as living at the
+// repository root. The reason explains why no module matched.
+func Targets(m *manifest.Manifest, tag TagEvent) ([]Target, string) {
+	prefix, version := "", tag.Tag
+	if i := strings.LastIndex(tag.Tag, "/"); i >= 0 {
+		prefix, version = tag.Tag[:i], tag.Tag[i+1:]
+	}
+	if !semver.IsValid(version) {
+		return nil, fmt.Sprintf("tag %s is not a semantic version", tag.Tag)
+	}
+	if m == nil {
+		return nil, "no manifest loaded"
+	}
+
+	var targets []Target
+	inRepo := false
+	for _, mod := range m.Modules {
+		host, repo := moduleRepository(mod)
+		if repo != tag.Repo || (host != "" && tag.Host != "" && host != tag.Host) {
+			continue
+		}
+		inRepo = true
+
+		path := majorSuffix.ReplaceAllString(mod.Module, "")
+		dir := ""
+		if root := tag.Host + "/" + tag.Repo; strings.HasPrefix(strings.ToLower(path), root+"/") {
+			dir = path[len(root)+1:]
+		}
+		if dir != prefix || !majorMatches(mod.Module, version) {
+			continue
+		}
+		targets = append(targets, Target{Module: mod.Module, Version: version})
+	}
+
+	switch {
+	case len(targets) > 0:
+		return targets, ""
+	case inRepo:
+		return nil, fmt.Sprintf("no module of %s is released by tag %s", tag.Repo, tag.Tag)
+	default:
+		return nil, fmt.Sprintf("%s is not a module repository in the manifest", tag.Repo)
+	}
+}
+
+// moduleRepository returns the lowercased host (when known) and owner/repo
+// path of the repository a manifest module lives in. Modules without a repo
+// are assumed to live in the repository their module path starts with.
+func moduleRepository(mod manifest.Module) (string, string) {
+	repo := strings.ToLower(strings.TrimSpace(mod.Repo))
+	if repo == "" {
+		segments := strings.Split(strings.ToLower(mod.Module), "/")
+		if len(segments) < 3 {
+			return "", ""
+		}
+		return segments[0], strings.Join(segments[1:3], "/")
+	}
+
+	host := ""
+	if i := strings.Index(repo, "://"); i >= 0 {
+		host, repo, _ = strings.Cut(repo[i+3:], "/")
+		if at := strings.LastIndex(host, "@"); at >= 0 {
+			host = host[at+1:]
+		}
+		host, _, _ = strings.Cut(host, ":")
+	} else if at, colon := strings.Index(repo, "@"), strings.Index(repo, ":"); at > 0 && colon > at {
+		host, repo = repo[at+1:colon], repo[colon+1:]
+	}
+
+	segments := strings.Split(strings.Trim(strings.TrimSuffix(repo, ".git"), "/"), "/")
+	if host == "" && len(segments) > 2 {
+		host, segments = segments[0], segments[1:]
+	}
+	return host, strings.Join(segments, "/")
+}
+
+// majorMatches reports whether version can be a release of modulePath: /vN
+// paths need major version N, other paths v0 or v1 (or +incompatible).
+func majorMatches(modulePath, version string) bool {
+	if match := majorSuffix.FindStringSubmatch(modulePath); match != nil {
+		return semver.Major(version) == match[1]
+	}
+	switch semver.Major(version) {
+	case "v0", "v1":
+		return true
+	}
+	return strings.HasSuffix(version, "+incompatible")
+}
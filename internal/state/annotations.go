@@ -0,0 +1,241 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Annotation is a free-form note an operator attached to a cascade or one of
+// its items, such as "waiting on upstream fix". Annotations are stored apart
+// from the item states, so the runs that rewrite those keep them.
+type Annotation struct {
+	// Repo is the dependent the note is about; empty annotates the cascade.
+	Repo    string    `json:"repo,omitempty"`
+	Note    string    `json:"note"`
+	Author  string    `json:"author,omitempty"`
+	Created time.Time `json:"created"`
+}
+
+// AnnotationStore is implemented by managers and storage backends that keep
+// operator annotations. It is optional; callers should type-assert before use.
+type AnnotationStore interface {
+	// LoadAnnotations returns the annotations of module@version, oldest first.
+	LoadAnnotations(module, version string) ([]Annotation, error)
+	// AddAnnotation appends annotation to module@version.
+	AddAnnotation(module, version string, annotation Annotation) error
+	// ClearAnnotations removes the annotations of repo, or of the cascade
+	// itself when repo is empty.
+	ClearAnnotations(module, version, repo string) error
+}
+
+// AnnotationsFor returns the annotations about repo, or about the cascade
+// itself when repo is empty, keeping their order.
+func AnnotationsFor(annotations []Annotation, repo string) []Annotation {
+	var matched []Annotation
+	for _, annotation := range annotations {
+		if annotation.Repo == repo {
+			matched = append(matched, annotation)
+		}
+	}
+	return matched
+}
+
+// LoadAnnotations returns the annotations when the underlying storage keeps them.
+func (m *manager) LoadAnnotations(module, version string) ([]Annotation, error) {
+	store, err := m.annotationStore(module, version)
+	if err != nil {
+		return nil, err
+	}
+	return store.LoadAnnotations(module, version)
+}
+
+// AddAnnotation records annotation when the underlying storage keeps annotations.
+func (m *manager) AddAnnotation(module, version string, annotation Annotation) error {
+	store, err := m.annotationStore(module, version)
+	if err != nil {
+		return err
+	}
+	annotation.Note = strings.TrimSpace(annotation.Note)
+	if annotation.Note == "" {
+		return fmt.Errorf("annotation note cannot be empty")
+	}
+	if annotation.Created.IsZero() {
+		annotation.Created = m.clock.Now()
+	}
+	annotation.Created = annotation.Created.UTC()
+
+	m.logger.Debug("Adding annotation", "module", module, "version", version, "repo", annotation.Repo)
+	return store.AddAnnotation(module, version, annotation)
+}
+
+// ClearAnnotations removes annotations when the underlying storage keeps them.
+func (m *manager) ClearAnnotations(module, version, repo string) error {
+	store, err := m.annotationStore(module, version)
+	if err != nil {
+		return err
+	}
+
+	m.logger.Debug("Clearing annotations", "module", module, "version", version, "repo", repo)
+	return store.ClearAnnotations(module, version, repo)
+}
+
+func (m *manager) annotationStore(module, version string) (AnnotationStore, error) {
+	if err := validateModuleVersion(module, version); err != nil {
+		return nil, err
+	}
+	store, ok := m.storage.(AnnotationStore)
+	if !ok {
+		return nil, ErrNotImplemented
+	}
+	return store, nil
+}
+
+// annotationsPath returns the file holding the annotations of module@version,
+// next to its summary.
+func (fs *filesystemStorage) annotationsPath(module, version string) string {
+	return filepath.Join(fs.rootDir, module, version, "annotations.json")
+}
+
+// LoadAnnotations reads annotations.json.
+func (fs *filesystemStorage) LoadAnnotations(module, version string) ([]Annotation, error) {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+	return fs.readAnnotations(module, version)
+}
+
+// AddAnnotation appends annotation to annotations.json.
+func (fs *filesystemStorage) AddAnnotation(module, version string, annotation Annotation) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	annotations, err := fs.readAnnotations(module, version)
+	if err != nil {
+		return err
+	}
+	return fs.writeAnnotations(module, version, append(annotations, annotation))
+}
+
+// ClearAnnotations removes repo's annotations from annotations.json.
+func (fs *filesystemStorage) ClearAnnotations(module, version, repo string) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	annotations, err := fs.readAnnotations(module, version)
+	if err != nil {
+		return err
+	}
+	kept := withoutAnnotations(annotations, repo)
+	if len(kept) == len(annotations) {
+		return nil
+	}
+	return fs.writeAnnotations(module, version, kept)
+}
+
+func (fs *filesystemStorage) readAnnotations(module, version string) ([]Annotation, error) {
+	path := fs.annotationsPath(module, version)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []Annotation{}, nil
+		}
+		return nil, fmt.Errorf("failed to read annotations from %s: %w", path, err)
+	}
+	return decodeAnnotations(path, data)
+}
+
+func (fs *filesystemStorage) writeAnnotations(module, version string, annotations []Annotation) error {
+	path := fs.annotationsPath(module, version)
+	if err := ensureDir(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	data, err := encodeAnnotations(annotations)
+	if err != nil {
+		return err
+	}
+	if err := atomicWrite(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to save annotations to %s: %w", path, err)
+	}
+	return nil
+}
+
+func (o *objectStorage) annotationsKey(module, version string) string {
+	return o.key(path.Join(module, version, "annotations.json"))
+}
+
+// LoadAnnotations reads annotations.json.
+func (o *objectStorage) LoadAnnotations(module, version string) ([]Annotation, error) {
+	ctx, cancel := o.context()
+	defer cancel()
+
+	key := o.annotationsKey(module, version)
+	data, _, err := o.store.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return []Annotation{}, nil
+		}
+		return nil, fmt.Errorf("failed to read annotations from %s: %w", key, err)
+	}
+	return decodeAnnotations(key, data)
+}
+
+// AddAnnotation appends annotation to annotations.json.
+func (o *objectStorage) AddAnnotation(module, version string, annotation Annotation) error {
+	return o.updateAnnotations(module, version, func(annotations []Annotation) []Annotation {
+		return append(annotations, annotation)
+	})
+}
+
+// ClearAnnotations removes repo's annotations from annotations.json.
+func (o *objectStorage) ClearAnnotations(module, version, repo string) error {
+	return o.updateAnnotations(module, version, func(annotations []Annotation) []Annotation {
+		return withoutAnnotations(annotations, repo)
+	})
+}
+
+func (o *objectStorage) updateAnnotations(module, version string, change func([]Annotation) []Annotation) error {
+	key := o.annotationsKey(module, version)
+	return o.update(key, func(current []byte) ([]byte, error) {
+		annotations := []Annotation{}
+		if current != nil {
+			var err error
+			if annotations, err = decodeAnnotations(key, current); err != nil {
+				return nil, err
+			}
+		}
+		return encodeAnnotations(change(annotations))
+	})
+}
+
+func encodeAnnotations(annotations []Annotation) ([]byte, error) {
+	sort.SliceStable(annotations, func(i, j int) bool { return annotations[i].Created.Before(annotations[j].Created) })
+	data, err := json.MarshalIndent(annotations, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal annotations: %w", err)
+	}
+	return data, nil
+}
+
+func decodeAnnotations(source string, data []byte) ([]Annotation, error) {
+	var annotations []Annotation
+	if err := json.Unmarshal(data, &annotations); err != nil {
+		return nil, fmt.Errorf("%w: annotations %s: %v", ErrCorrupt, source, err)
+	}
+	return annotations, nil
+}
+
+func withoutAnnotations(annotations []Annotation, repo string) []Annotation {
+	kept := make([]Annotation, 0, len(annotations))
+	for _, annotation := range annotations {
+		if annotation.Repo != repo {
+			kept = append(kept, annotation)
+		}
+	}
+	return kept
+}
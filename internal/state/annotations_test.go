@@ -0,0 +1,80 @@
+package state
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestManagerAnnotations(t *testing.T) {
+	fsStorage, err := NewFilesystemStorage(t.TempDir(), nopLogger{})
+	if err != nil {
+		t.Fatalf("failed to create filesystem storage: %v", err)
+	}
+	backends := map[string]Storage{
+		"filesystem": fsStorage,
+		"object":     NewObjectStorage(newMemoryObjectStore(), "ci/", 0, nopLogger{}),
+	}
+	for name, storage := range backends {
+		t.Run(name, func(t *testing.T) {
+			mgr := NewManager(WithStorage(storage))
+			store, ok := mgr.(AnnotationStore)
+			if !ok {
+				t.Fatal("manager does not implement AnnotationStore")
+			}
+
+			module, version := "example.com/lib", "v1.2.0"
+			annotations, err := store.LoadAnnotations(module, version)
+			if err != nil || len(annotations) != 0 {
+				t.Fatalf("LoadAnnotations() = %v, %v, want none", annotations, err)
+			}
+
+			noted := time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC)
+			for _, annotation := range []Annotation{
+				{Repo: "example/api", Note: " waiting on upstream fix ", Author: "sam", Created: noted.Add(time.Hour)},
+				{Note: "paused for the freeze", Created: noted},
+				{Repo: "example/web", Note: "flaky tests", Created: noted.Add(2 * time.Hour)},
+			} {
+				if err := store.AddAnnotation(module, version, annotation); err != nil {
+					t.Fatalf("AddAnnotation() error = %v", err)
+				}
+			}
+			if err := store.AddAnnotation(module, version, Annotation{Repo: "example/api", Note: "  "}); err == nil {
+				t.Fatal("expected an empty note to be rejected")
+			}
+
+			annotations, err = store.LoadAnnotations(module, version)
+			if err != nil {
+				t.Fatalf("LoadAnnotations() error = %v", err)
+			}
+			want := []Annotation{
+				{Note: "paused for the freeze", Created: noted},
+				{Repo: "example/api", Note: "waiting on upstream fix", Author: "sam", Created: noted.Add(time.Hour)},
+				{Repo: "example/web", Note: "flaky tests", Created: noted.Add(2 * time.Hour)},
+			}
+			if !reflect.DeepEqual(annotations, want) {
+				t.Fatalf("LoadAnnotations() = %+v, want %+v", annotations, want)
+			}
+			if got := AnnotationsFor(annotations, ""); len(got) != 1 || got[0].Note != "paused for the freeze" {
+				t.Errorf("AnnotationsFor(cascade) = %+v", got)
+			}
+
+			if err := store.ClearAnnotations(module, version, "example/api"); err != nil {
+				t.Fatalf("ClearAnnotations() error = %v", err)
+			}
+			annotations, err = store.LoadAnnotations(module, version)
+			if err != nil || len(annotations) != 2 || len(AnnotationsFor(annotations, "example/api")) != 0 {
+				t.Fatalf("LoadAnnotations() after clearing = %+v, %v", annotations, err)
+			}
+
+			remote := NewManager(WithStorage(ReadOnly(storage))).(AnnotationStore)
+			if annotations, err := remote.LoadAnnotations(module, version); err != nil || len(annotations) != 2 {
+				t.Errorf("read-only LoadAnnotations() = %+v, %v", annotations, err)
+			}
+			if err := remote.AddAnnotation(module, version, Annotation{Note: "x"}); !errors.Is(err, ErrReadOnly) {
+				t.Errorf("read-only AddAnnotation() error = %v, want ErrReadOnly", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"cloud.google.com/go/storage"
+	"golang.org/x/oauth2"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+)
+
+// GCSOptions configures a Google Cloud Storage object store.
+type GCSOptions struct {
+	// Bucket is the bucket holding the state objects.
+	Bucket string
+
+	// Endpoint overrides the base URL of the JSON API, such as
+	// http://localhost:4443 for an emulator. Default: Google Cloud Storage
+	Endpoint string
+
+	// TokenSource authorizes the requests. Default: Application Default
+	// Credentials, which cover GOOGLE_APPLICATION_CREDENTIALS, gcloud's
+	// credentials, workload identity federation, and the metadata server of
+	// GCE, GKE, and Cloud Run workloads
+	TokenSource oauth2.TokenSource
+}
+
+// gcsStore implements ObjectStore with the Cloud Storage client, using
+// generations as object versions and generation preconditions for
+// conditional writes.
+type gcsStore struct {
+	bucket *storage.BucketHandle
+}
+
+// NewGCSStore returns an ObjectStore for a Google Cloud Storage bucket.
+func NewGCSStore(opts GCSOptions) (ObjectStore, error) {
+	if opts.Bucket == "" {
+		return nil, fmt.Errorf("gcs bucket is required")
+	}
+
+	clientOpts := []option.ClientOption{storage.WithJSONReads()}
+	if opts.Endpoint != "" {
+		clientOpts = append(clientOpts, option.WithEndpoint(strings.TrimRight(opts.Endpoint, "/")+"/storage/v1/"))
+	}
+	if opts.TokenSource != nil {
+		clientOpts = append(clientOpts, option.WithTokenSource(opts.TokenSource))
+	}
+
+	// Creating the client only locates the credentials; tokens are fetched on
+	// the first request.
+	client, err := storage.NewClient(context.Background(), clientOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("create gcs client: %w", err)
+	}
+	return &gcsStore{bucket: client.Bucket(opts.Bucket)}, nil
+}
+
+// Get downloads an object; its generation is the version.
+func (g *gcsStore) Get(ctx context.Context, key string) ([]byte, string, error) {
+	reader, err := g.bucket.Object(key).NewReader(ctx)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			return nil, "", ErrNotFound
+		}
+		return nil, "", fmt.Errorf("gcs get %s: %w", key, err)
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, strconv.FormatInt(reader.Attrs.Generation, 10), nil
+}
+
+// Put uploads an object in a single request. A generation match of 0 requires
+// the object to be absent.
+func (g *gcsStore) Put(ctx context.Context, key string, data []byte, cond Precondition) (string, error) {
+	object := g.bucket.Object(key)
+	switch {
+	case cond.IfVersion != "":
+		generation, err := strconv.ParseInt(cond.IfVersion, 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("gcs put %s: invalid generation %q", key, cond.IfVersion)
+		}
+		object = object.If(storage.Conditions{GenerationMatch: generation})
+	case cond.IfAbsent:
+		object = object.If(storage.Conditions{DoesNotExist: true})
+	}
+
+	// Cancelling the context is the only way to abandon a Writer, so give it
+	// its own that is cancelled when the write fails.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	writer := object.NewWriter(ctx)
+	writer.ContentType = "application/json"
+	writer.ChunkSize = 0
+	if _, err := writer.Write(data); err != nil {
+		return "", gcsError(err, "put", key)
+	}
+	if err := writer.Close(); err != nil {
+		return "", gcsError(err, "put", key)
+	}
+	return strconv.FormatInt(writer.Attrs().Generation, 10), nil
+}
+
+// Delete removes an object.
+func (g *gcsStore) Delete(ctx context.Context, key string) error {
+	err := g.bucket.Object(key).Delete(ctx)
+	if err != nil && !errors.Is(err, storage.ErrObjectNotExist) {
+		return fmt.Errorf("gcs delete %s: %w", key, err)
+	}
+	return nil
+}
+
+// List pages through the objects under prefix.
+func (g *gcsStore) List(ctx context.Context, prefix string) ([]string, error) {
+	query := &storage.Query{Prefix: prefix}
+	if err := query.SetAttrSelection([]string{"Name"}); err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	objects := g.bucket.Objects(ctx, query)
+	for {
+		attrs, err := objects.Next()
+		if errors.Is(err, iterator.Done) {
+			return keys, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("gcs list %s: %w", prefix, err)
+		}
+		keys = append(keys, attrs.Name)
+	}
+}
+
+// gcsError maps a failed generation precondition to ErrConflict.
+func gcsError(err error, op, key string) error {
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) && apiErr.Code == http.StatusPreconditionFailed {
+		return ErrConflict
+	}
+	return fmt.Errorf("gcs %s %s: %w", op, key, err)
+}
@@ -0,0 +1,167 @@
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+// fakeGCS serves the subset of the GCS JSON API the store uses, with generation
+// preconditions.
+type fakeGCS struct {
+	mu          sync.Mutex
+	objects     map[string]string
+	generations map[string]int64
+	next        int64
+}
+
+func (f *fakeGCS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.Header.Get("Authorization") != "Bearer test-token" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	const objects = "/storage/v1/b/state-bucket/o"
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/upload"+objects:
+		name, body, err := readGCSUpload(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if match := r.URL.Query().Get("ifGenerationMatch"); match != "" {
+			want, _ := strconv.ParseInt(match, 10, 64)
+			if f.generations[name] != want {
+				http.Error(w, "conditionNotMet", http.StatusPreconditionFailed)
+				return
+			}
+		}
+		f.next++
+		f.objects[name] = string(body)
+		f.generations[name] = f.next
+		json.NewEncoder(w).Encode(map[string]string{"bucket": "state-bucket", "name": name, "generation": strconv.FormatInt(f.next, 10)})
+	case r.Method == http.MethodGet && r.URL.Path == objects:
+		var names []string
+		for name := range f.objects {
+			if strings.HasPrefix(name, r.URL.Query().Get("prefix")) {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		items := make([]map[string]string, 0, len(names))
+		for _, name := range names {
+			items = append(items, map[string]string{"name": name})
+		}
+		json.NewEncoder(w).Encode(map[string]any{"items": items})
+	case strings.HasPrefix(r.URL.Path, objects+"/"):
+		name := strings.TrimPrefix(r.URL.Path, objects+"/")
+		data, ok := f.objects[name]
+		if !ok {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		if r.Method == http.MethodDelete {
+			delete(f.objects, name)
+			delete(f.generations, name)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Header().Set("X-Goog-Generation", strconv.FormatInt(f.generations[name], 10))
+		io.WriteString(w, data)
+	default:
+		http.Error(w, "unexpected request "+r.Method+" "+r.URL.Path, http.StatusBadRequest)
+	}
+}
+
+// readGCSUpload returns the object name and data of a multipart upload, whose
+// first part is the object's JSON metadata and second part its data.
+func readGCSUpload(r *http.Request) (string, []byte, error) {
+	_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return "", nil, err
+	}
+	parts := multipart.NewReader(r.Body, params["boundary"])
+
+	metadata, err := parts.NextPart()
+	if err != nil {
+		return "", nil, err
+	}
+	var object struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(metadata).Decode(&object); err != nil {
+		return "", nil, err
+	}
+	media, err := parts.NextPart()
+	if err != nil {
+		return "", nil, err
+	}
+	data, err := io.ReadAll(media)
+	return object.Name, data, err
+}
+
+func TestGCSStore_ConditionalWrites(t *testing.T) {
+	fake := &fakeGCS{objects: make(map[string]string), generations: make(map[string]int64)}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	store, err := NewGCSStore(GCSOptions{
+		Bucket:      "state-bucket",
+		Endpoint:    server.URL,
+		TokenSource: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "test-token"}),
+	})
+	if err != nil {
+		t.Fatalf("NewGCSStore: %v", err)
+	}
+	ctx := context.Background()
+
+	// keys hold slashes, which the object URL must escape
+	key := "ci/example.com/lib/v1.0.0/summary.json"
+	generation, err := store.Put(ctx, key, []byte(`{}`), Precondition{IfAbsent: true})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := store.Put(ctx, key, []byte(`{}`), Precondition{IfAbsent: true}); !errors.Is(err, ErrConflict) {
+		t.Fatalf("create over an existing object: expected ErrConflict, got %v", err)
+	}
+	if _, err := store.Put(ctx, key, []byte(`{"v":2}`), Precondition{IfVersion: generation}); err != nil {
+		t.Fatalf("update with the current generation: %v", err)
+	}
+	if _, err := store.Put(ctx, key, []byte(`{"v":3}`), Precondition{IfVersion: generation}); !errors.Is(err, ErrConflict) {
+		t.Fatalf("update with a stale generation: expected ErrConflict, got %v", err)
+	}
+
+	data, current, err := store.Get(ctx, key)
+	if err != nil || string(data) != `{"v":2}` || current == generation {
+		t.Fatalf("Get = %q, generation %q, %v", data, current, err)
+	}
+
+	keys, err := store.List(ctx, "ci/")
+	if err != nil || len(keys) != 1 || keys[0] != key {
+		t.Fatalf("List = %v, %v", keys, err)
+	}
+
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, _, err := store.Get(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get after Delete: expected ErrNotFound, got %v", err)
+	}
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete of a missing object: %v", err)
+	}
+}
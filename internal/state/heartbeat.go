@@ -0,0 +1,165 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Heartbeat is the liveness record for a work item that is still executing.
+// It is refreshed periodically while the item runs and cleared when it finishes,
+// so a heartbeat that stops advancing indicates a hung or killed process.
+type Heartbeat struct {
+	Repo      string    `json:"repo"`
+	Branch    string    `json:"branch"`
+	Phase     string    `json:"phase"`
+	PID       int       `json:"pid"`
+	StartedAt time.Time `json:"started_at"`
+	LastBeat  time.Time `json:"last_beat"`
+}
+
+// Stale reports whether the heartbeat has not advanced within threshold of now.
+func (h Heartbeat) Stale(now time.Time, threshold time.Duration) bool {
+	return threshold > 0 && now.Sub(h.LastBeat) > threshold
+}
+
+// StaleHeartbeats returns the heartbeats that have not advanced within threshold.
+func StaleHeartbeats(heartbeats []Heartbeat, now time.Time, threshold time.Duration) []Heartbeat {
+	var stale []Heartbeat
+	for _, hb := range heartbeats {
+		if hb.Stale(now, threshold) {
+			stale = append(stale, hb)
+		}
+	}
+	return stale
+}
+
+// HeartbeatStore is implemented by managers and storage backends that track
+// in-flight work items. It is optional; callers should type-assert before use.
+type HeartbeatStore interface {
+	SaveHeartbeat(module, version string, hb Heartbeat) error
+	ClearHeartbeat(module, version, repo string) error
+	LoadHeartbeats(module, version string) ([]Heartbeat, error)
+}
+
+// SaveHeartbeat records liveness for an in-flight work item.
+func (m *manager) SaveHeartbeat(module, version string, hb Heartbeat) error {
+	if err := validateModuleVersion(module, version); err != nil {
+		return err
+	}
+	store, ok := m.storage.(HeartbeatStore)
+	if !ok {
+		return ErrNotImplemented
+	}
+
+	hb.StartedAt = hb.StartedAt.UTC()
+	hb.LastBeat = hb.LastBeat.UTC()
+	if hb.LastBeat.IsZero() {
+		hb.LastBeat = m.clock.Now().UTC()
+	}
+	return store.SaveHeartbeat(module, version, hb)
+}
+
+// ClearHeartbeat removes the heartbeat for a finished work item.
+func (m *manager) ClearHeartbeat(module, version, repo string) error {
+	if err := validateModuleVersion(module, version); err != nil {
+		return err
+	}
+	store, ok := m.storage.(HeartbeatStore)
+	if !ok {
+		return ErrNotImplemented
+	}
+	return store.ClearHeartbeat(module, version, repo)
+}
+
+// LoadHeartbeats returns the heartbeats of work items that are still in flight.
+func (m *manager) LoadHeartbeats(module, version string) ([]Heartbeat, error) {
+	if err := validateModuleVersion(module, version); err != nil {
+		return nil, err
+	}
+	store, ok := m.storage.(HeartbeatStore)
+	if !ok {
+		return nil, ErrNotImplemented
+	}
+	return store.LoadHeartbeats(module, version)
+}
+
+// heartbeatPath returns the file path for a work item heartbeat.
+func (fs *filesystemStorage) heartbeatPath(module, version, repo string) string {
+	name := filepath.Base(fs.itemPath(module, version, repo))
+	return filepath.Join(fs.rootDir, module, version, "heartbeats", name)
+}
+
+// SaveHeartbeat writes a heartbeat atomically, replacing any previous beat.
+func (fs *filesystemStorage) SaveHeartbeat(module, version string, hb Heartbeat) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	path := fs.heartbeatPath(module, version, hb.Repo)
+	if err := ensureDir(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create heartbeats directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(hb, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal heartbeat: %w", err)
+	}
+	if err := atomicWrite(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to save heartbeat to %s: %w", path, err)
+	}
+	return nil
+}
+
+// ClearHeartbeat deletes a heartbeat file; a missing file is not an error.
+func (fs *filesystemStorage) ClearHeartbeat(module, version, repo string) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	path := fs.heartbeatPath(module, version, repo)
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to clear heartbeat %s: %w", path, err)
+	}
+	return nil
+}
+
+// LoadHeartbeats loads every heartbeat for a module/version pair, sorted by repo.
+func (fs *filesystemStorage) LoadHeartbeats(module, version string) ([]Heartbeat, error) {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+
+	dir := filepath.Join(fs.rootDir, module, version, "heartbeats")
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []Heartbeat{}, nil
+		}
+		return nil, fmt.Errorf("failed to read heartbeats directory %s: %w", dir, err)
+	}
+
+	heartbeats := make([]Heartbeat, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			fs.logger.Error("failed to read heartbeat file", "path", path, "error", err)
+			continue
+		}
+
+		var hb Heartbeat
+		if err := json.Unmarshal(data, &hb); err != nil {
+			fs.logger.Error("failed to unmarshal heartbeat", "path", path, "error", err)
+			continue
+		}
+		heartbeats = append(heartbeats, hb)
+	}
+
+	sort.Slice(heartbeats, func(i, j int) bool { return heartbeats[i].Repo < heartbeats[j].Repo })
+	return heartbeats, nil
+}
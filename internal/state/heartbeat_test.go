@@ -0,0 +1,61 @@
+package state
+
+import (
+	"testing"
+	"time"
+)
+
+func TestManagerHeartbeats(t *testing.T) {
+	storage, err := NewFilesystemStorage(t.TempDir(), nopLogger{})
+	if err != nil {
+		t.Fatalf("failed to create filesystem storage: %v", err)
+	}
+	store := NewManager(WithStorage(storage)).(HeartbeatStore)
+
+	module, version := "example.com/lib", "v1.2.3"
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	for _, hb := range []Heartbeat{
+		{Repo: "example/b", Phase: "tests", StartedAt: start, LastBeat: start.Add(time.Minute)},
+		{Repo: "example/a", Phase: "clone", StartedAt: start, LastBeat: start},
+	} {
+		if err := store.SaveHeartbeat(module, version, hb); err != nil {
+			t.Fatalf("SaveHeartbeat() error = %v", err)
+		}
+	}
+
+	// A later beat replaces the previous one
+	if err := store.SaveHeartbeat(module, version, Heartbeat{Repo: "example/a", Phase: "tidy", StartedAt: start, LastBeat: start.Add(2 * time.Minute)}); err != nil {
+		t.Fatalf("SaveHeartbeat() error = %v", err)
+	}
+
+	heartbeats, err := store.LoadHeartbeats(module, version)
+	if err != nil {
+		t.Fatalf("LoadHeartbeats() error = %v", err)
+	}
+	if len(heartbeats) != 2 || heartbeats[0].Repo != "example/a" || heartbeats[0].Phase != "tidy" {
+		t.Fatalf("unexpected heartbeats: %+v", heartbeats)
+	}
+
+	stale := StaleHeartbeats(heartbeats, start.Add(3*time.Minute), 90*time.Second)
+	if len(stale) != 1 || stale[0].Repo != "example/b" {
+		t.Fatalf("StaleHeartbeats() = %+v", stale)
+	}
+
+	if err := store.ClearHeartbeat(module, version, "example/a"); err != nil {
+		t.Fatalf("ClearHeartbeat() error = %v", err)
+	}
+	if err := store.ClearHeartbeat(module, version, "example/a"); err != nil {
+		t.Fatalf("clearing a missing heartbeat should succeed, got %v", err)
+	}
+	heartbeats, err = store.LoadHeartbeats(module, version)
+	if err != nil || len(heartbeats) != 1 {
+		t.Fatalf("LoadHeartbeats() after clear = %+v, %v", heartbeats, err)
+	}
+
+	// Heartbeats do not show up as item states
+	items, err := storage.LoadItemStates(module, version)
+	if err != nil || len(items) != 0 {
+		t.Fatalf("LoadItemStates() = %+v, %v", items, err)
+	}
+}
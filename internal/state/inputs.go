@@ -0,0 +1,185 @@
+package state
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
+// Inputs is a copy of everything a run was started with: the manifest as read
+// from disk, the resolved configuration with secrets redacted, and the cascade
+// version. Inputs are content addressed, so runs started from identical inputs
+// share one copy.
+type Inputs struct {
+	// Digest is the SHA-256 of the cascade version, manifest, and config.
+	Digest         string          `json:"digest"`
+	CascadeVersion string          `json:"cascade_version"`
+	ManifestPath   string          `json:"manifest_path,omitempty"`
+	Manifest       string          `json:"manifest"`
+	Config         json.RawMessage `json:"config,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// NewInputs captures run inputs and computes their digest. config is marshalled
+// to JSON, so callers must redact secrets before passing it in.
+func NewInputs(cascadeVersion, manifestPath string, manifest []byte, config any) (*Inputs, error) {
+	inputs := &Inputs{
+		CascadeVersion: cascadeVersion,
+		ManifestPath:   manifestPath,
+		Manifest:       string(manifest),
+	}
+	if config != nil {
+		data, err := json.Marshal(config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal config: %w", err)
+		}
+		inputs.Config = data
+	}
+	inputs.Digest = inputs.ComputeDigest()
+	return inputs, nil
+}
+
+// ComputeDigest hashes the fields that determine a run's behaviour. The manifest
+// path and capture time are left out, so a moved but unchanged manifest keeps
+// its digest. The config is compacted first, since storage indents it.
+func (in *Inputs) ComputeDigest() string {
+	config := in.Config
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, in.Config); err == nil {
+		config = compact.Bytes()
+	}
+
+	h := sha256.New()
+	for _, part := range []string{in.CascadeVersion, in.Manifest, string(config)} {
+		fmt.Fprintf(h, "%d:%s\n", len(part), part)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// ManifestDigest returns the SHA-256 of the manifest alone, for comparing it
+// with the manifest currently on disk.
+func (in *Inputs) ManifestDigest() string {
+	return ManifestDigest([]byte(in.Manifest))
+}
+
+// ManifestDigest returns the SHA-256 of manifest content.
+func ManifestDigest(manifest []byte) string {
+	sum := sha256.Sum256(manifest)
+	return hex.EncodeToString(sum[:])
+}
+
+// InputStore is implemented by managers and storage backends that keep the
+// inputs of each run. It is optional; callers should type-assert before use.
+type InputStore interface {
+	// SaveInputs stores inputs under their digest. Saving inputs that are
+	// already stored is a no-op.
+	SaveInputs(module, version string, inputs *Inputs) error
+	// LoadInputs returns the inputs stored under digest, or ErrNotFound.
+	LoadInputs(module, version, digest string) (*Inputs, error)
+}
+
+var digestPattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// SaveInputs stores run inputs when the underlying storage supports it.
+func (m *manager) SaveInputs(module, version string, inputs *Inputs) error {
+	if inputs == nil {
+		return fmt.Errorf("inputs cannot be nil")
+	}
+	if err := validateModuleVersion(module, version); err != nil {
+		return err
+	}
+	store, ok := m.storage.(InputStore)
+	if !ok {
+		return ErrNotImplemented
+	}
+
+	normalized := *inputs
+	normalized.Digest = inputs.ComputeDigest()
+	if normalized.CreatedAt.IsZero() {
+		normalized.CreatedAt = m.clock.Now()
+	}
+	normalized.CreatedAt = normalized.CreatedAt.UTC()
+
+	m.logger.Debug("Saving run inputs", "module", module, "version", version, "digest", normalized.Digest)
+	if err := store.SaveInputs(module, version, &normalized); err != nil {
+		m.logger.Error("Failed to save run inputs", "module", module, "version", version, "error", err)
+		return err
+	}
+	inputs.Digest = normalized.Digest
+	return nil
+}
+
+// LoadInputs returns stored run inputs when the underlying storage supports it.
+func (m *manager) LoadInputs(module, version, digest string) (*Inputs, error) {
+	if err := validateModuleVersion(module, version); err != nil {
+		return nil, err
+	}
+	if !digestPattern.MatchString(digest) {
+		return nil, fmt.Errorf("invalid inputs digest %q", digest)
+	}
+	store, ok := m.storage.(InputStore)
+	if !ok {
+		return nil, ErrNotImplemented
+	}
+	return store.LoadInputs(module, version, digest)
+}
+
+// inputsPath returns the file holding the inputs stored under digest.
+func (fs *filesystemStorage) inputsPath(module, version, digest string) string {
+	return filepath.Join(fs.rootDir, module, version, "inputs", digest+".json")
+}
+
+// SaveInputs writes inputs to inputs/<digest>.json unless the file exists.
+func (fs *filesystemStorage) SaveInputs(module, version string, inputs *Inputs) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	path := fs.inputsPath(module, version, inputs.Digest)
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+	if err := ensureDir(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create inputs directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(inputs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal inputs: %w", err)
+	}
+	if err := atomicWrite(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to save inputs to %s: %w", path, err)
+	}
+
+	fs.logger.Debug("saved run inputs", "module", module, "version", version, "path", path)
+	return nil
+}
+
+// LoadInputs reads inputs/<digest>.json and checks it still matches its digest.
+func (fs *filesystemStorage) LoadInputs(module, version, digest string) (*Inputs, error) {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+
+	path := fs.inputsPath(module, version, digest)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to read inputs from %s: %w", path, err)
+	}
+
+	var inputs Inputs
+	if err := json.Unmarshal(data, &inputs); err != nil {
+		return nil, fmt.Errorf("%w: inputs %s: %v", ErrCorrupt, path, err)
+	}
+	if inputs.ComputeDigest() != digest {
+		return nil, fmt.Errorf("%w: inputs %s do not match their digest", ErrCorrupt, path)
+	}
+	return &inputs, nil
+}
@@ -0,0 +1,106 @@
+package state
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewInputsDigest(t *testing.T) {
+	manifest := []byte("manifest_version: 1\n")
+	config := map[string]string{"workspace": "/tmp/cascade"}
+
+	a, err := NewInputs("v1.2.0", ".cascade.yaml", manifest, config)
+	if err != nil {
+		t.Fatalf("NewInputs() error = %v", err)
+	}
+	b, err := NewInputs("v1.2.0", "moved/.cascade.yaml", manifest, config)
+	if err != nil {
+		t.Fatalf("NewInputs() error = %v", err)
+	}
+	if !digestPattern.MatchString(a.Digest) {
+		t.Fatalf("digest %q is not a SHA-256 hex string", a.Digest)
+	}
+	if a.Digest != b.Digest {
+		t.Error("expected the manifest path not to affect the digest")
+	}
+
+	for name, other := range map[string]func() (*Inputs, error){
+		"version": func() (*Inputs, error) { return NewInputs("v1.3.0", ".cascade.yaml", manifest, config) },
+		"manifest": func() (*Inputs, error) {
+			return NewInputs("v1.2.0", ".cascade.yaml", []byte("manifest_version: 2\n"), config)
+		},
+		"config": func() (*Inputs, error) { return NewInputs("v1.2.0", ".cascade.yaml", manifest, nil) },
+	} {
+		c, err := other()
+		if err != nil {
+			t.Fatalf("%s: NewInputs() error = %v", name, err)
+		}
+		if c.Digest == a.Digest {
+			t.Errorf("expected a different %s to change the digest", name)
+		}
+	}
+
+	if a.ManifestDigest() != ManifestDigest(manifest) {
+		t.Error("expected ManifestDigest to hash the stored manifest")
+	}
+}
+
+func TestManagerInputs(t *testing.T) {
+	root := t.TempDir()
+	storage, err := NewFilesystemStorage(root, nopLogger{})
+	if err != nil {
+		t.Fatalf("failed to create filesystem storage: %v", err)
+	}
+	mgr := NewManager(WithStorage(storage))
+	store, ok := mgr.(InputStore)
+	if !ok {
+		t.Fatal("manager does not implement InputStore")
+	}
+
+	module, version := "example.com/lib", "v1.0.0"
+	inputs, err := NewInputs("v1.2.0", ".cascade.yaml", []byte("manifest_version: 1\n"), map[string]int{"concurrency": 4})
+	if err != nil {
+		t.Fatalf("NewInputs() error = %v", err)
+	}
+	if err := store.SaveInputs(module, version, inputs); err != nil {
+		t.Fatalf("SaveInputs() error = %v", err)
+	}
+	// Saving identical inputs again keeps the single stored copy
+	if err := store.SaveInputs(module, version, inputs); err != nil {
+		t.Fatalf("SaveInputs() again error = %v", err)
+	}
+	entries, err := os.ReadDir(filepath.Join(root, module, version, "inputs"))
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected one stored inputs file, got %d (%v)", len(entries), err)
+	}
+
+	loaded, err := store.LoadInputs(module, version, inputs.Digest)
+	if err != nil {
+		t.Fatalf("LoadInputs() error = %v", err)
+	}
+	if loaded.Manifest != inputs.Manifest || loaded.ComputeDigest() != inputs.Digest || loaded.CascadeVersion != "v1.2.0" {
+		t.Errorf("LoadInputs() = %+v, want %+v", loaded, inputs)
+	}
+	if loaded.CreatedAt.IsZero() {
+		t.Error("expected the capture time to be recorded")
+	}
+
+	other, _ := NewInputs("v1.2.0", "", []byte("other"), nil)
+	if _, err := store.LoadInputs(module, version, other.Digest); !errors.Is(err, ErrNotFound) {
+		t.Errorf("LoadInputs() of unknown digest error = %v, want ErrNotFound", err)
+	}
+	if _, err := store.LoadInputs(module, version, "../../escape"); err == nil {
+		t.Error("expected malformed digests to be rejected")
+	}
+
+	// Edited inputs no longer match their digest
+	path := filepath.Join(root, module, version, "inputs", inputs.Digest+".json")
+	if err := os.WriteFile(path, []byte(`{"manifest":"tampered"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.LoadInputs(module, version, inputs.Digest); !errors.Is(err, ErrCorrupt) {
+		t.Errorf("LoadInputs() of edited inputs error = %v, want ErrCorrupt", err)
+	}
+}
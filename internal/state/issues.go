@@ -0,0 +1,164 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Issue is an open failure issue Cascade filed for a dependent. Issues are tracked
+// per module rather than per version, so a later release can close the issue an
+// earlier one opened.
+type Issue struct {
+	Repo    string    `json:"repo"`
+	Number  int       `json:"number"`
+	Version string    `json:"version"`
+	Opened  time.Time `json:"opened"`
+}
+
+// IssueStore is implemented by managers and storage backends that track open
+// failure issues. It is optional; callers should type-assert before use.
+type IssueStore interface {
+	// LoadIssues returns the open issues of module's dependents, sorted by repo.
+	LoadIssues(module string) ([]Issue, error)
+	// SaveIssue records issue as open, replacing any issue tracked for its repo.
+	SaveIssue(module string, issue Issue) error
+	// DeleteIssue stops tracking the issue of repo.
+	DeleteIssue(module, repo string) error
+}
+
+// LoadIssues returns the tracked issues when the underlying storage supports it.
+func (m *manager) LoadIssues(module string) ([]Issue, error) {
+	store, err := m.issueStore(module)
+	if err != nil {
+		return nil, err
+	}
+	return store.LoadIssues(module)
+}
+
+// SaveIssue tracks issue when the underlying storage supports it.
+func (m *manager) SaveIssue(module string, issue Issue) error {
+	store, err := m.issueStore(module)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(issue.Repo) == "" || issue.Number <= 0 {
+		return fmt.Errorf("issue needs a repo and a positive number")
+	}
+	issue.Opened = issue.Opened.UTC()
+
+	m.logger.Debug("Tracking failure issue", "module", module, "repo", issue.Repo, "issue", issue.Number)
+	return store.SaveIssue(module, issue)
+}
+
+// DeleteIssue stops tracking repo's issue when the underlying storage supports it.
+func (m *manager) DeleteIssue(module, repo string) error {
+	store, err := m.issueStore(module)
+	if err != nil {
+		return err
+	}
+
+	m.logger.Debug("Untracking failure issue", "module", module, "repo", repo)
+	return store.DeleteIssue(module, repo)
+}
+
+func (m *manager) issueStore(module string) (IssueStore, error) {
+	if strings.TrimSpace(module) == "" {
+		return nil, fmt.Errorf("module cannot be empty")
+	}
+	store, ok := m.storage.(IssueStore)
+	if !ok {
+		return nil, ErrNotImplemented
+	}
+	return store, nil
+}
+
+// issuesPath returns the file tracking a module's open issues. It sits next to the
+// version directories, which only directory scans pick up.
+func (fs *filesystemStorage) issuesPath(module string) string {
+	return filepath.Join(fs.rootDir, module, "issues.json")
+}
+
+// LoadIssues reads issues.json.
+func (fs *filesystemStorage) LoadIssues(module string) ([]Issue, error) {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+	return fs.readIssues(module)
+}
+
+// SaveIssue adds or replaces the issue of issue.Repo in issues.json.
+func (fs *filesystemStorage) SaveIssue(module string, issue Issue) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	issues, err := fs.readIssues(module)
+	if err != nil {
+		return err
+	}
+	kept := issues[:0]
+	for _, existing := range issues {
+		if existing.Repo != issue.Repo {
+			kept = append(kept, existing)
+		}
+	}
+	return fs.writeIssues(module, append(kept, issue))
+}
+
+// DeleteIssue removes repo's issue from issues.json.
+func (fs *filesystemStorage) DeleteIssue(module, repo string) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	issues, err := fs.readIssues(module)
+	if err != nil {
+		return err
+	}
+	kept := issues[:0]
+	for _, existing := range issues {
+		if existing.Repo != repo {
+			kept = append(kept, existing)
+		}
+	}
+	if len(kept) == len(issues) {
+		return nil
+	}
+	return fs.writeIssues(module, kept)
+}
+
+func (fs *filesystemStorage) readIssues(module string) ([]Issue, error) {
+	path := fs.issuesPath(module)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []Issue{}, nil
+		}
+		return nil, fmt.Errorf("failed to read issues from %s: %w", path, err)
+	}
+
+	var issues []Issue
+	if err := json.Unmarshal(data, &issues); err != nil {
+		return nil, fmt.Errorf("%w: issues %s: %v", ErrCorrupt, path, err)
+	}
+	return issues, nil
+}
+
+func (fs *filesystemStorage) writeIssues(module string, issues []Issue) error {
+	sort.Slice(issues, func(i, j int) bool { return issues[i].Repo < issues[j].Repo })
+
+	path := fs.issuesPath(module)
+	if err := ensureDir(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create module directory: %w", err)
+	}
+	data, err := json.MarshalIndent(issues, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal issues: %w", err)
+	}
+	if err := atomicWrite(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to save issues to %s: %w", path, err)
+	}
+	return nil
+}
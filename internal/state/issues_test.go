@@ -0,0 +1,74 @@
+package state
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/goliatone/cascade/internal/executor"
+)
+
+func TestManagerIssues(t *testing.T) {
+	storage, err := NewFilesystemStorage(t.TempDir(), nopLogger{})
+	if err != nil {
+		t.Fatalf("failed to create filesystem storage: %v", err)
+	}
+	mgr := NewManager(WithStorage(storage))
+	store, ok := mgr.(IssueStore)
+	if !ok {
+		t.Fatal("manager does not implement IssueStore")
+	}
+
+	module := "example.com/lib"
+	opened := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	issues, err := store.LoadIssues(module)
+	if err != nil || len(issues) != 0 {
+		t.Fatalf("LoadIssues() = %v, %v, want no issues", issues, err)
+	}
+
+	for _, issue := range []Issue{
+		{Repo: "example/b", Number: 7, Version: "v1.0.0", Opened: opened},
+		{Repo: "example/a", Number: 3, Version: "v1.0.0", Opened: opened},
+		{Repo: "example/b", Number: 9, Version: "v1.1.0", Opened: opened},
+	} {
+		if err := store.SaveIssue(module, issue); err != nil {
+			t.Fatalf("SaveIssue() error = %v", err)
+		}
+	}
+
+	// Tracked issues sit beside the version directories without being read as one
+	if err := mgr.SaveItemState(module, "v1.1.0", ItemState{Repo: "example/b", Branch: "update", Status: executor.StatusCompleted, Duration: time.Minute}); err != nil {
+		t.Fatalf("SaveItemState() error = %v", err)
+	}
+	if _, err := mgr.(TimingStore).LoadRepoTimings(module); err != nil {
+		t.Fatalf("LoadRepoTimings() error = %v", err)
+	}
+
+	issues, err = store.LoadIssues(module)
+	if err != nil {
+		t.Fatalf("LoadIssues() error = %v", err)
+	}
+	want := []Issue{
+		{Repo: "example/a", Number: 3, Version: "v1.0.0", Opened: opened},
+		{Repo: "example/b", Number: 9, Version: "v1.1.0", Opened: opened},
+	}
+	if !reflect.DeepEqual(issues, want) {
+		t.Fatalf("LoadIssues() = %+v, want %+v", issues, want)
+	}
+
+	if err := store.DeleteIssue(module, "example/a"); err != nil {
+		t.Fatalf("DeleteIssue() error = %v", err)
+	}
+	if err := store.DeleteIssue(module, "example/missing"); err != nil {
+		t.Fatalf("DeleteIssue() of untracked repo error = %v", err)
+	}
+	issues, _ = store.LoadIssues(module)
+	if len(issues) != 1 || issues[0].Repo != "example/b" {
+		t.Fatalf("LoadIssues() after delete = %+v", issues)
+	}
+
+	if err := store.SaveIssue(module, Issue{Repo: "example/c"}); err == nil {
+		t.Fatal("expected error for issue without a number")
+	}
+}
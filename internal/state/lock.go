@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"syscall"
 	"time"
 )
 
@@ -85,7 +86,7 @@ func (fl *filesystemLocker) acquireLock(ctx context.Context, module, version str
 	}
 
 	lockKey := filepath.Join(module, version)
-	lockPath := filepath.Join(fl.rootDir, module, version, ".cascade.lock")
+	lockPath := fl.lockPath(module, version)
 
 	attempt := 0
 	for {
@@ -124,6 +125,11 @@ func (fl *filesystemLocker) acquireLock(ctx context.Context, module, version str
 	}
 }
 
+// lockPath returns where the filesystem locker keeps the lock for module/version.
+func (fl *filesystemLocker) lockPath(module, version string) string {
+	return filepath.Join(fl.rootDir, module, version, ".cascade.lock")
+}
+
 func (fl *filesystemLocker) tryAcquireOnce(ctx context.Context, lockKey, lockPath, module, version string) (LockGuard, error) {
 	fl.mu.Lock()
 	if existing, exists := fl.activeLocks[lockKey]; exists && !existing.released {
@@ -139,14 +145,18 @@ func (fl *filesystemLocker) tryAcquireOnce(ctx context.Context, lockKey, lockPat
 	file, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
 	if err != nil {
 		if os.IsExist(err) {
-			return nil, ErrLocked
+			if fl.removeStaleLock(lockPath) {
+				return fl.tryAcquireOnce(ctx, lockKey, lockPath, module, version)
+			}
+			return nil, newLockedError(lockPath)
 		}
 		return nil, fmt.Errorf("failed to create lock file %s: %w", lockPath, err)
 	}
 
 	pid := os.Getpid()
+	host, _ := os.Hostname()
 	timestamp := time.Now().UTC().Format(time.RFC3339)
-	lockInfo := fmt.Sprintf("pid:%d\ntime:%s\nmodule:%s\nversion:%s\n", pid, timestamp, module, version)
+	lockInfo := fmt.Sprintf("pid:%d\nhost:%s\ntime:%s\nmodule:%s\nversion:%s\n", pid, host, timestamp, module, version)
 	if _, err := file.WriteString(lockInfo); err != nil {
 		file.Close()
 		os.Remove(lockPath)
@@ -190,6 +200,37 @@ func (fl *filesystemLocker) tryAcquireOnce(ctx context.Context, lockKey, lockPat
 	return guard, nil
 }
 
+// removeStaleLock deletes the lock file when it was written on this host by a
+// process that no longer exists, and reports whether it did. Locks from other
+// hosts are never considered stale since their processes cannot be checked.
+func (fl *filesystemLocker) removeStaleLock(lockPath string) bool {
+	info, err := readLockInfo(lockPath)
+	if err != nil || info.PID <= 0 || info.PID == os.Getpid() {
+		return false
+	}
+	host, err := os.Hostname()
+	if err != nil || info.Host != host || processAlive(info.PID) {
+		return false
+	}
+
+	if err := os.Remove(lockPath); err != nil && !os.IsNotExist(err) {
+		return false
+	}
+	fl.logger.Info("removed stale lock", "path", lockPath, "pid", info.PID, "started", info.Started)
+	return true
+}
+
+// processAlive reports whether pid refers to a running process. Errors other than
+// the process having finished (such as a permission error) count as alive.
+func processAlive(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	err = process.Signal(syscall.Signal(0))
+	return err == nil || !errors.Is(err, os.ErrProcessDone)
+}
+
 // filesystemLockGuard implements LockGuard for filesystem locks.
 type filesystemLockGuard struct {
 	locker  *filesystemLocker
@@ -49,10 +49,16 @@
 //
 //	<state_dir>/<module>/<version>/summary.json
 //	<state_dir>/<module>/<version>/items/<repo_hash>.json
+//	<state_dir>/<module>/<version>/runs/<run_id>.json
+//	<state_dir>/<module>/<version>/heartbeats/<repo_hash>.json
+//	<state_dir>/<module>/issues.json
 //
 // Where:
 //   - state_dir defaults to $XDG_STATE_HOME/cascade or ~/.cache/cascade
 //   - repo_hash is a SHA256 hash of the repository name for filesystem safety
+//   - run_id is the UTC start time of an attempt; each run file snapshots every item state
+//   - heartbeat files exist only while an item is executing and record its current phase
+//   - issues.json lists the open failure issues of the module's dependents across versions
 //   - Retention policy automatically prunes old versions based on configuration
 //
 // # Concurrency and Locking
@@ -62,6 +68,11 @@
 //   - Read-only operations (dry-run, status queries) bypass locking
 //   - Locks automatically release on process termination or context cancellation
 //   - ErrLocked returned with actionable message when lock acquisition fails
+//
+// Locks are local to one machine. The object storage backend (see
+// NewObjectStorage) shares state across CI runners instead, and guards it with
+// conditional writes: a write that loses to another runner re-reads and merges
+// the object, and ErrConflict is returned when it keeps losing.
 package state
 
 import (
@@ -267,7 +278,7 @@ func validateItemState(item ItemState) error {
 // isValidStatus checks if the status enum is valid.
 func isValidStatus(status executor.Status) bool {
 	switch status {
-	case executor.StatusCompleted, executor.StatusManualReview, executor.StatusFailed, executor.StatusSkipped:
+	case executor.StatusCompleted, executor.StatusManualReview, executor.StatusFailed, executor.StatusSkipped, executor.StatusNoChange:
 		return true
 	default:
 		return false
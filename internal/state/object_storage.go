@@ -0,0 +1,585 @@
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ObjectStore is the object storage API the remote state backend is built on.
+// Every object has an opaque version, such as an S3 ETag or a GCS generation,
+// that changes on every write.
+type ObjectStore interface {
+	// Get returns the data and version of the object at key, or ErrNotFound.
+	Get(ctx context.Context, key string) ([]byte, string, error)
+	// Put writes the object at key when cond holds and returns its new
+	// version. A write whose precondition fails returns ErrConflict.
+	Put(ctx context.Context, key string, data []byte, cond Precondition) (string, error)
+	// Delete removes the object at key; a missing object is not an error.
+	Delete(ctx context.Context, key string) error
+	// List returns the keys of every object under prefix.
+	List(ctx context.Context, prefix string) ([]string, error)
+}
+
+// Precondition makes a Put conditional. The zero value writes unconditionally.
+type Precondition struct {
+	// IfVersion only writes when the object's current version matches.
+	IfVersion string
+
+	// IfAbsent only writes when the object does not exist yet.
+	IfAbsent bool
+}
+
+// maxWriteAttempts bounds how often a conditional write is retried after losing
+// to a concurrent writer.
+const maxWriteAttempts = 5
+
+// objectStorage implements Storage on an ObjectStore, laying objects out like
+// the filesystem storage lays out files. Objects that several runners update,
+// such as summaries and item states, are written with conditional writes so a
+// concurrent writer's changes are merged rather than overwritten.
+type objectStorage struct {
+	store   ObjectStore
+	prefix  string
+	timeout time.Duration
+	logger  Logger
+
+	mu sync.Mutex
+	// versions are the summary versions this process last read or wrote, so a
+	// summary changed by another runner since then is detected
+	versions map[string]string
+}
+
+// NewObjectStorage creates a Storage that keeps state in store under prefix.
+// Each request is bounded by timeout; zero means 30 seconds.
+func NewObjectStorage(store ObjectStore, prefix string, timeout time.Duration, logger Logger) Storage {
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	if logger == nil {
+		logger = nopLogger{}
+	}
+	return &objectStorage{
+		store:    store,
+		prefix:   strings.Trim(prefix, "/"),
+		timeout:  timeout,
+		logger:   logger,
+		versions: make(map[string]string),
+	}
+}
+
+// key returns the object key of a slash separated path relative to the prefix.
+func (o *objectStorage) key(rel string) string {
+	if o.prefix == "" {
+		return rel
+	}
+	return o.prefix + "/" + rel
+}
+
+func (o *objectStorage) context() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), o.timeout)
+}
+
+// LoadSummary reads a summary and remembers its version for the next save.
+func (o *objectStorage) LoadSummary(module, version string) (*Summary, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	key := o.key(SummaryRelPath(module, version))
+	summary, objectVersion, err := o.getSummary(key)
+	if err != nil {
+		return nil, err
+	}
+	o.versions[key] = objectVersion
+
+	o.logger.Debug("loaded summary", "module", module, "version", version, "key", key)
+	return summary, nil
+}
+
+func (o *objectStorage) getSummary(key string) (*Summary, string, error) {
+	ctx, cancel := o.context()
+	defer cancel()
+
+	data, objectVersion, err := o.store.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, "", ErrNotFound
+		}
+		return nil, "", fmt.Errorf("failed to read summary %s: %w", key, err)
+	}
+
+	var summary Summary
+	if err := json.Unmarshal(data, &summary); err != nil {
+		o.logger.Error("failed to unmarshal summary", "key", key, "error", err)
+		return nil, "", ErrCorrupt
+	}
+	return &summary, objectVersion, nil
+}
+
+// SaveSummary writes a summary on the condition that nobody else wrote it since
+// this process last read or wrote it. When another runner did, its items are
+// merged into summary and the write is retried.
+func (o *objectStorage) SaveSummary(summary *Summary) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	key := o.key(SummaryRelPath(summary.Module, summary.Version))
+	merged := *summary
+	for attempt := 0; attempt < maxWriteAttempts; attempt++ {
+		data, err := json.MarshalIndent(&merged, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal summary: %w", err)
+		}
+
+		cond := Precondition{IfAbsent: true}
+		if known, ok := o.versions[key]; ok {
+			cond = Precondition{IfVersion: known}
+		}
+		ctx, cancel := o.context()
+		objectVersion, err := o.store.Put(ctx, key, data, cond)
+		cancel()
+		if err == nil {
+			o.versions[key] = objectVersion
+			o.logger.Debug("saved summary", "module", summary.Module, "version", summary.Version, "key", key)
+			return nil
+		}
+		if !errors.Is(err, ErrConflict) {
+			return fmt.Errorf("failed to save summary to %s: %w", key, err)
+		}
+
+		o.logger.Info("summary changed concurrently, merging", "module", summary.Module, "version", summary.Version, "key", key)
+		current, currentVersion, err := o.getSummary(key)
+		if errors.Is(err, ErrNotFound) {
+			delete(o.versions, key)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		merged = mergeSummaries(*current, merged)
+		o.versions[key] = currentVersion
+	}
+	return fmt.Errorf("%w: summary %s", ErrConflict, key)
+}
+
+// mergeSummaries merges the summary another runner stored, theirs, into ours.
+// Items recorded by only one side are kept, and the more recently updated state
+// wins for items both recorded. The run keeps the earliest start time.
+func mergeSummaries(theirs, ours Summary) Summary {
+	merged := ours
+	if !theirs.StartTime.IsZero() && (merged.StartTime.IsZero() || theirs.StartTime.Before(merged.StartTime)) {
+		merged.StartTime = theirs.StartTime
+	}
+	if theirs.RetryCount > merged.RetryCount {
+		merged.RetryCount = theirs.RetryCount
+	}
+
+	index := make(map[string]int, len(ours.Items))
+	merged.Items = append([]ItemState(nil), ours.Items...)
+	for i, item := range merged.Items {
+		index[item.Repo] = i
+	}
+	for _, item := range theirs.Items {
+		i, ok := index[item.Repo]
+		if !ok {
+			index[item.Repo] = len(merged.Items)
+			merged.Items = append(merged.Items, item)
+			continue
+		}
+		if item.LastUpdated.After(merged.Items[i].LastUpdated) {
+			merged.Items[i] = item
+		}
+	}
+
+	skipped := make(map[string]bool, len(ours.SkippedUpToDate))
+	for _, repo := range ours.SkippedUpToDate {
+		skipped[repo] = true
+	}
+	for _, repo := range theirs.SkippedUpToDate {
+		if !skipped[repo] {
+			skipped[repo] = true
+			merged.SkippedUpToDate = append(merged.SkippedUpToDate, repo)
+		}
+	}
+	return merged
+}
+
+// update applies a read-modify-write to the object at key with a conditional
+// write, re-reading and re-applying when another writer got there first. apply
+// receives nil when the object does not exist.
+func (o *objectStorage) update(key string, apply func(current []byte) ([]byte, error)) error {
+	for attempt := 0; attempt < maxWriteAttempts; attempt++ {
+		ctx, cancel := o.context()
+		current, objectVersion, err := o.store.Get(ctx, key)
+		cancel()
+		cond := Precondition{IfVersion: objectVersion}
+		switch {
+		case errors.Is(err, ErrNotFound):
+			current, cond = nil, Precondition{IfAbsent: true}
+		case err != nil:
+			return fmt.Errorf("failed to read %s: %w", key, err)
+		}
+
+		data, err := apply(current)
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel = o.context()
+		_, err = o.store.Put(ctx, key, data, cond)
+		cancel()
+		if !errors.Is(err, ErrConflict) {
+			return err
+		}
+		o.logger.Debug("state object changed concurrently, retrying", "key", key)
+	}
+	return fmt.Errorf("%w: %s", ErrConflict, key)
+}
+
+// put writes the object at key unconditionally.
+func (o *objectStorage) put(key string, value any) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", key, err)
+	}
+	ctx, cancel := o.context()
+	defer cancel()
+	if _, err := o.store.Put(ctx, key, data, Precondition{}); err != nil {
+		return fmt.Errorf("failed to write %s: %w", key, err)
+	}
+	return nil
+}
+
+// readAll decodes every JSON object under dir that match accepts, or every one
+// when match is nil. Unreadable objects are logged and skipped.
+func (o *objectStorage) readAll(dir string, match func(key string) bool, decode func(data []byte) error) error {
+	ctx, cancel := o.context()
+	keys, err := o.store.List(ctx, o.key(dir)+"/")
+	cancel()
+	if err != nil {
+		return fmt.Errorf("failed to list %s: %w", o.key(dir), err)
+	}
+
+	sort.Strings(keys)
+	for _, key := range keys {
+		if path.Ext(key) != ".json" || (match != nil && !match(key)) {
+			continue
+		}
+		ctx, cancel := o.context()
+		data, _, err := o.store.Get(ctx, key)
+		cancel()
+		if err != nil {
+			if !errors.Is(err, ErrNotFound) {
+				o.logger.Error("failed to read state object", "key", key, "error", err)
+			}
+			continue
+		}
+		if err := decode(data); err != nil {
+			o.logger.Error("failed to unmarshal state object", "key", key, "error", err)
+		}
+	}
+	return nil
+}
+
+// SaveItemState saves an item state, merging its attempts and command logs with
+// the stored state.
+func (o *objectStorage) SaveItemState(module, version string, item ItemState) error {
+	key := o.key(ItemRelPath(module, version, item.Repo))
+	err := o.update(key, func(current []byte) ([]byte, error) {
+		var existing *ItemState
+		if current != nil {
+			var previous ItemState
+			if err := json.Unmarshal(current, &previous); err == nil {
+				existing = &previous
+			}
+		}
+		data, err := json.MarshalIndent(mergeItemHistory(existing, item), "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal item state: %w", err)
+		}
+		return data, nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save item state to %s: %w", key, err)
+	}
+
+	o.logger.Debug("saved item state", "module", module, "version", version, "repo", item.Repo, "key", key)
+	return nil
+}
+
+// LoadItemStates loads all item states for a module/version pair.
+func (o *objectStorage) LoadItemStates(module, version string) ([]ItemState, error) {
+	items := []ItemState{}
+	err := o.readAll(path.Join(module, version, "items"), nil, func(data []byte) error {
+		var item ItemState
+		if err := json.Unmarshal(data, &item); err != nil {
+			return err
+		}
+		items = append(items, item)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	o.logger.Debug("loaded item states", "module", module, "version", version, "count", len(items))
+	return items, nil
+}
+
+// ListSummaries lists every summary object under the prefix.
+func (o *objectStorage) ListSummaries() ([]Summary, error) {
+	ctx, cancel := o.context()
+	defer cancel()
+
+	listPrefix := ""
+	if o.prefix != "" {
+		listPrefix = o.prefix + "/"
+	}
+	keys, err := o.store.List(ctx, listPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list summaries: %w", err)
+	}
+
+	summaries := []Summary{}
+	for _, key := range keys {
+		if path.Base(key) != "summary.json" {
+			continue
+		}
+		summary, _, err := o.getSummary(key)
+		if err != nil || summary.Module == "" || summary.Version == "" {
+			o.logger.Error("failed to read summary", "key", key, "error", err)
+			continue
+		}
+		summaries = append(summaries, *summary)
+	}
+
+	sort.SliceStable(summaries, func(i, j int) bool {
+		a, b := summaries[i], summaries[j]
+		if !a.StartTime.Equal(b.StartTime) {
+			return a.StartTime.After(b.StartTime)
+		}
+		if a.Module != b.Module {
+			return a.Module < b.Module
+		}
+		return a.Version < b.Version
+	})
+	return summaries, nil
+}
+
+// SaveRun writes a run snapshot to runs/<id>.json.
+func (o *objectStorage) SaveRun(run *Run) error {
+	return o.put(o.key(path.Join(run.Module, run.Version, "runs", run.ID+".json")), run)
+}
+
+// LoadRuns loads every run snapshot for a module/version pair, oldest first.
+func (o *objectStorage) LoadRuns(module, version string) ([]Run, error) {
+	runs := []Run{}
+	err := o.readAll(path.Join(module, version, "runs"), nil, func(data []byte) error {
+		var run Run
+		if err := json.Unmarshal(data, &run); err != nil {
+			return err
+		}
+		runs = append(runs, run)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(runs, func(i, j int) bool {
+		return runs[i].StartTime.Before(runs[j].StartTime)
+	})
+	return runs, nil
+}
+
+func (o *objectStorage) heartbeatKey(module, version, repo string) string {
+	return o.key(path.Join(module, version, "heartbeats", path.Base(ItemRelPath(module, version, repo))))
+}
+
+// SaveHeartbeat writes a heartbeat, replacing any previous beat.
+func (o *objectStorage) SaveHeartbeat(module, version string, hb Heartbeat) error {
+	return o.put(o.heartbeatKey(module, version, hb.Repo), hb)
+}
+
+// ClearHeartbeat deletes a heartbeat object.
+func (o *objectStorage) ClearHeartbeat(module, version, repo string) error {
+	ctx, cancel := o.context()
+	defer cancel()
+
+	key := o.heartbeatKey(module, version, repo)
+	if err := o.store.Delete(ctx, key); err != nil {
+		return fmt.Errorf("failed to clear heartbeat %s: %w", key, err)
+	}
+	return nil
+}
+
+// LoadHeartbeats loads every heartbeat for a module/version pair, sorted by repo.
+func (o *objectStorage) LoadHeartbeats(module, version string) ([]Heartbeat, error) {
+	heartbeats := []Heartbeat{}
+	err := o.readAll(path.Join(module, version, "heartbeats"), nil, func(data []byte) error {
+		var hb Heartbeat
+		if err := json.Unmarshal(data, &hb); err != nil {
+			return err
+		}
+		heartbeats = append(heartbeats, hb)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(heartbeats, func(i, j int) bool { return heartbeats[i].Repo < heartbeats[j].Repo })
+	return heartbeats, nil
+}
+
+// SaveInputs writes inputs/<digest>.json unless it exists. Inputs never change
+// once stored, so losing the race to another runner is not an error.
+func (o *objectStorage) SaveInputs(module, version string, inputs *Inputs) error {
+	key := o.key(path.Join(module, version, "inputs", inputs.Digest+".json"))
+	data, err := json.MarshalIndent(inputs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal inputs: %w", err)
+	}
+
+	ctx, cancel := o.context()
+	defer cancel()
+	if _, err := o.store.Put(ctx, key, data, Precondition{IfAbsent: true}); err != nil && !errors.Is(err, ErrConflict) {
+		return fmt.Errorf("failed to save inputs to %s: %w", key, err)
+	}
+	return nil
+}
+
+// LoadInputs reads inputs/<digest>.json and checks it still matches its digest.
+func (o *objectStorage) LoadInputs(module, version, digest string) (*Inputs, error) {
+	ctx, cancel := o.context()
+	defer cancel()
+
+	key := o.key(path.Join(module, version, "inputs", digest+".json"))
+	data, _, err := o.store.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to read inputs from %s: %w", key, err)
+	}
+
+	var inputs Inputs
+	if err := json.Unmarshal(data, &inputs); err != nil {
+		return nil, fmt.Errorf("%w: inputs %s: %v", ErrCorrupt, key, err)
+	}
+	if inputs.ComputeDigest() != digest {
+		return nil, fmt.Errorf("%w: inputs %s do not match their digest", ErrCorrupt, key)
+	}
+	return &inputs, nil
+}
+
+func (o *objectStorage) issuesKey(module string) string {
+	return o.key(path.Join(module, "issues.json"))
+}
+
+// LoadIssues reads issues.json.
+func (o *objectStorage) LoadIssues(module string) ([]Issue, error) {
+	ctx, cancel := o.context()
+	defer cancel()
+
+	key := o.issuesKey(module)
+	data, _, err := o.store.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return []Issue{}, nil
+		}
+		return nil, fmt.Errorf("failed to read issues from %s: %w", key, err)
+	}
+	return decodeIssues(key, data)
+}
+
+// SaveIssue adds or replaces the issue of issue.Repo in issues.json.
+func (o *objectStorage) SaveIssue(module string, issue Issue) error {
+	return o.updateIssues(module, func(issues []Issue) []Issue {
+		return append(withoutIssue(issues, issue.Repo), issue)
+	})
+}
+
+// DeleteIssue removes repo's issue from issues.json.
+func (o *objectStorage) DeleteIssue(module, repo string) error {
+	return o.updateIssues(module, func(issues []Issue) []Issue {
+		return withoutIssue(issues, repo)
+	})
+}
+
+func (o *objectStorage) updateIssues(module string, change func([]Issue) []Issue) error {
+	key := o.issuesKey(module)
+	return o.update(key, func(current []byte) ([]byte, error) {
+		issues := []Issue{}
+		if current != nil {
+			var err error
+			if issues, err = decodeIssues(key, current); err != nil {
+				return nil, err
+			}
+		}
+		issues = change(issues)
+		sort.Slice(issues, func(i, j int) bool { return issues[i].Repo < issues[j].Repo })
+		data, err := json.MarshalIndent(issues, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal issues: %w", err)
+		}
+		return data, nil
+	})
+}
+
+func decodeIssues(key string, data []byte) ([]Issue, error) {
+	var issues []Issue
+	if err := json.Unmarshal(data, &issues); err != nil {
+		return nil, fmt.Errorf("%w: issues %s: %v", ErrCorrupt, key, err)
+	}
+	return issues, nil
+}
+
+func withoutIssue(issues []Issue, repo string) []Issue {
+	kept := make([]Issue, 0, len(issues))
+	for _, existing := range issues {
+		if existing.Repo != repo {
+			kept = append(kept, existing)
+		}
+	}
+	return kept
+}
+
+// LoadRepoTimings scans the item states of every version of module.
+func (o *objectStorage) LoadRepoTimings(module string) (map[string]time.Duration, error) {
+	totals := make(map[string]time.Duration)
+	counts := make(map[string]int)
+	moduleDir := o.key(module) + "/"
+	// only <version>/items/<item>.json belongs to module itself; deeper keys
+	// belong to nested modules
+	isItem := func(key string) bool {
+		parts := strings.Split(strings.TrimPrefix(key, moduleDir), "/")
+		return len(parts) == 3 && parts[1] == "items"
+	}
+	err := o.readAll(module, isItem, func(data []byte) error {
+		var item ItemState
+		if err := json.Unmarshal(data, &item); err != nil {
+			return err
+		}
+		if countsTowardTiming(item) {
+			totals[item.Repo] += item.Duration
+			counts[item.Repo]++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	timings := make(map[string]time.Duration, len(totals))
+	for repo, total := range totals {
+		timings[repo] = total / time.Duration(counts[repo])
+	}
+	return timings, nil
+}
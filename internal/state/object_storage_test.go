@@ -0,0 +1,295 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/goliatone/cascade/internal/executor"
+)
+
+// memoryObjectStore is an ObjectStore with the conditional write semantics of
+// S3 and GCS. beforePut, when set, runs before every write to simulate another
+// runner writing concurrently.
+type memoryObjectStore struct {
+	mu        sync.Mutex
+	objects   map[string][]byte
+	versions  map[string]string
+	next      int
+	beforePut func(key string)
+}
+
+func newMemoryObjectStore() *memoryObjectStore {
+	return &memoryObjectStore{objects: make(map[string][]byte), versions: make(map[string]string)}
+}
+
+func (m *memoryObjectStore) Get(_ context.Context, key string) ([]byte, string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.objects[key]
+	if !ok {
+		return nil, "", ErrNotFound
+	}
+	return data, m.versions[key], nil
+}
+
+func (m *memoryObjectStore) Put(_ context.Context, key string, data []byte, cond Precondition) (string, error) {
+	if hook := m.beforePut; hook != nil {
+		m.beforePut = nil
+		hook(key)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	current, exists := m.versions[key]
+	if cond.IfAbsent && exists {
+		return "", ErrConflict
+	}
+	if cond.IfVersion != "" && cond.IfVersion != current {
+		return "", ErrConflict
+	}
+	m.next++
+	m.objects[key] = data
+	m.versions[key] = strconv.Itoa(m.next)
+	return m.versions[key], nil
+}
+
+func (m *memoryObjectStore) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.objects, key)
+	delete(m.versions, key)
+	return nil
+}
+
+func (m *memoryObjectStore) List(_ context.Context, prefix string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var keys []string
+	for key := range m.objects {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func TestObjectStorage_RoundTrip(t *testing.T) {
+	store := newMemoryObjectStore()
+	storage := NewObjectStorage(store, "ci/", 0, nopLogger{})
+
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	summary := &Summary{Module: "github.com/acme/lib", Version: "v1.2.0", StartTime: start}
+	if err := storage.SaveSummary(summary); err != nil {
+		t.Fatalf("SaveSummary: %v", err)
+	}
+	if _, ok := store.objects["ci/github.com/acme/lib/v1.2.0/summary.json"]; !ok {
+		t.Fatalf("summary not stored under the prefix: %v", store.objects)
+	}
+
+	item := ItemState{Repo: "github.com/acme/app", Status: executor.StatusFailed, CommandLogs: []executor.CommandResult{{Output: "ok"}}}
+	for i := 0; i < 2; i++ {
+		if err := storage.SaveItemState(summary.Module, summary.Version, item); err != nil {
+			t.Fatalf("SaveItemState: %v", err)
+		}
+	}
+
+	items, err := storage.LoadItemStates(summary.Module, summary.Version)
+	if err != nil {
+		t.Fatalf("LoadItemStates: %v", err)
+	}
+	if len(items) != 1 || items[0].Attempts != 2 || len(items[0].CommandLogs) != 2 {
+		t.Fatalf("expected one item with 2 attempts and 2 logs, got %+v", items)
+	}
+
+	loaded, err := storage.LoadSummary(summary.Module, summary.Version)
+	if err != nil {
+		t.Fatalf("LoadSummary: %v", err)
+	}
+	if !loaded.StartTime.Equal(start) {
+		t.Errorf("start time = %v, want %v", loaded.StartTime, start)
+	}
+	if _, err := storage.LoadSummary(summary.Module, "v9.9.9"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for a missing summary, got %v", err)
+	}
+
+	lister := storage.(SummaryLister)
+	summaries, err := lister.ListSummaries()
+	if err != nil || len(summaries) != 1 || summaries[0].Version != "v1.2.0" {
+		t.Fatalf("ListSummaries = %+v, %v", summaries, err)
+	}
+}
+
+func TestObjectStorage_SaveSummaryMergesConcurrentWriters(t *testing.T) {
+	store := newMemoryObjectStore()
+	first := NewObjectStorage(store, "", 0, nopLogger{})
+	second := NewObjectStorage(store, "", 0, nopLogger{})
+
+	now := time.Now()
+	if err := first.SaveSummary(&Summary{
+		Module: "example.com/lib", Version: "v1.0.0", StartTime: now,
+		Items: []ItemState{
+			{Repo: "example.com/a", Status: executor.StatusCompleted, LastUpdated: now},
+			{Repo: "example.com/b", Status: executor.StatusFailed, LastUpdated: now},
+		},
+	}); err != nil {
+		t.Fatalf("first SaveSummary: %v", err)
+	}
+
+	// the second runner never saw the first one's summary
+	if err := second.SaveSummary(&Summary{
+		Module: "example.com/lib", Version: "v1.0.0", StartTime: now.Add(time.Minute),
+		Items: []ItemState{
+			{Repo: "example.com/b", Status: executor.StatusCompleted, LastUpdated: now.Add(time.Minute)},
+			{Repo: "example.com/c", Status: executor.StatusCompleted, LastUpdated: now.Add(time.Minute)},
+		},
+	}); err != nil {
+		t.Fatalf("second SaveSummary: %v", err)
+	}
+
+	// the first runner's next save must not drop c
+	if err := first.SaveSummary(&Summary{
+		Module: "example.com/lib", Version: "v1.0.0", StartTime: now,
+		Items: []ItemState{
+			{Repo: "example.com/a", Status: executor.StatusCompleted, LastUpdated: now},
+			{Repo: "example.com/b", Status: executor.StatusFailed, LastUpdated: now},
+		},
+	}); err != nil {
+		t.Fatalf("first SaveSummary again: %v", err)
+	}
+
+	summary, err := second.LoadSummary("example.com/lib", "v1.0.0")
+	if err != nil {
+		t.Fatalf("LoadSummary: %v", err)
+	}
+	statuses := make(map[string]executor.Status)
+	for _, item := range summary.Items {
+		statuses[item.Repo] = item.Status
+	}
+	want := map[string]executor.Status{
+		"example.com/a": executor.StatusCompleted,
+		"example.com/b": executor.StatusCompleted,
+		"example.com/c": executor.StatusCompleted,
+	}
+	if len(statuses) != len(want) {
+		t.Fatalf("items = %v, want %v", statuses, want)
+	}
+	for repo, status := range want {
+		if statuses[repo] != status {
+			t.Errorf("%s status = %s, want %s", repo, statuses[repo], status)
+		}
+	}
+	if !summary.StartTime.Equal(now) {
+		t.Errorf("start time = %v, want the earliest %v", summary.StartTime, now)
+	}
+}
+
+func TestObjectStorage_SaveItemStateRetriesOnConflict(t *testing.T) {
+	store := newMemoryObjectStore()
+	storage := NewObjectStorage(store, "", 0, nopLogger{})
+	other := NewObjectStorage(store, "", 0, nopLogger{})
+
+	item := ItemState{Repo: "example.com/app", Status: executor.StatusFailed}
+	store.beforePut = func(string) {
+		if err := other.SaveItemState("example.com/lib", "v1.0.0", item); err != nil {
+			t.Errorf("concurrent SaveItemState: %v", err)
+		}
+	}
+	if err := storage.SaveItemState("example.com/lib", "v1.0.0", item); err != nil {
+		t.Fatalf("SaveItemState: %v", err)
+	}
+
+	items, err := storage.LoadItemStates("example.com/lib", "v1.0.0")
+	if err != nil {
+		t.Fatalf("LoadItemStates: %v", err)
+	}
+	if len(items) != 1 || items[0].Attempts != 2 {
+		t.Fatalf("expected both attempts to be counted, got %+v", items)
+	}
+}
+
+func TestObjectStorage_SaveItemStateGivesUpAfterRepeatedConflicts(t *testing.T) {
+	store := &conflictingObjectStore{memoryObjectStore: newMemoryObjectStore()}
+	storage := NewObjectStorage(store, "", 0, nopLogger{})
+
+	err := storage.SaveItemState("example.com/lib", "v1.0.0", ItemState{Repo: "example.com/app", Status: executor.StatusFailed})
+	if !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected ErrConflict, got %v", err)
+	}
+	if store.puts != maxWriteAttempts {
+		t.Errorf("puts = %d, want %d", store.puts, maxWriteAttempts)
+	}
+}
+
+// conflictingObjectStore loses every conditional write.
+type conflictingObjectStore struct {
+	*memoryObjectStore
+	puts int
+}
+
+func (c *conflictingObjectStore) Put(_ context.Context, _ string, _ []byte, _ Precondition) (string, error) {
+	c.puts++
+	return "", ErrConflict
+}
+
+func TestObjectStorage_LoadRepoTimingsIgnoresNestedModules(t *testing.T) {
+	storage := NewObjectStorage(newMemoryObjectStore(), "", 0, nopLogger{})
+
+	save := func(module, version string, d time.Duration) {
+		t.Helper()
+		item := ItemState{Repo: "example.com/app", Status: executor.StatusCompleted, Duration: d}
+		if err := storage.SaveItemState(module, version, item); err != nil {
+			t.Fatalf("SaveItemState: %v", err)
+		}
+	}
+	save("example.com/lib", "v1.0.0", time.Minute)
+	save("example.com/lib", "v1.1.0", 3*time.Minute)
+	save("example.com/lib/v2", "v2.0.0", time.Hour)
+
+	timings, err := storage.(TimingStore).LoadRepoTimings("example.com/lib")
+	if err != nil {
+		t.Fatalf("LoadRepoTimings: %v", err)
+	}
+	if got := timings["example.com/app"]; got != 2*time.Minute {
+		t.Errorf("average = %v, want 2m", got)
+	}
+}
+
+func TestObjectStorage_HeartbeatsAndIssues(t *testing.T) {
+	storage := NewObjectStorage(newMemoryObjectStore(), "", 0, nopLogger{})
+	heartbeats := storage.(HeartbeatStore)
+	issues := storage.(IssueStore)
+
+	if err := heartbeats.SaveHeartbeat("example.com/lib", "v1.0.0", Heartbeat{Repo: "example.com/app", Phase: "test"}); err != nil {
+		t.Fatalf("SaveHeartbeat: %v", err)
+	}
+	beats, err := heartbeats.LoadHeartbeats("example.com/lib", "v1.0.0")
+	if err != nil || len(beats) != 1 || beats[0].Phase != "test" {
+		t.Fatalf("LoadHeartbeats = %+v, %v", beats, err)
+	}
+	if err := heartbeats.ClearHeartbeat("example.com/lib", "v1.0.0", "example.com/app"); err != nil {
+		t.Fatalf("ClearHeartbeat: %v", err)
+	}
+	if beats, _ := heartbeats.LoadHeartbeats("example.com/lib", "v1.0.0"); len(beats) != 0 {
+		t.Fatalf("expected no heartbeats after clearing, got %+v", beats)
+	}
+
+	for _, repo := range []string{"example.com/b", "example.com/a"} {
+		if err := issues.SaveIssue("example.com/lib", Issue{Repo: repo, Number: 1}); err != nil {
+			t.Fatalf("SaveIssue: %v", err)
+		}
+	}
+	if err := issues.DeleteIssue("example.com/lib", "example.com/b"); err != nil {
+		t.Fatalf("DeleteIssue: %v", err)
+	}
+	open, err := issues.LoadIssues("example.com/lib")
+	if err != nil || len(open) != 1 || open[0].Repo != "example.com/a" {
+		t.Fatalf("LoadIssues = %+v, %v", open, err)
+	}
+}
@@ -0,0 +1,61 @@
+package state
+
+// Phase names a step of a work item whose completion is recorded in ItemState.
+type Phase string
+
+const (
+	// PhaseUpdated means the dependency was updated and go.mod tidied.
+	PhaseUpdated Phase = "updated"
+	// PhaseTested means the dependent's tests passed.
+	PhaseTested Phase = "tested"
+	// PhasePushed means the update branch was committed and pushed.
+	PhasePushed Phase = "pushed"
+	// PhasePRCreated means the pull request was created or updated.
+	PhasePRCreated Phase = "pr_created"
+	// PhaseNotified means notifications for the item were sent.
+	PhaseNotified Phase = "notified"
+)
+
+// PhaseOrder lists the phases in the order a work item goes through them.
+var PhaseOrder = []Phase{PhaseUpdated, PhaseTested, PhasePushed, PhasePRCreated, PhaseNotified}
+
+// Completed reports whether the item has completed phase.
+func (s ItemState) Completed(phase Phase) bool {
+	for _, p := range s.Phases {
+		if p == phase {
+			return true
+		}
+	}
+	return false
+}
+
+// MarkCompleted records phase as completed, keeping Phases in PhaseOrder.
+func (s *ItemState) MarkCompleted(phase Phase) {
+	if s.Completed(phase) {
+		return
+	}
+	done := make(map[Phase]bool, len(s.Phases)+1)
+	for _, p := range s.Phases {
+		done[p] = true
+	}
+	done[phase] = true
+
+	phases := make([]Phase, 0, len(done))
+	for _, p := range PhaseOrder {
+		if done[p] {
+			phases = append(phases, p)
+		}
+	}
+	s.Phases = phases
+}
+
+// NextPhase returns the first phase in PhaseOrder the item has not completed, or
+// "" when every phase is done.
+func (s ItemState) NextPhase() Phase {
+	for _, p := range PhaseOrder {
+		if !s.Completed(p) {
+			return p
+		}
+	}
+	return ""
+}
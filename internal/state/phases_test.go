@@ -0,0 +1,39 @@
+package state
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestItemStatePhases(t *testing.T) {
+	var st ItemState
+	if got := st.NextPhase(); got != PhaseUpdated {
+		t.Fatalf("NextPhase() = %q, want %q", got, PhaseUpdated)
+	}
+
+	st.MarkCompleted(PhasePushed)
+	st.MarkCompleted(PhaseUpdated)
+	st.MarkCompleted(PhaseTested)
+	st.MarkCompleted(PhaseUpdated)
+
+	want := []Phase{PhaseUpdated, PhaseTested, PhasePushed}
+	if !reflect.DeepEqual(st.Phases, want) {
+		t.Fatalf("Phases = %v, want %v", st.Phases, want)
+	}
+	if !st.Completed(PhaseTested) || st.Completed(PhaseNotified) {
+		t.Fatalf("Completed() mismatch for %v", st.Phases)
+	}
+	if got := st.NextPhase(); got != PhasePRCreated {
+		t.Fatalf("NextPhase() = %q, want %q", got, PhasePRCreated)
+	}
+
+	st.MarkCompleted(PhaseNotified)
+	if got := st.NextPhase(); got != PhasePRCreated {
+		t.Fatalf("NextPhase() = %q, want %q after notify without PR", got, PhasePRCreated)
+	}
+
+	st.MarkCompleted(PhasePRCreated)
+	if got := st.NextPhase(); got != "" {
+		t.Fatalf("NextPhase() = %q, want none", got)
+	}
+}
@@ -0,0 +1,57 @@
+package state
+
+// readOnlyStorage reads from another storage and refuses every write.
+type readOnlyStorage struct {
+	storage Storage
+}
+
+// ReadOnly returns storage that reads from storage and fails every write with
+// ErrReadOnly, for reporting on state that other teams' runs write. Summaries
+// can be listed when storage lists them.
+func ReadOnly(storage Storage) Storage {
+	return &readOnlyStorage{storage: storage}
+}
+
+func (r *readOnlyStorage) LoadSummary(module, version string) (*Summary, error) {
+	return r.storage.LoadSummary(module, version)
+}
+
+func (r *readOnlyStorage) SaveSummary(summary *Summary) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) SaveItemState(module, version string, item ItemState) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) LoadItemStates(module, version string) ([]ItemState, error) {
+	return r.storage.LoadItemStates(module, version)
+}
+
+// ListSummaries lists the summaries of the underlying storage, or returns
+// ErrNotImplemented when it cannot list them.
+func (r *readOnlyStorage) ListSummaries() ([]Summary, error) {
+	lister, ok := r.storage.(SummaryLister)
+	if !ok {
+		return nil, ErrNotImplemented
+	}
+	return lister.ListSummaries()
+}
+
+// LoadAnnotations reads the annotations of the underlying storage, or returns
+// ErrNotImplemented when it keeps none.
+func (r *readOnlyStorage) LoadAnnotations(module, version string) ([]Annotation, error) {
+	store, ok := r.storage.(AnnotationStore)
+	if !ok {
+		return nil, ErrNotImplemented
+	}
+	return store.LoadAnnotations(module, version)
+}
+
+func (r *readOnlyStorage) AddAnnotation(module, version string, annotation Annotation) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) ClearAnnotations(module, version, repo string) error {
+	return ErrReadOnly
+}
@@ -0,0 +1,45 @@
+package state
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/goliatone/cascade/internal/executor"
+)
+
+func TestReadOnly(t *testing.T) {
+	fs, err := NewFilesystemStorage(t.TempDir(), nopLogger{})
+	if err != nil {
+		t.Fatalf("NewFilesystemStorage() error = %v", err)
+	}
+	summary := &Summary{Module: "example.com/lib", Version: "v1.2.0", StartTime: time.Now()}
+	if err := fs.SaveSummary(summary); err != nil {
+		t.Fatalf("SaveSummary() error = %v", err)
+	}
+	if err := fs.SaveItemState(summary.Module, summary.Version, ItemState{Repo: "example/a", Status: executor.StatusCompleted}); err != nil {
+		t.Fatalf("SaveItemState() error = %v", err)
+	}
+
+	storage := ReadOnly(fs)
+	if _, err := storage.LoadSummary(summary.Module, summary.Version); err != nil {
+		t.Errorf("LoadSummary() error = %v", err)
+	}
+	if items, err := storage.LoadItemStates(summary.Module, summary.Version); err != nil || len(items) != 1 {
+		t.Errorf("LoadItemStates() = %v, %v, want the saved item", items, err)
+	}
+	if summaries, err := storage.(SummaryLister).ListSummaries(); err != nil || len(summaries) != 1 {
+		t.Errorf("ListSummaries() = %v, %v, want the saved summary", summaries, err)
+	}
+
+	if err := storage.SaveSummary(summary); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("SaveSummary() error = %v, want ErrReadOnly", err)
+	}
+	if err := storage.SaveItemState(summary.Module, summary.Version, ItemState{Repo: "example/b"}); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("SaveItemState() error = %v, want ErrReadOnly", err)
+	}
+
+	if _, err := ReadOnly(&nopStorage{}).(SummaryLister).ListSummaries(); !errors.Is(err, ErrNotImplemented) {
+		t.Errorf("ListSummaries() without a lister error = %v, want ErrNotImplemented", err)
+	}
+}
@@ -0,0 +1,24 @@
+package state
+
+import "time"
+
+// maxRetryBackoff caps the doubling backoff between attempts of a failing item.
+const maxRetryBackoff = 24 * time.Hour
+
+// AttemptsLeft reports whether the item's retry policy allows another attempt.
+func (s ItemState) AttemptsLeft() bool {
+	return s.MaxAttempts <= 0 || s.Attempts < s.MaxAttempts
+}
+
+// RetryAt returns when the item may be attempted again: the backoff after its
+// last attempt, doubled for every failed attempt before it.
+func (s ItemState) RetryAt() time.Time {
+	if s.RetryBackoff <= 0 {
+		return s.LastUpdated
+	}
+	backoff := s.RetryBackoff
+	for i := 1; i < s.Attempts && backoff < maxRetryBackoff; i++ {
+		backoff *= 2
+	}
+	return s.LastUpdated.Add(min(backoff, maxRetryBackoff))
+}
@@ -0,0 +1,36 @@
+package state
+
+import (
+	"testing"
+	"time"
+)
+
+func TestItemStateRetry(t *testing.T) {
+	failed := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	unbounded := ItemState{Attempts: 7, LastUpdated: failed}
+	if !unbounded.AttemptsLeft() || !unbounded.RetryAt().Equal(failed) {
+		t.Errorf("expected no limit and no wait without a policy, got %v at %s", unbounded.AttemptsLeft(), unbounded.RetryAt())
+	}
+
+	st := ItemState{Attempts: 1, LastUpdated: failed, MaxAttempts: 3, RetryBackoff: time.Minute}
+	if !st.AttemptsLeft() {
+		t.Error("expected attempts left after the first attempt")
+	}
+	if got := st.RetryAt(); !got.Equal(failed.Add(time.Minute)) {
+		t.Errorf("RetryAt() after one attempt = %s", got)
+	}
+
+	st.Attempts = 3
+	if st.AttemptsLeft() {
+		t.Error("expected no attempts left after max attempts")
+	}
+	if got := st.RetryAt(); !got.Equal(failed.Add(4 * time.Minute)) {
+		t.Errorf("RetryAt() after three attempts = %s, want the backoff doubled twice", got)
+	}
+
+	st.Attempts = 100
+	if got := st.RetryAt(); !got.Equal(failed.Add(maxRetryBackoff)) {
+		t.Errorf("RetryAt() = %s, want the backoff capped at %s", got, maxRetryBackoff)
+	}
+}
@@ -0,0 +1,107 @@
+package state
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LockInfo describes the process holding a lock, as recorded in the lock file.
+type LockInfo struct {
+	PID     int
+	Host    string
+	Started time.Time
+	Module  string
+	Version string
+}
+
+// String describes the holder for display, e.g. "pid 4242 on build-01 since 2024-05-01T12:00:00Z".
+func (i LockInfo) String() string {
+	var parts []string
+	if i.PID > 0 {
+		parts = append(parts, fmt.Sprintf("pid %d", i.PID))
+	}
+	if i.Host != "" {
+		parts = append(parts, "on "+i.Host)
+	}
+	if !i.Started.IsZero() {
+		parts = append(parts, "since "+i.Started.Format(time.RFC3339))
+	}
+	if len(parts) == 0 {
+		return "an unknown process"
+	}
+	return strings.Join(parts, " ")
+}
+
+// LockedError reports a lock held by another process. It matches ErrLocked with
+// errors.Is. Info is zero when the lock file could not be read.
+type LockedError struct {
+	Path string
+	Info LockInfo
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("%v: held by %s (%s)", ErrLocked, e.Info, e.Path)
+}
+
+func (e *LockedError) Unwrap() error {
+	return ErrLocked
+}
+
+func newLockedError(path string) *LockedError {
+	info, _ := readLockInfo(path)
+	return &LockedError{Path: path, Info: info}
+}
+
+// readLockInfo parses the key:value lines written by tryAcquireOnce. Unknown keys
+// and malformed values are ignored.
+func readLockInfo(path string) (LockInfo, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return LockInfo{}, err
+	}
+
+	var info LockInfo
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch key {
+		case "pid":
+			info.PID, _ = strconv.Atoi(value)
+		case "host":
+			info.Host = value
+		case "time":
+			info.Started, _ = time.Parse(time.RFC3339, value)
+		case "module":
+			info.Module = value
+		case "version":
+			info.Version = value
+		}
+	}
+	return info, scanner.Err()
+}
+
+// RunLocker is implemented by managers that can hold the module/version lock for
+// the duration of a release or resume, so two runs never process the same
+// release at once. It is optional; callers should type-assert before use.
+type RunLocker interface {
+	// LockRun acquires the run lock without waiting. When another process holds
+	// it, the error is a *LockedError describing the holder.
+	LockRun(module, version string) (LockGuard, error)
+}
+
+// LockRun acquires the module/version lock through the configured locker.
+func (m *manager) LockRun(module, version string) (LockGuard, error) {
+	if err := validateModuleVersion(module, version); err != nil {
+		return nil, err
+	}
+	return m.locker.TryAcquire(module, version)
+}
@@ -0,0 +1,116 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestManager_LockRun(t *testing.T) {
+	dir := t.TempDir()
+	first := NewManager(WithLocker(NewFilesystemLocker(dir, nopLogger{}))).(RunLocker)
+	second := NewManager(WithLocker(NewFilesystemLocker(dir, nopLogger{}))).(RunLocker)
+
+	guard, err := first.LockRun("example.com/lib", "v1.0.0")
+	if err != nil {
+		t.Fatalf("LockRun failed: %v", err)
+	}
+
+	_, err = second.LockRun("example.com/lib", "v1.0.0")
+	if !errors.Is(err, ErrLocked) {
+		t.Fatalf("expected ErrLocked, got %v", err)
+	}
+	var locked *LockedError
+	if !errors.As(err, &locked) {
+		t.Fatalf("expected *LockedError, got %T", err)
+	}
+	host, _ := os.Hostname()
+	if locked.Info.PID != os.Getpid() || locked.Info.Host != host || locked.Info.Started.IsZero() {
+		t.Errorf("unexpected lock info %+v", locked.Info)
+	}
+	if locked.Info.Module != "example.com/lib" || locked.Info.Version != "v1.0.0" {
+		t.Errorf("unexpected lock target %+v", locked.Info)
+	}
+
+	if err := guard.Release(); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	guard, err = second.LockRun("example.com/lib", "v1.0.0")
+	if err != nil {
+		t.Fatalf("expected lock after release, got %v", err)
+	}
+	guard.Release()
+
+	if _, err := first.LockRun("", "v1.0.0"); err == nil {
+		t.Error("expected error for empty module")
+	}
+}
+
+func TestManager_LockRun_NopLocker(t *testing.T) {
+	locker := NewManager().(RunLocker)
+	for i := 0; i < 2; i++ {
+		if _, err := locker.LockRun("example.com/lib", "v1.0.0"); err != nil {
+			t.Fatalf("nop locker should never be locked: %v", err)
+		}
+	}
+}
+
+func TestFilesystemLocker_RemovesStaleLock(t *testing.T) {
+	finished := exec.Command(os.Args[0], "-test.run=^$")
+	if err := finished.Run(); err != nil {
+		t.Skipf("cannot start helper process: %v", err)
+	}
+	deadPID := finished.Process.Pid
+	host, _ := os.Hostname()
+
+	writeLock := func(t *testing.T, dir, host string) {
+		t.Helper()
+		path := filepath.Join(dir, "example.com/lib", "v1.0.0", ".cascade.lock")
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		content := fmt.Sprintf("pid:%d\nhost:%s\ntime:2024-05-01T12:00:00Z\nmodule:example.com/lib\nversion:v1.0.0\n", deadPID, host)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("dead process on this host", func(t *testing.T) {
+		dir := t.TempDir()
+		writeLock(t, dir, host)
+
+		guard, err := NewFilesystemLocker(dir, nopLogger{}).TryAcquire("example.com/lib", "v1.0.0")
+		if err != nil {
+			t.Fatalf("expected stale lock to be replaced, got %v", err)
+		}
+		guard.Release()
+	})
+
+	t.Run("other host is left alone", func(t *testing.T) {
+		dir := t.TempDir()
+		writeLock(t, dir, "some-other-host")
+
+		_, err := NewFilesystemLocker(dir, nopLogger{}).TryAcquire("example.com/lib", "v1.0.0")
+		var locked *LockedError
+		if !errors.As(err, &locked) {
+			t.Fatalf("expected *LockedError, got %v", err)
+		}
+		if locked.Info.Host != "some-other-host" || locked.Info.PID != deadPID {
+			t.Errorf("unexpected lock info %+v", locked.Info)
+		}
+	})
+}
+
+func TestLockInfo_String(t *testing.T) {
+	info := LockInfo{PID: 4242, Host: "build-01", Started: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)}
+	if got, want := info.String(), "pid 4242 on build-01 since 2024-05-01T12:00:00Z"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if got := (LockInfo{}).String(); got != "an unknown process" {
+		t.Errorf("String() = %q for empty info", got)
+	}
+}
@@ -0,0 +1,250 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/goliatone/cascade/internal/executor"
+)
+
+// runIDLayout formats run identifiers from the attempt start time so they sort
+// chronologically.
+const runIDLayout = "20060102T150405Z"
+
+// Run is a snapshot of every item state at the end of one attempt (a release or a
+// resume) of a module/version cascade.
+type Run struct {
+	ID        string      `json:"id"`
+	Module    string      `json:"module"`
+	Version   string      `json:"version"`
+	Attempt   int         `json:"attempt"`
+	StartTime time.Time   `json:"start_time"`
+	EndTime   time.Time   `json:"end_time"`
+	Items     []ItemState `json:"items"`
+
+	// Inputs is the digest of the inputs the attempt ran with.
+	Inputs string `json:"inputs,omitempty"`
+}
+
+// RunID returns the identifier for a run that started at start.
+func RunID(start time.Time) string {
+	return start.UTC().Format(runIDLayout)
+}
+
+// RunStore is implemented by managers and storage backends that retain a snapshot
+// per attempt. It is optional; callers should type-assert before use.
+type RunStore interface {
+	SaveRun(run *Run) error
+	LoadRuns(module, version string) ([]Run, error)
+}
+
+// SaveRun stores a run snapshot when the underlying storage supports it.
+func (m *manager) SaveRun(run *Run) error {
+	if run == nil {
+		return fmt.Errorf("run cannot be nil")
+	}
+	if err := validateModuleVersion(run.Module, run.Version); err != nil {
+		return err
+	}
+	store, ok := m.storage.(RunStore)
+	if !ok {
+		return ErrNotImplemented
+	}
+
+	normalized := *run
+	normalized.StartTime = run.StartTime.UTC()
+	normalized.EndTime = run.EndTime.UTC()
+	if normalized.ID == "" {
+		normalized.ID = RunID(normalized.StartTime)
+	}
+
+	m.logger.Debug("Saving run", "module", run.Module, "version", run.Version, "run", normalized.ID)
+	if err := store.SaveRun(&normalized); err != nil {
+		m.logger.Error("Failed to save run", "module", run.Module, "version", run.Version, "error", err)
+		return err
+	}
+	return nil
+}
+
+// LoadRuns returns the stored run snapshots in chronological order.
+func (m *manager) LoadRuns(module, version string) ([]Run, error) {
+	if err := validateModuleVersion(module, version); err != nil {
+		return nil, err
+	}
+	store, ok := m.storage.(RunStore)
+	if !ok {
+		return nil, ErrNotImplemented
+	}
+	return store.LoadRuns(module, version)
+}
+
+// runsDir returns the directory holding run snapshots.
+func (fs *filesystemStorage) runsDir(module, version string) string {
+	return filepath.Join(fs.rootDir, module, version, "runs")
+}
+
+// SaveRun writes a run snapshot to runs/<id>.json.
+func (fs *filesystemStorage) SaveRun(run *Run) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	dir := fs.runsDir(run.Module, run.Version)
+	if err := ensureDir(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create runs directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal run: %w", err)
+	}
+
+	path := filepath.Join(dir, run.ID+".json")
+	if err := atomicWrite(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to save run to %s: %w", path, err)
+	}
+
+	fs.logger.Debug("saved run", "module", run.Module, "version", run.Version, "path", path)
+	return nil
+}
+
+// LoadRuns loads every run snapshot for a module/version pair, oldest first.
+func (fs *filesystemStorage) LoadRuns(module, version string) ([]Run, error) {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+
+	dir := fs.runsDir(module, version)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []Run{}, nil
+		}
+		return nil, fmt.Errorf("failed to read runs directory %s: %w", dir, err)
+	}
+
+	runs := make([]Run, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			fs.logger.Error("failed to read run file", "path", path, "error", err)
+			continue
+		}
+
+		var run Run
+		if err := json.Unmarshal(data, &run); err != nil {
+			fs.logger.Error("failed to unmarshal run", "path", path, "error", err)
+			continue
+		}
+		runs = append(runs, run)
+	}
+
+	sort.SliceStable(runs, func(i, j int) bool {
+		return runs[i].StartTime.Before(runs[j].StartTime)
+	})
+	return runs, nil
+}
+
+// RunDiff classifies how item outcomes changed between two runs.
+type RunDiff struct {
+	Before string `json:"before"`
+	After  string `json:"after"`
+
+	// NewlyPassing items failed or were missing before and succeeded after
+	NewlyPassing []RunDiffEntry `json:"newly_passing"`
+
+	// NewlyFailing items succeeded or were missing before and failed after
+	NewlyFailing []RunDiffEntry `json:"newly_failing"`
+
+	// StillStuck items failed or needed manual review in both runs
+	StillStuck []RunDiffEntry `json:"still_stuck"`
+
+	// StillPassing items succeeded in both runs
+	StillPassing []RunDiffEntry `json:"still_passing"`
+}
+
+// RunDiffEntry describes one repository's outcome in both runs. Statuses are empty
+// when the repository is absent from a run.
+type RunDiffEntry struct {
+	Repo         string          `json:"repo"`
+	BeforeStatus executor.Status `json:"before_status,omitempty"`
+	AfterStatus  executor.Status `json:"after_status,omitempty"`
+	Reason       string          `json:"reason,omitempty"`
+}
+
+// DiffRuns compares the item outcomes of two runs. Completed and skipped items count
+// as passing; failed and manual-review items count as stuck.
+func DiffRuns(before, after *Run) RunDiff {
+	diff := RunDiff{
+		NewlyPassing: []RunDiffEntry{},
+		NewlyFailing: []RunDiffEntry{},
+		StillStuck:   []RunDiffEntry{},
+		StillPassing: []RunDiffEntry{},
+	}
+	if before != nil {
+		diff.Before = before.ID
+	}
+	if after != nil {
+		diff.After = after.ID
+	}
+
+	beforeItems := runItemsByRepo(before)
+	afterItems := runItemsByRepo(after)
+
+	repos := make([]string, 0, len(beforeItems)+len(afterItems))
+	for repo := range beforeItems {
+		repos = append(repos, repo)
+	}
+	for repo := range afterItems {
+		if _, ok := beforeItems[repo]; !ok {
+			repos = append(repos, repo)
+		}
+	}
+	sort.Strings(repos)
+
+	for _, repo := range repos {
+		b, hadBefore := beforeItems[repo]
+		a, hasAfter := afterItems[repo]
+		if !hasAfter {
+			// Items are never removed from state, so there is nothing to compare
+			continue
+		}
+
+		entry := RunDiffEntry{Repo: repo, BeforeStatus: b.Status, AfterStatus: a.Status, Reason: a.Reason}
+		beforePassing := hadBefore && isPassingStatus(b.Status)
+		switch afterPassing := isPassingStatus(a.Status); {
+		case afterPassing && beforePassing:
+			diff.StillPassing = append(diff.StillPassing, entry)
+		case afterPassing:
+			diff.NewlyPassing = append(diff.NewlyPassing, entry)
+		case hadBefore && !beforePassing:
+			diff.StillStuck = append(diff.StillStuck, entry)
+		default:
+			diff.NewlyFailing = append(diff.NewlyFailing, entry)
+		}
+	}
+
+	return diff
+}
+
+func runItemsByRepo(run *Run) map[string]ItemState {
+	if run == nil {
+		return map[string]ItemState{}
+	}
+	items := make(map[string]ItemState, len(run.Items))
+	for _, item := range run.Items {
+		items[item.Repo] = item
+	}
+	return items
+}
+
+func isPassingStatus(status executor.Status) bool {
+	return status == executor.StatusCompleted || status == executor.StatusSkipped || status == executor.StatusNoChange
+}
@@ -0,0 +1,102 @@
+package state
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/goliatone/cascade/internal/executor"
+)
+
+func TestManagerRuns(t *testing.T) {
+	storage, err := NewFilesystemStorage(t.TempDir(), nopLogger{})
+	if err != nil {
+		t.Fatalf("failed to create filesystem storage: %v", err)
+	}
+	mgr := NewManager(WithStorage(storage))
+	store, ok := mgr.(RunStore)
+	if !ok {
+		t.Fatal("manager does not implement RunStore")
+	}
+
+	module, version := "example.com/lib", "v1.2.3"
+	first := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	second := first.Add(time.Hour)
+
+	// Save out of order to verify chronological loading
+	for _, run := range []*Run{
+		{Module: module, Version: version, Attempt: 2, StartTime: second, Items: []ItemState{{Repo: "a", Status: executor.StatusCompleted}}},
+		{Module: module, Version: version, Attempt: 1, StartTime: first, Items: []ItemState{{Repo: "a", Status: executor.StatusFailed}}},
+	} {
+		if err := store.SaveRun(run); err != nil {
+			t.Fatalf("SaveRun() error = %v", err)
+		}
+	}
+
+	runs, err := store.LoadRuns(module, version)
+	if err != nil {
+		t.Fatalf("LoadRuns() error = %v", err)
+	}
+	if len(runs) != 2 {
+		t.Fatalf("expected 2 runs, got %d", len(runs))
+	}
+	if runs[0].ID != "20250101T100000Z" || runs[1].ID != "20250101T110000Z" {
+		t.Fatalf("unexpected run order: %s, %s", runs[0].ID, runs[1].ID)
+	}
+
+	// Item states for the module/version are unaffected by run snapshots
+	items, err := storage.LoadItemStates(module, version)
+	if err != nil || len(items) != 0 {
+		t.Fatalf("LoadItemStates() = %v, %v", items, err)
+	}
+
+	if _, err := NewManager().(RunStore).LoadRuns(module, version); !errors.Is(err, ErrNotImplemented) {
+		t.Fatalf("expected ErrNotImplemented without storage support, got %v", err)
+	}
+}
+
+func TestDiffRuns(t *testing.T) {
+	before := &Run{ID: "1", Items: []ItemState{
+		{Repo: "fixed", Status: executor.StatusFailed},
+		{Repo: "regressed", Status: executor.StatusCompleted},
+		{Repo: "stuck", Status: executor.StatusManualReview},
+		{Repo: "stable", Status: executor.StatusSkipped},
+	}}
+	after := &Run{ID: "2", Items: []ItemState{
+		{Repo: "fixed", Status: executor.StatusCompleted},
+		{Repo: "regressed", Status: executor.StatusFailed, Reason: "tests failed"},
+		{Repo: "stuck", Status: executor.StatusFailed, Reason: "still broken"},
+		{Repo: "stable", Status: executor.StatusSkipped},
+		{Repo: "new", Status: executor.StatusFailed},
+	}}
+
+	diff := DiffRuns(before, after)
+
+	repos := func(entries []RunDiffEntry) []string {
+		out := []string{}
+		for _, e := range entries {
+			out = append(out, e.Repo)
+		}
+		return out
+	}
+
+	if got := repos(diff.NewlyPassing); !reflect.DeepEqual(got, []string{"fixed"}) {
+		t.Errorf("NewlyPassing = %v", got)
+	}
+	if got := repos(diff.NewlyFailing); !reflect.DeepEqual(got, []string{"new", "regressed"}) {
+		t.Errorf("NewlyFailing = %v", got)
+	}
+	if got := repos(diff.StillStuck); !reflect.DeepEqual(got, []string{"stuck"}) {
+		t.Errorf("StillStuck = %v", got)
+	}
+	if got := repos(diff.StillPassing); !reflect.DeepEqual(got, []string{"stable"}) {
+		t.Errorf("StillPassing = %v", got)
+	}
+	if diff.StillStuck[0].BeforeStatus != executor.StatusManualReview || diff.StillStuck[0].Reason != "still broken" {
+		t.Errorf("unexpected stuck entry: %+v", diff.StillStuck[0])
+	}
+	if diff.Before != "1" || diff.After != "2" {
+		t.Errorf("run ids = %s, %s", diff.Before, diff.After)
+	}
+}
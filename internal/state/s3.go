@@ -0,0 +1,185 @@
+package state
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// S3Options configures an S3-compatible object store.
+type S3Options struct {
+	// Bucket is the bucket holding the state objects.
+	Bucket string
+
+	// Region is the bucket's region. Default: the region of the AWS
+	// configuration (AWS_REGION, the shared config profile), then us-east-1
+	Region string
+
+	// Endpoint is the base URL of an S3-compatible service such as MinIO or
+	// Cloudflare R2, addressed path-style. Empty means AWS, addressed
+	// virtual-hosted style.
+	Endpoint string
+
+	// Credentials signs the requests. Default: the AWS SDK's default
+	// credential chain, which covers environment variables, shared
+	// config profiles, web identity tokens, and container and instance roles
+	Credentials aws.CredentialsProvider
+
+	// HTTPClient sends the requests. Default: the AWS SDK's client
+	HTTPClient *http.Client
+}
+
+// s3Store implements ObjectStore with the AWS SDK, using ETags as object
+// versions and If-Match/If-None-Match for conditional writes.
+type s3Store struct {
+	client *s3.Client
+	bucket string
+}
+
+// NewS3Store returns an ObjectStore for an S3 bucket or an S3-compatible
+// service.
+func NewS3Store(opts S3Options) (ObjectStore, error) {
+	if opts.Bucket == "" {
+		return nil, fmt.Errorf("s3 bucket is required")
+	}
+	opts.Endpoint = strings.TrimRight(opts.Endpoint, "/")
+	if opts.Endpoint != "" {
+		if _, err := url.Parse(opts.Endpoint); err != nil {
+			return nil, fmt.Errorf("invalid s3 endpoint %q: %w", opts.Endpoint, err)
+		}
+	}
+
+	var loadOpts []func(*awsconfig.LoadOptions) error
+	if opts.Region != "" {
+		loadOpts = append(loadOpts, awsconfig.WithRegion(opts.Region))
+	}
+	if opts.Credentials != nil {
+		loadOpts = append(loadOpts, awsconfig.WithCredentialsProvider(opts.Credentials))
+	}
+	// Only send checksums S3 requires: several S3-compatible services reject
+	// the flexible checksums the SDK adds by default.
+	loadOpts = append(loadOpts,
+		awsconfig.WithRequestChecksumCalculation(aws.RequestChecksumCalculationWhenRequired),
+		awsconfig.WithResponseChecksumValidation(aws.ResponseChecksumValidationWhenRequired),
+	)
+
+	// Loading only reads the environment and shared config files; credentials
+	// are resolved on the first request.
+	cfg, err := awsconfig.LoadDefaultConfig(context.Background(), loadOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("load aws configuration: %w", err)
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+
+	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+		if opts.HTTPClient != nil {
+			o.HTTPClient = opts.HTTPClient
+		}
+		if opts.Endpoint != "" {
+			o.BaseEndpoint = aws.String(opts.Endpoint)
+			o.UsePathStyle = true
+		}
+	})
+	return &s3Store{client: client, bucket: opts.Bucket}, nil
+}
+
+// Get downloads an object; its ETag is the version.
+func (s *s3Store) Get(ctx context.Context, key string) ([]byte, string, error) {
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		if s3Status(err) == http.StatusNotFound {
+			return nil, "", ErrNotFound
+		}
+		return nil, "", fmt.Errorf("s3 get %s: %w", key, err)
+	}
+	defer out.Body.Close()
+
+	data, err := io.ReadAll(out.Body)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, aws.ToString(out.ETag), nil
+}
+
+// Put uploads an object. S3 reports a failed precondition as 412, and as 409
+// when a concurrent conditional write is still in flight.
+func (s *s3Store) Put(ctx context.Context, key string, data []byte, cond Precondition) (string, error) {
+	input := &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(data),
+		ContentType: aws.String("application/json"),
+	}
+	switch {
+	case cond.IfVersion != "":
+		input.IfMatch = aws.String(cond.IfVersion)
+	case cond.IfAbsent:
+		input.IfNoneMatch = aws.String("*")
+	}
+
+	out, err := s.client.PutObject(ctx, input)
+	if err != nil {
+		switch s3Status(err) {
+		case http.StatusPreconditionFailed, http.StatusConflict:
+			return "", ErrConflict
+		}
+		return "", fmt.Errorf("s3 put %s: %w", key, err)
+	}
+	return aws.ToString(out.ETag), nil
+}
+
+// Delete removes an object.
+func (s *s3Store) Delete(ctx context.Context, key string) error {
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil && s3Status(err) != http.StatusNotFound {
+		return fmt.Errorf("s3 delete %s: %w", key, err)
+	}
+	return nil
+}
+
+// List pages through ListObjectsV2.
+func (s *s3Store) List(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	pages := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(prefix),
+	})
+	for pages.HasMorePages() {
+		page, err := pages.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("s3 list %s: %w", prefix, err)
+		}
+		for _, object := range page.Contents {
+			keys = append(keys, aws.ToString(object.Key))
+		}
+	}
+	return keys, nil
+}
+
+// s3Status returns the HTTP status of a failed S3 request, or zero when the
+// request got no response.
+func s3Status(err error) int {
+	var respErr *awshttp.ResponseError
+	if errors.As(err, &respErr) {
+		return respErr.HTTPStatusCode()
+	}
+	return 0
+}
@@ -0,0 +1,186 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+)
+
+// fakeS3 serves the subset of the S3 API the store uses, with conditional
+// writes, from a single path-style bucket.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string]string
+	etags   map[string]string
+	next    int
+	// pageSize limits ListObjectsV2 pages to exercise continuation tokens
+	pageSize int
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") {
+		s3ErrorResponse(w, http.StatusForbidden, "AccessDenied")
+		return
+	}
+	key := strings.TrimPrefix(r.URL.Path, "/state-bucket/")
+	switch {
+	case r.Method == http.MethodGet && r.URL.Query().Get("list-type") == "2":
+		f.list(w, r)
+	case r.Method == http.MethodGet:
+		data, ok := f.objects[key]
+		if !ok {
+			s3ErrorResponse(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		w.Header().Set("ETag", f.etags[key])
+		io.WriteString(w, data)
+	case r.Method == http.MethodPut:
+		current, exists := f.etags[key]
+		if match := r.Header.Get("If-Match"); match != "" && match != current {
+			s3ErrorResponse(w, http.StatusPreconditionFailed, "PreconditionFailed")
+			return
+		}
+		if r.Header.Get("If-None-Match") == "*" && exists {
+			s3ErrorResponse(w, http.StatusPreconditionFailed, "PreconditionFailed")
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		f.next++
+		f.objects[key] = string(body)
+		f.etags[key] = fmt.Sprintf(`"etag-%d"`, f.next)
+		w.Header().Set("ETag", f.etags[key])
+	case r.Method == http.MethodDelete:
+		delete(f.objects, key)
+		delete(f.etags, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (f *fakeS3) list(w http.ResponseWriter, r *http.Request) {
+	var keys []string
+	for key := range f.objects {
+		if strings.HasPrefix(key, r.URL.Query().Get("prefix")) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	start := 0
+	if token := r.URL.Query().Get("continuation-token"); token != "" {
+		fmt.Sscanf(token, "%d", &start)
+	}
+	end := len(keys)
+	if f.pageSize > 0 && start+f.pageSize < end {
+		end = start + f.pageSize
+	}
+
+	fmt.Fprint(w, "<ListBucketResult>")
+	for _, key := range keys[start:end] {
+		fmt.Fprintf(w, "<Contents><Key>%s</Key></Contents>", key)
+	}
+	if end < len(keys) {
+		fmt.Fprintf(w, "<IsTruncated>true</IsTruncated><NextContinuationToken>%d</NextContinuationToken>", end)
+	}
+	fmt.Fprint(w, "</ListBucketResult>")
+}
+
+// s3ErrorResponse writes an S3 XML error document.
+func s3ErrorResponse(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, "<Error><Code>%s</Code><Message>%s</Message></Error>", code, code)
+}
+
+// newFakeS3Store serves fake and returns a store for it. Nil credentials use
+// the SDK's default credential chain.
+func newFakeS3Store(t *testing.T, fake *fakeS3, creds aws.CredentialsProvider) ObjectStore {
+	t.Helper()
+	fake.objects = make(map[string]string)
+	fake.etags = make(map[string]string)
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	store, err := NewS3Store(S3Options{
+		Bucket:      "state-bucket",
+		Endpoint:    server.URL,
+		Credentials: creds,
+		HTTPClient:  server.Client(),
+	})
+	if err != nil {
+		t.Fatalf("NewS3Store: %v", err)
+	}
+	return store
+}
+
+func TestS3Store_ConditionalWrites(t *testing.T) {
+	store := newFakeS3Store(t, &fakeS3{}, credentials.NewStaticCredentialsProvider("AKID", "secret", ""))
+	ctx := context.Background()
+
+	etag, err := store.Put(ctx, "a/summary.json", []byte(`{}`), Precondition{IfAbsent: true})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := store.Put(ctx, "a/summary.json", []byte(`{}`), Precondition{IfAbsent: true}); !errors.Is(err, ErrConflict) {
+		t.Fatalf("create over an existing object: expected ErrConflict, got %v", err)
+	}
+	if _, err := store.Put(ctx, "a/summary.json", []byte(`{"v":2}`), Precondition{IfVersion: etag}); err != nil {
+		t.Fatalf("update with the current ETag: %v", err)
+	}
+	if _, err := store.Put(ctx, "a/summary.json", []byte(`{"v":3}`), Precondition{IfVersion: etag}); !errors.Is(err, ErrConflict) {
+		t.Fatalf("update with a stale ETag: expected ErrConflict, got %v", err)
+	}
+
+	data, _, err := store.Get(ctx, "a/summary.json")
+	if err != nil || string(data) != `{"v":2}` {
+		t.Fatalf("Get = %q, %v", data, err)
+	}
+	if err := store.Delete(ctx, "a/summary.json"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, _, err := store.Get(ctx, "a/summary.json"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get after Delete: expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestS3Store_ListPages(t *testing.T) {
+	store := newFakeS3Store(t, &fakeS3{pageSize: 2}, credentials.NewStaticCredentialsProvider("AKID", "secret", ""))
+	ctx := context.Background()
+
+	for _, key := range []string{"p/a.json", "p/b.json", "p/c.json", "q/d.json"} {
+		if _, err := store.Put(ctx, key, []byte(`{}`), Precondition{}); err != nil {
+			t.Fatalf("Put %s: %v", key, err)
+		}
+	}
+	keys, err := store.List(ctx, "p/")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if strings.Join(keys, ",") != "p/a.json,p/b.json,p/c.json" {
+		t.Errorf("keys = %v", keys)
+	}
+}
+
+func TestS3Store_DefaultCredentialChain(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_CONFIG_FILE", t.TempDir()+"/config")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", t.TempDir()+"/credentials")
+	store := newFakeS3Store(t, &fakeS3{}, nil)
+
+	if _, err := store.Put(context.Background(), "a.json", []byte(`{}`), Precondition{}); err != nil {
+		t.Fatalf("Put with environment credentials: %v", err)
+	}
+}
@@ -0,0 +1,143 @@
+package state
+
+import (
+	"sort"
+	"time"
+)
+
+// Stage names a part of a work item's attempt in ItemTimings.
+type Stage string
+
+// Stages in the order a work item passes through them.
+const (
+	StageQueueWait Stage = "queue_wait"
+	StageClone     Stage = "clone"
+	StageUpdate    Stage = "update"
+	StageTest      Stage = "test"
+	StagePush      Stage = "push"
+	StagePR        Stage = "pr"
+)
+
+// StageOrder lists every stage in execution order.
+var StageOrder = []Stage{StageQueueWait, StageClone, StageUpdate, StageTest, StagePush, StagePR}
+
+// ItemTimings breaks down how long the last attempt of a work item spent in each
+// stage. Queue wait covers the time before the item got a worker and an executor
+// slot; stages the attempt did not reach stay zero.
+type ItemTimings struct {
+	QueueWait time.Duration `json:"queue_wait,omitempty"`
+	Clone     time.Duration `json:"clone,omitempty"`
+	Update    time.Duration `json:"update,omitempty"`
+	Test      time.Duration `json:"test,omitempty"`
+	Push      time.Duration `json:"push,omitempty"`
+	PR        time.Duration `json:"pr,omitempty"`
+}
+
+// Get returns the time spent in stage.
+func (t ItemTimings) Get(stage Stage) time.Duration {
+	switch stage {
+	case StageQueueWait:
+		return t.QueueWait
+	case StageClone:
+		return t.Clone
+	case StageUpdate:
+		return t.Update
+	case StageTest:
+		return t.Test
+	case StagePush:
+		return t.Push
+	case StagePR:
+		return t.PR
+	}
+	return 0
+}
+
+// Add adds d to the time spent in stage.
+func (t *ItemTimings) Add(stage Stage, d time.Duration) {
+	switch stage {
+	case StageQueueWait:
+		t.QueueWait += d
+	case StageClone:
+		t.Clone += d
+	case StageUpdate:
+		t.Update += d
+	case StageTest:
+		t.Test += d
+	case StagePush:
+		t.Push += d
+	case StagePR:
+		t.PR += d
+	}
+}
+
+// StageStats summarises one stage across the items of a run. Percentiles use the
+// nearest-rank method over the items that spent time in the stage.
+type StageStats struct {
+	Stage Stage         `json:"stage"`
+	Count int           `json:"count"`
+	P50   time.Duration `json:"p50"`
+	P90   time.Duration `json:"p90"`
+	Max   time.Duration `json:"max"`
+	Total time.Duration `json:"total"`
+}
+
+// RunTimings aggregates the stage timings of the items processed in a run.
+type RunTimings struct {
+	// Items is the number of items with timings.
+	Items int `json:"items"`
+
+	// Stages holds one entry per stage any item spent time in, in StageOrder.
+	Stages []StageStats `json:"stages,omitempty"`
+
+	// PeakConcurrency is the most items that were processed at once.
+	PeakConcurrency int `json:"peak_concurrency,omitempty"`
+}
+
+// AggregateTimings computes per-stage statistics over the items that recorded
+// timings. It returns nil when none did.
+func AggregateTimings(items []ItemState) *RunTimings {
+	samples := make(map[Stage][]time.Duration)
+	count := 0
+	for _, item := range items {
+		if item.Timings == nil {
+			continue
+		}
+		count++
+		for _, stage := range StageOrder {
+			if d := item.Timings.Get(stage); d > 0 {
+				samples[stage] = append(samples[stage], d)
+			}
+		}
+	}
+	if count == 0 {
+		return nil
+	}
+
+	timings := &RunTimings{Items: count}
+	for _, stage := range StageOrder {
+		values := samples[stage]
+		if len(values) == 0 {
+			continue
+		}
+		sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+		var total time.Duration
+		for _, v := range values {
+			total += v
+		}
+		timings.Stages = append(timings.Stages, StageStats{
+			Stage: stage,
+			Count: len(values),
+			P50:   percentile(values, 50),
+			P90:   percentile(values, 90),
+			Max:   values[len(values)-1],
+			Total: total,
+		})
+	}
+	return timings
+}
+
+// percentile returns the nearest-rank p-th percentile of sorted values.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	return sorted[max(rank, 1)-1]
+}
@@ -0,0 +1,65 @@
+package state
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAggregateTimings(t *testing.T) {
+	items := []ItemState{
+		{Repo: "a", Timings: &ItemTimings{QueueWait: 1 * time.Second, Clone: 4 * time.Second, Test: 10 * time.Second}},
+		{Repo: "b", Timings: &ItemTimings{Clone: 2 * time.Second, Test: 30 * time.Second, PR: time.Second}},
+		{Repo: "c", Timings: &ItemTimings{Clone: 6 * time.Second, Test: 20 * time.Second}},
+		{Repo: "d"}, // no timings recorded, such as an item from an earlier run
+	}
+
+	got := AggregateTimings(items)
+	want := &RunTimings{
+		Items: 3,
+		Stages: []StageStats{
+			{Stage: StageQueueWait, Count: 1, P50: time.Second, P90: time.Second, Max: time.Second, Total: time.Second},
+			{Stage: StageClone, Count: 3, P50: 4 * time.Second, P90: 6 * time.Second, Max: 6 * time.Second, Total: 12 * time.Second},
+			{Stage: StageTest, Count: 3, P50: 20 * time.Second, P90: 30 * time.Second, Max: 30 * time.Second, Total: 60 * time.Second},
+			{Stage: StagePR, Count: 1, P50: time.Second, P90: time.Second, Max: time.Second, Total: time.Second},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("AggregateTimings() = %+v, want %+v", got, want)
+	}
+}
+
+func TestAggregateTimings_NoTimings(t *testing.T) {
+	if got := AggregateTimings([]ItemState{{Repo: "a"}}); got != nil {
+		t.Fatalf("AggregateTimings() = %+v, want nil", got)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	values := make([]time.Duration, 10)
+	for i := range values {
+		values[i] = time.Duration(i+1) * time.Second
+	}
+	if got := percentile(values, 50); got != 5*time.Second {
+		t.Errorf("p50 = %s, want 5s", got)
+	}
+	if got := percentile(values, 90); got != 9*time.Second {
+		t.Errorf("p90 = %s, want 9s", got)
+	}
+	if got := percentile(values[:1], 90); got != time.Second {
+		t.Errorf("p90 of one value = %s, want 1s", got)
+	}
+}
+
+func TestItemTimingsAddAndGet(t *testing.T) {
+	var timings ItemTimings
+	for i, stage := range StageOrder {
+		timings.Add(stage, time.Duration(i+1)*time.Second)
+		timings.Add(stage, time.Second)
+	}
+	for i, stage := range StageOrder {
+		if got, want := timings.Get(stage), time.Duration(i+2)*time.Second; got != want {
+			t.Errorf("Get(%s) = %s, want %s", stage, got, want)
+		}
+	}
+}
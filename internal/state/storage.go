@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -80,7 +81,13 @@ func ensureDir(path string, perm os.FileMode) error {
 
 // summaryPath returns the file path for a module/version summary.
 func (fs *filesystemStorage) summaryPath(module, version string) string {
-	return filepath.Join(fs.rootDir, module, version, "summary.json")
+	return filepath.Join(fs.rootDir, filepath.FromSlash(SummaryRelPath(module, version)))
+}
+
+// SummaryRelPath returns the slash separated path of a module/version summary
+// relative to the state directory.
+func SummaryRelPath(module, version string) string {
+	return path.Join(module, version, "summary.json")
 }
 
 // itemsDir returns the directory path for storing individual item states.
@@ -90,6 +97,12 @@ func (fs *filesystemStorage) itemsDir(module, version string) string {
 
 // itemPath returns the file path for a specific item state.
 func (fs *filesystemStorage) itemPath(module, version, repo string) string {
+	return filepath.Join(fs.rootDir, filepath.FromSlash(ItemRelPath(module, version, repo)))
+}
+
+// ItemRelPath returns the slash separated path of an item state relative to the
+// state directory.
+func ItemRelPath(module, version, repo string) string {
 	hash := sha256.Sum256([]byte(repo))
 	prefix := hex.EncodeToString(hash[:8])
 	safe := sanitizeRepoComponent(repo)
@@ -97,7 +110,7 @@ func (fs *filesystemStorage) itemPath(module, version, repo string) string {
 		safe = "repo"
 	}
 	name := fmt.Sprintf("%s_%s.json", prefix, safe)
-	return filepath.Join(fs.itemsDir(module, version), name)
+	return path.Join(module, version, "items", name)
 }
 
 // LoadSummary loads a summary for the given module and version.
@@ -160,21 +173,14 @@ func (fs *filesystemStorage) SaveItemState(module, version string, item ItemStat
 	path := fs.itemPath(module, version, item.Repo)
 
 	// Load existing state if present to merge attempts and preserve history
-	var existing ItemState
+	var existing *ItemState
 	if data, err := os.ReadFile(path); err == nil {
-		if err := json.Unmarshal(data, &existing); err == nil {
-			item.Attempts = existing.Attempts + 1
-			const maxCommandLogs = 50
-			item.CommandLogs = append(existing.CommandLogs, item.CommandLogs...)
-			if len(item.CommandLogs) > maxCommandLogs {
-				item.CommandLogs = item.CommandLogs[len(item.CommandLogs)-maxCommandLogs:]
-			}
-		} else {
-			item.Attempts = 1
+		var previous ItemState
+		if err := json.Unmarshal(data, &previous); err == nil {
+			existing = &previous
 		}
-	} else {
-		item.Attempts = 1
 	}
+	item = mergeItemHistory(existing, item)
 
 	data, err := json.MarshalIndent(item, "", "  ")
 	if err != nil {
@@ -189,13 +195,42 @@ func (fs *filesystemStorage) SaveItemState(module, version string, item ItemStat
 	return nil
 }
 
+// maxCommandLogs bounds the command logs kept across the attempts of an item.
+const maxCommandLogs = 50
+
+// mergeItemHistory counts item as one more attempt than existing, the stored
+// state of the same item, and appends its command logs to the stored ones. A nil
+// existing makes item the first attempt.
+func mergeItemHistory(existing *ItemState, item ItemState) ItemState {
+	if existing == nil {
+		item.Attempts = 1
+		return item
+	}
+	item.Attempts = existing.Attempts + 1
+	item.CommandLogs = append(existing.CommandLogs, item.CommandLogs...)
+	if len(item.CommandLogs) > maxCommandLogs {
+		item.CommandLogs = item.CommandLogs[len(item.CommandLogs)-maxCommandLogs:]
+	}
+	return item
+}
+
 // LoadItemStates loads all item states for a module/version pair.
 func (fs *filesystemStorage) LoadItemStates(module, version string) ([]ItemState, error) {
 	fs.mu.RLock()
 	defer fs.mu.RUnlock()
 
-	itemsDir := fs.itemsDir(module, version)
+	items, err := fs.readItemStates(fs.itemsDir(module, version))
+	if err != nil {
+		return nil, err
+	}
+
+	fs.logger.Debug("loaded item states", "module", module, "version", version, "count", len(items))
+	return items, nil
+}
 
+// readItemStates decodes every item state file in itemsDir. Unreadable files are
+// logged and skipped. Callers must hold fs.mu.
+func (fs *filesystemStorage) readItemStates(itemsDir string) ([]ItemState, error) {
 	entries, err := os.ReadDir(itemsDir)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -225,8 +260,6 @@ func (fs *filesystemStorage) LoadItemStates(module, version string) ([]ItemState
 
 		items = append(items, item)
 	}
-
-	fs.logger.Debug("loaded item states", "module", module, "version", version, "count", len(items))
 	return items, nil
 }
 
@@ -0,0 +1,90 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// SummaryLister is implemented by managers and storage backends that can
+// enumerate every recorded cascade. It is optional; callers should type-assert
+// before use.
+type SummaryLister interface {
+	// ListSummaries returns the summary of every module@version in state,
+	// most recently started first.
+	ListSummaries() ([]Summary, error)
+}
+
+// ListSummaries enumerates the recorded summaries when the underlying storage
+// supports it.
+func (m *manager) ListSummaries() ([]Summary, error) {
+	lister, ok := m.storage.(SummaryLister)
+	if !ok {
+		return nil, ErrNotImplemented
+	}
+	return lister.ListSummaries()
+}
+
+// summarySubdirs are the per-version directories that never hold a summary.
+var summarySubdirs = map[string]bool{"items": true, "runs": true, "heartbeats": true, "inputs": true}
+
+// ListSummaries walks the state directory for summary.json files. Module paths
+// nest, so a summary can sit at any depth. Unreadable or corrupt summaries are
+// logged and left out.
+func (fs *filesystemStorage) ListSummaries() ([]Summary, error) {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+
+	var summaries []Summary
+	err := filepath.WalkDir(fs.rootDir, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			if path == fs.rootDir {
+				return err
+			}
+			fs.logger.Error("failed to read state directory", "path", path, "error", err)
+			return nil
+		}
+		if entry.IsDir() {
+			if summarySubdirs[entry.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.Name() != "summary.json" {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			fs.logger.Error("failed to read summary file", "path", path, "error", err)
+			return nil
+		}
+		var summary Summary
+		if err := json.Unmarshal(data, &summary); err != nil || summary.Module == "" || summary.Version == "" {
+			fs.logger.Error("failed to unmarshal summary", "path", path, "error", err)
+			return nil
+		}
+		summaries = append(summaries, summary)
+		return nil
+	})
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []Summary{}, nil
+		}
+		return nil, fmt.Errorf("failed to list summaries in %s: %w", fs.rootDir, err)
+	}
+
+	sort.SliceStable(summaries, func(i, j int) bool {
+		a, b := summaries[i], summaries[j]
+		if !a.StartTime.Equal(b.StartTime) {
+			return a.StartTime.After(b.StartTime)
+		}
+		if a.Module != b.Module {
+			return a.Module < b.Module
+		}
+		return a.Version < b.Version
+	})
+	return summaries, nil
+}
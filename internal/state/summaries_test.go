@@ -0,0 +1,72 @@
+package state
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/goliatone/cascade/internal/executor"
+)
+
+func TestManagerListSummaries(t *testing.T) {
+	dir := t.TempDir()
+	storage, err := NewFilesystemStorage(dir, nopLogger{})
+	if err != nil {
+		t.Fatalf("failed to create filesystem storage: %v", err)
+	}
+	mgr := NewManager(WithStorage(storage))
+	lister, ok := mgr.(SummaryLister)
+	if !ok {
+		t.Fatal("manager does not implement SummaryLister")
+	}
+
+	if summaries, err := lister.ListSummaries(); err != nil || len(summaries) != 0 {
+		t.Fatalf("ListSummaries() on empty state = %v, %v", summaries, err)
+	}
+
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	for _, summary := range []*Summary{
+		{Module: "example.com/lib", Version: "v1.0.0", StartTime: start},
+		{Module: "example.com/lib", Version: "v1.1.0", StartTime: start.Add(2 * time.Hour)},
+		{Module: "github.com/org/nested/v2", Version: "v2.0.0", StartTime: start.Add(time.Hour)},
+	} {
+		if err := mgr.SaveSummary(summary); err != nil {
+			t.Fatalf("SaveSummary() error = %v", err)
+		}
+	}
+	if err := mgr.SaveItemState("example.com/lib", "v1.1.0", ItemState{Repo: "org/a", Branch: "update", Status: executor.StatusFailed}); err != nil {
+		t.Fatalf("SaveItemState() error = %v", err)
+	}
+	corrupt := filepath.Join(dir, "example.com", "broken", "v0.1.0", "summary.json")
+	if err := os.MkdirAll(filepath.Dir(corrupt), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(corrupt, []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	summaries, err := lister.ListSummaries()
+	if err != nil {
+		t.Fatalf("ListSummaries() error = %v", err)
+	}
+	var got []string
+	for _, summary := range summaries {
+		got = append(got, summary.Module+"@"+summary.Version)
+	}
+	want := []string{"example.com/lib@v1.1.0", "github.com/org/nested/v2@v2.0.0", "example.com/lib@v1.0.0"}
+	if len(got) != len(want) {
+		t.Fatalf("summaries = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("summaries = %v, want %v newest first", got, want)
+			break
+		}
+	}
+
+	if _, err := NewManager().(SummaryLister).ListSummaries(); !errors.Is(err, ErrNotImplemented) {
+		t.Fatalf("expected ErrNotImplemented without storage support, got %v", err)
+	}
+}
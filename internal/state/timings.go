@@ -0,0 +1,81 @@
+package state
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/goliatone/cascade/internal/executor"
+)
+
+// TimingStore is implemented by managers and storage backends that can report how
+// long work items took in earlier releases. It is optional; callers should
+// type-assert before use.
+type TimingStore interface {
+	// LoadRepoTimings returns the average duration per repository across every
+	// recorded version of module. Only completed and manual-review attempts count,
+	// since failures often stop early or run into the timeout.
+	LoadRepoTimings(module string) (map[string]time.Duration, error)
+}
+
+// LoadRepoTimings reports per-repository durations when the underlying storage
+// supports it.
+func (m *manager) LoadRepoTimings(module string) (map[string]time.Duration, error) {
+	if strings.TrimSpace(module) == "" {
+		return nil, fmt.Errorf("module cannot be empty")
+	}
+	store, ok := m.storage.(TimingStore)
+	if !ok {
+		return nil, ErrNotImplemented
+	}
+	return store.LoadRepoTimings(module)
+}
+
+// LoadRepoTimings scans the item states of every version directory under module.
+func (fs *filesystemStorage) LoadRepoTimings(module string) (map[string]time.Duration, error) {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+
+	moduleDir := filepath.Join(fs.rootDir, module)
+	entries, err := os.ReadDir(moduleDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]time.Duration{}, nil
+		}
+		return nil, fmt.Errorf("failed to read module directory %s: %w", moduleDir, err)
+	}
+
+	totals := make(map[string]time.Duration)
+	counts := make(map[string]int)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		items, err := fs.readItemStates(fs.itemsDir(module, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			if !countsTowardTiming(item) {
+				continue
+			}
+			totals[item.Repo] += item.Duration
+			counts[item.Repo]++
+		}
+	}
+
+	timings := make(map[string]time.Duration, len(totals))
+	for repo, total := range totals {
+		timings[repo] = total / time.Duration(counts[repo])
+	}
+	return timings, nil
+}
+
+func countsTowardTiming(item ItemState) bool {
+	if item.Duration <= 0 {
+		return false
+	}
+	return item.Status == executor.StatusCompleted || item.Status == executor.StatusManualReview
+}
@@ -0,0 +1,63 @@
+package state
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/goliatone/cascade/internal/executor"
+)
+
+func TestManagerLoadRepoTimings(t *testing.T) {
+	storage, err := NewFilesystemStorage(t.TempDir(), nopLogger{})
+	if err != nil {
+		t.Fatalf("failed to create filesystem storage: %v", err)
+	}
+	mgr := NewManager(WithStorage(storage))
+	store, ok := mgr.(TimingStore)
+	if !ok {
+		t.Fatal("manager does not implement TimingStore")
+	}
+
+	module := "example.com/lib"
+	save := func(version string, item ItemState) {
+		t.Helper()
+		item.Branch = "cascade/update"
+		item.LastUpdated = time.Now()
+		if err := mgr.SaveItemState(module, version, item); err != nil {
+			t.Fatalf("SaveItemState: %v", err)
+		}
+	}
+	save("v1.0.0", ItemState{Repo: "org/a", Status: executor.StatusCompleted, Duration: 2 * time.Minute})
+	save("v1.1.0", ItemState{Repo: "org/a", Status: executor.StatusCompleted, Duration: 4 * time.Minute})
+	save("v1.1.0", ItemState{Repo: "org/b", Status: executor.StatusManualReview, Duration: time.Minute})
+	save("v1.1.0", ItemState{Repo: "org/c", Status: executor.StatusFailed, Duration: 10 * time.Minute})
+	save("v1.1.0", ItemState{Repo: "org/d", Status: executor.StatusCompleted})
+	// A nested major version module must not leak into the parent module's history
+	if err := mgr.SaveItemState(module+"/v2", "v2.0.0", ItemState{Repo: "org/a", Branch: "cascade/update", Status: executor.StatusCompleted, Duration: time.Hour, LastUpdated: time.Now()}); err != nil {
+		t.Fatalf("SaveItemState: %v", err)
+	}
+
+	timings, err := store.LoadRepoTimings(module)
+	if err != nil {
+		t.Fatalf("LoadRepoTimings: %v", err)
+	}
+	want := map[string]time.Duration{"org/a": 3 * time.Minute, "org/b": time.Minute}
+	if len(timings) != len(want) {
+		t.Fatalf("timings = %v, want %v", timings, want)
+	}
+	for repo, d := range want {
+		if timings[repo] != d {
+			t.Fatalf("timings[%s] = %s, want %s", repo, timings[repo], d)
+		}
+	}
+
+	empty, err := store.LoadRepoTimings("example.com/unknown")
+	if err != nil || len(empty) != 0 {
+		t.Fatalf("expected no timings for unknown module, got %v, %v", empty, err)
+	}
+
+	if _, err := NewManager().(TimingStore).LoadRepoTimings(module); !errors.Is(err, ErrNotImplemented) {
+		t.Fatalf("expected ErrNotImplemented from nop storage, got %v", err)
+	}
+}
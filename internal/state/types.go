@@ -24,6 +24,43 @@ type Summary struct {
 	Items           []ItemState `json:"items"`
 	SkippedUpToDate []string    `json:"skipped_up_to_date,omitempty"`
 	RetryCount      int         `json:"retry_count"`
+
+	// Timings aggregates the stage timings of the items processed by the most
+	// recent run.
+	Timings *RunTimings `json:"timings,omitempty"`
+
+	// Inputs is the digest of the manifest, config, and cascade version the
+	// release started with. Resumes reuse them; see InputStore.
+	Inputs string `json:"inputs,omitempty"`
+
+	// Upstream is the module@version whose multi-level release started this
+	// one, and Wave its level in that cascade. Both are empty for releases
+	// started directly.
+	Upstream string `json:"upstream,omitempty"`
+	Wave     int    `json:"wave,omitempty"`
+
+	// Downstream records what a multi-level release did with each module
+	// beyond its first wave.
+	Downstream []DownstreamRelease `json:"downstream,omitempty"`
+
+	// ReleaseSet lists the module@version of every module released together
+	// with Module in one release set. It is empty for single-module releases.
+	ReleaseSet []string `json:"release_set,omitempty"`
+}
+
+// DownstreamRelease is the outcome of one module in a later wave of a
+// multi-level release.
+type DownstreamRelease struct {
+	Module string `json:"module"`
+	Wave   int    `json:"wave"`
+
+	// Version is the tag released to the module's dependents. It is empty when
+	// the wave stopped before a new tag was found.
+	Version string `json:"version,omitempty"`
+
+	// Reason explains why the module's dependents were not updated. It is
+	// empty when they were.
+	Reason string `json:"reason,omitempty"`
 }
 
 // ItemState describes the last known status for a particular repository update.
@@ -37,6 +74,93 @@ type ItemState struct {
 	LastUpdated time.Time                `json:"last_updated"`
 	Attempts    int                      `json:"attempts"`
 	CommandLogs []executor.CommandResult `json:"command_logs"`
+
+	// Duration is how long the last attempt took, from clone to pull request.
+	Duration time.Duration `json:"duration,omitempty"`
+
+	// Timings breaks the last attempt down by stage.
+	Timings *ItemTimings `json:"timings,omitempty"`
+
+	// CorrelationID is sent with every provider request made for the last
+	// attempt, linking debug logs and GitHub request IDs to the item.
+	CorrelationID string `json:"correlation_id,omitempty"`
+
+	// Provider is the provider the dependent selected in the manifest, so
+	// revert reaches its pull request on the same service. Empty follows the
+	// repository's host.
+	Provider string `json:"provider,omitempty"`
+
+	// AutoMerge is the pr.auto_merge method the item's pull request is merged
+	// with once its checks pass. Empty leaves the merge to people.
+	AutoMerge string `json:"auto_merge,omitempty"`
+
+	// ProxyFallback is set when the item fetched the target module directly
+	// because GOPROXY could not serve it.
+	ProxyFallback bool `json:"proxy_fallback,omitempty"`
+
+	// DependencyImpact records the version go.mod required before and after
+	// the update. It is nil when the executor did not reach the update.
+	DependencyImpact *executor.DependencyImpact `json:"dependency_impact,omitempty"`
+
+	// IssueNumber is the GitHub issue tracking the item's failure, or the issue
+	// closed after it succeeded when IssueClosed is set.
+	IssueNumber int  `json:"issue_number,omitempty"`
+	IssueClosed bool `json:"issue_closed,omitempty"`
+
+	// Phases lists the phases the item has completed, in PhaseOrder. Resume
+	// continues from the first phase missing here.
+	Phases []Phase `json:"phases,omitempty"`
+
+	// Revert records what `cascade revert` did to roll the item back.
+	Revert *RevertOutcome `json:"revert,omitempty"`
+
+	// MaxAttempts and RetryBackoff are the retry policy the item ran with.
+	// Resume --retry-failed retries a failed item while Attempts is below
+	// MaxAttempts, once the backoff since LastUpdated has passed. Zero values
+	// set no limit and no wait.
+	MaxAttempts  int           `json:"max_attempts,omitempty"`
+	RetryBackoff time.Duration `json:"retry_backoff,omitempty"`
+}
+
+// RevertAction names how an item was rolled back.
+type RevertAction string
+
+// Revert actions recorded in RevertOutcome.
+const (
+	// RevertClosed means the pull request was closed unmerged and its branch deleted.
+	RevertClosed RevertAction = "closed"
+
+	// RevertReverted means the merged update was reverted through a new pull request.
+	RevertReverted RevertAction = "reverted"
+
+	// RevertBranchDeleted means no pull request was open and the pushed branch was deleted.
+	RevertBranchDeleted RevertAction = "branch_deleted"
+
+	// RevertSkipped means the item left nothing behind to roll back.
+	RevertSkipped RevertAction = "skipped"
+
+	// RevertFailed means the rollback did not complete; Error says why.
+	RevertFailed RevertAction = "failed"
+)
+
+// RevertOutcome is the result of rolling back one item.
+type RevertOutcome struct {
+	Action RevertAction `json:"action"`
+
+	// PRURL is the pull request reverting a merged update.
+	PRURL string `json:"pr_url,omitempty"`
+
+	// CommitHash is the revert commit pushed for a merged update.
+	CommitHash string `json:"commit_hash,omitempty"`
+
+	Error      string    `json:"error,omitempty"`
+	RevertedAt time.Time `json:"reverted_at"`
+}
+
+// Done reports whether the rollback completed, so running revert again can
+// skip the item.
+func (o *RevertOutcome) Done() bool {
+	return o != nil && o.Action != RevertFailed
 }
 
 var (
@@ -48,6 +172,11 @@ var (
 	ErrLocked = errors.New("state: locked")
 	// ErrNotImplemented indicates functionality is not yet implemented.
 	ErrNotImplemented = errors.New("state: not implemented")
+	// ErrConflict indicates that a conditional write to remote state lost to a
+	// concurrent writer.
+	ErrConflict = errors.New("state: conflicting concurrent write")
+	// ErrReadOnly indicates a write to storage opened with ReadOnly.
+	ErrReadOnly = errors.New("state: read-only")
 )
 
 // Clock exposes time retrieval for deterministic testing.
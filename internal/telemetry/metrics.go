@@ -0,0 +1,134 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/client_golang/prometheus/push"
+)
+
+// DurationBuckets are the histogram buckets, in seconds, for work items and
+// their stages, which run from seconds to tens of minutes.
+var DurationBuckets = []float64{1, 5, 15, 30, 60, 120, 300, 600, 1200, 1800, 3600}
+
+// RequestBuckets are the histogram buckets, in seconds, for API requests and
+// other short operations.
+var RequestBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// Default is the registry cascade records its metrics in.
+var Default = NewRegistry()
+
+// Metrics cascade records while it runs.
+var (
+	WorkItems = Default.Counter("cascade_work_items_total",
+		"Work items processed, by final status.", "status")
+	WorkItemDuration = Default.Histogram("cascade_work_item_duration_seconds",
+		"Time spent processing a work item, from executor to notification.", DurationBuckets)
+	StageDuration = Default.Histogram("cascade_stage_duration_seconds",
+		"Time work items spent in each stage.", DurationBuckets, "stage")
+	APIRequests = Default.Counter("cascade_api_requests_total",
+		"HTTP requests to provider, notifier, and proxy APIs, by host and status code.", "host", "code")
+	APIRequestDuration = Default.Histogram("cascade_api_request_duration_seconds",
+		"Latency of HTTP requests to provider, notifier, and proxy APIs.", RequestBuckets, "host")
+	OperationDuration = Default.Histogram("cascade_operation_duration_seconds",
+		"Time spent in planner, executor, broker, and discovery operations.", RequestBuckets, "operation")
+	OperationErrors = Default.Counter("cascade_operation_errors_total",
+		"Planner, executor, broker, and discovery operations that failed.", "operation")
+)
+
+// Registry is a Prometheus registry holding cascade's counters and histograms.
+// It is safe for concurrent use.
+type Registry struct {
+	registry *prometheus.Registry
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{registry: prometheus.NewRegistry()}
+}
+
+// Counter registers a counter with the given label names.
+func (r *Registry) Counter(name, help string, labels ...string) *Counter {
+	vec := prometheus.NewCounterVec(prometheus.CounterOpts{Name: name, Help: help}, labels)
+	r.registry.MustRegister(vec)
+	return &Counter{vec: vec, labels: len(labels)}
+}
+
+// Histogram registers a histogram with the given upper bucket bounds, in
+// increasing order, and label names.
+func (r *Registry) Histogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	vec := prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: name, Help: help, Buckets: buckets}, labels)
+	r.registry.MustRegister(vec)
+	return &Histogram{vec: vec, labels: len(labels)}
+}
+
+// Gatherer returns the registry for the Prometheus client library.
+func (r *Registry) Gatherer() prometheus.Gatherer {
+	return r.registry
+}
+
+// Counter is a cumulative metric that only goes up.
+type Counter struct {
+	vec    *prometheus.CounterVec
+	labels int
+}
+
+// Inc adds 1 to the series with the given label values.
+func (c *Counter) Inc(values ...string) {
+	c.Add(1, values...)
+}
+
+// Add adds delta, which must not be negative, to the series with the given
+// label values.
+func (c *Counter) Add(delta float64, values ...string) {
+	if c == nil || delta < 0 {
+		return
+	}
+	c.vec.WithLabelValues(labelValues(values, c.labels)...).Add(delta)
+}
+
+// Histogram samples observations into buckets.
+type Histogram struct {
+	vec    *prometheus.HistogramVec
+	labels int
+}
+
+// Observe records v in the series with the given label values.
+func (h *Histogram) Observe(v float64, values ...string) {
+	if h == nil {
+		return
+	}
+	h.vec.WithLabelValues(labelValues(values, h.labels)...).Observe(v)
+}
+
+// labelValues returns exactly n label values: missing values are left empty
+// and extra ones are dropped, where the client library would panic.
+func labelValues(values []string, n int) []string {
+	if len(values) == n {
+		return values
+	}
+	normalized := make([]string, n)
+	copy(normalized, values)
+	return normalized
+}
+
+// Handler serves the registry for Prometheus to scrape.
+func (r *Registry) Handler() http.Handler {
+	return promhttp.HandlerFor(r.registry, promhttp.HandlerOpts{})
+}
+
+// Push replaces the metrics the Prometheus Pushgateway at gateway holds for
+// job with the contents of the registry.
+func (r *Registry) Push(ctx context.Context, client *http.Client, gateway, job string) error {
+	pusher := push.New(gateway, job).Gatherer(r.registry)
+	if client != nil {
+		pusher = pusher.Client(client)
+	}
+	if err := pusher.PushContext(ctx); err != nil {
+		return fmt.Errorf("telemetry: push metrics: %w", err)
+	}
+	return nil
+}
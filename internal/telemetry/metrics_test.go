@@ -0,0 +1,107 @@
+package telemetry
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// scrape returns the metrics r serves in the Prometheus text format.
+func scrape(t *testing.T, r *Registry) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /metrics = %d", rec.Code)
+	}
+	return rec.Body.String()
+}
+
+func TestRegistryHandler(t *testing.T) {
+	r := NewRegistry()
+	items := r.Counter("cascade_work_items_total", "Work items processed.", "status")
+	duration := r.Histogram("cascade_stage_duration_seconds", "Stage time.", []float64{1, 10}, "stage")
+
+	items.Inc("failed")
+	items.Add(2, "completed")
+	items.Add(-1, "completed")
+	items.Inc("failed", "extra")
+	duration.Observe(0.5, "clone")
+	duration.Observe(4, "clone")
+	duration.Observe(30)
+
+	out := scrape(t, r)
+	for _, want := range []string{
+		"# HELP cascade_work_items_total Work items processed.\n# TYPE cascade_work_items_total counter\n",
+		`cascade_work_items_total{status="completed"} 2` + "\n",
+		`cascade_work_items_total{status="failed"} 2` + "\n",
+		"# TYPE cascade_stage_duration_seconds histogram\n",
+		`cascade_stage_duration_seconds_bucket{stage="clone",le="1"} 1` + "\n",
+		`cascade_stage_duration_seconds_bucket{stage="clone",le="10"} 2` + "\n",
+		`cascade_stage_duration_seconds_sum{stage="clone"} 4.5` + "\n",
+		`cascade_stage_duration_seconds_count{stage=""} 1` + "\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("metrics missing %q:\n%s", want, out)
+		}
+	}
+
+	var nilCounter *Counter
+	nilCounter.Inc("failed")
+	var nilHistogram *Histogram
+	nilHistogram.Observe(1)
+}
+
+func TestRegistryPush(t *testing.T) {
+	var method, path, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path = r.Method, r.URL.EscapedPath()
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+	}))
+	defer server.Close()
+
+	r := NewRegistry()
+	r.Counter("cascade_work_items_total", "Work items processed.", "status").Inc("completed")
+	if err := r.Push(context.Background(), server.Client(), server.URL+"/", "cascade"); err != nil {
+		t.Fatalf("Push() error = %v", err)
+	}
+	if method != http.MethodPut || path != "/metrics/job/cascade" {
+		t.Errorf("request = %s %s", method, path)
+	}
+	if !strings.Contains(body, "cascade_work_items_total") {
+		t.Errorf("body = %q", body)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad", http.StatusBadRequest)
+	}))
+	defer failing.Close()
+	if err := r.Push(context.Background(), failing.Client(), failing.URL, "cascade"); err == nil {
+		t.Error("expected an error when the pushgateway rejects metrics")
+	}
+}
+
+func TestTransportRecordsRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewTransport(server.Client().Transport)}
+	resp, err := client.Get(server.URL + "/repos/example/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	out := scrape(t, Default)
+	host := strings.TrimPrefix(server.URL, "http://")
+	if !strings.Contains(out, `cascade_api_requests_total{code="404",host="`+host+`"} 1`) ||
+		!strings.Contains(out, `cascade_api_request_duration_seconds_count{host="`+host+`"} 1`) {
+		t.Errorf("metrics =\n%s", out)
+	}
+}
@@ -0,0 +1,27 @@
+// Package telemetry records what cascade does while it runs: Prometheus
+// metrics for work items, stages, and API calls, and OpenTelemetry spans for
+// planning, execution, the broker, and discovery. Metrics are recorded with the
+// Prometheus client library and only exposed when a listener or Pushgateway is
+// configured; spans go through the global OpenTelemetry tracer provider and are
+// exported over OTLP/HTTP once a tracer is installed with SetTracer.
+package telemetry
+
+import "go.opentelemetry.io/otel/attribute"
+
+// Attr is a span attribute.
+type Attr = attribute.KeyValue
+
+// String returns a string attribute.
+func String(key, value string) Attr {
+	return attribute.String(key, value)
+}
+
+// Int returns an integer attribute.
+func Int(key string, value int) Attr {
+	return attribute.Int(key, value)
+}
+
+// Bool returns a boolean attribute.
+func Bool(key string, value bool) Attr {
+	return attribute.Bool(key, value)
+}
@@ -0,0 +1,190 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// instrumentationScope names cascade as the source of its spans.
+const instrumentationScope = "github.com/goliatone/cascade"
+
+// NewOTLPExporter returns an exporter that posts spans to endpoint with the
+// OTLP/HTTP protocol. An endpoint without a path, such as
+// http://collector:4318, gets the standard /v1/traces path. headers are sent
+// with every request, typically to authenticate with a hosted backend. A nil
+// client uses the exporter's own.
+func NewOTLPExporter(ctx context.Context, endpoint string, headers map[string]string, client *http.Client) (sdktrace.SpanExporter, error) {
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpointURL(TracesURL(endpoint))}
+	if len(headers) > 0 {
+		opts = append(opts, otlptracehttp.WithHeaders(headers))
+	}
+	if client != nil {
+		opts = append(opts, otlptracehttp.WithHTTPClient(client))
+	}
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("telemetry: create OTLP exporter: %w", err)
+	}
+	return exporter, nil
+}
+
+// TracesURL returns the URL spans are posted to for endpoint.
+func TracesURL(endpoint string) string {
+	endpoint = strings.TrimRight(strings.TrimSpace(endpoint), "/")
+	scheme, rest, found := strings.Cut(endpoint, "://")
+	if !found || strings.Contains(rest, "/") {
+		return endpoint
+	}
+	return scheme + "://" + rest + "/v1/traces"
+}
+
+// Tracer is the tracer provider of a run, batching its spans to an exporter.
+type Tracer struct {
+	provider *sdktrace.TracerProvider
+
+	// root is the context of the root span, the parent of spans started
+	// without one
+	root context.Context
+}
+
+// TracerOption configures a Tracer.
+type TracerOption func(*tracerOptions)
+
+type tracerOptions struct {
+	onError func(error)
+}
+
+// WithErrorHandler sets the function export errors are reported to. It is
+// installed as the OpenTelemetry error handler. Default: errors are logged by
+// OpenTelemetry.
+func WithErrorHandler(onError func(error)) TracerOption {
+	return func(o *tracerOptions) {
+		o.onError = onError
+	}
+}
+
+// NewTracer returns a tracer that exports spans through exporter, under the
+// service name service ("cascade" when empty).
+func NewTracer(exporter sdktrace.SpanExporter, service string, opts ...TracerOption) *Tracer {
+	var o tracerOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.onError != nil {
+		otel.SetErrorHandler(otel.ErrorHandlerFunc(o.onError))
+	}
+	if service == "" {
+		service = "cascade"
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(String("service.name", service))),
+	)
+	return &Tracer{provider: provider}
+}
+
+// StartRoot starts the span every span started without a parent in its
+// context belongs to, normally one per command. When traceparent holds a W3C
+// trace context, such as the TRACEPARENT variable CI systems set, the root
+// span joins that trace.
+func (t *Tracer) StartRoot(name, traceparent string, attrs ...Attr) *Span {
+	ctx := propagation.TraceContext{}.Extract(context.Background(), propagation.MapCarrier{"traceparent": traceparent})
+	ctx, span := t.provider.Tracer(instrumentationScope).Start(ctx, name, trace.WithAttributes(attrs...))
+	t.root = ctx
+	return &Span{span: span}
+}
+
+// Shutdown exports the spans that have ended and stops the tracer.
+func (t *Tracer) Shutdown(ctx context.Context) error {
+	return t.provider.Shutdown(ctx)
+}
+
+var global atomic.Pointer[Tracer]
+
+// SetTracer installs t as the global OpenTelemetry tracer provider spans are
+// recorded with. A nil tracer stops recording spans.
+func SetTracer(t *Tracer) {
+	global.Store(t)
+	if t == nil {
+		otel.SetTracerProvider(noop.NewTracerProvider())
+		return
+	}
+	otel.SetTracerProvider(t.provider)
+}
+
+// Span is an operation in a trace. A nil *Span is valid and records nothing.
+type Span struct {
+	span    trace.Span
+	name    string
+	start   time.Time
+	metered bool
+	ended   atomic.Bool
+}
+
+// Start starts a span named name as a child of the span ctx carries, or of
+// the root span. Ending the span records its duration in OperationDuration,
+// and its failure in OperationErrors, under its name.
+func Start(ctx context.Context, name string, attrs ...Attr) (context.Context, *Span) {
+	ctx, span := start(ctx, name, trace.SpanKindInternal, attrs)
+	span.metered = true
+	return ctx, span
+}
+
+// Trace is Start for work whose duration other metrics already record, such
+// as work items and their stages: the span is traced but not recorded in
+// OperationDuration or OperationErrors.
+func Trace(ctx context.Context, name string, attrs ...Attr) (context.Context, *Span) {
+	return start(ctx, name, trace.SpanKindInternal, attrs)
+}
+
+func start(ctx context.Context, name string, kind trace.SpanKind, attrs []Attr) (context.Context, *Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if tracer := global.Load(); tracer != nil && tracer.root != nil && !trace.SpanContextFromContext(ctx).IsValid() {
+		ctx = trace.ContextWithSpan(ctx, trace.SpanFromContext(tracer.root))
+	}
+	ctx, span := otel.Tracer(instrumentationScope).Start(ctx, name, trace.WithSpanKind(kind), trace.WithAttributes(attrs...))
+	return ctx, &Span{span: span, name: name, start: time.Now()}
+}
+
+// SetAttributes adds attributes to the span.
+func (s *Span) SetAttributes(attrs ...Attr) {
+	if s == nil {
+		return
+	}
+	s.span.SetAttributes(attrs...)
+}
+
+// End ends the span, marking it failed when err is not nil. Only the first
+// call has an effect.
+func (s *Span) End(err error) {
+	if s == nil || !s.ended.CompareAndSwap(false, true) {
+		return
+	}
+	if err != nil {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+	s.span.End()
+
+	if s.metered {
+		OperationDuration.Observe(time.Since(s.start).Seconds(), s.name)
+		if err != nil {
+			OperationErrors.Inc(s.name)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracerSpans(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tracer := NewTracer(exporter, "")
+	SetTracer(tracer)
+	defer SetTracer(nil)
+
+	root := tracer.StartRoot("cascade release", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx, plan := Start(context.Background(), "planner.plan", String("module", "example.com/lib"))
+	_, item := Start(ctx, "work_item", Int("items", 2))
+	item.End(errors.New("tests failed"))
+	item.End(nil)
+	plan.End(nil)
+	root.End(nil)
+
+	// Shutting down would also clear the in-memory exporter
+	if err := tracer.provider.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("ForceFlush() error = %v", err)
+	}
+	spans := exporter.GetSpans()
+	if len(spans) != 3 {
+		t.Fatalf("spans = %+v", spans)
+	}
+	failed, planned, rooted := spans[0], spans[1], spans[2]
+	if rooted.SpanContext.TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" || rooted.Parent.SpanID().String() != "00f067aa0ba902b7" {
+		t.Errorf("root span = %+v, want it to join the TRACEPARENT trace", rooted)
+	}
+	if planned.SpanContext.TraceID() != rooted.SpanContext.TraceID() || planned.Parent.SpanID() != rooted.SpanContext.SpanID() {
+		t.Errorf("plan span = %+v, want a child of the root span", planned)
+	}
+	if failed.Parent.SpanID() != planned.SpanContext.SpanID() || failed.Status.Code != codes.Error || failed.Status.Description != "tests failed" {
+		t.Errorf("work item span = %+v", failed)
+	}
+	if len(failed.Attributes) != 1 || failed.Attributes[0] != attribute.Int("items", 2) {
+		t.Errorf("work item attributes = %+v", failed.Attributes)
+	}
+	if service, ok := rooted.Resource.Set().Value("service.name"); !ok || service.AsString() != "cascade" {
+		t.Errorf("resource = %v", rooted.Resource)
+	}
+}
+
+func TestOTLPExporter(t *testing.T) {
+	var path, auth, contentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, auth, contentType = r.URL.Path, r.Header.Get("Authorization"), r.Header.Get("Content-Type")
+	}))
+	defer server.Close()
+
+	exporter, err := NewOTLPExporter(context.Background(), server.URL, map[string]string{"Authorization": "Bearer token"}, server.Client())
+	if err != nil {
+		t.Fatalf("NewOTLPExporter() error = %v", err)
+	}
+	tracer := NewTracer(exporter, "cascade")
+	tracer.StartRoot("cascade release", "").End(nil)
+	if err := tracer.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	if path != "/v1/traces" || auth != "Bearer token" || contentType != "application/x-protobuf" {
+		t.Errorf("request = %s, Authorization %q, Content-Type %q", path, auth, contentType)
+	}
+}
+
+func TestSpansWithoutTracer(t *testing.T) {
+	SetTracer(nil)
+	_, span := Start(context.Background(), "discovery.workspace")
+	span.SetAttributes(Int("dependents", 3))
+	span.End(errors.New("boom"))
+	if span.span.IsRecording() {
+		t.Error("expected spans to go unrecorded without a tracer")
+	}
+
+	var nilSpan *Span
+	nilSpan.SetAttributes(String("a", "b"))
+	nilSpan.End(nil)
+}
+
+func TestTracesURL(t *testing.T) {
+	if got := TracesURL("https://otel.example.com:4318/"); got != "https://otel.example.com:4318/v1/traces" {
+		t.Errorf("TracesURL() = %q", got)
+	}
+	if got := TracesURL("https://otel.example.com/otlp/v1/traces"); got != "https://otel.example.com/otlp/v1/traces" {
+		t.Errorf("TracesURL() = %q, want a full URL kept", got)
+	}
+}
@@ -0,0 +1,54 @@
+package telemetry
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Transport records every request it sends in APIRequests and
+// APIRequestDuration and as a client span of the span the request context
+// carries. Only the host is recorded, since webhook URLs embed secrets in
+// their path.
+type Transport struct {
+	base http.RoundTripper
+}
+
+// NewTransport wraps base, or http.DefaultTransport when base is nil.
+func NewTransport(base http.RoundTripper) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{base: base}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Host
+	_, span := start(req.Context(), "HTTP "+req.Method, trace.SpanKindClient, []Attr{
+		String("http.request.method", req.Method),
+		String("server.address", host),
+	})
+
+	started := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	APIRequestDuration.Observe(time.Since(started).Seconds(), host)
+
+	code := "error"
+	if resp != nil {
+		code = strconv.Itoa(resp.StatusCode)
+		span.SetAttributes(Int("http.response.status_code", resp.StatusCode))
+		if err == nil && resp.StatusCode >= http.StatusBadRequest {
+			span.End(httpError(resp.Status))
+		}
+	}
+	APIRequests.Inc(host, code)
+	span.End(err)
+	return resp, err
+}
+
+type httpError string
+
+func (e httpError) Error() string { return string(e) }
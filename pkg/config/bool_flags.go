@@ -9,6 +9,16 @@ func (c *Config) setExecutorDryRun(value bool) {
 	c.setFlags.executorDryRun = true
 }
 
+// setExecutorDryRunMode records an explicit dry-run mode, which enables dry
+// runs.
+func (c *Config) setExecutorDryRunMode(mode string) {
+	if c == nil {
+		return
+	}
+	c.setExecutorDryRun(true)
+	c.Executor.DryRunMode = mode
+}
+
 func (c *Config) executorDryRunSet() bool {
 	if c == nil {
 		return false
@@ -11,6 +11,7 @@ import (
 // It provides a fluent interface for loading configuration from files,
 // environment variables, and command-line flags with proper precedence.
 type Builder interface {
+	Profile(name string) Builder
 	FromEnv() Builder
 	FromFlags(cmd *cobra.Command) Builder
 	FromFile(path string) Builder
@@ -28,6 +29,14 @@ func NewBuilder() Builder {
 type builder struct {
 	configs [](*Config)
 	errors  []error
+	profile string
+}
+
+// Profile selects the profile of the configuration file to merge over its base
+// settings. It applies to files loaded after it.
+func (b *builder) Profile(name string) Builder {
+	b.profile = name
+	return b
 }
 
 // FromEnv loads configuration from environment variables.
@@ -82,13 +91,16 @@ func (b *builder) FromFile(path string) Builder {
 		}
 
 		if discoveredPath == "" {
+			if b.profile != "" {
+				b.errors = append(b.errors, fmt.Errorf("profile %q selected but no configuration file was found", b.profile))
+			}
 			// No config file found, skip silently (this is normal)
 			return b
 		}
 
-		fileConfig, err = LoadFromFile(discoveredPath)
+		fileConfig, err = LoadFromFileWithProfile(discoveredPath, b.profile)
 	} else {
-		fileConfig, err = LoadFromFile(path)
+		fileConfig, err = LoadFromFileWithProfile(path, b.profile)
 	}
 
 	if err != nil {
@@ -1,6 +1,12 @@
 package config
 
-import "strings"
+import (
+	"net/url"
+	"strings"
+
+	"github.com/goliatone/cascade/pkg/repourl"
+	"github.com/goliatone/cascade/pkg/workspace/layout"
+)
 
 // CommandSpec describes a command invocation for manifest defaults.
 type CommandSpec struct {
@@ -35,6 +41,12 @@ func ManifestDefaultTests(cfg *Config) []CommandSpec {
 	return []CommandSpec{{Cmd: []string{"go", "test", "./...", "-race", "-count=1"}}}
 }
 
+// ManifestDetectTestCommands reports whether manifest generation should propose
+// per-dependent test commands from their build tooling.
+func ManifestDetectTestCommands(cfg *Config) bool {
+	return cfg == nil || !cfg.ManifestGenerator.Tests.DisableDetection
+}
+
 // ManifestDefaultSlackChannel returns the configured Slack channel if available.
 func ManifestDefaultSlackChannel(cfg *Config) string {
 	if cfg == nil {
@@ -95,3 +107,73 @@ func GitHubDiscoveryExcludePatterns(cfg *Config) []string {
 	}
 	return nil
 }
+
+// RepoURLs returns the clone URL resolver for the configured git hosts and
+// rewrites. Without an explicit default host, a GitHub Enterprise API endpoint
+// makes its host the default for owner/repo shorthands.
+func RepoURLs(cfg *Config) *repourl.Resolver {
+	if cfg == nil {
+		return repourl.New()
+	}
+
+	git := cfg.Integration.Git
+	defaultHost := strings.TrimSpace(git.DefaultHost)
+	if defaultHost == "" {
+		defaultHost = gitHubEnterpriseHost(cfg.Integration.GitHub.Endpoint)
+	}
+
+	hosts := make([]repourl.Host, 0, len(git.Hosts))
+	for _, host := range git.Hosts {
+		hosts = append(hosts, repourl.Host{
+			Name:      host.Name,
+			BaseURL:   host.URL,
+			Protocol:  repourl.Protocol(host.Protocol),
+			SSHUser:   host.SSHUser,
+			SSHHost:   host.SSHHost,
+			SSHPort:   host.SSHPort,
+			Subgroups: host.Subgroups,
+		})
+	}
+
+	rewrites := make([]repourl.Rewrite, 0, len(git.Rewrites))
+	for _, rewrite := range git.Rewrites {
+		rewrites = append(rewrites, repourl.Rewrite{Base: rewrite.Base, InsteadOf: rewrite.InsteadOf})
+	}
+
+	return repourl.New(
+		repourl.WithDefaultHost(defaultHost),
+		repourl.WithHosts(hosts...),
+		repourl.WithRewrites(rewrites...),
+	)
+}
+
+// gitHubEnterpriseHost returns the host of a GitHub Enterprise API endpoint, or
+// "" for github.com and unparsable endpoints.
+func gitHubEnterpriseHost(endpoint string) string {
+	parsed, err := url.Parse(strings.TrimSpace(endpoint))
+	if err != nil || parsed.Host == "" {
+		return ""
+	}
+	host := strings.ToLower(parsed.Hostname())
+	if host == "api.github.com" || host == "github.com" {
+		return ""
+	}
+	return host
+}
+
+// WorkspaceLayout returns the layout of repository clones in the workspace.
+// Repository identifiers are split with RepoURLs, so owner/repo shorthands
+// resolve on its default host. An invalid layout, which validation reports,
+// falls back to the default layout.
+func WorkspaceLayout(cfg *Config) layout.Layout {
+	var template string
+	if cfg != nil {
+		template = cfg.Workspace.Layout
+	}
+	urls := layout.WithResolver(RepoURLs(cfg))
+	l, err := layout.Parse(template, urls)
+	if err != nil {
+		l, _ = layout.Parse(layout.Default, urls)
+	}
+	return l
+}
@@ -0,0 +1,83 @@
+package config_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/goliatone/cascade/pkg/config"
+)
+
+func TestRepoURLs(t *testing.T) {
+	t.Run("nil config uses github.com", func(t *testing.T) {
+		got, err := config.RepoURLs(nil).CloneURL("goliatone/go-errors")
+		if err != nil || got != "https://github.com/goliatone/go-errors.git" {
+			t.Fatalf("CloneURL() = %q, %v", got, err)
+		}
+	})
+
+	t.Run("enterprise endpoint sets the default host", func(t *testing.T) {
+		cfg := &config.Config{}
+		cfg.Integration.GitHub.Endpoint = "https://github.example.com/api/v3"
+
+		urls := config.RepoURLs(cfg)
+		if urls.DefaultHost() != "github.example.com" {
+			t.Fatalf("DefaultHost() = %q", urls.DefaultHost())
+		}
+		got, _ := urls.CloneURL("team/app")
+		if got != "https://github.example.com/team/app.git" {
+			t.Errorf("CloneURL() = %q", got)
+		}
+	})
+
+	t.Run("public endpoint keeps github.com", func(t *testing.T) {
+		cfg := &config.Config{}
+		cfg.Integration.GitHub.Endpoint = "https://api.github.com"
+		if host := config.RepoURLs(cfg).DefaultHost(); host != "github.com" {
+			t.Errorf("DefaultHost() = %q", host)
+		}
+	})
+
+	t.Run("hosts and rewrites", func(t *testing.T) {
+		cfg := &config.Config{}
+		cfg.Integration.GitHub.Endpoint = "https://github.example.com/api/v3"
+		cfg.Integration.Git = config.GitConfig{
+			DefaultHost: "gitlab.example.com",
+			Hosts: []config.GitHostConfig{
+				{Name: "gitlab.example.com", Protocol: "ssh", SSHPort: 2222, Subgroups: true},
+			},
+			Rewrites: []config.GitURLRewrite{
+				{Base: "https://mirror.example.com/", InsteadOf: "https://github.com/"},
+			},
+		}
+
+		urls := config.RepoURLs(cfg)
+		if got, _ := urls.CloneURL("gitlab.example.com/platform/tools/lint"); got != "ssh://git@gitlab.example.com:2222/platform/tools/lint.git" {
+			t.Errorf("CloneURL() = %q", got)
+		}
+		if got, _ := urls.CloneURL("github.com/goliatone/go-errors"); got != "https://mirror.example.com/goliatone/go-errors.git" {
+			t.Errorf("CloneURL() with rewrite = %q", got)
+		}
+	})
+}
+
+func TestWorkspaceLayout(t *testing.T) {
+	if got := config.WorkspaceLayout(nil).String(); got != "{{repo}}" {
+		t.Errorf("WorkspaceLayout(nil) = %q", got)
+	}
+
+	cfg := &config.Config{}
+	cfg.Workspace.Layout = "{{host}}/{{owner}}/{{repo}}"
+	cfg.Integration.Git.DefaultHost = "git.example.com"
+	dir, err := config.WorkspaceLayout(cfg).Dir("/ws", "team/app")
+	if err != nil || dir != "/ws/git.example.com/team/app" {
+		t.Errorf("Dir() = %q, %v", dir, err)
+	}
+
+	cfg.Workspace.Layout = "{{org}}/{{repo}}"
+	if got := config.WorkspaceLayout(cfg).String(); got != "{{repo}}" {
+		t.Errorf("invalid layout = %q, want the default", got)
+	}
+	if err := config.Validate(cfg); err == nil || !strings.Contains(err.Error(), "workspace.layout") {
+		t.Errorf("Validate() error = %v, want a workspace.layout error", err)
+	}
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"log/slog"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -474,7 +475,7 @@ func TestDIContainerConfigAccessors(t *testing.T) {
 		t.Error("GetExecutorConfig should return config.Executor")
 	}
 
-	if diConfig.GetIntegrationConfig() != config.Integration {
+	if !reflect.DeepEqual(diConfig.GetIntegrationConfig(), config.Integration) {
 		t.Error("GetIntegrationConfig should return config.Integration")
 	}
 
@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"strconv"
 	"strings"
@@ -67,6 +68,20 @@ func (p *EnvParser) ParseEnv() (*Config, error) {
 		errs = append(errs, err.Error())
 	}
 
+	// Parse approval configuration
+	if err := p.parseApproval(config); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	// Parse UI configuration
+	p.parseUI(config)
+
+	// Parse pull request format configuration
+	p.parsePullRequests(config)
+
+	// Parse telemetry configuration
+	p.parseTelemetry(config)
+
 	if len(errs) > 0 {
 		return nil, fmt.Errorf("environment variable parsing errors: %s", strings.Join(errs, "; "))
 	}
@@ -88,6 +103,10 @@ func (p *EnvParser) parseWorkspace(config *Config) error {
 		config.Workspace.ManifestPath = manifestPath
 	}
 
+	if layout := p.getEnv(EnvWorkspaceLayout); layout != "" {
+		config.Workspace.Layout = layout
+	}
+
 	return nil
 }
 
@@ -117,8 +136,47 @@ func (p *EnvParser) parseExecutor(config *Config) error {
 		}
 	}
 
+	// Parse retries
+	if retriesStr := p.getEnv(EnvRetries); retriesStr != "" {
+		retries, err := strconv.Atoi(retriesStr)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("invalid %s: must be a non-negative integer", EnvRetries))
+		} else if retries < 0 {
+			errs = append(errs, fmt.Sprintf("invalid %s: must not be negative, got %d", EnvRetries, retries))
+		} else {
+			config.Executor.Retries = retries
+		}
+	}
+
+	// Parse retry delay
+	if delayStr := p.getEnv(EnvRetryDelay); delayStr != "" {
+		delay, err := time.ParseDuration(delayStr)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("invalid %s: %v", EnvRetryDelay, err))
+		} else {
+			config.Executor.RetryDelay = delay
+		}
+	}
+
+	// Parse plan max age
+	if ageStr := p.getEnv(EnvPlanMaxAge); ageStr != "" {
+		age, err := time.ParseDuration(ageStr)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("invalid %s: %v", EnvPlanMaxAge, err))
+		} else {
+			config.Executor.PlanMaxAge = age
+		}
+	}
+
+	// Parse release base branch
+	if baseBranch := p.getEnv(EnvBaseBranch); baseBranch != "" {
+		config.Executor.BaseBranch = baseBranch
+	}
+
 	// Parse dry run flag
-	if dryRunStr := p.getEnv(EnvDryRun); dryRunStr != "" {
+	if dryRunStr := p.getEnv(EnvDryRun); dryRunStr == DryRunModeDiff {
+		config.setExecutorDryRunMode(DryRunModeDiff)
+	} else if dryRunStr != "" {
 		dryRun, err := p.parseBool(dryRunStr)
 		if err != nil {
 			errs = append(errs, fmt.Sprintf("invalid %s: %v", EnvDryRun, err))
@@ -127,6 +185,48 @@ func (p *EnvParser) parseExecutor(config *Config) error {
 		}
 	}
 
+	// Parse multi-level release settings
+	if multiLevelStr := p.getEnv(EnvMultiLevel); multiLevelStr != "" {
+		multiLevel, err := p.parseBool(multiLevelStr)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("invalid %s: %v", EnvMultiLevel, err))
+		} else {
+			config.Executor.MultiLevel = multiLevel
+		}
+	}
+	if timeoutStr := p.getEnv(EnvWaveTimeout); timeoutStr != "" {
+		timeout, err := time.ParseDuration(timeoutStr)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("invalid %s: %v", EnvWaveTimeout, err))
+		} else {
+			config.Executor.WaveTimeout = timeout
+		}
+	}
+	if intervalStr := p.getEnv(EnvWavePollInterval); intervalStr != "" {
+		interval, err := time.ParseDuration(intervalStr)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("invalid %s: %v", EnvWavePollInterval, err))
+		} else {
+			config.Executor.WavePollInterval = interval
+		}
+	}
+	if timeoutStr := p.getEnv(EnvCanaryTimeout); timeoutStr != "" {
+		timeout, err := time.ParseDuration(timeoutStr)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("invalid %s: %v", EnvCanaryTimeout, err))
+		} else {
+			config.Executor.CanaryTimeout = timeout
+		}
+	}
+	if intervalStr := p.getEnv(EnvCanaryPollInterval); intervalStr != "" {
+		interval, err := time.ParseDuration(intervalStr)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("invalid %s: %v", EnvCanaryPollInterval, err))
+		} else {
+			config.Executor.CanaryPollInterval = interval
+		}
+	}
+
 	// Parse check strategy
 	if strategy := p.getEnv(EnvCheckStrategy); strategy != "" {
 		if !p.isValidCheckStrategy(strategy) {
@@ -177,6 +277,10 @@ func (p *EnvParser) parseExecutor(config *Config) error {
 
 // parseIntegration parses integration-related environment variables
 func (p *EnvParser) parseIntegration(config *Config) error {
+	if provider := p.getEnv(EnvProvider); provider != "" {
+		config.Integration.Provider = provider
+	}
+
 	// Parse GitHub configuration
 	if token := p.getEnv(EnvGitHubToken); token != "" {
 		config.Integration.GitHub.Token = token
@@ -190,6 +294,95 @@ func (p *EnvParser) parseIntegration(config *Config) error {
 		config.Integration.GitHub.Organization = org
 	}
 
+	if secret := p.getEnv(EnvGitHubWebhookSecret); secret != "" {
+		config.Integration.GitHub.WebhookSecret = secret
+	}
+
+	if waitStr := p.getEnv(EnvGitHubRateLimitWait); waitStr != "" {
+		wait, err := time.ParseDuration(waitStr)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", EnvGitHubRateLimitWait, err)
+		}
+		config.Integration.GitHub.RateLimit.MaxWait = wait
+	}
+
+	// Parse GitLab configuration
+	if token := p.getEnv(EnvGitLabToken); token != "" {
+		config.Integration.GitLab.Token = token
+	}
+
+	if endpoint := p.getEnv(EnvGitLabEndpoint); endpoint != "" {
+		config.Integration.GitLab.Endpoint = endpoint
+	}
+
+	// Parse Bitbucket configuration
+	if username := p.getEnv(EnvBitbucketUsername); username != "" {
+		config.Integration.Bitbucket.Username = username
+	}
+
+	if token := p.getEnv(EnvBitbucketToken); token != "" {
+		config.Integration.Bitbucket.Token = token
+	}
+
+	if endpoint := p.getEnv(EnvBitbucketEndpoint); endpoint != "" {
+		config.Integration.Bitbucket.Endpoint = endpoint
+	}
+
+	// Parse Go module proxy configuration
+	if proxyURL := p.getEnv(EnvGoProxyURL); proxyURL != "" {
+		config.Integration.GoProxy.URL = proxyURL
+	}
+
+	if username := p.getEnv(EnvGoProxyUsername); username != "" {
+		config.Integration.GoProxy.Username = username
+	}
+
+	if token := p.getEnv(EnvGoProxyToken); token != "" {
+		config.Integration.GoProxy.Token = token
+	}
+
+	if private := p.parseStringList(p.getEnv(EnvGoPrivate)); len(private) > 0 {
+		config.Integration.GoProxy.Private = private
+	}
+
+	// Parse SSH configuration
+	if keyPath := p.getEnv(EnvSSHKeyPath); keyPath != "" {
+		config.Integration.Git.SSH.KeyPath = keyPath
+	}
+
+	if agent := p.getEnv(EnvSSHAgent); agent != "" {
+		value, err := p.parseBool(agent)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", EnvSSHAgent, err)
+		}
+		config.Integration.Git.SSH.Agent = value
+	}
+
+	if knownHosts := p.getEnv(EnvSSHKnownHosts); knownHosts != "" {
+		config.Integration.Git.SSH.KnownHosts = knownHosts
+	}
+
+	// Parse request tagging configuration
+	if userAgent := p.getEnv(EnvUserAgent); userAgent != "" {
+		config.Integration.Requests.UserAgent = userAgent
+	}
+
+	if org := p.getEnv(EnvRequestOrg); org != "" {
+		config.Integration.Requests.Org = org
+	}
+
+	if value := p.getEnv(EnvRequestHeaders); value != "" {
+		headers := make(map[string]string)
+		for _, pair := range p.parseStringList(value) {
+			name, headerValue, ok := strings.Cut(pair, "=")
+			if !ok || strings.TrimSpace(name) == "" {
+				return fmt.Errorf("invalid %s entry %q: expected Name=value", EnvRequestHeaders, pair)
+			}
+			headers[strings.TrimSpace(name)] = strings.TrimSpace(headerValue)
+		}
+		config.Integration.Requests.Headers = headers
+	}
+
 	// Parse Slack configuration
 	if token := p.getEnv(EnvSlackToken); token != "" {
 		config.Integration.Slack.Token = token
@@ -203,9 +396,113 @@ func (p *EnvParser) parseIntegration(config *Config) error {
 		config.Integration.Slack.Channel = channel
 	}
 
+	if secret := p.getEnv(EnvSlackSigningSecret); secret != "" {
+		config.Integration.Slack.SigningSecret = secret
+	}
+
+	// Parse PagerDuty configuration
+	if key := p.getEnv(EnvPagerDutyRoutingKey); key != "" {
+		config.Integration.PagerDuty.RoutingKey = key
+	}
+
 	return nil
 }
 
+// parseApproval parses approval gate environment variables
+func (p *EnvParser) parseApproval(config *Config) error {
+	if mode := p.getEnv(EnvApprovalMode); mode != "" {
+		config.Approval.Mode = mode
+	}
+
+	if channel := p.getEnv(EnvApprovalChannel); channel != "" {
+		config.Approval.Channel = channel
+	}
+
+	if listen := p.getEnv(EnvApprovalListen); listen != "" {
+		config.Approval.Listen = listen
+	}
+
+	if approvers := p.parseStringList(p.getEnv(EnvApprovalApprovers)); len(approvers) > 0 {
+		config.Approval.Approvers = approvers
+	}
+
+	if timeoutStr := p.getEnv(EnvApprovalTimeout); timeoutStr != "" {
+		timeout, err := time.ParseDuration(timeoutStr)
+		if err != nil {
+			return fmt.Errorf("approval configuration errors: invalid %s: %v", EnvApprovalTimeout, err)
+		}
+		config.Approval.Timeout = timeout
+	}
+
+	return nil
+}
+
+// parseUI parses UI environment variables
+func (p *EnvParser) parseUI(config *Config) {
+	if baseURL := p.getEnv(EnvUIBaseURL); baseURL != "" {
+		config.UI.BaseURL = baseURL
+	}
+}
+
+// parseTelemetry parses metrics and tracing environment variables. The
+// standard OTEL_ variables apply when the CASCADE_ ones are not set.
+func (p *EnvParser) parseTelemetry(config *Config) {
+	if listen := p.getEnv(EnvMetricsListen); listen != "" {
+		config.Telemetry.MetricsListen = listen
+	}
+	if gateway := p.getEnv(EnvPushgatewayURL); gateway != "" {
+		config.Telemetry.Pushgateway.URL = gateway
+	}
+	if job := p.getEnv(EnvPushgatewayJob); job != "" {
+		config.Telemetry.Pushgateway.Job = job
+	}
+
+	for _, name := range []string{EnvTracingEndpoint, EnvOTLPTracesEndpoint, EnvOTLPEndpoint} {
+		if endpoint := p.getEnv(name); endpoint != "" {
+			config.Telemetry.Tracing.Endpoint = endpoint
+			break
+		}
+	}
+	if headers := p.getEnv(EnvOTLPHeaders); headers != "" {
+		config.Telemetry.Tracing.Headers = parseOTLPHeaders(headers)
+	}
+	for _, name := range []string{EnvTracingServiceName, EnvOTELServiceName} {
+		if service := p.getEnv(name); service != "" {
+			config.Telemetry.Tracing.ServiceName = service
+			break
+		}
+	}
+}
+
+// parseOTLPHeaders parses comma-separated key=value pairs with URL-encoded
+// values, the format of OTEL_EXPORTER_OTLP_HEADERS.
+func parseOTLPHeaders(value string) map[string]string {
+	headers := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		key, val, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			continue
+		}
+		val = strings.TrimSpace(val)
+		if unescaped, err := url.PathUnescape(val); err == nil {
+			val = unescaped
+		}
+		headers[key] = val
+	}
+	return headers
+}
+
+// parsePullRequests parses pull request format environment variables
+func (p *EnvParser) parsePullRequests(config *Config) {
+	if format := p.getEnv(EnvPRFormat); format != "" {
+		config.PullRequests.Format = format
+	}
+	if prefix := p.getEnv(EnvPRCommitPrefix); prefix != "" {
+		config.PullRequests.CommitPrefix = prefix
+	}
+}
+
 // parseLogging parses logging-related environment variables
 func (p *EnvParser) parseLogging(config *Config) error {
 	var errs []string
@@ -286,6 +583,23 @@ func (p *EnvParser) parseState(config *Config) error {
 		}
 	}
 
+	// Parse remote backend settings
+	if backend := p.getEnv(EnvStateBackend); backend != "" {
+		config.State.Backend = strings.ToLower(strings.TrimSpace(backend))
+	}
+	if bucket := p.getEnv(EnvStateBucket); bucket != "" {
+		config.State.Bucket = bucket
+	}
+	if prefix := p.getEnv(EnvStatePrefix); prefix != "" {
+		config.State.Prefix = prefix
+	}
+	if endpoint := p.getEnv(EnvStateEndpoint); endpoint != "" {
+		config.State.Endpoint = endpoint
+	}
+	if region := p.getEnv(EnvStateRegion); region != "" {
+		config.State.Region = region
+	}
+
 	if len(errs) > 0 {
 		return fmt.Errorf("state configuration errors: %s", strings.Join(errs, "; "))
 	}
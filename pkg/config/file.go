@@ -30,6 +30,8 @@ func applyRawBoolFlags(cfg *Config, raw *rawFileConfig) {
 
 	if raw.Executor.DryRun != nil {
 		cfg.setExecutorDryRun(*raw.Executor.DryRun)
+	} else if cfg.Executor.DryRunMode != "" {
+		cfg.setExecutorDryRunMode(cfg.Executor.DryRunMode)
 	}
 
 	if raw.Logging.Verbose != nil {
@@ -97,6 +99,13 @@ func DiscoverConfigFile() (string, error) {
 // LoadFromFile reads configuration from the provided path.
 // Supports YAML, JSON, and TOML formats based on file extension.
 func LoadFromFile(path string) (*Config, error) {
+	return LoadFromFileWithProfile(path, "")
+}
+
+// LoadFromFileWithProfile reads configuration from the provided path with the
+// named profile of its profiles section merged over the base settings. An empty
+// profile loads the base settings.
+func LoadFromFileWithProfile(path, profile string) (*Config, error) {
 	if path == "" {
 		return nil, fmt.Errorf("config file path cannot be empty")
 	}
@@ -106,8 +115,30 @@ func LoadFromFile(path string) (*Config, error) {
 		return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
 	}
 
-	config := New()
 	ext := strings.ToLower(filepath.Ext(path))
+	config, err := decodeConfigFile(path, data, ext)
+	if err != nil {
+		return nil, err
+	}
+
+	profiled, err := applyProfile(path, data, ext, profile)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	if profiled != nil {
+		config = profiled
+	}
+
+	if err := validateConfigFile(config); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	return config, nil
+}
+
+// decodeConfigFile decodes the contents of the config file at path.
+func decodeConfigFile(path string, data []byte, ext string) (*Config, error) {
+	config := New()
 
 	switch ext {
 	case ".yaml", ".yml":
@@ -132,10 +163,6 @@ func LoadFromFile(path string) (*Config, error) {
 		return nil, fmt.Errorf("unsupported config file format: %s (supported: .yaml, .yml, .json)", ext)
 	}
 
-	if err := validateConfigFile(config); err != nil {
-		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
-	}
-
 	return config, nil
 }
 
@@ -191,6 +218,9 @@ func mergeConfig(dst, src *Config) {
 	if src.Workspace.ManifestPath != "" {
 		dst.Workspace.ManifestPath = src.Workspace.ManifestPath
 	}
+	if src.Workspace.Layout != "" {
+		dst.Workspace.Layout = src.Workspace.Layout
+	}
 
 	// Executor config
 	if src.Executor.Timeout != 0 {
@@ -199,8 +229,48 @@ func mergeConfig(dst, src *Config) {
 	if src.Executor.ConcurrentLimit != 0 {
 		dst.Executor.ConcurrentLimit = src.Executor.ConcurrentLimit
 	}
+	if src.Executor.HeartbeatInterval != 0 {
+		dst.Executor.HeartbeatInterval = src.Executor.HeartbeatInterval
+	}
+	if src.Executor.Retries != 0 {
+		dst.Executor.Retries = src.Executor.Retries
+	}
+	if src.Executor.RetryDelay != 0 {
+		dst.Executor.RetryDelay = src.Executor.RetryDelay
+	}
+	if src.Executor.PlanMaxAge != 0 {
+		dst.Executor.PlanMaxAge = src.Executor.PlanMaxAge
+	}
+	if src.Executor.BaseBranch != "" {
+		dst.Executor.BaseBranch = src.Executor.BaseBranch
+	}
+	if src.Executor.MultiLevel {
+		dst.Executor.MultiLevel = true
+	}
+	if src.Executor.WaveTimeout != 0 {
+		dst.Executor.WaveTimeout = src.Executor.WaveTimeout
+	}
+	if src.Executor.WavePollInterval != 0 {
+		dst.Executor.WavePollInterval = src.Executor.WavePollInterval
+	}
+	if src.Executor.CanaryTimeout != 0 {
+		dst.Executor.CanaryTimeout = src.Executor.CanaryTimeout
+	}
+	if src.Executor.CanaryPollInterval != 0 {
+		dst.Executor.CanaryPollInterval = src.Executor.CanaryPollInterval
+	}
+	if src.Executor.Limits.CPU != 0 {
+		dst.Executor.Limits.CPU = src.Executor.Limits.CPU
+	}
+	if src.Executor.Limits.Memory != "" {
+		dst.Executor.Limits.Memory = src.Executor.Limits.Memory
+	}
+	if src.Executor.Limits.Backend != "" {
+		dst.Executor.Limits.Backend = src.Executor.Limits.Backend
+	}
 	if src.executorDryRunSet() {
 		dst.setExecutorDryRun(src.Executor.DryRun)
+		dst.Executor.DryRunMode = src.Executor.DryRunMode
 	}
 	// Merge SkipUpToDate and ForceAll - these are booleans that need special handling
 	// We need to check if the source explicitly set these values
@@ -222,6 +292,95 @@ func mergeConfig(dst, src *Config) {
 	if src.Integration.GitHub.Organization != "" {
 		dst.Integration.GitHub.Organization = src.Integration.GitHub.Organization
 	}
+	if src.Integration.GitHub.WebhookSecret != "" {
+		dst.Integration.GitHub.WebhookSecret = src.Integration.GitHub.WebhookSecret
+	}
+	if len(src.Integration.GitHub.RateLimitAlerts) > 0 {
+		dst.Integration.GitHub.RateLimitAlerts = append([]int(nil), src.Integration.GitHub.RateLimitAlerts...)
+	}
+	if src.Integration.GitHub.RateLimit.MaxWait != 0 {
+		dst.Integration.GitHub.RateLimit.MaxWait = src.Integration.GitHub.RateLimit.MaxWait
+	}
+	if src.Integration.GitHub.RateLimit.MaxRetries != 0 {
+		dst.Integration.GitHub.RateLimit.MaxRetries = src.Integration.GitHub.RateLimit.MaxRetries
+	}
+	if src.Integration.GitHub.Labels.AutoCreate {
+		dst.Integration.GitHub.Labels.AutoCreate = src.Integration.GitHub.Labels.AutoCreate
+	}
+	if src.Integration.GitHub.Labels.Color != "" {
+		dst.Integration.GitHub.Labels.Color = src.Integration.GitHub.Labels.Color
+	}
+	if src.Integration.GitHub.Labels.Description != "" {
+		dst.Integration.GitHub.Labels.Description = src.Integration.GitHub.Labels.Description
+	}
+	if len(src.Integration.GitHub.Labels.Colors) > 0 {
+		if dst.Integration.GitHub.Labels.Colors == nil {
+			dst.Integration.GitHub.Labels.Colors = make(map[string]string)
+		}
+		for name, color := range src.Integration.GitHub.Labels.Colors {
+			dst.Integration.GitHub.Labels.Colors[name] = color
+		}
+	}
+
+	// Integration config - provider
+	if src.Integration.Provider != "" {
+		dst.Integration.Provider = src.Integration.Provider
+	}
+
+	// Integration config - GitLab
+	if src.Integration.GitLab.Token != "" {
+		dst.Integration.GitLab.Token = src.Integration.GitLab.Token
+	}
+	if src.Integration.GitLab.Endpoint != "" {
+		dst.Integration.GitLab.Endpoint = src.Integration.GitLab.Endpoint
+	}
+
+	// Integration config - Bitbucket
+	if src.Integration.Bitbucket.Username != "" {
+		dst.Integration.Bitbucket.Username = src.Integration.Bitbucket.Username
+	}
+	if src.Integration.Bitbucket.Token != "" {
+		dst.Integration.Bitbucket.Token = src.Integration.Bitbucket.Token
+	}
+	if src.Integration.Bitbucket.Endpoint != "" {
+		dst.Integration.Bitbucket.Endpoint = src.Integration.Bitbucket.Endpoint
+	}
+
+	// Integration config - Go module proxy
+	if src.Integration.GoProxy.URL != "" {
+		dst.Integration.GoProxy.URL = src.Integration.GoProxy.URL
+	}
+	if src.Integration.GoProxy.Username != "" {
+		dst.Integration.GoProxy.Username = src.Integration.GoProxy.Username
+	}
+	if src.Integration.GoProxy.Token != "" {
+		dst.Integration.GoProxy.Token = src.Integration.GoProxy.Token
+	}
+	if src.Integration.GoProxy.WaitTimeout != 0 {
+		dst.Integration.GoProxy.WaitTimeout = src.Integration.GoProxy.WaitTimeout
+	}
+	if src.Integration.GoProxy.PollInterval != 0 {
+		dst.Integration.GoProxy.PollInterval = src.Integration.GoProxy.PollInterval
+	}
+	if len(src.Integration.GoProxy.Private) > 0 {
+		dst.Integration.GoProxy.Private = append([]string(nil), src.Integration.GoProxy.Private...)
+	}
+
+	// Integration config - request tagging
+	if src.Integration.Requests.UserAgent != "" {
+		dst.Integration.Requests.UserAgent = src.Integration.Requests.UserAgent
+	}
+	if src.Integration.Requests.Org != "" {
+		dst.Integration.Requests.Org = src.Integration.Requests.Org
+	}
+	if len(src.Integration.Requests.Headers) > 0 {
+		if dst.Integration.Requests.Headers == nil {
+			dst.Integration.Requests.Headers = make(map[string]string)
+		}
+		for name, value := range src.Integration.Requests.Headers {
+			dst.Integration.Requests.Headers[name] = value
+		}
+	}
 
 	// Integration config - Slack
 	if src.Integration.Slack.Token != "" {
@@ -233,6 +392,83 @@ func mergeConfig(dst, src *Config) {
 	if src.Integration.Slack.Channel != "" {
 		dst.Integration.Slack.Channel = src.Integration.Slack.Channel
 	}
+	if src.Integration.Slack.SigningSecret != "" {
+		dst.Integration.Slack.SigningSecret = src.Integration.Slack.SigningSecret
+	}
+
+	// Integration config - Notification templates
+	if src.Integration.Notifications.Template != "" {
+		dst.Integration.Notifications.Template = src.Integration.Notifications.Template
+	}
+	if len(src.Integration.Notifications.Templates) > 0 {
+		if dst.Integration.Notifications.Templates == nil {
+			dst.Integration.Notifications.Templates = make(map[string]string)
+		}
+		for status, tmpl := range src.Integration.Notifications.Templates {
+			dst.Integration.Notifications.Templates[status] = tmpl
+		}
+	}
+	for name, channel := range src.Integration.Notifications.Channels {
+		if dst.Integration.Notifications.Channels == nil {
+			dst.Integration.Notifications.Channels = make(map[string]NotificationChannelTemplates)
+		}
+		merged := dst.Integration.Notifications.Channels[name]
+		if channel.Template != "" {
+			merged.Template = channel.Template
+		}
+		if len(channel.Templates) > 0 {
+			templates := make(map[string]string, len(merged.Templates)+len(channel.Templates))
+			for status, tmpl := range merged.Templates {
+				templates[status] = tmpl
+			}
+			for status, tmpl := range channel.Templates {
+				templates[status] = tmpl
+			}
+			merged.Templates = templates
+		}
+		dst.Integration.Notifications.Channels[name] = merged
+	}
+
+	if len(src.Integration.Notifications.Escalation) > 0 {
+		dst.Integration.Notifications.Escalation = append([]EscalationStepConfig(nil), src.Integration.Notifications.Escalation...)
+	}
+	if src.Integration.Notifications.Digest.Window != 0 {
+		dst.Integration.Notifications.Digest.Window = src.Integration.Notifications.Digest.Window
+	}
+	if len(src.Integration.Notifications.Digest.Channels) > 0 {
+		dst.Integration.Notifications.Digest.Channels = append([]string(nil), src.Integration.Notifications.Digest.Channels...)
+	}
+
+	// Integration config - PagerDuty
+	if src.Integration.PagerDuty.RoutingKey != "" {
+		dst.Integration.PagerDuty.RoutingKey = src.Integration.PagerDuty.RoutingKey
+	}
+	if src.Integration.PagerDuty.Endpoint != "" {
+		dst.Integration.PagerDuty.Endpoint = src.Integration.PagerDuty.Endpoint
+	}
+	if src.Integration.PagerDuty.Severity != "" {
+		dst.Integration.PagerDuty.Severity = src.Integration.PagerDuty.Severity
+	}
+
+	// Integration config - Git hosts
+	if src.Integration.Git.DefaultHost != "" {
+		dst.Integration.Git.DefaultHost = src.Integration.Git.DefaultHost
+	}
+	if len(src.Integration.Git.Hosts) > 0 {
+		dst.Integration.Git.Hosts = append([]GitHostConfig(nil), src.Integration.Git.Hosts...)
+	}
+	if len(src.Integration.Git.Rewrites) > 0 {
+		dst.Integration.Git.Rewrites = append([]GitURLRewrite(nil), src.Integration.Git.Rewrites...)
+	}
+	if src.Integration.Git.SSH.KeyPath != "" {
+		dst.Integration.Git.SSH.KeyPath = src.Integration.Git.SSH.KeyPath
+	}
+	if src.Integration.Git.SSH.Agent {
+		dst.Integration.Git.SSH.Agent = true
+	}
+	if src.Integration.Git.SSH.KnownHosts != "" {
+		dst.Integration.Git.SSH.KnownHosts = src.Integration.Git.SSH.KnownHosts
+	}
 
 	// Logging config
 	if src.Logging.Level != "" {
@@ -258,6 +494,79 @@ func mergeConfig(dst, src *Config) {
 	if src.stateEnabledSet() {
 		dst.setStateEnabled(src.State.Enabled)
 	}
+	if src.State.Backend != "" {
+		dst.State.Backend = src.State.Backend
+	}
+	if src.State.Bucket != "" {
+		dst.State.Bucket = src.State.Bucket
+	}
+	if src.State.Prefix != "" {
+		dst.State.Prefix = src.State.Prefix
+	}
+	if src.State.Endpoint != "" {
+		dst.State.Endpoint = src.State.Endpoint
+	}
+	if src.State.Region != "" {
+		dst.State.Region = src.State.Region
+	}
+
+	// Approval config
+	if src.Approval.Mode != "" {
+		dst.Approval.Mode = src.Approval.Mode
+	}
+	if src.Approval.Channel != "" {
+		dst.Approval.Channel = src.Approval.Channel
+	}
+	if src.Approval.Timeout != 0 {
+		dst.Approval.Timeout = src.Approval.Timeout
+	}
+	if src.Approval.Listen != "" {
+		dst.Approval.Listen = src.Approval.Listen
+	}
+	if len(src.Approval.Approvers) > 0 {
+		dst.Approval.Approvers = src.Approval.Approvers
+	}
+
+	// UI config
+	if src.UI.BaseURL != "" {
+		dst.UI.BaseURL = src.UI.BaseURL
+	}
+
+	// Pull request format config
+	if src.PullRequests.Format != "" {
+		dst.PullRequests.Format = src.PullRequests.Format
+	}
+	if src.PullRequests.CommitPrefix != "" {
+		dst.PullRequests.CommitPrefix = src.PullRequests.CommitPrefix
+	}
+
+	// Telemetry config
+	if src.Telemetry.MetricsListen != "" {
+		dst.Telemetry.MetricsListen = src.Telemetry.MetricsListen
+	}
+	if src.Telemetry.Pushgateway.URL != "" {
+		dst.Telemetry.Pushgateway.URL = src.Telemetry.Pushgateway.URL
+	}
+	if src.Telemetry.Pushgateway.Job != "" {
+		dst.Telemetry.Pushgateway.Job = src.Telemetry.Pushgateway.Job
+	}
+	if src.Telemetry.Pushgateway.Interval != 0 {
+		dst.Telemetry.Pushgateway.Interval = src.Telemetry.Pushgateway.Interval
+	}
+	if src.Telemetry.Tracing.Endpoint != "" {
+		dst.Telemetry.Tracing.Endpoint = src.Telemetry.Tracing.Endpoint
+	}
+	if len(src.Telemetry.Tracing.Headers) > 0 {
+		if dst.Telemetry.Tracing.Headers == nil {
+			dst.Telemetry.Tracing.Headers = make(map[string]string)
+		}
+		for name, value := range src.Telemetry.Tracing.Headers {
+			dst.Telemetry.Tracing.Headers[name] = value
+		}
+	}
+	if src.Telemetry.Tracing.ServiceName != "" {
+		dst.Telemetry.Tracing.ServiceName = src.Telemetry.Tracing.ServiceName
+	}
 
 	// ManifestGenerator config
 	if src.ManifestGenerator.DefaultWorkspace != "" {
@@ -277,6 +586,9 @@ func mergeConfig(dst, src *Config) {
 	if src.ManifestGenerator.Tests.WorkingDirectory != "" {
 		dst.ManifestGenerator.Tests.WorkingDirectory = src.ManifestGenerator.Tests.WorkingDirectory
 	}
+	if src.ManifestGenerator.Tests.DisableDetection {
+		dst.ManifestGenerator.Tests.DisableDetection = true
+	}
 
 	// ManifestGenerator notifications config
 	if src.ManifestGenerator.Notifications.Enabled {
@@ -322,6 +634,21 @@ func mergeConfig(dst, src *Config) {
 	if len(src.ManifestGenerator.Discovery.GitHub.ExcludePatterns) > 0 {
 		dst.ManifestGenerator.Discovery.GitHub.ExcludePatterns = src.ManifestGenerator.Discovery.GitHub.ExcludePatterns
 	}
+	if src.ManifestGenerator.Discovery.GitHub.Filter != "" {
+		dst.ManifestGenerator.Discovery.GitHub.Filter = src.ManifestGenerator.Discovery.GitHub.Filter
+	}
+	if src.ManifestGenerator.Discovery.GitHub.FullScan {
+		dst.ManifestGenerator.Discovery.GitHub.FullScan = src.ManifestGenerator.Discovery.GitHub.FullScan
+	}
+	if src.ManifestGenerator.Discovery.GitHub.GraphQL {
+		dst.ManifestGenerator.Discovery.GitHub.GraphQL = src.ManifestGenerator.Discovery.GitHub.GraphQL
+	}
+	if src.ManifestGenerator.Discovery.GitHub.ScanCheckpoint != "" {
+		dst.ManifestGenerator.Discovery.GitHub.ScanCheckpoint = src.ManifestGenerator.Discovery.GitHub.ScanCheckpoint
+	}
+	if src.ManifestGenerator.Discovery.GitHub.CacheDir != "" {
+		dst.ManifestGenerator.Discovery.GitHub.CacheDir = src.ManifestGenerator.Discovery.GitHub.CacheDir
+	}
 
 	// ManifestGenerator template profiles
 	if len(src.ManifestGenerator.TemplateProfiles) > 0 {
@@ -380,6 +707,54 @@ func validateConfigFile(config *Config) error {
 		errors = append(errors, "timeout must be positive")
 	}
 
+	if config.Executor.HeartbeatInterval < 0 {
+		errors = append(errors, "heartbeat_interval must be positive")
+	}
+
+	if config.Executor.Retries < 0 {
+		errors = append(errors, "retries must not be negative")
+	}
+
+	if config.Executor.RetryDelay < 0 {
+		errors = append(errors, "retry_delay must be positive")
+	}
+
+	if config.Executor.PlanMaxAge < 0 {
+		errors = append(errors, "plan_max_age must not be negative")
+	}
+
+	if config.Executor.WaveTimeout < 0 {
+		errors = append(errors, "wave_timeout must be positive")
+	}
+
+	if config.Executor.WavePollInterval < 0 {
+		errors = append(errors, "wave_poll_interval must be positive")
+	}
+
+	if config.Executor.CanaryTimeout < 0 {
+		errors = append(errors, "canary_timeout must be positive")
+	}
+
+	if config.Executor.CanaryPollInterval < 0 {
+		errors = append(errors, "canary_poll_interval must be positive")
+	}
+
+	// Validate label colors
+	if color := config.Integration.GitHub.Labels.Color; color != "" && !isHexColor(color) {
+		errors = append(errors, fmt.Sprintf("invalid integration.github.labels.color '%s', must be a 6 digit hex color", color))
+	}
+	for name, color := range config.Integration.GitHub.Labels.Colors {
+		if !isHexColor(color) {
+			errors = append(errors, fmt.Sprintf("invalid integration.github.labels.colors[%s] '%s', must be a 6 digit hex color", name, color))
+		}
+	}
+
+	for _, threshold := range config.Integration.GitHub.RateLimitAlerts {
+		if threshold < 1 || threshold > 100 {
+			errors = append(errors, fmt.Sprintf("invalid integration.github.rate_limit_alerts value %d, must be between 1 and 100", threshold))
+		}
+	}
+
 	// Validate state settings
 	if config.State.RetentionCount < 0 {
 		errors = append(errors, "state retention_count must be positive")
@@ -398,3 +773,19 @@ func validateConfigFile(config *Config) error {
 
 	return nil
 }
+
+// isHexColor reports whether value is a 6 digit hex color with an optional leading '#'.
+func isHexColor(value string) bool {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "#")
+	if len(value) != 6 {
+		return false
+	}
+	for _, r := range value {
+		switch {
+		case r >= '0' && r <= '9', r >= 'a' && r <= 'f', r >= 'A' && r <= 'F':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -260,6 +260,17 @@ executor:
 `,
 			errorMsg: "timeout must be positive",
 		},
+		{
+			name: "invalid label color",
+			config: `
+integration:
+  github:
+    labels:
+      auto_create: true
+      color: "green"
+`,
+			errorMsg: "invalid integration.github.labels.color",
+		},
 	}
 
 	for _, tt := range tests {
@@ -468,6 +479,117 @@ func TestMergeConfigs(t *testing.T) {
 	}
 }
 
+func TestMergeConfigs_GitHubLabels(t *testing.T) {
+	base := &config.Config{}
+	base.Integration.GitHub.Labels.Color = "ededed"
+	base.Integration.GitHub.Labels.Colors = map[string]string{"deps": "0366d6"}
+
+	override := &config.Config{}
+	override.Integration.GitHub.Labels.AutoCreate = true
+	override.Integration.GitHub.Labels.Colors = map[string]string{"automation:cascade": "5319e7"}
+
+	result := config.MergeConfigs(base, override)
+	labels := result.Integration.GitHub.Labels
+
+	if !labels.AutoCreate {
+		t.Error("Expected auto_create to be enabled")
+	}
+	if labels.Color != "ededed" {
+		t.Errorf("Expected base color to be kept, got %q", labels.Color)
+	}
+	if labels.Colors["deps"] != "0366d6" || labels.Colors["automation:cascade"] != "5319e7" {
+		t.Errorf("Expected per-label colors to be merged, got %v", labels.Colors)
+	}
+}
+
+func TestMergeConfigs_RateLimitAlerts(t *testing.T) {
+	base := &config.Config{}
+	base.Integration.GitHub.RateLimitAlerts = []int{90}
+
+	kept := config.MergeConfigs(base, &config.Config{})
+	if len(kept.Integration.GitHub.RateLimitAlerts) != 1 || kept.Integration.GitHub.RateLimitAlerts[0] != 90 {
+		t.Errorf("Expected base thresholds to be kept, got %v", kept.Integration.GitHub.RateLimitAlerts)
+	}
+
+	override := &config.Config{}
+	override.Integration.GitHub.RateLimitAlerts = []int{50, 80}
+
+	result := config.MergeConfigs(base, override)
+	if got := result.Integration.GitHub.RateLimitAlerts; len(got) != 2 || got[0] != 50 || got[1] != 80 {
+		t.Errorf("Expected override thresholds to replace the base list, got %v", got)
+	}
+}
+
+func TestMergeConfigs_GitHosts(t *testing.T) {
+	base := &config.Config{}
+	base.Integration.Git.DefaultHost = "github.example.com"
+	base.Integration.Git.Hosts = []config.GitHostConfig{{Name: "github.example.com"}}
+
+	override := &config.Config{}
+	override.Integration.Git.Rewrites = []config.GitURLRewrite{{Base: "ssh://git@mirror/", InsteadOf: "https://github.example.com/"}}
+
+	result := config.MergeConfigs(base, override).Integration.Git
+	if result.DefaultHost != "github.example.com" || len(result.Hosts) != 1 {
+		t.Errorf("Expected base hosts to be kept, got %+v", result)
+	}
+	if len(result.Rewrites) != 1 || result.Rewrites[0].Base != "ssh://git@mirror/" {
+		t.Errorf("Expected override rewrites, got %+v", result.Rewrites)
+	}
+}
+
+func TestMergeConfigs_NotificationTemplates(t *testing.T) {
+	base := &config.Config{}
+	base.Integration.Notifications.Template = "base default"
+	base.Integration.Notifications.Templates = map[string]string{"failed": "base failed"}
+	base.Integration.Notifications.Channels = map[string]config.NotificationChannelTemplates{
+		"slack": {Template: "base slack", Templates: map[string]string{"completed": "base slack completed"}},
+	}
+
+	override := &config.Config{}
+	override.Integration.Notifications.Templates = map[string]string{"completed": "override completed"}
+	override.Integration.Notifications.Channels = map[string]config.NotificationChannelTemplates{
+		"slack":   {Templates: map[string]string{"failed": "override slack failed"}},
+		"webhook": {Template: "override webhook"},
+	}
+
+	result := config.MergeConfigs(base, override).Integration.Notifications
+
+	if result.Template != "base default" {
+		t.Errorf("Expected base template to be kept, got %q", result.Template)
+	}
+	if result.Templates["failed"] != "base failed" || result.Templates["completed"] != "override completed" {
+		t.Errorf("Expected status templates to be merged, got %v", result.Templates)
+	}
+	slack := result.Channels["slack"]
+	if slack.Template != "base slack" || slack.Templates["completed"] != "base slack completed" || slack.Templates["failed"] != "override slack failed" {
+		t.Errorf("Expected slack templates to be merged, got %+v", slack)
+	}
+	if result.Channels["webhook"].Template != "override webhook" {
+		t.Errorf("Expected webhook channel to be added, got %+v", result.Channels["webhook"])
+	}
+}
+
+func TestMergeConfigs_EscalationAndPagerDuty(t *testing.T) {
+	base := &config.Config{}
+	base.Integration.Notifications.Escalation = []config.EscalationStepConfig{{Attempt: 1, Channels: []string{"slack"}}}
+	base.Integration.PagerDuty = config.PagerDutyConfig{RoutingKey: "base-key", Severity: "warning"}
+
+	override := &config.Config{}
+	override.Integration.Notifications.Escalation = []config.EscalationStepConfig{
+		{Attempt: 1, Channels: []string{"slack"}},
+		{Attempt: 3, Channels: []string{"pagerduty"}, Assignees: []string{"team-lead"}},
+	}
+	override.Integration.PagerDuty.Severity = "critical"
+
+	result := config.MergeConfigs(base, override).Integration
+	if len(result.Notifications.Escalation) != 2 || result.Notifications.Escalation[1].Attempt != 3 {
+		t.Errorf("Expected override ladder to replace the base ladder, got %+v", result.Notifications.Escalation)
+	}
+	if result.PagerDuty.RoutingKey != "base-key" || result.PagerDuty.Severity != "critical" {
+		t.Errorf("Expected PagerDuty settings to be merged, got %+v", result.PagerDuty)
+	}
+}
+
 func TestMergeConfigs_BooleanOverride(t *testing.T) {
 	t.Setenv(config.EnvDryRun, "true")
 	cTrue, err := config.FromEnv()
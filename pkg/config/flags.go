@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -17,11 +18,13 @@ type FlagConfig struct {
 	Module     string
 	Version    string
 	DryRun     bool
+	DryRunMode string
 	Verbose    bool
 	Quiet      bool
 	Timeout    time.Duration
 	Parallel   int
 	ConfigFile string
+	Profile    string
 
 	// GitHub integration flags
 	GitHubToken    string
@@ -45,6 +48,9 @@ type FlagConfig struct {
 	SkipUpToDate bool
 	ForceAll     bool
 
+	// Telemetry flags
+	MetricsListen string
+
 	timeoutSet      bool
 	parallelSet     bool
 	dryRunSet       bool
@@ -74,10 +80,12 @@ func AddFlags(cmd *cobra.Command) *FlagConfig {
 		"Target version for operations")
 	cmd.PersistentFlags().StringVarP(&fc.ConfigFile, "config", "c", "",
 		"Configuration file path")
+	cmd.PersistentFlags().StringVar(&fc.Profile, "profile", "",
+		"Configuration file profile to apply over the base settings (e.g. prod)")
 
 	// Execution control flags
-	cmd.PersistentFlags().BoolVarP(&fc.DryRun, "dry-run", "n", false,
-		"Preview mode without making changes")
+	cmd.PersistentFlags().VarPF(&dryRunValue{enabled: &fc.DryRun, mode: &fc.DryRunMode}, "dry-run", "n",
+		"Preview mode without making changes; --dry-run=diff applies updates in a temporary clone and prints their diffs").NoOptDefVal = "true"
 	cmd.PersistentFlags().DurationVar(&fc.Timeout, "timeout", 5*time.Minute,
 		"Operation timeout duration")
 	cmd.PersistentFlags().IntVarP(&fc.Parallel, "parallel", "p", 0,
@@ -99,6 +107,10 @@ func AddFlags(cmd *cobra.Command) *FlagConfig {
 	cmd.PersistentFlags().StringVar(&fc.LogFormat, "log-format", "",
 		"Log output format (text, json)")
 
+	// Telemetry flags
+	cmd.PersistentFlags().StringVar(&fc.MetricsListen, "metrics-listen", "",
+		"Serve Prometheus metrics at /metrics on this address while the command runs (e.g. :9464)")
+
 	// GitHub integration flags
 	cmd.Flags().StringVar(&fc.GitHubToken, "github-token", "",
 		"GitHub authentication token")
@@ -129,6 +141,39 @@ func AddFlags(cmd *cobra.Command) *FlagConfig {
 	return fc
 }
 
+// dryRunValue is the --dry-run flag: a boolean that also accepts "diff".
+type dryRunValue struct {
+	enabled *bool
+	mode    *string
+}
+
+func (v *dryRunValue) String() string {
+	if v.mode != nil && *v.mode != "" {
+		return *v.mode
+	}
+	return strconv.FormatBool(v.enabled != nil && *v.enabled)
+}
+
+func (v *dryRunValue) Set(value string) error {
+	if value == DryRunModeDiff {
+		*v.enabled = true
+		*v.mode = value
+		return nil
+	}
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return fmt.Errorf("must be true, false, or %s", DryRunModeDiff)
+	}
+	*v.enabled = enabled
+	*v.mode = ""
+	return nil
+}
+
+// Type reports bool, so help shows --dry-run without a value placeholder.
+func (v *dryRunValue) Type() string {
+	return "bool"
+}
+
 // ValidateFlags validates flag combinations and values.
 // Returns an error if any validation rules are violated.
 func (fc *FlagConfig) ValidateFlags() error {
@@ -206,6 +251,7 @@ func (fc *FlagConfig) ToConfig() (*Config, error) {
 	// Dry run flag
 	if fc.dryRunSet {
 		config.setExecutorDryRun(fc.DryRun)
+		config.Executor.DryRunMode = fc.DryRunMode
 	}
 
 	// Dependency checking flags - use setter methods to properly track that these were set
@@ -269,6 +315,11 @@ func (fc *FlagConfig) ToConfig() (*Config, error) {
 		config.setStateEnabled(fc.StateEnabled)
 	}
 
+	// Telemetry flags
+	if fc.MetricsListen != "" {
+		config.Telemetry.MetricsListen = fc.MetricsListen
+	}
+
 	return config, nil
 }
 
@@ -310,8 +361,13 @@ func extractFlagConfig(flags *pflag.FlagSet) *FlagConfig {
 	if flags.Changed("config") {
 		fc.ConfigFile, _ = flags.GetString("config")
 	}
+	if flags.Changed("profile") {
+		fc.Profile, _ = flags.GetString("profile")
+	}
 	if flags.Changed("dry-run") {
-		fc.DryRun, _ = flags.GetBool("dry-run")
+		// --dry-run=diff is not a boolean, so the flag is parsed again
+		value := &dryRunValue{enabled: &fc.DryRun, mode: &fc.DryRunMode}
+		_ = value.Set(flags.Lookup("dry-run").Value.String())
 		fc.dryRunSet = true
 	}
 	if flags.Changed("timeout") {
@@ -378,6 +434,9 @@ func extractFlagConfig(flags *pflag.FlagSet) *FlagConfig {
 		fc.StateEnabled, _ = flags.GetBool("state")
 		fc.stateSet = true
 	}
+	if flags.Changed("metrics-listen") {
+		fc.MetricsListen, _ = flags.GetString("metrics-listen")
+	}
 
 	if !fc.timeoutSet {
 		fc.Timeout = 0
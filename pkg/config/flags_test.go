@@ -461,6 +461,30 @@ func TestExtractFlagConfig(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "extracts dry run diff mode",
+			setup: func() *cobra.Command {
+				cmd := &cobra.Command{Use: "test"}
+				AddFlags(cmd)
+				cmd.SetOut(io.Discard)
+				cmd.SetErr(io.Discard)
+				cmd.SetArgs([]string{"--dry-run=diff"})
+				cmd.Execute()
+				return cmd
+			},
+			test: func(t *testing.T, fc *FlagConfig) {
+				if !fc.DryRun || fc.DryRunMode != DryRunModeDiff || !fc.dryRunSet {
+					t.Errorf("Expected a diff dry run, got DryRun=%v DryRunMode=%q", fc.DryRun, fc.DryRunMode)
+				}
+				cfg, err := fc.ToConfig()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !cfg.Executor.DiffPreview() {
+					t.Error("Expected DiffPreview() to be true")
+				}
+			},
+		},
 		{
 			name: "extracts logging flags",
 			setup: func() *cobra.Command {
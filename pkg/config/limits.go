@@ -0,0 +1,75 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Resource limit backends accepted by ResourceLimits.Backend.
+const (
+	LimitBackendEnv    = "env"
+	LimitBackendCgroup = "cgroup"
+)
+
+// byteUnits maps quantity suffixes to multipliers. Binary (Ki, Mi, Gi) and
+// decimal (K, M, G) suffixes follow the Kubernetes quantity conventions.
+var byteUnits = map[string]int64{
+	"":   1,
+	"b":  1,
+	"k":  1000,
+	"kb": 1000,
+	"ki": 1 << 10,
+	"m":  1000 * 1000,
+	"mb": 1000 * 1000,
+	"mi": 1 << 20,
+	"g":  1000 * 1000 * 1000,
+	"gb": 1000 * 1000 * 1000,
+	"gi": 1 << 30,
+	"t":  1000 * 1000 * 1000 * 1000,
+	"tb": 1000 * 1000 * 1000 * 1000,
+	"ti": 1 << 40,
+}
+
+// MemoryBytes returns the configured memory limit in bytes, or 0 when unset.
+func (l ResourceLimits) MemoryBytes() (int64, error) {
+	if strings.TrimSpace(l.Memory) == "" {
+		return 0, nil
+	}
+	return ParseByteSize(l.Memory)
+}
+
+// ParseByteSize parses a byte quantity such as "4Gi", "512Mi", "2G", or "1048576".
+func ParseByteSize(value string) (int64, error) {
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "" {
+		return 0, fmt.Errorf("empty byte size")
+	}
+
+	split := len(trimmed)
+	for split > 0 && !isQuantityDigit(trimmed[split-1]) {
+		split--
+	}
+	number, unit := trimmed[:split], strings.ToLower(strings.TrimSpace(trimmed[split:]))
+
+	multiplier, ok := byteUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("invalid byte size %q: unknown unit %q", value, trimmed[split:])
+	}
+
+	n, err := strconv.ParseFloat(number, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid byte size %q: must be a positive number with an optional unit such as Mi or Gi", value)
+	}
+
+	bytes := n * float64(multiplier)
+	if bytes > math.MaxInt64 {
+		return 0, fmt.Errorf("invalid byte size %q: too large", value)
+	}
+	return int64(bytes), nil
+}
+
+func isQuantityDigit(c byte) bool {
+	return (c >= '0' && c <= '9') || c == '.'
+}
@@ -0,0 +1,69 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/goliatone/cascade/pkg/config"
+	"gopkg.in/yaml.v3"
+)
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    int64
+		wantErr bool
+	}{
+		{input: "1048576", want: 1 << 20},
+		{input: "4Gi", want: 4 << 30},
+		{input: "512Mi", want: 512 << 20},
+		{input: "512mi", want: 512 << 20},
+		{input: "2G", want: 2_000_000_000},
+		{input: "1.5Gi", want: 3 << 29},
+		{input: " 64 KiB", wantErr: true},
+		{input: "", wantErr: true},
+		{input: "Gi", wantErr: true},
+		{input: "-1Gi", wantErr: true},
+		{input: "4Xi", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := config.ParseByteSize(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error for %q, got %d", tt.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("ParseByteSize(%q) = %d, want %d", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResourceLimitsYAML(t *testing.T) {
+	var cfg config.Config
+	data := []byte("executor:\n  limits:\n    cpu: 2\n    memory: 4Gi\n    backend: cgroup\n")
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	limits := cfg.Executor.Limits
+	if limits.CPU != 2 || limits.Memory != "4Gi" || limits.Backend != config.LimitBackendCgroup {
+		t.Fatalf("unexpected limits: %+v", limits)
+	}
+	if limits.IsZero() {
+		t.Fatal("expected configured limits to be non-zero")
+	}
+	bytes, err := limits.MemoryBytes()
+	if err != nil || bytes != 4<<30 {
+		t.Fatalf("MemoryBytes() = %d, %v", bytes, err)
+	}
+	if !(config.ResourceLimits{Backend: config.LimitBackendEnv}).IsZero() {
+		t.Fatal("expected limits with only a backend to be zero")
+	}
+}
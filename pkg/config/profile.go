@@ -0,0 +1,169 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// profilesKey is the config file section holding named profiles. Each profile
+// holds settings in the same layout as the file itself, merged over the base
+// settings when the profile is selected.
+const profilesKey = "profiles"
+
+// SelectedProfile returns the profile selected with the --profile flag of cmd,
+// or with CASCADE_PROFILE when the flag is not set.
+func SelectedProfile(cmd *cobra.Command) string {
+	if cmd != nil && cmd.Flags().Lookup("profile") != nil && cmd.Flags().Changed("profile") {
+		profile, _ := cmd.Flags().GetString("profile")
+		return strings.TrimSpace(profile)
+	}
+	return strings.TrimSpace(os.Getenv(EnvProfile))
+}
+
+// applyProfile decodes a config file with the named profile merged over its
+// base settings. Maps are merged key by key; lists and other values of the
+// profile replace those of the base. Every profile of the file is checked to
+// name only known settings and to merge into a valid configuration, so a broken
+// profile is reported before it is first selected.
+//
+// It returns nil when the file has no profiles and none is selected, leaving
+// the base settings as decoded.
+func applyProfile(path string, data []byte, ext, profile string) (*Config, error) {
+	doc, err := parseDocument(data, ext)
+	if err != nil {
+		return nil, err
+	}
+	rawProfiles, hasProfiles := doc[profilesKey]
+	if !hasProfiles && profile == "" {
+		return nil, nil
+	}
+	delete(doc, profilesKey)
+
+	profiles, ok := rawProfiles.(map[string]any)
+	if rawProfiles != nil && !ok {
+		return nil, fmt.Errorf("%s must map profile names to settings", profilesKey)
+	}
+
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var selected *Config
+	for _, name := range names {
+		settings, ok := profiles[name].(map[string]any)
+		if profiles[name] != nil && !ok {
+			return nil, fmt.Errorf("profile %q must be a mapping of settings", name)
+		}
+		if err := checkProfileSettings(settings, ext); err != nil {
+			return nil, fmt.Errorf("profile %q: %w", name, err)
+		}
+		cfg, err := decodeDocument(path, mergeDocuments(doc, settings), ext)
+		if err != nil {
+			return nil, fmt.Errorf("profile %q: %w", name, err)
+		}
+		if err := validateConfigFile(cfg); err != nil {
+			return nil, fmt.Errorf("profile %q: %w", name, err)
+		}
+		if name == profile {
+			selected = cfg
+		}
+	}
+
+	if profile == "" {
+		return decodeDocument(path, doc, ext)
+	}
+	if selected == nil {
+		if len(names) == 0 {
+			return nil, fmt.Errorf("profile %q not found: the file defines no profiles", profile)
+		}
+		return nil, fmt.Errorf("profile %q not found (available: %s)", profile, strings.Join(names, ", "))
+	}
+	return selected, nil
+}
+
+// mergeDocuments returns overlay merged over base, without modifying either.
+func mergeDocuments(base, overlay map[string]any) map[string]any {
+	merged := make(map[string]any, len(base)+len(overlay))
+	for key, value := range base {
+		merged[key] = value
+	}
+	for key, value := range overlay {
+		baseMap, baseOK := merged[key].(map[string]any)
+		overlayMap, overlayOK := value.(map[string]any)
+		if baseOK && overlayOK {
+			merged[key] = mergeDocuments(baseMap, overlayMap)
+			continue
+		}
+		merged[key] = value
+	}
+	return merged
+}
+
+// checkProfileSettings rejects settings that are not part of the configuration,
+// such as misspelled keys, which would otherwise be ignored.
+func checkProfileSettings(settings map[string]any, ext string) error {
+	data, err := encodeDocument(settings, ext)
+	if err != nil {
+		return err
+	}
+	switch ext {
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(New())
+	default:
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		err = dec.Decode(New())
+	}
+	if err != nil {
+		return fmt.Errorf("unknown or invalid setting: %w", err)
+	}
+	return nil
+}
+
+// parseDocument decodes a config file without a schema, so profiles can be
+// merged key by key.
+func parseDocument(data []byte, ext string) (map[string]any, error) {
+	doc := map[string]any{}
+	switch ext {
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		if err := dec.Decode(&doc); err != nil {
+			return nil, err
+		}
+	default:
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, err
+		}
+	}
+	if doc == nil {
+		doc = map[string]any{}
+	}
+	return doc, nil
+}
+
+func encodeDocument(doc map[string]any, ext string) ([]byte, error) {
+	if ext == ".json" {
+		return json.Marshal(doc)
+	}
+	return yaml.Marshal(doc)
+}
+
+func decodeDocument(path string, doc map[string]any, ext string) (*Config, error) {
+	data, err := encodeDocument(doc, ext)
+	if err != nil {
+		return nil, err
+	}
+	return decodeConfigFile(path, data, ext)
+}
@@ -0,0 +1,179 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goliatone/cascade/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+const profiledYAML = `
+executor:
+  timeout: "5m"
+  dry_run: true
+integration:
+  github:
+    webhook_secret: "base-secret"
+    organization: "acme"
+    rate_limit_alerts: [50, 80]
+profiles:
+  ghe:
+    executor:
+      dry_run: false
+    integration:
+      github:
+        endpoint: "https://github.acme.internal/api/v3"
+        rate_limit_alerts: [90]
+  staging:
+    logging:
+      level: "debug"
+`
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test config file: %v", err)
+	}
+	return path
+}
+
+func TestLoadFromFileWithProfile(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", profiledYAML)
+
+	cfg, err := config.LoadFromFileWithProfile(path, "ghe")
+	if err != nil {
+		t.Fatalf("LoadFromFileWithProfile failed: %v", err)
+	}
+	github := cfg.Integration.GitHub
+	if github.Endpoint != "https://github.acme.internal/api/v3" {
+		t.Errorf("Expected the profile endpoint, got %q", github.Endpoint)
+	}
+	if github.WebhookSecret != "base-secret" || github.Organization != "acme" {
+		t.Errorf("Expected base settings the profile does not set to be kept, got %+v", github)
+	}
+	if len(github.RateLimitAlerts) != 1 || github.RateLimitAlerts[0] != 90 {
+		t.Errorf("Expected profile lists to replace base lists, got %v", github.RateLimitAlerts)
+	}
+	if cfg.Executor.DryRun {
+		t.Error("Expected the profile to turn dry run off")
+	}
+	if cfg.Executor.Timeout != 5*time.Minute {
+		t.Errorf("Expected base timeout 5m, got %v", cfg.Executor.Timeout)
+	}
+
+	base, err := config.LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadFromFile failed: %v", err)
+	}
+	if base.Integration.GitHub.Endpoint != "" || !base.Executor.DryRun || base.Logging.Level != "" {
+		t.Errorf("Expected no profile to be applied by default, got %+v", base)
+	}
+}
+
+func TestLoadFromFileWithProfile_JSON(t *testing.T) {
+	path := writeConfigFile(t, "config.json", `{
+  "executor": {"timeout": 300000000000},
+  "integration": {"github": {"organization": "acme"}},
+  "profiles": {
+    "ghe": {"integration": {"github": {"endpoint": "https://github.acme.internal/api/v3"}}}
+  }
+}`)
+
+	cfg, err := config.LoadFromFileWithProfile(path, "ghe")
+	if err != nil {
+		t.Fatalf("LoadFromFileWithProfile failed: %v", err)
+	}
+	if cfg.Integration.GitHub.Endpoint != "https://github.acme.internal/api/v3" || cfg.Integration.GitHub.Organization != "acme" {
+		t.Errorf("Expected the profile merged over the base, got %+v", cfg.Integration.GitHub)
+	}
+	if cfg.Executor.Timeout != 5*time.Minute {
+		t.Errorf("Expected base timeout 5m, got %v", cfg.Executor.Timeout)
+	}
+}
+
+func TestLoadFromFileWithProfile_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		profile string
+		want    string
+	}{
+		{
+			name:    "unknown profile",
+			content: profiledYAML,
+			profile: "prod",
+			want:    `profile "prod" not found (available: ghe, staging)`,
+		},
+		{
+			name:    "no profiles",
+			content: "integration:\n  github:\n    organization: acme\n",
+			profile: "prod",
+			want:    `profile "prod" not found: the file defines no profiles`,
+		},
+		{
+			name:    "misspelled setting in an unselected profile",
+			content: "profiles:\n  prod:\n    integration:\n      github:\n        endpiont: https://github.acme.internal/api/v3\n",
+			want:    `profile "prod": unknown or invalid setting`,
+		},
+		{
+			name:    "invalid value in an unselected profile",
+			content: "profiles:\n  prod:\n    integration:\n      github:\n        rate_limit_alerts: [150]\n",
+			want:    `profile "prod": configuration validation failed`,
+		},
+		{
+			name:    "profile that is not a mapping",
+			content: "profiles:\n  prod: github.com\n",
+			want:    `profile "prod" must be a mapping of settings`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfigFile(t, "config.yaml", tt.content)
+			_, err := config.LoadFromFileWithProfile(path, tt.profile)
+			if err == nil {
+				t.Fatal("Expected an error")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got: %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestBuilder_Profile(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", profiledYAML)
+
+	cfg, err := config.NewBuilder().Profile("staging").FromFile(path).Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if cfg.Logging.Level != "debug" {
+		t.Errorf("Expected the staging profile log level, got %s", cfg.Logging.Level)
+	}
+	if cfg.Integration.GitHub.Endpoint != "https://api.github.com" {
+		t.Errorf("Expected the default endpoint, got %s", cfg.Integration.GitHub.Endpoint)
+	}
+}
+
+func TestSelectedProfile(t *testing.T) {
+	t.Setenv(config.EnvProfile, "staging")
+
+	cmd := &cobra.Command{Use: "test"}
+	config.AddFlags(cmd)
+	if got := config.SelectedProfile(cmd); got != "staging" {
+		t.Errorf("Expected the CASCADE_PROFILE profile, got %q", got)
+	}
+
+	if err := cmd.ParseFlags([]string{"--profile", "ghe"}); err != nil {
+		t.Fatalf("ParseFlags failed: %v", err)
+	}
+	if got := config.SelectedProfile(cmd); got != "ghe" {
+		t.Errorf("Expected --profile to win over CASCADE_PROFILE, got %q", got)
+	}
+}
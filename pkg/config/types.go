@@ -24,6 +24,18 @@ type Config struct {
 	// ManifestGenerator contains defaults for manifest generation operations
 	ManifestGenerator ManifestGeneratorConfig `json:"manifest_generator" yaml:"manifest_generator"`
 
+	// Approval makes release wait for approval in Slack before executing work
+	Approval ApprovalConfig `json:"approval" yaml:"approval"`
+
+	// UI links notifications to the state Cascade records for each work item
+	UI UIConfig `json:"ui" yaml:"ui"`
+
+	// PullRequests selects the format of pull requests and commit messages
+	PullRequests PullRequestsConfig `json:"pull_requests" yaml:"pull_requests"`
+
+	// Telemetry exposes Prometheus metrics and OpenTelemetry traces of runs
+	Telemetry TelemetryConfig `json:"telemetry" yaml:"telemetry"`
+
 	// Target module and version for cascade operations
 	// These are typically specified via command-line flags
 	Module  string `json:"module,omitempty" yaml:"module,omitempty"`
@@ -55,6 +67,11 @@ type WorkspaceConfig struct {
 	// ManifestPath is the path to the .cascade.yaml manifest file.
 	// Required for most operations unless specified via command-line flags.
 	ManifestPath string `json:"manifest_path,omitempty" yaml:"manifest_path,omitempty"`
+
+	// Layout places repository clones inside the workspace, using the {{host}},
+	// {{owner}} and {{repo}} placeholders, e.g. "{{host}}/{{owner}}/{{repo}}".
+	// Default: "{{repo}}"
+	Layout string `json:"layout,omitempty" yaml:"layout,omitempty"`
 }
 
 // ExecutorConfig contains executor-specific settings that control
@@ -72,6 +89,13 @@ type ExecutorConfig struct {
 	// Can be overridden by command-line flags.
 	DryRun bool `json:"dry_run" yaml:"dry_run"`
 
+	// DryRunMode selects what a dry run does. "diff" applies each update in a
+	// temporary clone, without committing or pushing it, and prints the
+	// changes it makes. Setting a mode enables DryRun.
+	// Valid values: "", "diff"
+	// Default: empty (only list the work items)
+	DryRunMode string `json:"dry_run_mode,omitempty" yaml:"dry_run_mode,omitempty"`
+
 	// SkipUpToDate controls whether to skip work items for up-to-date dependents.
 	// When enabled, the planner checks if each dependent already has the target
 	// dependency version and skips it if no update is needed.
@@ -103,16 +127,298 @@ type ExecutorConfig struct {
 	// CheckTimeout sets the timeout for individual repository checks.
 	// Default: 30 seconds
 	CheckTimeout time.Duration `json:"check_timeout" yaml:"check_timeout"`
+
+	// HeartbeatInterval controls how often in-flight work items refresh their
+	// liveness record in state.
+	// Default: 30 seconds
+	HeartbeatInterval time.Duration `json:"heartbeat_interval" yaml:"heartbeat_interval"`
+
+	// Retries is how many more times the network-bound steps of a work item
+	// (clone, dependency download, and push) are attempted after a failure.
+	// Default: 0 (no retries)
+	Retries int `json:"retries" yaml:"retries"`
+
+	// RetryDelay is the wait before the first retry; it doubles after each one.
+	// Default: 2 seconds
+	RetryDelay time.Duration `json:"retry_delay" yaml:"retry_delay"`
+
+	// PlanMaxAge is how old a plan saved with plan --output, or a run being
+	// resumed, may get before its work items are re-validated against the
+	// current remote state: whether the base branch still exists, whether the
+	// dependent already requires the target, and whether a newer version of
+	// the target was released. Invalidated items are recorded as skipped
+	// instead of executed.
+	// Default: 0 (plans never expire)
+	PlanMaxAge time.Duration `json:"plan_max_age" yaml:"plan_max_age"`
+
+	// BaseBranch makes every work item of a release start from and open its
+	// pull request against this branch, such as release/1.x, instead of the
+	// branch the manifest resolves for the dependent.
+	// Default: empty (use the manifest)
+	BaseBranch string `json:"base_branch" yaml:"base_branch"`
+
+	// Limits constrains the CPU and memory available to the test and extra
+	// commands of each work item, so one runaway dependent cannot starve the
+	// rest of a parallel run. Zero values leave resources unconstrained.
+	Limits ResourceLimits `json:"limits" yaml:"limits"`
+
+	// MultiLevel continues a release into the dependents of dependents. When a
+	// dependent is itself a manifest module, the release waits for its update
+	// to merge and a new version to be tagged, then releases that version to
+	// the module's own dependents.
+	// Default: false
+	MultiLevel bool `json:"multi_level" yaml:"multi_level"`
+
+	// WaveTimeout bounds how long a multi-level release waits for one module's
+	// updates to merge and its next version to be tagged.
+	// Default: 2 hours
+	WaveTimeout time.Duration `json:"wave_timeout" yaml:"wave_timeout"`
+
+	// WavePollInterval is how often a multi-level release checks merge and tag
+	// status while it waits.
+	// Default: 1 minute
+	WavePollInterval time.Duration `json:"wave_poll_interval" yaml:"wave_poll_interval"`
+
+	// CanaryTimeout bounds how long a release waits for the checks on the pull
+	// requests of its canary dependents before holding back the rest.
+	// Default: 1 hour
+	CanaryTimeout time.Duration `json:"canary_timeout" yaml:"canary_timeout"`
+
+	// CanaryPollInterval is how often a release checks the pull requests of its
+	// canary dependents while it waits.
+	// Default: 30 seconds
+	CanaryPollInterval time.Duration `json:"canary_poll_interval" yaml:"canary_poll_interval"`
+
+	// LocalTarget is a checkout of the target module the tests of canary
+	// dependents build against, through a go.work, instead of the released
+	// version. Their commits and pull requests still require the release.
+	// It is set by the --local-target flag only.
+	LocalTarget string `json:"-" yaml:"-"`
+
+	// Reports lists the formats, comma-separated json and junit, of the run
+	// report written into ReportDir when a release or resume ends. Both are
+	// set by the --report and --report-dir flags only.
+	Reports   string `json:"-" yaml:"-"`
+	ReportDir string `json:"-" yaml:"-"`
+}
+
+// DryRunModeDiff previews the changes of each update during a dry run.
+const DryRunModeDiff = "diff"
+
+// DiffPreview reports whether a dry run should preview each update's changes.
+func (e ExecutorConfig) DiffPreview() bool {
+	return e.DryRun && e.DryRunMode == DryRunModeDiff
+}
+
+// ResourceLimits describes per work item resource constraints.
+type ResourceLimits struct {
+	// CPU is the number of CPUs a work item may use, e.g. 2 or 0.5.
+	CPU float64 `json:"cpu,omitempty" yaml:"cpu,omitempty"`
+
+	// Memory is the memory ceiling as a byte quantity, e.g. "4Gi", "512Mi", or "2G".
+	Memory string `json:"memory,omitempty" yaml:"memory,omitempty"`
+
+	// Backend selects how limits are enforced.
+	// Valid values: "env", "cgroup"
+	// - env: export GOMAXPROCS and GOMEMLIMIT (soft limits honoured by Go programs)
+	// - cgroup: run commands in a transient systemd scope with hard CPU and memory caps
+	// Default: "env"
+	Backend string `json:"backend,omitempty" yaml:"backend,omitempty"`
+}
+
+// IsZero reports whether no resource limit is configured.
+func (l ResourceLimits) IsZero() bool {
+	return l.CPU == 0 && l.Memory == ""
 }
 
 // IntegrationConfig manages settings for external service integrations
 // including GitHub, Slack, and other third-party services.
 type IntegrationConfig struct {
+	// Provider set to "sandbox" records pull requests and notifications as JSON
+	// files in the sandbox directory of the state directory instead of calling
+	// a git host, and skips pushing branches, so runs need no credentials.
+	// Default: empty, which uses the GitHub, GitLab, and Bitbucket settings
+	Provider string `json:"provider" yaml:"provider"`
+
 	// GitHub contains GitHub API integration settings
 	GitHub GitHubConfig `json:"github" yaml:"github"`
 
+	// GitLab contains GitLab API settings for dependents hosted on GitLab
+	GitLab GitLabConfig `json:"gitlab" yaml:"gitlab"`
+
+	// Bitbucket contains Bitbucket Cloud API settings for dependents hosted on Bitbucket
+	Bitbucket BitbucketConfig `json:"bitbucket" yaml:"bitbucket"`
+
 	// Slack contains Slack notification integration settings
 	Slack SlackConfig `json:"slack" yaml:"slack"`
+
+	// Notifications customises notification message templates, escalation, and digests
+	Notifications NotificationTemplatesConfig `json:"notifications" yaml:"notifications"`
+
+	// PagerDuty contains PagerDuty incident integration settings
+	PagerDuty PagerDutyConfig `json:"pagerduty" yaml:"pagerduty"`
+
+	// Git describes git hosts other than github.com and clone URL rewrites
+	Git GitConfig `json:"git" yaml:"git"`
+
+	// GoProxy makes releases wait until a module proxy serves the new version
+	GoProxy GoProxyConfig `json:"goproxy" yaml:"goproxy"`
+
+	// Requests tags every API and notification request so egress proxies can
+	// attribute the traffic
+	Requests RequestTaggingConfig `json:"requests" yaml:"requests"`
+}
+
+// ProviderSandbox is the IntegrationConfig.Provider value that records pull
+// requests locally instead of opening them on a git host.
+const ProviderSandbox = "sandbox"
+
+// GitConfig controls how clone URLs are built for dependents, so repositories on
+// GitHub Enterprise Server, self-hosted GitLab, or SSH-only servers can be cloned.
+type GitConfig struct {
+	// DefaultHost is the host assumed for owner/repo shorthands.
+	// Default: the host of integration.github.endpoint when it is not
+	// api.github.com, otherwise github.com
+	DefaultHost string `json:"default_host,omitempty" yaml:"default_host,omitempty"`
+
+	// Hosts lists per host clone settings.
+	Hosts []GitHostConfig `json:"hosts,omitempty" yaml:"hosts,omitempty"`
+
+	// Rewrites replace URL prefixes after clone URLs are built, like git's
+	// url.<base>.insteadOf.
+	Rewrites []GitURLRewrite `json:"rewrites,omitempty" yaml:"rewrites,omitempty"`
+
+	// SSH selects the key and known_hosts file for SSH clones and pushes.
+	SSH GitSSHConfig `json:"ssh,omitempty" yaml:"ssh,omitempty"`
+}
+
+// GitSSHConfig selects how git authenticates to SSH remotes and checks their
+// host keys. It applies to every git command cascade runs, including those
+// the go command starts, and to remote dependency checks.
+type GitSSHConfig struct {
+	// KeyPath is the private key offered to SSH servers.
+	// Default: empty (ssh-agent or the keys ssh finds on its own)
+	KeyPath string `json:"key_path,omitempty" yaml:"key_path,omitempty"`
+
+	// Agent offers the keys loaded in ssh-agent alongside KeyPath. Without it,
+	// only KeyPath is offered when set.
+	// Default: false
+	Agent bool `json:"agent,omitempty" yaml:"agent,omitempty"`
+
+	// KnownHosts is the known_hosts file host keys are checked against. Hosts
+	// missing from it are rejected instead of prompting.
+	// Default: empty (~/.ssh/known_hosts)
+	KnownHosts string `json:"known_hosts,omitempty" yaml:"known_hosts,omitempty"`
+}
+
+// GitHostConfig describes how repositories on one git host are cloned.
+type GitHostConfig struct {
+	// Name is the host as it appears in repository identifiers and module paths.
+	Name string `json:"name" yaml:"name"`
+
+	// URL is the HTTPS base for clones, which may include a path prefix.
+	// Default: https://<name>
+	URL string `json:"url,omitempty" yaml:"url,omitempty"`
+
+	// Protocol selects clone URLs over "https" or "ssh".
+	// Default: "https"
+	Protocol string `json:"protocol,omitempty" yaml:"protocol,omitempty"`
+
+	// SSHUser is the SSH login.
+	// Default: "git"
+	SSHUser string `json:"ssh_user,omitempty" yaml:"ssh_user,omitempty"`
+
+	// SSHHost is the SSH server when it differs from Name.
+	SSHHost string `json:"ssh_host,omitempty" yaml:"ssh_host,omitempty"`
+
+	// SSHPort is the SSH port; ports other than 22 produce ssh:// URLs.
+	SSHPort int `json:"ssh_port,omitempty" yaml:"ssh_port,omitempty"`
+
+	// Subgroups keeps every path segment after the host (GitLab groups) instead
+	// of only owner/repo.
+	Subgroups bool `json:"subgroups,omitempty" yaml:"subgroups,omitempty"`
+}
+
+// GitURLRewrite replaces the InsteadOf prefix of clone URLs with Base.
+type GitURLRewrite struct {
+	Base      string `json:"base" yaml:"base"`
+	InsteadOf string `json:"instead_of" yaml:"instead_of"`
+}
+
+// NotificationTemplatesConfig selects notification message templates by result status
+// and by channel. Unset entries fall back to built-in defaults that are terse for
+// completed and skipped items and detailed for failures and manual review. It also
+// holds the escalation ladder that picks channels for repeated failures.
+type NotificationTemplatesConfig struct {
+	// Template applies to every channel and status.
+	Template string `json:"template,omitempty" yaml:"template,omitempty"`
+
+	// Templates maps a result status (completed, failed, manual-review, skipped)
+	// to a template and takes precedence over Template.
+	Templates map[string]string `json:"templates,omitempty" yaml:"templates,omitempty"`
+
+	// Channels holds per channel (slack, webhook) templates that take precedence
+	// over Templates and Template.
+	Channels map[string]NotificationChannelTemplates `json:"channels,omitempty" yaml:"channels,omitempty"`
+
+	// Escalation lists the channels that report a dependent's failures by the
+	// attempt count recorded in state. When set, each notification goes only to
+	// the channels of one step.
+	// Default: none (every configured channel receives every notification)
+	Escalation []EscalationStepConfig `json:"escalation,omitempty" yaml:"escalation,omitempty"`
+
+	// Digest batches the notifications of busy channels into one message per
+	// window. Failures are still sent right away.
+	Digest NotificationDigestConfig `json:"digest,omitempty" yaml:"digest,omitempty"`
+}
+
+// NotificationDigestConfig batches per-item notifications into digests.
+type NotificationDigestConfig struct {
+	// Window is how long notifications are held back, counted from the first
+	// one. A notification or `cascade digest flush` after it elapsed sends them.
+	// Default: 0 (every notification is sent right away)
+	Window time.Duration `json:"window,omitempty" yaml:"window,omitempty"`
+
+	// Channels lists the channels that send digests: slack, webhook, sandbox.
+	// Default: every channel that supports digests
+	Channels []string `json:"channels,omitempty" yaml:"channels,omitempty"`
+}
+
+// EscalationStepConfig is one step of the failure escalation ladder.
+type EscalationStepConfig struct {
+	// Attempt is the first attempt the step applies to, counting from 1. The
+	// step applies until a step with a later attempt takes over.
+	Attempt int `json:"attempt" yaml:"attempt"`
+
+	// Channels lists the notifiers to use: slack, webhook, github_issues, pagerduty.
+	Channels []string `json:"channels" yaml:"channels"`
+
+	// Assignees are GitHub logins added to the failure issue, such as a team lead.
+	Assignees []string `json:"assignees,omitempty" yaml:"assignees,omitempty"`
+}
+
+// PagerDutyConfig contains PagerDuty Events API v2 settings.
+type PagerDutyConfig struct {
+	// RoutingKey is the integration key of the PagerDuty service.
+	// Should be loaded from environment variables or secure files.
+	RoutingKey string `json:"routing_key,omitempty" yaml:"routing_key,omitempty"`
+
+	// Endpoint is the Events API URL.
+	// Default: https://events.pagerduty.com/v2/enqueue
+	Endpoint string `json:"endpoint,omitempty" yaml:"endpoint,omitempty"`
+
+	// Severity of triggered incidents: critical, error, warning, or info.
+	// Default: error
+	Severity string `json:"severity,omitempty" yaml:"severity,omitempty"`
+}
+
+// NotificationChannelTemplates holds the templates for one notification channel.
+type NotificationChannelTemplates struct {
+	// Template applies to every status on the channel.
+	Template string `json:"template,omitempty" yaml:"template,omitempty"`
+
+	// Templates maps a result status to a template for the channel.
+	Templates map[string]string `json:"templates,omitempty" yaml:"templates,omitempty"`
 }
 
 // GitHubConfig contains GitHub API integration settings including
@@ -128,6 +434,135 @@ type GitHubConfig struct {
 
 	// Organization is the default GitHub organization for operations.
 	Organization string `json:"organization,omitempty" yaml:"organization,omitempty"`
+
+	// WebhookSecret is the secret cascade serve verifies webhook deliveries with.
+	// Should be loaded from environment variables or secure files.
+	WebhookSecret string `json:"webhook_secret,omitempty" yaml:"webhook_secret,omitempty"`
+
+	// Labels controls how PR labels missing from dependent repositories are handled.
+	Labels GitHubLabelsConfig `json:"labels" yaml:"labels"`
+
+	// RateLimitAlerts lists percentages of the GitHub API quota (1-100). A warning
+	// notification is sent the first time a run's cumulative usage crosses each one.
+	// Default: none (alerts disabled)
+	RateLimitAlerts []int `json:"rate_limit_alerts,omitempty" yaml:"rate_limit_alerts,omitempty"`
+
+	// RateLimit controls how API requests rejected by a rate limit are retried.
+	RateLimit GitHubRateLimitConfig `json:"rate_limit" yaml:"rate_limit"`
+}
+
+// GitLabConfig contains GitLab API integration settings. Merge requests are
+// opened on GitLab for dependents on the GitLab host.
+type GitLabConfig struct {
+	// Token is a GitLab access token with the api scope.
+	// Should be loaded from environment variables or secure files.
+	Token string `json:"token,omitempty" yaml:"token,omitempty"`
+
+	// Endpoint is the GitLab API endpoint URL. Its host selects which
+	// dependents are handled by GitLab.
+	// Default: https://gitlab.com/api/v4
+	Endpoint string `json:"endpoint,omitempty" yaml:"endpoint,omitempty"`
+}
+
+// BitbucketConfig contains Bitbucket Cloud API integration settings. Pull
+// requests are opened on Bitbucket for dependents on bitbucket.org.
+type BitbucketConfig struct {
+	// Username is the Bitbucket user an app password belongs to. Leave it empty
+	// when Token is a repository, project, or workspace access token.
+	Username string `json:"username,omitempty" yaml:"username,omitempty"`
+
+	// Token is an app password or access token with pull request write access.
+	// Should be loaded from environment variables or secure files.
+	Token string `json:"token,omitempty" yaml:"token,omitempty"`
+
+	// Endpoint is the Bitbucket API endpoint URL.
+	// Default: https://api.bitbucket.org/2.0
+	Endpoint string `json:"endpoint,omitempty" yaml:"endpoint,omitempty"`
+}
+
+// GoProxyConfig points at a private Go module proxy, such as Athens or
+// Artifactory. When URL is set, release asks the proxy for the released version
+// and waits until it is served before updating dependents, so their go get does
+// not fail while the proxy has yet to index the tag.
+type GoProxyConfig struct {
+	// URL is the proxy base URL, as it would appear in GOPROXY.
+	// Default: empty (no wait)
+	URL string `json:"url,omitempty" yaml:"url,omitempty"`
+
+	// Username is sent with Token as basic auth. Without it, Token is sent as
+	// a bearer token.
+	Username string `json:"username,omitempty" yaml:"username,omitempty"`
+
+	// Token authenticates requests to the proxy.
+	// Should be loaded from environment variables or secure files.
+	Token string `json:"token,omitempty" yaml:"token,omitempty"`
+
+	// WaitTimeout is how long release waits for the version before failing.
+	// Default: 10 minutes
+	WaitTimeout time.Duration `json:"wait_timeout" yaml:"wait_timeout"`
+
+	// PollInterval is how often the proxy is asked for the version.
+	// Default: 10 seconds
+	PollInterval time.Duration `json:"poll_interval" yaml:"poll_interval"`
+
+	// Private lists module path patterns, in GOPRIVATE syntax, of modules that
+	// must never be requested from a proxy or the checksum database. They are
+	// added to GOPRIVATE (and GONOPROXY and GONOSUMDB when set) for every go
+	// command cascade runs, and version checks fetch them from their origin.
+	// Default: empty (GOPRIVATE from the environment only)
+	Private []string `json:"private,omitempty" yaml:"private,omitempty"`
+}
+
+// RequestTaggingConfig identifies cascade's outbound requests to GitHub,
+// GitLab, Bitbucket, Slack, and webhooks, for egress proxies that attribute
+// traffic per tool or team.
+type RequestTaggingConfig struct {
+	// UserAgent is a product token, such as "platform-bot/1.0", placed before
+	// cascade's own User-Agent.
+	// Default: empty (cascade's User-Agent only)
+	UserAgent string `json:"user_agent,omitempty" yaml:"user_agent,omitempty"`
+
+	// Org is the organization requests are attributed to. It is added to the
+	// User-Agent and sent in the X-Cascade-Org header.
+	// Default: integration.github.organization
+	Org string `json:"org,omitempty" yaml:"org,omitempty"`
+
+	// Headers are sent with every request, replacing any value the client
+	// library sets. Authorization, Content-Type, and other headers that
+	// requests depend on cannot be set.
+	Headers map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+}
+
+// GitHubLabelsConfig controls creation of PR labels that do not exist in the
+// dependent repository.
+type GitHubLabelsConfig struct {
+	// AutoCreate creates missing labels before applying them to pull requests.
+	// Default: false
+	AutoCreate bool `json:"auto_create" yaml:"auto_create"`
+
+	// Color is the default hex color (e.g. "0e8a16") for created labels.
+	// Default: "ededed"
+	Color string `json:"color,omitempty" yaml:"color,omitempty"`
+
+	// Description is the default description for created labels.
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+
+	// Colors maps specific label names to hex colors, overriding Color.
+	Colors map[string]string `json:"colors,omitempty" yaml:"colors,omitempty"`
+}
+
+// GitHubRateLimitConfig controls how GitHub API requests rejected by a primary
+// or secondary rate limit are retried once the limit resets.
+type GitHubRateLimitConfig struct {
+	// MaxWait is the longest a request waits for a rate limit to reset. Requests
+	// limited for longer fail with a rate limit error.
+	// Default: 5m
+	MaxWait time.Duration `json:"max_wait,omitempty" yaml:"max_wait,omitempty"`
+
+	// MaxRetries is how many times a rate limited request is retried. A negative
+	// value disables retries.
+	// Default: 3
+	MaxRetries int `json:"max_retries,omitempty" yaml:"max_retries,omitempty"`
 }
 
 // SlackConfig contains Slack integration settings for notifications
@@ -141,6 +576,142 @@ type SlackConfig struct {
 
 	// Channel is the default Slack channel for notifications.
 	Channel string `json:"channel,omitempty" yaml:"channel,omitempty"`
+
+	// SigningSecret is the Slack app signing secret that interactivity requests,
+	// such as approval button clicks, are verified with.
+	// Should be loaded from environment variables or secure files.
+	SigningSecret string `json:"signing_secret,omitempty" yaml:"signing_secret,omitempty"`
+}
+
+// ApprovalConfig makes release post approve and reject buttons to Slack and wait
+// for an answer before executing the plan or each work item. Slack delivers the
+// clicks to a callback listener that release runs while it waits; the Slack
+// app's interactivity Request URL must reach it.
+type ApprovalConfig struct {
+	// Mode selects what needs approval.
+	// Valid values: "plan", "item"
+	// - plan: approve the whole plan once before any work item runs
+	// - item: approve each work item before it runs; rejected items are skipped
+	// Default: empty (no approval)
+	Mode string `json:"mode,omitempty" yaml:"mode,omitempty"`
+
+	// Channel is the Slack channel approval requests are posted to.
+	// Default: integration.slack.channel
+	Channel string `json:"channel,omitempty" yaml:"channel,omitempty"`
+
+	// Timeout is how long to wait for an answer. No answer counts as a rejection.
+	// Default: 1 hour
+	Timeout time.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+
+	// Listen is the address the callback listener binds to. It serves Slack
+	// interactivity requests at /slack/interactions.
+	// Default: ":8086"
+	Listen string `json:"listen,omitempty" yaml:"listen,omitempty"`
+
+	// Approvers are the Slack user IDs or usernames allowed to answer approval
+	// requests. Clicks from anyone else leave the request pending.
+	// Default: empty (anyone who can see the request)
+	Approvers []string `json:"approvers,omitempty" yaml:"approvers,omitempty"`
+}
+
+// UIConfig configures where the state Cascade records can be browsed.
+type UIConfig struct {
+	// BaseURL is the address the state directory is served from, such as a
+	// dashboard or an artifact store the directory is synced to. When set,
+	// notifications link to the run summary and to the state file of the work
+	// item, which holds its command logs. The URL mirrors the directory layout:
+	// <base_url>/<module>/<version>/items/<item>.json.
+	// Default: empty (no links)
+	BaseURL string `json:"base_url,omitempty" yaml:"base_url,omitempty"`
+}
+
+// PullRequestsConfig selects the format of the pull requests and commit
+// messages Cascade writes.
+type PullRequestsConfig struct {
+	// Format is "cascade" for Cascade's own PR bodies and commit messages, or
+	// "dependabot" for a semantic PR title and commit subject, such as
+	// "chore(deps): bump github.com/acme/lib from v1.2.0 to v1.3.0", followed by
+	// Dependabot's updated-dependencies metadata block, so tooling that parses
+	// Dependabot and Renovate pull requests handles Cascade's the same way.
+	// PR templates still take precedence over the format's defaults.
+	// Default: "cascade"
+	Format string `json:"format,omitempty" yaml:"format,omitempty"`
+
+	// CommitPrefix is the semantic prefix of "dependabot" titles and commit
+	// subjects.
+	// Default: "chore(deps)"
+	CommitPrefix string `json:"commit_prefix,omitempty" yaml:"commit_prefix,omitempty"`
+}
+
+// TelemetryConfig exposes metrics and traces of runs, so long cascades can be
+// observed from CI.
+type TelemetryConfig struct {
+	// MetricsListen is the address Prometheus metrics are served on, at
+	// /metrics, while a command runs, such as ":9464".
+	// Default: empty (not served)
+	MetricsListen string `json:"metrics_listen,omitempty" yaml:"metrics_listen,omitempty"`
+
+	// Pushgateway pushes metrics to a Prometheus Pushgateway, for runs that
+	// end before Prometheus can scrape them.
+	Pushgateway PushgatewayConfig `json:"pushgateway" yaml:"pushgateway"`
+
+	// Tracing exports OpenTelemetry spans of planning, execution, broker calls,
+	// and discovery.
+	Tracing TracingConfig `json:"tracing" yaml:"tracing"`
+}
+
+// PushgatewayConfig configures pushes to a Prometheus Pushgateway.
+type PushgatewayConfig struct {
+	// URL is the address of the Pushgateway, such as
+	// http://pushgateway:9091. Metrics are pushed while the command runs and
+	// once more when it exits.
+	// Default: empty (no pushes)
+	URL string `json:"url,omitempty" yaml:"url,omitempty"`
+
+	// Job is the job label metrics are grouped under.
+	// Default: "cascade"
+	Job string `json:"job,omitempty" yaml:"job,omitempty"`
+
+	// Interval is how often metrics are pushed while the command runs.
+	// Default: 30 seconds
+	Interval time.Duration `json:"interval,omitempty" yaml:"interval,omitempty"`
+}
+
+// TracingConfig configures the export of OpenTelemetry spans.
+type TracingConfig struct {
+	// Endpoint is the OTLP/HTTP endpoint of a collector, such as
+	// http://otel-collector:4318. Spans are posted to <endpoint>/v1/traces
+	// unless the endpoint has a path of its own.
+	// Default: empty (spans are not exported)
+	Endpoint string `json:"endpoint,omitempty" yaml:"endpoint,omitempty"`
+
+	// Headers are sent with every export, typically to authenticate with a
+	// hosted backend.
+	Headers map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+
+	// ServiceName is the service.name resource attribute of the spans.
+	// Default: "cascade"
+	ServiceName string `json:"service_name,omitempty" yaml:"service_name,omitempty"`
+}
+
+// Pull request formats.
+const (
+	PRFormatCascade    = "cascade"
+	PRFormatDependabot = "dependabot"
+)
+
+// Approval modes.
+const (
+	ApprovalModePlan = "plan"
+	ApprovalModeItem = "item"
+)
+
+// ApprovalChannel returns the Slack channel approval requests are posted to.
+func (a ApprovalConfig) ApprovalChannel(slack SlackConfig) string {
+	if a.Channel != "" {
+		return a.Channel
+	}
+	return slack.Channel
 }
 
 // LoggingConfig manages logging level, output format, and
@@ -179,8 +750,35 @@ type StateConfig struct {
 	// Enabled controls whether state persistence is active.
 	// Default: true
 	Enabled bool `json:"enabled" yaml:"enabled"`
+
+	// Backend selects where state is persisted: "filesystem", "s3" for S3 and
+	// S3-compatible services, or "gcs" for Google Cloud Storage. Remote
+	// backends let ephemeral CI runners share state.
+	// Default: "filesystem"
+	Backend string `json:"backend,omitempty" yaml:"backend,omitempty"`
+
+	// Bucket is the bucket of the s3 and gcs backends.
+	Bucket string `json:"bucket,omitempty" yaml:"bucket,omitempty"`
+
+	// Prefix is the key prefix state objects are stored under in Bucket.
+	Prefix string `json:"prefix,omitempty" yaml:"prefix,omitempty"`
+
+	// Endpoint overrides the object storage API URL, for S3-compatible
+	// services such as MinIO or R2 and for storage emulators.
+	Endpoint string `json:"endpoint,omitempty" yaml:"endpoint,omitempty"`
+
+	// Region is the region of an s3 bucket.
+	// Default: $AWS_REGION, or us-east-1
+	Region string `json:"region,omitempty" yaml:"region,omitempty"`
 }
 
+// State backends.
+const (
+	StateBackendFilesystem = "filesystem"
+	StateBackendS3         = "s3"
+	StateBackendGCS        = "gcs"
+)
+
 // ManifestGeneratorConfig contains default settings for manifest generation
 // operations to reduce the need for command-line flags.
 type ManifestGeneratorConfig struct {
@@ -218,6 +816,11 @@ type TestsConfig struct {
 	// WorkingDirectory is the default working directory for test execution.
 	// If empty, uses the module root directory.
 	WorkingDirectory string `json:"working_directory,omitempty" yaml:"working_directory,omitempty"`
+
+	// DisableDetection turns off proposing per-dependent test commands (task test,
+	// make test, mage test) from each discovered dependent's build tooling.
+	// Default: false
+	DisableDetection bool `json:"disable_detection,omitempty" yaml:"disable_detection,omitempty"`
 }
 
 // NotificationsConfig contains default notification settings.
@@ -296,38 +899,147 @@ type GitHubDiscoveryConfig struct {
 	// Enabled controls whether GitHub discovery is enabled by default.
 	// Default: false (only when explicitly requested via --github-org flag)
 	Enabled bool `json:"enabled" yaml:"enabled"`
+
+	// Filter narrows discovery by repository attributes using GitHub search
+	// qualifiers, e.g. "topic:go-service pushed:>2024-01-01 archived:false".
+	Filter string `json:"filter,omitempty" yaml:"filter,omitempty"`
+
+	// FullScan lists every repository of the organization and reads its go.mod
+	// instead of using code search, which returns at most 1000 results.
+	FullScan bool `json:"full_scan,omitempty" yaml:"full_scan,omitempty"`
+
+	// GraphQL lists every repository of the organization through the GitHub
+	// GraphQL API, reading the go.mod of 100 repositories per query. Discovery
+	// falls back to a REST full scan when the server has no GraphQL API.
+	GraphQL bool `json:"graphql,omitempty" yaml:"graphql,omitempty"`
+
+	// ScanCheckpoint is the file a full scan saves its position to, so a scan
+	// interrupted by a rate limit or cancellation continues where it stopped.
+	// Default: github-scan/<organization>.json in the state directory.
+	ScanCheckpoint string `json:"scan_checkpoint,omitempty" yaml:"scan_checkpoint,omitempty"`
+
+	// CacheDir is where discovery keeps GitHub API responses between runs, one
+	// file per organization and module, and revalidates them with conditional
+	// requests that do not count against the rate limit.
+	// Default: discovery-cache in the state directory.
+	CacheDir string `json:"cache_dir,omitempty" yaml:"cache_dir,omitempty"`
+
+	// RefreshCache fetches every response again instead of revalidating the
+	// cached ones. It is set by the --refresh-discovery flag only.
+	RefreshCache bool `json:"-" yaml:"-"`
 }
 
 // Environment variable mapping constants for configuration parsing
 const (
+	// Config file environment variables
+	EnvProfile = "CASCADE_PROFILE"
+
 	// Workspace environment variables
-	EnvWorkspacePath = "CASCADE_WORKSPACE"
-	EnvTempDir       = "CASCADE_TEMP_DIR"
-	EnvManifestPath  = "CASCADE_MANIFEST"
+	EnvWorkspacePath   = "CASCADE_WORKSPACE"
+	EnvTempDir         = "CASCADE_TEMP_DIR"
+	EnvManifestPath    = "CASCADE_MANIFEST"
+	EnvWorkspaceLayout = "CASCADE_WORKSPACE_LAYOUT"
 
 	// Executor environment variables
 	EnvTimeout         = "CASCADE_TIMEOUT"
 	EnvConcurrentLimit = "CASCADE_CONCURRENT_LIMIT"
+	EnvRetries         = "CASCADE_RETRIES"
+	EnvRetryDelay      = "CASCADE_RETRY_DELAY"
+	EnvPlanMaxAge      = "CASCADE_PLAN_MAX_AGE"
+	EnvBaseBranch      = "CASCADE_BASE_BRANCH"
 	EnvDryRun          = "CASCADE_DRY_RUN"
 	EnvSkipUpToDate    = "CASCADE_SKIP_UP_TO_DATE"
 	EnvForceAll        = "CASCADE_FORCE_ALL"
 
+	// Multi-level release environment variables
+	EnvMultiLevel       = "CASCADE_MULTI_LEVEL"
+	EnvWaveTimeout      = "CASCADE_WAVE_TIMEOUT"
+	EnvWavePollInterval = "CASCADE_WAVE_POLL_INTERVAL"
+
+	// Canary rollout environment variables
+	EnvCanaryTimeout      = "CASCADE_CANARY_TIMEOUT"
+	EnvCanaryPollInterval = "CASCADE_CANARY_POLL_INTERVAL"
+
 	// Dependency checking environment variables
 	EnvCheckStrategy = "CASCADE_CHECK_STRATEGY"
 	EnvCheckCacheTTL = "CASCADE_CHECK_CACHE_TTL"
 	EnvCheckParallel = "CASCADE_CHECK_PARALLEL"
 	EnvCheckTimeout  = "CASCADE_CHECK_TIMEOUT"
 
+	// Provider environment variables
+	EnvProvider = "CASCADE_PROVIDER"
+
 	// GitHub integration environment variables
-	EnvGitHubToken    = "CASCADE_GITHUB_TOKEN"
-	EnvGitHubEndpoint = "CASCADE_GITHUB_ENDPOINT"
-	EnvGitHubOrg      = "CASCADE_GITHUB_ORG"
+	EnvGitHubToken         = "CASCADE_GITHUB_TOKEN"
+	EnvGitHubEndpoint      = "CASCADE_GITHUB_ENDPOINT"
+	EnvGitHubOrg           = "CASCADE_GITHUB_ORG"
+	EnvGitHubWebhookSecret = "CASCADE_GITHUB_WEBHOOK_SECRET"
+	EnvGitHubRateLimitWait = "CASCADE_GITHUB_RATE_LIMIT_MAX_WAIT"
+
+	// GitLab integration environment variables
+	EnvGitLabToken    = "CASCADE_GITLAB_TOKEN"
+	EnvGitLabEndpoint = "CASCADE_GITLAB_ENDPOINT"
+
+	// Bitbucket integration environment variables
+	EnvBitbucketUsername = "CASCADE_BITBUCKET_USERNAME"
+	EnvBitbucketToken    = "CASCADE_BITBUCKET_TOKEN"
+	EnvBitbucketEndpoint = "CASCADE_BITBUCKET_ENDPOINT"
+
+	// Go module proxy environment variables
+	EnvGoProxyURL      = "CASCADE_GOPROXY_URL"
+	EnvGoProxyUsername = "CASCADE_GOPROXY_USERNAME"
+	EnvGoProxyToken    = "CASCADE_GOPROXY_TOKEN"
+	EnvGoPrivate       = "CASCADE_GOPRIVATE"
+
+	// SSH environment variables
+	EnvSSHKeyPath    = "CASCADE_SSH_KEY_PATH"
+	EnvSSHAgent      = "CASCADE_SSH_AGENT"
+	EnvSSHKnownHosts = "CASCADE_SSH_KNOWN_HOSTS"
+
+	// Request tagging environment variables
+	EnvUserAgent      = "CASCADE_USER_AGENT"
+	EnvRequestOrg     = "CASCADE_REQUEST_ORG"
+	EnvRequestHeaders = "CASCADE_REQUEST_HEADERS"
 
 	// Slack integration environment variables
 	EnvSlackToken   = "CASCADE_SLACK_TOKEN"
 	EnvSlackWebhook = "CASCADE_SLACK_WEBHOOK"
 	EnvSlackChannel = "CASCADE_SLACK_CHANNEL"
 
+	// EnvSlackSigningSecret sets the Slack app signing secret
+	EnvSlackSigningSecret = "CASCADE_SLACK_SIGNING_SECRET"
+
+	// Approval gate environment variables
+	EnvApprovalMode      = "CASCADE_APPROVAL_MODE"
+	EnvApprovalChannel   = "CASCADE_APPROVAL_CHANNEL"
+	EnvApprovalTimeout   = "CASCADE_APPROVAL_TIMEOUT"
+	EnvApprovalListen    = "CASCADE_APPROVAL_LISTEN"
+	EnvApprovalApprovers = "CASCADE_APPROVAL_APPROVERS"
+
+	// EnvUIBaseURL sets the address notifications link state and logs to
+	EnvUIBaseURL = "CASCADE_UI_BASE_URL"
+
+	// Pull request format environment variables
+	EnvPRFormat       = "CASCADE_PR_FORMAT"
+	EnvPRCommitPrefix = "CASCADE_PR_COMMIT_PREFIX"
+
+	// Telemetry environment variables
+	EnvMetricsListen      = "CASCADE_METRICS_LISTEN"
+	EnvPushgatewayURL     = "CASCADE_PUSHGATEWAY_URL"
+	EnvPushgatewayJob     = "CASCADE_PUSHGATEWAY_JOB"
+	EnvTracingEndpoint    = "CASCADE_TRACING_ENDPOINT"
+	EnvTracingServiceName = "CASCADE_TRACING_SERVICE_NAME"
+
+	// Standard OpenTelemetry exporter variables, used when the CASCADE_
+	// variables above are not set
+	EnvOTLPEndpoint       = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	EnvOTLPTracesEndpoint = "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"
+	EnvOTLPHeaders        = "OTEL_EXPORTER_OTLP_HEADERS"
+	EnvOTELServiceName    = "OTEL_SERVICE_NAME"
+
+	// PagerDuty integration environment variables
+	EnvPagerDutyRoutingKey = "CASCADE_PAGERDUTY_ROUTING_KEY"
+
 	// Logging environment variables
 	EnvLogLevel  = "CASCADE_LOG_LEVEL"
 	EnvLogFormat = "CASCADE_LOG_FORMAT"
@@ -338,6 +1050,11 @@ const (
 	EnvStateDir       = "CASCADE_STATE_DIR"
 	EnvStateRetention = "CASCADE_STATE_RETENTION"
 	EnvStateEnabled   = "CASCADE_STATE_ENABLED"
+	EnvStateBackend   = "CASCADE_STATE_BACKEND"
+	EnvStateBucket    = "CASCADE_STATE_BUCKET"
+	EnvStatePrefix    = "CASCADE_STATE_PREFIX"
+	EnvStateEndpoint  = "CASCADE_STATE_ENDPOINT"
+	EnvStateRegion    = "CASCADE_STATE_REGION"
 
 	// Manifest Generator environment variables
 	EnvManifestGeneratorWorkspace            = "CASCADE_MANIFEST_GENERATOR_WORKSPACE"
@@ -130,13 +130,21 @@ func TestEnvironmentConstants(t *testing.T) {
 		{"manifest path", config.EnvManifestPath, "CASCADE_MANIFEST"},
 		{"timeout", config.EnvTimeout, "CASCADE_TIMEOUT"},
 		{"concurrent limit", config.EnvConcurrentLimit, "CASCADE_CONCURRENT_LIMIT"},
+		{"retries", config.EnvRetries, "CASCADE_RETRIES"},
+		{"retry delay", config.EnvRetryDelay, "CASCADE_RETRY_DELAY"},
 		{"dry run", config.EnvDryRun, "CASCADE_DRY_RUN"},
+		{"multi level", config.EnvMultiLevel, "CASCADE_MULTI_LEVEL"},
+		{"wave timeout", config.EnvWaveTimeout, "CASCADE_WAVE_TIMEOUT"},
+		{"wave poll interval", config.EnvWavePollInterval, "CASCADE_WAVE_POLL_INTERVAL"},
+		{"canary timeout", config.EnvCanaryTimeout, "CASCADE_CANARY_TIMEOUT"},
+		{"canary poll interval", config.EnvCanaryPollInterval, "CASCADE_CANARY_POLL_INTERVAL"},
 		{"github token", config.EnvGitHubToken, "CASCADE_GITHUB_TOKEN"},
 		{"github endpoint", config.EnvGitHubEndpoint, "CASCADE_GITHUB_ENDPOINT"},
 		{"github org", config.EnvGitHubOrg, "CASCADE_GITHUB_ORG"},
 		{"slack token", config.EnvSlackToken, "CASCADE_SLACK_TOKEN"},
 		{"slack webhook", config.EnvSlackWebhook, "CASCADE_SLACK_WEBHOOK"},
 		{"slack channel", config.EnvSlackChannel, "CASCADE_SLACK_CHANNEL"},
+		{"pagerduty routing key", config.EnvPagerDutyRoutingKey, "CASCADE_PAGERDUTY_ROUTING_KEY"},
 		{"log level", config.EnvLogLevel, "CASCADE_LOG_LEVEL"},
 		{"log format", config.EnvLogFormat, "CASCADE_LOG_FORMAT"},
 		{"verbose", config.EnvVerbose, "CASCADE_VERBOSE"},
@@ -2,12 +2,18 @@ package config
 
 import (
 	"fmt"
+	"net"
+	"net/http"
 	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"runtime"
 	"strings"
 	"time"
+
+	"github.com/goliatone/cascade/pkg/gitutil"
+	"github.com/goliatone/cascade/pkg/workspace/layout"
 )
 
 // ValidationError represents a configuration validation failure.
@@ -67,6 +73,18 @@ func Validate(cfg *Config) error {
 	// Validate state configuration
 	errors = append(errors, validateState(&cfg.State)...)
 
+	// Validate approval configuration
+	errors = append(errors, validateApproval(&cfg.Approval, &cfg.Integration.Slack)...)
+
+	// Validate UI configuration
+	errors = append(errors, validateUI(&cfg.UI)...)
+
+	// Validate pull request format configuration
+	errors = append(errors, validatePullRequests(&cfg.PullRequests)...)
+
+	// Validate telemetry configuration
+	errors = append(errors, validateTelemetry(&cfg.Telemetry)...)
+
 	if len(errors) > 0 {
 		return errors
 	}
@@ -100,6 +118,12 @@ func ApplyDefaults(cfg *Config) error {
 	// Apply state defaults
 	applyStateDefaults(cfg)
 
+	// Apply approval defaults
+	applyApprovalDefaults(&cfg.Approval)
+
+	// Apply telemetry defaults
+	applyTelemetryDefaults(&cfg.Telemetry)
+
 	return nil
 }
 
@@ -133,6 +157,16 @@ func validateWorkspace(ws *WorkspaceConfig) []ValidationError {
 		}
 	}
 
+	if ws.Layout != "" {
+		if _, err := layout.Parse(ws.Layout); err != nil {
+			errors = append(errors, ValidationError{
+				Field:   "workspace.layout",
+				Value:   ws.Layout,
+				Message: err.Error(),
+			})
+		}
+	}
+
 	// ManifestPath validation (if provided)
 	if ws.ManifestPath != "" {
 		if !filepath.IsAbs(ws.ManifestPath) {
@@ -196,6 +230,49 @@ func validateExecutor(exec *ExecutorConfig) []ValidationError {
 		})
 	}
 
+	// Resource limit validation
+	if exec.Limits.CPU < 0 {
+		errors = append(errors, ValidationError{
+			Field:   "executor.limits.cpu",
+			Value:   exec.Limits.CPU,
+			Message: "cpu limit must be positive",
+		})
+	}
+	if _, err := exec.Limits.MemoryBytes(); err != nil {
+		errors = append(errors, ValidationError{
+			Field:   "executor.limits.memory",
+			Value:   exec.Limits.Memory,
+			Message: err.Error(),
+		})
+	}
+	if backend := exec.Limits.Backend; backend != "" && backend != LimitBackendEnv && backend != LimitBackendCgroup {
+		errors = append(errors, ValidationError{
+			Field:   "executor.limits.backend",
+			Value:   backend,
+			Message: fmt.Sprintf("limits backend must be one of: %s, %s", LimitBackendEnv, LimitBackendCgroup),
+		})
+	}
+
+	// Dry run mode validation
+	if exec.DryRunMode != "" && exec.DryRunMode != DryRunModeDiff {
+		errors = append(errors, ValidationError{
+			Field:   "executor.dry_run_mode",
+			Value:   exec.DryRunMode,
+			Message: fmt.Sprintf("dry run mode must be %s or empty", DryRunModeDiff),
+		})
+	}
+
+	// Base branch validation
+	if exec.BaseBranch != "" {
+		if err := gitutil.ValidateBranchName(exec.BaseBranch); err != nil {
+			errors = append(errors, ValidationError{
+				Field:   "executor.base_branch",
+				Value:   exec.BaseBranch,
+				Message: err.Error(),
+			})
+		}
+	}
+
 	return errors
 }
 
@@ -203,12 +280,41 @@ func validateExecutor(exec *ExecutorConfig) []ValidationError {
 func validateIntegration(integ *IntegrationConfig) []ValidationError {
 	var errors []ValidationError
 
+	if integ.Provider != "" && integ.Provider != ProviderSandbox {
+		errors = append(errors, ValidationError{
+			Field:   "integration.provider",
+			Value:   integ.Provider,
+			Message: fmt.Sprintf("unknown provider, must be %s or empty", ProviderSandbox),
+		})
+	}
+
 	// Validate GitHub configuration
 	errors = append(errors, validateGitHub(&integ.GitHub)...)
 
+	// Validate GitLab configuration
+	errors = append(errors, validateGitLab(&integ.GitLab)...)
+
+	// Validate Bitbucket configuration
+	errors = append(errors, validateBitbucket(&integ.Bitbucket)...)
+
+	// Validate Go module proxy configuration
+	errors = append(errors, validateGoProxy(&integ.GoProxy)...)
+
+	// Validate request tagging
+	errors = append(errors, validateRequestTagging(&integ.Requests)...)
+
 	// Validate Slack configuration
 	errors = append(errors, validateSlack(&integ.Slack)...)
 
+	// Validate notification templates and escalation
+	errors = append(errors, validateNotifications(&integ.Notifications)...)
+
+	// Validate PagerDuty configuration
+	errors = append(errors, validatePagerDuty(&integ.PagerDuty)...)
+
+	// Validate git hosts and rewrites
+	errors = append(errors, validateGit(&integ.Git)...)
+
 	return errors
 }
 
@@ -222,7 +328,7 @@ func validateGitHub(gh *GitHubConfig) []ValidationError {
 		if !isValidGitHubToken(gh.Token) {
 			errors = append(errors, ValidationError{
 				Field:   "integration.github.token",
-				Value:   "[REDACTED]",
+				Value:   RedactedValue,
 				Message: "invalid GitHub token format",
 			})
 		}
@@ -239,9 +345,267 @@ func validateGitHub(gh *GitHubConfig) []ValidationError {
 		}
 	}
 
+	// Rate limit alert thresholds are percentages of the quota
+	for i, threshold := range gh.RateLimitAlerts {
+		if threshold < 1 || threshold > 100 {
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("integration.github.rate_limit_alerts[%d]", i),
+				Value:   threshold,
+				Message: "rate limit alert threshold must be between 1 and 100",
+			})
+		}
+	}
+
+	if gh.RateLimit.MaxWait < 0 {
+		errors = append(errors, ValidationError{
+			Field:   "integration.github.rate_limit.max_wait",
+			Value:   gh.RateLimit.MaxWait,
+			Message: "rate limit max wait must not be negative",
+		})
+	}
+
+	return errors
+}
+
+// validateGit validates git host settings and clone URL rewrites.
+func validateGit(git *GitConfig) []ValidationError {
+	var errors []ValidationError
+
+	if strings.ContainsAny(git.DefaultHost, "/:@ ") {
+		errors = append(errors, ValidationError{
+			Field:   "integration.git.default_host",
+			Value:   git.DefaultHost,
+			Message: "default host must be a bare host name such as github.example.com",
+		})
+	}
+
+	for i, host := range git.Hosts {
+		field := fmt.Sprintf("integration.git.hosts[%d]", i)
+		if strings.TrimSpace(host.Name) == "" || strings.ContainsAny(host.Name, "/:@ ") {
+			errors = append(errors, ValidationError{
+				Field:   field + ".name",
+				Value:   host.Name,
+				Message: "host name is required and must be a bare host name",
+			})
+		}
+		if host.URL != "" {
+			if parsed, err := url.Parse(host.URL); err != nil || parsed.Scheme == "" || parsed.Host == "" {
+				errors = append(errors, ValidationError{
+					Field:   field + ".url",
+					Value:   host.URL,
+					Message: "url must be an absolute URL such as https://git.example.com",
+				})
+			}
+		}
+		switch host.Protocol {
+		case "", "https", "ssh":
+		default:
+			errors = append(errors, ValidationError{
+				Field:   field + ".protocol",
+				Value:   host.Protocol,
+				Message: "protocol must be https or ssh",
+			})
+		}
+		if host.SSHPort < 0 || host.SSHPort > 65535 {
+			errors = append(errors, ValidationError{
+				Field:   field + ".ssh_port",
+				Value:   host.SSHPort,
+				Message: "ssh port must be between 1 and 65535",
+			})
+		}
+	}
+
+	for i, rewrite := range git.Rewrites {
+		if rewrite.Base == "" || rewrite.InsteadOf == "" {
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("integration.git.rewrites[%d]", i),
+				Value:   rewrite,
+				Message: "rewrites need both base and instead_of",
+			})
+		}
+	}
+
+	for _, file := range []struct{ field, path string }{
+		{"key_path", git.SSH.KeyPath},
+		{"known_hosts", git.SSH.KnownHosts},
+	} {
+		if file.path != "" && !filepath.IsAbs(file.path) {
+			errors = append(errors, ValidationError{
+				Field:   "integration.git.ssh." + file.field,
+				Value:   file.path,
+				Message: "path must be absolute",
+			})
+		}
+	}
+
+	return errors
+}
+
+// validateGitLab validates GitLab integration settings.
+func validateGitLab(gl *GitLabConfig) []ValidationError {
+	var errors []ValidationError
+
+	if gl.Endpoint != "" {
+		if parsed, err := url.Parse(gl.Endpoint); err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+			errors = append(errors, ValidationError{
+				Field:   "integration.gitlab.endpoint",
+				Value:   gl.Endpoint,
+				Message: "GitLab endpoint must be an HTTP(S) URL such as https://gitlab.example.com/api/v4",
+			})
+		}
+	}
+
+	return errors
+}
+
+// validateBitbucket validates Bitbucket integration settings.
+func validateBitbucket(bb *BitbucketConfig) []ValidationError {
+	var errors []ValidationError
+
+	if bb.Endpoint != "" {
+		if parsed, err := url.Parse(bb.Endpoint); err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+			errors = append(errors, ValidationError{
+				Field:   "integration.bitbucket.endpoint",
+				Value:   bb.Endpoint,
+				Message: "Bitbucket endpoint must be an HTTP(S) URL such as https://api.bitbucket.org/2.0",
+			})
+		}
+	}
+
+	if bb.Username != "" && bb.Token == "" {
+		errors = append(errors, ValidationError{
+			Field:   "integration.bitbucket.username",
+			Value:   bb.Username,
+			Message: "Bitbucket username requires a token (app password)",
+		})
+	}
+
 	return errors
 }
 
+// validateGoProxy validates Go module proxy settings.
+func validateGoProxy(proxy *GoProxyConfig) []ValidationError {
+	var errors []ValidationError
+
+	if proxy.URL != "" {
+		if parsed, err := url.Parse(proxy.URL); err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+			errors = append(errors, ValidationError{
+				Field:   "integration.goproxy.url",
+				Value:   redactURLUserinfo(proxy.URL),
+				Message: "Go module proxy URL must be an HTTP(S) URL such as https://athens.example.com",
+			})
+		}
+	}
+
+	if proxy.WaitTimeout < 0 {
+		errors = append(errors, ValidationError{
+			Field:   "integration.goproxy.wait_timeout",
+			Value:   proxy.WaitTimeout,
+			Message: "wait timeout cannot be negative",
+		})
+	}
+
+	if proxy.PollInterval < 0 {
+		errors = append(errors, ValidationError{
+			Field:   "integration.goproxy.poll_interval",
+			Value:   proxy.PollInterval,
+			Message: "poll interval cannot be negative",
+		})
+	}
+
+	for i, pattern := range proxy.Private {
+		trimmed := strings.TrimSpace(pattern)
+		if _, err := path.Match(trimmed, ""); err != nil || trimmed == "" || strings.ContainsAny(trimmed, ", \t") {
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("integration.goproxy.private[%d]", i),
+				Value:   pattern,
+				Message: "private module pattern must be a single GOPRIVATE glob such as git.example.com/* or github.com/acme/*",
+			})
+		}
+	}
+
+	return errors
+}
+
+// reservedRequestHeaders are headers clients set per request, which tagging
+// must not replace.
+var reservedRequestHeaders = map[string]bool{
+	"Accept":            true,
+	"Authorization":     true,
+	"Connection":        true,
+	"Content-Length":    true,
+	"Content-Type":      true,
+	"Cookie":            true,
+	"Host":              true,
+	"Private-Token":     true,
+	"Transfer-Encoding": true,
+	"User-Agent":        true,
+}
+
+// validateRequestTagging validates the user agent, org, and headers added to
+// outbound requests.
+func validateRequestTagging(tags *RequestTaggingConfig) []ValidationError {
+	var errors []ValidationError
+
+	if strings.ContainsAny(tags.UserAgent, "\r\n") {
+		errors = append(errors, ValidationError{
+			Field:   "integration.requests.user_agent",
+			Value:   tags.UserAgent,
+			Message: "user agent cannot contain line breaks",
+		})
+	}
+
+	if strings.ContainsAny(tags.Org, "\r\n") {
+		errors = append(errors, ValidationError{
+			Field:   "integration.requests.org",
+			Value:   tags.Org,
+			Message: "org cannot contain line breaks",
+		})
+	}
+
+	for name, value := range tags.Headers {
+		field := fmt.Sprintf("integration.requests.headers.%s", name)
+		switch {
+		case !isHeaderToken(name):
+			errors = append(errors, ValidationError{
+				Field:   field,
+				Value:   name,
+				Message: "header name must be a valid HTTP token such as X-Team",
+			})
+		case reservedRequestHeaders[http.CanonicalHeaderKey(name)]:
+			errors = append(errors, ValidationError{
+				Field:   field,
+				Value:   name,
+				Message: "header is set by cascade per request; use user_agent for the User-Agent",
+			})
+		case strings.ContainsAny(value, "\r\n"):
+			errors = append(errors, ValidationError{
+				Field:   field,
+				Value:   value,
+				Message: "header value cannot contain line breaks",
+			})
+		}
+	}
+
+	return errors
+}
+
+// isHeaderToken reports whether name is a valid HTTP header field name.
+func isHeaderToken(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case strings.ContainsRune("!#$%&'*+-.^_`|~", r):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
 // validateSlack validates Slack integration settings.
 func validateSlack(slack *SlackConfig) []ValidationError {
 	var errors []ValidationError
@@ -251,7 +615,7 @@ func validateSlack(slack *SlackConfig) []ValidationError {
 		if !isValidSlackToken(slack.Token) {
 			errors = append(errors, ValidationError{
 				Field:   "integration.slack.token",
-				Value:   "[REDACTED]",
+				Value:   RedactedValue,
 				Message: "invalid Slack token format",
 			})
 		}
@@ -262,13 +626,13 @@ func validateSlack(slack *SlackConfig) []ValidationError {
 		if u, err := url.Parse(slack.WebhookURL); err != nil {
 			errors = append(errors, ValidationError{
 				Field:   "integration.slack.webhook_url",
-				Value:   "[REDACTED]",
+				Value:   RedactedValue,
 				Message: fmt.Sprintf("invalid Slack webhook URL: %v", err),
 			})
 		} else if u.Scheme != "https" {
 			errors = append(errors, ValidationError{
 				Field:   "integration.slack.webhook_url",
-				Value:   "[REDACTED]",
+				Value:   RedactedValue,
 				Message: "Slack webhook URL must use HTTPS",
 			})
 		}
@@ -288,6 +652,287 @@ func validateSlack(slack *SlackConfig) []ValidationError {
 	return errors
 }
 
+// validateUI validates the base URL notifications link to.
+func validateUI(ui *UIConfig) []ValidationError {
+	if ui.BaseURL == "" {
+		return nil
+	}
+	if parsed, err := url.Parse(ui.BaseURL); err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+		return []ValidationError{{
+			Field:   "ui.base_url",
+			Value:   ui.BaseURL,
+			Message: "base_url must be an absolute URL such as https://cascade.example.com/state",
+		}}
+	}
+	return nil
+}
+
+// validatePullRequests validates the pull request format.
+func validatePullRequests(prs *PullRequestsConfig) []ValidationError {
+	switch prs.Format {
+	case "", PRFormatCascade, PRFormatDependabot:
+		return nil
+	default:
+		return []ValidationError{{
+			Field:   "pull_requests.format",
+			Value:   prs.Format,
+			Message: fmt.Sprintf("pull request format must be one of: %s, %s", PRFormatCascade, PRFormatDependabot),
+		}}
+	}
+}
+
+// validateTelemetry validates the metrics listener, the Pushgateway, and the
+// tracing endpoint.
+func validateTelemetry(telemetry *TelemetryConfig) []ValidationError {
+	var errors []ValidationError
+
+	if telemetry.MetricsListen != "" {
+		if _, _, err := net.SplitHostPort(telemetry.MetricsListen); err != nil {
+			errors = append(errors, ValidationError{
+				Field:   "telemetry.metrics_listen",
+				Value:   telemetry.MetricsListen,
+				Message: "metrics_listen must be a host:port address such as :9464",
+			})
+		}
+	}
+
+	endpoints := []struct {
+		field, value string
+	}{
+		{"telemetry.pushgateway.url", telemetry.Pushgateway.URL},
+		{"telemetry.tracing.endpoint", telemetry.Tracing.Endpoint},
+	}
+	for _, endpoint := range endpoints {
+		if endpoint.value == "" {
+			continue
+		}
+		if parsed, err := url.Parse(endpoint.value); err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+			errors = append(errors, ValidationError{
+				Field:   endpoint.field,
+				Value:   endpoint.value,
+				Message: "must be an absolute http or https URL",
+			})
+		}
+	}
+
+	if telemetry.Pushgateway.Interval < 0 {
+		errors = append(errors, ValidationError{
+			Field:   "telemetry.pushgateway.interval",
+			Value:   telemetry.Pushgateway.Interval,
+			Message: "push interval cannot be negative",
+		})
+	}
+
+	return errors
+}
+
+// validateApproval validates the approval gate, which needs a Slack app that
+// can post messages and sign interactivity requests.
+func validateApproval(approval *ApprovalConfig, slack *SlackConfig) []ValidationError {
+	var errors []ValidationError
+
+	if approval.Mode == "" {
+		return nil
+	}
+	if approval.Mode != ApprovalModePlan && approval.Mode != ApprovalModeItem {
+		errors = append(errors, ValidationError{
+			Field:   "approval.mode",
+			Value:   approval.Mode,
+			Message: fmt.Sprintf("approval mode must be one of: %s, %s", ApprovalModePlan, ApprovalModeItem),
+		})
+	}
+	if slack.Token == "" {
+		errors = append(errors, ValidationError{
+			Field:   "integration.slack.token",
+			Value:   "",
+			Message: "approval requires a Slack bot token to post approval requests",
+		})
+	}
+	if slack.SigningSecret == "" {
+		errors = append(errors, ValidationError{
+			Field:   "integration.slack.signing_secret",
+			Value:   "",
+			Message: "approval requires the Slack app signing secret to verify button clicks",
+		})
+	}
+	if channel := approval.ApprovalChannel(*slack); channel == "" {
+		errors = append(errors, ValidationError{
+			Field:   "approval.channel",
+			Value:   "",
+			Message: "approval requires a channel, set approval.channel or integration.slack.channel",
+		})
+	} else if !strings.HasPrefix(channel, "#") && !strings.HasPrefix(channel, "@") {
+		errors = append(errors, ValidationError{
+			Field:   "approval.channel",
+			Value:   channel,
+			Message: "Slack channel must start with # (channel) or @ (user)",
+		})
+	}
+	if approval.Timeout < 0 {
+		errors = append(errors, ValidationError{
+			Field:   "approval.timeout",
+			Value:   approval.Timeout,
+			Message: "timeout cannot be negative",
+		})
+	}
+
+	return errors
+}
+
+// notificationStatuses lists the result statuses notification templates can target.
+var notificationStatuses = []string{"completed", "failed", "manual-review", "skipped", "no-change"}
+
+// notificationChannels lists the channels notification templates can target.
+var notificationChannels = []string{"slack", "webhook", "sandbox"}
+
+// validateNotifications validates the status and channel keys of notification templates.
+func validateNotifications(n *NotificationTemplatesConfig) []ValidationError {
+	var errors []ValidationError
+
+	for status := range n.Templates {
+		if !contains(notificationStatuses, status) {
+			errors = append(errors, ValidationError{
+				Field:   "integration.notifications.templates",
+				Value:   status,
+				Message: fmt.Sprintf("unknown status, must be one of: %s", strings.Join(notificationStatuses, ", ")),
+			})
+		}
+	}
+
+	for name, channel := range n.Channels {
+		if !contains(notificationChannels, name) {
+			errors = append(errors, ValidationError{
+				Field:   "integration.notifications.channels",
+				Value:   name,
+				Message: fmt.Sprintf("unknown channel, must be one of: %s", strings.Join(notificationChannels, ", ")),
+			})
+		}
+		for status := range channel.Templates {
+			if !contains(notificationStatuses, status) {
+				errors = append(errors, ValidationError{
+					Field:   fmt.Sprintf("integration.notifications.channels.%s.templates", name),
+					Value:   status,
+					Message: fmt.Sprintf("unknown status, must be one of: %s", strings.Join(notificationStatuses, ", ")),
+				})
+			}
+		}
+	}
+
+	errors = append(errors, validateEscalation(n.Escalation)...)
+	errors = append(errors, validateDigest(n.Digest)...)
+
+	return errors
+}
+
+// digestChannels lists the channels that can batch notifications into digests.
+var digestChannels = []string{"slack", "webhook", "sandbox"}
+
+// validateDigest validates the notification digest settings.
+func validateDigest(digest NotificationDigestConfig) []ValidationError {
+	var errors []ValidationError
+
+	if digest.Window < 0 {
+		errors = append(errors, ValidationError{
+			Field:   "integration.notifications.digest.window",
+			Value:   digest.Window,
+			Message: "digest window must not be negative",
+		})
+	}
+	for _, channel := range digest.Channels {
+		if !contains(digestChannels, channel) {
+			errors = append(errors, ValidationError{
+				Field:   "integration.notifications.digest.channels",
+				Value:   channel,
+				Message: fmt.Sprintf("unknown channel, must be one of: %s", strings.Join(digestChannels, ", ")),
+			})
+		}
+	}
+
+	return errors
+}
+
+// escalationChannels lists the channels escalation steps can select.
+var escalationChannels = []string{"slack", "webhook", "github_issues", "pagerduty", "sandbox"}
+
+// validateEscalation validates the steps of the failure escalation ladder.
+func validateEscalation(steps []EscalationStepConfig) []ValidationError {
+	var errors []ValidationError
+
+	seen := make(map[int]bool, len(steps))
+	for i, step := range steps {
+		field := fmt.Sprintf("integration.notifications.escalation[%d]", i)
+		if step.Attempt < 1 {
+			errors = append(errors, ValidationError{
+				Field:   field + ".attempt",
+				Value:   step.Attempt,
+				Message: "escalation attempt must be at least 1",
+			})
+		} else if seen[step.Attempt] {
+			errors = append(errors, ValidationError{
+				Field:   field + ".attempt",
+				Value:   step.Attempt,
+				Message: "escalation attempt is used by another step",
+			})
+		}
+		seen[step.Attempt] = true
+
+		if len(step.Channels) == 0 {
+			errors = append(errors, ValidationError{
+				Field:   field + ".channels",
+				Value:   step.Channels,
+				Message: "escalation step must list at least one channel",
+			})
+		}
+		for _, channel := range step.Channels {
+			if !contains(escalationChannels, channel) {
+				errors = append(errors, ValidationError{
+					Field:   field + ".channels",
+					Value:   channel,
+					Message: fmt.Sprintf("unknown channel, must be one of: %s", strings.Join(escalationChannels, ", ")),
+				})
+			}
+		}
+
+		for _, assignee := range step.Assignees {
+			if strings.TrimSpace(assignee) == "" || strings.ContainsAny(assignee, " \t@") {
+				errors = append(errors, ValidationError{
+					Field:   field + ".assignees",
+					Value:   assignee,
+					Message: "assignee must be a GitHub login without @",
+				})
+			}
+		}
+	}
+
+	return errors
+}
+
+// validatePagerDuty validates PagerDuty integration settings.
+func validatePagerDuty(pd *PagerDutyConfig) []ValidationError {
+	var errors []ValidationError
+
+	if pd.Endpoint != "" {
+		if parsed, err := url.Parse(pd.Endpoint); err != nil || parsed.Scheme != "https" || parsed.Host == "" {
+			errors = append(errors, ValidationError{
+				Field:   "integration.pagerduty.endpoint",
+				Value:   pd.Endpoint,
+				Message: "PagerDuty endpoint must be an HTTPS URL",
+			})
+		}
+	}
+
+	validSeverities := []string{"critical", "error", "warning", "info"}
+	if pd.Severity != "" && !contains(validSeverities, pd.Severity) {
+		errors = append(errors, ValidationError{
+			Field:   "integration.pagerduty.severity",
+			Value:   pd.Severity,
+			Message: fmt.Sprintf("severity must be one of: %s", strings.Join(validSeverities, ", ")),
+		})
+	}
+
+	return errors
+}
+
 // validateLogging validates logging configuration settings.
 func validateLogging(log *LoggingConfig) []ValidationError {
 	var errors []ValidationError
@@ -354,6 +999,24 @@ func validateState(state *StateConfig) []ValidationError {
 		})
 	}
 
+	switch state.Backend {
+	case "", StateBackendFilesystem:
+	case StateBackendS3, StateBackendGCS:
+		if strings.TrimSpace(state.Bucket) == "" {
+			errors = append(errors, ValidationError{
+				Field:   "state.bucket",
+				Value:   state.Bucket,
+				Message: fmt.Sprintf("bucket is required for the %s state backend", state.Backend),
+			})
+		}
+	default:
+		errors = append(errors, ValidationError{
+			Field:   "state.backend",
+			Value:   state.Backend,
+			Message: "state backend must be filesystem, s3, or gcs",
+		})
+	}
+
 	return errors
 }
 
@@ -370,6 +1033,30 @@ func applyExecutorDefaults(exec *ExecutorConfig) {
 		exec.Timeout = 5 * time.Minute // Default: 5 minutes
 	}
 
+	if exec.HeartbeatInterval == 0 {
+		exec.HeartbeatInterval = 30 * time.Second // Default: 30 seconds
+	}
+
+	if exec.RetryDelay == 0 {
+		exec.RetryDelay = 2 * time.Second // Default: 2 seconds
+	}
+
+	if exec.WaveTimeout == 0 {
+		exec.WaveTimeout = 2 * time.Hour // Default: 2 hours
+	}
+
+	if exec.WavePollInterval == 0 {
+		exec.WavePollInterval = time.Minute // Default: 1 minute
+	}
+
+	if exec.CanaryTimeout == 0 {
+		exec.CanaryTimeout = time.Hour // Default: 1 hour
+	}
+
+	if exec.CanaryPollInterval == 0 {
+		exec.CanaryPollInterval = 30 * time.Second // Default: 30 seconds
+	}
+
 	if exec.ConcurrentLimit == 0 {
 		// Default: CPU count or 4, whichever is smaller
 		cpuCount := runtime.NumCPU()
@@ -389,6 +1076,53 @@ func applyIntegrationDefaults(integ *IntegrationConfig) {
 	if integ.GitHub.Endpoint == "" {
 		integ.GitHub.Endpoint = "https://api.github.com" // Default GitHub endpoint
 	}
+
+	if integ.GitHub.RateLimit.MaxWait == 0 {
+		integ.GitHub.RateLimit.MaxWait = 5 * time.Minute // Default: 5 minutes
+	}
+
+	if integ.GitHub.RateLimit.MaxRetries == 0 {
+		integ.GitHub.RateLimit.MaxRetries = 3 // Default: 3 retries
+	}
+
+	if integ.GoProxy.WaitTimeout == 0 {
+		integ.GoProxy.WaitTimeout = 10 * time.Minute // Default: 10 minutes
+	}
+
+	if integ.GoProxy.PollInterval == 0 {
+		integ.GoProxy.PollInterval = 10 * time.Second // Default: 10 seconds
+	}
+}
+
+// applyApprovalDefaults applies default values to approval configuration.
+func applyApprovalDefaults(approval *ApprovalConfig) {
+	if approval.Mode == "" {
+		return
+	}
+
+	if approval.Timeout == 0 {
+		approval.Timeout = time.Hour // Default: 1 hour
+	}
+
+	if approval.Listen == "" {
+		approval.Listen = ":8086" // Default: port 8086 on every interface
+	}
+}
+
+// applyTelemetryDefaults applies default values to telemetry configuration.
+func applyTelemetryDefaults(telemetry *TelemetryConfig) {
+	if telemetry.Pushgateway.URL != "" {
+		if telemetry.Pushgateway.Job == "" {
+			telemetry.Pushgateway.Job = "cascade" // Default job label
+		}
+		if telemetry.Pushgateway.Interval == 0 {
+			telemetry.Pushgateway.Interval = 30 * time.Second // Default: 30 seconds
+		}
+	}
+
+	if telemetry.Tracing.Endpoint != "" && telemetry.Tracing.ServiceName == "" {
+		telemetry.Tracing.ServiceName = "cascade" // Default service name
+	}
 }
 
 // applyLoggingDefaults applies default values to logging configuration.
@@ -203,6 +203,45 @@ func TestValidateExecutor(t *testing.T) {
 			wantError: true,
 			errorMsg:  "concurrent limit cannot exceed 1000",
 		},
+		{
+			name: "valid resource limits",
+			executor: config.ExecutorConfig{
+				Timeout:         5 * time.Minute,
+				ConcurrentLimit: 4,
+				Limits:          config.ResourceLimits{CPU: 2, Memory: "4Gi", Backend: "cgroup"},
+			},
+			wantError: false,
+		},
+		{
+			name: "negative cpu limit",
+			executor: config.ExecutorConfig{
+				Timeout:         5 * time.Minute,
+				ConcurrentLimit: 4,
+				Limits:          config.ResourceLimits{CPU: -1},
+			},
+			wantError: true,
+			errorMsg:  "cpu limit must be positive",
+		},
+		{
+			name: "invalid memory limit",
+			executor: config.ExecutorConfig{
+				Timeout:         5 * time.Minute,
+				ConcurrentLimit: 4,
+				Limits:          config.ResourceLimits{Memory: "lots"},
+			},
+			wantError: true,
+			errorMsg:  "invalid byte size",
+		},
+		{
+			name: "unknown limits backend",
+			executor: config.ExecutorConfig{
+				Timeout:         5 * time.Minute,
+				ConcurrentLimit: 4,
+				Limits:          config.ResourceLimits{CPU: 1, Backend: "docker"},
+			},
+			wantError: true,
+			errorMsg:  "limits backend must be one of",
+		},
 	}
 
 	for _, tt := range tests {
@@ -284,6 +323,90 @@ func TestValidateIntegration(t *testing.T) {
 			wantError: true,
 			errorMsg:  "invalid GitHub endpoint URL",
 		},
+		{
+			name:        "sandbox provider",
+			integration: config.IntegrationConfig{Provider: config.ProviderSandbox},
+			wantError:   false,
+		},
+		{
+			name:        "unknown provider",
+			integration: config.IntegrationConfig{Provider: "gitea"},
+			wantError:   true,
+			errorMsg:    "unknown provider, must be sandbox or empty",
+		},
+		{
+			name: "valid rate limit alerts",
+			integration: config.IntegrationConfig{
+				GitHub: config.GitHubConfig{
+					RateLimitAlerts: []int{50, 80, 100},
+				},
+			},
+			wantError: false,
+		},
+		{
+			name: "rate limit alert out of range",
+			integration: config.IntegrationConfig{
+				GitHub: config.GitHubConfig{
+					RateLimitAlerts: []int{50, 120},
+				},
+			},
+			wantError: true,
+			errorMsg:  "rate limit alert threshold must be between 1 and 100",
+		},
+		{
+			name: "valid git hosts and rewrites",
+			integration: config.IntegrationConfig{
+				Git: config.GitConfig{
+					DefaultHost: "github.example.com",
+					Hosts: []config.GitHostConfig{
+						{Name: "github.example.com", URL: "https://github.example.com"},
+						{Name: "gitlab.example.com", Protocol: "ssh", SSHPort: 2222, Subgroups: true},
+					},
+					Rewrites: []config.GitURLRewrite{{Base: "ssh://git@mirror:7999/", InsteadOf: "https://github.example.com/"}},
+				},
+			},
+			wantError: false,
+		},
+		{
+			name: "git host with unknown protocol",
+			integration: config.IntegrationConfig{
+				Git: config.GitConfig{
+					Hosts: []config.GitHostConfig{{Name: "git.example.com", Protocol: "ftp"}},
+				},
+			},
+			wantError: true,
+			errorMsg:  "protocol must be https or ssh",
+		},
+		{
+			name: "git host given as URL",
+			integration: config.IntegrationConfig{
+				Git: config.GitConfig{
+					Hosts: []config.GitHostConfig{{Name: "https://git.example.com"}},
+				},
+			},
+			wantError: true,
+			errorMsg:  "must be a bare host name",
+		},
+		{
+			name: "git host with invalid SSH port",
+			integration: config.IntegrationConfig{
+				Git: config.GitConfig{
+					Hosts: []config.GitHostConfig{{Name: "git.example.com", SSHPort: 70000}},
+				},
+			},
+			wantError: true,
+			errorMsg:  "ssh port must be between 1 and 65535",
+		},
+		{
+			name: "git rewrite without instead_of",
+			integration: config.IntegrationConfig{
+				Git: config.GitConfig{
+					Rewrites: []config.GitURLRewrite{{Base: "ssh://git@mirror/"}},
+				},
+			},
+			wantError: true,
+			errorMsg:  "rewrites need both base and instead_of",
+		},
 		{
 			name: "valid Slack bot token",
 			integration: config.IntegrationConfig{
@@ -350,6 +473,249 @@ func TestValidateIntegration(t *testing.T) {
 			wantError: true,
 			errorMsg:  "Slack channel must start with # (channel) or @ (user)",
 		},
+		{
+			name: "valid notification templates",
+			integration: config.IntegrationConfig{
+				Notifications: config.NotificationTemplatesConfig{
+					Templates: map[string]string{"completed": "ok", "failed": "details"},
+					Channels: map[string]config.NotificationChannelTemplates{
+						"webhook": {Templates: map[string]string{"manual-review": "review"}},
+					},
+				},
+			},
+			wantError: false,
+		},
+		{
+			name: "unknown notification status",
+			integration: config.IntegrationConfig{
+				Notifications: config.NotificationTemplatesConfig{
+					Templates: map[string]string{"success": "ok"},
+				},
+			},
+			wantError: true,
+			errorMsg:  "unknown status, must be one of: completed, failed, manual-review, skipped",
+		},
+		{
+			name: "unknown notification channel",
+			integration: config.IntegrationConfig{
+				Notifications: config.NotificationTemplatesConfig{
+					Channels: map[string]config.NotificationChannelTemplates{"email": {Template: "x"}},
+				},
+			},
+			wantError: true,
+			errorMsg:  "unknown channel, must be one of: slack, webhook",
+		},
+		{
+			name: "valid escalation ladder",
+			integration: config.IntegrationConfig{
+				Notifications: config.NotificationTemplatesConfig{
+					Escalation: []config.EscalationStepConfig{
+						{Attempt: 1, Channels: []string{"slack"}},
+						{Attempt: 2, Channels: []string{"slack", "github_issues"}},
+						{Attempt: 3, Channels: []string{"slack", "github_issues", "pagerduty"}, Assignees: []string{"team-lead"}},
+					},
+				},
+				PagerDuty: config.PagerDutyConfig{RoutingKey: "key", Severity: "critical"},
+			},
+			wantError: false,
+		},
+		{
+			name: "escalation attempt below one",
+			integration: config.IntegrationConfig{
+				Notifications: config.NotificationTemplatesConfig{
+					Escalation: []config.EscalationStepConfig{{Attempt: 0, Channels: []string{"slack"}}},
+				},
+			},
+			wantError: true,
+			errorMsg:  "escalation attempt must be at least 1",
+		},
+		{
+			name: "duplicate escalation attempt",
+			integration: config.IntegrationConfig{
+				Notifications: config.NotificationTemplatesConfig{
+					Escalation: []config.EscalationStepConfig{
+						{Attempt: 2, Channels: []string{"slack"}},
+						{Attempt: 2, Channels: []string{"pagerduty"}},
+					},
+				},
+			},
+			wantError: true,
+			errorMsg:  "escalation attempt is used by another step",
+		},
+		{
+			name: "unknown escalation channel",
+			integration: config.IntegrationConfig{
+				Notifications: config.NotificationTemplatesConfig{
+					Escalation: []config.EscalationStepConfig{{Attempt: 1, Channels: []string{"email"}}},
+				},
+			},
+			wantError: true,
+			errorMsg:  "unknown channel, must be one of: slack, webhook, github_issues, pagerduty",
+		},
+		{
+			name: "escalation step without channels",
+			integration: config.IntegrationConfig{
+				Notifications: config.NotificationTemplatesConfig{
+					Escalation: []config.EscalationStepConfig{{Attempt: 1}},
+				},
+			},
+			wantError: true,
+			errorMsg:  "escalation step must list at least one channel",
+		},
+		{
+			name: "escalation assignee with @",
+			integration: config.IntegrationConfig{
+				Notifications: config.NotificationTemplatesConfig{
+					Escalation: []config.EscalationStepConfig{{Attempt: 1, Channels: []string{"github_issues"}, Assignees: []string{"@lead"}}},
+				},
+			},
+			wantError: true,
+			errorMsg:  "assignee must be a GitHub login without @",
+		},
+		{
+			name: "valid notification digest",
+			integration: config.IntegrationConfig{
+				Notifications: config.NotificationTemplatesConfig{
+					Digest: config.NotificationDigestConfig{Window: time.Hour, Channels: []string{"slack", "webhook"}},
+				},
+			},
+			wantError: false,
+		},
+		{
+			name: "negative digest window",
+			integration: config.IntegrationConfig{
+				Notifications: config.NotificationTemplatesConfig{
+					Digest: config.NotificationDigestConfig{Window: -time.Minute},
+				},
+			},
+			wantError: true,
+			errorMsg:  "digest window must not be negative",
+		},
+		{
+			name: "unknown digest channel",
+			integration: config.IntegrationConfig{
+				Notifications: config.NotificationTemplatesConfig{
+					Digest: config.NotificationDigestConfig{Window: time.Hour, Channels: []string{"pagerduty"}},
+				},
+			},
+			wantError: true,
+			errorMsg:  "unknown channel, must be one of: slack, webhook, sandbox",
+		},
+		{
+			name: "invalid PagerDuty severity",
+			integration: config.IntegrationConfig{
+				PagerDuty: config.PagerDutyConfig{Severity: "high"},
+			},
+			wantError: true,
+			errorMsg:  "severity must be one of: critical, error, warning, info",
+		},
+		{
+			name: "PagerDuty endpoint must use HTTPS",
+			integration: config.IntegrationConfig{
+				PagerDuty: config.PagerDutyConfig{Endpoint: "http://events.example.com"},
+			},
+			wantError: true,
+			errorMsg:  "PagerDuty endpoint must be an HTTPS URL",
+		},
+		{
+			name: "self-hosted GitLab endpoint",
+			integration: config.IntegrationConfig{
+				GitLab: config.GitLabConfig{Token: "glpat-token", Endpoint: "https://gitlab.example.com/api/v4"},
+			},
+			wantError: false,
+		},
+		{
+			name: "GitLab endpoint must be a URL",
+			integration: config.IntegrationConfig{
+				GitLab: config.GitLabConfig{Endpoint: "gitlab.example.com"},
+			},
+			wantError: true,
+			errorMsg:  "GitLab endpoint must be an HTTP(S) URL",
+		},
+		{
+			name: "Bitbucket app password",
+			integration: config.IntegrationConfig{
+				Bitbucket: config.BitbucketConfig{Username: "ci-bot", Token: "app-password"},
+			},
+			wantError: false,
+		},
+		{
+			name: "Bitbucket username needs a token",
+			integration: config.IntegrationConfig{
+				Bitbucket: config.BitbucketConfig{Username: "ci-bot"},
+			},
+			wantError: true,
+			errorMsg:  "Bitbucket username requires a token",
+		},
+		{
+			name: "Go module proxy",
+			integration: config.IntegrationConfig{
+				GoProxy: config.GoProxyConfig{URL: "https://athens.example.com", WaitTimeout: 5 * time.Minute},
+			},
+			wantError: false,
+		},
+		{
+			name: "Go module proxy URL must be a URL",
+			integration: config.IntegrationConfig{
+				GoProxy: config.GoProxyConfig{URL: "athens.example.com"},
+			},
+			wantError: true,
+			errorMsg:  "Go module proxy URL must be an HTTP(S) URL",
+		},
+		{
+			name: "Go module proxy poll interval cannot be negative",
+			integration: config.IntegrationConfig{
+				GoProxy: config.GoProxyConfig{URL: "https://athens.example.com", PollInterval: -time.Second},
+			},
+			wantError: true,
+			errorMsg:  "poll interval cannot be negative",
+		},
+		{
+			name: "Go private module patterns",
+			integration: config.IntegrationConfig{
+				GoProxy: config.GoProxyConfig{Private: []string{"git.example.com/*", "github.com/acme"}},
+			},
+			wantError: false,
+		},
+		{
+			name: "Go private module patterns are single globs",
+			integration: config.IntegrationConfig{
+				GoProxy: config.GoProxyConfig{Private: []string{"git.example.com/*,github.com/acme"}},
+			},
+			wantError: true,
+			errorMsg:  "private module pattern must be a single GOPRIVATE glob",
+		},
+		{
+			name: "relative SSH key path",
+			integration: config.IntegrationConfig{
+				Git: config.GitConfig{SSH: config.GitSSHConfig{KeyPath: "keys/deploy"}},
+			},
+			wantError: true,
+			errorMsg:  "path must be absolute",
+		},
+		{
+			name: "request tagging headers",
+			integration: config.IntegrationConfig{
+				Requests: config.RequestTaggingConfig{UserAgent: "platform-bot/1.0", Headers: map[string]string{"X-Team": "payments"}},
+			},
+			wantError: false,
+		},
+		{
+			name: "request tagging cannot replace Authorization",
+			integration: config.IntegrationConfig{
+				Requests: config.RequestTaggingConfig{Headers: map[string]string{"authorization": "Bearer x"}},
+			},
+			wantError: true,
+			errorMsg:  "header is set by cascade per request",
+		},
+		{
+			name: "request tagging header names are tokens",
+			integration: config.IntegrationConfig{
+				Requests: config.RequestTaggingConfig{Headers: map[string]string{"X Team": "payments"}},
+			},
+			wantError: true,
+			errorMsg:  "header name must be a valid HTTP token",
+		},
 	}
 
 	for _, tt := range tests {
@@ -509,6 +875,36 @@ func TestValidateState(t *testing.T) {
 			wantError: true,
 			errorMsg:  "retention count cannot exceed 10000",
 		},
+		{
+			name: "s3 backend with bucket",
+			state: config.StateConfig{
+				Dir:            "/tmp/cascade-state",
+				RetentionCount: 10,
+				Backend:        config.StateBackendS3,
+				Bucket:         "cascade-state",
+			},
+			wantError: false,
+		},
+		{
+			name: "gcs backend without bucket",
+			state: config.StateConfig{
+				Dir:            "/tmp/cascade-state",
+				RetentionCount: 10,
+				Backend:        config.StateBackendGCS,
+			},
+			wantError: true,
+			errorMsg:  "bucket is required for the gcs state backend",
+		},
+		{
+			name: "unknown backend",
+			state: config.StateConfig{
+				Dir:            "/tmp/cascade-state",
+				RetentionCount: 10,
+				Backend:        "azure",
+			},
+			wantError: true,
+			errorMsg:  "state backend must be filesystem, s3, or gcs",
+		},
 	}
 
 	for _, tt := range tests {
@@ -543,6 +939,145 @@ func TestValidateState(t *testing.T) {
 	}
 }
 
+func TestValidateApproval(t *testing.T) {
+	token := "xoxb-" + strings.Repeat("0", 50)
+	slack := config.SlackConfig{Token: token, Channel: "#releases", SigningSecret: "secret"}
+	tests := []struct {
+		name      string
+		approval  config.ApprovalConfig
+		slack     config.SlackConfig
+		wantError bool
+		errorMsg  string
+	}{
+		{
+			name:     "disabled",
+			approval: config.ApprovalConfig{},
+		},
+		{
+			name:     "plan approval in the Slack channel",
+			approval: config.ApprovalConfig{Mode: config.ApprovalModePlan},
+			slack:    slack,
+		},
+		{
+			name:      "unknown mode",
+			approval:  config.ApprovalConfig{Mode: "repo"},
+			slack:     slack,
+			wantError: true,
+			errorMsg:  "approval mode must be one of",
+		},
+		{
+			name:      "missing signing secret",
+			approval:  config.ApprovalConfig{Mode: config.ApprovalModeItem},
+			slack:     config.SlackConfig{Token: token, Channel: "#releases"},
+			wantError: true,
+			errorMsg:  "signing secret",
+		},
+		{
+			name:      "missing channel",
+			approval:  config.ApprovalConfig{Mode: config.ApprovalModeItem},
+			slack:     config.SlackConfig{Token: token, SigningSecret: "secret"},
+			wantError: true,
+			errorMsg:  "approval requires a channel",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				Workspace: config.WorkspaceConfig{
+					Path: "/tmp/cascade",
+				},
+				Executor: config.ExecutorConfig{
+					Timeout:         5 * time.Minute,
+					ConcurrentLimit: 4,
+				},
+				Integration: config.IntegrationConfig{
+					Slack: tt.slack,
+				},
+				Logging: config.LoggingConfig{
+					Level:  "info",
+					Format: "text",
+				},
+				State: config.StateConfig{
+					Dir:            "/tmp/cascade-state",
+					RetentionCount: 10,
+				},
+				Approval: tt.approval,
+			}
+
+			err := config.Validate(cfg)
+			if tt.wantError {
+				if err == nil {
+					t.Fatal("expected validation error")
+				}
+				if !strings.Contains(err.Error(), tt.errorMsg) {
+					t.Errorf("expected error message %q, got: %v", tt.errorMsg, err)
+				}
+			} else if err != nil {
+				t.Errorf("unexpected validation error: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateUI(t *testing.T) {
+	for _, tt := range []struct {
+		baseURL   string
+		wantError bool
+	}{
+		{baseURL: ""},
+		{baseURL: "https://cascade.example.com/state"},
+		{baseURL: "cascade.example.com/state", wantError: true},
+		{baseURL: "ftp://cascade.example.com", wantError: true},
+	} {
+		cfg := &config.Config{
+			Workspace: config.WorkspaceConfig{Path: "/tmp/cascade"},
+			Executor:  config.ExecutorConfig{Timeout: 5 * time.Minute, ConcurrentLimit: 4},
+			Logging:   config.LoggingConfig{Level: "info", Format: "text"},
+			State:     config.StateConfig{Dir: "/tmp/cascade-state", RetentionCount: 10},
+			UI:        config.UIConfig{BaseURL: tt.baseURL},
+		}
+
+		err := config.Validate(cfg)
+		if tt.wantError {
+			if err == nil || !strings.Contains(err.Error(), "ui.base_url") {
+				t.Errorf("base_url %q: expected a ui.base_url error, got %v", tt.baseURL, err)
+			}
+		} else if err != nil {
+			t.Errorf("base_url %q: unexpected validation error: %v", tt.baseURL, err)
+		}
+	}
+}
+
+func TestValidatePullRequests(t *testing.T) {
+	for _, tt := range []struct {
+		format    string
+		wantError bool
+	}{
+		{format: ""},
+		{format: config.PRFormatCascade},
+		{format: config.PRFormatDependabot},
+		{format: "renovate", wantError: true},
+	} {
+		cfg := &config.Config{
+			Workspace:    config.WorkspaceConfig{Path: "/tmp/cascade"},
+			Executor:     config.ExecutorConfig{Timeout: 5 * time.Minute, ConcurrentLimit: 4},
+			Logging:      config.LoggingConfig{Level: "info", Format: "text"},
+			State:        config.StateConfig{Dir: "/tmp/cascade-state", RetentionCount: 10},
+			PullRequests: config.PullRequestsConfig{Format: tt.format},
+		}
+
+		err := config.Validate(cfg)
+		if tt.wantError {
+			if err == nil || !strings.Contains(err.Error(), "pull_requests.format") {
+				t.Errorf("format %q: expected a pull_requests.format error, got %v", tt.format, err)
+			}
+		} else if err != nil {
+			t.Errorf("format %q: unexpected validation error: %v", tt.format, err)
+		}
+	}
+}
+
 func TestApplyDefaults_NilConfig(t *testing.T) {
 	err := config.ApplyDefaults(nil)
 	if err == nil {
@@ -580,6 +1115,18 @@ func TestApplyDefaults(t *testing.T) {
 		t.Errorf("expected concurrent limit default of %d, got: %d", expectedConcurrency, cfg.Executor.ConcurrentLimit)
 	}
 
+	if cfg.Executor.Retries != 0 || cfg.Executor.RetryDelay != 2*time.Second {
+		t.Errorf("expected no retries with a 2s delay by default, got: %d, %v", cfg.Executor.Retries, cfg.Executor.RetryDelay)
+	}
+
+	if cfg.Executor.MultiLevel || cfg.Executor.WaveTimeout != 2*time.Hour || cfg.Executor.WavePollInterval != time.Minute {
+		t.Errorf("expected single-level releases with 2h/1m wave defaults, got: %v, %v, %v", cfg.Executor.MultiLevel, cfg.Executor.WaveTimeout, cfg.Executor.WavePollInterval)
+	}
+
+	if cfg.Executor.CanaryTimeout != time.Hour || cfg.Executor.CanaryPollInterval != 30*time.Second {
+		t.Errorf("expected 1h/30s canary defaults, got: %v, %v", cfg.Executor.CanaryTimeout, cfg.Executor.CanaryPollInterval)
+	}
+
 	// Verify integration defaults
 	if cfg.Integration.GitHub.Endpoint != "https://api.github.com" {
 		t.Errorf("expected GitHub endpoint default, got: %s", cfg.Integration.GitHub.Endpoint)
@@ -771,3 +1318,38 @@ func TestManifestFileValidation(t *testing.T) {
 		t.Errorf("expected manifest file error, got: %v", err)
 	}
 }
+
+func TestValidateTelemetry(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		telemetry config.TelemetryConfig
+		wantField string
+	}{
+		{name: "empty"},
+		{name: "valid", telemetry: config.TelemetryConfig{
+			MetricsListen: ":9464",
+			Pushgateway:   config.PushgatewayConfig{URL: "http://pushgateway:9091"},
+			Tracing:       config.TracingConfig{Endpoint: "https://otel.example.com:4318"},
+		}},
+		{name: "listen without port", telemetry: config.TelemetryConfig{MetricsListen: "9464"}, wantField: "telemetry.metrics_listen"},
+		{name: "relative pushgateway", telemetry: config.TelemetryConfig{Pushgateway: config.PushgatewayConfig{URL: "pushgateway:9091"}}, wantField: "telemetry.pushgateway.url"},
+		{name: "grpc endpoint", telemetry: config.TelemetryConfig{Tracing: config.TracingConfig{Endpoint: "grpc://collector:4317"}}, wantField: "telemetry.tracing.endpoint"},
+	} {
+		cfg := &config.Config{
+			Workspace: config.WorkspaceConfig{Path: "/tmp/cascade"},
+			Executor:  config.ExecutorConfig{Timeout: 5 * time.Minute, ConcurrentLimit: 4},
+			Logging:   config.LoggingConfig{Level: "info", Format: "text"},
+			State:     config.StateConfig{Dir: "/tmp/cascade-state", RetentionCount: 10},
+			Telemetry: tt.telemetry,
+		}
+
+		err := config.Validate(cfg)
+		if tt.wantField != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantField) {
+				t.Errorf("%s: expected a %s error, got %v", tt.name, tt.wantField, err)
+			}
+		} else if err != nil {
+			t.Errorf("%s: unexpected validation error: %v", tt.name, err)
+		}
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/goliatone/cascade/internal/planner"
 	"github.com/goliatone/cascade/internal/state"
 	"github.com/goliatone/cascade/pkg/config"
+	"github.com/goliatone/cascade/pkg/repometa"
 )
 
 // Logger defines the logging interface used throughout the application.
@@ -38,6 +39,10 @@ type Container interface {
 	Logger() Logger
 	HTTPClient() *http.Client
 
+	// RepoMetadata returns the repository metadata cache shared across phases,
+	// or nil when GitHub credentials are not configured
+	RepoMetadata() *repometa.Service
+
 	// Resource management
 	Close() error
 }
@@ -82,6 +87,7 @@ type builder struct {
 	executor          executor.Executor
 	broker            broker.Broker
 	stateManager      state.Manager
+	repoMetadata      *repometa.Service
 }
 
 // container implements the Container interface with concrete dependencies.
@@ -96,6 +102,7 @@ type container struct {
 	executor          executor.Executor
 	broker            broker.Broker
 	stateManager      state.Manager
+	repoMetadata      *repometa.Service
 }
 
 // Core service accessors
@@ -114,7 +121,7 @@ func (c *container) BrokerWithManifestNotifications(notifications *ManifestNotif
 		return broker.NewStub(), nil
 	}
 
-	return provideBrokerForProductionWithManifest(c.cfg, notifications, c.httpClient, c.logger)
+	return provideBrokerForProductionWithManifest(c.cfg, notifications, c.httpClient, c.repoMetadata, c.logger)
 }
 
 func (c *container) State() state.Manager { return c.stateManager }
@@ -124,6 +131,8 @@ func (c *container) Config() *config.Config   { return c.cfg }
 func (c *container) Logger() Logger           { return c.logger }
 func (c *container) HTTPClient() *http.Client { return c.httpClient }
 
+func (c *container) RepoMetadata() *repometa.Service { return c.repoMetadata }
+
 // Close performs cleanup of container resources.
 // It attempts to close any services that implement io.Closer,
 // logging any errors that occur during cleanup.
@@ -215,8 +224,20 @@ func (b *builder) build() (Container, error) {
 		b.manifestGenerator = provideManifestGeneratorWithConfig(b.cfg, b.logger)
 	}
 
+	// One metadata cache serves every phase so repositories are looked up once per run
+	if b.repoMetadata == nil {
+		b.repoMetadata = provideRepoMetadataWithConfig(b.cfg, b.httpClient, b.logger)
+	}
+
+	// State manager depends on config for storage directory and settings
+	if b.stateManager == nil {
+		b.stateManager = provideStateWithConfig(b.cfg, b.logger)
+	}
+
+	// The planner estimates runtime from the item timings kept in state
 	if b.planner == nil {
-		b.planner = providePlannerWithConfig(b.cfg, b.logger)
+		history, _ := b.stateManager.(planner.TimingHistory)
+		b.planner = providePlannerWithConfig(b.cfg, b.repoMetadata, history, b.logger)
 	}
 
 	// Executor can use config for timeout settings
@@ -227,21 +248,16 @@ func (b *builder) build() (Container, error) {
 	// Broker depends on config for GitHub/Slack credentials and dry-run mode
 	if b.broker == nil {
 		if b.requireProductionCredentials {
-			broker, err := provideBrokerForProduction(b.cfg, b.httpClient, b.logger)
+			broker, err := provideBrokerForProductionWithManifest(b.cfg, nil, b.httpClient, b.repoMetadata, b.logger)
 			if err != nil {
 				return nil, fmt.Errorf("di: failed to provide production broker: %w", err)
 			}
 			b.broker = broker
 		} else {
-			b.broker = provideBrokerWithConfig(b.cfg, b.httpClient, b.logger)
+			b.broker = provideBrokerWithConfigAndManifest(b.cfg, nil, b.httpClient, b.repoMetadata, b.logger)
 		}
 	}
 
-	// State manager depends on config for storage directory and settings
-	if b.stateManager == nil {
-		b.stateManager = provideStateWithConfig(b.cfg, b.logger)
-	}
-
 	// Validate that all required dependencies are present
 	if b.cfg == nil {
 		return nil, fmt.Errorf("di: config is required")
@@ -282,6 +298,7 @@ func (b *builder) build() (Container, error) {
 		executor:          b.executor,
 		broker:            b.broker,
 		stateManager:      b.stateManager,
+		repoMetadata:      b.repoMetadata,
 	}
 
 	// Log container creation metrics if instrumentation is enabled
@@ -394,6 +411,18 @@ func WithBroker(b broker.Broker) Option {
 	}
 }
 
+// WithRepoMetadata injects the repository metadata cache shared by the planner,
+// broker, and discovery.
+func WithRepoMetadata(svc *repometa.Service) Option {
+	return func(b *builder) error {
+		if svc == nil {
+			return fmt.Errorf("repository metadata service cannot be nil")
+		}
+		b.repoMetadata = svc
+		return nil
+	}
+}
+
 // WithStateManager injects a custom state manager implementation.
 func WithStateManager(mgr state.Manager) Option {
 	return func(b *builder) error {
@@ -68,6 +68,22 @@ func (m *mockBroker) Notify(ctx context.Context, item planner.WorkItem, result *
 	return nil, errors.New("not implemented")
 }
 
+func (m *mockBroker) MergePR(ctx context.Context, pr *broker.PullRequest, opts broker.MergeOptions) (*broker.MergeResult, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockBroker) ClosePR(ctx context.Context, pr *broker.PullRequest) error {
+	return errors.New("not implemented")
+}
+
+func (m *mockBroker) ResolveIssue(ctx context.Context, item planner.WorkItem, number int, result *executor.Result) (*broker.NotificationResult, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockBroker) NotifyPlan(ctx context.Context, summary broker.PlanSummary) (*broker.NotificationResult, error) {
+	return nil, errors.New("not implemented")
+}
+
 type mockStateManager struct{}
 
 func (m *mockStateManager) LoadSummary(module, version string) (*state.Summary, error) {
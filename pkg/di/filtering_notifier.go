@@ -54,3 +54,44 @@ func (f *FilteringNotifier) Send(ctx context.Context, item planner.WorkItem, res
 	// Send the notification
 	return f.notifier.Send(ctx, item, result)
 }
+
+// SendPlan forwards plan summaries to the wrapped notifier. Plans have no
+// execution outcome, so the on_success/on_failure flags do not apply.
+func (f *FilteringNotifier) SendPlan(ctx context.Context, summary broker.PlanSummary) (*broker.NotificationResult, error) {
+	planNotifier, ok := f.notifier.(broker.PlanNotifier)
+	if !ok {
+		return nil, nil
+	}
+	return planNotifier.SendPlan(ctx, summary)
+}
+
+// ResolveIssue forwards issue resolution to the wrapped notifier. Closing an issue
+// records a state change rather than announcing an outcome, so the
+// on_success/on_failure flags do not apply.
+func (f *FilteringNotifier) ResolveIssue(ctx context.Context, item planner.WorkItem, number int, result *executor.Result) (*broker.NotificationResult, error) {
+	resolver, ok := f.notifier.(broker.IssueResolver)
+	if !ok {
+		return nil, nil
+	}
+	return resolver.ResolveIssue(ctx, item, number, result)
+}
+
+// Alert forwards operational alerts to the wrapped notifier. Alerts are not tied to
+// a work item outcome, so the on_success/on_failure flags do not apply.
+func (f *FilteringNotifier) Alert(ctx context.Context, message string) (*broker.NotificationResult, error) {
+	alerter, ok := f.notifier.(broker.Alerter)
+	if !ok {
+		return nil, nil
+	}
+	return alerter.Alert(ctx, message)
+}
+
+// FlushDigests forwards digest flushes to the wrapped notifier. Notifications
+// were filtered before they were held back for a digest.
+func (f *FilteringNotifier) FlushDigests(ctx context.Context, force bool) (int, error) {
+	flusher, ok := f.notifier.(broker.DigestFlusher)
+	if !ok {
+		return 0, nil
+	}
+	return flusher.FlushDigests(ctx, force)
+}
@@ -175,3 +175,69 @@ func TestFilteringNotifier_SkippedTreatedAsFailure(t *testing.T) {
 		t.Error("expected notifier to be called for skipped when on_failure=true")
 	}
 }
+
+type mockAlertingNotifier struct {
+	mockNotifierForFiltering
+	alerts []string
+}
+
+func (m *mockAlertingNotifier) Alert(ctx context.Context, message string) (*broker.NotificationResult, error) {
+	m.alerts = append(m.alerts, message)
+	return &broker.NotificationResult{Channel: "mock", Message: message}, nil
+}
+
+func TestFilteringNotifier_AlertBypassesFlags(t *testing.T) {
+	mock := &mockAlertingNotifier{}
+	filtering := NewFilteringNotifier(mock, false, false, testLogger{})
+
+	alerter, ok := filtering.(broker.Alerter)
+	if !ok {
+		t.Fatal("expected filtering notifier to support alerts")
+	}
+	if _, err := alerter.Alert(context.Background(), "quota warning"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(mock.alerts) != 1 {
+		t.Errorf("expected alert to reach the wrapped notifier, got %v", mock.alerts)
+	}
+
+	// Wrapped notifiers without alert support are skipped
+	plain := NewFilteringNotifier(&mockNotifierForFiltering{}, true, true, testLogger{})
+	result, err := plain.(broker.Alerter).Alert(context.Background(), "quota warning")
+	if err != nil || result != nil {
+		t.Errorf("expected no-op alert, got %+v, %v", result, err)
+	}
+}
+
+type mockPlanNotifier struct {
+	mockNotifierForFiltering
+	plans []broker.PlanSummary
+}
+
+func (m *mockPlanNotifier) SendPlan(ctx context.Context, summary broker.PlanSummary) (*broker.NotificationResult, error) {
+	m.plans = append(m.plans, summary)
+	return &broker.NotificationResult{Channel: "mock", Message: "plan"}, nil
+}
+
+func TestFilteringNotifier_SendPlanBypassesFlags(t *testing.T) {
+	mock := &mockPlanNotifier{}
+	filtering := NewFilteringNotifier(mock, false, false, testLogger{})
+
+	planNotifier, ok := filtering.(broker.PlanNotifier)
+	if !ok {
+		t.Fatal("expected filtering notifier to support plan summaries")
+	}
+	if _, err := planNotifier.SendPlan(context.Background(), broker.PlanSummary{Module: "github.com/example/lib"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(mock.plans) != 1 {
+		t.Errorf("expected plan summary to reach the wrapped notifier, got %v", mock.plans)
+	}
+
+	// Wrapped notifiers without plan support are skipped
+	plain := NewFilteringNotifier(&mockNotifierForFiltering{}, true, true, testLogger{})
+	result, err := plain.(broker.PlanNotifier).SendPlan(context.Background(), broker.PlanSummary{})
+	if err != nil || result != nil {
+		t.Errorf("expected no-op plan summary, got %+v, %v", result, err)
+	}
+}
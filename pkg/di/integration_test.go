@@ -379,6 +379,26 @@ func (f *fakeBroker) Notify(ctx context.Context, item planner.WorkItem, result *
 	}, nil
 }
 
+func (f *fakeBroker) MergePR(ctx context.Context, pr *broker.PullRequest, opts broker.MergeOptions) (*broker.MergeResult, error) {
+	f.messages = append(f.messages, "broker.MergePR called")
+	return &broker.MergeResult{Merged: true}, nil
+}
+
+func (f *fakeBroker) ClosePR(ctx context.Context, pr *broker.PullRequest) error {
+	f.messages = append(f.messages, "broker.ClosePR called")
+	return nil
+}
+
+func (f *fakeBroker) ResolveIssue(ctx context.Context, item planner.WorkItem, number int, result *executor.Result) (*broker.NotificationResult, error) {
+	f.messages = append(f.messages, "broker.ResolveIssue called")
+	return nil, nil
+}
+
+func (f *fakeBroker) NotifyPlan(ctx context.Context, summary broker.PlanSummary) (*broker.NotificationResult, error) {
+	f.messages = append(f.messages, "broker.NotifyPlan called")
+	return &broker.NotificationResult{}, nil
+}
+
 type fakeStateManager struct {
 	messages []string
 }
@@ -1,10 +1,14 @@
 package di
 
 import (
+	"context"
 	"net/http"
 	"os"
 	"testing"
+	"time"
 
+	"github.com/goliatone/cascade/internal/broker"
+	"github.com/goliatone/cascade/internal/executor"
 	"github.com/goliatone/cascade/pkg/config"
 )
 
@@ -19,7 +23,7 @@ func TestNewNotifierFromConfigWithManifest_PreferManifest(t *testing.T) {
 		}
 
 		logger := testLogger{}
-		notifier := newNotifierFromConfigWithManifest(cfg, manifestNotifications, &http.Client{}, logger)
+		notifier := newNotifierFromConfigWithManifest(cfg, manifestNotifications, &http.Client{}, nil, logger)
 
 		if notifier == nil {
 			t.Fatal("expected notifier, got nil")
@@ -41,7 +45,7 @@ func TestNewNotifierFromConfigWithManifest_GlobalConfigTakesPrecedence(t *testin
 		}
 
 		logger := testLogger{}
-		notifier := newNotifierFromConfigWithManifest(cfg, manifestNotifications, &http.Client{}, logger)
+		notifier := newNotifierFromConfigWithManifest(cfg, manifestNotifications, &http.Client{}, nil, logger)
 
 		if notifier == nil {
 			t.Fatal("expected notifier, got nil")
@@ -59,7 +63,7 @@ func TestNewNotifierFromConfigWithManifest_WebhookFromManifest(t *testing.T) {
 		}
 
 		logger := testLogger{}
-		notifier := newNotifierFromConfigWithManifest(cfg, manifestNotifications, &http.Client{}, logger)
+		notifier := newNotifierFromConfigWithManifest(cfg, manifestNotifications, &http.Client{}, nil, logger)
 
 		if notifier == nil {
 			t.Fatal("expected notifier, got nil")
@@ -74,7 +78,7 @@ func TestNewNotifierFromConfigWithManifest_NoManifestFallback(t *testing.T) {
 		cfg.Integration.Slack.Channel = "#global-channel"
 
 		logger := testLogger{}
-		notifier := newNotifierFromConfigWithManifest(cfg, nil, &http.Client{}, logger)
+		notifier := newNotifierFromConfigWithManifest(cfg, nil, &http.Client{}, nil, logger)
 
 		if notifier == nil {
 			t.Fatal("expected notifier, got nil")
@@ -165,3 +169,106 @@ func withClearedSlackEnv(t *testing.T, fn func()) {
 	}()
 	fn()
 }
+
+func TestApplyNotificationTemplates(t *testing.T) {
+	src := config.NotificationTemplatesConfig{
+		Template:  "default",
+		Templates: map[string]string{"completed": "done"},
+		Channels: map[string]config.NotificationChannelTemplates{
+			"webhook": {Template: "hook", Templates: map[string]string{"failed": "hook failed"}},
+		},
+	}
+
+	dst := broker.DefaultNotificationConfig()
+	applyNotificationTemplates(&dst, src)
+
+	if got := dst.TemplateFor(broker.NotificationChannelSlack, executor.StatusCompleted); got != "done" {
+		t.Errorf("slack completed template = %q", got)
+	}
+	if got := dst.TemplateFor(broker.NotificationChannelSlack, executor.StatusFailed); got != "default" {
+		t.Errorf("slack failed template = %q", got)
+	}
+	if got := dst.TemplateFor(broker.NotificationChannelWebhook, executor.StatusFailed); got != "hook failed" {
+		t.Errorf("webhook failed template = %q", got)
+	}
+	if got := dst.TemplateFor(broker.NotificationChannelWebhook, executor.StatusCompleted); got != "hook" {
+		t.Errorf("webhook completed template = %q", got)
+	}
+}
+
+func TestNewNotifierFromConfigWithManifest_Escalation(t *testing.T) {
+	withClearedSlackEnv(t, func() {
+		withClearedGitHubEnv(t, func() {
+			cfg := &config.Config{}
+			cfg.Integration.Slack.Token = "test-token"
+			cfg.Integration.Slack.Channel = "#alerts"
+			cfg.Integration.PagerDuty.RoutingKey = "routing-key"
+			cfg.Integration.Notifications.Escalation = []config.EscalationStepConfig{
+				{Attempt: 1, Channels: []string{"slack"}},
+				{Attempt: 3, Channels: []string{"slack", "github_issues", "pagerduty"}, Assignees: []string{"team-lead"}},
+			}
+
+			notifier := newNotifierFromConfigWithManifest(cfg, nil, &http.Client{}, nil, testLogger{})
+
+			escalating, ok := notifier.(*broker.EscalatingNotifier)
+			if !ok {
+				t.Fatalf("expected escalating notifier, got %T", notifier)
+			}
+			step := escalating.Step(5)
+			if step.Attempt != 3 || len(step.Channels) != 3 || len(step.Assignees) != 1 {
+				t.Errorf("Step(5) = %+v, want the third attempt step", step)
+			}
+			if _, ok := notifier.(broker.Alerter); !ok {
+				t.Error("expected escalating notifier to forward alerts")
+			}
+		})
+	})
+}
+
+func TestNewNotifierFromConfigWithManifest_PagerDutyWithoutEscalation(t *testing.T) {
+	withClearedSlackEnv(t, func() {
+		withClearedGitHubEnv(t, func() {
+			cfg := &config.Config{}
+			cfg.Integration.PagerDuty.RoutingKey = "routing-key"
+
+			notifier := newNotifierFromConfigWithManifest(cfg, nil, &http.Client{}, nil, testLogger{})
+
+			if _, ok := notifier.(*broker.PagerDutyNotifier); !ok {
+				t.Fatalf("expected PagerDuty notifier, got %T", notifier)
+			}
+		})
+	})
+}
+
+func TestNewNotifierFromConfigWithManifest_Digest(t *testing.T) {
+	withClearedSlackEnv(t, func() {
+		withClearedGitHubEnv(t, func() {
+			cfg := &config.Config{}
+			cfg.State.Dir = t.TempDir()
+			cfg.Integration.Slack.Token = "test-token"
+			cfg.Integration.Slack.Channel = "#releases"
+			cfg.Integration.Slack.WebhookURL = "https://hooks.example.com/cascade"
+			cfg.Integration.Notifications.Digest = config.NotificationDigestConfig{Window: time.Hour, Channels: []string{"webhook"}}
+
+			notifier := newNotifierFromConfigWithManifest(cfg, nil, &http.Client{}, nil, testLogger{})
+
+			multi, ok := notifier.(*broker.MultiNotifier)
+			if !ok {
+				t.Fatalf("expected multi notifier, got %T", notifier)
+			}
+			if _, ok := notifier.(broker.DigestFlusher); !ok {
+				t.Fatal("expected the notifier to flush digests")
+			}
+			// Only the webhook is held back; nothing is pending yet
+			if sent, err := multi.FlushDigests(context.Background(), true); err != nil || sent != 0 {
+				t.Errorf("FlushDigests() = %d, %v, want nothing pending", sent, err)
+			}
+			if _, ok := withDigest(cfg, broker.NotificationChannelWebhook, broker.NewWebhookNotifier("https://hooks.example.com", nil, broker.DefaultNotificationConfig())).(*broker.DigestNotifier); !ok {
+				t.Error("expected the webhook notifier to be batched")
+			}
+			if _, ok := withDigest(cfg, broker.NotificationChannelSlack, broker.NewSlackNotifier("token", "#releases", nil, broker.DefaultNotificationConfig())).(*broker.SlackNotifier); !ok {
+				t.Error("expected the Slack notifier, which digest.channels does not list, to be left alone")
+			}
+		})
+	})
+}
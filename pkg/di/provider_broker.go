@@ -4,6 +4,9 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/url"
+	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
@@ -12,6 +15,9 @@ import (
 
 	"github.com/goliatone/cascade/internal/broker"
 	"github.com/goliatone/cascade/pkg/config"
+	"github.com/goliatone/cascade/pkg/githubx"
+	"github.com/goliatone/cascade/pkg/repometa"
+	"github.com/goliatone/cascade/pkg/retry"
 )
 
 // provideBroker creates a default broker implementation.
@@ -21,17 +27,18 @@ func provideBroker() broker.Broker {
 }
 
 // provideBrokerWithConfig creates a broker implementation configured from config.
-// Returns a real broker with GitHub provider and Slack notifier if credentials are available.
+// Returns a real broker with GitHub, GitLab, and/or Bitbucket providers and Slack notifier if credentials are available.
 // For dry-run operations, returns a stub broker with clear warnings.
-// For production operations (release/resume/revert), fails fast if GitHub credentials are missing.
+// For production operations (release/resume/revert), fails fast if no GitHub, GitLab, or Bitbucket credentials are configured.
 func provideBrokerWithConfig(cfg *config.Config, httpClient *http.Client, logger Logger) broker.Broker {
-	return provideBrokerWithConfigAndManifest(cfg, nil, httpClient, logger)
+	return provideBrokerWithConfigAndManifest(cfg, nil, httpClient, nil, logger)
 }
 
 // provideBrokerWithConfigAndManifest creates a broker with optional manifest notification settings.
 // If manifestNotifications is provided, it will be used as a fallback for notification configuration
 // when global config doesn't specify notification targets.
-func provideBrokerWithConfigAndManifest(cfg *config.Config, manifestNotifications *ManifestNotifications, httpClient *http.Client, logger Logger) broker.Broker {
+// Repository metadata lookups go through metadata, which may be nil.
+func provideBrokerWithConfigAndManifest(cfg *config.Config, manifestNotifications *ManifestNotifications, httpClient *http.Client, metadata *repometa.Service, logger Logger) broker.Broker {
 	if cfg == nil {
 		logger.Warn("No configuration provided, using stub broker")
 		return broker.NewStub()
@@ -42,32 +49,35 @@ func provideBrokerWithConfigAndManifest(cfg *config.Config, manifestNotification
 		return broker.NewStub()
 	}
 
-	provider, err := newGitHubProviderFromConfig(cfg, httpClient, logger)
+	monitor := newRateLimitMonitor(cfg, logger)
+
+	provider, err := newPullRequestProvider(cfg, httpClient, monitor, metadata, logger)
 	if err != nil {
-		logger.Error("Failed to initialize GitHub provider", "error", err)
+		logger.Error("Failed to initialize pull request provider", "error", err)
 		return broker.NewStub()
 	}
 
-	notifier := newNotifierFromConfigWithManifest(cfg, manifestNotifications, httpClient, logger)
+	notifier := newNotifierFromConfigWithManifest(cfg, manifestNotifications, withRequestLogging(httpClient, logger, true), monitor, logger)
+	attachRateLimitAlerter(monitor, notifier, logger)
 
-	brokerCfg := broker.DefaultConfig()
-	brokerCfg.DryRun = cfg.Executor.DryRun
+	brokerCfg := BrokerConfig(cfg)
 
 	return broker.New(provider, notifier, brokerCfg, logger)
 }
 
 // provideBrokerForProduction creates a broker implementation for production commands.
-// Unlike provideBrokerWithConfig, this function returns an error if GitHub credentials
+// Unlike provideBrokerWithConfig, this function returns an error if provider credentials
 // are missing and dry-run is not enabled, preventing production commands from running
 // with a stub broker.
 func provideBrokerForProduction(cfg *config.Config, httpClient *http.Client, logger Logger) (broker.Broker, error) {
-	return provideBrokerForProductionWithManifest(cfg, nil, httpClient, logger)
+	return provideBrokerForProductionWithManifest(cfg, nil, httpClient, nil, logger)
 }
 
 // provideBrokerForProductionWithManifest creates a production broker with optional manifest notification settings.
 // If manifestNotifications is provided, it will be used as a fallback for notification configuration
 // when global config doesn't specify notification targets.
-func provideBrokerForProductionWithManifest(cfg *config.Config, manifestNotifications *ManifestNotifications, httpClient *http.Client, logger Logger) (broker.Broker, error) {
+// Repository metadata lookups go through metadata, which may be nil.
+func provideBrokerForProductionWithManifest(cfg *config.Config, manifestNotifications *ManifestNotifications, httpClient *http.Client, metadata *repometa.Service, logger Logger) (broker.Broker, error) {
 	if cfg == nil {
 		return nil, fmt.Errorf("configuration is required for production broker")
 	}
@@ -77,20 +87,156 @@ func provideBrokerForProductionWithManifest(cfg *config.Config, manifestNotifica
 		return broker.NewStub(), nil
 	}
 
-	provider, err := newGitHubProviderFromConfig(cfg, httpClient, logger)
+	monitor := newRateLimitMonitor(cfg, logger)
+
+	provider, err := newPullRequestProvider(cfg, httpClient, monitor, metadata, logger)
 	if err != nil {
-		return nil, fmt.Errorf("production commands require GitHub credentials: %w\n\nTo fix this issue:\n  1. Set CASCADE_GITHUB_TOKEN environment variable, or\n  2. Configure integration.github.token in your config file, or\n  3. Use --dry-run flag to test without GitHub integration", err)
+		return nil, fmt.Errorf("production commands require GitHub, GitLab, or Bitbucket credentials: %w\n\nTo fix this issue:\n  1. Set CASCADE_GITHUB_TOKEN (or CASCADE_GITLAB_TOKEN, CASCADE_BITBUCKET_TOKEN) environment variable, or\n  2. Configure integration.github.token (or integration.gitlab.token, integration.bitbucket.token) in your config file, or\n  3. Set integration.provider to sandbox (or CASCADE_PROVIDER=sandbox) to record pull requests locally, or\n  4. Use --dry-run flag to test without GitHub integration", err)
 	}
 
-	notifier := newNotifierFromConfigWithManifest(cfg, manifestNotifications, httpClient, logger)
+	notifier := newNotifierFromConfigWithManifest(cfg, manifestNotifications, withRequestLogging(httpClient, logger, true), monitor, logger)
+	attachRateLimitAlerter(monitor, notifier, logger)
 
+	brokerCfg := BrokerConfig(cfg)
+
+	return broker.New(provider, notifier, brokerCfg, logger), nil
+}
+
+// BrokerConfig returns the broker defaults adjusted by cfg: dry-run, the pull
+// request format, and retries of failed provider calls.
+func BrokerConfig(cfg *config.Config) broker.Config {
 	brokerCfg := broker.DefaultConfig()
+	if cfg == nil {
+		return brokerCfg
+	}
 	brokerCfg.DryRun = cfg.Executor.DryRun
+	if cfg.PullRequests.Format != "" {
+		brokerCfg.PRFormat = cfg.PullRequests.Format
+	}
+	if prefix := strings.TrimSpace(cfg.PullRequests.CommitPrefix); prefix != "" {
+		brokerCfg.CommitPrefix = prefix
+	}
+	brokerCfg.ProviderRetry = retry.Policy{
+		MaxAttempts: cfg.Executor.Retries + 1,
+		Delay:       cfg.Executor.RetryDelay,
+		Jitter:      0.2,
+	}
+	return brokerCfg
+}
 
-	return broker.New(provider, notifier, brokerCfg, logger), nil
+// newProviderFromConfig builds the pull request provider for the configured
+// hosts. With a single token, every dependent goes to that provider. With
+// several, dependents on the GitLab endpoint's host get merge requests, those on
+// bitbucket.org get Bitbucket pull requests, and all others get GitHub pull
+// requests; a dependent's provider setting overrides its host. GitHub requests
+// are reported to monitor, which may be nil.
+func newProviderFromConfig(cfg *config.Config, httpClient *http.Client, monitor *broker.RateLimitMonitor, metadata *repometa.Service, logger Logger) (broker.Provider, error) {
+	gitlab := newGitLabProviderFromConfig(cfg, withRequestLogging(httpClient, logger, false), logger)
+	bitbucket := newBitbucketProviderFromConfig(cfg, withRequestLogging(httpClient, logger, false), logger)
+	github, err := newGitHubProviderFromConfig(cfg, withRequestLogging(withRateLimitMonitor(httpClient, monitor), logger, false), metadata, logger)
+
+	routes := map[string]broker.Provider{}
+	var named []broker.RoutingOption
+	if gitlab != nil {
+		routes[gitLabHost(cfg.Integration.GitLab.Endpoint)] = gitlab
+		named = append(named, broker.WithNamedProvider(broker.ProviderGitLab, gitlab))
+	}
+	if bitbucket != nil {
+		routes[broker.BitbucketHost] = bitbucket
+		named = append(named, broker.WithNamedProvider(broker.ProviderBitbucket, bitbucket))
+	}
+
+	if len(routes) == 0 {
+		return github, err
+	}
+
+	fallback := github
+	if err == nil {
+		named = append(named, broker.WithNamedProvider(broker.ProviderGitHub, github))
+	} else {
+		// Without GitHub, GitLab takes repositories on other hosts, or the only
+		// provider configured takes them all
+		if fallback = gitlab; fallback == nil {
+			fallback = bitbucket
+		}
+		if len(routes) == 1 {
+			logger.Debug("GitHub token not configured; opening pull requests on one provider for all dependents")
+			return fallback, nil
+		}
+	}
+
+	for host := range routes {
+		logger.Debug("Routing dependents by git host", "host", host)
+	}
+	return broker.NewRoutingProvider(fallback, config.RepoURLs(cfg).DefaultHost(), routes, named...), nil
 }
 
-func newGitHubProviderFromConfig(cfg *config.Config, baseHTTP *http.Client, logger Logger) (broker.Provider, error) {
+// newGitLabProviderFromConfig returns a GitLab provider, or nil when no GitLab
+// token is configured.
+func newGitLabProviderFromConfig(cfg *config.Config, baseHTTP *http.Client, logger Logger) broker.Provider {
+	token := strings.TrimSpace(cfg.Integration.GitLab.Token)
+	if token == "" {
+		return nil
+	}
+	endpoint := strings.TrimSpace(cfg.Integration.GitLab.Endpoint)
+	if endpoint != "" {
+		logger.Info("Configured GitLab endpoint", "endpoint", endpoint)
+	}
+	return broker.NewGitLabProvider(token, endpoint, baseHTTP)
+}
+
+// newBitbucketProviderFromConfig returns a Bitbucket provider, or nil when no
+// Bitbucket token is configured.
+func newBitbucketProviderFromConfig(cfg *config.Config, baseHTTP *http.Client, logger Logger) broker.Provider {
+	bb := cfg.Integration.Bitbucket
+	token := strings.TrimSpace(bb.Token)
+	if token == "" {
+		return nil
+	}
+	endpoint := strings.TrimSpace(bb.Endpoint)
+	if endpoint != "" {
+		logger.Info("Configured Bitbucket endpoint", "endpoint", endpoint)
+	}
+	return broker.NewBitbucketProvider(bb.Username, token, endpoint, baseHTTP)
+}
+
+// gitLabHost returns the host of a GitLab API endpoint, gitlab.com by default.
+func gitLabHost(endpoint string) string {
+	if strings.TrimSpace(endpoint) == "" {
+		endpoint = broker.DefaultGitLabEndpoint
+	}
+	parsed, err := url.Parse(strings.TrimSpace(endpoint))
+	if err != nil || parsed.Host == "" {
+		return "gitlab.com"
+	}
+	return strings.ToLower(parsed.Hostname())
+}
+
+func newGitHubProviderFromConfig(cfg *config.Config, baseHTTP *http.Client, metadata *repometa.Service, logger Logger) (broker.Provider, error) {
+	ghClient, err := newGitHubClientFromConfig(cfg, baseHTTP, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	labels := cfg.Integration.GitHub.Labels
+	if labels.AutoCreate {
+		logger.Debug("GitHub label auto-creation enabled", "color", labels.Color)
+	}
+
+	return broker.NewGitHubProvider(ghClient,
+		broker.WithLabelPolicy(broker.LabelPolicy{
+			AutoCreate:  labels.AutoCreate,
+			Color:       labels.Color,
+			Description: labels.Description,
+			Colors:      labels.Colors,
+		}),
+		broker.WithRepoMetadata(metadata),
+	), nil
+}
+
+// newGitHubClientFromConfig creates an authenticated GitHub API client for the
+// configured endpoint.
+func newGitHubClientFromConfig(cfg *config.Config, baseHTTP *http.Client, logger Logger) (*github.Client, error) {
 	token := strings.TrimSpace(cfg.Integration.GitHub.Token)
 	if token == "" {
 		if envToken, err := broker.LoadGitHubToken(); err == nil && strings.TrimSpace(envToken) != "" {
@@ -103,7 +249,7 @@ func newGitHubProviderFromConfig(cfg *config.Config, baseHTTP *http.Client, logg
 		return nil, fmt.Errorf("github token not configured; set integration.github.token or CASCADE_GITHUB_TOKEN")
 	}
 
-	oauthClient, err := newGitHubHTTPClient(token, baseHTTP)
+	oauthClient, err := newGitHubHTTPClient(token, baseHTTP, GitHubRateLimitOptions(cfg, logger)...)
 	if err != nil {
 		return nil, err
 	}
@@ -121,10 +267,12 @@ func newGitHubProviderFromConfig(cfg *config.Config, baseHTTP *http.Client, logg
 		logger.Info("Configured GitHub Enterprise endpoint", "base", baseURL, "upload", uploadURL)
 	}
 
-	return broker.NewGitHubProvider(ghClient), nil
+	return ghClient, nil
 }
 
-func newGitHubHTTPClient(token string, base *http.Client) (*http.Client, error) {
+// newGitHubHTTPClient returns a client authenticating with token whose
+// requests wait out GitHub rate limits.
+func newGitHubHTTPClient(token string, base *http.Client, opts ...githubx.Option) (*http.Client, error) {
 	if strings.TrimSpace(token) == "" {
 		return nil, fmt.Errorf("github token is required")
 	}
@@ -145,7 +293,28 @@ func newGitHubHTTPClient(token string, base *http.Client) (*http.Client, error)
 		oauthClient.Jar = base.Jar
 	}
 
-	return oauthClient, nil
+	return githubx.WrapClient(oauthClient, opts...), nil
+}
+
+// GitHubRateLimitOptions configures GitHub clients to retry rate limited
+// requests as integration.github.rate_limit sets, logging every wait when
+// logger is not nil.
+func GitHubRateLimitOptions(cfg *config.Config, logger Logger) []githubx.Option {
+	limits := cfg.Integration.GitHub.RateLimit
+	var opts []githubx.Option
+	if logger != nil {
+		opts = append(opts, githubx.WithWaitHandler(func(w githubx.Wait) {
+			logger.Warn("GitHub rate limit reached; waiting before continuing",
+				"limit", string(w.Kind), "wait", w.Delay.Round(time.Second), "method", w.Method, "path", w.Path)
+		}))
+	}
+	if limits.MaxWait > 0 {
+		opts = append(opts, githubx.WithMaxWait(limits.MaxWait))
+	}
+	if limits.MaxRetries != 0 {
+		opts = append(opts, githubx.WithMaxRetries(limits.MaxRetries))
+	}
+	return opts
 }
 
 func normalizeEnterpriseEndpoints(endpoint string) (string, string) {
@@ -170,20 +339,43 @@ func normalizeEnterpriseEndpoints(endpoint string) (string, string) {
 }
 
 func newNotifierFromConfig(cfg *config.Config, baseClient *http.Client, logger Logger) broker.Notifier {
-	return newNotifierFromConfigWithManifest(cfg, nil, baseClient, logger)
+	return newNotifierFromConfigWithManifest(cfg, nil, baseClient, nil, logger)
 }
 
-func newNotifierFromConfigWithManifest(cfg *config.Config, manifestNotifications *ManifestNotifications, baseClient *http.Client, logger Logger) broker.Notifier {
+// newNotifierFromConfigWithManifest assembles the configured notifiers. GitHub issue
+// requests are reported to monitor, which may be nil.
+func newNotifierFromConfigWithManifest(cfg *config.Config, manifestNotifications *ManifestNotifications, baseClient *http.Client, monitor *broker.RateLimitMonitor, logger Logger) broker.Notifier {
 	notifyCfg := broker.DefaultNotificationConfig()
+	applyNotificationTemplates(&notifyCfg, cfg.Integration.Notifications)
+	notifyCfg.LinkBaseURL = strings.TrimSpace(cfg.UI.BaseURL)
+	if err := notifyCfg.Validate(); err != nil {
+		logger.Warn("Notification templates failed to parse; affected notifications will fail to render", "error", err)
+	}
 	var notifiers []broker.Notifier
+	named := make(map[string]broker.Notifier)
+	add := func(channel string, notifier broker.Notifier) {
+		notifier = withDigest(cfg, channel, notifier)
+		notifiers = append(notifiers, notifier)
+		named[channel] = notifier
+	}
 
 	var githubDefaults *broker.GitHubIssueConfig
 	if manifestNotifications != nil && manifestNotifications.GitHubIssues != nil {
+		issues := manifestNotifications.GitHubIssues
 		githubDefaults = &broker.GitHubIssueConfig{
-			Enabled: manifestNotifications.GitHubIssues.Enabled,
+			Enabled:       issues.Enabled,
+			TitleTemplate: issues.TitleTemplate,
+			BodyTemplate:  issues.BodyTemplate,
+			OnRecurrence:  issues.OnRecurrence,
+		}
+		if len(issues.Labels) > 0 {
+			githubDefaults.Labels = append([]string(nil), issues.Labels...)
+		}
+		if len(issues.Assignees) > 0 {
+			githubDefaults.Assignees = append([]string(nil), issues.Assignees...)
 		}
-		if len(manifestNotifications.GitHubIssues.Labels) > 0 {
-			githubDefaults.Labels = append([]string(nil), manifestNotifications.GitHubIssues.Labels...)
+		if err := githubDefaults.Validate(); err != nil {
+			logger.Warn("GitHub issue templates failed to parse; issue notifications will fail to render", "error", err)
 		}
 	}
 
@@ -201,7 +393,7 @@ func newNotifierFromConfigWithManifest(cfg *config.Config, manifestNotifications
 
 	if slackToken != "" && slackChannel != "" {
 		client := cloneHTTPClient(baseClient, notifyCfg.Timeout)
-		notifiers = append(notifiers, broker.NewSlackNotifier(slackToken, slackChannel, client, notifyCfg))
+		add(broker.NotificationChannelSlack, broker.NewSlackNotifier(slackToken, slackChannel, client, notifyCfg))
 	} else if slackToken != "" || slackChannel != "" {
 		logger.Warn("Slack integration requires both token and channel; skipping Slack notifier")
 	}
@@ -217,7 +409,7 @@ func newNotifierFromConfigWithManifest(cfg *config.Config, manifestNotifications
 
 	if webhook != "" {
 		client := cloneHTTPClient(baseClient, notifyCfg.Timeout)
-		notifiers = append(notifiers, broker.NewWebhookNotifier(webhook, client, notifyCfg))
+		add(broker.NotificationChannelWebhook, broker.NewWebhookNotifier(webhook, client, notifyCfg))
 	}
 
 	// Configure GitHub issue notifications if credentials are available
@@ -232,8 +424,13 @@ func newNotifierFromConfigWithManifest(cfg *config.Config, manifestNotifications
 		}
 	}
 
-	if githubToken != "" {
-		oauthClient, err := newGitHubHTTPClient(githubToken, baseClient)
+	if sandboxEnabled(cfg) {
+		// Issues would be opened on the real dependent repositories
+		if githubToken != "" || (githubDefaults != nil && githubDefaults.Enabled) {
+			logger.Debug("Sandbox provider enabled; skipping GitHub issue notifier")
+		}
+	} else if githubToken != "" {
+		oauthClient, err := newGitHubHTTPClient(githubToken, withRateLimitMonitor(baseClient, monitor), GitHubRateLimitOptions(cfg, logger)...)
 		if err != nil {
 			logger.Error("Failed to initialize GitHub HTTP client for issue notifications", "error", err)
 		} else {
@@ -250,19 +447,34 @@ func newNotifierFromConfigWithManifest(cfg *config.Config, manifestNotifications
 			}
 
 			if err == nil && ghClient != nil {
-				notifiers = append(notifiers, broker.NewGitHubIssueNotifier(ghClient.Issues, githubDefaults))
+				add(broker.NotificationChannelGitHubIssues, broker.NewGitHubIssueNotifier(ghClient.Issues, githubDefaults, broker.WithIssueLinkBaseURL(notifyCfg.LinkBaseURL)))
 			}
 		}
 	} else if githubDefaults != nil && githubDefaults.Enabled {
 		logger.Warn("GitHub issue notifications enabled but GitHub token not configured; skipping GitHub issue notifier")
 	}
 
+	if routingKey := strings.TrimSpace(cfg.Integration.PagerDuty.RoutingKey); routingKey != "" {
+		client := cloneHTTPClient(baseClient, notifyCfg.Timeout)
+		add(broker.NotificationChannelPagerDuty, broker.NewPagerDutyNotifier(broker.PagerDutyConfig{
+			RoutingKey: routingKey,
+			Endpoint:   cfg.Integration.PagerDuty.Endpoint,
+			Severity:   cfg.Integration.PagerDuty.Severity,
+		}, client, notifyCfg))
+	}
+
+	if sandboxEnabled(cfg) {
+		add(broker.NotificationChannelSandbox, broker.NewSandboxNotifier(filepath.Join(sandboxDir(cfg), "notifications.jsonl"), notifyCfg))
+	}
+
 	var baseNotifier broker.Notifier
-	switch len(notifiers) {
-	case 0:
+	switch {
+	case len(cfg.Integration.Notifications.Escalation) > 0:
+		baseNotifier = newEscalatingNotifier(cfg.Integration.Notifications.Escalation, named, logger)
+	case len(notifiers) == 0:
 		logger.Info("No notification integrations configured, using no-op notifier")
 		baseNotifier = broker.NewNoOpNotifier()
-	case 1:
+	case len(notifiers) == 1:
 		baseNotifier = notifiers[0]
 	default:
 		baseNotifier = broker.NewMultiNotifier(notifiers...)
@@ -276,6 +488,103 @@ func newNotifierFromConfigWithManifest(cfg *config.Config, manifestNotifications
 	return baseNotifier
 }
 
+// newEscalatingNotifier selects among the named notifiers by the escalation steps,
+// warning about channels no notifier is configured for.
+func newEscalatingNotifier(escalation []config.EscalationStepConfig, named map[string]broker.Notifier, logger Logger) broker.Notifier {
+	steps := make([]broker.EscalationStep, 0, len(escalation))
+	missing := make(map[string]bool)
+	for _, step := range escalation {
+		for _, channel := range step.Channels {
+			if _, ok := named[channel]; !ok && !missing[channel] {
+				missing[channel] = true
+				logger.Warn("Escalation step uses a channel that is not configured; it will be skipped", "channel", channel, "attempt", step.Attempt)
+			}
+		}
+		steps = append(steps, broker.EscalationStep{
+			Attempt:   step.Attempt,
+			Channels:  append([]string(nil), step.Channels...),
+			Assignees: append([]string(nil), step.Assignees...),
+		})
+	}
+	logger.Debug("Failure escalation enabled", "steps", len(steps))
+	return broker.NewEscalatingNotifier(named, steps)
+}
+
+// newRateLimitMonitor returns the run's GitHub API usage monitor, or nil when no
+// rate limit alert thresholds are configured.
+func newRateLimitMonitor(cfg *config.Config, logger Logger) *broker.RateLimitMonitor {
+	monitor := broker.NewRateLimitMonitor(cfg.Integration.GitHub.RateLimitAlerts, logger)
+	if monitor != nil {
+		logger.Debug("GitHub rate limit alerts enabled", "thresholds", cfg.Integration.GitHub.RateLimitAlerts)
+	}
+	return monitor
+}
+
+// withRateLimitMonitor returns a copy of base whose responses are reported to
+// monitor. base is returned unchanged when monitor is nil.
+func withRateLimitMonitor(base *http.Client, monitor *broker.RateLimitMonitor) *http.Client {
+	if monitor == nil {
+		return base
+	}
+	clone := &http.Client{}
+	if base != nil {
+		*clone = *base
+	}
+	clone.Transport = broker.NewRateLimitTransport(clone.Transport, monitor)
+	return clone
+}
+
+// withRequestLogging returns a copy of base whose requests carry a correlation ID
+// and are logged at debug level. hostOnly drops the URL path from log lines, for
+// clients that post to webhook URLs with embedded secrets.
+func withRequestLogging(base *http.Client, logger Logger, hostOnly bool) *http.Client {
+	clone := &http.Client{}
+	if base != nil {
+		*clone = *base
+	}
+	clone.Transport = broker.NewCorrelationTransport(clone.Transport, logger, hostOnly)
+	return clone
+}
+
+// attachRateLimitAlerter routes rate limit alerts through the notifier when it can
+// deliver them; otherwise threshold crossings are only logged.
+func attachRateLimitAlerter(monitor *broker.RateLimitMonitor, notifier broker.Notifier, logger Logger) {
+	if monitor == nil {
+		return
+	}
+	alerter, ok := notifier.(broker.Alerter)
+	if !ok {
+		logger.Warn("Rate limit alerts configured but no Slack or webhook notifier is available; threshold crossings will only be logged")
+		return
+	}
+	monitor.SetAlerter(alerter)
+}
+
+// applyNotificationTemplates copies the configured per-status and per-channel
+// notification templates onto the broker notification config.
+func applyNotificationTemplates(dst *broker.NotificationConfig, src config.NotificationTemplatesConfig) {
+	dst.Template = src.Template
+	if len(src.Templates) > 0 {
+		dst.Templates.Status = make(map[string]string, len(src.Templates))
+		for status, tmpl := range src.Templates {
+			dst.Templates.Status[status] = tmpl
+		}
+	}
+	if len(src.Channels) > 0 {
+		dst.Templates.Channels = make(map[string]broker.ChannelTemplates, len(src.Channels))
+		for name, channel := range src.Channels {
+			templates := broker.ChannelTemplates{Default: channel.Template}
+			if len(channel.Templates) > 0 {
+				templates.Status = make(map[string]string, len(channel.Templates))
+				for status, tmpl := range channel.Templates {
+					templates.Status[status] = tmpl
+				}
+			}
+			dst.Templates.Channels[name] = templates
+		}
+	}
+}
+
 // ManifestNotifications holds notification settings from manifest defaults.
 // This allows manifest-level notification configuration to be used when
 // global config doesn't specify notification targets.
@@ -289,14 +598,18 @@ type ManifestNotifications struct {
 
 // ManifestGitHubIssues captures default GitHub issue configuration from the manifest.
 type ManifestGitHubIssues struct {
-	Enabled bool
-	Labels  []string
+	Enabled       bool
+	Labels        []string
+	TitleTemplate string
+	BodyTemplate  string
+	Assignees     []string
+	OnRecurrence  string
 }
 
 func cloneHTTPClient(base *http.Client, timeout time.Duration) *http.Client {
 	if base == nil {
 		client := &http.Client{Timeout: timeout}
-		client.Transport = newHeaderRoundTripper(nil, defaultHTTPHeaders(nil))
+		client.Transport = newHeaderRoundTripper(nil, defaultHTTPHeaders(), requestTagHeaders(nil))
 		return client
 	}
 	clone := *base
@@ -304,7 +617,34 @@ func cloneHTTPClient(base *http.Client, timeout time.Duration) *http.Client {
 		clone.Timeout = timeout
 	}
 	if clone.Transport == nil {
-		clone.Transport = newHeaderRoundTripper(nil, defaultHTTPHeaders(nil))
+		clone.Transport = newHeaderRoundTripper(nil, defaultHTTPHeaders(), requestTagHeaders(nil))
 	}
 	return &clone
 }
+
+// withDigest batches the notifications of channel into digests when
+// integration.notifications.digest selects it. Pending notifications are kept
+// in the notifications directory of the state directory.
+func withDigest(cfg *config.Config, channel string, notifier broker.Notifier) broker.Notifier {
+	digest := cfg.Integration.Notifications.Digest
+	if digest.Window <= 0 {
+		return notifier
+	}
+	if len(digest.Channels) > 0 && !slices.Contains(digest.Channels, channel) {
+		return notifier
+	}
+	sender, ok := notifier.(broker.DigestSender)
+	if !ok {
+		return notifier
+	}
+	return broker.NewDigestNotifier(sender, channel, digestPath(cfg, channel), digest.Window)
+}
+
+// digestPath returns the file the pending digest of channel is kept in.
+func digestPath(cfg *config.Config, channel string) string {
+	stateDir := cfg.State.Dir
+	if stateDir == "" {
+		stateDir = getDefaultStateDir()
+	}
+	return filepath.Join(stateDir, "notifications", "digest-"+channel+".json")
+}
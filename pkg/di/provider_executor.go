@@ -11,23 +11,30 @@ func provideExecutor() executor.Executor {
 	return executor.New()
 }
 
-// provideExecutorWithConfig creates an executor with configuration-driven timeouts and settings.
-// The executor implementation itself doesn't need config changes, but this documents the intent
-// for future expansion when we add timeout configuration.
+// provideExecutorWithConfig creates an executor that applies the configured
+// command timeout, caps concurrent work items at the configured limit, retries
+// network-bound steps as configured, builds clone URLs for the configured
+// git hosts, and writes commit messages in the configured pull request format.
 func provideExecutorWithConfig(cfg *config.Config, logger Logger) executor.Executor {
 	if cfg == nil {
 		logger.Warn("No configuration provided, using default executor")
 		return executor.New()
 	}
 
-	// The current executor implementation doesn't take configuration,
-	// but we can log the intended timeout settings
-	if cfg.Executor.Timeout > 0 {
-		logger.Debug("Executor configured with timeout", "timeout", cfg.Executor.Timeout)
+	logger.Debug("Executor configured",
+		"timeout", cfg.Executor.Timeout,
+		"concurrent_limit", cfg.Executor.ConcurrentLimit,
+		"retries", cfg.Executor.Retries,
+		"retry_delay", cfg.Executor.RetryDelay)
+
+	opts := []executor.Option{
+		executor.WithDefaultTimeout(cfg.Executor.Timeout),
+		executor.WithConcurrentLimit(cfg.Executor.ConcurrentLimit),
+		executor.WithRetry(cfg.Executor.Retries, cfg.Executor.RetryDelay),
+		executor.WithRepoURLs(config.RepoURLs(cfg)),
 	}
-	if cfg.Executor.ConcurrentLimit > 0 {
-		logger.Debug("Executor configured with concurrency limit", "limit", cfg.Executor.ConcurrentLimit)
+	if cfg.PullRequests.Format == config.PRFormatDependabot {
+		opts = append(opts, executor.WithDependabotCommits(cfg.PullRequests.CommitPrefix))
 	}
-
-	return executor.New()
+	return executor.New(opts...)
 }
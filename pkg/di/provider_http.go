@@ -7,19 +7,24 @@ import (
 	"strings"
 	"time"
 
+	"github.com/goliatone/cascade/internal/telemetry"
 	"github.com/goliatone/cascade/pkg/config"
 )
 
 const (
 	defaultUserAgent = "cascade-cli (+https://github.com/goliatone/cascade)"
 	defaultAccept    = "application/json"
+
+	// orgHeader carries the organization requests are attributed to.
+	orgHeader = "X-Cascade-Org"
 )
 
 // provideHTTPClient creates a default HTTP client implementation.
-// Configured with reasonable defaults for API calls and timeouts.
+// Configured with reasonable defaults for API calls and timeouts. Requests are
+// recorded in the API request metrics and traces.
 func provideHTTPClient() *http.Client {
 	return &http.Client{
-		Transport: newHeaderRoundTripper(nil, defaultHTTPHeaders(nil)),
+		Transport: telemetry.NewTransport(newHeaderRoundTripper(nil, defaultHTTPHeaders(), requestTagHeaders(nil))),
 	}
 }
 
@@ -42,44 +47,83 @@ func provideHTTPClientWithConfig(cfg *config.Config) *http.Client {
 
 	return &http.Client{
 		Timeout:   timeout,
-		Transport: newHeaderRoundTripper(nil, defaultHTTPHeaders(cfg)),
+		Transport: telemetry.NewTransport(newHeaderRoundTripper(nil, defaultHTTPHeaders(), requestTagHeaders(cfg))),
 	}
 }
 
-func defaultHTTPHeaders(cfg *config.Config) http.Header {
+// defaultHTTPHeaders returns headers added to requests that do not set them.
+func defaultHTTPHeaders() http.Header {
 	headers := make(http.Header)
-	userAgent := buildUserAgent(cfg)
-	headers.Set("User-Agent", userAgent)
 	headers.Set("Accept", defaultAccept)
 	return headers
 }
 
+// requestTagHeaders returns the headers that identify cascade on every
+// request: the User-Agent, the org, and integration.requests.headers. They
+// replace values set by client libraries, such as go-github's User-Agent, so
+// egress proxies see the same tags on every provider and notifier call.
+func requestTagHeaders(cfg *config.Config) http.Header {
+	headers := make(http.Header)
+	if cfg != nil {
+		for name, value := range cfg.Integration.Requests.Headers {
+			headers.Set(name, value)
+		}
+	}
+	if org := requestOrg(cfg); org != "" {
+		headers.Set(orgHeader, org)
+	}
+	headers.Set("User-Agent", buildUserAgent(cfg))
+	return headers
+}
+
+// requestOrg returns the organization requests are attributed to:
+// integration.requests.org, or the GitHub organization.
+func requestOrg(cfg *config.Config) string {
+	if cfg == nil {
+		return ""
+	}
+	if org := strings.TrimSpace(cfg.Integration.Requests.Org); org != "" {
+		return org
+	}
+	return strings.TrimSpace(cfg.Integration.GitHub.Organization)
+}
+
 func buildUserAgent(cfg *config.Config) string {
 	userAgent := defaultUserAgent
+	if org := requestOrg(cfg); org != "" {
+		userAgent = fmt.Sprintf("%s org/%s", defaultUserAgent, org)
+	}
+	userAgent = fmt.Sprintf("%s go/%s %s/%s", userAgent, runtime.Version(), runtime.GOOS, runtime.GOARCH)
 	if cfg != nil {
-		if org := strings.TrimSpace(cfg.Integration.GitHub.Organization); org != "" {
-			userAgent = fmt.Sprintf("%s org/%s", defaultUserAgent, org)
+		if product := strings.TrimSpace(cfg.Integration.Requests.UserAgent); product != "" {
+			userAgent = product + " " + userAgent
 		}
 	}
-	return fmt.Sprintf("%s go/%s %s/%s", userAgent, runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	return userAgent
 }
 
 type headerRoundTripper struct {
-	base    http.RoundTripper
-	headers http.Header
+	base     http.RoundTripper
+	headers  http.Header
+	override http.Header
 }
 
-func newHeaderRoundTripper(base http.RoundTripper, headers http.Header) http.RoundTripper {
+// newHeaderRoundTripper wraps base so requests carry headers when they do not
+// set them, and always carry override.
+func newHeaderRoundTripper(base http.RoundTripper, headers, override http.Header) http.RoundTripper {
 	if headers == nil {
 		headers = make(http.Header)
 	}
+	if override == nil {
+		override = make(http.Header)
+	}
 	var underlying http.RoundTripper = http.DefaultTransport
 	if base != nil {
 		underlying = base
 	} else if transport, ok := http.DefaultTransport.(*http.Transport); ok {
 		underlying = transport.Clone()
 	}
-	return &headerRoundTripper{base: underlying, headers: headers}
+	return &headerRoundTripper{base: underlying, headers: headers, override: override}
 }
 
 func (h *headerRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
@@ -95,5 +139,8 @@ func (h *headerRoundTripper) RoundTrip(req *http.Request) (*http.Response, error
 			clone.Header.Add(key, value)
 		}
 	}
+	for key, values := range h.override {
+		clone.Header[key] = append([]string(nil), values...)
+	}
 	return h.base.RoundTrip(clone)
 }
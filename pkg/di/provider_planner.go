@@ -6,6 +6,7 @@ import (
 
 	"github.com/goliatone/cascade/internal/planner"
 	"github.com/goliatone/cascade/pkg/config"
+	"github.com/goliatone/cascade/pkg/repometa"
 )
 
 // providePlanner creates a default planner implementation.
@@ -17,13 +18,21 @@ func providePlanner() planner.Planner {
 // providePlannerWithConfig creates a planner with configuration-driven dependency checking.
 // When SkipUpToDate is enabled (and ForceAll is false), the planner checks if dependents
 // already have the target dependency version and skips them if no update is needed.
-func providePlannerWithConfig(cfg *config.Config, logger Logger) planner.Planner {
+// A non-nil metadata service enables the repository preflight, and a non-nil
+// history enables the runtime estimate in plan stats.
+func providePlannerWithConfig(cfg *config.Config, metadata *repometa.Service, history planner.TimingHistory, logger Logger) planner.Planner {
 	if cfg == nil {
 		logger.Warn("No configuration provided, using default planner")
 		return planner.New()
 	}
 
-	opts := []planner.Option{}
+	opts := []planner.Option{planner.WithConcurrency(cfg.Executor.ConcurrentLimit)}
+	if metadata != nil {
+		opts = append(opts, planner.WithRepoMetadata(metadata))
+	}
+	if history != nil {
+		opts = append(opts, planner.WithTimingHistory(history))
+	}
 
 	// Only enable dependency checking if SkipUpToDate is true and ForceAll is false
 	if cfg.Executor.SkipUpToDate && !cfg.Executor.ForceAll {
@@ -54,21 +63,27 @@ func providePlannerWithConfig(cfg *config.Config, logger Logger) planner.Planner
 			CacheTTL:       cacheTTL,
 			ParallelChecks: parallel,
 			Timeout:        timeout,
+			Retries:        cfg.Executor.Retries,
+			RetryDelay:     cfg.Executor.RetryDelay,
+			RepoURLs:       config.RepoURLs(cfg),
 		}
 
-		// Create checkers based on strategy
-		var checker planner.DependencyChecker
-		localChecker := planner.NewDependencyChecker(logger)
+		// Create checkers based on strategy. Every strategy is routed through the
+		// hybrid checker so dependents can override check_strategy and
+		// check_cache_ttl in the manifest; the global strategy only applies when a
+		// dependent leaves those fields unset.
+		workspaceLayout := config.WorkspaceLayout(cfg)
+		localChecker := planner.NewDependencyChecker(logger, planner.WithCheckerLayout(workspaceLayout))
 		remoteChecker := planner.NewRemoteDependencyChecker(checkOpts, logger)
 
-		switch checkOpts.Strategy {
+		globalStrategy := checkOpts.Strategy
+		switch globalStrategy {
 		case planner.CheckStrategyLocal:
 			if cfg.Workspace.Path == "" {
 				logger.Warn("Local strategy requested but workspace path not configured, using remote")
-				checker = remoteChecker
+				globalStrategy = planner.CheckStrategyRemote
 			} else {
 				logger.Debug("Using local dependency checking", "workspace", cfg.Workspace.Path)
-				checker = localChecker
 			}
 
 		case planner.CheckStrategyRemote:
@@ -76,7 +91,6 @@ func providePlannerWithConfig(cfg *config.Config, logger Logger) planner.Planner
 				"cache_ttl", cacheTTL,
 				"parallel", parallel,
 				"timeout", timeout)
-			checker = remoteChecker
 
 		case planner.CheckStrategyAuto:
 			logger.Debug("Using auto dependency checking (local with remote fallback)",
@@ -84,25 +98,20 @@ func providePlannerWithConfig(cfg *config.Config, logger Logger) planner.Planner
 				"cache_ttl", cacheTTL,
 				"parallel", parallel,
 				"timeout", timeout)
-			checker = planner.NewHybridDependencyChecker(
-				localChecker,
-				remoteChecker,
-				checkOpts.Strategy,
-				cfg.Workspace.Path,
-				logger,
-			)
 
 		default:
 			logger.Warn("Unknown check strategy, using auto", "strategy", strategy)
-			checker = planner.NewHybridDependencyChecker(
-				localChecker,
-				remoteChecker,
-				planner.CheckStrategyAuto,
-				cfg.Workspace.Path,
-				logger,
-			)
+			globalStrategy = planner.CheckStrategyAuto
 		}
 
+		var checker planner.DependencyChecker = planner.NewHybridDependencyChecker(
+			localChecker,
+			remoteChecker,
+			globalStrategy,
+			cfg.Workspace.Path,
+			logger,
+		)
+
 		// Wrap in parallel checker if concurrency > 1
 		if checkOpts.ParallelChecks > 1 {
 			logger.Debug("Enabling parallel dependency checking", "concurrency", checkOpts.ParallelChecks)
@@ -115,7 +124,8 @@ func providePlannerWithConfig(cfg *config.Config, logger Logger) planner.Planner
 
 		opts = append(opts,
 			planner.WithDependencyChecker(checker),
-			planner.WithWorkspace(cfg.Workspace.Path))
+			planner.WithWorkspace(cfg.Workspace.Path),
+			planner.WithWorkspaceLayout(workspaceLayout))
 	} else if cfg.Executor.ForceAll {
 		logger.Debug("ForceAll enabled, processing all dependents without version checking")
 	} else {
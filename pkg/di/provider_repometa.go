@@ -0,0 +1,25 @@
+package di
+
+import (
+	"net/http"
+
+	"github.com/goliatone/cascade/pkg/config"
+	"github.com/goliatone/cascade/pkg/repometa"
+)
+
+// provideRepoMetadataWithConfig creates the repository metadata cache shared by the
+// planner preflight, the broker, and GitHub discovery. It returns nil when no GitHub
+// token is available, which disables metadata lookups.
+func provideRepoMetadataWithConfig(cfg *config.Config, httpClient *http.Client, logger Logger) *repometa.Service {
+	if cfg == nil {
+		return nil
+	}
+
+	client, err := newGitHubClientFromConfig(cfg, withRequestLogging(httpClient, logger, false), logger)
+	if err != nil {
+		logger.Debug("Repository metadata lookups disabled", "reason", err.Error())
+		return nil
+	}
+
+	return repometa.NewService(repometa.NewGitHubFetcher(client), repometa.WithTTL(cfg.Executor.CheckCacheTTL))
+}
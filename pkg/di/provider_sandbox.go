@@ -0,0 +1,58 @@
+package di
+
+import (
+	"net/http"
+	"path/filepath"
+
+	"github.com/goliatone/cascade/internal/broker"
+	"github.com/goliatone/cascade/pkg/config"
+	"github.com/goliatone/cascade/pkg/repometa"
+)
+
+// sandboxEnabled reports whether the configuration replaces the git host
+// providers with the sandbox provider.
+func sandboxEnabled(cfg *config.Config) bool {
+	return cfg != nil && cfg.Integration.Provider == config.ProviderSandbox
+}
+
+// sandboxDir returns the directory sandbox pull requests and notifications are
+// recorded in: the sandbox directory of the state directory.
+func sandboxDir(cfg *config.Config) string {
+	stateDir := ""
+	if cfg != nil {
+		stateDir = cfg.State.Dir
+	}
+	if stateDir == "" {
+		stateDir = getDefaultStateDir()
+	}
+	return filepath.Join(stateDir, "sandbox")
+}
+
+// newPullRequestProvider returns the sandbox provider when the configuration
+// selects it. Otherwise it returns the configured git host providers, with
+// dependents whose provider setting is sandbox sent to the sandbox.
+func newPullRequestProvider(cfg *config.Config, httpClient *http.Client, monitor *broker.RateLimitMonitor, metadata *repometa.Service, logger Logger) (broker.Provider, error) {
+	sandbox := broker.NewSandboxProvider(sandboxDir(cfg))
+	if sandboxEnabled(cfg) {
+		logger.Info("Sandbox provider enabled; pull requests are recorded locally", "dir", sandboxDir(cfg))
+		return sandbox, nil
+	}
+
+	provider, err := newProviderFromConfig(cfg, httpClient, monitor, metadata, logger)
+	if err != nil {
+		return nil, err
+	}
+	return withSandboxProvider(provider, sandbox), nil
+}
+
+// withSandboxProvider routes requests for dependents whose provider setting is
+// sandbox to sandbox, and all others to provider, which resolves the github,
+// gitlab, and bitbucket settings itself.
+func withSandboxProvider(provider, sandbox broker.Provider) broker.Provider {
+	return broker.NewRoutingProvider(provider, "", nil,
+		broker.WithNamedProvider(broker.ProviderGitHub, provider),
+		broker.WithNamedProvider(broker.ProviderGitLab, provider),
+		broker.WithNamedProvider(broker.ProviderBitbucket, provider),
+		broker.WithNamedProvider(broker.ProviderSandbox, sandbox),
+	)
+}
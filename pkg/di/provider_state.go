@@ -1,11 +1,15 @@
 package di
 
 import (
+	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/goliatone/cascade/internal/state"
 	"github.com/goliatone/cascade/pkg/config"
+	"golang.org/x/oauth2"
 )
 
 // provideState creates a default state manager implementation.
@@ -25,10 +29,7 @@ func provideStateWithConfig(cfg *config.Config, logger Logger) state.Manager {
 
 	// Apply defaults for state configuration if not explicitly set by user.
 	// This ensures state persistence is enabled by default as documented.
-	stateDir := cfg.State.Dir
-	if stateDir == "" {
-		stateDir = getDefaultStateDir()
-	}
+	stateDir := StateDir(cfg)
 
 	// Only disable state if user explicitly disabled it.
 	// If Enabled is false but wasn't explicitly set, enable it (default behavior).
@@ -38,17 +39,17 @@ func provideStateWithConfig(cfg *config.Config, logger Logger) state.Manager {
 		return state.NewManager()
 	}
 
-	// Create filesystem storage
-	stateStorage, err := state.NewFilesystemStorage(stateDir, logger)
+	stateStorage, err := provideStateStorage(cfg.State, stateDir, logger)
 	if err != nil {
-		logger.Error("Failed to create filesystem storage, using nop state manager", "error", err)
+		logger.Error("Failed to create state storage, using nop state manager", "backend", cfg.State.Backend, "error", err)
 		return state.NewManager()
 	}
 
-	// Create filesystem locker
+	// Create filesystem locker. It only guards against concurrent processes on
+	// this machine; remote backends rely on conditional writes across runners.
 	stateLocker := state.NewFilesystemLocker(stateDir, logger)
 
-	logger.Debug("State persistence enabled", "dir", stateDir)
+	logger.Debug("State persistence enabled", "backend", cfg.State.Backend, "dir", stateDir)
 
 	return state.NewManager(
 		state.WithStorage(stateStorage),
@@ -57,6 +58,96 @@ func provideStateWithConfig(cfg *config.Config, logger Logger) state.Manager {
 	)
 }
 
+// RemoteState opens the state backend at rawURL, s3://bucket/prefix or
+// gs://bucket/prefix, for reading only. Writes fail with state.ErrReadOnly and
+// nothing is locked, so read access to the bucket is enough. The endpoint and
+// region of configured are used when it is a backend of the same kind.
+func RemoteState(rawURL string, configured config.StateConfig, logger Logger) (state.Manager, error) {
+	cfg, err := parseStateURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if configured.Backend == cfg.Backend {
+		cfg.Endpoint = configured.Endpoint
+		cfg.Region = configured.Region
+	}
+
+	storage, err := provideStateStorage(cfg, "", logger)
+	if err != nil {
+		return nil, err
+	}
+	return state.NewManager(
+		state.WithStorage(state.ReadOnly(storage)),
+		state.WithLogger(logger),
+	), nil
+}
+
+// parseStateURL returns the state configuration of an s3:// or gs:// URL.
+func parseStateURL(rawURL string) (config.StateConfig, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return config.StateConfig{}, fmt.Errorf("invalid state URL %q: %w", rawURL, err)
+	}
+
+	cfg := config.StateConfig{Bucket: u.Host, Prefix: strings.Trim(u.Path, "/")}
+	switch u.Scheme {
+	case "s3":
+		cfg.Backend = config.StateBackendS3
+	case "gs", "gcs":
+		cfg.Backend = config.StateBackendGCS
+	default:
+		return config.StateConfig{}, fmt.Errorf("state URL %q must start with s3:// or gs://", rawURL)
+	}
+	if cfg.Bucket == "" {
+		return config.StateConfig{}, fmt.Errorf("state URL %q has no bucket", rawURL)
+	}
+	return cfg, nil
+}
+
+// provideStateStorage creates the storage of the configured state backend.
+// Remote backends use the default credential chains of the cloud SDKs: for s3
+// the AWS_* environment variables, shared config profiles, web identity
+// tokens, and IAM roles; for gcs a GOOGLE_OAUTH_ACCESS_TOKEN when it is set,
+// and otherwise Application Default Credentials.
+func provideStateStorage(cfg config.StateConfig, stateDir string, logger Logger) (state.Storage, error) {
+	switch cfg.Backend {
+	case config.StateBackendS3:
+		store, err := state.NewS3Store(state.S3Options{
+			Bucket:   cfg.Bucket,
+			Region:   cfg.Region,
+			Endpoint: cfg.Endpoint,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return state.NewObjectStorage(store, cfg.Prefix, 0, logger), nil
+	case config.StateBackendGCS:
+		opts := state.GCSOptions{
+			Bucket:   cfg.Bucket,
+			Endpoint: cfg.Endpoint,
+		}
+		if static := os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN"); static != "" {
+			opts.TokenSource = oauth2.StaticTokenSource(&oauth2.Token{AccessToken: static})
+		}
+		store, err := state.NewGCSStore(opts)
+		if err != nil {
+			return nil, err
+		}
+		return state.NewObjectStorage(store, cfg.Prefix, 0, logger), nil
+	default:
+		return state.NewFilesystemStorage(stateDir, logger)
+	}
+}
+
+// StateDir returns the directory local state is kept in: state.dir, or the XDG
+// state directory when it is unset.
+func StateDir(cfg *config.Config) string {
+	if cfg != nil && cfg.State.Dir != "" {
+		return cfg.State.Dir
+	}
+	return getDefaultStateDir()
+}
+
 // getDefaultStateDir returns the default state directory following XDG Base Directory spec.
 func getDefaultStateDir() string {
 	// Follow XDG Base Directory specification
@@ -1,11 +1,16 @@
 package di
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/goliatone/cascade/internal/broker"
 	"github.com/goliatone/cascade/internal/executor"
@@ -13,6 +18,8 @@ import (
 	"github.com/goliatone/cascade/internal/planner"
 	"github.com/goliatone/cascade/internal/state"
 	"github.com/goliatone/cascade/pkg/config"
+	"github.com/goliatone/cascade/pkg/githubx"
+	"github.com/goliatone/cascade/pkg/repometa"
 )
 
 type testLogger struct{}
@@ -149,6 +156,174 @@ func TestProvideBrokerWithConfig_WithTokenCreatesProvider(t *testing.T) {
 	})
 }
 
+func TestNewProviderFromConfig_GitLab(t *testing.T) {
+	withClearedGitHubEnv(t, func() {
+		cfg := &config.Config{}
+		cfg.Integration.GitLab.Token = "glpat-token"
+
+		provider, err := newProviderFromConfig(cfg, &http.Client{}, nil, nil, testLogger{})
+		if err != nil {
+			t.Fatalf("newProviderFromConfig() error = %v", err)
+		}
+		if _, ok := provider.(*broker.GitLabProvider); !ok {
+			t.Fatalf("expected a GitLab provider without a GitHub token, got %T", provider)
+		}
+
+		b, err := provideBrokerForProduction(cfg, &http.Client{}, testLogger{})
+		if err != nil || isStubBroker(b) {
+			t.Fatalf("expected a real production broker with only a GitLab token, got %v", err)
+		}
+
+		cfg.Integration.GitHub.Token = "test-token"
+		provider, err = newProviderFromConfig(cfg, &http.Client{}, nil, nil, testLogger{})
+		if err != nil {
+			t.Fatalf("newProviderFromConfig() error = %v", err)
+		}
+		if _, ok := provider.(*broker.RoutingProvider); !ok {
+			t.Fatalf("expected repositories routed by host with both tokens, got %T", provider)
+		}
+	})
+}
+
+func TestNewProviderFromConfig_Bitbucket(t *testing.T) {
+	withClearedGitHubEnv(t, func() {
+		cfg := &config.Config{}
+		cfg.Integration.Bitbucket.Username = "ci-bot"
+		cfg.Integration.Bitbucket.Token = "app-password"
+
+		provider, err := newProviderFromConfig(cfg, &http.Client{}, nil, nil, testLogger{})
+		if err != nil {
+			t.Fatalf("newProviderFromConfig() error = %v", err)
+		}
+		if _, ok := provider.(*broker.BitbucketProvider); !ok {
+			t.Fatalf("expected a Bitbucket provider without a GitHub token, got %T", provider)
+		}
+
+		cfg.Integration.GitLab.Token = "glpat-token"
+		provider, err = newProviderFromConfig(cfg, &http.Client{}, nil, nil, testLogger{})
+		if err != nil {
+			t.Fatalf("newProviderFromConfig() error = %v", err)
+		}
+		if _, ok := provider.(*broker.RoutingProvider); !ok {
+			t.Fatalf("expected repositories routed by host with GitLab and Bitbucket tokens, got %T", provider)
+		}
+	})
+}
+
+func TestGitLabHost(t *testing.T) {
+	for endpoint, want := range map[string]string{
+		"":                                   "gitlab.com",
+		"https://GitLab.example.com/api/v4":  "gitlab.example.com",
+		"http://gitlab.internal:8080/api/v4": "gitlab.internal",
+	} {
+		if got := gitLabHost(endpoint); got != want {
+			t.Errorf("gitLabHost(%q) = %q, want %q", endpoint, got, want)
+		}
+	}
+}
+
+func TestWithRateLimitMonitor(t *testing.T) {
+	base := &http.Client{Timeout: 5 * time.Second}
+	if got := withRateLimitMonitor(base, nil); got != base {
+		t.Fatal("expected base client to be returned when alerts are disabled")
+	}
+
+	cfg := &config.Config{}
+	cfg.Integration.GitHub.RateLimitAlerts = []int{80}
+	monitor := newRateLimitMonitor(cfg, testLogger{})
+	if monitor == nil {
+		t.Fatal("expected monitor when thresholds are configured")
+	}
+
+	wrapped := withRateLimitMonitor(base, monitor)
+	if wrapped == base || base.Transport != nil {
+		t.Fatal("expected base client to be copied, not modified")
+	}
+	if _, ok := wrapped.Transport.(*broker.RateLimitTransport); !ok {
+		t.Fatalf("expected rate limit transport, got %T", wrapped.Transport)
+	}
+	if wrapped.Timeout != base.Timeout {
+		t.Errorf("expected timeout to be preserved, got %v", wrapped.Timeout)
+	}
+}
+
+func TestGitHubRateLimitOptions_NilLogger(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := githubx.WrapClient(nil, GitHubRateLimitOptions(config.New(), nil)...)
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || calls != 2 {
+		t.Errorf("response = %d after %d calls, want the limited request retried without a logger", resp.StatusCode, calls)
+	}
+}
+
+func TestWithRequestLogging(t *testing.T) {
+	base := &http.Client{Timeout: 5 * time.Second}
+	wrapped := withRequestLogging(base, testLogger{}, true)
+	if wrapped == base || base.Transport != nil {
+		t.Fatal("expected base client to be copied, not modified")
+	}
+	transport, ok := wrapped.Transport.(*broker.CorrelationTransport)
+	if !ok {
+		t.Fatalf("expected correlation transport, got %T", wrapped.Transport)
+	}
+	if !transport.HostOnly {
+		t.Error("expected host-only logging to be preserved")
+	}
+	if wrapped.Timeout != base.Timeout {
+		t.Errorf("expected timeout to be preserved, got %v", wrapped.Timeout)
+	}
+}
+
+func TestProvideRepoMetadataWithConfig(t *testing.T) {
+	withClearedGitHubEnv(t, func() {
+		logger := testLogger{}
+		cfg := &config.Config{}
+		if svc := provideRepoMetadataWithConfig(cfg, &http.Client{}, logger); svc != nil {
+			t.Fatal("expected no metadata service without a GitHub token")
+		}
+		if svc := provideRepoMetadataWithConfig(nil, &http.Client{}, logger); svc != nil {
+			t.Fatal("expected no metadata service without config")
+		}
+
+		cfg.Integration.GitHub.Token = "test-token"
+		if svc := provideRepoMetadataWithConfig(cfg, &http.Client{}, logger); svc == nil {
+			t.Fatal("expected metadata service when a GitHub token is present")
+		}
+	})
+}
+
+func TestContainer_SharesRepoMetadata(t *testing.T) {
+	svc := repometa.NewService(repometa.FetcherFunc(func(ctx context.Context, repo string) (*repometa.Metadata, error) {
+		return &repometa.Metadata{Repo: repo}, nil
+	}))
+	c, err := New(WithConfig(&config.Config{}), WithLogger(testLogger{}), WithRepoMetadata(svc))
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	if c.RepoMetadata() != svc {
+		t.Fatal("expected container to expose the injected metadata service")
+	}
+
+	if _, err := New(WithRepoMetadata(nil)); err == nil {
+		t.Fatal("expected error for nil metadata service")
+	}
+}
+
 func withClearedGitHubEnv(t *testing.T, fn func()) {
 	t.Helper()
 	vars := []string{"GITHUB_TOKEN", "GITHUB_ACCESS_TOKEN", "GH_TOKEN", "CASCADE_GITHUB_TOKEN"}
@@ -198,7 +373,7 @@ func TestProvideBrokerForProduction_NoToken(t *testing.T) {
 			t.Fatal("expected nil broker when production broker creation fails")
 		}
 
-		expectedMsg := "production commands require GitHub credentials"
+		expectedMsg := "production commands require GitHub, GitLab, or Bitbucket credentials"
 		if !strings.Contains(err.Error(), expectedMsg) {
 			t.Errorf("error message should mention production credentials requirement, got: %v", err)
 		}
@@ -407,3 +582,133 @@ func isNopStateManager(mgr state.Manager) bool {
 	typeName := storageField.Type().String()
 	return typeName == "state.nopStorage"
 }
+
+func TestProvideBrokerForProduction_Sandbox(t *testing.T) {
+	withClearedGitHubEnv(t, func() {
+		cfg := &config.Config{}
+		cfg.State.Dir = t.TempDir()
+		cfg.Integration.Provider = config.ProviderSandbox
+
+		provider, err := newPullRequestProvider(cfg, &http.Client{}, nil, nil, testLogger{})
+		if err != nil {
+			t.Fatalf("newPullRequestProvider() error = %v", err)
+		}
+		if _, ok := provider.(*broker.SandboxProvider); !ok {
+			t.Fatalf("expected the sandbox provider, got %T", provider)
+		}
+
+		b, err := provideBrokerForProduction(cfg, &http.Client{}, testLogger{})
+		if err != nil || isStubBroker(b) {
+			t.Fatalf("expected a real production broker without credentials, got %v", err)
+		}
+		pr, err := b.EnsurePR(context.Background(), planner.WorkItem{Repo: "example/api", BranchName: "cascade/update", Branch: "main", Module: "github.com/example/lib", SourceModule: "github.com/example/lib", SourceVersion: "v1.0.0"}, &executor.Result{Status: executor.StatusCompleted})
+		if err != nil || !strings.HasPrefix(pr.URL, "file://") {
+			t.Fatalf("EnsurePR() = %+v, %v", pr, err)
+		}
+		if _, err := os.Stat(filepath.Join(cfg.State.Dir, "sandbox", "example", "api", "pulls", "1.json")); err != nil {
+			t.Errorf("expected the pull request in the sandbox directory: %v", err)
+		}
+	})
+}
+
+func TestNewPullRequestProvider_SandboxDependents(t *testing.T) {
+	withClearedGitHubEnv(t, func() {
+		cfg := &config.Config{}
+		cfg.State.Dir = t.TempDir()
+		cfg.Integration.GitLab.Token = "glpat-token"
+
+		provider, err := newPullRequestProvider(cfg, &http.Client{}, nil, nil, testLogger{})
+		if err != nil {
+			t.Fatalf("newPullRequestProvider() error = %v", err)
+		}
+		ctx := broker.WithProviderName(context.Background(), broker.ProviderSandbox)
+		pr, err := provider.CreateOrUpdatePullRequest(ctx, broker.PRInput{Repo: "group/project", BaseBranch: "main", HeadBranch: "cascade/update"})
+		if err != nil || !strings.HasPrefix(pr.URL, "file://") {
+			t.Fatalf("expected the sandbox to take dependents whose provider is sandbox, got %+v, %v", pr, err)
+		}
+	})
+}
+
+func TestProvideHTTPClientWithConfig_RequestTagging(t *testing.T) {
+	var got http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{}
+	cfg.Integration.GitHub.Organization = "acme"
+	cfg.Integration.Requests = config.RequestTaggingConfig{
+		UserAgent: "platform-bot/1.0",
+		Org:       "payments",
+		Headers:   map[string]string{"X-Team": "release-eng"},
+	}
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("User-Agent", "go-github/v66")
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	resp, err := provideHTTPClientWithConfig(cfg).Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if ua := got.Get("User-Agent"); !strings.HasPrefix(ua, "platform-bot/1.0 cascade-cli") || !strings.Contains(ua, "org/payments") {
+		t.Errorf("expected the tagged User-Agent to replace the client's, got %q", ua)
+	}
+	if org := got.Get("X-Cascade-Org"); org != "payments" {
+		t.Errorf("X-Cascade-Org = %q, want payments", org)
+	}
+	if team := got.Get("X-Team"); team != "release-eng" {
+		t.Errorf("X-Team = %q, want release-eng", team)
+	}
+	if accept := got.Get("Accept"); accept != "application/vnd.github.v3+json" {
+		t.Errorf("expected the request's Accept header to be kept, got %q", accept)
+	}
+}
+
+func TestParseStateURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		want    config.StateConfig
+		wantErr string
+	}{
+		{url: "s3://team-state/cascade/ci", want: config.StateConfig{Backend: config.StateBackendS3, Bucket: "team-state", Prefix: "cascade/ci"}},
+		{url: "gs://team-state", want: config.StateConfig{Backend: config.StateBackendGCS, Bucket: "team-state"}},
+		{url: "gcs://team-state/cascade/", want: config.StateConfig{Backend: config.StateBackendGCS, Bucket: "team-state", Prefix: "cascade"}},
+		{url: "https://team-state/cascade", wantErr: "must start with s3:// or gs://"},
+		{url: "s3:///cascade", wantErr: "has no bucket"},
+	}
+
+	for _, tt := range tests {
+		got, err := parseStateURL(tt.url)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("parseStateURL(%q) error = %v, want %q", tt.url, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("parseStateURL(%q) = %+v, %v, want %+v", tt.url, got, err, tt.want)
+		}
+	}
+}
+
+func TestRemoteStateIsReadOnly(t *testing.T) {
+	configured := config.StateConfig{Backend: config.StateBackendS3, Endpoint: "http://127.0.0.1:1", Region: "eu-west-1"}
+	manager, err := RemoteState("s3://team-state/cascade", configured, testLogger{})
+	if err != nil {
+		t.Fatalf("RemoteState() error = %v", err)
+	}
+	err = manager.SaveSummary(&state.Summary{Module: "example.com/lib", Version: "v1.2.0"})
+	if !errors.Is(err, state.ErrReadOnly) {
+		t.Errorf("SaveSummary() error = %v, want state.ErrReadOnly", err)
+	}
+
+	if _, err := RemoteState("file:///tmp/state", configured, testLogger{}); err == nil {
+		t.Error("RemoteState() accepted a non-object-storage URL")
+	}
+}
@@ -0,0 +1,377 @@
+package doctor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/goliatone/cascade/internal/manifest"
+	"github.com/goliatone/cascade/pkg/config"
+	"golang.org/x/mod/semver"
+)
+
+// minGitVersion is the oldest git the executor works with: it runs
+// `git worktree repair` when clones move to a new workspace layout.
+const minGitVersion = "v2.29.0"
+
+// Free space thresholds for the workspace, which holds a clone and a worktree
+// of every dependent.
+const (
+	lowDiskSpace     = 1 << 30   // warn below 1 GiB
+	minimumDiskSpace = 100 << 20 // fail below 100 MiB
+)
+
+// errUnsupported is returned by freeSpace on platforms it cannot measure.
+var errUnsupported = errors.New("unsupported platform")
+
+var (
+	githubAPIURL       = "https://api.github.com/"
+	slackAuthTestURL   = "https://slack.com/api/auth.test"
+	toolVersionPattern = regexp.MustCompile(`(\d+)\.(\d+)(?:\.(\d+))?`)
+)
+
+func builtinChecks() []Check {
+	return []Check{
+		NewCheck("git", checkGit),
+		NewCheck("go", checkGo),
+		NewCheck("github-token", checkGitHubToken),
+		NewCheck("slack", checkSlack),
+		NewCheck("state-dir", checkStateDir),
+		NewCheck("workspace-disk", checkWorkspaceDisk),
+		NewCheck("manifest", checkManifest),
+	}
+}
+
+// toolVersion extracts the first dotted version number from output as a
+// semantic version, such as v2.39.2 from "git version 2.39.2 (Apple Git-143)".
+func toolVersion(output []byte) (string, bool) {
+	m := toolVersionPattern.FindStringSubmatch(string(output))
+	if m == nil {
+		return "", false
+	}
+	patch := m[3]
+	if patch == "" {
+		patch = "0"
+	}
+	return fmt.Sprintf("v%s.%s.%s", m[1], m[2], patch), true
+}
+
+func checkGit(ctx context.Context, env Env) Result {
+	output, err := env.run(ctx, "git", "--version")
+	if err != nil {
+		return Result{
+			Status:  StatusFailed,
+			Message: fmt.Sprintf("git is not available: %v", err),
+			Fix:     "install git and make sure it is on PATH",
+		}
+	}
+	version, ok := toolVersion(output)
+	if !ok {
+		return Result{Status: StatusWarning, Message: fmt.Sprintf("could not read the git version from %q", strings.TrimSpace(string(output)))}
+	}
+	if semver.Compare(version, minGitVersion) < 0 {
+		return Result{
+			Status:  StatusWarning,
+			Message: fmt.Sprintf("git %s is older than %s", strings.TrimPrefix(version, "v"), strings.TrimPrefix(minGitVersion, "v")),
+			Fix:     "upgrade git; older releases cannot repair worktrees after the workspace layout changes",
+		}
+	}
+	return Result{Status: StatusOK, Message: "git " + strings.TrimPrefix(version, "v")}
+}
+
+func checkGo(ctx context.Context, env Env) Result {
+	output, err := env.run(ctx, "go", "env", "GOVERSION")
+	if err != nil {
+		return Result{
+			Status:  StatusFailed,
+			Message: fmt.Sprintf("the Go toolchain is not available: %v", err),
+			Fix:     "install Go from https://go.dev/dl and make sure `go` is on PATH",
+		}
+	}
+	version := strings.TrimSpace(string(output))
+	message := version
+	if toolchain, err := env.run(ctx, "go", "env", "GOTOOLCHAIN"); err == nil {
+		if value := strings.TrimSpace(string(toolchain)); value != "" {
+			message += ", GOTOOLCHAIN=" + value
+		}
+	}
+	return Result{Status: StatusOK, Message: message}
+}
+
+func checkGitHubToken(ctx context.Context, env Env) Result {
+	token := strings.TrimSpace(env.GitHubToken)
+	if token == "" {
+		return Result{
+			Status:  StatusWarning,
+			Message: "no GitHub token configured; releases cannot open pull requests on GitHub",
+			Fix:     "set GITHUB_TOKEN or integration.github.token",
+		}
+	}
+
+	base := githubAPIURL
+	if env.Config != nil {
+		if endpoint := strings.TrimSpace(env.Config.Integration.GitHub.Endpoint); endpoint != "" {
+			base = endpoint
+		}
+	}
+	if !strings.HasSuffix(base, "/") {
+		base += "/"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, base+"user", nil)
+	if err != nil {
+		return Result{Status: StatusFailed, Message: fmt.Sprintf("invalid GitHub endpoint %s: %v", base, err), Fix: "check integration.github.endpoint"}
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	resp, err := env.httpClient().Do(req)
+	if err != nil {
+		return Result{
+			Status:  StatusFailed,
+			Message: fmt.Sprintf("GitHub API unreachable: %v", err),
+			Fix:     "check network access to " + base + " and any proxy settings",
+		}
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		return Result{
+			Status:  StatusFailed,
+			Message: "GitHub rejected the token (401)",
+			Fix:     "the token is invalid or expired; create a new one and update GITHUB_TOKEN",
+		}
+	case resp.StatusCode >= 300:
+		return Result{
+			Status:  StatusFailed,
+			Message: fmt.Sprintf("GitHub API returned %s", resp.Status),
+			Fix:     "check the token and integration.github.endpoint",
+		}
+	}
+
+	var user struct {
+		Login string `json:"login"`
+	}
+	_ = json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&user)
+	who := "authenticated"
+	if user.Login != "" {
+		who = "authenticated as " + user.Login
+	}
+
+	// Classic tokens list their scopes; fine-grained and app tokens do not
+	header, listed := resp.Header["X-Oauth-Scopes"]
+	if !listed {
+		return Result{Status: StatusOK, Message: who + " (fine-grained or app token; grant it contents and pull requests write access)"}
+	}
+	scopes := make(map[string]bool)
+	for _, scope := range strings.Split(strings.Join(header, ","), ",") {
+		scopes[strings.TrimSpace(scope)] = true
+	}
+	if !scopes["repo"] {
+		message := who + ", but the token lacks the repo scope"
+		if scopes["public_repo"] {
+			message = who + " with public_repo only; private dependents cannot be updated"
+		}
+		return Result{
+			Status:  StatusWarning,
+			Message: message,
+			Fix:     "grant the token the repo scope so cascade can push branches and open pull requests",
+		}
+	}
+	return Result{Status: StatusOK, Message: who + " with the repo scope"}
+}
+
+func checkSlack(ctx context.Context, env Env) Result {
+	if env.Config == nil {
+		return Result{Status: StatusSkipped, Message: "no configuration loaded"}
+	}
+	slack := env.Config.Integration.Slack
+	token := strings.TrimSpace(slack.Token)
+	webhook := strings.TrimSpace(slack.WebhookURL)
+
+	if token == "" {
+		if webhook == "" {
+			return Result{Status: StatusSkipped, Message: "Slack notifications are not configured"}
+		}
+		// Calling a webhook posts a message, so only its form is checked
+		if u, err := url.Parse(webhook); err != nil || u.Scheme != "https" || u.Host == "" {
+			return Result{
+				Status:  StatusFailed,
+				Message: "the Slack webhook URL is not a valid https URL",
+				Fix:     "copy the webhook URL from the Slack app's Incoming Webhooks page into integration.slack.webhook_url",
+			}
+		}
+		return Result{Status: StatusOK, Message: "webhook configured (not called, to avoid posting a message)"}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, slackAuthTestURL, nil)
+	if err != nil {
+		return Result{Status: StatusFailed, Message: err.Error()}
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := env.httpClient().Do(req)
+	if err != nil {
+		return Result{
+			Status:  StatusFailed,
+			Message: fmt.Sprintf("Slack API unreachable: %v", err),
+			Fix:     "check network access to slack.com",
+		}
+	}
+	defer resp.Body.Close()
+
+	var auth struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+		Team  string `json:"team"`
+		User  string `json:"user"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&auth); err != nil {
+		return Result{Status: StatusFailed, Message: fmt.Sprintf("unexpected Slack response (%s)", resp.Status)}
+	}
+	if !auth.OK {
+		return Result{
+			Status:  StatusFailed,
+			Message: "Slack rejected the token: " + auth.Error,
+			Fix:     "use the bot token (xoxb-...) of an installed Slack app in integration.slack.token",
+		}
+	}
+	if strings.TrimSpace(slack.Channel) == "" {
+		return Result{
+			Status:  StatusWarning,
+			Message: fmt.Sprintf("authenticated as %s in %s, but no channel is configured", auth.User, auth.Team),
+			Fix:     "set integration.slack.channel or a notifications.slack_channel in the manifest",
+		}
+	}
+	return Result{Status: StatusOK, Message: fmt.Sprintf("authenticated as %s in %s", auth.User, auth.Team)}
+}
+
+func checkStateDir(ctx context.Context, env Env) Result {
+	if env.Config != nil {
+		if !env.Config.State.Enabled && env.Config.ExplicitlySetStateEnabled() {
+			return Result{Status: StatusSkipped, Message: "state persistence is disabled"}
+		}
+		if backend := env.Config.State.Backend; backend != "" && backend != config.StateBackendFilesystem {
+			return Result{Status: StatusSkipped, Message: fmt.Sprintf("state is kept in %s bucket %s", backend, env.Config.State.Bucket)}
+		}
+	}
+	if env.StateDir == "" {
+		return Result{Status: StatusSkipped, Message: "no state directory configured"}
+	}
+
+	if err := writable(env.StateDir); err != nil {
+		return Result{
+			Status:  StatusFailed,
+			Message: fmt.Sprintf("%s is not writable: %v", env.StateDir, err),
+			Fix:     "fix the directory's permissions or point state.dir (CASCADE_STATE_DIR) somewhere writable",
+		}
+	}
+	return Result{Status: StatusOK, Message: env.StateDir + " is writable"}
+}
+
+// writable creates dir if needed and writes and removes a file in it.
+func writable(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(dir, ".cascade-doctor-*")
+	if err != nil {
+		return err
+	}
+	name := f.Name()
+	_, err = f.WriteString("ok")
+	err = errors.Join(err, f.Close())
+	return errors.Join(err, os.Remove(name))
+}
+
+func checkWorkspaceDisk(ctx context.Context, env Env) Result {
+	if env.Config == nil || strings.TrimSpace(env.Config.Workspace.Path) == "" {
+		return Result{Status: StatusSkipped, Message: "no workspace configured"}
+	}
+	workspace := env.Config.Workspace.Path
+
+	// The workspace may not exist yet; measure the volume it will be created on
+	dir := workspace
+	for {
+		if _, err := os.Stat(dir); err == nil {
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	free, err := freeSpace(dir)
+	if errors.Is(err, errUnsupported) {
+		return Result{Status: StatusSkipped, Message: "free space cannot be measured on this platform"}
+	}
+	if err != nil {
+		return Result{Status: StatusWarning, Message: fmt.Sprintf("could not measure free space at %s: %v", dir, err)}
+	}
+
+	message := fmt.Sprintf("%s free at %s", formatBytes(free), workspace)
+	switch {
+	case free < minimumDiskSpace:
+		return Result{Status: StatusFailed, Message: message, Fix: "free up disk space or move workspace.path to a larger volume"}
+	case free < lowDiskSpace:
+		return Result{Status: StatusWarning, Message: message, Fix: "large releases clone every dependent; free up space or move workspace.path"}
+	}
+	return Result{Status: StatusOK, Message: message}
+}
+
+func formatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+func checkManifest(ctx context.Context, env Env) Result {
+	path := strings.TrimSpace(env.ManifestPath)
+	if path == "" {
+		return Result{Status: StatusSkipped, Message: "no manifest path given"}
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Result{
+			Status:  StatusWarning,
+			Message: path + " does not exist",
+			Fix:     "create one with `cascade manifest generate` or pass --manifest",
+		}
+	}
+	if err != nil {
+		return Result{Status: StatusFailed, Message: fmt.Sprintf("cannot read %s: %v", path, err)}
+	}
+
+	if diagnostics := manifest.ValidateSource(path, data); len(diagnostics) > 0 {
+		return Result{
+			Status:  StatusFailed,
+			Message: fmt.Sprintf("%s has %d problem(s), first: %s", path, len(diagnostics), diagnostics[0]),
+			Fix:     "run `cascade manifest validate` to list them",
+		}
+	}
+	m, err := manifest.Parse(path, data)
+	if err != nil {
+		return Result{Status: StatusFailed, Message: err.Error(), Fix: "run `cascade manifest validate` for details"}
+	}
+	dependents := 0
+	for _, module := range m.Modules {
+		dependents += len(module.Dependents)
+	}
+	return Result{Status: StatusOK, Message: fmt.Sprintf("%s loads: %d module(s), %d dependent(s)", path, len(m.Modules), dependents)}
+}
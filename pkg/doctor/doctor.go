@@ -0,0 +1,157 @@
+// Package doctor diagnoses the environment cascade runs in: the tools it calls,
+// the credentials it uses, and the directories it writes to. Each diagnostic is
+// a Check; the built-in checks are registered by default and other subsystems
+// can add their own with Register.
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goliatone/cascade/pkg/config"
+)
+
+// DefaultCheckTimeout bounds each check run by Run.
+const DefaultCheckTimeout = 20 * time.Second
+
+// Status is the outcome of a check.
+type Status string
+
+const (
+	// StatusOK means the check passed.
+	StatusOK Status = "ok"
+	// StatusWarning means cascade can run, but something may fail or degrade.
+	StatusWarning Status = "warning"
+	// StatusFailed means cascade cannot work until the problem is fixed.
+	StatusFailed Status = "failed"
+	// StatusSkipped means the check does not apply to this configuration.
+	StatusSkipped Status = "skipped"
+)
+
+// Result is the outcome of one check.
+type Result struct {
+	Check   string `json:"check"`
+	Status  Status `json:"status"`
+	Message string `json:"message"`
+	// Fix is an actionable suggestion for warnings and failures.
+	Fix string `json:"fix,omitempty"`
+}
+
+// Env is what checks inspect. Every field may be empty; checks that need a
+// missing field report themselves skipped.
+type Env struct {
+	Config       *config.Config
+	HTTPClient   *http.Client
+	ManifestPath string
+	StateDir     string
+	GitHubToken  string
+
+	// Run runs a command and returns its combined output. Nil runs it with
+	// os/exec.
+	Run func(ctx context.Context, name string, args ...string) ([]byte, error)
+}
+
+func (e Env) run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	if e.Run != nil {
+		return e.Run(ctx, name, args...)
+	}
+	return exec.CommandContext(ctx, name, args...).CombinedOutput()
+}
+
+func (e Env) httpClient() *http.Client {
+	if e.HTTPClient != nil {
+		return e.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+// Check is one diagnostic.
+type Check interface {
+	// Name identifies the check in output, such as "git" or "github-token".
+	Name() string
+	// Run inspects env and reports the outcome. Result.Check may be left empty.
+	Run(ctx context.Context, env Env) Result
+}
+
+// NewCheck adapts a function to Check.
+func NewCheck(name string, run func(ctx context.Context, env Env) Result) Check {
+	return funcCheck{name: name, run: run}
+}
+
+type funcCheck struct {
+	name string
+	run  func(ctx context.Context, env Env) Result
+}
+
+func (c funcCheck) Name() string { return c.name }
+
+func (c funcCheck) Run(ctx context.Context, env Env) Result { return c.run(ctx, env) }
+
+var registry = struct {
+	sync.RWMutex
+	checks []Check
+}{
+	checks: builtinChecks(),
+}
+
+// Register adds a check to the ones Checks returns, after those already
+// registered. Register checks from an init function. Names cannot be
+// registered twice, so built-in checks cannot be replaced.
+func Register(check Check) error {
+	if check == nil {
+		return fmt.Errorf("doctor check cannot be nil")
+	}
+	name := strings.TrimSpace(check.Name())
+	if name == "" {
+		return fmt.Errorf("doctor check name cannot be empty")
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+	for _, existing := range registry.checks {
+		if existing.Name() == name {
+			return fmt.Errorf("doctor check %s is already registered", name)
+		}
+	}
+	registry.checks = append(registry.checks, check)
+	return nil
+}
+
+// Checks returns the registered checks in registration order, built-in checks
+// first.
+func Checks() []Check {
+	registry.RLock()
+	defer registry.RUnlock()
+	return append([]Check(nil), registry.checks...)
+}
+
+// Run runs checks one after another, each bounded by DefaultCheckTimeout, and
+// returns their results in the same order.
+func Run(ctx context.Context, env Env, checks []Check) []Result {
+	results := make([]Result, 0, len(checks))
+	for _, check := range checks {
+		checkCtx, cancel := context.WithTimeout(ctx, DefaultCheckTimeout)
+		result := check.Run(checkCtx, env)
+		cancel()
+		if result.Check == "" {
+			result.Check = check.Name()
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// Failed reports whether any result failed.
+func Failed(results []Result) bool {
+	for _, result := range results {
+		if result.Status == StatusFailed {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,194 @@
+package doctor
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/goliatone/cascade/pkg/config"
+)
+
+func TestRegister(t *testing.T) {
+	check := NewCheck("doctor-test-extra", func(context.Context, Env) Result {
+		return Result{Status: StatusOK, Message: "fine"}
+	})
+	if err := Register(check); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if err := Register(check); err == nil {
+		t.Error("registering a name twice should fail")
+	}
+	if err := Register(NewCheck("git", checkGit)); err == nil {
+		t.Error("replacing a built-in check should fail")
+	}
+
+	checks := Checks()
+	if checks[0].Name() != "git" || checks[len(checks)-1].Name() != "doctor-test-extra" {
+		t.Errorf("checks are not in registration order: first %s, last %s", checks[0].Name(), checks[len(checks)-1].Name())
+	}
+
+	results := Run(context.Background(), Env{}, []Check{check})
+	if len(results) != 1 || results[0].Check != "doctor-test-extra" || results[0].Status != StatusOK {
+		t.Errorf("Run = %+v", results)
+	}
+}
+
+func TestCheckGit(t *testing.T) {
+	tests := []struct {
+		output string
+		err    error
+		want   Status
+	}{
+		{output: "git version 2.39.2 (Apple Git-143)\n", want: StatusOK},
+		{output: "git version 2.45.1.windows.1\n", want: StatusOK},
+		{output: "git version 2.25.1\n", want: StatusWarning},
+		{err: errors.New(`exec: "git": executable file not found in $PATH`), want: StatusFailed},
+	}
+	for _, tt := range tests {
+		env := Env{Run: func(context.Context, string, ...string) ([]byte, error) {
+			return []byte(tt.output), tt.err
+		}}
+		if got := checkGit(context.Background(), env); got.Status != tt.want {
+			t.Errorf("checkGit(%q, %v) = %+v, want %s", tt.output, tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestCheckGitHubToken(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		scopes *string
+		want   Status
+	}{
+		{name: "classic token with repo", status: http.StatusOK, scopes: ptr("repo, workflow"), want: StatusOK},
+		{name: "public_repo only", status: http.StatusOK, scopes: ptr("public_repo"), want: StatusWarning},
+		{name: "fine-grained token", status: http.StatusOK, want: StatusOK},
+		{name: "rejected token", status: http.StatusUnauthorized, want: StatusFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/v3/user" || r.Header.Get("Authorization") != "Bearer secret" {
+					http.Error(w, "unexpected request", http.StatusBadRequest)
+					return
+				}
+				if tt.scopes != nil {
+					w.Header().Set("X-OAuth-Scopes", *tt.scopes)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(`{"login":"octocat"}`))
+			}))
+			defer server.Close()
+
+			cfg := &config.Config{}
+			cfg.Integration.GitHub.Endpoint = server.URL + "/api/v3"
+			got := checkGitHubToken(context.Background(), Env{Config: cfg, HTTPClient: server.Client(), GitHubToken: "secret"})
+			if got.Status != tt.want {
+				t.Errorf("checkGitHubToken = %+v, want %s", got, tt.want)
+			}
+			if got.Status != StatusOK && got.Fix == "" {
+				t.Error("expected a fix for a problem")
+			}
+		})
+	}
+}
+
+func ptr(s string) *string { return &s }
+
+func TestCheckSlack(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "Bearer xoxb-good" {
+			w.Write([]byte(`{"ok":true,"team":"Acme","user":"cascade"}`))
+			return
+		}
+		w.Write([]byte(`{"ok":false,"error":"invalid_auth"}`))
+	}))
+	defer server.Close()
+	defer func(previous string) { slackAuthTestURL = previous }(slackAuthTestURL)
+	slackAuthTestURL = server.URL
+
+	check := func(slack config.SlackConfig) Result {
+		cfg := &config.Config{}
+		cfg.Integration.Slack = slack
+		return checkSlack(context.Background(), Env{Config: cfg, HTTPClient: server.Client()})
+	}
+	if got := check(config.SlackConfig{Token: "xoxb-good", Channel: "#releases"}); got.Status != StatusOK {
+		t.Errorf("valid token: %+v", got)
+	}
+	if got := check(config.SlackConfig{Token: "xoxb-good"}); got.Status != StatusWarning {
+		t.Errorf("valid token without a channel: %+v", got)
+	}
+	if got := check(config.SlackConfig{Token: "xoxb-bad"}); got.Status != StatusFailed || !strings.Contains(got.Message, "invalid_auth") {
+		t.Errorf("rejected token: %+v", got)
+	}
+	if got := check(config.SlackConfig{WebhookURL: "http://hooks.slack.com/services/x"}); got.Status != StatusFailed {
+		t.Errorf("plain http webhook: %+v", got)
+	}
+	if got := check(config.SlackConfig{}); got.Status != StatusSkipped {
+		t.Errorf("unconfigured: %+v", got)
+	}
+}
+
+func TestCheckStateDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "state")
+	if got := checkStateDir(context.Background(), Env{StateDir: dir}); got.Status != StatusOK {
+		t.Fatalf("checkStateDir = %+v", got)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("probe file left behind: %v", entries)
+	}
+
+	file := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := checkStateDir(context.Background(), Env{StateDir: filepath.Join(file, "state")}); got.Status != StatusFailed {
+		t.Errorf("state dir under a file: %+v", got)
+	}
+}
+
+func TestCheckManifest(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "valid.yaml")
+	os.WriteFile(valid, []byte(`manifest_version: 1
+modules:
+  - name: lib
+    module: github.com/acme/lib
+    repo: acme/lib
+    dependents:
+      - repo: acme/app
+        module: github.com/acme/app
+        module_path: .
+`), 0o644)
+	invalid := filepath.Join(dir, "invalid.yaml")
+	os.WriteFile(invalid, []byte("manifest_version: 1\nmodules: [\n"), 0o644)
+
+	tests := []struct {
+		path string
+		want Status
+	}{
+		{path: valid, want: StatusOK},
+		{path: invalid, want: StatusFailed},
+		{path: filepath.Join(dir, "missing.yaml"), want: StatusWarning},
+		{path: "", want: StatusSkipped},
+	}
+	for _, tt := range tests {
+		if got := checkManifest(context.Background(), Env{ManifestPath: tt.path}); got.Status != tt.want {
+			t.Errorf("checkManifest(%q) = %+v, want %s", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	for n, want := range map[uint64]string{512: "512 B", 1536: "1.5 KiB", 3 << 30: "3.0 GiB"} {
+		if got := formatBytes(n); got != want {
+			t.Errorf("formatBytes(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+// Package githubx holds the GitHub API client plumbing the broker and
+// discovery share: a transport that waits out primary and secondary rate
+// limits, honouring Retry-After, and the classification of the rate limit
+// errors that reach callers when a wait is too long.
+package githubx
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+)
+
+// Kind tells primary rate limits, the hourly request quota, apart from
+// secondary ones, which GitHub applies to bursts of requests and to content
+// creation and which it also calls abuse detection.
+type Kind string
+
+// Rate limit kinds.
+const (
+	Primary   Kind = "primary"
+	Secondary Kind = "secondary"
+)
+
+// secondaryWait is how long GitHub asks clients to wait after a secondary
+// rate limit response that carries no Retry-After header.
+const secondaryWait = time.Minute
+
+// Limit is a rate limit GitHub rejected a request for.
+type Limit struct {
+	Kind Kind
+
+	// Reset is when requests may resume.
+	Reset time.Time
+
+	// Limit and Remaining are the quota of a primary rate limit, zero when
+	// unknown.
+	Limit     int
+	Remaining int
+}
+
+// RateLimited reports whether err, or an error it wraps, is a GitHub rate limit
+// error, and which limit it hit.
+func RateLimited(err error) (Limit, bool) {
+	var rateErr *github.RateLimitError
+	if errors.As(err, &rateErr) {
+		return Limit{
+			Kind:      Primary,
+			Reset:     rateErr.Rate.Reset.Time,
+			Limit:     rateErr.Rate.Limit,
+			Remaining: rateErr.Rate.Remaining,
+		}, true
+	}
+	var abuseErr *github.AbuseRateLimitError
+	if errors.As(err, &abuseErr) {
+		wait := secondaryWait
+		if retryAfter := abuseErr.GetRetryAfter(); retryAfter > 0 {
+			wait = retryAfter
+		}
+		return Limit{Kind: Secondary, Reset: time.Now().Add(wait)}, true
+	}
+	return Limit{}, false
+}
+
+// StatusCode returns the HTTP status GitHub answered a failed call with, or 0
+// when err carries no response.
+func StatusCode(err error) int {
+	var errResp *github.ErrorResponse
+	if errors.As(err, &errResp) && errResp.Response != nil {
+		return errResp.Response.StatusCode
+	}
+	return 0
+}
@@ -0,0 +1,336 @@
+package githubx
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goliatone/cascade/pkg/retry"
+)
+
+const (
+	// DefaultMaxWait is the longest a request waits for a rate limit to reset
+	// before its response is returned to the caller.
+	DefaultMaxWait = 5 * time.Minute
+
+	// DefaultMaxRetries is how many times a rate limited request is retried.
+	DefaultMaxRetries = 3
+
+	// resetBuffer is added to primary rate limit resets, so clock skew between
+	// the runner and GitHub does not make the retry land before the reset.
+	resetBuffer = time.Second
+
+	// bodyPeekLimit bounds how much of an error response is read to tell a
+	// secondary rate limit from a permission error.
+	bodyPeekLimit = 64 << 10
+)
+
+// Wait is a rate limit the transport waits out before retrying a request.
+type Wait struct {
+	Kind  Kind
+	Delay time.Duration
+
+	// Attempt is the request attempt that was rate limited, from 1. It is 0
+	// when a successful response used the last request of the quota and the
+	// transport waits for the reset before returning it.
+	Attempt int
+
+	Method string
+	Path   string
+}
+
+// Option configures a Transport.
+type Option func(*Transport)
+
+// WithMaxWait sets the longest a request waits for a rate limit before the
+// limited response is returned to the caller. Zero or less never waits.
+// Default: DefaultMaxWait
+func WithMaxWait(d time.Duration) Option {
+	return func(t *Transport) {
+		t.maxWait = d
+	}
+}
+
+// WithMaxRetries sets how many times a rate limited request is retried. Zero
+// or less never retries. Default: DefaultMaxRetries
+func WithMaxRetries(n int) Option {
+	return func(t *Transport) {
+		t.maxRetries = n
+	}
+}
+
+// WithAttemptTimeout bounds each attempt of a request, including reading its
+// response body, leaving rate limit waits out. Default: no timeout
+func WithAttemptTimeout(d time.Duration) Option {
+	return func(t *Transport) {
+		t.attemptTimeout = d
+	}
+}
+
+// WithWaitHandler sets a function told about every wait, typically to log it.
+func WithWaitHandler(onWait func(Wait)) Option {
+	return func(t *Transport) {
+		t.onWait = onWait
+	}
+}
+
+// Transport retries GitHub API requests rejected by a rate limit once the
+// limit resets:
+//
+//   - a Retry-After header is waited out as GitHub asks;
+//   - a primary limit, with X-RateLimit-Remaining at 0, is waited out until
+//     X-RateLimit-Reset;
+//   - a secondary limit without Retry-After waits a minute, doubling on every
+//     retry.
+//
+// Waits get a little jitter, so runners limited together do not retry in
+// lockstep. Waits longer than the maximum, requests whose body cannot be
+// replayed, and requests past the retry limit return the limited response, so
+// callers see go-github's rate limit errors.
+//
+// A successful response that used the last request of the quota is held until
+// the quota resets, when that is within the maximum wait. Otherwise go-github
+// would refuse the next call locally without sending it.
+type Transport struct {
+	base           http.RoundTripper
+	maxWait        time.Duration
+	maxRetries     int
+	attemptTimeout time.Duration
+	onWait         func(Wait)
+
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// NewTransport wraps base, or http.DefaultTransport when base is nil.
+func NewTransport(base http.RoundTripper, opts ...Option) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	t := &Transport{
+		base:       base,
+		maxWait:    DefaultMaxWait,
+		maxRetries: DefaultMaxRetries,
+		now:        time.Now,
+		sleep:      retry.Sleep,
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// WrapClient returns a copy of client whose requests go through a Transport.
+// The client's Timeout becomes the attempt timeout, so it no longer cuts rate
+// limit waits short. A nil client wraps http.DefaultTransport.
+func WrapClient(client *http.Client, opts ...Option) *http.Client {
+	clone := &http.Client{}
+	if client != nil {
+		*clone = *client
+	}
+	if clone.Timeout > 0 {
+		opts = append([]Option{WithAttemptTimeout(clone.Timeout)}, opts...)
+		clone.Timeout = 0
+	}
+	clone.Transport = NewTransport(clone.Transport, opts...)
+	return clone
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		resp, err := t.send(req)
+		if err != nil {
+			return resp, err
+		}
+
+		kind, delay, limited := t.limit(resp, attempt)
+		if !limited {
+			t.holdExhausted(req, resp)
+			return resp, nil
+		}
+		if attempt > t.maxRetries || delay > t.maxWait || !replayable(req) {
+			return resp, nil
+		}
+
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		if err := t.wait(req, Wait{Kind: kind, Delay: delay, Attempt: attempt}); err != nil {
+			return nil, err
+		}
+		if req, err = rewind(req); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// send makes one attempt of req, within the attempt timeout.
+func (t *Transport) send(req *http.Request) (*http.Response, error) {
+	if t.attemptTimeout <= 0 {
+		return t.base.RoundTrip(req)
+	}
+	ctx, cancel := context.WithTimeout(req.Context(), t.attemptTimeout)
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return resp, err
+	}
+	resp.Body = cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelOnClose releases the attempt timeout of a response once its body is
+// closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// limit reports whether resp rejected the request for a rate limit, which
+// one, and how long to wait before retrying it.
+func (t *Transport) limit(resp *http.Response, attempt int) (Kind, time.Duration, bool) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return "", 0, false
+	}
+	if delay, ok := t.retryAfter(resp.Header.Get("Retry-After")); ok {
+		return Secondary, delay, true
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		if reset, ok := parseReset(resp.Header.Get("X-RateLimit-Reset")); ok {
+			return Primary, max(reset.Sub(t.now()), 0) + resetBuffer, true
+		}
+	}
+	if resp.StatusCode == http.StatusTooManyRequests || mentionsSecondaryLimit(resp) {
+		backoff := retry.Policy{Delay: secondaryWait}
+		return Secondary, backoff.Backoff(attempt), true
+	}
+	return "", 0, false
+}
+
+// holdExhausted waits for the quota to reset when a successful response used
+// its last request and the reset is near enough.
+func (t *Transport) holdExhausted(req *http.Request, resp *http.Response) {
+	if t.maxRetries <= 0 || resp.StatusCode >= http.StatusBadRequest || resp.Header.Get("X-RateLimit-Remaining") != "0" {
+		return
+	}
+	reset, ok := parseReset(resp.Header.Get("X-RateLimit-Reset"))
+	if !ok {
+		return
+	}
+	delay := reset.Sub(t.now()) + resetBuffer
+	if delay <= resetBuffer || delay > t.maxWait {
+		return
+	}
+
+	// The body is read first, so the attempt timeout cannot expire during the
+	// wait and fail reading it
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		resp.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), failedReader{err: err}))
+		return
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	// A cancelled wait leaves it to go-github to refuse the next call
+	_ = t.wait(req, Wait{Kind: Primary, Delay: delay})
+}
+
+// failedReader returns the error reading a response body failed with.
+type failedReader struct {
+	err error
+}
+
+func (r failedReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
+
+func (t *Transport) wait(req *http.Request, wait Wait) error {
+	wait.Method, wait.Path = req.Method, req.URL.Path
+	if t.onWait != nil {
+		t.onWait(wait)
+	}
+	return t.sleep(req.Context(), jitter(wait.Delay))
+}
+
+// retryAfter parses a Retry-After header, in seconds or as an HTTP date.
+func (t *Transport) retryAfter(value string) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if when, err := http.ParseTime(value); err == nil {
+		return max(when.Sub(t.now()), 0), true
+	}
+	return 0, false
+}
+
+func parseReset(value string) (time.Time, bool) {
+	seconds, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || seconds <= 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(seconds, 0), true
+}
+
+// mentionsSecondaryLimit reports whether the body of resp is GitHub's
+// secondary rate limit or abuse detection message. The body is left readable.
+func mentionsSecondaryLimit(resp *http.Response) bool {
+	if resp.Body == nil {
+		return false
+	}
+	peek, err := io.ReadAll(io.LimitReader(resp.Body, bodyPeekLimit))
+	resp.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(peek), resp.Body), Closer: resp.Body}
+	if err != nil {
+		return false
+	}
+	message := strings.ToLower(string(peek))
+	return strings.Contains(message, "secondary rate limit") || strings.Contains(message, "abuse")
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// replayable reports whether req can be sent again.
+func replayable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// rewind returns req ready to be sent again, with a fresh body.
+func rewind(req *http.Request) (*http.Request, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return req, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	clone := req.Clone(req.Context())
+	clone.Body = body
+	return clone, nil
+}
+
+// jitter lengthens d by up to a tenth, never shortening a wait GitHub asked
+// for.
+func jitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return d
+	}
+	return d + time.Duration(rand.Int64N(int64(d/10)+1))
+}
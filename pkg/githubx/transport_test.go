@@ -0,0 +1,246 @@
+package githubx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+)
+
+// newTestTransport returns a transport that records its waits instead of
+// sleeping.
+func newTestTransport(waits *[]Wait, opts ...Option) *Transport {
+	opts = append([]Option{WithWaitHandler(func(w Wait) { *waits = append(*waits, w) })}, opts...)
+	t := NewTransport(nil, opts...)
+	t.sleep = func(context.Context, time.Duration) error { return nil }
+	return t
+}
+
+func TestTransportRetriesRateLimits(t *testing.T) {
+	reset := strconv.FormatInt(time.Now().Add(30*time.Second).Unix(), 10)
+	tests := []struct {
+		name     string
+		limited  func(w http.ResponseWriter)
+		wantKind Kind
+		minDelay time.Duration
+		maxDelay time.Duration
+	}{
+		{
+			name: "retry after",
+			limited: func(w http.ResponseWriter) {
+				w.Header().Set("Retry-After", "7")
+				w.WriteHeader(http.StatusForbidden)
+			},
+			wantKind: Secondary,
+			minDelay: 7 * time.Second,
+			maxDelay: 7 * time.Second,
+		},
+		{
+			name: "primary limit",
+			limited: func(w http.ResponseWriter) {
+				w.Header().Set("X-RateLimit-Remaining", "0")
+				w.Header().Set("X-RateLimit-Reset", reset)
+				w.WriteHeader(http.StatusForbidden)
+			},
+			wantKind: Primary,
+			minDelay: 25 * time.Second,
+			maxDelay: 32 * time.Second,
+		},
+		{
+			name: "secondary limit message",
+			limited: func(w http.ResponseWriter) {
+				w.WriteHeader(http.StatusForbidden)
+				fmt.Fprint(w, `{"message":"You have exceeded a secondary rate limit."}`)
+			},
+			wantKind: Secondary,
+			minDelay: time.Minute,
+			maxDelay: time.Minute,
+		},
+		{
+			name: "too many requests",
+			limited: func(w http.ResponseWriter) {
+				w.WriteHeader(http.StatusTooManyRequests)
+			},
+			wantKind: Secondary,
+			minDelay: time.Minute,
+			maxDelay: time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				if calls.Add(1) == 1 {
+					tt.limited(w)
+					return
+				}
+				fmt.Fprintf(w, "ok %s", body)
+			}))
+			defer server.Close()
+
+			var waits []Wait
+			client := &http.Client{Transport: newTestTransport(&waits)}
+			resp, err := client.Post(server.URL+"/repos/o/r/pulls", "application/json", strings.NewReader("payload"))
+			if err != nil {
+				t.Fatalf("Post() error = %v", err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK || string(body) != "ok payload" {
+				t.Errorf("response = %d %q, want the retried request to succeed with its body", resp.StatusCode, body)
+			}
+			if len(waits) != 1 {
+				t.Fatalf("waits = %+v, want 1", waits)
+			}
+			w := waits[0]
+			if w.Kind != tt.wantKind || w.Attempt != 1 || w.Method != http.MethodPost || w.Path != "/repos/o/r/pulls" {
+				t.Errorf("wait = %+v", w)
+			}
+			if w.Delay < tt.minDelay || w.Delay > tt.maxDelay {
+				t.Errorf("delay = %v, want between %v and %v", w.Delay, tt.minDelay, tt.maxDelay)
+			}
+		})
+	}
+}
+
+func TestTransportReturnsLimitedResponse(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      []Option
+		status    int
+		header    map[string]string
+		body      string
+		wantCalls int32
+	}{
+		{
+			name:      "permission error",
+			status:    http.StatusForbidden,
+			body:      `{"message":"Resource not accessible by integration"}`,
+			wantCalls: 1,
+		},
+		{
+			name:      "wait longer than max wait",
+			opts:      []Option{WithMaxWait(time.Minute)},
+			status:    http.StatusForbidden,
+			header:    map[string]string{"Retry-After": "3600"},
+			wantCalls: 1,
+		},
+		{
+			name:      "retries exhausted",
+			opts:      []Option{WithMaxRetries(2)},
+			status:    http.StatusTooManyRequests,
+			wantCalls: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls.Add(1)
+				for k, v := range tt.header {
+					w.Header().Set(k, v)
+				}
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, tt.body)
+			}))
+			defer server.Close()
+
+			var waits []Wait
+			client := &http.Client{Transport: newTestTransport(&waits, tt.opts...)}
+			resp, err := client.Get(server.URL)
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.status || string(body) != tt.body {
+				t.Errorf("response = %d %q, want %d %q", resp.StatusCode, body, tt.status, tt.body)
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestTransportHoldsExhaustedQuota(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(20*time.Second).Unix(), 10))
+		fmt.Fprint(w, "{}")
+	}))
+	defer server.Close()
+
+	var waits []Wait
+	client := &http.Client{Transport: newTestTransport(&waits)}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if len(waits) != 1 || waits[0].Kind != Primary || waits[0].Attempt != 0 {
+		t.Errorf("waits = %+v, want one wait for the quota to reset", waits)
+	}
+}
+
+func TestWrapClientMovesTimeoutToAttempts(t *testing.T) {
+	base := &http.Client{Timeout: 30 * time.Second}
+	client := WrapClient(base, WithMaxRetries(1))
+	if client.Timeout != 0 || base.Timeout != 30*time.Second {
+		t.Errorf("timeouts = %v, %v, want the wrapped client to leave waits unbounded", client.Timeout, base.Timeout)
+	}
+	transport, ok := client.Transport.(*Transport)
+	if !ok || transport.attemptTimeout != 30*time.Second || transport.maxRetries != 1 {
+		t.Errorf("transport = %+v, want the client timeout to bound each attempt", client.Transport)
+	}
+}
+
+func TestTransportStopsWaitingWhenCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	transport := NewTransport(nil)
+	if _, err := transport.RoundTrip(req); !errors.Is(err, context.Canceled) {
+		t.Errorf("RoundTrip() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestRateLimited(t *testing.T) {
+	reset := time.Now().Add(time.Hour).Truncate(time.Second)
+	rateErr := &github.RateLimitError{Rate: github.Rate{Limit: 5000, Remaining: 0, Reset: github.Timestamp{Time: reset}}}
+	limit, ok := RateLimited(fmt.Errorf("list repos: %w", rateErr))
+	if !ok || limit.Kind != Primary || !limit.Reset.Equal(reset) || limit.Limit != 5000 {
+		t.Errorf("RateLimited(RateLimitError) = %+v, %v", limit, ok)
+	}
+
+	retryAfter := 2 * time.Minute
+	limit, ok = RateLimited(&github.AbuseRateLimitError{RetryAfter: &retryAfter})
+	if !ok || limit.Kind != Secondary || time.Until(limit.Reset) < time.Minute {
+		t.Errorf("RateLimited(AbuseRateLimitError) = %+v, %v", limit, ok)
+	}
+
+	if _, ok := RateLimited(errors.New("API rate limit exceeded")); ok {
+		t.Error("expected errors that are not go-github rate limit errors to be ignored")
+	}
+}
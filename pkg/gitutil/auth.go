@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 )
 
@@ -33,23 +34,44 @@ const (
 // 4. GITHUB_ACCESS_TOKEN
 // Returns empty string if no token is found.
 func GetGitHubToken() string {
-	// Check environment variables in order of precedence
-	envVars := []string{
-		EnvGitHubToken,
-		EnvGitHubToken2,
-		EnvCascadeToken,
-		EnvGitHubAccessToken,
+	if envVar := gitHubTokenEnv(); envVar != "" {
+		return strings.TrimSpace(os.Getenv(envVar))
 	}
+	return ""
+}
 
-	for _, envVar := range envVars {
-		if token := os.Getenv(envVar); token != "" {
-			return strings.TrimSpace(token)
+// gitHubTokenEnv returns the name of the first environment variable, in the
+// precedence of GetGitHubToken, that holds a GitHub token, or "".
+func gitHubTokenEnv() string {
+	for _, envVar := range []string{EnvGitHubToken, EnvGitHubToken2, EnvCascadeToken, EnvGitHubAccessToken} {
+		if os.Getenv(envVar) != "" {
+			return envVar
 		}
 	}
-
 	return ""
 }
 
+// GitHubCredentialEnv returns GIT_CONFIG_* variables that add a git credential
+// helper for https://github.com answering with the GitHub token, so git
+// commands started by the go command, such as direct fetches of private
+// modules, authenticate the way cascade does. The helper reads the token from
+// its environment variable when git runs it, so the token never appears in
+// the configuration. Entries already configured through GIT_CONFIG_COUNT are
+// kept. It returns nil when no token is set.
+func GitHubCredentialEnv() map[string]string {
+	envVar := gitHubTokenEnv()
+	if envVar == "" {
+		return nil
+	}
+	n, _ := strconv.Atoi(os.Getenv("GIT_CONFIG_COUNT"))
+	index := strconv.Itoa(n)
+	return map[string]string{
+		"GIT_CONFIG_KEY_" + index:   "credential.https://github.com.helper",
+		"GIT_CONFIG_VALUE_" + index: fmt.Sprintf(`!f() { test "$1" = get && printf 'username=x-access-token\npassword=%%s\n' "$%s"; }; f`, envVar),
+		"GIT_CONFIG_COUNT":          strconv.Itoa(n + 1),
+	}
+}
+
 // GetGitHubTokenOrError retrieves a GitHub token or returns an error if not found.
 // This is useful when a token is required for an operation.
 func GetGitHubTokenOrError() (string, error) {
@@ -3,6 +3,7 @@ package gitutil
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -143,6 +144,27 @@ func TestGetGitHubTokenOrError(t *testing.T) {
 	}
 }
 
+func TestGitHubCredentialEnv(t *testing.T) {
+	for _, envVar := range []string{EnvGitHubToken, EnvGitHubToken2, EnvCascadeToken, EnvGitHubAccessToken} {
+		t.Setenv(envVar, "")
+	}
+	t.Setenv("GIT_CONFIG_COUNT", "")
+	if env := GitHubCredentialEnv(); env != nil {
+		t.Errorf("GitHubCredentialEnv() without a token = %v, want nil", env)
+	}
+
+	t.Setenv(EnvGitHubToken2, "secret")
+	t.Setenv("GIT_CONFIG_COUNT", "2")
+	env := GitHubCredentialEnv()
+	if env["GIT_CONFIG_COUNT"] != "3" || env["GIT_CONFIG_KEY_2"] != "credential.https://github.com.helper" {
+		t.Errorf("GitHubCredentialEnv() = %v, want a third config entry for github.com", env)
+	}
+	helper := env["GIT_CONFIG_VALUE_2"]
+	if !strings.Contains(helper, `"$GH_TOKEN"`) || strings.Contains(helper, "secret") {
+		t.Errorf("helper %q should read the token from GH_TOKEN without embedding it", helper)
+	}
+}
+
 func TestGetSSHKeyPath(t *testing.T) {
 	tests := []struct {
 		name       string
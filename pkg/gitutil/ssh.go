@@ -0,0 +1,84 @@
+package gitutil
+
+import (
+	"strings"
+)
+
+// Environment variable names read by git and go-git for SSH remotes.
+const (
+	// EnvGitSSHCommand is the ssh command line git runs for SSH remotes
+	EnvGitSSHCommand = "GIT_SSH_COMMAND"
+
+	// EnvSSHKnownHosts lists the known_hosts files go-git checks host keys against
+	EnvSSHKnownHosts = "SSH_KNOWN_HOSTS"
+
+	// EnvSSHAuthSock is the socket of a running ssh-agent
+	EnvSSHAuthSock = "SSH_AUTH_SOCK"
+)
+
+// SSHOptions selects the key and host keys used for SSH clones and pushes.
+type SSHOptions struct {
+	// KeyPath is the private key offered to SSH servers.
+	KeyPath string
+
+	// Agent offers the keys loaded in ssh-agent alongside KeyPath. Without
+	// KeyPath, ssh uses the agent on its own.
+	Agent bool
+
+	// KnownHosts is the known_hosts file host keys are checked against. Hosts
+	// missing from it are rejected.
+	KnownHosts string
+}
+
+// SSHEnv returns the environment that makes git commands, including those the
+// go command starts, and go-git clones use opts: GIT_SSH_COMMAND with the key
+// and known_hosts file appended to the current command, SSH_KEY_PATH, and
+// SSH_KNOWN_HOSTS. Current values are read with getenv. It returns an empty
+// map when opts sets neither a key nor a known_hosts file.
+func SSHEnv(opts SSHOptions, getenv func(string) string) map[string]string {
+	env := make(map[string]string)
+	keyPath := strings.TrimSpace(opts.KeyPath)
+	knownHosts := strings.TrimSpace(opts.KnownHosts)
+	if keyPath == "" && knownHosts == "" {
+		return env
+	}
+
+	command := strings.TrimSpace(getenv(EnvGitSSHCommand))
+	if command == "" {
+		command = "ssh"
+	}
+	// Fail instead of prompting for passphrases or unknown host keys
+	args := []string{command, "-o BatchMode=yes"}
+	if keyPath != "" {
+		args = append(args, "-i "+shellQuote(keyPath))
+		if !opts.Agent {
+			args = append(args, "-o IdentitiesOnly=yes")
+		}
+		env[EnvSSHKeyPath] = keyPath
+	}
+	if knownHosts != "" {
+		args = append(args, "-o UserKnownHostsFile="+shellQuote(knownHosts), "-o StrictHostKeyChecking=yes")
+		env[EnvSSHKnownHosts] = knownHosts
+	}
+	env[EnvGitSSHCommand] = strings.Join(args, " ")
+	return env
+}
+
+// IsSSHURL reports whether cloneURL is an ssh:// URL or an scp-style address
+// such as git@host:owner/repo.git.
+func IsSSHURL(cloneURL string) bool {
+	if strings.HasPrefix(cloneURL, "ssh://") || strings.HasPrefix(cloneURL, "git+ssh://") {
+		return true
+	}
+	if strings.Contains(cloneURL, "://") {
+		return false
+	}
+	at := strings.Index(cloneURL, "@")
+	colon := strings.Index(cloneURL, ":")
+	return at > 0 && colon > at && !strings.Contains(cloneURL[:colon], "/")
+}
+
+// shellQuote quotes s for the shell git runs GIT_SSH_COMMAND with.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,44 @@
+package gitutil
+
+import (
+	"testing"
+)
+
+func TestSSHEnv(t *testing.T) {
+	getenv := func(values map[string]string) func(string) string {
+		return func(key string) string { return values[key] }
+	}
+
+	if env := SSHEnv(SSHOptions{Agent: true}, getenv(nil)); len(env) != 0 {
+		t.Errorf("SSHEnv() without a key or known_hosts = %v, want empty", env)
+	}
+
+	env := SSHEnv(SSHOptions{KeyPath: "/keys/deploy key", KnownHosts: "/etc/cascade/known_hosts"}, getenv(nil))
+	want := `ssh -o BatchMode=yes -i '/keys/deploy key' -o IdentitiesOnly=yes -o UserKnownHostsFile='/etc/cascade/known_hosts' -o StrictHostKeyChecking=yes`
+	if env[EnvGitSSHCommand] != want {
+		t.Errorf("GIT_SSH_COMMAND = %q, want %q", env[EnvGitSSHCommand], want)
+	}
+	if env[EnvSSHKeyPath] != "/keys/deploy key" || env[EnvSSHKnownHosts] != "/etc/cascade/known_hosts" {
+		t.Errorf("SSHEnv() = %v, want SSH_KEY_PATH and SSH_KNOWN_HOSTS", env)
+	}
+
+	env = SSHEnv(SSHOptions{KeyPath: "/keys/id", Agent: true}, getenv(map[string]string{EnvGitSSHCommand: "ssh -v"}))
+	if got := env[EnvGitSSHCommand]; got != `ssh -v -o BatchMode=yes -i '/keys/id'` {
+		t.Errorf("GIT_SSH_COMMAND = %q, want the existing command extended without IdentitiesOnly", got)
+	}
+}
+
+func TestIsSSHURL(t *testing.T) {
+	tests := map[string]bool{
+		"git@github.com:owner/repo.git":               true,
+		"ssh://git@git.example.com:2222/team/app.git": true,
+		"https://github.com/owner/repo.git":           false,
+		"https://user@github.com/owner/repo.git":      false,
+		"github.com/owner/repo":                       false,
+	}
+	for url, want := range tests {
+		if got := IsSSHURL(url); got != want {
+			t.Errorf("IsSSHURL(%q) = %v, want %v", url, got, want)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+// Package goproxy talks to Go module proxies, such as Athens or Artifactory,
+// through the GOPROXY protocol. Its main use is waiting for a freshly tagged
+// version to become fetchable before dependents run go get against it.
+package goproxy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/module"
+)
+
+// ErrNotFound is returned when the proxy does not serve the requested version.
+var ErrNotFound = errors.New("version not found on proxy")
+
+// Info is the metadata a proxy returns for a module version.
+type Info struct {
+	Version string    `json:"Version"`
+	Time    time.Time `json:"Time"`
+}
+
+// StatusError reports an unexpected HTTP status from the proxy.
+type StatusError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *StatusError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("proxy returned status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("proxy returned status %d: %s", e.StatusCode, e.Message)
+}
+
+// Client queries one module proxy. Create one with New.
+type Client struct {
+	baseURL    string
+	username   string
+	token      string
+	httpClient *http.Client
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used for requests.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Client) {
+		if client != nil {
+			c.httpClient = client
+		}
+	}
+}
+
+// WithToken authenticates requests with token. Without a username the token is
+// sent as a bearer token; with one, as the basic auth password.
+func WithToken(username, token string) Option {
+	return func(c *Client) {
+		c.username = username
+		c.token = token
+	}
+}
+
+// New returns a client for the proxy at baseURL, the value that would appear
+// in GOPROXY.
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(strings.TrimSpace(baseURL), "/"),
+		httpClient: http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// URL returns the proxy base URL.
+func (c *Client) URL() string {
+	return c.baseURL
+}
+
+// Info fetches the metadata of mod@version. Proxies that mirror an origin,
+// as Athens and Artifactory remote repositories do, fetch and store the
+// version the first time it is requested, so Info also triggers publication.
+func (c *Client) Info(ctx context.Context, mod, version string) (*Info, error) {
+	escapedPath, err := module.EscapePath(mod)
+	if err != nil {
+		return nil, fmt.Errorf("invalid module path %q: %w", mod, err)
+	}
+	escapedVersion, err := module.EscapeVersion(version)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version %q: %w", version, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/"+escapedPath+"/@v/"+escapedVersion+".info", nil)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case c.username != "":
+		req.SetBasicAuth(c.username, c.token)
+	case c.token != "":
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return nil, fmt.Errorf("%s@%s: %w", mod, version, ErrNotFound)
+	case resp.StatusCode != http.StatusOK:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, &StatusError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(body))}
+	}
+
+	var info Info
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, fmt.Errorf("failed to decode proxy response for %s@%s: %w", mod, version, err)
+	}
+	return &info, nil
+}
+
+// Wait polls Info every interval until the proxy serves mod@version or ctx is
+// done. Missing versions, server errors, and network failures are retried;
+// other responses, such as rejected credentials, fail at once.
+func (c *Client) Wait(ctx context.Context, mod, version string, interval time.Duration) (*Info, error) {
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	var lastErr error
+	for {
+		info, err := c.Info(ctx, mod, version)
+		if err == nil {
+			return info, nil
+		}
+		if ctx.Err() == nil {
+			// Requests cut short by ctx say nothing about the proxy
+			if !retryable(err) {
+				return nil, err
+			}
+			lastErr = err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%s@%s is not available on %s: %w", mod, version, c.baseURL, errors.Join(ctx.Err(), lastErr))
+		case <-ticker.C:
+		}
+	}
+}
+
+// retryable reports whether a failed Info call may succeed later.
+func retryable(err error) bool {
+	if errors.Is(err, ErrNotFound) {
+		return true
+	}
+	var status *StatusError
+	if errors.As(err, &status) {
+		return status.StatusCode >= http.StatusInternalServerError || status.StatusCode == http.StatusTooManyRequests
+	}
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
@@ -0,0 +1,120 @@
+package goproxy
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_Info(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/proxy/github.com/!example/lib/@v/v1.2.3.info" {
+			http.NotFound(w, r)
+			return
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Authorization = %q, want bearer token", got)
+		}
+		w.Write([]byte(`{"Version":"v1.2.3","Time":"2024-05-01T10:00:00Z"}`))
+	}))
+	defer server.Close()
+
+	client := New(server.URL+"/proxy/", WithToken("", "secret"))
+	info, err := client.Info(context.Background(), "github.com/Example/lib", "v1.2.3")
+	if err != nil {
+		t.Fatalf("Info() error = %v", err)
+	}
+	if info.Version != "v1.2.3" || info.Time.IsZero() {
+		t.Errorf("Info() = %+v", info)
+	}
+
+	if _, err := client.Info(context.Background(), "github.com/Example/lib", "v9.9.9"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for missing version, got %v", err)
+	}
+}
+
+func TestClient_InfoBasicAuth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "ci" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte("bad credentials"))
+			return
+		}
+		w.Write([]byte(`{"Version":"v1.0.0"}`))
+	}))
+	defer server.Close()
+
+	if _, err := New(server.URL, WithToken("ci", "secret")).Info(context.Background(), "example.com/lib", "v1.0.0"); err != nil {
+		t.Fatalf("Info() error = %v", err)
+	}
+
+	_, err := New(server.URL).Info(context.Background(), "example.com/lib", "v1.0.0")
+	var status *StatusError
+	if !errors.As(err, &status) || status.StatusCode != http.StatusUnauthorized || status.Message != "bad credentials" {
+		t.Errorf("expected 401 StatusError, got %v", err)
+	}
+}
+
+func TestClient_WaitPollsUntilAvailable(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch calls.Add(1) {
+		case 1:
+			http.NotFound(w, r)
+		case 2:
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			w.Write([]byte(`{"Version":"v1.2.3"}`))
+		}
+	}))
+	defer server.Close()
+
+	info, err := New(server.URL).Wait(context.Background(), "example.com/lib", "v1.2.3", time.Millisecond)
+	if err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	if info.Version != "v1.2.3" || calls.Load() != 3 {
+		t.Errorf("Wait() = %+v after %d calls, want v1.2.3 after 3", info, calls.Load())
+	}
+}
+
+func TestClient_WaitTimesOut(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := New(server.URL).Wait(ctx, "example.com/lib", "v1.2.3", time.Millisecond)
+	if !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected deadline and not found errors, got %v", err)
+	}
+}
+
+func TestClient_WaitFailsFastOnRejectedRequest(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	_, err := New(server.URL).Wait(context.Background(), "example.com/lib", "v1.2.3", time.Millisecond)
+	var status *StatusError
+	if !errors.As(err, &status) || status.StatusCode != http.StatusForbidden {
+		t.Fatalf("expected 403 StatusError, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("expected one request, got %d", calls.Load())
+	}
+}
+
+func TestClient_InfoRejectsInvalidVersion(t *testing.T) {
+	if _, err := New("https://proxy.example.com").Wait(context.Background(), "example.com/lib", "v1.0.0\n", time.Millisecond); err == nil {
+		t.Error("expected error for invalid version")
+	}
+}
@@ -0,0 +1,67 @@
+package goproxy
+
+import (
+	"strings"
+
+	"golang.org/x/mod/module"
+)
+
+// NoProxyPatterns returns the comma-separated module path patterns the go
+// command fetches from their origin instead of a proxy: GONOPROXY, or
+// GOPRIVATE when GONOPROXY is not set, as read with getenv.
+func NoProxyPatterns(getenv func(string) string) string {
+	if patterns := strings.TrimSpace(getenv("GONOPROXY")); patterns != "" {
+		return patterns
+	}
+	return strings.TrimSpace(getenv("GOPRIVATE"))
+}
+
+// IsPrivate reports whether the go command fetches modulePath directly rather
+// than through GOPROXY, according to the patterns NoProxyPatterns returns.
+func IsPrivate(modulePath string, getenv func(string) string) bool {
+	patterns := NoProxyPatterns(getenv)
+	return patterns != "" && module.MatchPrefixPatterns(patterns, modulePath)
+}
+
+// PrivateEnv returns the environment that makes the go command treat modules
+// matching patterns as private: GOPRIVATE with patterns appended, and
+// GONOPROXY and GONOSUMDB as well when they are set, since they take
+// precedence over GOPRIVATE. Current values are read with getenv. Variables
+// that already list every pattern are left out, so the result is empty when
+// nothing needs to change.
+func PrivateEnv(patterns []string, getenv func(string) string) map[string]string {
+	env := make(map[string]string)
+	for _, key := range []string{"GOPRIVATE", "GONOPROXY", "GONOSUMDB"} {
+		base := strings.TrimSpace(getenv(key))
+		if key != "GOPRIVATE" && base == "" {
+			continue
+		}
+		list := base
+		for _, pattern := range patterns {
+			list = AppendPattern(list, pattern)
+		}
+		if list != base {
+			env[key] = list
+		}
+	}
+	return env
+}
+
+// AppendPattern adds pattern to list, a comma-separated module path pattern
+// list such as GOPRIVATE, unless pattern is blank or already listed. A blank
+// list is replaced by pattern.
+func AppendPattern(list, pattern string) string {
+	pattern = strings.TrimSpace(pattern)
+	if pattern == "" {
+		return list
+	}
+	for _, existing := range strings.Split(list, ",") {
+		if strings.TrimSpace(existing) == pattern {
+			return list
+		}
+	}
+	if strings.TrimSpace(list) == "" {
+		return pattern
+	}
+	return list + "," + pattern
+}
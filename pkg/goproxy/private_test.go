@@ -0,0 +1,83 @@
+package goproxy
+
+import (
+	"reflect"
+	"testing"
+)
+
+func envFunc(vars map[string]string) func(string) string {
+	return func(key string) string { return vars[key] }
+}
+
+func TestIsPrivate(t *testing.T) {
+	tests := []struct {
+		name   string
+		env    map[string]string
+		module string
+		want   bool
+	}{
+		{name: "no patterns", module: "github.com/acme/lib", want: false},
+		{name: "GOPRIVATE prefix", env: map[string]string{"GOPRIVATE": "github.com/acme"}, module: "github.com/acme/lib/v2", want: true},
+		{name: "GOPRIVATE glob", env: map[string]string{"GOPRIVATE": "*.corp.example.com"}, module: "git.corp.example.com/team/lib", want: true},
+		{name: "other module", env: map[string]string{"GOPRIVATE": "github.com/acme"}, module: "github.com/acmeco/lib", want: false},
+		{name: "GONOPROXY overrides GOPRIVATE", env: map[string]string{"GOPRIVATE": "github.com/acme", "GONOPROXY": "git.example.com"}, module: "github.com/acme/lib", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsPrivate(tt.module, envFunc(tt.env)); got != tt.want {
+				t.Errorf("IsPrivate(%q) = %v, want %v", tt.module, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrivateEnv(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		patterns []string
+		want     map[string]string
+	}{
+		{
+			name:     "sets GOPRIVATE",
+			patterns: []string{"git.example.com/*", "github.com/acme"},
+			want:     map[string]string{"GOPRIVATE": "git.example.com/*,github.com/acme"},
+		},
+		{
+			name:     "extends GONOPROXY and GONOSUMDB when set",
+			env:      map[string]string{"GOPRIVATE": "github.com/other", "GONOSUMDB": "github.com/other"},
+			patterns: []string{"github.com/acme"},
+			want:     map[string]string{"GOPRIVATE": "github.com/other,github.com/acme", "GONOSUMDB": "github.com/other,github.com/acme"},
+		},
+		{
+			name:     "already listed",
+			env:      map[string]string{"GOPRIVATE": "github.com/acme"},
+			patterns: []string{"github.com/acme", " "},
+			want:     map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PrivateEnv(tt.patterns, envFunc(tt.env)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PrivateEnv() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAppendPattern(t *testing.T) {
+	tests := []struct {
+		list, pattern, want string
+	}{
+		{list: "", pattern: "github.com/acme", want: "github.com/acme"},
+		{list: "  ", pattern: "github.com/acme", want: "github.com/acme"},
+		{list: "github.com/other", pattern: " github.com/acme ", want: "github.com/other,github.com/acme"},
+		{list: "github.com/other, github.com/acme", pattern: "github.com/acme", want: "github.com/other, github.com/acme"},
+		{list: "github.com/other", pattern: " ", want: "github.com/other"},
+	}
+	for _, tt := range tests {
+		if got := AppendPattern(tt.list, tt.pattern); got != tt.want {
+			t.Errorf("AppendPattern(%q, %q) = %q, want %q", tt.list, tt.pattern, got, tt.want)
+		}
+	}
+}
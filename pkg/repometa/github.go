@@ -0,0 +1,64 @@
+package repometa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-github/v66/github"
+)
+
+// GitHubFetcher reads repository metadata from the GitHub REST API.
+type GitHubFetcher struct {
+	client *github.Client
+}
+
+// NewGitHubFetcher creates a fetcher using client.
+func NewGitHubFetcher(client *github.Client) *GitHubFetcher {
+	return &GitHubFetcher{client: client}
+}
+
+// FetchRepository implements Fetcher.
+func (f *GitHubFetcher) FetchRepository(ctx context.Context, repo string) (*Metadata, error) {
+	parts := strings.Split(strings.TrimSuffix(repo, ".git"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("invalid repository %q: expected owner/repo", repo)
+	}
+
+	ghRepo, _, err := f.client.Repositories.Get(ctx, parts[0], parts[1])
+	if err != nil {
+		var errResp *github.ErrorResponse
+		if errors.As(err, &errResp) && errResp.Response != nil && errResp.Response.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("%s: %w", repo, ErrNotFound)
+		}
+		return nil, fmt.Errorf("get repository %s: %w", repo, err)
+	}
+
+	meta := FromGitHubRepository(ghRepo)
+	meta.Repo = repo
+	return &meta, nil
+}
+
+// FromGitHubRepository converts an API repository object. Search results carry the
+// same fields, so they can be stored directly.
+func FromGitHubRepository(repo *github.Repository) Metadata {
+	visibility := repo.GetVisibility()
+	if visibility == "" {
+		visibility = VisibilityPublic
+		if repo.GetPrivate() {
+			visibility = VisibilityPrivate
+		}
+	}
+	return Metadata{
+		Repo:          repo.GetFullName(),
+		DefaultBranch: repo.GetDefaultBranch(),
+		Archived:      repo.GetArchived(),
+		Visibility:    visibility,
+		CloneURL:      repo.GetCloneURL(),
+		SSHURL:        repo.GetSSHURL(),
+		Topics:        repo.Topics,
+		PushedAt:      repo.GetPushedAt().Time,
+	}
+}
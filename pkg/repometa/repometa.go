@@ -0,0 +1,211 @@
+// Package repometa provides cached repository metadata (default branch, archived
+// state, visibility, clone URLs, topics) shared by discovery, planning, and the
+// broker so a run looks each repository up at most once.
+package repometa
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultTTL is how long metadata is reused when no TTL is configured.
+const DefaultTTL = 15 * time.Minute
+
+// Visibility values reported by providers.
+const (
+	VisibilityPublic   = "public"
+	VisibilityPrivate  = "private"
+	VisibilityInternal = "internal"
+)
+
+// ErrNotFound is returned by fetchers when the repository does not exist or is not
+// visible to the configured credentials.
+var ErrNotFound = errors.New("repository not found")
+
+// Metadata describes a hosted repository.
+type Metadata struct {
+	// Repo is the owner/repo identifier used to look the repository up
+	Repo string
+
+	DefaultBranch string
+	Archived      bool
+	Visibility    string
+	CloneURL      string
+	SSHURL        string
+	Topics        []string
+
+	// PushedAt is when the repository last received a push, zero when unknown
+	PushedAt time.Time
+}
+
+// Private reports whether the repository is not publicly visible.
+func (m Metadata) Private() bool {
+	return m.Visibility != "" && m.Visibility != VisibilityPublic
+}
+
+// HasTopic reports whether topic is set on the repository, ignoring case.
+func (m Metadata) HasTopic(topic string) bool {
+	for _, t := range m.Topics {
+		if strings.EqualFold(t, topic) {
+			return true
+		}
+	}
+	return false
+}
+
+// Fetcher loads metadata from a hosting provider.
+type Fetcher interface {
+	FetchRepository(ctx context.Context, repo string) (*Metadata, error)
+}
+
+// FetcherFunc adapts a function to the Fetcher interface.
+type FetcherFunc func(ctx context.Context, repo string) (*Metadata, error)
+
+// FetchRepository calls f.
+func (f FetcherFunc) FetchRepository(ctx context.Context, repo string) (*Metadata, error) {
+	return f(ctx, repo)
+}
+
+// Stats counts cache activity.
+type Stats struct {
+	Hits    int
+	Misses  int
+	Fetches int
+	Errors  int
+}
+
+// Service caches metadata per repository. Concurrent lookups of the same
+// repository share a single fetch, and not-found results are cached like hits so
+// missing repositories are not queried again. Other errors are not cached.
+type Service struct {
+	fetcher Fetcher
+	ttl     time.Duration
+	now     func() time.Time
+
+	mu       sync.Mutex
+	entries  map[string]entry
+	inflight map[string]*call
+	stats    Stats
+}
+
+type entry struct {
+	meta    *Metadata
+	err     error
+	expires time.Time
+}
+
+type call struct {
+	done chan struct{}
+	meta *Metadata
+	err  error
+}
+
+// Option customises a Service.
+type Option func(*Service)
+
+// WithTTL sets how long metadata is reused. Non-positive values keep the default.
+func WithTTL(ttl time.Duration) Option {
+	return func(s *Service) {
+		if ttl > 0 {
+			s.ttl = ttl
+		}
+	}
+}
+
+// WithClock overrides the time source, for tests.
+func WithClock(now func() time.Time) Option {
+	return func(s *Service) {
+		if now != nil {
+			s.now = now
+		}
+	}
+}
+
+// NewService creates a metadata service backed by fetcher.
+func NewService(fetcher Fetcher, opts ...Option) *Service {
+	s := &Service{
+		fetcher:  fetcher,
+		ttl:      DefaultTTL,
+		now:      time.Now,
+		entries:  make(map[string]entry),
+		inflight: make(map[string]*call),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Get returns the metadata of repo (owner/repo), fetching it on a cache miss.
+// The returned value is shared and must not be modified.
+func (s *Service) Get(ctx context.Context, repo string) (*Metadata, error) {
+	key := cacheKey(repo)
+	if key == "" {
+		return nil, errors.New("repository is required")
+	}
+
+	s.mu.Lock()
+	if e, ok := s.entries[key]; ok && s.now().Before(e.expires) {
+		s.stats.Hits++
+		s.mu.Unlock()
+		return e.meta, e.err
+	}
+	s.stats.Misses++
+	if c, ok := s.inflight[key]; ok {
+		s.mu.Unlock()
+		select {
+		case <-c.done:
+			return c.meta, c.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	c := &call{done: make(chan struct{})}
+	s.inflight[key] = c
+	s.stats.Fetches++
+	s.mu.Unlock()
+
+	c.meta, c.err = s.fetcher.FetchRepository(ctx, strings.TrimSpace(repo))
+
+	s.mu.Lock()
+	delete(s.inflight, key)
+	switch {
+	case c.err == nil:
+		s.entries[key] = entry{meta: c.meta, expires: s.now().Add(s.ttl)}
+	case errors.Is(c.err, ErrNotFound):
+		s.entries[key] = entry{err: c.err, expires: s.now().Add(s.ttl)}
+	default:
+		s.stats.Errors++
+	}
+	s.mu.Unlock()
+	close(c.done)
+
+	return c.meta, c.err
+}
+
+// Store records metadata obtained elsewhere, such as from a search or listing
+// response, so later lookups are served from the cache.
+func (s *Service) Store(meta Metadata) {
+	key := cacheKey(meta.Repo)
+	if key == "" {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[key] = entry{meta: &meta, expires: s.now().Add(s.ttl)}
+}
+
+// Stats returns the cache counters.
+func (s *Service) Stats() Stats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stats
+}
+
+// cacheKey normalises owner/repo identifiers; GitHub names are case-insensitive.
+func cacheKey(repo string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(repo), ".git"))
+}
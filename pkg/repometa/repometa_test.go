@@ -0,0 +1,205 @@
+package repometa
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+)
+
+func TestService_CachesLookups(t *testing.T) {
+	var fetches atomic.Int32
+	fetcher := FetcherFunc(func(ctx context.Context, repo string) (*Metadata, error) {
+		fetches.Add(1)
+		return &Metadata{Repo: repo, DefaultBranch: "main"}, nil
+	})
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	svc := NewService(fetcher, WithTTL(time.Minute), WithClock(func() time.Time { return now }))
+	ctx := context.Background()
+
+	for _, repo := range []string{"Owner/App", "owner/app", "owner/app.git"} {
+		meta, err := svc.Get(ctx, repo)
+		if err != nil {
+			t.Fatalf("Get(%s) error: %v", repo, err)
+		}
+		if meta.DefaultBranch != "main" {
+			t.Fatalf("unexpected metadata: %+v", meta)
+		}
+	}
+	if got := fetches.Load(); got != 1 {
+		t.Fatalf("expected one fetch for the same repository, got %d", got)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, err := svc.Get(ctx, "owner/app"); err != nil {
+		t.Fatalf("Get after expiry error: %v", err)
+	}
+	if got := fetches.Load(); got != 2 {
+		t.Fatalf("expected refetch after TTL, got %d fetches", got)
+	}
+
+	stats := svc.Stats()
+	if stats.Hits != 2 || stats.Misses != 2 || stats.Fetches != 2 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestService_SharesConcurrentFetches(t *testing.T) {
+	var fetches atomic.Int32
+	release := make(chan struct{})
+	fetcher := FetcherFunc(func(ctx context.Context, repo string) (*Metadata, error) {
+		fetches.Add(1)
+		<-release
+		return &Metadata{Repo: repo, Archived: true}, nil
+	})
+	svc := NewService(fetcher)
+
+	var wg sync.WaitGroup
+	results := make([]*Metadata, 5)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = svc.Get(context.Background(), "owner/app")
+		}(i)
+	}
+
+	// Let every goroutine reach the cache before the fetch completes
+	for svc.Stats().Misses < len(results) {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	if got := fetches.Load(); got != 1 {
+		t.Fatalf("expected concurrent lookups to share one fetch, got %d", got)
+	}
+	for i, meta := range results {
+		if meta == nil || !meta.Archived {
+			t.Fatalf("result %d missing metadata: %+v", i, meta)
+		}
+	}
+}
+
+func TestService_ErrorCaching(t *testing.T) {
+	var fetches atomic.Int32
+	fetcher := FetcherFunc(func(ctx context.Context, repo string) (*Metadata, error) {
+		fetches.Add(1)
+		if repo == "owner/missing" {
+			return nil, ErrNotFound
+		}
+		return nil, errors.New("connection reset")
+	})
+	svc := NewService(fetcher)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := svc.Get(ctx, "owner/missing"); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("expected ErrNotFound, got %v", err)
+		}
+		if _, err := svc.Get(ctx, "owner/flaky"); err == nil {
+			t.Fatal("expected transient error")
+		}
+	}
+
+	// Not found is cached, transient failures are retried
+	if got := fetches.Load(); got != 3 {
+		t.Fatalf("expected 3 fetches, got %d", got)
+	}
+	if stats := svc.Stats(); stats.Errors != 2 {
+		t.Errorf("expected 2 errors counted, got %+v", stats)
+	}
+
+	if _, err := svc.Get(ctx, " "); err == nil {
+		t.Error("expected error for empty repository")
+	}
+}
+
+func TestService_Store(t *testing.T) {
+	fetcher := FetcherFunc(func(ctx context.Context, repo string) (*Metadata, error) {
+		t.Fatalf("unexpected fetch for %s", repo)
+		return nil, nil
+	})
+	svc := NewService(fetcher)
+	svc.Store(Metadata{Repo: "Owner/App", DefaultBranch: "develop", Topics: []string{"Go"}})
+
+	meta, err := svc.Get(context.Background(), "owner/app")
+	if err != nil {
+		t.Fatalf("Get error: %v", err)
+	}
+	if meta.DefaultBranch != "develop" || !meta.HasTopic("go") {
+		t.Fatalf("unexpected metadata: %+v", meta)
+	}
+}
+
+type routeTransport map[string]*http.Response
+
+func (r routeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if resp, ok := r[req.URL.Path]; ok {
+		resp.Request = req
+		return resp, nil
+	}
+	return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader(`{"message":"Not Found"}`)), Header: make(http.Header), Request: req}, nil
+}
+
+func TestGitHubFetcher(t *testing.T) {
+	body, _ := json.Marshal(map[string]any{
+		"full_name":      "owner/app",
+		"default_branch": "trunk",
+		"archived":       true,
+		"private":        true,
+		"visibility":     "internal",
+		"clone_url":      "https://github.com/owner/app.git",
+		"ssh_url":        "git@github.com:owner/app.git",
+		"topics":         []string{"go", "cascade"},
+	})
+	client := github.NewClient(&http.Client{Transport: routeTransport{
+		"/repos/owner/app": {StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(string(body))), Header: http.Header{"Content-Type": {"application/json"}}},
+	}})
+	fetcher := NewGitHubFetcher(client)
+
+	meta, err := fetcher.FetchRepository(context.Background(), "owner/app")
+	if err != nil {
+		t.Fatalf("FetchRepository error: %v", err)
+	}
+	want := Metadata{
+		Repo:          "owner/app",
+		DefaultBranch: "trunk",
+		Archived:      true,
+		Visibility:    VisibilityInternal,
+		CloneURL:      "https://github.com/owner/app.git",
+		SSHURL:        "git@github.com:owner/app.git",
+		Topics:        []string{"go", "cascade"},
+	}
+	if meta.Repo != want.Repo || meta.DefaultBranch != want.DefaultBranch || !meta.Archived || meta.Visibility != want.Visibility ||
+		meta.CloneURL != want.CloneURL || meta.SSHURL != want.SSHURL || !meta.HasTopic("cascade") || !meta.Private() {
+		t.Fatalf("metadata = %+v, want %+v", meta, want)
+	}
+
+	if _, err := fetcher.FetchRepository(context.Background(), "owner/missing"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if _, err := fetcher.FetchRepository(context.Background(), "github.com/owner/app"); err == nil {
+		t.Fatal("expected error for non owner/repo identifier")
+	}
+}
+
+func TestFromGitHubRepository_DerivesVisibility(t *testing.T) {
+	meta := FromGitHubRepository(&github.Repository{FullName: github.String("owner/app"), Private: github.Bool(true)})
+	if meta.Visibility != VisibilityPrivate {
+		t.Errorf("expected private visibility, got %s", meta.Visibility)
+	}
+	meta = FromGitHubRepository(&github.Repository{FullName: github.String("owner/app")})
+	if meta.Visibility != VisibilityPublic || meta.Private() {
+		t.Errorf("expected public visibility, got %s", meta.Visibility)
+	}
+}
@@ -0,0 +1,356 @@
+// Package repourl builds git clone URLs from repository identifiers. Hosts other
+// than github.com, such as GitHub Enterprise Server or a self-hosted GitLab, are
+// described with Host entries, and URL rewrites mirror git's url.<base>.insteadOf.
+package repourl
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultHost is the host assumed for owner/repo shorthands.
+const DefaultHost = "github.com"
+
+// Protocol selects the transport of generated clone URLs.
+type Protocol string
+
+const (
+	ProtocolHTTPS Protocol = "https"
+	ProtocolSSH   Protocol = "ssh"
+)
+
+// ErrEmptyRepo is returned when a repository identifier is empty.
+var ErrEmptyRepo = errors.New("repository identifier is empty")
+
+// Host describes how repositories on one git host are cloned.
+type Host struct {
+	// Name is the host as it appears in repository identifiers and module paths,
+	// e.g. "github.example.com".
+	Name string
+
+	// BaseURL is the HTTPS base clones are served from. It may include a path
+	// prefix, e.g. "https://git.example.com/scm". Default: "https://" + Name.
+	BaseURL string
+
+	// Protocol selects HTTPS or SSH clone URLs. Default: https.
+	Protocol Protocol
+
+	// SSHUser is the SSH login. Default: "git".
+	SSHUser string
+
+	// SSHHost is the SSH server when it differs from Name.
+	SSHHost string
+
+	// SSHPort is the SSH port. Zero and 22 produce scp-style URLs
+	// (git@host:owner/repo.git); other ports use ssh:// URLs.
+	SSHPort int
+
+	// Subgroups keeps every path segment after the host, as GitLab groups need.
+	// Otherwise only owner/repo are used and deeper segments, such as the
+	// directory of a nested module, are dropped.
+	Subgroups bool
+}
+
+// Parts are the components of a repository identifier.
+type Parts struct {
+	// Host is the lower-cased git host. It is empty for a bare repository name.
+	Host string
+
+	// Owner is the path between the host and the repository, including any
+	// subgroups, e.g. "group/subgroup" on GitLab.
+	Owner string
+
+	// Repo is the repository name without the .git suffix.
+	Repo string
+}
+
+// Rewrite replaces the InsteadOf prefix of a clone URL with Base, like git's
+// url.<base>.insteadOf setting.
+type Rewrite struct {
+	Base      string
+	InsteadOf string
+}
+
+// builtinHosts are the public hosts known without configuration.
+var builtinHosts = map[string]Host{
+	"github.com":    {Name: "github.com"},
+	"bitbucket.org": {Name: "bitbucket.org"},
+	"gitlab.com":    {Name: "gitlab.com", Subgroups: true},
+}
+
+// majorVersionSuffix matches the /vN element of module paths for major versions 2+.
+var majorVersionSuffix = regexp.MustCompile(`/v([2-9]|[1-9][0-9]+)$`)
+
+// Resolver builds clone URLs for configured hosts. The zero value is not usable;
+// create one with New.
+type Resolver struct {
+	defaultHost string
+	hosts       map[string]Host
+	rewrites    []Rewrite
+}
+
+// Option configures a Resolver.
+type Option func(*Resolver)
+
+// WithDefaultHost sets the host used for owner/repo shorthands. Empty values keep
+// github.com.
+func WithDefaultHost(name string) Option {
+	return func(r *Resolver) {
+		if name = normalizeHost(name); name != "" {
+			r.defaultHost = name
+		}
+	}
+}
+
+// WithHosts registers host settings, replacing built-in defaults for the same name.
+func WithHosts(hosts ...Host) Option {
+	return func(r *Resolver) {
+		for _, host := range hosts {
+			name := normalizeHost(host.Name)
+			if name == "" {
+				continue
+			}
+			host.Name = name
+			r.hosts[name] = host
+		}
+	}
+}
+
+// WithRewrites adds URL rewrites. When several prefixes match, the longest wins.
+func WithRewrites(rewrites ...Rewrite) Option {
+	return func(r *Resolver) {
+		for _, rw := range rewrites {
+			if rw.InsteadOf != "" {
+				r.rewrites = append(r.rewrites, rw)
+			}
+		}
+		sort.SliceStable(r.rewrites, func(i, j int) bool {
+			return len(r.rewrites[i].InsteadOf) > len(r.rewrites[j].InsteadOf)
+		})
+	}
+}
+
+// New returns a resolver configured by opts.
+func New(opts ...Option) *Resolver {
+	r := &Resolver{
+		defaultHost: DefaultHost,
+		hosts:       make(map[string]Host, len(builtinHosts)),
+	}
+	for name, host := range builtinHosts {
+		r.hosts[name] = host
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+var defaultResolver = New()
+
+// CloneURL builds a clone URL with the built-in host defaults.
+func CloneURL(repo string) (string, error) {
+	return defaultResolver.CloneURL(repo)
+}
+
+// Split breaks a repository identifier into its parts with the built-in host
+// defaults.
+func Split(repo string) (Parts, error) {
+	return defaultResolver.Split(repo)
+}
+
+// DefaultHost returns the host used for owner/repo shorthands.
+func (r *Resolver) DefaultHost() string {
+	return r.defaultHost
+}
+
+// HasHost reports whether name is a built-in or configured host.
+func (r *Resolver) HasHost(name string) bool {
+	_, ok := r.hosts[normalizeHost(name)]
+	return ok
+}
+
+// CloneURL converts a repository identifier into a clone URL. It accepts
+// owner/repo and group/subgroup/repo shorthands (on the default host),
+// host/owner/repo identifiers and module paths, and full HTTPS, ssh:// and
+// scp-style URLs, which are kept as they are. Rewrites apply to every result.
+func (r *Resolver) CloneURL(repo string) (string, error) {
+	return r.CloneURLWithProtocol(repo, "")
+}
+
+// CloneURLWithProtocol is CloneURL with protocol in place of the host's
+// configured protocol, for dependents that must be cloned over SSH or HTTPS
+// whatever their host's default. An empty protocol keeps the host's. Clone URLs
+// are kept as they are.
+func (r *Resolver) CloneURLWithProtocol(repo string, protocol Protocol) (string, error) {
+	repo = strings.TrimSpace(repo)
+	if repo == "" {
+		return "", ErrEmptyRepo
+	}
+	if IsURL(repo) {
+		return r.rewrite(repo), nil
+	}
+
+	name, segments, err := r.shorthand(repo)
+	if err != nil {
+		return "", err
+	}
+	if name == "" {
+		return "", fmt.Errorf("repository identifier %q must be owner/repo or host/owner/repo", repo)
+	}
+	host := r.host(name)
+	if protocol != "" {
+		host.Protocol = protocol
+	}
+
+	path := strings.Join(segments, "/")
+	if host.Subgroups {
+		path = majorVersionSuffix.ReplaceAllString(path, "")
+	} else {
+		path = strings.Join(segments[:2], "/")
+	}
+
+	return r.rewrite(host.url(path)), nil
+}
+
+// Split breaks a repository identifier into its host, owner and repository
+// name. It accepts the identifiers CloneURL does, as well as a bare repository
+// name, which has neither host nor owner. Shorthands resolve on the default
+// host; their first element only names the host when it is a configured host
+// or looks like a host name, so group/subgroup/repo stays on the default host.
+func (r *Resolver) Split(repo string) (Parts, error) {
+	repo = strings.TrimSpace(repo)
+	if repo == "" {
+		return Parts{}, ErrEmptyRepo
+	}
+
+	var (
+		host     string
+		segments []string
+		err      error
+	)
+	switch {
+	case strings.Contains(repo, "://"):
+		parsed, parseErr := url.Parse(repo)
+		if parseErr != nil {
+			return Parts{}, fmt.Errorf("invalid repository URL %q: %w", repo, parseErr)
+		}
+		host = parsed.Hostname()
+		segments, err = pathSegments(repo, parsed.Path)
+	case IsURL(repo):
+		var path string
+		host, path, _ = strings.Cut(repo[strings.Index(repo, "@")+1:], ":")
+		segments, err = pathSegments(repo, path)
+	default:
+		host, segments, err = r.shorthand(repo)
+	}
+	if err != nil {
+		return Parts{}, err
+	}
+
+	return Parts{
+		Host:  normalizeHost(host),
+		Owner: strings.Join(segments[:len(segments)-1], "/"),
+		Repo:  segments[len(segments)-1],
+	}, nil
+}
+
+// shorthand splits an identifier that is not a URL into its host and path
+// segments. A single segment has no host, and owner/repo is on the default
+// host. Of longer identifiers, the first segment is the host when it is known
+// or looks like a host name, such as a module path's.
+func (r *Resolver) shorthand(repo string) (string, []string, error) {
+	segments, err := pathSegments(repo, repo)
+	if err != nil {
+		return "", nil, err
+	}
+	switch {
+	case len(segments) == 1:
+		return "", segments, nil
+	case len(segments) > 2 && r.isHostName(segments[0]):
+		return segments[0], segments[1:], nil
+	default:
+		return r.defaultHost, segments, nil
+	}
+}
+
+// isHostName reports whether segment names a host rather than an owner or
+// group: a configured host, a name with a dot or port, or localhost.
+func (r *Resolver) isHostName(segment string) bool {
+	return r.HasHost(segment) || strings.ContainsAny(segment, ".:") || strings.EqualFold(segment, "localhost")
+}
+
+// pathSegments splits the repository path of repo, without the .git suffix,
+// rejecting empty and relative segments.
+func pathSegments(repo, path string) ([]string, error) {
+	segments := strings.Split(strings.Trim(strings.TrimSuffix(strings.TrimSpace(path), ".git"), "/"), "/")
+	for _, segment := range segments {
+		if segment == "" || segment == "." || segment == ".." {
+			return nil, fmt.Errorf("invalid repository identifier %q", repo)
+		}
+	}
+	return segments, nil
+}
+
+// host returns the settings for name, treating unknown hosts as HTTPS servers
+// that may nest repositories in groups.
+func (r *Resolver) host(name string) Host {
+	name = normalizeHost(name)
+	if host, ok := r.hosts[name]; ok {
+		return host
+	}
+	return Host{Name: name, Subgroups: true}
+}
+
+// rewrite applies the longest matching rewrite to url.
+func (r *Resolver) rewrite(url string) string {
+	for _, rw := range r.rewrites {
+		if strings.HasPrefix(url, rw.InsteadOf) {
+			return rw.Base + strings.TrimPrefix(url, rw.InsteadOf)
+		}
+	}
+	return url
+}
+
+// url builds the clone URL of path (owner/repo or group/.../repo) on h.
+func (h Host) url(path string) string {
+	if h.Protocol == ProtocolSSH {
+		user := h.SSHUser
+		if user == "" {
+			user = "git"
+		}
+		server := h.SSHHost
+		if server == "" {
+			server = h.Name
+		}
+		if h.SSHPort > 0 && h.SSHPort != 22 {
+			return "ssh://" + user + "@" + server + ":" + strconv.Itoa(h.SSHPort) + "/" + path + ".git"
+		}
+		return user + "@" + server + ":" + path + ".git"
+	}
+
+	base := h.BaseURL
+	if base == "" {
+		base = "https://" + h.Name
+	}
+	return strings.TrimSuffix(base, "/") + "/" + path + ".git"
+}
+
+// IsURL reports whether repo is already a clone URL: a URL with a scheme or an
+// scp-style address such as git@host:owner/repo.git.
+func IsURL(repo string) bool {
+	if strings.Contains(repo, "://") {
+		return true
+	}
+	at := strings.Index(repo, "@")
+	colon := strings.Index(repo, ":")
+	return at > 0 && colon > at && !strings.Contains(repo[:colon], "/")
+}
+
+func normalizeHost(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
@@ -0,0 +1,173 @@
+package repourl
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestResolver_CloneURL(t *testing.T) {
+	resolver := New(
+		WithHosts(
+			Host{Name: "github.example.com"},
+			Host{Name: "git.corp.example.com", BaseURL: "https://git.corp.example.com/scm/", Subgroups: true},
+			Host{Name: "ssh.example.com", Protocol: ProtocolSSH},
+			Host{Name: "bitbucket.example.com", Protocol: ProtocolSSH, SSHUser: "scm", SSHHost: "ssh.bitbucket.example.com", SSHPort: 7999},
+		),
+		WithRewrites(
+			Rewrite{Base: "https://mirror.example.com/", InsteadOf: "https://github.com/"},
+			Rewrite{Base: "https://mirror.example.com/special/", InsteadOf: "https://github.com/special/"},
+		),
+	)
+
+	tests := []struct {
+		name string
+		repo string
+		want string
+	}{
+		{name: "default host shorthand with rewrite", repo: "goliatone/go-errors", want: "https://mirror.example.com/goliatone/go-errors.git"},
+		{name: "longest rewrite wins", repo: "special/repo", want: "https://mirror.example.com/special/repo.git"},
+		{name: "enterprise host", repo: "github.example.com/team/app", want: "https://github.example.com/team/app.git"},
+		{name: "nested module on owner/repo host", repo: "github.example.com/team/app/cmd/tool/v2", want: "https://github.example.com/team/app.git"},
+		{name: "base URL with path prefix keeps subgroups", repo: "git.corp.example.com/platform/tools/lint/v3", want: "https://git.corp.example.com/scm/platform/tools/lint.git"},
+		{name: "gitlab.com subgroups", repo: "gitlab.com/group/sub/project", want: "https://gitlab.com/group/sub/project.git"},
+		{name: "unknown host keeps full path", repo: "git.unknown.org/a/b/c", want: "https://git.unknown.org/a/b/c.git"},
+		{name: "scp-style SSH", repo: "ssh.example.com/team/app.git", want: "git@ssh.example.com:team/app.git"},
+		{name: "SSH on custom port", repo: "bitbucket.example.com/proj/repo", want: "ssh://scm@ssh.bitbucket.example.com:7999/proj/repo.git"},
+		{name: "host names are case insensitive", repo: "GitHub.Example.com/team/app", want: "https://github.example.com/team/app.git"},
+		{name: "full URL kept", repo: "https://gitlab.com/group/project.git", want: "https://gitlab.com/group/project.git"},
+		{name: "scp URL kept", repo: "git@github.com:owner/repo.git", want: "git@github.com:owner/repo.git"},
+		{name: "full URL rewritten", repo: "https://github.com/owner/repo", want: "https://mirror.example.com/owner/repo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolver.CloneURL(tt.repo)
+			if err != nil {
+				t.Fatalf("CloneURL(%q) error = %v", tt.repo, err)
+			}
+			if got != tt.want {
+				t.Errorf("CloneURL(%q) = %q, want %q", tt.repo, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolver_CloneURLWithProtocol(t *testing.T) {
+	resolver := New(WithHosts(Host{Name: "ssh.example.com", Protocol: ProtocolSSH, SSHPort: 2222}))
+
+	tests := []struct {
+		repo     string
+		protocol Protocol
+		want     string
+	}{
+		{repo: "owner/repo", protocol: ProtocolSSH, want: "git@github.com:owner/repo.git"},
+		{repo: "ssh.example.com/team/app", protocol: ProtocolHTTPS, want: "https://ssh.example.com/team/app.git"},
+		{repo: "ssh.example.com/team/app", protocol: "", want: "ssh://git@ssh.example.com:2222/team/app.git"},
+		{repo: "https://github.com/owner/repo.git", protocol: ProtocolSSH, want: "https://github.com/owner/repo.git"},
+	}
+
+	for _, tt := range tests {
+		got, err := resolver.CloneURLWithProtocol(tt.repo, tt.protocol)
+		if err != nil {
+			t.Fatalf("CloneURLWithProtocol(%q, %q) error = %v", tt.repo, tt.protocol, err)
+		}
+		if got != tt.want {
+			t.Errorf("CloneURLWithProtocol(%q, %q) = %q, want %q", tt.repo, tt.protocol, got, tt.want)
+		}
+	}
+}
+
+func TestResolver_CloneURLErrors(t *testing.T) {
+	resolver := New()
+	if _, err := resolver.CloneURL("  "); !errors.Is(err, ErrEmptyRepo) {
+		t.Errorf("expected ErrEmptyRepo, got %v", err)
+	}
+	for _, repo := range []string{"repo", "owner//repo"} {
+		if _, err := resolver.CloneURL(repo); err == nil {
+			t.Errorf("expected error for %q", repo)
+		}
+	}
+}
+
+func TestResolver_DefaultHost(t *testing.T) {
+	resolver := New(WithDefaultHost("GitHub.Example.com"))
+	if resolver.DefaultHost() != "github.example.com" {
+		t.Fatalf("DefaultHost() = %q", resolver.DefaultHost())
+	}
+	got, err := resolver.CloneURL("team/app")
+	if err != nil || got != "https://github.example.com/team/app.git" {
+		t.Errorf("CloneURL() = %q, %v", got, err)
+	}
+	// Hosts named explicitly are not affected by the default host
+	if got, _ := resolver.CloneURL("github.com/owner/repo"); got != "https://github.com/owner/repo.git" {
+		t.Errorf("CloneURL() = %q", got)
+	}
+	if !resolver.HasHost("gitlab.com") || resolver.HasHost("github.example.com") {
+		t.Error("HasHost should only report built-in and configured hosts")
+	}
+}
+
+func TestResolver_Split(t *testing.T) {
+	resolver := New(
+		WithDefaultHost("gitlab.example.com"),
+		WithHosts(Host{Name: "scm"}),
+	)
+
+	tests := []struct {
+		repo    string
+		want    Parts
+		wantErr string
+	}{
+		{repo: "acme/api", want: Parts{Host: "gitlab.example.com", Owner: "acme", Repo: "api"}},
+		{repo: "group/sub/api", want: Parts{Host: "gitlab.example.com", Owner: "group/sub", Repo: "api"}},
+		{repo: "GitHub.com/acme/api.git", want: Parts{Host: "github.com", Owner: "acme", Repo: "api"}},
+		{repo: "scm/team/api", want: Parts{Host: "scm", Owner: "team", Repo: "api"}},
+		{repo: "localhost/team/api", want: Parts{Host: "localhost", Owner: "team", Repo: "api"}},
+		{repo: "https://token@github.com/acme/api.git", want: Parts{Host: "github.com", Owner: "acme", Repo: "api"}},
+		{repo: "ssh://git@git.example.com:2222/group/sub/api.git", want: Parts{Host: "git.example.com", Owner: "group/sub", Repo: "api"}},
+		{repo: "git@github.com:acme/api.git", want: Parts{Host: "github.com", Owner: "acme", Repo: "api"}},
+		{repo: "api", want: Parts{Repo: "api"}},
+		{repo: " ", wantErr: "repository identifier is empty"},
+		{repo: "acme/../api", wantErr: "invalid repository identifier"},
+	}
+
+	for _, tt := range tests {
+		got, err := resolver.Split(tt.repo)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Split(%q) error = %v, want %q", tt.repo, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Split(%q) error = %v", tt.repo, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Split(%q) = %+v, want %+v", tt.repo, got, tt.want)
+		}
+	}
+
+	// Subgroup shorthands keep every group in the clone URL
+	if got, _ := New(WithDefaultHost("gitlab.com")).CloneURL("group/sub/api"); got != "https://gitlab.com/group/sub/api.git" {
+		t.Errorf("CloneURL() = %q", got)
+	}
+}
+
+func TestIsURL(t *testing.T) {
+	for repo, want := range map[string]bool{
+		"https://github.com/o/r":        true,
+		"ssh://git@host:22/o/r.git":     true,
+		"git@github.com:o/r.git":        true,
+		"deploy@host.example.com:o/r":   true,
+		"owner/repo":                    false,
+		"github.com/owner/repo":         false,
+		"127.0.0.1:8080/owner/repo":     false,
+		"example.com/user@team/project": false,
+	} {
+		if got := IsURL(repo); got != want {
+			t.Errorf("IsURL(%q) = %v, want %v", repo, got, want)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+// Package retry runs operations that may fail transiently, waiting between
+// attempts with exponential backoff and jitter. Every wait and every attempt
+// honors context cancellation, so a cancelled run stops retrying at once instead
+// of sleeping out its backoff.
+package retry
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+)
+
+// DefaultMultiplier is how much the delay grows after each retry when a Policy
+// leaves Multiplier unset.
+const DefaultMultiplier = 2
+
+// Policy configures Do.
+type Policy struct {
+	// MaxAttempts is the number of attempts, including the first. Values below
+	// one mean a single attempt.
+	MaxAttempts int
+
+	// Delay is the wait before the first retry.
+	Delay time.Duration
+
+	// MaxDelay caps the wait between attempts; zero means no cap.
+	MaxDelay time.Duration
+
+	// Multiplier scales the wait after each retry. Default: DefaultMultiplier
+	Multiplier float64
+
+	// Jitter randomizes each wait by up to this fraction of it, in either
+	// direction, so runners failing together do not retry in lockstep. It is
+	// clamped to [0, 1].
+	Jitter float64
+
+	// AttemptTimeout bounds each attempt; zero leaves attempts bounded only by
+	// the context passed to Do.
+	AttemptTimeout time.Duration
+
+	// Retryable reports whether a failed attempt is worth retrying. Nil
+	// retries every error.
+	Retryable func(error) bool
+
+	// OnRetry is called before waiting to retry, with the attempt that failed
+	// (starting at 1), its error, and the wait.
+	OnRetry func(attempt int, err error, delay time.Duration)
+}
+
+// Do runs op until it succeeds, fails with an error that is not retryable,
+// exhausts the policy's attempts, or ctx is done. It returns the error of the
+// last attempt, or nil on success; callers can check ctx.Err() to tell whether
+// cancellation ended the retries. The first attempt always runs.
+func Do(ctx context.Context, policy Policy, op func(ctx context.Context) error) error {
+	attempts := max(policy.MaxAttempts, 1)
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = runAttempt(ctx, policy.AttemptTimeout, op)
+		if err == nil || attempt >= attempts || ctx.Err() != nil {
+			return err
+		}
+		if policy.Retryable != nil && !policy.Retryable(err) {
+			return err
+		}
+
+		delay := policy.Backoff(attempt)
+		if policy.OnRetry != nil {
+			policy.OnRetry(attempt, err, delay)
+		}
+		if Sleep(ctx, delay) != nil {
+			return err
+		}
+	}
+}
+
+func runAttempt(ctx context.Context, timeout time.Duration, op func(ctx context.Context) error) error {
+	if timeout <= 0 {
+		return op(ctx)
+	}
+	attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return op(attemptCtx)
+}
+
+// Backoff returns the wait after the given failed attempt, starting at 1:
+// Delay grown by Multiplier for every earlier retry, capped at MaxDelay, with
+// Jitter applied.
+func (p Policy) Backoff(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier <= 0 {
+		multiplier = DefaultMultiplier
+	}
+
+	delay := float64(p.Delay)
+	for i := 1; i < attempt; i++ {
+		delay *= multiplier
+		if p.MaxDelay > 0 && delay >= float64(p.MaxDelay) {
+			break
+		}
+	}
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		delay = float64(p.MaxDelay)
+	}
+
+	if jitter := min(max(p.Jitter, 0), 1); jitter > 0 {
+		delay += delay * jitter * (2*rand.Float64() - 1)
+	}
+	return time.Duration(delay)
+}
+
+// Sleep waits for d or until ctx is done, whichever comes first, and returns
+// ctx.Err() in the latter case.
+func Sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,126 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDo_RetriesUntilSuccess(t *testing.T) {
+	calls := 0
+	var delays []time.Duration
+	err := Do(context.Background(), Policy{
+		MaxAttempts: 5,
+		Delay:       time.Millisecond,
+		OnRetry: func(attempt int, err error, delay time.Duration) {
+			delays = append(delays, delay)
+		},
+	}, func(context.Context) error {
+		calls++
+		if calls < 3 {
+			return errors.New("transient")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3", calls)
+	}
+	if len(delays) != 2 || delays[0] != time.Millisecond || delays[1] != 2*time.Millisecond {
+		t.Errorf("delays = %v, want [1ms 2ms]", delays)
+	}
+}
+
+func TestDo_StopsOnNonRetryableError(t *testing.T) {
+	permanent := errors.New("permanent")
+	calls := 0
+	err := Do(context.Background(), Policy{
+		MaxAttempts: 5,
+		Retryable:   func(err error) bool { return !errors.Is(err, permanent) },
+	}, func(context.Context) error {
+		calls++
+		return permanent
+	})
+	if !errors.Is(err, permanent) || calls != 1 {
+		t.Fatalf("Do = %v after %d calls, want the permanent error after 1", err, calls)
+	}
+}
+
+func TestDo_ReturnsLastErrorWhenAttemptsRunOut(t *testing.T) {
+	calls := 0
+	err := Do(context.Background(), Policy{MaxAttempts: 3}, func(context.Context) error {
+		calls++
+		return errors.New("attempt failed")
+	})
+	if err == nil || calls != 3 {
+		t.Fatalf("Do = %v after %d calls, want an error after 3", err, calls)
+	}
+}
+
+func TestDo_CancellationInterruptsBackoff(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	start := time.Now()
+	err := Do(ctx, Policy{MaxAttempts: 3, Delay: time.Hour}, func(context.Context) error {
+		calls++
+		cancel()
+		return errors.New("failed")
+	})
+	if err == nil || ctx.Err() == nil {
+		t.Fatalf("Do = %v, want the attempt's error with ctx cancelled", err)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Do waited %v after cancellation", elapsed)
+	}
+}
+
+func TestDo_AttemptTimeout(t *testing.T) {
+	calls := 0
+	err := Do(context.Background(), Policy{MaxAttempts: 2, AttemptTimeout: 10 * time.Millisecond}, func(ctx context.Context) error {
+		calls++
+		if calls == 1 {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("calls = %d, want a second attempt after the first timed out", calls)
+	}
+}
+
+func TestPolicy_Backoff(t *testing.T) {
+	p := Policy{Delay: time.Second, MaxDelay: 5 * time.Second}
+	for attempt, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 5 * time.Second, 50: 5 * time.Second} {
+		if got := p.Backoff(attempt); got != want {
+			t.Errorf("Backoff(%d) = %v, want %v", attempt, got, want)
+		}
+	}
+
+	p = Policy{Delay: time.Second, Jitter: 0.5}
+	for i := 0; i < 100; i++ {
+		if got := p.Backoff(1); got < 500*time.Millisecond || got > 1500*time.Millisecond {
+			t.Fatalf("Backoff with 50%% jitter = %v, want within [500ms, 1.5s]", got)
+		}
+	}
+}
+
+func TestSleep(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := Sleep(ctx, time.Hour); !errors.Is(err, context.Canceled) {
+		t.Errorf("Sleep on a cancelled context = %v, want context.Canceled", err)
+	}
+	if err := Sleep(context.Background(), time.Millisecond); err != nil {
+		t.Errorf("Sleep = %v", err)
+	}
+}
@@ -0,0 +1,231 @@
+// Package templatefuncs holds the helpers shared by every user-supplied
+// template: PR titles and bodies, notifications, and commit messages.
+package templatefuncs
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// Func documents a template helper.
+type Func struct {
+	Name        string
+	Signature   string
+	Description string
+	Example     string
+}
+
+// docs describes every entry in funcMap. Keep the two in sync;
+// TestFuncsDocumented fails when a helper is missing documentation.
+var docs = []Func{
+	{Name: "upper", Signature: "upper STRING", Description: "Convert to upper case", Example: `{{upper .Repo}}`},
+	{Name: "lower", Signature: "lower STRING", Description: "Convert to lower case", Example: `{{lower .Module}}`},
+	{Name: "title", Signature: "title STRING", Description: "Capitalize the first letter of each word", Example: `{{title .Status}}`},
+	{Name: "trim", Signature: "trim STRING", Description: "Remove leading and trailing whitespace", Example: `{{trim .Reason}}`},
+	{Name: "replace", Signature: "replace OLD NEW STRING", Description: "Replace every occurrence of OLD with NEW", Example: `{{replace "/" "-" .Repo}}`},
+	{Name: "truncate", Signature: "truncate STRING N", Description: "Shorten to N characters, adding an ellipsis", Example: `{{truncate .Reason 80}}`},
+	{Name: "truncate8", Signature: "truncate8 STRING", Description: "Shorten to 8 characters", Example: `{{truncate8 .CommitHash}}`},
+	{Name: "truncate200", Signature: "truncate200 STRING", Description: "Shorten to 200 characters", Example: `{{truncate200 .FailureMessage}}`},
+	{Name: "escape", Signature: "escape STRING", Description: "Escape Slack mrkdwn control characters", Example: `{{escape .FailureSummary}}`},
+	{Name: "join", Signature: "join LIST SEP", Description: "Join a string list with a separator", Example: `{{join .Labels ", "}}`},
+	{Name: "shortSHA", Signature: "shortSHA STRING", Description: "Abbreviate a commit hash to 7 characters", Example: `{{shortSHA .CommitHash}}`},
+	{Name: "semverMajor", Signature: "semverMajor VERSION", Description: "Major component of a semantic version (0 when invalid)", Example: `v{{semverMajor .SourceVersion}}`},
+	{Name: "semverMinor", Signature: "semverMinor VERSION", Description: "Minor component of a semantic version (0 when invalid)", Example: `{{semverMinor .SourceVersion}}`},
+	{Name: "semverPatch", Signature: "semverPatch VERSION", Description: "Patch component of a semantic version (0 when invalid)", Example: `{{semverPatch .SourceVersion}}`},
+	{Name: "semverDelta", Signature: "semverDelta OLD NEW", Description: "Largest changed component between two versions: major, minor, patch, prerelease, or none", Example: `{{semverDelta .DependencyOld .DependencyNew}}`},
+	{Name: "toJSON", Signature: "toJSON VALUE", Description: "Encode a value as compact JSON", Example: `{{toJSON .Labels}}`},
+	{Name: "default", Signature: "default FALLBACK VALUE", Description: "Use FALLBACK when VALUE is empty", Example: `{{.Reason | default "n/a"}}`},
+	{Name: "formatDate", Signature: "formatDate LAYOUT TIME", Description: "Format a time using a Go layout", Example: `{{formatDate "2006-01-02" .Timestamp}}`},
+	{Name: "now", Signature: "now", Description: "Current time in UTC", Example: `{{formatDate "15:04" now}}`},
+}
+
+// Docs returns the documentation of every helper, sorted by name.
+func Docs() []Func {
+	funcs := make([]Func, len(docs))
+	copy(funcs, docs)
+	sort.Slice(funcs, func(i, j int) bool {
+		return funcs[i].Name < funcs[j].Name
+	})
+	return funcs
+}
+
+// FuncMap returns the helpers, keyed by name. Each call returns a new map, so
+// callers may add their own functions to it.
+func FuncMap() template.FuncMap {
+	funcs := make(template.FuncMap, len(funcMap))
+	for name, fn := range funcMap {
+		funcs[name] = fn
+	}
+	return funcs
+}
+
+// funcMap provides safe template functions
+var funcMap = template.FuncMap{
+	"upper":       strings.ToUpper,
+	"lower":       strings.ToLower,
+	"title":       strings.Title,
+	"trim":        strings.TrimSpace,
+	"replace":     replaceString,
+	"truncate":    Truncate,
+	"truncate8":   func(s string) string { return Truncate(s, 8) },
+	"truncate200": func(s string) string { return Truncate(s, 200) },
+	"escape":      EscapeMarkdown,
+	"join":        joinStrings,
+	"shortSHA":    shortSHA,
+	"semverMajor": semverMajor,
+	"semverMinor": semverMinor,
+	"semverPatch": semverPatch,
+	"semverDelta": SemverDelta,
+	"toJSON":      toJSON,
+	"default":     defaultValue,
+	"formatDate":  formatDate,
+	"now":         func() time.Time { return time.Now().UTC() },
+}
+
+// replaceString is argument-ordered for pipelines: {{.Repo | replace "/" "-"}}.
+func replaceString(old, new, s string) string {
+	return strings.ReplaceAll(s, old, new)
+}
+
+// shortSHA abbreviates a commit hash to the conventional 7 characters.
+func shortSHA(sha string) string {
+	sha = strings.TrimSpace(sha)
+	if len(sha) <= 7 {
+		return sha
+	}
+	return sha[:7]
+}
+
+func parseSemver(v string) *semver.Version {
+	parsed, err := semver.NewVersion(strings.TrimSpace(v))
+	if err != nil {
+		return nil
+	}
+	return parsed
+}
+
+func semverMajor(v string) uint64 {
+	if parsed := parseSemver(v); parsed != nil {
+		return parsed.Major()
+	}
+	return 0
+}
+
+func semverMinor(v string) uint64 {
+	if parsed := parseSemver(v); parsed != nil {
+		return parsed.Minor()
+	}
+	return 0
+}
+
+func semverPatch(v string) uint64 {
+	if parsed := parseSemver(v); parsed != nil {
+		return parsed.Patch()
+	}
+	return 0
+}
+
+// SemverDelta reports the most significant component that differs between two versions.
+// It returns an empty string when either version cannot be parsed.
+func SemverDelta(oldVersion, newVersion string) string {
+	oldV := parseSemver(oldVersion)
+	newV := parseSemver(newVersion)
+	if oldV == nil || newV == nil {
+		return ""
+	}
+
+	switch {
+	case oldV.Major() != newV.Major():
+		return "major"
+	case oldV.Minor() != newV.Minor():
+		return "minor"
+	case oldV.Patch() != newV.Patch():
+		return "patch"
+	case oldV.Prerelease() != newV.Prerelease():
+		return "prerelease"
+	default:
+		return "none"
+	}
+}
+
+// toJSON encodes a value as compact JSON, returning the error text on failure so a
+// bad value never aborts rendering.
+func toJSON(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%q", err.Error())
+	}
+	return string(data)
+}
+
+// defaultValue follows the pipeline-friendly argument order: {{.X | default "y"}}.
+func defaultValue(fallback, value any) any {
+	if isEmptyValue(value) {
+		return fallback
+	}
+	return value
+}
+
+func isEmptyValue(value any) bool {
+	if value == nil {
+		return true
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.String:
+		return strings.TrimSpace(rv.String()) == ""
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return rv.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return rv.IsNil()
+	}
+	if t, ok := value.(time.Time); ok {
+		return t.IsZero()
+	}
+	return rv.IsZero()
+}
+
+// formatDate formats a time value with the provided Go layout. Zero times render empty.
+func formatDate(layout string, t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(layout)
+}
+
+// Truncate truncates a string to maxLen characters, adding ellipsis if needed.
+func Truncate(s string, maxLen int) string {
+	if len(s) <= maxLen {
+		return s
+	}
+	return s[:maxLen-3] + "..."
+}
+
+var slackEscapeReplacer = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+	"`", "\\`",
+	"*", "\\*",
+	"[", "\\[",
+	"]", "\\]",
+	"|", "\\|",
+)
+
+// EscapeMarkdown escapes characters that would break Slack mrkdwn formatting while
+// keeping common symbols (like parentheses and underscores) readable.
+func EscapeMarkdown(s string) string {
+	return slackEscapeReplacer.Replace(s)
+}
+
+// joinStrings is a template function that joins string slices.
+func joinStrings(elems []string, sep string) string {
+	return strings.Join(elems, sep)
+}
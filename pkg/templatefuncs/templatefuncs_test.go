@@ -0,0 +1,116 @@
+package templatefuncs
+
+import "testing"
+
+func TestFuncsDocumented(t *testing.T) {
+	documented := make(map[string]bool)
+	for _, fn := range Docs() {
+		if fn.Description == "" || fn.Signature == "" {
+			t.Errorf("template func %q is missing signature or description", fn.Name)
+		}
+		documented[fn.Name] = true
+	}
+
+	for name := range funcMap {
+		if !documented[name] {
+			t.Errorf("template func %q is not documented", name)
+		}
+	}
+	for name := range documented {
+		if _, ok := funcMap[name]; !ok {
+			t.Errorf("documented template func %q is not registered", name)
+		}
+	}
+}
+
+func TestSemverDelta(t *testing.T) {
+	tests := []struct {
+		old, new, want string
+	}{
+		{"v1.2.3", "v1.2.4", "patch"},
+		{"v1.2.3", "v1.3.0", "minor"},
+		{"v1.2.3", "v2.0.0", "major"},
+		{"v1.2.3-rc.1", "v1.2.3-rc.2", "prerelease"},
+		{"v1.2.3", "v1.2.3", "none"},
+		{"", "v1.2.3", ""},
+	}
+
+	for _, tt := range tests {
+		if got := SemverDelta(tt.old, tt.new); got != tt.want {
+			t.Errorf("SemverDelta(%q, %q) = %q, want %q", tt.old, tt.new, got, tt.want)
+		}
+	}
+}
+
+func TestTruncateString(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		maxLen int
+		want   string
+	}{
+		{
+			name:   "short string",
+			input:  "hello",
+			maxLen: 10,
+			want:   "hello",
+		},
+		{
+			name:   "exact length",
+			input:  "hello",
+			maxLen: 5,
+			want:   "hello",
+		},
+		{
+			name:   "needs truncation",
+			input:  "this is a very long string that needs truncation",
+			maxLen: 20,
+			want:   "this is a very lo...",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Truncate(tt.input, tt.maxLen); got != tt.want {
+				t.Fatalf("Truncate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEscapeMarkdown(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "backticks",
+			input: "code with `backticks`",
+			want:  "code with \\`backticks\\`",
+		},
+		{
+			name:  "asterisks",
+			input: "text with *emphasis*",
+			want:  "text with \\*emphasis\\*",
+		},
+		{
+			name:  "html characters",
+			input: "<script>alert('xss')</script>",
+			want:  "&lt;script&gt;alert('xss')&lt;/script&gt;",
+		},
+		{
+			name:  "mixed characters",
+			input: "# Header with `code` and <tags>",
+			want:  "# Header with \\`code\\` and &lt;tags&gt;",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EscapeMarkdown(tt.input); got != tt.want {
+				t.Fatalf("EscapeMarkdown() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
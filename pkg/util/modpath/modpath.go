@@ -2,6 +2,8 @@ package modpath
 
 import (
 	"strings"
+
+	"github.com/goliatone/cascade/pkg/repourl"
 )
 
 // DeriveRepository converts module paths into owner/repo identifiers for common hosts.
@@ -31,13 +33,13 @@ func DeriveLocalModulePath(modulePath string) string {
 	return "."
 }
 
-// BuildCloneURL normalises repository identifiers into HTTPS clone URLs.
+// BuildCloneURL normalises repository identifiers into clone URLs using the
+// built-in host defaults. Identifiers that cannot be converted are returned as
+// they are. Use a repourl.Resolver to honour configured hosts and rewrites.
 func BuildCloneURL(repo string) string {
-	if strings.HasPrefix(repo, "https://") || strings.HasPrefix(repo, "http://") || strings.HasPrefix(repo, "git@") {
+	cloneURL, err := repourl.CloneURL(repo)
+	if err != nil {
 		return repo
 	}
-	if strings.Count(repo, "/") == 1 {
-		return "https://github.com/" + repo
-	}
-	return repo
+	return cloneURL
 }
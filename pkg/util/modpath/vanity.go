@@ -0,0 +1,192 @@
+package modpath
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// knownHosts are code hosts whose module paths map to repositories without a lookup.
+var knownHosts = map[string]bool{
+	"github.com":    true,
+	"gitlab.com":    true,
+	"bitbucket.org": true,
+}
+
+// ImportRoot is the repository a vanity import path points to, as declared by a
+// go-import meta tag.
+type ImportRoot struct {
+	// Prefix is the import path prefix served by the repository
+	Prefix string
+
+	// VCS is the version control system (e.g., "git")
+	VCS string
+
+	// RepoURL is the repository clone URL
+	RepoURL string
+}
+
+// Repository returns the owner/repo identifier for roots hosted on a known code
+// host, or the repository URL without scheme and .git suffix otherwise.
+func (r ImportRoot) Repository() string {
+	trimmed := strings.TrimSuffix(r.RepoURL, ".git")
+	for _, scheme := range []string{"https://", "http://", "git://", "ssh://"} {
+		trimmed = strings.TrimPrefix(trimmed, scheme)
+	}
+	trimmed = strings.TrimSuffix(trimmed, "/")
+	return DeriveRepository(trimmed)
+}
+
+// LocalModulePath returns the directory of modulePath within the repository.
+func (r ImportRoot) LocalModulePath(modulePath string) string {
+	rel := strings.TrimPrefix(strings.TrimPrefix(modulePath, r.Prefix), "/")
+	if rel == "" {
+		return "."
+	}
+	return rel
+}
+
+// IsVanityPath reports whether modulePath looks like a custom import path: its
+// first element is a domain that is not one of the known code hosts.
+func IsVanityPath(modulePath string) bool {
+	if modulePath == "" || strings.Contains(modulePath, "://") || strings.HasPrefix(modulePath, "git@") {
+		return false
+	}
+	host := strings.SplitN(modulePath, "/", 2)[0]
+	return strings.Contains(host, ".") && !knownHosts[host]
+}
+
+// VanityResolver maps vanity import paths to repositories by fetching
+// https://<path>?go-get=1 and reading the go-import meta tag, the same lookup the
+// go command performs. Results, including failures, are cached per path.
+type VanityResolver struct {
+	client *http.Client
+
+	mu    sync.Mutex
+	cache map[string]vanityResult
+}
+
+type vanityResult struct {
+	root *ImportRoot
+	err  error
+}
+
+// NewVanityResolver creates a resolver using client, or a client with a 10 second
+// timeout when client is nil.
+func NewVanityResolver(client *http.Client) *VanityResolver {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &VanityResolver{client: client, cache: make(map[string]vanityResult)}
+}
+
+// Resolve returns the import root serving modulePath.
+func (v *VanityResolver) Resolve(ctx context.Context, modulePath string) (*ImportRoot, error) {
+	if !IsVanityPath(modulePath) {
+		return nil, fmt.Errorf("%s is not a vanity import path", modulePath)
+	}
+
+	v.mu.Lock()
+	cached, ok := v.cache[modulePath]
+	v.mu.Unlock()
+	if ok {
+		return cached.root, cached.err
+	}
+
+	root, err := v.fetch(ctx, modulePath)
+	if ctx.Err() == nil {
+		v.mu.Lock()
+		v.cache[modulePath] = vanityResult{root: root, err: err}
+		v.mu.Unlock()
+	}
+	return root, err
+}
+
+func (v *VanityResolver) fetch(ctx context.Context, modulePath string) (*ImportRoot, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://"+modulePath+"?go-get=1", nil)
+	if err != nil {
+		return nil, fmt.Errorf("resolve %s: %w", modulePath, err)
+	}
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("resolve %s: %w", modulePath, err)
+	}
+	defer resp.Body.Close()
+
+	// Servers may answer go-get requests with a 404 page that still carries the
+	// meta tag, so the status code is only checked when no tag is found
+	root, err := ParseGoImport(resp.Body, modulePath)
+	if err != nil && resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("resolve %s: unexpected status %s", modulePath, resp.Status)
+	}
+	return root, err
+}
+
+// ParseGoImport reads the go-import meta tags in an HTML page and returns the one
+// whose prefix covers modulePath. Git roots are preferred over module proxy
+// ("mod") entries.
+func ParseGoImport(r io.Reader, modulePath string) (*ImportRoot, error) {
+	decoder := xml.NewDecoder(r)
+	decoder.Strict = false
+	decoder.AutoClose = xml.HTMLAutoClose
+	decoder.Entity = xml.HTMLEntity
+
+	var match *ImportRoot
+	for {
+		token, err := decoder.RawToken()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, fmt.Errorf("parse go-import meta tag for %s: %w", modulePath, err)
+		}
+
+		if end, ok := token.(xml.EndElement); ok && strings.EqualFold(end.Name.Local, "head") {
+			break
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if strings.EqualFold(start.Name.Local, "body") {
+			break
+		}
+		if !strings.EqualFold(start.Name.Local, "meta") || attrValue(start.Attr, "name") != "go-import" {
+			continue
+		}
+
+		fields := strings.Fields(attrValue(start.Attr, "content"))
+		if len(fields) != 3 {
+			continue
+		}
+		root := &ImportRoot{Prefix: fields[0], VCS: fields[1], RepoURL: fields[2]}
+		if modulePath != root.Prefix && !strings.HasPrefix(modulePath, root.Prefix+"/") {
+			continue
+		}
+		if match == nil || (match.VCS == "mod" && root.VCS != "mod") {
+			match = root
+		}
+	}
+
+	if match == nil {
+		return nil, fmt.Errorf("no go-import meta tag found for %s", modulePath)
+	}
+	if match.VCS != "git" {
+		return nil, fmt.Errorf("%s is served by %s, only git repositories are supported", modulePath, match.VCS)
+	}
+	return match, nil
+}
+
+func attrValue(attrs []xml.Attr, name string) string {
+	for _, attr := range attrs {
+		if strings.EqualFold(attr.Name.Local, name) {
+			return attr.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,123 @@
+package modpath
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestIsVanityPath(t *testing.T) {
+	tests := map[string]bool{
+		"go.uber.org/zap":                  true,
+		"golang.org/x/mod":                 true,
+		"github.com/goliatone/go-errors":   false,
+		"gitlab.com/group/project":         false,
+		"goliatone/go-errors":              false,
+		"https://go.uber.org/zap":          false,
+		"git@github.com:goliatone/app.git": false,
+		"":                                 false,
+	}
+	for path, want := range tests {
+		if got := IsVanityPath(path); got != want {
+			t.Errorf("IsVanityPath(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestParseGoImport(t *testing.T) {
+	page := `<!DOCTYPE html>
+<html>
+<head>
+<meta name="go-import" content="go.example.com/tools mod https://proxy.example.com">
+<meta name="go-import" content="go.example.com/tools git https://github.com/example/tools">
+<meta name="go-import" content="go.example.com/other git https://github.com/example/other">
+<meta name="go-source" content="go.example.com/tools https://github.com/example/tools">
+</head>
+<body><meta name="go-import" content="go.example.com/tools git https://evil.example.com/tools"></body>
+</html>`
+
+	root, err := ParseGoImport(strings.NewReader(page), "go.example.com/tools/cmd/lint")
+	if err != nil {
+		t.Fatalf("ParseGoImport error: %v", err)
+	}
+	if root.RepoURL != "https://github.com/example/tools" || root.VCS != "git" {
+		t.Fatalf("unexpected root: %+v", root)
+	}
+	if got := root.Repository(); got != "example/tools" {
+		t.Errorf("Repository() = %s, want example/tools", got)
+	}
+	if got := root.LocalModulePath("go.example.com/tools/cmd/lint"); got != "cmd/lint" {
+		t.Errorf("LocalModulePath() = %s, want cmd/lint", got)
+	}
+	if got := root.LocalModulePath("go.example.com/tools"); got != "." {
+		t.Errorf("LocalModulePath() = %s, want .", got)
+	}
+
+	// A prefix must match on a path element boundary
+	if _, err := ParseGoImport(strings.NewReader(page), "go.example.com/toolsmith"); err == nil {
+		t.Error("expected no match for go.example.com/toolsmith")
+	}
+
+	hg := `<meta name="go-import" content="go.example.com/hg hg https://hg.example.com/repo">`
+	if _, err := ParseGoImport(strings.NewReader(hg), "go.example.com/hg"); err == nil || !strings.Contains(err.Error(), "only git") {
+		t.Errorf("expected unsupported VCS error, got %v", err)
+	}
+}
+
+func TestImportRoot_RepositoryOnOtherHosts(t *testing.T) {
+	root := ImportRoot{Prefix: "go.example.com/app", VCS: "git", RepoURL: "https://git.example.com/team/app.git"}
+	if got := root.Repository(); got != "git.example.com/team/app" {
+		t.Errorf("Repository() = %s, want git.example.com/team/app", got)
+	}
+}
+
+func TestVanityResolver_Resolve(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Query().Get("go-get") != "1" {
+			t.Errorf("expected go-get=1 query, got %s", r.URL.RawQuery)
+		}
+		// Some servers answer with 404 but still include the tag
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprintf(w, `<html><head><meta name="go-import" content="%s/zap git https://github.com/uber-go/zap"></head></html>`, r.Host)
+	}))
+	defer server.Close()
+
+	resolver := NewVanityResolver(server.Client())
+	modulePath := strings.TrimPrefix(server.URL, "https://") + "/zap/zapcore"
+
+	for i := 0; i < 2; i++ {
+		root, err := resolver.Resolve(context.Background(), modulePath)
+		if err != nil {
+			t.Fatalf("Resolve error: %v", err)
+		}
+		if root.Repository() != "uber-go/zap" || root.LocalModulePath(modulePath) != "zapcore" {
+			t.Fatalf("unexpected root: %+v", root)
+		}
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("expected one request with caching, got %d", got)
+	}
+
+	if _, err := resolver.Resolve(context.Background(), "github.com/uber-go/zap"); err == nil {
+		t.Error("expected error for a path on a known host")
+	}
+}
+
+func TestVanityResolver_ResolveFailure(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "gone", http.StatusGone)
+	}))
+	defer server.Close()
+
+	resolver := NewVanityResolver(server.Client())
+	_, err := resolver.Resolve(context.Background(), strings.TrimPrefix(server.URL, "https://")+"/missing")
+	if err == nil || !strings.Contains(err.Error(), "unexpected status") {
+		t.Fatalf("expected status error, got %v", err)
+	}
+}
@@ -0,0 +1,210 @@
+package version
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+const (
+	checksumsAssetName = "checksums.txt"
+	binaryName         = "cascade"
+
+	// maxBinarySize guards against unbounded downloads.
+	maxBinarySize = 256 << 20
+)
+
+// ErrChecksumMismatch is returned when a downloaded archive does not match the
+// checksum published with the release.
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// ArchiveName returns the release archive name for the given platform, following
+// the naming template in .goreleaser.yml.
+func ArchiveName(goos, goarch string) string {
+	arch := goarch
+	switch goarch {
+	case "amd64":
+		arch = "x86_64"
+	case "386":
+		arch = "i386"
+	case "arm":
+		arch = "armv6"
+	}
+	if goos != "" {
+		goos = strings.ToUpper(goos[:1]) + goos[1:]
+	}
+	return fmt.Sprintf("%s_%s_%s.tar.gz", binaryName, goos, arch)
+}
+
+// DownloadBinary downloads the release archive for the running platform, verifies the
+// release checksums file against its signature and the archive against the checksums
+// file, and returns the extracted cascade binary.
+func (c *UpdateChecker) DownloadBinary(ctx context.Context, release *Release) ([]byte, error) {
+	if release == nil {
+		return nil, fmt.Errorf("release is required")
+	}
+
+	archiveName := ArchiveName(runtime.GOOS, runtime.GOARCH)
+	archiveAsset, ok := findAsset(release, archiveName)
+	if !ok {
+		return nil, fmt.Errorf("release %s has no archive for %s/%s (expected %s)", release.Tag, runtime.GOOS, runtime.GOARCH, archiveName)
+	}
+	checksumAsset, ok := findAsset(release, checksumsAssetName)
+	if !ok {
+		return nil, fmt.Errorf("release %s does not publish %s; refusing to install an unverified binary", release.Tag, checksumsAssetName)
+	}
+	signatureAsset, ok := findAsset(release, signatureAssetName)
+	if !ok {
+		return nil, fmt.Errorf("%w: release %s does not publish %s; refusing to install an unverified binary", ErrSignatureInvalid, release.Tag, signatureAssetName)
+	}
+
+	checksums, err := c.download(ctx, checksumAsset.URL)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := c.download(ctx, signatureAsset.URL)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifySignature(c.signingKey, checksums, signature); err != nil {
+		return nil, err
+	}
+	expected, err := lookupChecksum(checksums, archiveName)
+	if err != nil {
+		return nil, err
+	}
+
+	archive, err := c.download(ctx, archiveAsset.URL)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(archive)
+	if actual := hex.EncodeToString(sum[:]); !strings.EqualFold(actual, expected) {
+		return nil, fmt.Errorf("%w for %s: expected %s, got %s", ErrChecksumMismatch, archiveName, expected, actual)
+	}
+
+	return extractBinary(archive)
+}
+
+// InstallBinary atomically replaces the executable at path with binary.
+func InstallBinary(path string, binary []byte) error {
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return fmt.Errorf("resolve executable path: %w", err)
+	}
+
+	info, err := os.Stat(resolved)
+	if err != nil {
+		return fmt.Errorf("stat executable: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(resolved), "."+binaryName+"-update-*")
+	if err != nil {
+		return fmt.Errorf("create temporary binary: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := tmp.Write(binary); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write temporary binary: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write temporary binary: %w", err)
+	}
+	if err := os.Chmod(tmpPath, info.Mode().Perm()|0o111); err != nil {
+		return fmt.Errorf("set binary permissions: %w", err)
+	}
+	if err := os.Rename(tmpPath, resolved); err != nil {
+		return fmt.Errorf("replace executable: %w", err)
+	}
+	return nil
+}
+
+func findAsset(release *Release, name string) (Asset, bool) {
+	for _, asset := range release.Assets {
+		if asset.Name == name {
+			return asset, true
+		}
+	}
+	return Asset{}, false
+}
+
+func (c *UpdateChecker) download(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build download request: %w", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("download %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download %s: unexpected status %s", url, resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBinarySize+1))
+	if err != nil {
+		return nil, fmt.Errorf("download %s: %w", url, err)
+	}
+	if len(data) > maxBinarySize {
+		return nil, fmt.Errorf("download %s: exceeds %d bytes", url, maxBinarySize)
+	}
+	return data, nil
+}
+
+// lookupChecksum finds the sha256 for name in a goreleaser checksums file.
+func lookupChecksum(checksums []byte, name string) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			return fields[0], nil
+		}
+	}
+	return "", fmt.Errorf("no checksum published for %s", name)
+}
+
+// extractBinary returns the cascade executable from a tar.gz archive.
+func extractBinary(archive []byte) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, fmt.Errorf("open release archive: %w", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read release archive: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg || filepath.Base(header.Name) != binaryName {
+			continue
+		}
+		data, err := io.ReadAll(io.LimitReader(tr, maxBinarySize))
+		if err != nil {
+			return nil, fmt.Errorf("extract %s: %w", binaryName, err)
+		}
+		return data, nil
+	}
+	return nil, fmt.Errorf("release archive does not contain %s", binaryName)
+}
@@ -0,0 +1,95 @@
+package version
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const signatureAssetName = checksumsAssetName + ".sig"
+
+// SigningKey is the public key release checksums are signed with, set at build
+// time with -ldflags "-X github.com/goliatone/cascade/pkg/version.SigningKey=...".
+// It holds a PKIX public key, ECDSA P-256 as cosign generates or Ed25519, either
+// PEM encoded or as the base64 body of the PEM block. Builds without a key
+// refuse to self-update.
+var SigningKey string
+
+// ErrSignatureInvalid is returned when the checksums file of a release is not
+// signed by the pinned signing key.
+var ErrSignatureInvalid = errors.New("invalid release signature")
+
+// ErrNoSigningKey is returned when the running build has no signing key to
+// verify releases with.
+var ErrNoSigningKey = errors.New("no release signing key")
+
+// WithSigningKey overrides the public key release checksums are verified with,
+// which defaults to SigningKey.
+func WithSigningKey(key string) UpdateOption {
+	return func(c *UpdateChecker) {
+		c.signingKey = key
+	}
+}
+
+// verifySignature checks that signature, raw or base64 encoded as cosign
+// writes it, signs checksums with key.
+func verifySignature(key string, checksums, signature []byte) error {
+	if strings.TrimSpace(key) == "" {
+		return fmt.Errorf("%w: this build cannot verify releases; install the release manually", ErrNoSigningKey)
+	}
+	pub, err := parseSigningKey(key)
+	if err != nil {
+		return err
+	}
+
+	sig := signature
+	if decoded, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(signature))); err == nil {
+		sig = decoded
+	}
+
+	var ok bool
+	switch pub := pub.(type) {
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(checksums)
+		ok = ecdsa.VerifyASN1(pub, digest[:], sig)
+	case ed25519.PublicKey:
+		ok = ed25519.Verify(pub, checksums, sig)
+	}
+	if !ok {
+		return fmt.Errorf("%w: %s is not signed by the release key", ErrSignatureInvalid, checksumsAssetName)
+	}
+	return nil
+}
+
+// parseSigningKey decodes a PEM or bare base64 PKIX public key.
+func parseSigningKey(key string) (any, error) {
+	key = strings.TrimSpace(key)
+	var der []byte
+	if block, _ := pem.Decode([]byte(key)); block != nil {
+		der = block.Bytes
+	} else {
+		decoded, err := base64.StdEncoding.DecodeString(key)
+		if err != nil {
+			return nil, fmt.Errorf("decode release signing key: %w", err)
+		}
+		der = decoded
+	}
+
+	pub, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("parse release signing key: %w", err)
+	}
+	switch pub.(type) {
+	case *ecdsa.PublicKey, ed25519.PublicKey:
+		return pub, nil
+	default:
+		return nil, fmt.Errorf("release signing key: unsupported key type %T", pub)
+	}
+}
@@ -0,0 +1,282 @@
+package version
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+const (
+	// DefaultRepository is the GitHub repository cascade releases are published to.
+	DefaultRepository = "goliatone/cascade"
+
+	// DefaultAPIURL is the GitHub REST API endpoint used for release lookups.
+	DefaultAPIURL = "https://api.github.com"
+
+	// DefaultCheckTTL is how long a release lookup is cached before GitHub is queried again.
+	DefaultCheckTTL = 24 * time.Hour
+
+	cacheFileName = "update-check.json"
+)
+
+// Release describes a published cascade release.
+type Release struct {
+	Tag         string    `json:"tag_name"`
+	URL         string    `json:"html_url"`
+	PublishedAt time.Time `json:"published_at"`
+	Assets      []Asset   `json:"assets"`
+}
+
+// Asset is a downloadable file attached to a release.
+type Asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+// UpdateInfo reports the outcome of an update check.
+type UpdateInfo struct {
+	Current   string
+	Latest    string
+	URL       string
+	Available bool
+	// Cached is true when the result was served from the local cache
+	Cached bool
+}
+
+// UpdateChecker queries GitHub Releases for newer cascade versions.
+type UpdateChecker struct {
+	client     *http.Client
+	apiURL     string
+	repository string
+	cacheDir   string
+	ttl        time.Duration
+	current    string
+	signingKey string
+	now        func() time.Time
+}
+
+// UpdateOption configures an UpdateChecker.
+type UpdateOption func(*UpdateChecker)
+
+// WithHTTPClient sets the HTTP client used for GitHub requests.
+func WithHTTPClient(client *http.Client) UpdateOption {
+	return func(c *UpdateChecker) {
+		if client != nil {
+			c.client = client
+		}
+	}
+}
+
+// WithAPIURL overrides the GitHub API endpoint, e.g. for GitHub Enterprise or tests.
+func WithAPIURL(url string) UpdateOption {
+	return func(c *UpdateChecker) {
+		if url != "" {
+			c.apiURL = strings.TrimRight(url, "/")
+		}
+	}
+}
+
+// WithRepository overrides the owner/name repository releases are read from.
+func WithRepository(repo string) UpdateOption {
+	return func(c *UpdateChecker) {
+		if repo != "" {
+			c.repository = repo
+		}
+	}
+}
+
+// WithCacheDir sets the directory the release lookup cache is stored in.
+// An empty directory disables caching.
+func WithCacheDir(dir string) UpdateOption {
+	return func(c *UpdateChecker) {
+		c.cacheDir = dir
+	}
+}
+
+// WithCheckTTL sets how long cached lookups remain valid.
+func WithCheckTTL(ttl time.Duration) UpdateOption {
+	return func(c *UpdateChecker) {
+		if ttl > 0 {
+			c.ttl = ttl
+		}
+	}
+}
+
+// WithCurrentVersion overrides the running version, which defaults to Tag.
+func WithCurrentVersion(v string) UpdateOption {
+	return func(c *UpdateChecker) {
+		c.current = v
+	}
+}
+
+// NewUpdateChecker creates an UpdateChecker. By default lookups are cached for
+// DefaultCheckTTL in the user cache directory.
+func NewUpdateChecker(opts ...UpdateOption) *UpdateChecker {
+	c := &UpdateChecker{
+		client:     &http.Client{Timeout: 15 * time.Second},
+		apiURL:     DefaultAPIURL,
+		repository: DefaultRepository,
+		cacheDir:   DefaultCacheDir(),
+		ttl:        DefaultCheckTTL,
+		current:    Tag,
+		signingKey: SigningKey,
+		now:        time.Now,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// DefaultCacheDir returns the directory used to cache update checks, or an empty
+// string when the user cache directory cannot be determined.
+func DefaultCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "cascade")
+}
+
+// Check compares the running version with the latest published release.
+// Development builds never report an available update.
+func (c *UpdateChecker) Check(ctx context.Context) (*UpdateInfo, error) {
+	release, cached := c.cachedRelease()
+	if release == nil {
+		var err error
+		release, err = c.Latest(ctx)
+		if err != nil {
+			return nil, err
+		}
+		c.storeRelease(release)
+	}
+
+	return &UpdateInfo{
+		Current:   c.current,
+		Latest:    release.Tag,
+		URL:       release.URL,
+		Available: IsNewer(release.Tag, c.current),
+		Cached:    cached,
+	}, nil
+}
+
+// Latest fetches the latest published release, bypassing the cache.
+func (c *UpdateChecker) Latest(ctx context.Context) (*Release, error) {
+	return c.fetchRelease(ctx, fmt.Sprintf("%s/repos/%s/releases/latest", c.apiURL, c.repository))
+}
+
+// ReleaseByTag fetches a specific release, bypassing the cache.
+func (c *UpdateChecker) ReleaseByTag(ctx context.Context, tag string) (*Release, error) {
+	return c.fetchRelease(ctx, fmt.Sprintf("%s/repos/%s/releases/tags/%s", c.apiURL, c.repository, tag))
+}
+
+func (c *UpdateChecker) fetchRelease(ctx context.Context, url string) (*Release, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build release request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if token := githubToken(); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("query github releases: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("query github releases: unexpected status %s", resp.Status)
+	}
+
+	var release Release
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return nil, fmt.Errorf("decode github release: %w", err)
+	}
+	if release.Tag == "" {
+		return nil, fmt.Errorf("decode github release: missing tag name")
+	}
+	return &release, nil
+}
+
+type cacheEntry struct {
+	CheckedAt time.Time `json:"checked_at"`
+	Release   Release   `json:"release"`
+}
+
+func (c *UpdateChecker) cachePath() string {
+	if c.cacheDir == "" {
+		return ""
+	}
+	return filepath.Join(c.cacheDir, cacheFileName)
+}
+
+// cachedRelease returns the cached release when it is still fresh.
+func (c *UpdateChecker) cachedRelease() (*Release, bool) {
+	path := c.cachePath()
+	if path == "" {
+		return nil, false
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Release.Tag == "" {
+		return nil, false
+	}
+	if c.now().Sub(entry.CheckedAt) > c.ttl {
+		return nil, false
+	}
+	return &entry.Release, true
+}
+
+// storeRelease persists a lookup result. Cache failures are not fatal.
+func (c *UpdateChecker) storeRelease(release *Release) {
+	path := c.cachePath()
+	if path == "" || release == nil {
+		return
+	}
+
+	data, err := json.Marshal(cacheEntry{CheckedAt: c.now(), Release: *release})
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return
+	}
+	_ = os.WriteFile(path, data, 0o644)
+}
+
+// IsNewer reports whether latest is a greater semantic version than current.
+// It returns false when either version cannot be parsed, e.g. for "dev" builds.
+func IsNewer(latest, current string) bool {
+	latestV, err := semver.NewVersion(strings.TrimSpace(latest))
+	if err != nil {
+		return false
+	}
+	currentV, err := semver.NewVersion(strings.TrimSpace(current))
+	if err != nil {
+		return false
+	}
+	return latestV.GreaterThan(currentV)
+}
+
+func githubToken() string {
+	for _, key := range []string{"CASCADE_GITHUB_TOKEN", "GITHUB_TOKEN", "GH_TOKEN"} {
+		if value := strings.TrimSpace(os.Getenv(key)); value != "" {
+			return value
+		}
+	}
+	return ""
+}
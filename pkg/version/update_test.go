@@ -0,0 +1,303 @@
+package version
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestIsNewer(t *testing.T) {
+	tests := []struct {
+		latest, current string
+		want            bool
+	}{
+		{"v1.2.0", "v1.1.9", true},
+		{"v1.2.0", "v1.2.0", false},
+		{"v1.1.0", "v1.2.0", false},
+		{"v1.2.0", "dev", false},
+		{"garbage", "v1.0.0", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsNewer(tt.latest, tt.current); got != tt.want {
+			t.Errorf("IsNewer(%q, %q) = %v, want %v", tt.latest, tt.current, got, tt.want)
+		}
+	}
+}
+
+func TestUpdateChecker_CheckUsesCache(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.URL.Path != "/repos/goliatone/cascade/releases/latest" {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(Release{Tag: "v1.3.0", URL: "https://example.com/v1.3.0"})
+	}))
+	defer server.Close()
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	checker := NewUpdateChecker(
+		WithAPIURL(server.URL),
+		WithCacheDir(t.TempDir()),
+		WithCurrentVersion("v1.2.0"),
+		WithCheckTTL(time.Hour),
+	)
+	checker.now = func() time.Time { return now }
+
+	info, err := checker.Check(context.Background())
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if !info.Available || info.Latest != "v1.3.0" || info.Cached {
+		t.Fatalf("unexpected first check result: %+v", info)
+	}
+
+	info, err = checker.Check(context.Background())
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if !info.Cached {
+		t.Fatalf("expected second check to be served from cache: %+v", info)
+	}
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Fatalf("expected 1 request, got %d", got)
+	}
+
+	now = now.Add(2 * time.Hour)
+	if _, err := checker.Check(context.Background()); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if got := atomic.LoadInt32(&requests); got != 2 {
+		t.Fatalf("expected expired cache to trigger a request, got %d requests", got)
+	}
+}
+
+func TestUpdateChecker_CheckHTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	checker := NewUpdateChecker(WithAPIURL(server.URL), WithCacheDir(""))
+	if _, err := checker.Check(context.Background()); err == nil {
+		t.Fatal("expected error for non-200 response")
+	}
+}
+
+// newSigningKey returns a cosign style ECDSA P-256 key and its public key in
+// the form SigningKey holds.
+func newSigningKey(t *testing.T) (*ecdsa.PrivateKey, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key, base64.StdEncoding.EncodeToString(der)
+}
+
+// signChecksums signs checksums as cosign sign-blob does.
+func signChecksums(t *testing.T, key *ecdsa.PrivateKey, checksums string) string {
+	t.Helper()
+	digest := sha256.Sum256([]byte(checksums))
+	sig, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return base64.StdEncoding.EncodeToString(sig)
+}
+
+func TestDownloadBinaryVerifiesChecksum(t *testing.T) {
+	archive := buildArchive(t, "cascade", []byte("new-binary"))
+	archiveName := ArchiveName(runtime.GOOS, runtime.GOARCH)
+	sum := sha256.Sum256(archive)
+	valid := fmt.Sprintf("%s  %s\n", hex.EncodeToString(sum[:]), archiveName)
+	mismatched := fmt.Sprintf("%064d  %s\n", 0, archiveName)
+
+	releaseKey, publicKey := newSigningKey(t)
+	otherKey, _ := newSigningKey(t)
+
+	tests := []struct {
+		name       string
+		checksums  string
+		signature  string
+		signingKey string
+		wantErr    error
+	}{
+		{name: "valid checksum", checksums: valid, signature: signChecksums(t, releaseKey, valid), signingKey: publicKey},
+		{name: "mismatched checksum", checksums: mismatched, signature: signChecksums(t, releaseKey, mismatched), signingKey: publicKey, wantErr: ErrChecksumMismatch},
+		{name: "tampered checksums", checksums: valid, signature: signChecksums(t, releaseKey, mismatched), signingKey: publicKey, wantErr: ErrSignatureInvalid},
+		{name: "signed by another key", checksums: valid, signature: signChecksums(t, otherKey, valid), signingKey: publicKey, wantErr: ErrSignatureInvalid},
+		{name: "no signing key", checksums: valid, signature: signChecksums(t, releaseKey, valid), wantErr: ErrNoSigningKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/checksums.txt":
+					w.Write([]byte(tt.checksums))
+				case "/checksums.txt.sig":
+					w.Write([]byte(tt.signature))
+				case "/" + archiveName:
+					w.Write(archive)
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			defer server.Close()
+
+			release := &Release{Tag: "v1.3.0", Assets: []Asset{
+				{Name: "checksums.txt", URL: server.URL + "/checksums.txt"},
+				{Name: "checksums.txt.sig", URL: server.URL + "/checksums.txt.sig"},
+				{Name: archiveName, URL: server.URL + "/" + archiveName},
+			}}
+
+			checker := NewUpdateChecker(WithCacheDir(""), WithSigningKey(tt.signingKey))
+			binary, err := checker.DownloadBinary(context.Background(), release)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("DownloadBinary() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DownloadBinary() error = %v", err)
+			}
+			if string(binary) != "new-binary" {
+				t.Fatalf("DownloadBinary() = %q", binary)
+			}
+		})
+	}
+}
+
+func TestVerifySignatureEd25519(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	checksums := []byte("abc  cascade_Linux_x86_64.tar.gz\n")
+
+	if err := verifySignature(key, checksums, ed25519.Sign(priv, checksums)); err != nil {
+		t.Errorf("verifySignature() error = %v", err)
+	}
+	if err := verifySignature(key, []byte("tampered"), ed25519.Sign(priv, checksums)); !errors.Is(err, ErrSignatureInvalid) {
+		t.Errorf("verifySignature() error = %v, want %v", err, ErrSignatureInvalid)
+	}
+	if err := verifySignature("not a key", checksums, nil); err == nil || errors.Is(err, ErrSignatureInvalid) {
+		t.Errorf("verifySignature() error = %v, want a key error", err)
+	}
+}
+
+func TestDownloadBinaryRequiresChecksums(t *testing.T) {
+	release := &Release{Tag: "v1.3.0", Assets: []Asset{
+		{Name: ArchiveName(runtime.GOOS, runtime.GOARCH), URL: "http://127.0.0.1:0/archive"},
+	}}
+	if _, err := NewUpdateChecker().DownloadBinary(context.Background(), release); err == nil {
+		t.Fatal("expected error when checksums.txt is missing")
+	}
+
+	release.Assets = append(release.Assets, Asset{Name: "checksums.txt", URL: "http://127.0.0.1:0/checksums.txt"})
+	if _, err := NewUpdateChecker().DownloadBinary(context.Background(), release); !errors.Is(err, ErrSignatureInvalid) {
+		t.Fatalf("DownloadBinary() error = %v, want %v when checksums.txt.sig is missing", err, ErrSignatureInvalid)
+	}
+}
+
+func TestInstallBinary(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cascade")
+	if err := os.WriteFile(path, []byte("old"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := InstallBinary(path, []byte("new")); err != nil {
+		t.Fatalf("InstallBinary() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "new" {
+		t.Fatalf("expected binary to be replaced, got %q", data)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm()&0o100 == 0 {
+		t.Fatalf("expected installed binary to be executable, mode %v", info.Mode())
+	}
+}
+
+func TestArchiveName(t *testing.T) {
+	tests := map[[2]string]string{
+		{"linux", "amd64"}:  "cascade_Linux_x86_64.tar.gz",
+		{"darwin", "arm64"}: "cascade_Darwin_arm64.tar.gz",
+		{"linux", "386"}:    "cascade_Linux_i386.tar.gz",
+	}
+	for platform, want := range tests {
+		if got := ArchiveName(platform[0], platform[1]); got != want {
+			t.Errorf("ArchiveName(%q, %q) = %q, want %q", platform[0], platform[1], got, want)
+		}
+	}
+}
+
+func buildArchive(t *testing.T, name string, content []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, file := range []struct {
+		name string
+		data []byte
+	}{
+		{"README.md", []byte("readme")},
+		{name, content},
+	} {
+		if err := tw.WriteHeader(&tar.Header{Name: file.name, Mode: 0o755, Size: int64(len(file.data)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write(file.data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
@@ -3,6 +3,8 @@ package version
 import (
 	"fmt"
 	"io"
+	"runtime"
+	"strings"
 	"text/tabwriter"
 )
 
@@ -35,3 +37,32 @@ func Print(w io.Writer) error {
 	fmt.Fprintln(tw)
 	return tw.Flush()
 }
+
+// Info is machine-readable build metadata. Field names follow the goreleaser
+// metadata.json artifact so packagers can consume either source.
+type Info struct {
+	ProjectName string `json:"project_name"`
+	Tag         string `json:"tag"`
+	Version     string `json:"version"`
+	Commit      string `json:"commit"`
+	Date        string `json:"date"`
+	User        string `json:"user"`
+	GoVersion   string `json:"go_version"`
+	OS          string `json:"os"`
+	Arch        string `json:"arch"`
+}
+
+// GetInfo returns the build metadata embedded at link time.
+func GetInfo() Info {
+	return Info{
+		ProjectName: "cascade",
+		Tag:         Tag,
+		Version:     strings.TrimPrefix(Tag, "v"),
+		Commit:      Commit,
+		Date:        Time,
+		User:        User,
+		GoVersion:   runtime.Version(),
+		OS:          runtime.GOOS,
+		Arch:        runtime.GOARCH,
+	}
+}
@@ -0,0 +1,181 @@
+// Package layout decides where repository clones live inside a workspace. A
+// layout is a slash separated template of the {{host}}, {{owner}} and {{repo}}
+// placeholders, such as "{{host}}/{{owner}}/{{repo}}", so repositories that
+// share a name across owners or hosts get separate directories.
+package layout
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/goliatone/cascade/pkg/repourl"
+)
+
+// Default is the layout used when none is configured: each clone sits directly
+// in the workspace, named after its repository.
+const Default = "{{repo}}"
+
+// Placeholders understood in layout templates.
+const (
+	PlaceholderHost  = "host"
+	PlaceholderOwner = "owner"
+	PlaceholderRepo  = "repo"
+)
+
+// conventional are the locations repositories were found in before layouts
+// were configurable. Lookups still try them after the configured layout.
+var conventional = []string{Default, "{{owner}}/{{repo}}", "{{host}}/{{owner}}/{{repo}}"}
+
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([^{}]*?)\s*\}\}`)
+
+// Layout maps repositories to directories inside a workspace. The zero value
+// is the Default layout.
+type Layout struct {
+	template string
+	urls     *repourl.Resolver
+}
+
+// Option configures a Layout.
+type Option func(*Layout)
+
+// WithResolver sets the resolver repository identifiers are split with, which
+// knows the default host of owner/repo shorthands and the configured hosts.
+// Nil keeps the built-in host defaults.
+func WithResolver(urls *repourl.Resolver) Option {
+	return func(l *Layout) {
+		l.urls = urls
+	}
+}
+
+// Parse validates template and returns its layout. An empty template selects
+// Default. Templates must be relative, must place {{repo}} in their last path
+// element, and may only use the {{host}}, {{owner}} and {{repo}} placeholders.
+func Parse(template string, opts ...Option) (Layout, error) {
+	template = strings.Trim(strings.TrimSpace(template), "/")
+	if template == "" {
+		template = Default
+	}
+
+	if filepath.IsAbs(template) || strings.HasPrefix(template, "~") {
+		return Layout{}, fmt.Errorf("layout %q must be relative to the workspace", template)
+	}
+	segments := strings.Split(template, "/")
+	for _, segment := range segments {
+		if segment == "" || segment == "." || segment == ".." {
+			return Layout{}, fmt.Errorf("layout %q has an invalid path element %q", template, segment)
+		}
+		for _, match := range placeholderPattern.FindAllStringSubmatch(segment, -1) {
+			switch match[1] {
+			case PlaceholderHost, PlaceholderOwner, PlaceholderRepo:
+			default:
+				return Layout{}, fmt.Errorf("layout %q uses unknown placeholder %q; use {{host}}, {{owner}} or {{repo}}", template, match[0])
+			}
+		}
+		if rest := placeholderPattern.ReplaceAllString(segment, ""); strings.ContainsAny(rest, "{}") {
+			return Layout{}, fmt.Errorf("layout %q has an unterminated placeholder in %q", template, segment)
+		}
+	}
+	if !usesPlaceholder(segments[len(segments)-1], PlaceholderRepo) {
+		return Layout{}, fmt.Errorf("layout %q must include {{repo}} in its last path element", template)
+	}
+
+	l := Layout{template: template}
+	for _, opt := range opts {
+		opt(&l)
+	}
+	return l, nil
+}
+
+// String returns the layout template.
+func (l Layout) String() string {
+	if l.template == "" {
+		return Default
+	}
+	return l.template
+}
+
+// IsDefault reports whether l places clones like the Default layout.
+func (l Layout) IsDefault() bool {
+	return l.String() == Default
+}
+
+// Depth returns the number of directory levels the layout places between the
+// workspace and a repository, counting the repository directory itself.
+// Owners with subgroups add one level per group.
+func (l Layout) Depth() int {
+	return strings.Count(l.String(), "/") + 1
+}
+
+// Dir returns the directory of repo inside workspace. repo may be an owner/repo
+// shorthand, a host/owner/repo identifier or a clone URL.
+func (l Layout) Dir(workspace, repo string) (string, error) {
+	return l.dir(l.String(), workspace, repo)
+}
+
+// Locations returns the directories repo may already be cloned in: the
+// directory of the layout first, followed by the conventional locations
+// <repo>, <owner>/<repo> and <host>/<owner>/<repo>. Duplicates and locations
+// that cannot be built for repo are left out.
+func (l Layout) Locations(workspace, repo string) []string {
+	var dirs []string
+	seen := make(map[string]bool)
+	for _, template := range append([]string{l.String()}, conventional...) {
+		dir, err := l.dir(template, workspace, repo)
+		if err != nil || seen[dir] {
+			continue
+		}
+		seen[dir] = true
+		dirs = append(dirs, dir)
+	}
+	return dirs
+}
+
+func (l Layout) dir(template, workspace, repo string) (string, error) {
+	parts, err := l.split(repo)
+	if err != nil {
+		return "", err
+	}
+
+	var missing error
+	expanded := placeholderPattern.ReplaceAllStringFunc(template, func(placeholder string) string {
+		name := placeholderPattern.FindStringSubmatch(placeholder)[1]
+		value := parts[name]
+		if value == "" && missing == nil {
+			missing = fmt.Errorf("repository %q has no %s for layout %q", repo, name, template)
+		}
+		return value
+	})
+	if missing != nil {
+		return "", missing
+	}
+	return filepath.Join(workspace, filepath.FromSlash(expanded)), nil
+}
+
+// split breaks repo into its host, owner and repository name. Owners keep any
+// subgroups, e.g. "group/subgroup" on GitLab.
+func (l Layout) split(repo string) (map[string]string, error) {
+	split := repourl.Split
+	if l.urls != nil {
+		split = l.urls.Split
+	}
+	parts, err := split(repo)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{
+		PlaceholderHost:  parts.Host,
+		PlaceholderOwner: parts.Owner,
+		PlaceholderRepo:  parts.Repo,
+	}, nil
+}
+
+func usesPlaceholder(segment, name string) bool {
+	for _, match := range placeholderPattern.FindAllStringSubmatch(segment, -1) {
+		if match[1] == name {
+			return true
+		}
+	}
+	return false
+}
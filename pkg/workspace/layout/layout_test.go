@@ -0,0 +1,119 @@
+package layout
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/goliatone/cascade/pkg/repourl"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		template string
+		want     string
+		wantErr  string
+	}{
+		{template: "", want: Default},
+		{template: " /{{host}}/{{owner}}/{{repo}}/ ", want: "{{host}}/{{owner}}/{{repo}}"},
+		{template: "{{ owner }}-{{ repo }}", want: "{{ owner }}-{{ repo }}"},
+		{template: "/srv/{{repo}}", want: "srv/{{repo}}"},
+		{template: "~/{{repo}}", wantErr: "must be relative"},
+		{template: "{{owner}}/../{{repo}}", wantErr: "invalid path element"},
+		{template: "{{org}}/{{repo}}", wantErr: `unknown placeholder "{{org}}"`},
+		{template: "{{owner}}/{{repo", wantErr: "unterminated placeholder"},
+		{template: "{{repo}}/{{owner}}", wantErr: "must include {{repo}} in its last path element"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			l, err := Parse(tt.template)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Parse() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if l.String() != tt.want {
+				t.Errorf("String() = %q, want %q", l.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestLayoutDir(t *testing.T) {
+	nested, err := Parse("{{host}}/{{owner}}/{{repo}}", WithResolver(repourl.New(repourl.WithDefaultHost("GIT.example.com"))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	flat, err := Parse("{{owner}}-{{repo}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		layout  Layout
+		repo    string
+		want    string
+		wantErr string
+	}{
+		{name: "zero value", layout: Layout{}, repo: "acme/api", want: "api"},
+		{name: "shorthand", layout: nested, repo: "acme/api", want: "git.example.com/acme/api"},
+		{name: "host identifier", layout: nested, repo: "gitlab.com/group/sub/api", want: "gitlab.com/group/sub/api"},
+		{name: "subgroup shorthand", layout: nested, repo: "group/sub/api", want: "git.example.com/group/sub/api"},
+		{name: "https url", layout: nested, repo: "https://github.com/acme/api.git", want: "github.com/acme/api"},
+		{name: "ssh url with port", layout: nested, repo: "ssh://git@git.example.com:2222/acme/api.git", want: "git.example.com/acme/api"},
+		{name: "scp url", layout: nested, repo: "git@github.com:acme/api.git", want: "github.com/acme/api"},
+		{name: "flat", layout: flat, repo: "https://github.com/acme/api", want: "acme-api"},
+		{name: "missing owner", layout: flat, repo: "api", wantErr: `repository "api" has no owner`},
+		{name: "empty", layout: nested, repo: " ", wantErr: "repository identifier is empty"},
+		{name: "traversal", layout: nested, repo: "acme/../api", wantErr: "invalid repository identifier"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.layout.Dir("/ws", tt.repo)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Dir() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Dir() error = %v", err)
+			}
+			if want := filepath.Join("/ws", filepath.FromSlash(tt.want)); got != want {
+				t.Errorf("Dir() = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestLayoutLocations(t *testing.T) {
+	l, err := Parse("{{host}}/{{owner}}/{{repo}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := l.Locations("/ws", "acme/api")
+	want := []string{
+		filepath.Join("/ws", "github.com", "acme", "api"),
+		filepath.Join("/ws", "api"),
+		filepath.Join("/ws", "acme", "api"),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Locations() = %v, want %v", got, want)
+	}
+
+	if got := (Layout{}).Locations("/ws", "api"); !reflect.DeepEqual(got, []string{filepath.Join("/ws", "api")}) {
+		t.Errorf("Locations() without owner = %v", got)
+	}
+	if l.Depth() != 3 || (Layout{}).Depth() != 1 {
+		t.Errorf("Depth() = %d, %d, want 3, 1", l.Depth(), Layout{}.Depth())
+	}
+}